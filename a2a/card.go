@@ -0,0 +1,207 @@
+package a2a
+
+import (
+	"sort"
+
+	"github.com/spetersoncode/gains/agent"
+	"github.com/spetersoncode/gains/tool"
+)
+
+// AgentCardPath is the well-known path at which an A2A server publishes its
+// agent card.
+const AgentCardPath = "/.well-known/agent-card.json"
+
+// ProtocolVersion is the version of the A2A protocol these types follow.
+const ProtocolVersion = "0.3.0"
+
+// AgentCard describes an agent to A2A clients: who it is, where to reach
+// it, what it can do, and how to authenticate.
+type AgentCard struct {
+	ProtocolVersion    string                    `json:"protocolVersion"`
+	Name               string                    `json:"name"`
+	Description        string                    `json:"description"`
+	URL                string                    `json:"url"`
+	PreferredTransport string                    `json:"preferredTransport,omitempty"`
+	Version            string                    `json:"version"`
+	Provider           *AgentProvider            `json:"provider,omitempty"`
+	DocumentationURL   string                    `json:"documentationUrl,omitempty"`
+	Capabilities       AgentCapabilities         `json:"capabilities"`
+	SecuritySchemes    map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+	Security           []map[string][]string     `json:"security,omitempty"`
+	DefaultInputModes  []string                  `json:"defaultInputModes"`
+	DefaultOutputModes []string                  `json:"defaultOutputModes"`
+	Skills             []AgentSkill              `json:"skills"`
+}
+
+// AgentProvider identifies the organization offering an agent.
+type AgentProvider struct {
+	Organization string `json:"organization"`
+	URL          string `json:"url"`
+}
+
+// AgentCapabilities lists the optional protocol features an agent supports.
+type AgentCapabilities struct {
+	Streaming              bool `json:"streaming,omitempty"`
+	PushNotifications      bool `json:"pushNotifications,omitempty"`
+	StateTransitionHistory bool `json:"stateTransitionHistory,omitempty"`
+}
+
+// AgentSkill is a capability an agent advertises, such as a tool it can use
+// on the client's behalf.
+type AgentSkill struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags"`
+	Examples    []string `json:"examples,omitempty"`
+	InputModes  []string `json:"inputModes,omitempty"`
+	OutputModes []string `json:"outputModes,omitempty"`
+}
+
+// SecurityScheme describes how clients authenticate, following the
+// OpenAPI security scheme object. Type is "apiKey", "http", "oauth2", or
+// "openIdConnect"; the other fields apply to some types only.
+type SecurityScheme struct {
+	Type             string `json:"type"`
+	Description      string `json:"description,omitempty"`
+	Name             string `json:"name,omitempty"`
+	In               string `json:"in,omitempty"`
+	Scheme           string `json:"scheme,omitempty"`
+	BearerFormat     string `json:"bearerFormat,omitempty"`
+	OpenIDConnectURL string `json:"openIdConnectUrl,omitempty"`
+}
+
+// CardOption configures an AgentCard built by NewAgentCard.
+type CardOption func(*AgentCard)
+
+// WithCardName sets the agent name (default: "agent").
+func WithCardName(name string) CardOption {
+	return func(c *AgentCard) {
+		c.Name = name
+	}
+}
+
+// WithCardDescription sets the agent description.
+func WithCardDescription(desc string) CardOption {
+	return func(c *AgentCard) {
+		c.Description = desc
+	}
+}
+
+// WithCardURL sets the URL of the agent's JSON-RPC endpoint.
+func WithCardURL(url string) CardOption {
+	return func(c *AgentCard) {
+		c.URL = url
+	}
+}
+
+// WithCardVersion sets the version of the agent (default: "1.0.0").
+func WithCardVersion(version string) CardOption {
+	return func(c *AgentCard) {
+		c.Version = version
+	}
+}
+
+// WithCardProvider names the organization offering the agent.
+func WithCardProvider(organization, url string) CardOption {
+	return func(c *AgentCard) {
+		c.Provider = &AgentProvider{Organization: organization, URL: url}
+	}
+}
+
+// WithCardDocumentationURL sets the URL of the agent's documentation.
+func WithCardDocumentationURL(url string) CardOption {
+	return func(c *AgentCard) {
+		c.DocumentationURL = url
+	}
+}
+
+// WithSecurityScheme declares an authentication scheme under name and
+// requires clients to use it. Schemes added by separate calls are
+// alternatives; any one of them is accepted.
+//
+//	a2a.WithSecurityScheme("bearer", a2a.SecurityScheme{Type: "http", Scheme: "bearer"})
+func WithSecurityScheme(name string, scheme SecurityScheme) CardOption {
+	return func(c *AgentCard) {
+		if c.SecuritySchemes == nil {
+			c.SecuritySchemes = make(map[string]SecurityScheme)
+		}
+		c.SecuritySchemes[name] = scheme
+		c.Security = append(c.Security, map[string][]string{name: {}})
+	}
+}
+
+// WithCardSkills adds skills to those generated from the agent and registry.
+func WithCardSkills(skills ...AgentSkill) CardOption {
+	return func(c *AgentCard) {
+		c.Skills = append(c.Skills, skills...)
+	}
+}
+
+// WithCardModes sets the default MIME types the agent accepts and produces
+// (default: "text/plain" for both).
+func WithCardModes(input, output []string) CardOption {
+	return func(c *AgentCard) {
+		c.DefaultInputModes = input
+		c.DefaultOutputModes = output
+	}
+}
+
+// NewAgentCard describes a gains agent for A2A discovery. Each skill of
+// the agent's default options and each server-side tool in registry, in
+// name order, is advertised as a skill; client tools are skipped, since
+// A2A callers cannot run them. Streaming is advertised, since every
+// executor supports message/stream. Either a or registry may be nil.
+//
+//	card := a2a.NewAgentCard(a, registry,
+//	    a2a.WithCardName("support"),
+//	    a2a.WithCardURL("https://agents.example.com/support/"),
+//	)
+func NewAgentCard(a *agent.Agent, registry *tool.Registry, opts ...CardOption) AgentCard {
+	card := AgentCard{
+		ProtocolVersion:    ProtocolVersion,
+		Name:               "agent",
+		PreferredTransport: "JSONRPC",
+		Version:            "1.0.0",
+		Capabilities:       AgentCapabilities{Streaming: true},
+		DefaultInputModes:  []string{"text/plain"},
+		DefaultOutputModes: []string{"text/plain"},
+		Skills:             []AgentSkill{},
+	}
+
+	seen := make(map[string]bool)
+	if a != nil {
+		for _, s := range a.Options().Skills {
+			if s.Name == "" || seen[s.Name] {
+				continue
+			}
+			seen[s.Name] = true
+			skill := AgentSkill{ID: s.Name, Name: s.Name, Description: s.Description, Tags: []string{"skill"}}
+			for _, pair := range s.Tools {
+				skill.Tags = append(skill.Tags, pair.Tool.Name)
+			}
+			card.Skills = append(card.Skills, skill)
+		}
+	}
+	if registry != nil {
+		tools := registry.Tools()
+		sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+		for _, t := range tools {
+			if registry.IsClientTool(t.Name) || seen[t.Name] {
+				continue
+			}
+			seen[t.Name] = true
+			card.Skills = append(card.Skills, AgentSkill{
+				ID:          t.Name,
+				Name:        t.Name,
+				Description: t.Description,
+				Tags:        []string{"tool"},
+			})
+		}
+	}
+
+	for _, opt := range opts {
+		opt(&card)
+	}
+	return card
+}
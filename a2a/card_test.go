@@ -0,0 +1,90 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/agent"
+	"github.com/spetersoncode/gains/tool"
+)
+
+func TestNewAgentCard(t *testing.T) {
+	noop := func(context.Context, ai.ToolCall) (string, error) { return "", nil }
+	registry := tool.NewRegistry()
+	registry.MustRegister(ai.Tool{Name: "search", Description: "Search the web"}, noop)
+	registry.MustRegister(ai.Tool{Name: "calculate", Description: "Do arithmetic"}, noop)
+	if err := registry.RegisterClientTool(ai.Tool{Name: "confirm"}); err != nil {
+		t.Fatal(err)
+	}
+
+	a := agent.NewBuilder(nil).
+		Registry(registry).
+		Skills(agent.Skill{
+			Name:        "tickets",
+			Description: "Look up support tickets",
+			Tools:       []tool.ToolPair{{Tool: ai.Tool{Name: "lookup_ticket"}, Handler: noop}},
+		}).
+		MustBuild()
+
+	card := NewAgentCard(a, registry,
+		WithCardName("support"),
+		WithCardURL("https://agents.example.com/"),
+		WithSecurityScheme("bearer", SecurityScheme{Type: "http", Scheme: "bearer"}),
+	)
+
+	if card.Name != "support" || card.URL != "https://agents.example.com/" {
+		t.Errorf("card identity = %q %q", card.Name, card.URL)
+	}
+	if card.ProtocolVersion != ProtocolVersion || !card.Capabilities.Streaming {
+		t.Errorf("card protocol = %q, streaming = %v", card.ProtocolVersion, card.Capabilities.Streaming)
+	}
+
+	var ids []string
+	for _, s := range card.Skills {
+		ids = append(ids, s.ID)
+	}
+	if want := []string{"tickets", "calculate", "search"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("skills = %v, want %v", ids, want)
+	}
+	if want := []string{"skill", "lookup_ticket"}; !reflect.DeepEqual(card.Skills[0].Tags, want) {
+		t.Errorf("skill tags = %v, want %v", card.Skills[0].Tags, want)
+	}
+
+	if _, ok := card.SecuritySchemes["bearer"]; !ok {
+		t.Error("bearer scheme missing")
+	}
+	if want := []map[string][]string{{"bearer": {}}}; !reflect.DeepEqual(card.Security, want) {
+		t.Errorf("security = %v, want %v", card.Security, want)
+	}
+
+	data, err := json.Marshal(card)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"protocolVersion", "name", "description", "url", "version", "capabilities", "defaultInputModes", "defaultOutputModes", "skills"} {
+		if _, ok := decoded[field]; !ok {
+			t.Errorf("JSON missing required field %q", field)
+		}
+	}
+}
+
+func TestNewAgentCard_Defaults(t *testing.T) {
+	card := NewAgentCard(nil, nil)
+
+	if card.Name != "agent" || card.Version != "1.0.0" {
+		t.Errorf("defaults = %q %q", card.Name, card.Version)
+	}
+	if card.Skills == nil {
+		t.Error("Skills should be an empty list, not null")
+	}
+	if !card.Capabilities.Streaming {
+		t.Error("streaming should be advertised")
+	}
+}
@@ -11,9 +11,10 @@
 //   - Core A2A types: [Message], [Task], [TaskState], [Artifact], and Part types
 //   - Message conversion: [ToGainsMessages], [FromGainsMessages] for bidirectional conversion
 //   - Event mapping: [Mapper] for converting gains events to A2A task updates
+//   - Agent discovery: [NewAgentCard] builds an [AgentCard] to publish at [AgentCardPath]
 //
-// The package does NOT provide HTTP handlers or transport implementations. Users are
-// responsible for implementing their own JSON-RPC server using their preferred framework.
+// The package does NOT provide HTTP handlers or transport implementations. The
+// cmd/a2aserver command is a reference JSON-RPC server built on [AgentExecutor].
 //
 // # Message Conversion
 //
@@ -55,6 +56,19 @@
 //	// Finalize with completed or failed status
 //	finalTask := mapper.Complete(artifacts)
 //
+// # Agent Cards
+//
+// A2A servers publish an [AgentCard] at [AgentCardPath] so clients can
+// discover what an agent does and how to call it. [NewAgentCard] builds one
+// from an agent and its tool registry, advertising the agent's skills and
+// server-side tools as card skills:
+//
+//	card := a2a.NewAgentCard(a, registry,
+//	    a2a.WithCardName("support"),
+//	    a2a.WithCardURL("https://agents.example.com/support/"),
+//	    a2a.WithSecurityScheme("bearer", a2a.SecurityScheme{Type: "http", Scheme: "bearer"}),
+//	)
+//
 // # Protocol Compliance
 //
 // This package implements types compatible with A2A Protocol version 0.3. For full
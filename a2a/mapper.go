@@ -1,7 +1,7 @@
 package a2a
 
 import (
-	"github.com/google/uuid"
+	ai "github.com/spetersoncode/gains"
 	"github.com/spetersoncode/gains/event"
 )
 
@@ -36,10 +36,10 @@ type Mapper struct {
 // NewMapper creates a new Mapper for a single task.
 func NewMapper(taskID, contextID string) *Mapper {
 	if taskID == "" {
-		taskID = uuid.New().String()
+		taskID = ai.NewID()
 	}
 	if contextID == "" {
-		contextID = uuid.New().String()
+		contextID = ai.NewID()
 	}
 	return &Mapper{
 		taskID:    taskID,
@@ -1,9 +1,21 @@
 package a2a
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
 	ai "github.com/spetersoncode/gains"
 )
 
+// Metadata keys used to carry gains message fields that have no
+// dedicated A2A equivalent.
+const (
+	metadataKeyName      = "name"
+	metadataKeyCreatedAt = "createdAt"
+)
+
 // ToGainsMessages converts A2A messages to gains messages.
 func ToGainsMessages(msgs []Message) []ai.Message {
 	result := make([]ai.Message, 0, len(msgs))
@@ -14,30 +26,53 @@ func ToGainsMessages(msgs []Message) []ai.Message {
 }
 
 // ToGainsMessage converts a single A2A message to a gains message.
+// Text parts are concatenated into Content. Image files and non-tool data
+// parts are carried as gains content parts alongside the text.
 func ToGainsMessage(msg Message) ai.Message {
 	m := ai.Message{
 		ID:   msg.MessageID,
 		Role: toGainsRole(msg.Role),
 	}
 
-	// Extract text content from parts
+	var parts []ai.ContentPart
+	multimodal := false
+
 	for _, part := range msg.Parts {
 		switch p := part.(type) {
 		case TextPart:
 			m.Content += p.Text
+			parts = append(parts, ai.NewTextPart(p.Text))
+		case FilePart:
+			if cp, ok := filePartToContentPart(p); ok {
+				parts = append(parts, cp)
+				multimodal = true
+			}
 		case DataPart:
 			// Data parts might contain tool calls or tool results
 			if data, ok := p.Data.(map[string]any); ok {
-				if toolCalls := extractToolCalls(data); len(toolCalls) > 0 {
+				toolCalls := extractToolCalls(data)
+				toolResults := extractToolResults(data)
+				if len(toolCalls) > 0 || len(toolResults) > 0 {
 					m.ToolCalls = append(m.ToolCalls, toolCalls...)
-				}
-				if toolResults := extractToolResults(data); len(toolResults) > 0 {
 					m.ToolResults = append(m.ToolResults, toolResults...)
+					continue
 				}
 			}
+			// Other structured data is passed to the model as JSON text
+			if raw, err := json.Marshal(p.Data); err == nil {
+				parts = append(parts, ai.NewTextPart(string(raw)))
+				multimodal = true
+			}
 		}
 	}
 
+	// Only use parts when there is non-text content; plain text stays in Content
+	if multimodal {
+		m.Parts = parts
+	}
+
+	applyMetadata(&m, msg.Metadata)
+
 	return m
 }
 
@@ -60,8 +95,14 @@ func FromGainsMessage(msg ai.Message) Message {
 	// Build parts from message content
 	var parts []Part
 
-	// Add text content
-	if msg.Content != "" {
+	// Add content, preferring multimodal parts when present
+	if msg.HasParts() {
+		for _, cp := range msg.Parts {
+			if p, ok := contentPartToPart(cp); ok {
+				parts = append(parts, p)
+			}
+		}
+	} else if msg.Content != "" {
 		parts = append(parts, NewTextPart(msg.Content))
 	}
 
@@ -90,9 +131,106 @@ func FromGainsMessage(msg ai.Message) Message {
 	}
 
 	m.Parts = parts
+	m.Metadata = buildMetadata(msg)
 	return m
 }
 
+// buildMetadata converts gains message metadata, name, and timestamp into
+// A2A message metadata. Returns nil if there is nothing to carry.
+func buildMetadata(msg ai.Message) map[string]any {
+	if len(msg.Metadata) == 0 && msg.Name == "" && msg.CreatedAt.IsZero() {
+		return nil
+	}
+	md := make(map[string]any, len(msg.Metadata)+2)
+	for k, v := range msg.Metadata {
+		md[k] = v
+	}
+	if msg.Name != "" {
+		md[metadataKeyName] = msg.Name
+	}
+	if !msg.CreatedAt.IsZero() {
+		md[metadataKeyCreatedAt] = msg.CreatedAt.Format(time.RFC3339Nano)
+	}
+	return md
+}
+
+// applyMetadata populates gains message fields from A2A message metadata.
+// Only string values are kept since gains metadata is string-valued.
+func applyMetadata(m *ai.Message, md map[string]any) {
+	for k, v := range md {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		switch k {
+		case metadataKeyName:
+			m.Name = s
+		case metadataKeyCreatedAt:
+			if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+				m.CreatedAt = t
+			}
+		default:
+			if m.Metadata == nil {
+				m.Metadata = make(map[string]string)
+			}
+			m.Metadata[k] = s
+		}
+	}
+}
+
+// filePartToContentPart converts an A2A file part to a gains content part.
+// Image files become image parts; all other files become document parts.
+func filePartToContentPart(p FilePart) (ai.ContentPart, bool) {
+	if !strings.HasPrefix(p.File.MimeType, "image/") {
+		switch {
+		case p.File.Bytes != "":
+			return ai.NewDocumentBase64Part(p.File.Bytes, p.File.MimeType, p.File.Name), true
+		case p.File.URI != "":
+			cp := ai.NewDocumentURLPart(p.File.URI, p.File.MimeType)
+			cp.Filename = p.File.Name
+			return cp, true
+		default:
+			return ai.ContentPart{}, false
+		}
+	}
+	switch {
+	case p.File.Bytes != "":
+		return ai.NewImageBase64Part(p.File.Bytes, p.File.MimeType), true
+	case p.File.URI != "":
+		cp := ai.NewImageURLPart(p.File.URI)
+		cp.MimeType = p.File.MimeType
+		return cp, true
+	default:
+		return ai.ContentPart{}, false
+	}
+}
+
+// contentPartToPart converts a gains content part to an A2A part.
+func contentPartToPart(cp ai.ContentPart) (Part, bool) {
+	switch cp.Type {
+	case ai.ContentPartTypeText:
+		return NewTextPart(cp.Text), true
+	case ai.ContentPartTypeImage:
+		if cp.Base64 != "" {
+			return NewFilePartWithBytes("", cp.MimeType, cp.Base64), true
+		}
+		return NewFilePartWithURI("", cp.MimeType, cp.ImageURL), true
+	case ai.ContentPartTypeDocument:
+		switch {
+		case cp.Base64 != "":
+			return NewFilePartWithBytes(cp.Filename, cp.MimeType, cp.Base64), true
+		case cp.FileURL != "":
+			return NewFilePartWithURI(cp.Filename, cp.MimeType, cp.FileURL), true
+		default:
+			// Plain text documents are sent inline as bytes.
+			data := base64.StdEncoding.EncodeToString([]byte(cp.Text))
+			return NewFilePartWithBytes(cp.Filename, cp.MimeType, data), true
+		}
+	default:
+		return nil, false
+	}
+}
+
 // toGainsRole converts an A2A role to a gains Role.
 func toGainsRole(role MessageRole) ai.Role {
 	switch role {
@@ -2,6 +2,7 @@ package a2a
 
 import (
 	"testing"
+	"time"
 
 	ai "github.com/spetersoncode/gains"
 )
@@ -201,6 +202,123 @@ func TestRoundTrip(t *testing.T) {
 	}
 }
 
+func TestFilePartConversion(t *testing.T) {
+	msg := NewMessage(MessageRoleUser,
+		NewTextPart("What is in this image?"),
+		NewFilePartWithBytes("photo.png", "image/png", "aGVsbG8="),
+		NewFilePartWithURI("chart.jpg", "image/jpeg", "https://example.com/chart.jpg"),
+		NewFilePartWithURI("notes.zip", "application/zip", "https://example.com/notes.zip"),
+	)
+
+	got := ToGainsMessage(msg)
+	if got.Content != "What is in this image?" {
+		t.Errorf("Content = %q, want text only", got.Content)
+	}
+	if len(got.Parts) != 4 {
+		t.Fatalf("len(Parts) = %d, want 4", len(got.Parts))
+	}
+	if got.Parts[1].Type != ai.ContentPartTypeImage || got.Parts[1].Base64 != "aGVsbG8=" || got.Parts[1].MimeType != "image/png" {
+		t.Errorf("Parts[1] = %+v, want base64 png image", got.Parts[1])
+	}
+	if got.Parts[2].ImageURL != "https://example.com/chart.jpg" {
+		t.Errorf("Parts[2].ImageURL = %q", got.Parts[2].ImageURL)
+	}
+	if got.Parts[3].Type != ai.ContentPartTypeDocument || got.Parts[3].FileURL != "https://example.com/notes.zip" || got.Parts[3].Filename != "notes.zip" {
+		t.Errorf("Parts[3] = %+v, want document URL", got.Parts[3])
+	}
+
+	back := FromGainsMessage(got)
+	if len(back.Parts) != 4 {
+		t.Fatalf("round trip len(Parts) = %d, want 4", len(back.Parts))
+	}
+	fp, ok := back.Parts[1].(FilePart)
+	if !ok {
+		t.Fatalf("Parts[1] is %T, want FilePart", back.Parts[1])
+	}
+	if fp.File.Bytes != "aGVsbG8=" || fp.File.MimeType != "image/png" {
+		t.Errorf("FilePart = %+v, want png bytes", fp.File)
+	}
+	fp, ok = back.Parts[2].(FilePart)
+	if !ok || fp.File.URI != "https://example.com/chart.jpg" {
+		t.Errorf("Parts[2] = %+v, want image URI", back.Parts[2])
+	}
+	fp, ok = back.Parts[3].(FilePart)
+	if !ok || fp.File.URI != "https://example.com/notes.zip" || fp.File.Name != "notes.zip" {
+		t.Errorf("Parts[3] = %+v, want document URI", back.Parts[3])
+	}
+}
+
+func TestTextDocumentConversion(t *testing.T) {
+	msg := FromGainsMessage(ai.Message{
+		Role:  ai.RoleUser,
+		Parts: []ai.ContentPart{ai.NewTextDocumentPart("hello", "notes.txt")},
+	})
+	fp, ok := msg.Parts[0].(FilePart)
+	if !ok {
+		t.Fatalf("Parts[0] is %T, want FilePart", msg.Parts[0])
+	}
+	if fp.File.Bytes != "aGVsbG8=" || fp.File.MimeType != "text/plain" || fp.File.Name != "notes.txt" {
+		t.Errorf("FilePart = %+v, want base64 text file", fp.File)
+	}
+
+	back := ToGainsMessage(msg)
+	if text, ok := back.Parts[0].DocumentText(); !ok || text != "hello" {
+		t.Errorf("DocumentText() = %q, %v, want hello", text, ok)
+	}
+}
+
+func TestDataPartConversion(t *testing.T) {
+	msg := NewMessage(MessageRoleUser,
+		NewTextPart("Summarize: "),
+		NewDataPart(map[string]any{"revenue": 42}),
+	)
+
+	got := ToGainsMessage(msg)
+	if len(got.Parts) != 2 {
+		t.Fatalf("len(Parts) = %d, want 2", len(got.Parts))
+	}
+	if got.Parts[1].Type != ai.ContentPartTypeText || got.Parts[1].Text != `{"revenue":42}` {
+		t.Errorf("Parts[1] = %+v, want JSON text", got.Parts[1])
+	}
+}
+
+func TestMetadataRoundTrip(t *testing.T) {
+	created := time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC)
+	gainsMsg := ai.Message{
+		Role:      ai.RoleUser,
+		Content:   "Hello",
+		Name:      "alice",
+		CreatedAt: created,
+		Metadata:  map[string]string{"user_id": "u-123"},
+	}
+
+	a2aMsg := FromGainsMessage(gainsMsg)
+	if a2aMsg.Metadata["user_id"] != "u-123" {
+		t.Errorf("Metadata[user_id] = %v, want %q", a2aMsg.Metadata["user_id"], "u-123")
+	}
+
+	roundTrip := ToGainsMessage(a2aMsg)
+	if roundTrip.Name != "alice" {
+		t.Errorf("Name = %q, want %q", roundTrip.Name, "alice")
+	}
+	if !roundTrip.CreatedAt.Equal(created) {
+		t.Errorf("CreatedAt = %v, want %v", roundTrip.CreatedAt, created)
+	}
+	if len(roundTrip.Metadata) != 1 || roundTrip.Metadata["user_id"] != "u-123" {
+		t.Errorf("Metadata = %v, want only user_id", roundTrip.Metadata)
+	}
+}
+
+func TestMetadataNonStringValuesDropped(t *testing.T) {
+	msg := NewMessage(MessageRoleUser, NewTextPart("Hi"))
+	msg.Metadata = map[string]any{"count": 3, "source": "web"}
+
+	got := ToGainsMessage(msg)
+	if len(got.Metadata) != 1 || got.Metadata["source"] != "web" {
+		t.Errorf("Metadata = %v, want only source", got.Metadata)
+	}
+}
+
 func TestToolCallConversion(t *testing.T) {
 	// Test gains message with tool calls converts to A2A data parts
 	gainsMsg := ai.Message{
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"time"
 
-	"github.com/google/uuid"
+	ai "github.com/spetersoncode/gains"
 )
 
 // MessageRole indicates the originator of a message.
@@ -58,7 +58,7 @@ type Message struct {
 func NewMessage(role MessageRole, parts ...Part) Message {
 	return Message{
 		Kind:      "message",
-		MessageID: uuid.New().String(),
+		MessageID: ai.NewID(),
 		Role:      role,
 		Parts:     parts,
 	}
@@ -240,7 +240,7 @@ type Artifact struct {
 // NewArtifact creates a new artifact with the given parts.
 func NewArtifact(name, description string, parts ...Part) Artifact {
 	return Artifact{
-		ArtifactID:  uuid.New().String(),
+		ArtifactID:  ai.NewID(),
 		Name:        name,
 		Description: description,
 		Parts:       parts,
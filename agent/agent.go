@@ -2,13 +2,13 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"sync"
-	"time"
 
 	ai "github.com/spetersoncode/gains"
 	"github.com/spetersoncode/gains/chat"
 	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/internal/group"
 	"github.com/spetersoncode/gains/internal/store"
 	"github.com/spetersoncode/gains/tool"
 )
@@ -17,6 +17,9 @@ import (
 type Agent struct {
 	chatClient chat.Client
 	registry   *tool.Registry
+
+	// defaults are applied before the options passed to each run.
+	defaults []Option
 }
 
 // New creates a new Agent with the given chat client and tool registry.
@@ -30,8 +33,13 @@ func New(c chat.Client, registry *tool.Registry) *Agent {
 // Run executes the agent loop and returns the final result.
 // This is a blocking call that runs until the agent completes.
 func (a *Agent) Run(ctx context.Context, messages []ai.Message, opts ...Option) (*Result, error) {
-	eventCh := a.RunStream(ctx, messages, opts...)
+	return collect(a.RunStream(ctx, messages, opts...), messages, nil)
+}
 
+// collect assembles the result of a run from its events, starting from the
+// conversation history the run was given and any tool results already
+// recorded for its last message.
+func collect(eventCh <-chan Event, messages []ai.Message, pendingToolResults []ai.ToolResult) (*Result, error) {
 	result := &Result{
 		history: store.NewMessageStoreFrom(messages, nil),
 	}
@@ -39,12 +47,28 @@ func (a *Agent) Run(ctx context.Context, messages []ai.Message, opts ...Option)
 	var totalUsage ai.Usage
 	var lastResponse *ai.Response
 	var pendingAssistantMsg *ai.Message
-	var pendingToolResults []ai.ToolResult
+
+	// Tool invocations are indexed by call ID; nested runs forwarded by
+	// sub-agent tools are not part of this run's transcript
+	invocations := make(map[string]int)
+	var depth int
+
+	// Tool results are kept in the order of the calls that requested them
+	var lastCalls []ai.ToolCall
+	if n := len(messages); n > 0 {
+		lastCalls = messages[n-1].ToolCalls
+	}
 
 	for ev := range eventCh {
 		result.Steps = ev.Step
 
 		switch ev.Type {
+		case event.RunStart:
+			depth++
+			if ev.RunID != "" {
+				result.RunID = ev.RunID
+			}
+
 		case event.StepStart:
 			// Commit pending messages from previous step
 			if pendingAssistantMsg != nil {
@@ -52,7 +76,7 @@ func (a *Agent) Run(ctx context.Context, messages []ai.Message, opts ...Option)
 				pendingAssistantMsg = nil
 			}
 			if len(pendingToolResults) > 0 {
-				result.history.Append(ai.NewToolResultMessage(pendingToolResults...))
+				result.history.Append(ai.NewToolResultMessage(mergeResults(lastCalls, pendingToolResults)...))
 				pendingToolResults = nil
 			}
 
@@ -61,8 +85,11 @@ func (a *Agent) Run(ctx context.Context, messages []ai.Message, opts ...Option)
 			if ev.Response != nil {
 				totalUsage.InputTokens += ev.Response.Usage.InputTokens
 				totalUsage.OutputTokens += ev.Response.Usage.OutputTokens
+				totalUsage.CachedInputTokens += ev.Response.Usage.CachedInputTokens
+				totalUsage.CacheWriteInputTokens += ev.Response.Usage.CacheWriteInputTokens
 
 				if len(ev.Response.ToolCalls) > 0 {
+					lastCalls = ev.Response.ToolCalls
 					pendingAssistantMsg = &ai.Message{
 						Role:      ai.RoleAssistant,
 						Content:   ev.Response.Content,
@@ -75,8 +102,48 @@ func (a *Agent) Run(ctx context.Context, messages []ai.Message, opts ...Option)
 			if ev.ToolResult != nil {
 				pendingToolResults = append(pendingToolResults, *ev.ToolResult)
 			}
+			if inv := invocation(result, invocations, ev, depth); inv != nil && ev.ToolResult != nil {
+				tr := *ev.ToolResult
+				inv.Result = &tr
+				if !inv.StartedAt.IsZero() {
+					inv.Duration = ev.Timestamp.Sub(inv.StartedAt)
+					if tr.IsError {
+						inv.Error = tr.Content
+					}
+				}
+			}
+
+		case event.ToolCallStart:
+			if ev.ToolCall != nil && depth <= 1 {
+				invocations[ev.ToolCall.ID] = len(result.ToolInvocations)
+				result.ToolInvocations = append(result.ToolInvocations, ToolInvocation{Step: ev.Step, Call: *ev.ToolCall})
+			}
+
+		case event.ToolCallApproved:
+			if inv := invocation(result, invocations, ev, depth); inv != nil {
+				inv.Approved = true
+			}
+
+		case event.ToolCallRejected:
+			if inv := invocation(result, invocations, ev, depth); inv != nil {
+				inv.RejectionReason = ev.Message
+			}
+
+		case event.ToolCallExecuting:
+			if inv := invocation(result, invocations, ev, depth); inv != nil {
+				inv.StartedAt = ev.Timestamp
+			}
+
+		case event.ActivitySnapshot:
+			if act, ok := ev.ActivityContent.(event.ToolApprovalActivity); ok && act.ResumeToken != "" {
+				result.ResumeToken = act.ResumeToken
+			}
 
 		case event.RunEnd:
+			depth--
+			if len(ev.PendingToolCalls) > 0 && depth <= 0 {
+				result.PendingClientToolCalls = ev.PendingToolCalls
+			}
 			result.Response = ev.Response
 			result.Termination = TerminationReason(ev.Message)
 			if result.Response == nil {
@@ -86,6 +153,10 @@ func (a *Agent) Run(ctx context.Context, messages []ai.Message, opts ...Option)
 		case event.RunError:
 			result.Error = ev.Error
 			result.Termination = TerminationError
+			var budgetErr *ai.ErrBudgetExceeded
+			if errors.As(ev.Error, &budgetErr) {
+				result.Termination = TerminationBudgetExceeded
+			}
 		}
 	}
 
@@ -94,28 +165,93 @@ func (a *Agent) Run(ctx context.Context, messages []ai.Message, opts ...Option)
 		result.history.Append(*pendingAssistantMsg)
 	}
 	if len(pendingToolResults) > 0 {
-		result.history.Append(ai.NewToolResultMessage(pendingToolResults...))
+		result.history.Append(ai.NewToolResultMessage(mergeResults(lastCalls, pendingToolResults)...))
 	}
 
 	result.TotalUsage = totalUsage
+	for _, tc := range result.PendingClientToolCalls {
+		if i, ok := invocations[tc.ID]; ok {
+			result.ToolInvocations[i].Client = true
+		}
+	}
 	return result, result.Error
 }
 
+// invocation returns the transcript entry of the tool call of ev, or nil if
+// ev belongs to a nested run or an unknown call.
+func invocation(result *Result, invocations map[string]int, ev Event, depth int) *ToolInvocation {
+	if ev.ToolCall == nil || depth > 1 {
+		return nil
+	}
+	i, ok := invocations[ev.ToolCall.ID]
+	if !ok {
+		return nil
+	}
+	return &result.ToolInvocations[i]
+}
+
 // RunStream executes the agent loop and returns a channel of events.
 // The channel is closed when the agent completes or encounters a fatal error.
 // Callers should drain the channel to ensure proper cleanup.
+//
+// With WithEventLog, every event is also recorded under the run ID, which
+// the RunStart event carries, so the run can be continued with ResumeStream.
 func (a *Agent) RunStream(ctx context.Context, messages []ai.Message, opts ...Option) <-chan Event {
-	eventCh := event.NewChannel()
+	options := a.applyOptions(opts)
+	if options.EventLog == nil {
+		eventCh := event.NewChannelSize(options.EventBuffer)
+		go a.runLoop(ctx, "", messages, nil, eventCh, opts...)
+		return logRun(ctx, options, eventCh)
+	}
 
-	go a.runLoop(ctx, messages, eventCh, opts...)
+	runID := options.RunID
+	if runID == "" {
+		runID = ai.NewID()
+	}
+	rec := &runRecord{RunID: runID, Messages: messages, SuspendForApproval: options.SuspendForApproval}
+	return a.startLogged(ctx, newEventLog(options.EventLog, rec), messages, nil, opts)
+}
+
+// startLogged runs the agent loop with its events recorded in log.
+func (a *Agent) startLogged(ctx context.Context, log *eventLog, messages []ai.Message, rp *resumePoint, opts []Option) <-chan Event {
+	ctx, cancel := context.WithCancelCause(ctx)
+	options := a.applyOptions(opts)
+	inner := event.NewChannelSize(options.EventBuffer)
+	out := event.NewChannelSize(options.EventBuffer)
 
-	return eventCh
+	go a.runLoop(ctx, log.rec.RunID, messages, rp, inner, opts...)
+	go func() {
+		defer cancel(nil)
+		log.record(ctx, inner, out, cancel)
+	}()
+
+	return logRun(ctx, options, out)
 }
 
-func (a *Agent) runLoop(ctx context.Context, messages []ai.Message, eventCh chan<- Event, opts ...Option) {
+// logRun logs the events of ch to the run's WithLogger logger, if it has
+// one.
+func logRun(ctx context.Context, options *Options, ch <-chan Event) <-chan Event {
+	if options.Logger == nil {
+		return ch
+	}
+	return event.LogStream(ctx, options.Logger, options.LogLevels, ch)
+}
+
+// runLoop runs the agent on messages, or continues from rp if it is
+// non-nil, emitting events to eventCh. runID is reported on RunStart.
+func (a *Agent) runLoop(ctx context.Context, runID string, messages []ai.Message, rp *resumePoint, eventCh chan<- Event, opts ...Option) {
 	defer close(eventCh)
 
-	options := ApplyOptions(opts...)
+	// A panic in a callback such as an approver or stop predicate ends the
+	// run with a RunError instead of crashing the process
+	var step int
+	defer func() {
+		if r := recover(); r != nil {
+			event.Emit(eventCh, Event{Type: event.RunError, Step: step, Error: group.Recovered(r)})
+		}
+	}()
+
+	options := a.applyOptions(opts)
 
 	// Apply overall timeout if specified
 	if options.Timeout > 0 {
@@ -124,16 +260,65 @@ func (a *Agent) runLoop(ctx context.Context, messages []ai.Message, eventCh chan
 		defer cancel()
 	}
 
+	// A dry run also reaches tools and nested agents through the context
+	if options.DryRun {
+		ctx = ai.WithDryRun(ctx)
+	}
+
+	// Tool calls and nested runs share one concurrency budget
+	ctx = ai.WithConcurrencyLimit(ctx, options.RunConcurrency)
+
 	// Emit run start
-	event.Emit(eventCh, Event{Type: event.RunStart})
+	event.Emit(eventCh, Event{Type: event.RunStart, RunID: runID})
+
+	if options.SuspendForApproval && runID == "" {
+		event.Emit(eventCh, Event{Type: event.RunError, Error: fmt.Errorf("%w: WithSuspendForApproval requires it", ErrNoEventLog)})
+		return
+	}
 
-	// Prepare chat options with tools
-	chatOpts := append([]ai.Option{ai.WithTools(a.registry.Tools())}, options.ChatOptions...)
+	// Skills and client tools extend a copy of the agent's registry for
+	// this run
+	skills := uniqueSkills(options.Skills)
+	skillPrompt := skillPrompts(skills)
+	a, err := a.withSkills(skills, ai.ApplyOptions(append(ai.ContextOptions(ctx), options.ChatOptions...)...).Model)
+	if err != nil {
+		event.Emit(eventCh, Event{Type: event.RunError, Error: err})
+		return
+	}
+	a = a.withClientTools(options.ClientTools)
+
+	// Prepare chat options with tools; options attached to the context come
+	// before the agent's own so the agent's settings win
+	chatOpts := append([]ai.Option{ai.WithTools(a.registry.Tools())}, ai.ContextOptions(ctx)...)
+	chatOpts = append(chatOpts, options.ChatOptions...)
 
 	// Copy messages to avoid mutating the original
 	history := store.NewMessageStoreFrom(messages, nil)
 
-	step := 0
+	// A cost budget needs the price of the model the run uses
+	var spent budgetSpend
+	var priced pricedModel
+	if options.Budget > 0 {
+		if priced, err = a.pricedModel(chatOpts); err != nil {
+			event.Emit(eventCh, Event{Type: event.RunError, Error: err})
+			return
+		}
+	}
+
+	// A resumed run first finishes the tool calls of its last step
+	if rp != nil {
+		step = rp.step
+		for _, usage := range rp.usage {
+			spent.add(usage, priced)
+		}
+		if len(rp.calls) > 0 {
+			processResult := a.processToolCalls(ctx, rp.pending(), options, step, eventCh, rp.decisions)
+			processResult.results = mergeResults(rp.calls, rp.results, processResult.results)
+			if a.recordToolResults(ctx, runID, history, processResult, step, rp.response, eventCh) {
+				return
+			}
+		}
+	}
 
 	for {
 		step++
@@ -143,18 +328,69 @@ func (a *Agent) runLoop(ctx context.Context, messages []ai.Message, eventCh chan
 			a.emitComplete(eventCh, step, nil, reason)
 			return
 		}
+		if err := spent.check(options); err != nil {
+			event.Emit(eventCh, Event{Type: event.BudgetExceeded, Step: step, Error: err})
+			event.Emit(eventCh, Event{Type: event.RunError, Step: step, Error: err})
+			return
+		}
 
 		event.Emit(eventCh, Event{Type: event.StepStart, Step: step})
 
-		// Execute chat call with streaming
-		response, err := a.executeStep(ctx, history.Messages(), chatOpts, step, eventCh)
+		// Compact the history, then apply per-step option overrides
+		messages := history.Messages()
+		if options.Memory != nil {
+			compacted, err := options.Memory.Compact(ctx, messages)
+			if err != nil {
+				event.Emit(eventCh, Event{Type: event.RunError, Step: step, Error: err})
+				return
+			}
+			messages = compacted
+		}
+		messages = limitHistory(messages, options.HistoryLimit)
+		stepOpts, stepPriced := chatOpts, priced
+		if options.StepOptions != nil {
+			if extra := options.StepOptions(step, messages); len(extra) > 0 {
+				stepOpts = append(append([]ai.Option{}, chatOpts...), extra...)
+				if options.Budget > 0 {
+					if stepPriced, err = a.pricedModel(stepOpts); err != nil {
+						event.Emit(eventCh, Event{Type: event.RunError, Step: step, Error: err})
+						return
+					}
+				}
+			}
+		}
+
+		messages = withSystemSection(messages, options.SystemPrompt)
+		messages = withSystemSection(messages, skillPrompt)
+		if options.ToolSummary {
+			messages = withSystemSection(messages, a.registry.SystemPrompt())
+		}
+		if options.ContextManager != nil {
+			fitted, err := options.ContextManager.Fit(ctx, messages, stepOpts...)
+			if err != nil {
+				event.Emit(eventCh, Event{Type: event.RunError, Step: step, Error: err})
+				return
+			}
+			messages = fitted
+		}
+
+		// Execute chat call (streaming unless disabled)
+		response, err := a.executeStep(ctx, messages, stepOpts, options, step, eventCh)
 		if err != nil {
 			event.Emit(eventCh, Event{Type: event.RunError, Step: step, Error: err})
 			return
 		}
 
+		spent.add(response.Usage, stepPriced)
+
 		event.Emit(eventCh, Event{Type: event.StepEnd, Step: step, Response: response})
 
+		// A response stopped mid-stream ends the run with its partial content
+		if response.FinishReason == ai.FinishReasonStopped {
+			a.emitComplete(eventCh, step, response, TerminationStopped)
+			return
+		}
+
 		// Check custom stop predicate
 		if options.StopPredicate != nil && options.StopPredicate(step, response) {
 			a.emitComplete(eventCh, step, response, TerminationCustom)
@@ -168,7 +404,7 @@ func (a *Agent) runLoop(ctx context.Context, messages []ai.Message, eventCh chan
 		}
 
 		// Process tool calls
-		processResult := a.processToolCalls(ctx, response.ToolCalls, options, step, eventCh)
+		processResult := a.processToolCalls(ctx, response.ToolCalls, options, step, eventCh, nil)
 
 		// Append assistant message with tool calls to history
 		history.Append(ai.Message{
@@ -177,29 +413,65 @@ func (a *Agent) runLoop(ctx context.Context, messages []ai.Message, eventCh chan
 			ToolCalls: response.ToolCalls,
 		})
 
-		// If there are client tool calls, terminate and let frontend handle
-		if processResult.hasClientTools {
-			// Don't append tool results for client tools - frontend will provide them
-			// Only append results for any backend tools that were executed
-			if len(processResult.results) > 0 {
-				history.Append(ai.NewToolResultMessage(processResult.results...))
-			}
-			a.emitClientToolCall(eventCh, step, response, processResult.clientToolCalls)
+		if a.recordToolResults(ctx, runID, history, processResult, step, response, eventCh) {
 			return
 		}
+	}
+}
 
-		// Append tool results to history
-		history.Append(ai.NewToolResultMessage(processResult.results...))
+// recordToolResults appends the results of a step's tool calls to history
+// and reports whether the run ended because of them.
+func (a *Agent) recordToolResults(ctx context.Context, runID string, history *store.MessageStore, processResult toolCallProcessResult, step int, response *ai.Response, eventCh chan<- Event) bool {
+	// A call waiting for a decision suspends the run with a resume token
+	if tc := processResult.awaitingApproval; tc != nil {
+		token := newResumeToken(runID, tc.ID)
+		event.Emit(eventCh, event.NewToolApprovalSuspended(tc.ID, tc.Name, tc.Arguments, token))
+		a.emitComplete(eventCh, step, response, TerminationSuspended)
+		return true
+	}
 
-		// If all tools were rejected, stop
-		if processResult.allRejected {
-			a.emitComplete(eventCh, step, response, TerminationRejected)
-			return
+	// An approval interrupted by cancellation leaves the calls pending
+	if processResult.interrupted {
+		reason := TerminationCancelled
+		if ctx.Err() == context.DeadlineExceeded {
+			reason = TerminationTimeout
+		}
+		a.emitComplete(eventCh, step, response, reason)
+		return true
+	}
+
+	// If there are client tool calls, terminate and let frontend handle
+	if processResult.hasClientTools {
+		// Don't append tool results for client tools - frontend will provide them
+		// Only append results for any backend tools that were executed
+		if len(processResult.results) > 0 {
+			history.Append(ai.NewToolResultMessage(processResult.results...))
 		}
+		a.emitClientToolCall(eventCh, step, response, processResult.clientToolCalls)
+		return true
 	}
+
+	// Append tool results to history
+	history.Append(ai.NewToolResultMessage(processResult.results...))
+
+	// If all tools were rejected, stop
+	if processResult.allRejected {
+		a.emitComplete(eventCh, step, response, TerminationRejected)
+		return true
+	}
+	return false
 }
 
-func (a *Agent) executeStep(ctx context.Context, messages []ai.Message, chatOpts []ai.Option, step int, eventCh chan<- Event) (*ai.Response, error) {
+func (a *Agent) executeStep(ctx context.Context, messages []ai.Message, chatOpts []ai.Option, options *Options, step int, eventCh chan<- Event) (*ai.Response, error) {
+	if ai.IsDryRun(ctx) {
+		event.Emit(eventCh, Event{Type: event.ChatPlanned, Step: step, Messages: messages, Tools: a.registry.Tools()})
+		return &ai.Response{}, nil
+	}
+
+	if !options.Streaming {
+		return a.executeStepBlocking(ctx, messages, chatOpts, step, eventCh)
+	}
+
 	// Use streaming to emit deltas
 	streamCh, err := a.chatClient.ChatStream(ctx, messages, chatOpts...)
 	if err != nil {
@@ -207,7 +479,7 @@ func (a *Agent) executeStep(ctx context.Context, messages []ai.Message, chatOpts
 	}
 
 	var response *ai.Response
-	messageID := fmt.Sprintf("msg_%d_%d", step, time.Now().UnixNano())
+	messageID := ai.GenerateMessageID()
 	messageStarted := false
 
 	for ev := range streamCh {
@@ -266,15 +538,50 @@ func (a *Agent) executeStep(ctx context.Context, messages []ai.Message, chatOpts
 	return response, nil
 }
 
+// executeStepBlocking performs a non-streaming chat call and emits the same
+// message lifecycle events as a streamed step, with the full content as a
+// single delta.
+func (a *Agent) executeStepBlocking(ctx context.Context, messages []ai.Message, chatOpts []ai.Option, step int, eventCh chan<- Event) (*ai.Response, error) {
+	response, err := a.chatClient.Chat(ctx, messages, chatOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	messageID := ai.GenerateMessageID()
+
+	event.Emit(eventCh, Event{Type: event.MessageStart, Step: step, MessageID: messageID})
+	if response.Content != "" {
+		event.Emit(eventCh, Event{
+			Type:      event.MessageDelta,
+			Step:      step,
+			MessageID: messageID,
+			Delta:     response.Content,
+		})
+	}
+	event.Emit(eventCh, Event{
+		Type:      event.MessageEnd,
+		Step:      step,
+		MessageID: messageID,
+		Response:  response,
+	})
+
+	return response, nil
+}
+
 // toolCallProcessResult contains the outcome of processing tool calls.
 type toolCallProcessResult struct {
 	results          []ai.ToolResult
 	allRejected      bool
 	hasClientTools   bool
 	clientToolCalls  []ai.ToolCall
+	interrupted      bool
+	awaitingApproval *ai.ToolCall
 }
 
-func (a *Agent) processToolCalls(ctx context.Context, toolCalls []ai.ToolCall, options *Options, step int, eventCh chan<- Event) toolCallProcessResult {
+// processToolCalls approves and executes a step's tool calls. Calls with a
+// decision in decisions skip approval; a resumed run uses it for calls
+// decided before the run stopped.
+func (a *Agent) processToolCalls(ctx context.Context, toolCalls []ai.ToolCall, options *Options, step int, eventCh chan<- Event, decisions map[string]ApprovalDecision) toolCallProcessResult {
 	// Separate client tools from backend tools
 	var clientToolCalls []ai.ToolCall
 	var backendToolCalls []ai.ToolCall
@@ -314,11 +621,27 @@ func (a *Agent) processToolCalls(ctx context.Context, toolCalls []ai.ToolCall, o
 			continue
 		}
 
-		if a.requiresApproval(tc.Name, options) {
-			// Emit activity snapshot for pending approval (enables AG-UI approval UI)
-			event.EmitToolApprovalPending(eventCh, tc.ID, tc.Name, tc.Arguments)
-
-			approved, reason := options.Approver(ctx, tc)
+		decision, decided := decisions[tc.ID]
+		if decided || a.requiresApproval(tc.Name, options) {
+			var approved bool
+			var reason string
+			switch {
+			case decided:
+				// Decided before the run was suspended or interrupted
+				approved, reason = decision.Approved, decision.Reason
+			case options.SuspendForApproval:
+				// Stop here; the run continues once the decision arrives
+				return toolCallProcessResult{awaitingApproval: &tc}
+			default:
+				// Emit activity snapshot for pending approval (enables AG-UI approval UI)
+				event.EmitToolApprovalPending(eventCh, tc.ID, tc.Name, tc.Arguments)
+
+				approved, reason = options.Approver(ctx, tc)
+				if ctx.Err() != nil {
+					// Leave the calls unanswered so a resumed run asks again
+					return toolCallProcessResult{interrupted: true}
+				}
+			}
 			approvals[i] = approvalResult{call: tc, approved: approved, reason: reason, isClient: false}
 
 			if approved {
@@ -394,6 +717,11 @@ func (a *Agent) processToolCalls(ctx context.Context, toolCalls []ai.ToolCall, o
 			results = append(results, executedResults[approvedIdx])
 			approvedIdx++
 		} else {
+			// Report rejections like the all-rejected case so the result
+			// history and event log hold every tool result
+			tc := ar.call
+			event.Emit(eventCh, Event{Type: event.ToolCallEnd, Step: step, ToolCall: &tc})
+			event.Emit(eventCh, Event{Type: event.ToolCallResult, Step: step, ToolCall: &tc, ToolResult: &rejectedResults[rejectedIdx]})
 			results = append(results, rejectedResults[rejectedIdx])
 			rejectedIdx++
 		}
@@ -419,17 +747,18 @@ func (a *Agent) executeToolCallsSequential(ctx context.Context, toolCalls []ai.T
 
 func (a *Agent) executeToolCallsParallel(ctx context.Context, toolCalls []ai.ToolCall, options *Options, step int, eventCh chan<- Event) []ai.ToolResult {
 	results := make([]ai.ToolResult, len(toolCalls))
-	var wg sync.WaitGroup
+	var g group.Group
+	g.SetLimit(options.MaxParallelToolCalls)
+	g.SetLimiter(ai.ContextConcurrencyLimiter(ctx))
 
 	for i, tc := range toolCalls {
-		wg.Add(1)
-		go func(idx int, call ai.ToolCall) {
-			defer wg.Done()
-			results[idx] = a.executeToolCall(ctx, call, options, step, eventCh)
-		}(i, tc)
+		g.Go(func() error {
+			results[i] = a.executeToolCall(ctx, tc, options, step, eventCh)
+			return nil
+		})
 	}
 
-	wg.Wait()
+	_ = g.Wait() // Tool failures are reported in results
 	return results
 }
 
@@ -447,7 +776,15 @@ func (a *Agent) executeToolCall(ctx context.Context, tc ai.ToolCall, options *Op
 	// Add event forwarding channel to context for nested runs
 	execCtx = event.WithForwardChannel(execCtx, eventCh)
 
-	result, err := a.registry.Execute(execCtx, tc)
+	// A panicking handler becomes an error result instead of crashing the run
+	var result ai.ToolResult
+	err := group.Call(func() (err error) {
+		result, err = a.registry.Execute(execCtx, tc)
+		return err
+	})
+	if pe, ok := err.(*group.PanicError); ok {
+		err = fmt.Errorf("tool %q panicked: %v", tc.Name, pe.Value)
+	}
 	if err != nil {
 		// Tool not found or other registry error
 		result = ai.ToolResult{
@@ -463,7 +800,7 @@ func (a *Agent) executeToolCall(ctx context.Context, tc ai.ToolCall, options *Op
 }
 
 func (a *Agent) requiresApproval(toolName string, options *Options) bool {
-	if options.Approver == nil {
+	if options.Approver == nil && !options.SuspendForApproval {
 		return false
 	}
 	if len(options.ApprovalRequired) == 0 {
@@ -485,6 +822,9 @@ func (a *Agent) checkTermination(ctx context.Context, step int, response *ai.Res
 		}
 		return TerminationCancelled
 	}
+	if ai.IsStopped(ctx) {
+		return TerminationStopped
+	}
 
 	// Check max steps (step is 1-indexed, check before executing)
 	if options.MaxSteps > 0 && step > options.MaxSteps {
@@ -494,6 +834,122 @@ func (a *Agent) checkTermination(ctx context.Context, step int, response *ai.Res
 	return ""
 }
 
+// withSystemSection returns messages with section appended to the leading
+// system message, or with a new system message prepended if there is none.
+// The input slice is not modified.
+func withSystemSection(messages []ai.Message, section string) []ai.Message {
+	if section == "" {
+		return messages
+	}
+	if len(messages) > 0 && messages[0].Role == ai.RoleSystem {
+		result := append([]ai.Message{}, messages...)
+		result[0].Content += "\n\n" + section
+		return result
+	}
+	return append([]ai.Message{{Role: ai.RoleSystem, Content: section}}, messages...)
+}
+
+// limitHistory returns the system messages and the last limit other
+// messages, moving the window back past any leading tool results so they
+// stay with the assistant message that requested them. A limit of 0 or less
+// returns messages unchanged.
+func limitHistory(messages []ai.Message, limit int) []ai.Message {
+	if limit <= 0 {
+		return messages
+	}
+	var system, rest []ai.Message
+	for _, m := range messages {
+		if m.Role == ai.RoleSystem {
+			system = append(system, m)
+		} else {
+			rest = append(rest, m)
+		}
+	}
+	if len(rest) <= limit {
+		return messages
+	}
+	start := len(rest) - limit
+	for start > 0 && rest[start].Role == ai.RoleTool {
+		start--
+	}
+	return append(system, rest[start:]...)
+}
+
+// Options returns the options the agent's runs start from: its defaults,
+// such as those set by a Builder, applied over the package defaults.
+// Options passed to Run are applied on top.
+func (a *Agent) Options() *Options {
+	return a.applyOptions(nil)
+}
+
+// applyOptions applies the agent's default options followed by opts.
+func (a *Agent) applyOptions(opts []Option) *Options {
+	if len(a.defaults) == 0 {
+		return ApplyOptions(opts...)
+	}
+	return ApplyOptions(append(append([]Option{}, a.defaults...), opts...)...)
+}
+
+// budgetSpend accumulates the cost and tokens of a run's chat calls.
+type budgetSpend struct {
+	cost   float64
+	tokens int
+}
+
+// pricedModel is a model with pricing, such as a model.ChatModel.
+type pricedModel interface {
+	Cost(ai.Usage) float64
+}
+
+// add records usage from a chat call made with m, pricing it if m is not
+// nil.
+func (b *budgetSpend) add(usage ai.Usage, m pricedModel) {
+	b.tokens += usage.InputTokens + usage.OutputTokens
+	if m != nil {
+		b.cost += m.Cost(usage)
+	}
+}
+
+// pricedModel returns the model a chat call with opts uses: the model the
+// chat client resolves opts to if it is an ai.ModelResolver, such as
+// client.Client with its default model and aliases, and otherwise the model
+// set in opts. The error matches ErrUnpricedModel if that model has no
+// pricing.
+func (a *Agent) pricedModel(opts []ai.Option) (pricedModel, error) {
+	m := ai.ApplyOptions(opts...).Model
+	if resolver, ok := a.chatClient.(ai.ModelResolver); ok {
+		resolved, err := resolver.ChatModel(opts...)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnpricedModel, err)
+		}
+		m = resolved
+	}
+	priced, ok := m.(pricedModel)
+	if !ok {
+		name := "no model"
+		if m != nil {
+			name = m.String()
+		}
+		return nil, fmt.Errorf("%w: %s", ErrUnpricedModel, name)
+	}
+	return priced, nil
+}
+
+// check returns *ai.ErrBudgetExceeded if a configured budget has been reached.
+func (b *budgetSpend) check(options *Options) error {
+	costExceeded := options.Budget > 0 && b.cost >= options.Budget
+	tokensExceeded := options.TokenBudget > 0 && b.tokens >= options.TokenBudget
+	if !costExceeded && !tokensExceeded {
+		return nil
+	}
+	return &ai.ErrBudgetExceeded{
+		MaxCost:   options.Budget,
+		Cost:      b.cost,
+		MaxTokens: options.TokenBudget,
+		Tokens:    b.tokens,
+	}
+}
+
 func (a *Agent) emitComplete(ch chan<- Event, step int, response *ai.Response, reason TerminationReason) {
 	event.Emit(ch, Event{
 		Type:     event.RunEnd,
@@ -503,12 +959,29 @@ func (a *Agent) emitComplete(ch chan<- Event, step int, response *ai.Response, r
 	})
 }
 
+// withClientTools returns a copy of the agent whose registry is a copy of
+// its own with tools added as client tools, or a itself if there are none.
+func (a *Agent) withClientTools(tools []ai.Tool) *Agent {
+	if len(tools) == 0 {
+		return a
+	}
+	registry := a.registry.Clone()
+	for _, t := range tools {
+		if _, exists := registry.GetTool(t.Name); !exists {
+			registry.RegisterClientTool(t)
+		}
+	}
+	withTools := *a
+	withTools.registry = registry
+	return &withTools
+}
+
 func (a *Agent) emitClientToolCall(ch chan<- Event, step int, response *ai.Response, clientToolCalls []ai.ToolCall) {
 	event.Emit(ch, Event{
-		Type:            event.RunEnd,
-		Step:            step,
-		Response:        response,
-		Message:         string(TerminationClientToolCall),
+		Type:             event.RunEnd,
+		Step:             step,
+		Response:         response,
+		Message:          string(TerminationClientToolCall),
 		PendingToolCalls: clientToolCalls,
 	})
 }
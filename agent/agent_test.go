@@ -1,9 +1,13 @@
 package agent
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -11,6 +15,8 @@ import (
 
 	ai "github.com/spetersoncode/gains"
 	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/memory"
+	"github.com/spetersoncode/gains/model"
 	"github.com/spetersoncode/gains/tool"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,9 +29,10 @@ type mockProvider struct {
 }
 
 type mockResponse struct {
-	content   string
-	toolCalls []ai.ToolCall
-	err       error
+	content      string
+	toolCalls    []ai.ToolCall
+	finishReason string
+	err          error
 }
 
 func (m *mockProvider) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
@@ -38,9 +45,10 @@ func (m *mockProvider) Chat(ctx context.Context, messages []ai.Message, opts ...
 		return nil, resp.err
 	}
 	return &ai.Response{
-		Content:   resp.content,
-		ToolCalls: resp.toolCalls,
-		Usage:     ai.Usage{InputTokens: 10, OutputTokens: 20},
+		Content:      resp.content,
+		ToolCalls:    resp.toolCalls,
+		FinishReason: resp.finishReason,
+		Usage:        ai.Usage{InputTokens: 10, OutputTokens: 20},
 	}, nil
 }
 
@@ -98,9 +106,10 @@ func (m *mockProvider) ChatStream(ctx context.Context, messages []ai.Message, op
 			Type:      event.MessageEnd,
 			MessageID: msgID,
 			Response: &ai.Response{
-				Content:   resp.content,
-				ToolCalls: resp.toolCalls,
-				Usage:     ai.Usage{InputTokens: 10, OutputTokens: 20},
+				Content:      resp.content,
+				ToolCalls:    resp.toolCalls,
+				FinishReason: resp.finishReason,
+				Usage:        ai.Usage{InputTokens: 10, OutputTokens: 20},
 			},
 		}
 	}()
@@ -259,6 +268,7 @@ func TestApplyOptions(t *testing.T) {
 		assert.Equal(t, 10, opts.MaxSteps)
 		assert.Equal(t, 30*time.Second, opts.HandlerTimeout)
 		assert.True(t, opts.ParallelToolCalls)
+		assert.True(t, opts.Streaming)
 	})
 
 	t.Run("applies custom options", func(t *testing.T) {
@@ -267,12 +277,14 @@ func TestApplyOptions(t *testing.T) {
 			WithTimeout(time.Minute),
 			WithHandlerTimeout(10*time.Second),
 			WithParallelToolCalls(false),
+			WithStreaming(false),
 		)
 
 		assert.Equal(t, 5, opts.MaxSteps)
 		assert.Equal(t, time.Minute, opts.Timeout)
 		assert.Equal(t, 10*time.Second, opts.HandlerTimeout)
 		assert.False(t, opts.ParallelToolCalls)
+		assert.False(t, opts.Streaming)
 	})
 }
 
@@ -334,6 +346,31 @@ func TestAgent_Run_WithToolCalls(t *testing.T) {
 	assert.True(t, len(result.Messages()) > 1)
 }
 
+func TestAgent_Run_Logger(t *testing.T) {
+	provider := &mockProvider{
+		responses: []mockResponse{
+			{toolCalls: []ai.ToolCall{{ID: "call_1", Name: "lookup", Arguments: `{}`}}},
+			{content: "Done"},
+		},
+	}
+	registry := tool.NewRegistry()
+	registry.MustRegister(
+		ai.Tool{Name: "lookup", Parameters: json.RawMessage(`{"type":"object"}`)},
+		func(ctx context.Context, call ai.ToolCall) (string, error) { return "ok", nil },
+	)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	_, err := New(provider, registry).Run(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}, WithLogger(logger))
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, `msg="run started"`)
+	assert.Contains(t, out, `msg="step finished"`)
+	assert.Contains(t, out, "tool=lookup")
+	assert.Contains(t, out, `msg="run finished"`)
+}
+
 func TestAgent_Run_MaxSteps(t *testing.T) {
 	// Provider always returns tool calls, causing infinite loop
 	provider := &mockProvider{
@@ -365,6 +402,138 @@ func TestAgent_Run_MaxSteps(t *testing.T) {
 	assert.Equal(t, 3, result.Steps)
 }
 
+func TestAgent_Run_Budget(t *testing.T) {
+	loopingProvider := func() *mockProvider {
+		return &mockProvider{
+			responses: []mockResponse{
+				{content: "Step 1", toolCalls: []ai.ToolCall{{ID: "c1", Name: "tool1", Arguments: "{}"}}},
+				{content: "Step 2", toolCalls: []ai.ToolCall{{ID: "c2", Name: "tool1", Arguments: "{}"}}},
+				{content: "Step 3", toolCalls: []ai.ToolCall{{ID: "c3", Name: "tool1", Arguments: "{}"}}},
+				{content: "Done"},
+			},
+		}
+	}
+	newRegistry := func() *tool.Registry {
+		registry := tool.NewRegistry()
+		registry.MustRegister(
+			ai.Tool{Name: "tool1"},
+			func(ctx context.Context, call ai.ToolCall) (string, error) { return "ok", nil },
+		)
+		return registry
+	}
+
+	t.Run("token budget stops the run", func(t *testing.T) {
+		provider := loopingProvider()
+		agent := New(provider, newRegistry())
+
+		// Each mock step uses 30 tokens
+		result, err := agent.Run(context.Background(), []ai.Message{
+			{Role: ai.RoleUser, Content: "Go"},
+		}, WithTokenBudget(50))
+
+		var budgetErr *ai.ErrBudgetExceeded
+		require.ErrorAs(t, err, &budgetErr)
+		assert.Equal(t, 50, budgetErr.MaxTokens)
+		assert.Equal(t, 60, budgetErr.Tokens)
+		assert.Equal(t, TerminationBudgetExceeded, result.Termination)
+		assert.Equal(t, 2, provider.callCount)
+	})
+
+	t.Run("cost budget uses model pricing", func(t *testing.T) {
+		provider := loopingProvider()
+		agent := New(provider, newRegistry())
+
+		// Sonnet 4.5 at 10 input + 20 output tokens costs $0.00033 per step
+		var events []Event
+		for ev := range agent.RunStream(context.Background(), []ai.Message{
+			{Role: ai.RoleUser, Content: "Go"},
+		}, WithModel(model.ClaudeSonnet45), WithBudget(0.0005)) {
+			events = append(events, ev)
+		}
+
+		assert.Equal(t, 2, provider.callCount)
+		var budgetEvent *Event
+		for i := range events {
+			if events[i].Type == event.BudgetExceeded {
+				budgetEvent = &events[i]
+			}
+		}
+		require.NotNil(t, budgetEvent)
+		var budgetErr *ai.ErrBudgetExceeded
+		require.ErrorAs(t, budgetEvent.Error, &budgetErr)
+		assert.InDelta(t, 0.00066, budgetErr.Cost, 0.000001)
+		assert.Equal(t, event.RunError, events[len(events)-1].Type)
+	})
+
+	t.Run("run completes within budget", func(t *testing.T) {
+		agent := New(loopingProvider(), newRegistry())
+
+		result, err := agent.Run(context.Background(), []ai.Message{
+			{Role: ai.RoleUser, Content: "Go"},
+		}, WithTokenBudget(1000))
+
+		require.NoError(t, err)
+		assert.Equal(t, TerminationComplete, result.Termination)
+	})
+
+	t.Run("cost budget prices the client's default model", func(t *testing.T) {
+		provider := &resolvingProvider{mockProvider: loopingProvider(), model: model.ClaudeSonnet45}
+		agent := New(provider, newRegistry())
+
+		_, err := agent.Run(context.Background(), []ai.Message{
+			{Role: ai.RoleUser, Content: "Go"},
+		}, WithBudget(0.0005))
+
+		var budgetErr *ai.ErrBudgetExceeded
+		require.ErrorAs(t, err, &budgetErr)
+		assert.InDelta(t, 0.00066, budgetErr.Cost, 0.000001)
+		assert.Equal(t, 2, provider.callCount)
+	})
+
+	t.Run("cost budget requires a priced model", func(t *testing.T) {
+		provider := loopingProvider()
+		agent := New(provider, newRegistry())
+
+		_, err := agent.Run(context.Background(), []ai.Message{
+			{Role: ai.RoleUser, Content: "Go"},
+		}, WithBudget(1))
+
+		assert.ErrorIs(t, err, ErrUnpricedModel)
+		assert.Zero(t, provider.callCount)
+	})
+
+	t.Run("cost budget requires a priced step model", func(t *testing.T) {
+		provider := loopingProvider()
+		agent := New(provider, newRegistry())
+
+		_, err := agent.Run(context.Background(), []ai.Message{
+			{Role: ai.RoleUser, Content: "Go"},
+		}, WithModel(model.ClaudeSonnet45), WithBudget(1), WithStepOptions(func(step int, _ []ai.Message) []ai.Option {
+			if step == 2 {
+				return []ai.Option{ai.WithModel(model.Alias("cheap-chat"))}
+			}
+			return nil
+		}))
+
+		assert.ErrorIs(t, err, ErrUnpricedModel)
+		assert.Equal(t, 1, provider.callCount)
+	})
+}
+
+// resolvingProvider resolves requests without a model to model, as
+// client.Client does with its default chat model.
+type resolvingProvider struct {
+	*mockProvider
+	model ai.Model
+}
+
+func (p *resolvingProvider) ChatModel(opts ...ai.Option) (ai.Model, error) {
+	if m := ai.ApplyOptions(opts...).Model; m != nil {
+		return m, nil
+	}
+	return p.model, nil
+}
+
 func TestAgent_Run_Timeout(t *testing.T) {
 	provider := &mockProvider{
 		responses: []mockResponse{
@@ -414,6 +583,32 @@ func TestAgent_Run_CustomStopPredicate(t *testing.T) {
 	assert.Equal(t, TerminationCustom, result.Termination)
 }
 
+func TestAgent_Run_Stopped(t *testing.T) {
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "Go"}}
+
+	t.Run("stopped response ends the run", func(t *testing.T) {
+		provider := &mockProvider{responses: []mockResponse{
+			{content: "Partial", finishReason: ai.FinishReasonStopped},
+			{content: "Never sent"},
+		}}
+		result, err := New(provider, tool.NewRegistry()).Run(context.Background(), messages)
+		require.NoError(t, err)
+		assert.Equal(t, TerminationStopped, result.Termination)
+		assert.Equal(t, "Partial", result.Response.Content)
+		assert.Equal(t, 1, provider.callCount)
+	})
+
+	t.Run("stopped context starts no step", func(t *testing.T) {
+		ctx, stop := ai.WithStop(context.Background())
+		stop()
+		provider := &mockProvider{responses: []mockResponse{{content: "Never sent"}}}
+		result, err := New(provider, tool.NewRegistry()).Run(ctx, messages)
+		require.NoError(t, err)
+		assert.Equal(t, TerminationStopped, result.Termination)
+		assert.Equal(t, 0, provider.callCount)
+	})
+}
+
 func TestAgent_Run_Approval(t *testing.T) {
 	t.Run("approved tool executes", func(t *testing.T) {
 		provider := &mockProvider{
@@ -536,6 +731,221 @@ func TestAgent_RunStream_Events(t *testing.T) {
 	assert.Contains(t, eventTypes, event.RunEnd)
 }
 
+func TestAgent_RunStream_StreamingDisabled(t *testing.T) {
+	provider := &mockProvider{
+		responses: []mockResponse{
+			{content: "Calling tool", toolCalls: []ai.ToolCall{{ID: "c1", Name: "tool1", Arguments: "{}"}}},
+			{content: "Done"},
+		},
+	}
+
+	registry := tool.NewRegistry()
+	registry.MustRegister(
+		ai.Tool{Name: "tool1"},
+		func(ctx context.Context, call ai.ToolCall) (string, error) { return "result", nil },
+	)
+
+	agent := New(provider, registry)
+
+	events := agent.RunStream(context.Background(), []ai.Message{
+		{Role: ai.RoleUser, Content: "Go"},
+	}, WithStreaming(false))
+
+	var deltas []string
+	var final *ai.Response
+	for ev := range events {
+		switch ev.Type {
+		case event.MessageDelta:
+			deltas = append(deltas, ev.Delta)
+		case event.RunEnd:
+			final = ev.Response
+		}
+	}
+
+	// Each step emits its full content as a single delta
+	assert.Equal(t, []string{"Calling tool", "Done"}, deltas)
+	require.NotNil(t, final)
+	assert.Equal(t, "Done", final.Content)
+	assert.Equal(t, 2, provider.callCount)
+}
+
+func TestWithSystemSection(t *testing.T) {
+	t.Run("appends to leading system message", func(t *testing.T) {
+		messages := []ai.Message{
+			{Role: ai.RoleSystem, Content: "Be brief."},
+			{Role: ai.RoleUser, Content: "Hi"},
+		}
+		result := withSystemSection(messages, "## Available Tools")
+
+		require.Len(t, result, 2)
+		assert.Equal(t, "Be brief.\n\n## Available Tools", result[0].Content)
+		assert.Equal(t, "Be brief.", messages[0].Content, "input not modified")
+	})
+
+	t.Run("prepends system message when absent", func(t *testing.T) {
+		messages := []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}
+		result := withSystemSection(messages, "## Available Tools")
+
+		require.Len(t, result, 2)
+		assert.Equal(t, ai.RoleSystem, result[0].Role)
+		assert.Equal(t, "## Available Tools", result[0].Content)
+	})
+
+	t.Run("empty section leaves messages unchanged", func(t *testing.T) {
+		messages := []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}
+		assert.Equal(t, messages, withSystemSection(messages, ""))
+	})
+}
+
+// optionsRecorder wraps mockProvider and records the options of each call.
+type optionsRecorder struct {
+	*mockProvider
+	calls []*ai.Options
+}
+
+func (r *optionsRecorder) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+	r.calls = append(r.calls, ai.ApplyOptions(opts...))
+	return r.mockProvider.ChatStream(ctx, messages, opts...)
+}
+
+func TestAgent_Run_StepOptions(t *testing.T) {
+	provider := &optionsRecorder{mockProvider: &mockProvider{
+		responses: []mockResponse{
+			{content: "Calling tool", toolCalls: []ai.ToolCall{{ID: "c1", Name: "tool1", Arguments: "{}"}}},
+			{content: "Done"},
+		},
+	}}
+
+	registry := tool.NewRegistry()
+	registry.MustRegister(
+		ai.Tool{Name: "tool1"},
+		func(ctx context.Context, call ai.ToolCall) (string, error) { return "result", nil },
+	)
+
+	var historyLens []int
+	agent := New(provider, registry)
+
+	_, err := agent.Run(context.Background(), []ai.Message{
+		{Role: ai.RoleUser, Content: "Go"},
+	},
+		WithTemperature(0.9),
+		WithStepOptions(func(step int, history []ai.Message) []ai.Option {
+			historyLens = append(historyLens, len(history))
+			if step > 1 {
+				return []ai.Option{ai.WithTemperature(0.1)}
+			}
+			return nil
+		}),
+	)
+
+	require.NoError(t, err)
+	require.Len(t, provider.calls, 2)
+	assert.Equal(t, 0.9, *provider.calls[0].Temperature)
+	assert.Equal(t, 0.1, *provider.calls[1].Temperature)
+	assert.Len(t, provider.calls[1].Tools, 1, "step overrides keep agent-wide options")
+	assert.Equal(t, []int{1, 3}, historyLens)
+}
+
+func TestAgent_Run_ContextOptions(t *testing.T) {
+	provider := &optionsRecorder{mockProvider: &mockProvider{
+		responses: []mockResponse{{content: "Done"}},
+	}}
+	agent := New(provider, tool.NewRegistry())
+
+	ctx := ai.WithContextOptions(context.Background(),
+		ai.WithTemperature(0.5),
+		ai.WithMaxTokens(256),
+		ai.WithMetadata("tenant", "acme"),
+	)
+	_, err := agent.Run(ctx, []ai.Message{{Role: ai.RoleUser, Content: "Go"}}, WithTemperature(0.9))
+
+	require.NoError(t, err)
+	require.Len(t, provider.calls, 1)
+	assert.Equal(t, 0.9, *provider.calls[0].Temperature, "agent options override context options")
+	assert.Equal(t, 256, provider.calls[0].MaxTokens)
+	assert.Equal(t, "acme", provider.calls[0].Metadata["tenant"])
+}
+
+func TestAgent_Run_Memory(t *testing.T) {
+	provider := &recordingProvider{mockProvider: mockProvider{
+		responses: []mockResponse{{content: "Done"}},
+	}}
+	agent := New(provider, tool.NewRegistry())
+
+	messages := []ai.Message{
+		{Role: ai.RoleUser, Content: "First"},
+		{Role: ai.RoleAssistant, Content: "Reply"},
+		{Role: ai.RoleUser, Content: "Second"},
+	}
+	result, err := agent.Run(context.Background(), messages, WithMemory(memory.NewWindow(1)))
+	require.NoError(t, err)
+
+	require.Len(t, provider.messages, 1)
+	assert.Equal(t, messages[2:], provider.messages[0], "only the compacted history is sent")
+	assert.Equal(t, messages, result.Messages(), "the run keeps the full history")
+
+	t.Run("memory errors end the run", func(t *testing.T) {
+		failing := memory.Func(func(ctx context.Context, messages []ai.Message) ([]ai.Message, error) {
+			return nil, errors.New("store unavailable")
+		})
+		_, err := agent.Run(context.Background(), messages, WithMemory(failing))
+		assert.ErrorContains(t, err, "store unavailable")
+	})
+}
+
+func TestAgent_Run_ContextManager(t *testing.T) {
+	provider := &recordingProvider{mockProvider: mockProvider{
+		responses: []mockResponse{{content: "Done"}},
+	}}
+	agent := New(provider, tool.NewRegistry())
+
+	long := strings.Repeat("x", 400)
+	messages := []ai.Message{
+		{Role: ai.RoleUser, Content: long},
+		{Role: ai.RoleAssistant, Content: long},
+		{Role: ai.RoleUser, Content: "Latest"},
+	}
+	cm := ai.NewContextManager(ai.WithContextLimit(100), ai.WithOutputReserve(0))
+	result, err := agent.Run(context.Background(), messages, WithContextManager(cm))
+	require.NoError(t, err)
+
+	require.Len(t, provider.messages, 1)
+	assert.Equal(t, messages[2:], provider.messages[0], "older messages are trimmed")
+	assert.Equal(t, messages, result.Messages(), "the run keeps the full history")
+}
+
+func TestAgent_RunStream_DryRun(t *testing.T) {
+	provider := &mockProvider{responses: []mockResponse{{content: "Done"}}}
+	registry := tool.NewRegistry()
+	tool.MustRegisterFunc(registry, "get_weather", "Get the weather",
+		func(ctx context.Context, args struct{}) (string, error) {
+			return "sunny", nil
+		},
+	)
+	agent := New(provider, registry)
+
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "Weather?"}}
+	var planned []Event
+	var ended bool
+	for e := range agent.RunStream(context.Background(), messages, WithDryRun()) {
+		switch e.Type {
+		case event.ChatPlanned:
+			planned = append(planned, e)
+		case event.RunEnd:
+			ended = true
+			assert.Equal(t, string(TerminationComplete), e.Message)
+		}
+	}
+
+	assert.Equal(t, 0, provider.callCount, "the model is not called")
+	require.Len(t, planned, 1)
+	assert.Equal(t, 1, planned[0].Step)
+	assert.Equal(t, messages, planned[0].Messages)
+	require.Len(t, planned[0].Tools, 1)
+	assert.Equal(t, "get_weather", planned[0].Tools[0].Name)
+	assert.True(t, ended)
+}
+
 func TestAgent_ParallelToolCalls(t *testing.T) {
 	var executionOrder []string
 	var mu sync.Mutex
@@ -582,8 +992,237 @@ func TestAgent_ParallelToolCalls(t *testing.T) {
 	assert.Len(t, executionOrder, 3)
 }
 
+func TestAgent_MaxParallelToolCalls(t *testing.T) {
+	var active, peak atomic.Int32
+
+	provider := &mockProvider{
+		responses: []mockResponse{
+			{
+				content: "Calling tools",
+				toolCalls: []ai.ToolCall{
+					{ID: "c1", Name: "slow", Arguments: "{}"},
+					{ID: "c2", Name: "slow", Arguments: "{}"},
+					{ID: "c3", Name: "slow", Arguments: "{}"},
+					{ID: "c4", Name: "slow", Arguments: "{}"},
+				},
+			},
+			{content: "Done"},
+		},
+	}
+
+	registry := tool.NewRegistry()
+	registry.MustRegister(
+		ai.Tool{Name: "slow"},
+		func(ctx context.Context, call ai.ToolCall) (string, error) {
+			n := active.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			active.Add(-1)
+			return "ok", nil
+		},
+	)
+
+	agent := New(provider, registry)
+	_, err := agent.Run(context.Background(), []ai.Message{
+		{Role: ai.RoleUser, Content: "Go"},
+	}, WithMaxParallelToolCalls(2))
+
+	require.NoError(t, err)
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+}
+
+// fanOutClient answers a user message with calls to tool and anything else
+// with a final response. It is stateless, so concurrent runs can share it.
+type fanOutClient struct {
+	tool  string
+	calls int
+}
+
+func (f *fanOutClient) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	if messages[len(messages)-1].Role != ai.RoleUser {
+		return &ai.Response{Content: "Done"}, nil
+	}
+	resp := &ai.Response{}
+	for i := range f.calls {
+		resp.ToolCalls = append(resp.ToolCalls, ai.ToolCall{ID: fmt.Sprintf("c%d", i), Name: f.tool, Arguments: `{"query":"go"}`})
+	}
+	return resp, nil
+}
+
+func (f *fanOutClient) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestAgent_RunConcurrency(t *testing.T) {
+	var active, peak atomic.Int32
+	workers := tool.NewRegistry()
+	workers.MustRegister(
+		ai.Tool{Name: "slow"},
+		func(ctx context.Context, call ai.ToolCall) (string, error) {
+			n := active.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			active.Add(-1)
+			return "ok", nil
+		},
+	)
+	sub := New(&fanOutClient{tool: "slow", calls: 3}, workers)
+
+	registry := tool.NewRegistry()
+	registry.Add(NewTool("sub", sub, WithToolAgentOptions(WithStreaming(false))))
+	parent := New(&fanOutClient{tool: "sub", calls: 3}, registry)
+
+	_, err := parent.Run(context.Background(), []ai.Message{
+		{Role: ai.RoleUser, Content: "Go"},
+	}, WithStreaming(false), WithRunConcurrency(3))
+
+	require.NoError(t, err)
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+}
+
+func TestAgent_ToolPanic(t *testing.T) {
+	for _, parallel := range []bool{true, false} {
+		provider := &mockProvider{
+			responses: []mockResponse{
+				{
+					content: "Calling tools",
+					toolCalls: []ai.ToolCall{
+						{ID: "c1", Name: "boom", Arguments: "{}"},
+						{ID: "c2", Name: "ok", Arguments: "{}"},
+					},
+				},
+				{content: "Done"},
+			},
+		}
+
+		registry := tool.NewRegistry()
+		registry.MustRegister(ai.Tool{Name: "boom"}, func(ctx context.Context, call ai.ToolCall) (string, error) {
+			panic("nil map")
+		})
+		registry.MustRegister(ai.Tool{Name: "ok"}, func(ctx context.Context, call ai.ToolCall) (string, error) {
+			return "fine", nil
+		})
+
+		result, err := New(provider, registry).Run(context.Background(), []ai.Message{
+			{Role: ai.RoleUser, Content: "Go"},
+		}, WithParallelToolCalls(parallel))
+
+		require.NoError(t, err)
+		results := make(map[string]ai.ToolResult)
+		for _, m := range result.Messages() {
+			for _, r := range m.ToolResults {
+				results[r.ToolCallID] = r
+			}
+		}
+		require.Len(t, results, 2)
+		assert.True(t, results["c1"].IsError)
+		assert.Equal(t, `tool "boom" panicked: nil map`, results["c1"].Content)
+		assert.Equal(t, "fine", results["c2"].Content)
+		assert.Equal(t, "Done", result.Response.Content)
+	}
+}
+
+func TestAgent_RecoversLoopPanic(t *testing.T) {
+	provider := &mockProvider{
+		responses: []mockResponse{{content: "Done"}},
+	}
+	agent := New(provider, tool.NewRegistry())
+
+	events := agent.RunStream(context.Background(), []ai.Message{
+		{Role: ai.RoleUser, Content: "Go"},
+	}, WithStopPredicate(func(step int, response *ai.Response) bool {
+		panic("bad predicate")
+	}))
+
+	var runErr *Event
+	for ev := range events {
+		if ev.Type == event.RunError {
+			runErr = &ev
+		}
+	}
+
+	require.NotNil(t, runErr, "channel closes after a RunError")
+	var panicErr *ai.PanicError
+	require.ErrorAs(t, runErr.Error, &panicErr)
+	assert.Equal(t, "bad predicate", panicErr.Value)
+	assert.NotEmpty(t, panicErr.Stack)
+	assert.Equal(t, 1, runErr.Step)
+}
+
 // --- Error Tests ---
 
+func TestAgent_ToolInvocations(t *testing.T) {
+	provider := &mockProvider{
+		responses: []mockResponse{
+			{toolCalls: []ai.ToolCall{
+				{ID: "c1", Name: "lookup", Arguments: "{}"},
+				{ID: "c2", Name: "delete", Arguments: "{}"},
+				{ID: "c3", Name: "broken", Arguments: "{}"},
+			}},
+			{toolCalls: []ai.ToolCall{{ID: "c4", Name: "confirm", Arguments: "{}"}}},
+		},
+	}
+
+	registry := tool.NewRegistry()
+	registry.MustRegister(ai.Tool{Name: "lookup"}, func(ctx context.Context, call ai.ToolCall) (string, error) {
+		time.Sleep(5 * time.Millisecond)
+		return "found", nil
+	})
+	registry.MustRegister(ai.Tool{Name: "delete"}, func(ctx context.Context, call ai.ToolCall) (string, error) {
+		return "deleted", nil
+	})
+	registry.MustRegister(ai.Tool{Name: "broken"}, func(ctx context.Context, call ai.ToolCall) (string, error) {
+		return "", errors.New("backend down")
+	})
+	require.NoError(t, registry.RegisterClientTool(ai.Tool{Name: "confirm"}))
+
+	result, err := New(provider, registry).Run(context.Background(), []ai.Message{
+		{Role: ai.RoleUser, Content: "Clean up"},
+	}, WithApprovalRequired("delete"), WithApprover(func(ctx context.Context, call ai.ToolCall) (bool, string) {
+		return false, "not allowed"
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, TerminationClientToolCall, result.Termination)
+	require.Len(t, result.ToolInvocations, 4)
+
+	lookup := result.ToolInvocations[0]
+	assert.Equal(t, 1, lookup.Step)
+	assert.Equal(t, "lookup", lookup.Call.Name)
+	assert.True(t, lookup.Approved)
+	require.NotNil(t, lookup.Result)
+	assert.Equal(t, "found", lookup.Result.Content)
+	assert.False(t, lookup.StartedAt.IsZero())
+	assert.GreaterOrEqual(t, lookup.Duration, 5*time.Millisecond)
+	assert.Empty(t, lookup.Error)
+
+	rejected := result.ToolInvocations[1]
+	assert.False(t, rejected.Approved)
+	assert.Equal(t, "not allowed", rejected.RejectionReason)
+	require.NotNil(t, rejected.Result)
+	assert.True(t, rejected.Result.IsError)
+	assert.Zero(t, rejected.Duration)
+
+	broken := result.ToolInvocations[2]
+	assert.True(t, broken.Approved)
+	assert.Contains(t, broken.Error, "backend down")
+
+	confirm := result.ToolInvocations[3]
+	assert.Equal(t, 2, confirm.Step)
+	assert.True(t, confirm.Client)
+	assert.Nil(t, confirm.Result)
+	assert.Equal(t, []ai.ToolCall{confirm.Call}, result.PendingClientToolCalls)
+}
+
 func TestErrors(t *testing.T) {
 	t.Run("ErrToolNotFound", func(t *testing.T) {
 		err := &tool.ErrToolNotFound{Name: "missing_tool"}
@@ -630,4 +1269,5 @@ func TestTerminationReason_Constants(t *testing.T) {
 	assert.Equal(t, TerminationReason("rejected"), TerminationRejected)
 	assert.Equal(t, TerminationReason("error"), TerminationError)
 	assert.Equal(t, TerminationReason("cancelled"), TerminationCancelled)
+	assert.Equal(t, TerminationReason("budget_exceeded"), TerminationBudgetExceeded)
 }
@@ -0,0 +1,183 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/chat"
+	"github.com/spetersoncode/gains/memory"
+	"github.com/spetersoncode/gains/tool"
+)
+
+// Builder configures an Agent fluently. Settings become the agent's default
+// options; options passed to Run or RunStream are applied after them and
+// take precedence.
+//
+//	a, err := agent.NewBuilder(client).
+//	    Model(model.ClaudeSonnet45).
+//	    SystemPrompt("You help {{.Team}} engineers.").
+//	    PromptData(map[string]string{"Team": "platform"}).
+//	    Tools(tool.WebTools()...).
+//	    MaxSteps(8).
+//	    Budget(0.50).
+//	    Build()
+//
+// Start from a preset such as CodingAgent to get reviewed defaults, then
+// override what differs.
+type Builder struct {
+	client   chat.Client
+	registry *tool.Registry
+	tools    []tool.ToolPair
+	prompt   string
+	data     any
+	opts     []Option
+}
+
+// NewBuilder starts building an agent that uses the given chat client.
+func NewBuilder(c chat.Client) *Builder {
+	return &Builder{client: c}
+}
+
+// Model sets the model for the agent's chat calls.
+func (b *Builder) Model(m ai.Model) *Builder {
+	return b.Options(WithModel(m))
+}
+
+// SystemPrompt sets the agent's system prompt. The prompt is a text/template
+// rendered once at Build with the value set by PromptData; a prompt without
+// template actions is used as is.
+func (b *Builder) SystemPrompt(tmpl string) *Builder {
+	b.prompt = tmpl
+	return b
+}
+
+// PromptData sets the data the system prompt template is rendered with.
+func (b *Builder) PromptData(data any) *Builder {
+	b.data = data
+	return b
+}
+
+// Registry sets the registry the agent uses. Tools added with Tools are
+// registered into it at Build. By default a new registry is created.
+func (b *Builder) Registry(r *tool.Registry) *Builder {
+	b.registry = r
+	return b
+}
+
+// Tools adds tools to the agent, such as a set from tool.FileTools or
+// tool.WebTools.
+func (b *Builder) Tools(pairs ...tool.ToolPair) *Builder {
+	b.tools = append(b.tools, pairs...)
+	return b
+}
+
+// Skills adds skills to the agent's runs. See WithSkills.
+func (b *Builder) Skills(skills ...Skill) *Builder {
+	return b.Options(WithSkills(skills...))
+}
+
+// RequireApproval guards tool calls with an approver. If tools are given,
+// only those tools require approval; otherwise every call does.
+func (b *Builder) RequireApproval(fn ApproverFunc, tools ...string) *Builder {
+	b.opts = append(b.opts, WithApprover(fn))
+	if len(tools) > 0 {
+		b.opts = append(b.opts, WithApprovalRequired(tools...))
+	}
+	return b
+}
+
+// MaxSteps limits the agent's iterations per run.
+func (b *Builder) MaxSteps(n int) *Builder {
+	return b.Options(WithMaxSteps(n))
+}
+
+// Timeout limits the duration of each run.
+func (b *Builder) Timeout(d time.Duration) *Builder {
+	return b.Options(WithTimeout(d))
+}
+
+// HistoryLimit caps how many recent messages are sent with each step.
+// See WithHistoryLimit.
+func (b *Builder) HistoryLimit(n int) *Builder {
+	return b.Options(WithHistoryLimit(n))
+}
+
+// Memory sets the memory that compacts the history before each step.
+// See WithMemory.
+func (b *Builder) Memory(m memory.Memory) *Builder {
+	return b.Options(WithMemory(m))
+}
+
+// ContextManager keeps each step within the model's context window.
+// See WithContextManager.
+func (b *Builder) ContextManager(m *ai.ContextManager) *Builder {
+	return b.Options(WithContextManager(m))
+}
+
+// Budget stops each run once its cost reaches maxUSD.
+func (b *Builder) Budget(maxUSD float64) *Builder {
+	return b.Options(WithBudget(maxUSD))
+}
+
+// TokenBudget stops each run once its tokens reach maxTokens.
+func (b *Builder) TokenBudget(maxTokens int) *Builder {
+	return b.Options(WithTokenBudget(maxTokens))
+}
+
+// Options adds arbitrary agent options, for settings without a dedicated
+// builder method.
+func (b *Builder) Options(opts ...Option) *Builder {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+// Build renders the system prompt, registers the tools, and returns the
+// configured agent.
+func (b *Builder) Build() (*Agent, error) {
+	registry := b.registry
+	if registry == nil {
+		registry = tool.NewRegistry()
+	}
+	if err := tool.RegisterAll(registry, b.tools); err != nil {
+		return nil, err
+	}
+
+	defaults := append([]Option{}, b.opts...)
+	if b.prompt != "" {
+		prompt, err := renderPrompt(b.prompt, b.data)
+		if err != nil {
+			return nil, err
+		}
+		defaults = append(defaults, WithSystemPrompt(prompt))
+	}
+
+	a := New(b.client, registry)
+	a.defaults = defaults
+	return a, nil
+}
+
+// MustBuild is like Build but panics on error.
+func (b *Builder) MustBuild() *Agent {
+	a, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// renderPrompt executes tmpl with data. Missing keys are errors so typos in
+// a prompt template fail at Build rather than producing "<no value>".
+func renderPrompt(tmpl string, data any) (string, error) {
+	t, err := template.New("system").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("agent: parse system prompt: %w", err)
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("agent: render system prompt: %w", err)
+	}
+	return sb.String(), nil
+}
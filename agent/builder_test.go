@@ -0,0 +1,160 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/model"
+	"github.com/spetersoncode/gains/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingProvider records the messages and options of each chat call.
+type recordingProvider struct {
+	mockProvider
+	messages [][]ai.Message
+	options  []*ai.Options
+}
+
+func (r *recordingProvider) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	r.messages = append(r.messages, messages)
+	r.options = append(r.options, ai.ApplyOptions(opts...))
+	return r.mockProvider.Chat(ctx, messages, opts...)
+}
+
+func (r *recordingProvider) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+	r.messages = append(r.messages, messages)
+	r.options = append(r.options, ai.ApplyOptions(opts...))
+	return r.mockProvider.ChatStream(ctx, messages, opts...)
+}
+
+func TestBuilder_Build(t *testing.T) {
+	provider := &recordingProvider{mockProvider: mockProvider{
+		responses: []mockResponse{{content: "Done"}},
+	}}
+	pairs := []tool.ToolPair{{
+		Tool:    ai.Tool{Name: "lookup", Description: "Look something up"},
+		Handler: func(context.Context, ai.ToolCall) (string, error) { return "", nil },
+	}}
+
+	a, err := NewBuilder(provider).
+		Model(model.ClaudeSonnet45).
+		SystemPrompt("You help the {{.Team}} team.").
+		PromptData(map[string]string{"Team": "platform"}).
+		Tools(pairs...).
+		MaxSteps(3).
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lookup"}, a.registry.Names())
+
+	_, err = a.Run(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Hi"}})
+	require.NoError(t, err)
+
+	require.Len(t, provider.messages, 1)
+	sent := provider.messages[0]
+	require.Len(t, sent, 2)
+	assert.Equal(t, ai.RoleSystem, sent[0].Role)
+	assert.Equal(t, "You help the platform team.", sent[0].Content)
+	assert.Equal(t, model.ClaudeSonnet45, provider.options[0].Model)
+
+	opts := a.applyOptions(nil)
+	assert.Equal(t, 3, opts.MaxSteps)
+}
+
+func TestBuilder_RunOptionsOverrideDefaults(t *testing.T) {
+	a := NewBuilder(&mockProvider{}).MaxSteps(3).Budget(1).MustBuild()
+
+	opts := a.applyOptions([]Option{WithMaxSteps(7)})
+	assert.Equal(t, 7, opts.MaxSteps)
+	assert.Equal(t, 1.0, opts.Budget)
+}
+
+func TestAgent_Options(t *testing.T) {
+	a := NewBuilder(&mockProvider{}).MaxSteps(3).MustBuild()
+	assert.Equal(t, 3, a.Options().MaxSteps)
+	assert.True(t, a.Options().Streaming)
+
+	assert.Equal(t, 10, New(&mockProvider{}, tool.NewRegistry()).Options().MaxSteps)
+}
+
+func TestBuilder_PromptErrors(t *testing.T) {
+	_, err := NewBuilder(&mockProvider{}).SystemPrompt("{{.Missing").Build()
+	assert.ErrorContains(t, err, "parse system prompt")
+
+	_, err = NewBuilder(&mockProvider{}).
+		SystemPrompt("Hello {{.Name}}").
+		PromptData(map[string]string{}).
+		Build()
+	assert.ErrorContains(t, err, "render system prompt")
+}
+
+func TestBuilder_DuplicateTools(t *testing.T) {
+	pair := tool.ToolPair{
+		Tool:    ai.Tool{Name: "dup"},
+		Handler: func(context.Context, ai.ToolCall) (string, error) { return "", nil },
+	}
+	_, err := NewBuilder(&mockProvider{}).Tools(pair, pair).Build()
+
+	var dupErr *tool.ErrToolAlreadyRegistered
+	assert.ErrorAs(t, err, &dupErr)
+}
+
+func TestBuilder_RequireApproval(t *testing.T) {
+	approver := func(context.Context, ai.ToolCall) (bool, string) { return true, "" }
+	a := NewBuilder(&mockProvider{}).RequireApproval(approver, "write_file").MustBuild()
+
+	opts := a.applyOptions(nil)
+	assert.NotNil(t, opts.Approver)
+	assert.Equal(t, []string{"write_file"}, opts.ApprovalRequired)
+}
+
+func TestPresets(t *testing.T) {
+	root := t.TempDir()
+
+	t.Run("coding", func(t *testing.T) {
+		a := CodingAgent(&mockProvider{}, root).MustBuild()
+		assert.ElementsMatch(t,
+			[]string{"read_file", "write_file", "edit_file", "list_directory", "search_files"},
+			a.registry.Names())
+		opts := a.applyOptions(nil)
+		assert.Equal(t, 30, opts.MaxSteps)
+		assert.True(t, opts.ToolSummary)
+		assert.Contains(t, opts.SystemPrompt, root)
+	})
+
+	t.Run("research", func(t *testing.T) {
+		a := ResearchAgent(&mockProvider{}).MustBuild()
+		assert.Equal(t, []string{"http_request"}, a.registry.Names())
+		opts := a.applyOptions(nil)
+		assert.Equal(t, 15, opts.MaxSteps)
+		assert.Equal(t, 40, opts.HistoryLimit)
+	})
+
+	t.Run("support", func(t *testing.T) {
+		a := SupportAgent(&mockProvider{}, "Acme Cloud").MaxSteps(8).MustBuild()
+		assert.Zero(t, a.registry.Len())
+		opts := a.applyOptions(nil)
+		assert.Equal(t, 8, opts.MaxSteps)
+		assert.Contains(t, opts.SystemPrompt, "support agent for Acme Cloud")
+	})
+}
+
+func TestLimitHistory(t *testing.T) {
+	system := ai.Message{Role: ai.RoleSystem, Content: "sys"}
+	user := func(s string) ai.Message { return ai.Message{Role: ai.RoleUser, Content: s} }
+	call := ai.Message{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{ID: "1", Name: "t"}}}
+	result := ai.NewToolResultMessage(ai.ToolResult{ToolCallID: "1", Content: "ok"})
+	answer := ai.Message{Role: ai.RoleAssistant, Content: "answer"}
+
+	messages := []ai.Message{system, user("a"), user("b"), call, result, answer}
+
+	assert.Equal(t, messages, limitHistory(messages, 0))
+	assert.Equal(t, messages, limitHistory(messages, 10))
+
+	// The window widens to keep the tool result with its call.
+	assert.Equal(t, []ai.Message{system, call, result, answer}, limitHistory(messages, 2))
+	assert.Equal(t, []ai.Message{system, user("b"), call, result, answer}, limitHistory(messages, 4))
+}
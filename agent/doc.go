@@ -45,6 +45,13 @@
 //	    }
 //	}
 //
+// Run also records every tool call in Result.ToolInvocations, with its
+// result, approval, error, and handler duration, for audit logs:
+//
+//	for _, inv := range result.ToolInvocations {
+//	    log.Printf("%s approved=%t took %s", inv.Call.Name, inv.Approved, inv.Duration)
+//	}
+//
 // # Human-in-the-Loop Approval
 //
 // Use WithApprover to require approval before tool execution:
@@ -58,6 +65,112 @@
 //	    }),
 //	)
 //
+// An approver blocks the run until it decides. When the decision arrives in
+// a later HTTP request, use WithSuspendForApproval instead: the run ends
+// with TerminationSuspended and a resume token, which the frontend receives
+// in the tool approval activity and sends back with its decision:
+//
+//	opts := []agent.Option{agent.WithEventLog(adapter), agent.WithSuspendForApproval()}
+//	result, err := a.Run(ctx, messages, opts...)
+//	// ... in the request carrying the decision
+//	result, err = a.ResumeWithApproval(ctx, token, approved, reason, opts...)
+//
+// Each token carries a random nonce recorded in the run's event log, so it
+// cannot be made up from the run ID. A token decides its call once: when
+// the same approval is submitted twice, the second ResumeWithApproval fails
+// with ErrInvalidResumeToken instead of running the tool again. Every
+// resume holds a lock on the run until the continued run ends, so a
+// recovery worker's Resume and a ResumeWithApproval never continue the same
+// run twice. Servers sharing one event log need an adapter that implements
+// store.Locker, such as the store/redis adapter, for this to hold across
+// processes. The run record keeps WithSuspendForApproval, and a resume
+// without it or WithApprover fails with ErrApprovalModeRequired.
+//
+// # Builder and Presets
+//
+// Use NewBuilder to configure an agent fluently. Its settings become the
+// agent's defaults, which options passed to Run override:
+//
+//	a, err := agent.NewBuilder(client).
+//	    Model(model.ClaudeSonnet45).
+//	    SystemPrompt("You are a release assistant for {{.Project}}.").
+//	    PromptData(map[string]string{"Project": "gains"}).
+//	    Tools(tool.WebTools()...).
+//	    Budget(0.25).
+//	    Build()
+//
+// CodingAgent, ResearchAgent, and SupportAgent return builders with
+// reviewed defaults for common roles; adjust them before Build:
+//
+//	a, err := agent.CodingAgent(client, "./repo").
+//	    RequireApproval(confirm, "write_file", "edit_file").
+//	    Build()
+//
+// # Skills
+//
+// A Skill bundles tools, a system prompt section, and the model capabilities
+// they need, so a capability like web research can be packaged once and
+// added to any agent:
+//
+//	research := agent.Skill{
+//	    Name:     "web-research",
+//	    Prompt:   "Cite the URL of every source you rely on.",
+//	    Tools:    tool.WebTools(),
+//	    Requires: []gains.Capability{gains.CapabilityTools},
+//	}
+//
+//	result, err := a.Run(ctx, messages, agent.WithSkills(research, editing))
+//
+// Tools named like one the agent already has are skipped, a skill whose
+// name repeats an earlier one is applied once, and prompts are added in
+// order after WithSystemPrompt's. Builder.Skills adds skills to every run.
+//
+// # Sessions
+//
+// Use RunSession to keep a multi-turn conversation in a session.Session,
+// which persists after each turn and can be resumed after a restart:
+//
+//	sess, err := sessions.Resume(ctx, id)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	sess.Append(ctx, gains.Message{Role: gains.RoleUser, Content: input})
+//	result, err := a.RunSession(ctx, sess, agent.WithMaxSteps(5))
+//
+// Hold the session's lock from session.Manager.TryLock around the run when
+// several requests may continue the same conversation at once.
+//
+// RegenerateLastSession replaces the last answer, with its tool calls, by
+// running the turn again, optionally with another model or temperature.
+// RegenerateLast and RegenerateLastStream do the same for a message slice:
+//
+//	result, err := a.RegenerateLastSession(ctx, sess, agent.WithTemperature(1.0))
+//
+// # Teams
+//
+// A Team lets a supervisor model route tasks to named worker agents through
+// a handoff tool. Workers share the team's conversation, can be given their
+// own budgets, and their events reach the team's stream tagged with their
+// name in Event.Agent:
+//
+//	team := agent.NewTeam(client).
+//	    AddWorker("research", "Finds and summarizes sources", researcher,
+//	        agent.WithWorkerTokenBudget(50_000)).
+//	    AddWorker("writer", "Drafts the final answer", writer)
+//	result, err := team.Run(ctx, messages)
+//
+// # Durable Runs
+//
+// WithEventLog records every event of a run in a store adapter, keyed by
+// the run ID from WithRunID or a generated one reported in Result.RunID.
+// If the run is interrupted, for example by cancelling it while a tool call
+// waits for approval, Resume rebuilds the conversation from the log and
+// continues it, asking the approver again for calls that were not decided:
+//
+//	result, err := a.Run(ctx, msgs, agent.WithEventLog(adapter), agent.WithRunID(id))
+//	// ... hours later, once the approval arrives
+//	result, err = a.Resume(ctx, id, agent.WithEventLog(adapter), agent.WithApprover(approve))
+//
 // # Configuration Options
 //
 // The agent supports various configuration options:
@@ -66,10 +179,27 @@
 //   - WithTimeout(d): Set overall execution timeout
 //   - WithHandlerTimeout(d): Set per-handler timeout (default: 30s)
 //   - WithParallelToolCalls(bool): Enable/disable parallel tool execution (default: true)
+//   - WithMaxParallelToolCalls(n): Limit concurrently running tool calls (default: unlimited)
+//   - WithRunConcurrency(n): Limit total parallelism across the run and its sub-agents (default: unlimited)
+//   - WithStreaming(bool): Use ChatStream or blocking Chat for each step (default: true)
 //   - WithApprover(fn): Enable human-in-the-loop approval
 //   - WithApprovalRequired(tools...): Require approval only for specific tools
 //   - WithStopPredicate(fn): Custom termination condition
 //   - WithChatOptions(opts...): Pass options to underlying ChatProvider
+//   - WithStepOptions(fn): Override chat options per step (e.g., model or temperature)
+//   - WithToolSummary(): Add a summary of available tools to the system prompt
+//   - WithBudget(maxUSD): Stop once cumulative cost reaches a limit
+//   - WithTokenBudget(n): Stop once cumulative tokens reach a limit
+//   - WithSystemPrompt(prompt): Add a system prompt to every step
+//   - WithSkills(skills...): Add bundles of tools and prompt sections
+//   - WithHistoryLimit(n): Send only the most recent n messages per step
+//   - WithMemory(m): Compact the history before each step, e.g. by summarizing it
+//   - WithContextManager(m): Trim each step to fit the model's context window
+//   - WithEventLog(adapter): Record events so the run can be resumed
+//   - WithRunID(id): Set the run ID used by the event log
+//   - WithSuspendForApproval(): Suspend the run until ResumeWithApproval
+//   - WithDryRun(): Emit the planned prompts and tools without calling the model
+//   - WithLogger(logger): Log steps, tool calls, retries, and token usage with slog
 //
 // # Termination Conditions
 //
@@ -79,7 +209,14 @@
 //   - MaxSteps is reached (TerminationMaxSteps)
 //   - Timeout is exceeded (TerminationTimeout)
 //   - Context is cancelled (TerminationCancelled)
+//   - The StopFunc of gains.WithStop is called (TerminationStopped)
 //   - StopPredicate returns true (TerminationCustom)
 //   - All tool calls are rejected (TerminationRejected)
+//   - A tool call awaits a suspended approval (TerminationSuspended)
+//   - The cost or token budget is reached (TerminationBudgetExceeded)
 //   - An error occurs (TerminationError)
+//
+// A panic in a callback such as an approver or stop predicate ends the run
+// with a *gains.PanicError carrying the stack trace. A panicking tool
+// handler does not end the run; its call gets an error result instead.
 package agent
@@ -2,6 +2,9 @@ package agent
 
 import (
 	"errors"
+	"fmt"
+
+	ai "github.com/spetersoncode/gains"
 )
 
 // Sentinel errors for agent termination conditions.
@@ -11,4 +14,48 @@ var (
 
 	// ErrAgentTimeout indicates the overall timeout was exceeded.
 	ErrAgentTimeout = errors.New("agent: timeout exceeded")
+
+	// ErrNoEventLog indicates Resume was called without WithEventLog.
+	ErrNoEventLog = errors.New("agent: no event log configured")
+
+	// ErrRunNotFound indicates the event log has no run with the given ID.
+	ErrRunNotFound = errors.New("agent: run not found")
+
+	// ErrRunFinished indicates a resumed run already ended and has nothing
+	// left to continue.
+	ErrRunFinished = errors.New("agent: run already finished")
+
+	// ErrInvalidResumeToken indicates a resume token is malformed or does
+	// not match a tool call awaiting approval.
+	ErrInvalidResumeToken = errors.New("agent: invalid resume token")
+
+	// ErrRunInProgress indicates a run cannot be resumed because another
+	// Resume or ResumeWithApproval is continuing it.
+	ErrRunInProgress = errors.New("agent: run in progress")
+
+	// ErrApprovalModeRequired indicates a run recorded with
+	// WithSuspendForApproval was resumed with neither it nor WithApprover,
+	// which would run its gated tools unapproved.
+	ErrApprovalModeRequired = errors.New("agent: run suspends for approval; resume it with WithSuspendForApproval or WithApprover")
+
+	// ErrUnpricedModel indicates WithBudget was set but the model a chat
+	// call would use has no pricing, so its cost could not be counted.
+	ErrUnpricedModel = errors.New("agent: budget set for a model without pricing")
+
+	// ErrNoTurnToRegenerate indicates a conversation has no assistant turn
+	// after its last user message to regenerate.
+	ErrNoTurnToRegenerate = errors.New("agent: no assistant turn to regenerate")
 )
+
+// ErrSkillRequirement is returned when a run uses a skill that needs a
+// capability its model does not support.
+type ErrSkillRequirement struct {
+	Skill      string
+	Capability ai.Capability
+	Model      ai.Model
+}
+
+// Error returns a message naming the skill, capability, and model.
+func (e *ErrSkillRequirement) Error() string {
+	return fmt.Sprintf("agent: skill %q requires %s, which model %s does not support", e.Skill, e.Capability, e.Model)
+}
@@ -1,6 +1,8 @@
 package agent
 
 import (
+	"time"
+
 	ai "github.com/spetersoncode/gains"
 	"github.com/spetersoncode/gains/event"
 	"github.com/spetersoncode/gains/internal/store"
@@ -40,9 +42,21 @@ const (
 	// TerminationCancelled indicates context cancellation.
 	TerminationCancelled TerminationReason = "cancelled"
 
+	// TerminationBudgetExceeded indicates the cost or token budget was reached.
+	TerminationBudgetExceeded TerminationReason = "budget_exceeded"
+
 	// TerminationClientToolCall indicates the model called a client-side tool.
 	// The frontend should execute the tool and resume with the result.
 	TerminationClientToolCall TerminationReason = "client_tool_call"
+
+	// TerminationSuspended indicates the run is waiting for a tool approval.
+	// Continue it with Agent.ResumeWithApproval and Result.ResumeToken.
+	TerminationSuspended TerminationReason = "suspended"
+
+	// TerminationStopped indicates the run was stopped with the StopFunc of
+	// gains.WithStop. A response stopped mid-stream is kept as the final
+	// response with its partial content.
+	TerminationStopped TerminationReason = "stopped"
 )
 
 // Result represents the final outcome of an agent execution.
@@ -50,6 +64,13 @@ type Result struct {
 	// Response is the final response from the model.
 	Response *ai.Response
 
+	// RunID identifies the run in the event log when WithEventLog is used.
+	RunID string
+
+	// ResumeToken identifies the tool call awaiting approval when
+	// Termination is TerminationSuspended.
+	ResumeToken string
+
 	// history contains the complete conversation history (private).
 	history *store.MessageStore
 
@@ -68,6 +89,43 @@ type Result struct {
 	// PendingClientToolCalls contains tool calls awaiting client execution.
 	// These are set when Termination is TerminationClientToolCall.
 	PendingClientToolCalls []ai.ToolCall
+
+	// ToolInvocations records each tool call of the run, in call order.
+	ToolInvocations []ToolInvocation
+}
+
+// ToolInvocation is the transcript of one tool call of a run, for audit
+// logging and analytics.
+type ToolInvocation struct {
+	// Step is the step whose response made the call.
+	Step int `json:"step"`
+
+	// Call is the tool call requested by the model.
+	Call ai.ToolCall `json:"call"`
+
+	// Result is the result returned to the model, including the error
+	// result of a rejected or failed call. It is nil for client tool calls
+	// and calls still awaiting approval when the run ended.
+	Result *ai.ToolResult `json:"result,omitempty"`
+
+	// Approved reports whether the call was allowed to run, by an approver
+	// or because it needed no approval.
+	Approved bool `json:"approved"`
+
+	// RejectionReason is the approver's reason for rejecting the call.
+	RejectionReason string `json:"rejectionReason,omitempty"`
+
+	// Client reports whether the call was left to the client to execute.
+	Client bool `json:"client,omitempty"`
+
+	// Error is the content of the result when the handler failed.
+	Error string `json:"error,omitempty"`
+
+	// StartedAt is when the handler started. Zero if it did not run.
+	StartedAt time.Time `json:"startedAt,omitzero"`
+
+	// Duration is how long the handler ran. Zero if it did not run.
+	Duration time.Duration `json:"duration,omitempty"`
 }
 
 // Messages returns the conversation history as a slice.
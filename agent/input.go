@@ -6,7 +6,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/google/uuid"
+	ai "github.com/spetersoncode/gains"
 )
 
 // InputType specifies the kind of user input expected.
@@ -137,9 +137,17 @@ func (b *UserInputBroker) HasPending() bool {
 // Request sends a user input request and waits for a response.
 // This is the low-level method; prefer the typed methods like RequestConfirm.
 func (b *UserInputBroker) Request(ctx context.Context, req UserInputRequest) (*UserInputResponse, error) {
+	return b.RequestWith(ctx, req, nil)
+}
+
+// RequestWith is Request with a callback called, after the broker's
+// WithOnInputSubmit callback, once the request is registered, so a
+// response to it cannot arrive too early. Use it to publish the request,
+// such as with an ActivityUserInput event.
+func (b *UserInputBroker) RequestWith(ctx context.Context, req UserInputRequest, onSubmit func(req UserInputRequest)) (*UserInputResponse, error) {
 	// Generate ID if not set
 	if req.ID == "" {
-		req.ID = uuid.New().String()
+		req.ID = ai.NewID()
 	}
 
 	// Create response channel
@@ -158,10 +166,13 @@ func (b *UserInputBroker) Request(ctx context.Context, req UserInputRequest) (*U
 		b.mu.Unlock()
 	}()
 
-	// Call the onSubmit callback if set
+	// Call the onSubmit callbacks if set
 	if b.onSubmit != nil {
 		b.onSubmit(req)
 	}
+	if onSubmit != nil {
+		onSubmit(req)
+	}
 
 	// Wait for response with timeout
 	timeoutCtx, cancel := context.WithTimeout(ctx, b.timeout)
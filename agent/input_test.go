@@ -211,3 +211,23 @@ func TestUserInputBroker_OnInputSubmit(t *testing.T) {
 		t.Errorf("expected type InputTypeConfirm, got %q", submitted.Type)
 	}
 }
+
+func TestUserInputBroker_RequestWith(t *testing.T) {
+	broker := NewUserInputBrokerWith(WithInputTimeout(100 * time.Millisecond))
+
+	// Responding from the callback succeeds, as the request is registered
+	response, err := broker.RequestWith(context.Background(), UserInputRequest{
+		Type:    InputTypeText,
+		Message: "Name?",
+	}, func(req UserInputRequest) {
+		if err := broker.Respond(UserInputResponse{RequestID: req.ID, Value: "Ada"}); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.Value != "Ada" {
+		t.Errorf("expected 'Ada', got %q", response.Value)
+	}
+}
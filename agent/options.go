@@ -2,9 +2,13 @@ package agent
 
 import (
 	"context"
+	"log/slog"
 	"time"
 
 	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/internal/store"
+	"github.com/spetersoncode/gains/memory"
 )
 
 // ApproverFunc is called when a tool call requires approval.
@@ -17,6 +21,12 @@ type ApproverFunc func(ctx context.Context, call ai.ToolCall) (approved bool, re
 // Return true to stop the agent.
 type StopFunc func(step int, response *ai.Response) bool
 
+// StepOptionsFunc returns additional chat options for a single step.
+// It receives the current step number and the conversation history the
+// step will be sent. The returned options are applied after ChatOptions,
+// so they override any agent-wide settings for that step only.
+type StepOptionsFunc func(step int, history []ai.Message) []ai.Option
+
 // Options contains configuration for agent execution.
 type Options struct {
 	// MaxSteps limits the number of agent iterations.
@@ -35,6 +45,20 @@ type Options struct {
 	// Default is true.
 	ParallelToolCalls bool
 
+	// MaxParallelToolCalls limits how many tool calls run at once when
+	// ParallelToolCalls is enabled. A value of 0 means no limit.
+	MaxParallelToolCalls int
+
+	// RunConcurrency limits how many tool calls, including those of nested
+	// sub-agents and workflows, run at once across the whole run. A value
+	// of 0 means no limit.
+	RunConcurrency int
+
+	// Streaming controls whether each step uses ChatStream or Chat.
+	// When false, the full response is emitted as a single message delta.
+	// Default is true.
+	Streaming bool
+
 	// Approver enables human-in-the-loop approval for tool calls.
 	// If nil, all tool calls are automatically approved.
 	Approver ApproverFunc
@@ -44,12 +68,78 @@ type Options struct {
 	// If non-empty, only the listed tools require approval.
 	ApprovalRequired []string
 
+	// SuspendForApproval ends the run with TerminationSuspended when a tool
+	// call needs approval, instead of calling Approver. Requires EventLog.
+	SuspendForApproval bool
+
 	// StopPredicate is a custom termination condition.
 	// Called after each step; return true to stop the agent.
 	StopPredicate StopFunc
 
 	// ChatOptions are passed through to the underlying ChatProvider.
 	ChatOptions []ai.Option
+
+	// StepOptions computes per-step chat option overrides.
+	// If nil, every step uses ChatOptions unchanged.
+	StepOptions StepOptionsFunc
+
+	// ToolSummary adds the registry's SystemPrompt summary to the system
+	// message sent with each step. Default is false.
+	ToolSummary bool
+
+	// SystemPrompt is added to the system message sent with each step.
+	SystemPrompt string
+
+	// Skills add tools and system prompt sections to the run.
+	Skills []Skill
+
+	// ClientTools are offered to the model for this run only, executed by
+	// the client rather than the agent.
+	ClientTools []ai.Tool
+
+	// HistoryLimit caps the non-system messages sent with each step to the
+	// most recent N. A value of 0 sends the full history.
+	HistoryLimit int
+
+	// Memory compacts the history sent with each step, for example by
+	// summarizing older messages. If nil, the history is sent as is.
+	Memory memory.Memory
+
+	// ContextManager trims the messages of each step to fit the model's
+	// context window. If nil, steps are sent regardless of their size.
+	ContextManager *ai.ContextManager
+
+	// Budget stops the run once the cumulative cost in USD reaches this value.
+	// Cost is only known for models that expose pricing. A value of 0 means no limit.
+	Budget float64
+
+	// TokenBudget stops the run once cumulative input and output tokens reach
+	// this value. A value of 0 means no limit.
+	TokenBudget int
+
+	// EventLog persists the run's events so it can be continued with Resume.
+	// If nil, runs are not recorded.
+	EventLog store.Adapter
+
+	// RunID identifies the run in the EventLog. If empty, an ID is generated.
+	RunID string
+
+	// DryRun plans each step without calling the model or executing tools.
+	// Default is false.
+	DryRun bool
+
+	// Logger receives a structured log record for each run, step, tool
+	// call, and retry event. If nil, nothing is logged.
+	Logger *slog.Logger
+
+	// LogLevels sets the level of each kind of record sent to Logger.
+	// Default is event.DefaultLogLevels.
+	LogLevels event.LogLevels
+
+	// EventBuffer is the capacity of the run's event channel. Events are
+	// dropped when a slow consumer lets it fill; see event.Stats. Default
+	// is event.DefaultBufferSize.
+	EventBuffer int
 }
 
 // Option is a functional option for configuring agent execution.
@@ -86,6 +176,36 @@ func WithParallelToolCalls(enabled bool) Option {
 	}
 }
 
+// WithMaxParallelToolCalls limits how many tool calls from a single step run
+// at once; the rest wait for a free slot. A value of 0 means no limit.
+func WithMaxParallelToolCalls(n int) Option {
+	return func(o *Options) {
+		o.MaxParallelToolCalls = n
+	}
+}
+
+// WithRunConcurrency limits the total parallelism of a run to n: its own
+// tool calls, the tool calls of sub-agents it invokes, and the parallel
+// branches of workflows its tools run all share one budget, so a single
+// request cannot fan out into unbounded concurrent provider calls. Work
+// that finds the budget spent runs sequentially rather than waiting. A
+// sub-agent's own limit is ignored in favor of its parent's. A value of 0
+// means no limit. See ai.WithConcurrencyLimit.
+func WithRunConcurrency(n int) Option {
+	return func(o *Options) {
+		o.RunConcurrency = n
+	}
+}
+
+// WithStreaming enables or disables streaming chat calls for each step.
+// Disable streaming for providers with unreliable streamed tool calls or
+// for batch runs where per-token deltas are unnecessary. Default is true.
+func WithStreaming(enabled bool) Option {
+	return func(o *Options) {
+		o.Streaming = enabled
+	}
+}
+
 // WithApprover sets the human-in-the-loop approval function.
 // The function is called before each tool execution and must return
 // an approval decision.
@@ -104,6 +224,19 @@ func WithApprovalRequired(tools ...string) Option {
 	}
 }
 
+// WithSuspendForApproval makes tool calls that need approval suspend the
+// run instead of blocking in an Approver, for frontends that collect the
+// decision in a later request. The run ends with TerminationSuspended after
+// emitting an ActivitySnapshot whose ToolApprovalActivity carries a resume
+// token; pass the token and the decision to Agent.ResumeWithApproval.
+// WithApprovalRequired limits which tools need approval, as with
+// WithApprover. The run must be recorded with WithEventLog.
+func WithSuspendForApproval() Option {
+	return func(o *Options) {
+		o.SuspendForApproval = true
+	}
+}
+
 // WithStopPredicate sets a custom termination condition.
 // The predicate is called after each step with the step number and response.
 // Return true to stop the agent.
@@ -121,6 +254,156 @@ func WithChatOptions(opts ...ai.Option) Option {
 	}
 }
 
+// WithStepOptions sets a function that supplies chat options for each step.
+// Use it to adjust settings as the run progresses, such as lowering the
+// temperature or switching to a cheaper model once tools have gathered data:
+//
+//	agent.WithStepOptions(func(step int, history []ai.Message) []ai.Option {
+//	    if step > 1 {
+//	        return []ai.Option{ai.WithModel(model.ClaudeHaiku45)}
+//	    }
+//	    return nil
+//	})
+func WithStepOptions(fn StepOptionsFunc) Option {
+	return func(o *Options) {
+		o.StepOptions = fn
+	}
+}
+
+// WithToolSummary adds a summary of the registry's tools to the system prompt
+// of every step. The summary is rendered fresh each step with
+// tool.Registry.SystemPrompt, so it tracks tools added or removed mid-run.
+// It is appended to the leading system message, or sent as a new system
+// message if the conversation has none; the stored history is not modified.
+func WithToolSummary() Option {
+	return func(o *Options) {
+		o.ToolSummary = true
+	}
+}
+
+// WithSystemPrompt adds prompt to the system message of every step. It is
+// appended to the leading system message, or sent as a new system message
+// if the conversation has none; the stored history is not modified.
+func WithSystemPrompt(prompt string) Option {
+	return func(o *Options) {
+		o.SystemPrompt = prompt
+	}
+}
+
+// WithClientTools offers tools executed by the client, such as an AG-UI
+// frontend, for this run only. They are added to a copy of the agent's
+// registry, which is not modified, so concurrent runs never see each
+// other's client tools. A call to one ends the run with
+// TerminationClientToolCall. Tools named like a tool the agent already has
+// are ignored.
+func WithClientTools(tools ...ai.Tool) Option {
+	return func(o *Options) {
+		o.ClientTools = append(o.ClientTools, tools...)
+	}
+}
+
+// WithHistoryLimit sends only the most recent n non-system messages with
+// each step, bounding context size in long conversations. System messages
+// are always sent. The window is widened as needed so tool results are
+// never sent without the assistant message that requested them. The full
+// history is still kept in the Result.
+func WithHistoryLimit(n int) Option {
+	return func(o *Options) {
+		o.HistoryLimit = n
+	}
+}
+
+// WithMemory compacts the conversation history with m before each step.
+// The run's own history, reported in Result, stays complete; only the
+// messages sent to the model change. WithHistoryLimit applies afterwards.
+func WithMemory(m memory.Memory) Option {
+	return func(o *Options) {
+		o.Memory = m
+	}
+}
+
+// WithDryRun walks the run without calling the model or executing tools.
+// Each step emits a ChatPlanned event carrying the messages and tool
+// definitions it would send, then the run completes.
+func WithDryRun() Option {
+	return func(o *Options) {
+		o.DryRun = true
+	}
+}
+
+// WithContextManager keeps each step within the context window of its
+// model by trimming or summarizing the oldest messages with m. It applies
+// last, after WithMemory, WithHistoryLimit, and the system prompt, so it
+// measures exactly what is sent. The run's own history stays complete.
+func WithContextManager(m *ai.ContextManager) Option {
+	return func(o *Options) {
+		o.ContextManager = m
+	}
+}
+
+// WithBudget stops the run with *ai.ErrBudgetExceeded once the cumulative
+// cost of its chat calls reaches maxUSD. The budget is checked before each
+// step, so the step that crosses it completes. Cost is computed from the
+// pricing of the model each call uses: the model set via WithModel or
+// WithStepOptions, or the chat client's default, with aliases resolved when
+// the client is an ai.ModelResolver such as client.Client. A run whose
+// model has no pricing fails at its start, or at the step that selects it,
+// with an error matching ErrUnpricedModel.
+func WithBudget(maxUSD float64) Option {
+	return func(o *Options) {
+		o.Budget = maxUSD
+	}
+}
+
+// WithTokenBudget stops the run with *ai.ErrBudgetExceeded once the
+// cumulative input and output tokens of its chat calls reach maxTokens.
+// The budget is checked before each step.
+func WithTokenBudget(maxTokens int) Option {
+	return func(o *Options) {
+		o.TokenBudget = maxTokens
+	}
+}
+
+// WithEventLog records every event of the run in adapter, keyed by run ID,
+// so a run interrupted by a crash, cancellation, or a pending approval can
+// be continued later with Agent.Resume.
+func WithEventLog(adapter store.Adapter) Option {
+	return func(o *Options) {
+		o.EventLog = adapter
+	}
+}
+
+// WithRunID sets the ID under which WithEventLog records the run. Without
+// it, a random ID is generated and reported in Result.RunID.
+func WithRunID(id string) Option {
+	return func(o *Options) {
+		o.RunID = id
+	}
+}
+
+// WithEventBuffer sets the capacity of the run's event channel. Raise it
+// when consumers fall behind bursts of deltas, which are otherwise dropped.
+func WithEventBuffer(n int) Option {
+	return func(o *Options) {
+		o.EventBuffer = n
+	}
+}
+
+// WithLogger logs the run's step starts and ends, tool calls, retries,
+// errors, and token usage to logger, at the levels set by WithLogLevels.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *Options) {
+		o.Logger = logger
+	}
+}
+
+// WithLogLevels sets the levels used by WithLogger.
+func WithLogLevels(levels event.LogLevels) Option {
+	return func(o *Options) {
+		o.LogLevels = levels
+	}
+}
+
 // WithModel is a convenience option to set the model for chat calls.
 func WithModel(model ai.Model) Option {
 	return func(o *Options) {
@@ -148,6 +431,8 @@ func ApplyOptions(opts ...Option) *Options {
 		MaxSteps:          10,
 		HandlerTimeout:    30 * time.Second,
 		ParallelToolCalls: true,
+		Streaming:         true,
+		LogLevels:         event.DefaultLogLevels(),
 	}
 	for _, opt := range opts {
 		opt(o)
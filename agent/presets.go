@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"github.com/spetersoncode/gains/chat"
+	"github.com/spetersoncode/gains/tool"
+)
+
+// CodingAgentPrompt is the system prompt template used by CodingAgent.
+// It is rendered with a struct whose Root field is the workspace path.
+const CodingAgentPrompt = `You are a careful software engineer working in the repository at {{.Root}}.
+Read the relevant code before changing it, follow the conventions already in use, and keep changes minimal and focused.
+Prefer editing existing files over creating new ones. Explain what you changed and why when you finish.`
+
+// ResearchAgentPrompt is the system prompt used by ResearchAgent.
+const ResearchAgentPrompt = `You are a thorough research assistant.
+Gather information from primary sources, cross-check important claims, and say when sources disagree or evidence is thin.
+Cite the URL of every source you rely on. Do not present guesses as facts.`
+
+// SupportAgentPrompt is the system prompt template used by SupportAgent.
+// It is rendered with a struct whose Product field is the product name.
+const SupportAgentPrompt = `You are a friendly, professional support agent for {{.Product}}.
+Answer clearly and concisely, ask a clarifying question when a request is ambiguous, and never invent product features, prices, or policies.
+If you cannot resolve an issue, say so and suggest contacting a human support representative.`
+
+// CodingAgent returns a Builder for an agent that reads, searches, and edits
+// files under root. It has file and search tools confined to root, a tool
+// summary in its prompt, low temperature, and up to 30 steps. Consider
+// adding RequireApproval for "write_file" and "edit_file".
+func CodingAgent(c chat.Client, root string) *Builder {
+	return NewBuilder(c).
+		SystemPrompt(CodingAgentPrompt).
+		PromptData(struct{ Root string }{root}).
+		Tools(tool.FileTools(tool.WithBasePath(root))...).
+		Tools(tool.SearchTools(tool.WithSearchPath(root))...).
+		MaxSteps(30).
+		Options(WithToolSummary(), WithTemperature(0.2))
+}
+
+// ResearchAgent returns a Builder for an agent that researches questions on
+// the web. It has the HTTP tool, up to 15 steps, and sends at most the 40
+// most recent messages per step to bound context growth from fetched pages.
+func ResearchAgent(c chat.Client) *Builder {
+	return NewBuilder(c).
+		SystemPrompt(ResearchAgentPrompt).
+		Tools(tool.WebTools()...).
+		MaxSteps(15).
+		HistoryLimit(40)
+}
+
+// SupportAgent returns a Builder for a customer support agent for product.
+// It has no tools; add your own, such as order lookup, with Tools. Runs are
+// limited to 5 steps and the 20 most recent messages.
+func SupportAgent(c chat.Client, product string) *Builder {
+	return NewBuilder(c).
+		SystemPrompt(SupportAgentPrompt).
+		PromptData(struct{ Product string }{product}).
+		MaxSteps(5).
+		HistoryLimit(20).
+		Options(WithTemperature(0.3))
+}
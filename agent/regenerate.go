@@ -0,0 +1,56 @@
+package agent
+
+import (
+	"context"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/session"
+)
+
+// RegenerateLast discards the last assistant turn of messages, every
+// message after the last user message including tool calls and their
+// results, and runs the agent again from the user message. Pass options
+// such as WithModel or WithTemperature to regenerate it differently.
+//
+// The result history starts from the trimmed conversation. RegenerateLast
+// returns ErrNoTurnToRegenerate if no assistant turn follows a user message.
+func (a *Agent) RegenerateLast(ctx context.Context, messages []ai.Message, opts ...Option) (*Result, error) {
+	start := ai.LastTurnStart(messages)
+	if start < 0 {
+		return &Result{Termination: TerminationError, Error: ErrNoTurnToRegenerate}, ErrNoTurnToRegenerate
+	}
+	return a.Run(ctx, messages[:start], opts...)
+}
+
+// RegenerateLastStream is the streaming form of RegenerateLast. The run
+// emits the same events as RunStream; a conversation without a turn to
+// regenerate is reported as a RunError event.
+func (a *Agent) RegenerateLastStream(ctx context.Context, messages []ai.Message, opts ...Option) <-chan Event {
+	start := ai.LastTurnStart(messages)
+	if start < 0 {
+		return errorStream(ErrNoTurnToRegenerate)
+	}
+	return a.RunStream(ctx, messages[:start], opts...)
+}
+
+// RegenerateLastSession regenerates the last assistant turn of a session.
+// The session keeps its previous turn until the new run succeeds, then the
+// old turn is replaced with the messages the run produced, as with
+// RunSession. If the run fails, the session is unchanged.
+func (a *Agent) RegenerateLastSession(ctx context.Context, sess *session.Session, opts ...Option) (*Result, error) {
+	messages := sess.Messages()
+	start := ai.LastTurnStart(messages)
+	if start < 0 {
+		return &Result{Termination: TerminationError, Error: ErrNoTurnToRegenerate}, ErrNoTurnToRegenerate
+	}
+
+	history := messages[:start]
+	result, err := a.Run(ctx, history, opts...)
+	if err != nil {
+		return result, err
+	}
+	if _, err := sess.DropLastTurn(ctx); err != nil {
+		return result, err
+	}
+	return result, a.saveRun(ctx, sess, history, result, opts)
+}
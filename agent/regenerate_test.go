@@ -0,0 +1,105 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/model"
+	"github.com/spetersoncode/gains/session"
+	"github.com/spetersoncode/gains/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// answeredConversation returns a conversation whose last turn used a tool.
+func answeredConversation() []ai.Message {
+	return []ai.Message{
+		{Role: ai.RoleUser, Content: "Hi"},
+		{Role: ai.RoleAssistant, Content: "Hello!"},
+		{Role: ai.RoleUser, Content: "Weather in Tokyo?"},
+		{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{ID: "c1", Name: "get_weather", Arguments: `{}`}}},
+		{Role: ai.RoleTool, ToolResults: []ai.ToolResult{{ToolCallID: "c1", Content: "sunny"}}},
+		{Role: ai.RoleAssistant, Content: "Sunny."},
+	}
+}
+
+func TestAgent_RegenerateLast(t *testing.T) {
+	ctx := context.Background()
+	messages := answeredConversation()
+	provider := &recordingProvider{mockProvider: mockProvider{responses: []mockResponse{{content: "Clear skies."}}}}
+
+	result, err := New(provider, tool.NewRegistry()).RegenerateLast(ctx, messages,
+		WithModel(model.ClaudeSonnet45), WithTemperature(0.9))
+	require.NoError(t, err)
+	assert.Equal(t, "Clear skies.", result.Response.Content)
+
+	require.Len(t, provider.messages, 1)
+	assert.Equal(t, messages[:3], provider.messages[0])
+	assert.Equal(t, model.ClaudeSonnet45, provider.options[0].Model)
+	require.NotNil(t, provider.options[0].Temperature)
+	assert.Equal(t, 0.9, *provider.options[0].Temperature)
+	assert.Equal(t, answeredConversation(), messages)
+
+	t.Run("nothing to regenerate", func(t *testing.T) {
+		_, err := New(provider, tool.NewRegistry()).RegenerateLast(ctx, messages[:3])
+		assert.ErrorIs(t, err, ErrNoTurnToRegenerate)
+	})
+}
+
+func TestAgent_RegenerateLastStream(t *testing.T) {
+	ctx := context.Background()
+	provider := &recordingProvider{mockProvider: mockProvider{responses: []mockResponse{{content: "Clear skies."}}}}
+	a := New(provider, tool.NewRegistry())
+
+	var types []event.Type
+	for ev := range a.RegenerateLastStream(ctx, answeredConversation()) {
+		types = append(types, ev.Type)
+	}
+	assert.Contains(t, types, event.RunStart)
+	assert.Contains(t, types, event.RunEnd)
+
+	var errs []error
+	for ev := range a.RegenerateLastStream(ctx, []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}) {
+		if ev.Type == event.RunError {
+			errs = append(errs, ev.Error)
+		}
+	}
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], ErrNoTurnToRegenerate)
+}
+
+func TestAgent_RegenerateLastSession(t *testing.T) {
+	ctx := context.Background()
+	sess, err := session.NewManager(nil).Create(ctx)
+	require.NoError(t, err)
+	require.NoError(t, sess.Append(ctx, answeredConversation()...))
+
+	t.Run("failed run keeps the previous turn", func(t *testing.T) {
+		provider := &mockProvider{responses: []mockResponse{{err: errors.New("overloaded")}}}
+		_, err := New(provider, tool.NewRegistry()).RegenerateLastSession(ctx, sess, WithStreaming(false))
+		require.Error(t, err)
+		assert.Equal(t, answeredConversation(), sess.Messages())
+	})
+
+	t.Run("successful run replaces the turn", func(t *testing.T) {
+		provider := &mockProvider{responses: []mockResponse{{content: "Clear skies."}}}
+		_, err := New(provider, tool.NewRegistry()).RegenerateLastSession(ctx, sess, WithModel(model.ClaudeSonnet45))
+		require.NoError(t, err)
+
+		msgs := sess.Messages()
+		require.Len(t, msgs, 4)
+		assert.Equal(t, answeredConversation()[:3], msgs[:3])
+		assert.Equal(t, ai.Message{Role: ai.RoleAssistant, Content: "Clear skies."}, msgs[3])
+		assert.Equal(t, model.ClaudeSonnet45.String(), sess.Metadata().Model)
+	})
+
+	t.Run("nothing to regenerate", func(t *testing.T) {
+		require.NoError(t, sess.Append(ctx, ai.Message{Role: ai.RoleUser, Content: "Thanks"}))
+		_, err := New(&mockProvider{}, tool.NewRegistry()).RegenerateLastSession(ctx, sess)
+		assert.ErrorIs(t, err, ErrNoTurnToRegenerate)
+		assert.Equal(t, 5, sess.Len())
+	})
+}
@@ -0,0 +1,505 @@
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/internal/store"
+)
+
+// Resume continues a run recorded with WithEventLog and returns its result.
+// The conversation is rebuilt from the recorded events, tool calls of the
+// last step that have no result are approved and executed, and the loop
+// continues from there with step numbers following on from the recording.
+// Calls that were approved or rejected before the interruption are not
+// decided again; a call whose approval was interrupted by cancellation is
+// sent to the approver again, which lets a human decide long after the
+// original run.
+//
+// Pass the same options as the original run, including WithEventLog, which
+// continues to record the run. Resume returns an error matching
+// ErrRunNotFound for unknown runs and ErrRunFinished for runs that ended
+// other than by cancellation, timeout, suspension, or error. The result's
+// history includes the messages of the earlier attempts.
+//
+// A resume holds a lock on the run until the continued run ends, taken
+// through the event log adapter if it implements store.Locker, as the
+// store/redis adapter does, and otherwise held within this process only.
+// Resuming a run that is already being resumed fails with an error
+// matching ErrRunInProgress. A run recorded with WithSuspendForApproval
+// must be resumed with it or with WithApprover, and fails with
+// ErrApprovalModeRequired otherwise, so its gated tools never run
+// unapproved.
+func (a *Agent) Resume(ctx context.Context, runID string, opts ...Option) (*Result, error) {
+	rp, eventCh, err := a.resume(ctx, runID, "", nil, opts)
+	if err != nil {
+		return &Result{RunID: runID, Termination: TerminationError, Error: err}, err
+	}
+	return collect(eventCh, rp.history, rp.results)
+}
+
+// ResumeStream is the streaming form of Resume. Errors loading the run are
+// reported as a RunError event.
+func (a *Agent) ResumeStream(ctx context.Context, runID string, opts ...Option) <-chan Event {
+	_, eventCh, err := a.resume(ctx, runID, "", nil, opts)
+	if err != nil {
+		return errorStream(err)
+	}
+	return eventCh
+}
+
+// ResumeWithApproval continues a run suspended by WithSuspendForApproval
+// with the decision for the tool call identified by token, the resume token
+// from Result.ResumeToken or the run's tool approval activity. A rejected
+// call gets reason as its error result, as with an Approver. If the step
+// has more calls needing approval, the run suspends again with a new token.
+//
+// Pass the same options as the original run. ResumeWithApproval returns an
+// error matching ErrInvalidResumeToken if token is malformed, was not issued
+// by the run, or its tool call is no longer awaiting a decision. Of several
+// concurrent calls with the same token, such as a double-submitted approval
+// form, only the first continues the run; the others fail with an error
+// matching both ErrInvalidResumeToken and ErrRunInProgress while it runs,
+// and "already decided" after. The decision is recorded under the run's
+// lock, as described for Resume.
+func (a *Agent) ResumeWithApproval(ctx context.Context, token string, approved bool, reason string, opts ...Option) (*Result, error) {
+	runID, decision, err := parseDecision(token, approved, reason)
+	if err == nil {
+		var rp *resumePoint
+		var eventCh <-chan Event
+		if rp, eventCh, err = a.resume(ctx, runID, token, decision, opts); err == nil {
+			return collect(eventCh, rp.history, rp.results)
+		}
+	}
+	return &Result{RunID: runID, Termination: TerminationError, Error: err}, err
+}
+
+// ResumeWithApprovalStream is the streaming form of ResumeWithApproval.
+// Errors loading the run are reported as a RunError event.
+func (a *Agent) ResumeWithApprovalStream(ctx context.Context, token string, approved bool, reason string, opts ...Option) <-chan Event {
+	runID, decision, err := parseDecision(token, approved, reason)
+	if err != nil {
+		return errorStream(err)
+	}
+	_, eventCh, err := a.resume(ctx, runID, token, decision, opts)
+	if err != nil {
+		return errorStream(err)
+	}
+	return eventCh
+}
+
+// errorStream returns a closed channel holding a single RunError event.
+func errorStream(err error) <-chan Event {
+	ch := event.NewChannel()
+	event.Emit(ch, Event{Type: event.RunError, Error: err})
+	close(ch)
+	return ch
+}
+
+// resumeToken identifies a tool call awaiting approval in a recorded run.
+// Nonce is random, so a token cannot be forged from the run and call IDs;
+// the run record keeps the token it issued to compare against.
+type resumeToken struct {
+	RunID      string `json:"r"`
+	ToolCallID string `json:"c"`
+	Nonce      string `json:"n"`
+}
+
+// newResumeToken encodes a new resume token for a tool call of runID.
+func newResumeToken(runID, toolCallID string) string {
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	raw, _ := json.Marshal(resumeToken{
+		RunID:      runID,
+		ToolCallID: toolCallID,
+		Nonce:      base64.RawURLEncoding.EncodeToString(nonce),
+	})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// parseResumeToken decodes token.
+func parseResumeToken(token string) (resumeToken, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	var t resumeToken
+	if err == nil {
+		err = json.Unmarshal(raw, &t)
+	}
+	if err != nil || t.RunID == "" || t.ToolCallID == "" || t.Nonce == "" {
+		return resumeToken{}, ErrInvalidResumeToken
+	}
+	return t, nil
+}
+
+// parseDecision decodes token into its run ID and the decision for its call.
+func parseDecision(token string, approved bool, reason string) (string, *ApprovalDecision, error) {
+	t, err := parseResumeToken(token)
+	if err != nil {
+		return "", nil, err
+	}
+	return t.RunID, &ApprovalDecision{ToolCallID: t.ToolCallID, Approved: approved, Reason: reason}, nil
+}
+
+// resume loads runID and starts continuing it. If decision is non-nil it
+// is the decision for the pending tool call token was issued for; it is
+// checked and recorded before the run continues. The run's lock is held
+// from loading the record until the continued run ends, so only one
+// resume of a run proceeds at a time.
+func (a *Agent) resume(ctx context.Context, runID, token string, decision *ApprovalDecision, opts []Option) (*resumePoint, <-chan Event, error) {
+	options := a.applyOptions(opts)
+	if options.EventLog == nil {
+		return nil, nil, ErrNoEventLog
+	}
+	release, ok, err := store.Hold(ctx, options.EventLog, runKey(runID)+"/lock", runLockTTL)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		err := fmt.Errorf("%w: %s", ErrRunInProgress, runID)
+		if decision != nil {
+			err = fmt.Errorf("%w: %w", ErrInvalidResumeToken, err)
+		}
+		return nil, nil, err
+	}
+
+	rec, err := loadRun(ctx, options.EventLog, runID)
+	var rp *resumePoint
+	if err == nil {
+		rp, err = replay(rec)
+	}
+	if err == nil && rec.SuspendForApproval && !options.SuspendForApproval && options.Approver == nil {
+		err = ErrApprovalModeRequired
+	}
+	if err == nil && decision != nil {
+		err = decide(ctx, options.EventLog, rec, rp, token, *decision)
+	}
+	if err != nil {
+		release()
+		return nil, nil, err
+	}
+	rec.SuspendForApproval = rec.SuspendForApproval || options.SuspendForApproval
+	log := newEventLog(options.EventLog, rec)
+	log.release = release
+	return rp, a.startLogged(ctx, log, rp.history, rp, opts), nil
+}
+
+// decide records decision for the tool call that token was issued for in
+// the run rec, whose state is rp, while the caller holds the run's lock.
+func decide(ctx context.Context, adapter store.Adapter, rec *runRecord, rp *resumePoint, token string, decision ApprovalDecision) error {
+	id := decision.ToolCallID
+	if !slices.ContainsFunc(rp.pending(), func(tc ai.ToolCall) bool { return tc.ID == id }) {
+		return fmt.Errorf("%w: tool call %q is not awaiting approval", ErrInvalidResumeToken, id)
+	}
+	if subtle.ConstantTimeCompare([]byte(rp.tokens[id]), []byte(token)) != 1 {
+		return fmt.Errorf("%w: not issued for tool call %q", ErrInvalidResumeToken, id)
+	}
+	if _, decided := rp.decisions[id]; decided {
+		return fmt.Errorf("%w: tool call %q is already decided", ErrInvalidResumeToken, id)
+	}
+	rp.decisions[id] = decision
+
+	// The continued run logs the decision again when it applies it; replay
+	// treats the repeat as the same decision
+	tc := rp.calls[slices.IndexFunc(rp.calls, func(tc ai.ToolCall) bool { return tc.ID == id })]
+	decided := loggedEvent{Type: event.ToolCallApproved, Step: rp.step, ToolCall: &tc, Timestamp: time.Now()}
+	if !decision.Approved {
+		decided.Type, decided.Message = event.ToolCallRejected, decision.Reason
+	}
+	rec.Events = append(rec.Events, decided)
+	return newEventLog(adapter, rec).save(ctx)
+}
+
+// runLockTTL bounds how long a crashed process can hold a run's lock. A
+// live holder refreshes it if the event log adapter is a store.Refresher.
+const runLockTTL = time.Minute
+
+// runRecord is the persisted event log of a durable run.
+type runRecord struct {
+	RunID    string        `json:"runId"`
+	Messages []ai.Message  `json:"messages"`
+	Events   []loggedEvent `json:"events"`
+	// SuspendForApproval records that the run gates tools with
+	// WithSuspendForApproval, which resuming it must keep.
+	SuspendForApproval bool `json:"suspendForApproval,omitempty"`
+}
+
+// loggedEvent is the persisted form of an Event. Errors are kept as text.
+type loggedEvent struct {
+	Type             event.Type     `json:"type"`
+	Step             int            `json:"step,omitempty"`
+	MessageID        string         `json:"messageId,omitempty"`
+	Delta            string         `json:"delta,omitempty"`
+	Response         *ai.Response   `json:"response,omitempty"`
+	ToolCall         *ai.ToolCall   `json:"toolCall,omitempty"`
+	ToolResult       *ai.ToolResult `json:"toolResult,omitempty"`
+	Message          string         `json:"message,omitempty"`
+	Error            string         `json:"error,omitempty"`
+	PendingToolCalls []ai.ToolCall  `json:"pendingToolCalls,omitempty"`
+	ResumeToken      string         `json:"resumeToken,omitempty"`
+	Timestamp        time.Time      `json:"timestamp"`
+}
+
+func newLoggedEvent(ev Event) loggedEvent {
+	le := loggedEvent{
+		Type:             ev.Type,
+		Step:             ev.Step,
+		MessageID:        ev.MessageID,
+		Delta:            ev.Delta,
+		Response:         ev.Response,
+		ToolCall:         ev.ToolCall,
+		ToolResult:       ev.ToolResult,
+		Message:          ev.Message,
+		PendingToolCalls: ev.PendingToolCalls,
+		Timestamp:        ev.Timestamp,
+	}
+	if ev.Error != nil {
+		le.Error = ev.Error.Error()
+	}
+	if act, ok := ev.ActivityContent.(event.ToolApprovalActivity); ok {
+		le.ResumeToken = act.ResumeToken
+	}
+	return le
+}
+
+// runKey returns the adapter key for a run's event log.
+func runKey(runID string) string {
+	return "agent/run/" + runID
+}
+
+// loadRun reads the event log of runID.
+func loadRun(ctx context.Context, adapter store.Adapter, runID string) (*runRecord, error) {
+	raw, ok, err := adapter.Get(ctx, runKey(runID))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRunNotFound, runID)
+	}
+	var rec runRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return nil, &store.SerializationError{Key: runKey(runID), Err: err}
+	}
+	return &rec, nil
+}
+
+// eventLog appends a run's events to its record and persists it in the
+// background, so a slow adapter never blocks the run or drops events.
+// Saves are coalesced: each one writes every event appended so far.
+type eventLog struct {
+	adapter store.Adapter
+	release func() // of the run's lock, if a resume holds it
+
+	mu  sync.Mutex
+	rec *runRecord
+
+	dirty chan struct{}
+	err   error
+}
+
+func newEventLog(adapter store.Adapter, rec *runRecord) *eventLog {
+	return &eventLog{adapter: adapter, rec: rec, dirty: make(chan struct{}, 1)}
+}
+
+// record forwards events from in to out, logging each one, and closes out
+// once in is closed and the log is saved. A save failure cancels the run
+// through cancel and is reported as a final RunError.
+func (l *eventLog) record(ctx context.Context, in <-chan Event, out chan<- Event, cancel context.CancelCauseFunc) {
+	defer close(out)
+
+	// Saves outlive cancellation so an interrupted run is still recorded
+	saveCtx := context.WithoutCancel(ctx)
+	saved := make(chan struct{})
+	go func() {
+		defer close(saved)
+		for range l.dirty {
+			if l.err != nil {
+				continue
+			}
+			if err := l.save(saveCtx); err != nil {
+				l.err = fmt.Errorf("agent: event log %s: %w", l.rec.RunID, err)
+				cancel(l.err)
+			}
+		}
+	}()
+
+	for ev := range in {
+		l.mu.Lock()
+		l.rec.Events = append(l.rec.Events, newLoggedEvent(ev))
+		l.mu.Unlock()
+		select {
+		case l.dirty <- struct{}{}:
+		default:
+		}
+		// Block rather than drop, so consumers see every logged event
+		out <- ev
+	}
+
+	close(l.dirty)
+	<-saved
+	if l.release != nil {
+		l.release()
+	}
+	if l.err != nil {
+		out <- Event{Type: event.RunError, Error: l.err, Timestamp: time.Now()}
+	}
+}
+
+// save writes the record as it is now.
+func (l *eventLog) save(ctx context.Context) error {
+	l.mu.Lock()
+	raw, err := json.Marshal(l.rec)
+	l.mu.Unlock()
+	if err != nil {
+		return &store.SerializationError{Key: runKey(l.rec.RunID), Err: err}
+	}
+	return l.adapter.Set(ctx, runKey(l.rec.RunID), raw)
+}
+
+// resumePoint is where a recorded run left off.
+type resumePoint struct {
+	// history is the conversation so far, including the assistant message
+	// of the last step if it requested tools.
+	history []ai.Message
+
+	// step is the last step that completed its chat call.
+	step int
+
+	// response is the response of the last step.
+	response *ai.Response
+
+	// calls are the tool calls of the last step, and results the results
+	// recorded for them. Calls without a result are still pending.
+	calls   []ai.ToolCall
+	results []ai.ToolResult
+
+	// decisions holds the approval decisions already made for the calls,
+	// and tokens the resume token last issued for each suspended call.
+	decisions map[string]ApprovalDecision
+	tokens    map[string]string
+
+	// usage holds the usage of every completed step, for budgets.
+	usage []ai.Usage
+}
+
+// pending returns the tool calls of the last step that have no result.
+func (rp *resumePoint) pending() []ai.ToolCall {
+	var pending []ai.ToolCall
+	for _, tc := range rp.calls {
+		if !slices.ContainsFunc(rp.results, func(r ai.ToolResult) bool { return r.ToolCallID == tc.ID }) {
+			pending = append(pending, tc)
+		}
+	}
+	return pending
+}
+
+// replay rebuilds the state of a run from its event log, the same way Run
+// assembles its result. It returns ErrRunFinished if the last attempt ended
+// for a reason other than cancellation, a timeout, suspension, or an error.
+func replay(rec *runRecord) (*resumePoint, error) {
+	rp := &resumePoint{history: append([]ai.Message{}, rec.Messages...)}
+	var pendingAssistant *ai.Message
+	var finished bool
+
+	commit := func() {
+		if pendingAssistant != nil {
+			rp.history = append(rp.history, *pendingAssistant)
+			pendingAssistant = nil
+		}
+		if len(rp.results) > 0 {
+			rp.history = append(rp.history, ai.NewToolResultMessage(rp.results...))
+		}
+		rp.calls, rp.results, rp.decisions, rp.tokens = nil, nil, nil, nil
+	}
+
+	for _, ev := range rec.Events {
+		switch ev.Type {
+		case event.RunStart, event.RunError:
+			finished = false
+
+		case event.RunEnd:
+			switch TerminationReason(ev.Message) {
+			case TerminationCancelled, TerminationTimeout, TerminationSuspended:
+				finished = false
+			default:
+				finished = true
+			}
+
+		case event.StepStart:
+			commit()
+
+		case event.StepEnd:
+			rp.step = ev.Step
+			rp.response = ev.Response
+			if ev.Response == nil {
+				continue
+			}
+			rp.usage = append(rp.usage, ev.Response.Usage)
+			if len(ev.Response.ToolCalls) > 0 {
+				pendingAssistant = &ai.Message{
+					Role:      ai.RoleAssistant,
+					Content:   ev.Response.Content,
+					ToolCalls: ev.Response.ToolCalls,
+				}
+				rp.calls = ev.Response.ToolCalls
+				rp.decisions = make(map[string]ApprovalDecision)
+				rp.tokens = make(map[string]string)
+			}
+
+		case event.ActivitySnapshot:
+			if ev.ResumeToken != "" && rp.tokens != nil {
+				if t, err := parseResumeToken(ev.ResumeToken); err == nil {
+					rp.tokens[t.ToolCallID] = ev.ResumeToken
+				}
+			}
+
+		case event.ToolCallApproved:
+			if ev.ToolCall != nil && rp.decisions != nil {
+				rp.decisions[ev.ToolCall.ID] = ApprovalDecision{ToolCallID: ev.ToolCall.ID, Approved: true}
+			}
+
+		case event.ToolCallRejected:
+			if ev.ToolCall != nil && rp.decisions != nil {
+				rp.decisions[ev.ToolCall.ID] = ApprovalDecision{ToolCallID: ev.ToolCall.ID, Reason: ev.Message}
+			}
+
+		case event.ToolCallResult:
+			if ev.ToolResult != nil && len(rp.calls) > 0 {
+				rp.results = append(rp.results, *ev.ToolResult)
+			}
+		}
+	}
+
+	if finished {
+		return nil, fmt.Errorf("%w: %s", ErrRunFinished, rec.RunID)
+	}
+	if pendingAssistant != nil {
+		rp.history = append(rp.history, *pendingAssistant)
+	}
+	return rp, nil
+}
+
+// mergeResults orders the recorded and new results of a step's tool calls
+// like the calls themselves.
+func mergeResults(calls []ai.ToolCall, results ...[]ai.ToolResult) []ai.ToolResult {
+	byID := make(map[string]ai.ToolResult)
+	for _, rs := range results {
+		for _, r := range rs {
+			byID[r.ToolCallID] = r
+		}
+	}
+	merged := make([]ai.ToolResult, 0, len(byID))
+	for _, tc := range calls {
+		if r, ok := byID[tc.ID]; ok {
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
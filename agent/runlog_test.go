@@ -0,0 +1,430 @@
+package agent
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/internal/store"
+	"github.com/spetersoncode/gains/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgent_Run_EventLog(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	provider := &mockProvider{responses: []mockResponse{{content: "Hello"}}}
+	agent := New(provider, tool.NewRegistry())
+
+	result, err := agent.Run(context.Background(), []ai.Message{
+		{Role: ai.RoleUser, Content: "Hi"},
+	}, WithEventLog(adapter))
+	require.NoError(t, err)
+	require.NotEmpty(t, result.RunID)
+
+	rec, err := loadRun(context.Background(), adapter, result.RunID)
+	require.NoError(t, err)
+	assert.Equal(t, result.RunID, rec.RunID)
+	require.Len(t, rec.Messages, 1)
+	require.NotEmpty(t, rec.Events)
+	assert.Equal(t, event.RunStart, rec.Events[0].Type)
+	assert.Equal(t, event.RunEnd, rec.Events[len(rec.Events)-1].Type)
+}
+
+func TestAgent_Resume_PendingApproval(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	provider := &mockProvider{
+		responses: []mockResponse{
+			{content: "Calling tool", toolCalls: []ai.ToolCall{{ID: "c1", Name: "tool1", Arguments: "{}"}}},
+			{content: "Done"},
+		},
+	}
+
+	var executed atomic.Int32
+	registry := tool.NewRegistry()
+	registry.MustRegister(ai.Tool{Name: "tool1"}, func(ctx context.Context, call ai.ToolCall) (string, error) {
+		executed.Add(1)
+		return "result", nil
+	})
+	agent := New(provider, registry)
+
+	// The first attempt gives up waiting for a decision
+	ctx, cancel := context.WithCancel(context.Background())
+	result, err := agent.Run(ctx, []ai.Message{{Role: ai.RoleUser, Content: "Go"}},
+		WithEventLog(adapter),
+		WithRunID("run-1"),
+		WithApprover(func(ctx context.Context, call ai.ToolCall) (bool, string) {
+			cancel()
+			return false, "approval cancelled"
+		}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, TerminationCancelled, result.Termination)
+	assert.Equal(t, "run-1", result.RunID)
+	assert.Equal(t, int32(0), executed.Load())
+
+	// The decision arrives later
+	result, err = agent.Resume(context.Background(), "run-1",
+		WithEventLog(adapter),
+		WithApprover(func(ctx context.Context, call ai.ToolCall) (bool, string) {
+			return true, ""
+		}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, TerminationComplete, result.Termination)
+	assert.Equal(t, "Done", result.Response.Content)
+	assert.Equal(t, 2, result.Steps)
+	assert.Equal(t, int32(1), executed.Load())
+
+	msgs := result.Messages()
+	require.Len(t, msgs, 3)
+	assert.Equal(t, ai.RoleAssistant, msgs[1].Role)
+	require.Len(t, msgs[2].ToolResults, 1)
+	assert.Equal(t, "result", msgs[2].ToolResults[0].Content)
+
+	t.Run("finished run cannot resume", func(t *testing.T) {
+		_, err := agent.Resume(context.Background(), "run-1", WithEventLog(adapter))
+		assert.ErrorIs(t, err, ErrRunFinished)
+	})
+}
+
+func TestAgent_Resume_SkipsRecordedToolCalls(t *testing.T) {
+	calls := []ai.ToolCall{
+		{ID: "c1", Name: "tool1", Arguments: "{}"},
+		{ID: "c2", Name: "tool1", Arguments: "{}"},
+	}
+	result1 := ai.ToolResult{ToolCallID: "c1", Content: "first"}
+
+	// A run that stopped after the first of two approved calls completed
+	rec := runRecord{
+		RunID:    "run-1",
+		Messages: []ai.Message{{Role: ai.RoleUser, Content: "Go"}},
+		Events: []loggedEvent{
+			{Type: event.RunStart},
+			{Type: event.StepStart, Step: 1},
+			{Type: event.StepEnd, Step: 1, Response: &ai.Response{ToolCalls: calls}},
+			{Type: event.ToolCallApproved, Step: 1, ToolCall: &calls[0]},
+			{Type: event.ToolCallApproved, Step: 1, ToolCall: &calls[1]},
+			{Type: event.ToolCallResult, Step: 1, ToolCall: &calls[0], ToolResult: &result1},
+		},
+	}
+	raw, err := json.Marshal(rec)
+	require.NoError(t, err)
+	adapter := store.NewMemoryAdapter()
+	require.NoError(t, adapter.Set(context.Background(), runKey("run-1"), raw))
+
+	var executed []string
+	registry := tool.NewRegistry()
+	registry.MustRegister(ai.Tool{Name: "tool1"}, func(ctx context.Context, call ai.ToolCall) (string, error) {
+		executed = append(executed, call.ID)
+		return "second", nil
+	})
+	var approverCalls atomic.Int32
+	agent := New(&mockProvider{responses: []mockResponse{{content: "Done"}}}, registry)
+
+	result, err := agent.Resume(context.Background(), "run-1",
+		WithEventLog(adapter),
+		WithApprover(func(ctx context.Context, call ai.ToolCall) (bool, string) {
+			approverCalls.Add(1)
+			return true, ""
+		}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, TerminationComplete, result.Termination)
+	assert.Equal(t, []string{"c2"}, executed)
+	assert.Equal(t, int32(0), approverCalls.Load())
+
+	msgs := result.Messages()
+	require.Len(t, msgs, 3)
+	require.Len(t, msgs[2].ToolResults, 2)
+	assert.ElementsMatch(t, []string{"first", "second"},
+		[]string{msgs[2].ToolResults[0].Content, msgs[2].ToolResults[1].Content})
+}
+
+func TestAgent_Resume_Errors(t *testing.T) {
+	agent := New(&mockProvider{}, tool.NewRegistry())
+
+	t.Run("requires an event log", func(t *testing.T) {
+		_, err := agent.Resume(context.Background(), "run-1")
+		assert.ErrorIs(t, err, ErrNoEventLog)
+	})
+
+	t.Run("unknown run", func(t *testing.T) {
+		_, err := agent.Resume(context.Background(), "missing", WithEventLog(store.NewMemoryAdapter()))
+		assert.ErrorIs(t, err, ErrRunNotFound)
+	})
+
+	t.Run("stream reports load errors", func(t *testing.T) {
+		var errs []error
+		for ev := range agent.ResumeStream(context.Background(), "missing", WithEventLog(store.NewMemoryAdapter())) {
+			if ev.Type == event.RunError {
+				errs = append(errs, ev.Error)
+			}
+		}
+		require.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], ErrRunNotFound)
+	})
+}
+
+func TestAgent_ResumeWithApproval(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	provider := &mockProvider{
+		responses: []mockResponse{
+			{content: "Calling tools", toolCalls: []ai.ToolCall{
+				{ID: "c1", Name: "delete", Arguments: "{}"},
+				{ID: "c2", Name: "delete", Arguments: "{}"},
+			}},
+			{content: "Done"},
+		},
+	}
+
+	var executed []string
+	registry := tool.NewRegistry()
+	registry.MustRegister(ai.Tool{Name: "delete"}, func(ctx context.Context, call ai.ToolCall) (string, error) {
+		executed = append(executed, call.ID)
+		return "deleted", nil
+	})
+	agent := New(provider, registry)
+	opts := []Option{WithEventLog(adapter), WithSuspendForApproval()}
+
+	result, err := agent.Run(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Clean up"}}, opts...)
+	require.NoError(t, err)
+	assert.Equal(t, TerminationSuspended, result.Termination)
+	first := result.ResumeToken
+	require.NotEmpty(t, first)
+	assert.Empty(t, executed)
+
+	// Rejecting the first call suspends again for the second
+	result, err = agent.ResumeWithApproval(context.Background(), first, false, "keep it", opts...)
+	require.NoError(t, err)
+	assert.Equal(t, TerminationSuspended, result.Termination)
+	second := result.ResumeToken
+	require.NotEmpty(t, second)
+	assert.NotEqual(t, first, second)
+
+	t.Run("decided token is rejected", func(t *testing.T) {
+		_, err := agent.ResumeWithApproval(context.Background(), first, true, "", opts...)
+		assert.ErrorIs(t, err, ErrInvalidResumeToken)
+	})
+
+	result, err = agent.ResumeWithApproval(context.Background(), second, true, "", opts...)
+	require.NoError(t, err)
+	assert.Equal(t, TerminationComplete, result.Termination)
+	assert.Equal(t, "Done", result.Response.Content)
+	assert.Equal(t, []string{"c2"}, executed)
+
+	msgs := result.Messages()
+	require.Len(t, msgs, 3)
+	require.Len(t, msgs[2].ToolResults, 2)
+	assert.Equal(t, ai.ToolResult{ToolCallID: "c1", Content: "keep it", IsError: true}, msgs[2].ToolResults[0])
+	assert.Equal(t, "deleted", msgs[2].ToolResults[1].Content)
+}
+
+func TestAgent_ResumeWithApproval_Concurrent(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	provider := &mockProvider{
+		responses: []mockResponse{
+			{content: "Calling tool", toolCalls: []ai.ToolCall{{ID: "c1", Name: "delete", Arguments: "{}"}}},
+			{content: "Done"},
+		},
+	}
+
+	var executed atomic.Int32
+	registry := tool.NewRegistry()
+	registry.MustRegister(ai.Tool{Name: "delete"}, func(ctx context.Context, call ai.ToolCall) (string, error) {
+		executed.Add(1)
+		return "deleted", nil
+	})
+	agent := New(provider, registry)
+	opts := []Option{WithEventLog(adapter), WithSuspendForApproval()}
+
+	result, err := agent.Run(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Clean up"}}, opts...)
+	require.NoError(t, err)
+	token := result.ResumeToken
+	require.NotEmpty(t, token)
+
+	// A double-submitted approval
+	const submits = 8
+	errs := make([]error, submits)
+	var wg sync.WaitGroup
+	for i := range submits {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = agent.ResumeWithApproval(context.Background(), token, true, "", opts...)
+		}()
+	}
+	wg.Wait()
+
+	var succeeded int
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.ErrorIs(t, err, ErrInvalidResumeToken)
+	}
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, int32(1), executed.Load())
+
+	rec, err := loadRun(context.Background(), adapter, result.RunID)
+	require.NoError(t, err)
+	var results, ends int
+	for _, ev := range rec.Events {
+		switch ev.Type {
+		case event.ToolCallResult:
+			results++
+		case event.RunEnd:
+			ends++
+		}
+	}
+	assert.Equal(t, 1, results, "the tool result is recorded once")
+	assert.Equal(t, 2, ends, "the suspended run and the one continuation")
+}
+
+func TestAgent_ResumeWithApproval_ForgedToken(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	provider := &mockProvider{
+		responses: []mockResponse{
+			{content: "Calling tool", toolCalls: []ai.ToolCall{{ID: "c1", Name: "delete", Arguments: "{}"}}},
+		},
+	}
+	registry := tool.NewRegistry()
+	registry.MustRegister(ai.Tool{Name: "delete"}, func(ctx context.Context, call ai.ToolCall) (string, error) {
+		t.Fatal("forged approval executed the tool")
+		return "", nil
+	})
+	agent := New(provider, registry)
+	opts := []Option{WithEventLog(adapter), WithSuspendForApproval()}
+
+	result, err := agent.Run(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Clean up"}}, opts...)
+	require.NoError(t, err)
+	require.NotEmpty(t, result.ResumeToken)
+
+	encode := func(v any) string {
+		raw, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(raw)
+	}
+	forged := map[string]string{
+		"guessed nonce": encode(resumeToken{RunID: result.RunID, ToolCallID: "c1", Nonce: "guess"}),
+		"no nonce":      encode(map[string]string{"r": result.RunID, "c": "c1"}),
+		"minted nonce":  newResumeToken(result.RunID, "c1"),
+	}
+	for name, token := range forged {
+		t.Run(name, func(t *testing.T) {
+			_, err := agent.ResumeWithApproval(context.Background(), token, true, "", opts...)
+			assert.ErrorIs(t, err, ErrInvalidResumeToken)
+		})
+	}
+
+	// The genuine token still works after the failed attempts
+	result, err = agent.ResumeWithApproval(context.Background(), result.ResumeToken, false, "no", opts...)
+	require.NoError(t, err)
+	assert.Equal(t, TerminationRejected, result.Termination)
+}
+
+func TestAgent_SuspendForApproval_Errors(t *testing.T) {
+	agent := New(&mockProvider{}, tool.NewRegistry())
+
+	t.Run("requires an event log", func(t *testing.T) {
+		_, err := agent.Run(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}, WithSuspendForApproval())
+		assert.ErrorIs(t, err, ErrNoEventLog)
+	})
+
+	t.Run("malformed token", func(t *testing.T) {
+		_, err := agent.ResumeWithApproval(context.Background(), "not-a-token", true, "", WithEventLog(store.NewMemoryAdapter()))
+		assert.ErrorIs(t, err, ErrInvalidResumeToken)
+	})
+}
+
+func TestAgent_Resume_Locked(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	provider := &mockProvider{
+		responses: []mockResponse{
+			{content: "Calling tool", toolCalls: []ai.ToolCall{{ID: "c1", Name: "delete", Arguments: "{}"}}},
+			{content: "Done"},
+		},
+	}
+	registry := tool.NewRegistry()
+	registry.MustRegister(ai.Tool{Name: "delete"}, func(ctx context.Context, call ai.ToolCall) (string, error) {
+		return "deleted", nil
+	})
+	agent := New(provider, registry)
+	opts := []Option{WithEventLog(adapter), WithSuspendForApproval()}
+
+	result, err := agent.Run(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Clean up"}}, opts...)
+	require.NoError(t, err)
+	require.NotEmpty(t, result.ResumeToken)
+
+	// Another resume of the run is in progress
+	release, ok, err := store.Hold(context.Background(), adapter, runKey(result.RunID)+"/lock", runLockTTL)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, err = agent.Resume(context.Background(), result.RunID, opts...)
+	assert.ErrorIs(t, err, ErrRunInProgress)
+	_, err = agent.ResumeWithApproval(context.Background(), result.ResumeToken, true, "", opts...)
+	assert.ErrorIs(t, err, ErrRunInProgress)
+	assert.ErrorIs(t, err, ErrInvalidResumeToken)
+
+	release()
+	result, err = agent.ResumeWithApproval(context.Background(), result.ResumeToken, true, "", opts...)
+	require.NoError(t, err)
+	assert.Equal(t, TerminationComplete, result.Termination)
+}
+
+func TestAgent_Resume_RequiresApprovalMode(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	provider := &mockProvider{
+		responses: []mockResponse{
+			{content: "Calling tool", toolCalls: []ai.ToolCall{{ID: "c1", Name: "delete", Arguments: "{}"}}},
+		},
+	}
+	registry := tool.NewRegistry()
+	registry.MustRegister(ai.Tool{Name: "delete"}, func(ctx context.Context, call ai.ToolCall) (string, error) {
+		t.Fatal("resume ran a gated tool unapproved")
+		return "", nil
+	})
+	agent := New(provider, registry)
+
+	result, err := agent.Run(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Clean up"}},
+		WithEventLog(adapter), WithSuspendForApproval())
+	require.NoError(t, err)
+	require.Equal(t, TerminationSuspended, result.Termination)
+
+	_, err = agent.Resume(context.Background(), result.RunID, WithEventLog(adapter))
+	assert.ErrorIs(t, err, ErrApprovalModeRequired)
+
+	// The failed attempt released the run's lock
+	result, err = agent.Resume(context.Background(), result.RunID, WithEventLog(adapter), WithSuspendForApproval())
+	require.NoError(t, err)
+	assert.Equal(t, TerminationSuspended, result.Termination)
+}
+
+func TestAgent_RunStream_EventLogSlowConsumer(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	provider := &mockProvider{responses: []mockResponse{{content: "Hello"}}}
+	agent := New(provider, tool.NewRegistry())
+
+	events := agent.RunStream(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Hi"}},
+		WithEventLog(adapter), WithEventBuffer(1))
+	var got []Event
+	for ev := range events {
+		time.Sleep(time.Millisecond)
+		got = append(got, ev)
+	}
+	require.NotEmpty(t, got)
+
+	// The run itself drops what its buffer can't hold, but the relay
+	// delivers everything that was logged
+	rec, err := loadRun(context.Background(), adapter, got[0].RunID)
+	require.NoError(t, err)
+	assert.Len(t, got, len(rec.Events), "every logged event is delivered")
+}
@@ -0,0 +1,56 @@
+package agent
+
+import (
+	"context"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/session"
+)
+
+// RunSession runs the agent on a session's conversation history and appends
+// the messages the run produces, including the final assistant response, to
+// the session. Append the user's next message to the session before calling
+// it. The session is persisted after the run, so the conversation can be
+// resumed after a restart.
+//
+// The result is returned even if persisting the session fails; the run's
+// own error takes precedence.
+func (a *Agent) RunSession(ctx context.Context, sess *session.Session, opts ...Option) (*Result, error) {
+	history := sess.Messages()
+	result, err := a.Run(ctx, history, opts...)
+	syncErr := a.saveRun(ctx, sess, history, result, opts)
+	if err == nil {
+		err = syncErr
+	}
+	return result, err
+}
+
+// saveRun appends the messages a run on history produced to the session and
+// records the model it used.
+func (a *Agent) saveRun(ctx context.Context, sess *session.Session, history []ai.Message, result *Result, opts []Option) error {
+	var produced []ai.Message
+	if msgs := result.Messages(); len(msgs) > len(history) {
+		produced = msgs[len(history):]
+	}
+	if final := finalMessage(result); final != nil {
+		produced = append(produced, *final)
+	}
+
+	if err := sess.Append(ctx, produced...); err != nil {
+		return err
+	}
+	chatOpts := append(ai.ContextOptions(ctx), a.applyOptions(opts).ChatOptions...)
+	if m := ai.ApplyOptions(chatOpts...).Model; m != nil {
+		return sess.SetModel(ctx, m.String())
+	}
+	return nil
+}
+
+// finalMessage returns the assistant message for a run's final response.
+// Responses with tool calls are already part of the result history.
+func finalMessage(result *Result) *ai.Message {
+	if result.Response == nil || len(result.Response.ToolCalls) > 0 || result.Error != nil {
+		return nil
+	}
+	return &ai.Message{Role: ai.RoleAssistant, Content: result.Response.Content}
+}
@@ -0,0 +1,82 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/model"
+	"github.com/spetersoncode/gains/session"
+	"github.com/spetersoncode/gains/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgent_RunSession(t *testing.T) {
+	ctx := context.Background()
+	adapter := session.NewMemoryAdapter()
+	sessions := session.NewManager(adapter)
+
+	sess, err := sessions.Create(ctx)
+	require.NoError(t, err)
+	require.NoError(t, sess.Append(ctx, ai.Message{Role: ai.RoleUser, Content: "What's the weather in Tokyo?"}))
+
+	provider := &mockProvider{
+		responses: []mockResponse{
+			{
+				toolCalls: []ai.ToolCall{
+					{ID: "call_1", Name: "get_weather", Arguments: `{"location":"Tokyo"}`},
+				},
+			},
+			{content: "Sunny and 72°F."},
+		},
+	}
+	registry := tool.NewRegistry()
+	registry.MustRegister(
+		ai.Tool{Name: "get_weather", Description: "Get weather", Parameters: json.RawMessage(`{"type":"object"}`)},
+		func(ctx context.Context, call ai.ToolCall) (string, error) {
+			return `{"temp": 72}`, nil
+		},
+	)
+
+	result, err := New(provider, registry).RunSession(ctx, sess, WithModel(model.ClaudeSonnet45))
+	require.NoError(t, err)
+	assert.Equal(t, TerminationComplete, result.Termination)
+
+	msgs := sess.Messages()
+	require.Len(t, msgs, 4)
+	assert.Equal(t, ai.RoleUser, msgs[0].Role)
+	assert.Len(t, msgs[1].ToolCalls, 1)
+	assert.Equal(t, ai.RoleTool, msgs[2].Role)
+	assert.Equal(t, ai.RoleAssistant, msgs[3].Role)
+	assert.Equal(t, "Sunny and 72°F.", msgs[3].Content)
+	assert.Equal(t, model.ClaudeSonnet45.String(), sess.Metadata().Model)
+
+	// The conversation survives a restart.
+	resumed, err := session.NewManager(adapter).Resume(ctx, sess.ID())
+	require.NoError(t, err)
+	assert.Equal(t, msgs, resumed.Messages())
+
+	// A follow-up turn sees the full history.
+	require.NoError(t, resumed.Append(ctx, ai.Message{Role: ai.RoleUser, Content: "Thanks!"}))
+	_, err = New(&mockProvider{responses: []mockResponse{{content: "You're welcome."}}}, registry).RunSession(ctx, resumed)
+	require.NoError(t, err)
+	assert.Equal(t, 6, resumed.Len())
+}
+
+func TestAgent_RunSession_Stopped(t *testing.T) {
+	ctx := context.Background()
+	sess, err := session.NewManager(nil).Create(ctx)
+	require.NoError(t, err)
+	require.NoError(t, sess.Append(ctx, ai.Message{Role: ai.RoleUser, Content: "Tell me a story"}))
+
+	provider := &mockProvider{responses: []mockResponse{{content: "Once upon", finishReason: ai.FinishReasonStopped}}}
+	result, err := New(provider, tool.NewRegistry()).RunSession(ctx, sess)
+	require.NoError(t, err)
+	assert.Equal(t, TerminationStopped, result.Termination)
+
+	msgs := sess.Messages()
+	require.Len(t, msgs, 2)
+	assert.Equal(t, ai.Message{Role: ai.RoleAssistant, Content: "Once upon"}, msgs[1])
+}
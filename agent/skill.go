@@ -0,0 +1,114 @@
+package agent
+
+import (
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/tool"
+)
+
+// Skill bundles the tools and instructions for one capability, such as web
+// research or code editing, so it can be shared between agents and
+// packages. Add skills to a run with WithSkills, or to a Builder with
+// Skills.
+//
+// A package can export a skill as a function:
+//
+//	func Skill(apiKey string) agent.Skill {
+//	    return agent.Skill{
+//	        Name:   "tickets",
+//	        Prompt: "Look up the ticket before answering questions about it.",
+//	        Tools:  []tool.ToolPair{lookupTicket(apiKey), commentOnTicket(apiKey)},
+//	    }
+//	}
+type Skill struct {
+	// Name identifies the skill. A run applies each name once.
+	Name string
+
+	// Description explains what the skill provides.
+	Description string
+
+	// Prompt is added to the system prompt as its own section.
+	Prompt string
+
+	// Tools are registered for runs using the skill. A tool is skipped if
+	// the agent's registry or an earlier skill already has one of the
+	// same name.
+	Tools []tool.ToolPair
+
+	// Requires lists the model capabilities the skill needs. Runs on a
+	// model known to lack one fail with *ErrSkillRequirement.
+	Requires []ai.Capability
+}
+
+// WithSkills adds skills to the run: their tools join the agent's tools and
+// their prompts are added to the system prompt after WithSystemPrompt's, in
+// order. Skills with the same name as an earlier one are ignored, so
+// presets and callers can both add a common skill. The agent's registry is
+// not modified.
+func WithSkills(skills ...Skill) Option {
+	return func(o *Options) {
+		o.Skills = append(o.Skills, skills...)
+	}
+}
+
+// uniqueSkills returns skills without those whose name repeats an earlier
+// skill's.
+func uniqueSkills(skills []Skill) []Skill {
+	seen := make(map[string]bool, len(skills))
+	var unique []Skill
+	for _, s := range skills {
+		if s.Name != "" && seen[s.Name] {
+			continue
+		}
+		seen[s.Name] = true
+		unique = append(unique, s)
+	}
+	return unique
+}
+
+// withSkills returns a copy of the agent whose registry adds the tools of
+// skills, or a itself if there are no skills. It fails if model is known to
+// lack a capability a skill requires.
+func (a *Agent) withSkills(skills []Skill, model ai.Model) (*Agent, error) {
+	if len(skills) == 0 {
+		return a, nil
+	}
+
+	for _, s := range skills {
+		for _, c := range s.Requires {
+			if model != nil && !ai.ModelSupports(model, c) {
+				return nil, &ErrSkillRequirement{Skill: s.Name, Capability: c, Model: model}
+			}
+		}
+	}
+
+	registry := a.registry.Clone()
+	for _, s := range skills {
+		for _, p := range s.Tools {
+			if _, exists := registry.GetTool(p.Tool.Name); exists {
+				continue
+			}
+			if err := registry.Register(p.Tool, p.Handler); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	skilled := *a
+	skilled.registry = registry
+	return &skilled, nil
+}
+
+// skillPrompts returns the prompt sections of skills joined into one.
+func skillPrompts(skills []Skill) string {
+	var prompt string
+	for _, s := range skills {
+		if s.Prompt == "" {
+			continue
+		}
+		if prompt != "" {
+			prompt += "\n\n"
+		}
+		prompt += s.Prompt
+	}
+	return prompt
+}
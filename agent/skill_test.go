@@ -0,0 +1,94 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// capabilityModel is a model lacking the vision capability.
+type capabilityModel struct{}
+
+func (capabilityModel) String() string                          { return "no-vision" }
+func (capabilityModel) Provider() ai.Provider                   { return "test" }
+func (capabilityModel) SupportsCapability(c ai.Capability) bool { return c != ai.CapabilityVision }
+
+func skillTool(name, result string) tool.ToolPair {
+	return tool.ToolPair{
+		Tool:    ai.Tool{Name: name, Description: name},
+		Handler: func(context.Context, ai.ToolCall) (string, error) { return result, nil },
+	}
+}
+
+func TestAgent_Run_Skills(t *testing.T) {
+	provider := &recordingProvider{mockProvider: mockProvider{
+		responses: []mockResponse{
+			{toolCalls: []ai.ToolCall{{ID: "call_1", Name: "search", Arguments: `{}`}}},
+			{content: "Done"},
+		},
+	}}
+	registry := tool.NewRegistry()
+	registry.MustRegister(ai.Tool{Name: "search", Description: "agent search"}, func(context.Context, ai.ToolCall) (string, error) {
+		return "agent result", nil
+	})
+
+	research := Skill{
+		Name:   "research",
+		Prompt: "Cite your sources.",
+		Tools:  []tool.ToolPair{skillTool("search", "skill result"), skillTool("fetch", "")},
+	}
+	editing := Skill{
+		Name:   "editing",
+		Prompt: "Keep changes small.",
+		Tools:  []tool.ToolPair{skillTool("fetch", ""), skillTool("edit", "")},
+	}
+
+	a := New(provider, registry)
+	result, err := a.Run(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Hi"}},
+		WithSystemPrompt("Be brief."),
+		WithSkills(research, editing, Skill{Name: "research", Prompt: "Repeated."}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "Done", result.Response.Content)
+
+	sent := provider.messages[0]
+	assert.Equal(t, "Be brief.\n\nCite your sources.\n\nKeep changes small.", sent[0].Content)
+
+	var names []string
+	for _, t := range provider.options[0].Tools {
+		names = append(names, t.Name)
+	}
+	assert.ElementsMatch(t, []string{"search", "fetch", "edit"}, names)
+
+	// The agent's own tool wins over the skill's
+	tr := result.Messages()[2].ToolResults[0]
+	assert.Equal(t, "agent result", tr.Content)
+
+	// The agent's registry is unchanged
+	assert.Equal(t, []string{"search"}, registry.Names())
+}
+
+func TestAgent_Run_SkillRequirement(t *testing.T) {
+	vision := Skill{Name: "vision", Requires: []ai.Capability{ai.CapabilityVision}}
+	a := New(&mockProvider{}, tool.NewRegistry())
+
+	_, err := a.Run(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Hi"}},
+		WithModel(capabilityModel{}), WithSkills(vision))
+	var reqErr *ErrSkillRequirement
+	require.ErrorAs(t, err, &reqErr)
+	assert.Equal(t, "vision", reqErr.Skill)
+	assert.Equal(t, ai.CapabilityVision, reqErr.Capability)
+
+	// Without a known model the requirement cannot be checked
+	_, err = a.Run(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}, WithSkills(vision))
+	assert.NoError(t, err)
+}
+
+func TestBuilder_Skills(t *testing.T) {
+	a := NewBuilder(&mockProvider{}).Skills(Skill{Name: "s"}).MustBuild()
+	assert.Len(t, a.applyOptions(nil).Skills, 1)
+}
@@ -0,0 +1,318 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/chat"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/internal/store"
+	"github.com/spetersoncode/gains/tool"
+)
+
+// Supervisor is the Agent name carried by events from a team's supervisor.
+const Supervisor = "supervisor"
+
+// HandoffTool is the name of the tool a team's supervisor calls to hand a
+// task to a worker.
+const HandoffTool = "handoff"
+
+// HandoffArgs are the arguments of the handoff tool.
+type HandoffArgs struct {
+	Agent string `json:"agent"`
+	Task  string `json:"task"`
+}
+
+// WorkerOption configures a team worker.
+type WorkerOption func(*worker)
+
+// WithWorkerOptions sets agent options for every run of the worker, such as
+// WithMaxSteps or WithModel.
+func WithWorkerOptions(opts ...Option) WorkerOption {
+	return func(w *worker) {
+		w.options = append(w.options, opts...)
+	}
+}
+
+// WithWorkerBudget limits the cumulative cost in USD of all tasks handed to
+// the worker during a team run. Cost is computed from the model the worker
+// uses, as for WithBudget, and a handoff to a worker whose model has no
+// pricing fails with an error matching ErrUnpricedModel.
+func WithWorkerBudget(maxUSD float64) WorkerOption {
+	return func(w *worker) {
+		w.budget = maxUSD
+	}
+}
+
+// WithWorkerTokenBudget limits the cumulative input and output tokens of
+// all tasks handed to the worker during a team run.
+func WithWorkerTokenBudget(maxTokens int) WorkerOption {
+	return func(w *worker) {
+		w.tokenBudget = maxTokens
+	}
+}
+
+// worker is a named team member.
+type worker struct {
+	name        string
+	description string
+	agent       *Agent
+	options     []Option
+	budget      float64
+	tokenBudget int
+}
+
+// Team is a supervisor model that routes tasks to named worker agents.
+//
+// The supervisor sees a handoff tool listing the workers. Each handoff runs
+// the chosen worker on the team's shared conversation followed by the task,
+// and returns the worker's final response to the supervisor. Completed
+// handoffs are added to the shared conversation, so later workers see the
+// earlier ones' results.
+//
+// Example:
+//
+//	team := agent.NewTeam(client).
+//	    AddWorker("research", "Finds and summarizes sources", researchAgent,
+//	        agent.WithWorkerTokenBudget(50_000)).
+//	    AddWorker("writer", "Drafts the final answer", writerAgent)
+//
+//	result, err := team.Run(ctx, messages, agent.WithMaxSteps(8))
+type Team struct {
+	chatClient chat.Client
+
+	mu      sync.RWMutex
+	workers map[string]*worker
+}
+
+// NewTeam creates a team whose supervisor uses the given chat client.
+func NewTeam(c chat.Client) *Team {
+	return &Team{
+		chatClient: c,
+		workers:    make(map[string]*worker),
+	}
+}
+
+// AddWorker adds a worker agent to the team. The description tells the
+// supervisor what the worker is for. If a worker with the same name already
+// exists, it is replaced.
+func (t *Team) AddWorker(name, description string, a *Agent, opts ...WorkerOption) *Team {
+	w := &worker{name: name, description: description, agent: a}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.workers[name] = w
+	return t
+}
+
+// Workers returns the names of the team's workers in sorted order.
+func (t *Team) Workers() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	names := make([]string, 0, len(t.workers))
+	for name := range t.workers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run executes the team and returns the supervisor's final result. Its
+// history is the supervisor's conversation, and TotalUsage includes the
+// usage of every handoff.
+func (t *Team) Run(ctx context.Context, messages []ai.Message, opts ...Option) (*Result, error) {
+	var workerUsage ai.Usage
+	own := event.NewChannelSize(ApplyOptions(opts...).EventBuffer)
+	go func() {
+		defer close(own)
+		for ev := range t.RunStream(ctx, messages, opts...) {
+			if ev.Agent == Supervisor {
+				own <- ev
+				continue
+			}
+			if ev.Type == event.StepEnd && ev.Response != nil {
+				workerUsage.InputTokens += ev.Response.Usage.InputTokens
+				workerUsage.OutputTokens += ev.Response.Usage.OutputTokens
+				workerUsage.CachedInputTokens += ev.Response.Usage.CachedInputTokens
+				workerUsage.CacheWriteInputTokens += ev.Response.Usage.CacheWriteInputTokens
+			}
+		}
+	}()
+
+	result, err := collect(own, messages, nil)
+	result.TotalUsage.InputTokens += workerUsage.InputTokens
+	result.TotalUsage.OutputTokens += workerUsage.OutputTokens
+	result.TotalUsage.CachedInputTokens += workerUsage.CachedInputTokens
+	result.TotalUsage.CacheWriteInputTokens += workerUsage.CacheWriteInputTokens
+	return result, err
+}
+
+// RunStream executes the team and returns a channel of events from the
+// supervisor and its workers. Every event's Agent field names its producer:
+// Supervisor, or the name of the worker handling a handoff. Options apply to
+// the supervisor.
+func (t *Team) RunStream(ctx context.Context, messages []ai.Message, opts ...Option) <-chan Event {
+	h := &handoffs{
+		team:   t,
+		shared: store.NewMessageStoreFrom(messages, nil),
+		spent:  make(map[string]*budgetSpend),
+	}
+	registry := tool.NewRegistry()
+	registry.MustRegister(h.tool(), h.handle)
+	supervisor := New(t.chatClient, registry)
+
+	// A handoff lasts as long as the worker's run
+	supervisor.defaults = []Option{WithHandlerTimeout(0)}
+
+	out := event.NewChannelSize(ApplyOptions(opts...).EventBuffer)
+	go func() {
+		defer close(out)
+		for ev := range supervisor.RunStream(ctx, messages, opts...) {
+			if ev.Agent == "" {
+				ev.Agent = Supervisor
+			}
+			out <- ev
+		}
+	}()
+	return out
+}
+
+// handoffs runs the handoff tool calls of one team run.
+type handoffs struct {
+	team   *Team
+	shared *store.MessageStore
+
+	mu    sync.Mutex
+	spent map[string]*budgetSpend
+}
+
+// tool describes the handoff tool, listing the team's workers.
+func (h *handoffs) tool() ai.Tool {
+	names := h.team.Workers()
+
+	var desc strings.Builder
+	desc.WriteString("Hand a task to a member of your team and receive their answer. Team members:\n")
+	h.team.mu.RLock()
+	for _, name := range names {
+		fmt.Fprintf(&desc, "- %s: %s\n", name, h.team.workers[name].description)
+	}
+	h.team.mu.RUnlock()
+
+	schema, _ := json.Marshal(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"agent": map[string]any{
+				"type":        "string",
+				"description": "Name of the team member to hand the task to",
+				"enum":        names,
+			},
+			"task": map[string]any{
+				"type":        "string",
+				"description": "The task, with any context the team member needs",
+			},
+		},
+		"required": []string{"agent", "task"},
+	})
+
+	return ai.Tool{
+		Name:        HandoffTool,
+		Description: strings.TrimSpace(desc.String()),
+		Parameters:  schema,
+	}
+}
+
+// handle runs a worker on a handoff, forwarding its events tagged with the
+// worker's name, and returns the worker's final response.
+func (h *handoffs) handle(ctx context.Context, call ai.ToolCall) (string, error) {
+	var args HandoffArgs
+	if err := json.Unmarshal([]byte(call.Arguments), &args); err != nil {
+		return "", fmt.Errorf("failed to parse arguments: %w", err)
+	}
+
+	h.team.mu.RLock()
+	w, ok := h.team.workers[args.Agent]
+	h.team.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown team member %q", args.Agent)
+	}
+
+	h.mu.Lock()
+	spent := h.spent[w.name]
+	if spent == nil {
+		spent = &budgetSpend{}
+		h.spent[w.name] = spent
+	}
+	err := spent.check(&Options{Budget: w.budget, TokenBudget: w.tokenBudget})
+	remaining := budgetSpend{cost: w.budget - spent.cost, tokens: w.tokenBudget - spent.tokens}
+	h.mu.Unlock()
+	if err != nil {
+		return "", fmt.Errorf("team member %s has exhausted its budget: %w", w.name, err)
+	}
+
+	// The remaining budget bounds this handoff's run
+	runOpts := append([]Option{}, w.options...)
+	if w.budget > 0 {
+		runOpts = append(runOpts, WithBudget(remaining.cost))
+	}
+	if w.tokenBudget > 0 {
+		runOpts = append(runOpts, WithTokenBudget(remaining.tokens))
+	}
+	var priced pricedModel
+	if w.budget > 0 {
+		// The handoff's run fails at its start if the model has no pricing
+		priced, _ = w.agent.pricedModel(append(ai.ContextOptions(ctx), w.agent.applyOptions(w.options).ChatOptions...))
+	}
+
+	task := ai.Message{Role: ai.RoleUser, Content: args.Task}
+	messages := append(h.shared.Messages(), task)
+	forwardCh := event.ForwardChannelFromContext(ctx)
+
+	var response *ai.Response
+	var runErr error
+	for ev := range w.agent.RunStream(ctx, messages, runOpts...) {
+		if ev.Agent == "" {
+			ev.Agent = w.name
+		}
+		if forwardCh != nil {
+			select {
+			case forwardCh <- ev:
+			case <-ctx.Done():
+			}
+		}
+
+		switch ev.Type {
+		case event.StepEnd:
+			if ev.Response != nil {
+				h.mu.Lock()
+				spent.add(ev.Response.Usage, priced)
+				h.mu.Unlock()
+				response = ev.Response
+			}
+		case event.RunEnd:
+			if ev.Response != nil {
+				response = ev.Response
+			}
+		case event.RunError:
+			runErr = ev.Error
+		}
+	}
+
+	if runErr != nil {
+		return "", fmt.Errorf("team member %s failed: %w", w.name, runErr)
+	}
+	if response == nil {
+		return "", fmt.Errorf("team member %s returned no response", w.name)
+	}
+
+	h.shared.Append(task, ai.Message{Role: ai.RoleAssistant, Content: response.Content})
+	return response.Content, nil
+}
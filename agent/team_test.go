@@ -0,0 +1,126 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func handoffCall(id, agent, task string) ai.ToolCall {
+	args, _ := json.Marshal(HandoffArgs{Agent: agent, Task: task})
+	return ai.ToolCall{ID: id, Name: HandoffTool, Arguments: string(args)}
+}
+
+func TestTeam_Run(t *testing.T) {
+	supervisor := &recordingProvider{mockProvider: mockProvider{responses: []mockResponse{
+		{content: "Researching", toolCalls: []ai.ToolCall{handoffCall("c1", "research", "Find the release date")}},
+		{content: "Writing", toolCalls: []ai.ToolCall{handoffCall("c2", "writer", "Announce it")}},
+		{content: "All done"},
+	}}}
+	researcher := &recordingProvider{mockProvider: mockProvider{responses: []mockResponse{{content: "March 3"}}}}
+	writer := &recordingProvider{mockProvider: mockProvider{responses: []mockResponse{{content: "Shipping March 3!"}}}}
+
+	team := NewTeam(supervisor).
+		AddWorker("research", "Finds facts", New(researcher, tool.NewRegistry())).
+		AddWorker("writer", "Writes copy", New(writer, tool.NewRegistry()))
+	assert.Equal(t, []string{"research", "writer"}, team.Workers())
+
+	input := []ai.Message{{Role: ai.RoleUser, Content: "Announce the release"}}
+	result, err := team.Run(context.Background(), input)
+	require.NoError(t, err)
+	assert.Equal(t, TerminationComplete, result.Termination)
+	assert.Equal(t, "All done", result.Response.Content)
+	assert.Equal(t, 3, result.Steps)
+
+	// Five chat calls of 10 input and 20 output tokens each
+	assert.Equal(t, 50, result.TotalUsage.InputTokens)
+	assert.Equal(t, 100, result.TotalUsage.OutputTokens)
+
+	msgs := result.Messages()
+	require.Len(t, msgs, 5)
+	assert.Equal(t, "March 3", msgs[2].ToolResults[0].Content)
+	assert.Equal(t, "Shipping March 3!", msgs[4].ToolResults[0].Content)
+
+	t.Run("workers share the conversation", func(t *testing.T) {
+		require.Len(t, researcher.messages, 1)
+		assert.Equal(t, append(input, ai.Message{Role: ai.RoleUser, Content: "Find the release date"}), researcher.messages[0])
+
+		require.Len(t, writer.messages, 1)
+		assert.Equal(t, []ai.Message{
+			input[0],
+			{Role: ai.RoleUser, Content: "Find the release date"},
+			{Role: ai.RoleAssistant, Content: "March 3"},
+			{Role: ai.RoleUser, Content: "Announce it"},
+		}, writer.messages[0])
+	})
+
+	t.Run("supervisor sees the handoff tool", func(t *testing.T) {
+		tools := supervisor.options[0].Tools
+		require.Len(t, tools, 1)
+		assert.Equal(t, HandoffTool, tools[0].Name)
+		assert.Contains(t, tools[0].Description, "- research: Finds facts")
+		assert.Contains(t, string(tools[0].Parameters), `"enum":["research","writer"]`)
+	})
+}
+
+func TestTeam_RunStream_TagsEvents(t *testing.T) {
+	supervisor := &mockProvider{responses: []mockResponse{
+		{content: "Delegating", toolCalls: []ai.ToolCall{handoffCall("c1", "research", "Look it up")}},
+		{content: "Done"},
+	}}
+	researcher := &mockProvider{responses: []mockResponse{{content: "Found"}}}
+	team := NewTeam(supervisor).AddWorker("research", "Finds facts", New(researcher, tool.NewRegistry()))
+
+	starts := map[string]int{}
+	for ev := range team.RunStream(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Go"}}) {
+		require.NotEmpty(t, ev.Agent)
+		if ev.Type == event.RunStart {
+			starts[ev.Agent]++
+		}
+	}
+	assert.Equal(t, map[string]int{Supervisor: 1, "research": 1}, starts)
+}
+
+func TestTeam_WorkerTokenBudget(t *testing.T) {
+	supervisor := &mockProvider{responses: []mockResponse{
+		{content: "First", toolCalls: []ai.ToolCall{handoffCall("c1", "research", "One")}},
+		{content: "Second", toolCalls: []ai.ToolCall{handoffCall("c2", "research", "Two")}},
+		{content: "Done"},
+	}}
+	researcher := &mockProvider{responses: []mockResponse{{content: "Answer one"}, {content: "Answer two"}}}
+	team := NewTeam(supervisor).AddWorker("research", "Finds facts", New(researcher, tool.NewRegistry()),
+		WithWorkerTokenBudget(30))
+
+	result, err := team.Run(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Go"}})
+	require.NoError(t, err)
+
+	msgs := result.Messages()
+	require.Len(t, msgs, 5)
+	assert.Equal(t, "Answer one", msgs[2].ToolResults[0].Content)
+	second := msgs[4].ToolResults[0]
+	assert.True(t, second.IsError)
+	assert.Contains(t, second.Content, "exhausted its budget")
+	assert.Equal(t, 1, researcher.callCount)
+}
+
+func TestTeam_UnknownWorker(t *testing.T) {
+	supervisor := &mockProvider{responses: []mockResponse{
+		{content: "Delegating", toolCalls: []ai.ToolCall{handoffCall("c1", "legal", "Review")}},
+		{content: "Done"},
+	}}
+	team := NewTeam(supervisor).AddWorker("research", "Finds facts", New(&mockProvider{}, tool.NewRegistry()))
+
+	result, err := team.Run(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Go"}})
+	require.NoError(t, err)
+
+	msgs := result.Messages()
+	require.Len(t, msgs, 3)
+	assert.True(t, msgs[2].ToolResults[0].IsError)
+	assert.Contains(t, msgs[2].ToolResults[0].Content, `unknown team member "legal"`)
+}
@@ -11,10 +11,11 @@
 //   - [Mapper]: Stateful event converter that handles AG-UI's Start-Content-End pattern
 //   - Message conversion utilities: [ToGainsMessages], [FromGainsMessages]
 //   - State management: [DecodeState], [MustDecodeState] for typed frontend state access
+//   - Workflow discovery: [WorkflowSchemas] for the input schema of each registered workflow
+//   - HTTP serving: [Handler] runs an agent on each RunAgentInput and streams its events over SSE
 //
-// The package does NOT provide HTTP handlers or transport implementations. Users
-// are responsible for implementing their own server using the AG-UI SDK's SSE
-// writer or their preferred transport mechanism.
+// Use [Handler] for a ready-made SSE endpoint, or the Mapper with your own
+// transport for full control.
 //
 // # Usage
 //
@@ -37,6 +38,64 @@
 //	// Emit run finished
 //	writeEvent(mapper.RunFinished())
 //
+// # Handler
+//
+// [Handler] serves an agent in a few lines:
+//
+//	a := agent.New(c, registry)
+//	http.Handle("/agent", agui.NewHandler(a, registry,
+//	    agui.WithAgentOptions(agent.WithMaxSteps(10)),
+//	))
+//
+// For each POST it decodes the RunAgentInput, generates missing thread and
+// run IDs, registers the frontend tools as client tools for the run, shares
+// the input state with state tools, and streams the mapped events as SSE.
+// A thread runs one at a time; a second run on a busy thread gets 409
+// Conflict. Use [WithThreadLocks] to share the locks across servers.
+//
+// [WithForwardedProps] maps the frontend's forwarded props to agent options:
+//
+//	agui.WithForwardedProps(func(props any) ([]agent.Option, error) {
+//	    p, _ := props.(map[string]any)
+//	    if steps, ok := p["maxSteps"].(float64); ok {
+//	        return []agent.Option{agent.WithMaxSteps(int(steps))}, nil
+//	    }
+//	    return nil, nil
+//	})
+//
+// [Handler.Cancel] stops an in-flight run by ID; its stream ends with a
+// final STATE_SNAPSHOT and a RUN_ERROR reporting [ErrRunCanceled].
+//
+// [Handler.ServeWebSocket] serves the same runs over WebSocket, for
+// deployments behind proxies that buffer SSE. The client sends each
+// RunAgentInput as a text message and receives the run's events as JSON
+// text messages; runs on a connection are served in turn, and idle
+// connections are kept alive with pings:
+//
+//	h := agui.NewHandler(a, registry, agui.WithPingInterval(20*time.Second))
+//	http.Handle("/agent", h)
+//	http.HandleFunc("/agent/ws", h.ServeWebSocket)
+//
+// [WebSocketConn] is the underlying transport, for handlers of their own.
+//
+// # Frontend Tools
+//
+// Tools declared by the frontend in RunAgentInput.Tools run in the browser.
+// [FrontendTools] offers them to one run as client tools, so a run calling
+// one ends with the call pending and the frontend executes it:
+//
+//	events := a.RunStream(ctx, messages, agui.FrontendTools(prepared.Tools))
+//
+// The frontend then starts a new run on the same thread with the result as
+// a tool message. [PendingToolCalls] correlates the two runs: Track records
+// the calls a run leaves pending, and Resume restores the assistant message
+// that made them if the frontend sent back only the results:
+//
+//	messages, err := pending.Resume(threadID, prepared.Messages)
+//	events := pending.Track(threadID, a.RunStream(ctx, messages))
+//
+// [Handler] does both for every run.
+//
 // # Event Mapping
 //
 // The Mapper tracks state to properly emit AG-UI's Start-Content-End sequences:
@@ -55,7 +114,16 @@
 //
 // Use [FromGainsMessages] to convert gains messages to AG-UI format for snapshots:
 //
-//	snapshot := events.NewMessagesSnapshotEvent(agui.FromGainsMessages(history))
+//	snapshot := mapper.MessagesSnapshot(history)
+//
+// [Message] accepts user content as either a string or an array of
+// [InputContent]. [RunAgentInput] decodes content arrays into its
+// MultimodalMessages, and [ToGainsMultimodalMessages] and
+// [FromGainsMultimodalMessages] convert them, so multimodal frontends can
+// send images alongside text. [Message] also carries each message's
+// Metadata and CreatedAt, which AG-UI has no fields for, as extra
+// "metadata" and "createdAt" keys, so message snapshots keep them. The
+// SDK-typed functions above keep only text.
 //
 // # Shared State
 //
@@ -95,6 +163,44 @@
 //	    event.Replace("/progress", 100),
 //	)
 //
+// [StateSync] computes the deltas for typed state. It decodes the frontend
+// state into T, and Sync adds a STATE_DELTA with the changed fields after
+// each step:
+//
+//	state, err := agui.NewStateSync[MyState](prepared)
+//	state.Update(func(s *MyState) { s.Progress = 75 })
+//	events := state.Sync(a.RunStream(ctx, messages))
+//
+// For state kept in other forms, event.Diff computes the patches between two
+// values, and event.ApplyPatches applies them, as a frontend would:
+//
+//	patches := event.Diff(before, after)
+//	frontendState, err := event.ApplyPatches(before, patches)
+//
+// # State Tags
+//
+// The agui struct tag controls how state fields reach the frontend. Fields
+// tagged "-" or hidden are kept out of STATE_SNAPSHOT events, StateSync
+// deltas, and workflow input schemas; a label and the progress option are
+// rendering hints added to the schema:
+//
+//	type MyState struct {
+//	    Progress float64 `json:"progress" agui:"Completion,progress"`
+//	    APIKey   string  `json:"apiKey" agui:"-"`
+//	}
+//
+// Patches passed to StateDelta or event.NewStateDelta by hand are sent as
+// they are.
+//
+// # Workflow Input Schemas
+//
+// [WorkflowSchemas] lists the workflows of a workflow.Registry with the JSON
+// schema of their input state, generated by reflection from the state type's
+// struct tags. Serve it alongside the workflow endpoint so frontends can
+// render a form for each workflow and send the result as RunWorkflowInput.State:
+//
+//	json.NewEncoder(w).Encode(agui.WorkflowSchemas(registry))
+//
 // # Thread Safety
 //
 // The Mapper is NOT safe for concurrent use. Each goroutine should have its own
@@ -0,0 +1,290 @@
+package agui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+	"github.com/gorilla/websocket"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/agent"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/session"
+	"github.com/spetersoncode/gains/sse"
+	"github.com/spetersoncode/gains/tool"
+)
+
+// ErrRunCanceled is the error reported in the RUN_ERROR event of a run
+// stopped with Handler.Cancel.
+var ErrRunCanceled = errors.New("agui: run canceled")
+
+// ForwardedPropsFunc turns the forwarded props of a request into agent
+// options for its run. An error rejects the request with 400 Bad Request.
+type ForwardedPropsFunc func(props any) ([]agent.Option, error)
+
+// HandlerOption configures a Handler.
+type HandlerOption func(*Handler)
+
+// WithAgentOptions sets agent options applied to every run, before those
+// returned by the forwarded props function.
+func WithAgentOptions(opts ...agent.Option) HandlerOption {
+	return func(h *Handler) {
+		h.agentOpts = append(h.agentOpts, opts...)
+	}
+}
+
+// WithForwardedProps sets the function that maps RunAgentInput.ForwardedProps
+// to agent options, such as a model or step limit chosen by the frontend.
+// Without it, forwarded props are ignored.
+func WithForwardedProps(fn ForwardedPropsFunc) HandlerOption {
+	return func(h *Handler) {
+		h.forwardedProps = fn
+	}
+}
+
+// WithThreadLocks makes runs hold their thread's lock in threads, so a
+// thread runs one at a time across every server sharing the manager's
+// adapter. A run on a busy thread is rejected with 409 Conflict. Without
+// it, runs on the same thread are only serialized within the Handler.
+func WithThreadLocks(threads *session.Manager) HandlerOption {
+	return func(h *Handler) {
+		h.threads = threads
+	}
+}
+
+// Handler serves AG-UI runs of an agent over SSE. Each POST body is a
+// RunAgentInput; the response streams the run's AG-UI events. ServeWebSocket
+// serves the same runs over a WebSocket connection.
+//
+// Missing thread and run IDs are generated with ai.NewID. Frontend tools are
+// offered to the run alone with FrontendTools, and calls
+// to them left pending are correlated with the results of the thread's next
+// run by a PendingToolCalls. The input state is shared with state tools
+// through event.WithSharedState.
+// It is safe for concurrent use.
+type Handler struct {
+	agent          *agent.Agent
+	registry       *tool.Registry
+	agentOpts      []agent.Option
+	forwardedProps ForwardedPropsFunc
+	threads        *session.Manager
+	pending        *PendingToolCalls
+	upgrader       *websocket.Upgrader
+	pingInterval   time.Duration
+
+	mu   sync.Mutex
+	runs map[string]context.CancelCauseFunc // by run ID
+}
+
+// NewHandler creates a Handler running a, whose tools are in registry.
+func NewHandler(a *agent.Agent, registry *tool.Registry, opts ...HandlerOption) *Handler {
+	h := &Handler{
+		agent:    a,
+		registry: registry,
+		threads:  session.NewManager(nil),
+		pending:  NewPendingToolCalls(),
+		runs:     make(map[string]context.CancelCauseFunc),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Cancel stops the in-flight run with the given ID. Its stream ends with a
+// final STATE_SNAPSHOT and a RUN_ERROR reporting ErrRunCanceled. It returns
+// false if no such run is in flight.
+func (h *Handler) Cancel(runID string) bool {
+	h.mu.Lock()
+	cancel, ok := h.runs[runID]
+	h.mu.Unlock()
+	if ok {
+		cancel(ErrRunCanceled)
+	}
+	return ok
+}
+
+// ServeHTTP runs the agent on the RunAgentInput in the request body and
+// streams the mapped events as SSE.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var input RunAgentInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := w.(http.Flusher); !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	run, reqErr := h.startRun(r.Context(), input)
+	if reqErr != nil {
+		http.Error(w, reqErr.Error(), reqErr.status)
+		return
+	}
+	defer run.release()
+
+	sw, err := sse.NewWriter(r.Context(), w)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer sw.Close()
+
+	h.streamRun(run, func(ev events.Event) error {
+		return writeSSE(sw, ev)
+	})
+}
+
+// requestError rejects a run before it starts, with the HTTP status to
+// report it with.
+type requestError struct {
+	status int
+	msg    string
+}
+
+func (e *requestError) Error() string { return e.msg }
+
+// handlerRun is a run claimed by startRun.
+type handlerRun struct {
+	ctx      context.Context
+	cancel   context.CancelCauseFunc
+	prepared *PreparedInput
+	messages []ai.Message
+	opts     []agent.Option
+	release  func()
+}
+
+// startRun validates input and claims its thread and run ID. The returned
+// run must be released when it is done.
+func (h *Handler) startRun(ctx context.Context, input RunAgentInput) (*handlerRun, *requestError) {
+	if input.ThreadID == "" {
+		input.ThreadID = ai.NewID()
+	}
+	if input.RunID == "" {
+		input.RunID = ai.NewID()
+	}
+
+	prepared, err := input.Prepare()
+	if err != nil {
+		return nil, &requestError{http.StatusBadRequest, err.Error()}
+	}
+
+	// Offer the frontend tools to this run only
+	opts := append([]agent.Option{FrontendTools(prepared.Tools)}, h.agentOpts...)
+	if h.forwardedProps != nil && prepared.ForwardedProps != nil {
+		propOpts, err := h.forwardedProps(prepared.ForwardedProps)
+		if err != nil {
+			return nil, &requestError{http.StatusBadRequest, "Invalid forwarded props: " + err.Error()}
+		}
+		opts = append(opts, propOpts...)
+	}
+
+	// Reject a second run on a thread that is still running
+	unlock, err := h.threads.TryLock(ctx, prepared.ThreadID)
+	if errors.Is(err, session.ErrBusy) {
+		return nil, &requestError{http.StatusConflict, "thread already has a run in progress: " + prepared.ThreadID}
+	}
+	if err != nil {
+		return nil, &requestError{http.StatusInternalServerError, err.Error()}
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	untrack, ok := h.track(prepared.RunID, cancel)
+	if !ok {
+		cancel(nil)
+		unlock()
+		return nil, &requestError{http.StatusConflict, "run already in progress: " + prepared.RunID}
+	}
+	release := func() {
+		untrack()
+		cancel(nil)
+		unlock()
+	}
+
+	// Restore the calls answered by frontend tool results
+	messages, err := h.pending.Resume(prepared.ThreadID, prepared.Messages)
+	if err != nil {
+		release()
+		return nil, &requestError{http.StatusBadRequest, err.Error()}
+	}
+
+	return &handlerRun{
+		ctx:      ctx,
+		cancel:   cancel,
+		prepared: prepared,
+		messages: messages,
+		opts:     opts,
+		release:  release,
+	}, nil
+}
+
+// streamRun runs the agent and writes the mapped events with write. A write
+// error cancels the run.
+func (h *Handler) streamRun(run *handlerRun, write func(events.Event) error) {
+	prepared := run.prepared
+
+	mapper := NewMapper(prepared.ThreadID, prepared.RunID, WithInitialState(prepared.State))
+	sharedState := event.NewSharedState(prepared.State)
+	ctx := event.WithSharedState(run.ctx, sharedState)
+
+	canceled := func() bool { return errors.Is(context.Cause(ctx), ErrRunCanceled) }
+	for ev := range mapper.MapStream(h.pending.Track(prepared.ThreadID, h.agent.RunStream(ctx, run.messages, run.opts...))) {
+		// A canceled run ends with the cancellation events below instead
+		if canceled() && isTerminal(ev) {
+			continue
+		}
+		if err := write(ev); err != nil {
+			run.cancel(err)
+			continue // drain the stream so the run can finish
+		}
+	}
+
+	if canceled() {
+		write(mapper.StateSnapshot(sharedState.Get()))
+		write(mapper.RunError(ErrRunCanceled))
+	}
+}
+
+// track registers an in-flight run. It returns false if a run with the same
+// ID is already in flight.
+func (h *Handler) track(runID string, cancel context.CancelCauseFunc) (release func(), ok bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, exists := h.runs[runID]; exists {
+		return nil, false
+	}
+	h.runs[runID] = cancel
+	return func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.runs, runID)
+	}, true
+}
+
+// isTerminal reports whether ev ends an AG-UI run.
+func isTerminal(ev events.Event) bool {
+	return ev.Type() == events.EventTypeRunFinished || ev.Type() == events.EventTypeRunError
+}
+
+// writeSSE writes an AG-UI event to sw, with the event type as the SSE
+// event name.
+func writeSSE(sw *sse.Writer, ev events.Event) error {
+	data, err := ev.ToJSON()
+	if err != nil {
+		return fmt.Errorf("agui: serialize event: %w", err)
+	}
+	return sw.WriteEvent(string(ev.Type()), data)
+}
@@ -0,0 +1,296 @@
+package agui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/agent"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/tool"
+)
+
+// handlerChat replies "Hi" and records the options of each request.
+type handlerChat struct {
+	mu      sync.Mutex
+	options []*ai.Options
+	block   chan struct{} // if set, requests wait for it or cancellation
+}
+
+func (c *handlerChat) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	c.mu.Lock()
+	c.options = append(c.options, ai.ApplyOptions(opts...))
+	c.mu.Unlock()
+	if c.block != nil {
+		select {
+		case <-c.block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return &ai.Response{Content: "Hi"}, nil
+}
+
+func (c *handlerChat) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+	resp, err := c.Chat(ctx, messages, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan event.Event, 3)
+	event.Emit(ch, event.Event{Type: event.MessageStart, MessageID: "m1"})
+	event.Emit(ch, event.Event{Type: event.MessageDelta, MessageID: "m1", Delta: resp.Content})
+	event.Emit(ch, event.Event{Type: event.MessageEnd, MessageID: "m1", Response: resp})
+	close(ch)
+	return ch, nil
+}
+
+// sseEvents parses the data lines of an SSE response body.
+func sseEvents(t *testing.T, body string) []map[string]any {
+	t.Helper()
+	var evs []map[string]any
+	for _, line := range strings.Split(body, "\n") {
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		var ev map[string]any
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			t.Fatalf("invalid event %q: %v", data, err)
+		}
+		evs = append(evs, ev)
+	}
+	return evs
+}
+
+func eventTypes(evs []map[string]any) []string {
+	types := make([]string, len(evs))
+	for i, ev := range evs {
+		types[i], _ = ev["type"].(string)
+	}
+	return types
+}
+
+func TestHandler(t *testing.T) {
+	chat := &handlerChat{}
+	registry := tool.NewRegistry()
+	tool.MustRegisterFunc(registry, "search", "Search", func(ctx context.Context, args struct{}) (string, error) {
+		return "", nil
+	})
+
+	var props any
+	h := NewHandler(agent.New(chat, registry), registry,
+		WithAgentOptions(agent.WithMaxTokens(100)),
+		WithForwardedProps(func(p any) ([]agent.Option, error) {
+			props = p
+			return []agent.Option{agent.WithTemperature(0.5)}, nil
+		}),
+	)
+
+	body := `{
+		"messages": [{"id": "1", "role": "user", "content": "Hello"}],
+		"tools": [
+			{"name": "confirm", "description": "Ask the user", "parameters": {"type": "object"}},
+			{"name": "search", "description": "Frontend search", "parameters": {"type": "object"}}
+		],
+		"state": {"step": 1},
+		"forwarded_props": {"temperature": 0.5}
+	}`
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected text/event-stream, got %q", ct)
+	}
+
+	evs := sseEvents(t, w.Body.String())
+	types := eventTypes(evs)
+	if len(evs) == 0 || types[0] != "RUN_STARTED" || types[len(types)-1] != "RUN_FINISHED" {
+		t.Fatalf("unexpected events: %v", types)
+	}
+	if evs[0]["threadId"] == "" || evs[0]["threadId"] == nil || evs[0]["runId"] == nil {
+		t.Errorf("expected generated thread and run IDs, got %v", evs[0])
+	}
+	if types[1] != "STATE_SNAPSHOT" {
+		t.Errorf("expected initial state snapshot, got %v", types)
+	}
+	if !strings.Contains(w.Body.String(), `"delta":"Hi"`) {
+		t.Errorf("expected message content in %s", w.Body)
+	}
+
+	// Agent options and forwarded props apply to the run
+	opts := chat.options[0]
+	if opts.MaxTokens != 100 || opts.Temperature == nil || *opts.Temperature != 0.5 {
+		t.Errorf("expected max tokens and temperature options, got %+v", opts)
+	}
+	if m, ok := props.(map[string]any); !ok || m["temperature"] != 0.5 {
+		t.Errorf("expected forwarded props, got %v", props)
+	}
+
+	// Frontend tools are offered for the run only, and do not replace
+	// backend tools
+	var names []string
+	for _, t := range opts.Tools {
+		names = append(names, t.Name)
+	}
+	if strings.Join(names, ",") != "confirm,search" && strings.Join(names, ",") != "search,confirm" {
+		t.Errorf("expected confirm and search tools, got %v", names)
+	}
+	if registry.IsClientTool("search") {
+		t.Error("backend search tool replaced by frontend tool")
+	}
+	if _, ok := registry.GetTool("confirm"); ok {
+		t.Error("frontend tool still registered after the run")
+	}
+}
+
+func TestHandler_ConcurrentFrontendTools(t *testing.T) {
+	chat := &handlerChat{block: make(chan struct{})}
+	registry := tool.NewRegistry()
+	h := NewHandler(agent.New(chat, registry), registry)
+
+	run := func(threadID string, tools ...string) {
+		var decls []string
+		for _, name := range tools {
+			decls = append(decls, `{"name": "`+name+`", "description": "d", "parameters": {"type": "object"}}`)
+		}
+		body := `{"threadId": "` + threadID + `", "messages": [{"id": "1", "role": "user", "content": "Hello"}],
+			"tools": [` + strings.Join(decls, ",") + `]}`
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	}
+	waitRequests := func(n int) {
+		deadline := time.Now().Add(time.Second)
+		for {
+			chat.mu.Lock()
+			got := len(chat.options)
+			chat.mu.Unlock()
+			if got >= n {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d requests, got %d", n, got)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// Two threads run at once, declaring a tool of the same name
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); run("a", "confirm") }()
+	waitRequests(1)
+	go func() { defer wg.Done(); run("b", "confirm", "pick") }()
+	waitRequests(2)
+	if registry.Len() != 0 {
+		t.Errorf("frontend tools registered in the shared registry: %v", registry.Names())
+	}
+	close(chat.block)
+	wg.Wait()
+
+	toolNames := func(opts *ai.Options) string {
+		var names []string
+		for _, t := range opts.Tools {
+			names = append(names, t.Name)
+		}
+		sort.Strings(names)
+		return strings.Join(names, ",")
+	}
+	if got := toolNames(chat.options[0]); got != "confirm" {
+		t.Errorf("thread a: expected only its own tools, got %s", got)
+	}
+	if got := toolNames(chat.options[1]); got != "confirm,pick" {
+		t.Errorf("thread b: expected confirm and pick, got %s", got)
+	}
+}
+
+func TestHandler_Errors(t *testing.T) {
+	registry := tool.NewRegistry()
+	h := NewHandler(agent.New(&handlerChat{}, registry), registry,
+		WithForwardedProps(func(p any) ([]agent.Option, error) {
+			return nil, errors.New("unknown model")
+		}),
+	)
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		code   int
+	}{
+		{"method", http.MethodGet, "", http.StatusMethodNotAllowed},
+		{"invalid JSON", http.MethodPost, "{", http.StatusBadRequest},
+		{"no messages", http.MethodPost, `{"messages": []}`, http.StatusBadRequest},
+		{"forwarded props", http.MethodPost, `{"messages": [{"id": "1", "role": "user", "content": "Hi"}], "forwarded_props": {"model": "x"}}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(tt.method, "/", strings.NewReader(tt.body)))
+			if w.Code != tt.code {
+				t.Errorf("expected %d, got %d: %s", tt.code, w.Code, w.Body)
+			}
+		})
+	}
+}
+
+func TestHandler_Cancel(t *testing.T) {
+	chat := &handlerChat{block: make(chan struct{})}
+	registry := tool.NewRegistry()
+	h := NewHandler(agent.New(chat, registry), registry)
+
+	body := `{"thread_id": "t1", "run_id": "r1", "messages": [{"id": "1", "role": "user", "content": "Hi"}], "state": {"step": 1}}`
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	}()
+
+	// Wait for the run to reach the model
+	for {
+		chat.mu.Lock()
+		n := len(chat.options)
+		chat.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// The thread is busy while the run is in flight
+	busy := httptest.NewRecorder()
+	second := strings.Replace(body, `"r1"`, `"r2"`, 1)
+	h.ServeHTTP(busy, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(second)))
+	if busy.Code != http.StatusConflict {
+		t.Errorf("expected 409 for busy thread, got %d", busy.Code)
+	}
+
+	if h.Cancel("missing") {
+		t.Error("expected Cancel of unknown run to fail")
+	}
+	if !h.Cancel("r1") {
+		t.Fatal("expected Cancel to find the run")
+	}
+	<-done
+
+	types := eventTypes(sseEvents(t, w.Body.String()))
+	if n := len(types); n < 2 || types[n-2] != "STATE_SNAPSHOT" || types[n-1] != "RUN_ERROR" {
+		t.Fatalf("expected state snapshot and run error, got %v", types)
+	}
+	if strings.Count(w.Body.String(), "RUN_ERROR") != 2 { // event name and type
+		t.Errorf("expected a single RUN_ERROR, got %s", w.Body)
+	}
+	if !strings.Contains(w.Body.String(), ErrRunCanceled.Error()) {
+		t.Errorf("expected cancellation message, got %s", w.Body)
+	}
+}
@@ -3,6 +3,7 @@ package agui
 import (
 	"encoding/json"
 	"errors"
+	"slices"
 
 	ai "github.com/spetersoncode/gains"
 
@@ -19,6 +20,48 @@ type RunAgentInput struct {
 	Context        []any            `json:"context,omitempty"`         // Context items
 	State          any              `json:"state,omitempty"`           // State
 	ForwardedProps any              `json:"forwarded_props,omitempty"` // Forwarded props
+
+	// MultimodalMessages holds the messages with their multimodal content,
+	// set when decoding a request whose messages include content arrays.
+	// Messages then holds the same messages with only their text. When
+	// set, it replaces Messages in Prepare and when encoding.
+	MultimodalMessages []Message `json:"-"`
+}
+
+// UnmarshalJSON decodes the input, accepting message content as either a
+// string or an array of InputContent.
+func (r *RunAgentInput) UnmarshalJSON(data []byte) error {
+	type plain RunAgentInput
+	var wire struct {
+		plain
+		Messages []Message `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	*r = RunAgentInput(wire.plain)
+	r.Messages = make([]events.Message, len(wire.Messages))
+	for i, msg := range wire.Messages {
+		r.Messages[i] = msg.Message
+	}
+	if slices.ContainsFunc(wire.Messages, func(msg Message) bool { return len(msg.Parts) > 0 }) {
+		r.MultimodalMessages = wire.Messages
+	}
+	return nil
+}
+
+// MarshalJSON encodes the input, writing MultimodalMessages in place of
+// Messages when set.
+func (r RunAgentInput) MarshalJSON() ([]byte, error) {
+	type plain RunAgentInput
+	if len(r.MultimodalMessages) == 0 {
+		return json.Marshal(plain(r))
+	}
+	return json.Marshal(struct {
+		plain
+		Messages []Message `json:"messages"`
+	}{plain(r), r.MultimodalMessages})
 }
 
 // PreparedInput contains validated and converted input ready for agent execution.
@@ -29,26 +72,35 @@ type PreparedInput struct {
 	Tools     []Tool   // Parsed frontend tools
 	ToolNames []string // Tool names for cleanup tracking
 	State     any      // Raw state from frontend
+
+	// ForwardedProps are the frontend's forwarded props, passed through
+	// unchanged.
+	ForwardedProps any
 }
 
 // ErrNoMessages is returned when the input contains no messages.
 var ErrNoMessages = errors.New("no messages provided")
 
-// Prepare validates the input and converts it to gains types.
-// Returns ErrNoMessages if Messages is empty.
+// Prepare validates the input and converts it to gains types, converting
+// MultimodalMessages in place of Messages when set.
+// Returns ErrNoMessages if there are no messages.
 // Returns an error if tool parsing fails.
 func (r *RunAgentInput) Prepare() (*PreparedInput, error) {
 	// Convert messages
 	messages := ToGainsMessages(r.Messages)
+	if len(r.MultimodalMessages) > 0 {
+		messages = ToGainsMultimodalMessages(r.MultimodalMessages)
+	}
 	if len(messages) == 0 {
 		return nil, ErrNoMessages
 	}
 
 	result := &PreparedInput{
-		ThreadID: r.ThreadID,
-		RunID:    r.RunID,
-		Messages: messages,
-		State:    r.State,
+		ThreadID:       r.ThreadID,
+		RunID:          r.RunID,
+		Messages:       messages,
+		State:          r.State,
+		ForwardedProps: r.ForwardedProps,
 	}
 
 	// Parse frontend tools if provided
@@ -154,8 +206,8 @@ func MustMergeState[T any](state *T, input *PreparedInput) {
 type RunWorkflowInput struct {
 	ThreadID       string `json:"thread_id"`
 	RunID          string `json:"run_id"`
-	WorkflowName   string `json:"workflow_name"`           // Name of workflow to execute
-	State          any    `json:"state,omitempty"`         // Initial workflow state
+	WorkflowName   string `json:"workflow_name"`   // Name of workflow to execute
+	State          any    `json:"state,omitempty"` // Initial workflow state
 	ForwardedProps any    `json:"forwarded_props,omitempty"`
 }
 
@@ -3,10 +3,167 @@ package agui
 import (
 	"encoding/json"
 	"testing"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
 
 	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
 )
 
+func TestMessage_MultimodalJSON(t *testing.T) {
+	t.Run("decodes content array", func(t *testing.T) {
+		data := `{
+			"id": "msg-1",
+			"role": "user",
+			"content": [
+				{"type": "text", "text": "Describe "},
+				{"type": "text", "text": "this image"},
+				{"type": "binary", "mimeType": "image/png", "data": "aGVsbG8="},
+				{"type": "binary", "mimeType": "image/jpeg", "url": "https://example.com/a.jpg"},
+				{"type": "binary", "mimeType": "application/zip", "id": "file-1"}
+			]
+		}`
+
+		var msg Message
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(msg.Parts) != 5 {
+			t.Fatalf("len(Parts) = %d, want 5", len(msg.Parts))
+		}
+		if msg.Content == nil || *msg.Content != "Describe this image" {
+			t.Errorf("Content = %v, want joined text", msg.Content)
+		}
+
+		gainsMsg := ToGainsMultimodalMessage(msg)
+		if len(gainsMsg.Parts) != 4 {
+			t.Fatalf("len(gains Parts) = %d, want 4 (ID-only file skipped)", len(gainsMsg.Parts))
+		}
+		if gainsMsg.Parts[2].Base64 != "aGVsbG8=" || gainsMsg.Parts[2].MimeType != "image/png" {
+			t.Errorf("Parts[2] = %+v, want base64 png", gainsMsg.Parts[2])
+		}
+		if gainsMsg.Parts[3].ImageURL != "https://example.com/a.jpg" {
+			t.Errorf("Parts[3].ImageURL = %q", gainsMsg.Parts[3].ImageURL)
+		}
+	})
+
+	t.Run("decodes string content", func(t *testing.T) {
+		var msg Message
+		if err := json.Unmarshal([]byte(`{"id":"msg-1","role":"user","content":"Hello"}`), &msg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if msg.Content == nil || *msg.Content != "Hello" {
+			t.Errorf("Content = %v, want Hello", msg.Content)
+		}
+		if len(msg.Parts) != 0 {
+			t.Errorf("len(Parts) = %d, want 0", len(msg.Parts))
+		}
+	})
+
+	t.Run("encodes user parts as content array", func(t *testing.T) {
+		msg := FromGainsMultimodalMessage(ai.Message{
+			Role: ai.RoleUser,
+			Parts: []ai.ContentPart{
+				ai.NewTextPart("Look"),
+				ai.NewImageURLPart("https://example.com/a.jpg"),
+			},
+		}, 0)
+
+		data, err := json.Marshal(msg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var decoded struct {
+			Content []InputContent `json:"content"`
+		}
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("content is not an array: %v", err)
+		}
+		if len(decoded.Content) != 2 || decoded.Content[1].URL != "https://example.com/a.jpg" {
+			t.Errorf("Content = %+v, want text and image parts", decoded.Content)
+		}
+	})
+
+	t.Run("round trips document parts", func(t *testing.T) {
+		msg := FromGainsMultimodalMessage(ai.Message{
+			Role: ai.RoleUser,
+			Parts: []ai.ContentPart{
+				ai.NewDocumentBase64Part("JVBERi0=", "application/pdf", "report.pdf"),
+				ai.NewTextDocumentPart("hello", "notes.txt"),
+			},
+		}, 0)
+		if len(msg.Parts) != 2 {
+			t.Fatalf("len(Parts) = %d, want 2", len(msg.Parts))
+		}
+		if msg.Parts[0].Filename != "report.pdf" || msg.Parts[0].Data != "JVBERi0=" {
+			t.Errorf("Parts[0] = %+v, want pdf binary", msg.Parts[0])
+		}
+		if msg.Parts[1].Data != "aGVsbG8=" || msg.Parts[1].MimeType != "text/plain" {
+			t.Errorf("Parts[1] = %+v, want base64 text", msg.Parts[1])
+		}
+
+		gainsMsg := ToGainsMultimodalMessage(msg)
+		if len(gainsMsg.Parts) != 2 {
+			t.Fatalf("len(gains Parts) = %d, want 2", len(gainsMsg.Parts))
+		}
+		if gainsMsg.Parts[0].Type != ai.ContentPartTypeDocument || gainsMsg.Parts[0].Filename != "report.pdf" {
+			t.Errorf("Parts[0] = %+v, want pdf document", gainsMsg.Parts[0])
+		}
+		if text, ok := gainsMsg.Parts[1].DocumentText(); !ok || text != "hello" {
+			t.Errorf("DocumentText() = %q, %v, want hello", text, ok)
+		}
+	})
+}
+
+func TestMessage_MetadataRoundTrip(t *testing.T) {
+	created := time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC)
+	original := ai.Message{
+		ID:        "msg-1",
+		Role:      ai.RoleAssistant,
+		Content:   "Hello",
+		CreatedAt: created,
+		Metadata:  map[string]string{"userId": "u-42"},
+	}
+
+	data, err := json.Marshal(FromGainsMultimodalMessage(original, 0))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded Message
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	back := ToGainsMultimodalMessage(decoded)
+
+	if back.Content != "Hello" || back.ID != "msg-1" {
+		t.Errorf("message = %+v, want the original content and ID", back)
+	}
+	if !back.CreatedAt.Equal(created) {
+		t.Errorf("CreatedAt = %v, want %v", back.CreatedAt, created)
+	}
+	if back.Metadata["userId"] != "u-42" || len(back.Metadata) != 1 {
+		t.Errorf("Metadata = %v, want userId u-42", back.Metadata)
+	}
+
+	t.Run("omitted when unset", func(t *testing.T) {
+		data, err := json.Marshal(FromGainsMultimodalMessage(ai.Message{ID: "msg-2", Role: ai.RoleUser, Content: "Hi"}, 0))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var payload map[string]any
+		if err := json.Unmarshal(data, &payload); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := payload["metadata"]; ok {
+			t.Errorf("payload has metadata: %s", data)
+		}
+		if _, ok := payload["createdAt"]; ok {
+			t.Errorf("payload has createdAt: %s", data)
+		}
+	})
+}
+
 func TestRunAgentInput_Prepare(t *testing.T) {
 	t.Run("valid input with messages", func(t *testing.T) {
 		content := "Hello"
@@ -453,6 +610,53 @@ func TestRunAgentInput_JSON(t *testing.T) {
 	}
 }
 
+func TestRunAgentInput_MultimodalJSON(t *testing.T) {
+	jsonData := `{
+		"thread_id": "thread-123",
+		"run_id": "run-456",
+		"messages": [
+			{"id": "msg-1", "role": "user", "content": [
+				{"type": "text", "text": "What is this?"},
+				{"type": "binary", "mimeType": "image/png", "data": "aGVsbG8="}
+			]}
+		]
+	}`
+
+	var input RunAgentInput
+	if err := json.Unmarshal([]byte(jsonData), &input); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if input.ThreadID != "thread-123" {
+		t.Errorf("ThreadID = %q, want %q", input.ThreadID, "thread-123")
+	}
+	if len(input.Messages) != 1 || input.Messages[0].Content == nil || *input.Messages[0].Content != "What is this?" {
+		t.Fatalf("Messages = %+v, want the text of the message", input.Messages)
+	}
+	if len(input.MultimodalMessages) != 1 || len(input.MultimodalMessages[0].Parts) != 2 {
+		t.Fatalf("MultimodalMessages = %+v, want the message with its parts", input.MultimodalMessages)
+	}
+
+	prepared, err := input.Prepare()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(prepared.Messages[0].Parts) != 2 {
+		t.Errorf("len(Parts) = %d, want 2", len(prepared.Messages[0].Parts))
+	}
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	var decoded RunAgentInput
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if decoded.RunID != "run-456" || len(decoded.MultimodalMessages) != 1 || len(decoded.MultimodalMessages[0].Parts) != 2 {
+		t.Errorf("round trip = %+v, want the same input", decoded)
+	}
+}
+
 func TestRunWorkflowInput_Prepare(t *testing.T) {
 	t.Run("valid input with workflow name", func(t *testing.T) {
 		input := RunWorkflowInput{
@@ -1,6 +1,8 @@
 package agui
 
 import (
+	"encoding/json"
+
 	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
 
 	ai "github.com/spetersoncode/gains"
@@ -17,6 +19,12 @@ const (
 	// CustomEventLoopIteration is emitted at the start of each loop iteration.
 	// Value contains: stepName (string), iteration (int)
 	CustomEventLoopIteration = "gains.loop_iteration"
+
+	// CustomEventProgress is emitted as a chain or loop advances, with
+	// workflow.WithProgress.
+	// Value contains: stepName (string), completed (int), total (int),
+	// unit (string), fraction (float64, 0 to 1)
+	CustomEventProgress = "gains.progress"
 )
 
 // Mapper converts gains events to AG-UI events.
@@ -104,9 +112,10 @@ func (m *Mapper) RunError(err error) events.Event {
 	return events.NewRunErrorEvent(msg)
 }
 
-// StateSnapshot returns a STATE_SNAPSHOT event with the given state.
+// StateSnapshot returns a STATE_SNAPSHOT event with the given state,
+// without its hidden fields (see VisibleState).
 func (m *Mapper) StateSnapshot(state any) events.Event {
-	return events.NewStateSnapshotEvent(state)
+	return events.NewStateSnapshotEvent(VisibleState(state))
 }
 
 // StateDelta returns a STATE_DELTA event with the given JSON Patch operations.
@@ -116,7 +125,7 @@ func (m *Mapper) StateDelta(patches ...event.JSONPatch) events.Event {
 
 // MessagesSnapshot returns a MESSAGES_SNAPSHOT event with the given messages.
 func (m *Mapper) MessagesSnapshot(messages []ai.Message) events.Event {
-	return events.NewMessagesSnapshotEvent(FromGainsMessages(messages))
+	return newMessagesSnapshotEvent(FromGainsMultimodalMessages(messages))
 }
 
 // ActivitySnapshot returns an ACTIVITY_SNAPSHOT event.
@@ -247,14 +256,27 @@ func (m *Mapper) MapEvent(e event.Event) events.Event {
 				"stepName":  e.StepName,
 				"iteration": e.Iteration,
 			}))
+	case event.Progress:
+		// Map to AG-UI custom event for progress bars
+		if e.Progress == nil {
+			return nil
+		}
+		return events.NewCustomEvent(CustomEventProgress,
+			events.WithValue(map[string]any{
+				"stepName":  e.StepName,
+				"completed": e.Progress.Completed,
+				"total":     e.Progress.Total,
+				"unit":      e.Progress.Unit,
+				"fraction":  e.Progress.Fraction(),
+			}))
 
 	// State synchronization
 	case event.StateSnapshot:
-		return events.NewStateSnapshotEvent(e.State)
+		return m.StateSnapshot(e.State)
 	case event.StateDelta:
 		return events.NewStateDeltaEvent(toAGUIPatches(e.StatePatches))
 	case event.MessagesSnapshot:
-		return events.NewMessagesSnapshotEvent(FromGainsMessages(e.Messages))
+		return newMessagesSnapshotEvent(FromGainsMultimodalMessages(e.Messages))
 
 	// Activity events (human-in-the-loop)
 	case event.ActivitySnapshot:
@@ -267,6 +289,44 @@ func (m *Mapper) MapEvent(e event.Event) events.Event {
 	}
 }
 
+// messagesSnapshotEvent is a MESSAGES_SNAPSHOT event that serializes
+// multimodal message content, which the SDK event type cannot represent.
+type messagesSnapshotEvent struct {
+	*events.MessagesSnapshotEvent
+	messages []Message
+}
+
+// newMessagesSnapshotEvent creates a MESSAGES_SNAPSHOT event for the messages.
+func newMessagesSnapshotEvent(msgs []Message) events.Event {
+	base := make([]events.Message, len(msgs))
+	for i, msg := range msgs {
+		base[i] = msg.Message
+	}
+	return &messagesSnapshotEvent{
+		MessagesSnapshotEvent: events.NewMessagesSnapshotEvent(base),
+		messages:              msgs,
+	}
+}
+
+// MarshalJSON serializes the event with multimodal message content.
+func (e *messagesSnapshotEvent) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(e.MessagesSnapshotEvent)
+	if err != nil {
+		return nil, err
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	payload["messages"] = e.messages
+	return json.Marshal(payload)
+}
+
+// ToJSON serializes the event to JSON.
+func (e *messagesSnapshotEvent) ToJSON() ([]byte, error) {
+	return json.Marshal(e)
+}
+
 // toAGUIPatches converts gains JSONPatch operations to AG-UI JSONPatchOperation.
 func toAGUIPatches(patches []event.JSONPatch) []events.JSONPatchOperation {
 	if len(patches) == 0 {
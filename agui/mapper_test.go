@@ -469,6 +469,25 @@ func TestMapper_MapEvent_CustomWorkflowEvents(t *testing.T) {
 			t.Errorf("expected CUSTOM, got %s", result.Type())
 		}
 	})
+
+	t.Run("Progress maps to CUSTOM event", func(t *testing.T) {
+		result := m.MapEvent(event.Event{
+			Type:     event.Progress,
+			StepName: "chain",
+			Progress: &event.ProgressInfo{Completed: 1, Total: 4, Unit: "steps"},
+		})
+		custom, ok := result.(*events.CustomEvent)
+		if !ok {
+			t.Fatalf("expected CUSTOM, got %T", result)
+		}
+		if custom.Name != CustomEventProgress {
+			t.Errorf("expected %s, got %s", CustomEventProgress, custom.Name)
+		}
+		value := custom.Value.(map[string]any)
+		if value["fraction"] != 0.25 || value["total"] != 4 {
+			t.Errorf("unexpected value: %v", value)
+		}
+	})
 }
 
 func TestMapper_MapEvent_ApprovalEventsReturnNil(t *testing.T) {
@@ -690,6 +709,28 @@ func TestToGainsMessage(t *testing.T) {
 		}
 	})
 
+	t.Run("message with name", func(t *testing.T) {
+		content := "Hello"
+		name := "alice"
+		aguiMsg := events.Message{
+			ID:      "msg-1",
+			Role:    RoleUser,
+			Content: &content,
+			Name:    &name,
+		}
+
+		gainsMsg := ToGainsMessage(aguiMsg)
+
+		if gainsMsg.Name != "alice" {
+			t.Errorf("expected 'alice', got %q", gainsMsg.Name)
+		}
+
+		back := FromGainsMessage(gainsMsg, 0)
+		if back.Name == nil || *back.Name != "alice" {
+			t.Errorf("expected name 'alice', got %v", back.Name)
+		}
+	})
+
 	t.Run("assistant message with tool calls", func(t *testing.T) {
 		aguiMsg := events.Message{
 			ID:   "msg-1",
@@ -1,6 +1,12 @@
 package agui
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"maps"
+	"strings"
+	"time"
+
 	ai "github.com/spetersoncode/gains"
 
 	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
@@ -14,6 +20,138 @@ const (
 	RoleTool      = "tool"
 )
 
+// Input content types for multimodal AG-UI messages.
+const (
+	InputContentText   = "text"
+	InputContentBinary = "binary"
+)
+
+// InputContent is one part of a multimodal AG-UI message.
+// Binary content is provided inline as base64 Data, by URL, or by an ID
+// managed by the frontend.
+type InputContent struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+	ID       string `json:"id,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Data     string `json:"data,omitempty"`
+	Filename string `json:"filename,omitempty"`
+}
+
+// Message is an AG-UI message whose content may be multimodal.
+// The SDK message type only supports string content; Message adds Parts for
+// the array form used by frontends that send images and files, and carries
+// the gains message metadata and creation time, which the protocol has no
+// fields for, as extra "metadata" and "createdAt" keys.
+type Message struct {
+	events.Message
+
+	// Parts holds multimodal content. When non-empty it is serialized as the
+	// content array, and Content holds the concatenated text parts.
+	Parts []InputContent `json:"-"`
+
+	// Metadata holds the gains message's application-defined key/value
+	// pairs.
+	Metadata map[string]string `json:"-"`
+
+	// CreatedAt records when the message was created. Zero if unknown.
+	CreatedAt time.Time `json:"-"`
+}
+
+// MarshalJSON serializes the message, writing Parts as the content array
+// when present, and Metadata and CreatedAt when set.
+func (m Message) MarshalJSON() ([]byte, error) {
+	if len(m.Parts) == 0 && len(m.Metadata) == 0 && m.CreatedAt.IsZero() {
+		return m.Message.MarshalJSON()
+	}
+
+	base := m.Message
+	if len(m.Parts) > 0 {
+		base.Content = nil
+	}
+	data, err := base.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, err
+	}
+	if len(m.Parts) > 0 {
+		payload["content"] = m.Parts
+	}
+	if len(m.Metadata) > 0 {
+		payload["metadata"] = m.Metadata
+	}
+	if !m.CreatedAt.IsZero() {
+		payload["createdAt"] = m.CreatedAt
+	}
+	return json.Marshal(payload)
+}
+
+// UnmarshalJSON accepts content as either a string or an array of
+// InputContent, and decodes metadata and createdAt when present.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var extra struct {
+		Metadata  map[string]string `json:"metadata"`
+		CreatedAt time.Time         `json:"createdAt"`
+	}
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return err
+	}
+
+	var parts []InputContent
+	if content, ok := raw["content"]; ok && isJSONArray(content) {
+		if err := json.Unmarshal(content, &parts); err != nil {
+			return err
+		}
+		delete(raw, "content")
+		stripped, err := json.Marshal(raw)
+		if err != nil {
+			return err
+		}
+		data = stripped
+	}
+
+	if err := m.Message.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	m.Parts = parts
+	m.Metadata = extra.Metadata
+	m.CreatedAt = extra.CreatedAt
+
+	if len(parts) > 0 {
+		if text := joinTextContent(parts); text != "" {
+			m.Content = &text
+		}
+	}
+	return nil
+}
+
+// isJSONArray reports whether raw JSON encodes an array.
+func isJSONArray(raw json.RawMessage) bool {
+	trimmed := strings.TrimSpace(string(raw))
+	return strings.HasPrefix(trimmed, "[")
+}
+
+// joinTextContent concatenates the text parts of multimodal content.
+func joinTextContent(parts []InputContent) string {
+	var sb strings.Builder
+	for _, p := range parts {
+		if p.Type == InputContentText {
+			sb.WriteString(p.Text)
+		}
+	}
+	return sb.String()
+}
+
 // ToGainsMessages converts AG-UI messages to gains messages.
 func ToGainsMessages(msgs []events.Message) []ai.Message {
 	result := make([]ai.Message, 0, len(msgs))
@@ -25,9 +163,28 @@ func ToGainsMessages(msgs []events.Message) []ai.Message {
 
 // ToGainsMessage converts a single AG-UI message to a gains message.
 func ToGainsMessage(msg events.Message) ai.Message {
+	return ToGainsMultimodalMessage(Message{Message: msg})
+}
+
+// ToGainsMultimodalMessages converts AG-UI messages that may carry
+// multimodal content to gains messages.
+func ToGainsMultimodalMessages(msgs []Message) []ai.Message {
+	result := make([]ai.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		result = append(result, ToGainsMultimodalMessage(msg))
+	}
+	return result
+}
+
+// ToGainsMultimodalMessage converts a single AG-UI message to a gains
+// message. Text and image content parts are carried over as gains content
+// parts.
+func ToGainsMultimodalMessage(msg Message) ai.Message {
 	m := ai.Message{
-		ID:   msg.ID, // Preserve the AG-UI message ID
-		Role: toGainsRole(msg.Role),
+		ID:        msg.ID, // Preserve the AG-UI message ID
+		Role:      toGainsRole(msg.Role),
+		Metadata:  maps.Clone(msg.Metadata),
+		CreatedAt: msg.CreatedAt,
 	}
 
 	// Set content if present
@@ -35,6 +192,15 @@ func ToGainsMessage(msg events.Message) ai.Message {
 		m.Content = *msg.Content
 	}
 
+	if msg.Name != nil {
+		m.Name = *msg.Name
+	}
+
+	// Convert multimodal content
+	if len(msg.Parts) > 0 {
+		m.Parts = toGainsParts(msg.Parts)
+	}
+
 	// Convert tool calls (for assistant messages)
 	if len(msg.ToolCalls) > 0 {
 		m.ToolCalls = make([]ai.ToolCall, len(msg.ToolCalls))
@@ -68,15 +234,46 @@ func FromGainsMessages(msgs []ai.Message) []events.Message {
 }
 
 // FromGainsMessage converts a single gains message to an AG-UI message.
-// The index is used to generate a message ID if needed.
+// The index is used to generate a message ID if needed. The SDK message
+// has string content only and no metadata or creation time, so it keeps the
+// text of a multimodal message's parts and drops the rest; use
+// FromGainsMultimodalMessage to keep them.
 func FromGainsMessage(msg ai.Message, index int) events.Message {
+	m := FromGainsMultimodalMessage(msg, index)
+	if m.Content == nil && len(m.Parts) > 0 {
+		text := joinTextContent(m.Parts)
+		m.Content = &text
+	}
+	return m.Message
+}
+
+// FromGainsMultimodalMessages converts gains messages to AG-UI messages,
+// keeping the content parts of user messages and the metadata and creation
+// time of every message.
+func FromGainsMultimodalMessages(msgs []ai.Message) []Message {
+	result := make([]Message, 0, len(msgs))
+	for i, msg := range msgs {
+		result = append(result, FromGainsMultimodalMessage(msg, i))
+	}
+	return result
+}
+
+// FromGainsMultimodalMessage converts a single gains message to an AG-UI
+// message. The index is used to generate a message ID if needed.
+// Content parts are only carried for user messages, which are the only
+// messages AG-UI allows to have multimodal content.
+func FromGainsMultimodalMessage(msg ai.Message, index int) Message {
 	id := msg.ID
 	if id == "" {
 		id = events.GenerateMessageID()
 	}
-	m := events.Message{
-		ID:   id,
-		Role: fromGainsRole(msg.Role),
+	m := Message{
+		Message: events.Message{
+			ID:   id,
+			Role: fromGainsRole(msg.Role),
+		},
+		Metadata:  maps.Clone(msg.Metadata),
+		CreatedAt: msg.CreatedAt,
 	}
 
 	// Set content
@@ -84,6 +281,10 @@ func FromGainsMessage(msg ai.Message, index int) events.Message {
 		m.Content = &msg.Content
 	}
 
+	if msg.Name != "" {
+		m.Name = &msg.Name
+	}
+
 	// Convert tool calls (for assistant messages)
 	if len(msg.ToolCalls) > 0 {
 		m.ToolCalls = make([]events.ToolCall, len(msg.ToolCalls))
@@ -105,9 +306,80 @@ func FromGainsMessage(msg ai.Message, index int) events.Message {
 		m.Content = &msg.ToolResults[0].Content
 	}
 
+	if msg.Role == ai.RoleUser && msg.HasParts() {
+		m.Parts = fromGainsParts(msg.Parts)
+	}
+
 	return m
 }
 
+// toGainsParts converts AG-UI input content to gains content parts.
+// Binary image content becomes image parts and other binary content becomes
+// document parts. Content referenced only by a frontend ID cannot be resolved
+// and is skipped.
+func toGainsParts(parts []InputContent) []ai.ContentPart {
+	result := make([]ai.ContentPart, 0, len(parts))
+	for _, p := range parts {
+		switch p.Type {
+		case InputContentText:
+			result = append(result, ai.NewTextPart(p.Text))
+		case InputContentBinary:
+			if !strings.HasPrefix(p.MimeType, "image/") {
+				switch {
+				case p.Data != "":
+					result = append(result, ai.NewDocumentBase64Part(p.Data, p.MimeType, p.Filename))
+				case p.URL != "":
+					part := ai.NewDocumentURLPart(p.URL, p.MimeType)
+					part.Filename = p.Filename
+					result = append(result, part)
+				}
+				continue
+			}
+			switch {
+			case p.Data != "":
+				result = append(result, ai.NewImageBase64Part(p.Data, p.MimeType))
+			case p.URL != "":
+				part := ai.NewImageURLPart(p.URL)
+				part.MimeType = p.MimeType
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
+
+// fromGainsParts converts gains content parts to AG-UI input content.
+func fromGainsParts(parts []ai.ContentPart) []InputContent {
+	result := make([]InputContent, 0, len(parts))
+	for _, p := range parts {
+		switch p.Type {
+		case ai.ContentPartTypeText:
+			result = append(result, InputContent{Type: InputContentText, Text: p.Text})
+		case ai.ContentPartTypeImage:
+			result = append(result, InputContent{
+				Type:     InputContentBinary,
+				MimeType: p.MimeType,
+				URL:      p.ImageURL,
+				Data:     p.Base64,
+			})
+		case ai.ContentPartTypeDocument:
+			data := p.Base64
+			if data == "" && p.FileURL == "" {
+				// Plain text documents are sent inline as data.
+				data = base64.StdEncoding.EncodeToString([]byte(p.Text))
+			}
+			result = append(result, InputContent{
+				Type:     InputContentBinary,
+				MimeType: p.MimeType,
+				URL:      p.FileURL,
+				Data:     data,
+				Filename: p.Filename,
+			})
+		}
+	}
+	return result
+}
+
 // toGainsRole converts an AG-UI role string to a gains Role.
 func toGainsRole(role string) ai.Role {
 	switch role {
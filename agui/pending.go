@@ -0,0 +1,141 @@
+package agui
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/agent"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/tool"
+)
+
+// ErrUnknownToolCall is returned by PendingToolCalls.Resume for a tool
+// result that answers no tool call of the thread.
+var ErrUnknownToolCall = errors.New("agui: tool result for unknown tool call")
+
+// FrontendTools returns an agent option offering tools as client tools for
+// one run, so the agent ends the run with the calls pending instead of
+// executing them. They are added to a copy of the agent's registry, so runs
+// on other threads never see them. Tools named like a backend tool are
+// skipped, leaving the backend tool in place.
+func FrontendTools(tools []Tool) agent.Option {
+	return agent.WithClientTools(ToGainsTools(tools)...)
+}
+
+// RegisterFrontendTools registers tools as client tools in registry, so the
+// agent offers them to the model and ends its run with the calls pending
+// instead of executing them. Tools whose name is already registered are
+// skipped, leaving backend tools in place. It returns a function that
+// unregisters the tools it added, to be called when the run ends.
+//
+// The registry is changed for every run using it, so concurrent runs see
+// each other's frontend tools; use FrontendTools for servers handling
+// several threads at once.
+func RegisterFrontendTools(registry *tool.Registry, tools []Tool) (unregister func()) {
+	var added []string
+	for _, t := range ToGainsTools(tools) {
+		if registry.RegisterClientTool(t) == nil {
+			added = append(added, t.Name)
+		}
+	}
+	return func() {
+		for _, name := range added {
+			registry.Unregister(name)
+		}
+	}
+}
+
+// PendingToolCalls correlates frontend tool calls across runs. A run that
+// calls a frontend tool ends with the calls pending; the frontend executes
+// them and starts a new run on the same thread with the results as tool
+// messages. Track records the calls a run leaves pending, and Resume
+// prepares the next run's messages, restoring the assistant message that
+// made the calls if the frontend did not send it back.
+//
+// Pending calls are kept in memory. It is safe for concurrent use.
+type PendingToolCalls struct {
+	mu      sync.Mutex
+	threads map[string][]ai.ToolCall
+}
+
+// NewPendingToolCalls creates an empty PendingToolCalls.
+func NewPendingToolCalls() *PendingToolCalls {
+	return &PendingToolCalls{threads: make(map[string][]ai.ToolCall)}
+}
+
+// Track passes events through, recording the tool calls a run of thread
+// threadID leaves pending when it ends.
+func (p *PendingToolCalls) Track(threadID string, events <-chan event.Event) <-chan event.Event {
+	out := make(chan event.Event, cap(events))
+	go func() {
+		defer close(out)
+		for e := range events {
+			if e.Type == event.RunEnd && len(e.PendingToolCalls) > 0 {
+				p.Record(threadID, e.PendingToolCalls)
+			}
+			out <- e
+		}
+	}()
+	return out
+}
+
+// Record sets the pending tool calls of thread threadID, replacing any
+// recorded before.
+func (p *PendingToolCalls) Record(threadID string, calls []ai.ToolCall) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.threads[threadID] = append([]ai.ToolCall(nil), calls...)
+}
+
+// Pending returns the pending tool calls of thread threadID.
+func (p *PendingToolCalls) Pending(threadID string) []ai.ToolCall {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]ai.ToolCall(nil), p.threads[threadID]...)
+}
+
+// Resume prepares the messages of a new run of thread threadID and clears
+// its pending calls. Tool results answering pending calls whose assistant
+// message is missing from messages get that message restored before them.
+// Pending calls left unanswered are dropped, since the frontend moved on.
+// A tool result that answers neither a pending call nor a call in messages
+// returns an error wrapping ErrUnknownToolCall.
+func (p *PendingToolCalls) Resume(threadID string, messages []ai.Message) ([]ai.Message, error) {
+	p.mu.Lock()
+	pending := p.threads[threadID]
+	delete(p.threads, threadID)
+	p.mu.Unlock()
+
+	pendingIDs := make(map[string]ai.ToolCall, len(pending))
+	for _, tc := range pending {
+		pendingIDs[tc.ID] = tc
+	}
+
+	result := make([]ai.Message, 0, len(messages)+1)
+	called := make(map[string]bool)
+	for _, msg := range messages {
+		for _, tc := range msg.ToolCalls {
+			called[tc.ID] = true
+		}
+
+		var restore []ai.ToolCall
+		for _, tr := range msg.ToolResults {
+			if called[tr.ToolCallID] {
+				continue
+			}
+			tc, ok := pendingIDs[tr.ToolCallID]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownToolCall, tr.ToolCallID)
+			}
+			restore = append(restore, tc)
+			called[tc.ID] = true
+		}
+		if len(restore) > 0 {
+			result = append(result, ai.Message{Role: ai.RoleAssistant, ToolCalls: restore})
+		}
+		result = append(result, msg)
+	}
+	return result, nil
+}
@@ -0,0 +1,167 @@
+package agui
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/agent"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/tool"
+)
+
+func TestRegisterFrontendTools(t *testing.T) {
+	registry := tool.NewRegistry()
+	tool.MustRegisterFunc(registry, "search", "Search", func(ctx context.Context, args struct{}) (string, error) {
+		return "", nil
+	})
+
+	unregister := RegisterFrontendTools(registry, []Tool{
+		{Name: "confirm", Description: "Ask the user"},
+		{Name: "search", Description: "Frontend search"},
+	})
+	if !registry.IsClientTool("confirm") {
+		t.Error("expected confirm to be a client tool")
+	}
+	if registry.IsClientTool("search") {
+		t.Error("backend search tool replaced by frontend tool")
+	}
+
+	unregister()
+	if _, ok := registry.GetTool("confirm"); ok {
+		t.Error("expected confirm to be unregistered")
+	}
+	if _, ok := registry.GetTool("search"); !ok {
+		t.Error("backend search tool unregistered")
+	}
+}
+
+func TestPendingToolCalls_Resume(t *testing.T) {
+	confirm := ai.ToolCall{ID: "call-1", Name: "confirm", Arguments: `{"question":"Proceed?"}`}
+	user := ai.Message{Role: ai.RoleUser, Content: "Delete the file"}
+	result := ai.Message{Role: ai.RoleTool, ToolResults: []ai.ToolResult{{ToolCallID: "call-1", Content: "yes"}}}
+
+	t.Run("restores the missing assistant message", func(t *testing.T) {
+		p := NewPendingToolCalls()
+		p.Record("t1", []ai.ToolCall{confirm})
+
+		got, err := p.Resume("t1", []ai.Message{user, result})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 3 || got[1].Role != ai.RoleAssistant || len(got[1].ToolCalls) != 1 || got[1].ToolCalls[0] != confirm {
+			t.Fatalf("expected restored tool call before the result, got %+v", got)
+		}
+		if len(p.Pending("t1")) != 0 {
+			t.Error("expected pending calls to be cleared")
+		}
+	})
+
+	t.Run("keeps messages with the call", func(t *testing.T) {
+		p := NewPendingToolCalls()
+		p.Record("t1", []ai.ToolCall{confirm})
+		call := ai.Message{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{confirm}}
+
+		got, err := p.Resume("t1", []ai.Message{user, call, result})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 3 {
+			t.Errorf("expected messages unchanged, got %+v", got)
+		}
+	})
+
+	t.Run("rejects unknown results", func(t *testing.T) {
+		p := NewPendingToolCalls()
+		p.Record("t2", []ai.ToolCall{confirm})
+
+		_, err := p.Resume("t1", []ai.Message{user, result})
+		if !errors.Is(err, ErrUnknownToolCall) {
+			t.Errorf("expected ErrUnknownToolCall, got %v", err)
+		}
+		if len(p.Pending("t2")) != 1 {
+			t.Error("expected other threads to keep their pending calls")
+		}
+	})
+}
+
+func TestPendingToolCalls_Track(t *testing.T) {
+	p := NewPendingToolCalls()
+	calls := []ai.ToolCall{{ID: "call-1", Name: "confirm"}}
+
+	in := make(chan event.Event, 2)
+	in <- event.Event{Type: event.RunStart}
+	in <- event.Event{Type: event.RunEnd, PendingToolCalls: calls}
+	close(in)
+
+	var n int
+	for range p.Track("t1", in) {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("expected events passed through, got %d", n)
+	}
+	if got := p.Pending("t1"); len(got) != 1 || got[0].ID != "call-1" {
+		t.Errorf("expected recorded call, got %v", got)
+	}
+}
+
+// toolCallingChat calls the confirm tool, then answers with the result.
+type toolCallingChat struct {
+	requests [][]ai.Message
+}
+
+func (c *toolCallingChat) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	c.requests = append(c.requests, messages)
+	if len(c.requests) == 1 {
+		return &ai.Response{ToolCalls: []ai.ToolCall{{ID: "call-1", Name: "confirm", Arguments: "{}"}}}, nil
+	}
+	return &ai.Response{Content: "Deleted"}, nil
+}
+
+func (c *toolCallingChat) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+	resp, _ := c.Chat(ctx, messages, opts...)
+	ch := make(chan event.Event, 1)
+	event.Emit(ch, event.Event{Type: event.MessageEnd, MessageID: "m1", Response: resp})
+	close(ch)
+	return ch, nil
+}
+
+func TestHandler_FrontendToolRoundTrip(t *testing.T) {
+	chat := &toolCallingChat{}
+	registry := tool.NewRegistry()
+	h := NewHandler(agent.New(chat, registry), registry)
+
+	first := `{"thread_id": "t1", "messages": [{"id": "1", "role": "user", "content": "Delete the file"}],
+		"tools": [{"name": "confirm", "description": "Ask the user", "parameters": {"type": "object"}}]}`
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(first)))
+	if !strings.Contains(w.Body.String(), "TOOL_CALL_START") {
+		t.Fatalf("expected the frontend tool call, got %s", w.Body)
+	}
+
+	// The frontend sends back only the tool result
+	second := `{"thread_id": "t1", "messages": [
+		{"id": "1", "role": "user", "content": "Delete the file"},
+		{"id": "2", "role": "tool", "content": "yes", "toolCallId": "call-1"}]}`
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(second)))
+	if !strings.Contains(w.Body.String(), "RUN_FINISHED") {
+		t.Fatalf("expected the run to finish, got %s", w.Body)
+	}
+
+	if len(chat.requests) != 2 {
+		t.Fatalf("expected 2 model requests, got %d", len(chat.requests))
+	}
+	var resumed []ai.Role
+	for _, m := range chat.requests[1] {
+		resumed = append(resumed, m.Role)
+	}
+	if len(resumed) != 3 || resumed[1] != ai.RoleAssistant || resumed[2] != ai.RoleTool {
+		t.Errorf("expected user, assistant, tool messages, got %v", resumed)
+	}
+}
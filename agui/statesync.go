@@ -0,0 +1,134 @@
+package agui
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/spetersoncode/gains/event"
+)
+
+// StateSync keeps typed agent or workflow state in sync with an AG-UI
+// frontend. It decodes the state the frontend sent, and after each step
+// emits a STATE_DELTA with the JSON Patch operations that turn the state
+// the frontend last saw into the current one, so handlers never compute
+// patches by hand.
+//
+// Change the state with Update, which holds the lock Sync reads under:
+//
+//	state, err := agui.NewStateSync[MyState](prepared)
+//	// in a tool or step
+//	state.Update(func(s *MyState) { s.Progress = 50 })
+//	// in the handler
+//	for ev := range mapper.MapStream(state.Sync(a.RunStream(ctx, messages))) {
+//	    writeEvent(ev)
+//	}
+//
+// Patches are computed against the state as the frontend sent it, so
+// fields that T does not decode are removed by the first delta. Fields of T
+// tagged hidden (see VisibleState) are never sent.
+// It is safe for concurrent use.
+type StateSync[T any] struct {
+	mu    sync.Mutex
+	state T
+	seen  any // JSON form of the state the frontend last saw
+}
+
+// NewStateSync creates a StateSync holding the state of input, decoded into
+// T. Without frontend state it starts from the zero T.
+func NewStateSync[T any](input *PreparedInput) (*StateSync[T], error) {
+	state, err := DecodeState[T](input)
+	if err != nil {
+		return nil, err
+	}
+	seen, err := toJSONValue(input.State)
+	if err != nil {
+		return nil, err
+	}
+	return &StateSync[T]{state: state, seen: removeHidden(reflect.TypeFor[T](), seen)}, nil
+}
+
+// Get returns a copy of the current state. Pointers, maps, and slices in T
+// are shared with the synced state, so change them only through Update.
+func (s *StateSync[T]) Get() T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// Update calls fn with the state under the lock.
+func (s *StateSync[T]) Update(fn func(state *T)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.state)
+}
+
+// Delta returns the patches from the state the frontend last saw to the
+// current state, and records the current state as seen. It returns no
+// patches if the state is unchanged.
+func (s *StateSync[T]) Delta() ([]event.JSONPatch, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, err := toJSONValue(s.state)
+	if err != nil {
+		return nil, err
+	}
+	current = removeHidden(reflect.TypeFor[T](), current)
+	patches := event.Diff(s.seen, current)
+	s.seen = current
+	return patches, nil
+}
+
+// Sync passes events through, adding a STATE_DELTA event after each step
+// and before the run ends whenever the state changed. State that cannot
+// be encoded as JSON is not synced.
+func (s *StateSync[T]) Sync(events <-chan event.Event) <-chan event.Event {
+	out := make(chan event.Event, cap(events))
+	go func() {
+		defer close(out)
+		var depth int
+		for e := range events {
+			switch e.Type {
+			case event.RunStart:
+				depth++
+			case event.RunEnd, event.RunError:
+				if depth <= 1 {
+					s.emit(out)
+				}
+				if e.Type == event.RunEnd {
+					depth--
+				}
+			}
+			out <- e
+			if e.Type == event.StepEnd {
+				s.emit(out)
+			}
+		}
+	}()
+	return out
+}
+
+// emit sends a STATE_DELTA event for the changes since the last one.
+func (s *StateSync[T]) emit(out chan<- event.Event) {
+	if patches, err := s.Delta(); err == nil && len(patches) > 0 {
+		out <- event.NewStateDelta(patches...)
+	}
+}
+
+// toJSONValue converts v to its generic JSON form: maps, slices, strings,
+// float64s, bools, and nil.
+func toJSONValue(v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,131 @@
+package agui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spetersoncode/gains/event"
+)
+
+type syncState struct {
+	Progress int               `json:"progress"`
+	Items    []string          `json:"items"`
+	Labels   map[string]string `json:"labels,omitempty"`
+}
+
+func TestStateSync_Delta(t *testing.T) {
+	input := &PreparedInput{State: map[string]any{
+		"progress": 10,
+		"items":    []any{"a", "b", "c"},
+	}}
+	s, err := NewStateSync[syncState](input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Get(); got.Progress != 10 || len(got.Items) != 3 {
+		t.Fatalf("expected decoded frontend state, got %+v", got)
+	}
+
+	patches, err := s.Delta()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(patches) != 0 {
+		t.Errorf("expected no patches for unchanged state, got %v", patches)
+	}
+
+	s.Update(func(st *syncState) {
+		st.Progress = 50
+		st.Items = []string{"a", "x"}
+		st.Labels = map[string]string{"a/b": "1"}
+	})
+	patches, err = s.Delta()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []event.JSONPatch{
+		event.Replace("/items/1", "x"),
+		event.Remove("/items/2"),
+		event.Add("/labels", map[string]any{"a/b": "1"}),
+		event.Replace("/progress", float64(50)),
+	}
+	if !reflect.DeepEqual(patches, want) {
+		t.Errorf("unexpected patches:\n got %v\nwant %v", patches, want)
+	}
+
+	s.Update(func(st *syncState) { st.Labels["a/b"] = "2" })
+	patches, _ = s.Delta()
+	if want := []event.JSONPatch{event.Replace("/labels/a~1b", "2")}; !reflect.DeepEqual(patches, want) {
+		t.Errorf("expected escaped pointer, got %v", patches)
+	}
+}
+
+func TestStateSync_WithoutFrontendState(t *testing.T) {
+	s, err := NewStateSync[syncState](&PreparedInput{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	patches, _ := s.Delta()
+	if len(patches) != 1 || patches[0].Op != event.PatchAdd || patches[0].Path != "" {
+		t.Errorf("expected the whole state to be added, got %v", patches)
+	}
+}
+
+func TestStateSync_Sync(t *testing.T) {
+	collect := func(s *StateSync[syncState], events ...event.Event) []event.Type {
+		in := make(chan event.Event, len(events))
+		for _, e := range events {
+			in <- e
+		}
+		close(in)
+		var types []event.Type
+		for e := range s.Sync(in) {
+			types = append(types, e.Type)
+		}
+		return types
+	}
+
+	t.Run("after changed steps", func(t *testing.T) {
+		s, _ := NewStateSync[syncState](&PreparedInput{State: map[string]any{"progress": 0, "items": nil}})
+		in := make(chan event.Event)
+		out := s.Sync(in)
+		var types []event.Type
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for e := range out {
+				types = append(types, e.Type)
+			}
+		}()
+
+		in <- event.Event{Type: event.RunStart}
+		in <- event.Event{Type: event.StepStart}
+		s.Update(func(st *syncState) { st.Progress = 1 })
+		in <- event.Event{Type: event.StepEnd}
+		in <- event.Event{Type: event.StepStart}
+		in <- event.Event{Type: event.StepEnd} // unchanged
+		in <- event.Event{Type: event.RunEnd}
+		close(in)
+		<-done
+
+		want := []event.Type{
+			event.RunStart,
+			event.StepStart, event.StepEnd, event.StateDelta,
+			event.StepStart, event.StepEnd,
+			event.RunEnd,
+		}
+		if !reflect.DeepEqual(types, want) {
+			t.Errorf("unexpected events:\n got %v\nwant %v", types, want)
+		}
+	})
+
+	t.Run("before the run ends", func(t *testing.T) {
+		s, _ := NewStateSync[syncState](&PreparedInput{State: map[string]any{"progress": 0, "items": nil}})
+		s.Update(func(st *syncState) { st.Progress = 2 })
+		types := collect(s, event.Event{Type: event.RunStart}, event.Event{Type: event.RunEnd})
+		want := []event.Type{event.RunStart, event.StateDelta, event.RunEnd}
+		if !reflect.DeepEqual(types, want) {
+			t.Errorf("unexpected events:\n got %v\nwant %v", types, want)
+		}
+	})
+}
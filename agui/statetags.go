@@ -0,0 +1,251 @@
+package agui
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Struct tag hints for shared state. The agui tag of a state field holds an
+// optional display label followed by options:
+//
+//	type ReportState struct {
+//	    Topic    string  `json:"topic" agui:"Research topic"`
+//	    Progress float64 `json:"progress" agui:"Completion,progress"`
+//	    APIKey   string  `json:"apiKey" agui:"-"`
+//	    Scratch  string  `json:"scratch" agui:",hidden"`
+//	}
+//
+// Hidden fields, tagged "-" or with the hidden option, are left out of
+// STATE_SNAPSHOT and StateSync's STATE_DELTA payloads and out of workflow
+// input schemas, keeping secrets and internal bookkeeping on the server.
+// Labels become the title of the field's schema, and the progress option
+// marks it with HintProgress for frontends to render as a progress bar.
+const (
+	// SchemaHintKey is the schema keyword holding a field's display hint.
+	SchemaHintKey = "x-agui-hint"
+
+	// HintProgress is the display hint of fields tagged progress.
+	HintProgress = "progress"
+)
+
+// fieldHints is the parsed agui tag of a state field.
+type fieldHints struct {
+	label    string
+	hidden   bool
+	progress bool
+}
+
+func parseHints(f reflect.StructField) fieldHints {
+	tag, ok := f.Tag.Lookup("agui")
+	if !ok {
+		return fieldHints{}
+	}
+	if tag == "-" {
+		return fieldHints{hidden: true}
+	}
+	label, opts, _ := strings.Cut(tag, ",")
+	h := fieldHints{label: label}
+	for opt := range strings.SplitSeq(opts, ",") {
+		switch opt {
+		case "hidden":
+			h.hidden = true
+		case "progress":
+			h.progress = true
+		}
+	}
+	return h
+}
+
+// jsonName returns the name encoding/json gives field f, and false if f is
+// not encoded or is an embedded struct whose fields are promoted.
+func jsonName(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name != "" {
+		return name, true
+	}
+	if f.Anonymous {
+		t := f.Type
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Kind() == reflect.Struct {
+			return "", false
+		}
+	}
+	return f.Name, f.IsExported()
+}
+
+// hiddenTypes caches whether a type has hidden fields, at any depth.
+var hiddenTypes sync.Map // reflect.Type -> bool
+
+// hasHidden reports whether values of t have hidden fields.
+func hasHidden(t reflect.Type) bool {
+	if v, ok := hiddenTypes.Load(t); ok {
+		return v.(bool)
+	}
+	hidden := findHidden(t, make(map[reflect.Type]bool))
+	hiddenTypes.Store(t, hidden)
+	return hidden
+}
+
+func findHidden(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return findHidden(t.Elem(), seen)
+	case reflect.Struct:
+		for _, f := range reflect.VisibleFields(t) {
+			if _, ok := jsonName(f); !ok {
+				continue
+			}
+			if parseHints(f).hidden || findHidden(f.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// VisibleState returns state as the frontend may see it: if its type has
+// hidden fields, its JSON form without them; otherwise state itself. The
+// Mapper applies it to every STATE_SNAPSHOT. Hidden fields are found
+// through the static types of state, so struct values held in interface
+// fields or map[string]any are not filtered.
+func VisibleState(state any) any {
+	if state == nil || !hasHidden(reflect.TypeOf(state)) {
+		return state
+	}
+	v, err := toJSONValue(state)
+	if err != nil {
+		return state
+	}
+	return removeHidden(reflect.TypeOf(state), v)
+}
+
+// removeHidden deletes the hidden fields of values of type t from v, the
+// JSON form of such a value.
+func removeHidden(t reflect.Type, v any) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return v
+		}
+		for _, f := range reflect.VisibleFields(t) {
+			name, ok := jsonName(f)
+			if !ok {
+				continue
+			}
+			if _, ok := obj[name]; !ok {
+				continue
+			}
+			if parseHints(f).hidden {
+				delete(obj, name)
+			} else {
+				obj[name] = removeHidden(f.Type, obj[name])
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if arr, ok := v.([]any); ok {
+			for i := range arr {
+				arr[i] = removeHidden(t.Elem(), arr[i])
+			}
+		}
+	case reflect.Map:
+		if obj, ok := v.(map[string]any); ok {
+			for k := range obj {
+				obj[k] = removeHidden(t.Elem(), obj[k])
+			}
+		}
+	}
+	return v
+}
+
+// annotateSchema applies the agui tags of t to schema, a JSON schema of t
+// generated by gains.SchemaFor: hidden fields are removed, and labels and
+// hints added. The schema is returned unchanged if it cannot be decoded.
+func annotateSchema(t reflect.Type, schema json.RawMessage) json.RawMessage {
+	var s map[string]any
+	if err := json.Unmarshal(schema, &s); err != nil {
+		return schema
+	}
+	annotate(t, s, make(map[reflect.Type]bool))
+	out, err := json.Marshal(s)
+	if err != nil {
+		return schema
+	}
+	return out
+}
+
+func annotate(t reflect.Type, s map[string]any, seen map[reflect.Type]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if items, ok := s["items"].(map[string]any); ok {
+			annotate(t.Elem(), items, seen)
+		}
+		return
+	case reflect.Map:
+		if items, ok := s["additionalProperties"].(map[string]any); ok {
+			annotate(t.Elem(), items, seen)
+		}
+		return
+	case reflect.Struct:
+	default:
+		return
+	}
+	// Recursive types are described once, under $defs
+	if seen[t] {
+		return
+	}
+	seen[t] = true
+	props, ok := s["properties"].(map[string]any)
+	if !ok {
+		return
+	}
+	for _, f := range reflect.VisibleFields(t) {
+		name, ok := jsonName(f)
+		if !ok {
+			continue
+		}
+		prop, ok := props[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		h := parseHints(f)
+		if h.hidden {
+			delete(props, name)
+			if required, ok := s["required"].([]any); ok {
+				required = slices.DeleteFunc(required, func(r any) bool { return r == name })
+				if len(required) == 0 {
+					delete(s, "required")
+				} else {
+					s["required"] = required
+				}
+			}
+			continue
+		}
+		if h.label != "" {
+			prop["title"] = h.label
+		}
+		if h.progress {
+			prop[SchemaHintKey] = HintProgress
+		}
+		annotate(f.Type, prop, seen)
+	}
+}
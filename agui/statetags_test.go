@@ -0,0 +1,136 @@
+package agui
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/workflow"
+)
+
+type taggedStep struct {
+	Name  string `json:"name" agui:"Step"`
+	Token string `json:"token" agui:"-"`
+}
+
+type taggedState struct {
+	Topic    string                 `json:"topic" agui:"Research topic" required:"true"`
+	Progress float64                `json:"progress" agui:"Completion,progress"`
+	APIKey   string                 `json:"apiKey" agui:",hidden" required:"true"`
+	Steps    []taggedStep           `json:"steps"`
+	ByName   map[string]*taggedStep `json:"byName,omitempty"`
+}
+
+func TestVisibleState(t *testing.T) {
+	state := &taggedState{
+		Topic:    "batteries",
+		Progress: 0.5,
+		APIKey:   "sk-secret",
+		Steps:    []taggedStep{{Name: "search", Token: "t1"}},
+		ByName:   map[string]*taggedStep{"search": {Name: "search", Token: "t1"}},
+	}
+	got := VisibleState(state)
+	want := map[string]any{
+		"topic":    "batteries",
+		"progress": 0.5,
+		"steps":    []any{map[string]any{"name": "search"}},
+		"byName":   map[string]any{"search": map[string]any{"name": "search"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected visible state:\n got %#v\nwant %#v", got, want)
+	}
+
+	plain := syncState{Progress: 1}
+	if got := VisibleState(plain); !reflect.DeepEqual(got, plain) {
+		t.Errorf("expected state without hidden fields unchanged, got %#v", got)
+	}
+}
+
+func TestMapper_StateSnapshotHidesFields(t *testing.T) {
+	m := NewMapper("thread", "run")
+	ev := m.MapEvent(event.NewStateSnapshot(taggedState{Topic: "x", APIKey: "sk-secret"}))
+	snap, ok := ev.(*events.StateSnapshotEvent)
+	if !ok {
+		t.Fatalf("expected STATE_SNAPSHOT, got %T", ev)
+	}
+	data, err := json.Marshal(snap.Snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := decoded["apiKey"]; ok || decoded["topic"] != "x" {
+		t.Errorf("unexpected snapshot: %s", data)
+	}
+}
+
+func TestStateSync_HiddenFields(t *testing.T) {
+	s, err := NewStateSync[taggedState](&PreparedInput{State: map[string]any{
+		"topic":  "x",
+		"apiKey": "sk-from-frontend",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Get().APIKey != "sk-from-frontend" {
+		t.Errorf("expected hidden field decoded from frontend state")
+	}
+
+	s.Update(func(st *taggedState) {
+		st.APIKey = "sk-rotated"
+		st.Progress = 0.25
+	})
+	patches, err := s.Delta()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []event.JSONPatch{
+		event.Add("/progress", 0.25),
+		event.Add("/steps", nil),
+	}
+	if !reflect.DeepEqual(patches, want) {
+		t.Errorf("unexpected patches:\n got %v\nwant %v", patches, want)
+	}
+}
+
+func TestWorkflowSchemas_Tags(t *testing.T) {
+	registry := workflow.NewRegistry()
+	noop := workflow.NewFuncStep("noop", func(ctx context.Context, state *taggedState) error {
+		return nil
+	})
+	registry.Register(workflow.NewRunnerJSON[taggedState]("research", noop))
+
+	var schema struct {
+		Properties map[string]map[string]any `json:"properties"`
+		Required   []string                  `json:"required"`
+	}
+	if err := json.Unmarshal(WorkflowSchemas(registry)[0].InputSchema, &schema); err != nil {
+		t.Fatalf("invalid schema: %v", err)
+	}
+	if _, ok := schema.Properties["apiKey"]; ok {
+		t.Error("expected hidden field left out of the schema")
+	}
+	if !reflect.DeepEqual(schema.Required, []string{"topic"}) {
+		t.Errorf("expected hidden field left out of required, got %v", schema.Required)
+	}
+	if got := schema.Properties["topic"]["title"]; got != "Research topic" {
+		t.Errorf("expected label as title, got %v", got)
+	}
+	if got := schema.Properties["progress"][SchemaHintKey]; got != HintProgress {
+		t.Errorf("expected progress hint, got %v", got)
+	}
+	items := schema.Properties["steps"]["items"].(map[string]any)
+	props := items["properties"].(map[string]any)
+	if _, ok := props["token"]; ok {
+		t.Error("expected nested hidden field left out of the schema")
+	}
+	if got := props["name"].(map[string]any)["title"]; got != "Step" {
+		t.Errorf("expected nested label, got %v", got)
+	}
+}
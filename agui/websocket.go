@@ -0,0 +1,248 @@
+package agui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+	"github.com/gorilla/websocket"
+)
+
+// DefaultPingInterval is how often a WebSocketConn pings its peer when no
+// interval is set.
+const DefaultPingInterval = 30 * time.Second
+
+// closeTimeout bounds how long Close waits for the peer to acknowledge a
+// close frame, and how long a single write may block.
+const closeTimeout = 5 * time.Second
+
+// ErrConnClosed is returned by WebSocketConn.ReadInput once the peer has
+// disconnected or the connection is closed.
+var ErrConnClosed = errors.New("agui: websocket connection closed")
+
+// WithWebSocketUpgrader sets the upgrader ServeWebSocket accepts
+// connections with, for example to check the request origin. The default
+// upgrader accepts only same-origin requests.
+func WithWebSocketUpgrader(u *websocket.Upgrader) HandlerOption {
+	return func(h *Handler) {
+		h.upgrader = u
+	}
+}
+
+// WithPingInterval sets how often ServeWebSocket pings connected clients.
+// A client that answers no ping for two intervals is disconnected. Default
+// is DefaultPingInterval.
+func WithPingInterval(d time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.pingInterval = d
+	}
+}
+
+// WebSocketConn carries AG-UI runs over a WebSocket connection: the client
+// sends each RunAgentInput as a text message, and every mapped event is
+// written back as a text message holding its JSON. Proxies that buffer SSE
+// responses usually pass WebSocket frames through as they arrive.
+//
+// The connection is kept alive with pings; a peer that answers none for two
+// intervals is treated as gone. Close performs the WebSocket closing
+// handshake. WriteEvent is safe for concurrent use.
+type WebSocketConn struct {
+	conn *websocket.Conn
+	done chan struct{} // closed when reading stops
+	stop chan struct{} // closed by Close
+
+	// Inputs are queued so reading, and with it pong handling, never
+	// waits for a run to finish
+	mu     sync.Mutex
+	queue  [][]byte
+	queued chan struct{}
+
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+}
+
+// NewWebSocketConn starts reading inputs from conn and pinging the peer
+// every pingInterval, or DefaultPingInterval if it is zero or less. The
+// WebSocketConn owns conn from then on.
+func NewWebSocketConn(conn *websocket.Conn, pingInterval time.Duration) *WebSocketConn {
+	if pingInterval <= 0 {
+		pingInterval = DefaultPingInterval
+	}
+	c := &WebSocketConn{
+		conn:   conn,
+		done:   make(chan struct{}),
+		stop:   make(chan struct{}),
+		queued: make(chan struct{}, 1),
+	}
+
+	// Each pong extends the time the peer has to answer the next ping
+	conn.SetReadDeadline(time.Now().Add(2 * pingInterval))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * pingInterval))
+	})
+
+	go c.read()
+	go c.ping(pingInterval)
+	return c
+}
+
+// read queues text messages until the connection fails or the peer closes
+// it.
+func (c *WebSocketConn) read() {
+	defer close(c.done)
+	for {
+		typ, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if typ != websocket.TextMessage {
+			continue
+		}
+		c.mu.Lock()
+		c.queue = append(c.queue, data)
+		c.mu.Unlock()
+		select {
+		case c.queued <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// ping pings the peer every interval until the connection is done.
+func (c *WebSocketConn) ping(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(closeTimeout)); err != nil {
+				return
+			}
+		case <-c.done:
+			return
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// Done returns a channel closed when the peer disconnects or the
+// connection is closed.
+func (c *WebSocketConn) Done() <-chan struct{} {
+	return c.done
+}
+
+// ReadInput returns the next RunAgentInput sent by the peer. It returns
+// ErrConnClosed once the connection is gone, and a decoding error for a
+// message that is not a valid RunAgentInput, after which reading may go on.
+func (c *WebSocketConn) ReadInput() (RunAgentInput, error) {
+	var input RunAgentInput
+	for {
+		c.mu.Lock()
+		if len(c.queue) > 0 {
+			data := c.queue[0]
+			c.queue = c.queue[1:]
+			c.mu.Unlock()
+			if err := json.Unmarshal(data, &input); err != nil {
+				return input, fmt.Errorf("agui: invalid run input: %w", err)
+			}
+			return input, nil
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-c.queued:
+		case <-c.done:
+			return input, ErrConnClosed
+		case <-c.stop:
+			return input, ErrConnClosed
+		}
+	}
+}
+
+// WriteEvent writes ev to the peer as a JSON text message.
+func (c *WebSocketConn) WriteEvent(ev events.Event) error {
+	data, err := ev.ToJSON()
+	if err != nil {
+		return fmt.Errorf("agui: serialize event: %w", err)
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(closeTimeout))
+	if err := c.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		return fmt.Errorf("agui: write event: %w", err)
+	}
+	return nil
+}
+
+// Close sends a normal closure frame, waits briefly for the peer to answer
+// it, and closes the connection. It is safe to call more than once.
+func (c *WebSocketConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.stop)
+		msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+		if c.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(closeTimeout)) == nil {
+			select {
+			case <-c.done:
+			case <-time.After(closeTimeout):
+			}
+		}
+		err = c.conn.Close()
+	})
+	return err
+}
+
+// ServeWebSocket upgrades the request to a WebSocket connection and serves
+// runs over it, one at a time, in the order the client sends them. Each
+// text message from the client is a RunAgentInput, answered with the run's
+// events as described by WebSocketConn. An input that cannot be run gets a
+// RUN_ERROR event and leaves the connection open. Runs in progress are
+// canceled when the client disconnects.
+func (h *Handler) ServeWebSocket(w http.ResponseWriter, r *http.Request) {
+	upgrader := h.upgrader
+	if upgrader == nil {
+		upgrader = &websocket.Upgrader{}
+	}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // the upgrader has replied with an HTTP error
+	}
+	c := NewWebSocketConn(conn, h.pingInterval)
+	defer c.Close()
+
+	// The request context outlives the client once the connection is hijacked
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		input, err := c.ReadInput()
+		if errors.Is(err, ErrConnClosed) {
+			return
+		}
+		if err != nil {
+			c.WriteEvent(events.NewRunErrorEvent(err.Error()))
+			continue
+		}
+
+		run, reqErr := h.startRun(ctx, input)
+		if reqErr != nil {
+			c.WriteEvent(events.NewRunErrorEvent(reqErr.Error(), events.WithRunID(input.RunID)))
+			continue
+		}
+		h.streamRun(run, c.WriteEvent)
+		run.release()
+	}
+}
@@ -0,0 +1,146 @@
+package agui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/spetersoncode/gains/agent"
+	"github.com/spetersoncode/gains/tool"
+)
+
+// dialWebSocket serves h.ServeWebSocket and connects a client to it.
+func dialWebSocket(t *testing.T, h *Handler) *websocket.Conn {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(h.ServeWebSocket))
+	t.Cleanup(srv.Close)
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// readRun reads events from conn until one ends the run.
+func readRun(t *testing.T, conn *websocket.Conn) []map[string]any {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var evs []map[string]any
+	for {
+		var ev map[string]any
+		if err := conn.ReadJSON(&ev); err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+		evs = append(evs, ev)
+		if ev["type"] == "RUN_FINISHED" || ev["type"] == "RUN_ERROR" {
+			return evs
+		}
+	}
+}
+
+func TestHandler_ServeWebSocket(t *testing.T) {
+	registry := tool.NewRegistry()
+	h := NewHandler(agent.New(&handlerChat{}, registry), registry)
+	conn := dialWebSocket(t, h)
+
+	input := `{"thread_id": "t1", "run_id": "r1", "messages": [{"id": "1", "role": "user", "content": "Hello"}]}`
+	want := []string{
+		"RUN_STARTED", "STEP_STARTED",
+		"TEXT_MESSAGE_START", "TEXT_MESSAGE_CONTENT", "TEXT_MESSAGE_END",
+		"STEP_FINISHED", "RUN_FINISHED",
+	}
+
+	// Runs on one connection are served in turn
+	for _, runID := range []string{"r1", "r2"} {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(strings.Replace(input, "r1", runID, 1))); err != nil {
+			t.Fatal(err)
+		}
+		evs := readRun(t, conn)
+		if got := eventTypes(evs); !reflect.DeepEqual(got, want) {
+			t.Fatalf("unexpected events:\n got %v\nwant %v", got, want)
+		}
+		if evs[0]["runId"] != runID {
+			t.Errorf("expected run %s, got %v", runID, evs[0]["runId"])
+		}
+	}
+
+	// Invalid input is reported without closing the connection
+	conn.WriteMessage(websocket.TextMessage, []byte("not json"))
+	if evs := readRun(t, conn); len(evs) != 1 || !strings.Contains(evs[0]["message"].(string), "invalid run input") {
+		t.Errorf("expected RUN_ERROR for invalid input, got %v", evs)
+	}
+	conn.WriteMessage(websocket.TextMessage, []byte(input))
+	if evs := readRun(t, conn); evs[len(evs)-1]["type"] != "RUN_FINISHED" {
+		t.Errorf("expected the connection to stay usable, got %v", evs)
+	}
+}
+
+func TestWebSocketConn_Close(t *testing.T) {
+	closed := make(chan error, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		c := NewWebSocketConn(conn, 10*time.Millisecond)
+		input, err := c.ReadInput()
+		if err == nil {
+			data, _ := json.Marshal(input.ThreadID)
+			conn.WriteMessage(websocket.TextMessage, data)
+		}
+		closed <- c.Close()
+	}))
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var pings atomic.Int32
+	conn.SetPingHandler(func(data string) error {
+		pings.Add(1)
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+
+	// Read continuously so pings are answered
+	type message struct {
+		data []byte
+		err  error
+	}
+	messages := make(chan message)
+	go func() {
+		for {
+			_, data, err := conn.ReadMessage()
+			messages <- message{data, err}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	time.Sleep(50 * time.Millisecond) // outlast the pong deadline
+	conn.WriteMessage(websocket.TextMessage, []byte(`{"thread_id": "t1"}`))
+
+	if m := <-messages; m.err != nil || string(m.data) != `"t1"` {
+		t.Fatalf("expected the thread ID back, got %q, %v", m.data, m.err)
+	}
+	if m := <-messages; !websocket.IsCloseError(m.err, websocket.CloseNormalClosure) {
+		t.Errorf("expected a normal closure, got %v", m.err)
+	}
+	if err := <-closed; err != nil {
+		t.Errorf("close: %v", err)
+	}
+	if pings.Load() == 0 {
+		t.Error("expected pings while idle")
+	}
+}
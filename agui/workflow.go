@@ -0,0 +1,43 @@
+package agui
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/spetersoncode/gains/workflow"
+)
+
+// WorkflowSchema describes a registered workflow and the JSON schema of the
+// state it accepts in RunWorkflowInput.State. Frontends can use the schema
+// to render a form for launching the workflow.
+type WorkflowSchema struct {
+	Name        string          `json:"name"`
+	InputSchema json.RawMessage `json:"input_schema,omitempty"`
+}
+
+// WorkflowSchemas returns a WorkflowSchema for each workflow in registry,
+// sorted by name. Workflows without a generated input schema are listed
+// with an empty InputSchema. The agui tags of the state fields of
+// workflows that report their state type, as workflow.RunnerFunc does,
+// are applied: hidden fields are left out, and labels and hints added.
+func WorkflowSchemas(registry *workflow.Registry) []WorkflowSchema {
+	schemas := registry.InputSchemas()
+	names := registry.Names()
+	sort.Strings(names)
+
+	result := make([]WorkflowSchema, len(names))
+	for i, name := range names {
+		schema := schemas[name]
+		if st, ok := registry.Get(name).(stateTyped); ok && schema != nil {
+			schema = annotateSchema(st.StateType(), schema)
+		}
+		result[i] = WorkflowSchema{Name: name, InputSchema: schema}
+	}
+	return result
+}
+
+// stateTyped is implemented by runners that report their state type.
+type stateTyped interface {
+	StateType() reflect.Type
+}
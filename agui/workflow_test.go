@@ -0,0 +1,66 @@
+package agui
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/spetersoncode/gains/workflow"
+)
+
+type launchState struct {
+	Topic string `json:"topic" desc:"Topic to research" required:"true"`
+	Depth int    `json:"depth" min:"1" max:"5"`
+}
+
+type namedRunner struct{ name string }
+
+func (r namedRunner) Name() string { return r.name }
+
+func (r namedRunner) RunStream(ctx context.Context, state any, opts ...workflow.Option) <-chan workflow.Event {
+	ch := make(chan workflow.Event)
+	close(ch)
+	return ch
+}
+
+func TestWorkflowSchemas(t *testing.T) {
+	registry := workflow.NewRegistry()
+	noop := workflow.NewFuncStep("noop", func(ctx context.Context, state *launchState) error {
+		return nil
+	})
+	registry.Register(workflow.NewRunnerJSON[launchState]("research", noop))
+	registry.Register(namedRunner{name: "custom"})
+
+	schemas := WorkflowSchemas(registry)
+	if len(schemas) != 2 {
+		t.Fatalf("expected 2 workflows, got %d", len(schemas))
+	}
+
+	if schemas[0].Name != "custom" || len(schemas[0].InputSchema) != 0 {
+		t.Errorf("expected custom workflow without schema, got %+v", schemas[0])
+	}
+	data, err := json.Marshal(schemas[0])
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(data) != `{"name":"custom"}` {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+
+	if schemas[1].Name != "research" {
+		t.Fatalf("expected research workflow, got %q", schemas[1].Name)
+	}
+	var schema struct {
+		Properties map[string]map[string]any `json:"properties"`
+		Required   []string                  `json:"required"`
+	}
+	if err := json.Unmarshal(schemas[1].InputSchema, &schema); err != nil {
+		t.Fatalf("invalid schema: %v", err)
+	}
+	if got := schema.Properties["topic"]["description"]; got != "Topic to research" {
+		t.Errorf("expected topic description, got %v", got)
+	}
+	if len(schema.Required) != 1 || schema.Required[0] != "topic" {
+		t.Errorf("expected topic to be required, got %v", schema.Required)
+	}
+}
@@ -0,0 +1,54 @@
+// Package apiserver exposes gains agents and workflows as a language-neutral
+// JSON API, so services written in other languages can start runs, follow
+// their events, and cancel them without linking the library.
+//
+// A [Handler] serves the workflows of a workflow.Registry and any number of
+// named agents:
+//
+//	h := apiserver.NewHandler(
+//	    apiserver.WithWorkflows(registry),
+//	    apiserver.WithAgent("assistant", a, agent.WithMaxSteps(10)),
+//	    apiserver.WithAPIKeys(os.Getenv("APISERVER_API_KEY")),
+//	)
+//	http.Handle("/v1/", h)
+//
+// # Runs
+//
+// Starting a run answers 202 Accepted at once with the [Run], whose ID
+// names it in the other endpoints. The run executes in the background,
+// outliving the request, and is registered with the runtime package, or
+// the Coordinator set with WithCoordinator, so Drain waits for it and new
+// runs are refused with 503 Service Unavailable while draining. At most
+// WithMaxActiveRuns runs execute at once; starting another is refused with
+// 429 Too Many Requests:
+//
+//	curl localhost:8080/v1/workflows/greeting/runs -d '{"input": {"name": "Ada"}}'
+//	curl localhost:8080/v1/agents/assistant/runs -d '{"input": "What time is it?"}'
+//
+// A workflow run's input is decoded into the workflow's state; an agent
+// run takes messages, an input text appended as a user message, or both.
+// GET /v1/runs/{id} reports the status and, once finished, the output: the
+// workflow's final state or the agent's final response. Finished runs are
+// kept in memory up to WithMaxRuns, oldest forgotten first.
+//
+// # Events
+//
+// GET /v1/runs/{id}/events streams every event of a run as Server-Sent
+// Events, from the start even if the run has finished. Each SSE event is
+// named by the gains event type, identified by its index, and carries the
+// event in the JSON form of event.JSONLinesSink. A final "done" event
+// carries the finished [Run]. A client reconnecting with Last-Event-ID
+// resumes after that event.
+//
+// # OpenAPI
+//
+// GET /v1/openapi.json answers the OpenAPI 3.1 document of the API, also
+// available from [Handler.OpenAPI]. Each workflow and agent has its own run
+// operation, and workflow operations carry the input schema of the state,
+// so clients generated from the document are typed per workflow.
+//
+// # Errors
+//
+// Errors are answered with an [Error] under the "error" key, with one of
+// the Code constants and a message.
+package apiserver
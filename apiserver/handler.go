@@ -0,0 +1,380 @@
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/agent"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/internal/apikey"
+	"github.com/spetersoncode/gains/runtime"
+	"github.com/spetersoncode/gains/sse"
+	"github.com/spetersoncode/gains/workflow"
+)
+
+// DefaultMaxRuns is the number of finished runs a Handler keeps without
+// WithMaxRuns.
+const DefaultMaxRuns = 1000
+
+// DefaultMaxActiveRuns is the number of runs a Handler executes at once
+// without WithMaxActiveRuns.
+const DefaultMaxActiveRuns = 100
+
+// heartbeat is the interval of the comments keeping idle event streams
+// open.
+const heartbeat = 15 * time.Second
+
+// AgentRunner is the interface required from gains agents.
+type AgentRunner interface {
+	RunStream(ctx context.Context, messages []ai.Message, opts ...agent.Option) <-chan event.Event
+}
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithWorkflows serves the workflows of registry. Workflows registered
+// later are served too; the OpenAPI document describes those registered
+// when it is requested.
+func WithWorkflows(registry *workflow.Registry) Option {
+	return func(h *Handler) {
+		h.workflows = registry
+	}
+}
+
+// WithAgent serves a under name, running it with opts.
+func WithAgent(name string, a AgentRunner, opts ...agent.Option) Option {
+	return func(h *Handler) {
+		h.agents[name] = agentEntry{runner: a, opts: opts}
+	}
+}
+
+// WithAPIKeys requires requests to carry one of keys as a bearer token.
+// Empty keys are ignored. Without a non-empty key, requests are not
+// authenticated.
+func WithAPIKeys(keys ...string) Option {
+	return func(h *Handler) {
+		h.apiKeys.Add(keys...)
+	}
+}
+
+// WithMaxRuns sets how many finished runs are kept for status and event
+// requests, oldest forgotten first. Zero or less keeps every run. The
+// default is DefaultMaxRuns.
+func WithMaxRuns(n int) Option {
+	return func(h *Handler) {
+		h.runs.max = n
+	}
+}
+
+// WithMaxActiveRuns sets how many runs may execute at once. Starting a run
+// beyond the limit is refused with 429 Too Many Requests. Zero or less
+// removes the limit. The default is DefaultMaxActiveRuns.
+func WithMaxActiveRuns(n int) Option {
+	return func(h *Handler) {
+		h.runs.maxActive = n
+	}
+}
+
+// WithCoordinator registers runs with c, so c.Drain waits for them and
+// new runs are refused while it drains. The default is the process-wide
+// Coordinator of the runtime package functions.
+func WithCoordinator(c *runtime.Coordinator) Option {
+	return func(h *Handler) {
+		h.coordinator = c
+	}
+}
+
+// WithInfo sets the title and version of the API in its OpenAPI document.
+func WithInfo(title, version string) Option {
+	return func(h *Handler) {
+		h.title = title
+		h.version = version
+	}
+}
+
+// agentEntry is a served agent and its run options.
+type agentEntry struct {
+	runner AgentRunner
+	opts   []agent.Option
+}
+
+// Handler serves agents and workflows as a JSON API. Runs execute in the
+// background and are observed by polling or streaming:
+//
+//	GET  /v1/openapi.json           - the OpenAPI document of the API
+//	GET  /v1/workflows              - the workflows and their input schemas
+//	POST /v1/workflows/{name}/runs  - start a workflow run
+//	GET  /v1/agents                 - the agents
+//	POST /v1/agents/{name}/runs     - start an agent run
+//	GET  /v1/runs/{id}              - a run's status and output
+//	GET  /v1/runs/{id}/events       - a run's events, streamed as SSE
+//	POST /v1/runs/{id}/cancel       - cancel a run
+//
+// Mount it at "/v1/". It is safe for concurrent use.
+type Handler struct {
+	workflows *workflow.Registry
+	agents    map[string]agentEntry
+	apiKeys   apikey.Keys
+	title     string
+	version   string
+	runs      *runStore
+	mux       *http.ServeMux
+
+	// coordinator registers runs; nil uses the process-wide one
+	coordinator *runtime.Coordinator
+}
+
+// NewHandler creates a Handler serving the agents and workflows of opts.
+func NewHandler(opts ...Option) *Handler {
+	h := &Handler{
+		workflows: workflow.NewRegistry(),
+		agents:    make(map[string]agentEntry),
+		title:     "gains API",
+		version:   "1.0.0",
+		runs:      newRunStore(DefaultMaxRuns, DefaultMaxActiveRuns),
+		mux:       http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.mux.HandleFunc("GET /v1/openapi.json", h.serveOpenAPI)
+	h.mux.HandleFunc("GET /v1/workflows", h.serveWorkflows)
+	h.mux.HandleFunc("POST /v1/workflows/{name}/runs", h.serveWorkflowRun)
+	h.mux.HandleFunc("GET /v1/agents", h.serveAgents)
+	h.mux.HandleFunc("POST /v1/agents/{name}/runs", h.serveAgentRun)
+	h.mux.HandleFunc("GET /v1/runs/{id}", h.serveRun)
+	h.mux.HandleFunc("GET /v1/runs/{id}/events", h.serveEvents)
+	h.mux.HandleFunc("POST /v1/runs/{id}/cancel", h.serveCancel)
+	return h
+}
+
+// ServeHTTP authenticates the request and routes it.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.apiKeys.Authorized(r) {
+		writeError(w, http.StatusUnauthorized, CodeUnauthorized, "invalid API key")
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// serveOpenAPI writes the OpenAPI document.
+func (h *Handler) serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, h.OpenAPI())
+}
+
+// serveWorkflows lists the workflows.
+func (h *Handler) serveWorkflows(w http.ResponseWriter, r *http.Request) {
+	schemas := h.workflows.InputSchemas()
+	workflows := []WorkflowInfo{}
+	for _, name := range h.workflowNames() {
+		workflows = append(workflows, WorkflowInfo{Name: name, InputSchema: schemas[name]})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"workflows": workflows})
+}
+
+// serveAgents lists the agents.
+func (h *Handler) serveAgents(w http.ResponseWriter, r *http.Request) {
+	agents := []AgentInfo{}
+	for _, name := range h.agentNames() {
+		agents = append(agents, AgentInfo{Name: name})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"agents": agents})
+}
+
+// workflowNames returns the names of the workflows in order.
+func (h *Handler) workflowNames() []string {
+	names := h.workflows.Names()
+	slices.Sort(names)
+	return names
+}
+
+// agentNames returns the names of the agents in order.
+func (h *Handler) agentNames() []string {
+	names := make([]string, 0, len(h.agents))
+	for name := range h.agents {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// serveWorkflowRun starts a workflow run.
+func (h *Handler) serveWorkflowRun(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	runner := h.workflows.Get(name)
+	if runner == nil {
+		writeError(w, http.StatusNotFound, CodeNotFound, "workflow not found: "+name)
+		return
+	}
+	var req WorkflowRunRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	h.start(w, KindWorkflow, name, func(ctx context.Context) <-chan event.Event {
+		return runner.RunStream(ctx, req.Input)
+	})
+}
+
+// serveAgentRun starts an agent run.
+func (h *Handler) serveAgentRun(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	entry, ok := h.agents[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, CodeNotFound, "agent not found: "+name)
+		return
+	}
+	var req AgentRunRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	messages := req.Messages
+	if req.Input != "" {
+		messages = append(slices.Clip(messages), ai.Message{Role: ai.RoleUser, Content: req.Input})
+	}
+	if len(messages) == 0 {
+		writeError(w, http.StatusBadRequest, CodeInvalidRequest, "messages or input is required")
+		return
+	}
+	h.start(w, KindAgent, name, func(ctx context.Context) <-chan event.Event {
+		return entry.runner.RunStream(ctx, messages, entry.opts...)
+	})
+}
+
+// start runs the events of stream in the background as a new run, and
+// answers with the run. The run outlives the request; it stops when
+// cancelled or when its Coordinator drains.
+func (h *Handler) start(w http.ResponseWriter, kind Kind, name string, stream func(ctx context.Context) <-chan event.Event) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	begin := runtime.Begin
+	if h.coordinator != nil {
+		begin = h.coordinator.Begin
+	}
+	ctx, done, err := begin(ctx)
+	if err != nil {
+		cancel(nil)
+		writeError(w, http.StatusServiceUnavailable, CodeUnavailable, "server is shutting down")
+		return
+	}
+
+	run := newRun(kind, name, cancel)
+	if !h.runs.add(run) {
+		done()
+		cancel(nil)
+		writeError(w, http.StatusTooManyRequests, CodeTooManyRuns, "too many active runs")
+		return
+	}
+	go func() {
+		defer done()
+		defer cancel(nil)
+		for e := range stream(ctx) {
+			run.add(e)
+		}
+		run.finish(context.Cause(ctx))
+		h.runs.finish(run)
+	}()
+
+	w.Header().Set("Location", "/v1/runs/"+run.id)
+	writeJSON(w, http.StatusAccepted, run.snapshot())
+}
+
+// serveRun writes a run's status.
+func (h *Handler) serveRun(w http.ResponseWriter, r *http.Request) {
+	if run := h.run(w, r); run != nil {
+		writeJSON(w, http.StatusOK, run.snapshot())
+	}
+}
+
+// serveCancel cancels a running run.
+func (h *Handler) serveCancel(w http.ResponseWriter, r *http.Request) {
+	run := h.run(w, r)
+	if run == nil {
+		return
+	}
+	if !run.stop() {
+		writeError(w, http.StatusConflict, CodeConflict, "run has finished")
+		return
+	}
+	writeJSON(w, http.StatusAccepted, run.snapshot())
+}
+
+// serveEvents streams a run's events as SSE, from the start or after the
+// Last-Event-ID of a reconnecting client, and ends with a "done" event
+// carrying the finished run.
+func (h *Handler) serveEvents(w http.ResponseWriter, r *http.Request) {
+	run := h.run(w, r)
+	if run == nil {
+		return
+	}
+	next := 0
+	if id, err := strconv.Atoi(r.Header.Get("Last-Event-ID")); err == nil && id >= 0 {
+		next = id + 1
+	}
+
+	sw, err := sse.NewWriter(r.Context(), w, sse.WithHeartbeat(heartbeat))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, CodeInternal, err.Error())
+		return
+	}
+	defer sw.Close()
+
+	for {
+		records, changed, finished := run.since(next)
+		for _, rec := range records {
+			if sw.Write(sse.Event{ID: strconv.Itoa(next), Event: string(rec.typ), Data: rec.data}) != nil {
+				return
+			}
+			next++
+		}
+		if finished {
+			sw.WriteJSON("done", run.snapshot())
+			return
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// run returns the run named by the request, or writes 404 Not Found and
+// returns nil.
+func (h *Handler) run(w http.ResponseWriter, r *http.Request) *run {
+	id := r.PathValue("id")
+	run, ok := h.runs.get(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, CodeNotFound, "run not found: "+id)
+		return nil
+	}
+	return run
+}
+
+// decode decodes the request body into v, allowing an empty body. It
+// writes 400 Bad Request and returns false if the body is malformed.
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	err := json.NewDecoder(r.Body).Decode(v)
+	if err == nil || errors.Is(err, io.EOF) {
+		return true
+	}
+	writeError(w, http.StatusBadRequest, CodeInvalidRequest, "invalid request body: "+err.Error())
+	return false
+}
+
+// writeError writes an error response.
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]Error{"error": {Code: code, Message: message}})
+}
+
+// writeJSON writes v as a JSON response with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
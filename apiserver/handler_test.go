@@ -0,0 +1,306 @@
+package apiserver
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/agent"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/runtime"
+	"github.com/spetersoncode/gains/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type greetState struct {
+	Name     string `json:"name"`
+	Greeting string `json:"greeting"`
+}
+
+// testWorkflows registers a greeting workflow, a failing workflow, and a
+// workflow that blocks until cancelled.
+func testWorkflows() *workflow.Registry {
+	registry := workflow.NewRegistry()
+	registry.Register(workflow.NewRunnerJSON[greetState]("greet",
+		workflow.NewFuncStep("greet", func(ctx context.Context, s *greetState) error {
+			s.Greeting = "Hello, " + s.Name
+			return nil
+		})))
+	registry.Register(workflow.NewRunnerJSON[greetState]("fail",
+		workflow.NewFuncStep("fail", func(ctx context.Context, s *greetState) error {
+			return errors.New("boom")
+		})))
+	registry.Register(workflow.NewRunnerJSON[greetState]("block",
+		workflow.NewFuncStep("block", func(ctx context.Context, s *greetState) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})))
+	return registry
+}
+
+// echoAgent answers with the content of the last message.
+type echoAgent struct{}
+
+func (echoAgent) RunStream(ctx context.Context, messages []ai.Message, opts ...agent.Option) <-chan event.Event {
+	content := messages[len(messages)-1].Content
+	ch := make(chan event.Event, 3)
+	ch <- event.Event{Type: event.RunStart}
+	ch <- event.Event{Type: event.MessageDelta, Delta: content}
+	ch <- event.Event{Type: event.RunEnd, Response: &ai.Response{Content: content}}
+	close(ch)
+	return ch
+}
+
+// do sends a request with body, if not empty, to h.
+func do(h http.Handler, method, path, body string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// start starts a run and returns it.
+func start(t *testing.T, h http.Handler, path, body string) Run {
+	t.Helper()
+	rec := do(h, http.MethodPost, path, body)
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	var run Run
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &run))
+	assert.Equal(t, "/v1/runs/"+run.ID, rec.Header().Get("Location"))
+	return run
+}
+
+// sseEvent is an event of an SSE response.
+type sseEvent struct {
+	id, name, data string
+}
+
+// events streams the events of run id, which returns once it finishes.
+func events(t *testing.T, h http.Handler, id string, header ...string) []sseEvent {
+	t.Helper()
+	rec := do(h, http.MethodGet, "/v1/runs/"+id+"/events", "", header...)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var evs []sseEvent
+	var ev sseEvent
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if ev.name != "" || ev.data != "" {
+				evs = append(evs, ev)
+			}
+			ev = sseEvent{}
+		case strings.HasPrefix(line, "id: "):
+			ev.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			ev.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			ev.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+	return evs
+}
+
+// finished waits for run id to finish and returns it.
+func finished(t *testing.T, h http.Handler, id string) Run {
+	t.Helper()
+	evs := events(t, h, id)
+	require.NotEmpty(t, evs)
+	done := evs[len(evs)-1]
+	require.Equal(t, "done", done.name)
+	var run Run
+	require.NoError(t, json.Unmarshal([]byte(done.data), &run))
+	return run
+}
+
+func TestHandler_WorkflowRun(t *testing.T) {
+	h := NewHandler(WithWorkflows(testWorkflows()))
+
+	run := start(t, h, "/v1/workflows/greet/runs", `{"input": {"name": "Ada"}}`)
+	assert.Equal(t, KindWorkflow, run.Kind)
+	assert.Equal(t, "greet", run.Name)
+	assert.Equal(t, StatusRunning, run.Status)
+
+	done := finished(t, h, run.ID)
+	assert.Equal(t, StatusCompleted, done.Status)
+	assert.False(t, done.FinishedAt.IsZero())
+
+	rec := do(h, http.MethodGet, "/v1/runs/"+run.ID, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var got struct {
+		Status Status     `json:"status"`
+		Output greetState `json:"output"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, StatusCompleted, got.Status)
+	assert.Equal(t, "Hello, Ada", got.Output.Greeting)
+}
+
+func TestHandler_WorkflowRunFailed(t *testing.T) {
+	h := NewHandler(WithWorkflows(testWorkflows()))
+
+	run := start(t, h, "/v1/workflows/fail/runs", "")
+	done := finished(t, h, run.ID)
+	assert.Equal(t, StatusFailed, done.Status)
+	assert.Contains(t, done.Error, "boom")
+}
+
+func TestHandler_AgentRun(t *testing.T) {
+	h := NewHandler(WithAgent("echo", echoAgent{}))
+
+	run := start(t, h, "/v1/agents/echo/runs", `{"input": "Hi"}`)
+	assert.Equal(t, KindAgent, run.Kind)
+
+	done := finished(t, h, run.ID)
+	assert.Equal(t, StatusCompleted, done.Status)
+	out, err := json.Marshal(done.Output)
+	require.NoError(t, err)
+	var resp ai.Response
+	require.NoError(t, json.Unmarshal(out, &resp))
+	assert.Equal(t, "Hi", resp.Content)
+}
+
+func TestHandler_Events(t *testing.T) {
+	h := NewHandler(WithAgent("echo", echoAgent{}))
+	run := start(t, h, "/v1/agents/echo/runs", `{"messages": [{"role": "user", "content": "Hi"}]}`)
+
+	evs := events(t, h, run.ID)
+	require.Len(t, evs, 4)
+	assert.Equal(t, sseEvent{id: "0", name: "run_start", data: `{"type":"run_start"}`}, evs[0])
+	assert.Equal(t, sseEvent{id: "1", name: "message_delta", data: `{"type":"message_delta","delta":"Hi"}`}, evs[1])
+	assert.Equal(t, "run_end", evs[2].name)
+	assert.Equal(t, "done", evs[3].name)
+
+	resumed := events(t, h, run.ID, "Last-Event-ID", "1")
+	require.Len(t, resumed, 2)
+	assert.Equal(t, "2", resumed[0].id)
+	assert.Equal(t, "done", resumed[1].name)
+}
+
+func TestHandler_Cancel(t *testing.T) {
+	h := NewHandler(WithWorkflows(testWorkflows()))
+	run := start(t, h, "/v1/workflows/block/runs", `{}`)
+
+	rec := do(h, http.MethodPost, "/v1/runs/"+run.ID+"/cancel", "")
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+
+	done := finished(t, h, run.ID)
+	assert.Equal(t, StatusCancelled, done.Status)
+
+	rec = do(h, http.MethodPost, "/v1/runs/"+run.ID+"/cancel", "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestHandler_MaxRuns(t *testing.T) {
+	h := NewHandler(WithWorkflows(testWorkflows()), WithMaxRuns(1))
+
+	first := start(t, h, "/v1/workflows/greet/runs", "")
+	finished(t, h, first.ID)
+	second := start(t, h, "/v1/workflows/greet/runs", "")
+	finished(t, h, second.ID)
+
+	assert.Equal(t, http.StatusNotFound, do(h, http.MethodGet, "/v1/runs/"+first.ID, "").Code)
+	assert.Equal(t, http.StatusOK, do(h, http.MethodGet, "/v1/runs/"+second.ID, "").Code)
+}
+
+func TestHandler_MaxActiveRuns(t *testing.T) {
+	h := NewHandler(WithWorkflows(testWorkflows()), WithMaxActiveRuns(1))
+
+	blocked := start(t, h, "/v1/workflows/block/runs", "")
+	rec := do(h, http.MethodPost, "/v1/workflows/greet/runs", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Contains(t, rec.Body.String(), CodeTooManyRuns)
+
+	// Finished runs no longer count
+	do(h, http.MethodPost, "/v1/runs/"+blocked.ID+"/cancel", "")
+	finished(t, h, blocked.ID)
+	run := start(t, h, "/v1/workflows/greet/runs", "")
+	assert.Equal(t, StatusCompleted, finished(t, h, run.ID).Status)
+}
+
+func TestHandler_Coordinator(t *testing.T) {
+	c := runtime.New(runtime.WithGracePeriod(time.Second))
+	h := NewHandler(WithWorkflows(testWorkflows()), WithCoordinator(c))
+
+	run := start(t, h, "/v1/workflows/block/runs", "")
+	assert.Equal(t, 1, c.Active())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, c.Drain(ctx), context.DeadlineExceeded)
+	assert.Equal(t, StatusFailed, finished(t, h, run.ID).Status)
+
+	rec := do(h, http.MethodPost, "/v1/workflows/greet/runs", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.False(t, runtime.Draining(), "the process-wide coordinator is untouched")
+}
+
+func TestHandler_List(t *testing.T) {
+	h := NewHandler(WithWorkflows(testWorkflows()), WithAgent("echo", echoAgent{}))
+
+	rec := do(h, http.MethodGet, "/v1/workflows", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var workflows struct {
+		Workflows []WorkflowInfo `json:"workflows"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &workflows))
+	require.Len(t, workflows.Workflows, 3)
+	assert.Equal(t, "block", workflows.Workflows[0].Name)
+	assert.Contains(t, string(workflows.Workflows[0].InputSchema), `"name"`)
+
+	rec = do(h, http.MethodGet, "/v1/agents", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"agents": [{"name": "echo"}]}`, rec.Body.String())
+}
+
+func TestHandler_Errors(t *testing.T) {
+	h := NewHandler(WithWorkflows(testWorkflows()), WithAgent("echo", echoAgent{}))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		{"unknown workflow", http.MethodPost, "/v1/workflows/missing/runs", "", http.StatusNotFound, CodeNotFound},
+		{"unknown agent", http.MethodPost, "/v1/agents/missing/runs", "", http.StatusNotFound, CodeNotFound},
+		{"malformed body", http.MethodPost, "/v1/workflows/greet/runs", "{", http.StatusBadRequest, CodeInvalidRequest},
+		{"no messages", http.MethodPost, "/v1/agents/echo/runs", "{}", http.StatusBadRequest, CodeInvalidRequest},
+		{"unknown run", http.MethodGet, "/v1/runs/missing", "", http.StatusNotFound, CodeNotFound},
+		{"unknown run events", http.MethodGet, "/v1/runs/missing/events", "", http.StatusNotFound, CodeNotFound},
+		{"unknown run cancel", http.MethodPost, "/v1/runs/missing/cancel", "", http.StatusNotFound, CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(h, tt.method, tt.path, tt.body)
+			require.Equal(t, tt.status, rec.Code, rec.Body.String())
+			var body struct {
+				Error Error `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.code, body.Error.Code)
+		})
+	}
+}
+
+func TestHandler_APIKeys(t *testing.T) {
+	h := NewHandler(WithAPIKeys("secret"))
+
+	assert.Equal(t, http.StatusUnauthorized, do(h, http.MethodGet, "/v1/agents", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, do(h, http.MethodGet, "/v1/agents", "", "Authorization", "Bearer wrong").Code)
+	assert.Equal(t, http.StatusOK, do(h, http.MethodGet, "/v1/agents", "", "Authorization", "Bearer secret").Code)
+}
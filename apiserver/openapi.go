@@ -0,0 +1,283 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"unicode"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/schema"
+)
+
+// OpenAPIVersion is the OpenAPI version of the documents of OpenAPI.
+const OpenAPIVersion = "3.1.0"
+
+// schemaDepth is how many times recursive references are expanded when
+// schemas are inlined into the document.
+const schemaDepth = 3
+
+// OpenAPI returns the OpenAPI document of the API, with the agents and
+// workflows served when it is called. Each agent and workflow has its own
+// run operation, named after it, and a workflow's takes its input schema,
+// so clients generated from the document are typed per workflow.
+func (h *Handler) OpenAPI() map[string]any {
+	paths := map[string]any{
+		"/v1/openapi.json": map[string]any{
+			"get": operation("getOpenAPI", "Get the OpenAPI document of the API", nil,
+				response("The OpenAPI document", map[string]any{"type": "object"})),
+		},
+		"/v1/workflows": map[string]any{
+			"get": operation("listWorkflows", "List the workflows", nil,
+				response("The workflows", listSchema("workflows", map[string]any{
+					"type":     "object",
+					"required": []string{"name"},
+					"properties": map[string]any{
+						"name":        map[string]any{"type": "string"},
+						"inputSchema": map[string]any{"type": "object"},
+					},
+				}))),
+		},
+		"/v1/agents": map[string]any{
+			"get": operation("listAgents", "List the agents", nil,
+				response("The agents", listSchema("agents", map[string]any{
+					"type":       "object",
+					"required":   []string{"name"},
+					"properties": map[string]any{"name": map[string]any{"type": "string"}},
+				}))),
+		},
+		"/v1/runs/{id}": map[string]any{
+			"parameters": []any{runIDParameter},
+			"get": operation("getRun", "Get a run's status and output", nil,
+				response("The run", ref("Run")), errorResponse("404")),
+		},
+		"/v1/runs/{id}/events": map[string]any{
+			"parameters": []any{runIDParameter},
+			"get": withEventStream(operation("streamRunEvents",
+				"Stream a run's events as Server-Sent Events. Each event is named by its type, "+
+					"identified by its index, and carries a JSON object; a final \"done\" event "+
+					"carries the finished run. Reconnect with Last-Event-ID to resume.",
+				nil, errorResponse("404"))),
+		},
+		"/v1/runs/{id}/cancel": map[string]any{
+			"parameters": []any{runIDParameter},
+			"post": operation("cancelRun", "Cancel a running run", nil,
+				accepted("The run, stopping"), errorResponse("404"), errorResponse("409")),
+		},
+	}
+
+	schemas := h.workflows.InputSchemas()
+	for _, name := range h.workflowNames() {
+		input := map[string]any{"description": "The workflow's initial state"}
+		if raw, ok := schemas[name]; ok {
+			var s map[string]any
+			if json.Unmarshal(raw, &s) == nil {
+				input = schema.Flatten(s, schemaDepth)
+			}
+		}
+		body := map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"input": input},
+		}
+		paths["/v1/workflows/"+url.PathEscape(name)+"/runs"] = map[string]any{
+			"post": operation(operationID("runWorkflow", name), "Start a run of the "+name+" workflow", body,
+				accepted("The started run"), errorResponse("400"), errorResponse("429"), errorResponse("503")),
+		}
+	}
+	for _, name := range h.agentNames() {
+		paths["/v1/agents/"+url.PathEscape(name)+"/runs"] = map[string]any{
+			"post": operation(operationID("runAgent", name), "Start a run of the "+name+" agent", ref("AgentRunRequest"),
+				accepted("The started run"), errorResponse("400"), errorResponse("429"), errorResponse("503")),
+		}
+	}
+
+	doc := map[string]any{
+		"openapi": OpenAPIVersion,
+		"info":    map[string]any{"title": h.title, "version": h.version},
+		"paths":   paths,
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"Run":             runSchema,
+				"Event":           eventSchema,
+				"Error":           errorSchema,
+				"Message":         messageSchema(),
+				"AgentRunRequest": agentRunRequestSchema,
+			},
+		},
+	}
+	if h.apiKeys.Enabled() {
+		doc["components"].(map[string]any)["securitySchemes"] = map[string]any{
+			"bearerAuth": map[string]any{"type": "http", "scheme": "bearer"},
+		}
+		doc["security"] = []any{map[string]any{"bearerAuth": []string{}}}
+	}
+	return doc
+}
+
+// operation returns an operation with a JSON request body of schema body,
+// unless nil, and the responses, each a status and response pair. Every
+// operation may answer 401 Unauthorized.
+func operation(id, summary string, body map[string]any, responses ...[2]any) map[string]any {
+	op := map[string]any{"operationId": id, "summary": summary}
+	if body != nil {
+		op["requestBody"] = map[string]any{"content": jsonContent(body)}
+	}
+	rs := map[string]any{"401": errorResponse("401")[1]}
+	for _, r := range responses {
+		rs[r[0].(string)] = r[1]
+	}
+	op["responses"] = rs
+	return op
+}
+
+// withEventStream adds the event stream response to op.
+func withEventStream(op map[string]any) map[string]any {
+	op["responses"].(map[string]any)["200"] = map[string]any{
+		"description": "The run's events",
+		"content": map[string]any{
+			"text/event-stream": map[string]any{"schema": ref("Event")},
+		},
+	}
+	return op
+}
+
+// response returns a 200 OK response with a JSON body of schema s.
+func response(description string, s map[string]any) [2]any {
+	return [2]any{"200", map[string]any{"description": description, "content": jsonContent(s)}}
+}
+
+// accepted returns a 202 Accepted response with a run.
+func accepted(description string) [2]any {
+	return [2]any{"202", map[string]any{"description": description, "content": jsonContent(ref("Run"))}}
+}
+
+// errorResponse returns an error response with status.
+func errorResponse(status string) [2]any {
+	return [2]any{status, map[string]any{"description": "Error", "content": jsonContent(ref("Error"))}}
+}
+
+// jsonContent returns the content of a JSON body of schema s.
+func jsonContent(s map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": s}}
+}
+
+// ref returns a reference to a component schema.
+func ref(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+// listSchema returns the schema of an object listing items under key.
+func listSchema(key string, items map[string]any) map[string]any {
+	return map[string]any{
+		"type":       "object",
+		"required":   []string{key},
+		"properties": map[string]any{key: map[string]any{"type": "array", "items": items}},
+	}
+}
+
+// operationID returns prefix followed by the words of name, as in
+// "runWorkflowDailyReport" for the "daily-report" workflow.
+func operationID(prefix, name string) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// messageSchema returns the schema of a conversation message.
+func messageSchema() map[string]any {
+	s := map[string]any{"type": "object"}
+	if raw, err := ai.SchemaFor[ai.Message](); err == nil {
+		var m map[string]any
+		if json.Unmarshal(raw, &m) == nil {
+			s = schema.Flatten(m, schemaDepth)
+		}
+	}
+	return s
+}
+
+var runIDParameter = map[string]any{
+	"name":     "id",
+	"in":       "path",
+	"required": true,
+	"schema":   map[string]any{"type": "string"},
+}
+
+var runSchema = map[string]any{
+	"type":     "object",
+	"required": []string{"id", "kind", "name", "status", "events", "createdAt"},
+	"properties": map[string]any{
+		"id":     map[string]any{"type": "string"},
+		"kind":   map[string]any{"type": "string", "enum": []string{string(KindAgent), string(KindWorkflow)}},
+		"name":   map[string]any{"type": "string"},
+		"status": map[string]any{"type": "string", "enum": []string{string(StatusRunning), string(StatusCompleted), string(StatusFailed), string(StatusCancelled)}},
+		"output": map[string]any{
+			"description": "The result of a finished run: a workflow's final state or an agent's final response",
+		},
+		"error":      map[string]any{"type": "string", "description": "The error a failed run ended with"},
+		"events":     map[string]any{"type": "integer", "description": "The number of events the run has emitted"},
+		"createdAt":  map[string]any{"type": "string", "format": "date-time"},
+		"finishedAt": map[string]any{"type": "string", "format": "date-time"},
+	},
+}
+
+var eventSchema = map[string]any{
+	"type":        "object",
+	"description": "A gains event. Fields other than type are present when set.",
+	"required":    []string{"type"},
+	"properties": map[string]any{
+		"type":      map[string]any{"type": "string"},
+		"runId":     map[string]any{"type": "string"},
+		"stepName":  map[string]any{"type": "string"},
+		"messageId": map[string]any{"type": "string"},
+		"delta":     map[string]any{"type": "string"},
+		"message":   map[string]any{"type": "string"},
+		"error":     map[string]any{"type": "string"},
+		"response":  map[string]any{"type": "object"},
+		"toolCall":  map[string]any{"type": "object"},
+		"state":     map[string]any{},
+		"timestamp": map[string]any{"type": "string", "format": "date-time"},
+	},
+	"additionalProperties": true,
+}
+
+var errorSchema = map[string]any{
+	"type":     "object",
+	"required": []string{"error"},
+	"properties": map[string]any{
+		"error": map[string]any{
+			"type":     "object",
+			"required": []string{"code", "message"},
+			"properties": map[string]any{
+				"code":    map[string]any{"type": "string"},
+				"message": map[string]any{"type": "string"},
+			},
+		},
+	},
+}
+
+var agentRunRequestSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"messages": map[string]any{
+			"type":        "array",
+			"items":       ref("Message"),
+			"description": "The conversation to continue",
+		},
+		"input": map[string]any{
+			"type":        "string",
+			"description": "Appended to the messages as a user message",
+		},
+	},
+}
@@ -0,0 +1,62 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_OpenAPI(t *testing.T) {
+	h := NewHandler(
+		WithWorkflows(testWorkflows()),
+		WithAgent("echo", echoAgent{}),
+		WithAPIKeys("secret"),
+		WithInfo("Pipelines", "2.0.0"),
+	)
+
+	rec := do(h, http.MethodGet, "/v1/openapi.json", "", "Authorization", "Bearer secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var doc struct {
+		OpenAPI  string                    `json:"openapi"`
+		Info     map[string]any            `json:"info"`
+		Security []any                     `json:"security"`
+		Paths    map[string]map[string]any `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+
+	assert.Equal(t, OpenAPIVersion, doc.OpenAPI)
+	assert.Equal(t, "Pipelines", doc.Info["title"])
+	assert.NotEmpty(t, doc.Security)
+
+	greet := doc.Paths["/v1/workflows/greet/runs"]["post"].(map[string]any)
+	assert.Equal(t, "runWorkflowGreet", greet["operationId"])
+	var body struct {
+		Content struct {
+			JSON struct {
+				Schema struct {
+					Properties struct {
+						Input map[string]any `json:"input"`
+					} `json:"properties"`
+				} `json:"schema"`
+			} `json:"application/json"`
+		} `json:"content"`
+	}
+	raw, err := json.Marshal(greet["requestBody"])
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &body))
+	assert.Contains(t, body.Content.JSON.Schema.Properties.Input["properties"], "name")
+
+	echo := doc.Paths["/v1/agents/echo/runs"]["post"].(map[string]any)
+	assert.Equal(t, "runAgentEcho", echo["operationId"])
+	for _, path := range []string{"/v1/runs/{id}", "/v1/runs/{id}/events", "/v1/runs/{id}/cancel"} {
+		assert.Contains(t, doc.Paths, path)
+	}
+}
+
+func TestOperationID(t *testing.T) {
+	assert.Equal(t, "runWorkflowDailyReport", operationID("runWorkflow", "daily-report"))
+	assert.Equal(t, "runAgentSupportBot2", operationID("runAgent", "support bot_2"))
+}
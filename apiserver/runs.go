@@ -0,0 +1,183 @@
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+)
+
+// errRunCancelled is the cancellation cause of runs stopped by a cancel
+// request, distinguishing them from runs stopped by draining.
+var errRunCancelled = errors.New("apiserver: run cancelled")
+
+// record is an event of a run in its JSON form.
+type record struct {
+	typ  event.Type
+	data []byte
+}
+
+// run is a run in progress or finished, with its events.
+type run struct {
+	id string
+
+	mu      sync.Mutex
+	info    Run
+	records []record
+	changed chan struct{} // closed and replaced when records or info change
+	cancel  context.CancelCauseFunc
+}
+
+// newRun creates a running run that cancel stops.
+func newRun(kind Kind, name string, cancel context.CancelCauseFunc) *run {
+	id := ai.NewID()
+	return &run{
+		id: id,
+		info: Run{
+			ID:        id,
+			Kind:      kind,
+			Name:      name,
+			Status:    StatusRunning,
+			CreatedAt: time.Now(),
+		},
+		changed: make(chan struct{}),
+		cancel:  cancel,
+	}
+}
+
+// add records e. The final state or response of the outermost run becomes
+// the output, and an error the run's error.
+func (r *run) add(e event.Event) {
+	data, err := event.Marshal(e)
+	if err != nil {
+		data, _ = event.Marshal(event.Event{Type: e.Type, StepName: e.StepName, Error: err})
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, record{typ: e.Type, data: data})
+	r.info.Events = len(r.records)
+	switch e.Type {
+	case event.RunEnd:
+		if e.State != nil {
+			r.info.Output = e.State
+		} else if e.Response != nil {
+			r.info.Output = e.Response
+		}
+	case event.RunError:
+		if e.Error != nil {
+			r.info.Error = e.Error.Error()
+		}
+	}
+	r.notify()
+}
+
+// finish ends the run, whose context was cancelled with cause, if any.
+func (r *run) finish(cause error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch {
+	case errors.Is(cause, errRunCancelled):
+		r.info.Status = StatusCancelled
+	case r.info.Error != "":
+		r.info.Status = StatusFailed
+	default:
+		r.info.Status = StatusCompleted
+	}
+	r.info.FinishedAt = time.Now()
+	r.cancel = nil
+	r.notify()
+}
+
+// stop cancels the run. It reports false if the run has finished.
+func (r *run) stop() bool {
+	r.mu.Lock()
+	cancel := r.cancel
+	r.mu.Unlock()
+	if cancel == nil {
+		return false
+	}
+	cancel(errRunCancelled)
+	return true
+}
+
+// snapshot returns the run's status.
+func (r *run) snapshot() Run {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.info
+}
+
+// since returns the records from index next on, a channel closed when
+// more arrive, and whether the run has finished.
+func (r *run) since(next int) ([]record, <-chan struct{}, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var records []record
+	if next < len(r.records) {
+		records = r.records[next:len(r.records):len(r.records)]
+	}
+	return records, r.changed, r.info.Status != StatusRunning
+}
+
+// notify wakes the streams waiting for changes. r.mu must be held.
+func (r *run) notify() {
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// runStore keeps the runs of a Handler in memory. Finished runs beyond
+// the limit are forgotten, oldest first.
+type runStore struct {
+	mu       sync.Mutex
+	runs     map[string]*run
+	finished []string
+	max      int
+
+	// active counts running runs, up to maxActive if positive
+	active    int
+	maxActive int
+}
+
+// newRunStore creates a store keeping at most max finished runs and
+// maxActive running ones.
+func newRunStore(max, maxActive int) *runStore {
+	return &runStore{runs: make(map[string]*run), max: max, maxActive: maxActive}
+}
+
+// add registers a running run, reporting false without registering it if
+// the limit on running runs is reached.
+func (s *runStore) add(r *run) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.maxActive > 0 && s.active >= s.maxActive {
+		return false
+	}
+	s.active++
+	s.runs[r.id] = r
+	return true
+}
+
+// get returns run id.
+func (s *runStore) get(id string) (*run, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.runs[id]
+	return r, ok
+}
+
+// finish records that r has finished, forgetting the oldest finished runs
+// beyond the limit.
+func (s *runStore) finish(r *run) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.active--
+	s.finished = append(s.finished, r.id)
+	for s.max > 0 && len(s.finished) > s.max {
+		delete(s.runs, s.finished[0])
+		s.finished = s.finished[1:]
+	}
+}
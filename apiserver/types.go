@@ -0,0 +1,91 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// Kind is what a run executes.
+type Kind string
+
+const (
+	// KindAgent is a run of an agent.
+	KindAgent Kind = "agent"
+	// KindWorkflow is a run of a workflow.
+	KindWorkflow Kind = "workflow"
+)
+
+// Status is the state of a run.
+type Status string
+
+const (
+	// StatusRunning is a run in progress.
+	StatusRunning Status = "running"
+	// StatusCompleted is a run that finished without error.
+	StatusCompleted Status = "completed"
+	// StatusFailed is a run that ended with an error.
+	StatusFailed Status = "failed"
+	// StatusCancelled is a run stopped by a cancel request.
+	StatusCancelled Status = "cancelled"
+)
+
+// Error codes of error responses.
+const (
+	CodeInvalidRequest = "invalid_request"
+	CodeUnauthorized   = "unauthorized"
+	CodeNotFound       = "not_found"
+	CodeConflict       = "conflict"
+	CodeTooManyRuns    = "too_many_runs"
+	CodeUnavailable    = "unavailable"
+	CodeInternal       = "internal"
+)
+
+// Run is the status of a run, as answered by the run endpoints.
+type Run struct {
+	ID     string `json:"id"`
+	Kind   Kind   `json:"kind"`
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+	// Output is the result of a finished run: a workflow's final state or
+	// an agent's final response.
+	Output any `json:"output,omitempty"`
+	// Error is the message of the error a failed run ended with.
+	Error string `json:"error,omitempty"`
+	// Events is the number of events the run has emitted.
+	Events     int       `json:"events"`
+	CreatedAt  time.Time `json:"createdAt"`
+	FinishedAt time.Time `json:"finishedAt,omitzero"`
+}
+
+// WorkflowRunRequest is the body of a workflow run request.
+type WorkflowRunRequest struct {
+	// Input is the workflow's initial state. Omitted, the state is zero.
+	Input any `json:"input,omitempty"`
+}
+
+// AgentRunRequest is the body of an agent run request.
+type AgentRunRequest struct {
+	// Messages is the conversation to continue.
+	Messages []ai.Message `json:"messages,omitempty"`
+	// Input is appended to the messages as a user message.
+	Input string `json:"input,omitempty"`
+}
+
+// WorkflowInfo describes a served workflow.
+type WorkflowInfo struct {
+	Name        string          `json:"name"`
+	InputSchema json.RawMessage `json:"inputSchema,omitempty"`
+}
+
+// AgentInfo describes a served agent.
+type AgentInfo struct {
+	Name string `json:"name"`
+}
+
+// Error is the body of an error response, under the "error" key.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
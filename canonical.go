@@ -0,0 +1,130 @@
+package gains
+
+// leadingUserContent is the content of the user message inserted before a
+// conversation that starts with an assistant message.
+const leadingUserContent = "(conversation start)"
+
+// CanonicalMessages returns messages in a shape every provider accepts,
+// without modifying the input. Providers apply it before converting a
+// request, so histories assembled by hand or trimmed by a memory strategy
+// do not fail with an opaque 400 from the API.
+//
+// The pass:
+//   - drops messages with no content, parts, tool calls, or tool results
+//   - drops tool results that answer no earlier tool call, and tool calls
+//     that are never answered
+//   - merges consecutive messages of the same role, joining their text
+//   - inserts a user message before a conversation whose first non-system
+//     message is from the assistant
+//
+// Message IDs, names, and metadata of merged messages are taken from the
+// first of them.
+func CanonicalMessages(messages []Message) []Message {
+	answered := make(map[string]bool) // tool call ID → has a result
+	called := make(map[string]bool)
+	for _, msg := range messages {
+		for _, tc := range msg.ToolCalls {
+			called[tc.ID] = true
+		}
+		for _, tr := range msg.ToolResults {
+			if called[tr.ToolCallID] {
+				answered[tr.ToolCallID] = true
+			}
+		}
+	}
+
+	result := make([]Message, 0, len(messages))
+	for _, msg := range messages {
+		msg = pruneToolMessage(msg, answered)
+		if isEmptyMessage(msg) {
+			continue
+		}
+
+		if n := len(result); n > 0 && result[n-1].Role == msg.Role {
+			result[n-1] = mergeMessages(result[n-1], msg)
+			continue
+		}
+
+		if msg.Role == RoleAssistant && !hasConversation(result) {
+			result = append(result, Message{Role: RoleUser, Content: leadingUserContent})
+		}
+		result = append(result, msg)
+	}
+	return result
+}
+
+// pruneToolMessage removes unanswered tool calls and tool results without a
+// call from msg.
+func pruneToolMessage(msg Message, answered map[string]bool) Message {
+	if len(msg.ToolCalls) > 0 {
+		calls := make([]ToolCall, 0, len(msg.ToolCalls))
+		for _, tc := range msg.ToolCalls {
+			if answered[tc.ID] {
+				calls = append(calls, tc)
+			}
+		}
+		msg.ToolCalls = calls
+	}
+	if len(msg.ToolResults) > 0 {
+		results := make([]ToolResult, 0, len(msg.ToolResults))
+		for _, tr := range msg.ToolResults {
+			if answered[tr.ToolCallID] {
+				results = append(results, tr)
+				answered[tr.ToolCallID] = false // a second result is an orphan
+			}
+		}
+		msg.ToolResults = results
+	}
+	return msg
+}
+
+// isEmptyMessage reports whether msg has nothing to send.
+func isEmptyMessage(msg Message) bool {
+	return msg.Content == "" && len(msg.Parts) == 0 && len(msg.ToolCalls) == 0 && len(msg.ToolResults) == 0
+}
+
+// hasConversation reports whether messages include a non-system message.
+func hasConversation(messages []Message) bool {
+	for _, msg := range messages {
+		if msg.Role != RoleSystem {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeMessages joins next into prev, which have the same role.
+func mergeMessages(prev, next Message) Message {
+	if prev.HasParts() || next.HasParts() {
+		prev.Parts = append(messageParts(prev), messageParts(next)...)
+		prev.Content = ""
+	} else {
+		prev.Content = joinText(prev.Content, next.Content)
+	}
+	prev.ToolCalls = append(prev.ToolCalls[:len(prev.ToolCalls):len(prev.ToolCalls)], next.ToolCalls...)
+	prev.ToolResults = append(prev.ToolResults[:len(prev.ToolResults):len(prev.ToolResults)], next.ToolResults...)
+	return prev
+}
+
+// messageParts returns the content of msg as parts, converting plain
+// Content to a text part.
+func messageParts(msg Message) []ContentPart {
+	if msg.HasParts() {
+		return msg.Parts[:len(msg.Parts):len(msg.Parts)]
+	}
+	if msg.Content == "" {
+		return nil
+	}
+	return []ContentPart{NewTextPart(msg.Content)}
+}
+
+// joinText joins the text of two merged messages with a blank line.
+func joinText(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	}
+	return a + "\n\n" + b
+}
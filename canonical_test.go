@@ -0,0 +1,120 @@
+package gains
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalMessages(t *testing.T) {
+	call := func(id string) ToolCall { return ToolCall{ID: id, Name: "search", Arguments: "{}"} }
+	result := func(id string) ToolResult { return ToolResult{ToolCallID: id, Content: "ok"} }
+
+	tests := []struct {
+		name string
+		in   []Message
+		want []Message
+	}{
+		{
+			name: "already canonical",
+			in: []Message{
+				{Role: RoleSystem, Content: "Be brief."},
+				{Role: RoleUser, Content: "Hi"},
+				{Role: RoleAssistant, ToolCalls: []ToolCall{call("1")}},
+				{Role: RoleTool, ToolResults: []ToolResult{result("1")}},
+				{Role: RoleAssistant, Content: "Done"},
+			},
+			want: []Message{
+				{Role: RoleSystem, Content: "Be brief."},
+				{Role: RoleUser, Content: "Hi"},
+				{Role: RoleAssistant, ToolCalls: []ToolCall{call("1")}},
+				{Role: RoleTool, ToolResults: []ToolResult{result("1")}},
+				{Role: RoleAssistant, Content: "Done"},
+			},
+		},
+		{
+			name: "drops empty messages and merges neighbours",
+			in: []Message{
+				{Role: RoleUser, Content: "Hi", ID: "u1"},
+				{Role: RoleAssistant},
+				{Role: RoleUser, Content: "Are you there?", ID: "u2"},
+			},
+			want: []Message{
+				{Role: RoleUser, Content: "Hi\n\nAre you there?", ID: "u1"},
+			},
+		},
+		{
+			name: "merges text into parts",
+			in: []Message{
+				{Role: RoleUser, Content: "Look:"},
+				{Role: RoleUser, Parts: []ContentPart{NewImageURLPart("https://example.com/a.png")}},
+			},
+			want: []Message{
+				{Role: RoleUser, Parts: []ContentPart{
+					NewTextPart("Look:"),
+					NewImageURLPart("https://example.com/a.png"),
+				}},
+			},
+		},
+		{
+			name: "merges split tool results",
+			in: []Message{
+				{Role: RoleUser, Content: "Search twice"},
+				{Role: RoleAssistant, ToolCalls: []ToolCall{call("1"), call("2")}},
+				{Role: RoleTool, ToolResults: []ToolResult{result("1")}},
+				{Role: RoleTool, ToolResults: []ToolResult{result("2")}},
+			},
+			want: []Message{
+				{Role: RoleUser, Content: "Search twice"},
+				{Role: RoleAssistant, ToolCalls: []ToolCall{call("1"), call("2")}},
+				{Role: RoleTool, ToolResults: []ToolResult{result("1"), result("2")}},
+			},
+		},
+		{
+			name: "drops orphan tool results and unanswered calls",
+			in: []Message{
+				{Role: RoleTool, ToolResults: []ToolResult{result("0")}},
+				{Role: RoleUser, Content: "Search"},
+				{Role: RoleAssistant, Content: "Searching", ToolCalls: []ToolCall{call("1"), call("2")}},
+				{Role: RoleTool, ToolResults: []ToolResult{result("1"), result("1")}},
+				{Role: RoleAssistant, ToolCalls: []ToolCall{call("3")}},
+			},
+			want: []Message{
+				{Role: RoleUser, Content: "Search"},
+				{Role: RoleAssistant, Content: "Searching", ToolCalls: []ToolCall{call("1")}},
+				{Role: RoleTool, ToolResults: []ToolResult{result("1")}},
+			},
+		},
+		{
+			name: "assistant first",
+			in: []Message{
+				{Role: RoleSystem, Content: "Be brief."},
+				{Role: RoleAssistant, Content: "How can I help?"},
+				{Role: RoleUser, Content: "Hi"},
+			},
+			want: []Message{
+				{Role: RoleSystem, Content: "Be brief."},
+				{Role: RoleUser, Content: leadingUserContent},
+				{Role: RoleAssistant, Content: "How can I help?"},
+				{Role: RoleUser, Content: "Hi"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CanonicalMessages(tt.in))
+		})
+	}
+}
+
+func TestCanonicalMessagesDoesNotModifyInput(t *testing.T) {
+	in := []Message{
+		{Role: RoleUser, Parts: []ContentPart{NewTextPart("a")}},
+		{Role: RoleUser, Content: "b"},
+		{Role: RoleAssistant, ToolCalls: []ToolCall{{ID: "1"}}},
+	}
+	CanonicalMessages(in)
+	assert.Len(t, in[0].Parts, 1)
+	assert.Len(t, in[2].ToolCalls, 1)
+}
@@ -12,3 +12,19 @@ type ChatProvider interface {
 	// Callers should check StreamEvent.Err for any errors.
 	ChatStream(ctx context.Context, messages []Message, opts ...Option) (<-chan StreamEvent, error)
 }
+
+// TokenCounter is implemented by chat providers that can count the input
+// tokens of a request with the model's own tokenizer.
+type TokenCounter interface {
+	// CountTokens returns the number of input tokens messages and the tools
+	// set in opts would take for the model set in opts.
+	CountTokens(ctx context.Context, messages []Message, opts ...Option) (int, error)
+}
+
+// ModelResolver is implemented by chat providers that choose the model of a
+// request themselves, falling back to a default or resolving aliases, so
+// callers can price a request before sending it.
+type ModelResolver interface {
+	// ChatModel returns the model a chat request with opts would use.
+	ChatModel(opts ...Option) (Model, error)
+}
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"sync"
+)
+
+// Priority orders requests waiting for admission when the client's
+// concurrency limit is reached.
+type Priority int
+
+const (
+	// PriorityInteractive is for user-facing traffic such as chat. It is the
+	// default for requests without a priority.
+	PriorityInteractive Priority = iota
+	// PriorityBackground is for bulk work such as embedding backfills or
+	// evaluation runs. Background requests wait until no interactive
+	// requests are queued.
+	PriorityBackground
+)
+
+// String returns the priority name.
+func (p Priority) String() string {
+	switch p {
+	case PriorityInteractive:
+		return "interactive"
+	case PriorityBackground:
+		return "background"
+	default:
+		return "unknown"
+	}
+}
+
+// priorityKey is the context key for request priority.
+type priorityKey struct{}
+
+// WithPriority returns a context that marks client requests made with it as
+// having priority p. Priority only matters when admission control is enabled
+// with WithAdmissionControl.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the request priority stored in ctx, or
+// PriorityInteractive if none is set.
+func PriorityFromContext(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	return PriorityInteractive
+}
+
+// WithAdmissionControl limits the client to maxConcurrent in-flight provider
+// requests across all operations. Requests beyond the limit wait in a queue;
+// when a slot frees up, waiting interactive requests are admitted before
+// background ones, and requests of equal priority are admitted in arrival
+// order. Streaming requests hold their slot until the stream ends. A waiting
+// request returns the context's error if it is cancelled.
+//
+// Mark bulk work with WithPriority so it queues behind user-facing traffic
+// sharing the same client and rate limits:
+//
+//	ctx = client.WithPriority(ctx, client.PriorityBackground)
+//	resp, err := c.Embed(ctx, texts)
+func WithAdmissionControl(maxConcurrent int) ClientOption {
+	return func(c *Client) {
+		if maxConcurrent > 0 {
+			c.admission = newAdmissionController(maxConcurrent)
+		}
+	}
+}
+
+// admissionController is a priority-aware counting semaphore.
+type admissionController struct {
+	mu      sync.Mutex
+	limit   int
+	active  int
+	waiters [PriorityBackground + 1][]chan struct{}
+}
+
+func newAdmissionController(limit int) *admissionController {
+	return &admissionController{limit: limit}
+}
+
+// acquire blocks until a slot is available for a request of priority p or
+// ctx is done. On success the caller must call the returned release func
+// exactly once.
+func (a *admissionController) acquire(ctx context.Context, p Priority) (func(), error) {
+	if p < PriorityInteractive || p > PriorityBackground {
+		p = PriorityBackground
+	}
+
+	a.mu.Lock()
+	if a.active < a.limit && !a.hasWaitersAtOrAbove(p) {
+		a.active++
+		a.mu.Unlock()
+		return a.releaseFunc(), nil
+	}
+	ready := make(chan struct{})
+	a.waiters[p] = append(a.waiters[p], ready)
+	a.mu.Unlock()
+
+	select {
+	case <-ready:
+		return a.releaseFunc(), nil
+	case <-ctx.Done():
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		if a.removeWaiter(p, ready) {
+			return nil, ctx.Err()
+		}
+		// The slot was handed over while we were cancelling; pass it on.
+		a.handOff()
+		return nil, ctx.Err()
+	}
+}
+
+// hasWaitersAtOrAbove reports whether any request of priority p or higher
+// is queued. The caller must hold a.mu.
+func (a *admissionController) hasWaitersAtOrAbove(p Priority) bool {
+	for q := PriorityInteractive; q <= p; q++ {
+		if len(a.waiters[q]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// removeWaiter removes ready from the queue for p, reporting whether it was
+// still queued. The caller must hold a.mu.
+func (a *admissionController) removeWaiter(p Priority, ready chan struct{}) bool {
+	for i, w := range a.waiters[p] {
+		if w == ready {
+			a.waiters[p] = append(a.waiters[p][:i], a.waiters[p][i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// handOff gives a held slot to the highest-priority waiter, or frees it if
+// none are queued. The caller must hold a.mu.
+func (a *admissionController) handOff() {
+	for p := range a.waiters {
+		if len(a.waiters[p]) > 0 {
+			next := a.waiters[p][0]
+			a.waiters[p] = a.waiters[p][1:]
+			close(next)
+			return
+		}
+	}
+	a.active--
+}
+
+func (a *admissionController) releaseFunc() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			a.mu.Lock()
+			defer a.mu.Unlock()
+			a.handOff()
+		})
+	}
+}
+
+// admit waits for an admission slot if admission control is enabled.
+// The returned release func is never nil.
+func (c *Client) admit(ctx context.Context) (func(), error) {
+	if c.admission == nil {
+		return func() {}, nil
+	}
+	return c.admission.acquire(ctx, PriorityFromContext(ctx))
+}
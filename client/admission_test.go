@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriorityFromContext(t *testing.T) {
+	assert.Equal(t, PriorityInteractive, PriorityFromContext(context.Background()))
+
+	ctx := WithPriority(context.Background(), PriorityBackground)
+	assert.Equal(t, PriorityBackground, PriorityFromContext(ctx))
+	assert.Equal(t, "background", PriorityBackground.String())
+}
+
+func TestAdmissionController(t *testing.T) {
+	t.Run("admits up to the limit", func(t *testing.T) {
+		a := newAdmissionController(2)
+		r1, err := a.acquire(context.Background(), PriorityInteractive)
+		require.NoError(t, err)
+		r2, err := a.acquire(context.Background(), PriorityBackground)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err = a.acquire(ctx, PriorityInteractive)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		r1()
+		r2()
+		assert.Equal(t, 0, a.active)
+	})
+
+	t.Run("admits interactive before background", func(t *testing.T) {
+		a := newAdmissionController(1)
+		release, err := a.acquire(context.Background(), PriorityInteractive)
+		require.NoError(t, err)
+
+		order := make(chan Priority, 2)
+		wait := func(p Priority) {
+			r, err := a.acquire(context.Background(), p)
+			if err == nil {
+				order <- p
+				r()
+			}
+		}
+		go wait(PriorityBackground)
+		require.Eventually(t, func() bool { return queued(a, PriorityBackground) == 1 }, time.Second, time.Millisecond)
+		go wait(PriorityInteractive)
+		require.Eventually(t, func() bool { return queued(a, PriorityInteractive) == 1 }, time.Second, time.Millisecond)
+
+		release()
+		assert.Equal(t, PriorityInteractive, <-order)
+		assert.Equal(t, PriorityBackground, <-order)
+	})
+
+	t.Run("cancelled waiters leave the queue", func(t *testing.T) {
+		a := newAdmissionController(1)
+		release, err := a.acquire(context.Background(), PriorityInteractive)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() {
+			_, err := a.acquire(ctx, PriorityBackground)
+			done <- err
+		}()
+		require.Eventually(t, func() bool { return queued(a, PriorityBackground) == 1 }, time.Second, time.Millisecond)
+		cancel()
+		assert.ErrorIs(t, <-done, context.Canceled)
+
+		release()
+		assert.Equal(t, 0, a.active)
+		assert.Equal(t, 0, queued(a, PriorityBackground))
+	})
+
+	t.Run("release is idempotent", func(t *testing.T) {
+		a := newAdmissionController(1)
+		release, err := a.acquire(context.Background(), PriorityInteractive)
+		require.NoError(t, err)
+		release()
+		release()
+		assert.Equal(t, 0, a.active)
+	})
+}
+
+func TestClientAdmit(t *testing.T) {
+	c := New(Config{})
+	release, err := c.admit(context.Background())
+	require.NoError(t, err)
+	release()
+
+	c = New(Config{}, WithAdmissionControl(1))
+	require.NotNil(t, c.admission)
+}
+
+// queued returns the number of requests waiting at priority p.
+func queued(a *admissionController, p Priority) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.waiters[p])
+}
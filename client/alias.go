@@ -0,0 +1,96 @@
+package client
+
+import (
+	"fmt"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/model"
+)
+
+// ErrUnknownAlias is returned when a request names a model.Alias that is
+// neither in Config.Aliases nor in model.DefaultAliases, or whose aliases
+// refer to each other in a cycle.
+type ErrUnknownAlias struct {
+	Alias string
+	// Cycle is set if the alias resolves to itself.
+	Cycle bool
+}
+
+func (e *ErrUnknownAlias) Error() string {
+	if e.Cycle {
+		return fmt.Sprintf("model alias %q refers to itself", e.Alias)
+	}
+	return fmt.Sprintf("unknown model alias %q: add it to client.Config Aliases", e.Alias)
+}
+
+// ErrModelDeprecated describes a deprecated model in EventModelDeprecated
+// events. Requests using the model are still sent.
+type ErrModelDeprecated struct {
+	Deprecation model.Deprecation
+}
+
+func (e *ErrModelDeprecated) Error() string {
+	return "model " + e.Deprecation.String()
+}
+
+// ResolveModel returns the model m selects: the model a model.Alias maps
+// to in Config.Aliases or model.DefaultAliases, following aliases of
+// aliases, or m itself.
+func (c *Client) ResolveModel(m ai.Model) (ai.Model, error) {
+	seen := make(map[model.Alias]bool)
+	for {
+		alias, ok := m.(model.Alias)
+		if !ok {
+			return m, nil
+		}
+		if seen[alias] {
+			return nil, &ErrUnknownAlias{Alias: string(alias), Cycle: true}
+		}
+		seen[alias] = true
+		target, ok := c.aliases[string(alias)]
+		if !ok || target == nil {
+			return nil, &ErrUnknownAlias{Alias: string(alias)}
+		}
+		m = target
+	}
+}
+
+// ChatModel returns the model a chat request with opts would use: the
+// model set with ai.WithModel, or Config.Defaults.Chat, resolved with
+// ResolveModel. It implements ai.ModelResolver. A fallback model may still
+// replace it if it lacks a capability the request needs.
+func (c *Client) ChatModel(opts ...ai.Option) (ai.Model, error) {
+	m := ai.ApplyOptions(append(append([]ai.Option{}, c.defaultChatOpts...), opts...)...).Model
+	if m == nil {
+		m = c.defaults.Chat
+	}
+	if m == nil {
+		return nil, &ErrNoModel{Operation: "chat"}
+	}
+	return c.ResolveModel(m)
+}
+
+// resolveModel resolves m for operation with ResolveModel and reports
+// whether it was an alias, so the resolved model must be passed to the
+// provider in its place. It emits EventModelDeprecated the first time the
+// client uses a model its provider has deprecated.
+func (c *Client) resolveModel(operation string, m ai.Model) (ai.Model, bool, error) {
+	resolved, err := c.ResolveModel(m)
+	if err != nil {
+		return nil, false, err
+	}
+	_, aliased := m.(model.Alias)
+
+	if d, ok := model.Deprecated(resolved); ok {
+		if _, warned := c.deprecations.LoadOrStore(d.Provider.String()+"/"+d.Model, true); !warned {
+			c.emit(Event{
+				Type:      EventModelDeprecated,
+				Operation: operation,
+				Provider:  resolved.Provider(),
+				Model:     resolved.String(),
+				Error:     &ErrModelDeprecated{Deprecation: d},
+			})
+		}
+	}
+	return resolved, aliased, nil
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// modelProvider answers each chat with the ID of the model it was sent.
+type modelProvider struct{}
+
+func (modelProvider) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	return &ai.Response{Content: ai.ApplyOptions(opts...).Model.String()}, nil
+}
+
+func (modelProvider) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan ai.StreamEvent, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestClient_ResolveModel(t *testing.T) {
+	c := New(Config{Aliases: map[string]ai.Model{
+		"cheap-chat": model.GPT5Nano,
+		"fast":       model.Alias("cheap-chat"),
+		"loop":       model.Alias("loop"),
+		"gpt-latest": model.GPT51,
+	}})
+
+	tests := []struct {
+		name  string
+		model ai.Model
+		want  ai.Model
+	}{
+		{"not an alias", model.ClaudeHaiku45, model.ClaudeHaiku45},
+		{"configured", model.Alias("cheap-chat"), model.GPT5Nano},
+		{"alias of alias", model.Alias("fast"), model.GPT5Nano},
+		{"default", model.Alias("claude-latest"), model.DefaultClaudeModel},
+		{"overridden default", model.Alias("gpt-latest"), model.GPT51},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.ResolveModel(tt.model)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := c.ResolveModel(model.Alias("nope"))
+		var unknown *ErrUnknownAlias
+		require.ErrorAs(t, err, &unknown)
+		assert.Equal(t, "nope", unknown.Alias)
+		assert.False(t, unknown.Cycle)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		_, err := c.ResolveModel(model.Alias("loop"))
+		var unknown *ErrUnknownAlias
+		require.ErrorAs(t, err, &unknown)
+		assert.True(t, unknown.Cycle)
+	})
+}
+
+func TestClient_ChatAlias(t *testing.T) {
+	c := New(Config{
+		Defaults: Defaults{Chat: model.Alias("cheap-chat")},
+		Aliases:  map[string]ai.Model{"cheap-chat": model.ClaudeHaiku45, "smart": model.ClaudeOpus45},
+	}, WithChatProvider(ai.ProviderAnthropic, modelProvider{}))
+
+	resp, err := c.Chat(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "hi"}})
+	require.NoError(t, err)
+	assert.Equal(t, "claude-haiku-4-5", resp.Content)
+
+	resp, err = c.Chat(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "hi"}}, ai.WithModel(model.Alias("smart")))
+	require.NoError(t, err)
+	assert.Equal(t, "claude-opus-4-5", resp.Content)
+
+	_, err = c.Chat(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "hi"}}, ai.WithModel(model.Alias("nope")))
+	var unknown *ErrUnknownAlias
+	assert.ErrorAs(t, err, &unknown)
+}
+
+func TestClient_ChatModel(t *testing.T) {
+	c := New(Config{
+		Defaults: Defaults{Chat: model.Alias("cheap-chat")},
+		Aliases:  map[string]ai.Model{"cheap-chat": model.ClaudeHaiku45},
+	})
+
+	m, err := c.ChatModel()
+	require.NoError(t, err)
+	assert.Equal(t, model.ClaudeHaiku45, m)
+
+	m, err = c.ChatModel(ai.WithModel(model.ClaudeOpus45))
+	require.NoError(t, err)
+	assert.Equal(t, model.ClaudeOpus45, m)
+
+	_, err = New(Config{}).ChatModel()
+	var noModel *ErrNoModel
+	assert.ErrorAs(t, err, &noModel)
+}
+
+func TestClient_ModelDeprecated(t *testing.T) {
+	events := make(chan Event, 10)
+	deprecated := testModel{id: "claude-3-opus-20240229", provider: ai.ProviderAnthropic}
+	c := New(Config{Defaults: Defaults{Chat: deprecated}, Events: events},
+		WithChatProvider(ai.ProviderAnthropic, modelProvider{}))
+
+	for range 2 {
+		_, err := c.Chat(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "hi"}})
+		require.NoError(t, err)
+	}
+
+	var warnings []Event
+	for len(events) > 0 {
+		if e := <-events; e.Type == EventModelDeprecated {
+			warnings = append(warnings, e)
+		}
+	}
+	require.Len(t, warnings, 1, "warned once per model")
+	assert.Equal(t, "claude-3-opus-20240229", warnings[0].Model)
+	var depErr *ErrModelDeprecated
+	require.ErrorAs(t, warnings[0].Error, &depErr)
+	assert.Equal(t, "claude-opus-4-5", depErr.Deprecation.Replacement)
+	assert.Contains(t, depErr.Error(), "retires on 2026-01-05")
+}
@@ -0,0 +1,212 @@
+package client
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+)
+
+// WithResponseCache caches the responses of deterministic chat requests,
+// those with a temperature of 0, so identical requests are answered without
+// calling the provider. Requests are identical when their model, messages,
+// and generation options match. Entries expire after ttl, or never if ttl
+// is 0, and the least recently used entries are evicted beyond maxEntries
+// (default 1000).
+//
+// Cached responses are returned with zero Usage, since no tokens were
+// spent, and are not recorded by the usage tracker. EventCacheHit and
+// EventCacheMiss report each lookup. Both Chat and ChatStream use the cache;
+// a hit on ChatStream replays the response as a single delta. Responses
+// stopped with ai.WithStop are not cached.
+func WithResponseCache(ttl time.Duration, maxEntries int) ClientOption {
+	return func(c *Client) {
+		if maxEntries <= 0 {
+			maxEntries = 1000
+		}
+		c.cache = &responseCache{
+			ttl:     ttl,
+			max:     maxEntries,
+			entries: make(map[string]*list.Element),
+			order:   list.New(),
+		}
+	}
+}
+
+// responseCache is an LRU cache of chat responses with expiry.
+type responseCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	max     int
+	entries map[string]*list.Element
+	order   *list.List // front is most recently used
+}
+
+// cacheEntry is a cached response, stored serialized so callers cannot
+// modify it.
+type cacheEntry struct {
+	key     string
+	data    []byte
+	expires time.Time
+}
+
+// get returns a copy of the response cached under key.
+func (rc *responseCache) get(key string) (*ai.Response, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	el, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*cacheEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		rc.order.Remove(el)
+		delete(rc.entries, key)
+		return nil, false
+	}
+	var resp ai.Response
+	if err := json.Unmarshal(entry.data, &resp); err != nil {
+		return nil, false
+	}
+	rc.order.MoveToFront(el)
+	return &resp, true
+}
+
+// put caches resp under key, evicting the least recently used entries
+// beyond the size limit.
+func (rc *responseCache) put(key string, resp *ai.Response) {
+	stored := *resp
+	stored.Usage = ai.Usage{}
+	stored.Timing = nil
+	data, err := json.Marshal(&stored)
+	if err != nil {
+		return
+	}
+	entry := &cacheEntry{key: key, data: data}
+	if rc.ttl > 0 {
+		entry.expires = time.Now().Add(rc.ttl)
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if el, ok := rc.entries[key]; ok {
+		el.Value = entry
+		rc.order.MoveToFront(el)
+		return
+	}
+	rc.entries[key] = rc.order.PushFront(entry)
+	for rc.order.Len() > rc.max {
+		oldest := rc.order.Back()
+		rc.order.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// len returns the number of cached entries, including expired ones not yet
+// evicted.
+func (rc *responseCache) len() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.order.Len()
+}
+
+// cacheRequest holds the parts of a chat request that determine its
+// response.
+type cacheRequest struct {
+	Provider         ai.Provider          `json:"provider"`
+	Model            string               `json:"model"`
+	Messages         []ai.Message         `json:"messages"`
+	MaxTokens        int                  `json:"maxTokens,omitempty"`
+	Tools            []ai.Tool            `json:"tools,omitempty"`
+	ToolChoice       ai.ToolChoice        `json:"toolChoice,omitempty"`
+	ResponseFormat   ai.ResponseFormat    `json:"responseFormat,omitempty"`
+	ResponseSchema   *ai.ResponseSchema   `json:"responseSchema,omitempty"`
+	StrictSchema     *bool                `json:"strictSchema,omitempty"`
+	ImageOutput      bool                 `json:"imageOutput,omitempty"`
+	ImageAspectRatio ai.ImageAspectRatio  `json:"imageAspectRatio,omitempty"`
+	ImageOutputSize  ai.ImageOutputSize   `json:"imageOutputSize,omitempty"`
+	AudioOutput      *ai.AudioOutput      `json:"audioOutput,omitempty"`
+	CandidateCount   int                  `json:"candidateCount,omitempty"`
+	Constraint       *ai.OutputConstraint `json:"constraint,omitempty"`
+}
+
+// cacheKey returns the cache key of a chat request, or "" if the client has
+// no cache or the request is not deterministic. Requests with a schema
+// transform are not cached, since the function cannot be compared.
+func (c *Client) cacheKey(model ai.Model, messages []ai.Message, options *ai.Options) string {
+	if c.cache == nil || options.Temperature == nil || *options.Temperature != 0 || options.SchemaTransform != nil {
+		return ""
+	}
+	data, err := json.Marshal(cacheRequest{
+		Provider:         model.Provider(),
+		Model:            model.String(),
+		Messages:         messages,
+		MaxTokens:        options.MaxTokens,
+		Tools:            options.Tools,
+		ToolChoice:       options.ToolChoice,
+		ResponseFormat:   options.ResponseFormat,
+		ResponseSchema:   options.ResponseSchema,
+		StrictSchema:     options.StrictSchema,
+		ImageOutput:      options.ImageOutput,
+		ImageAspectRatio: options.ImageAspectRatio,
+		ImageOutputSize:  options.ImageOutputSize,
+		AudioOutput:      options.AudioOutput,
+		CandidateCount:   options.CandidateCount,
+		Constraint:       options.Constraint,
+	})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// lookupCache returns the cached response for key and emits EventCacheHit
+// or EventCacheMiss. An empty key is not looked up.
+func (c *Client) lookupCache(key, operation string, model ai.Model, options *ai.Options) (*ai.Response, bool) {
+	if key == "" {
+		return nil, false
+	}
+	resp, ok := c.cache.get(key)
+	eventType := EventCacheMiss
+	if ok {
+		eventType = EventCacheHit
+	}
+	c.emit(Event{
+		Type:      eventType,
+		Operation: operation,
+		Provider:  model.Provider(),
+		Model:     model.String(),
+		Metadata:  options.Metadata,
+	})
+	return resp, ok
+}
+
+// storeCache caches resp under key unless the key is empty or the response
+// was stopped early.
+func (c *Client) storeCache(key string, resp *ai.Response) {
+	if key == "" || resp == nil || resp.FinishReason == ai.FinishReasonStopped {
+		return
+	}
+	c.cache.put(key, resp)
+}
+
+// cachedStream returns a closed stream replaying a cached response.
+func cachedStream(resp *ai.Response) <-chan event.Event {
+	ch := make(chan event.Event, 5)
+	id := generateMessageID()
+	ch <- event.Event{Type: event.RunStart}
+	ch <- event.Event{Type: event.MessageStart, MessageID: id}
+	if resp.Content != "" {
+		ch <- event.Event{Type: event.MessageDelta, MessageID: id, Delta: resp.Content}
+	}
+	ch <- event.Event{Type: event.MessageEnd, MessageID: id, Response: resp}
+	ch <- event.Event{Type: event.RunEnd, Response: resp}
+	close(ch)
+	return ch
+}
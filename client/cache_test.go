@@ -0,0 +1,167 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/internal/provider/openai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cannedTransport answers OpenAI chat requests with a fixed completion and
+// counts them.
+type cannedTransport struct {
+	requests atomic.Int32
+}
+
+func (t *cannedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+	body, _ := io.ReadAll(req.Body)
+	header := http.Header{}
+	payload := `{"id":"c1","object":"chat.completion","created":1,"model":"gpt-4o","choices":[{"index":0,"message":{"role":"assistant","content":"Paris"},"finish_reason":"stop"}],"usage":{"prompt_tokens":5,"completion_tokens":1,"total_tokens":6}}`
+	header.Set("Content-Type", "application/json")
+	if strings.Contains(string(body), `"stream":true`) {
+		header.Set("Content-Type", "text/event-stream")
+		payload = `data: {"id":"c1","object":"chat.completion.chunk","created":1,"model":"gpt-4o","choices":[{"index":0,"delta":{"role":"assistant","content":"Paris"},"finish_reason":"stop"}],"usage":{"prompt_tokens":5,"completion_tokens":1,"total_tokens":6}}` + "\n\ndata: [DONE]\n\n"
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(payload)),
+		Request:    req,
+	}, nil
+}
+
+// newCachingClient returns a client with a response cache whose OpenAI
+// requests are answered by transport.
+func newCachingClient(transport *cannedTransport, events chan Event, ttl time.Duration, maxEntries int) *Client {
+	c := New(Config{Events: events, Defaults: Defaults{Chat: testModel{id: "gpt-4o", provider: ai.ProviderOpenAI}}},
+		WithResponseCache(ttl, maxEntries))
+	c.openaiClient = openai.New("key", openai.WithTransport(func(http.RoundTripper) http.RoundTripper { return transport }))
+	return c
+}
+
+func cacheEvents(events chan Event) []EventType {
+	var types []EventType
+	for {
+		select {
+		case e := <-events:
+			if e.Type == EventCacheHit || e.Type == EventCacheMiss {
+				types = append(types, e.Type)
+			}
+		default:
+			return types
+		}
+	}
+}
+
+func TestResponseCache_Chat(t *testing.T) {
+	ctx := context.Background()
+	transport := &cannedTransport{}
+	events := make(chan Event, 100)
+	c := newCachingClient(transport, events, time.Hour, 10)
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "Capital of France?"}}
+
+	first, err := c.Chat(ctx, messages, ai.WithTemperature(0))
+	require.NoError(t, err)
+	assert.Equal(t, "Paris", first.Content)
+	assert.Equal(t, 5, first.Usage.InputTokens)
+
+	second, err := c.Chat(ctx, messages, ai.WithTemperature(0))
+	require.NoError(t, err)
+	assert.Equal(t, "Paris", second.Content)
+	assert.Equal(t, ai.Usage{}, second.Usage)
+	assert.Equal(t, int32(1), transport.requests.Load())
+	assert.Equal(t, []EventType{EventCacheMiss, EventCacheHit}, cacheEvents(events))
+
+	t.Run("different requests miss", func(t *testing.T) {
+		_, err := c.Chat(ctx, messages, ai.WithTemperature(0), ai.WithMaxTokens(10))
+		require.NoError(t, err)
+		_, err = c.Chat(ctx, append(messages, ai.Message{Role: ai.RoleUser, Content: "Sure?"}), ai.WithTemperature(0))
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), transport.requests.Load())
+	})
+
+	t.Run("nondeterministic requests are not cached", func(t *testing.T) {
+		cacheEvents(events)
+		_, err := c.Chat(ctx, messages)
+		require.NoError(t, err)
+		_, err = c.Chat(ctx, messages, ai.WithTemperature(0.7))
+		require.NoError(t, err)
+		assert.Equal(t, int32(5), transport.requests.Load())
+		assert.Empty(t, cacheEvents(events))
+	})
+
+	t.Run("cached responses cannot be modified", func(t *testing.T) {
+		second.Content = "London"
+		third, err := c.Chat(ctx, messages, ai.WithTemperature(0))
+		require.NoError(t, err)
+		assert.Equal(t, "Paris", third.Content)
+	})
+}
+
+func TestResponseCache_ChatStream(t *testing.T) {
+	ctx := context.Background()
+	transport := &cannedTransport{}
+	events := make(chan Event, 100)
+	c := newCachingClient(transport, events, 0, 10)
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "Capital of France?"}}
+
+	for i := 0; i < 2; i++ {
+		stream, err := c.ChatStream(ctx, messages, ai.WithTemperature(0))
+		require.NoError(t, err)
+		resp, _, err := event.Accumulate(stream)
+		require.NoError(t, err)
+		assert.Equal(t, "Paris", resp.Content)
+	}
+	assert.Equal(t, int32(1), transport.requests.Load())
+	assert.Equal(t, []EventType{EventCacheMiss, EventCacheHit}, cacheEvents(events))
+
+	// Chat and ChatStream share entries
+	resp, err := c.Chat(ctx, messages, ai.WithTemperature(0))
+	require.NoError(t, err)
+	assert.Equal(t, "Paris", resp.Content)
+	assert.Equal(t, int32(1), transport.requests.Load())
+}
+
+func TestResponseCache_Limits(t *testing.T) {
+	response := &ai.Response{Content: "x", Usage: ai.Usage{InputTokens: 3}}
+
+	t.Run("least recently used entries are evicted", func(t *testing.T) {
+		c := New(Config{}, WithResponseCache(0, 2))
+		c.cache.put("a", response)
+		c.cache.put("b", response)
+		_, ok := c.cache.get("a")
+		require.True(t, ok)
+		c.cache.put("c", response)
+
+		assert.Equal(t, 2, c.cache.len())
+		_, ok = c.cache.get("b")
+		assert.False(t, ok)
+		_, ok = c.cache.get("a")
+		assert.True(t, ok)
+	})
+
+	t.Run("entries expire", func(t *testing.T) {
+		c := New(Config{}, WithResponseCache(time.Millisecond, 0))
+		c.cache.put("a", response)
+		time.Sleep(5 * time.Millisecond)
+		_, ok := c.cache.get("a")
+		assert.False(t, ok)
+		assert.Equal(t, 0, c.cache.len())
+	})
+
+	t.Run("stopped responses are not cached", func(t *testing.T) {
+		c := New(Config{}, WithResponseCache(0, 0))
+		c.storeCache("a", &ai.Response{Content: "partial", FinishReason: ai.FinishReasonStopped})
+		assert.Equal(t, 0, c.cache.len())
+	})
+}
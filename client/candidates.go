@@ -0,0 +1,61 @@
+package client
+
+import (
+	"context"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/internal/group"
+)
+
+// nativeCandidates reports whether provider returns several candidates from
+// a single request.
+func nativeCandidates(provider ai.Provider) bool {
+	switch provider {
+	case ai.ProviderOpenAI, ai.ProviderGoogle, ai.ProviderVertex:
+		return true
+	default:
+		return false
+	}
+}
+
+// chatCandidates sends a chat request for options.CandidateCount
+// candidates. Providers without native support are sent that many
+// single-candidate requests concurrently, and their responses are combined
+// with usage summed. If any request fails, the first error is returned.
+func chatCandidates(ctx context.Context, chatProvider ai.ChatProvider, provider ai.Provider, messages []ai.Message, options *ai.Options, opts []ai.Option) (*ai.Response, error) {
+	n := options.CandidateCount
+	if n < 2 || nativeCandidates(provider) {
+		return chatProvider.Chat(ctx, messages, opts...)
+	}
+
+	single := append(append([]ai.Option(nil), opts...), ai.WithCandidateCount(1))
+	responses := make([]*ai.Response, n)
+	g, gctx := group.WithContext(ctx)
+	for i := range n {
+		g.Go(func() error {
+			resp, err := chatProvider.Chat(gctx, messages, single...)
+			responses[i] = resp
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	combined := *responses[0]
+	combined.Candidates = make([]ai.Candidate, n)
+	combined.Usage = ai.Usage{}
+	for i, resp := range responses {
+		combined.Candidates[i] = ai.Candidate{
+			Content:      resp.Content,
+			FinishReason: resp.FinishReason,
+			ToolCalls:    resp.ToolCalls,
+			Parts:        resp.Parts,
+		}
+		combined.Usage.InputTokens += resp.Usage.InputTokens
+		combined.Usage.OutputTokens += resp.Usage.OutputTokens
+		combined.Usage.CachedInputTokens += resp.Usage.CachedInputTokens
+		combined.Usage.CacheWriteInputTokens += resp.Usage.CacheWriteInputTokens
+	}
+	return &combined, nil
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingProvider answers each Chat call with a numbered response.
+type countingProvider struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (p *countingProvider) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	n := p.calls.Add(1)
+	if p.err != nil {
+		return nil, p.err
+	}
+	if ai.ApplyOptions(opts...).CandidateCount > 1 {
+		return nil, errors.New("candidate count not reset")
+	}
+	return &ai.Response{
+		Content:      fmt.Sprintf("answer %d", n),
+		FinishReason: "stop",
+		Usage:        ai.Usage{InputTokens: 10, OutputTokens: 2},
+	}, nil
+}
+
+func (p *countingProvider) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan ai.StreamEvent, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestChatCandidates(t *testing.T) {
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}
+
+	t.Run("emulates candidates with concurrent requests", func(t *testing.T) {
+		p := &countingProvider{}
+		opts := []ai.Option{ai.WithCandidateCount(3)}
+		resp, err := chatCandidates(context.Background(), p, ai.ProviderAnthropic, messages, ai.ApplyOptions(opts...), opts)
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), p.calls.Load())
+		require.Len(t, resp.Candidates, 3)
+		assert.Equal(t, resp.Candidates[0].Content, resp.Content)
+		assert.ElementsMatch(t, []string{"answer 1", "answer 2", "answer 3"}, resp.CandidateContents())
+		assert.Equal(t, ai.Usage{InputTokens: 30, OutputTokens: 6}, resp.Usage)
+	})
+
+	t.Run("sends one request for a single candidate", func(t *testing.T) {
+		p := &countingProvider{}
+		resp, err := chatCandidates(context.Background(), p, ai.ProviderAnthropic, messages, ai.ApplyOptions(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), p.calls.Load())
+		assert.Nil(t, resp.Candidates)
+		assert.True(t, nativeCandidates(ai.ProviderOpenAI))
+		assert.False(t, nativeCandidates(ai.ProviderAnthropic))
+	})
+
+	t.Run("returns the first error", func(t *testing.T) {
+		p := &countingProvider{err: errors.New("boom")}
+		opts := []ai.Option{ai.WithCandidateCount(2)}
+		_, err := chatCandidates(context.Background(), p, ai.ProviderAnthropic, messages, ai.ApplyOptions(opts...), opts)
+		assert.EqualError(t, err, "boom")
+	})
+}
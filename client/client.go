@@ -3,6 +3,9 @@ package client
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
+	"maps"
 	"sync"
 	"time"
 
@@ -13,39 +16,44 @@ import (
 	"github.com/spetersoncode/gains/internal/provider/openai"
 	"github.com/spetersoncode/gains/internal/provider/vertex"
 	"github.com/spetersoncode/gains/internal/retry"
+	"github.com/spetersoncode/gains/model"
 )
 
-
 // Feature represents a capability that a provider may support.
 type Feature string
 
 const (
-	FeatureChat      Feature = "chat"
-	FeatureImage     Feature = "image"
-	FeatureEmbedding Feature = "embedding"
+	FeatureChat          Feature = "chat"
+	FeatureImage         Feature = "image"
+	FeatureEmbedding     Feature = "embedding"
+	FeatureTranscription Feature = "transcription"
 )
 
 // providerCapabilities defines which features each provider supports.
 var providerCapabilities = map[ai.Provider]map[Feature]bool{
 	ai.ProviderAnthropic: {
-		FeatureChat:      true,
-		FeatureImage:     false,
-		FeatureEmbedding: false,
+		FeatureChat:          true,
+		FeatureImage:         false,
+		FeatureEmbedding:     false,
+		FeatureTranscription: false,
 	},
 	ai.ProviderOpenAI: {
-		FeatureChat:      true,
-		FeatureImage:     true,
-		FeatureEmbedding: true,
+		FeatureChat:          true,
+		FeatureImage:         true,
+		FeatureEmbedding:     true,
+		FeatureTranscription: true,
 	},
 	ai.ProviderGoogle: {
-		FeatureChat:      true,
-		FeatureImage:     true,
-		FeatureEmbedding: true,
+		FeatureChat:          true,
+		FeatureImage:         true,
+		FeatureEmbedding:     true,
+		FeatureTranscription: true,
 	},
 	ai.ProviderVertex: {
-		FeatureChat:      true,
-		FeatureImage:     true,
-		FeatureEmbedding: true,
+		FeatureChat:          true,
+		FeatureImage:         true,
+		FeatureEmbedding:     true,
+		FeatureTranscription: true,
 	},
 }
 
@@ -63,14 +71,20 @@ type Credentials struct {
 type VertexConfig struct {
 	Project  string // GCP project ID
 	Location string // e.g., "us-central1"
+
+	// Regions lists further locations chat requests may be sent to with
+	// gains.WithRegion or WithRegionRouting. Location remains the default
+	// and serves embedding, image, and transcription requests.
+	Regions []string
 }
 
 // Defaults holds default models for each capability.
 // The model's provider determines which backend is used.
 type Defaults struct {
-	Chat      ai.Model
-	Embedding ai.Model
-	Image     ai.Model
+	Chat          ai.Model
+	Embedding     ai.Model
+	Image         ai.Model
+	Transcription ai.Model
 }
 
 // Config holds configuration for creating a unified client.
@@ -87,9 +101,20 @@ type Config struct {
 	// If nil, uses default retry configuration (10 retries with exponential backoff).
 	RetryConfig *retry.Config
 
+	// RequestTimeouts bounds each attempt of a provider request, per
+	// provider and operation, independently of the caller's context.
+	// Zero values leave attempts bounded only by the caller's context.
+	RequestTimeouts RequestTimeouts
+
 	// Events is an optional channel for receiving client operation events.
 	// Events are sent non-blocking; if the channel is full, events are dropped.
 	Events chan<- Event
+
+	// Aliases maps alias names to the models they select, such as
+	// "cheap-chat" to model.GPT5Nano. Requests and defaults naming a
+	// model.Alias use the model it maps to here, or in model.DefaultAliases
+	// if not set here. An alias may name another alias.
+	Aliases map[string]ai.Model
 }
 
 // ErrFeatureNotSupported is returned when a feature is unavailable for the provider.
@@ -102,6 +127,18 @@ func (e *ErrFeatureNotSupported) Error() string {
 	return fmt.Sprintf("%s provider does not support %s", e.Provider, e.Feature)
 }
 
+// ErrCapabilityUnsupported is returned when the model selected for a chat
+// request does not support a feature the request uses, such as tools or
+// images, and no fallback model from WithCapabilityFallback does.
+type ErrCapabilityUnsupported struct {
+	Model      string
+	Capability ai.Capability
+}
+
+func (e *ErrCapabilityUnsupported) Error() string {
+	return fmt.Sprintf("model %q does not support %s", e.Model, e.Capability)
+}
+
 // ErrMissingAPIKey is returned when a model is used but no API key
 // is configured for that model's provider.
 type ErrMissingAPIKey struct {
@@ -126,10 +163,12 @@ var operationHints = map[string]struct {
 	configField string
 	optionFunc  string
 }{
-	"chat":        {"Defaults.Chat", "gains.WithModel()"},
-	"chat_stream": {"Defaults.Chat", "gains.WithModel()"},
-	"image":       {"Defaults.Image", "gains.WithImageModel()"},
-	"embedding":   {"Defaults.Embedding", "gains.WithEmbeddingModel()"},
+	"chat":          {"Defaults.Chat", "gains.WithModel()"},
+	"chat_stream":   {"Defaults.Chat", "gains.WithModel()"},
+	"count_tokens":  {"Defaults.Chat", "the model argument"},
+	"image":         {"Defaults.Image", "gains.WithImageModel()"},
+	"embedding":     {"Defaults.Embedding", "gains.WithEmbeddingModel()"},
+	"transcription": {"Defaults.Transcription", "gains.WithTranscriptionModel()"},
 }
 
 func (e *ErrNoModel) Error() string {
@@ -167,14 +206,119 @@ func WithDefaultChatOptions(opts ...ai.Option) ClientOption {
 	}
 }
 
+// WithBudget rejects requests with *ai.ErrBudgetExceeded once the cumulative
+// cost recorded by the client's usage tracker reaches maxUSD. A tracker is
+// created automatically if WithUsageTracker is not used. Streams stopped
+// with ai.WithStop record no usage and do not count toward the budget.
+func WithBudget(maxUSD float64) ClientOption {
+	return func(c *Client) {
+		c.budget = maxUSD
+	}
+}
+
+// WithTokenBudget rejects requests with *ai.ErrBudgetExceeded once the
+// cumulative input and output tokens recorded by the client's usage tracker
+// reach maxTokens. A tracker is created automatically if WithUsageTracker is
+// not used. Streams stopped with ai.WithStop record no usage and do not count
+// toward the budget.
+func WithTokenBudget(maxTokens int) ClientOption {
+	return func(c *Client) {
+		c.tokenBudget = maxTokens
+	}
+}
+
+// WithCapabilityFallback sends chat requests to the first of models that
+// supports them when the selected model lacks a feature the request uses,
+// instead of failing with ErrCapabilityUnsupported. An EventModelFallback
+// event reports each switch.
+func WithCapabilityFallback(models ...ai.Model) ClientOption {
+	return func(c *Client) {
+		c.fallbackModels = append(c.fallbackModels, models...)
+	}
+}
+
+// WithOpenAIBaseURL routes OpenAI models to an OpenAI-compatible server,
+// such as vLLM or a local inference server. An API key is optional when a
+// base URL is set. Such servers are assumed to support constrained decoding
+// for ai.WithConstrainedOutput.
+func WithOpenAIBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.openaiBaseURL = url
+	}
+}
+
+// WithChatProvider routes chat requests for models of provider to cp
+// instead of the built-in provider client, which is then never created.
+// Use it with fakes or testkit.Chaos to exercise the client's retry,
+// fallback, and budget handling without network access.
+func WithChatProvider(provider ai.Provider, cp ai.ChatProvider) ClientOption {
+	return func(c *Client) {
+		if c.chatProviders == nil {
+			c.chatProviders = make(map[ai.Provider]ai.ChatProvider)
+		}
+		c.chatProviders[provider] = cp
+	}
+}
+
+// WithImageDelegate sends image generation requests to model when the
+// requested image model's provider cannot generate images, or when no image
+// model is requested or configured. This lets an application whose default
+// chat model is from Anthropic call GenerateImage without checking
+// FeatureImage first:
+//
+//	c := client.New(cfg, client.WithImageDelegate(model.GPTImage1))
+//
+// The delegate's provider must be configured. An EventModelFallback event
+// reports each request sent to the delegate in place of another model.
+func WithImageDelegate(model ai.Model) ClientOption {
+	return func(c *Client) {
+		c.imageDelegate = model
+	}
+}
+
+// WithEventBuffer sets the capacity of the event channel returned by
+// ChatStream. Raise it when consumers fall behind bursts of deltas, which
+// are otherwise dropped; see event.Stats. The default is
+// event.DefaultBufferSize.
+func WithEventBuffer(n int) ClientOption {
+	return func(c *Client) {
+		c.eventBuffer = n
+	}
+}
+
 // Client is a unified interface to all AI provider capabilities.
 // Provider clients are lazily initialized when first needed.
 type Client struct {
 	creds           Credentials
 	defaults        Defaults
 	retryConfig     retry.Config
+	requestTimeouts RequestTimeouts
 	events          chan<- Event
 	defaultChatOpts []ai.Option
+	usage           *UsageTracker
+	budget          float64
+	tokenBudget     int
+	transcript      TranscriptSink
+	transcriptCfg   *transcriptConfig
+	admission       *admissionController
+	fallbackModels  []ai.Model
+	cache           *responseCache
+	openaiBaseURL   string
+	middleware      []Middleware
+	logger          *slog.Logger
+	logLevels       event.LogLevels
+	aliases         map[string]ai.Model
+	deprecations    sync.Map // deprecated model IDs already warned about
+	chatProviders   map[ai.Provider]ai.ChatProvider
+	imageDelegate   ai.Model
+	regionRouting   bool
+	eventBuffer     int
+
+	// Operations wrapped in middleware, built by New
+	chatFn       ChatFunc
+	chatStreamFn ChatStreamFunc
+	embedFn      EmbedFunc
+	imageFn      ImageFunc
 
 	// Lazy-initialized providers (protected by mutex)
 	mu              sync.RWMutex
@@ -182,8 +326,7 @@ type Client struct {
 	openaiClient    *openai.Client
 	googleClient    *google.Client
 	googleInitErr   error
-	vertexClient    *vertex.Client
-	vertexInitErr   error
+	vertexClients   map[vertexKey]*regionEntry
 }
 
 // New creates a unified client with the given configuration.
@@ -196,17 +339,86 @@ func New(cfg Config, opts ...ClientOption) *Client {
 	}
 
 	c := &Client{
-		creds:       cfg.Credentials,
-		defaults:    cfg.Defaults,
-		retryConfig: retryConfig,
-		events:      cfg.Events,
-	}
+		creds:           cfg.Credentials,
+		defaults:        cfg.Defaults,
+		retryConfig:     retryConfig,
+		requestTimeouts: cfg.RequestTimeouts,
+		events:          cfg.Events,
+		logLevels:       event.DefaultLogLevels(),
+		aliases:         model.DefaultAliases(),
+	}
+	maps.Copy(c.aliases, cfg.Aliases)
 	for _, opt := range opts {
 		opt(c)
 	}
+	if c.usage == nil && (c.budget > 0 || c.tokenBudget > 0) {
+		c.usage = NewUsageTracker()
+	}
+	c.buildMiddleware()
 	return c
 }
 
+// checkBudget returns *ai.ErrBudgetExceeded and emits EventBudgetExceeded if
+// the client's cost or token budget has been reached.
+func (c *Client) checkBudget(operation string, provider ai.Provider) error {
+	if c.budget <= 0 && c.tokenBudget <= 0 {
+		return nil
+	}
+	total := c.usage.Total()
+	tokens := total.InputTokens + total.OutputTokens
+	if (c.budget <= 0 || total.Cost < c.budget) && (c.tokenBudget <= 0 || tokens < c.tokenBudget) {
+		return nil
+	}
+	err := &ai.ErrBudgetExceeded{
+		MaxCost:   c.budget,
+		Cost:      total.Cost,
+		MaxTokens: c.tokenBudget,
+		Tokens:    tokens,
+	}
+	c.emit(Event{
+		Type:      EventBudgetExceeded,
+		Operation: operation,
+		Provider:  provider,
+		Error:     err,
+	})
+	return err
+}
+
+// negotiateModel checks that model supports the features a chat request
+// uses. If it does not, the first fallback model that does replaces it and
+// is appended to opts; without one, *ErrCapabilityUnsupported is returned.
+func (c *Client) negotiateModel(operation string, model ai.Model, messages []ai.Message, options *ai.Options, opts []ai.Option) (ai.Model, []ai.Option, error) {
+	required := ai.RequiredCapabilities(messages, options)
+	missing, ok := supportsAll(model, required)
+	if ok {
+		return model, opts, nil
+	}
+	for _, fallback := range c.fallbackModels {
+		if _, ok := supportsAll(fallback, required); ok {
+			c.emit(Event{
+				Type:      EventModelFallback,
+				Operation: operation,
+				Provider:  fallback.Provider(),
+				Model:     fallback.String(),
+				Error:     &ErrCapabilityUnsupported{Model: model.String(), Capability: missing},
+			})
+			return fallback, append(opts, ai.WithModel(fallback)), nil
+		}
+	}
+	return nil, nil, &ErrCapabilityUnsupported{Model: model.String(), Capability: missing}
+}
+
+// supportsAll reports whether model supports every capability in caps, and
+// if not, the first one it lacks.
+func supportsAll(model ai.Model, caps []ai.Capability) (ai.Capability, bool) {
+	for _, c := range caps {
+		if !ai.ModelSupports(model, c) {
+			return c, false
+		}
+	}
+	return "", true
+}
+
 // getAnthropicClient returns the Anthropic client, initializing it if needed.
 func (c *Client) getAnthropicClient() (*anthropic.Client, error) {
 	c.mu.RLock()
@@ -228,7 +440,11 @@ func (c *Client) getAnthropicClient() (*anthropic.Client, error) {
 		return nil, &ErrMissingAPIKey{Provider: "anthropic"}
 	}
 
-	c.anthropicClient = anthropic.New(c.creds.Anthropic)
+	var opts []anthropic.ClientOption
+	if wrap := c.providerTransport(ai.ProviderAnthropic); wrap != nil {
+		opts = append(opts, anthropic.WithTransport(wrap))
+	}
+	c.anthropicClient = anthropic.New(c.creds.Anthropic, opts...)
 	return c.anthropicClient, nil
 }
 
@@ -249,11 +465,18 @@ func (c *Client) getOpenAIClient() (*openai.Client, error) {
 		return c.openaiClient, nil
 	}
 
-	if c.creds.OpenAI == "" {
+	if c.creds.OpenAI == "" && c.openaiBaseURL == "" {
 		return nil, &ErrMissingAPIKey{Provider: "openai"}
 	}
 
-	c.openaiClient = openai.New(c.creds.OpenAI)
+	var opts []openai.ClientOption
+	if c.openaiBaseURL != "" {
+		opts = append(opts, openai.WithBaseURL(c.openaiBaseURL))
+	}
+	if wrap := c.providerTransport(ai.ProviderOpenAI); wrap != nil {
+		opts = append(opts, openai.WithTransport(wrap))
+	}
+	c.openaiClient = openai.New(c.creds.OpenAI, opts...)
 	return c.openaiClient, nil
 }
 
@@ -285,7 +508,11 @@ func (c *Client) getGoogleClient(ctx context.Context) (*google.Client, error) {
 		return nil, &ErrMissingAPIKey{Provider: "google"}
 	}
 
-	client, err := google.New(ctx, c.creds.Google)
+	var opts []google.ClientOption
+	if wrap := c.providerTransport(ai.ProviderGoogle); wrap != nil {
+		opts = append(opts, google.WithTransport(wrap))
+	}
+	client, err := google.New(ctx, c.creds.Google, opts...)
 	if err != nil {
 		c.googleInitErr = fmt.Errorf("failed to initialize Google client: %w", err)
 		return nil, c.googleInitErr
@@ -295,42 +522,14 @@ func (c *Client) getGoogleClient(ctx context.Context) (*google.Client, error) {
 	return c.googleClient, nil
 }
 
-// getVertexClient returns the Vertex AI client, initializing it if needed.
+// getVertexClient returns the Vertex AI client of the primary location,
+// initializing it if needed.
 func (c *Client) getVertexClient(ctx context.Context) (*vertex.Client, error) {
-	c.mu.RLock()
-	if c.vertexClient != nil {
-		defer c.mu.RUnlock()
-		return c.vertexClient, nil
-	}
-	if c.vertexInitErr != nil {
-		defer c.mu.RUnlock()
-		return nil, c.vertexInitErr
-	}
-	c.mu.RUnlock()
-
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	// Double-check after acquiring write lock
-	if c.vertexClient != nil {
-		return c.vertexClient, nil
-	}
-	if c.vertexInitErr != nil {
-		return nil, c.vertexInitErr
-	}
-
-	if c.creds.Vertex.Project == "" || c.creds.Vertex.Location == "" {
-		return nil, &ErrMissingAPIKey{Provider: "vertex (requires Project and Location)"}
-	}
-
-	client, err := vertex.New(ctx, c.creds.Vertex.Project, c.creds.Vertex.Location)
+	entry, err := c.getVertexRegion(ctx, c.creds.Vertex.Location)
 	if err != nil {
-		c.vertexInitErr = fmt.Errorf("failed to initialize Vertex AI client: %w", err)
-		return nil, c.vertexInitErr
+		return nil, err
 	}
-
-	c.vertexClient = client
-	return c.vertexClient, nil
+	return entry.client, nil
 }
 
 // resolveProvider determines which provider to use for a given model.
@@ -338,9 +537,13 @@ func (c *Client) resolveProvider(model ai.Model) ai.Provider {
 	return model.Provider()
 }
 
-// getChatProvider returns the chat provider for the given model.
-func (c *Client) getChatProvider(ctx context.Context, model ai.Model) (ai.ChatProvider, ai.Provider, error) {
+// getChatProvider returns the chat provider for the given model. Region
+// selects the Vertex AI location; it is ignored for other providers.
+func (c *Client) getChatProvider(ctx context.Context, model ai.Model, region string) (ai.ChatProvider, ai.Provider, error) {
 	provider := c.resolveProvider(model)
+	if cp, ok := c.chatProviders[provider]; ok {
+		return cp, provider, nil
+	}
 
 	switch provider {
 	case ai.ProviderAnthropic:
@@ -362,22 +565,43 @@ func (c *Client) getChatProvider(ctx context.Context, model ai.Model) (ai.ChatPr
 		}
 		return client, provider, nil
 	case ai.ProviderVertex:
-		client, err := c.getVertexClient(ctx)
+		entry, err := c.getVertexRegion(ctx, c.vertexLocation(region))
 		if err != nil {
 			return nil, "", err
 		}
-		return client, provider, nil
+		return regionalChat{client: entry.client, entry: entry}, provider, nil
 	default:
 		return nil, "", fmt.Errorf("unsupported provider: %s", provider)
 	}
 }
 
+// chatOptions merges the options for a chat call. Client defaults come
+// first, then options attached to ctx with ai.WithContextOptions, then the
+// per-request options, so later options override earlier ones.
+func (c *Client) chatOptions(ctx context.Context, opts []ai.Option) []ai.Option {
+	ctxOpts := ai.ContextOptions(ctx)
+	merged := make([]ai.Option, 0, len(c.defaultChatOpts)+len(ctxOpts)+len(opts))
+	merged = append(merged, c.defaultChatOpts...)
+	merged = append(merged, ctxOpts...)
+	return append(merged, opts...)
+}
+
 // Chat sends a conversation and returns a complete response.
 // The model can be specified via WithModel option, or the default chat model is used.
 // Automatically retries on transient errors according to the client's retry configuration.
+// In a dry run (see gains.WithDryRun), it returns an empty response without
+// calling the provider.
 func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
-	// Prepend default options so per-request options override them
-	opts = append(c.defaultChatOpts, opts...)
+	return c.chatFn(ctx, messages, opts...)
+}
+
+// chat implements Chat beneath the client's middleware.
+func (c *Client) chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	if ai.IsDryRun(ctx) {
+		return &ai.Response{}, nil
+	}
+
+	opts = c.chatOptions(ctx, opts)
 	options := ai.ApplyOptions(opts...)
 
 	// Determine which model to use
@@ -388,18 +612,44 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 	if model == nil {
 		return nil, &ErrNoModel{Operation: "chat"}
 	}
+	model, aliased, err := c.resolveModel("chat", model)
+	if err != nil {
+		return nil, err
+	}
+	if aliased {
+		opts = append(opts, ai.WithModel(model))
+	}
+	model, opts, err = c.negotiateModel("chat", model, messages, options, opts)
+	if err != nil {
+		return nil, err
+	}
+	cacheKey := c.cacheKey(model, messages, options)
+	if resp, ok := c.lookupCache(cacheKey, "chat", model, options); ok {
+		return resp, nil
+	}
 
 	// Get the appropriate provider
-	chatProvider, provider, err := c.getChatProvider(ctx, model)
+	chatProvider, provider, err := c.getChatProvider(ctx, model, options.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkBudget("chat", provider); err != nil {
+		return nil, err
+	}
+
+	release, err := c.admit(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer release()
 
 	start := time.Now()
-	emit(c.events, Event{
+	c.emit(Event{
 		Type:      EventRequestStart,
 		Operation: "chat",
 		Provider:  provider,
+		Metadata:  options.Metadata,
 	})
 
 	// Ensure model is passed to the underlying provider
@@ -407,9 +657,9 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 		opts = append([]ai.Option{ai.WithModel(model)}, opts...)
 	}
 
-	// Create retry events channel if client events are enabled
+	// Create retry events channel if client events or logging are enabled
 	var retryEvents chan retry.Event
-	if c.events != nil {
+	if c.events != nil || c.logger != nil {
 		retryEvents = make(chan retry.Event, 10)
 		go c.forwardRetryEvents(retryEvents, "chat", provider)
 	}
@@ -420,8 +670,12 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 		retryConfig = toInternalRetryConfig(options.RetryConfig)
 	}
 
-	resp, err := retry.DoWithEvents(ctx, retryConfig, retryEvents, func() (*ai.Response, error) {
-		return chatProvider.Chat(ctx, messages, opts...)
+	resp, err := c.chatSchemaRetry(provider, messages, options, func(messages []ai.Message) (*ai.Response, error) {
+		return retry.DoWithEvents(ctx, retryConfig, retryEvents, func() (*ai.Response, error) {
+			return withAttempt(c, ctx, provider, "chat", func(ctx context.Context) (*ai.Response, error) {
+				return chatConstrained(ctx, chatProvider, provider, messages, options, opts)
+			})
+		})
 	})
 
 	if retryEvents != nil {
@@ -429,10 +683,11 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 	}
 
 	if err != nil {
-		emit(c.events, Event{
+		c.emit(Event{
 			Type:      EventRequestError,
 			Operation: "chat",
 			Provider:  provider,
+			Metadata:  options.Metadata,
 			Duration:  time.Since(start),
 			Error:     err,
 		})
@@ -440,16 +695,24 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 	}
 
 	var usage *ai.Usage
+	var providerMetadata *ai.ProviderMetadata
 	if resp != nil {
 		usage = &resp.Usage
-	}
-	emit(c.events, Event{
-		Type:      EventRequestComplete,
-		Operation: "chat",
-		Provider:  provider,
-		Duration:  time.Since(start),
-		Usage:     usage,
+		providerMetadata = resp.ProviderMetadata
+	}
+	c.emit(Event{
+		Type:             EventRequestComplete,
+		Operation:        "chat",
+		Provider:         provider,
+		Metadata:         options.Metadata,
+		Duration:         time.Since(start),
+		Usage:            usage,
+		ProviderMetadata: providerMetadata,
 	})
+	if resp != nil {
+		c.recordUsage("chat", provider, model, chatUsageTotals(model, resp.Usage))
+	}
+	c.storeCache(cacheKey, resp)
 	return resp, nil
 }
 
@@ -457,10 +720,42 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 // The model can be specified via WithModel option, or the default chat model is used.
 // Automatically retries on transient errors when establishing the stream connection.
 //
-// Events emitted: MessageStart, MessageDelta*, MessageEnd (or RunError on failure).
+// Events emitted: RunStart, MessageStart, MessageDelta*, MessageEnd, RunEnd
+// (or RunError on failure).
+//
+// A stream stopped with ai.WithStop ends with FinishReasonStopped and no
+// usage, since providers report usage only when a response completes. Its
+// tokens are therefore not counted by the usage tracker or by WithBudget and
+// WithTokenBudget, although the provider may still bill them.
 func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
-	// Prepend default options so per-request options override them
-	opts = append(c.defaultChatOpts, opts...)
+	return c.chatStreamFn(ctx, messages, opts...)
+}
+
+// ChatStreamTo streams a response like ChatStream, writing its content to w
+// as it arrives, and returns the final response once the stream ends:
+//
+//	resp, err := c.ChatStreamTo(ctx, messages, os.Stdout)
+//
+// It fails if the stream cannot be started, ends with a RunError event, or
+// a write to w fails.
+func (c *Client) ChatStreamTo(ctx context.Context, messages []ai.Message, w io.Writer, opts ...ai.Option) (*ai.Response, error) {
+	ch, err := c.ChatStream(ctx, messages, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return event.StreamToWriter(ctx, ch, w)
+}
+
+// chatStream implements ChatStream beneath the client's middleware.
+func (c *Client) chatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+	if ai.IsDryRun(ctx) {
+		return dryRunStream(), nil
+	}
+	if ai.IsStopped(ctx) {
+		return stoppedStream(), nil
+	}
+
+	opts = c.chatOptions(ctx, opts)
 	options := ai.ApplyOptions(opts...)
 
 	// Determine which model to use
@@ -471,18 +766,45 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 	if model == nil {
 		return nil, &ErrNoModel{Operation: "chat_stream"}
 	}
+	model, aliased, err := c.resolveModel("chat_stream", model)
+	if err != nil {
+		return nil, err
+	}
+	if aliased {
+		opts = append(opts, ai.WithModel(model))
+	}
+	model, opts, err = c.negotiateModel("chat_stream", model, messages, options, opts)
+	if err != nil {
+		return nil, err
+	}
+	cacheKey := c.cacheKey(model, messages, options)
+	if resp, ok := c.lookupCache(cacheKey, "chat_stream", model, options); ok {
+		return cachedStream(resp), nil
+	}
 
 	// Get the appropriate provider
-	chatProvider, provider, err := c.getChatProvider(ctx, model)
+	chatProvider, provider, err := c.getChatProvider(ctx, model, options.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.checkBudget("chat_stream", provider); err != nil {
+		return nil, err
+	}
+
+	// The admission slot is held until the stream ends.
+	release, err := c.admit(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	start := time.Now()
-	emit(c.events, Event{
+	acc := ai.NewStreamAccumulator()
+	c.emit(Event{
 		Type:      EventRequestStart,
 		Operation: "chat_stream",
 		Provider:  provider,
+		Metadata:  options.Metadata,
 	})
 
 	// Ensure model is passed to the underlying provider
@@ -490,9 +812,9 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 		opts = append([]ai.Option{ai.WithModel(model)}, opts...)
 	}
 
-	// Create retry events channel if client events are enabled
+	// Create retry events channel if client events or logging are enabled
 	var retryEvents chan retry.Event
-	if c.events != nil {
+	if c.events != nil || c.logger != nil {
 		retryEvents = make(chan retry.Event, 10)
 		go c.forwardRetryEvents(retryEvents, "chat_stream", provider)
 	}
@@ -503,8 +825,25 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 		retryConfig = toInternalRetryConfig(options.RetryConfig)
 	}
 
-	providerCh, err := retry.DoStreamWithEvents(ctx, retryConfig, retryEvents, func() (<-chan ai.StreamEvent, error) {
-		return chatProvider.ChatStream(ctx, messages, opts...)
+	// Without constrained decoding, the model is only instructed to follow
+	// the constraint, since a stream cannot be validated and retried
+	if options.Constraint != nil && !ai.SupportsConstraint(chatProvider, options.Constraint.Kind) {
+		messages = constraintMessages(messages, *options.Constraint)
+	}
+
+	// The provider request gets its own context so a stop can end it
+	// without cancelling the caller's context
+	streamCtx, cancel := context.WithCancel(ctx)
+	providerCh, err := retry.DoStreamWithEvents(streamCtx, retryConfig, retryEvents, func() (<-chan ai.StreamEvent, error) {
+		// A successful attempt's context lives until the stream ends
+		attemptCtx, cancelAttempt := c.attemptContext(streamCtx, provider, "chat_stream")
+		ch, err := chatProvider.ChatStream(attemptCtx, messages, opts...)
+		if err != nil {
+			cancelAttempt()
+			return nil, attemptError(streamCtx, attemptCtx, "chat_stream", err)
+		}
+		context.AfterFunc(streamCtx, cancelAttempt)
+		return ch, nil
 	})
 
 	if retryEvents != nil {
@@ -512,26 +851,33 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 	}
 
 	if err != nil {
-		emit(c.events, Event{
+		cancel()
+		release()
+		c.emit(Event{
 			Type:      EventRequestError,
 			Operation: "chat_stream",
 			Provider:  provider,
+			Metadata:  options.Metadata,
 			Duration:  time.Since(start),
 			Error:     err,
 		})
 		return nil, err
 	}
 
-	emit(c.events, Event{
+	c.emit(Event{
 		Type:      EventRequestComplete,
 		Operation: "chat_stream",
 		Provider:  provider,
+		Metadata:  options.Metadata,
 		Duration:  time.Since(start),
 	})
 
 	// Wrap provider stream in unified event stream
-	eventCh := event.NewChannel()
-	go c.wrapProviderStream(providerCh, eventCh)
+	eventCh := event.NewChannelSize(c.eventBuffer)
+	go c.wrapProviderStream(providerCh, eventCh, provider, model, acc, cacheKey, ai.StopSignal(ctx), func() {
+		cancel()
+		release()
+	})
 
 	return eventCh, nil
 }
@@ -539,8 +885,15 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 // wrapProviderStream converts provider StreamEvents to unified events.
 // Emits: RunStart -> MessageStart -> MessageDelta* -> MessageEnd -> RunEnd
 // Or on error: RunStart -> RunError
-func (c *Client) wrapProviderStream(providerCh <-chan ai.StreamEvent, eventCh chan<- event.Event) {
+// Usage from the final response is recorded in the client's usage tracker,
+// and the response's Timing is filled in from acc, which was started when
+// the request began, and the response is cached under cacheKey unless it is
+// empty. When stop is closed, the stream ends early with the content
+// received so far and FinishReason ai.FinishReasonStopped. release ends the
+// provider request and frees its admission slot on return.
+func (c *Client) wrapProviderStream(providerCh <-chan ai.StreamEvent, eventCh chan<- event.Event, provider ai.Provider, model ai.Model, acc *ai.StreamAccumulator, cacheKey string, stop <-chan struct{}, release func()) {
 	defer close(eventCh)
+	defer release()
 
 	// Emit RunStart at the beginning
 	event.Emit(eventCh, event.Event{Type: event.RunStart})
@@ -548,7 +901,20 @@ func (c *Client) wrapProviderStream(providerCh <-chan ai.StreamEvent, eventCh ch
 	messageID := generateMessageID()
 	messageStarted := false
 
-	for se := range providerCh {
+	for {
+		var se ai.StreamEvent
+		var ok bool
+		select {
+		case se, ok = <-providerCh:
+		case <-stop:
+			c.stopProviderStream(providerCh, eventCh, provider, model, acc, messageID, messageStarted)
+			return
+		}
+		if !ok {
+			return
+		}
+		acc.Add(se)
+
 		// Handle errors
 		if se.Err != nil {
 			event.Emit(eventCh, event.Event{
@@ -578,6 +944,27 @@ func (c *Client) wrapProviderStream(providerCh <-chan ai.StreamEvent, eventCh ch
 
 		// Handle completion
 		if se.Done {
+			stats := acc.Stats()
+			var usage *ai.Usage
+			var providerMetadata *ai.ProviderMetadata
+			if se.Response != nil {
+				se.Response.Timing = &stats
+				usage = &se.Response.Usage
+				providerMetadata = se.Response.ProviderMetadata
+				c.recordUsage("chat_stream", provider, model, chatUsageTotals(model, se.Response.Usage))
+				c.storeCache(cacheKey, se.Response)
+			}
+			c.emit(Event{
+				Type:             EventStreamComplete,
+				Operation:        "chat_stream",
+				Provider:         provider,
+				Model:            model.String(),
+				Duration:         stats.Duration,
+				Usage:            usage,
+				Stream:           &stats,
+				ProviderMetadata: providerMetadata,
+			})
+
 			// Ensure message was started (handles empty responses)
 			if !messageStarted {
 				event.Emit(eventCh, event.Event{
@@ -602,9 +989,66 @@ func (c *Client) wrapProviderStream(providerCh <-chan ai.StreamEvent, eventCh ch
 	}
 }
 
+// stopProviderStream ends a stream stopped with ai.WithStop, completing it
+// with the content received so far. The provider reports no usage for a
+// stopped stream, so none is recorded. The provider channel is drained in
+// the background once its request is cancelled.
+func (c *Client) stopProviderStream(providerCh <-chan ai.StreamEvent, eventCh chan<- event.Event, provider ai.Provider, model ai.Model, acc *ai.StreamAccumulator, messageID string, messageStarted bool) {
+	go func() {
+		for range providerCh {
+		}
+	}()
+
+	resp := acc.Response()
+	resp.FinishReason = ai.FinishReasonStopped
+	acc.Complete(resp)
+	stats := acc.Stats()
+	resp.Timing = &stats
+	c.emit(Event{
+		Type:      EventStreamComplete,
+		Operation: "chat_stream",
+		Provider:  provider,
+		Model:     model.String(),
+		Duration:  stats.Duration,
+		Stream:    &stats,
+	})
+
+	if !messageStarted {
+		event.Emit(eventCh, event.Event{Type: event.MessageStart, MessageID: messageID})
+	}
+	event.Emit(eventCh, event.Event{Type: event.MessageEnd, MessageID: messageID, Response: resp})
+	event.Emit(eventCh, event.Event{Type: event.RunEnd, Response: resp})
+}
+
 // generateMessageID creates a unique message ID.
 func generateMessageID() string {
-	return fmt.Sprintf("msg_%d", time.Now().UnixNano())
+	return ai.GenerateMessageID()
+}
+
+// dryRunStream returns a closed stream holding an empty message, which
+// ChatStream returns in a dry run.
+func dryRunStream() <-chan event.Event {
+	ch := make(chan event.Event, 2)
+	id := generateMessageID()
+	ch <- event.Event{Type: event.MessageStart, MessageID: id}
+	ch <- event.Event{Type: event.MessageEnd, MessageID: id, Response: &ai.Response{}}
+	close(ch)
+	return ch
+}
+
+// stoppedStream returns a closed stream holding an empty stopped message,
+// which ChatStream returns for a context already stopped with ai.WithStop.
+// Like a stream stopped mid-flight, it ends with RunEnd.
+func stoppedStream() <-chan event.Event {
+	ch := make(chan event.Event, 4)
+	id := generateMessageID()
+	resp := &ai.Response{FinishReason: ai.FinishReasonStopped}
+	ch <- event.Event{Type: event.RunStart}
+	ch <- event.Event{Type: event.MessageStart, MessageID: id}
+	ch <- event.Event{Type: event.MessageEnd, MessageID: id, Response: resp}
+	ch <- event.Event{Type: event.RunEnd, Response: resp}
+	close(ch)
+	return ch
 }
 
 // GenerateImage creates images from a text prompt.
@@ -612,6 +1056,11 @@ func generateMessageID() string {
 // Returns ErrFeatureNotSupported if the provider doesn't support image generation.
 // Automatically retries on transient errors according to the client's retry configuration.
 func (c *Client) GenerateImage(ctx context.Context, prompt string, opts ...ai.ImageOption) (*ai.ImageResponse, error) {
+	return c.imageFn(ctx, prompt, opts...)
+}
+
+// generateImage implements GenerateImage beneath the client's middleware.
+func (c *Client) generateImage(ctx context.Context, prompt string, opts ...ai.ImageOption) (*ai.ImageResponse, error) {
 	options := ai.ApplyImageOptions(opts...)
 
 	// Determine which model to use
@@ -619,6 +1068,29 @@ func (c *Client) GenerateImage(ctx context.Context, prompt string, opts ...ai.Im
 	if model == nil {
 		model = c.defaults.Image
 	}
+	aliased := false
+	if model != nil {
+		var err error
+		if model, aliased, err = c.resolveModel("image", model); err != nil {
+			return nil, err
+		}
+	}
+	delegated := false
+	if c.imageDelegate != nil {
+		switch {
+		case model == nil:
+			model, delegated = c.imageDelegate, true
+		case !providerCapabilities[c.resolveProvider(model)][FeatureImage]:
+			c.emit(Event{
+				Type:      EventModelFallback,
+				Operation: "image",
+				Provider:  c.imageDelegate.Provider(),
+				Model:     c.imageDelegate.String(),
+				Error:     &ErrFeatureNotSupported{Provider: c.resolveProvider(model).String(), Feature: "image"},
+			})
+			model, delegated = c.imageDelegate, true
+		}
+	}
 	if model == nil {
 		return nil, &ErrNoModel{Operation: "image"}
 	}
@@ -655,21 +1127,33 @@ func (c *Client) GenerateImage(ctx context.Context, prompt string, opts ...ai.Im
 		return nil, &ErrFeatureNotSupported{Provider: provider.String(), Feature: "image"}
 	}
 
+	if err := c.checkBudget("image", provider); err != nil {
+		return nil, err
+	}
+
+	release, err := c.admit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	start := time.Now()
-	emit(c.events, Event{
+	c.emit(Event{
 		Type:      EventRequestStart,
 		Operation: "image",
 		Provider:  provider,
 	})
 
 	// Ensure model is passed to the underlying provider
-	if options.Model == nil {
+	if delegated || aliased {
+		opts = append(opts, ai.WithImageModel(model))
+	} else if options.Model == nil {
 		opts = append([]ai.ImageOption{ai.WithImageModel(model)}, opts...)
 	}
 
-	// Create retry events channel if client events are enabled
+	// Create retry events channel if client events or logging are enabled
 	var retryEvents chan retry.Event
-	if c.events != nil {
+	if c.events != nil || c.logger != nil {
 		retryEvents = make(chan retry.Event, 10)
 		go c.forwardRetryEvents(retryEvents, "image", provider)
 	}
@@ -681,7 +1165,9 @@ func (c *Client) GenerateImage(ctx context.Context, prompt string, opts ...ai.Im
 	}
 
 	resp, err := retry.DoWithEvents(ctx, retryConfig, retryEvents, func() (*ai.ImageResponse, error) {
-		return imageProvider.GenerateImage(ctx, prompt, opts...)
+		return withAttempt(c, ctx, provider, "image", func(ctx context.Context) (*ai.ImageResponse, error) {
+			return imageProvider.GenerateImage(ctx, prompt, opts...)
+		})
 	})
 
 	if retryEvents != nil {
@@ -689,7 +1175,7 @@ func (c *Client) GenerateImage(ctx context.Context, prompt string, opts ...ai.Im
 	}
 
 	if err != nil {
-		emit(c.events, Event{
+		c.emit(Event{
 			Type:      EventRequestError,
 			Operation: "image",
 			Provider:  provider,
@@ -699,12 +1185,15 @@ func (c *Client) GenerateImage(ctx context.Context, prompt string, opts ...ai.Im
 		return nil, err
 	}
 
-	emit(c.events, Event{
+	c.emit(Event{
 		Type:      EventRequestComplete,
 		Operation: "image",
 		Provider:  provider,
 		Duration:  time.Since(start),
 	})
+	if resp != nil {
+		c.recordUsage("image", provider, model, imageUsageTotals(model, options.Quality, len(resp.Images)))
+	}
 	return resp, nil
 }
 
@@ -713,6 +1202,11 @@ func (c *Client) GenerateImage(ctx context.Context, prompt string, opts ...ai.Im
 // Returns ErrFeatureNotSupported if the provider doesn't support embeddings.
 // Automatically retries on transient errors according to the client's retry configuration.
 func (c *Client) Embed(ctx context.Context, texts []string, opts ...ai.EmbeddingOption) (*ai.EmbeddingResponse, error) {
+	return c.embedFn(ctx, texts, opts...)
+}
+
+// embed implements Embed beneath the client's middleware.
+func (c *Client) embed(ctx context.Context, texts []string, opts ...ai.EmbeddingOption) (*ai.EmbeddingResponse, error) {
 	options := ai.ApplyEmbeddingOptions(opts...)
 
 	// Determine which model to use
@@ -723,6 +1217,10 @@ func (c *Client) Embed(ctx context.Context, texts []string, opts ...ai.Embedding
 	if model == nil {
 		return nil, &ErrNoModel{Operation: "embedding"}
 	}
+	model, aliased, err := c.resolveModel("embedding", model)
+	if err != nil {
+		return nil, err
+	}
 
 	// Resolve provider and check capability
 	provider := c.resolveProvider(model)
@@ -756,21 +1254,33 @@ func (c *Client) Embed(ctx context.Context, texts []string, opts ...ai.Embedding
 		return nil, &ErrFeatureNotSupported{Provider: provider.String(), Feature: "embedding"}
 	}
 
+	if err := c.checkBudget("embed", provider); err != nil {
+		return nil, err
+	}
+
+	release, err := c.admit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	start := time.Now()
-	emit(c.events, Event{
+	c.emit(Event{
 		Type:      EventRequestStart,
 		Operation: "embed",
 		Provider:  provider,
 	})
 
 	// Ensure model is passed to the underlying provider
-	if options.Model == nil {
+	if aliased {
+		opts = append(opts, ai.WithEmbeddingModel(model))
+	} else if options.Model == nil {
 		opts = append([]ai.EmbeddingOption{ai.WithEmbeddingModel(model)}, opts...)
 	}
 
-	// Create retry events channel if client events are enabled
+	// Create retry events channel if client events or logging are enabled
 	var retryEvents chan retry.Event
-	if c.events != nil {
+	if c.events != nil || c.logger != nil {
 		retryEvents = make(chan retry.Event, 10)
 		go c.forwardRetryEvents(retryEvents, "embed", provider)
 	}
@@ -782,7 +1292,9 @@ func (c *Client) Embed(ctx context.Context, texts []string, opts ...ai.Embedding
 	}
 
 	resp, err := retry.DoWithEvents(ctx, retryConfig, retryEvents, func() (*ai.EmbeddingResponse, error) {
-		return embedProvider.Embed(ctx, texts, opts...)
+		return withAttempt(c, ctx, provider, "embedding", func(ctx context.Context) (*ai.EmbeddingResponse, error) {
+			return embedProvider.Embed(ctx, texts, opts...)
+		})
 	})
 
 	if retryEvents != nil {
@@ -790,7 +1302,7 @@ func (c *Client) Embed(ctx context.Context, texts []string, opts ...ai.Embedding
 	}
 
 	if err != nil {
-		emit(c.events, Event{
+		c.emit(Event{
 			Type:      EventRequestError,
 			Operation: "embed",
 			Provider:  provider,
@@ -800,12 +1312,137 @@ func (c *Client) Embed(ctx context.Context, texts []string, opts ...ai.Embedding
 		return nil, err
 	}
 
-	emit(c.events, Event{
+	c.emit(Event{
 		Type:      EventRequestComplete,
 		Operation: "embed",
 		Provider:  provider,
 		Duration:  time.Since(start),
 	})
+	if resp != nil {
+		c.recordUsage("embed", provider, model, embeddingUsageTotals(model, resp.Usage))
+	}
+	return resp, nil
+}
+
+// Transcribe converts spoken audio to text.
+// The model can be specified via WithTranscriptionModel option, or the default transcription model is used.
+// Returns ErrFeatureNotSupported if the provider doesn't support transcription.
+// Automatically retries on transient errors according to the client's retry configuration.
+func (c *Client) Transcribe(ctx context.Context, audio []byte, opts ...ai.TranscriptionOption) (*ai.TranscriptionResponse, error) {
+	options := ai.ApplyTranscriptionOptions(opts...)
+
+	// Determine which model to use
+	model := options.Model
+	if model == nil {
+		model = c.defaults.Transcription
+	}
+	if model == nil {
+		return nil, &ErrNoModel{Operation: "transcription"}
+	}
+	model, aliased, err := c.resolveModel("transcription", model)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve provider and check capability
+	provider := c.resolveProvider(model)
+
+	if !providerCapabilities[provider][FeatureTranscription] {
+		return nil, &ErrFeatureNotSupported{Provider: provider.String(), Feature: "transcription"}
+	}
+
+	// Get the transcription provider
+	var transcriptionProvider ai.TranscriptionProvider
+	switch provider {
+	case ai.ProviderOpenAI:
+		client, err := c.getOpenAIClient()
+		if err != nil {
+			return nil, err
+		}
+		transcriptionProvider = client
+	case ai.ProviderGoogle:
+		client, err := c.getGoogleClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		transcriptionProvider = client
+	case ai.ProviderVertex:
+		client, err := c.getVertexClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		transcriptionProvider = client
+	default:
+		return nil, &ErrFeatureNotSupported{Provider: provider.String(), Feature: "transcription"}
+	}
+
+	if err := c.checkBudget("transcribe", provider); err != nil {
+		return nil, err
+	}
+
+	release, err := c.admit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	start := time.Now()
+	c.emit(Event{
+		Type:      EventRequestStart,
+		Operation: "transcribe",
+		Provider:  provider,
+	})
+
+	// Ensure model is passed to the underlying provider
+	if aliased {
+		opts = append(opts, ai.WithTranscriptionModel(model))
+	} else if options.Model == nil {
+		opts = append([]ai.TranscriptionOption{ai.WithTranscriptionModel(model)}, opts...)
+	}
+
+	// Create retry events channel if client events or logging are enabled
+	var retryEvents chan retry.Event
+	if c.events != nil || c.logger != nil {
+		retryEvents = make(chan retry.Event, 10)
+		go c.forwardRetryEvents(retryEvents, "transcribe", provider)
+	}
+
+	// Use per-call retry config if specified, otherwise use client default
+	retryConfig := c.retryConfig
+	if options.RetryConfig != nil {
+		retryConfig = toInternalRetryConfig(options.RetryConfig)
+	}
+
+	resp, err := retry.DoWithEvents(ctx, retryConfig, retryEvents, func() (*ai.TranscriptionResponse, error) {
+		return withAttempt(c, ctx, provider, "transcription", func(ctx context.Context) (*ai.TranscriptionResponse, error) {
+			return transcriptionProvider.Transcribe(ctx, audio, opts...)
+		})
+	})
+
+	if retryEvents != nil {
+		close(retryEvents)
+	}
+
+	if err != nil {
+		c.emit(Event{
+			Type:      EventRequestError,
+			Operation: "transcribe",
+			Provider:  provider,
+			Duration:  time.Since(start),
+			Error:     err,
+		})
+		return nil, err
+	}
+
+	c.emit(Event{
+		Type:      EventRequestComplete,
+		Operation: "transcribe",
+		Provider:  provider,
+		Duration:  time.Since(start),
+	})
+	if resp != nil {
+		c.recordUsage("transcribe", provider, model, transcriptionUsageTotals(model, resp))
+	}
 	return resp, nil
 }
 
@@ -819,6 +1456,8 @@ func (c *Client) SupportsFeature(f Feature) bool {
 		return c.creds.OpenAI != "" || c.creds.Google != "" || hasVertex
 	case FeatureEmbedding:
 		return c.creds.OpenAI != "" || c.creds.Google != "" || hasVertex
+	case FeatureTranscription:
+		return c.creds.OpenAI != "" || c.creds.Google != "" || hasVertex
 	default:
 		return false
 	}
@@ -829,7 +1468,7 @@ func (c *Client) SupportsFeature(f Feature) bool {
 func (c *Client) forwardRetryEvents(retryEvents <-chan retry.Event, operation string, provider ai.Provider) {
 	for re := range retryEvents {
 		reCopy := re // Copy to avoid pointer issues
-		emit(c.events, Event{
+		c.emit(Event{
 			Type:       EventRetry,
 			Operation:  operation,
 			Provider:   provider,
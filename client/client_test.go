@@ -1,10 +1,17 @@
 package client
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // testModel implements gains.Model for testing.
@@ -20,6 +27,26 @@ func TestFeatureConstants(t *testing.T) {
 	assert.Equal(t, Feature("chat"), FeatureChat)
 	assert.Equal(t, Feature("image"), FeatureImage)
 	assert.Equal(t, Feature("embedding"), FeatureEmbedding)
+	assert.Equal(t, Feature("transcription"), FeatureTranscription)
+}
+
+func TestTranscribe(t *testing.T) {
+	t.Run("requires a model", func(t *testing.T) {
+		c := New(Config{Credentials: Credentials{OpenAI: "key"}})
+		_, err := c.Transcribe(context.Background(), []byte("audio"))
+		var noModel *ErrNoModel
+		assert.ErrorAs(t, err, &noModel)
+		assert.Equal(t, "transcription", noModel.Operation)
+	})
+
+	t.Run("rejects providers without transcription", func(t *testing.T) {
+		c := New(Config{Credentials: Credentials{Anthropic: "key"}})
+		_, err := c.Transcribe(context.Background(), []byte("audio"),
+			ai.WithTranscriptionModel(testModel{id: "claude", provider: ai.ProviderAnthropic}))
+		var notSupported *ErrFeatureNotSupported
+		assert.ErrorAs(t, err, &notSupported)
+		assert.Equal(t, "transcription", notSupported.Feature)
+	})
 }
 
 func TestErrFeatureNotSupported(t *testing.T) {
@@ -81,6 +108,12 @@ func TestErrNoModel(t *testing.T) {
 		assert.Equal(t, expected, err.Error())
 	})
 
+	t.Run("transcription operation", func(t *testing.T) {
+		err := &ErrNoModel{Operation: "transcription"}
+		expected := "no model specified for transcription: set client.Config Defaults.Transcription or use gains.WithTranscriptionModel()"
+		assert.Equal(t, expected, err.Error())
+	})
+
 	t.Run("unknown operation fallback", func(t *testing.T) {
 		err := &ErrNoModel{Operation: "unknown"}
 		expected := "no model specified for unknown and no default configured"
@@ -88,6 +121,213 @@ func TestErrNoModel(t *testing.T) {
 	})
 }
 
+func TestDryRun(t *testing.T) {
+	c := New(Config{})
+	ctx := ai.WithDryRun(context.Background())
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}
+
+	t.Run("chat", func(t *testing.T) {
+		resp, err := c.Chat(ctx, messages)
+		require.NoError(t, err)
+		assert.Empty(t, resp.Content)
+	})
+
+	t.Run("chat stream", func(t *testing.T) {
+		ch, err := c.ChatStream(ctx, messages)
+		require.NoError(t, err)
+		var types []event.Type
+		for e := range ch {
+			types = append(types, e.Type)
+		}
+		assert.Equal(t, []event.Type{event.MessageStart, event.MessageEnd}, types)
+	})
+}
+
+func TestChatStreamTo(t *testing.T) {
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}
+	final := &ai.Response{Content: "Hello"}
+	stream := Middleware{ChatStream: func(next ChatStreamFunc) ChatStreamFunc {
+		return func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+			ch := make(chan event.Event, 4)
+			ch <- event.Event{Type: event.MessageStart}
+			ch <- event.Event{Type: event.MessageDelta, Delta: "Hel"}
+			ch <- event.Event{Type: event.MessageDelta, Delta: "lo"}
+			ch <- event.Event{Type: event.MessageEnd, Response: final}
+			close(ch)
+			return ch, nil
+		}
+	}}
+
+	t.Run("writes content and returns response", func(t *testing.T) {
+		c := New(Config{}, WithMiddleware(stream))
+		var sb strings.Builder
+		resp, err := c.ChatStreamTo(context.Background(), messages, &sb)
+		require.NoError(t, err)
+		assert.Equal(t, "Hello", sb.String())
+		assert.Same(t, final, resp)
+	})
+
+	t.Run("returns error starting stream", func(t *testing.T) {
+		_, err := New(Config{}).ChatStreamTo(context.Background(), messages, &strings.Builder{})
+		var noModel *ErrNoModel
+		assert.ErrorAs(t, err, &noModel)
+	})
+}
+
+// noToolsModel is a testModel that rejects tools.
+type noToolsModel struct{ testModel }
+
+func (noToolsModel) SupportsCapability(c ai.Capability) bool {
+	return c != ai.CapabilityTools
+}
+
+func TestCapabilityNegotiation(t *testing.T) {
+	ctx := context.Background()
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}
+	tools := ai.WithTools([]ai.Tool{{Name: "search"}})
+	plain := noToolsModel{testModel{id: "plain", provider: ai.ProviderOpenAI}}
+
+	t.Run("unsupported feature", func(t *testing.T) {
+		c := New(Config{})
+		_, err := c.Chat(ctx, messages, ai.WithModel(plain), tools)
+		var unsupported *ErrCapabilityUnsupported
+		require.True(t, errors.As(err, &unsupported))
+		assert.Equal(t, "plain", unsupported.Model)
+		assert.Equal(t, ai.CapabilityTools, unsupported.Capability)
+		assert.Equal(t, `model "plain" does not support tools`, err.Error())
+
+		_, err = c.ChatStream(ctx, messages, ai.WithModel(plain), tools)
+		assert.True(t, errors.As(err, &unsupported))
+	})
+
+	t.Run("fallback model", func(t *testing.T) {
+		events := make(chan Event, 10)
+		fallback := testModel{id: "capable", provider: ai.ProviderAnthropic}
+		c := New(Config{Events: events}, WithCapabilityFallback(plain, fallback))
+
+		// The fallback's provider has no credentials, so the request fails
+		// after switching models
+		_, err := c.Chat(ctx, messages, ai.WithModel(plain), tools)
+		var missing *ErrMissingAPIKey
+		require.True(t, errors.As(err, &missing))
+		assert.Equal(t, "anthropic", missing.Provider)
+
+		e := <-events
+		assert.Equal(t, EventModelFallback, e.Type)
+		assert.Equal(t, "capable", e.Model)
+	})
+
+	t.Run("supported features pass through", func(t *testing.T) {
+		c := New(Config{})
+		_, err := c.Chat(ctx, messages, ai.WithModel(plain))
+		var missing *ErrMissingAPIKey
+		assert.True(t, errors.As(err, &missing))
+	})
+}
+
+func TestImageDelegate(t *testing.T) {
+	ctx := context.Background()
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"created":1,"data":[{"url":"https://example.com/cat.png"}]}`))
+	}))
+	defer server.Close()
+
+	claude := testModel{id: "claude", provider: ai.ProviderAnthropic}
+	delegate := testModel{id: "gpt-image-1", provider: ai.ProviderOpenAI}
+	cfg := Config{
+		Credentials: Credentials{Anthropic: "key"},
+		Defaults:    Defaults{Chat: claude},
+	}
+
+	t.Run("without delegate", func(t *testing.T) {
+		c := New(cfg)
+		_, err := c.GenerateImage(ctx, "a cat")
+		var noModel *ErrNoModel
+		assert.ErrorAs(t, err, &noModel)
+
+		_, err = c.GenerateImage(ctx, "a cat", ai.WithImageModel(claude))
+		var unsupported *ErrFeatureNotSupported
+		assert.ErrorAs(t, err, &unsupported)
+	})
+
+	t.Run("no image model", func(t *testing.T) {
+		c := New(cfg, WithImageDelegate(delegate), WithOpenAIBaseURL(server.URL))
+		resp, err := c.GenerateImage(ctx, "a cat")
+		require.NoError(t, err)
+		require.Len(t, resp.Images, 1)
+		assert.Equal(t, "https://example.com/cat.png", resp.Images[0].URL)
+		assert.Equal(t, []string{"/images/generations"}, requested)
+	})
+
+	t.Run("unsupported image model", func(t *testing.T) {
+		events := make(chan Event, 10)
+		cfg := cfg
+		cfg.Events = events
+		c := New(cfg, WithImageDelegate(delegate), WithOpenAIBaseURL(server.URL))
+		_, err := c.GenerateImage(ctx, "a cat", ai.WithImageModel(claude))
+		require.NoError(t, err)
+
+		e := <-events
+		assert.Equal(t, EventModelFallback, e.Type)
+		assert.Equal(t, "image", e.Operation)
+		assert.Equal(t, "gpt-image-1", e.Model)
+		var unsupported *ErrFeatureNotSupported
+		assert.ErrorAs(t, e.Error, &unsupported)
+	})
+}
+
+func TestCountTokens(t *testing.T) {
+	ctx := context.Background()
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "How many tokens is this message?"}}
+
+	t.Run("requires a model", func(t *testing.T) {
+		_, err := New(Config{}).CountTokens(ctx, nil, messages)
+		var noModel *ErrNoModel
+		require.True(t, errors.As(err, &noModel))
+		assert.Equal(t, "no model specified for count_tokens: set client.Config Defaults.Chat or use the model argument", err.Error())
+	})
+
+	t.Run("estimates without a counting endpoint", func(t *testing.T) {
+		c := New(Config{})
+		gpt := testModel{id: "gpt", provider: ai.ProviderOpenAI}
+		n, err := c.CountTokens(ctx, gpt, messages)
+		require.NoError(t, err)
+		assert.Equal(t, ai.EstimateTokens(gpt, messages), n.Tokens)
+		assert.True(t, n.Estimated)
+
+		withTools, err := c.CountTokens(ctx, gpt, messages, ai.WithTools([]ai.Tool{{Name: "search", Description: "Search the web"}}))
+		require.NoError(t, err)
+		assert.Greater(t, withTools.Tokens, n.Tokens)
+		assert.True(t, withTools.Estimated)
+	})
+
+	t.Run("counts with the provider's endpoint", func(t *testing.T) {
+		c := New(Config{}, WithChatProvider(ai.ProviderAnthropic, tokenCountingProvider{n: 12}))
+		n, err := c.CountTokens(ctx, testModel{id: "claude", provider: ai.ProviderAnthropic}, messages)
+		require.NoError(t, err)
+		assert.Equal(t, TokenCount{Tokens: 12}, n)
+	})
+
+	t.Run("counting endpoints need credentials", func(t *testing.T) {
+		_, err := New(Config{}).CountTokens(ctx, testModel{id: "claude", provider: ai.ProviderAnthropic}, messages)
+		var missing *ErrMissingAPIKey
+		assert.True(t, errors.As(err, &missing))
+	})
+}
+
+// tokenCountingProvider counts every request as n tokens.
+type tokenCountingProvider struct {
+	modelProvider
+	n int
+}
+
+func (p tokenCountingProvider) CountTokens(ctx context.Context, messages []ai.Message, opts ...ai.Option) (int, error) {
+	return p.n, nil
+}
+
 func TestNew(t *testing.T) {
 	t.Run("creates client with API keys", func(t *testing.T) {
 		cfg := Config{
@@ -159,6 +399,18 @@ func TestSupportsFeature(t *testing.T) {
 		assert.False(t, c3.SupportsFeature(FeatureEmbedding))
 	})
 
+	t.Run("transcription supported with OpenAI or Google", func(t *testing.T) {
+		c1 := New(Config{
+			Credentials: Credentials{OpenAI: "key"},
+		})
+		assert.True(t, c1.SupportsFeature(FeatureTranscription))
+
+		c2 := New(Config{
+			Credentials: Credentials{Anthropic: "key"},
+		})
+		assert.False(t, c2.SupportsFeature(FeatureTranscription))
+	})
+
 	t.Run("unknown feature not supported", func(t *testing.T) {
 		c := New(Config{
 			Credentials: Credentials{OpenAI: "key", Anthropic: "key", Google: "key"},
@@ -173,6 +425,7 @@ func TestProviderCapabilities(t *testing.T) {
 		assert.True(t, caps[FeatureChat])
 		assert.False(t, caps[FeatureImage])
 		assert.False(t, caps[FeatureEmbedding])
+		assert.False(t, caps[FeatureTranscription])
 	})
 
 	t.Run("OpenAI has correct capabilities", func(t *testing.T) {
@@ -180,6 +433,7 @@ func TestProviderCapabilities(t *testing.T) {
 		assert.True(t, caps[FeatureChat])
 		assert.True(t, caps[FeatureImage])
 		assert.True(t, caps[FeatureEmbedding])
+		assert.True(t, caps[FeatureTranscription])
 	})
 
 	t.Run("Google has correct capabilities", func(t *testing.T) {
@@ -187,6 +441,7 @@ func TestProviderCapabilities(t *testing.T) {
 		assert.True(t, caps[FeatureChat])
 		assert.True(t, caps[FeatureImage])
 		assert.True(t, caps[FeatureEmbedding])
+		assert.True(t, caps[FeatureTranscription])
 	})
 }
 
@@ -229,6 +484,11 @@ func TestClientOptions(t *testing.T) {
 		assert.Len(t, c.defaultChatOpts, 1)
 	})
 
+	t.Run("WithEventBuffer sets stream capacity", func(t *testing.T) {
+		c := New(Config{}, WithEventBuffer(500))
+		assert.Equal(t, 500, c.eventBuffer)
+	})
+
 	t.Run("WithDefaultChatOptions adds multiple options", func(t *testing.T) {
 		c := New(Config{}, WithDefaultChatOptions(
 			ai.WithTemperature(0.5),
@@ -265,4 +525,118 @@ func TestClientOptions(t *testing.T) {
 		// Default max tokens should be preserved
 		assert.Equal(t, 100, applied.MaxTokens)
 	})
+
+	t.Run("context options apply between defaults and request options", func(t *testing.T) {
+		c := New(Config{},
+			WithDefaultTemperature(0.3),
+			WithDefaultMaxTokens(100),
+		)
+		ctx := ai.WithContextOptions(context.Background(),
+			ai.WithTemperature(0.5),
+			ai.WithMaxTokens(200),
+			ai.WithMetadata("tenant", "acme"),
+		)
+
+		applied := ai.ApplyOptions(c.chatOptions(ctx, []ai.Option{ai.WithTemperature(0.9)})...)
+
+		assert.Equal(t, 0.9, *applied.Temperature)
+		assert.Equal(t, 200, applied.MaxTokens)
+		assert.Equal(t, map[string]string{"tenant": "acme"}, applied.Metadata)
+		assert.Len(t, c.defaultChatOpts, 2)
+	})
+}
+
+func TestWrapProviderStreamStop(t *testing.T) {
+	c := New(Config{})
+	ctx, stop := ai.WithStop(context.Background())
+
+	providerCh := make(chan ai.StreamEvent)
+	sent := make(chan struct{})
+	go func() {
+		defer close(providerCh)
+		providerCh <- ai.StreamEvent{Delta: "Hello"}
+		<-sent
+		providerCh <- ai.StreamEvent{Delta: " there"}
+		providerCh <- ai.StreamEvent{Done: true, Response: &ai.Response{Content: "Hello there"}}
+	}()
+
+	released := make(chan struct{})
+	eventCh := event.NewChannel()
+	m := testModel{id: "gpt-4", provider: ai.ProviderOpenAI}
+	go c.wrapProviderStream(providerCh, eventCh, ai.ProviderOpenAI, m, ai.NewStreamAccumulator(), "", ai.StopSignal(ctx), func() { close(released) })
+
+	var final *ai.Response
+	for ev := range eventCh {
+		switch ev.Type {
+		case event.MessageDelta:
+			stop()
+			close(sent)
+		case event.RunEnd:
+			final = ev.Response
+		}
+	}
+	<-released
+	require.NotNil(t, final)
+	assert.Equal(t, "Hello", final.Content)
+	assert.Equal(t, ai.FinishReasonStopped, final.FinishReason)
+	require.NotNil(t, final.Timing)
+	assert.Equal(t, 1, final.Timing.Deltas)
+}
+
+func TestChatStream_AlreadyStopped(t *testing.T) {
+	ctx, stop := ai.WithStop(context.Background())
+	stop()
+
+	ch, err := New(Config{}).ChatStream(ctx, []ai.Message{{Role: ai.RoleUser, Content: "Hi"}})
+	require.NoError(t, err)
+	var final *ai.Response
+	var types []event.Type
+	for ev := range ch {
+		types = append(types, ev.Type)
+		if ev.Type == event.RunEnd {
+			final = ev.Response
+		}
+	}
+	assert.Equal(t, []event.Type{event.RunStart, event.MessageStart, event.MessageEnd, event.RunEnd}, types)
+	require.NotNil(t, final)
+	assert.Equal(t, ai.FinishReasonStopped, final.FinishReason)
+	assert.Empty(t, final.Content)
+	assert.Zero(t, final.Usage)
+}
+
+func TestWrapProviderStreamTiming(t *testing.T) {
+	events := make(chan Event, 10)
+	c := New(Config{Events: events})
+
+	providerCh := make(chan ai.StreamEvent, 3)
+	providerCh <- ai.StreamEvent{Delta: "Hello"}
+	providerCh <- ai.StreamEvent{Delta: " there"}
+	providerCh <- ai.StreamEvent{Done: true, Response: &ai.Response{
+		Content: "Hello there",
+		Usage:   ai.Usage{InputTokens: 5, OutputTokens: 2},
+	}}
+	close(providerCh)
+
+	eventCh := event.NewChannel()
+	m := testModel{id: "gpt-4", provider: ai.ProviderOpenAI}
+	c.wrapProviderStream(providerCh, eventCh, ai.ProviderOpenAI, m, ai.NewStreamAccumulator(), "", nil, func() {})
+
+	var final *ai.Response
+	for ev := range eventCh {
+		if ev.Type == event.RunEnd {
+			final = ev.Response
+		}
+	}
+	require.NotNil(t, final)
+	require.NotNil(t, final.Timing)
+	assert.Equal(t, 2, final.Timing.Deltas)
+	assert.Equal(t, 2, final.Timing.OutputTokens)
+	assert.LessOrEqual(t, final.Timing.TimeToFirstToken, final.Timing.Duration)
+
+	require.Len(t, events, 1)
+	e := <-events
+	assert.Equal(t, EventStreamComplete, e.Type)
+	assert.Equal(t, "gpt-4", e.Model)
+	assert.Same(t, final.Timing, e.Stream)
+	assert.Equal(t, 5, e.Usage.InputTokens)
 }
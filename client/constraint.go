@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// chatConstrained sends a chat request whose response must conform to
+// options.Constraint. Providers that enforce constraints natively get the
+// request unchanged. Otherwise the model is instructed to follow the
+// constraint, and a nonconforming response is sent back with the reason
+// until one conforms or the attempts run out, in which case the last
+// *ai.ErrConstraintViolation is returned. Usage is summed across attempts.
+func chatConstrained(ctx context.Context, chatProvider ai.ChatProvider, provider ai.Provider, messages []ai.Message, options *ai.Options, opts []ai.Option) (*ai.Response, error) {
+	constraint := options.Constraint
+	if constraint == nil || ai.SupportsConstraint(chatProvider, constraint.Kind) {
+		return chatCandidates(ctx, chatProvider, provider, messages, options, opts)
+	}
+
+	conversation := constraintMessages(messages, *constraint)
+	var usage ai.Usage
+	var violation error
+	for range constraint.Attempts() {
+		resp, err := chatProvider.Chat(ctx, conversation, opts...)
+		if err != nil {
+			return nil, err
+		}
+		usage.InputTokens += resp.Usage.InputTokens
+		usage.OutputTokens += resp.Usage.OutputTokens
+		usage.CachedInputTokens += resp.Usage.CachedInputTokens
+		usage.CacheWriteInputTokens += resp.Usage.CacheWriteInputTokens
+
+		if violation = constraint.Check(resp.Content); violation == nil {
+			resp.Usage = usage
+			return resp, nil
+		}
+		conversation = append(conversation,
+			ai.Message{Role: ai.RoleAssistant, Content: resp.Content},
+			ai.Message{Role: ai.RoleUser, Content: fmt.Sprintf("That response is invalid: %v. Reply again with only the required output.", violation)},
+		)
+	}
+	return nil, violation
+}
+
+// constraintMessages prepends a system message instructing the model to
+// follow constraint.
+func constraintMessages(messages []ai.Message, constraint ai.OutputConstraint) []ai.Message {
+	result := make([]ai.Message, 0, len(messages)+1)
+	result = append(result, ai.Message{Role: ai.RoleSystem, Content: constraint.Instructions()})
+	return append(result, messages...)
+}
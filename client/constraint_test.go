@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedProvider answers Chat calls with replies in order and records the
+// messages it was sent.
+type scriptedProvider struct {
+	replies  []string
+	received [][]ai.Message
+	native   bool
+}
+
+func (p *scriptedProvider) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	p.received = append(p.received, messages)
+	reply := p.replies[len(p.received)-1]
+	return &ai.Response{Content: reply, Usage: ai.Usage{InputTokens: 4, OutputTokens: 1}}, nil
+}
+
+func (p *scriptedProvider) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan ai.StreamEvent, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *scriptedProvider) SupportsConstraint(kind ai.ConstraintKind) bool {
+	return p.native
+}
+
+func TestChatConstrained(t *testing.T) {
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "Order ID?"}}
+	opts := []ai.Option{ai.WithConstrainedOutput(ai.Regex(`ORD-\d+`))}
+	options := ai.ApplyOptions(opts...)
+
+	t.Run("retries until the response conforms", func(t *testing.T) {
+		p := &scriptedProvider{replies: []string{"The ID is ORD-12", "ORD-12"}}
+		resp, err := chatConstrained(context.Background(), p, ai.ProviderAnthropic, messages, options, opts)
+		require.NoError(t, err)
+		assert.Equal(t, "ORD-12", resp.Content)
+		assert.Equal(t, ai.Usage{InputTokens: 8, OutputTokens: 2}, resp.Usage)
+
+		require.Len(t, p.received, 2)
+		assert.Equal(t, ai.RoleSystem, p.received[0][0].Role)
+		retry := p.received[1]
+		require.Len(t, retry, 4)
+		assert.Equal(t, "The ID is ORD-12", retry[2].Content)
+		assert.Contains(t, retry[3].Content, "invalid")
+	})
+
+	t.Run("fails after the last attempt", func(t *testing.T) {
+		p := &scriptedProvider{replies: []string{"a", "b", "c"}}
+		_, err := chatConstrained(context.Background(), p, ai.ProviderAnthropic, messages, options, opts)
+		var violation *ai.ErrConstraintViolation
+		require.ErrorAs(t, err, &violation)
+		assert.Equal(t, "c", violation.Content)
+		assert.Len(t, p.received, 3)
+	})
+
+	t.Run("native providers get the request unchanged", func(t *testing.T) {
+		p := &scriptedProvider{replies: []string{"ORD-1"}, native: true}
+		_, err := chatConstrained(context.Background(), p, ai.ProviderOpenAI, messages, options, opts)
+		require.NoError(t, err)
+		assert.Equal(t, messages, p.received[0])
+	})
+}
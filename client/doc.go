@@ -38,6 +38,19 @@
 //	// Override with Gemini (routes to Google)
 //	resp, _ := c.Chat(ctx, messages, ai.WithModel(model.Gemini25Flash))
 //
+// A model.Alias is resolved through Config.Aliases and model.DefaultAliases
+// before routing, so deployments can swap the model behind a name without
+// code changes:
+//
+//	c := client.New(client.Config{
+//	    Aliases: map[string]ai.Model{"cheap-chat": model.GPT5Nano},
+//	})
+//	resp, _ := c.Chat(ctx, messages, ai.WithModel(model.Alias("cheap-chat")))
+//
+// An unknown alias fails with ErrUnknownAlias. The first request to a model
+// its provider has deprecated emits EventModelDeprecated, whose Error is an
+// ErrModelDeprecated naming the retirement date and replacement.
+//
 // # Feature Detection
 //
 // Check provider capabilities before use:
@@ -50,11 +63,53 @@
 //
 // Feature support by provider:
 //
-//	| Provider  | Chat | Embeddings | Images |
-//	|-----------|------|------------|--------|
-//	| Anthropic | Yes  | No         | No     |
-//	| OpenAI    | Yes  | Yes        | Yes    |
-//	| Google    | Yes  | Yes        | Yes    |
+//	| Provider  | Chat | Embeddings | Images | Transcription |
+//	|-----------|------|------------|--------|---------------|
+//	| Anthropic | Yes  | No         | No     | No            |
+//	| OpenAI    | Yes  | Yes        | Yes    | Yes           |
+//	| Google    | Yes  | Yes        | Yes    | Yes           |
+//
+// WithImageDelegate sends image requests that would go to a provider
+// without image generation, such as those of an Anthropic-only setup, to a
+// configured OpenAI or Google image model instead:
+//
+//	c := client.New(cfg, client.WithImageDelegate(model.GPTImage1))
+//
+// # Model Capabilities
+//
+// Before sending a chat request, the client checks that the model supports
+// the features it uses: tools, images in messages, and response schemas.
+// A request the model cannot serve fails with ErrCapabilityUnsupported
+// instead of a provider error. WithCapabilityFallback names models to use
+// in that case:
+//
+//	c := client.New(cfg, client.WithCapabilityFallback(model.GPT52))
+//
+// # Token Counting
+//
+// CountTokens counts the input tokens of a request before sending it, using
+// the provider's counting endpoint where one exists and the offline
+// estimators of the tokenizer package otherwise. Offline counts are marked
+// Estimated:
+//
+//	n, err := c.CountTokens(ctx, model.ClaudeSonnet45, messages)
+//	if n.Estimated {
+//	    limit -= limit / 10 // leave headroom for estimation error
+//	}
+//
+// # Transcription
+//
+// Transcribe converts speech to text, so voice input can feed a chat or agent:
+//
+//	audio, _ := os.ReadFile("question.wav")
+//	resp, err := c.Transcribe(ctx, audio,
+//	    ai.WithTranscriptionModel(model.GPT4oMiniTranscribe),
+//	    ai.WithTranscriptionLanguage("en"),
+//	)
+//	messages := []ai.Message{{Role: ai.RoleUser, Content: resp.Text}}
+//
+// The audio format is detected from the data; use ai.WithAudioMimeType to set
+// it explicitly. Gemini models transcribe via audio understanding.
 //
 // # Retry Configuration
 //
@@ -70,6 +125,58 @@
 //	    },
 //	})
 //
+// # Request Timeouts
+//
+// Config.RequestTimeouts bounds each attempt of a provider request, so a
+// hung call fails with ErrRequestTimeout and is retried even when the
+// caller's context allows minutes. Connect bounds the wait for response
+// headers and Overall the whole attempt, with overrides per provider and
+// per operation:
+//
+//	c := client.New(client.Config{
+//	    RequestTimeouts: client.RequestTimeouts{
+//	        Default:   client.Timeouts{Connect: 10 * time.Second, Overall: 2 * time.Minute},
+//	        Providers: map[ai.Provider]client.Timeouts{ai.ProviderGoogle: {Overall: time.Minute}},
+//	    },
+//	})
+//
+// # Vertex AI Regions
+//
+// The client keeps one connection per Vertex AI location, created on first
+// use. VertexConfig.Regions lists further locations chat requests may use;
+// gains.WithRegion picks one per request, and WithRegionRouting sends other
+// requests to the healthy location with the lowest observed latency:
+//
+//	c := client.New(client.Config{
+//	    Credentials: client.Credentials{Vertex: client.VertexConfig{
+//	        Project:  "my-project",
+//	        Location: "us-central1",
+//	        Regions:  []string{"europe-west4", "asia-northeast1"},
+//	    }},
+//	}, client.WithRegionRouting())
+//
+// A location whose request fails with a transient error, such as a 429 or
+// 503, is skipped for 30 seconds. The Gemini API used with a Google API key
+// is global and has no locations.
+//
+// # Middleware
+//
+// [WithMiddleware] wraps Chat, ChatStream, Embed, and GenerateImage with
+// interceptors for logging, redaction, caching, or guardrails. Each
+// [Middleware] field wraps the next handler of one operation; the first
+// middleware is outermost. [LoggingMiddleware] and [TimingMiddleware] are
+// built in, and [ChatMiddleware] adapts a function that rewrites chat
+// requests:
+//
+//	c := client.New(cfg, client.WithMiddleware(
+//	    client.LoggingMiddleware(slog.Default()),
+//	    client.ChatMiddleware(redactPII),
+//	))
+//
+// The otel package provides middleware for OpenTelemetry tracing and
+// metrics, and the redact package middleware that replaces personal data
+// with reversible placeholders.
+//
 // # Events
 //
 // Observe operations via an event channel:
@@ -85,4 +192,138 @@
 //	        fmt.Printf("[%s] %s took %v\n", e.Type, e.Operation, e.Duration)
 //	    }
 //	}()
+//
+// For ChatStream, EventRequestComplete fires once the stream is open and
+// EventStreamComplete fires when it finishes, carrying time-to-first-token,
+// total duration, and output tokens per second in Event.Stream. The same
+// statistics are set on the final Response.Timing.
+//
+// Completed chat requests and streams also carry the provider's request ID,
+// served model version, and rate-limit state in Event.ProviderMetadata,
+// which is also set on Response.ProviderMetadata. Alert before a limit is
+// reached with [gains.RateLimit.Remaining]:
+//
+//	if md := e.ProviderMetadata; md != nil && md.RateLimit != nil && md.RateLimit.Remaining() < 0.1 {
+//	    log.Printf("rate limit nearly exhausted (request %s)", md.RequestID)
+//	}
+//
+// # Logging
+//
+// [WithLogger] logs the same events with slog, including retries and token
+// usage, for operators who do not consume the event channel:
+//
+//	c := client.New(cfg, client.WithLogger(slog.Default()))
+//
+// Requests are logged at debug level, retries at warn, and failures at
+// error; [WithLogLevels] changes them. Message contents are never logged.
+//
+// # Usage Tracking
+//
+// Aggregate token usage and cost across requests with a [UsageTracker]:
+//
+//	tracker := client.NewUsageTracker()
+//	c := client.New(cfg, client.WithUsageTracker(tracker))
+//
+//	// ... make requests ...
+//
+//	snap := tracker.Snapshot()
+//	fmt.Printf("total: $%.4f\n", snap.Total.Cost)
+//	for model, totals := range snap.ByModel() {
+//	    fmt.Printf("%s: %d in, %d out\n", model, totals.InputTokens, totals.OutputTokens)
+//	}
+//	tracker.Reset()
+//
+// Costs use the pricing of the model constants in the model package, including
+// cached input and long context tiers. Models without pricing are tracked by
+// token count only.
+//
+// # Request Transcripts
+//
+// Record the exact provider payloads and raw responses to debug message
+// conversion with [WithTranscript]. Credentials are always redacted; add
+// [WithRedactedFields] or [WithRedactor] to scrub bodies:
+//
+//	f, _ := os.Create("transcript.jsonl")
+//	c := client.New(cfg, client.WithTranscript(
+//	    client.JSONLinesSink(f),
+//	    client.WithRedactedFields("data"), // drop base64 image payloads
+//	))
+//
+// # Budgets
+//
+// Cap spending with [WithBudget] (USD) or [WithTokenBudget]. Once the usage
+// tracker's totals reach a budget, requests fail with *gains.ErrBudgetExceeded
+// and an EventBudgetExceeded event is emitted:
+//
+//	c := client.New(cfg, client.WithBudget(5.00))
+//	_, err := c.Chat(ctx, messages)
+//	var budgetErr *gains.ErrBudgetExceeded
+//	if errors.As(err, &budgetErr) {
+//	    // stop issuing requests
+//	}
+//
+// # Admission Control
+//
+// When one client serves both users and bulk jobs, [WithAdmissionControl]
+// caps in-flight requests and admits queued interactive requests before
+// background ones. Requests are interactive unless marked with [WithPriority]:
+//
+//	c := client.New(cfg, client.WithAdmissionControl(8))
+//
+//	// Eval or backfill jobs yield to chat traffic.
+//	bg := client.WithPriority(ctx, client.PriorityBackground)
+//	resp, err := c.Embed(bg, texts)
+//
+// # Response Caching
+//
+// [WithResponseCache] answers repeated deterministic chat requests, those
+// with a temperature of 0, from memory, which makes evaluation re-runs and
+// tests cheap. Entries expire after a TTL and the least recently used are
+// evicted beyond a size limit. [EventCacheHit] and [EventCacheMiss] report
+// each lookup:
+//
+//	c := client.New(cfg,
+//	    client.WithDefaultTemperature(0),
+//	    client.WithResponseCache(24*time.Hour, 500),
+//	)
+//
+// # Multiple Candidates
+//
+// ai.WithCandidateCount asks Chat for several alternative completions, for
+// best-of-N selection or judge-based evaluation. OpenAI and Google sample
+// them in one request; for Anthropic the client sends concurrent requests
+// and sums their usage:
+//
+//	resp, err := c.Chat(ctx, messages,
+//	    ai.WithTemperature(1),
+//	    ai.WithCandidateCount(4),
+//	)
+//	for _, content := range resp.CandidateContents() {
+//	    fmt.Println(content)
+//	}
+//
+// # Constrained Output
+//
+// ai.WithConstrainedOutput restricts a response to a regular expression or
+// EBNF grammar. OpenAI-compatible servers set with [WithOpenAIBaseURL], such
+// as vLLM, enforce it while decoding. Other providers are instructed to
+// follow it, and Chat validates the response, sending invalid ones back for
+// correction before failing with *ai.ErrConstraintViolation:
+//
+//	resp, err := c.Chat(ctx, messages,
+//	    ai.WithConstrainedOutput(ai.Regex(`[A-Z]{3}-\d{4}`)),
+//	)
+//
+// # Schema Retries
+//
+// ai.WithSchemaRetry repairs responses to a response schema request, such as
+// JSON wrapped in a code fence or followed by commentary, and validates them
+// against the schema. Invalid responses are sent back to the model with the
+// validation errors, firing EventSchemaRetry, before Chat fails with
+// *ai.ErrSchemaViolation:
+//
+//	resp, err := c.Chat(ctx, messages,
+//	    ai.WithResponseSchema(schema),
+//	    ai.WithSchemaRetry(2),
+//	)
 package client
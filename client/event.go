@@ -21,6 +21,40 @@ const (
 
 	// EventRetry fires when a retry event occurs (forwarded from retry package).
 	EventRetry EventType = "retry"
+
+	// EventSchemaRetry fires when a chat response fails validation against
+	// the response schema and is sent back to the model, as enabled with
+	// ai.WithSchemaRetry. Error holds the validation errors and RetryEvent
+	// the attempt number.
+	EventSchemaRetry EventType = "schema_retry"
+
+	// EventStreamComplete fires when a streaming response finishes
+	// successfully. Duration covers the whole stream and Stream holds
+	// time-to-first-token and throughput.
+	EventStreamComplete EventType = "stream_complete"
+
+	// EventBudgetExceeded fires when a request is rejected because the
+	// client's cost or token budget has been reached.
+	EventBudgetExceeded EventType = "budget_exceeded"
+
+	// EventModelFallback fires when a chat request is sent to a fallback
+	// model because the selected model lacks a feature it uses. Model names
+	// the fallback and Error describes the missing feature.
+	EventModelFallback EventType = "model_fallback"
+
+	// EventModelDeprecated fires the first time a client uses a model its
+	// provider has deprecated, per model.Deprecated. Model names the model
+	// and Error is an *ErrModelDeprecated with the retirement date and
+	// replacement. The request is still sent.
+	EventModelDeprecated EventType = "model_deprecated"
+
+	// EventCacheHit fires when a chat request is answered from the response
+	// cache enabled with WithResponseCache.
+	EventCacheHit EventType = "cache_hit"
+
+	// EventCacheMiss fires when a cacheable chat request is not in the
+	// response cache and is sent to the provider.
+	EventCacheMiss EventType = "cache_miss"
 )
 
 // Event represents an observable occurrence during client operations.
@@ -28,7 +62,7 @@ type Event struct {
 	// Type identifies the kind of event.
 	Type EventType
 
-	// Operation identifies the API operation ("chat", "chat_stream", "embed", "image").
+	// Operation identifies the API operation ("chat", "chat_stream", "embed", "image", "transcribe").
 	Operation string
 
 	// Provider identifies which AI provider is being used.
@@ -43,6 +77,18 @@ type Event struct {
 	// Usage contains token usage information (for chat operations).
 	Usage *ai.Usage
 
+	// Stream contains timing statistics for EventStreamComplete.
+	Stream *ai.StreamStats
+
+	// ProviderMetadata contains the request ID, model version, and
+	// rate-limit state the provider reported, for EventRequestComplete of
+	// chat requests and EventStreamComplete.
+	ProviderMetadata *ai.ProviderMetadata
+
+	// Metadata contains the request metadata set with ai.WithMetadata
+	// (for chat operations).
+	Metadata map[string]string
+
 	// Error contains the error for EventRequestError.
 	Error error
 
@@ -53,14 +99,18 @@ type Event struct {
 	Timestamp time.Time
 }
 
-// emit sends an event with timestamp to the channel without blocking.
-func emit(ch chan<- Event, event Event) {
-	if ch == nil {
+// emit stamps an event, logs it if the client has a logger, and sends it to
+// the client's event channel without blocking.
+func (c *Client) emit(event Event) {
+	event.Timestamp = time.Now()
+	if c.logger != nil {
+		c.log(event)
+	}
+	if c.events == nil {
 		return
 	}
-	event.Timestamp = time.Now()
 	select {
-	case ch <- event:
+	case c.events <- event:
 	default:
 		// Channel full - don't block
 	}
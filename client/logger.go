@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/spetersoncode/gains/event"
+)
+
+// WithLogger logs the client's events to logger, so operators get request
+// durations, token usage, retries, and failures without consuming
+// Config.Events. Requests and cache lookups are logged at the Step level of
+// WithLogLevels, retries, model fallbacks, and deprecated models at the
+// Retry level, and
+// failures and exceeded budgets at the Error level. Message contents are
+// never logged; see LoggingMiddleware to log each operation's outcome
+// instead.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithLogLevels sets the levels used by WithLogger. Default is
+// event.DefaultLogLevels.
+func WithLogLevels(levels event.LogLevels) ClientOption {
+	return func(c *Client) {
+		c.logLevels = levels
+	}
+}
+
+// log writes e to the client's logger.
+func (c *Client) log(e Event) {
+	level, msg, ok := c.logRecord(e)
+	ctx := context.Background()
+	if !ok || !c.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("event", string(e.Type)),
+		slog.String("operation", e.Operation),
+	}
+	if e.Provider != "" {
+		attrs = append(attrs, slog.String("provider", e.Provider.String()))
+	}
+	if e.Model != "" {
+		attrs = append(attrs, slog.String("model", e.Model))
+	}
+	if e.Duration > 0 {
+		attrs = append(attrs, slog.Duration("duration", e.Duration))
+	}
+	if e.Usage != nil {
+		attrs = append(attrs,
+			slog.Int("input_tokens", e.Usage.InputTokens),
+			slog.Int("output_tokens", e.Usage.OutputTokens),
+		)
+	}
+	if md := e.ProviderMetadata; md != nil {
+		if md.RequestID != "" {
+			attrs = append(attrs, slog.String("request_id", md.RequestID))
+		}
+		if md.RateLimit != nil {
+			attrs = append(attrs, slog.Float64("rate_limit_remaining", md.RateLimit.Remaining()))
+		}
+	}
+	if e.Stream != nil {
+		attrs = append(attrs,
+			slog.Duration("time_to_first_token", e.Stream.TimeToFirstToken),
+			slog.Float64("tokens_per_second", e.Stream.TokensPerSecond),
+		)
+	}
+	if re := e.RetryEvent; re != nil {
+		attrs = append(attrs,
+			slog.Int("attempt", re.Attempt),
+			slog.Int("max_attempts", re.MaxAttempts),
+		)
+		if re.Delay > 0 {
+			attrs = append(attrs, slog.Duration("delay", re.Delay))
+		}
+		if re.Error != nil {
+			attrs = append(attrs, slog.Any("error", re.Error))
+		}
+	}
+	if e.Error != nil {
+		attrs = append(attrs, slog.Any("error", e.Error))
+	}
+	c.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// logRecord returns the level and message of e, and false if it is not
+// logged.
+func (c *Client) logRecord(e Event) (slog.Level, string, bool) {
+	switch e.Type {
+	case EventRequestStart:
+		return c.logLevels.Step, "request started", true
+	case EventRequestComplete:
+		return c.logLevels.Step, "request finished", true
+	case EventStreamComplete:
+		return c.logLevels.Step, "stream finished", true
+	case EventCacheHit:
+		return c.logLevels.Step, "cache hit", true
+	case EventCacheMiss:
+		return c.logLevels.Step, "cache miss", true
+	case EventModelFallback:
+		return c.logLevels.Retry, "model fallback", true
+	case EventModelDeprecated:
+		return c.logLevels.Retry, "model deprecated", true
+	case EventSchemaRetry:
+		return c.logLevels.Retry, "retrying invalid structured output", true
+	case EventRequestError:
+		return c.logLevels.Error, "request failed", true
+	case EventBudgetExceeded:
+		return c.logLevels.Error, "budget exceeded", true
+	case EventRetry:
+		switch e.RetryEvent.Type {
+		case RetryEventRetrying:
+			return c.logLevels.Retry, "retrying request", true
+		case RetryEventExhausted:
+			return c.logLevels.Retry, "retries exhausted", true
+		}
+	}
+	return 0, "", false
+}
@@ -0,0 +1,61 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	events := make(chan Event, 10)
+	c := New(Config{Events: events}, WithLogger(logger))
+
+	t.Run("logs requests with usage", func(t *testing.T) {
+		buf.Reset()
+		c.emit(Event{
+			Type:      EventRequestComplete,
+			Operation: "chat",
+			Provider:  ai.ProviderOpenAI,
+			Duration:  time.Second,
+			Usage:     &ai.Usage{InputTokens: 12, OutputTokens: 3},
+		})
+		out := buf.String()
+		assert.Contains(t, out, `level=DEBUG msg="request finished"`)
+		assert.Contains(t, out, "provider=openai")
+		assert.Contains(t, out, "input_tokens=12")
+		assert.Len(t, events, 1, "events are still sent")
+	})
+
+	t.Run("logs retries", func(t *testing.T) {
+		buf.Reset()
+		c.emit(Event{Type: EventRetry, Operation: "chat", RetryEvent: &RetryEvent{Type: RetryEventAttemptStart, Attempt: 1}})
+		assert.Empty(t, buf.String())
+
+		c.emit(Event{Type: EventRetry, Operation: "chat", RetryEvent: &RetryEvent{
+			Type:        RetryEventRetrying,
+			Attempt:     1,
+			MaxAttempts: 3,
+			Delay:       time.Second,
+			Error:       errors.New("rate limited"),
+		}})
+		assert.Contains(t, buf.String(), `level=WARN msg="retrying request"`)
+		assert.Contains(t, buf.String(), "attempt=1")
+	})
+
+	t.Run("uses configured levels", func(t *testing.T) {
+		buf.Reset()
+		levels := event.DefaultLogLevels()
+		levels.Error = slog.LevelWarn
+		c := New(Config{}, WithLogger(logger), WithLogLevels(levels))
+		c.emit(Event{Type: EventRequestError, Operation: "embed", Error: errors.New("boom")})
+		assert.Contains(t, buf.String(), `level=WARN msg="request failed"`)
+	})
+}
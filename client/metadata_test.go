@@ -0,0 +1,103 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rateLimitedServer serves OpenAI chat completions with request ID and
+// rate-limit headers.
+func rateLimitedServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("x-request-id", "req_123")
+		h.Set("x-ratelimit-limit-requests", "500")
+		h.Set("x-ratelimit-remaining-requests", "499")
+		h.Set("x-ratelimit-reset-requests", "120ms")
+		h.Set("x-ratelimit-limit-tokens", "30000")
+		h.Set("x-ratelimit-remaining-tokens", "3000")
+		h.Set("x-ratelimit-reset-tokens", "6m0s")
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"stream":true`) {
+			h.Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":"c1","object":"chat.completion","model":"gpt-5-2025-08-07","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"}],"usage":{"prompt_tokens":5,"completion_tokens":1}}`))
+			return
+		}
+		h.Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: {\"id\":\"c1\",\"object\":\"chat.completion.chunk\",\"model\":\"gpt-5-2025-08-07\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n"))
+		w.Write([]byte("data: {\"id\":\"c1\",\"object\":\"chat.completion.chunk\",\"model\":\"gpt-5-2025-08-07\",\"choices\":[{\"index\":0,\"delta\":{},\"finish_reason\":\"stop\"}],\"usage\":{\"prompt_tokens\":5,\"completion_tokens\":1}}\n\n"))
+		w.Write([]byte("data: [DONE]\n\n"))
+	}))
+}
+
+// waitForEvent returns the first event of type typ from events.
+func waitForEvent(t *testing.T, events <-chan Event, typ EventType) Event {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case e := <-events:
+			if e.Type == typ {
+				return e
+			}
+		case <-timeout:
+			t.Fatalf("no %s event", typ)
+		}
+	}
+}
+
+func TestProviderMetadata(t *testing.T) {
+	server := rateLimitedServer()
+	defer server.Close()
+
+	assertMetadata := func(t *testing.T, md *ai.ProviderMetadata) {
+		t.Helper()
+		require.NotNil(t, md)
+		assert.Equal(t, "req_123", md.RequestID)
+		assert.Equal(t, "gpt-5-2025-08-07", md.Model)
+		require.NotNil(t, md.RateLimit)
+		assert.Equal(t, 500, md.RateLimit.RequestsLimit)
+		assert.Equal(t, 499, md.RateLimit.RequestsRemaining)
+		assert.Equal(t, 3000, md.RateLimit.TokensRemaining)
+		assert.WithinDuration(t, time.Now().Add(6*time.Minute), md.RateLimit.TokensReset, 5*time.Second)
+		assert.InDelta(t, 0.1, md.RateLimit.Remaining(), 1e-9)
+		assert.Equal(t, "req_123", md.Header.Get("X-Request-Id"))
+	}
+
+	t.Run("chat", func(t *testing.T) {
+		events := make(chan Event, 10)
+		c := New(Config{Defaults: Defaults{Chat: model.GPT5}, Events: events}, WithOpenAIBaseURL(server.URL))
+
+		resp, err := c.Chat(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Hi"}})
+		require.NoError(t, err)
+		assertMetadata(t, resp.ProviderMetadata)
+
+		complete := waitForEvent(t, events, EventRequestComplete)
+		assert.Same(t, resp.ProviderMetadata, complete.ProviderMetadata)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		events := make(chan Event, 10)
+		c := New(Config{Defaults: Defaults{Chat: model.GPT5}, Events: events}, WithOpenAIBaseURL(server.URL))
+
+		ch, err := c.ChatStream(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Hi"}})
+		require.NoError(t, err)
+		resp, _, err := event.Accumulate(ch)
+		require.NoError(t, err)
+		assertMetadata(t, resp.ProviderMetadata)
+
+		complete := waitForEvent(t, events, EventStreamComplete)
+		assertMetadata(t, complete.ProviderMetadata)
+	})
+}
@@ -0,0 +1,239 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+)
+
+// ChatFunc is the signature of Client.Chat.
+type ChatFunc func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error)
+
+// ChatStreamFunc is the signature of Client.ChatStream.
+type ChatStreamFunc func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error)
+
+// EmbedFunc is the signature of Client.Embed.
+type EmbedFunc func(ctx context.Context, texts []string, opts ...ai.EmbeddingOption) (*ai.EmbeddingResponse, error)
+
+// ImageFunc is the signature of Client.GenerateImage.
+type ImageFunc func(ctx context.Context, prompt string, opts ...ai.ImageOption) (*ai.ImageResponse, error)
+
+// Middleware intercepts client operations, for logging, redaction, caching,
+// or guardrails. Each field wraps the next handler of one operation and may
+// inspect or rewrite the request, short-circuit it, or post-process the
+// result. Nil fields leave their operation unchanged.
+type Middleware struct {
+	Chat       func(next ChatFunc) ChatFunc
+	ChatStream func(next ChatStreamFunc) ChatStreamFunc
+	Embed      func(next EmbedFunc) EmbedFunc
+	Image      func(next ImageFunc) ImageFunc
+}
+
+// ChatMiddleware returns a Middleware that wraps both Chat and ChatStream
+// requests with fn, for middleware that only needs to inspect or rewrite
+// the conversation and options, such as redaction:
+//
+//	redact := client.ChatMiddleware(func(ctx context.Context, msgs []ai.Message, opts []ai.Option) (context.Context, []ai.Message, []ai.Option, error) {
+//	    return ctx, scrub(msgs), opts, nil
+//	})
+//
+// A non-nil error from fn fails the request without calling the provider.
+func ChatMiddleware(fn func(ctx context.Context, messages []ai.Message, opts []ai.Option) (context.Context, []ai.Message, []ai.Option, error)) Middleware {
+	return Middleware{
+		Chat: func(next ChatFunc) ChatFunc {
+			return func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+				ctx, messages, opts, err := fn(ctx, messages, opts)
+				if err != nil {
+					return nil, err
+				}
+				return next(ctx, messages, opts...)
+			}
+		},
+		ChatStream: func(next ChatStreamFunc) ChatStreamFunc {
+			return func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+				ctx, messages, opts, err := fn(ctx, messages, opts)
+				if err != nil {
+					return nil, err
+				}
+				return next(ctx, messages, opts...)
+			}
+		},
+	}
+}
+
+// WithMiddleware adds middleware around Chat, ChatStream, Embed, and
+// GenerateImage. The first middleware is outermost: it sees each request
+// first and each result last. Middleware runs before the client's own
+// handling, so it also sees dry runs and cached responses.
+func WithMiddleware(mw ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, mw...)
+	}
+}
+
+// buildMiddleware wraps the client's operations in its middleware.
+func (c *Client) buildMiddleware() {
+	c.chatFn = c.chat
+	c.chatStreamFn = c.chatStream
+	c.embedFn = c.embed
+	c.imageFn = c.generateImage
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		mw := c.middleware[i]
+		if mw.Chat != nil {
+			c.chatFn = mw.Chat(c.chatFn)
+		}
+		if mw.ChatStream != nil {
+			c.chatStreamFn = mw.ChatStream(c.chatStreamFn)
+		}
+		if mw.Embed != nil {
+			c.embedFn = mw.Embed(c.embedFn)
+		}
+		if mw.Image != nil {
+			c.imageFn = mw.Image(c.imageFn)
+		}
+	}
+}
+
+// TimingMiddleware reports the duration and outcome of every operation to
+// fn. The operation is "chat", "chat_stream", "embedding", or "image"; a
+// stream is timed until its channel closes, and err is set if it ended with
+// a RunError event.
+func TimingMiddleware(fn func(operation string, d time.Duration, err error)) Middleware {
+	return Middleware{
+		Chat: func(next ChatFunc) ChatFunc {
+			return func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+				start := time.Now()
+				resp, err := next(ctx, messages, opts...)
+				fn("chat", time.Since(start), err)
+				return resp, err
+			}
+		},
+		ChatStream: func(next ChatStreamFunc) ChatStreamFunc {
+			return func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+				start := time.Now()
+				ch, err := next(ctx, messages, opts...)
+				if err != nil {
+					fn("chat_stream", time.Since(start), err)
+					return nil, err
+				}
+				return observeStream(ch, func(streamErr error) {
+					fn("chat_stream", time.Since(start), streamErr)
+				}), nil
+			}
+		},
+		Embed: func(next EmbedFunc) EmbedFunc {
+			return func(ctx context.Context, texts []string, opts ...ai.EmbeddingOption) (*ai.EmbeddingResponse, error) {
+				start := time.Now()
+				resp, err := next(ctx, texts, opts...)
+				fn("embedding", time.Since(start), err)
+				return resp, err
+			}
+		},
+		Image: func(next ImageFunc) ImageFunc {
+			return func(ctx context.Context, prompt string, opts ...ai.ImageOption) (*ai.ImageResponse, error) {
+				start := time.Now()
+				resp, err := next(ctx, prompt, opts...)
+				fn("image", time.Since(start), err)
+				return resp, err
+			}
+		},
+	}
+}
+
+// LoggingMiddleware logs every operation to logger: its duration and token
+// usage at debug level on success, and the error at error level on failure.
+// Message contents are never logged.
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	log := func(ctx context.Context, operation string, start time.Time, err error, attrs ...slog.Attr) {
+		attrs = append(attrs,
+			slog.String("operation", operation),
+			slog.Duration("duration", time.Since(start)),
+		)
+		if err != nil {
+			attrs = append(attrs, slog.Any("error", err))
+			logger.LogAttrs(ctx, slog.LevelError, "gains request failed", attrs...)
+			return
+		}
+		logger.LogAttrs(ctx, slog.LevelDebug, "gains request", attrs...)
+	}
+	usageAttrs := func(u ai.Usage) []slog.Attr {
+		return []slog.Attr{
+			slog.Int("input_tokens", u.InputTokens),
+			slog.Int("output_tokens", u.OutputTokens),
+		}
+	}
+
+	return Middleware{
+		Chat: func(next ChatFunc) ChatFunc {
+			return func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+				start := time.Now()
+				resp, err := next(ctx, messages, opts...)
+				var attrs []slog.Attr
+				if resp != nil {
+					attrs = usageAttrs(resp.Usage)
+				}
+				log(ctx, "chat", start, err, append(attrs, slog.Int("messages", len(messages)))...)
+				return resp, err
+			}
+		},
+		ChatStream: func(next ChatStreamFunc) ChatStreamFunc {
+			return func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+				start := time.Now()
+				ch, err := next(ctx, messages, opts...)
+				if err != nil {
+					log(ctx, "chat_stream", start, err)
+					return nil, err
+				}
+				var usage ai.Usage
+				return observeStream(ch, func(streamErr error) {
+					log(ctx, "chat_stream", start, streamErr, append(usageAttrs(usage), slog.Int("messages", len(messages)))...)
+				}, func(e event.Event) {
+					if e.Type == event.MessageEnd && e.Response != nil {
+						usage = e.Response.Usage
+					}
+				}), nil
+			}
+		},
+		Embed: func(next EmbedFunc) EmbedFunc {
+			return func(ctx context.Context, texts []string, opts ...ai.EmbeddingOption) (*ai.EmbeddingResponse, error) {
+				start := time.Now()
+				resp, err := next(ctx, texts, opts...)
+				log(ctx, "embedding", start, err, slog.Int("texts", len(texts)))
+				return resp, err
+			}
+		},
+		Image: func(next ImageFunc) ImageFunc {
+			return func(ctx context.Context, prompt string, opts ...ai.ImageOption) (*ai.ImageResponse, error) {
+				start := time.Now()
+				resp, err := next(ctx, prompt, opts...)
+				log(ctx, "image", start, err)
+				return resp, err
+			}
+		},
+	}
+}
+
+// observeStream forwards the events of ch to a new channel, passing each to
+// the inspect functions, and calls done with the stream's error, if any,
+// once ch is closed.
+func observeStream(ch <-chan event.Event, done func(err error), inspect ...func(event.Event)) <-chan event.Event {
+	out := make(chan event.Event, cap(ch))
+	go func() {
+		defer close(out)
+		var err error
+		for e := range ch {
+			if e.Type == event.RunError {
+				err = e.Error
+			}
+			for _, fn := range inspect {
+				fn(e)
+			}
+			out <- e
+		}
+		done(err)
+	}()
+	return out
+}
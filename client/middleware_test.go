@@ -0,0 +1,114 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMiddleware(t *testing.T) {
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}
+
+	t.Run("first middleware is outermost", func(t *testing.T) {
+		var calls []string
+		record := func(name string) Middleware {
+			return Middleware{Chat: func(next ChatFunc) ChatFunc {
+				return func(ctx context.Context, msgs []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+					calls = append(calls, name+" before")
+					resp, err := next(ctx, msgs, opts...)
+					calls = append(calls, name+" after")
+					return resp, err
+				}
+			}}
+		}
+		c := New(Config{}, WithMiddleware(record("outer"), record("inner")))
+		_, err := c.Chat(ai.WithDryRun(context.Background()), messages)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"outer before", "inner before", "inner after", "outer after"}, calls)
+	})
+
+	t.Run("middleware can short-circuit", func(t *testing.T) {
+		blocked := errors.New("blocked by guardrail")
+		guard := ChatMiddleware(func(ctx context.Context, msgs []ai.Message, opts []ai.Option) (context.Context, []ai.Message, []ai.Option, error) {
+			return ctx, msgs, opts, blocked
+		})
+		c := New(Config{}, WithMiddleware(guard))
+		_, err := c.Chat(context.Background(), messages)
+		assert.ErrorIs(t, err, blocked)
+		_, err = c.ChatStream(context.Background(), messages)
+		assert.ErrorIs(t, err, blocked)
+	})
+
+	t.Run("middleware can rewrite the context", func(t *testing.T) {
+		dryRun := ChatMiddleware(func(ctx context.Context, msgs []ai.Message, opts []ai.Option) (context.Context, []ai.Message, []ai.Option, error) {
+			return ai.WithDryRun(ctx), msgs, opts, nil
+		})
+		// Without a default model the request would fail unless dry run.
+		c := New(Config{}, WithMiddleware(dryRun))
+		_, err := c.Chat(context.Background(), messages)
+		assert.NoError(t, err)
+	})
+}
+
+func TestTimingMiddleware(t *testing.T) {
+	var ops []string
+	c := New(Config{}, WithMiddleware(TimingMiddleware(func(op string, d time.Duration, err error) {
+		ops = append(ops, op)
+	})))
+	ctx := ai.WithDryRun(context.Background())
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}
+
+	_, err := c.Chat(ctx, messages)
+	require.NoError(t, err)
+	ch, err := c.ChatStream(ctx, messages)
+	require.NoError(t, err)
+	for range ch {
+	}
+	_, err = c.Embed(context.Background(), []string{"a"})
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"chat", "chat_stream", "embedding"}, ops)
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := New(Config{}, WithMiddleware(LoggingMiddleware(logger)))
+
+	_, err := c.Chat(ai.WithDryRun(context.Background()), []ai.Message{{Role: ai.RoleUser, Content: "secret"}})
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "operation=chat")
+	assert.NotContains(t, buf.String(), "secret")
+
+	buf.Reset()
+	_, err = c.GenerateImage(context.Background(), "a cat")
+	require.Error(t, err)
+	assert.Contains(t, buf.String(), "level=ERROR")
+	assert.Contains(t, buf.String(), "operation=image")
+}
+
+func TestObserveStream(t *testing.T) {
+	ch := make(chan event.Event, 2)
+	ch <- event.Event{Type: event.RunStart}
+	ch <- event.Event{Type: event.RunError, Error: errors.New("boom")}
+	close(ch)
+
+	done := make(chan error, 1)
+	var seen int
+	out := observeStream(ch, func(err error) { done <- err }, func(event.Event) { seen++ })
+	var forwarded int
+	for range out {
+		forwarded++
+	}
+	assert.Equal(t, 2, forwarded)
+	assert.EqualError(t, <-done, "boom")
+	assert.Equal(t, 2, seen)
+}
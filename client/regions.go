@@ -0,0 +1,218 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/internal/provider/vertex"
+)
+
+// regionCooldown is how long a location that failed a request is skipped
+// by latency routing before it is tried again.
+const regionCooldown = 30 * time.Second
+
+// latencyWeight is the weight of the newest sample in a location's moving
+// average latency.
+const latencyWeight = 0.3
+
+// WithRegionRouting sends Vertex AI chat requests that do not name a
+// region with ai.WithRegion to the healthy location with the lowest
+// observed latency, among VertexConfig.Location and VertexConfig.Regions.
+// Locations not yet measured are tried first, so each gets a sample, and a
+// location whose request fails with a transient error is skipped for 30
+// seconds. Without it, requests go to VertexConfig.Location.
+func WithRegionRouting() ClientOption {
+	return func(c *Client) {
+		c.regionRouting = true
+	}
+}
+
+// vertexKey identifies a Vertex AI client by project and location.
+type vertexKey struct {
+	project  string
+	location string
+}
+
+// regionEntry is the cached Vertex AI client of one location, with the
+// health and latency of the requests it served.
+type regionEntry struct {
+	client  *vertex.Client
+	initErr error
+
+	mu             sync.Mutex
+	latency        time.Duration // moving average
+	samples        int
+	unhealthyUntil time.Time
+}
+
+// observe records the outcome of a request that took d. A transient error
+// marks the location unhealthy for regionCooldown; a success clears it.
+func (e *regionEntry) observe(d time.Duration, err error, now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch {
+	case err == nil:
+		if e.samples == 0 {
+			e.latency = d
+		} else {
+			e.latency = time.Duration(latencyWeight*float64(d) + (1-latencyWeight)*float64(e.latency))
+		}
+		e.samples++
+		e.unhealthyUntil = time.Time{}
+	case ai.IsTransient(err):
+		e.unhealthyUntil = now.Add(regionCooldown)
+	}
+}
+
+// health returns whether the location may serve requests at now, its
+// average latency, and whether that latency has been measured.
+func (e *regionEntry) health(now time.Time) (healthy bool, latency time.Duration, measured bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return !now.Before(e.unhealthyUntil), e.latency, e.samples > 0
+}
+
+// vertexLocations returns the configured Vertex AI locations, primary
+// first.
+func (c *Client) vertexLocations() []string {
+	locations := []string{c.creds.Vertex.Location}
+	for _, r := range c.creds.Vertex.Regions {
+		if r != "" && r != c.creds.Vertex.Location {
+			locations = append(locations, r)
+		}
+	}
+	return locations
+}
+
+// vertexLocation picks the location to serve a chat request: the one the
+// request names, the best one under WithRegionRouting, or the primary.
+func (c *Client) vertexLocation(region string) string {
+	if region != "" {
+		return region
+	}
+	if !c.regionRouting {
+		return c.creds.Vertex.Location
+	}
+
+	now := time.Now()
+	best, bestLatency := "", time.Duration(0)
+	for _, location := range c.vertexLocations() {
+		c.mu.RLock()
+		entry := c.vertexClients[vertexKey{c.creds.Vertex.Project, location}]
+		c.mu.RUnlock()
+		if entry == nil {
+			return location // not connected yet, so not measured
+		}
+		if entry.initErr != nil {
+			continue
+		}
+		healthy, latency, measured := entry.health(now)
+		switch {
+		case !healthy:
+			continue
+		case !measured:
+			return location
+		case best == "" || latency < bestLatency:
+			best, bestLatency = location, latency
+		}
+	}
+	if best == "" {
+		return c.creds.Vertex.Location
+	}
+	return best
+}
+
+// getVertexRegion returns the Vertex AI client of location, connecting on
+// first use. Connections are reused for the life of the client; a failed
+// connection is remembered and its error returned on later calls.
+func (c *Client) getVertexRegion(ctx context.Context, location string) (*regionEntry, error) {
+	if c.creds.Vertex.Project == "" || location == "" {
+		return nil, &ErrMissingAPIKey{Provider: "vertex (requires Project and Location)"}
+	}
+	key := vertexKey{c.creds.Vertex.Project, location}
+
+	c.mu.RLock()
+	entry := c.vertexClients[key]
+	c.mu.RUnlock()
+	if entry == nil {
+		c.mu.Lock()
+		// Double-check after acquiring write lock
+		if entry = c.vertexClients[key]; entry == nil {
+			entry = &regionEntry{}
+			var opts []vertex.ClientOption
+			if wrap := c.providerTransport(ai.ProviderVertex); wrap != nil {
+				opts = append(opts, vertex.WithTransport(wrap))
+			}
+			entry.client, entry.initErr = vertex.New(ctx, key.project, key.location, opts...)
+			if entry.initErr != nil {
+				entry.initErr = fmt.Errorf("failed to initialize Vertex AI client (%s): %w", location, entry.initErr)
+			}
+			if c.vertexClients == nil {
+				c.vertexClients = make(map[vertexKey]*regionEntry)
+			}
+			c.vertexClients[key] = entry
+		}
+		c.mu.Unlock()
+	}
+
+	if entry.initErr != nil {
+		return nil, entry.initErr
+	}
+	return entry, nil
+}
+
+// regionalChat serves chat requests from one Vertex AI location, recording
+// their latency and failures for routing.
+type regionalChat struct {
+	client *vertex.Client
+	entry  *regionEntry
+}
+
+func (r regionalChat) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	start := time.Now()
+	resp, err := r.client.Chat(ctx, messages, opts...)
+	r.observe(start, err)
+	return resp, err
+}
+
+// ChatStream records the time to the first event, or the stream's error.
+func (r regionalChat) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan ai.StreamEvent, error) {
+	start := time.Now()
+	ch, err := r.client.ChatStream(ctx, messages, opts...)
+	if err != nil {
+		r.observe(start, err)
+		return nil, err
+	}
+
+	out := make(chan ai.StreamEvent, cap(ch))
+	go func() {
+		defer close(out)
+		first := true
+		for ev := range ch {
+			if first || ev.Err != nil {
+				r.observe(start, ev.Err)
+				first = false
+			}
+			out <- ev
+		}
+	}()
+	return out, nil
+}
+
+func (r regionalChat) CountTokens(ctx context.Context, messages []ai.Message, opts ...ai.Option) (int, error) {
+	return r.client.CountTokens(ctx, messages, opts...)
+}
+
+// observe records a request that started at start, ignoring cancellations
+// by the caller.
+func (r regionalChat) observe(start time.Time, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	r.entry.observe(time.Since(start), err, time.Now())
+}
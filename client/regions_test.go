@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// regionalClient returns a client with cached Vertex AI entries for the
+// given locations, primary first, without connecting.
+func regionalClient(locations ...string) *Client {
+	c := New(Config{Credentials: Credentials{Vertex: VertexConfig{
+		Project:  "proj",
+		Location: locations[0],
+		Regions:  locations[1:],
+	}}}, WithRegionRouting())
+	c.vertexClients = make(map[vertexKey]*regionEntry)
+	for _, l := range locations {
+		c.vertexClients[vertexKey{"proj", l}] = &regionEntry{}
+	}
+	return c
+}
+
+func (c *Client) region(location string) *regionEntry {
+	return c.vertexClients[vertexKey{"proj", location}]
+}
+
+func TestVertexLocation(t *testing.T) {
+	now := time.Now()
+
+	t.Run("without routing uses the primary", func(t *testing.T) {
+		c := New(Config{Credentials: Credentials{Vertex: VertexConfig{
+			Project: "proj", Location: "us-central1", Regions: []string{"europe-west4"},
+		}}})
+		assert.Equal(t, "us-central1", c.vertexLocation(""))
+		assert.Equal(t, "asia-east1", c.vertexLocation("asia-east1"))
+	})
+
+	t.Run("unmeasured locations first", func(t *testing.T) {
+		c := regionalClient("us-central1", "europe-west4")
+		c.region("us-central1").observe(100*time.Millisecond, nil, now)
+		assert.Equal(t, "europe-west4", c.vertexLocation(""))
+	})
+
+	t.Run("lowest latency", func(t *testing.T) {
+		c := regionalClient("us-central1", "europe-west4", "asia-east1")
+		c.region("us-central1").observe(300*time.Millisecond, nil, now)
+		c.region("europe-west4").observe(100*time.Millisecond, nil, now)
+		c.region("asia-east1").observe(200*time.Millisecond, nil, now)
+		assert.Equal(t, "europe-west4", c.vertexLocation(""))
+
+		// A request naming a region is not rerouted
+		assert.Equal(t, "asia-east1", c.vertexLocation("asia-east1"))
+	})
+
+	t.Run("unhealthy locations are skipped", func(t *testing.T) {
+		c := regionalClient("us-central1", "europe-west4")
+		c.region("us-central1").observe(300*time.Millisecond, nil, now)
+		c.region("europe-west4").observe(100*time.Millisecond, nil, now)
+		c.region("europe-west4").observe(0, ai.NewTransientError("unavailable", 503, nil), now)
+		assert.Equal(t, "us-central1", c.vertexLocation(""))
+
+		// Permanent errors do not affect health
+		c.region("us-central1").observe(0, ai.NewPermanentError("bad request", 400, nil), now)
+		assert.Equal(t, "us-central1", c.vertexLocation(""))
+
+		// Failed connections are skipped
+		c.region("us-central1").initErr = errors.New("no credentials")
+		c.region("europe-west4").unhealthyUntil = time.Time{}
+		assert.Equal(t, "europe-west4", c.vertexLocation(""))
+	})
+
+	t.Run("all unhealthy falls back to the primary", func(t *testing.T) {
+		c := regionalClient("us-central1", "europe-west4")
+		for _, l := range []string{"us-central1", "europe-west4"} {
+			c.region(l).observe(0, ai.NewTransientError("rate limited", 429, nil), now)
+		}
+		assert.Equal(t, "us-central1", c.vertexLocation(""))
+	})
+}
+
+func TestRegionEntryObserve(t *testing.T) {
+	now := time.Now()
+	e := &regionEntry{}
+	e.observe(100*time.Millisecond, nil, now)
+	e.observe(200*time.Millisecond, nil, now)
+
+	healthy, latency, measured := e.health(now)
+	assert.True(t, healthy)
+	assert.True(t, measured)
+	assert.Equal(t, 130*time.Millisecond, latency)
+
+	e.observe(0, ai.NewTransientError("internal", 500, nil), now)
+	healthy, _, _ = e.health(now)
+	assert.False(t, healthy)
+	healthy, _, _ = e.health(now.Add(regionCooldown))
+	assert.True(t, healthy, "retried after the cooldown")
+}
+
+func TestGetVertexRegionRequiresProject(t *testing.T) {
+	c := New(Config{})
+	_, err := c.getVertexRegion(context.Background(), "us-central1")
+	var missing *ErrMissingAPIKey
+	require.ErrorAs(t, err, &missing)
+}
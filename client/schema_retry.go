@@ -0,0 +1,63 @@
+package client
+
+import (
+	"fmt"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// chatSchemaRetry sends messages with chat and, when WithSchemaRetry is set
+// on a request with a response schema, repairs and validates the response.
+// Invalid responses are sent back to the model with the validation errors,
+// emitting EventSchemaRetry, until one is valid or the retries run out.
+func (c *Client) chatSchemaRetry(provider ai.Provider, messages []ai.Message, options *ai.Options, chat func([]ai.Message) (*ai.Response, error)) (*ai.Response, error) {
+	schema := options.ResponseSchema
+	if options.SchemaRetries <= 0 || schema == nil {
+		return chat(messages)
+	}
+
+	attempts := options.SchemaRetries + 1
+	conversation := messages
+	var usage ai.Usage
+	var violation error
+	var content string
+	for attempt := 1; attempt <= attempts; attempt++ {
+		resp, err := chat(conversation)
+		if err != nil {
+			return nil, err
+		}
+		usage.InputTokens += resp.Usage.InputTokens
+		usage.OutputTokens += resp.Usage.OutputTokens
+		usage.CachedInputTokens += resp.Usage.CachedInputTokens
+		usage.CacheWriteInputTokens += resp.Usage.CacheWriteInputTokens
+
+		content = resp.Content
+		repaired := ai.RepairJSON(content)
+		if violation = ai.ValidateJSON(schema.Schema, []byte(repaired)); violation == nil {
+			resp.Content = repaired
+			resp.Usage = usage
+			return resp, nil
+		}
+		if attempt == attempts {
+			break
+		}
+
+		c.emit(Event{
+			Type:      EventSchemaRetry,
+			Operation: "chat",
+			Provider:  provider,
+			Metadata:  options.Metadata,
+			Error:     violation,
+			RetryEvent: &RetryEvent{
+				Type:        RetryEventRetrying,
+				Attempt:     attempt,
+				MaxAttempts: attempts,
+			},
+		})
+		conversation = append(conversation[:len(conversation):len(conversation)],
+			ai.Message{Role: ai.RoleAssistant, Content: content},
+			ai.Message{Role: ai.RoleUser, Content: fmt.Sprintf("That response does not match the required JSON schema:\n%v\n\nReply again with only the corrected JSON.", violation)},
+		)
+	}
+	return nil, &ai.ErrSchemaViolation{Content: content, Attempts: attempts, Err: violation}
+}
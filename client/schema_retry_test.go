@@ -0,0 +1,89 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatSchemaRetry(t *testing.T) {
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "Who?"}}
+	schema := ai.ResponseSchema{
+		Name:   "person",
+		Schema: json.RawMessage(`{"type":"object","properties":{"name":{"type":"string"}},"required":["name"]}`),
+	}
+
+	// scripted returns a chat func answering with replies in order,
+	// recording the conversations it was sent.
+	scripted := func(replies ...string) (func([]ai.Message) (*ai.Response, error), *[][]ai.Message) {
+		var received [][]ai.Message
+		return func(messages []ai.Message) (*ai.Response, error) {
+			received = append(received, messages)
+			return &ai.Response{Content: replies[len(received)-1], Usage: ai.Usage{InputTokens: 4, OutputTokens: 1}}, nil
+		}, &received
+	}
+
+	t.Run("repairs JSON without retrying", func(t *testing.T) {
+		c := New(Config{})
+		options := ai.ApplyOptions(ai.WithResponseSchema(schema), ai.WithSchemaRetry(2))
+		chat, received := scripted("Sure! ```json\n{\"name\": \"Ada\"}\n``` Anything else?")
+
+		resp, err := c.chatSchemaRetry(ai.ProviderGoogle, messages, options, chat)
+		require.NoError(t, err)
+		assert.Equal(t, `{"name": "Ada"}`, resp.Content)
+		assert.Len(t, *received, 1)
+	})
+
+	t.Run("feeds validation errors back", func(t *testing.T) {
+		events := make(chan Event, 10)
+		c := New(Config{Events: events})
+		options := ai.ApplyOptions(ai.WithResponseSchema(schema), ai.WithSchemaRetry(2))
+		chat, received := scripted(`{"nom": "Ada"}`, `{"name": "Ada"}`)
+
+		resp, err := c.chatSchemaRetry(ai.ProviderGoogle, messages, options, chat)
+		require.NoError(t, err)
+		assert.Equal(t, `{"name": "Ada"}`, resp.Content)
+		assert.Equal(t, ai.Usage{InputTokens: 8, OutputTokens: 2}, resp.Usage)
+
+		require.Len(t, *received, 2)
+		retry := (*received)[1]
+		require.Len(t, retry, 3)
+		assert.Equal(t, `{"nom": "Ada"}`, retry[1].Content)
+		assert.Contains(t, retry[2].Content, `missing required property "name"`)
+		assert.Len(t, messages, 1, "caller's messages must not be modified")
+
+		e := <-events
+		assert.Equal(t, EventSchemaRetry, e.Type)
+		assert.Equal(t, ai.ProviderGoogle, e.Provider)
+		assert.Error(t, e.Error)
+		require.NotNil(t, e.RetryEvent)
+		assert.Equal(t, 1, e.RetryEvent.Attempt)
+		assert.Equal(t, 3, e.RetryEvent.MaxAttempts)
+	})
+
+	t.Run("fails after the last retry", func(t *testing.T) {
+		c := New(Config{})
+		options := ai.ApplyOptions(ai.WithResponseSchema(schema), ai.WithSchemaRetry(1))
+		chat, received := scripted(`{}`, `not json`)
+
+		_, err := c.chatSchemaRetry(ai.ProviderGoogle, messages, options, chat)
+		var violation *ai.ErrSchemaViolation
+		require.ErrorAs(t, err, &violation)
+		assert.Equal(t, "not json", violation.Content)
+		assert.Equal(t, 2, violation.Attempts)
+		assert.Len(t, *received, 2)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		c := New(Config{})
+		options := ai.ApplyOptions(ai.WithResponseSchema(schema))
+		chat, _ := scripted(`not json`)
+
+		resp, err := c.chatSchemaRetry(ai.ProviderGoogle, messages, options, chat)
+		require.NoError(t, err)
+		assert.Equal(t, "not json", resp.Content)
+	})
+}
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// ErrRequestTimeout is wrapped by the error of a provider request attempt
+// that exceeded its RequestTimeouts. The error is transient, so the attempt
+// is retried and counts toward fallbacks like any other transient failure.
+var ErrRequestTimeout = errors.New("provider request timed out")
+
+// Timeouts bounds a single attempt of a provider request. Zero fields do
+// not bound it.
+type Timeouts struct {
+	// Connect bounds the time until the provider responds with headers,
+	// which catches providers that accept a connection and then hang.
+	Connect time.Duration
+	// Overall bounds the whole attempt. For streams, it bounds the stream
+	// until its last event, and a stream cut off by it ends with an error.
+	Overall time.Duration
+}
+
+// merge returns t with the non-zero fields of o.
+func (t Timeouts) merge(o Timeouts) Timeouts {
+	if o.Connect > 0 {
+		t.Connect = o.Connect
+	}
+	if o.Overall > 0 {
+		t.Overall = o.Overall
+	}
+	return t
+}
+
+// RequestTimeouts sets the Timeouts of provider requests. They apply to each
+// attempt separately and independently of the caller's context, so a hung
+// call fails promptly and is retried even when the caller's context allows
+// minutes:
+//
+//	client.Config{RequestTimeouts: client.RequestTimeouts{
+//	    Default:    client.Timeouts{Connect: 10 * time.Second, Overall: 2 * time.Minute},
+//	    Operations: map[string]client.Timeouts{"embedding": {Overall: 20 * time.Second}},
+//	}}
+type RequestTimeouts struct {
+	// Default applies to every request.
+	Default Timeouts
+	// Providers overrides Default for the requests of a provider.
+	Providers map[ai.Provider]Timeouts
+	// Operations overrides Default and Providers for an operation, named
+	// as in Event.Operation: "chat", "chat_stream", "embedding", "image",
+	// or "transcription".
+	Operations map[string]Timeouts
+}
+
+// For returns the timeouts of operation requests to provider: Default,
+// overridden by the provider's and then the operation's non-zero fields.
+func (t RequestTimeouts) For(provider ai.Provider, operation string) Timeouts {
+	return t.Default.merge(t.Providers[provider]).merge(t.Operations[operation])
+}
+
+// attemptKey is the context key for the attempt a provider request is for.
+type attemptKey struct{}
+
+// attempt is one attempt of a provider request.
+type attempt struct {
+	connect time.Duration
+	cancel  context.CancelCauseFunc
+}
+
+// attemptContext returns the context of one attempt of an operation request
+// to provider, cancelled with ErrRequestTimeout once a timeout runs out.
+// The connect timeout is enforced by the provider's transport; see
+// timeoutTransport.
+func (c *Client) attemptContext(ctx context.Context, provider ai.Provider, operation string) (context.Context, context.CancelFunc) {
+	t := c.requestTimeouts.For(provider, operation)
+	ctx, cancel := context.WithCancelCause(ctx)
+	stop := func() bool { return false }
+	if t.Overall > 0 {
+		stop = time.AfterFunc(t.Overall, func() { cancel(ErrRequestTimeout) }).Stop
+	}
+	if t.Connect > 0 {
+		ctx = context.WithValue(ctx, attemptKey{}, &attempt{connect: t.Connect, cancel: cancel})
+	}
+	return ctx, func() {
+		stop()
+		cancel(nil)
+	}
+}
+
+// attemptError returns the error of an attempt made with attemptCtx, derived
+// from ctx. If the attempt timed out while ctx was still live, the error is
+// made transient and wraps ErrRequestTimeout.
+func attemptError(ctx, attemptCtx context.Context, operation string, err error) error {
+	if err == nil || ctx.Err() != nil || !errors.Is(context.Cause(attemptCtx), ErrRequestTimeout) {
+		return err
+	}
+	return ai.NewTransientError(operation, 0, fmt.Errorf("%w: %w", ErrRequestTimeout, err))
+}
+
+// withAttempt runs one attempt of an operation request to provider under
+// its timeouts.
+func withAttempt[T any](c *Client, ctx context.Context, provider ai.Provider, operation string, fn func(ctx context.Context) (T, error)) (T, error) {
+	attemptCtx, cancel := c.attemptContext(ctx, provider, operation)
+	defer cancel()
+	result, err := fn(attemptCtx)
+	return result, attemptError(ctx, attemptCtx, operation, err)
+}
+
+// hasConnectTimeouts reports whether any connect timeout is configured, so
+// provider transports need to enforce them.
+func (t RequestTimeouts) hasConnectTimeouts() bool {
+	if t.Default.Connect > 0 {
+		return true
+	}
+	for _, o := range t.Providers {
+		if o.Connect > 0 {
+			return true
+		}
+	}
+	for _, o := range t.Operations {
+		if o.Connect > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// timeoutTransport cancels a request's attempt when the response headers
+// take longer than the attempt's connect timeout.
+type timeoutTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip sends req, enforcing the connect timeout of its attempt.
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	a, ok := req.Context().Value(attemptKey{}).(*attempt)
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+	timer := time.AfterFunc(a.connect, func() { a.cancel(ErrRequestTimeout) })
+	defer timer.Stop()
+	return t.base.RoundTrip(req)
+}
+
+// providerTransport returns the transport wrapper for provider's client,
+// or nil if its requests need none.
+func (c *Client) providerTransport(provider ai.Provider) func(http.RoundTripper) http.RoundTripper {
+	transcript := c.transcriptTransport(provider)
+	if !c.requestTimeouts.hasConnectTimeouts() {
+		return transcript
+	}
+	return func(base http.RoundTripper) http.RoundTripper {
+		if transcript != nil {
+			base = transcript(base)
+		}
+		return &timeoutTransport{base: base}
+	}
+}
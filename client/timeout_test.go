@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/internal/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hangingProvider hangs on its first hang calls until their context is done
+// and answers the rest.
+type hangingProvider struct {
+	hang  int32
+	calls atomic.Int32
+}
+
+func (p *hangingProvider) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	if p.calls.Add(1) <= p.hang {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &ai.Response{Content: "done", FinishReason: "stop"}, nil
+}
+
+func (p *hangingProvider) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan ai.StreamEvent, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestRequestTimeouts_For(t *testing.T) {
+	timeouts := RequestTimeouts{
+		Default:    Timeouts{Connect: time.Second, Overall: time.Minute},
+		Providers:  map[ai.Provider]Timeouts{ai.ProviderOpenAI: {Overall: 30 * time.Second}},
+		Operations: map[string]Timeouts{"embedding": {Overall: 5 * time.Second}},
+	}
+
+	assert.Equal(t, Timeouts{Connect: time.Second, Overall: time.Minute}, timeouts.For(ai.ProviderAnthropic, "chat"))
+	assert.Equal(t, Timeouts{Connect: time.Second, Overall: 30 * time.Second}, timeouts.For(ai.ProviderOpenAI, "chat"))
+	assert.Equal(t, Timeouts{Connect: time.Second, Overall: 5 * time.Second}, timeouts.For(ai.ProviderOpenAI, "embedding"))
+	assert.Equal(t, Timeouts{}, RequestTimeouts{}.For(ai.ProviderOpenAI, "chat"))
+}
+
+func TestRequestTimeouts_Chat(t *testing.T) {
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}
+	newClient := func(p *hangingProvider, attempts int) *Client {
+		return New(Config{
+			Defaults:        Defaults{Chat: testModel{id: "test", provider: ai.ProviderAnthropic}},
+			RetryConfig:     &retry.Config{MaxAttempts: attempts, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1},
+			RequestTimeouts: RequestTimeouts{Default: Timeouts{Overall: 20 * time.Millisecond}},
+		}, WithChatProvider(ai.ProviderAnthropic, p))
+	}
+
+	t.Run("retries an attempt that times out", func(t *testing.T) {
+		p := &hangingProvider{hang: 1}
+		resp, err := newClient(p, 3).Chat(context.Background(), messages)
+		require.NoError(t, err)
+		assert.Equal(t, "done", resp.Content)
+		assert.Equal(t, int32(2), p.calls.Load())
+	})
+
+	t.Run("reports the timeout once retries run out", func(t *testing.T) {
+		p := &hangingProvider{hang: 2}
+		_, err := newClient(p, 2).Chat(context.Background(), messages)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrRequestTimeout)
+		assert.Equal(t, int32(2), p.calls.Load())
+	})
+
+	t.Run("leaves the caller's cancellation alone", func(t *testing.T) {
+		p := &hangingProvider{hang: 1}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		_, err := newClient(p, 3).Chat(ctx, messages)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrRequestTimeout)
+	})
+}
+
+// stallingTransport waits for the request's context before responding.
+type stallingTransport struct{}
+
+func (stallingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestTimeoutTransport(t *testing.T) {
+	c := New(Config{RequestTimeouts: RequestTimeouts{Default: Timeouts{Connect: 10 * time.Millisecond}}})
+	wrap := c.providerTransport(ai.ProviderOpenAI)
+	require.NotNil(t, wrap)
+	transport := wrap(stallingTransport{})
+
+	ctx, cancel := c.attemptContext(context.Background(), ai.ProviderOpenAI, "chat")
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://example.com", io.NopCloser(strings.NewReader("{}")))
+	require.NoError(t, err)
+
+	_, err = transport.RoundTrip(req)
+	require.Error(t, err)
+	assert.ErrorIs(t, attemptError(context.Background(), ctx, "chat", err), ErrRequestTimeout)
+
+	assert.Nil(t, New(Config{}).providerTransport(ai.ProviderOpenAI))
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/internal/retry"
+)
+
+// TokenCount is the number of input tokens a request takes.
+type TokenCount struct {
+	// Tokens is the number of input tokens.
+	Tokens int
+	// Estimated is true if Tokens was estimated offline rather than counted
+	// by the provider with the model's own tokenizer. Estimates can be off
+	// by a large margin; leave headroom when checking them against a
+	// context window or budget.
+	Estimated bool
+}
+
+// CountTokens returns the number of input tokens messages take for model,
+// including the tools set in opts. If model is nil, the default chat model
+// is used.
+//
+// Anthropic, Vertex AI, and Gemini models are counted by their provider's
+// token counting endpoint; Gemini does not count tool definitions. OpenAI
+// offers no counting endpoint, so OpenAI models and models of unknown
+// providers are estimated offline with ai.EstimateTokens, which uses the
+// tokenizer package's estimators or a tokenizer registered with
+// tokenizer.Register, and the result is marked Estimated.
+func (c *Client) CountTokens(ctx context.Context, model ai.Model, messages []ai.Message, opts ...ai.Option) (TokenCount, error) {
+	if model == nil {
+		model = c.defaults.Chat
+	}
+	if model == nil {
+		return TokenCount{}, &ErrNoModel{Operation: "count_tokens"}
+	}
+	model, err := c.ResolveModel(model)
+	if err != nil {
+		return TokenCount{}, err
+	}
+	opts = append(append([]ai.Option{}, opts...), ai.WithModel(model))
+	estimate := func() TokenCount {
+		return TokenCount{Tokens: estimateRequestTokens(model, messages, ai.ApplyOptions(opts...)), Estimated: true}
+	}
+
+	// Estimates need no provider client, and so no credentials
+	switch c.resolveProvider(model) {
+	case ai.ProviderAnthropic, ai.ProviderGoogle, ai.ProviderVertex:
+	default:
+		return estimate(), nil
+	}
+
+	chatProvider, _, err := c.getChatProvider(ctx, model, ai.ApplyOptions(opts...).Region)
+	if err != nil {
+		return TokenCount{}, err
+	}
+	counter, ok := chatProvider.(ai.TokenCounter)
+	if !ok {
+		return estimate(), nil
+	}
+	n, err := retry.Do(ctx, c.retryConfig, func() (int, error) {
+		return counter.CountTokens(ctx, messages, opts...)
+	})
+	if err != nil {
+		return TokenCount{}, err
+	}
+	return TokenCount{Tokens: n}, nil
+}
+
+// estimateRequestTokens estimates the input tokens of messages and the
+// tools in options.
+func estimateRequestTokens(model ai.Model, messages []ai.Message, options *ai.Options) int {
+	n := ai.EstimateTokens(model, messages)
+	for _, t := range options.Tools {
+		n += ai.EstimateTokens(model, []ai.Message{{Content: t.Name + t.Description + string(t.Parameters)}})
+	}
+	return n
+}
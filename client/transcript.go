@@ -0,0 +1,289 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// redacted replaces credentials and redacted fields in recorded transcripts.
+const redacted = "[REDACTED]"
+
+// credentialHeaders are request headers that carry provider credentials.
+var credentialHeaders = []string{"Authorization", "X-Api-Key", "X-Goog-Api-Key", "Api-Key"}
+
+// Transcript is a recorded provider HTTP exchange. RequestBody is the exact
+// payload sent after converting gains messages to the provider's format, and
+// ResponseBody is the raw response (server-sent events for streaming calls).
+type Transcript struct {
+	// Provider is the provider that served the request.
+	Provider ai.Provider
+	// Method is the HTTP method.
+	Method string
+	// URL is the request URL with any API key query parameter redacted.
+	URL string
+	// RequestHeader holds the request headers with credentials redacted.
+	RequestHeader http.Header
+	// RequestBody is the request payload.
+	RequestBody []byte
+	// StatusCode is the HTTP status code, or 0 if the request failed.
+	StatusCode int
+	// ResponseBody is the raw response payload.
+	ResponseBody []byte
+	// Duration is the time from sending the request to the end of the response body.
+	Duration time.Duration
+	// Timestamp is when the request was sent.
+	Timestamp time.Time
+	// Err is the transport error, if the request failed.
+	Err error
+}
+
+// TranscriptSink receives recorded transcripts. It may be called concurrently.
+type TranscriptSink func(Transcript)
+
+// Redactor rewrites a request or response body before it is recorded.
+type Redactor func(body []byte) []byte
+
+// TranscriptOption configures transcript capture.
+type TranscriptOption func(*transcriptConfig)
+
+type transcriptConfig struct {
+	redactors []Redactor
+}
+
+// WithRedactor adds a function that rewrites bodies before they are recorded.
+// Redactors run in the order they are added, after credentials are removed.
+func WithRedactor(r Redactor) TranscriptOption {
+	return func(c *transcriptConfig) {
+		c.redactors = append(c.redactors, r)
+	}
+}
+
+// WithRedactedFields replaces the values of the named JSON object fields with
+// "[REDACTED]" wherever they appear in a body. Use it to drop personal data or
+// large base64 payloads (e.g. "data") from transcripts. Bodies that are not
+// JSON are recorded unchanged.
+func WithRedactedFields(fields ...string) TranscriptOption {
+	names := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		names[f] = true
+	}
+	return WithRedactor(func(body []byte) []byte {
+		var v any
+		if err := json.Unmarshal(body, &v); err != nil {
+			return body
+		}
+		out, err := json.Marshal(redactFields(v, names))
+		if err != nil {
+			return body
+		}
+		return out
+	})
+}
+
+// redactFields replaces values of matching keys throughout a decoded JSON value.
+func redactFields(v any, names map[string]bool) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if names[k] {
+				val[k] = redacted
+			} else {
+				val[k] = redactFields(child, names)
+			}
+		}
+	case []any:
+		for i, child := range val {
+			val[i] = redactFields(child, names)
+		}
+	}
+	return v
+}
+
+// WithTranscript records every provider HTTP exchange made by the client to
+// sink. Credentials in headers and URLs are always redacted; use
+// WithRedactor or WithRedactedFields to scrub bodies. Intended for debugging
+// request conversion, since recording buffers full request and response bodies.
+func WithTranscript(sink TranscriptSink, opts ...TranscriptOption) ClientOption {
+	cfg := &transcriptConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return func(c *Client) {
+		c.transcript = sink
+		c.transcriptCfg = cfg
+	}
+}
+
+// JSONLinesSink returns a TranscriptSink that writes each transcript to w as
+// one JSON object per line. Writes are serialized.
+func JSONLinesSink(w io.Writer) TranscriptSink {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	return func(t Transcript) {
+		record := struct {
+			Provider      ai.Provider     `json:"provider"`
+			Method        string          `json:"method"`
+			URL           string          `json:"url"`
+			RequestHeader http.Header     `json:"requestHeader,omitempty"`
+			RequestBody   json.RawMessage `json:"requestBody,omitempty"`
+			StatusCode    int             `json:"statusCode,omitempty"`
+			ResponseBody  string          `json:"responseBody,omitempty"`
+			DurationMS    int64           `json:"durationMs"`
+			Timestamp     time.Time       `json:"timestamp"`
+			Error         string          `json:"error,omitempty"`
+		}{
+			Provider:      t.Provider,
+			Method:        t.Method,
+			URL:           t.URL,
+			RequestHeader: t.RequestHeader,
+			StatusCode:    t.StatusCode,
+			ResponseBody:  string(t.ResponseBody),
+			DurationMS:    t.Duration.Milliseconds(),
+			Timestamp:     t.Timestamp,
+		}
+		if json.Valid(t.RequestBody) {
+			record.RequestBody = t.RequestBody
+		}
+		if t.Err != nil {
+			record.Error = t.Err.Error()
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		enc.Encode(record)
+	}
+}
+
+// transcriptTransport returns a transport wrapper that records exchanges for
+// provider, or nil if transcripts are disabled.
+func (c *Client) transcriptTransport(provider ai.Provider) func(http.RoundTripper) http.RoundTripper {
+	if c.transcript == nil {
+		return nil
+	}
+	return func(base http.RoundTripper) http.RoundTripper {
+		return &transcriptRoundTripper{
+			base:     base,
+			provider: provider,
+			sink:     c.transcript,
+			cfg:      c.transcriptCfg,
+		}
+	}
+}
+
+// transcriptRoundTripper records requests and responses passing through it.
+type transcriptRoundTripper struct {
+	base     http.RoundTripper
+	provider ai.Provider
+	sink     TranscriptSink
+	cfg      *transcriptConfig
+}
+
+// RoundTrip sends the request and records the exchange. Response bodies are
+// recorded when fully read or closed, so streaming responses still stream.
+func (t *transcriptRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	record := Transcript{
+		Provider:      t.provider,
+		Method:        req.Method,
+		URL:           redactURL(req),
+		RequestHeader: redactHeader(req.Header),
+		Timestamp:     time.Now(),
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		record.RequestBody = t.redact(body)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		record.Err = err
+		record.Duration = time.Since(record.Timestamp)
+		t.sink(record)
+		return nil, err
+	}
+
+	record.StatusCode = resp.StatusCode
+	resp.Body = &recordingBody{
+		ReadCloser: resp.Body,
+		finish: func(body []byte, readErr error) {
+			record.ResponseBody = t.redact(body)
+			record.Err = readErr
+			record.Duration = time.Since(record.Timestamp)
+			t.sink(record)
+		},
+	}
+	return resp, nil
+}
+
+// redact applies the configured redactors to a body.
+func (t *transcriptRoundTripper) redact(body []byte) []byte {
+	for _, r := range t.cfg.redactors {
+		body = r(body)
+	}
+	return body
+}
+
+// redactHeader returns a copy of h with credential headers redacted.
+func redactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range credentialHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, redacted)
+		}
+	}
+	return out
+}
+
+// redactURL returns the request URL with any "key" query parameter redacted.
+func redactURL(req *http.Request) string {
+	u := *req.URL
+	q := u.Query()
+	if q.Has("key") {
+		q.Set("key", redacted)
+		u.RawQuery = q.Encode()
+	}
+	return u.String()
+}
+
+// recordingBody buffers a response body as it is read and reports it once,
+// at EOF, on a read error, or when closed.
+type recordingBody struct {
+	io.ReadCloser
+	buf    bytes.Buffer
+	once   sync.Once
+	finish func(body []byte, err error)
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if err == io.EOF {
+		b.done(nil)
+	} else if err != nil {
+		b.done(err)
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.done(nil)
+	return err
+}
+
+func (b *recordingBody) done(err error) {
+	b.once.Do(func() {
+		b.finish(b.buf.Bytes(), err)
+	})
+}
@@ -0,0 +1,138 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// transcriptRecorder collects transcripts from a sink.
+type transcriptRecorder struct {
+	mu      sync.Mutex
+	records []Transcript
+}
+
+func (r *transcriptRecorder) sink(t Transcript) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, t)
+}
+
+func newTranscriptHTTPClient(sink TranscriptSink, opts ...TranscriptOption) *http.Client {
+	c := New(Config{}, WithTranscript(sink, opts...))
+	wrap := c.transcriptTransport(ai.ProviderOpenAI)
+	return &http.Client{Transport: wrap(http.DefaultTransport)}
+}
+
+func TestTranscript(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"echo":` + string(body) + `}`))
+	}))
+	defer server.Close()
+
+	t.Run("records request and response with credentials redacted", func(t *testing.T) {
+		rec := &transcriptRecorder{}
+		httpClient := newTranscriptHTTPClient(rec.sink)
+
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/v1/chat?key=secret&alt=sse", strings.NewReader(`{"model":"gpt"}`))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer sk-secret")
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := httpClient.Do(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		// The server still received the original body
+		assert.JSONEq(t, `{"echo":{"model":"gpt"}}`, string(body))
+
+		require.Len(t, rec.records, 1)
+		record := rec.records[0]
+		assert.Equal(t, ai.ProviderOpenAI, record.Provider)
+		assert.Equal(t, http.MethodPost, record.Method)
+		assert.NotContains(t, record.URL, "secret")
+		assert.Contains(t, record.URL, "alt=sse")
+		assert.Equal(t, "[REDACTED]", record.RequestHeader.Get("Authorization"))
+		assert.Equal(t, "application/json", record.RequestHeader.Get("Content-Type"))
+		assert.JSONEq(t, `{"model":"gpt"}`, string(record.RequestBody))
+		assert.Equal(t, http.StatusOK, record.StatusCode)
+		assert.Equal(t, string(body), string(record.ResponseBody))
+		assert.NoError(t, record.Err)
+	})
+
+	t.Run("records response when closed without reading", func(t *testing.T) {
+		rec := &transcriptRecorder{}
+		httpClient := newTranscriptHTTPClient(rec.sink)
+
+		resp, err := httpClient.Get(server.URL)
+		require.NoError(t, err)
+		assert.Empty(t, rec.records, "not recorded until body is done")
+		resp.Body.Close()
+
+		require.Len(t, rec.records, 1)
+		assert.Nil(t, rec.records[0].RequestBody)
+	})
+
+	t.Run("redacts configured fields", func(t *testing.T) {
+		rec := &transcriptRecorder{}
+		httpClient := newTranscriptHTTPClient(rec.sink, WithRedactedFields("data"))
+
+		resp, err := httpClient.Post(server.URL, "application/json",
+			strings.NewReader(`{"parts":[{"text":"hi"},{"data":"aGVsbG8="}]}`))
+		require.NoError(t, err)
+		io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		require.Len(t, rec.records, 1)
+		assert.JSONEq(t, `{"parts":[{"text":"hi"},{"data":"[REDACTED]"}]}`, string(rec.records[0].RequestBody))
+		assert.NotContains(t, string(rec.records[0].ResponseBody), "aGVsbG8=")
+	})
+
+	t.Run("records transport errors", func(t *testing.T) {
+		rec := &transcriptRecorder{}
+		httpClient := newTranscriptHTTPClient(rec.sink)
+
+		_, err := httpClient.Get("http://127.0.0.1:0")
+		require.Error(t, err)
+		require.Len(t, rec.records, 1)
+		assert.Error(t, rec.records[0].Err)
+		assert.Zero(t, rec.records[0].StatusCode)
+	})
+
+	t.Run("disabled without sink", func(t *testing.T) {
+		assert.Nil(t, New(Config{}).transcriptTransport(ai.ProviderOpenAI))
+	})
+}
+
+func TestJSONLinesSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := JSONLinesSink(&buf)
+
+	sink(Transcript{Provider: ai.ProviderAnthropic, Method: "POST", RequestBody: []byte(`{"a":1}`), StatusCode: 200})
+	sink(Transcript{Provider: ai.ProviderGoogle, Method: "POST", ResponseBody: []byte("data: {}\n\n")})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var first map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, "anthropic", first["provider"])
+	assert.Equal(t, map[string]any{"a": float64(1)}, first["requestBody"])
+
+	var second map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+	assert.Equal(t, "data: {}\n\n", second["responseBody"])
+}
@@ -0,0 +1,201 @@
+package client
+
+import (
+	"sync"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/model"
+)
+
+// UsageKey identifies a bucket of tracked usage.
+type UsageKey struct {
+	// Provider is the provider that served the requests.
+	Provider ai.Provider
+	// Model is the model identifier.
+	Model string
+	// Operation is the client operation ("chat", "chat_stream", "embed", "image", "transcribe").
+	Operation string
+}
+
+// UsageTotals aggregates token usage and cost across requests.
+type UsageTotals struct {
+	// Requests is the number of successful requests recorded.
+	Requests int
+	// InputTokens is the total number of input tokens.
+	InputTokens int
+	// OutputTokens is the total number of output tokens.
+	OutputTokens int
+	// CachedInputTokens is the portion of InputTokens served from a prompt cache.
+	CachedInputTokens int
+	// CacheWriteInputTokens is the portion of InputTokens written to a
+	// prompt cache.
+	CacheWriteInputTokens int
+	// Images is the number of images generated.
+	Images int
+	// Cost is the total cost in USD. Requests against models without known
+	// pricing contribute tokens but no cost.
+	Cost float64
+}
+
+// add accumulates other into t.
+func (t *UsageTotals) add(other UsageTotals) {
+	t.Requests += other.Requests
+	t.InputTokens += other.InputTokens
+	t.OutputTokens += other.OutputTokens
+	t.CachedInputTokens += other.CachedInputTokens
+	t.CacheWriteInputTokens += other.CacheWriteInputTokens
+	t.Images += other.Images
+	t.Cost += other.Cost
+}
+
+// UsageSnapshot is a point-in-time copy of a UsageTracker's totals.
+type UsageSnapshot struct {
+	// Entries holds totals for each provider/model/operation combination.
+	Entries map[UsageKey]UsageTotals
+	// Total holds totals across all entries.
+	Total UsageTotals
+}
+
+// ByProvider returns totals grouped by provider.
+func (s UsageSnapshot) ByProvider() map[ai.Provider]UsageTotals {
+	return groupUsage(s.Entries, func(k UsageKey) ai.Provider { return k.Provider })
+}
+
+// ByModel returns totals grouped by model identifier.
+func (s UsageSnapshot) ByModel() map[string]UsageTotals {
+	return groupUsage(s.Entries, func(k UsageKey) string { return k.Model })
+}
+
+// ByOperation returns totals grouped by operation.
+func (s UsageSnapshot) ByOperation() map[string]UsageTotals {
+	return groupUsage(s.Entries, func(k UsageKey) string { return k.Operation })
+}
+
+func groupUsage[K comparable](entries map[UsageKey]UsageTotals, keyFn func(UsageKey) K) map[K]UsageTotals {
+	grouped := make(map[K]UsageTotals)
+	for key, totals := range entries {
+		g := grouped[keyFn(key)]
+		g.add(totals)
+		grouped[keyFn(key)] = g
+	}
+	return grouped
+}
+
+// UsageTracker aggregates token usage and cost across client requests.
+// It is safe for concurrent use and may be shared between clients.
+type UsageTracker struct {
+	mu      sync.Mutex
+	entries map[UsageKey]UsageTotals
+}
+
+// NewUsageTracker creates an empty UsageTracker.
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{entries: make(map[UsageKey]UsageTotals)}
+}
+
+// Record adds totals to the bucket identified by key.
+// The client calls this automatically; it is exported so usage from other
+// sources can be folded into the same tracker.
+func (t *UsageTracker) Record(key UsageKey, totals UsageTotals) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entry := t.entries[key]
+	entry.add(totals)
+	t.entries[key] = entry
+}
+
+// Snapshot returns a copy of the current totals.
+func (t *UsageTracker) Snapshot() UsageSnapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	snap := UsageSnapshot{Entries: make(map[UsageKey]UsageTotals, len(t.entries))}
+	for key, totals := range t.entries {
+		snap.Entries[key] = totals
+		snap.Total.add(totals)
+	}
+	return snap
+}
+
+// Total returns totals across all recorded requests.
+func (t *UsageTracker) Total() UsageTotals {
+	return t.Snapshot().Total
+}
+
+// Reset discards all recorded usage.
+func (t *UsageTracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.entries = make(map[UsageKey]UsageTotals)
+}
+
+// WithUsageTracker records token usage and cost for every successful request
+// made by the client into the given tracker.
+func WithUsageTracker(t *UsageTracker) ClientOption {
+	return func(c *Client) {
+		c.usage = t
+	}
+}
+
+// UsageTracker returns the client's usage tracker, or nil if none is configured.
+func (c *Client) UsageTracker() *UsageTracker {
+	return c.usage
+}
+
+// recordUsage records totals for a request if a tracker is configured.
+func (c *Client) recordUsage(operation string, provider ai.Provider, m ai.Model, totals UsageTotals) {
+	if c.usage == nil {
+		return
+	}
+	totals.Requests = 1
+	c.usage.Record(UsageKey{Provider: provider, Model: m.String(), Operation: operation}, totals)
+}
+
+// chatUsageTotals builds totals for a chat response, pricing it when the
+// model exposes chat pricing.
+func chatUsageTotals(m ai.Model, usage ai.Usage) UsageTotals {
+	totals := UsageTotals{
+		InputTokens:           usage.InputTokens,
+		OutputTokens:          usage.OutputTokens,
+		CachedInputTokens:     usage.CachedInputTokens,
+		CacheWriteInputTokens: usage.CacheWriteInputTokens,
+	}
+	if priced, ok := m.(interface{ Pricing() model.ChatPricing }); ok {
+		totals.Cost = model.CalculateTieredCost(usage, priced.Pricing())
+	}
+	return totals
+}
+
+// embeddingUsageTotals builds totals for an embedding response, pricing it
+// when the model exposes embedding pricing.
+func embeddingUsageTotals(m ai.Model, usage ai.Usage) UsageTotals {
+	totals := UsageTotals{InputTokens: usage.InputTokens}
+	if priced, ok := m.(interface{ Pricing() model.EmbeddingPricing }); ok {
+		totals.Cost = priced.Pricing().Cost(usage)
+	}
+	return totals
+}
+
+// imageUsageTotals builds totals for an image response, pricing it when the
+// model exposes image pricing.
+func imageUsageTotals(m ai.Model, quality ai.ImageQuality, count int) UsageTotals {
+	totals := UsageTotals{Images: count}
+	if priced, ok := m.(interface{ Pricing() model.ImagePricing }); ok {
+		totals.Cost = priced.Pricing().Cost(quality, count)
+	}
+	return totals
+}
+
+// transcriptionUsageTotals builds totals for a transcription response, pricing
+// it when the model exposes transcription pricing.
+func transcriptionUsageTotals(m ai.Model, resp *ai.TranscriptionResponse) UsageTotals {
+	totals := UsageTotals{
+		InputTokens:  resp.Usage.InputTokens,
+		OutputTokens: resp.Usage.OutputTokens,
+	}
+	if priced, ok := m.(interface {
+		Pricing() model.TranscriptionPricing
+	}); ok {
+		totals.Cost = priced.Pricing().Cost(resp)
+	}
+	return totals
+}
@@ -0,0 +1,170 @@
+package client
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageTracker(t *testing.T) {
+	chatKey := UsageKey{Provider: ai.ProviderOpenAI, Model: "gpt-5.2", Operation: "chat"}
+	embedKey := UsageKey{Provider: ai.ProviderOpenAI, Model: "text-embedding-3-small", Operation: "embed"}
+	claudeKey := UsageKey{Provider: ai.ProviderAnthropic, Model: "claude-sonnet-4-5", Operation: "chat"}
+
+	t.Run("aggregates totals per key", func(t *testing.T) {
+		tracker := NewUsageTracker()
+		tracker.Record(chatKey, UsageTotals{Requests: 1, InputTokens: 100, OutputTokens: 50, Cost: 0.5})
+		tracker.Record(chatKey, UsageTotals{Requests: 1, InputTokens: 200, OutputTokens: 25, Cost: 0.25})
+		tracker.Record(embedKey, UsageTotals{Requests: 1, InputTokens: 1000, Cost: 0.01})
+		tracker.Record(claudeKey, UsageTotals{Requests: 1, InputTokens: 10, OutputTokens: 10, Cost: 1})
+
+		snap := tracker.Snapshot()
+		assert.Len(t, snap.Entries, 3)
+		assert.Equal(t, 2, snap.Entries[chatKey].Requests)
+		assert.Equal(t, 300, snap.Entries[chatKey].InputTokens)
+		assert.Equal(t, 4, snap.Total.Requests)
+		assert.InDelta(t, 1.76, snap.Total.Cost, 0.0001)
+
+		byProvider := snap.ByProvider()
+		assert.Equal(t, 3, byProvider[ai.ProviderOpenAI].Requests)
+		assert.InDelta(t, 1.0, byProvider[ai.ProviderAnthropic].Cost, 0.0001)
+
+		byOperation := snap.ByOperation()
+		assert.Equal(t, 3, byOperation["chat"].Requests)
+		assert.Equal(t, 1000, byOperation["embed"].InputTokens)
+
+		assert.Equal(t, 75, snap.ByModel()["gpt-5.2"].OutputTokens)
+	})
+
+	t.Run("snapshot is independent of later records", func(t *testing.T) {
+		tracker := NewUsageTracker()
+		tracker.Record(chatKey, UsageTotals{Requests: 1})
+		snap := tracker.Snapshot()
+		tracker.Record(chatKey, UsageTotals{Requests: 1})
+
+		assert.Equal(t, 1, snap.Entries[chatKey].Requests)
+		assert.Equal(t, 2, tracker.Total().Requests)
+	})
+
+	t.Run("reset clears totals", func(t *testing.T) {
+		tracker := NewUsageTracker()
+		tracker.Record(chatKey, UsageTotals{Requests: 1, Cost: 1})
+		tracker.Reset()
+
+		snap := tracker.Snapshot()
+		assert.Empty(t, snap.Entries)
+		assert.Equal(t, UsageTotals{}, snap.Total)
+	})
+
+	t.Run("safe for concurrent use", func(t *testing.T) {
+		tracker := NewUsageTracker()
+		var wg sync.WaitGroup
+		for range 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				tracker.Record(chatKey, UsageTotals{Requests: 1, InputTokens: 2})
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 50, tracker.Total().Requests)
+		assert.Equal(t, 100, tracker.Total().InputTokens)
+	})
+}
+
+func TestClientRecordUsage(t *testing.T) {
+	t.Run("records with tracker configured", func(t *testing.T) {
+		tracker := NewUsageTracker()
+		c := New(Config{}, WithUsageTracker(tracker))
+		assert.Same(t, tracker, c.UsageTracker())
+
+		usage := ai.Usage{InputTokens: 1_000_000, OutputTokens: 1_000_000, CachedInputTokens: 500_000}
+		c.recordUsage("chat", ai.ProviderOpenAI, model.GPT52, chatUsageTotals(model.GPT52, usage))
+
+		totals := tracker.Snapshot().Entries[UsageKey{Provider: ai.ProviderOpenAI, Model: "gpt-5.2", Operation: "chat"}]
+		assert.Equal(t, 1, totals.Requests)
+		assert.Equal(t, 500_000, totals.CachedInputTokens)
+		// 500K * $1.75/M + 500K * $0.175/M + 1M * $14/M
+		assert.InDelta(t, 0.875+0.0875+14.0, totals.Cost, 0.0001)
+	})
+
+	t.Run("no-op without tracker", func(t *testing.T) {
+		c := New(Config{})
+		assert.Nil(t, c.UsageTracker())
+		c.recordUsage("chat", ai.ProviderOpenAI, model.GPT52, UsageTotals{})
+	})
+
+	t.Run("unpriced models track tokens only", func(t *testing.T) {
+		m := testModel{id: "custom", provider: ai.ProviderOpenAI}
+		totals := chatUsageTotals(m, ai.Usage{InputTokens: 10, OutputTokens: 5})
+		assert.Equal(t, 10, totals.InputTokens)
+		assert.Equal(t, 0.0, totals.Cost)
+	})
+
+	t.Run("prices embeddings and images", func(t *testing.T) {
+		embed := embeddingUsageTotals(model.TextEmbedding3Small, ai.Usage{InputTokens: 1_000_000})
+		assert.InDelta(t, 0.02, embed.Cost, 0.0001)
+
+		image := imageUsageTotals(model.Imagen4, "", 2)
+		assert.Equal(t, 2, image.Images)
+		assert.Greater(t, image.Cost, 0.0)
+	})
+
+	t.Run("prices transcriptions", func(t *testing.T) {
+		whisper := transcriptionUsageTotals(model.Whisper1, &ai.TranscriptionResponse{Duration: 2 * time.Minute})
+		assert.InDelta(t, 0.012, whisper.Cost, 0.0001)
+
+		gemini := transcriptionUsageTotals(model.Gemini25FlashTranscribe, &ai.TranscriptionResponse{Usage: ai.Usage{InputTokens: 1_000_000}})
+		assert.Equal(t, 1_000_000, gemini.InputTokens)
+		assert.InDelta(t, 1.0, gemini.Cost, 0.0001)
+	})
+}
+
+func TestClientBudget(t *testing.T) {
+	key := UsageKey{Provider: ai.ProviderOpenAI, Model: "gpt-5.2", Operation: "chat"}
+
+	t.Run("creates tracker when budget set", func(t *testing.T) {
+		c := New(Config{}, WithBudget(1))
+		assert.NotNil(t, c.UsageTracker())
+	})
+
+	t.Run("rejects requests once cost budget reached", func(t *testing.T) {
+		events := make(chan Event, 10)
+		c := New(Config{Events: events}, WithBudget(1))
+		assert.NoError(t, c.checkBudget("chat", ai.ProviderOpenAI))
+
+		c.UsageTracker().Record(key, UsageTotals{Requests: 1, Cost: 1.25})
+		err := c.checkBudget("chat", ai.ProviderOpenAI)
+
+		var budgetErr *ai.ErrBudgetExceeded
+		require.ErrorAs(t, err, &budgetErr)
+		assert.InDelta(t, 1.25, budgetErr.Cost, 0.0001)
+
+		ev := <-events
+		assert.Equal(t, EventBudgetExceeded, ev.Type)
+		assert.Equal(t, "chat", ev.Operation)
+		assert.Equal(t, err, ev.Error)
+	})
+
+	t.Run("rejects requests once token budget reached", func(t *testing.T) {
+		tracker := NewUsageTracker()
+		c := New(Config{}, WithUsageTracker(tracker), WithTokenBudget(100))
+		tracker.Record(key, UsageTotals{Requests: 1, InputTokens: 60, OutputTokens: 40})
+
+		var budgetErr *ai.ErrBudgetExceeded
+		require.ErrorAs(t, c.checkBudget("embed", ai.ProviderOpenAI), &budgetErr)
+		assert.Equal(t, 100, budgetErr.Tokens)
+	})
+
+	t.Run("no budget never rejects", func(t *testing.T) {
+		tracker := NewUsageTracker()
+		tracker.Record(key, UsageTotals{Requests: 1, Cost: 100})
+		c := New(Config{}, WithUsageTracker(tracker))
+		assert.NoError(t, c.checkBudget("chat", ai.ProviderOpenAI))
+	})
+}
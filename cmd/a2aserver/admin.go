@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/spetersoncode/gains/cmd/internal/admin"
+	"github.com/spetersoncode/gains/tool"
+)
+
+// NewAdminHandler creates the admin handler over the server's tasks, the
+// tool registry, and the configuration. It is mounted only if A2A_ADMIN or
+// AGUI_ADMIN is set.
+func NewAdminHandler(tasks *taskStore, registry *tool.Registry, cfg *Config) *admin.Handler {
+	return admin.NewHandler(map[string]admin.Runs{"a2a": tasks}, registry, map[string]any{
+		"port":             cfg.Port,
+		"publicUrl":        cfg.PublicURL,
+		"logLevel":         cfg.LogLevel,
+		"maxTasks":         cfg.MaxTasks,
+		"agentName":        cfg.AgentName,
+		"agentDescription": cfg.AgentDescription,
+		"provider":         cfg.Provider,
+		"anthropicKey":     admin.Redact(cfg.AnthropicKey),
+		"openaiKey":        admin.Redact(cfg.OpenAIKey),
+		"googleKey":        admin.Redact(cfg.GoogleKey),
+		"vertexProject":    cfg.VertexProject,
+		"vertexLocation":   cfg.VertexLocation,
+		"maxSteps":         cfg.MaxSteps,
+		"timeout":          cfg.Timeout.String(),
+		"admin":            cfg.EnableAdmin,
+	})
+}
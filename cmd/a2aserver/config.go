@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Config holds the server configuration loaded from environment variables.
+type Config struct {
+	// Server
+	Port      string
+	PublicURL string // URL advertised in the agent card
+	LogLevel  string // debug, info, warn, error
+	MaxTasks  int    // finished tasks kept for tasks/get
+
+	// Agent card
+	AgentName        string
+	AgentDescription string
+
+	// Provider selection
+	Provider string
+
+	// API Keys
+	AnthropicKey string
+	OpenAIKey    string
+	GoogleKey    string
+
+	// Vertex AI (uses ADC for auth)
+	VertexProject  string
+	VertexLocation string
+
+	// Agent config
+	MaxSteps int
+	Timeout  time.Duration
+
+	// Operations
+	EnableAdmin bool
+}
+
+// LoadConfig loads configuration from environment variables.
+// It loads a .env file if present (silent fail if not found).
+func LoadConfig() (*Config, error) {
+	godotenv.Load() // Load .env file if present
+
+	cfg := &Config{
+		Port:             getEnvOrDefault("A2A_PORT", "8001"),
+		PublicURL:        os.Getenv("A2A_PUBLIC_URL"),
+		LogLevel:         getEnvOrDefault("A2A_LOG_LEVEL", "info"),
+		MaxTasks:         getEnvIntOrDefault("A2A_MAX_TASKS", 1000),
+		AgentName:        getEnvOrDefault("A2A_AGENT_NAME", "gains-agent"),
+		AgentDescription: getEnvOrDefault("A2A_AGENT_DESCRIPTION", "A gains agent served over the A2A protocol"),
+		Provider:         os.Getenv("GAINS_PROVIDER"),
+		AnthropicKey:     os.Getenv("ANTHROPIC_API_KEY"),
+		OpenAIKey:        os.Getenv("OPENAI_API_KEY"),
+		GoogleKey:        os.Getenv("GOOGLE_API_KEY"),
+		VertexProject:    os.Getenv("VERTEX_PROJECT"),
+		VertexLocation:   os.Getenv("VERTEX_LOCATION"),
+		MaxSteps:         getEnvIntOrDefault("GAINS_MAX_STEPS", 10),
+		Timeout:          getEnvDurationOrDefault("GAINS_TIMEOUT", 2*time.Minute),
+		EnableAdmin:      getEnvBoolOrDefault("A2A_ADMIN", getEnvBoolOrDefault("AGUI_ADMIN", false)),
+	}
+	if cfg.PublicURL == "" {
+		cfg.PublicURL = "http://localhost:" + cfg.Port + "/"
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Validate checks that required configuration is present.
+func (c *Config) Validate() error {
+	if c.Provider == "" {
+		return fmt.Errorf("GAINS_PROVIDER is required (anthropic, openai, google, or vertex)")
+	}
+
+	switch c.Provider {
+	case "anthropic":
+		if c.AnthropicKey == "" {
+			return fmt.Errorf("ANTHROPIC_API_KEY is required for anthropic provider")
+		}
+	case "openai":
+		if c.OpenAIKey == "" {
+			return fmt.Errorf("OPENAI_API_KEY is required for openai provider")
+		}
+	case "google":
+		if c.GoogleKey == "" {
+			return fmt.Errorf("GOOGLE_API_KEY is required for google provider")
+		}
+	case "vertex":
+		if c.VertexProject == "" || c.VertexLocation == "" {
+			return fmt.Errorf("VERTEX_PROJECT and VERTEX_LOCATION are required for vertex provider")
+		}
+	default:
+		return fmt.Errorf("unknown provider: %s (must be anthropic, openai, google, or vertex)", c.Provider)
+	}
+
+	return nil
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBoolOrDefault(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,227 @@
+// Package main provides a reference A2A HTTP server that exposes a gains
+// agent via the Agent2Agent protocol over JSON-RPC 2.0.
+//
+// The server implements the message/send, message/stream, tasks/get, and
+// tasks/cancel methods at its root URL and publishes an agent card at
+// /.well-known/agent-card.json. It uses only the Go standard library for
+// HTTP.
+//
+// Configuration is via environment variables:
+//
+//	A2A_PORT              - Server port (default: 8001)
+//	A2A_PUBLIC_URL        - URL advertised in the agent card (default: http://localhost:<port>/)
+//	A2A_LOG_LEVEL         - Log level: debug, info, warn, error (default: info)
+//	A2A_MAX_TASKS         - Finished tasks kept for tasks/get (default: 1000)
+//	A2A_AGENT_NAME        - Agent name in the agent card (default: gains-agent)
+//	A2A_AGENT_DESCRIPTION - Agent description in the agent card
+//	GAINS_PROVIDER        - Provider: anthropic, openai, google, or vertex (required)
+//	GAINS_MAX_STEPS       - Max agent iterations (default: 10)
+//	GAINS_TIMEOUT         - Agent timeout (default: 2m)
+//	A2A_ADMIN             - Enable the read-only /admin endpoints (default: AGUI_ADMIN, else false)
+//	ANTHROPIC_API_KEY     - Anthropic API key
+//	OPENAI_API_KEY        - OpenAI API key
+//	GOOGLE_API_KEY        - Google API key
+//	VERTEX_PROJECT        - Vertex AI project ID
+//	VERTEX_LOCATION       - Vertex AI location (e.g., us-central1)
+//
+// Usage:
+//
+//	GAINS_PROVIDER=anthropic go run ./cmd/a2aserver
+//
+// Then send a message:
+//
+//	curl -s localhost:8001 -d '{"jsonrpc":"2.0","id":1,"method":"message/send",
+//	    "params":{"message":{"kind":"message","messageId":"1","role":"user",
+//	    "parts":[{"kind":"text","text":"What time is it?"}]}}}'
+//
+// With A2A_ADMIN=true, or AGUI_ADMIN=true as for cmd/serve, the same
+// read-only endpoints as cmd/serve show what the server is doing. Runs are
+// the server's tasks, and their events are the task's status and artifact
+// updates. Anything that can reach the endpoints can read the configuration
+// (API keys redacted) and task details, so expose them only on trusted
+// networks:
+//
+//	GET /admin/runs                 - tasks in flight
+//	GET /admin/runs/{taskId}/events - a task's recent updates, in flight or finished
+//	GET /admin/tools                - the registered tools and their parameter schemas
+//	GET /admin/config               - the configuration, API keys redacted
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/a2a"
+	"github.com/spetersoncode/gains/agent"
+	"github.com/spetersoncode/gains/client"
+	"github.com/spetersoncode/gains/model"
+	"github.com/spetersoncode/gains/runtime"
+	"github.com/spetersoncode/gains/tool"
+)
+
+func main() {
+	// Load configuration
+	cfg, err := LoadConfig()
+	if err != nil {
+		slog.Error("configuration error", "error", err)
+		os.Exit(1)
+	}
+
+	// Setup structured logger
+	slog.SetDefault(setupLogger(cfg.LogLevel))
+
+	// Create gains client
+	gainsClient, err := createClient(cfg)
+	if err != nil {
+		slog.Error("failed to create client", "error", err)
+		os.Exit(1)
+	}
+
+	// Create tool registry and agent
+	registry := tool.NewRegistry()
+	setupDemoTools(registry)
+	a := agent.New(gainsClient, registry)
+
+	// Create A2A executor and server
+	executor := a2a.NewAgentExecutor(a,
+		agent.WithMaxSteps(cfg.MaxSteps),
+		agent.WithTimeout(cfg.Timeout),
+	)
+	card := a2a.NewAgentCard(a, registry,
+		a2a.WithCardName(cfg.AgentName),
+		a2a.WithCardDescription(cfg.AgentDescription),
+		a2a.WithCardURL(cfg.PublicURL),
+	)
+	srv := NewServer(executor, card, cfg.MaxTasks)
+
+	handler := srv.Handler()
+	if cfg.EnableAdmin {
+		mux := http.NewServeMux()
+		mux.Handle("/", handler)
+		mux.Handle("/admin/", NewAdminHandler(srv.tasks, registry, cfg))
+		handler = mux
+	}
+
+	// Create server
+	server := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      handler,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 0, // SSE needs no write timeout
+		IdleTimeout:  120 * time.Second,
+	}
+
+	// Graceful shutdown
+	go func() {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+		<-sigCh
+
+		slog.Info("shutting down")
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		// Let in-flight runs finish before closing connections
+		if err := runtime.Drain(ctx); err != nil {
+			slog.Warn("drain incomplete", "error", err)
+		}
+		if err := server.Shutdown(ctx); err != nil {
+			slog.Error("shutdown error", "error", err)
+		}
+	}()
+
+	// Start server
+	slog.Info("server starting",
+		"port", cfg.Port,
+		"provider", cfg.Provider,
+		"log_level", cfg.LogLevel,
+		"rpc_endpoint", fmt.Sprintf("POST http://localhost:%s/", cfg.Port),
+		"agent_card", fmt.Sprintf("GET http://localhost:%s%s", cfg.Port, a2a.AgentCardPath),
+		"health", fmt.Sprintf("GET http://localhost:%s/health", cfg.Port),
+	)
+	if cfg.EnableAdmin {
+		slog.Warn("admin endpoints enabled", "admin", fmt.Sprintf("GET http://localhost:%s/admin/runs", cfg.Port))
+	}
+
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		slog.Error("server error", "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("server stopped")
+}
+
+// setupDemoTools registers the tools the demo agent can use.
+func setupDemoTools(registry *tool.Registry) {
+	tool.MustRegisterFunc(registry, "get_time",
+		"Get the current time",
+		func(ctx context.Context, args struct{}) (string, error) {
+			return fmt.Sprintf(`{"time": %q, "timezone": "UTC"}`, time.Now().UTC().Format(time.RFC3339)), nil
+		},
+	)
+
+	tool.MustRegisterFunc(registry, "echo",
+		"Echo back the input message (useful for testing)",
+		func(ctx context.Context, args struct {
+			Message string `json:"message" desc:"Message to echo back" required:"true"`
+		}) (string, error) {
+			return fmt.Sprintf(`{"echo": %q}`, args.Message), nil
+		},
+	)
+}
+
+func createClient(cfg *Config) (*client.Client, error) {
+	// Determine default model based on provider
+	var defaultChat gains.Model
+	switch cfg.Provider {
+	case "anthropic":
+		defaultChat = model.ClaudeSonnet45
+	case "openai":
+		defaultChat = model.GPT52
+	case "google":
+		defaultChat = model.Gemini25Flash
+	case "vertex":
+		defaultChat = model.VertexGemini25Flash
+	default:
+		return nil, fmt.Errorf("unknown provider: %s", cfg.Provider)
+	}
+
+	return client.New(client.Config{
+		Credentials: client.Credentials{
+			Anthropic: cfg.AnthropicKey,
+			OpenAI:    cfg.OpenAIKey,
+			Google:    cfg.GoogleKey,
+			Vertex: client.VertexConfig{
+				Project:  cfg.VertexProject,
+				Location: cfg.VertexLocation,
+			},
+		},
+		Defaults: client.Defaults{
+			Chat: defaultChat,
+		},
+	}), nil
+}
+
+// setupLogger creates a text logger with the specified level.
+func setupLogger(level string) *slog.Logger {
+	var logLevel slog.Level
+	switch strings.ToLower(level) {
+	case "debug":
+		logLevel = slog.LevelDebug
+	case "warn", "warning":
+		logLevel = slog.LevelWarn
+	case "error":
+		logLevel = slog.LevelError
+	default:
+		logLevel = slog.LevelInfo
+	}
+	return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel}))
+}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/spetersoncode/gains/a2a"
+	"github.com/spetersoncode/gains/runtime"
+	"github.com/spetersoncode/gains/sse"
+)
+
+// jsonRPCRequest represents a JSON-RPC 2.0 request.
+type jsonRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      any             `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// jsonRPCResponse represents a JSON-RPC 2.0 response.
+type jsonRPCResponse struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      any           `json:"id"`
+	Result  any           `json:"result,omitempty"`
+	Error   *jsonRPCError `json:"error,omitempty"`
+}
+
+// jsonRPCError represents a JSON-RPC 2.0 error.
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+// JSON-RPC and A2A error codes
+const (
+	jsonRPCParseError     = -32700
+	jsonRPCInvalidRequest = -32600
+	jsonRPCMethodNotFound = -32601
+	jsonRPCInvalidParams  = -32602
+	jsonRPCInternalError  = -32603
+	a2aTaskNotFound       = -32001
+	a2aTaskNotCancelable  = -32002
+	a2aPushNotSupported   = -32003
+)
+
+// taskQueryParams represents the params of tasks/get and tasks/cancel.
+type taskQueryParams struct {
+	ID            string `json:"id"`
+	HistoryLength *int   `json:"historyLength,omitempty"`
+}
+
+// Server serves an A2A executor over JSON-RPC 2.0 and publishes its
+// agent card.
+//
+// It supports message/send, message/stream, tasks/get, and tasks/cancel,
+// plus the older tasks/send and tasks/sendSubscribe names used by
+// a2a.Client.
+type Server struct {
+	executor a2a.Executor
+	tasks    *taskStore
+	card     a2a.AgentCard
+}
+
+// NewServer creates a server running requests on executor and keeping up
+// to maxTasks finished tasks.
+func NewServer(executor a2a.Executor, card a2a.AgentCard, maxTasks int) *Server {
+	return &Server{executor: executor, tasks: newTaskStore(maxTasks), card: card}
+}
+
+// Handler returns the HTTP handler of the server: JSON-RPC requests are
+// POSTed to "/" and the agent card is at a2a.AgentCardPath. Requests are
+// refused once the process starts draining for shutdown.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("POST /{$}", runtime.Handler(http.HandlerFunc(s.serveRPC)))
+	mux.HandleFunc("GET "+a2a.AgentCardPath, s.serveCard)
+	// Path used by clients of protocol versions before 0.3
+	mux.HandleFunc("GET /.well-known/agent.json", s.serveCard)
+	mux.HandleFunc("GET /health", healthHandler)
+	return mux
+}
+
+// serveCard writes the agent card.
+func (s *Server) serveCard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.card)
+}
+
+// serveRPC decodes a JSON-RPC request and dispatches it by method.
+func (s *Server) serveRPC(w http.ResponseWriter, r *http.Request) {
+	var req jsonRPCRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.Warn("invalid JSON-RPC request", "error", err)
+		writeError(w, nil, jsonRPCParseError, "Parse error: "+err.Error())
+		return
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		writeError(w, req.ID, jsonRPCInvalidRequest, "Invalid JSON-RPC request")
+		return
+	}
+
+	log := slog.With("method", req.Method, "id", req.ID)
+	log.Info("A2A request received")
+
+	switch req.Method {
+	case "message/send", "tasks/send":
+		s.handleSend(w, r, req, log)
+	case "message/stream", "tasks/sendSubscribe":
+		s.handleStream(w, r, req, log)
+	case "tasks/get":
+		s.handleGet(w, req)
+	case "tasks/cancel":
+		s.handleCancel(w, req, log)
+	default:
+		log.Warn("unknown method")
+		writeError(w, req.ID, jsonRPCMethodNotFound, "Method not found: "+req.Method)
+	}
+}
+
+// handleSend runs a message to completion and returns the resulting task.
+func (s *Server) handleSend(w http.ResponseWriter, r *http.Request, req jsonRPCRequest, log *slog.Logger) {
+	params, ok := decodeSendParams(w, req)
+	if !ok {
+		return
+	}
+
+	start := time.Now()
+	task, err := s.execute(r.Context(), params, nil)
+	if err != nil {
+		log.Error("execution error", "error", err)
+		writeError(w, req.ID, jsonRPCInternalError, "Execution error: "+err.Error())
+		return
+	}
+
+	writeResult(w, req.ID, task)
+	log.Info("A2A request completed",
+		"task_id", task.ID,
+		"status", task.Status.State,
+		"duration_ms", time.Since(start).Milliseconds(),
+	)
+}
+
+// handleStream runs a message, streaming its events as SSE. Each event is
+// sent as a JSON-RPC response to the request.
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request, req jsonRPCRequest, log *slog.Logger) {
+	params, ok := decodeSendParams(w, req)
+	if !ok {
+		return
+	}
+
+	sw, err := sse.NewWriter(r.Context(), w)
+	if err != nil {
+		log.Error("streaming not supported")
+		writeError(w, req.ID, jsonRPCInternalError, "Streaming not supported")
+		return
+	}
+	defer sw.Close()
+
+	start := time.Now()
+	var eventCount int
+	task, err := s.execute(r.Context(), params, func(evt a2a.Event) error {
+		if err := sw.WriteJSON("", jsonRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: evt}); err != nil {
+			return err
+		}
+		eventCount++
+		return nil
+	})
+	if err != nil {
+		log.Error("execution error", "error", err)
+		return
+	}
+
+	log.Info("A2A streaming request completed",
+		"task_id", task.ID,
+		"status", task.Status.State,
+		"duration_ms", time.Since(start).Milliseconds(),
+		"events_sent", eventCount,
+	)
+}
+
+// handleGet returns a stored task.
+func (s *Server) handleGet(w http.ResponseWriter, req jsonRPCRequest) {
+	var params taskQueryParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.ID == "" {
+		writeError(w, req.ID, jsonRPCInvalidParams, "Invalid params: task id required")
+		return
+	}
+
+	task, ok := s.tasks.get(params.ID, params.HistoryLength)
+	if !ok {
+		writeError(w, req.ID, a2aTaskNotFound, "Task not found: "+params.ID)
+		return
+	}
+	writeResult(w, req.ID, task)
+}
+
+// handleCancel cancels a running task and returns it in the canceled state.
+func (s *Server) handleCancel(w http.ResponseWriter, req jsonRPCRequest, log *slog.Logger) {
+	var params taskQueryParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.ID == "" {
+		writeError(w, req.ID, jsonRPCInvalidParams, "Invalid params: task id required")
+		return
+	}
+
+	found, err := s.tasks.cancel(params.ID)
+	switch {
+	case !found:
+		writeError(w, req.ID, a2aTaskNotFound, "Task not found: "+params.ID)
+		return
+	case err != nil:
+		writeError(w, req.ID, a2aTaskNotCancelable, "Task cannot be canceled: "+params.ID)
+		return
+	}
+
+	task, ok := s.tasks.get(params.ID, params.HistoryLength)
+	if !ok {
+		writeError(w, req.ID, a2aTaskNotFound, "Task not found: "+params.ID)
+		return
+	}
+	task.Status = a2a.NewTaskStatus(a2a.TaskStateCanceled)
+	writeResult(w, req.ID, task)
+	log.Info("A2A task canceled", "task_id", params.ID)
+}
+
+// execute runs params on the executor, recording the task in the store
+// and passing each event to emit, if set. It returns the finished task.
+//
+// A task stopped by tasks/cancel ends in the canceled state rather than
+// the failure the executor reports. A write error from emit cancels the
+// run, since the client has gone.
+func (s *Server) execute(ctx context.Context, params a2a.SendMessageRequest, emit func(a2a.Event) error) (*a2a.Task, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var taskID, contextID string
+	var final bool
+	send := func(evt a2a.Event) {
+		s.tasks.apply(evt)
+		if emit != nil && ctx.Err() == nil {
+			if err := emit(evt); err != nil {
+				cancel(err)
+			}
+		}
+	}
+
+	for evt := range s.executor.ExecuteStream(ctx, params) {
+		if taskID == "" {
+			taskID, contextID = taskOf(evt)
+			task := a2a.NewTask(taskID, contextID)
+			msg := params.Message
+			msg.TaskID = &taskID
+			msg.ContextID = &contextID
+			task.History = []a2a.Message{msg}
+			s.tasks.start(task, cancel)
+		}
+
+		if update, ok := evt.(a2a.TaskStatusUpdateEvent); ok && update.Final {
+			// A canceled task ends with the canceled status below instead
+			if canceled(ctx) {
+				continue
+			}
+			final = true
+		}
+		send(evt)
+	}
+
+	if taskID == "" {
+		return nil, fmt.Errorf("executor produced no events")
+	}
+	switch {
+	case canceled(ctx):
+		send(a2a.NewTaskStatusUpdateEvent(taskID, contextID, a2a.NewTaskStatus(a2a.TaskStateCanceled), true))
+	case !final:
+		msg := a2a.NewMessage(a2a.MessageRoleAgent, a2a.NewTextPart("task ended without a result"))
+		send(a2a.NewTaskStatusUpdateEvent(taskID, contextID, a2a.NewTaskStatusWithMessage(a2a.TaskStateFailed, &msg), true))
+	}
+
+	task, _ := s.tasks.get(taskID, historyLength(params))
+	return task, nil
+}
+
+// decodeSendParams decodes the params of a send request, writing an error
+// response if they are invalid.
+func decodeSendParams(w http.ResponseWriter, req jsonRPCRequest) (a2a.SendMessageRequest, bool) {
+	var params a2a.SendMessageRequest
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		writeError(w, req.ID, jsonRPCInvalidParams, "Invalid params: "+err.Error())
+		return params, false
+	}
+	if len(params.Message.Parts) == 0 {
+		writeError(w, req.ID, jsonRPCInvalidParams, "Invalid params: message has no parts")
+		return params, false
+	}
+	if params.Configuration != nil && params.Configuration.PushNotificationConfig != nil {
+		writeError(w, req.ID, a2aPushNotSupported, "Push notifications are not supported")
+		return params, false
+	}
+	return params, true
+}
+
+// historyLength returns the history length requested by params, if any.
+func historyLength(params a2a.SendMessageRequest) *int {
+	if params.Configuration == nil {
+		return nil
+	}
+	return params.Configuration.HistoryLength
+}
+
+// taskOf returns the task and context IDs of an A2A event.
+func taskOf(evt a2a.Event) (taskID, contextID string) {
+	switch e := evt.(type) {
+	case a2a.TaskStatusUpdateEvent:
+		return e.TaskID, e.ContextID
+	case a2a.TaskArtifactUpdateEvent:
+		return e.TaskID, e.ContextID
+	}
+	return "", ""
+}
+
+// writeResult writes a successful JSON-RPC response.
+func writeResult(w http.ResponseWriter, id any, result any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jsonRPCResponse{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+// writeError writes a JSON-RPC error response.
+func writeError(w http.ResponseWriter, id any, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jsonRPCResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   &jsonRPCError{Code: code, Message: message},
+	})
+}
+
+// healthHandler returns a simple health check response.
+func healthHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status":"ok"}`))
+}
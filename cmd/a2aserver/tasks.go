@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/spetersoncode/gains/a2a"
+	"github.com/spetersoncode/gains/cmd/internal/admin"
+)
+
+// errTaskCanceled is the cancellation cause of tasks stopped by
+// tasks/cancel, distinguishing them from requests whose client went away.
+var errTaskCanceled = errors.New("task canceled")
+
+// errTaskNotCancelable is returned when canceling a task that has finished.
+var errTaskNotCancelable = errors.New("task not cancelable")
+
+// taskEntry is a task, its status for the admin endpoints, and, while it
+// runs, the function that cancels it.
+type taskEntry struct {
+	task   *a2a.Task
+	status *admin.Status
+	cancel context.CancelCauseFunc
+}
+
+// taskStore keeps the tasks of the server in memory for tasks/get,
+// tasks/cancel, and the admin endpoints. Finished tasks beyond the limit
+// are forgotten, oldest first.
+type taskStore struct {
+	mu       sync.Mutex
+	tasks    map[string]*taskEntry
+	finished []string
+	max      int
+}
+
+// newTaskStore creates a store keeping at most max finished tasks.
+func newTaskStore(max int) *taskStore {
+	return &taskStore{tasks: make(map[string]*taskEntry), max: max}
+}
+
+// start registers a running task that cancel stops.
+func (s *taskStore) start(task *a2a.Task, cancel context.CancelCauseFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tasks[task.ID] = &taskEntry{task: task, status: admin.NewStatus(task.ID, task.ContextID), cancel: cancel}
+}
+
+// apply updates the task of evt with its status or artifact. A final
+// status finishes the task and adds its message to the history.
+func (s *taskStore) apply(evt a2a.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch e := evt.(type) {
+	case a2a.TaskStatusUpdateEvent:
+		entry, ok := s.tasks[e.TaskID]
+		if !ok || entry.cancel == nil {
+			return
+		}
+		entry.task.Status = e.Status
+		entry.status.Add(statusEvent(e.Status))
+		if !e.Final {
+			return
+		}
+		if e.Status.Message != nil {
+			entry.task.History = append(entry.task.History, *e.Status.Message)
+		}
+		entry.status.SetOutcome(outcome(e.Status.State))
+		entry.status.End()
+		entry.cancel = nil
+		s.finished = append(s.finished, e.TaskID)
+		for s.max > 0 && len(s.finished) > s.max {
+			delete(s.tasks, s.finished[0])
+			s.finished = s.finished[1:]
+		}
+	case a2a.TaskArtifactUpdateEvent:
+		if entry, ok := s.tasks[e.TaskID]; ok {
+			entry.task.Artifacts = append(entry.task.Artifacts, e.Artifact)
+			entry.status.Add(admin.RunEvent{Type: "artifact-update", Message: e.Artifact.Name})
+		}
+	}
+}
+
+// statusEvent returns a task status update as the admin endpoints show it.
+func statusEvent(status a2a.TaskStatus) admin.RunEvent {
+	e := admin.RunEvent{Type: "status-update", Message: string(status.State)}
+	e.Timestamp, _ = time.Parse(time.RFC3339, status.Timestamp)
+	if status.State == a2a.TaskStateFailed && status.Message != nil {
+		e.Error = status.Message.TextContent()
+	}
+	return e
+}
+
+// outcome returns the admin outcome of a final task state.
+func outcome(state a2a.TaskState) string {
+	switch state {
+	case a2a.TaskStateCompleted:
+		return admin.OutcomeCompleted
+	case a2a.TaskStateCanceled:
+		return admin.OutcomeCanceled
+	case a2a.TaskStateFailed, a2a.TaskStateRejected:
+		return admin.OutcomeFailed
+	}
+	return admin.OutcomeEnded
+}
+
+// Active returns the admin status of the running tasks, oldest first.
+func (s *taskStore) Active() []admin.RunInfo {
+	s.mu.Lock()
+	var infos []admin.RunInfo
+	for _, entry := range s.tasks {
+		if entry.cancel != nil {
+			infos = append(infos, entry.status.Info())
+		}
+	}
+	s.mu.Unlock()
+	slices.SortFunc(infos, func(a, b admin.RunInfo) int { return a.StartedAt.Compare(b.StartedAt) })
+	return infos
+}
+
+// Status returns the admin status of task id, running or finished.
+func (s *taskStore) Status(id string) (*admin.Status, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.tasks[id]
+	if !ok {
+		return nil, false
+	}
+	return entry.status, true
+}
+
+// get returns a copy of task id with at most historyLength messages of
+// history, or all of it if historyLength is nil.
+func (s *taskStore) get(id string, historyLength *int) (*a2a.Task, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.tasks[id]
+	if !ok {
+		return nil, false
+	}
+	task := *entry.task
+	task.Artifacts = slices.Clone(task.Artifacts)
+	task.History = slices.Clone(task.History)
+	if historyLength != nil && len(task.History) > *historyLength {
+		task.History = task.History[len(task.History)-max(*historyLength, 0):]
+	}
+	return &task, true
+}
+
+// cancel stops running task id with errTaskCanceled. It reports whether
+// the task exists, and returns errTaskNotCancelable if it has finished.
+func (s *taskStore) cancel(id string) (ok bool, err error) {
+	s.mu.Lock()
+	entry, ok := s.tasks[id]
+	var cancel context.CancelCauseFunc
+	if ok {
+		cancel = entry.cancel
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		return false, nil
+	}
+	if cancel == nil {
+		return true, errTaskNotCancelable
+	}
+	cancel(errTaskCanceled)
+	return true, nil
+}
+
+// canceled reports whether ctx was canceled by tasks/cancel.
+func canceled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errTaskCanceled)
+}
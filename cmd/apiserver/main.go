@@ -0,0 +1,252 @@
+// Package main provides a reference JSON API server that exposes a gains
+// agent and demo workflows over REST, so services in any language can run
+// them without linking the library.
+//
+// Runs start in the background; their status, output, and events are read
+// by run ID, and an OpenAPI document describing every agent and workflow
+// is served for generating clients. It uses only the Go standard library
+// for HTTP.
+//
+// Configuration is via environment variables:
+//
+//	APISERVER_PORT             - Server port (default: 8002)
+//	APISERVER_LOG_LEVEL        - Log level: debug, info, warn, error (default: info)
+//	APISERVER_MAX_RUNS         - Finished runs kept for status and events (default: 1000)
+//	APISERVER_MAX_ACTIVE_RUNS  - Runs executing at once; more get 429 (default: 100)
+//	APISERVER_API_KEYS         - Comma-separated bearer tokens; unset disables authentication
+//	GAINS_PROVIDER             - Provider: anthropic, openai, google, or vertex (required)
+//	GAINS_MAX_STEPS            - Max agent iterations (default: 10)
+//	GAINS_TIMEOUT              - Agent timeout (default: 2m)
+//	ANTHROPIC_API_KEY          - Anthropic API key
+//	OPENAI_API_KEY             - OpenAI API key
+//	GOOGLE_API_KEY      - Google API key
+//	VERTEX_PROJECT      - Vertex AI project ID
+//	VERTEX_LOCATION     - Vertex AI location (e.g., us-central1)
+//
+// Usage:
+//
+//	GAINS_PROVIDER=anthropic go run ./cmd/apiserver
+//
+// Then start a run, follow its events, and read its output:
+//
+//	curl -s localhost:8002/v1/workflows/greeting/runs -d '{"input": {"name": "Ada"}}'
+//	curl -N localhost:8002/v1/runs/<id>/events
+//	curl -s localhost:8002/v1/runs/<id>
+//
+// Generate a client from the OpenAPI document:
+//
+//	curl -s localhost:8002/v1/openapi.json > openapi.json
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/agent"
+	"github.com/spetersoncode/gains/apiserver"
+	"github.com/spetersoncode/gains/client"
+	"github.com/spetersoncode/gains/model"
+	"github.com/spetersoncode/gains/runtime"
+	"github.com/spetersoncode/gains/tool"
+	"github.com/spetersoncode/gains/workflow"
+)
+
+func main() {
+	// Load configuration
+	cfg, err := LoadConfig()
+	if err != nil {
+		slog.Error("configuration error", "error", err)
+		os.Exit(1)
+	}
+
+	// Setup structured logger
+	slog.SetDefault(setupLogger(cfg.LogLevel))
+
+	// Create gains client
+	gainsClient, err := createClient(cfg)
+	if err != nil {
+		slog.Error("failed to create client", "error", err)
+		os.Exit(1)
+	}
+
+	// Create tool registry and agent
+	registry := tool.NewRegistry()
+	setupDemoTools(registry)
+	a := agent.New(gainsClient, registry)
+
+	// Create workflow registry with demo workflows
+	workflows := setupDemoWorkflows(gainsClient)
+	slog.Info("registered demo workflows", "count", workflows.Len(), "names", workflows.Names())
+
+	opts := []apiserver.Option{
+		apiserver.WithWorkflows(workflows),
+		apiserver.WithAgent("assistant", a,
+			agent.WithMaxSteps(cfg.MaxSteps),
+			agent.WithTimeout(cfg.Timeout),
+		),
+		apiserver.WithMaxRuns(cfg.MaxRuns),
+		apiserver.WithMaxActiveRuns(cfg.MaxActiveRuns),
+	}
+	if len(cfg.APIKeys) > 0 {
+		opts = append(opts, apiserver.WithAPIKeys(cfg.APIKeys...))
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/v1/", apiserver.NewHandler(opts...))
+	mux.HandleFunc("GET /health", healthHandler)
+
+	// Create server
+	server := &http.Server{
+		Addr:         ":" + cfg.Port,
+		Handler:      mux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 0, // SSE needs no write timeout
+		IdleTimeout:  120 * time.Second,
+	}
+
+	// Graceful shutdown
+	go func() {
+		sigCh := make(chan os.Signal, 1)
+		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+		<-sigCh
+
+		slog.Info("shutting down")
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		// Let in-flight runs finish before closing connections
+		if err := runtime.Drain(ctx); err != nil {
+			slog.Warn("drain incomplete", "error", err)
+		}
+		if err := server.Shutdown(ctx); err != nil {
+			slog.Error("shutdown error", "error", err)
+		}
+	}()
+
+	// Start server
+	slog.Info("server starting",
+		"port", cfg.Port,
+		"provider", cfg.Provider,
+		"log_level", cfg.LogLevel,
+		"authenticated", len(cfg.APIKeys) > 0,
+		"openapi", fmt.Sprintf("GET http://localhost:%s/v1/openapi.json", cfg.Port),
+		"health", fmt.Sprintf("GET http://localhost:%s/health", cfg.Port),
+	)
+
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		slog.Error("server error", "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("server stopped")
+}
+
+// setupDemoTools registers the tools the demo agent can use.
+func setupDemoTools(registry *tool.Registry) {
+	tool.MustRegisterFunc(registry, "get_time",
+		"Get the current time",
+		func(ctx context.Context, args struct{}) (string, error) {
+			return fmt.Sprintf(`{"time": %q, "timezone": "UTC"}`, time.Now().UTC().Format(time.RFC3339)), nil
+		},
+	)
+}
+
+// GreetingState is the state for the greeting demo workflow.
+type GreetingState struct {
+	Name     string `json:"name" desc:"Who to greet"`
+	Style    string `json:"style" desc:"Greeting style, e.g. formal, casual, pirate"`
+	Greeting string `json:"greeting" desc:"The generated greeting"`
+}
+
+// setupDemoWorkflows creates a workflow registry with demo workflows.
+func setupDemoWorkflows(c *client.Client) *workflow.Registry {
+	registry := workflow.NewRegistry()
+
+	validate := workflow.NewFuncStep("validate", func(ctx context.Context, state *GreetingState) error {
+		if state.Name == "" {
+			state.Name = "friend"
+		}
+		if state.Style == "" {
+			state.Style = "casual"
+		}
+		return nil
+	})
+	generate := workflow.NewPromptStep[GreetingState, string](
+		"generate",
+		c,
+		func(state *GreetingState) []gains.Message {
+			return []gains.Message{{
+				Role:    gains.RoleUser,
+				Content: "Generate a " + state.Style + " greeting for " + state.Name + ". Keep it to one sentence.",
+			}}
+		},
+		nil, // No schema - plain text response
+		func(state *GreetingState) *string { return &state.Greeting },
+	)
+	registry.Register(workflow.NewRunnerJSON[GreetingState]("greeting",
+		workflow.NewChain("greeting-workflow", validate, generate)))
+
+	return registry
+}
+
+func createClient(cfg *Config) (*client.Client, error) {
+	// Determine default model based on provider
+	var defaultChat gains.Model
+	switch cfg.Provider {
+	case "anthropic":
+		defaultChat = model.ClaudeSonnet45
+	case "openai":
+		defaultChat = model.GPT52
+	case "google":
+		defaultChat = model.Gemini25Flash
+	case "vertex":
+		defaultChat = model.VertexGemini25Flash
+	default:
+		return nil, fmt.Errorf("unknown provider: %s", cfg.Provider)
+	}
+
+	return client.New(client.Config{
+		Credentials: client.Credentials{
+			Anthropic: cfg.AnthropicKey,
+			OpenAI:    cfg.OpenAIKey,
+			Google:    cfg.GoogleKey,
+			Vertex: client.VertexConfig{
+				Project:  cfg.VertexProject,
+				Location: cfg.VertexLocation,
+			},
+		},
+		Defaults: client.Defaults{
+			Chat: defaultChat,
+		},
+	}), nil
+}
+
+// healthHandler returns a simple health check response.
+func healthHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status":"ok"}`))
+}
+
+// setupLogger creates a text logger with the specified level.
+func setupLogger(level string) *slog.Logger {
+	var logLevel slog.Level
+	switch strings.ToLower(level) {
+	case "debug":
+		logLevel = slog.LevelDebug
+	case "warn", "warning":
+		logLevel = slog.LevelWarn
+	case "error":
+		logLevel = slog.LevelError
+	default:
+		logLevel = slog.LevelInfo
+	}
+	return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: logLevel}))
+}
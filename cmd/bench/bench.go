@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/client"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/judge"
+	"github.com/spetersoncode/gains/model"
+)
+
+// bench runs suites against models.
+type bench struct {
+	client *client.Client
+	// judgeModel scores responses when set.
+	judgeModel *model.ChatModel
+	runs       int
+	maxTokens  int
+	timeout    time.Duration
+}
+
+// runResult is the outcome of one run of a case against a model.
+type runResult struct {
+	Case             string        `json:"case"`
+	Run              int           `json:"run"`
+	TimeToFirstToken time.Duration `json:"timeToFirstToken"`
+	TokensPerSecond  float64       `json:"tokensPerSecond"`
+	Usage            ai.Usage      `json:"usage"`
+	Cost             float64       `json:"cost"`
+	Score            int           `json:"score,omitempty"`
+	Reasoning        string        `json:"reasoning,omitempty"`
+	Error            string        `json:"error,omitempty"`
+}
+
+// modelResult summarizes the runs of one model.
+type modelResult struct {
+	Model    string `json:"model"`
+	Provider string `json:"provider"`
+	Runs     int    `json:"runs"`
+	Errors   int    `json:"errors"`
+	// Means are over successful runs; MeanScore is over scored runs.
+	MeanTimeToFirstToken time.Duration `json:"meanTimeToFirstToken"`
+	MeanTokensPerSecond  float64       `json:"meanTokensPerSecond"`
+	MeanScore            float64       `json:"meanScore,omitempty"`
+	TotalCost            float64       `json:"totalCost"`
+	// JudgeCost is what scoring this model's responses cost.
+	JudgeCost float64     `json:"judgeCost,omitempty"`
+	Results   []runResult `json:"results"`
+}
+
+// run benchmarks each model on each case of suite, logging progress to
+// stderr.
+func (b *bench) run(ctx context.Context, models []model.ChatModel, suite []benchCase) []modelResult {
+	var results []modelResult
+	for _, m := range models {
+		mr := modelResult{Model: m.String(), Provider: m.Provider().String()}
+		for _, c := range suite {
+			for run := 1; run <= b.runs; run++ {
+				log.Printf("%s: %s (run %d/%d)", m, c.Name, run, b.runs)
+				r, judgeUsage := b.runCase(ctx, m, c)
+				r.Run = run
+				if r.Error != "" {
+					log.Printf("%s: %s: %s", m, c.Name, r.Error)
+				}
+				if b.judgeModel != nil {
+					mr.JudgeCost += b.judgeModel.Cost(judgeUsage)
+				}
+				mr.Results = append(mr.Results, r)
+			}
+		}
+		mr.summarize()
+		results = append(results, mr)
+	}
+	return results
+}
+
+// runCase sends c to m and scores the response, returning the result and
+// the judge's token usage.
+func (b *bench) runCase(ctx context.Context, m model.ChatModel, c benchCase) (runResult, ai.Usage) {
+	r := runResult{Case: c.Name}
+	ctx, cancel := context.WithTimeout(ctx, b.timeout)
+	defer cancel()
+
+	var messages []ai.Message
+	if c.System != "" {
+		messages = append(messages, ai.Message{Role: ai.RoleSystem, Content: c.System})
+	}
+	messages = append(messages, ai.Message{Role: ai.RoleUser, Content: c.Prompt})
+
+	ch, err := b.client.ChatStream(ctx, messages, ai.WithModel(m), ai.WithMaxTokens(b.maxTokens))
+	if err != nil {
+		r.Error = err.Error()
+		return r, ai.Usage{}
+	}
+	resp, stats, err := event.Accumulate(ch)
+	if err != nil {
+		r.Error = err.Error()
+		return r, ai.Usage{}
+	}
+	r.TimeToFirstToken = stats.TimeToFirstToken
+	r.TokensPerSecond = stats.TokensPerSecond
+	r.Usage = resp.Usage
+	r.Cost = m.Cost(resp.Usage)
+
+	if b.judgeModel == nil {
+		return r, ai.Usage{}
+	}
+	opts := []judge.Option{judge.WithModel(*b.judgeModel)}
+	if c.Criteria != "" {
+		opts = append(opts, judge.WithCriteria(c.Criteria))
+	}
+	verdict, err := judge.New(b.client, opts...).Score(ctx, c.Prompt, resp.Content)
+	if err != nil {
+		r.Error = "judge: " + err.Error()
+		return r, ai.Usage{}
+	}
+	r.Score = verdict.Score
+	r.Reasoning = verdict.Reasoning
+	return r, verdict.Usage
+}
+
+// summarize computes the model's totals and means from its results.
+func (mr *modelResult) summarize() {
+	var ok, scored int
+	var ttft time.Duration
+	var tps, score float64
+	for _, r := range mr.Results {
+		mr.Runs++
+		mr.TotalCost += r.Cost
+		if r.Score > 0 {
+			scored++
+			score += float64(r.Score)
+		}
+		if r.Error != "" {
+			mr.Errors++
+			if r.Usage.OutputTokens == 0 {
+				continue
+			}
+		}
+		ok++
+		ttft += r.TimeToFirstToken
+		tps += r.TokensPerSecond
+	}
+	if ok > 0 {
+		mr.MeanTimeToFirstToken = ttft / time.Duration(ok)
+		mr.MeanTokensPerSecond = tps / float64(ok)
+	}
+	if scored > 0 {
+		mr.MeanScore = score / float64(scored)
+	}
+}
@@ -0,0 +1,146 @@
+// Command bench runs a prompt suite across chat models and compares their
+// latency, throughput, cost, and quality, to help choose a model.
+//
+// Each case of the suite is sent to each model as a streaming request. The
+// report gives each model's mean time to first token, mean output tokens
+// per second, total cost, and, when a judge model is set, mean quality
+// score from 1 to 10 as rated by the judge package.
+//
+// Models are given by API identifier, optionally prefixed with the
+// provider to tell Google and Vertex models apart; by default the default
+// model of every provider with credentials is benchmarked.
+//
+// Usage:
+//
+//	go run ./cmd/bench -models claude-haiku-4-5,gpt-5-mini,vertex/gemini-2.5-flash -judge claude-opus-4-5
+//	go run ./cmd/bench -suite prompts.json -runs 3 -json > results.json
+//
+// A suite file is a JSON array of cases:
+//
+//	[{"name": "summary", "system": "Be brief.", "prompt": "Summarize ...", "criteria": "Accuracy and brevity."}]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/spetersoncode/gains/client"
+	"github.com/spetersoncode/gains/model"
+)
+
+func main() {
+	models := flag.String("models", "", "comma-separated model IDs, optionally provider/ID (default: each provider's default model)")
+	suitePath := flag.String("suite", "", "JSON file of prompt cases (default: built-in suite)")
+	runs := flag.Int("runs", 1, "runs of each case per model")
+	judgeModel := flag.String("judge", "", "model ID that scores responses (default: no scoring)")
+	maxTokens := flag.Int("max-tokens", 1024, "maximum output tokens per response")
+	timeout := flag.Duration("timeout", 2*time.Minute, "timeout per request")
+	asJSON := flag.Bool("json", false, "print results as JSON instead of a table")
+	flag.Parse()
+
+	godotenv.Load()
+
+	suite := defaultSuite
+	if *suitePath != "" {
+		var err error
+		if suite, err = loadSuite(*suitePath); err != nil {
+			log.Fatal(err)
+		}
+	}
+	selected, err := selectModels(*models)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	c := client.New(client.Config{
+		Credentials: client.Credentials{
+			Anthropic: os.Getenv("ANTHROPIC_API_KEY"),
+			OpenAI:    os.Getenv("OPENAI_API_KEY"),
+			Google:    os.Getenv("GOOGLE_API_KEY"),
+			Vertex: client.VertexConfig{
+				Project:  os.Getenv("VERTEX_PROJECT"),
+				Location: os.Getenv("VERTEX_LOCATION"),
+			},
+		},
+	})
+
+	b := &bench{
+		client:    c,
+		runs:      *runs,
+		maxTokens: *maxTokens,
+		timeout:   *timeout,
+	}
+	if *judgeModel != "" {
+		m, err := lookupModel(*judgeModel)
+		if err != nil {
+			log.Fatalf("judge: %v", err)
+		}
+		b.judgeModel = &m
+	}
+
+	results := b.run(context.Background(), selected, suite)
+	if *asJSON {
+		err = writeJSON(os.Stdout, results)
+	} else {
+		err = writeTable(os.Stdout, results, b.judgeModel != nil)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// selectModels parses the -models flag, defaulting to the default model of
+// each provider with credentials.
+func selectModels(list string) ([]model.ChatModel, error) {
+	if list == "" {
+		var models []model.ChatModel
+		if os.Getenv("ANTHROPIC_API_KEY") != "" {
+			models = append(models, model.DefaultClaudeModel)
+		}
+		if os.Getenv("OPENAI_API_KEY") != "" {
+			models = append(models, model.DefaultGPTModel)
+		}
+		if os.Getenv("GOOGLE_API_KEY") != "" {
+			models = append(models, model.DefaultGeminiModel)
+		}
+		if os.Getenv("VERTEX_PROJECT") != "" && os.Getenv("VERTEX_LOCATION") != "" {
+			models = append(models, model.DefaultVertexModel)
+		}
+		if len(models) == 0 {
+			return nil, fmt.Errorf("no credentials: set ANTHROPIC_API_KEY, OPENAI_API_KEY, GOOGLE_API_KEY, or VERTEX_PROJECT + VERTEX_LOCATION")
+		}
+		return models, nil
+	}
+
+	var models []model.ChatModel
+	for _, id := range strings.Split(list, ",") {
+		m, err := lookupModel(strings.TrimSpace(id))
+		if err != nil {
+			return nil, err
+		}
+		models = append(models, m)
+	}
+	return models, nil
+}
+
+// lookupModel returns the chat model with the given API identifier, which
+// may be prefixed with its provider as in "vertex/gemini-2.5-flash".
+// Without a prefix the first model with the identifier is returned.
+func lookupModel(id string) (model.ChatModel, error) {
+	provider, name, ok := strings.Cut(id, "/")
+	if !ok {
+		provider, name = "", id
+	}
+	for _, m := range model.ChatModels() {
+		if m.String() == name && (provider == "" || m.Provider().String() == provider) {
+			return m, nil
+		}
+	}
+	return model.ChatModel{}, fmt.Errorf("unknown model %q", id)
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// writeTable prints one row per model, with a score column when responses
+// were judged.
+func writeTable(w io.Writer, results []modelResult, scored bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	header := "MODEL\tPROVIDER\tRUNS\tERRORS\tTTFT\tTOK/S\tCOST (USD)\t"
+	if scored {
+		header += "SCORE\tJUDGE COST\t"
+	}
+	fmt.Fprintln(tw, header)
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\t%.1f\t%.5f\t",
+			r.Model, r.Provider, r.Runs, r.Errors,
+			r.MeanTimeToFirstToken.Round(time.Millisecond), r.MeanTokensPerSecond, r.TotalCost)
+		if scored {
+			fmt.Fprintf(tw, "%.1f\t%.5f\t", r.MeanScore, r.JudgeCost)
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// writeJSON prints the full results, including each run, as indented JSON.
+func writeJSON(w io.Writer, results []modelResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// benchCase is one prompt of a suite.
+type benchCase struct {
+	Name   string `json:"name"`
+	System string `json:"system,omitempty"`
+	Prompt string `json:"prompt"`
+	// Criteria is what the judge rates responses to this case on. Empty
+	// uses judge.DefaultCriteria.
+	Criteria string `json:"criteria,omitempty"`
+}
+
+// defaultSuite covers short answers, reasoning, writing, and code.
+var defaultSuite = []benchCase{
+	{
+		Name:     "fact",
+		Prompt:   "What causes the seasons on Earth? Answer in two or three sentences.",
+		Criteria: "Scientific accuracy and brevity. The answer must attribute the seasons to axial tilt, not distance from the Sun.",
+	},
+	{
+		Name:     "reasoning",
+		Prompt:   "A bat and a ball cost $1.10 in total. The bat costs $1.00 more than the ball. How much does the ball cost? Explain briefly.",
+		Criteria: "Correctness of the final answer ($0.05) and soundness of the explanation.",
+	},
+	{
+		Name:     "summary",
+		System:   "You are a concise technical writer.",
+		Prompt:   "Summarize the trade-offs between optimistic and pessimistic locking in databases for a new backend engineer, in under 120 words.",
+		Criteria: "Accuracy, coverage of the main trade-offs, clarity for the audience, and respecting the length limit.",
+	},
+	{
+		Name:     "code",
+		Prompt:   "Write a Go function that reports whether a string is a palindrome, ignoring case and non-letter characters. Include only the code.",
+		Criteria: "Correctness, including Unicode handling, idiomatic Go, and following the instruction to include only code.",
+	},
+}
+
+// loadSuite reads a JSON array of cases from path.
+func loadSuite(path string) ([]benchCase, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var suite []benchCase
+	if err := json.Unmarshal(data, &suite); err != nil {
+		return nil, fmt.Errorf("parse suite %s: %w", path, err)
+	}
+	if len(suite) == 0 {
+		return nil, fmt.Errorf("suite %s has no cases", path)
+	}
+	for i, c := range suite {
+		if c.Prompt == "" {
+			return nil, fmt.Errorf("suite %s: case %d has no prompt", path, i)
+		}
+		if c.Name == "" {
+			suite[i].Name = fmt.Sprintf("case-%d", i+1)
+		}
+	}
+	return suite, nil
+}
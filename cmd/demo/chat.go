@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	ai "github.com/spetersoncode/gains"
 	"github.com/spetersoncode/gains/client"
@@ -41,6 +42,7 @@ func demoChatStream(ctx context.Context, c *client.Client) {
 		{Role: ai.RoleUser, Content: "Say hello in 3 different languages, one per line."},
 	}
 
+	acc := ai.NewStreamAccumulator()
 	stream, err := c.ChatStream(ctx, messages)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -49,18 +51,20 @@ func demoChatStream(ctx context.Context, c *client.Client) {
 
 	fmt.Print("\nAssistant:\n")
 	for ev := range stream {
-		switch ev.Type {
-		case event.MessageDelta:
+		if ev.Type == event.MessageDelta {
 			fmt.Print(ev.Delta)
-		case event.MessageEnd:
-			if ev.Response != nil {
-				fmt.Printf("\n[Tokens: %d in, %d out]\n",
-					ev.Response.Usage.InputTokens,
-					ev.Response.Usage.OutputTokens)
-			}
-		case event.RunError:
-			fmt.Fprintf(os.Stderr, "Stream error: %v\n", ev.Error)
-			return
 		}
+		event.AddTo(acc, ev)
 	}
+	if err := acc.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Stream error: %v\n", err)
+		return
+	}
+
+	resp, stats := acc.Response(), acc.Stats()
+	fmt.Printf("\n[Tokens: %d in, %d out | TTFT: %s | %.1f tokens/s]\n",
+		resp.Usage.InputTokens,
+		resp.Usage.OutputTokens,
+		stats.TimeToFirstToken.Round(time.Millisecond),
+		stats.TokensPerSecond)
 }
@@ -0,0 +1,132 @@
+// Command ingest loads the text and Markdown files of a directory into an
+// in-memory vector store and answers a search query against them.
+//
+// It chunks each file by tokens, embeds the chunks with the first embedding
+// provider that has credentials, and prints the chunks closest to the query.
+//
+// Usage:
+//
+//	go run ./cmd/ingest -dir ./docs -query "How do approvals work?"
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/client"
+	"github.com/spetersoncode/gains/ingest"
+	"github.com/spetersoncode/gains/model"
+	"github.com/spetersoncode/gains/vectorstore"
+)
+
+func main() {
+	dir := flag.String("dir", "docs", "directory of .md and .txt files to ingest")
+	query := flag.String("query", "", "search query to run after ingesting")
+	chunkTokens := flag.Int("chunk-tokens", 256, "maximum tokens per chunk")
+	overlap := flag.Int("overlap", 32, "tokens shared between consecutive chunks")
+	topK := flag.Int("k", 3, "number of chunks to show")
+	flag.Parse()
+
+	godotenv.Load()
+	ctx := context.Background()
+
+	embeddingModel, err := selectEmbeddingModel()
+	if err != nil {
+		log.Fatal(err)
+	}
+	c := client.New(client.Config{
+		Credentials: client.Credentials{
+			OpenAI: os.Getenv("OPENAI_API_KEY"),
+			Google: os.Getenv("GOOGLE_API_KEY"),
+			Vertex: client.VertexConfig{
+				Project:  os.Getenv("VERTEX_PROJECT"),
+				Location: os.Getenv("VERTEX_LOCATION"),
+			},
+		},
+		Defaults: client.Defaults{Embedding: embeddingModel},
+	})
+
+	docs, err := readDocuments(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(docs) == 0 {
+		log.Fatalf("no .md or .txt files in %s", *dir)
+	}
+
+	store := vectorstore.NewMemory()
+	pipeline := ingest.NewPipeline(ingest.NewTokenChunker(*chunkTokens, *overlap, nil), c, store)
+	result, err := pipeline.Ingest(ctx, docs...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Ingested %d documents as %d chunks with %s (%d tokens)\n",
+		result.Documents, result.Chunks, embeddingModel, result.Usage.InputTokens)
+
+	if *query == "" {
+		return
+	}
+	resp, err := c.Embed(ctx, []string{*query}, ai.WithEmbeddingTaskType(ai.EmbeddingTaskTypeRetrievalQuery))
+	if err != nil {
+		log.Fatal(err)
+	}
+	matches, err := store.Query(ctx, resp.Embeddings[0], *topK)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, m := range matches {
+		fmt.Printf("\n[%.3f] %s (chunk %s)\n%s\n", m.Score, m.Metadata[ingest.MetadataSource], m.Metadata[ingest.MetadataChunk], m.Content)
+	}
+}
+
+// selectEmbeddingModel returns the default embedding model of the first
+// provider with credentials.
+func selectEmbeddingModel() (ai.Model, error) {
+	switch {
+	case os.Getenv("OPENAI_API_KEY") != "":
+		return model.DefaultOpenAIEmbeddingModel, nil
+	case os.Getenv("GOOGLE_API_KEY") != "":
+		return model.DefaultGoogleEmbeddingModel, nil
+	case os.Getenv("VERTEX_PROJECT") != "" && os.Getenv("VERTEX_LOCATION") != "":
+		return model.DefaultVertexEmbeddingModel, nil
+	}
+	return nil, fmt.Errorf("no embedding credentials: set OPENAI_API_KEY, GOOGLE_API_KEY, or VERTEX_PROJECT + VERTEX_LOCATION")
+}
+
+// readDocuments reads the .md and .txt files under dir, using their paths
+// relative to dir as document IDs.
+func readDocuments(dir string) ([]ingest.Document, error) {
+	var docs []ingest.Document
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext != ".md" && ext != ".txt" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		id, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, ingest.Document{
+			ID:       filepath.ToSlash(id),
+			Text:     string(data),
+			Metadata: map[string]string{"path": path},
+		})
+		return nil
+	})
+	return docs, err
+}
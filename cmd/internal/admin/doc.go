@@ -0,0 +1,12 @@
+// Package admin provides the read-only operational endpoints shared by the
+// reference servers in cmd: the runs in flight, a run's recent events, the
+// registered tools, and the configuration with secrets redacted.
+//
+// A server records each run in a [Status], exposes its runs through
+// [Runs], and mounts a [Handler] at "/admin/" when its admin flag is set:
+//
+//	GET /admin/runs                 - runs in flight, with their step and executing tools
+//	GET /admin/runs/{runId}/events  - a run's recent events, in flight or recently finished
+//	GET /admin/tools                - the registered tools and their parameter schemas
+//	GET /admin/config               - the configuration, secrets redacted
+package admin
@@ -0,0 +1,132 @@
+package admin
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/spetersoncode/gains/tool"
+)
+
+// Redacted replaces secrets in the config dump.
+const Redacted = "[redacted]"
+
+// Redact returns Redacted for a set secret, and "" for an unset one.
+func Redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// Runs is a source of runs, such as a server's tracker of in-flight runs
+// or its task store.
+type Runs interface {
+	// Active returns the runs in flight, oldest first.
+	Active() []RunInfo
+
+	// Status returns run id, in flight or recently finished.
+	Status(id string) (*Status, bool)
+}
+
+// Handler serves read-only operational endpoints: the runs in flight, a
+// run's recent events, the registered tools, and the configuration with
+// secrets redacted. It exposes internals, so servers mount it only when
+// asked to.
+type Handler struct {
+	mux      *http.ServeMux
+	runs     map[string]Runs // by run kind
+	registry *tool.Registry
+	config   map[string]any
+}
+
+// NewHandler creates an admin handler over runs, keyed by the kind of run
+// they hold, the tool registry, and the configuration to show, which must
+// already have its secrets redacted.
+func NewHandler(runs map[string]Runs, registry *tool.Registry, config map[string]any) *Handler {
+	h := &Handler{mux: http.NewServeMux(), runs: runs, registry: registry, config: config}
+	h.mux.HandleFunc("GET /admin/runs", h.serveRuns)
+	h.mux.HandleFunc("GET /admin/runs/{runId}/events", h.serveRunEvents)
+	h.mux.HandleFunc("GET /admin/tools", h.serveTools)
+	h.mux.HandleFunc("GET /admin/config", h.serveConfig)
+	return h
+}
+
+// ServeHTTP routes admin requests.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// kinds returns the run kinds in a stable order.
+func (h *Handler) kinds() []string {
+	kinds := make([]string, 0, len(h.runs))
+	for kind := range h.runs {
+		kinds = append(kinds, kind)
+	}
+	slices.Sort(kinds)
+	return kinds
+}
+
+// serveRuns lists the runs in flight with their step and executing tools.
+func (h *Handler) serveRuns(w http.ResponseWriter, r *http.Request) {
+	runs := []RunInfo{}
+	for _, kind := range h.kinds() {
+		for _, info := range h.runs[kind].Active() {
+			info.Kind = kind
+			runs = append(runs, info)
+		}
+	}
+	writeJSON(w, map[string]any{"runs": runs})
+}
+
+// serveRunEvents shows a run, in flight or recently finished, with its
+// recent events.
+func (h *Handler) serveRunEvents(w http.ResponseWriter, r *http.Request) {
+	runID := r.PathValue("runId")
+	for _, kind := range h.kinds() {
+		status, ok := h.runs[kind].Status(runID)
+		if !ok {
+			continue
+		}
+		info := status.Info()
+		info.Kind = kind
+		writeJSON(w, map[string]any{"run": info, "events": status.Events()})
+		return
+	}
+	slog.Warn("run not found", "run_id", runID)
+	http.Error(w, "run not found: "+runID, http.StatusNotFound)
+}
+
+// serveTools lists the registered tools with their parameter schemas.
+func (h *Handler) serveTools(w http.ResponseWriter, r *http.Request) {
+	type toolInfo struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description"`
+		Parameters  json.RawMessage `json:"parameters,omitempty"`
+		Client      bool            `json:"client,omitempty"`
+	}
+	tools := []toolInfo{}
+	for _, t := range h.registry.Tools() {
+		tools = append(tools, toolInfo{
+			Name:        t.Name,
+			Description: t.Description,
+			Parameters:  t.Parameters,
+			Client:      h.registry.IsClientTool(t.Name),
+		})
+	}
+	slices.SortFunc(tools, func(a, b toolInfo) int { return strings.Compare(a.Name, b.Name) })
+	writeJSON(w, map[string]any{"tools": tools})
+}
+
+// serveConfig writes the configuration.
+func (h *Handler) serveConfig(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, h.config)
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,124 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statuses is a Runs holding fixed statuses, in flight unless ended.
+type statuses []*Status
+
+func (s statuses) Active() []RunInfo {
+	var infos []RunInfo
+	for _, status := range s {
+		if info := status.Info(); info.EndedAt.IsZero() {
+			infos = append(infos, info)
+		}
+	}
+	return infos
+}
+
+func (s statuses) Status(id string) (*Status, bool) {
+	for _, status := range s {
+		if status.ID() == id {
+			return status, true
+		}
+	}
+	return nil, false
+}
+
+func get(t *testing.T, h http.Handler, path string, v any) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	if rec.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), v), rec.Body.String())
+	}
+	return rec.Code
+}
+
+func TestHandler(t *testing.T) {
+	running := NewStatus("run-1", "thread-1")
+	running.Record(event.Event{Type: event.StepStart, Step: 2})
+	running.Record(event.Event{Type: event.MessageDelta, Delta: "Hi"})
+	running.Record(event.Event{Type: event.ToolCallExecuting, ToolCall: &ai.ToolCall{ID: "c1", Name: "search"}})
+
+	failed := NewStatus("task-1", "ctx-1")
+	failed.Add(RunEvent{Type: "status-update", Message: "working"})
+	failed.Add(RunEvent{Type: "status-update", Message: "failed", Error: "boom"})
+	failed.SetOutcome(OutcomeFailed)
+	failed.End()
+
+	agentErr := NewStatus("run-2", "")
+	agentErr.Record(event.Event{Type: event.RunError, Error: errors.New("overloaded")})
+
+	registry := tool.NewRegistry()
+	registry.MustRegister(ai.Tool{Name: "search", Description: "Search the web"}, func(ctx context.Context, call ai.ToolCall) (string, error) {
+		return "", nil
+	})
+	h := NewHandler(map[string]Runs{
+		"agent": statuses{running, agentErr},
+		"a2a":   statuses{failed},
+	}, registry, map[string]any{"port": "8000", "openaiKey": Redact("sk-secret"), "googleKey": Redact("")})
+
+	var runs struct{ Runs []RunInfo }
+	require.Equal(t, http.StatusOK, get(t, h, "/admin/runs", &runs))
+	require.Len(t, runs.Runs, 2)
+	assert.Equal(t, "agent", runs.Runs[0].Kind)
+	assert.Equal(t, "run-1", runs.Runs[0].ID)
+	assert.Equal(t, OutcomeRunning, runs.Runs[0].Status)
+	assert.Equal(t, 2, runs.Runs[0].Step)
+	assert.Equal(t, []string{"search"}, runs.Runs[0].Tools)
+	assert.Equal(t, 3, runs.Runs[0].Events)
+	assert.Equal(t, OutcomeFailed, runs.Runs[1].Status)
+	assert.Equal(t, "overloaded", runs.Runs[1].Error)
+
+	var detail struct {
+		Run    RunInfo
+		Events []RunEvent
+	}
+	require.Equal(t, http.StatusOK, get(t, h, "/admin/runs/task-1/events", &detail))
+	assert.Equal(t, "a2a", detail.Run.Kind)
+	assert.Equal(t, OutcomeFailed, detail.Run.Status)
+	assert.Equal(t, "boom", detail.Run.Error)
+	assert.False(t, detail.Run.EndedAt.IsZero())
+	require.Len(t, detail.Events, 2)
+	assert.Equal(t, "failed", detail.Events[1].Message)
+
+	require.Equal(t, http.StatusOK, get(t, h, "/admin/runs/run-1/events", &detail))
+	require.Len(t, detail.Events, 2, "deltas are counted but not kept")
+	assert.Equal(t, "search", detail.Events[1].Tool)
+
+	assert.Equal(t, http.StatusNotFound, get(t, h, "/admin/runs/missing/events", &detail))
+
+	var tools struct{ Tools []struct{ Name string } }
+	require.Equal(t, http.StatusOK, get(t, h, "/admin/tools", &tools))
+	require.Len(t, tools.Tools, 1)
+	assert.Equal(t, "search", tools.Tools[0].Name)
+
+	var config map[string]any
+	require.Equal(t, http.StatusOK, get(t, h, "/admin/config", &config))
+	assert.Equal(t, Redacted, config["openaiKey"])
+	assert.Equal(t, "", config["googleKey"])
+}
+
+func TestStatus_KeepsRecentEvents(t *testing.T) {
+	s := NewStatus("run", "")
+	for i := range MaxEvents + 5 {
+		s.Record(event.Event{Type: event.StepStart, Step: i + 1})
+	}
+	events := s.Events()
+	require.Len(t, events, MaxEvents)
+	assert.Equal(t, 6, events[0].Step)
+	assert.Equal(t, MaxEvents+5, s.Info().Events)
+}
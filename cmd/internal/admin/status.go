@@ -0,0 +1,201 @@
+package admin
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/spetersoncode/gains/event"
+)
+
+// MaxEvents is how many recent events are kept per run.
+const MaxEvents = 100
+
+// Run outcomes shown by the admin endpoints.
+const (
+	OutcomeRunning   = "running"
+	OutcomeCompleted = "completed"
+	OutcomeFailed    = "failed"
+	OutcomeCanceled  = "canceled"
+	OutcomeEnded     = "ended" // ended without a run end event, e.g. disconnected
+)
+
+// RunInfo is the status of a run as the admin endpoints show it.
+type RunInfo struct {
+	Kind      string    `json:"kind"`
+	ID        string    `json:"id"`
+	ThreadID  string    `json:"threadId,omitempty"`
+	Status    string    `json:"status"`
+	StartedAt time.Time `json:"startedAt"`
+	EndedAt   time.Time `json:"endedAt,omitzero"`
+	Step      int       `json:"step,omitempty"`
+	StepName  string    `json:"stepName,omitempty"`
+	Tools     []string  `json:"tools,omitempty"` // tools executing now
+	Events    int       `json:"events"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// RunEvent is a recorded event, without streamed content such as message
+// deltas and state, which are left to the run's own stream.
+type RunEvent struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp,omitzero"`
+	Step      int       `json:"step,omitempty"`
+	StepName  string    `json:"stepName,omitempty"`
+	Tool      string    `json:"tool,omitempty"`
+	ToolID    string    `json:"toolCallId,omitempty"`
+	Attempt   int       `json:"attempt,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Status follows a run through its events. It is safe for concurrent use.
+type Status struct {
+	id       string
+	threadID string
+	started  time.Time
+
+	mu       sync.Mutex
+	ended    time.Time
+	outcome  string
+	err      string
+	step     int
+	stepName string
+	tools    map[string]string // executing tool names by call ID
+	events   []RunEvent        // the latest MaxEvents, oldest first
+	count    int
+}
+
+// NewStatus creates the status of run id of thread threadID, starting now.
+func NewStatus(id, threadID string) *Status {
+	return &Status{
+		id:       id,
+		threadID: threadID,
+		started:  time.Now(),
+		outcome:  OutcomeRunning,
+		tools:    make(map[string]string),
+	}
+}
+
+// ID returns the run ID.
+func (s *Status) ID() string {
+	return s.id
+}
+
+// Record updates the status with the gains event e. It is an event.Sink.
+func (s *Status) Record(e event.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.count++
+	switch e.Type {
+	case event.MessageDelta, event.ToolCallArgs, event.StateDelta, event.ActivityDelta:
+		return // too many to keep, and in the run's stream anyway
+	case event.StepStart:
+		s.step, s.stepName = e.Step, e.StepName
+	case event.ToolCallExecuting:
+		if e.ToolCall != nil {
+			s.tools[e.ToolCall.ID] = e.ToolCall.Name
+		}
+	case event.ToolCallResult:
+		if e.ToolResult != nil {
+			delete(s.tools, e.ToolResult.ToolCallID)
+		}
+	case event.RunEnd:
+		if s.outcome == OutcomeRunning {
+			s.outcome = OutcomeCompleted
+		}
+	case event.RunError:
+		if s.outcome == OutcomeRunning {
+			s.outcome = OutcomeFailed
+		}
+		if e.Error != nil {
+			s.err = e.Error.Error()
+		}
+	}
+
+	rec := RunEvent{
+		Type:      string(e.Type),
+		Timestamp: e.Timestamp,
+		Step:      e.Step,
+		StepName:  e.StepName,
+		Attempt:   e.Attempt,
+		Message:   e.Message,
+	}
+	if e.ToolCall != nil {
+		rec.Tool, rec.ToolID = e.ToolCall.Name, e.ToolCall.ID
+	}
+	if e.ToolResult != nil {
+		rec.ToolID = e.ToolResult.ToolCallID
+	}
+	if e.Error != nil {
+		rec.Error = e.Error.Error()
+	}
+	s.appendLocked(rec)
+}
+
+// Add records an event that is not a gains event, such as an A2A task
+// update. Set its outcome with SetOutcome.
+func (s *Status) Add(e RunEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count++
+	if e.Error != "" {
+		s.err = e.Error
+	}
+	s.appendLocked(e)
+}
+
+func (s *Status) appendLocked(e RunEvent) {
+	if len(s.events) == MaxEvents {
+		s.events = slices.Delete(s.events, 0, 1)
+	}
+	s.events = append(s.events, e)
+}
+
+// SetOutcome sets the outcome of a run still running.
+func (s *Status) SetOutcome(outcome string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.outcome == OutcomeRunning {
+		s.outcome = outcome
+	}
+}
+
+// End marks the run finished, with OutcomeEnded if it has no outcome yet.
+func (s *Status) End() {
+	s.SetOutcome(OutcomeEnded)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ended = time.Now()
+	clear(s.tools)
+}
+
+// Info returns a snapshot of the status.
+func (s *Status) Info() RunInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info := RunInfo{
+		ID:        s.id,
+		ThreadID:  s.threadID,
+		Status:    s.outcome,
+		StartedAt: s.started,
+		EndedAt:   s.ended,
+		Step:      s.step,
+		StepName:  s.stepName,
+		Events:    s.count,
+		Error:     s.err,
+	}
+	for _, name := range s.tools {
+		info.Tools = append(info.Tools, name)
+	}
+	slices.Sort(info.Tools)
+	return info
+}
+
+// Events returns a copy of the recorded events, oldest first.
+func (s *Status) Events() []RunEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.events)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"github.com/spetersoncode/gains/cmd/internal/admin"
+	"github.com/spetersoncode/gains/tool"
+)
+
+// maxFinishedRuns is how many finished runs per tracker stay inspectable.
+const maxFinishedRuns = 20
+
+// NewAdminHandler creates the admin handler over the run trackers, keyed by
+// the kind of run they track, the tool registry, and the configuration. It
+// is mounted only if AGUI_ADMIN is set.
+func NewAdminHandler(runs map[string]*runTracker, registry *tool.Registry, cfg *Config) *admin.Handler {
+	sources := make(map[string]admin.Runs, len(runs))
+	for kind, tracker := range runs {
+		sources[kind] = tracker
+	}
+	return admin.NewHandler(sources, registry, map[string]any{
+		"port":           cfg.Port,
+		"logLevel":       cfg.LogLevel,
+		"logFormat":      cfg.LogFormat,
+		"provider":       cfg.Provider,
+		"model":          cfg.Model,
+		"anthropicKey":   admin.Redact(cfg.AnthropicKey),
+		"openaiKey":      admin.Redact(cfg.OpenAIKey),
+		"googleKey":      admin.Redact(cfg.GoogleKey),
+		"vertexProject":  cfg.VertexProject,
+		"vertexLocation": cfg.VertexLocation,
+		"maxSteps":       cfg.MaxSteps,
+		"timeout":        cfg.Timeout.String(),
+		"demoTools":      cfg.EnableDemoTools,
+		"admin":          cfg.EnableAdmin,
+	})
+}
@@ -12,8 +12,9 @@ import (
 // Config holds the server configuration loaded from environment variables.
 type Config struct {
 	// Server
-	Port     string
-	LogLevel string // debug, info, warn, error
+	Port      string
+	LogLevel  string // debug, info, warn, error
+	LogFormat string // text, json
 
 	// Provider selection
 	Provider string
@@ -32,6 +33,9 @@ type Config struct {
 	MaxSteps        int
 	Timeout         time.Duration
 	EnableDemoTools bool
+
+	// Operations
+	EnableAdmin bool
 }
 
 // LoadConfig loads configuration from environment variables.
@@ -42,6 +46,7 @@ func LoadConfig() (*Config, error) {
 	cfg := &Config{
 		Port:            getEnvOrDefault("AGUI_PORT", "8000"),
 		LogLevel:        getEnvOrDefault("AGUI_LOG_LEVEL", "info"),
+		LogFormat:       getEnvOrDefault("AGUI_LOG_FORMAT", "text"),
 		Provider:        os.Getenv("GAINS_PROVIDER"),
 		Model:           os.Getenv("GAINS_MODEL"),
 		AnthropicKey:    os.Getenv("ANTHROPIC_API_KEY"),
@@ -52,6 +57,7 @@ func LoadConfig() (*Config, error) {
 		MaxSteps:        getEnvIntOrDefault("GAINS_MAX_STEPS", 10),
 		Timeout:         getEnvDurationOrDefault("GAINS_TIMEOUT", 2*time.Minute),
 		EnableDemoTools: getEnvBoolOrDefault("GAINS_DEMO_TOOLS", true),
+		EnableAdmin:     getEnvBoolOrDefault("AGUI_ADMIN", false),
 	}
 
 	if err := cfg.Validate(); err != nil {
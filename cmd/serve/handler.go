@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -13,20 +15,41 @@ import (
 	"github.com/spetersoncode/gains/agent"
 	"github.com/spetersoncode/gains/agui"
 	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/session"
+	"github.com/spetersoncode/gains/sse"
 	"github.com/spetersoncode/gains/tool"
 	"github.com/spetersoncode/gains/workflow"
 )
 
+// sseHeartbeat is how often idle SSE streams get a keepalive comment.
+const sseHeartbeat = 15 * time.Second
+
+// eventTrace receives every gains event streamed by the handlers. It is set
+// in debug mode to write events to stdout as JSON Lines.
+var eventTrace event.Sink
+
+// traceEvents passes events through eventTrace when it is set.
+func traceEvents(ch <-chan event.Event) <-chan event.Event {
+	if eventTrace == nil {
+		return ch
+	}
+	return event.Tee(ch, eventTrace)
+}
+
 // AgentHandler handles AG-UI agent requests over SSE.
 type AgentHandler struct {
 	agent    *agent.Agent
 	registry *tool.Registry
+	runs     *runTracker
+	threads  *session.Manager
 	config   *Config
 }
 
 // NewAgentHandler creates a new handler for the given agent and registry.
-func NewAgentHandler(a *agent.Agent, r *tool.Registry, cfg *Config) *AgentHandler {
-	return &AgentHandler{agent: a, registry: r, config: cfg}
+// Its runs are tracked in runs so they can be canceled with a CancelHandler,
+// and hold their thread's lock in threads so a thread runs one at a time.
+func NewAgentHandler(a *agent.Agent, r *tool.Registry, runs *runTracker, threads *session.Manager, cfg *Config) *AgentHandler {
+	return &AgentHandler{agent: a, registry: r, runs: runs, threads: threads, config: cfg}
 }
 
 // ServeHTTP handles POST requests to run the agent and stream events via SSE.
@@ -62,73 +85,105 @@ func (h *AgentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Register frontend tools if provided
-	if len(prepared.Tools) > 0 {
-		gainsTools := prepared.GainsTools()
-		if err := h.registry.RegisterClientTools(gainsTools); err != nil {
-			log.Warn("failed to register frontend tools", "error", err)
-		} else {
-			log.Info("registered frontend tools", "count", len(prepared.ToolNames), "names", prepared.ToolNames)
+	// Reject a second run on a thread that is still running
+	if prepared.ThreadID != "" {
+		unlock, err := h.threads.TryLock(r.Context(), prepared.ThreadID)
+		if errors.Is(err, session.ErrBusy) {
+			log.Warn("thread busy")
+			http.Error(w, "thread already has a run in progress: "+prepared.ThreadID, http.StatusConflict)
+			return
 		}
+		if err != nil {
+			log.Error("failed to lock thread", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer unlock()
 	}
 
-	// Ensure cleanup of client tools after request
-	defer func() {
-		for _, name := range prepared.ToolNames {
-			h.registry.Unregister(name)
-		}
-		if len(prepared.ToolNames) > 0 {
-			log.Debug("unregistered frontend tools", "count", len(prepared.ToolNames))
-		}
-	}()
+	// Offer frontend tools to this request's run only
+	if len(prepared.Tools) > 0 {
+		log.Info("offering frontend tools", "count", len(prepared.ToolNames), "names", prepared.ToolNames)
+	}
 
 	log.Info("request started", "message_count", len(prepared.Messages))
 
-	// Set SSE headers
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("Cache-Control", "no-cache")
-	w.Header().Set("Connection", "keep-alive")
+	// Create mapper for this run (with initial state for STATE_SNAPSHOT emission)
+	mapper := agui.NewMapper(prepared.ThreadID, prepared.RunID,
+		agui.WithInitialState(prepared.State),
+	)
 
-	// Get flusher for streaming
-	flusher, ok := w.(http.Flusher)
+	// Track the run so the cancel endpoint can stop it
+	ctx, cancel := context.WithCancelCause(r.Context())
+	defer cancel(nil)
+	release, ok := h.runs.track(prepared.RunID, prepared.ThreadID, cancel)
 	if !ok {
+		log.Warn("run already in progress")
+		http.Error(w, "run already in progress: "+prepared.RunID, http.StatusConflict)
+		return
+	}
+	defer release()
+
+	sw, err := sse.NewWriter(r.Context(), w, sse.WithHeartbeat(sseHeartbeat))
+	if err != nil {
 		log.Error("streaming not supported")
 		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
 		return
 	}
-
-	// Create mapper for this run (with initial state for STATE_SNAPSHOT emission)
-	mapper := agui.NewMapper(prepared.ThreadID, prepared.RunID,
-		agui.WithInitialState(prepared.State),
-	)
+	defer sw.Close()
 
 	// Set up shared state in context for state tools
 	sharedState := event.NewSharedState(prepared.State)
-	ctx := event.WithSharedState(r.Context(), sharedState)
+	ctx = event.WithSharedState(ctx, sharedState)
 
 	// Run agent with streaming
-	gainsEvents := h.agent.RunStream(ctx, prepared.Messages,
+	gainsEvents := traceEvents(h.runs.observe(prepared.RunID, h.agent.RunStream(ctx, prepared.Messages,
 		agent.WithMaxSteps(h.config.MaxSteps),
 		agent.WithTimeout(h.config.Timeout),
-	)
+		agui.FrontendTools(prepared.Tools),
+	)))
 
 	// Stream events as SSE using the mapper's filtered stream
 	var eventCount int
 	var lastError error
 	for aguiEvent := range mapper.MapStream(gainsEvents) {
+		// A canceled run ends with the cancellation events below instead
+		if canceled(ctx) && isTerminal(aguiEvent) {
+			continue
+		}
+
 		eventCount++
 		log.Debug("sending SSE event",
 			"event_type", aguiEvent.Type(),
 			"event_num", eventCount,
 		)
 
-		if err := writeSSE(w, flusher, aguiEvent); err != nil {
+		if err := writeSSE(sw, aguiEvent); err != nil {
 			log.Error("failed to write SSE event", "error", err, "event_type", aguiEvent.Type())
 			lastError = err
 			return
 		}
 	}
 
+	// Flush the partial state and report the cancellation
+	if canceled(ctx) {
+		for _, aguiEvent := range []aguievents.Event{
+			mapper.StateSnapshot(sharedState.Get()),
+			mapper.RunError(errRunCanceled),
+		} {
+			eventCount++
+			if err := writeSSE(sw, aguiEvent); err != nil {
+				log.Error("failed to write SSE event", "error", err, "event_type", aguiEvent.Type())
+				return
+			}
+		}
+		log.Info("request canceled",
+			"duration_ms", time.Since(start).Milliseconds(),
+			"events_sent", eventCount,
+		)
+		return
+	}
+
 	duration := time.Since(start)
 	if lastError != nil {
 		log.Error("request failed",
@@ -144,27 +199,25 @@ func (h *AgentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// isTerminal reports whether ev ends an AG-UI run.
+func isTerminal(ev aguievents.Event) bool {
+	return ev.Type() == aguievents.EventTypeRunFinished || ev.Type() == aguievents.EventTypeRunError
+}
+
 // writeSSE writes an AG-UI event in SSE format.
-func writeSSE(w http.ResponseWriter, flusher http.Flusher, ev aguievents.Event) error {
+func writeSSE(sw *sse.Writer, ev aguievents.Event) error {
 	data, err := ev.ToJSON()
 	if err != nil {
 		return fmt.Errorf("failed to serialize event: %w", err)
 	}
-
-	// Write SSE format: event: TYPE\ndata: {json}\n\n
-	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type(), string(data)); err != nil {
-		return fmt.Errorf("failed to write event: %w", err)
-	}
-
-	flusher.Flush()
-	return nil
+	return sw.WriteEvent(string(ev.Type()), data)
 }
 
 // corsMiddleware adds CORS headers for cross-origin frontend requests.
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 		if r.Method == http.MethodOptions {
@@ -185,18 +238,26 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 // WorkflowHandler handles AG-UI workflow requests over SSE.
 type WorkflowHandler struct {
 	registry *workflow.Registry
+	runs     *runTracker
 	config   *Config
 }
 
 // NewWorkflowHandler creates a new handler for the given workflow registry.
-func NewWorkflowHandler(r *workflow.Registry, cfg *Config) *WorkflowHandler {
-	return &WorkflowHandler{registry: r, config: cfg}
+// Its runs are tracked in runs.
+func NewWorkflowHandler(r *workflow.Registry, runs *runTracker, cfg *Config) *WorkflowHandler {
+	return &WorkflowHandler{registry: r, runs: runs, config: cfg}
 }
 
-// ServeHTTP handles POST requests to run a workflow and stream events via SSE.
+// ServeHTTP handles POST requests to run a workflow and stream events via SSE,
+// and GET requests listing the registered workflows and their input schemas.
 func (h *WorkflowHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
 
+	if r.Method == http.MethodGet {
+		h.serveSchemas(w)
+		return
+	}
+
 	// Only accept POST
 	if r.Method != http.MethodPost {
 		slog.Warn("method not allowed", "method", r.Method, "path", r.URL.Path)
@@ -236,18 +297,23 @@ func (h *WorkflowHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	log.Info("workflow request started")
 
-	// Set SSE headers
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("Cache-Control", "no-cache")
-	w.Header().Set("Connection", "keep-alive")
-
-	// Get flusher for streaming
-	flusher, ok := w.(http.Flusher)
+	ctx, cancel := context.WithCancelCause(r.Context())
+	defer cancel(nil)
+	release, ok := h.runs.track(prepared.RunID, prepared.ThreadID, cancel)
 	if !ok {
+		log.Warn("run already in progress")
+		http.Error(w, "run already in progress: "+prepared.RunID, http.StatusConflict)
+		return
+	}
+	defer release()
+
+	sw, err := sse.NewWriter(r.Context(), w, sse.WithHeartbeat(sseHeartbeat))
+	if err != nil {
 		log.Error("streaming not supported")
 		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
 		return
 	}
+	defer sw.Close()
 
 	// Create mapper for this run
 	mapper := agui.NewMapper(prepared.ThreadID, prepared.RunID,
@@ -255,8 +321,7 @@ func (h *WorkflowHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	)
 
 	// Run workflow with streaming
-	ctx := r.Context()
-	gainsEvents := h.registry.RunStream(ctx, prepared.WorkflowName, prepared.State)
+	gainsEvents := traceEvents(h.runs.observe(prepared.RunID, h.registry.RunStream(ctx, prepared.WorkflowName, prepared.State)))
 
 	// Stream events as SSE using the mapper's filtered stream
 	var eventCount int
@@ -268,7 +333,7 @@ func (h *WorkflowHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			"event_num", eventCount,
 		)
 
-		if err := writeSSE(w, flusher, aguiEvent); err != nil {
+		if err := writeSSE(sw, aguiEvent); err != nil {
 			log.Error("failed to write SSE event", "error", err, "event_type", aguiEvent.Type())
 			lastError = err
 			return
@@ -290,16 +355,27 @@ func (h *WorkflowHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// serveSchemas writes the registered workflows and the JSON schemas of
+// their input state, so frontends can render forms for launching them.
+func (h *WorkflowHandler) serveSchemas(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"workflows": agui.WorkflowSchemas(h.registry),
+	})
+}
+
 // A2AHandler handles A2A protocol requests over SSE.
-// Supports the tasks/send and tasks/sendSubscribe methods.
+// Supports the tasks/send, tasks/sendSubscribe, and tasks/cancel methods.
 type A2AHandler struct {
 	executor a2a.Executor
+	tasks    *runTracker
 	config   *Config
 }
 
 // NewA2AHandler creates a new handler for A2A requests.
-func NewA2AHandler(executor a2a.Executor, cfg *Config) *A2AHandler {
-	return &A2AHandler{executor: executor, config: cfg}
+// Its streaming tasks are tracked in tasks so tasks/cancel can stop them.
+func NewA2AHandler(executor a2a.Executor, tasks *runTracker, cfg *Config) *A2AHandler {
+	return &A2AHandler{executor: executor, tasks: tasks, config: cfg}
 }
 
 // jsonRPCRequest represents a JSON-RPC 2.0 request.
@@ -332,8 +408,14 @@ const (
 	jsonRPCMethodNotFound = -32601
 	jsonRPCInvalidParams  = -32602
 	jsonRPCInternalError  = -32603
+	a2aTaskNotFound       = -32001
 )
 
+// taskIDParams represents the params of a tasks/cancel request.
+type taskIDParams struct {
+	ID string `json:"id"`
+}
+
 // ServeHTTP handles A2A protocol requests.
 func (h *A2AHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
@@ -368,6 +450,8 @@ func (h *A2AHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handleSend(w, r, req, log)
 	case "tasks/sendSubscribe":
 		h.handleSendSubscribe(w, r, req, log, start)
+	case "tasks/cancel":
+		h.handleCancel(w, req, log)
 	default:
 		log.Warn("unknown method")
 		h.writeError(w, req.ID, jsonRPCMethodNotFound, "Method not found: "+req.Method)
@@ -407,25 +491,37 @@ func (h *A2AHandler) handleSendSubscribe(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	// Set SSE headers
-	w.Header().Set("Content-Type", "text/event-stream")
-	w.Header().Set("Cache-Control", "no-cache")
-	w.Header().Set("Connection", "keep-alive")
-
-	// Get flusher for streaming
-	flusher, ok := w.(http.Flusher)
-	if !ok {
+	sw, err := sse.NewWriter(r.Context(), w, sse.WithHeartbeat(sseHeartbeat))
+	if err != nil {
 		log.Error("streaming not supported")
 		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
 		return
 	}
+	defer sw.Close()
 
-	// Execute with streaming
-	events := h.executor.ExecuteStream(r.Context(), params)
+	// Execute with streaming, tracking the task once its ID is known so
+	// tasks/cancel can stop it
+	ctx, cancel := context.WithCancelCause(r.Context())
+	defer cancel(nil)
+	events := h.executor.ExecuteStream(ctx, params)
 
 	// Stream events as SSE
 	var eventCount int
+	var taskID, contextID string
 	for evt := range events {
+		if taskID == "" {
+			taskID, contextID = taskOf(evt)
+			if release, ok := h.tasks.track(taskID, contextID, cancel); ok {
+				defer release()
+			}
+		}
+
+		// A canceled task ends with the canceled status below instead
+		if canceled(ctx) {
+			if update, ok := evt.(a2a.TaskStatusUpdateEvent); ok && update.Final {
+				continue
+			}
+		}
 		eventCount++
 
 		data, err := json.Marshal(evt)
@@ -445,16 +541,22 @@ func (h *A2AHandler) handleSendSubscribe(w http.ResponseWriter, r *http.Request,
 			eventType = "message"
 		}
 
-		// Write SSE format
-		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", eventType, string(data)); err != nil {
+		if err := sw.WriteEvent(eventType, data); err != nil {
 			log.Error("failed to write SSE event", "error", err)
 			return
 		}
-
-		flusher.Flush()
 		log.Debug("sent A2A event", "event_type", eventType, "event_num", eventCount)
 	}
 
+	if canceled(ctx) {
+		evt := a2a.NewTaskStatusUpdateEvent(taskID, contextID, a2a.NewTaskStatus(a2a.TaskStateCanceled), true)
+		data, err := json.Marshal(evt)
+		if err == nil {
+			sw.WriteEvent("status-update", data)
+		}
+		log.Info("A2A task canceled", "task_id", taskID)
+	}
+
 	duration := time.Since(start)
 	log.Info("A2A streaming request completed",
 		"duration_ms", duration.Milliseconds(),
@@ -462,6 +564,38 @@ func (h *A2AHandler) handleSendSubscribe(w http.ResponseWriter, r *http.Request,
 	)
 }
 
+// handleCancel cancels a streaming task and returns it in the canceled state.
+func (h *A2AHandler) handleCancel(w http.ResponseWriter, req jsonRPCRequest, log *slog.Logger) {
+	var params taskIDParams
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.ID == "" {
+		log.Warn("invalid params", "error", err)
+		h.writeError(w, req.ID, jsonRPCInvalidParams, "Invalid params: task id required")
+		return
+	}
+
+	contextID, ok := h.tasks.cancel(params.ID)
+	if !ok {
+		h.writeError(w, req.ID, a2aTaskNotFound, "Task not found: "+params.ID)
+		return
+	}
+
+	task := a2a.NewTask(params.ID, contextID)
+	task.Status = a2a.NewTaskStatus(a2a.TaskStateCanceled)
+	h.writeResult(w, req.ID, task)
+	log.Info("A2A task canceled", "task_id", params.ID)
+}
+
+// taskOf returns the task and context IDs of an A2A event.
+func taskOf(evt a2a.Event) (taskID, contextID string) {
+	switch e := evt.(type) {
+	case a2a.TaskStatusUpdateEvent:
+		return e.TaskID, e.ContextID
+	case a2a.TaskArtifactUpdateEvent:
+		return e.TaskID, e.ContextID
+	}
+	return "", ""
+}
+
 // writeResult writes a successful JSON-RPC response.
 func (h *A2AHandler) writeResult(w http.ResponseWriter, id any, result any) {
 	resp := jsonRPCResponse{
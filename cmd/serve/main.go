@@ -8,11 +8,13 @@
 //
 //	AGUI_PORT         - Server port (default: 8000)
 //	AGUI_LOG_LEVEL    - Log level: debug, info, warn, error (default: info)
+//	AGUI_LOG_FORMAT   - Log format: text or json (default: text)
 //	GAINS_PROVIDER    - Provider: anthropic, openai, google, or vertex (required)
 //	GAINS_MODEL       - Model override (optional, uses provider default)
 //	GAINS_MAX_STEPS   - Max agent iterations (default: 10)
 //	GAINS_TIMEOUT     - Agent timeout (default: 2m)
 //	GAINS_DEMO_TOOLS  - Enable demo tools (default: true)
+//	AGUI_ADMIN        - Enable the read-only /admin endpoints (default: false)
 //	ANTHROPIC_API_KEY - Anthropic API key
 //	OPENAI_API_KEY    - OpenAI API key
 //	GOOGLE_API_KEY    - Google API key
@@ -27,6 +29,22 @@
 // Debug logging (shows all events):
 //
 //	AGUI_LOG_LEVEL=debug GAINS_PROVIDER=anthropic go run ./cmd/serve
+//
+// In debug mode every gains event is also written to stdout as a JSON line.
+// With JSON logs, the whole output can be filtered with jq:
+//
+//	AGUI_LOG_LEVEL=debug AGUI_LOG_FORMAT=json GAINS_PROVIDER=anthropic go run ./cmd/serve \
+//	    | jq 'select(.type == "tool_call_result")'
+//
+// With AGUI_ADMIN=true, read-only endpoints show what the server is doing
+// without digging through logs. Anything that can reach them can read the
+// configuration (API keys redacted) and run details, so expose them only on
+// trusted networks:
+//
+//	GET /admin/runs                 - runs in flight, with their step and executing tools
+//	GET /admin/runs/{runId}/events  - a run's recent events, in flight or recently finished
+//	GET /admin/tools                - the registered tools and their parameter schemas
+//	GET /admin/config               - the configuration, API keys redacted
 package main
 
 import (
@@ -44,7 +62,10 @@ import (
 	"github.com/spetersoncode/gains/a2a"
 	"github.com/spetersoncode/gains/agent"
 	"github.com/spetersoncode/gains/client"
+	"github.com/spetersoncode/gains/event"
 	"github.com/spetersoncode/gains/model"
+	"github.com/spetersoncode/gains/runtime"
+	"github.com/spetersoncode/gains/session"
 	"github.com/spetersoncode/gains/tool"
 )
 
@@ -57,8 +78,11 @@ func main() {
 	}
 
 	// Setup structured logger
-	logger := setupLogger(cfg.LogLevel)
+	logger := setupLogger(cfg.LogLevel, cfg.LogFormat)
 	slog.SetDefault(logger)
+	if logger.Enabled(context.Background(), slog.LevelDebug) {
+		eventTrace = event.JSONLinesSink(os.Stdout)
+	}
 
 	// Create gains client
 	gainsClient, err := createClient(cfg)
@@ -87,22 +111,34 @@ func main() {
 	slog.Info("registered demo workflows", "count", workflowRegistry.Len(), "names", workflowRegistry.Names())
 
 	// Create HTTP handlers
-	handler := NewAgentHandler(a, registry, cfg)
-	workflowHandler := NewWorkflowHandler(workflowRegistry, cfg)
+	agentRuns := newRunTracker()
+	workflowRuns := newRunTracker()
+	a2aTasks := newRunTracker()
+	threads := session.NewManager(nil)
+	handler := NewAgentHandler(a, registry, agentRuns, threads, cfg)
+	workflowHandler := NewWorkflowHandler(workflowRegistry, workflowRuns, cfg)
 
 	// Create A2A executor and handler
 	a2aExecutor := a2a.NewAgentExecutor(a,
 		agent.WithMaxSteps(cfg.MaxSteps),
 		agent.WithTimeout(cfg.Timeout),
 	)
-	a2aHandler := NewA2AHandler(a2aExecutor, cfg)
+	a2aHandler := NewA2AHandler(a2aExecutor, a2aTasks, cfg)
 
 	// Setup routes
 	mux := http.NewServeMux()
-	mux.Handle("/api/agent", corsMiddleware(handler))
-	mux.Handle("/api/workflow", corsMiddleware(workflowHandler))
-	mux.Handle("/api/a2a", corsMiddleware(a2aHandler))
+	mux.Handle("/api/agent", corsMiddleware(runtime.Handler(handler)))
+	mux.Handle("/api/agent/{runId}/cancel", corsMiddleware(NewCancelHandler(agentRuns)))
+	mux.Handle("/api/workflow", corsMiddleware(runtime.Handler(workflowHandler)))
+	mux.Handle("/api/a2a", corsMiddleware(runtime.Handler(a2aHandler)))
 	mux.HandleFunc("/health", healthHandler)
+	if cfg.EnableAdmin {
+		mux.Handle("/admin/", NewAdminHandler(map[string]*runTracker{
+			"agent":    agentRuns,
+			"workflow": workflowRuns,
+			"a2a":      a2aTasks,
+		}, registry, cfg))
+	}
 
 	// Create server
 	server := &http.Server{
@@ -123,6 +159,10 @@ func main() {
 		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 
+		// Let in-flight runs finish before closing connections
+		if err := runtime.Drain(ctx); err != nil {
+			slog.Warn("drain incomplete", "error", err)
+		}
 		if err := server.Shutdown(ctx); err != nil {
 			slog.Error("shutdown error", "error", err)
 		}
@@ -134,10 +174,15 @@ func main() {
 		"provider", cfg.Provider,
 		"log_level", cfg.LogLevel,
 		"agent_endpoint", fmt.Sprintf("POST http://localhost:%s/api/agent", cfg.Port),
+		"cancel_endpoint", fmt.Sprintf("POST http://localhost:%s/api/agent/{runId}/cancel", cfg.Port),
 		"workflow_endpoint", fmt.Sprintf("POST http://localhost:%s/api/workflow", cfg.Port),
+		"workflow_schemas", fmt.Sprintf("GET http://localhost:%s/api/workflow", cfg.Port),
 		"a2a_endpoint", fmt.Sprintf("POST http://localhost:%s/api/a2a", cfg.Port),
 		"health", fmt.Sprintf("GET http://localhost:%s/health", cfg.Port),
 	)
+	if cfg.EnableAdmin {
+		slog.Warn("admin endpoints enabled", "admin", fmt.Sprintf("GET http://localhost:%s/admin/runs", cfg.Port))
+	}
 
 	if err := server.ListenAndServe(); err != http.ErrServerClosed {
 		slog.Error("server error", "error", err)
@@ -184,8 +229,9 @@ func createClient(cfg *Config) (*client.Client, error) {
 	}), nil
 }
 
-// setupLogger creates a structured logger with the specified level.
-func setupLogger(level string) *slog.Logger {
+// setupLogger creates a structured logger with the specified level and
+// format ("json" for JSON lines, otherwise text).
+func setupLogger(level, format string) *slog.Logger {
 	var logLevel slog.Level
 	switch strings.ToLower(level) {
 	case "debug":
@@ -202,5 +248,8 @@ func setupLogger(level string) *slog.Logger {
 		Level: logLevel,
 	}
 
+	if strings.ToLower(format) == "json" {
+		return slog.New(slog.NewJSONHandler(os.Stdout, opts))
+	}
 	return slog.New(slog.NewTextHandler(os.Stdout, opts))
 }
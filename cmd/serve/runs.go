@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/spetersoncode/gains/cmd/internal/admin"
+	"github.com/spetersoncode/gains/event"
+)
+
+// errRunCanceled is the cancellation cause of runs stopped through a cancel
+// endpoint, distinguishing them from runs whose client disconnected.
+var errRunCanceled = errors.New("run canceled")
+
+// activeRun is an in-flight run that can be canceled.
+type activeRun struct {
+	cancel   context.CancelCauseFunc
+	threadID string
+	status   *admin.Status
+}
+
+// runTracker tracks in-flight runs by ID so cancel endpoints can stop them,
+// and keeps the status of the most recently finished runs for the admin
+// endpoints.
+type runTracker struct {
+	mu       sync.Mutex
+	runs     map[string]activeRun
+	finished []*admin.Status // oldest first
+}
+
+// newRunTracker creates an empty run tracker.
+func newRunTracker() *runTracker {
+	return &runTracker{runs: make(map[string]activeRun)}
+}
+
+// track registers cancel as the way to stop run id of thread threadID. It
+// returns a function that unregisters the run, and false if a run with the
+// same ID is already in flight. Runs without an ID are not tracked.
+func (t *runTracker) track(id, threadID string, cancel context.CancelCauseFunc) (release func(), ok bool) {
+	if id == "" {
+		return func() {}, true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, exists := t.runs[id]; exists {
+		return nil, false
+	}
+	status := admin.NewStatus(id, threadID)
+	t.runs[id] = activeRun{cancel: cancel, threadID: threadID, status: status}
+	return func() {
+		status.End()
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		delete(t.runs, id)
+		t.finished = append(t.finished, status)
+		if len(t.finished) > maxFinishedRuns {
+			t.finished = t.finished[len(t.finished)-maxFinishedRuns:]
+		}
+	}, true
+}
+
+// observe returns ch with its events recorded in the status of run id, or
+// ch itself if the run is not tracked.
+func (t *runTracker) observe(id string, ch <-chan event.Event) <-chan event.Event {
+	t.mu.Lock()
+	run, ok := t.runs[id]
+	t.mu.Unlock()
+	if !ok {
+		return ch
+	}
+	return event.Tee(ch, run.status.Record)
+}
+
+// Active returns the status of the in-flight runs, oldest first.
+func (t *runTracker) Active() []admin.RunInfo {
+	t.mu.Lock()
+	statuses := make([]*admin.Status, 0, len(t.runs))
+	for _, run := range t.runs {
+		statuses = append(statuses, run.status)
+	}
+	t.mu.Unlock()
+
+	infos := make([]admin.RunInfo, len(statuses))
+	for i, status := range statuses {
+		infos[i] = status.Info()
+	}
+	slices.SortFunc(infos, func(a, b admin.RunInfo) int { return a.StartedAt.Compare(b.StartedAt) })
+	return infos
+}
+
+// Status returns the status of run id, in flight or recently finished.
+func (t *runTracker) Status(id string) (*admin.Status, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if run, ok := t.runs[id]; ok {
+		return run.status, true
+	}
+	for i := len(t.finished) - 1; i >= 0; i-- {
+		if t.finished[i].ID() == id {
+			return t.finished[i], true
+		}
+	}
+	return nil, false
+}
+
+// cancel cancels run id with errRunCanceled and returns its thread ID, or
+// false if no such run is in flight.
+func (t *runTracker) cancel(id string) (threadID string, ok bool) {
+	t.mu.Lock()
+	run, ok := t.runs[id]
+	t.mu.Unlock()
+	if !ok {
+		return "", false
+	}
+	run.status.SetOutcome(admin.OutcomeCanceled)
+	run.cancel(errRunCanceled)
+	return run.threadID, true
+}
+
+// canceled reports whether ctx was canceled through a cancel endpoint.
+func canceled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errRunCanceled)
+}
+
+// CancelHandler cancels in-flight runs by the ID in the request path.
+type CancelHandler struct {
+	runs *runTracker
+}
+
+// NewCancelHandler creates a handler canceling the runs tracked by runs.
+func NewCancelHandler(runs *runTracker) *CancelHandler {
+	return &CancelHandler{runs: runs}
+}
+
+// ServeHTTP handles POST requests to cancel the run named by the runId path
+// value. The run's stream ends with a final state snapshot and a RUN_ERROR
+// event; the response only acknowledges the request.
+func (h *CancelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		slog.Warn("method not allowed", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	runID := r.PathValue("runId")
+	threadID, ok := h.runs.cancel(runID)
+	if !ok {
+		slog.Warn("run not found", "run_id", runID)
+		http.Error(w, "run not found: "+runID, http.StatusNotFound)
+		return
+	}
+	slog.Info("run canceled", "run_id", runID, "thread_id", threadID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{
+		"run_id":    runID,
+		"thread_id": threadID,
+		"status":    "canceled",
+	})
+}
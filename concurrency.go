@@ -0,0 +1,63 @@
+package gains
+
+import "context"
+
+// ConcurrencyLimiter bounds the total concurrency of one agent or workflow
+// run: its parallel tool calls, the tool calls of nested sub-agents, and
+// parallel workflow branches all draw from the same slots. It is created by
+// WithConcurrencyLimit and carried in the run's context.
+//
+// Work that finds no free slot runs in the goroutine that started it, which
+// already counts toward the limit, rather than waiting. Nested fan-outs
+// therefore degrade to sequential execution instead of deadlocking while
+// their parents hold every slot.
+type ConcurrencyLimiter struct {
+	sem chan struct{}
+}
+
+// TryAcquire takes a slot for a new goroutine without blocking and reports
+// whether one was free. A nil limiter always has a free slot.
+func (l *ConcurrencyLimiter) TryAcquire() bool {
+	if l == nil {
+		return true
+	}
+	select {
+	case l.sem <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Release returns a slot taken with TryAcquire.
+func (l *ConcurrencyLimiter) Release() {
+	if l == nil {
+		return
+	}
+	<-l.sem
+}
+
+// concurrencyLimiterKey is the context key for a run's ConcurrencyLimiter.
+type concurrencyLimiterKey struct{}
+
+// WithConcurrencyLimit returns a context limiting the work started with it
+// to n concurrent tasks, counting the goroutine that starts the run. A
+// value of 1 runs everything sequentially; 0 or less leaves ctx unchanged.
+// If ctx already carries a limit, as in a sub-agent or nested workflow, it
+// is kept so the whole run shares one budget. Use agent.WithRunConcurrency
+// or workflow.WithRunConcurrency to limit a whole agent or workflow run.
+func WithConcurrencyLimit(ctx context.Context, n int) context.Context {
+	if n <= 0 || ContextConcurrencyLimiter(ctx) != nil {
+		return ctx
+	}
+	// The run's own goroutine holds one of the n slots
+	l := &ConcurrencyLimiter{sem: make(chan struct{}, n-1)}
+	return context.WithValue(ctx, concurrencyLimiterKey{}, l)
+}
+
+// ContextConcurrencyLimiter returns the limiter attached to ctx with
+// WithConcurrencyLimit, or nil if there is none.
+func ContextConcurrencyLimiter(ctx context.Context) *ConcurrencyLimiter {
+	l, _ := ctx.Value(concurrencyLimiterKey{}).(*ConcurrencyLimiter)
+	return l
+}
@@ -0,0 +1,42 @@
+package gains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithConcurrencyLimit(t *testing.T) {
+	t.Run("no limit by default", func(t *testing.T) {
+		assert.Nil(t, ContextConcurrencyLimiter(context.Background()))
+		assert.Nil(t, ContextConcurrencyLimiter(WithConcurrencyLimit(context.Background(), 0)))
+	})
+
+	t.Run("the run goroutine holds one slot", func(t *testing.T) {
+		l := ContextConcurrencyLimiter(WithConcurrencyLimit(context.Background(), 3))
+
+		assert.True(t, l.TryAcquire())
+		assert.True(t, l.TryAcquire())
+		assert.False(t, l.TryAcquire())
+		l.Release()
+		assert.True(t, l.TryAcquire())
+	})
+
+	t.Run("a limit of one is sequential", func(t *testing.T) {
+		l := ContextConcurrencyLimiter(WithConcurrencyLimit(context.Background(), 1))
+		assert.False(t, l.TryAcquire())
+	})
+
+	t.Run("nested limits share the outer limiter", func(t *testing.T) {
+		outer := WithConcurrencyLimit(context.Background(), 2)
+		inner := WithConcurrencyLimit(outer, 10)
+		assert.Same(t, ContextConcurrencyLimiter(outer), ContextConcurrencyLimiter(inner))
+	})
+
+	t.Run("nil limiter is unlimited", func(t *testing.T) {
+		var l *ConcurrencyLimiter
+		assert.True(t, l.TryAcquire())
+		l.Release()
+	})
+}
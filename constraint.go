@@ -0,0 +1,121 @@
+package gains
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// ConstraintKind identifies the language of an output constraint.
+type ConstraintKind string
+
+const (
+	// ConstraintRegex restricts output to text fully matching a regular
+	// expression.
+	ConstraintRegex ConstraintKind = "regex"
+	// ConstraintGrammar restricts output to text derivable from an EBNF
+	// grammar.
+	ConstraintGrammar ConstraintKind = "grammar"
+)
+
+// defaultConstraintAttempts is the number of requests made by the
+// validation fallback when OutputConstraint.MaxAttempts is not set.
+const defaultConstraintAttempts = 3
+
+// OutputConstraint restricts a response to a strict format, such as an ID,
+// a date, or a small DSL. Backends with constrained decoding enforce it
+// while generating. Elsewhere the model is instructed to follow it and the
+// response is validated, with invalid responses sent back for correction.
+type OutputConstraint struct {
+	// Kind is the language of Pattern.
+	Kind ConstraintKind `json:"kind"`
+	// Pattern is the regular expression or EBNF grammar.
+	Pattern string `json:"pattern"`
+	// Validate checks a response in the validation fallback. Regex
+	// constraints are checked against Pattern when it is nil; grammar
+	// constraints are only validated when it is set.
+	Validate func(content string) error `json:"-"`
+	// MaxAttempts is the number of requests the validation fallback makes
+	// before failing with *ErrConstraintViolation. Defaults to 3.
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+}
+
+// Regex returns a constraint requiring output that fully matches pattern,
+// in Go regexp syntax.
+func Regex(pattern string) OutputConstraint {
+	return OutputConstraint{Kind: ConstraintRegex, Pattern: pattern}
+}
+
+// Grammar returns a constraint requiring output derivable from an EBNF
+// grammar. Set Validate to check responses when the backend cannot enforce
+// the grammar itself.
+func Grammar(ebnf string) OutputConstraint {
+	return OutputConstraint{Kind: ConstraintGrammar, Pattern: ebnf}
+}
+
+// WithConstrainedOutput restricts the response to text matching c:
+//
+//	ai.WithConstrainedOutput(ai.Regex(`[A-Z]{3}-\d{4}`))
+//
+// Providers reporting support through ConstrainedDecoder enforce the
+// constraint natively. For others, client.Client.Chat falls back to
+// instructing the model and validating the response, retrying up to
+// c.MaxAttempts times; ChatStream adds the instructions only.
+func WithConstrainedOutput(c OutputConstraint) Option {
+	return func(o *Options) {
+		o.Constraint = &c
+	}
+}
+
+// Attempts returns the number of requests the validation fallback makes.
+func (c OutputConstraint) Attempts() int {
+	if c.MaxAttempts <= 0 {
+		return defaultConstraintAttempts
+	}
+	return c.MaxAttempts
+}
+
+// Check validates content against the constraint, returning
+// *ErrConstraintViolation if it does not conform. A grammar constraint
+// without a Validate function accepts any content.
+func (c OutputConstraint) Check(content string) error {
+	var err error
+	switch {
+	case c.Validate != nil:
+		err = c.Validate(content)
+	case c.Kind == ConstraintRegex:
+		re, compileErr := regexp.Compile(`^(?:` + c.Pattern + `)$`)
+		if compileErr != nil {
+			err = fmt.Errorf("invalid pattern: %w", compileErr)
+		} else if !re.MatchString(content) {
+			err = fmt.Errorf("does not match %s", c.Pattern)
+		}
+	}
+	if err != nil {
+		return &ErrConstraintViolation{Kind: c.Kind, Content: content, Err: err}
+	}
+	return nil
+}
+
+// Instructions returns a system prompt asking the model to follow the
+// constraint, used when the provider cannot enforce it.
+func (c OutputConstraint) Instructions() string {
+	if c.Kind == ConstraintGrammar {
+		return "Respond with only text that conforms to this EBNF grammar, with no other text:\n\n" + c.Pattern
+	}
+	return "Respond with only text that fully matches this regular expression, with no other text:\n\n" + c.Pattern
+}
+
+// ConstrainedDecoder is an optional interface for chat providers that can
+// enforce an OutputConstraint while decoding.
+type ConstrainedDecoder interface {
+	SupportsConstraint(kind ConstraintKind) bool
+}
+
+// SupportsConstraint reports whether provider enforces constraints of kind
+// natively.
+func SupportsConstraint(provider ChatProvider, kind ConstraintKind) bool {
+	if cd, ok := provider.(ConstrainedDecoder); ok {
+		return cd.SupportsConstraint(kind)
+	}
+	return false
+}
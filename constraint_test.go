@@ -0,0 +1,58 @@
+package gains
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithConstrainedOutput(t *testing.T) {
+	assert.Nil(t, ApplyOptions().Constraint)
+
+	opts := ApplyOptions(WithConstrainedOutput(Regex(`\d+`)))
+	require.NotNil(t, opts.Constraint)
+	assert.Equal(t, ConstraintRegex, opts.Constraint.Kind)
+	assert.Equal(t, `\d+`, opts.Constraint.Pattern)
+}
+
+func TestOutputConstraint_Check(t *testing.T) {
+	t.Run("regex requires a full match", func(t *testing.T) {
+		c := Regex(`[A-Z]{3}-\d{4}`)
+		assert.NoError(t, c.Check("ABC-1234"))
+
+		err := c.Check("ID: ABC-1234")
+		var violation *ErrConstraintViolation
+		require.ErrorAs(t, err, &violation)
+		assert.Equal(t, ConstraintRegex, violation.Kind)
+		assert.Equal(t, "ID: ABC-1234", violation.Content)
+	})
+
+	t.Run("alternation is anchored as a whole", func(t *testing.T) {
+		c := Regex(`yes|no`)
+		assert.NoError(t, c.Check("no"))
+		assert.Error(t, c.Check("yes or no"))
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		assert.Error(t, Regex(`(`).Check("x"))
+	})
+
+	t.Run("grammar without validator accepts anything", func(t *testing.T) {
+		assert.NoError(t, Grammar(`expr = term { "+" term } .`).Check("anything"))
+	})
+
+	t.Run("validator overrides pattern", func(t *testing.T) {
+		c := Grammar(`expr = term .`)
+		c.Validate = func(string) error { return errors.New("bad expression") }
+		assert.ErrorContains(t, c.Check("1+"), "bad expression")
+	})
+}
+
+func TestOutputConstraint_Attempts(t *testing.T) {
+	assert.Equal(t, 3, Regex(`x`).Attempts())
+	c := Regex(`x`)
+	c.MaxAttempts = 5
+	assert.Equal(t, 5, c.Attempts())
+}
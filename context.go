@@ -0,0 +1,113 @@
+package gains
+
+import (
+	"context"
+	"sync"
+)
+
+// contextOptionsKey is the context key for per-request chat options.
+type contextOptionsKey struct{}
+
+// WithContextOptions returns a context carrying opts for chat calls made with
+// it. Middlewares and HTTP handlers can use it to attach per-tenant settings
+// such as a model, temperature, or metadata without threading options through
+// every layer:
+//
+//	ctx = gains.WithContextOptions(ctx,
+//	    gains.WithModel(tenant.Model),
+//	    gains.WithMetadata("tenant", tenant.ID),
+//	)
+//
+// The client, workflow steps, and agents merge these options into each chat
+// call. They take precedence over client defaults, and options passed to the
+// call itself take precedence over them. Calling WithContextOptions on a
+// context that already carries options appends to them, so later options win.
+func WithContextOptions(ctx context.Context, opts ...Option) context.Context {
+	if len(opts) == 0 {
+		return ctx
+	}
+	existing := ContextOptions(ctx)
+	return context.WithValue(ctx, contextOptionsKey{}, append(existing, opts...))
+}
+
+// ContextOptions returns the chat options attached to ctx with
+// WithContextOptions, or nil if there are none. The returned slice is a copy
+// and may be appended to freely.
+func ContextOptions(ctx context.Context) []Option {
+	opts, _ := ctx.Value(contextOptionsKey{}).([]Option)
+	if len(opts) == 0 {
+		return nil
+	}
+	return append([]Option(nil), opts...)
+}
+
+// dryRunKey is the context key marking a dry run.
+type dryRunKey struct{}
+
+// WithDryRun returns a context marking a dry run. Chat calls made with it
+// return an empty response without reaching a provider, tool handlers are
+// not executed, and workflow steps and agents report the prompts they would
+// send instead of sending them. Use agent.WithDryRun or workflow.WithDryRun
+// to dry-run a whole agent or workflow.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun reports whether ctx marks a dry run.
+func IsDryRun(ctx context.Context) bool {
+	dry, _ := ctx.Value(dryRunKey{}).(bool)
+	return dry
+}
+
+// FinishReasonStopped is the FinishReason of a streamed response that was
+// stopped with the StopFunc of WithStop before the model finished.
+const FinishReasonStopped = "stopped"
+
+// stopKey is the context key for a stop signal.
+type stopKey struct{}
+
+// StopFunc stops the streaming responses of a context made by WithStop.
+// Calling it more than once has no effect.
+type StopFunc func()
+
+// WithStop returns a context whose streaming chat responses can be stopped
+// gracefully, as with the stop button of a chat UI. After stop is called,
+// an in-flight stream ends with a response holding the content received so
+// far and FinishReason FinishReasonStopped, and agents end their run with
+// that response instead of starting another step. Unlike cancelling the
+// context, which fails the request, stopping keeps the partial content so
+// it can be committed to the conversation.
+//
+// A stopped response carries no usage, because providers report it only
+// when a response completes, so its tokens are not counted in usage
+// tracking or budgets even though the provider may bill them.
+//
+// Non-streaming requests are not interrupted; they complete normally.
+func WithStop(ctx context.Context) (context.Context, StopFunc) {
+	ch := make(chan struct{})
+	var once sync.Once
+	return context.WithValue(ctx, stopKey{}, ch), func() {
+		once.Do(func() { close(ch) })
+	}
+}
+
+// StopSignal returns a channel that is closed when the StopFunc of ctx is
+// called, or nil if ctx was not made by WithStop.
+func StopSignal(ctx context.Context) <-chan struct{} {
+	ch, _ := ctx.Value(stopKey{}).(chan struct{})
+	return ch
+}
+
+// IsStopped reports whether the StopFunc of ctx has been called.
+func IsStopped(ctx context.Context) bool {
+	ch := StopSignal(ctx)
+	if ch == nil {
+		return false
+	}
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,269 @@
+package gains
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/spetersoncode/gains/tokenizer"
+)
+
+// ContextWindowCapable is an optional interface that models can implement
+// to report the size of their context window in tokens.
+type ContextWindowCapable interface {
+	ContextWindow() int
+}
+
+// ModelContextWindow returns the context window of a model in tokens, or 0
+// if the model does not implement ContextWindowCapable.
+func ModelContextWindow(m Model) int {
+	if cw, ok := m.(ContextWindowCapable); ok {
+		return cw.ContextWindow()
+	}
+	return 0
+}
+
+// ErrContextOverflow is returned by ContextManager.Fit when the messages it
+// must keep, the system messages and the latest message, do not fit in the
+// context window on their own.
+type ErrContextOverflow struct {
+	Tokens int // estimated tokens of the messages that must be kept
+	Limit  int // tokens available for messages
+}
+
+// Error returns a message with the estimated and available token counts.
+func (e *ErrContextOverflow) Error() string {
+	return fmt.Sprintf("context overflow: messages need about %d tokens, %d available", e.Tokens, e.Limit)
+}
+
+// TokenEstimator returns the number of tokens messages occupy in a prompt
+// for model. The model may be nil if it is not known.
+type TokenEstimator func(model Model, messages []Message) int
+
+// EstimateTokens approximates the token count of messages for model with
+// the offline estimator tokenizer.For selects from the model's ID, plus a
+// small per-message overhead: tiktoken-style splitting for OpenAI models,
+// about 3.5 characters per token for Anthropic models, and 4 for others. It
+// is the default TokenEstimator of a ContextManager; register an exact
+// tokenizer with tokenizer.Register, or use a provider's counting endpoint,
+// where precision matters.
+func EstimateTokens(model Model, messages []Message) int {
+	var text strings.Builder
+	for _, m := range messages {
+		text.WriteString(m.Content)
+		for _, p := range m.Parts {
+			text.WriteString(p.Text)
+		}
+		for _, tc := range m.ToolCalls {
+			text.WriteString(tc.Name)
+			text.WriteString(tc.Arguments)
+		}
+		for _, tr := range m.ToolResults {
+			text.WriteString(tr.Content)
+		}
+	}
+	return countTokens(model, text.String()) + 4*len(messages)
+}
+
+// countTokens estimates the tokens of text for model with its offline
+// tokenizer. Models whose IDs name no known family are estimated by
+// provider.
+func countTokens(model Model, text string) int {
+	if model == nil || text == "" {
+		return tokenizer.EncodeCount("", text)
+	}
+	if t, ok := tokenizer.Lookup(model.String()); ok {
+		return t.Count(text)
+	}
+	switch model.Provider() {
+	case ProviderAnthropic:
+		return tokenizer.Claude.Count(text)
+	case ProviderOpenAI:
+		return tokenizer.OpenAI.Count(text)
+	case ProviderGoogle, ProviderVertex:
+		return tokenizer.Gemini.Count(text)
+	}
+	return tokenizer.Default.Count(text)
+}
+
+// SummarizeFunc summarizes messages that no longer fit in the context
+// window. It typically asks a cheap model for the summary.
+type SummarizeFunc func(ctx context.Context, messages []Message) (string, error)
+
+// ContextManagerOption configures a ContextManager.
+type ContextManagerOption func(*ContextManager)
+
+// WithContextLimit sets the context window in tokens, overriding the window
+// of the model. Use it for models that do not report one.
+func WithContextLimit(tokens int) ContextManagerOption {
+	return func(m *ContextManager) {
+		m.limit = tokens
+	}
+}
+
+// WithOutputReserve sets the tokens kept free for the model's reply when
+// the request does not set WithMaxTokens. Default is 4096.
+func WithOutputReserve(tokens int) ContextManagerOption {
+	return func(m *ContextManager) {
+		m.reserve = tokens
+	}
+}
+
+// WithTokenEstimator replaces EstimateTokens for measuring messages.
+func WithTokenEstimator(fn TokenEstimator) ContextManagerOption {
+	return func(m *ContextManager) {
+		m.estimate = fn
+	}
+}
+
+// WithContextSummarizer summarizes the messages that are trimmed instead of
+// dropping them. The summary is sent as a system message after the leading
+// system messages, and summaryTokens are set aside for it.
+func WithContextSummarizer(fn SummarizeFunc, summaryTokens int) ContextManagerOption {
+	return func(m *ContextManager) {
+		m.summarize = fn
+		m.summaryTokens = summaryTokens
+	}
+}
+
+// ContextManager keeps requests within the context window of their model.
+// When the estimated size of a request exceeds the window, Fit trims the
+// oldest messages, or summarizes them with WithContextSummarizer. System
+// messages and the latest message are always kept, and tool results stay
+// with the assistant message that requested them.
+//
+// A ContextManager is safe for concurrent use. It caches its last summary,
+// so a growing conversation only summarizes the messages trimmed since.
+type ContextManager struct {
+	limit         int
+	reserve       int
+	estimate      TokenEstimator
+	summarize     SummarizeFunc
+	summaryTokens int
+
+	mu      sync.Mutex
+	covered []Message // messages the cached summary covers
+	summary string
+}
+
+// NewContextManager returns a ContextManager that trims the oldest messages
+// of requests that would exceed their model's context window.
+func NewContextManager(opts ...ContextManagerOption) *ContextManager {
+	m := &ContextManager{
+		reserve:  4096,
+		estimate: EstimateTokens,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Fit returns messages trimmed to fit the context window of the request
+// described by opts. The window comes from WithContextLimit or the model set
+// by WithModel; if neither is known, messages are returned unchanged. The
+// tools and maximum output tokens of the request count against the window.
+// The input slice is not modified.
+func (m *ContextManager) Fit(ctx context.Context, messages []Message, opts ...Option) ([]Message, error) {
+	o := ApplyOptions(opts...)
+	limit := m.limit
+	if limit == 0 && o.Model != nil {
+		limit = ModelContextWindow(o.Model)
+	}
+	if limit == 0 {
+		return messages, nil
+	}
+
+	available := limit - m.reserve - m.toolTokens(o)
+	if o.MaxTokens > 0 {
+		available = limit - o.MaxTokens - m.toolTokens(o)
+	}
+	if m.estimate(o.Model, messages) <= available {
+		return messages, nil
+	}
+
+	system, rest := leadingSystem(messages)
+	if m.summarize != nil {
+		available -= m.summaryTokens
+	}
+
+	// Drop the oldest messages until the rest fit, keeping tool results
+	// with their assistant message and always keeping the latest message
+	start := 0
+	kept := func() []Message { return append(append([]Message{}, system...), rest[start:]...) }
+	for start < len(rest)-1 && m.estimate(o.Model, kept()) > available {
+		start++
+		for start < len(rest)-1 && rest[start].Role == RoleTool {
+			start++
+		}
+	}
+	if tokens := m.estimate(o.Model, kept()); tokens > available {
+		return nil, &ErrContextOverflow{Tokens: tokens, Limit: available}
+	}
+	if m.summarize == nil || start == 0 {
+		return kept(), nil
+	}
+
+	summary, err := m.summarizeTrimmed(ctx, rest[:start])
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Message, 0, len(system)+1+len(rest)-start)
+	result = append(result, system...)
+	result = append(result, Message{Role: RoleSystem, Content: "Summary of the earlier conversation:\n" + summary})
+	return append(result, rest[start:]...), nil
+}
+
+// toolTokens estimates the tokens taken by the tool definitions of a request.
+func (m *ContextManager) toolTokens(o *Options) int {
+	if len(o.Tools) == 0 {
+		return 0
+	}
+	data, err := json.Marshal(o.Tools)
+	if err != nil {
+		return 0
+	}
+	return countTokens(o.Model, string(data))
+}
+
+// summarizeTrimmed returns a summary of trimmed, extending the cached
+// summary when it covers a prefix of trimmed.
+func (m *ContextManager) summarizeTrimmed(ctx context.Context, trimmed []Message) (string, error) {
+	m.mu.Lock()
+	previous, covered := m.summary, m.covered
+	m.mu.Unlock()
+
+	if len(covered) > len(trimmed) || !reflect.DeepEqual(covered, trimmed[:len(covered)]) {
+		previous, covered = "", nil
+	}
+	if previous != "" && len(covered) == len(trimmed) {
+		return previous, nil
+	}
+
+	input := trimmed[len(covered):]
+	if previous != "" {
+		input = append([]Message{{Role: RoleSystem, Content: "Summary so far:\n" + previous}}, input...)
+	}
+	summary, err := m.summarize(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("summarize trimmed messages: %w", err)
+	}
+
+	m.mu.Lock()
+	m.summary = summary
+	m.covered = append([]Message{}, trimmed...)
+	m.mu.Unlock()
+	return summary, nil
+}
+
+// leadingSystem returns the leading system messages of messages and the rest.
+func leadingSystem(messages []Message) (system, rest []Message) {
+	i := 0
+	for i < len(messages) && messages[i].Role == RoleSystem {
+		i++
+	}
+	return messages[:i], messages[i:]
+}
@@ -0,0 +1,150 @@
+package gains
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// windowModel is a testModel reporting a context window.
+type windowModel struct {
+	testModel
+	window int
+}
+
+func (m windowModel) ContextWindow() int { return m.window }
+
+// contextMessages returns a conversation of five messages estimated at 14
+// tokens each: 40 characters plus the per-message overhead.
+func contextMessages() []Message {
+	text := strings.Repeat("x", 40)
+	return []Message{
+		{Role: RoleSystem, Content: text},
+		{Role: RoleUser, Content: text},
+		{Role: RoleAssistant, ToolCalls: []ToolCall{{ID: "c1", Name: "t", Arguments: strings.Repeat("x", 39)}}},
+		{Role: RoleTool, ToolResults: []ToolResult{{ToolCallID: "c1", Content: text}}},
+		{Role: RoleUser, Content: text},
+	}
+}
+
+func TestModelContextWindow(t *testing.T) {
+	assert.Equal(t, 0, ModelContextWindow(testModel("m")))
+	assert.Equal(t, 1000, ModelContextWindow(windowModel{testModel: "m", window: 1000}))
+}
+
+func TestEstimateTokens(t *testing.T) {
+	messages := []Message{{Role: RoleUser, Content: strings.Repeat("x", 70)}}
+	assert.Equal(t, 17+4, EstimateTokens(nil, messages))
+	assert.Equal(t, 20+4, EstimateTokens(anthropicModel{}, messages))
+	// OpenAI models are split like tiktoken; the nine-letter word is 2 tokens
+	assert.Equal(t, 4+4, EstimateTokens(testModel("gpt-4o"), []Message{{Content: "Hello, wonderful"}}))
+}
+
+// anthropicModel is a Model of the Anthropic provider.
+type anthropicModel struct{}
+
+func (anthropicModel) String() string     { return "claude" }
+func (anthropicModel) Provider() Provider { return ProviderAnthropic }
+
+func TestContextManager_Fit(t *testing.T) {
+	ctx := context.Background()
+	messages := contextMessages()
+
+	t.Run("unknown window leaves messages unchanged", func(t *testing.T) {
+		m := NewContextManager()
+		fitted, err := m.Fit(ctx, messages, WithModel(testModel("m")))
+		require.NoError(t, err)
+		assert.Equal(t, messages, fitted)
+	})
+
+	t.Run("messages within the window are unchanged", func(t *testing.T) {
+		m := NewContextManager(WithOutputReserve(0))
+		fitted, err := m.Fit(ctx, messages, WithModel(windowModel{testModel: "m", window: 70}))
+		require.NoError(t, err)
+		assert.Equal(t, messages, fitted)
+	})
+
+	t.Run("oldest messages are trimmed", func(t *testing.T) {
+		m := NewContextManager(WithContextLimit(60), WithOutputReserve(0))
+		fitted, err := m.Fit(ctx, messages)
+		require.NoError(t, err)
+		assert.Equal(t, []Message{messages[0], messages[2], messages[3], messages[4]}, fitted)
+	})
+
+	t.Run("tool results are trimmed with their tool call", func(t *testing.T) {
+		m := NewContextManager(WithContextLimit(45), WithOutputReserve(0))
+		fitted, err := m.Fit(ctx, messages)
+		require.NoError(t, err)
+		assert.Equal(t, []Message{messages[0], messages[4]}, fitted)
+	})
+
+	t.Run("max tokens of the request are reserved", func(t *testing.T) {
+		m := NewContextManager(WithContextLimit(80))
+		fitted, err := m.Fit(ctx, messages, WithMaxTokens(20))
+		require.NoError(t, err)
+		assert.Equal(t, []Message{messages[0], messages[2], messages[3], messages[4]}, fitted)
+	})
+
+	t.Run("overflow when kept messages do not fit", func(t *testing.T) {
+		m := NewContextManager(WithContextLimit(20), WithOutputReserve(0))
+		_, err := m.Fit(ctx, messages)
+		var overflow *ErrContextOverflow
+		require.True(t, errors.As(err, &overflow))
+		assert.Equal(t, 28, overflow.Tokens)
+		assert.Equal(t, 20, overflow.Limit)
+	})
+
+	t.Run("input is not modified", func(t *testing.T) {
+		m := NewContextManager(WithContextLimit(45), WithOutputReserve(0))
+		_, err := m.Fit(ctx, messages)
+		require.NoError(t, err)
+		assert.Equal(t, contextMessages(), messages)
+	})
+}
+
+func TestContextManager_Summarizer(t *testing.T) {
+	ctx := context.Background()
+	var calls [][]Message
+	summarize := func(ctx context.Context, messages []Message) (string, error) {
+		calls = append(calls, messages)
+		return "summary", nil
+	}
+	m := NewContextManager(WithContextLimit(60), WithOutputReserve(0), WithContextSummarizer(summarize, 20))
+
+	messages := contextMessages()
+	fitted, err := m.Fit(ctx, messages)
+	require.NoError(t, err)
+	require.Len(t, fitted, 3)
+	assert.Equal(t, messages[0], fitted[0])
+	assert.Equal(t, Message{Role: RoleSystem, Content: "Summary of the earlier conversation:\nsummary"}, fitted[1])
+	assert.Equal(t, messages[4], fitted[2])
+	require.Len(t, calls, 1)
+	assert.Equal(t, messages[1:4], calls[0])
+
+	t.Run("summary is cached", func(t *testing.T) {
+		_, err := m.Fit(ctx, messages)
+		require.NoError(t, err)
+		assert.Len(t, calls, 1)
+	})
+
+	t.Run("summary is extended as messages age out", func(t *testing.T) {
+		longer := append(contextMessages(), Message{Role: RoleAssistant, Content: strings.Repeat("y", 40)})
+		_, err := m.Fit(ctx, longer)
+		require.NoError(t, err)
+		require.Len(t, calls, 2)
+		assert.Equal(t, []Message{{Role: RoleSystem, Content: "Summary so far:\nsummary"}, longer[4]}, calls[1])
+	})
+
+	t.Run("summarizer errors are returned", func(t *testing.T) {
+		failing := NewContextManager(WithContextLimit(60), WithOutputReserve(0),
+			WithContextSummarizer(func(ctx context.Context, messages []Message) (string, error) {
+				return "", errors.New("model unavailable")
+			}, 20))
+		_, err := failing.Fit(ctx, messages)
+		assert.ErrorContains(t, err, "model unavailable")
+	})
+}
@@ -0,0 +1,81 @@
+package gains
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextOptions(t *testing.T) {
+	t.Run("empty context has no options", func(t *testing.T) {
+		assert.Nil(t, ContextOptions(context.Background()))
+	})
+
+	t.Run("options are attached to the context", func(t *testing.T) {
+		ctx := WithContextOptions(context.Background(), WithModel(testModel("m1")), WithTemperature(0.3))
+
+		o := ApplyOptions(ContextOptions(ctx)...)
+		assert.Equal(t, testModel("m1"), o.Model)
+		assert.Equal(t, 0.3, *o.Temperature)
+	})
+
+	t.Run("nested calls append and later options win", func(t *testing.T) {
+		ctx := WithContextOptions(context.Background(), WithTemperature(0.3), WithMaxTokens(100))
+		ctx = WithContextOptions(ctx, WithTemperature(0.8))
+
+		o := ApplyOptions(ContextOptions(ctx)...)
+		assert.Equal(t, 0.8, *o.Temperature)
+		assert.Equal(t, 100, o.MaxTokens)
+	})
+
+	t.Run("parent context is unaffected", func(t *testing.T) {
+		parent := WithContextOptions(context.Background(), WithMaxTokens(100))
+		_ = WithContextOptions(parent, WithMaxTokens(200))
+		_ = WithContextOptions(parent, WithTemperature(0.5))
+
+		o := ApplyOptions(ContextOptions(parent)...)
+		assert.Equal(t, 100, o.MaxTokens)
+		assert.Nil(t, o.Temperature)
+	})
+
+	t.Run("returned slice is a copy", func(t *testing.T) {
+		ctx := WithContextOptions(context.Background(), WithMaxTokens(100))
+		opts := ContextOptions(ctx)
+		opts[0] = WithMaxTokens(999)
+
+		assert.Equal(t, 100, ApplyOptions(ContextOptions(ctx)...).MaxTokens)
+	})
+
+	t.Run("no options returns the same context", func(t *testing.T) {
+		ctx := context.Background()
+		assert.Equal(t, ctx, WithContextOptions(ctx))
+	})
+}
+
+func TestWithMetadata(t *testing.T) {
+	o := ApplyOptions(WithMetadata("tenant", "acme"), WithMetadata("user", "u1"), WithMetadata("tenant", "globex"))
+	assert.Equal(t, map[string]string{"tenant": "globex", "user": "u1"}, o.Metadata)
+	assert.Nil(t, ApplyOptions().Metadata)
+}
+
+func TestWithDryRun(t *testing.T) {
+	assert.False(t, IsDryRun(context.Background()))
+	assert.True(t, IsDryRun(WithDryRun(context.Background())))
+}
+
+func TestWithStop(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, StopSignal(ctx))
+	assert.False(t, IsStopped(ctx))
+
+	ctx, stop := WithStop(ctx)
+	assert.NotNil(t, StopSignal(ctx))
+	assert.False(t, IsStopped(ctx))
+
+	stop()
+	stop()
+	assert.True(t, IsStopped(ctx))
+	_, open := <-StopSignal(ctx)
+	assert.False(t, open)
+}
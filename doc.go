@@ -73,6 +73,28 @@
 //	    fmt.Print(event.Delta)
 //	}
 //
+// To collect a stream into a final [Response], use [AccumulateStream] for
+// provider streams or event.Accumulate for client event streams. Both report
+// [StreamStats] such as time-to-first-token and tokens per second:
+//
+//	resp, stats, err := event.Accumulate(stream)
+//	fmt.Printf("TTFT %s, %.1f tok/s\n", stats.TimeToFirstToken, stats.TokensPerSecond)
+//
+// To print a stream as it arrives, [StreamToWriter] and
+// event.StreamToWriter write its content to an io.Writer and return the
+// final response; client.Client.ChatStreamTo does both in one call:
+//
+//	resp, err := c.ChatStreamTo(ctx, messages, os.Stdout)
+//
+// To let users stop a response mid-stream, make the request with a context
+// from [WithStop]. Calling stop ends the stream with the content received
+// so far and FinishReason [FinishReasonStopped], which agents keep as their
+// final response:
+//
+//	ctx, stop := ai.WithStop(ctx)
+//	go func() { <-stopButton; stop() }()
+//	result, err := a.RunSession(ctx, sess) // result.Termination == agent.TerminationStopped
+//
 // # Configuration Options
 //
 // Customize requests with functional options:
@@ -83,6 +105,51 @@
 //	    ai.WithTemperature(0.7),
 //	)
 //
+// # Context Options
+//
+// Middlewares and HTTP handlers can attach options to a request context with
+// WithContextOptions. The client, workflow steps, and agents merge them into
+// every chat call made with that context, so per-tenant settings need not be
+// threaded through each layer:
+//
+//	ctx = ai.WithContextOptions(r.Context(),
+//	    ai.WithModel(tenant.Model),
+//	    ai.WithMetadata("tenant", tenant.ID),
+//	)
+//
+// Options passed to a call override context options, which override client
+// defaults.
+//
+// # Context Windows
+//
+// A ContextManager keeps long conversations within the context window of
+// their model, which chat models report through ContextWindowCapable. Its
+// Fit method trims the oldest messages, or summarizes them with
+// WithContextSummarizer, when a request would not fit. Agents apply one
+// before every step with agent.WithContextManager:
+//
+//	cm := ai.NewContextManager(ai.WithOutputReserve(8192))
+//	result, err := a.Run(ctx, messages, agent.WithContextManager(cm))
+//
+// Before converting a request, every provider passes the conversation
+// through CanonicalMessages, which drops empty messages and unmatched tool
+// calls and results, merges consecutive messages of the same role, and
+// starts an assistant-first conversation with a user message. Trimmed or
+// hand-built histories are therefore accepted by every API.
+//
+// DiffHistory finds where two histories of a conversation diverge, and
+// MergeHistory joins them, for example when a client resubmits a
+// conversation after running its tools. OrderToolResults moves tool results
+// that arrived out of order back after the message with their calls.
+//
+// # Identifiers
+//
+// Message, run, session, and task IDs come from NewID, which returns ULIDs
+// by default. Install UUIDGenerator or a custom IDGenerator to change the
+// format everywhere, for example to get deterministic IDs in tests:
+//
+//	ai.SetIDGenerator(ai.UUIDGenerator)
+//
 // # Higher-Level Abstractions
 //
 // For more complex use cases, see:
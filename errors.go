@@ -188,6 +188,59 @@ func (e *UnmarshalError) Unwrap() error {
 	return e.Err
 }
 
+// ErrConstraintViolation is returned when a response does not conform to
+// the constraint set with WithConstrainedOutput.
+type ErrConstraintViolation struct {
+	Kind    ConstraintKind // language of the constraint
+	Content string         // the nonconforming response
+	Err     error          // why validation failed
+}
+
+// Error returns a message describing the violation.
+func (e *ErrConstraintViolation) Error() string {
+	return fmt.Sprintf("response violates %s constraint: %v", e.Kind, e.Err)
+}
+
+// Unwrap returns the underlying validation error.
+func (e *ErrConstraintViolation) Unwrap() error {
+	return e.Err
+}
+
+// ErrSchemaViolation is returned when a response still does not match the
+// response schema after the retries allowed by WithSchemaRetry.
+type ErrSchemaViolation struct {
+	Content  string // the last invalid response
+	Attempts int    // number of requests made
+	Err      error  // why validation failed
+}
+
+// Error returns a message describing the violation.
+func (e *ErrSchemaViolation) Error() string {
+	return fmt.Sprintf("response violates schema after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the underlying validation error.
+func (e *ErrSchemaViolation) Unwrap() error {
+	return e.Err
+}
+
+// ErrBudgetExceeded is returned when cumulative spend reaches a configured
+// cost or token budget.
+type ErrBudgetExceeded struct {
+	MaxCost   float64 // cost budget in USD, 0 if not set
+	Cost      float64 // cost in USD spent so far
+	MaxTokens int     // token budget, 0 if not set
+	Tokens    int     // input plus output tokens used so far
+}
+
+// Error returns a message describing which budget was exceeded.
+func (e *ErrBudgetExceeded) Error() string {
+	if e.MaxCost > 0 && e.Cost >= e.MaxCost {
+		return fmt.Sprintf("budget exceeded: spent $%.4f of $%.4f", e.Cost, e.MaxCost)
+	}
+	return fmt.Sprintf("token budget exceeded: used %d of %d tokens", e.Tokens, e.MaxTokens)
+}
+
 // ImageError represents an error during image processing.
 type ImageError struct {
 	Op  string // "decode" or "fetch"
@@ -204,3 +257,24 @@ func (e *ImageError) Error() string {
 func (e *ImageError) Unwrap() error {
 	return e.Err
 }
+
+// PanicError reports a panic recovered from a tool handler, workflow step,
+// or agent run so that it surfaces as an error instead of crashing the
+// process.
+type PanicError struct {
+	Value any    // value passed to panic
+	Stack []byte // stack trace of the panicking goroutine
+}
+
+// Error returns a message describing the panic value.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
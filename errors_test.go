@@ -81,6 +81,18 @@ func TestImageError(t *testing.T) {
 	})
 }
 
+func TestErrBudgetExceeded(t *testing.T) {
+	t.Run("cost budget", func(t *testing.T) {
+		err := &ErrBudgetExceeded{MaxCost: 1.5, Cost: 1.75, MaxTokens: 1000, Tokens: 10}
+		assert.Equal(t, "budget exceeded: spent $1.7500 of $1.5000", err.Error())
+	})
+
+	t.Run("token budget", func(t *testing.T) {
+		err := &ErrBudgetExceeded{MaxTokens: 1000, Tokens: 1200}
+		assert.Equal(t, "token budget exceeded: used 1200 of 1000 tokens", err.Error())
+	})
+}
+
 func TestUnmarshalError(t *testing.T) {
 	t.Run("Error without context", func(t *testing.T) {
 		err := &UnmarshalError{
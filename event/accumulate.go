@@ -0,0 +1,89 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// AddTo records e in acc and reports whether the stream has finished.
+// MessageDelta events add content, MessageEnd and RunEnd events record the
+// response, and RunEnd or RunError finish the stream. Other events are
+// ignored. For agent streams with several messages, the content spans all
+// messages and the response is the last one.
+func AddTo(acc *ai.StreamAccumulator, e Event) bool {
+	switch e.Type {
+	case MessageDelta:
+		acc.AddDelta(e.Delta)
+	case MessageEnd:
+		if e.Response != nil {
+			acc.Complete(e.Response)
+			// A later message or RunEnd may still follow.
+			return false
+		}
+	case RunEnd:
+		acc.Complete(e.Response)
+		return true
+	case RunError:
+		acc.Fail(e.Error)
+		return true
+	}
+	return false
+}
+
+// Accumulate drains an event channel, such as one returned by
+// client.Client.ChatStream, and returns the final response and its timing
+// statistics. On a RunError event the response is nil.
+func Accumulate(ch <-chan Event) (*ai.Response, ai.StreamStats, error) {
+	acc := ai.NewStreamAccumulator()
+	finished := false
+	for e := range ch {
+		if !finished {
+			finished = AddTo(acc, e)
+		}
+	}
+	if acc.Err() != nil {
+		return nil, acc.Stats(), acc.Err()
+	}
+	return acc.Response(), acc.Stats(), nil
+}
+
+// StreamToWriter writes the message content of an event channel to w as it
+// arrives and returns the final response, like Accumulate. It returns early
+// with an error on a RunError event, when ctx is cancelled, or when a write
+// to w fails; the rest of the channel is then drained in the background.
+func StreamToWriter(ctx context.Context, ch <-chan Event, w io.Writer) (*ai.Response, error) {
+	acc := ai.NewStreamAccumulator()
+	for {
+		select {
+		case <-ctx.Done():
+			go drain(ch)
+			return nil, ctx.Err()
+		case e, ok := <-ch:
+			if !ok {
+				return acc.Response(), nil
+			}
+			if e.Type == MessageDelta && e.Delta != "" {
+				if _, err := io.WriteString(w, e.Delta); err != nil {
+					go drain(ch)
+					return nil, fmt.Errorf("write stream: %w", err)
+				}
+			}
+			if AddTo(acc, e) {
+				go drain(ch)
+				if acc.Err() != nil {
+					return nil, acc.Err()
+				}
+				return acc.Response(), nil
+			}
+		}
+	}
+}
+
+// drain discards the remaining events of ch so its sender can finish.
+func drain(ch <-chan Event) {
+	for range ch {
+	}
+}
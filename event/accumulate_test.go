@@ -0,0 +1,84 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccumulate(t *testing.T) {
+	t.Run("returns final response from client stream", func(t *testing.T) {
+		final := &ai.Response{Content: "Hello", Usage: ai.Usage{OutputTokens: 2}}
+		ch := make(chan Event, 6)
+		ch <- Event{Type: RunStart}
+		ch <- Event{Type: MessageStart, MessageID: "msg_1"}
+		ch <- Event{Type: MessageDelta, MessageID: "msg_1", Delta: "Hel"}
+		ch <- Event{Type: MessageDelta, MessageID: "msg_1", Delta: "lo"}
+		ch <- Event{Type: MessageEnd, MessageID: "msg_1", Response: final}
+		ch <- Event{Type: RunEnd, Response: final}
+		close(ch)
+
+		resp, stats, err := Accumulate(ch)
+		require.NoError(t, err)
+		assert.Same(t, final, resp)
+		assert.Equal(t, 2, stats.Deltas)
+		assert.Equal(t, 2, stats.OutputTokens)
+	})
+
+	t.Run("returns run error", func(t *testing.T) {
+		ch := make(chan Event, 2)
+		ch <- Event{Type: MessageDelta, Delta: "partial"}
+		ch <- Event{Type: RunError, Error: errors.New("stream failed")}
+		close(ch)
+
+		resp, _, err := Accumulate(ch)
+		assert.Nil(t, resp)
+		assert.EqualError(t, err, "stream failed")
+	})
+
+	t.Run("keeps last message response for multi-step streams", func(t *testing.T) {
+		acc := ai.NewStreamAccumulator()
+		first := &ai.Response{Content: "calling tool"}
+		last := &ai.Response{Content: "done"}
+		assert.False(t, AddTo(acc, Event{Type: MessageEnd, Response: first}))
+		assert.False(t, AddTo(acc, Event{Type: ToolCallResult}))
+		assert.False(t, AddTo(acc, Event{Type: MessageEnd, Response: last}))
+		assert.True(t, AddTo(acc, Event{Type: RunEnd}))
+		assert.Same(t, last, acc.Response())
+	})
+}
+
+func TestStreamToWriter(t *testing.T) {
+	t.Run("writes message deltas and returns final response", func(t *testing.T) {
+		final := &ai.Response{Content: "Hello"}
+		ch := make(chan Event, 5)
+		ch <- Event{Type: MessageStart}
+		ch <- Event{Type: MessageDelta, Delta: "Hel"}
+		ch <- Event{Type: ToolCallArgs, Delta: `{"q":1}`}
+		ch <- Event{Type: MessageDelta, Delta: "lo"}
+		ch <- Event{Type: MessageEnd, Response: final}
+		close(ch)
+
+		var sb strings.Builder
+		resp, err := StreamToWriter(context.Background(), ch, &sb)
+		require.NoError(t, err)
+		assert.Equal(t, "Hello", sb.String())
+		assert.Same(t, final, resp)
+	})
+
+	t.Run("returns run error", func(t *testing.T) {
+		ch := make(chan Event, 2)
+		ch <- Event{Type: MessageDelta, Delta: "partial"}
+		ch <- Event{Type: RunError, Error: errors.New("stream failed")}
+		close(ch)
+
+		resp, err := StreamToWriter(context.Background(), ch, &strings.Builder{})
+		assert.Nil(t, resp)
+		assert.EqualError(t, err, "stream failed")
+	})
+}
@@ -5,6 +5,7 @@ package event
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	ai "github.com/spetersoncode/gains"
@@ -84,11 +85,16 @@ const (
 	// ParallelEnd fires when all parallel branches complete.
 	ParallelEnd Type = "parallel_end"
 
-	// RouteSelected fires when a route is chosen.
+	// RouteSelected fires when a route is chosen, or when a branch
+	// contributes to a parallel step's quorum.
 	RouteSelected Type = "route_selected"
 
 	// LoopIteration fires at the start of each loop iteration.
 	LoopIteration Type = "loop_iteration"
+
+	// Progress fires as a step advances, such as when a chain completes a
+	// step, with an estimate of how far it has come in Progress.
+	Progress Type = "progress"
 )
 
 // Retry events
@@ -109,6 +115,18 @@ const (
 	RetryExhausted Type = "retry_exhausted"
 )
 
+// Budget events
+const (
+	// ChatPlanned fires in a dry run in place of a chat call. Messages holds
+	// the rendered prompt and Tools the tool definitions offered to the model.
+	ChatPlanned Type = "chat_planned"
+)
+const (
+	// BudgetExceeded fires when cumulative cost or token usage reaches a
+	// configured budget. The Error field holds the budget error.
+	BudgetExceeded Type = "budget_exceeded"
+)
+
 // State synchronization events (AG-UI shared state)
 const (
 	// StateSnapshot fires to send the complete state to the frontend.
@@ -164,6 +182,9 @@ type ToolApprovalActivity struct {
 	Arguments  string         `json:"arguments"`
 	Status     ApprovalStatus `json:"status"`
 	Reason     string         `json:"reason,omitempty"` // Reason for rejection
+	// ResumeToken is set when the run suspended to wait for this decision.
+	// Pass it with the decision to agent.ResumeWithApproval.
+	ResumeToken string `json:"resumeToken,omitempty"`
 }
 
 // PatchOp represents a JSON Patch operation type (RFC 6902).
@@ -211,9 +232,15 @@ type Event struct {
 	// Step is the current iteration number (1-indexed) for agent events.
 	Step int
 
+	// RunID identifies a durable agent run on its RunStart event.
+	RunID string
+
 	// StepName identifies the step for workflow events.
 	StepName string
 
+	// Agent names the team member that produced the event in agent.Team runs.
+	Agent string
+
 	// RouteName identifies the selected route for RouteSelected events.
 	RouteName string
 
@@ -223,6 +250,9 @@ type Event struct {
 	// Attempt is the retry attempt number (1-indexed) for retry events.
 	Attempt int
 
+	// Progress estimates how far a step has come for Progress events.
+	Progress *ProgressInfo
+
 	// Error contains the error for RunError events.
 	Error error
 
@@ -239,32 +269,104 @@ type Event struct {
 	// StatePatches contains JSON Patch operations for StateDelta events.
 	StatePatches []JSONPatch
 
-	// Messages contains the complete message history for MessagesSnapshot events.
+	// Messages contains the complete message history for MessagesSnapshot
+	// events and the rendered prompt for ChatPlanned events.
 	Messages []ai.Message
 
+	// Tools contains the tool definitions offered to the model for ChatPlanned events.
+	Tools []ai.Tool
+
 	// Activity fields for ActivitySnapshot and ActivityDelta events.
 	ActivityID      string       // Unique ID for activity correlation
 	Activity        ActivityType // Type of activity (tool_approval, loading, etc.)
 	ActivityContent any          // Content for the activity (e.g., ToolApprovalActivity)
 	ActivityPatches []JSONPatch  // Patches for ActivityDelta events
 
+	// TraceParent is the W3C traceparent of the span active when the event
+	// occurred, set when the run is traced with the otel package, so that
+	// consumers can correlate events with traces.
+	TraceParent string
+
 	// Timestamp is when the event occurred.
 	Timestamp time.Time
 }
 
+// ProgressInfo estimates how far a step has come.
+type ProgressInfo struct {
+	// Completed is the number of units done.
+	Completed int `json:"completed"`
+	// Total is the number of units known. A loop reports its iteration
+	// limit, and completes early if it exits before reaching it.
+	Total int `json:"total"`
+	// Unit names what is counted, such as "steps" or "iterations".
+	Unit string `json:"unit"`
+}
+
+// Fraction returns the completed fraction, from 0 to 1, or 0 if Total is
+// unknown.
+func (p ProgressInfo) Fraction() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+	return min(float64(p.Completed)/float64(p.Total), 1)
+}
+
 // emit sends an event with timestamp to the channel (non-blocking).
+// Events sent to a full channel are dropped and counted in Stats.
 func Emit(ch chan<- Event, e Event) {
 	e.Timestamp = time.Now()
 	select {
 	case ch <- e:
+		emitted.Add(1)
 	default:
 		// Channel full - don't block
+		dropped.Add(1)
 	}
 }
 
+// DefaultBufferSize is the capacity of channels made by NewChannel.
+const DefaultBufferSize = 100
+
 // NewChannel creates a buffered event channel with standard capacity.
 func NewChannel() chan Event {
-	return make(chan Event, 100)
+	return NewChannelSize(DefaultBufferSize)
+}
+
+// NewChannelSize creates a buffered event channel with capacity n, or
+// DefaultBufferSize if n is not positive.
+func NewChannelSize(n int) chan Event {
+	if n <= 0 {
+		n = DefaultBufferSize
+	}
+	return make(chan Event, n)
+}
+
+// Process-wide Emit counters reported by Stats.
+var emitted, dropped atomic.Uint64
+
+// EmitStats counts the events sent with Emit since the process started.
+type EmitStats struct {
+	// Emitted is the number of events delivered to their channel.
+	Emitted uint64
+
+	// Dropped is the number of events discarded because their channel was
+	// full. A growing count means consumers are too slow for the buffer
+	// size; raise it with the buffer options of agent, workflow, and client.
+	Dropped uint64
+}
+
+// Stats returns the process-wide Emit counters, for export as metrics.
+func Stats() EmitStats {
+	return EmitStats{Emitted: emitted.Load(), Dropped: dropped.Load()}
+}
+
+// Saturation returns how full ch is, from 0 (empty) to 1 (full). It
+// returns 0 for unbuffered channels.
+func Saturation(ch <-chan Event) float64 {
+	if cap(ch) == 0 {
+		return 0
+	}
+	return float64(len(ch)) / float64(cap(ch))
 }
 
 // NewStateSnapshot creates a StateSnapshot event with the given state.
@@ -381,6 +483,19 @@ func NewToolApprovalPending(toolCallID, toolName, arguments string) Event {
 	})
 }
 
+// NewToolApprovalSuspended creates an ActivitySnapshot event for a pending
+// tool approval that suspended the run. The frontend sends its decision
+// together with resumeToken to continue the run.
+func NewToolApprovalSuspended(toolCallID, toolName, arguments, resumeToken string) Event {
+	return NewActivitySnapshot(toolCallID, ActivityToolApproval, ToolApprovalActivity{
+		ToolCallID:  toolCallID,
+		ToolName:    toolName,
+		Arguments:   arguments,
+		Status:      ApprovalPending,
+		ResumeToken: resumeToken,
+	})
+}
+
 // NewToolApprovalApproved creates an ActivityDelta event to mark a tool as approved.
 func NewToolApprovalApproved(toolCallID string) Event {
 	return NewActivityDelta(toolCallID, ActivityToolApproval,
@@ -448,6 +563,9 @@ type UserInputActivity struct {
 	Status      string   `json:"status"` // "pending", "responded", "cancelled", "timeout"
 	Value       string   `json:"value,omitempty"`
 	Confirmed   bool     `json:"confirmed,omitempty"`
+	// ResumeToken is set when the run suspended to wait for this input.
+	// Pass it with the response to workflow.ResumeWithApproval.
+	ResumeToken string `json:"resumeToken,omitempty"`
 }
 
 // NewUserInputPending creates an ActivitySnapshot event for a pending user input request.
@@ -465,6 +583,20 @@ func NewUserInputPending(requestID, inputType, title, message string, choices []
 	})
 }
 
+// NewUserInputSuspended creates an ActivitySnapshot event for a pending
+// user input request that suspended the run. The frontend sends its
+// response together with resumeToken to continue the run.
+func NewUserInputSuspended(requestID, inputType, title, message, resumeToken string) Event {
+	return NewActivitySnapshot(requestID, ActivityUserInput, UserInputActivity{
+		RequestID:   requestID,
+		Type:        inputType,
+		Title:       title,
+		Message:     message,
+		Status:      "pending",
+		ResumeToken: resumeToken,
+	})
+}
+
 // NewUserInputResponded creates an ActivityDelta event to mark an input as responded.
 func NewUserInputResponded(requestID, value string, confirmed bool) Event {
 	return NewActivityDelta(requestID, ActivityUserInput,
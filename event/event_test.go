@@ -0,0 +1,35 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewChannelSize(t *testing.T) {
+	assert.Equal(t, DefaultBufferSize, cap(NewChannel()))
+	assert.Equal(t, 8, cap(NewChannelSize(8)))
+	assert.Equal(t, DefaultBufferSize, cap(NewChannelSize(0)))
+}
+
+func TestEmitStats(t *testing.T) {
+	before := Stats()
+	ch := NewChannelSize(2)
+
+	Emit(ch, Event{Type: MessageDelta})
+	assert.Equal(t, 0.5, Saturation(ch))
+	Emit(ch, Event{Type: MessageDelta})
+	Emit(ch, Event{Type: MessageDelta}) // full, dropped
+	assert.Equal(t, 1.0, Saturation(ch))
+
+	after := Stats()
+	assert.Equal(t, uint64(2), after.Emitted-before.Emitted)
+	assert.Equal(t, uint64(1), after.Dropped-before.Dropped)
+	assert.Zero(t, Saturation(make(chan Event)))
+}
+
+func TestProgressInfo_Fraction(t *testing.T) {
+	assert.Equal(t, 0.5, ProgressInfo{Completed: 2, Total: 4}.Fraction())
+	assert.Equal(t, 1.0, ProgressInfo{Completed: 5, Total: 4}.Fraction())
+	assert.Zero(t, ProgressInfo{Completed: 2}.Fraction())
+}
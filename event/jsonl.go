@@ -0,0 +1,118 @@
+package event
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// Sink receives events as they are produced. It may be called concurrently.
+type Sink func(Event)
+
+// JSONLinesSink returns a Sink that writes each event to w as one JSON
+// object per line, for piping into jq and similar tools. Fields are named
+// in camelCase, empty fields are omitted, and errors are written as their
+// message. Writes are serialized; write errors are ignored.
+func JSONLinesSink(w io.Writer) Sink {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	return func(e Event) {
+		record := newRecord(e)
+		mu.Lock()
+		defer mu.Unlock()
+		enc.Encode(record)
+	}
+}
+
+// Marshal returns the JSON encoding of e in the format written by
+// JSONLinesSink, for other transports such as SSE.
+func Marshal(e Event) ([]byte, error) {
+	return json.Marshal(newRecord(e))
+}
+
+// record is the JSON form of an event.
+type record struct {
+	Type             Type           `json:"type"`
+	RunID            string         `json:"runId,omitempty"`
+	Step             int            `json:"step,omitempty"`
+	StepName         string         `json:"stepName,omitempty"`
+	Agent            string         `json:"agent,omitempty"`
+	RouteName        string         `json:"routeName,omitempty"`
+	Iteration        int            `json:"iteration,omitempty"`
+	Attempt          int            `json:"attempt,omitempty"`
+	MessageID        string         `json:"messageId,omitempty"`
+	Delta            string         `json:"delta,omitempty"`
+	Response         *ai.Response   `json:"response,omitempty"`
+	ToolCall         *ai.ToolCall   `json:"toolCall,omitempty"`
+	ToolResult       *ai.ToolResult `json:"toolResult,omitempty"`
+	Message          string         `json:"message,omitempty"`
+	Error            string         `json:"error,omitempty"`
+	PendingToolCalls []ai.ToolCall  `json:"pendingToolCalls,omitempty"`
+	State            any            `json:"state,omitempty"`
+	StatePatches     []JSONPatch    `json:"statePatches,omitempty"`
+	Messages         []ai.Message   `json:"messages,omitempty"`
+	Tools            []ai.Tool      `json:"tools,omitempty"`
+	ActivityID       string         `json:"activityId,omitempty"`
+	Activity         ActivityType   `json:"activity,omitempty"`
+	ActivityContent  any            `json:"activityContent,omitempty"`
+	ActivityPatches  []JSONPatch    `json:"activityPatches,omitempty"`
+	Progress         *ProgressInfo  `json:"progress,omitempty"`
+	TraceParent      string         `json:"traceParent,omitempty"`
+	Timestamp        time.Time      `json:"timestamp,omitzero"`
+}
+
+// newRecord returns the JSON form of e.
+func newRecord(e Event) record {
+	r := record{
+		Type:             e.Type,
+		RunID:            e.RunID,
+		Step:             e.Step,
+		StepName:         e.StepName,
+		Agent:            e.Agent,
+		RouteName:        e.RouteName,
+		Iteration:        e.Iteration,
+		Attempt:          e.Attempt,
+		MessageID:        e.MessageID,
+		Delta:            e.Delta,
+		Response:         e.Response,
+		ToolCall:         e.ToolCall,
+		ToolResult:       e.ToolResult,
+		Message:          e.Message,
+		PendingToolCalls: e.PendingToolCalls,
+		State:            e.State,
+		StatePatches:     e.StatePatches,
+		Messages:         e.Messages,
+		Tools:            e.Tools,
+		ActivityID:       e.ActivityID,
+		Activity:         e.Activity,
+		ActivityContent:  e.ActivityContent,
+		ActivityPatches:  e.ActivityPatches,
+		Progress:         e.Progress,
+		TraceParent:      e.TraceParent,
+		Timestamp:        e.Timestamp,
+	}
+	if e.Error != nil {
+		r.Error = e.Error.Error()
+	}
+	return r
+}
+
+// Tee returns a channel that carries every event from ch after passing it
+// to sink. The returned channel is closed when ch is closed, and must be
+// drained like ch:
+//
+//	events := event.Tee(a.RunStream(ctx, msgs), event.JSONLinesSink(os.Stdout))
+func Tee(ch <-chan Event, sink Sink) <-chan Event {
+	out := make(chan Event, cap(ch))
+	go func() {
+		defer close(out)
+		for e := range ch {
+			sink(e)
+			out <- e
+		}
+	}()
+	return out
+}
@@ -0,0 +1,61 @@
+package event
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLinesSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := JSONLinesSink(&buf)
+
+	sink(Event{Type: MessageDelta, MessageID: "msg_1", Delta: "Hi"})
+	sink(Event{Type: ToolCallStart, Step: 2, ToolCall: &ai.ToolCall{ID: "c1", Name: "search"}})
+	sink(Event{Type: RunError, Error: errors.New("boom")})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.JSONEq(t, `{"type":"message_delta","messageId":"msg_1","delta":"Hi"}`, lines[0])
+	assert.JSONEq(t, `{"type":"tool_call_start","step":2,"toolCall":{"id":"c1","name":"search","arguments":""}}`, lines[1])
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &record))
+	assert.Equal(t, "boom", record["error"])
+}
+
+func TestMarshal(t *testing.T) {
+	data, err := Marshal(Event{
+		Type:     Progress,
+		StepName: "pipeline",
+		Progress: &ProgressInfo{Completed: 1, Total: 4, Unit: "steps"},
+		Error:    errors.New("boom"),
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"progress","stepName":"pipeline","error":"boom",
+		"progress":{"completed":1,"total":4,"unit":"steps"}}`, string(data))
+}
+
+func TestTee(t *testing.T) {
+	ch := make(chan Event, 3)
+	ch <- Event{Type: RunStart}
+	ch <- Event{Type: MessageDelta, Delta: "Hi"}
+	ch <- Event{Type: RunEnd}
+	close(ch)
+
+	var seen []Type
+	out := Tee(ch, func(e Event) { seen = append(seen, e.Type) })
+
+	var forwarded []Type
+	for e := range out {
+		forwarded = append(forwarded, e.Type)
+	}
+	assert.Equal(t, []Type{RunStart, MessageDelta, RunEnd}, forwarded)
+	assert.Equal(t, forwarded, seen)
+}
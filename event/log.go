@@ -0,0 +1,156 @@
+package event
+
+import (
+	"context"
+	"log/slog"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// LogLevels sets the slog level at which each kind of event is logged.
+type LogLevels struct {
+	// Run is the level of run start and end events.
+	Run slog.Level
+	// Step is the level of step, route, loop, and parallel events.
+	Step slog.Level
+	// Tool is the level of tool execution, approval, and result events.
+	Tool slog.Level
+	// Retry is the level of retry events.
+	Retry slog.Level
+	// Error is the level of run errors and exceeded budgets.
+	Error slog.Level
+}
+
+// DefaultLogLevels logs runs at info, steps and tool calls at debug,
+// retries at warn, and errors at error.
+func DefaultLogLevels() LogLevels {
+	return LogLevels{
+		Run:   slog.LevelInfo,
+		Step:  slog.LevelDebug,
+		Tool:  slog.LevelDebug,
+		Retry: slog.LevelWarn,
+		Error: slog.LevelError,
+	}
+}
+
+// Log writes e to logger as a structured record at the level levels gives
+// its type. Message and tool argument deltas, state, and activity events
+// are not logged, and neither are message contents or tool results, so
+// logs stay small and free of user data.
+func Log(ctx context.Context, logger *slog.Logger, levels LogLevels, e Event) {
+	level, msg, ok := logRecord(levels, e)
+	if !ok || !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{slog.String("event", string(e.Type))}
+	if e.RunID != "" {
+		attrs = append(attrs, slog.String("run_id", e.RunID))
+	}
+	if e.Agent != "" {
+		attrs = append(attrs, slog.String("agent", e.Agent))
+	}
+	if e.Step > 0 {
+		attrs = append(attrs, slog.Int("step", e.Step))
+	}
+	if e.StepName != "" {
+		attrs = append(attrs, slog.String("step_name", e.StepName))
+	}
+	if e.RouteName != "" {
+		attrs = append(attrs, slog.String("route", e.RouteName))
+	}
+	if e.Iteration > 0 {
+		attrs = append(attrs, slog.Int("iteration", e.Iteration))
+	}
+	if e.Attempt > 0 {
+		attrs = append(attrs, slog.Int("attempt", e.Attempt))
+	}
+	if e.ToolCall != nil {
+		attrs = append(attrs,
+			slog.String("tool", e.ToolCall.Name),
+			slog.String("tool_call_id", e.ToolCall.ID),
+		)
+	}
+	if e.ToolResult != nil {
+		attrs = append(attrs, slog.Bool("tool_error", e.ToolResult.IsError))
+	}
+	if e.Message != "" {
+		attrs = append(attrs, slog.String("message", e.Message))
+	}
+	if e.Response != nil && (e.Type == StepEnd || e.Type == RunEnd) {
+		attrs = append(attrs, usageAttrs(e.Response.Usage)...)
+	}
+	if e.Error != nil {
+		attrs = append(attrs, slog.Any("error", e.Error))
+	}
+	logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// logRecord returns the level and message of e, and false if events of its
+// type are not logged.
+func logRecord(levels LogLevels, e Event) (slog.Level, string, bool) {
+	switch e.Type {
+	case RunStart:
+		return levels.Run, "run started", true
+	case RunEnd:
+		return levels.Run, "run finished", true
+	case RunError:
+		return levels.Error, "run failed", true
+	case BudgetExceeded:
+		return levels.Error, "budget exceeded", true
+
+	case StepStart:
+		return levels.Step, "step started", true
+	case StepEnd:
+		return levels.Step, "step finished", true
+	case StepSkipped:
+		return levels.Step, "step skipped", true
+	case RouteSelected:
+		return levels.Step, "route selected", true
+	case LoopIteration:
+		return levels.Step, "loop iteration", true
+	case ParallelStart:
+		return levels.Step, "parallel started", true
+	case ParallelEnd:
+		return levels.Step, "parallel finished", true
+
+	case ToolCallApproved:
+		return levels.Tool, "tool call approved", true
+	case ToolCallRejected:
+		return levels.Tool, "tool call rejected", true
+	case ToolCallExecuting:
+		return levels.Tool, "tool call executing", true
+	case ToolCallResult:
+		return levels.Tool, "tool call finished", true
+
+	case RetryFailed:
+		return levels.Retry, "attempt failed", true
+	case RetryScheduled:
+		return levels.Retry, "retry scheduled", true
+	case RetryExhausted:
+		return levels.Retry, "retries exhausted", true
+	}
+	return 0, "", false
+}
+
+// usageAttrs returns the log attributes of token usage.
+func usageAttrs(u ai.Usage) []slog.Attr {
+	return []slog.Attr{
+		slog.Int("input_tokens", u.InputTokens),
+		slog.Int("output_tokens", u.OutputTokens),
+	}
+}
+
+// LogStream forwards the events of ch to the returned channel, logging each
+// with Log. The returned channel closes when ch does and must be drained.
+func LogStream(ctx context.Context, logger *slog.Logger, levels LogLevels, ch <-chan Event) <-chan Event {
+	out := make(chan Event, cap(ch))
+	go func() {
+		defer close(out)
+		for e := range ch {
+			Log(ctx, logger, levels, e)
+			out <- e
+		}
+	}()
+	return out
+}
@@ -0,0 +1,80 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ctx := context.Background()
+	levels := DefaultLogLevels()
+
+	t.Run("logs steps with usage", func(t *testing.T) {
+		buf.Reset()
+		Log(ctx, logger, levels, Event{Type: StepEnd, Step: 2, Response: &ai.Response{Content: "secret", Usage: ai.Usage{InputTokens: 10, OutputTokens: 5}}})
+		out := buf.String()
+		assert.Contains(t, out, "level=DEBUG")
+		assert.Contains(t, out, `msg="step finished"`)
+		assert.Contains(t, out, "step=2")
+		assert.Contains(t, out, "input_tokens=10")
+		assert.NotContains(t, out, "secret")
+	})
+
+	t.Run("logs tool calls without arguments", func(t *testing.T) {
+		buf.Reset()
+		call := &ai.ToolCall{ID: "call_1", Name: "search", Arguments: `{"q":"secret"}`}
+		Log(ctx, logger, levels, Event{Type: ToolCallExecuting, ToolCall: call})
+		assert.Contains(t, buf.String(), "tool=search")
+		assert.NotContains(t, buf.String(), "secret")
+	})
+
+	t.Run("logs errors at error level", func(t *testing.T) {
+		buf.Reset()
+		Log(ctx, logger, levels, Event{Type: RunError, Error: errors.New("boom")})
+		assert.Contains(t, buf.String(), "level=ERROR")
+		assert.Contains(t, buf.String(), "error=boom")
+	})
+
+	t.Run("skips deltas", func(t *testing.T) {
+		buf.Reset()
+		Log(ctx, logger, levels, Event{Type: MessageDelta, Delta: "Hi"})
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("respects configured levels", func(t *testing.T) {
+		buf.Reset()
+		quiet := slog.New(slog.NewTextHandler(&buf, nil))
+		Log(ctx, quiet, levels, Event{Type: StepStart, Step: 1})
+		assert.Empty(t, buf.String())
+
+		levels := DefaultLogLevels()
+		levels.Step = slog.LevelInfo
+		Log(ctx, quiet, levels, Event{Type: StepStart, Step: 1})
+		assert.Contains(t, buf.String(), "step started")
+	})
+}
+
+func TestLogStream(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	ch := make(chan Event, 2)
+	ch <- Event{Type: RunStart, RunID: "run-1"}
+	ch <- Event{Type: RunEnd, Message: "complete"}
+	close(ch)
+
+	var types []Type
+	for e := range LogStream(context.Background(), logger, DefaultLogLevels(), ch) {
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []Type{RunStart, RunEnd}, types)
+	assert.Contains(t, buf.String(), "run_id=run-1")
+	assert.Contains(t, buf.String(), "message=complete")
+}
@@ -0,0 +1,361 @@
+package event
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Patch errors.
+var (
+	// ErrInvalidPatch is returned by ApplyPatches for an operation that
+	// cannot be applied, such as one whose path does not exist.
+	ErrInvalidPatch = errors.New("event: invalid patch")
+
+	// ErrPatchTestFailed is returned by ApplyPatches when a test operation
+	// does not match the document.
+	ErrPatchTestFailed = errors.New("event: patch test failed")
+)
+
+// ApplyPatches applies patches to doc as described by RFC 6902 and returns
+// the resulting document. The document and patch values are converted to
+// their generic JSON form first (maps, slices, strings, float64s, bools, and
+// nil), so doc may be any value that encodes as JSON and is never modified.
+//
+// Patches are applied in order. If one fails, ApplyPatches returns an error
+// wrapping ErrInvalidPatch or ErrPatchTestFailed and no document.
+func ApplyPatches(doc any, patches []JSONPatch) (any, error) {
+	result, err := toJSONValue(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	for i, p := range patches {
+		result, err = applyPatch(result, p)
+		if err != nil {
+			return nil, fmt.Errorf("patch %d (%s %s): %w", i, p.Op, p.Path, err)
+		}
+	}
+	return result, nil
+}
+
+// Diff returns the patches that turn from into to. Both are compared in
+// their generic JSON form: objects key by key, in sorted order, and arrays
+// element by element, so only changed leaves are patched. Diff emits only
+// add, remove, and replace operations, and returns no patches for equal
+// values. Values that cannot be encoded as JSON are compared as they are.
+//
+// Applying the result to from with ApplyPatches yields to.
+func Diff(from, to any) []JSONPatch {
+	if v, err := toJSONValue(from); err == nil {
+		from = v
+	}
+	if v, err := toJSONValue(to); err == nil {
+		to = v
+	}
+	return diff("", from, to, nil)
+}
+
+// diff appends to patches the operations turning from into to at path.
+func diff(path string, from, to any, patches []JSONPatch) []JSONPatch {
+	switch o := from.(type) {
+	case map[string]any:
+		n, ok := to.(map[string]any)
+		if !ok {
+			break
+		}
+		for _, k := range sortedKeys(o) {
+			if _, ok := n[k]; !ok {
+				patches = append(patches, Remove(path+"/"+escapePointer(k)))
+			}
+		}
+		for _, k := range sortedKeys(n) {
+			child := path + "/" + escapePointer(k)
+			if ov, ok := o[k]; ok {
+				patches = diff(child, ov, n[k], patches)
+			} else {
+				patches = append(patches, Add(child, n[k]))
+			}
+		}
+		return patches
+	case []any:
+		n, ok := to.([]any)
+		if !ok {
+			break
+		}
+		common := min(len(o), len(n))
+		for i := range common {
+			patches = diff(path+"/"+strconv.Itoa(i), o[i], n[i], patches)
+		}
+		for i := common; i < len(n); i++ {
+			patches = append(patches, Add(path+"/"+strconv.Itoa(i), n[i]))
+		}
+		// Remove from the end so earlier indices stay valid
+		for i := len(o) - 1; i >= common; i-- {
+			patches = append(patches, Remove(path+"/"+strconv.Itoa(i)))
+		}
+		return patches
+	}
+
+	if reflect.DeepEqual(from, to) {
+		return patches
+	}
+	if path == "" && from == nil {
+		return append(patches, Add("", to))
+	}
+	return append(patches, Replace(path, to))
+}
+
+// applyPatch applies a single operation to doc, which is in generic JSON
+// form and may be modified.
+func applyPatch(doc any, p JSONPatch) (any, error) {
+	tokens, err := parsePointer(p.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch p.Op {
+	case PatchAdd, PatchReplace, PatchTest:
+		value, err := toJSONValue(p.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		switch p.Op {
+		case PatchAdd:
+			return addValue(doc, tokens, value)
+		case PatchReplace:
+			return edit(doc, tokens, func(parent any, key string) (any, error) {
+				return replaceIn(parent, key, value)
+			}, func() (any, error) { return value, nil })
+		default:
+			current, err := getValue(doc, tokens)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, ErrPatchTestFailed
+			}
+			return doc, nil
+		}
+
+	case PatchRemove:
+		doc, _, err := removeValue(doc, tokens)
+		return doc, err
+
+	case PatchMove, PatchCopy:
+		from, err := parsePointer(p.From)
+		if err != nil {
+			return nil, err
+		}
+		if p.Op == PatchMove {
+			if p.From == p.Path {
+				return doc, nil
+			}
+			if strings.HasPrefix(p.Path, p.From+"/") {
+				return nil, fmt.Errorf("%w: cannot move %s into its own child", ErrInvalidPatch, p.From)
+			}
+			doc, value, err := removeValue(doc, from)
+			if err != nil {
+				return nil, err
+			}
+			return addValue(doc, tokens, value)
+		}
+		value, err := getValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		// Copy through JSON so the two locations do not share containers
+		if value, err = toJSONValue(value); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		return addValue(doc, tokens, value)
+	}
+	return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, p.Op)
+}
+
+// addValue adds value at tokens, inserting into arrays.
+func addValue(doc any, tokens []string, value any) (any, error) {
+	return edit(doc, tokens, func(parent any, key string) (any, error) {
+		switch c := parent.(type) {
+		case map[string]any:
+			c[key] = value
+			return c, nil
+		case []any:
+			if key == "-" {
+				return append(c, value), nil
+			}
+			i, err := arrayIndex(key, len(c)+1)
+			if err != nil {
+				return nil, err
+			}
+			c = append(c, nil)
+			copy(c[i+1:], c[i:])
+			c[i] = value
+			return c, nil
+		}
+		return nil, fmt.Errorf("%w: cannot add %q to a %T", ErrInvalidPatch, key, parent)
+	}, func() (any, error) { return value, nil })
+}
+
+// removeValue removes the value at tokens and returns it.
+func removeValue(doc any, tokens []string) (any, any, error) {
+	var removed any
+	doc, err := edit(doc, tokens, func(parent any, key string) (any, error) {
+		switch c := parent.(type) {
+		case map[string]any:
+			v, ok := c[key]
+			if !ok {
+				return nil, fmt.Errorf("%w: no member %q", ErrInvalidPatch, key)
+			}
+			removed = v
+			delete(c, key)
+			return c, nil
+		case []any:
+			i, err := arrayIndex(key, len(c))
+			if err != nil {
+				return nil, err
+			}
+			removed = c[i]
+			return append(c[:i], c[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("%w: cannot remove %q from a %T", ErrInvalidPatch, key, parent)
+	}, func() (any, error) {
+		removed = doc
+		return nil, nil
+	})
+	return doc, removed, err
+}
+
+// replaceIn replaces the existing member key of parent with value.
+func replaceIn(parent any, key string, value any) (any, error) {
+	switch c := parent.(type) {
+	case map[string]any:
+		if _, ok := c[key]; !ok {
+			return nil, fmt.Errorf("%w: no member %q", ErrInvalidPatch, key)
+		}
+		c[key] = value
+		return c, nil
+	case []any:
+		i, err := arrayIndex(key, len(c))
+		if err != nil {
+			return nil, err
+		}
+		c[i] = value
+		return c, nil
+	}
+	return nil, fmt.Errorf("%w: cannot replace %q in a %T", ErrInvalidPatch, key, parent)
+}
+
+// edit walks doc to the parent of the location at tokens and calls fn with
+// the parent and the last token. fn returns the updated parent, which is
+// stored back in its own parent. For the root location, root is called
+// instead to produce the new document.
+func edit(doc any, tokens []string, fn func(parent any, key string) (any, error), root func() (any, error)) (any, error) {
+	if len(tokens) == 0 {
+		return root()
+	}
+	if len(tokens) == 1 {
+		return fn(doc, tokens[0])
+	}
+
+	child, err := getValue(doc, tokens[:1])
+	if err != nil {
+		return nil, err
+	}
+	child, err = edit(child, tokens[1:], fn, root)
+	if err != nil {
+		return nil, err
+	}
+	switch c := doc.(type) {
+	case map[string]any:
+		c[tokens[0]] = child
+	case []any:
+		i, _ := arrayIndex(tokens[0], len(c))
+		c[i] = child
+	}
+	return doc, nil
+}
+
+// getValue returns the value at tokens.
+func getValue(doc any, tokens []string) (any, error) {
+	for _, key := range tokens {
+		switch c := doc.(type) {
+		case map[string]any:
+			v, ok := c[key]
+			if !ok {
+				return nil, fmt.Errorf("%w: no member %q", ErrInvalidPatch, key)
+			}
+			doc = v
+		case []any:
+			i, err := arrayIndex(key, len(c))
+			if err != nil {
+				return nil, err
+			}
+			doc = c[i]
+		default:
+			return nil, fmt.Errorf("%w: cannot index a %T with %q", ErrInvalidPatch, doc, key)
+		}
+	}
+	return doc, nil
+}
+
+// arrayIndex parses key as an array index below limit.
+func arrayIndex(key string, limit int) (int, error) {
+	i, err := strconv.Atoi(key)
+	if err != nil || i < 0 || (len(key) > 1 && key[0] == '0') || key[0] == '+' {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrInvalidPatch, key)
+	}
+	if i >= limit {
+		return 0, fmt.Errorf("%w: array index %d out of bounds", ErrInvalidPatch, i)
+	}
+	return i, nil
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into unescaped tokens.
+func parsePointer(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if path[0] != '/' {
+		return nil, fmt.Errorf("%w: path %q must start with /", ErrInvalidPatch, path)
+	}
+	tokens := strings.Split(path[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(t)
+	}
+	return tokens, nil
+}
+
+// escapePointer escapes a key for use as a JSON Pointer reference token.
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// toJSONValue converts v to its generic JSON form.
+func toJSONValue(v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,119 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPatches(t *testing.T) {
+	doc := map[string]any{
+		"foo":  "bar",
+		"list": []any{"a", "b"},
+		"nested": map[string]any{
+			"a/b": 1,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		patches []JSONPatch
+		want    any
+	}{
+		{
+			name:    "add member",
+			patches: []JSONPatch{Add("/baz", "qux")},
+			want:    map[string]any{"foo": "bar", "baz": "qux", "list": []any{"a", "b"}, "nested": map[string]any{"a/b": float64(1)}},
+		},
+		{
+			name:    "insert and append to array",
+			patches: []JSONPatch{Add("/list/1", "x"), Add("/list/-", "z")},
+			want:    map[string]any{"foo": "bar", "list": []any{"a", "x", "b", "z"}, "nested": map[string]any{"a/b": float64(1)}},
+		},
+		{
+			name:    "remove and replace",
+			patches: []JSONPatch{Remove("/list/0"), Replace("/nested/a~1b", 2)},
+			want:    map[string]any{"foo": "bar", "list": []any{"b"}, "nested": map[string]any{"a/b": float64(2)}},
+		},
+		{
+			name:    "move and copy",
+			patches: []JSONPatch{Move("/foo", "/nested/foo"), Copy("/list", "/copy")},
+			want:    map[string]any{"list": []any{"a", "b"}, "copy": []any{"a", "b"}, "nested": map[string]any{"a/b": float64(1), "foo": "bar"}},
+		},
+		{
+			name:    "test passes",
+			patches: []JSONPatch{Test("/list", []string{"a", "b"}), Test("/nested/a~1b", 1)},
+			want:    map[string]any{"foo": "bar", "list": []any{"a", "b"}, "nested": map[string]any{"a/b": float64(1)}},
+		},
+		{
+			name:    "replace root",
+			patches: []JSONPatch{Replace("", []int{1})},
+			want:    []any{float64(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyPatches(doc, tt.patches)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Equal(t, []any{"a", "b"}, doc["list"], "input document modified")
+}
+
+func TestApplyPatchesErrors(t *testing.T) {
+	doc := map[string]any{"foo": "bar", "list": []any{"a"}}
+
+	tests := []struct {
+		name  string
+		patch JSONPatch
+		err   error
+	}{
+		{"missing parent", Add("/a/b", 1), ErrInvalidPatch},
+		{"replace missing member", Replace("/baz", 1), ErrInvalidPatch},
+		{"remove out of bounds", Remove("/list/1"), ErrInvalidPatch},
+		{"leading zero index", Add("/list/01", "x"), ErrInvalidPatch},
+		{"path without slash", Add("foo", 1), ErrInvalidPatch},
+		{"move into child", Move("/list", "/list/0"), ErrInvalidPatch},
+		{"unknown op", JSONPatch{Op: "merge", Path: "/foo"}, ErrInvalidPatch},
+		{"test mismatch", Test("/foo", "baz"), ErrPatchTestFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ApplyPatches(doc, []JSONPatch{tt.patch})
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	type state struct {
+		Progress int               `json:"progress"`
+		Items    []string          `json:"items"`
+		Labels   map[string]string `json:"labels,omitempty"`
+	}
+
+	from := state{Progress: 10, Items: []string{"a", "b", "c"}}
+	to := state{Progress: 50, Items: []string{"a", "x"}, Labels: map[string]string{"a/b": "1"}}
+
+	patches := Diff(from, to)
+	assert.Equal(t, []JSONPatch{
+		Replace("/items/1", "x"),
+		Remove("/items/2"),
+		Add("/labels", map[string]any{"a/b": "1"}),
+		Replace("/progress", float64(50)),
+	}, patches)
+
+	got, err := ApplyPatches(from, patches)
+	require.NoError(t, err)
+	want, _ := toJSONValue(to)
+	assert.Equal(t, want, got)
+
+	assert.Empty(t, Diff(from, from))
+	assert.Equal(t, []JSONPatch{Add("", map[string]any{"a": float64(1)})}, Diff(nil, map[string]int{"a": 1}))
+	assert.Equal(t, []JSONPatch{Replace("/a", "x")}, Diff(map[string]any{"a": 1}, map[string]any{"a": "x"}))
+}
@@ -0,0 +1,156 @@
+package gains
+
+import "reflect"
+
+// HistoryDiff describes where two message histories diverge.
+type HistoryDiff struct {
+	// Common is the number of leading messages the histories share.
+	Common int
+	// Removed are the messages of the first history after the shared ones.
+	Removed []Message
+	// Added are the messages of the second history after the shared ones.
+	Added []Message
+}
+
+// Appends reports whether the second history only adds messages to the
+// first, as a resubmission that continues a conversation does.
+func (d HistoryDiff) Appends() bool {
+	return len(d.Removed) == 0
+}
+
+// DiffHistory compares two message histories and returns where to diverges
+// from from. Messages that both carry an ID are the same message if their
+// IDs match; others are compared by value.
+func DiffHistory(from, to []Message) HistoryDiff {
+	n := 0
+	for n < len(from) && n < len(to) && sameMessage(from[n], to[n]) {
+		n++
+	}
+	return HistoryDiff{Common: n, Removed: from[n:], Added: to[n:]}
+}
+
+// MergeHistory merges two branches of a conversation, such as the history
+// a server kept and the one a client resubmitted after running its tools.
+// The result is deterministic:
+//   - the shared messages and the rest of ours come first
+//   - messages only in theirs follow, in their order
+//   - a tool call keeps the first result it got, from ours before theirs
+//   - tool results are moved after the message with their call, as
+//     OrderToolResults does
+//
+// Neither input is modified.
+func MergeHistory(ours, theirs []Message) []Message {
+	d := DiffHistory(ours, theirs)
+	merged := append([]Message(nil), ours...)
+
+	answered := make(map[string]bool)
+	for _, msg := range ours {
+		for _, tr := range msg.ToolResults {
+			answered[tr.ToolCallID] = true
+		}
+	}
+
+	for _, msg := range d.Added {
+		if containsMessage(d.Removed, msg) {
+			continue
+		}
+		if len(msg.ToolResults) > 0 {
+			results := make([]ToolResult, 0, len(msg.ToolResults))
+			for _, tr := range msg.ToolResults {
+				if !answered[tr.ToolCallID] {
+					results = append(results, tr)
+					answered[tr.ToolCallID] = true
+				}
+			}
+			if len(results) == 0 {
+				continue
+			}
+			msg.ToolResults = results
+		}
+		merged = append(merged, msg)
+	}
+	return OrderToolResults(merged)
+}
+
+// OrderToolResults returns messages with tool results delivered out of
+// order put back in place, without modifying the input. The results for
+// the calls of an assistant message are gathered into one tool message
+// right after it, in the order of the calls; the tool message takes its
+// ID, name, and metadata from the first message holding one of them. A
+// call answered twice keeps its first result. Results that answer no call
+// in messages stay where they are.
+func OrderToolResults(messages []Message) []Message {
+	called := make(map[string]bool)
+	for _, msg := range messages {
+		for _, tc := range msg.ToolCalls {
+			called[tc.ID] = true
+		}
+	}
+
+	// The first result for each call, and the message it came in
+	results := make(map[string]ToolResult)
+	sources := make(map[string]Message)
+	for _, msg := range messages {
+		for _, tr := range msg.ToolResults {
+			if _, seen := results[tr.ToolCallID]; called[tr.ToolCallID] && !seen {
+				results[tr.ToolCallID] = tr
+				sources[tr.ToolCallID] = msg
+			}
+		}
+	}
+
+	ordered := make([]Message, 0, len(messages))
+	for _, msg := range messages {
+		if len(msg.ToolResults) > 0 {
+			// Keep only the results that answer no call
+			var orphans []ToolResult
+			for _, tr := range msg.ToolResults {
+				if !called[tr.ToolCallID] {
+					orphans = append(orphans, tr)
+				}
+			}
+			msg.ToolResults = orphans
+			if isEmptyMessage(msg) {
+				continue
+			}
+		}
+		ordered = append(ordered, msg)
+
+		var slot *Message
+		for _, tc := range msg.ToolCalls {
+			tr, ok := results[tc.ID]
+			if !ok {
+				continue
+			}
+			if slot == nil {
+				src := sources[tc.ID]
+				slot = &Message{ID: src.ID, Role: RoleTool, Name: src.Name, CreatedAt: src.CreatedAt, Metadata: src.Metadata}
+			}
+			slot.ToolResults = append(slot.ToolResults, tr)
+			delete(results, tc.ID) // a call repeated later gets no second slot
+		}
+		if slot != nil {
+			ordered = append(ordered, *slot)
+		}
+	}
+	return ordered
+}
+
+// sameMessage reports whether a and b are the same message: by ID if both
+// have one, by value otherwise.
+func sameMessage(a, b Message) bool {
+	if a.ID != "" && b.ID != "" {
+		return a.ID == b.ID
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// containsMessage reports whether messages include msg.
+func containsMessage(messages []Message, msg Message) bool {
+	for _, m := range messages {
+		if sameMessage(m, msg) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package gains
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffHistory(t *testing.T) {
+	user := Message{ID: "u1", Role: RoleUser, Content: "Hi"}
+	reply := Message{ID: "a1", Role: RoleAssistant, Content: "Hello"}
+	edited := Message{ID: "a1", Role: RoleAssistant, Content: "Hello!"}
+	next := Message{Role: RoleUser, Content: "Bye"}
+
+	d := DiffHistory([]Message{user, reply}, []Message{user, edited, next})
+	assert.Equal(t, 2, d.Common, "messages with the same ID are the same")
+	assert.Empty(t, d.Removed)
+	assert.Equal(t, []Message{next}, d.Added)
+	assert.True(t, d.Appends())
+
+	d = DiffHistory([]Message{user, reply, next}, []Message{user, {Role: RoleUser, Content: "Bye"}, reply})
+	assert.Equal(t, 1, d.Common)
+	assert.Equal(t, []Message{reply, next}, d.Removed)
+	assert.False(t, d.Appends())
+}
+
+func TestOrderToolResults(t *testing.T) {
+	call := func(id string) ToolCall { return ToolCall{ID: id, Name: "search"} }
+	result := func(id, content string) ToolResult { return ToolResult{ToolCallID: id, Content: content} }
+
+	in := []Message{
+		{Role: RoleUser, Content: "Search"},
+		{Role: RoleTool, ID: "t2", ToolResults: []ToolResult{result("2", "second"), result("0", "orphan")}},
+		{Role: RoleAssistant, ToolCalls: []ToolCall{call("1"), call("2")}},
+		{Role: RoleUser, Content: "Well?"},
+		{Role: RoleTool, ID: "t1", ToolResults: []ToolResult{result("1", "first"), result("2", "late duplicate")}},
+	}
+	assert.Equal(t, []Message{
+		{Role: RoleUser, Content: "Search"},
+		{Role: RoleTool, ID: "t2", ToolResults: []ToolResult{result("0", "orphan")}},
+		{Role: RoleAssistant, ToolCalls: []ToolCall{call("1"), call("2")}},
+		{Role: RoleTool, ID: "t1", ToolResults: []ToolResult{result("1", "first"), result("2", "second")}},
+		{Role: RoleUser, Content: "Well?"},
+	}, OrderToolResults(in))
+	assert.Len(t, in[4].ToolResults, 2, "input modified")
+}
+
+func TestMergeHistory(t *testing.T) {
+	user := Message{ID: "u1", Role: RoleUser, Content: "Delete the file"}
+	call := Message{ID: "a1", Role: RoleAssistant, ToolCalls: []ToolCall{{ID: "c1", Name: "confirm"}}}
+	result := Message{ID: "t1", Role: RoleTool, ToolResults: []ToolResult{{ToolCallID: "c1", Content: "yes"}}}
+
+	t.Run("restores the call a resubmission left out", func(t *testing.T) {
+		merged := MergeHistory([]Message{user, call}, []Message{user, result})
+		assert.Equal(t, []Message{user, call, result}, merged)
+	})
+
+	t.Run("is idempotent", func(t *testing.T) {
+		history := []Message{user, call, result}
+		assert.Equal(t, history, MergeHistory(history, history))
+	})
+
+	t.Run("keeps our result on conflict", func(t *testing.T) {
+		conflicting := Message{ID: "t2", Role: RoleTool, ToolResults: []ToolResult{{ToolCallID: "c1", Content: "no"}}}
+		followUp := Message{ID: "u2", Role: RoleUser, Content: "Thanks"}
+		merged := MergeHistory([]Message{user, call, result}, []Message{user, call, conflicting, followUp})
+		assert.Equal(t, []Message{user, call, result, followUp}, merged)
+	})
+
+	t.Run("moves results delivered before the call", func(t *testing.T) {
+		merged := MergeHistory([]Message{user, result}, []Message{user, call})
+		assert.Equal(t, []Message{user, call, result}, merged)
+	})
+}
@@ -0,0 +1,73 @@
+package gains
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IDGenerator produces unique identifiers for messages, runs, tool calls,
+// and other records. Implementations must be safe for concurrent use.
+type IDGenerator interface {
+	NewID() string
+}
+
+// IDGeneratorFunc adapts a function to the IDGenerator interface.
+type IDGeneratorFunc func() string
+
+// NewID calls f.
+func (f IDGeneratorFunc) NewID() string { return f() }
+
+// ULIDGenerator generates ULIDs: 26-character, lexicographically sortable
+// identifiers made of a millisecond timestamp and 80 random bits. It is the
+// default generator.
+var ULIDGenerator IDGenerator = IDGeneratorFunc(newULID)
+
+// UUIDGenerator generates random (version 4) UUIDs.
+var UUIDGenerator IDGenerator = IDGeneratorFunc(func() string { return uuid.New().String() })
+
+var idGenerator atomic.Pointer[IDGenerator]
+
+// SetIDGenerator replaces the generator used by NewID throughout gains, for
+// example with a deterministic sequence in tests or a format required by a
+// gateway. A nil generator restores the default ULIDGenerator.
+func SetIDGenerator(g IDGenerator) {
+	if g == nil {
+		idGenerator.Store(nil)
+		return
+	}
+	idGenerator.Store(&g)
+}
+
+// NewID returns a new identifier from the generator set with
+// SetIDGenerator, or a ULID by default.
+func NewID() string {
+	if g := idGenerator.Load(); g != nil {
+		return (*g).NewID()
+	}
+	return newULID()
+}
+
+// crockford is the Crockford base32 alphabet used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newULID returns a ULID for the current time.
+func newULID() string {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(time.Now().UnixMilli())<<16)
+	rand.Read(b[6:])
+
+	// Encode 128 bits as 26 base32 characters, most significant first
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
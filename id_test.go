@@ -0,0 +1,43 @@
+package gains
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewID(t *testing.T) {
+	t.Run("defaults to ULIDs", func(t *testing.T) {
+		id := NewID()
+		require.Len(t, id, 26)
+		for _, c := range id {
+			assert.Contains(t, crockford, string(c))
+		}
+		assert.NotEqual(t, id, NewID())
+	})
+
+	t.Run("ULIDs sort by time", func(t *testing.T) {
+		first := NewID()
+		time.Sleep(2 * time.Millisecond)
+		assert.Less(t, first, NewID())
+	})
+
+	t.Run("uses the configured generator", func(t *testing.T) {
+		var n int
+		SetIDGenerator(IDGeneratorFunc(func() string {
+			n++
+			return "id-" + string(rune('0'+n))
+		}))
+		defer SetIDGenerator(nil)
+
+		assert.Equal(t, "id-1", NewID())
+		assert.Equal(t, "msg-id-2", GenerateMessageID())
+	})
+
+	t.Run("UUID generator", func(t *testing.T) {
+		id := UUIDGenerator.NewID()
+		assert.Len(t, id, 36)
+	})
+}
@@ -0,0 +1,155 @@
+package ingest
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultSeparators are the separators a RecursiveChunker tries, from
+// paragraphs down to single characters.
+var DefaultSeparators = []string{"\n\n", "\n", ". ", " ", ""}
+
+// Chunker splits text into chunks for embedding.
+type Chunker interface {
+	// Chunk splits text into chunks. Chunks are trimmed of surrounding
+	// whitespace and empty chunks are omitted.
+	Chunk(text string) []string
+}
+
+// LengthFunc measures a piece of text, in characters, tokens, or any other
+// unit a chunk size is expressed in.
+type LengthFunc func(text string) int
+
+// RecursiveChunker splits text on the first separator that occurs in it,
+// such as paragraph breaks, and splits pieces that are still too long on
+// the next separator, such as line breaks, then sentences, words, and
+// characters. Adjacent pieces are merged into chunks of up to Size, and
+// consecutive chunks share up to Overlap of text so context spanning a
+// boundary is kept.
+type RecursiveChunker struct {
+	// Size is the maximum length of a chunk as measured by Length. A piece
+	// that cannot be split further, such as a single long word with no
+	// empty separator to fall back on, may exceed it.
+	Size int
+	// Overlap is the length of text repeated at the start of a chunk from
+	// the end of the previous one. It should be well below Size.
+	Overlap int
+	// Separators are tried in order. An empty separator splits into
+	// characters. Defaults to DefaultSeparators.
+	Separators []string
+	// Length measures text. Defaults to counting characters.
+	Length LengthFunc
+}
+
+// NewRecursiveChunker returns a chunker producing chunks of up to size
+// characters with overlap characters shared between consecutive chunks.
+func NewRecursiveChunker(size, overlap int) *RecursiveChunker {
+	return &RecursiveChunker{Size: size, Overlap: overlap}
+}
+
+// NewTokenChunker returns a chunker producing chunks of up to maxTokens
+// tokens with overlap tokens shared between consecutive chunks, splitting on
+// the same boundaries as a RecursiveChunker. count measures tokens; if it is
+// nil, EstimateTokens is used.
+func NewTokenChunker(maxTokens, overlap int, count LengthFunc) *RecursiveChunker {
+	if count == nil {
+		count = EstimateTokens
+	}
+	return &RecursiveChunker{Size: maxTokens, Overlap: overlap, Length: count}
+}
+
+// EstimateTokens approximates the token count of text at about four
+// characters per token, rounding up.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// Chunk splits text into chunks of up to Size.
+func (c *RecursiveChunker) Chunk(text string) []string {
+	separators := c.Separators
+	if len(separators) == 0 {
+		separators = DefaultSeparators
+	}
+	length := c.Length
+	if length == nil {
+		length = utf8.RuneCountInString
+	}
+	if c.Size <= 0 {
+		if text = strings.TrimSpace(text); text == "" {
+			return nil
+		}
+		return []string{text}
+	}
+	return c.split(text, separators, length)
+}
+
+// split splits text on the first separator found in it, recursing into
+// pieces longer than Size with the remaining separators.
+func (c *RecursiveChunker) split(text string, separators []string, length LengthFunc) []string {
+	sep, rest := separators[len(separators)-1], []string(nil)
+	for i, s := range separators {
+		if s == "" || strings.Contains(text, s) {
+			sep, rest = s, separators[i+1:]
+			break
+		}
+	}
+
+	var chunks, fitting []string
+	for _, piece := range strings.Split(text, sep) {
+		if length(piece) <= c.Size {
+			fitting = append(fitting, piece)
+			continue
+		}
+		if len(fitting) > 0 {
+			chunks = append(chunks, c.merge(fitting, sep, length)...)
+			fitting = nil
+		}
+		if len(rest) == 0 {
+			if piece = strings.TrimSpace(piece); piece != "" {
+				chunks = append(chunks, piece)
+			}
+			continue
+		}
+		chunks = append(chunks, c.split(piece, rest, length)...)
+	}
+	if len(fitting) > 0 {
+		chunks = append(chunks, c.merge(fitting, sep, length)...)
+	}
+	return chunks
+}
+
+// merge joins pieces with sep into chunks of up to Size, starting each
+// chunk with up to Overlap of the previous one's trailing pieces.
+func (c *RecursiveChunker) merge(pieces []string, sep string, length LengthFunc) []string {
+	sepLen := length(sep)
+	var chunks, current []string
+	total := 0
+	joined := func() int {
+		if len(current) == 0 {
+			return 0
+		}
+		return sepLen
+	}
+	for _, piece := range pieces {
+		n := length(piece)
+		if total+n+joined() > c.Size && len(current) > 0 {
+			if chunk := strings.TrimSpace(strings.Join(current, sep)); chunk != "" {
+				chunks = append(chunks, chunk)
+			}
+			// Keep trailing pieces as overlap while they fit
+			for len(current) > 0 && (total > c.Overlap || total+n+joined() > c.Size) {
+				total -= length(current[0])
+				if len(current) > 1 {
+					total -= sepLen
+				}
+				current = current[1:]
+			}
+		}
+		total += n + joined()
+		current = append(current, piece)
+	}
+	if chunk := strings.TrimSpace(strings.Join(current, sep)); chunk != "" {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
@@ -0,0 +1,81 @@
+package ingest
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecursiveChunker(t *testing.T) {
+	t.Run("short text is one chunk", func(t *testing.T) {
+		assert.Equal(t, []string{"Hello world."}, NewRecursiveChunker(100, 10).Chunk("  Hello world.\n"))
+	})
+
+	t.Run("empty text has no chunks", func(t *testing.T) {
+		assert.Empty(t, NewRecursiveChunker(100, 10).Chunk(" \n\n "))
+	})
+
+	t.Run("paragraphs are kept together", func(t *testing.T) {
+		text := "First paragraph here.\n\nSecond paragraph here.\n\nThird one."
+		chunks := NewRecursiveChunker(45, 0).Chunk(text)
+		assert.Equal(t, []string{
+			"First paragraph here.\n\nSecond paragraph here.",
+			"Third one.",
+		}, chunks)
+	})
+
+	t.Run("long paragraphs fall back to words", func(t *testing.T) {
+		text := "one two three four five six seven eight nine ten"
+		chunks := NewRecursiveChunker(15, 0).Chunk(text)
+		assert.Equal(t, []string{"one two three", "four five six", "seven eight", "nine ten"}, chunks)
+	})
+
+	t.Run("overlap repeats trailing words", func(t *testing.T) {
+		text := "one two three four five six seven eight nine ten"
+		chunks := NewRecursiveChunker(15, 6).Chunk(text)
+		assert.Equal(t, []string{"one two three", "three four five", "five six seven", "seven eight", "eight nine ten"}, chunks)
+	})
+
+	t.Run("long words fall back to characters", func(t *testing.T) {
+		chunks := NewRecursiveChunker(4, 0).Chunk("abcdefghij")
+		assert.Equal(t, []string{"abcd", "efgh", "ij"}, chunks)
+	})
+
+	t.Run("sizes count characters, not bytes", func(t *testing.T) {
+		chunks := NewRecursiveChunker(5, 0).Chunk("héllo wörld")
+		assert.Equal(t, []string{"héllo", "wörld"}, chunks)
+	})
+
+	t.Run("every chunk fits", func(t *testing.T) {
+		text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 40)
+		for _, chunk := range NewRecursiveChunker(120, 30).Chunk(text) {
+			assert.LessOrEqual(t, utf8.RuneCountInString(chunk), 120)
+		}
+	})
+
+	t.Run("custom separators", func(t *testing.T) {
+		c := &RecursiveChunker{Size: 3, Separators: []string{"|"}}
+		assert.Equal(t, []string{"a|b", "cde", "toolong"}, c.Chunk("a|b|cde|toolong"))
+	})
+
+	t.Run("no size keeps the text whole", func(t *testing.T) {
+		assert.Equal(t, []string{"abc def"}, NewRecursiveChunker(0, 0).Chunk("abc def"))
+	})
+}
+
+func TestTokenChunker(t *testing.T) {
+	assert.Equal(t, 0, EstimateTokens(""))
+	assert.Equal(t, 1, EstimateTokens("abc"))
+	assert.Equal(t, 2, EstimateTokens("abcde"))
+
+	words := func(s string) int { return len(strings.Fields(s)) }
+	chunks := NewTokenChunker(3, 1, words).Chunk("a b c d e f g")
+	assert.Equal(t, []string{"a b c", "c d e", "e f g"}, chunks)
+
+	text := strings.Repeat("word ", 200)
+	for _, chunk := range NewTokenChunker(20, 4, nil).Chunk(text) {
+		assert.LessOrEqual(t, EstimateTokens(chunk), 20)
+	}
+}
@@ -0,0 +1,33 @@
+// Package ingest splits documents into chunks and loads them into a vector
+// store for retrieval-augmented generation.
+//
+// # Chunking
+//
+// A RecursiveChunker splits text on paragraph breaks, then line breaks,
+// sentences, words, and characters until every chunk fits its size, and
+// repeats some text between consecutive chunks so ideas spanning a
+// boundary stay retrievable. Sizes are in characters by default; a token
+// chunker measures them in tokens, to stay within an embedding model's
+// input limit:
+//
+//	chunker := ingest.NewRecursiveChunker(1000, 200)
+//	chunker := ingest.NewTokenChunker(256, 32, nil) // estimated tokens
+//
+// Split turns a Document into chunks that carry its metadata, plus the
+// source document ID and chunk position.
+//
+// # Pipelines
+//
+// A Pipeline chunks, embeds, and upserts documents in one call, batching
+// embedding requests:
+//
+//	store := vectorstore.NewMemory()
+//	p := ingest.NewPipeline(ingest.NewTokenChunker(256, 32, nil), c, store)
+//	result, err := p.Ingest(ctx, ingest.Document{
+//	    ID:       "handbook.md",
+//	    Text:     text,
+//	    Metadata: map[string]string{"team": "support"},
+//	})
+//
+// The stored chunks can then be retrieved with workflow.NewRetrievalStep.
+package ingest
@@ -0,0 +1,164 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/vectorstore"
+)
+
+// Metadata keys set on every chunk.
+const (
+	// MetadataSource holds the ID of the document a chunk came from.
+	MetadataSource = "source"
+	// MetadataChunk holds the position of a chunk in its document,
+	// starting at 0.
+	MetadataChunk = "chunk"
+)
+
+// ErrMissingID indicates a document without an ID.
+var ErrMissingID = errors.New("ingest: document has no ID")
+
+// Document is a text to ingest.
+type Document struct {
+	// ID identifies the document. Chunk IDs are derived from it.
+	ID string
+	// Text is the content to chunk.
+	Text string
+	// Metadata is copied to every chunk of the document.
+	Metadata map[string]string
+}
+
+// ChunkID returns the ID of the chunk at index of the document docID.
+func ChunkID(docID string, index int) string {
+	return docID + "#" + strconv.Itoa(index)
+}
+
+// Split chunks a document into vector store documents without vectors.
+// Each chunk carries the document's metadata plus MetadataSource and
+// MetadataChunk, and has the ID ChunkID(doc.ID, index).
+func Split(doc Document, chunker Chunker) []vectorstore.Document {
+	texts := chunker.Chunk(doc.Text)
+	chunks := make([]vectorstore.Document, len(texts))
+	for i, text := range texts {
+		metadata := maps.Clone(doc.Metadata)
+		if metadata == nil {
+			metadata = make(map[string]string, 2)
+		}
+		metadata[MetadataSource] = doc.ID
+		metadata[MetadataChunk] = strconv.Itoa(i)
+		chunks[i] = vectorstore.Document{
+			ID:       ChunkID(doc.ID, i),
+			Content:  text,
+			Metadata: metadata,
+		}
+	}
+	return chunks
+}
+
+// PipelineOption configures a Pipeline.
+type PipelineOption func(*Pipeline)
+
+// WithBatchSize sets how many chunks are embedded and upserted per request.
+// Default is 100.
+func WithBatchSize(n int) PipelineOption {
+	return func(p *Pipeline) {
+		p.batchSize = n
+	}
+}
+
+// WithEmbeddingOptions sets options for embedding chunks, such as the
+// embedding model. The task type defaults to
+// ai.EmbeddingTaskTypeRetrievalDocument.
+func WithEmbeddingOptions(opts ...ai.EmbeddingOption) PipelineOption {
+	return func(p *Pipeline) {
+		p.embeddingOpts = append(p.embeddingOpts, opts...)
+	}
+}
+
+// Pipeline chunks documents, embeds the chunks, and upserts them into a
+// vector store.
+type Pipeline struct {
+	chunker       Chunker
+	embedder      ai.EmbeddingProvider
+	store         vectorstore.Store
+	batchSize     int
+	embeddingOpts []ai.EmbeddingOption
+}
+
+// NewPipeline returns a pipeline that splits documents with chunker, embeds
+// the chunks with embedder, such as a client.Client, and stores them in
+// store.
+func NewPipeline(chunker Chunker, embedder ai.EmbeddingProvider, store vectorstore.Store, opts ...PipelineOption) *Pipeline {
+	p := &Pipeline{
+		chunker:   chunker,
+		embedder:  embedder,
+		store:     store,
+		batchSize: 100,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Result summarizes an ingestion.
+type Result struct {
+	// Documents is the number of documents ingested.
+	Documents int
+	// Chunks is the number of chunks stored.
+	Chunks int
+	// Usage is the token usage of the embedding requests.
+	Usage ai.Usage
+}
+
+// Ingest chunks, embeds, and stores documents. Chunks replace stored chunks
+// with the same ID, so ingesting a document again updates it; remove
+// leftover chunks of a document that became shorter with Store.Delete.
+//
+// On error, the chunks of earlier batches remain stored and the result
+// counts them.
+func (p *Pipeline) Ingest(ctx context.Context, docs ...Document) (*Result, error) {
+	result := &Result{}
+	var chunks []vectorstore.Document
+	for _, doc := range docs {
+		if doc.ID == "" {
+			return result, ErrMissingID
+		}
+		chunks = append(chunks, Split(doc, p.chunker)...)
+	}
+
+	embeddingOpts := append([]ai.EmbeddingOption{ai.WithEmbeddingTaskType(ai.EmbeddingTaskTypeRetrievalDocument)}, p.embeddingOpts...)
+	batchSize := max(p.batchSize, 1)
+	for start := 0; start < len(chunks); start += batchSize {
+		batch := chunks[start:min(start+batchSize, len(chunks))]
+		texts := make([]string, len(batch))
+		for i, c := range batch {
+			texts[i] = c.Content
+		}
+
+		resp, err := p.embedder.Embed(ctx, texts, embeddingOpts...)
+		if err != nil {
+			return result, fmt.Errorf("ingest: embed chunks: %w", err)
+		}
+		if len(resp.Embeddings) != len(batch) {
+			return result, fmt.Errorf("ingest: embed chunks: got %d embeddings for %d chunks", len(resp.Embeddings), len(batch))
+		}
+		result.Usage.InputTokens += resp.Usage.InputTokens
+		result.Usage.OutputTokens += resp.Usage.OutputTokens
+		for i := range batch {
+			batch[i].Vector = resp.Embeddings[i]
+		}
+
+		if err := p.store.Upsert(ctx, batch...); err != nil {
+			return result, fmt.Errorf("ingest: store chunks: %w", err)
+		}
+		result.Chunks += len(batch)
+	}
+	result.Documents = len(docs)
+	return result, nil
+}
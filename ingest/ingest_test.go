@@ -0,0 +1,81 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/vectorstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lengthEmbedder embeds each text as a vector of its length and records
+// the size of each request.
+type lengthEmbedder struct {
+	batches []int
+	opts    *ai.EmbeddingOptions
+	err     error
+}
+
+func (e *lengthEmbedder) Embed(ctx context.Context, texts []string, opts ...ai.EmbeddingOption) (*ai.EmbeddingResponse, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	e.batches = append(e.batches, len(texts))
+	e.opts = ai.ApplyEmbeddingOptions(opts...)
+	resp := &ai.EmbeddingResponse{Usage: ai.Usage{InputTokens: len(texts)}}
+	for _, text := range texts {
+		resp.Embeddings = append(resp.Embeddings, []float64{float64(len(text)), 1})
+	}
+	return resp, nil
+}
+
+func TestSplit(t *testing.T) {
+	metadata := map[string]string{"team": "support"}
+	chunks := Split(Document{ID: "doc", Text: "one two six four", Metadata: metadata}, NewRecursiveChunker(8, 0))
+	require.Len(t, chunks, 2)
+	assert.Equal(t, vectorstore.Document{
+		ID:       "doc#0",
+		Content:  "one two",
+		Metadata: map[string]string{"team": "support", MetadataSource: "doc", MetadataChunk: "0"},
+	}, chunks[0])
+	assert.Equal(t, "doc#1", chunks[1].ID)
+	assert.Equal(t, "1", chunks[1].Metadata[MetadataChunk])
+	assert.Equal(t, map[string]string{"team": "support"}, metadata)
+}
+
+func TestPipeline_Ingest(t *testing.T) {
+	ctx := context.Background()
+	store := vectorstore.NewMemory()
+	embedder := &lengthEmbedder{}
+	p := NewPipeline(NewRecursiveChunker(8, 0), embedder, store, WithBatchSize(2))
+
+	result, err := p.Ingest(ctx,
+		Document{ID: "a", Text: "one two six four"},
+		Document{ID: "b", Text: "five six", Metadata: map[string]string{"lang": "en"}},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, &Result{Documents: 2, Chunks: 3, Usage: ai.Usage{InputTokens: 3}}, result)
+	assert.Equal(t, []int{2, 1}, embedder.batches)
+	assert.Equal(t, ai.EmbeddingTaskTypeRetrievalDocument, embedder.opts.TaskType)
+	assert.Equal(t, 3, store.Len())
+
+	matches, err := store.Query(ctx, []float64{8, 1}, 1, vectorstore.WithFilter(map[string]string{"lang": "en"}))
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "b#0", matches[0].ID)
+	assert.Equal(t, "five six", matches[0].Content)
+	assert.Equal(t, []float64{8, 1}, matches[0].Vector)
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := p.Ingest(ctx, Document{Text: "no id"})
+		assert.ErrorIs(t, err, ErrMissingID)
+
+		failing := NewPipeline(NewRecursiveChunker(8, 0), &lengthEmbedder{err: errors.New("rate limited")}, store)
+		result, err := failing.Ingest(ctx, Document{ID: "c", Text: "text"})
+		assert.ErrorContains(t, err, "rate limited")
+		assert.Equal(t, 0, result.Chunks)
+	})
+}
@@ -0,0 +1,49 @@
+// Package apikey authenticates HTTP requests that carry an API key as a
+// bearer token, for the handlers of the serve and apiserver packages.
+package apikey
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+// Keys is a set of API keys. The zero value holds no keys and authorizes
+// every request.
+type Keys struct {
+	keys []string
+}
+
+// Add adds keys to the set. Empty keys are ignored, so a key read from an
+// unset environment variable never matches an empty bearer token.
+func (k *Keys) Add(keys ...string) {
+	for _, key := range keys {
+		if key != "" {
+			k.keys = append(k.keys, key)
+		}
+	}
+}
+
+// Enabled reports whether the set holds any keys, and so whether requests
+// are authenticated.
+func (k *Keys) Enabled() bool {
+	return len(k.keys) > 0
+}
+
+// Authorized reports whether r carries one of the keys as a bearer token,
+// or true if the set holds no keys.
+func (k *Keys) Authorized(r *http.Request) bool {
+	if !k.Enabled() {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return false
+	}
+	for _, key := range k.keys {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(key)) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package apikey
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeys_Authorized(t *testing.T) {
+	request := func(header string) bool {
+		var k Keys
+		k.Add("", "secret", "")
+		r := httptest.NewRequest("GET", "/", nil)
+		if header != "" {
+			r.Header.Set("Authorization", header)
+		}
+		return k.Authorized(r)
+	}
+
+	assert.True(t, request("Bearer secret"))
+	assert.False(t, request("Bearer wrong"))
+	assert.False(t, request("Bearer "), "empty keys are ignored")
+	assert.False(t, request("secret"))
+	assert.False(t, request(""))
+
+	t.Run("no keys", func(t *testing.T) {
+		var k Keys
+		k.Add("")
+		assert.False(t, k.Enabled())
+		assert.True(t, k.Authorized(httptest.NewRequest("GET", "/", nil)))
+	})
+}
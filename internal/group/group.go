@@ -0,0 +1,126 @@
+// Package group runs related goroutines with bounded concurrency, shared
+// cancellation, and panic recovery.
+//
+// Its API follows golang.org/x/sync/errgroup: a Group started with
+// WithContext cancels its context when the first goroutine returns an error,
+// SetLimit bounds how many goroutines run at once, and Wait returns the first
+// error. Unlike errgroup, a panicking goroutine does not crash the process;
+// the panic is recovered and reported as a *PanicError.
+package group
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+
+	"github.com/spetersoncode/gains"
+)
+
+// PanicError reports a panic recovered from a goroutine or function.
+type PanicError = gains.PanicError
+
+// Call runs fn and converts a panic in fn into a *PanicError.
+func Call(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = Recovered(r)
+		}
+	}()
+	return fn()
+}
+
+// Recovered wraps a value returned by recover in a *PanicError with the
+// current stack. Call it from the deferred function that recovered.
+func Recovered(r any) *PanicError {
+	return &PanicError{Value: r, Stack: debug.Stack()}
+}
+
+// Group is a collection of goroutines working on subtasks of a common task.
+// The zero value is a valid Group with no limit that does not cancel on error.
+type Group struct {
+	cancel context.CancelCauseFunc
+
+	wg      sync.WaitGroup
+	sem     chan struct{}
+	limiter *gains.ConcurrencyLimiter
+
+	errOnce sync.Once
+	err     error
+}
+
+// WithContext returns a new Group and a derived context. The context is
+// cancelled the first time a goroutine in the group returns an error, with
+// that error as its cause, or when Wait returns, whichever occurs first.
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// SetLimit limits the number of goroutines active in the group to n.
+// A value of 0 or less removes the limit. It must not be called while
+// goroutines in the group are active.
+func (g *Group) SetLimit(n int) {
+	if n <= 0 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// SetLimiter shares l, the run-wide limiter of the group's context, with
+// the group. When l has no free slot, Go runs fn in the calling goroutine
+// instead of starting a new one. A nil l has no effect. It must not be
+// called while goroutines in the group are active.
+func (g *Group) SetLimiter(l *gains.ConcurrencyLimiter) {
+	g.limiter = l
+}
+
+// Go calls fn in a new goroutine, blocking until a slot is free if the group
+// has a limit. If the group's run-wide limiter has no free slot, fn runs in
+// the calling goroutine before Go returns. The first non-nil error,
+// including a recovered panic, cancels the group's context and is returned
+// by Wait.
+func (g *Group) Go(fn func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.wg.Add(1)
+	if !g.limiter.TryAcquire() {
+		g.run(fn)
+		return
+	}
+	go func() {
+		defer g.limiter.Release()
+		g.run(fn)
+	}()
+}
+
+// run calls fn, recording its error, and marks it done.
+func (g *Group) run(fn func() error) {
+	defer g.done()
+	if err := Call(fn); err != nil {
+		g.errOnce.Do(func() {
+			g.err = err
+			if g.cancel != nil {
+				g.cancel(err)
+			}
+		})
+	}
+}
+
+// Wait blocks until all goroutines started with Go have returned, then
+// returns the first error from them, if any.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	return g.err
+}
+
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
@@ -0,0 +1,145 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCall(t *testing.T) {
+	t.Run("returns the function error", func(t *testing.T) {
+		err := Call(func() error { return assert.AnError })
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("converts a panic into PanicError", func(t *testing.T) {
+		err := Call(func() error { panic("boom") })
+
+		var pe *PanicError
+		require.ErrorAs(t, err, &pe)
+		assert.Equal(t, "boom", pe.Value)
+		assert.Equal(t, "panic: boom", pe.Error())
+		assert.NotEmpty(t, pe.Stack)
+	})
+
+	t.Run("unwraps an error panic value", func(t *testing.T) {
+		err := Call(func() error { panic(assert.AnError) })
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestGroup(t *testing.T) {
+	t.Run("waits for all goroutines", func(t *testing.T) {
+		var g Group
+		var count atomic.Int32
+		for range 5 {
+			g.Go(func() error {
+				count.Add(1)
+				return nil
+			})
+		}
+		require.NoError(t, g.Wait())
+		assert.Equal(t, int32(5), count.Load())
+	})
+
+	t.Run("returns the first error and cancels siblings", func(t *testing.T) {
+		g, ctx := WithContext(context.Background())
+		g.Go(func() error { return assert.AnError })
+		g.Go(func() error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		err := g.Wait()
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorIs(t, context.Cause(ctx), assert.AnError)
+	})
+
+	t.Run("recovers panics", func(t *testing.T) {
+		g, ctx := WithContext(context.Background())
+		g.Go(func() error { panic("boom") })
+
+		var pe *PanicError
+		require.ErrorAs(t, g.Wait(), &pe)
+		assert.Error(t, ctx.Err())
+	})
+
+	t.Run("context is cancelled after Wait", func(t *testing.T) {
+		g, ctx := WithContext(context.Background())
+		g.Go(func() error { return nil })
+		require.NoError(t, g.Wait())
+		assert.True(t, errors.Is(ctx.Err(), context.Canceled))
+	})
+
+	t.Run("limit bounds active goroutines", func(t *testing.T) {
+		var g Group
+		g.SetLimit(2)
+		var active, peak atomic.Int32
+		for range 6 {
+			g.Go(func() error {
+				n := active.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				active.Add(-1)
+				return nil
+			})
+		}
+		require.NoError(t, g.Wait())
+		assert.LessOrEqual(t, peak.Load(), int32(2))
+	})
+
+	t.Run("shared limiter bounds nested groups without deadlock", func(t *testing.T) {
+		l := gains.ContextConcurrencyLimiter(gains.WithConcurrencyLimit(context.Background(), 3))
+		var active, peak atomic.Int32
+		work := func() error {
+			n := active.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			active.Add(-1)
+			return nil
+		}
+
+		var outer Group
+		outer.SetLimiter(l)
+		for range 4 {
+			outer.Go(func() error {
+				var inner Group
+				inner.SetLimiter(l)
+				for range 4 {
+					inner.Go(work)
+				}
+				return inner.Wait()
+			})
+		}
+		require.NoError(t, outer.Wait())
+		assert.LessOrEqual(t, peak.Load(), int32(3))
+	})
+
+	t.Run("limiter with no free slot runs inline", func(t *testing.T) {
+		var g Group
+		g.SetLimiter(gains.ContextConcurrencyLimiter(gains.WithConcurrencyLimit(context.Background(), 1)))
+		ran := false
+		g.Go(func() error {
+			ran = true
+			return nil
+		})
+		assert.True(t, ran)
+		require.NoError(t, g.Wait())
+	})
+}
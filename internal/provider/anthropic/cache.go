@@ -0,0 +1,53 @@
+package anthropic
+
+import (
+	"github.com/anthropics/anthropic-sdk-go"
+	ai "github.com/spetersoncode/gains"
+)
+
+// applyCacheControl marks cache breakpoints on the last tool definition, the
+// last system block, and the last content block of the final message.
+// Anthropic caches the full prompt prefix up to each breakpoint.
+func applyCacheControl(params *anthropic.MessageNewParams) {
+	cc := anthropic.NewCacheControlEphemeralParam()
+
+	if n := len(params.Tools); n > 0 && params.Tools[n-1].OfTool != nil {
+		params.Tools[n-1].OfTool.CacheControl = cc
+	}
+	if n := len(params.System); n > 0 {
+		params.System[n-1].CacheControl = cc
+	}
+	if n := len(params.Messages); n > 0 {
+		blocks := params.Messages[n-1].Content
+		if m := len(blocks); m > 0 {
+			setBlockCacheControl(&blocks[m-1], cc)
+		}
+	}
+}
+
+// setBlockCacheControl sets cache control on whichever block variant is populated.
+func setBlockCacheControl(block *anthropic.ContentBlockParamUnion, cc anthropic.CacheControlEphemeralParam) {
+	switch {
+	case block.OfText != nil:
+		block.OfText.CacheControl = cc
+	case block.OfImage != nil:
+		block.OfImage.CacheControl = cc
+	case block.OfToolUse != nil:
+		block.OfToolUse.CacheControl = cc
+	case block.OfToolResult != nil:
+		block.OfToolResult.CacheControl = cc
+	}
+}
+
+// convertUsage maps Anthropic usage to gains usage. Anthropic reports cache
+// reads and writes separately from uncached input, so they are added back
+// into InputTokens; cache reads are also reported as CachedInputTokens and
+// cache writes as CacheWriteInputTokens.
+func convertUsage(u anthropic.Usage) ai.Usage {
+	return ai.Usage{
+		InputTokens:           int(u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens),
+		OutputTokens:          int(u.OutputTokens),
+		CachedInputTokens:     int(u.CacheReadInputTokens),
+		CacheWriteInputTokens: int(u.CacheCreationInputTokens),
+	}
+}
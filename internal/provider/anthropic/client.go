@@ -2,6 +2,7 @@ package anthropic
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
@@ -12,24 +13,40 @@ import (
 type Client struct {
 	client *anthropic.Client
 	model  ChatModel
+
+	wrapTransport func(http.RoundTripper) http.RoundTripper
 }
 
 // New creates a new Anthropic client with the given API key.
 func New(apiKey string, opts ...ClientOption) *Client {
-	client := anthropic.NewClient(option.WithAPIKey(apiKey))
 	c := &Client{
-		client: &client,
-		model:  DefaultChatModel,
+		model: DefaultChatModel,
 	}
 	for _, opt := range opts {
 		opt(c)
 	}
+
+	reqOpts := []option.RequestOption{option.WithAPIKey(apiKey)}
+	if c.wrapTransport != nil {
+		reqOpts = append(reqOpts, option.WithHTTPClient(&http.Client{
+			Transport: c.wrapTransport(http.DefaultTransport),
+		}))
+	}
+	client := anthropic.NewClient(reqOpts...)
+	c.client = &client
 	return c
 }
 
 // ClientOption configures the Anthropic client.
 type ClientOption func(*Client)
 
+// WithTransport wraps the HTTP transport used for API requests.
+func WithTransport(wrap func(http.RoundTripper) http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.wrapTransport = wrap
+	}
+}
+
 // WithModel sets the default model for requests.
 func WithModel(model ChatModel) ClientOption {
 	return func(c *Client) {
@@ -81,8 +98,12 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 			params.ToolChoice = convertToolChoice(options.ToolChoice)
 		}
 	}
+	if options.CacheControl {
+		applyCacheControl(&params)
+	}
 
-	resp, err := c.client.Messages.New(ctx, params)
+	var httpResp *http.Response
+	resp, err := c.client.Messages.New(ctx, params, option.WithResponseInto(&httpResp))
 	if err != nil {
 		return nil, wrapError(err)
 	}
@@ -108,13 +129,11 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 	}
 
 	return &ai.Response{
-		Content:      content,
-		FinishReason: string(resp.StopReason),
-		Usage: ai.Usage{
-			InputTokens:  int(resp.Usage.InputTokens),
-			OutputTokens: int(resp.Usage.OutputTokens),
-		},
-		ToolCalls: toolCalls,
+		Content:          content,
+		FinishReason:     string(resp.StopReason),
+		Usage:            convertUsage(resp.Usage),
+		ToolCalls:        toolCalls,
+		ProviderMetadata: responseMetadata(httpResp, string(resp.Model)),
 	}, nil
 }
 
@@ -162,8 +181,14 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 			params.ToolChoice = convertToolChoice(options.ToolChoice)
 		}
 	}
+	if options.CacheControl {
+		applyCacheControl(&params)
+	}
 
-	stream := c.client.Messages.NewStreaming(ctx, params)
+	// The SDK sends the request before returning, so httpResp is set
+	// before the stream is read
+	var httpResp *http.Response
+	stream := c.client.Messages.NewStreaming(ctx, params, option.WithResponseInto(&httpResp))
 	ch := make(chan ai.StreamEvent)
 
 	go func() {
@@ -213,13 +238,11 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 		ch <- ai.StreamEvent{
 			Done: true,
 			Response: &ai.Response{
-				Content:      content,
-				FinishReason: string(acc.StopReason),
-				Usage: ai.Usage{
-					InputTokens:  int(acc.Usage.InputTokens),
-					OutputTokens: int(acc.Usage.OutputTokens),
-				},
-				ToolCalls: toolCalls,
+				Content:          content,
+				FinishReason:     string(acc.StopReason),
+				Usage:            convertUsage(acc.Usage),
+				ToolCalls:        toolCalls,
+				ProviderMetadata: responseMetadata(httpResp, string(acc.Model)),
 			},
 		}
 	}()
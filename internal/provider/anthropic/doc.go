@@ -73,4 +73,11 @@
 //	pricing := anthropic.ClaudeSonnet45.Pricing()
 //	fmt.Printf("Input: $%.2f/M tokens, Output: $%.2f/M tokens\n",
 //	    pricing.InputPerMillion, pricing.OutputPerMillion)
+//
+// # Prompt Caching
+//
+// Pass gains.WithCacheControl() to mark cache breakpoints on the system prompt,
+// tool definitions, and latest message. Cache reads are reported in
+// Usage.CachedInputTokens and cache writes in Usage.CacheWriteInputTokens;
+// both are counted within Usage.InputTokens.
 package anthropic
@@ -8,6 +8,9 @@ import (
 )
 
 func convertMessages(messages []ai.Message) ([]anthropic.MessageParam, []anthropic.TextBlockParam) {
+	// Reshape the history into a form the API accepts
+	messages = ai.CanonicalMessages(messages)
+
 	var result []anthropic.MessageParam
 	var system []anthropic.TextBlockParam
 
@@ -97,7 +100,30 @@ func convertPartsToAnthropicBlocks(parts []ai.ContentPart) []anthropic.ContentBl
 				}
 				blocks = append(blocks, anthropic.NewImageBlockBase64(mediaType, part.Base64))
 			}
+		case ai.ContentPartTypeDocument:
+			if block, ok := convertDocumentPart(part); ok {
+				blocks = append(blocks, block)
+			}
 		}
 	}
 	return blocks
 }
+
+// convertDocumentPart converts a document part to an Anthropic document block.
+// Plain text documents use a text source; other data is sent as a PDF.
+func convertDocumentPart(part ai.ContentPart) (anthropic.ContentBlockParamUnion, bool) {
+	var block anthropic.ContentBlockParamUnion
+	if text, ok := part.DocumentText(); ok {
+		block = anthropic.NewDocumentBlock(anthropic.PlainTextSourceParam{Data: text})
+	} else if part.Base64 != "" {
+		block = anthropic.NewDocumentBlock(anthropic.Base64PDFSourceParam{Data: part.Base64})
+	} else if part.FileURL != "" {
+		block = anthropic.NewDocumentBlock(anthropic.URLPDFSourceParam{URL: part.FileURL})
+	} else {
+		return block, false
+	}
+	if part.Filename != "" {
+		block.OfDocument.Title = anthropic.String(part.Filename)
+	}
+	return block, true
+}
@@ -0,0 +1,49 @@
+package anthropic
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// rateLimitPrefix starts the names of Anthropic's rate-limit headers.
+const rateLimitPrefix = "anthropic-ratelimit-"
+
+// responseMetadata returns the metadata of a response from model, read from
+// its HTTP response if the SDK captured one.
+func responseMetadata(httpResp *http.Response, model string) *ai.ProviderMetadata {
+	md := &ai.ProviderMetadata{Model: model}
+	if httpResp == nil {
+		return md
+	}
+	md.Header = httpResp.Header.Clone()
+	md.RequestID = httpResp.Header.Get("request-id")
+	md.RateLimit = parseRateLimit(httpResp.Header)
+	return md
+}
+
+// parseRateLimit reads the anthropic-ratelimit-* headers, whose reset
+// times are RFC 3339 timestamps. It returns nil if there are none.
+func parseRateLimit(h http.Header) *ai.RateLimit {
+	if h.Get(rateLimitPrefix+"requests-limit") == "" && h.Get(rateLimitPrefix+"tokens-limit") == "" {
+		return nil
+	}
+	atoi := func(name string) int {
+		n, _ := strconv.Atoi(h.Get(rateLimitPrefix + name))
+		return n
+	}
+	reset := func(name string) time.Time {
+		t, _ := time.Parse(time.RFC3339, h.Get(rateLimitPrefix+name))
+		return t
+	}
+	return &ai.RateLimit{
+		RequestsLimit:     atoi("requests-limit"),
+		RequestsRemaining: atoi("requests-remaining"),
+		RequestsReset:     reset("requests-reset"),
+		TokensLimit:       atoi("tokens-limit"),
+		TokensRemaining:   atoi("tokens-remaining"),
+		TokensReset:       reset("tokens-reset"),
+	}
+}
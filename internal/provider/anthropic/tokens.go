@@ -0,0 +1,36 @@
+package anthropic
+
+import (
+	"context"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	ai "github.com/spetersoncode/gains"
+)
+
+// CountTokens counts the input tokens of a request with the count_tokens
+// endpoint, including the system prompt and tools.
+func (c *Client) CountTokens(ctx context.Context, messages []ai.Message, opts ...ai.Option) (int, error) {
+	options := ai.ApplyOptions(opts...)
+	model := c.model
+	if options.Model != nil {
+		model = ChatModel(options.Model.String())
+	}
+
+	msgs, system := convertMessages(messages)
+	params := anthropic.MessageCountTokensParams{
+		Model:    anthropic.Model(model.String()),
+		Messages: msgs,
+	}
+	if len(system) > 0 {
+		params.System = anthropic.MessageCountTokensParamsSystemUnion{OfTextBlockArray: system}
+	}
+	for _, t := range convertTools(options.Tools) {
+		params.Tools = append(params.Tools, anthropic.MessageCountTokensToolUnionParam{OfTool: t.OfTool})
+	}
+
+	resp, err := c.client.Messages.CountTokens(ctx, params)
+	if err != nil {
+		return 0, wrapError(err)
+	}
+	return int(resp.InputTokens), nil
+}
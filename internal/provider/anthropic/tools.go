@@ -85,6 +85,7 @@ func buildAnthropicJSONTool(options *ai.Options) (anthropic.ToolUnionParam, anth
 	var schema map[string]any
 	if options.ResponseSchema != nil && len(options.ResponseSchema.Schema) > 0 {
 		json.Unmarshal(options.ResponseSchema.Schema, &schema)
+		schema = options.TransformSchema(ai.ProviderAnthropic, schema)
 	} else {
 		// Generic object schema for basic JSON mode
 		schema = map[string]any{
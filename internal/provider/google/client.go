@@ -2,8 +2,8 @@ package google
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
+	"net/http"
 
 	ai "github.com/spetersoncode/gains"
 	"google.golang.org/genai"
@@ -13,30 +13,44 @@ import (
 type Client struct {
 	client *genai.Client
 	model  ChatModel
+
+	wrapTransport func(http.RoundTripper) http.RoundTripper
 }
 
 // New creates a new Google GenAI client with the given API key.
 func New(ctx context.Context, apiKey string, opts ...ClientOption) (*Client, error) {
-	client, err := genai.NewClient(ctx, &genai.ClientConfig{
-		APIKey:  apiKey,
-		Backend: genai.BackendGeminiAPI,
-	})
-	if err != nil {
-		return nil, err
-	}
 	c := &Client{
-		client: client,
-		model:  DefaultChatModel,
+		model: DefaultChatModel,
 	}
 	for _, opt := range opts {
 		opt(c)
 	}
+
+	cfg := &genai.ClientConfig{
+		APIKey:  apiKey,
+		Backend: genai.BackendGeminiAPI,
+	}
+	if c.wrapTransport != nil {
+		cfg.HTTPClient = &http.Client{Transport: c.wrapTransport(http.DefaultTransport)}
+	}
+	client, err := genai.NewClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	c.client = client
 	return c, nil
 }
 
 // ClientOption configures the Google client.
 type ClientOption func(*Client)
 
+// WithTransport wraps the HTTP transport used for API requests.
+func WithTransport(wrap func(http.RoundTripper) http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.wrapTransport = wrap
+	}
+}
+
 // WithModel sets the default model for requests.
 func WithModel(model ChatModel) ClientOption {
 	return func(c *Client) {
@@ -66,7 +80,7 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 		config.Temperature = &temp
 	}
 	if len(options.Tools) > 0 {
-		config.Tools = ConvertTools(options.Tools)
+		config.Tools = ConvertTools(options.Tools, options, ai.ProviderGoogle)
 		if options.ToolChoice != "" {
 			config.ToolConfig = ConvertToolChoice(options.ToolChoice)
 		}
@@ -75,11 +89,15 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 	// Handle JSON mode / response schema
 	if options.ResponseSchema != nil {
 		config.ResponseMIMEType = "application/json"
-		config.ResponseSchema = ConvertJSONSchemaToGenaiSchema(options.ResponseSchema.Schema)
+		config.ResponseSchema = ConvertResponseSchema(options, ai.ProviderGoogle)
 	} else if options.ResponseFormat == ai.ResponseFormatJSON {
 		config.ResponseMIMEType = "application/json"
 	}
 
+	if options.CandidateCount > 1 {
+		config.CandidateCount = int32(options.CandidateCount)
+	}
+
 	// Enable image output if requested
 	if options.ImageOutput {
 		config.ResponseModalities = []string{"TEXT", "IMAGE"}
@@ -105,20 +123,9 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 	var parts []ai.ContentPart
 	if len(resp.Candidates) > 0 && resp.Candidates[0].Content != nil {
 		for _, part := range resp.Candidates[0].Content.Parts {
-			if part.Text != "" {
-				content += part.Text
-				if options.ImageOutput {
-					parts = append(parts, ai.NewTextPart(part.Text))
-				}
-			}
-			if part.InlineData != nil && len(part.InlineData.Data) > 0 {
-				parts = append(parts, ai.ContentPart{
-					Type:     ai.ContentPartTypeImage,
-					Base64:   base64.StdEncoding.EncodeToString(part.InlineData.Data),
-					MimeType: part.InlineData.MIMEType,
-				})
-			}
+			content += part.Text
 		}
+		parts = ResponseParts(resp.Candidates[0].Content.Parts)
 		toolCalls = ExtractToolCalls(resp.Candidates[0].Content.Parts)
 	}
 
@@ -131,14 +138,17 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 	if resp.UsageMetadata != nil {
 		usage.InputTokens = int(resp.UsageMetadata.PromptTokenCount)
 		usage.OutputTokens = int(resp.UsageMetadata.CandidatesTokenCount)
+		usage.CachedInputTokens = int(resp.UsageMetadata.CachedContentTokenCount)
 	}
 
 	return &ai.Response{
-		Content:      content,
-		FinishReason: finishReason,
-		Usage:        usage,
-		ToolCalls:    toolCalls,
-		Parts:        parts,
+		Content:          content,
+		FinishReason:     finishReason,
+		Usage:            usage,
+		ToolCalls:        toolCalls,
+		Parts:            parts,
+		Candidates:       ResponseCandidates(resp.Candidates),
+		ProviderMetadata: ResponseMetadata(resp),
 	}, nil
 }
 
@@ -164,7 +174,7 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 		config.Temperature = &temp
 	}
 	if len(options.Tools) > 0 {
-		config.Tools = ConvertTools(options.Tools)
+		config.Tools = ConvertTools(options.Tools, options, ai.ProviderGoogle)
 		if options.ToolChoice != "" {
 			config.ToolConfig = ConvertToolChoice(options.ToolChoice)
 		}
@@ -173,7 +183,7 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 	// Handle JSON mode / response schema
 	if options.ResponseSchema != nil {
 		config.ResponseMIMEType = "application/json"
-		config.ResponseSchema = ConvertJSONSchemaToGenaiSchema(options.ResponseSchema.Schema)
+		config.ResponseSchema = ConvertResponseSchema(options, ai.ProviderGoogle)
 	} else if options.ResponseFormat == ai.ResponseFormatJSON {
 		config.ResponseMIMEType = "application/json"
 	}
@@ -202,8 +212,8 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 		var finishReason string
 		var usage ai.Usage
 		var allParts []*genai.Part
-		var contentParts []ai.ContentPart
 		var iterCount int
+		var metadata *ai.ProviderMetadata
 
 		for resp, err := range c.client.Models.GenerateContentStream(ctx, model.String(), contents, config) {
 			iterCount++
@@ -226,24 +236,17 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 					if part.Text != "" {
 						ch <- ai.StreamEvent{Delta: part.Text}
 						fullContent += part.Text
-						if options.ImageOutput {
-							contentParts = append(contentParts, ai.NewTextPart(part.Text))
-						}
-					}
-					if part.InlineData != nil && len(part.InlineData.Data) > 0 {
-						contentParts = append(contentParts, ai.ContentPart{
-							Type:     ai.ContentPartTypeImage,
-							Base64:   base64.StdEncoding.EncodeToString(part.InlineData.Data),
-							MimeType: part.InlineData.MIMEType,
-						})
 					}
 				}
 				finishReason = string(resp.Candidates[0].FinishReason)
 			}
 
+			metadata = MergeResponseMetadata(metadata, resp)
+
 			if resp.UsageMetadata != nil {
 				usage.InputTokens = int(resp.UsageMetadata.PromptTokenCount)
 				usage.OutputTokens = int(resp.UsageMetadata.CandidatesTokenCount)
+				usage.CachedInputTokens = int(resp.UsageMetadata.CachedContentTokenCount)
 			}
 		}
 
@@ -256,11 +259,12 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 		ch <- ai.StreamEvent{
 			Done: true,
 			Response: &ai.Response{
-				Content:      fullContent,
-				FinishReason: finishReason,
-				Usage:        usage,
-				ToolCalls:    ExtractToolCalls(allParts),
-				Parts:        contentParts,
+				Content:          fullContent,
+				FinishReason:     finishReason,
+				Usage:            usage,
+				ToolCalls:        ExtractToolCalls(allParts),
+				Parts:            ResponseParts(allParts),
+				ProviderMetadata: metadata,
 			},
 		}
 	}()
@@ -271,3 +275,4 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 var _ ai.ChatProvider = (*Client)(nil)
 var _ ai.ImageProvider = (*Client)(nil)
 var _ ai.EmbeddingProvider = (*Client)(nil)
+var _ ai.TranscriptionProvider = (*Client)(nil)
@@ -8,6 +8,7 @@
 //   - Chat completions via [gains.ChatProvider]
 //   - Text embeddings via [gains.EmbeddingProvider]
 //   - Image generation via [gains.ImageProvider] (Imagen models)
+//   - Speech-to-text via [gains.TranscriptionProvider] (Gemini audio understanding)
 //   - Tool/function calling
 //   - Multimodal inputs (images)
 //   - Structured JSON output with schema validation
@@ -83,6 +84,14 @@
 //	    fmt.Println(img.URL)
 //	}
 //
+// # Transcription
+//
+// Gemini transcribes audio with a chat model, [DefaultChatModel] by default:
+//
+//	resp, err := client.Transcribe(ctx, audio,
+//	    gains.WithTranscriptionLanguage("en"),
+//	)
+//
 // # Pricing
 //
 // Google has tiered pricing based on context length:
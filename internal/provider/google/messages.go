@@ -14,7 +14,11 @@ import (
 
 // ConvertMessages converts gains Messages to Google genai Contents.
 func ConvertMessages(messages []ai.Message) ([]*genai.Content, error) {
+	// Reshape the history into a form the API accepts
+	messages = ai.CanonicalMessages(messages)
+
 	var contents []*genai.Content
+	names := toolNames(messages)
 
 	for _, msg := range messages {
 		role := "user"
@@ -51,6 +55,7 @@ func ConvertMessages(messages []ai.Message) ([]*genai.Content, error) {
 			json.Unmarshal([]byte(tc.Arguments), &args)
 			parts = append(parts, &genai.Part{
 				FunctionCall: &genai.FunctionCall{
+					ID:   tc.ID,
 					Name: tc.Name,
 					Args: args,
 				},
@@ -66,7 +71,8 @@ func ConvertMessages(messages []ai.Message) ([]*genai.Content, error) {
 			}
 			parts = append(parts, &genai.Part{
 				FunctionResponse: &genai.FunctionResponse{
-					Name:     tr.ToolCallID, // Google uses the function name, but we store ID; handle in extractToolCalls
+					ID:       tr.ToolCallID,
+					Name:     toolName(names, tr.ToolCallID),
 					Response: result,
 				},
 			})
@@ -138,11 +144,55 @@ func convertPartsToGoogleParts(parts []ai.ContentPart) ([]*genai.Part, error) {
 					})
 				}
 			}
+		case ai.ContentPartTypeDocument:
+			gp, err := convertDocumentPart(part)
+			if err != nil {
+				return nil, err
+			}
+			if gp != nil {
+				result = append(result, gp)
+			}
 		}
 	}
 	return result, nil
 }
 
+// convertDocumentPart converts a document part to a Gemini part. Plain text
+// documents are sent as text, GCS and Files API URIs as FileData, and other
+// documents as inline data. Returns nil if the part carries no content.
+func convertDocumentPart(part ai.ContentPart) (*genai.Part, error) {
+	if text, ok := part.DocumentText(); ok {
+		if part.Filename != "" {
+			text = fmt.Sprintf("<document filename=%q>\n%s\n</document>", part.Filename, text)
+		}
+		return &genai.Part{Text: text}, nil
+	}
+
+	mimeType := part.MimeType
+	if mimeType == "" {
+		mimeType = "application/pdf" // Default
+	}
+
+	switch {
+	case part.Base64 != "":
+		data, err := base64.StdEncoding.DecodeString(part.Base64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode document: %w", err)
+		}
+		return &genai.Part{InlineData: &genai.Blob{Data: data, MIMEType: mimeType}}, nil
+	case strings.HasPrefix(part.FileURL, "gs://"),
+		strings.HasPrefix(part.FileURL, "https://generativelanguage.googleapis.com/"):
+		return &genai.Part{FileData: &genai.FileData{FileURI: part.FileURL, MIMEType: mimeType}}, nil
+	case part.FileURL != "":
+		data, _, err := fetchImageFromURL(part.FileURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch document %s: %w", part.FileURL, err)
+		}
+		return &genai.Part{InlineData: &genai.Blob{Data: data, MIMEType: mimeType}}, nil
+	}
+	return nil, nil
+}
+
 func fetchImageFromURL(url string) ([]byte, string, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -0,0 +1,37 @@
+package google
+
+import (
+	ai "github.com/spetersoncode/gains"
+	"google.golang.org/genai"
+)
+
+// ResponseMetadata returns the metadata of a generate content response:
+// its response ID, model version, and HTTP headers. Gemini reports no
+// rate-limit headers, so RateLimit is nil. For a stream, merge the
+// metadata of each chunk with MergeResponseMetadata.
+func ResponseMetadata(resp *genai.GenerateContentResponse) *ai.ProviderMetadata {
+	md := &ai.ProviderMetadata{RequestID: resp.ResponseID, Model: resp.ModelVersion}
+	if resp.SDKHTTPResponse != nil {
+		md.Header = resp.SDKHTTPResponse.Headers.Clone()
+	}
+	return md
+}
+
+// MergeResponseMetadata returns md updated with the non-empty metadata of
+// a stream chunk. md may be nil.
+func MergeResponseMetadata(md *ai.ProviderMetadata, chunk *genai.GenerateContentResponse) *ai.ProviderMetadata {
+	next := ResponseMetadata(chunk)
+	if md == nil {
+		return next
+	}
+	if next.RequestID != "" {
+		md.RequestID = next.RequestID
+	}
+	if next.Model != "" {
+		md.Model = next.Model
+	}
+	if next.Header != nil {
+		md.Header = next.Header
+	}
+	return md
+}
@@ -0,0 +1,72 @@
+package google
+
+import (
+	"encoding/base64"
+	"strings"
+
+	ai "github.com/spetersoncode/gains"
+	"google.golang.org/genai"
+)
+
+// ResponseParts converts Gemini response parts to gains content parts,
+// keeping text and inline media in the order the model produced them.
+// Adjacent text parts, as produced by streaming, are merged. Returns nil for
+// text-only responses, whose text is carried in Response.Content alone.
+func ResponseParts(parts []*genai.Part) []ai.ContentPart {
+	var result []ai.ContentPart
+	hasMedia := false
+	for _, part := range parts {
+		if part.Text != "" {
+			if n := len(result); n > 0 && result[n-1].Type == ai.ContentPartTypeText {
+				result[n-1].Text += part.Text
+			} else {
+				result = append(result, ai.NewTextPart(part.Text))
+			}
+		}
+		if part.InlineData != nil && len(part.InlineData.Data) > 0 {
+			result = append(result, InlineDataPart(part.InlineData))
+			hasMedia = true
+		}
+	}
+	if !hasMedia {
+		return nil
+	}
+	return result
+}
+
+// InlineDataPart converts inline response data to a content part typed by
+// its MIME type: images and audio map to their part types, anything else to
+// a document part.
+func InlineDataPart(blob *genai.Blob) ai.ContentPart {
+	data := base64.StdEncoding.EncodeToString(blob.Data)
+	switch {
+	case strings.HasPrefix(blob.MIMEType, "image/"):
+		return ai.NewImageBase64Part(data, blob.MIMEType)
+	case strings.HasPrefix(blob.MIMEType, "audio/"):
+		return ai.NewAudioBase64Part(data, blob.MIMEType)
+	default:
+		return ai.NewDocumentBase64Part(data, blob.MIMEType, "")
+	}
+}
+
+// ResponseCandidates converts the candidates of a response with several
+// candidates, as requested with ai.WithCandidateCount. It returns nil for a
+// single candidate.
+func ResponseCandidates(candidates []*genai.Candidate) []ai.Candidate {
+	if len(candidates) < 2 {
+		return nil
+	}
+	result := make([]ai.Candidate, len(candidates))
+	for i, cand := range candidates {
+		result[i].FinishReason = string(cand.FinishReason)
+		if cand.Content == nil {
+			continue
+		}
+		for _, part := range cand.Content.Parts {
+			result[i].Content += part.Text
+		}
+		result[i].Parts = ResponseParts(cand.Content.Parts)
+		result[i].ToolCalls = ExtractToolCalls(cand.Content.Parts)
+	}
+	return result
+}
@@ -3,23 +3,47 @@ package google
 import (
 	"encoding/json"
 
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/schema"
 	"google.golang.org/genai"
 )
 
-// ConvertJSONSchemaToGenaiSchema converts JSON Schema to Google genai Schema.
-func ConvertJSONSchemaToGenaiSchema(schemaJSON json.RawMessage) *genai.Schema {
-	if len(schemaJSON) == 0 {
+// refDepth is how many times a recursive $ref is expanded when flattening
+// schemas, since Gemini does not support references.
+const refDepth = 3
+
+// ConvertParameters converts the parameters of tool to a genai Schema.
+// References are inlined, recursive ones up to refDepth levels deep, and
+// keywords Gemini lacks are downgraded, reporting the dropped constraints
+// through options.
+func ConvertParameters(tool ai.Tool, options *ai.Options, provider ai.Provider) *genai.Schema {
+	if len(tool.Parameters) == 0 {
 		return nil
 	}
 
-	var schema map[string]any
-	if err := json.Unmarshal(schemaJSON, &schema); err != nil {
+	var parsed map[string]any
+	if err := json.Unmarshal(tool.Parameters, &parsed); err != nil {
 		return nil
 	}
 
-	return convertSchemaObject(schema)
+	flat := schema.Flatten(parsed, refDepth)
+	return convertSchemaObject(options.DowngradeSchema(provider, tool.Name, flat, schema.Gemini))
+}
+
+// ConvertResponseSchema converts the response schema in options to a genai
+// Schema, applying the options' schema transform for provider to the
+// flattened schema before downgrading it.
+func ConvertResponseSchema(options *ai.Options, provider ai.Provider) *genai.Schema {
+	var parsed map[string]any
+	if err := json.Unmarshal(options.ResponseSchema.Schema, &parsed); err != nil {
+		return nil
+	}
+	transformed := options.TransformSchema(provider, schema.Flatten(parsed, refDepth))
+	return convertSchemaObject(options.DowngradeSchema(provider, "", transformed, schema.Gemini))
 }
 
+// convertSchemaObject converts a schema already downgraded to the
+// schema.Gemini profile.
 func convertSchemaObject(schema map[string]any) *genai.Schema {
 	if schema == nil {
 		return nil
@@ -45,10 +69,34 @@ func convertSchemaObject(schema map[string]any) *genai.Schema {
 		}
 	}
 
-	// Handle description
+	// Handle annotations and format
+	if title, ok := schema["title"].(string); ok {
+		result.Title = title
+	}
 	if desc, ok := schema["description"].(string); ok {
 		result.Description = desc
 	}
+	if format, ok := schema["format"].(string); ok {
+		result.Format = format
+	}
+	if nullable, ok := schema["nullable"].(bool); ok {
+		result.Nullable = &nullable
+	}
+	result.Default = schema["default"]
+	result.Example = schema["example"]
+
+	// Handle numeric, string, array, and object bounds
+	result.Minimum = floatValue(schema["minimum"])
+	result.Maximum = floatValue(schema["maximum"])
+	result.MinLength = intValue(schema["minLength"])
+	result.MaxLength = intValue(schema["maxLength"])
+	result.MinItems = intValue(schema["minItems"])
+	result.MaxItems = intValue(schema["maxItems"])
+	result.MinProperties = intValue(schema["minProperties"])
+	result.MaxProperties = intValue(schema["maxProperties"])
+	if pattern, ok := schema["pattern"].(string); ok {
+		result.Pattern = pattern
+	}
 
 	// Handle enum
 	if enumVal, ok := schema["enum"].([]any); ok {
@@ -69,6 +117,14 @@ func convertSchemaObject(schema map[string]any) *genai.Schema {
 		}
 	}
 
+	if order, ok := schema["propertyOrdering"].([]any); ok {
+		for _, name := range order {
+			if s, ok := name.(string); ok {
+				result.PropertyOrdering = append(result.PropertyOrdering, s)
+			}
+		}
+	}
+
 	// Handle required fields
 	if required, ok := schema["required"].([]any); ok {
 		for _, r := range required {
@@ -83,5 +139,31 @@ func convertSchemaObject(schema map[string]any) *genai.Schema {
 		result.Items = convertSchemaObject(items)
 	}
 
+	// Handle alternatives
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		for _, alt := range anyOf {
+			if altMap, ok := alt.(map[string]any); ok {
+				result.AnyOf = append(result.AnyOf, convertSchemaObject(altMap))
+			}
+		}
+	}
+
 	return result
 }
+
+// floatValue returns a JSON number as a float64 pointer, or nil.
+func floatValue(v any) *float64 {
+	if f, ok := v.(float64); ok {
+		return &f
+	}
+	return nil
+}
+
+// intValue returns a JSON number as an int64 pointer, or nil.
+func intValue(v any) *int64 {
+	if f, ok := v.(float64); ok {
+		n := int64(f)
+		return &n
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package google
+
+import (
+	"context"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// CountTokens counts the input tokens of messages with the countTokens
+// endpoint. The Gemini API does not count tool definitions, so tools set
+// in opts are not included.
+func (c *Client) CountTokens(ctx context.Context, messages []ai.Message, opts ...ai.Option) (int, error) {
+	options := ai.ApplyOptions(opts...)
+	model := c.model
+	if options.Model != nil {
+		model = ChatModel(options.Model.String())
+	}
+
+	contents, err := ConvertMessages(messages)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.client.Models.CountTokens(ctx, model.String(), contents, nil)
+	if err != nil {
+		return 0, WrapError(err)
+	}
+	return int(resp.TotalTokens), nil
+}
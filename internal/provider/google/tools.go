@@ -2,14 +2,15 @@ package google
 
 import (
 	"encoding/json"
-	"fmt"
+	"strconv"
+	"strings"
 
 	ai "github.com/spetersoncode/gains"
 	"google.golang.org/genai"
 )
 
-// ConvertTools converts gains Tools to Google genai Tools.
-func ConvertTools(tools []ai.Tool) []*genai.Tool {
+// ConvertTools converts gains Tools to Google genai Tools for provider.
+func ConvertTools(tools []ai.Tool, options *ai.Options, provider ai.Provider) []*genai.Tool {
 	if len(tools) == 0 {
 		return nil
 	}
@@ -19,7 +20,7 @@ func ConvertTools(tools []ai.Tool) []*genai.Tool {
 		funcs[i] = &genai.FunctionDeclaration{
 			Name:        t.Name,
 			Description: t.Description,
-			Parameters:  ConvertJSONSchemaToGenaiSchema(t.Parameters),
+			Parameters:  ConvertParameters(t, options, provider),
 		}
 	}
 
@@ -50,14 +51,20 @@ func ConvertToolChoice(choice ai.ToolChoice) *genai.ToolConfig {
 	}
 }
 
-// ExtractToolCalls extracts tool calls from Google genai Parts.
+// ExtractToolCalls extracts tool calls from Google genai Parts. Calls keep
+// the ID assigned by the API; when it omits one, a unique ID is generated so
+// results can be matched to their call later in the conversation.
 func ExtractToolCalls(parts []*genai.Part) []ai.ToolCall {
 	var calls []ai.ToolCall
-	for i, part := range parts {
+	for _, part := range parts {
 		if part.FunctionCall != nil {
+			id := part.FunctionCall.ID
+			if id == "" {
+				id = "call_" + ai.NewID()
+			}
 			args, _ := json.Marshal(part.FunctionCall.Args)
 			calls = append(calls, ai.ToolCall{
-				ID:        fmt.Sprintf("call_%d_%s", i, part.FunctionCall.Name),
+				ID:        id,
 				Name:      part.FunctionCall.Name,
 				Arguments: string(args),
 			})
@@ -65,3 +72,33 @@ func ExtractToolCalls(parts []*genai.Part) []ai.ToolCall {
 	}
 	return calls
 }
+
+// toolNames maps tool call IDs to function names for the calls made in
+// messages. Gemini matches function responses to calls by name, while gains
+// tool results only carry the call ID.
+func toolNames(messages []ai.Message) map[string]string {
+	names := make(map[string]string)
+	for _, msg := range messages {
+		for _, tc := range msg.ToolCalls {
+			names[tc.ID] = tc.Name
+		}
+	}
+	return names
+}
+
+// toolName returns the function name for a tool call ID. IDs of calls not
+// found in names fall back to the legacy "call_<index>_<name>" format, then
+// to the ID itself.
+func toolName(names map[string]string, id string) string {
+	if name, ok := names[id]; ok {
+		return name
+	}
+	if rest, ok := strings.CutPrefix(id, "call_"); ok {
+		if index, name, ok := strings.Cut(rest, "_"); ok && name != "" {
+			if _, err := strconv.Atoi(index); err == nil {
+				return name
+			}
+		}
+	}
+	return id
+}
@@ -0,0 +1,114 @@
+package google
+
+import (
+	"strings"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genai"
+)
+
+func TestExtractToolCalls(t *testing.T) {
+	calls := ExtractToolCalls([]*genai.Part{
+		{FunctionCall: &genai.FunctionCall{ID: "abc123", Name: "get_weather", Args: map[string]any{"city": "Paris"}}},
+		{Text: "thinking"},
+		{FunctionCall: &genai.FunctionCall{Name: "get_time"}},
+		{FunctionCall: &genai.FunctionCall{Name: "get_time"}},
+	})
+	require.Len(t, calls, 3)
+
+	assert.Equal(t, "abc123", calls[0].ID, "provider ID kept")
+	assert.Equal(t, "get_weather", calls[0].Name)
+	assert.JSONEq(t, `{"city":"Paris"}`, calls[0].Arguments)
+
+	assert.True(t, strings.HasPrefix(calls[1].ID, "call_"), "missing ID generated")
+	assert.NotEqual(t, calls[1].ID, calls[2].ID, "generated IDs are unique")
+}
+
+func TestToolName(t *testing.T) {
+	names := map[string]string{
+		"abc123":           "get_weather",
+		"call_7_from_call": "lookup",
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		want string
+	}{
+		{"provider ID", "abc123", "get_weather"},
+		{"recorded call wins over legacy parsing", "call_7_from_call", "lookup"},
+		{"legacy format", "call_0_get_weather", "get_weather"},
+		{"legacy format with underscores in name", "call_12_search_web_pages", "search_web_pages"},
+		{"generated ID without index", "call_" + "01JD8Z", "call_01JD8Z"},
+		{"legacy prefix without name", "call_3_", "call_3_"},
+		{"unknown ID", "xyz", "xyz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, toolName(names, tt.id))
+		})
+	}
+}
+
+func TestConvertMessages_ToolResults(t *testing.T) {
+	tests := []struct {
+		name   string
+		callID string
+		wantID string
+	}{
+		{"provider ID", "abc123", "abc123"},
+		{"generated ID", "call_01JD8ZQ4", "call_01JD8ZQ4"},
+		{"legacy ID", "call_0_get_weather", "call_0_get_weather"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contents, err := ConvertMessages([]ai.Message{
+				{Role: ai.RoleUser, Content: "Weather in Paris?"},
+				{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{
+					{ID: tt.callID, Name: "get_weather", Arguments: `{"city":"Paris"}`},
+				}},
+				{Role: ai.RoleTool, ToolResults: []ai.ToolResult{
+					{ToolCallID: tt.callID, Content: `{"temp":21}`},
+				}},
+			})
+			require.NoError(t, err)
+			require.Len(t, contents, 3)
+
+			call := contents[1].Parts[0].FunctionCall
+			require.NotNil(t, call)
+			assert.Equal(t, tt.wantID, call.ID)
+			assert.Equal(t, "get_weather", call.Name)
+
+			resp := contents[2].Parts[0].FunctionResponse
+			require.NotNil(t, resp)
+			assert.Equal(t, "user", contents[2].Role)
+			assert.Equal(t, tt.wantID, resp.ID)
+			assert.Equal(t, "get_weather", resp.Name, "result sent under the call's function name")
+			assert.Equal(t, map[string]any{"temp": float64(21)}, resp.Response)
+		})
+	}
+}
+
+func TestConvertMessages_ParallelToolResults(t *testing.T) {
+	contents, err := ConvertMessages([]ai.Message{
+		{Role: ai.RoleUser, Content: "Weather and time in Paris?"},
+		{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{
+			{ID: "a", Name: "get_weather", Arguments: `{}`},
+			{ID: "b", Name: "get_time", Arguments: `{}`},
+		}},
+		{Role: ai.RoleTool, ToolResults: []ai.ToolResult{
+			{ToolCallID: "b", Content: "noon"},
+			{ToolCallID: "a", Content: "sunny"},
+		}},
+	})
+	require.NoError(t, err)
+	require.Len(t, contents, 3)
+	require.Len(t, contents[2].Parts, 2)
+
+	assert.Equal(t, "get_time", contents[2].Parts[0].FunctionResponse.Name)
+	assert.Equal(t, map[string]any{"result": "noon"}, contents[2].Parts[0].FunctionResponse.Response)
+	assert.Equal(t, "get_weather", contents[2].Parts[1].FunctionResponse.Name)
+}
@@ -0,0 +1,78 @@
+package google
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ai "github.com/spetersoncode/gains"
+	"google.golang.org/genai"
+)
+
+// transcriptionInstruction asks Gemini for a verbatim transcript only.
+const transcriptionInstruction = "Generate a verbatim transcript of the speech in this audio. " +
+	"Respond with only the transcribed text, without timestamps, speaker labels, or commentary."
+
+// Transcribe converts speech to text using Gemini audio understanding.
+func (c *Client) Transcribe(ctx context.Context, audio []byte, opts ...ai.TranscriptionOption) (*ai.TranscriptionResponse, error) {
+	return Transcribe(ctx, c.client, audio, opts...)
+}
+
+// Transcribe converts speech to text with the given genai client. It is
+// shared by the Gemini API and Vertex AI providers.
+func Transcribe(ctx context.Context, client *genai.Client, audio []byte, opts ...ai.TranscriptionOption) (*ai.TranscriptionResponse, error) {
+	if len(audio) == 0 {
+		return nil, fmt.Errorf("%w: audio is required for transcription", ai.ErrEmptyInput)
+	}
+
+	options := ai.ApplyTranscriptionOptions(opts...)
+
+	// Determine model; transcription runs on a multimodal chat model
+	model := DefaultChatModel
+	if options.Model != nil {
+		model = ChatModel(options.Model.String())
+	}
+
+	mimeType := options.MimeType
+	if mimeType == "" {
+		mimeType = ai.DetectAudioMimeType(audio)
+	}
+
+	contents := []*genai.Content{{
+		Role: "user",
+		Parts: []*genai.Part{
+			{InlineData: &genai.Blob{Data: audio, MIMEType: mimeType}},
+			{Text: transcriptionPrompt(options)},
+		},
+	}}
+	temperature := float32(0)
+	config := &genai.GenerateContentConfig{Temperature: &temperature}
+
+	// Make API call
+	resp, err := client.Models.GenerateContent(ctx, model.String(), contents, config)
+	if err != nil {
+		return nil, WrapError(err)
+	}
+
+	result := &ai.TranscriptionResponse{Text: strings.TrimSpace(resp.Text())}
+	if resp.UsageMetadata != nil {
+		result.Usage = ai.Usage{
+			InputTokens:  int(resp.UsageMetadata.PromptTokenCount),
+			OutputTokens: int(resp.UsageMetadata.CandidatesTokenCount),
+		}
+	}
+	return result, nil
+}
+
+// transcriptionPrompt builds the instruction sent alongside the audio.
+func transcriptionPrompt(options *ai.TranscriptionOptions) string {
+	var b strings.Builder
+	b.WriteString(transcriptionInstruction)
+	if options.Language != "" {
+		fmt.Fprintf(&b, "\nThe spoken language is %q.", options.Language)
+	}
+	if options.Prompt != "" {
+		fmt.Fprintf(&b, "\nContext: %s", options.Prompt)
+	}
+	return b.String()
+}
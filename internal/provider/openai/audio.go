@@ -0,0 +1,87 @@
+package openai
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/openai/openai-go"
+	ai "github.com/spetersoncode/gains"
+)
+
+// applyAudioOutput enables spoken audio output on the request.
+func applyAudioOutput(params *openai.ChatCompletionNewParams, audio *ai.AudioOutput) {
+	params.Modalities = []string{"text", "audio"}
+	params.Audio = openai.ChatCompletionAudioParam{
+		Voice:  openai.ChatCompletionAudioParamVoice(audio.Voice),
+		Format: openai.ChatCompletionAudioParamFormat(audio.Format),
+	}
+}
+
+// audioMimeType returns the MIME type for an OpenAI audio output format.
+func audioMimeType(format string) string {
+	switch format {
+	case "pcm16":
+		return "audio/pcm"
+	case "mp3":
+		return "audio/mpeg"
+	case "":
+		return "audio/wav"
+	default:
+		return "audio/" + format
+	}
+}
+
+// audioParts builds response parts for spoken output: the transcript as text
+// followed by the audio itself. Returns nil if no audio was produced.
+func audioParts(data, transcript, format string) []ai.ContentPart {
+	if data == "" {
+		return nil
+	}
+	var parts []ai.ContentPart
+	if transcript != "" {
+		parts = append(parts, ai.NewTextPart(transcript))
+	}
+	return append(parts, ai.NewAudioBase64Part(data, audioMimeType(format)))
+}
+
+// audioDelta is the audio field of a streaming chunk delta, which the SDK
+// does not model.
+type audioDelta struct {
+	Data       string `json:"data"`
+	Transcript string `json:"transcript"`
+}
+
+// streamAudio collects audio and transcript deltas from a streaming response.
+type streamAudio struct {
+	data       []byte
+	transcript strings.Builder
+}
+
+// add parses the audio field of a chunk delta, returning the transcript
+// delta. Malformed chunks are ignored.
+func (s *streamAudio) add(delta openai.ChatCompletionChunkChoiceDelta) string {
+	field, ok := delta.JSON.ExtraFields["audio"]
+	if !ok {
+		return ""
+	}
+	var d audioDelta
+	if err := json.Unmarshal([]byte(field.Raw()), &d); err != nil {
+		return ""
+	}
+	if d.Data != "" {
+		if b, err := base64.StdEncoding.DecodeString(d.Data); err == nil {
+			s.data = append(s.data, b...)
+		}
+	}
+	s.transcript.WriteString(d.Transcript)
+	return d.Transcript
+}
+
+// parts returns the accumulated audio as response parts.
+func (s *streamAudio) parts(format string) []ai.ContentPart {
+	if len(s.data) == 0 {
+		return nil
+	}
+	return audioParts(base64.StdEncoding.EncodeToString(s.data), s.transcript.String(), format)
+}
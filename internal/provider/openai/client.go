@@ -2,6 +2,7 @@ package openai
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
@@ -10,26 +11,54 @@ import (
 
 // Client wraps the OpenAI SDK to implement ai.ChatProvider.
 type Client struct {
-	client *openai.Client
-	model  ChatModel
+	client  *openai.Client
+	model   ChatModel
+	baseURL string
+
+	wrapTransport func(http.RoundTripper) http.RoundTripper
 }
 
 // New creates a new OpenAI client with the given API key.
 func New(apiKey string, opts ...ClientOption) *Client {
-	client := openai.NewClient(option.WithAPIKey(apiKey))
 	c := &Client{
-		client: &client,
-		model:  DefaultChatModel,
+		model: DefaultChatModel,
 	}
 	for _, opt := range opts {
 		opt(c)
 	}
+
+	reqOpts := []option.RequestOption{option.WithAPIKey(apiKey)}
+	if c.baseURL != "" {
+		reqOpts = append(reqOpts, option.WithBaseURL(c.baseURL))
+	}
+	if c.wrapTransport != nil {
+		reqOpts = append(reqOpts, option.WithHTTPClient(&http.Client{
+			Transport: c.wrapTransport(http.DefaultTransport),
+		}))
+	}
+	client := openai.NewClient(reqOpts...)
+	c.client = &client
 	return c
 }
 
 // ClientOption configures the OpenAI client.
 type ClientOption func(*Client)
 
+// WithTransport wraps the HTTP transport used for API requests.
+func WithTransport(wrap func(http.RoundTripper) http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.wrapTransport = wrap
+	}
+}
+
+// WithBaseURL sends requests to an OpenAI-compatible server, such as vLLM
+// or a local inference server, instead of the OpenAI API.
+func WithBaseURL(url string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = url
+	}
+}
+
 // WithModel sets the default model for requests.
 func WithModel(model ChatModel) ClientOption {
 	return func(c *Client) {
@@ -67,9 +96,16 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 		}
 	}
 
+	if options.AudioOutput != nil {
+		applyAudioOutput(&params, options.AudioOutput)
+	}
+	if options.CandidateCount > 1 {
+		params.N = openai.Int(int64(options.CandidateCount))
+	}
+
 	// Handle JSON mode / response schema
 	if options.ResponseSchema != nil {
-		params.ResponseFormat = buildOpenAISchemaFormat(options.ResponseSchema)
+		params.ResponseFormat = buildOpenAISchemaFormat(options)
 	} else if options.ResponseFormat == ai.ResponseFormatJSON {
 		params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
 			OfJSONObject: &openai.ResponseFormatJSONObjectParam{
@@ -78,22 +114,55 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 		}
 	}
 
-	resp, err := c.client.Chat.Completions.New(ctx, params)
+	var httpResp *http.Response
+	reqOpts := append(c.constraintOptions(options), option.WithResponseInto(&httpResp))
+	resp, err := c.client.Chat.Completions.New(ctx, params, reqOpts...)
 	if err != nil {
 		return nil, wrapError(err)
 	}
 
+	message := resp.Choices[0].Message
+	content := message.Content
+	var parts []ai.ContentPart
+	if options.AudioOutput != nil {
+		parts = audioParts(message.Audio.Data, message.Audio.Transcript, options.AudioOutput.Format)
+		if content == "" {
+			content = message.Audio.Transcript
+		}
+	}
+
 	return &ai.Response{
-		Content:      resp.Choices[0].Message.Content,
+		Content:      content,
 		FinishReason: string(resp.Choices[0].FinishReason),
 		Usage: ai.Usage{
-			InputTokens:  int(resp.Usage.PromptTokens),
-			OutputTokens: int(resp.Usage.CompletionTokens),
+			InputTokens:       int(resp.Usage.PromptTokens),
+			OutputTokens:      int(resp.Usage.CompletionTokens),
+			CachedInputTokens: int(resp.Usage.PromptTokensDetails.CachedTokens),
 		},
-		ToolCalls: extractToolCalls(resp.Choices[0].Message),
+		ToolCalls:        extractToolCalls(message),
+		Parts:            parts,
+		Candidates:       candidates(resp.Choices),
+		ProviderMetadata: responseMetadata(httpResp, resp.Model),
 	}, nil
 }
 
+// candidates converts the choices of a completion with several choices to
+// candidates. It returns nil for a single choice.
+func candidates(choices []openai.ChatCompletionChoice) []ai.Candidate {
+	if len(choices) < 2 {
+		return nil
+	}
+	result := make([]ai.Candidate, len(choices))
+	for i, choice := range choices {
+		result[i] = ai.Candidate{
+			Content:      choice.Message.Content,
+			FinishReason: string(choice.FinishReason),
+			ToolCalls:    extractToolCalls(choice.Message),
+		}
+	}
+	return result
+}
+
 // ChatStream sends a conversation and returns a channel of streaming events.
 func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan ai.StreamEvent, error) {
 	options := ai.ApplyOptions(opts...)
@@ -127,9 +196,13 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 		}
 	}
 
+	if options.AudioOutput != nil {
+		applyAudioOutput(&params, options.AudioOutput)
+	}
+
 	// Handle JSON mode / response schema
 	if options.ResponseSchema != nil {
-		params.ResponseFormat = buildOpenAISchemaFormat(options.ResponseSchema)
+		params.ResponseFormat = buildOpenAISchemaFormat(options)
 	} else if options.ResponseFormat == ai.ResponseFormatJSON {
 		params.ResponseFormat = openai.ChatCompletionNewParamsResponseFormatUnion{
 			OfJSONObject: &openai.ResponseFormatJSONObjectParam{
@@ -138,12 +211,17 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 		}
 	}
 
-	stream := c.client.Chat.Completions.NewStreaming(ctx, params)
+	// The SDK sends the request before returning, so httpResp is set
+	// before the stream is read
+	var httpResp *http.Response
+	reqOpts := append(c.constraintOptions(options), option.WithResponseInto(&httpResp))
+	stream := c.client.Chat.Completions.NewStreaming(ctx, params, reqOpts...)
 	ch := make(chan ai.StreamEvent)
 
 	go func() {
 		defer close(ch)
 		var acc openai.ChatCompletionAccumulator
+		var audio streamAudio
 
 		for stream.Next() {
 			chunk := stream.Current()
@@ -154,6 +232,11 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 					Delta: chunk.Choices[0].Delta.Content,
 				}
 			}
+			if options.AudioOutput != nil && len(chunk.Choices) > 0 {
+				if transcript := audio.add(chunk.Choices[0].Delta); transcript != "" {
+					ch <- ai.StreamEvent{Delta: transcript}
+				}
+			}
 		}
 
 		if err := stream.Err(); err != nil {
@@ -163,16 +246,27 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 
 		// Send final event with complete response
 		completion := acc.Choices[0]
+		content := completion.Message.Content
+		var parts []ai.ContentPart
+		if options.AudioOutput != nil {
+			parts = audio.parts(options.AudioOutput.Format)
+			if content == "" {
+				content = audio.transcript.String()
+			}
+		}
 		ch <- ai.StreamEvent{
 			Done: true,
 			Response: &ai.Response{
-				Content:      completion.Message.Content,
+				Content:      content,
 				FinishReason: string(completion.FinishReason),
 				Usage: ai.Usage{
-					InputTokens:  int(acc.Usage.PromptTokens),
-					OutputTokens: int(acc.Usage.CompletionTokens),
+					InputTokens:       int(acc.Usage.PromptTokens),
+					OutputTokens:      int(acc.Usage.CompletionTokens),
+					CachedInputTokens: int(acc.Usage.PromptTokensDetails.CachedTokens),
 				},
-				ToolCalls: extractToolCallsFromAccumulator(completion.Message.ToolCalls),
+				ToolCalls:        extractToolCallsFromAccumulator(completion.Message.ToolCalls),
+				Parts:            parts,
+				ProviderMetadata: responseMetadata(httpResp, acc.Model),
 			},
 		}
 	}()
@@ -180,6 +274,28 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 	return ch, nil
 }
 
+// SupportsConstraint reports whether the server enforces output
+// constraints. The OpenAI API does not; OpenAI-compatible servers set with
+// WithBaseURL are assumed to accept vLLM's guided decoding parameters.
+func (c *Client) SupportsConstraint(kind ai.ConstraintKind) bool {
+	return c.baseURL != ""
+}
+
+// constraintOptions adds the guided decoding parameter for the request's
+// output constraint, if the server supports it.
+func (c *Client) constraintOptions(options *ai.Options) []option.RequestOption {
+	if options.Constraint == nil || !c.SupportsConstraint(options.Constraint.Kind) {
+		return nil
+	}
+	key := "guided_regex"
+	if options.Constraint.Kind == ai.ConstraintGrammar {
+		key = "guided_grammar"
+	}
+	return []option.RequestOption{option.WithJSONSet(key, options.Constraint.Pattern)}
+}
+
 var _ ai.ChatProvider = (*Client)(nil)
+var _ ai.ConstrainedDecoder = (*Client)(nil)
 var _ ai.ImageProvider = (*Client)(nil)
 var _ ai.EmbeddingProvider = (*Client)(nil)
+var _ ai.TranscriptionProvider = (*Client)(nil)
@@ -8,6 +8,7 @@
 //   - Chat completions via [gains.ChatProvider]
 //   - Text embeddings via [gains.EmbeddingProvider]
 //   - Image generation via [gains.ImageProvider]
+//   - Speech-to-text via [gains.TranscriptionProvider]
 //   - Tool/function calling
 //   - Multimodal inputs (images)
 //   - Structured JSON output with schema validation
@@ -31,6 +32,12 @@
 //   - [TextEmbedding3Large]: 3072 dimensions
 //   - [TextEmbedding3Small]: 1536 dimensions (recommended default)
 //
+// Transcription models:
+//
+//   - [GPT4oTranscribe]: Highest accuracy
+//   - [GPT4oMiniTranscribe]: Cost-effective option (recommended default)
+//   - [Whisper1]: Billed by audio duration
+//
 // # Basic Usage
 //
 //	client := openai.New(os.Getenv("OPENAI_API_KEY"))
@@ -80,6 +87,19 @@
 //	}
 //	fmt.Println(resp.Images[0].URL)
 //
+// # Audio Output
+//
+// Audio models can reply with speech. The audio is returned as an audio part
+// and its transcript as the response content:
+//
+//	resp, err := client.Chat(ctx, messages,
+//	    gains.WithModel(gains.ChatModel("gpt-4o-audio-preview")),
+//	    gains.WithAudioOutput("alloy", "wav"),
+//	)
+//	audio := resp.PartsOfType(gains.ContentPartTypeAudio)
+//
+// Streaming audio requires the "pcm16" format.
+//
 // # Pricing
 //
 // Get model pricing programmatically:
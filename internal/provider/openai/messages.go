@@ -12,6 +12,9 @@ import (
 )
 
 func convertMessages(messages []ai.Message) ([]openai.ChatCompletionMessageParamUnion, error) {
+	// Reshape the history into a form the API accepts
+	messages = ai.CanonicalMessages(messages)
+
 	var result []openai.ChatCompletionMessageParamUnion
 	for _, msg := range messages {
 		switch msg.Role {
@@ -118,11 +121,64 @@ func convertPartsToOpenAIParts(parts []ai.ContentPart) ([]openai.ChatCompletionC
 					URL: imageURL,
 				}))
 			}
+		case ai.ContentPartTypeDocument:
+			contentPart, ok, err := convertDocumentPart(part)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				result = append(result, contentPart)
+			}
 		}
 	}
 	return result, nil
 }
 
+// convertDocumentPart converts a document part to an OpenAI content part.
+// OpenAI file inputs only accept PDFs, so plain text documents are sent as
+// text wrapped in a document tag. URL documents are fetched client-side.
+func convertDocumentPart(part ai.ContentPart) (openai.ChatCompletionContentPartUnionParam, bool, error) {
+	if text, ok := part.DocumentText(); ok {
+		return openai.TextContentPart(formatTextDocument(part.Filename, text)), true, nil
+	}
+
+	data := part.Base64
+	mimeType := part.MimeType
+	if data == "" && part.FileURL != "" {
+		raw, fetchedType, err := fetchImageFromURL(part.FileURL)
+		if err != nil {
+			return openai.ChatCompletionContentPartUnionParam{}, false, fmt.Errorf("failed to fetch document %s: %w", part.FileURL, err)
+		}
+		data = base64.StdEncoding.EncodeToString(raw)
+		if mimeType == "" {
+			mimeType = fetchedType
+		}
+	}
+	if data == "" {
+		return openai.ChatCompletionContentPartUnionParam{}, false, nil
+	}
+	if mimeType == "" || !strings.Contains(mimeType, "/") || strings.HasPrefix(mimeType, "image/") {
+		mimeType = "application/pdf"
+	}
+
+	filename := part.Filename
+	if filename == "" {
+		filename = "document.pdf"
+	}
+	return openai.FileContentPart(openai.ChatCompletionContentPartFileFileParam{
+		FileData: openai.String(fmt.Sprintf("data:%s;base64,%s", mimeType, data)),
+		Filename: openai.String(filename),
+	}), true, nil
+}
+
+// formatTextDocument wraps plain text document contents for inclusion as text.
+func formatTextDocument(filename, text string) string {
+	if filename == "" {
+		return "<document>\n" + text + "\n</document>"
+	}
+	return fmt.Sprintf("<document filename=%q>\n%s\n</document>", filename, text)
+}
+
 func fetchImageFromURL(url string) ([]byte, string, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -0,0 +1,50 @@
+package openai
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// responseMetadata returns the metadata of a response from model, read from
+// its HTTP response if the SDK captured one.
+func responseMetadata(httpResp *http.Response, model string) *ai.ProviderMetadata {
+	md := &ai.ProviderMetadata{Model: model}
+	if httpResp == nil {
+		return md
+	}
+	md.Header = httpResp.Header.Clone()
+	md.RequestID = httpResp.Header.Get("x-request-id")
+	md.RateLimit = parseRateLimit(httpResp.Header, time.Now())
+	return md
+}
+
+// parseRateLimit reads the x-ratelimit-* headers, whose reset times are
+// durations from now such as "6m0s" or "20ms". It returns nil if there are
+// none.
+func parseRateLimit(h http.Header, now time.Time) *ai.RateLimit {
+	if h.Get("x-ratelimit-limit-requests") == "" && h.Get("x-ratelimit-limit-tokens") == "" {
+		return nil
+	}
+	atoi := func(name string) int {
+		n, _ := strconv.Atoi(h.Get(name))
+		return n
+	}
+	reset := func(name string) time.Time {
+		d, err := time.ParseDuration(h.Get(name))
+		if err != nil {
+			return time.Time{}
+		}
+		return now.Add(d)
+	}
+	return &ai.RateLimit{
+		RequestsLimit:     atoi("x-ratelimit-limit-requests"),
+		RequestsRemaining: atoi("x-ratelimit-remaining-requests"),
+		RequestsReset:     reset("x-ratelimit-reset-requests"),
+		TokensLimit:       atoi("x-ratelimit-limit-tokens"),
+		TokensRemaining:   atoi("x-ratelimit-remaining-tokens"),
+		TokensReset:       reset("x-ratelimit-reset-tokens"),
+	}
+}
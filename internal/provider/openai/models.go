@@ -136,3 +136,18 @@ func (m EmbeddingModel) Pricing() EmbeddingModelPricing {
 
 // String returns the model identifier string.
 func (m EmbeddingModel) String() string { return string(m) }
+
+// TranscriptionModel represents an OpenAI speech-to-text model.
+type TranscriptionModel string
+
+const (
+	GPT4oTranscribe     TranscriptionModel = "gpt-4o-transcribe"      // Highest accuracy
+	GPT4oMiniTranscribe TranscriptionModel = "gpt-4o-mini-transcribe" // Budget option
+	Whisper1            TranscriptionModel = "whisper-1"              // Billed by duration
+
+	// DefaultTranscriptionModel is the recommended default transcription model.
+	DefaultTranscriptionModel TranscriptionModel = GPT4oMiniTranscribe
+)
+
+// String returns the model identifier string.
+func (m TranscriptionModel) String() string { return string(m) }
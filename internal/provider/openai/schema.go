@@ -2,12 +2,16 @@ package openai
 
 import (
 	"encoding/json"
+	"sort"
 
 	"github.com/openai/openai-go"
 	ai "github.com/spetersoncode/gains"
 )
 
-func buildOpenAISchemaFormat(schema *ai.ResponseSchema) openai.ChatCompletionNewParamsResponseFormatUnion {
+func buildOpenAISchemaFormat(options *ai.Options) openai.ChatCompletionNewParamsResponseFormatUnion {
+	schema := options.ResponseSchema
+
+	// Unmarshalling gives a private copy, so the caller's schema is not modified
 	var schemaMap map[string]any
 	json.Unmarshal(schema.Schema, &schemaMap)
 
@@ -16,12 +20,11 @@ func buildOpenAISchemaFormat(schema *ai.ResponseSchema) openai.ChatCompletionNew
 		name = "response_schema"
 	}
 
-	strict := true // Default to strict
-
-	// OpenAI strict mode requires additionalProperties: false on all objects
+	strict := options.StrictSchema == nil || *options.StrictSchema
 	if strict {
-		addAdditionalPropertiesFalse(schemaMap)
+		makeStrict(schemaMap)
 	}
+	schemaMap = options.TransformSchema(ai.ProviderOpenAI, schemaMap)
 
 	return openai.ChatCompletionNewParamsResponseFormatUnion{
 		OfJSONSchema: &openai.ResponseFormatJSONSchemaParam{
@@ -36,29 +39,90 @@ func buildOpenAISchemaFormat(schema *ai.ResponseSchema) openai.ChatCompletionNew
 	}
 }
 
-// addAdditionalPropertiesFalse recursively adds additionalProperties: false to all object schemas.
-// This is required by OpenAI's strict mode.
-func addAdditionalPropertiesFalse(schema map[string]any) {
+// makeStrict rewrites a schema for OpenAI's strict mode, which requires
+// additionalProperties: false on every object and every property to be
+// required. Properties that were optional become nullable instead, so the
+// model can still leave them out by answering null.
+func makeStrict(schema map[string]any) {
 	if schema == nil {
 		return
 	}
 
+	if props, ok := schema["properties"].(map[string]any); ok {
+		required := make(map[string]bool)
+		if list, ok := schema["required"].([]any); ok {
+			for _, name := range list {
+				if s, ok := name.(string); ok {
+					required[s] = true
+				}
+			}
+		}
+
+		names := make([]string, 0, len(props))
+		for name, propSchema := range props {
+			names = append(names, name)
+			propMap, ok := propSchema.(map[string]any)
+			if !ok {
+				continue
+			}
+			makeStrict(propMap)
+			if !required[name] {
+				props[name] = nullable(propMap)
+			}
+		}
+		sort.Strings(names)
+		schema["required"] = names
+	}
+
 	// If this is an object type, add additionalProperties: false
 	if schemaType, ok := schema["type"].(string); ok && schemaType == "object" {
 		schema["additionalProperties"] = false
 	}
 
-	// Recurse into properties
-	if props, ok := schema["properties"].(map[string]any); ok {
-		for _, propSchema := range props {
-			if propMap, ok := propSchema.(map[string]any); ok {
-				addAdditionalPropertiesFalse(propMap)
+	// Recurse into array items, alternatives, and definitions
+	if items, ok := schema["items"].(map[string]any); ok {
+		makeStrict(items)
+	}
+	for _, key := range []string{"anyOf", "oneOf", "allOf"} {
+		if list, ok := schema[key].([]any); ok {
+			for _, sub := range list {
+				if subMap, ok := sub.(map[string]any); ok {
+					makeStrict(subMap)
+				}
+			}
+		}
+	}
+	for _, key := range []string{"$defs", "definitions"} {
+		if defs, ok := schema[key].(map[string]any); ok {
+			for _, def := range defs {
+				if defMap, ok := def.(map[string]any); ok {
+					makeStrict(defMap)
+				}
 			}
 		}
 	}
+}
 
-	// Recurse into array items
-	if items, ok := schema["items"].(map[string]any); ok {
-		addAdditionalPropertiesFalse(items)
+// nullable returns schema extended to also accept null.
+func nullable(schema map[string]any) map[string]any {
+	switch t := schema["type"].(type) {
+	case string:
+		if t == "null" {
+			return schema
+		}
+		schema["type"] = []any{t, "null"}
+		if enum, ok := schema["enum"].([]any); ok {
+			schema["enum"] = append(enum, nil)
+		}
+		return schema
+	case []any:
+		for _, v := range t {
+			if v == "null" {
+				return schema
+			}
+		}
+		schema["type"] = append(t, "null")
+		return schema
 	}
+	return map[string]any{"anyOf": []any{schema, map[string]any{"type": "null"}}}
 }
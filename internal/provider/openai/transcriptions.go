@@ -0,0 +1,80 @@
+package openai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openai/openai-go"
+	ai "github.com/spetersoncode/gains"
+)
+
+// audioExtensions maps audio MIME types to the file extensions OpenAI uses
+// to identify the upload format.
+var audioExtensions = map[string]string{
+	"audio/wav":  "wav",
+	"audio/mpeg": "mp3",
+	"audio/mp3":  "mp3",
+	"audio/flac": "flac",
+	"audio/ogg":  "ogg",
+	"audio/webm": "webm",
+	"audio/mp4":  "m4a",
+	"audio/m4a":  "m4a",
+}
+
+// Transcribe converts speech to text using OpenAI's transcription API.
+func (c *Client) Transcribe(ctx context.Context, audio []byte, opts ...ai.TranscriptionOption) (*ai.TranscriptionResponse, error) {
+	if len(audio) == 0 {
+		return nil, fmt.Errorf("%w: audio is required for transcription", ai.ErrEmptyInput)
+	}
+
+	options := ai.ApplyTranscriptionOptions(opts...)
+
+	// Determine model
+	model := DefaultTranscriptionModel
+	if options.Model != nil {
+		model = TranscriptionModel(options.Model.String())
+	}
+
+	// Determine format; OpenAI infers it from the filename extension
+	mimeType := options.MimeType
+	if mimeType == "" {
+		mimeType = ai.DetectAudioMimeType(audio)
+	}
+	ext, ok := audioExtensions[mimeType]
+	if !ok {
+		ext = "mp3"
+	}
+
+	// Build request params
+	params := openai.AudioTranscriptionNewParams{
+		File:           openai.File(bytes.NewReader(audio), "audio."+ext, mimeType),
+		Model:          openai.AudioModel(model.String()),
+		ResponseFormat: openai.AudioResponseFormatJSON,
+	}
+	if options.Language != "" {
+		params.Language = openai.String(options.Language)
+	}
+	if options.Prompt != "" {
+		params.Prompt = openai.String(options.Prompt)
+	}
+
+	// Make API call
+	resp, err := c.client.Audio.Transcriptions.New(ctx, params)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	result := &ai.TranscriptionResponse{Text: resp.Text}
+	switch resp.Usage.Type {
+	case "tokens":
+		result.Usage = ai.Usage{
+			InputTokens:  int(resp.Usage.InputTokens),
+			OutputTokens: int(resp.Usage.OutputTokens),
+		}
+	case "duration":
+		result.Duration = time.Duration(resp.Usage.Seconds * float64(time.Second))
+	}
+	return result, nil
+}
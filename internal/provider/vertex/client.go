@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"net/http"
 
+	"cloud.google.com/go/auth/credentials"
+	"cloud.google.com/go/auth/httptransport"
 	ai "github.com/spetersoncode/gains"
 	"github.com/spetersoncode/gains/internal/provider/google"
 	"google.golang.org/genai"
@@ -16,21 +19,17 @@ type Client struct {
 	project  string
 	location string
 	model    google.ChatModel
+
+	wrapTransport func(http.RoundTripper) http.RoundTripper
 }
 
+// cloudPlatformScope is the OAuth scope required for Vertex AI requests.
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
 // New creates a new Vertex AI client with the given project and location.
 // Uses Application Default Credentials (ADC) for authentication.
 func New(ctx context.Context, project, location string, opts ...ClientOption) (*Client, error) {
-	client, err := genai.NewClient(ctx, &genai.ClientConfig{
-		Backend:  genai.BackendVertexAI,
-		Project:  project,
-		Location: location,
-	})
-	if err != nil {
-		return nil, err
-	}
 	c := &Client{
-		client:   client,
 		project:  project,
 		location: location,
 		model:    google.DefaultChatModel,
@@ -38,12 +37,48 @@ func New(ctx context.Context, project, location string, opts ...ClientOption) (*
 	for _, opt := range opts {
 		opt(c)
 	}
+
+	cfg := &genai.ClientConfig{
+		Backend:  genai.BackendVertexAI,
+		Project:  project,
+		Location: location,
+	}
+	if c.wrapTransport != nil {
+		// A custom HTTP client bypasses the SDK's ADC setup, so build an
+		// authenticated client ourselves and wrap its transport.
+		creds, err := credentials.DetectDefault(&credentials.DetectOptions{
+			Scopes: []string{cloudPlatformScope},
+		})
+		if err != nil {
+			return nil, err
+		}
+		httpClient, err := httptransport.NewClient(&httptransport.Options{Credentials: creds})
+		if err != nil {
+			return nil, err
+		}
+		httpClient.Transport = c.wrapTransport(httpClient.Transport)
+		cfg.Credentials = creds
+		cfg.HTTPClient = httpClient
+	}
+	client, err := genai.NewClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	c.client = client
 	return c, nil
 }
 
 // ClientOption configures the Vertex AI client.
 type ClientOption func(*Client)
 
+// WithTransport wraps the HTTP transport used for API requests.
+// The wrapped transport already authenticates with Application Default Credentials.
+func WithTransport(wrap func(http.RoundTripper) http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.wrapTransport = wrap
+	}
+}
+
 // WithModel sets the default model for requests.
 func WithModel(model google.ChatModel) ClientOption {
 	return func(c *Client) {
@@ -73,7 +108,7 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 		config.Temperature = &temp
 	}
 	if len(options.Tools) > 0 {
-		config.Tools = google.ConvertTools(options.Tools)
+		config.Tools = google.ConvertTools(options.Tools, options, ai.ProviderVertex)
 		if options.ToolChoice != "" {
 			config.ToolConfig = google.ConvertToolChoice(options.ToolChoice)
 		}
@@ -82,11 +117,15 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 	// Handle JSON mode / response schema
 	if options.ResponseSchema != nil {
 		config.ResponseMIMEType = "application/json"
-		config.ResponseSchema = google.ConvertJSONSchemaToGenaiSchema(options.ResponseSchema.Schema)
+		config.ResponseSchema = google.ConvertResponseSchema(options, ai.ProviderVertex)
 	} else if options.ResponseFormat == ai.ResponseFormatJSON {
 		config.ResponseMIMEType = "application/json"
 	}
 
+	if options.CandidateCount > 1 {
+		config.CandidateCount = int32(options.CandidateCount)
+	}
+
 	// Enable image output if requested
 	if options.ImageOutput {
 		config.ResponseModalities = []string{"TEXT", "IMAGE"}
@@ -112,20 +151,9 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 	var parts []ai.ContentPart
 	if len(resp.Candidates) > 0 && resp.Candidates[0].Content != nil {
 		for _, part := range resp.Candidates[0].Content.Parts {
-			if part.Text != "" {
-				content += part.Text
-				if options.ImageOutput {
-					parts = append(parts, ai.NewTextPart(part.Text))
-				}
-			}
-			if part.InlineData != nil && len(part.InlineData.Data) > 0 {
-				parts = append(parts, ai.ContentPart{
-					Type:     ai.ContentPartTypeImage,
-					Base64:   base64.StdEncoding.EncodeToString(part.InlineData.Data),
-					MimeType: part.InlineData.MIMEType,
-				})
-			}
+			content += part.Text
 		}
+		parts = google.ResponseParts(resp.Candidates[0].Content.Parts)
 		toolCalls = google.ExtractToolCalls(resp.Candidates[0].Content.Parts)
 	}
 
@@ -138,14 +166,17 @@ func (c *Client) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Opt
 	if resp.UsageMetadata != nil {
 		usage.InputTokens = int(resp.UsageMetadata.PromptTokenCount)
 		usage.OutputTokens = int(resp.UsageMetadata.CandidatesTokenCount)
+		usage.CachedInputTokens = int(resp.UsageMetadata.CachedContentTokenCount)
 	}
 
 	return &ai.Response{
-		Content:      content,
-		FinishReason: finishReason,
-		Usage:        usage,
-		ToolCalls:    toolCalls,
-		Parts:        parts,
+		Content:          content,
+		FinishReason:     finishReason,
+		Usage:            usage,
+		ToolCalls:        toolCalls,
+		Parts:            parts,
+		Candidates:       google.ResponseCandidates(resp.Candidates),
+		ProviderMetadata: google.ResponseMetadata(resp),
 	}, nil
 }
 
@@ -171,7 +202,7 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 		config.Temperature = &temp
 	}
 	if len(options.Tools) > 0 {
-		config.Tools = google.ConvertTools(options.Tools)
+		config.Tools = google.ConvertTools(options.Tools, options, ai.ProviderVertex)
 		if options.ToolChoice != "" {
 			config.ToolConfig = google.ConvertToolChoice(options.ToolChoice)
 		}
@@ -180,7 +211,7 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 	// Handle JSON mode / response schema
 	if options.ResponseSchema != nil {
 		config.ResponseMIMEType = "application/json"
-		config.ResponseSchema = google.ConvertJSONSchemaToGenaiSchema(options.ResponseSchema.Schema)
+		config.ResponseSchema = google.ConvertResponseSchema(options, ai.ProviderVertex)
 	} else if options.ResponseFormat == ai.ResponseFormatJSON {
 		config.ResponseMIMEType = "application/json"
 	}
@@ -209,8 +240,8 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 		var finishReason string
 		var usage ai.Usage
 		var allParts []*genai.Part
-		var contentParts []ai.ContentPart
 		var iterCount int
+		var metadata *ai.ProviderMetadata
 
 		for resp, err := range c.client.Models.GenerateContentStream(ctx, model.String(), contents, config) {
 			iterCount++
@@ -233,24 +264,17 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 					if part.Text != "" {
 						ch <- ai.StreamEvent{Delta: part.Text}
 						fullContent += part.Text
-						if options.ImageOutput {
-							contentParts = append(contentParts, ai.NewTextPart(part.Text))
-						}
-					}
-					if part.InlineData != nil && len(part.InlineData.Data) > 0 {
-						contentParts = append(contentParts, ai.ContentPart{
-							Type:     ai.ContentPartTypeImage,
-							Base64:   base64.StdEncoding.EncodeToString(part.InlineData.Data),
-							MimeType: part.InlineData.MIMEType,
-						})
 					}
 				}
 				finishReason = string(resp.Candidates[0].FinishReason)
 			}
 
+			metadata = google.MergeResponseMetadata(metadata, resp)
+
 			if resp.UsageMetadata != nil {
 				usage.InputTokens = int(resp.UsageMetadata.PromptTokenCount)
 				usage.OutputTokens = int(resp.UsageMetadata.CandidatesTokenCount)
+				usage.CachedInputTokens = int(resp.UsageMetadata.CachedContentTokenCount)
 			}
 		}
 
@@ -263,11 +287,12 @@ func (c *Client) ChatStream(ctx context.Context, messages []ai.Message, opts ...
 		ch <- ai.StreamEvent{
 			Done: true,
 			Response: &ai.Response{
-				Content:      fullContent,
-				FinishReason: finishReason,
-				Usage:        usage,
-				ToolCalls:    google.ExtractToolCalls(allParts),
-				Parts:        contentParts,
+				Content:          fullContent,
+				FinishReason:     finishReason,
+				Usage:            usage,
+				ToolCalls:        google.ExtractToolCalls(allParts),
+				Parts:            google.ResponseParts(allParts),
+				ProviderMetadata: metadata,
 			},
 		}
 	}()
@@ -337,6 +362,11 @@ func (c *Client) Embed(ctx context.Context, texts []string, opts ...ai.Embedding
 	}, nil
 }
 
+// Transcribe converts speech to text using Gemini audio understanding on Vertex AI.
+func (c *Client) Transcribe(ctx context.Context, audio []byte, opts ...ai.TranscriptionOption) (*ai.TranscriptionResponse, error) {
+	return google.Transcribe(ctx, c.client, audio, opts...)
+}
+
 // GenerateImage generates images from a text prompt using Vertex AI Imagen.
 func (c *Client) GenerateImage(ctx context.Context, prompt string, opts ...ai.ImageOption) (*ai.ImageResponse, error) {
 	options := ai.ApplyImageOptions(opts...)
@@ -409,3 +439,4 @@ func encodeBase64(data []byte) string {
 var _ ai.ChatProvider = (*Client)(nil)
 var _ ai.ImageProvider = (*Client)(nil)
 var _ ai.EmbeddingProvider = (*Client)(nil)
+var _ ai.TranscriptionProvider = (*Client)(nil)
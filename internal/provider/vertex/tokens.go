@@ -0,0 +1,33 @@
+package vertex
+
+import (
+	"context"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/internal/provider/google"
+	"google.golang.org/genai"
+)
+
+// CountTokens counts the input tokens of a request with the countTokens
+// endpoint, including the tools set in opts.
+func (c *Client) CountTokens(ctx context.Context, messages []ai.Message, opts ...ai.Option) (int, error) {
+	options := ai.ApplyOptions(opts...)
+	model := c.model
+	if options.Model != nil {
+		model = google.ChatModel(options.Model.String())
+	}
+
+	contents, err := google.ConvertMessages(messages)
+	if err != nil {
+		return 0, err
+	}
+	var config *genai.CountTokensConfig
+	if len(options.Tools) > 0 {
+		config = &genai.CountTokensConfig{Tools: google.ConvertTools(options.Tools, options, ai.ProviderVertex)}
+	}
+	resp, err := c.client.Models.CountTokens(ctx, model.String(), contents, config)
+	if err != nil {
+		return 0, google.WrapError(err)
+	}
+	return int(resp.TotalTokens), nil
+}
@@ -25,6 +25,18 @@ type Config struct {
 	// Jitter adds randomness to prevent thundering herd (default: 0.1 = 10%).
 	// Delay is multiplied by (1 + random(-jitter, +jitter)).
 	Jitter float64
+
+	// Retryable reports whether a failed attempt is retried. Nil retries
+	// transient errors, as reported by IsTransient.
+	Retryable func(error) bool
+}
+
+// retryable reports whether err should be retried under c.
+func (c Config) retryable(err error) bool {
+	if c.Retryable != nil {
+		return c.Retryable(err)
+	}
+	return IsTransient(err)
 }
 
 // DefaultConfig returns the default retry configuration.
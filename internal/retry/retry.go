@@ -43,7 +43,7 @@ func Do[T any](ctx context.Context, cfg Config, fn func() (T, error)) (T, error)
 		lastErr = err
 
 		// Check if error is retryable
-		if !IsTransient(err) {
+		if !cfg.retryable(err) {
 			return zero, err
 		}
 
@@ -78,7 +78,7 @@ func DoStream[T any](ctx context.Context, cfg Config, fn func() (<-chan T, error
 		lastErr = err
 
 		// Check if error is retryable
-		if !IsTransient(err) {
+		if !cfg.retryable(err) {
 			return nil, err
 		}
 
@@ -123,7 +123,7 @@ func DoWithEvents[T any](ctx context.Context, cfg Config, events chan<- Event, f
 		}
 
 		lastErr = err
-		retryable := IsTransient(err)
+		retryable := cfg.retryable(err)
 
 		emit(events, Event{
 			Type:        EventAttemptFailed,
@@ -193,7 +193,7 @@ func DoStreamWithEvents[T any](ctx context.Context, cfg Config, events chan<- Ev
 		}
 
 		lastErr = err
-		retryable := IsTransient(err)
+		retryable := cfg.retryable(err)
 
 		emit(events, Event{
 			Type:        EventAttemptFailed,
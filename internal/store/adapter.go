@@ -35,3 +35,40 @@ type Adapter interface {
 	// Save stores all data from a map, replacing existing data.
 	Save(ctx context.Context, data map[string]json.RawMessage) error
 }
+
+// RevisionSaver is implemented by adapters that can compare the stored
+// revision and save in one atomic step. Store.UpdateCAS uses it when
+// available; otherwise it checks the revision with Get before calling Save,
+// which is not atomic. Adapters shared by several processes should
+// implement it.
+type RevisionSaver interface {
+	// SaveRevision replaces all data like Save, but only if the value stored
+	// under RevisionKey equals expected (0 if absent). It returns a
+	// *ConflictError otherwise.
+	SaveRevision(ctx context.Context, data map[string]json.RawMessage, expected int64) error
+}
+
+// Versioner is implemented by adapters with per-key optimistic
+// concurrency. The caller keeps the version GetVersion returns and passes
+// it back to SetVersion, so each reader detects writes made since its own
+// read, even by another reader sharing the adapter.
+type Versioner interface {
+	// GetVersion retrieves a value by key together with its version. A
+	// missing key has version 0.
+	GetVersion(ctx context.Context, key string) (value json.RawMessage, version int64, ok bool, err error)
+
+	// SetVersion stores a value by key only if its version still equals
+	// expected, returning the new version. Otherwise it writes nothing and
+	// returns an error matching ErrConflict.
+	SetVersion(ctx context.Context, key string, value json.RawMessage, expected int64) (version int64, err error)
+}
+
+// GetVersion retrieves a value by key with its version if adapter is a
+// Versioner, and with version 0 otherwise.
+func GetVersion(ctx context.Context, adapter Adapter, key string) (json.RawMessage, int64, bool, error) {
+	if v, ok := adapter.(Versioner); ok {
+		return v.GetVersion(ctx, key)
+	}
+	value, ok, err := adapter.Get(ctx, key)
+	return value, 0, ok, err
+}
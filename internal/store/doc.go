@@ -44,9 +44,25 @@
 //	    log.Fatal(err)
 //	}
 //
+// # Optimistic Concurrency
+//
+// Each Sync stores a revision number under RevisionKey. When several
+// processes share one adapter, use UpdateCAS instead of Sync: it writes only
+// if the revision is unchanged since the last Reload, and otherwise returns
+// an error matching ErrConflict so the caller can reload and retry:
+//
+//	err := s.UpdateCAS(ctx, func(data map[string]any) error {
+//	    data["status"] = "approved"
+//	    return nil
+//	})
+//	if errors.Is(err, store.ErrConflict) {
+//	    // Another process won; Reload and try again
+//	}
+//
 // # Custom Adapters
 //
-// Implement the Adapter interface for custom persistence:
+// For Redis, use the adapter in github.com/spetersoncode/gains/store/redis.
+// Implement the Adapter interface for other persistence backends:
 //
 //	type RedisAdapter struct { ... }
 //
@@ -11,6 +11,10 @@ var (
 
 	// ErrAdapterClosed indicates the adapter has been closed.
 	ErrAdapterClosed = errors.New("store: adapter closed")
+
+	// ErrConflict indicates a conditional write was rejected because the
+	// stored state changed since it was last read.
+	ErrConflict = errors.New("store: conflict")
 )
 
 // SerializationError wraps JSON marshaling/unmarshaling errors with context.
@@ -26,3 +30,19 @@ func (e *SerializationError) Error() string {
 func (e *SerializationError) Unwrap() error {
 	return e.Err
 }
+
+// ConflictError reports a rejected compare-and-swap write. It matches
+// ErrConflict with errors.Is.
+type ConflictError struct {
+	Expected int64 // Revision this store last read or wrote
+	Actual   int64 // Revision currently stored
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("store: conflict: expected revision %d, found %d", e.Expected, e.Actual)
+}
+
+// Is reports whether target is ErrConflict.
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
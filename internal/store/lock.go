@@ -0,0 +1,160 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// Locker is implemented by adapters that can hold named locks shared by
+// every process using the same backend. session.Manager uses it to let one
+// run at a time write to a conversation across instances.
+type Locker interface {
+	// TryLock acquires the named lock for ttl and returns the token that
+	// releases it, or false if the lock is held. A lock not released within
+	// ttl expires.
+	TryLock(ctx context.Context, name string, ttl time.Duration) (token string, ok bool, err error)
+
+	// Unlock releases the named lock if it is still held under token.
+	Unlock(ctx context.Context, name, token string) error
+}
+
+// Refresher is implemented by Lockers whose held locks can be extended,
+// so a lock can outlive its ttl while its holder is alive.
+type Refresher interface {
+	// Refresh extends the named lock to expire ttl from now if it is still
+	// held under token, and reports whether it is.
+	Refresh(ctx context.Context, name, token string, ttl time.Duration) (bool, error)
+}
+
+// LocalLocker is a Locker whose locks are held in memory, shared only
+// within one process. The zero value is ready to use.
+type LocalLocker struct {
+	mu    sync.Mutex
+	locks map[string]localLock
+}
+
+// localLock is a held lock and when it expires.
+type localLock struct {
+	token   string
+	expires time.Time
+}
+
+// TryLock acquires the named lock for ttl, unless it is held and unexpired.
+func (l *LocalLocker) TryLock(_ context.Context, name string, ttl time.Duration) (string, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if held, ok := l.locks[name]; ok && now.Before(held.expires) {
+		return "", false, nil
+	}
+	if l.locks == nil {
+		l.locks = make(map[string]localLock)
+	}
+	token := ai.NewID()
+	l.locks[name] = localLock{token: token, expires: now.Add(ttl)}
+	return token, true, nil
+}
+
+// Refresh extends the named lock if it is held under token and unexpired.
+func (l *LocalLocker) Refresh(_ context.Context, name, token string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	held, ok := l.locks[name]
+	if !ok || held.token != token || !time.Now().Before(held.expires) {
+		return false, nil
+	}
+	l.locks[name] = localLock{token: token, expires: time.Now().Add(ttl)}
+	return true, nil
+}
+
+// Unlock releases the named lock if it is held under token.
+func (l *LocalLocker) Unlock(_ context.Context, name, token string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if held, ok := l.locks[name]; ok && held.token == token {
+		delete(l.locks, name)
+	}
+	return nil
+}
+
+// lockPollInterval is how often Lock retries a held lock.
+const lockPollInterval = 20 * time.Millisecond
+
+// localLocks holds the locks of adapters that are not Lockers.
+var localLocks LocalLocker
+
+// Hold takes the named lock through adapter if it is a Locker, and
+// otherwise within this process only, returning false if it is held. While
+// held, the lock is refreshed every ttl/2 if the Locker is a Refresher, so
+// it lasts until release is called however long that takes, and expires
+// within ttl if the process dies.
+func Hold(ctx context.Context, adapter Adapter, name string, ttl time.Duration) (release func(), ok bool, err error) {
+	locker, isLocker := adapter.(Locker)
+	if !isLocker {
+		locker = &localLocks
+	}
+	token, ok, err := locker.TryLock(ctx, name, ttl)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		refresher, ok := locker.(Refresher)
+		if !ok {
+			return
+		}
+		ticker := time.NewTicker(ttl / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if held, err := refresher.Refresh(ctx, name, token, ttl); err == nil && !held {
+					return
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+			locker.Unlock(ctx, name, token)
+		})
+	}, true, nil
+}
+
+// Lock waits for and takes the named lock for ttl, through adapter if it is
+// a Locker and otherwise held within this process only. It returns a
+// function releasing the lock.
+func Lock(ctx context.Context, adapter Adapter, name string, ttl time.Duration) (unlock func(), err error) {
+	locker, ok := adapter.(Locker)
+	if !ok {
+		locker = &localLocks
+	}
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+	for {
+		token, ok, err := locker.TryLock(ctx, name, ttl)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return func() { locker.Unlock(context.WithoutCancel(ctx), name, token) }, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
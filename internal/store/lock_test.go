@@ -0,0 +1,85 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalLocker(t *testing.T) {
+	ctx := context.Background()
+	var l LocalLocker
+
+	token, ok, err := l.TryLock(ctx, "a", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, ok, _ = l.TryLock(ctx, "a", time.Minute)
+	assert.False(t, ok)
+
+	require.NoError(t, l.Unlock(ctx, "a", "wrong"))
+	_, ok, _ = l.TryLock(ctx, "a", time.Minute)
+	assert.False(t, ok, "only the holder can unlock")
+
+	require.NoError(t, l.Unlock(ctx, "a", token))
+	_, ok, _ = l.TryLock(ctx, "a", time.Millisecond)
+	assert.True(t, ok)
+
+	time.Sleep(5 * time.Millisecond)
+	_, ok, _ = l.TryLock(ctx, "a", time.Minute)
+	assert.True(t, ok, "expired locks can be taken")
+}
+
+func TestMemoryAdapter_IsLocker(t *testing.T) {
+	var _ Locker = NewMemoryAdapter()
+}
+
+func TestLock(t *testing.T) {
+	ctx := context.Background()
+	adapter := NewMemoryAdapter()
+
+	unlock, err := Lock(ctx, adapter, "a", time.Minute)
+	require.NoError(t, err)
+
+	waitCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = Lock(waitCtx, adapter, "a", time.Minute)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "waits while the lock is held")
+
+	acquired := make(chan struct{})
+	go func() {
+		if unlock, err := Lock(ctx, adapter, "a", time.Minute); err == nil {
+			unlock()
+			close(acquired)
+		}
+	}()
+	unlock()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("lock not taken after release")
+	}
+}
+
+func TestHold(t *testing.T) {
+	ctx := context.Background()
+	adapter := NewMemoryAdapter()
+
+	release, ok, err := Hold(ctx, adapter, "a", 20*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	time.Sleep(60 * time.Millisecond)
+	_, ok, err = Hold(ctx, adapter, "a", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "a held lock is refreshed past its ttl")
+
+	release()
+	release2, ok, err := Hold(ctx, adapter, "a", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok, "released locks can be taken")
+	release2()
+}
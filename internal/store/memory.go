@@ -6,8 +6,11 @@ import (
 	"sync"
 )
 
-// MemoryAdapter provides thread-safe in-memory storage.
+// MemoryAdapter provides thread-safe in-memory storage. Its locks are
+// shared within the process only.
 type MemoryAdapter struct {
+	LocalLocker
+
 	mu   sync.RWMutex
 	data map[string]json.RawMessage
 }
@@ -98,3 +101,21 @@ func (m *MemoryAdapter) Save(_ context.Context, data map[string]json.RawMessage)
 	}
 	return nil
 }
+
+// SaveRevision replaces all data if the stored revision equals expected.
+func (m *MemoryAdapter) SaveRevision(_ context.Context, data map[string]json.RawMessage, expected int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	actual, err := parseRevision(m.data[RevisionKey])
+	if err != nil {
+		return err
+	}
+	if actual != expected {
+		return &ConflictError{Expected: expected, Actual: actual}
+	}
+	m.data = make(map[string]json.RawMessage, len(data))
+	for k, v := range data {
+		m.data[k] = v
+	}
+	return nil
+}
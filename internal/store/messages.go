@@ -13,6 +13,7 @@ type MessageStore struct {
 	mu       sync.RWMutex
 	messages []ai.Message
 	adapter  Adapter
+	version  int64 // last read or written, for adapters that are Versioners
 }
 
 // NewMessageStore creates a new MessageStore with the given adapter.
@@ -70,6 +71,18 @@ func (m *MessageStore) Clear() {
 	m.messages = make([]ai.Message, 0)
 }
 
+// Truncate keeps the first n messages and removes the rest.
+func (m *MessageStore) Truncate(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if n < 0 {
+		n = 0
+	}
+	if n < len(m.messages) {
+		m.messages = m.messages[:n:n]
+	}
+}
+
 // Clone creates a deep copy of the MessageStore.
 func (m *MessageStore) Clone() *MessageStore {
 	m.mu.RLock()
@@ -102,21 +115,33 @@ func (m *MessageStore) Last(n int) []ai.Message {
 	return result
 }
 
-// Sync persists the messages to the adapter under the given key.
+// Sync persists the messages to the adapter under the given key. If the
+// adapter is a Versioner, Sync fails with an error matching ErrConflict
+// when the key changed since this store last read or wrote it; a store
+// that has done neither expects the key not to exist.
 func (m *MessageStore) Sync(ctx context.Context, key string) error {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
 	raw, err := json.Marshal(m.messages)
 	if err != nil {
 		return &SerializationError{Key: key, Err: err}
 	}
-	return m.adapter.Set(ctx, key, raw)
+	v, ok := m.adapter.(Versioner)
+	if !ok {
+		return m.adapter.Set(ctx, key, raw)
+	}
+	version, err := v.SetVersion(ctx, key, raw, m.version)
+	if err != nil {
+		return err
+	}
+	m.version = version
+	return nil
 }
 
 // Reload loads messages from the adapter using the given key.
 func (m *MessageStore) Reload(ctx context.Context, key string) error {
-	raw, ok, err := m.adapter.Get(ctx, key)
+	raw, version, ok, err := GetVersion(ctx, m.adapter, key)
 	if err != nil {
 		return err
 	}
@@ -132,6 +157,7 @@ func (m *MessageStore) Reload(ctx context.Context, key string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.messages = messages
+	m.version = version
 	return nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 
 	ai "github.com/spetersoncode/gains"
 	"github.com/stretchr/testify/assert"
@@ -57,6 +58,24 @@ func TestMessageStore_Clear(t *testing.T) {
 	assert.Empty(t, ms.Messages())
 }
 
+func TestMessageStore_Truncate(t *testing.T) {
+	ms := NewMessageStore(nil)
+	ms.Append(
+		ai.Message{Role: ai.RoleUser, Content: "1"},
+		ai.Message{Role: ai.RoleAssistant, Content: "2"},
+		ai.Message{Role: ai.RoleUser, Content: "3"},
+	)
+
+	ms.Truncate(5)
+	assert.Equal(t, 3, ms.Len())
+
+	ms.Truncate(1)
+	assert.Equal(t, []ai.Message{{Role: ai.RoleUser, Content: "1"}}, ms.Messages())
+
+	ms.Truncate(-1)
+	assert.Equal(t, 0, ms.Len())
+}
+
 func TestMessageStore_Clone(t *testing.T) {
 	ms := NewMessageStore(nil)
 
@@ -146,6 +165,30 @@ func TestMessageStore_SyncReload(t *testing.T) {
 	assert.Equal(t, "Hi there", messages[1].Content)
 }
 
+func TestMessageStore_SyncReloadMetadata(t *testing.T) {
+	ctx := context.Background()
+	adapter := NewMemoryAdapter()
+	created := time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC)
+
+	ms1 := NewMessageStore(adapter)
+	ms1.Append(ai.Message{
+		Role:      ai.RoleUser,
+		Content:   "Hello",
+		Name:      "alice",
+		CreatedAt: created,
+		Metadata:  map[string]string{"channel": "support"},
+	})
+	require.NoError(t, ms1.Sync(ctx, "conversation"))
+
+	ms2 := NewMessageStore(adapter)
+	require.NoError(t, ms2.Reload(ctx, "conversation"))
+
+	msg := ms2.Messages()[0]
+	assert.Equal(t, "alice", msg.Name)
+	assert.True(t, created.Equal(msg.CreatedAt))
+	assert.Equal(t, map[string]string{"channel": "support"}, msg.Metadata)
+}
+
 func TestMessageStore_ReloadNotFound(t *testing.T) {
 	ctx := context.Background()
 	ms := NewMessageStore(nil)
@@ -3,14 +3,20 @@ package store
 import (
 	"context"
 	"encoding/json"
+	"maps"
 	"sync"
 )
 
+// RevisionKey is the reserved adapter key under which Store persists its
+// revision number. It is never visible in the store's own keys.
+const RevisionKey = "_revision"
+
 // Store provides thread-safe key-value state management with pluggable persistence.
 type Store struct {
-	mu      sync.RWMutex
-	adapter Adapter
-	cache   map[string]any
+	mu       sync.RWMutex
+	adapter  Adapter
+	cache    map[string]any
+	revision int64
 }
 
 // New creates a new Store with the given adapter.
@@ -172,23 +178,25 @@ func (s *Store) Merge(other *Store) {
 	}
 }
 
-// Sync persists the current cache to the adapter.
+// Sync persists the current cache to the adapter along with the next
+// revision number. It overwrites the stored state unconditionally; use
+// UpdateCAS when other processes may write the same adapter.
 func (s *Store) Sync(ctx context.Context) error {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	data := make(map[string]json.RawMessage, len(s.cache))
-	for k, v := range s.cache {
-		raw, err := json.Marshal(v)
-		if err != nil {
-			return &SerializationError{Key: k, Err: err}
-		}
-		data[k] = raw
+	data, err := encodeState(s.cache, s.revision+1)
+	if err != nil {
+		return err
 	}
-	return s.adapter.Save(ctx, data)
+	if err := s.adapter.Save(ctx, data); err != nil {
+		return err
+	}
+	s.revision++
+	return nil
 }
 
-// Reload loads data from the adapter into the cache.
+// Reload loads data from the adapter into the cache and records its revision.
 func (s *Store) Reload(ctx context.Context) error {
 	data, err := s.adapter.Load(ctx)
 	if err != nil {
@@ -198,17 +206,124 @@ func (s *Store) Reload(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	revision, err := parseRevision(data[RevisionKey])
+	if err != nil {
+		return err
+	}
 	s.cache = make(map[string]any, len(data))
 	for k, raw := range data {
+		if k == RevisionKey {
+			continue
+		}
 		var v any
 		if err := json.Unmarshal(raw, &v); err != nil {
 			return &SerializationError{Key: k, Err: err}
 		}
 		s.cache[k] = v
 	}
+	s.revision = revision
 	return nil
 }
 
+// UpdateCAS applies fn to a copy of the cached data and persists the result
+// only if the stored revision is unchanged since the last Reload or
+// successful write. If another process wrote in the meantime it returns an
+// error matching ErrConflict and leaves the cache untouched; call Reload and
+// retry. An error from fn aborts the update.
+//
+//	for {
+//	    err := s.UpdateCAS(ctx, func(data map[string]any) error {
+//	        data["status"] = "approved"
+//	        return nil
+//	    })
+//	    if !errors.Is(err, store.ErrConflict) {
+//	        return err
+//	    }
+//	    if err := s.Reload(ctx); err != nil {
+//	        return err
+//	    }
+//	}
+func (s *Store) UpdateCAS(ctx context.Context, fn func(data map[string]any) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := maps.Clone(s.cache)
+	if err := fn(next); err != nil {
+		return err
+	}
+
+	data, err := encodeState(next, s.revision+1)
+	if err != nil {
+		return err
+	}
+	if err := s.saveIfRevision(ctx, data); err != nil {
+		return err
+	}
+	s.cache = next
+	s.revision++
+	return nil
+}
+
+// Revision returns the revision last loaded by Reload or written by Sync or
+// UpdateCAS. A store that has never been persisted is at revision 0.
+func (s *Store) Revision() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.revision
+}
+
+// saveIfRevision saves data if the stored revision matches s.revision.
+// Adapters implementing RevisionSaver, such as the memory and Redis
+// adapters, check atomically; for others the revision is read first,
+// leaving a window in which a concurrent writer can be overwritten.
+func (s *Store) saveIfRevision(ctx context.Context, data map[string]json.RawMessage) error {
+	if rs, ok := s.adapter.(RevisionSaver); ok {
+		return rs.SaveRevision(ctx, data, s.revision)
+	}
+	raw, _, err := s.adapter.Get(ctx, RevisionKey)
+	if err != nil {
+		return err
+	}
+	actual, err := parseRevision(raw)
+	if err != nil {
+		return err
+	}
+	if actual != s.revision {
+		return &ConflictError{Expected: s.revision, Actual: actual}
+	}
+	return s.adapter.Save(ctx, data)
+}
+
+// encodeState marshals cache for the adapter, adding the revision.
+func encodeState(cache map[string]any, revision int64) (map[string]json.RawMessage, error) {
+	data := make(map[string]json.RawMessage, len(cache)+1)
+	for k, v := range cache {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, &SerializationError{Key: k, Err: err}
+		}
+		data[k] = raw
+	}
+	rev, err := json.Marshal(revision)
+	if err != nil {
+		return nil, &SerializationError{Key: RevisionKey, Err: err}
+	}
+	data[RevisionKey] = rev
+	return data, nil
+}
+
+// parseRevision decodes a stored revision. A missing value is revision 0.
+func parseRevision(raw json.RawMessage) (int64, error) {
+	if len(raw) == 0 {
+		return 0, nil
+	}
+	var revision int64
+	if err := json.Unmarshal(raw, &revision); err != nil {
+		return 0, &SerializationError{Key: RevisionKey, Err: err}
+	}
+	return revision, nil
+}
+
 // Data returns a shallow copy of the internal cache map.
 func (s *Store) Data() map[string]any {
 	s.mu.RLock()
@@ -188,6 +188,91 @@ func TestStore_SyncReload(t *testing.T) {
 	assert.Equal(t, 30, s2.GetInt("age"))
 }
 
+func TestStore_Revision(t *testing.T) {
+	ctx := context.Background()
+	adapter := NewMemoryAdapter()
+
+	s1 := New(adapter)
+	assert.Equal(t, int64(0), s1.Revision())
+	s1.Set("name", "Alice")
+	require.NoError(t, s1.Sync(ctx))
+	require.NoError(t, s1.Sync(ctx))
+	assert.Equal(t, int64(2), s1.Revision())
+
+	s2 := New(adapter)
+	require.NoError(t, s2.Reload(ctx))
+	assert.Equal(t, int64(2), s2.Revision())
+	assert.Equal(t, []string{"name"}, s2.Keys(), "revision key is not visible")
+}
+
+func TestStore_UpdateCAS(t *testing.T) {
+	ctx := context.Background()
+	setOwner := func(owner string) func(map[string]any) error {
+		return func(data map[string]any) error {
+			data["owner"] = owner
+			return nil
+		}
+	}
+
+	t.Run("conflicts after a concurrent write", func(t *testing.T) {
+		adapter := NewMemoryAdapter()
+		s1 := New(adapter)
+		s2 := New(adapter)
+		require.NoError(t, s1.Reload(ctx))
+		require.NoError(t, s2.Reload(ctx))
+
+		require.NoError(t, s1.UpdateCAS(ctx, setOwner("s1")))
+
+		err := s2.UpdateCAS(ctx, setOwner("s2"))
+		assert.ErrorIs(t, err, ErrConflict)
+		var conflict *ConflictError
+		require.ErrorAs(t, err, &conflict)
+		assert.Equal(t, int64(0), conflict.Expected)
+		assert.Equal(t, int64(1), conflict.Actual)
+		assert.False(t, s2.Has("owner"), "cache is untouched on conflict")
+
+		require.NoError(t, s2.Reload(ctx))
+		require.NoError(t, s2.UpdateCAS(ctx, setOwner("s2")))
+
+		require.NoError(t, s1.Reload(ctx))
+		assert.Equal(t, "s2", s1.GetString("owner"))
+		assert.Equal(t, int64(2), s1.Revision())
+	})
+
+	t.Run("sync from another store conflicts", func(t *testing.T) {
+		adapter := NewMemoryAdapter()
+		s1 := New(adapter)
+		s2 := New(adapter)
+
+		require.NoError(t, s2.Sync(ctx))
+		assert.ErrorIs(t, s1.UpdateCAS(ctx, setOwner("s1")), ErrConflict)
+	})
+
+	t.Run("function error aborts", func(t *testing.T) {
+		adapter := NewMemoryAdapter()
+		s := New(adapter)
+
+		err := s.UpdateCAS(ctx, func(data map[string]any) error {
+			data["owner"] = "s"
+			return assert.AnError
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.False(t, s.Has("owner"))
+		n, err := adapter.Len(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("adapters without RevisionSaver check with Get", func(t *testing.T) {
+		adapter := struct{ Adapter }{NewMemoryAdapter()}
+		s1 := New(adapter)
+		s2 := New(adapter)
+
+		require.NoError(t, s1.UpdateCAS(ctx, setOwner("s1")))
+		assert.ErrorIs(t, s2.UpdateCAS(ctx, setOwner("s2")), ErrConflict)
+	})
+}
+
 func TestStore_Concurrent(t *testing.T) {
 	s := New(nil)
 	var wg sync.WaitGroup
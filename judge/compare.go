@@ -0,0 +1,90 @@
+package judge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// Preference is the outcome of a pairwise comparison.
+type Preference string
+
+// Pairwise comparison outcomes.
+const (
+	PreferA Preference = "a"
+	PreferB Preference = "b"
+	Tie     Preference = "tie"
+)
+
+const compareSystemPrompt = `You are an impartial judge of AI assistant responses. Compare two responses to the same prompt on the criteria given and decide which is better, or call a tie if neither is meaningfully better. Judge only the responses, not the prompt. Do not let the order of the responses or their length influence the decision beyond what the criteria ask for.`
+
+// Comparison is a judge's decision between two responses.
+type Comparison struct {
+	// Preferred is the better response, or Tie.
+	Preferred Preference `json:"preferred"`
+	// Reasoning explains the decision.
+	Reasoning string `json:"reasoning"`
+	// Usage is the token usage of the judge's request.
+	Usage ai.Usage `json:"usage"`
+}
+
+// comparisonOutput is the structured output requested from the judge model.
+// Reasoning comes first so the model explains before it decides.
+type comparisonOutput struct {
+	Reasoning string `json:"reasoning" desc:"Brief explanation of the decision" required:"true"`
+	Preferred string `json:"preferred" desc:"The better response: a, b, or tie" required:"true" enum:"a,b,tie"`
+}
+
+// Compare decides which of responses a and b better answers prompt. It
+// returns an error if the request fails or the judge does not return a
+// valid decision.
+//
+// Judges tend to favor the response they read first; Gate compares each
+// pair in both orders to cancel that out.
+func (j *Judge) Compare(ctx context.Context, prompt, a, b string) (*Comparison, error) {
+	messages := []ai.Message{
+		{Role: ai.RoleSystem, Content: compareSystemPrompt},
+		{Role: ai.RoleUser, Content: j.compareRequest(prompt, a, b)},
+	}
+	opts := append([]ai.Option{
+		ai.WithResponseSchema(ai.ResponseSchema{
+			Name:   "comparison",
+			Schema: ai.MustSchemaFor[comparisonOutput](),
+		}),
+		ai.WithTemperature(0),
+	}, j.chatOptions...)
+
+	resp, err := j.client.Chat(ctx, messages, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("judge: %w", err)
+	}
+	var out comparisonOutput
+	if err := json.Unmarshal([]byte(resp.Content), &out); err != nil {
+		return nil, &ai.UnmarshalError{Content: resp.Content, TargetType: "judge.Comparison", Err: err}
+	}
+	preferred := Preference(strings.ToLower(strings.TrimSpace(out.Preferred)))
+	switch preferred {
+	case PreferA, PreferB, Tie:
+	default:
+		return nil, fmt.Errorf("judge: invalid preference %q", out.Preferred)
+	}
+	return &Comparison{Preferred: preferred, Reasoning: out.Reasoning, Usage: resp.Usage}, nil
+}
+
+// compareRequest builds the judge's user message for a comparison.
+func (j *Judge) compareRequest(prompt, a, b string) string {
+	var sb strings.Builder
+	sb.WriteString("Criteria:\n")
+	sb.WriteString(j.criteria)
+	sb.WriteString("\n\n<prompt>\n")
+	sb.WriteString(prompt)
+	sb.WriteString("\n</prompt>\n\n<response_a>\n")
+	sb.WriteString(a)
+	sb.WriteString("\n</response_a>\n\n<response_b>\n")
+	sb.WriteString(b)
+	sb.WriteString("\n</response_b>")
+	return sb.String()
+}
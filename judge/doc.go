@@ -0,0 +1,27 @@
+// Package judge scores model responses with an LLM judge, for comparing
+// models and prompts on quality alongside cost and latency.
+//
+// A [Judge] asks a model to rate a response to a prompt from 1 to 10
+// against its criteria and explain the rating:
+//
+//	j := judge.New(c, judge.WithModel(model.ClaudeSonnet45),
+//	    judge.WithCriteria("Accuracy and concision."))
+//	verdict, err := j.Score(ctx, prompt, resp.Content)
+//	fmt.Println(verdict.Score, verdict.Reasoning)
+//
+// [Judge.Compare] decides instead which of two responses is better, and a
+// [Gate] uses it to check a candidate version of a prompt or workflow
+// against the current one before release. It answers each case of a
+// dataset with both, has the judge compare the responses in both orders to
+// cancel out position bias, and reports the candidate's win rate:
+//
+//	gate := judge.NewGate(j, judge.WithMinWinRate(0.5))
+//	result, err := gate.Run(ctx, cases,
+//	    judge.Prompted(c, currentPrompt), judge.Prompted(c, candidatePrompt))
+//	if err == nil && !result.Passed {
+//	    t.Fatalf("candidate win rate %.2f", result.WinRate)
+//	}
+//
+// Use a stronger model than the ones being judged where possible, and keep
+// the judge fixed across the runs being compared.
+package judge
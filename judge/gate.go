@@ -0,0 +1,188 @@
+package judge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/chat"
+)
+
+// DefaultMinWinRate is the win rate a candidate needs to pass a Gate when
+// none is set: it must do at least as well as the baseline.
+const DefaultMinWinRate = 0.5
+
+// ErrEmptyDataset is returned by Gate.Run without cases.
+var ErrEmptyDataset = errors.New("judge: empty dataset")
+
+// Responder answers a prompt with one version of a prompt, agent, or
+// workflow.
+type Responder func(ctx context.Context, prompt string) (string, error)
+
+// Prompted returns a Responder that sends the prompt to c after the system
+// prompt system, for comparing versions of a system prompt.
+func Prompted(c chat.Client, system string, opts ...ai.Option) Responder {
+	return func(ctx context.Context, prompt string) (string, error) {
+		var messages []ai.Message
+		if system != "" {
+			messages = append(messages, ai.Message{Role: ai.RoleSystem, Content: system})
+		}
+		messages = append(messages, ai.Message{Role: ai.RoleUser, Content: prompt})
+		resp, err := c.Chat(ctx, messages, opts...)
+		if err != nil {
+			return "", err
+		}
+		return resp.Content, nil
+	}
+}
+
+// Case is one prompt of a Gate's dataset.
+type Case struct {
+	Name   string `json:"name"`
+	Prompt string `json:"prompt"`
+}
+
+// Outcome is how a candidate fared against the baseline on one case.
+type Outcome string
+
+// Case outcomes.
+const (
+	OutcomeWin  Outcome = "win"
+	OutcomeLoss Outcome = "loss"
+	OutcomeTie  Outcome = "tie"
+)
+
+// CaseResult is the outcome of one case of a Gate run.
+type CaseResult struct {
+	Case      string  `json:"case"`
+	Baseline  string  `json:"baseline"`
+	Candidate string  `json:"candidate"`
+	Outcome   Outcome `json:"outcome"`
+	// Comparisons are the judge's decisions with the baseline shown first
+	// and then with the candidate shown first.
+	Comparisons []Comparison `json:"comparisons"`
+}
+
+// GateResult is the verdict of a Gate run, suitable for failing a CI job
+// when Passed is false.
+type GateResult struct {
+	// Passed reports whether WinRate reached MinWinRate.
+	Passed bool `json:"passed"`
+	// WinRate is the candidate's share of wins, counting ties as half.
+	WinRate    float64      `json:"winRate"`
+	MinWinRate float64      `json:"minWinRate"`
+	Wins       int          `json:"wins"`
+	Losses     int          `json:"losses"`
+	Ties       int          `json:"ties"`
+	Results    []CaseResult `json:"results"`
+	// Usage is the token usage of the judge's requests.
+	Usage ai.Usage `json:"usage"`
+}
+
+// Gate decides whether a candidate version of a prompt or workflow may
+// replace the current one, by having a judge compare their responses on a
+// dataset.
+type Gate struct {
+	judge      *Judge
+	minWinRate float64
+}
+
+// GateOption configures a Gate.
+type GateOption func(*Gate)
+
+// WithMinWinRate sets the win rate, from 0 to 1 with ties counting as half
+// a win, that a candidate needs to pass. Default is DefaultMinWinRate.
+func WithMinWinRate(rate float64) GateOption {
+	return func(g *Gate) {
+		g.minWinRate = rate
+	}
+}
+
+// NewGate returns a Gate that compares responses with j.
+func NewGate(j *Judge, opts ...GateOption) *Gate {
+	g := &Gate{judge: j, minWinRate: DefaultMinWinRate}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// Run answers each case with baseline and candidate and has the judge
+// compare the responses twice, once in each order. A case is a win or loss
+// for the candidate only if the two decisions together favor it or the
+// baseline; otherwise it is a tie. Run returns an error if a responder or
+// the judge fails.
+func (g *Gate) Run(ctx context.Context, cases []Case, baseline, candidate Responder) (*GateResult, error) {
+	if len(cases) == 0 {
+		return nil, ErrEmptyDataset
+	}
+	result := &GateResult{MinWinRate: g.minWinRate}
+	for _, c := range cases {
+		r, err := g.runCase(ctx, c, baseline, candidate)
+		if err != nil {
+			return nil, fmt.Errorf("judge: case %q: %w", c.Name, err)
+		}
+		for _, cmp := range r.Comparisons {
+			result.Usage.InputTokens += cmp.Usage.InputTokens
+			result.Usage.OutputTokens += cmp.Usage.OutputTokens
+			result.Usage.CachedInputTokens += cmp.Usage.CachedInputTokens
+			result.Usage.CacheWriteInputTokens += cmp.Usage.CacheWriteInputTokens
+		}
+		switch r.Outcome {
+		case OutcomeWin:
+			result.Wins++
+		case OutcomeLoss:
+			result.Losses++
+		default:
+			result.Ties++
+		}
+		result.Results = append(result.Results, r)
+	}
+	result.WinRate = (float64(result.Wins) + float64(result.Ties)/2) / float64(len(cases))
+	result.Passed = result.WinRate >= g.minWinRate
+	return result, nil
+}
+
+// runCase answers and judges one case.
+func (g *Gate) runCase(ctx context.Context, c Case, baseline, candidate Responder) (CaseResult, error) {
+	r := CaseResult{Case: c.Name}
+	var err error
+	if r.Baseline, err = baseline(ctx, c.Prompt); err != nil {
+		return r, fmt.Errorf("baseline: %w", err)
+	}
+	if r.Candidate, err = candidate(ctx, c.Prompt); err != nil {
+		return r, fmt.Errorf("candidate: %w", err)
+	}
+
+	// Each decision scores +1 for the candidate, -1 for the baseline
+	var score int
+	for _, candidateFirst := range []bool{false, true} {
+		a, b := r.Baseline, r.Candidate
+		if candidateFirst {
+			a, b = b, a
+		}
+		cmp, err := g.judge.Compare(ctx, c.Prompt, a, b)
+		if err != nil {
+			return r, err
+		}
+		switch {
+		case cmp.Preferred == Tie:
+		case (cmp.Preferred == PreferA) == candidateFirst:
+			score++
+		default:
+			score--
+		}
+		r.Comparisons = append(r.Comparisons, *cmp)
+	}
+
+	switch {
+	case score > 0:
+		r.Outcome = OutcomeWin
+	case score < 0:
+		r.Outcome = OutcomeLoss
+	default:
+		r.Outcome = OutcomeTie
+	}
+	return r, nil
+}
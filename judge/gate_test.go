@@ -0,0 +1,108 @@
+package judge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/testkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJudge_Compare(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns the comparison", func(t *testing.T) {
+		replay := testkit.NewReplay(ai.Response{Content: `{"reasoning":"B is correct.","preferred":"b"}`})
+		cmp, err := New(replay, WithCriteria("Accuracy.")).Compare(ctx, "What is 2+2?", "5", "4")
+		require.NoError(t, err)
+		assert.Equal(t, PreferB, cmp.Preferred)
+		assert.Equal(t, "B is correct.", cmp.Reasoning)
+
+		request := replay.Requests()[0][1].Content
+		assert.Contains(t, request, "Accuracy.")
+		assert.Contains(t, request, "<response_a>\n5\n</response_a>")
+		assert.Contains(t, request, "<response_b>\n4\n</response_b>")
+	})
+
+	t.Run("rejects invalid preferences", func(t *testing.T) {
+		j := New(testkit.NewReplay(ai.Response{Content: `{"reasoning":"Both","preferred":"both"}`}))
+		_, err := j.Compare(ctx, "prompt", "a", "b")
+		assert.ErrorContains(t, err, "invalid preference")
+	})
+}
+
+func TestGate_Run(t *testing.T) {
+	ctx := context.Background()
+	decision := func(preferred Preference) ai.Response {
+		return ai.Response{
+			Content: `{"reasoning":"","preferred":"` + string(preferred) + `"}`,
+			Usage:   ai.Usage{InputTokens: 10, OutputTokens: 2},
+		}
+	}
+	respond := func(answer string) Responder {
+		return func(ctx context.Context, prompt string) (string, error) { return answer, nil }
+	}
+	cases := []Case{{Name: "one", Prompt: "1"}, {Name: "two", Prompt: "2"}, {Name: "three", Prompt: "3"}}
+
+	t.Run("counts consistent preferences and ties", func(t *testing.T) {
+		replay := testkit.NewReplay(
+			decision(PreferB), decision(PreferA), // candidate wins in both orders
+			decision(PreferA), decision(PreferA), // position bias: tie
+			decision(Tie), decision(PreferA), // candidate wins once
+		)
+		result, err := NewGate(New(replay)).Run(ctx, cases, respond("old"), respond("new"))
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, result.Wins)
+		assert.Equal(t, 1, result.Ties)
+		assert.Equal(t, 0, result.Losses)
+		assert.InDelta(t, 5.0/6, result.WinRate, 1e-9)
+		assert.True(t, result.Passed)
+		assert.Equal(t, 60, result.Usage.InputTokens)
+		assert.Equal(t, []Outcome{OutcomeWin, OutcomeTie, OutcomeWin},
+			[]Outcome{result.Results[0].Outcome, result.Results[1].Outcome, result.Results[2].Outcome})
+
+		// The candidate is shown second, then first
+		requests := replay.Requests()
+		assert.Contains(t, requests[0][1].Content, "<response_a>\nold\n")
+		assert.Contains(t, requests[1][1].Content, "<response_a>\nnew\n")
+	})
+
+	t.Run("fails below the minimum win rate", func(t *testing.T) {
+		replay := testkit.NewReplay(
+			decision(PreferA), decision(PreferB),
+			decision(Tie), decision(Tie),
+			decision(PreferB), decision(PreferA),
+		)
+		result, err := NewGate(New(replay), WithMinWinRate(0.6)).Run(ctx, cases, respond("old"), respond("new"))
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Losses)
+		assert.InDelta(t, 0.5, result.WinRate, 1e-9)
+		assert.False(t, result.Passed)
+	})
+
+	t.Run("returns responder errors", func(t *testing.T) {
+		failing := func(ctx context.Context, prompt string) (string, error) { return "", errors.New("boom") }
+		_, err := NewGate(New(testkit.NewReplay())).Run(ctx, cases, respond("old"), failing)
+		assert.ErrorContains(t, err, `case "one": candidate: boom`)
+	})
+
+	t.Run("rejects an empty dataset", func(t *testing.T) {
+		_, err := NewGate(New(testkit.NewReplay())).Run(ctx, nil, respond("old"), respond("new"))
+		assert.ErrorIs(t, err, ErrEmptyDataset)
+	})
+}
+
+func TestPrompted(t *testing.T) {
+	replay := testkit.NewReplay(ai.Response{Content: "4"})
+	answer, err := Prompted(replay, "Be brief.")(context.Background(), "What is 2+2?")
+	require.NoError(t, err)
+	assert.Equal(t, "4", answer)
+
+	request := replay.Requests()[0]
+	assert.Equal(t, "Be brief.", request[0].Content)
+	assert.Equal(t, "What is 2+2?", request[1].Content)
+}
@@ -0,0 +1,122 @@
+package judge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/chat"
+)
+
+// MinScore and MaxScore bound the scores a Judge gives.
+const (
+	MinScore = 1
+	MaxScore = 10
+)
+
+// DefaultCriteria is what a Judge rates responses on when none are set.
+const DefaultCriteria = "Helpfulness, correctness, and clarity of the response to the prompt."
+
+const systemPrompt = `You are an impartial judge of AI assistant responses. Rate the response to the prompt on the criteria given, from 1 (unusable) to 10 (excellent). Judge only the response, not the prompt. Do not let the length of the response influence the rating beyond what the criteria ask for.`
+
+// Verdict is a judge's rating of one response.
+type Verdict struct {
+	// Score is the rating, from MinScore to MaxScore.
+	Score int `json:"score"`
+	// Reasoning explains the rating.
+	Reasoning string `json:"reasoning"`
+	// Usage is the token usage of the judge's request.
+	Usage ai.Usage `json:"usage"`
+}
+
+// verdictOutput is the structured output requested from the judge model.
+// Reasoning comes first so the model explains before it rates.
+type verdictOutput struct {
+	Reasoning string `json:"reasoning" desc:"Brief explanation of the rating" required:"true"`
+	Score     int    `json:"score" desc:"Rating from 1 (unusable) to 10 (excellent)" required:"true" min:"1" max:"10"`
+}
+
+// Judge rates responses with a chat model.
+type Judge struct {
+	client      chat.Client
+	criteria    string
+	chatOptions []ai.Option
+}
+
+// Option configures a Judge.
+type Option func(*Judge)
+
+// WithCriteria sets what responses are rated on. Default is
+// DefaultCriteria.
+func WithCriteria(criteria string) Option {
+	return func(j *Judge) {
+		j.criteria = criteria
+	}
+}
+
+// WithModel sets the model that rates responses.
+func WithModel(model ai.Model) Option {
+	return func(j *Judge) {
+		j.chatOptions = append(j.chatOptions, ai.WithModel(model))
+	}
+}
+
+// WithChatOptions passes options to the judge's chat requests.
+func WithChatOptions(opts ...ai.Option) Option {
+	return func(j *Judge) {
+		j.chatOptions = append(j.chatOptions, opts...)
+	}
+}
+
+// New returns a Judge that rates responses with c.
+func New(c chat.Client, opts ...Option) *Judge {
+	j := &Judge{client: c, criteria: DefaultCriteria}
+	for _, opt := range opts {
+		opt(j)
+	}
+	return j
+}
+
+// Score rates response as an answer to prompt. It returns an error if the
+// request fails or the judge does not return a valid rating.
+func (j *Judge) Score(ctx context.Context, prompt, response string) (*Verdict, error) {
+	messages := []ai.Message{
+		{Role: ai.RoleSystem, Content: systemPrompt},
+		{Role: ai.RoleUser, Content: j.request(prompt, response)},
+	}
+	opts := append([]ai.Option{
+		ai.WithResponseSchema(ai.ResponseSchema{
+			Name:   "verdict",
+			Schema: ai.MustSchemaFor[verdictOutput](),
+		}),
+		ai.WithTemperature(0),
+	}, j.chatOptions...)
+
+	resp, err := j.client.Chat(ctx, messages, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("judge: %w", err)
+	}
+	var out verdictOutput
+	if err := json.Unmarshal([]byte(resp.Content), &out); err != nil {
+		return nil, &ai.UnmarshalError{Content: resp.Content, TargetType: "judge.Verdict", Err: err}
+	}
+	if out.Score < MinScore || out.Score > MaxScore {
+		return nil, fmt.Errorf("judge: score %d out of range %d-%d", out.Score, MinScore, MaxScore)
+	}
+	return &Verdict{Score: out.Score, Reasoning: out.Reasoning, Usage: resp.Usage}, nil
+}
+
+// request builds the judge's user message.
+func (j *Judge) request(prompt, response string) string {
+	var b strings.Builder
+	b.WriteString("Criteria:\n")
+	b.WriteString(j.criteria)
+	b.WriteString("\n\n<prompt>\n")
+	b.WriteString(prompt)
+	b.WriteString("\n</prompt>\n\n<response>\n")
+	b.WriteString(response)
+	b.WriteString("\n</response>")
+	return b.String()
+}
@@ -0,0 +1,56 @@
+package judge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/model"
+	"github.com/spetersoncode/gains/testkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJudge_Score(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns the verdict", func(t *testing.T) {
+		replay := testkit.NewReplay(ai.Response{
+			Content: `{"reasoning":"Correct and concise.","score":9}`,
+			Usage:   ai.Usage{InputTokens: 120, OutputTokens: 15},
+		})
+		j := New(replay, WithCriteria("Accuracy."), WithModel(model.ClaudeSonnet45))
+
+		verdict, err := j.Score(ctx, "What is 2+2?", "4")
+		require.NoError(t, err)
+		assert.Equal(t, 9, verdict.Score)
+		assert.Equal(t, "Correct and concise.", verdict.Reasoning)
+		assert.Equal(t, 120, verdict.Usage.InputTokens)
+
+		request := replay.Requests()[0]
+		assert.Equal(t, ai.RoleSystem, request[0].Role)
+		assert.Contains(t, request[1].Content, "Accuracy.")
+		assert.Contains(t, request[1].Content, "<prompt>\nWhat is 2+2?\n</prompt>")
+		assert.Contains(t, request[1].Content, "<response>\n4\n</response>")
+	})
+
+	t.Run("rejects scores out of range", func(t *testing.T) {
+		j := New(testkit.NewReplay(ai.Response{Content: `{"reasoning":"Great","score":11}`}))
+		_, err := j.Score(ctx, "prompt", "response")
+		assert.ErrorContains(t, err, "out of range")
+	})
+
+	t.Run("rejects malformed output", func(t *testing.T) {
+		j := New(testkit.NewReplay(ai.Response{Content: "Nine out of ten."}))
+		_, err := j.Score(ctx, "prompt", "response")
+		var unmarshalErr *ai.UnmarshalError
+		assert.True(t, errors.As(err, &unmarshalErr))
+	})
+
+	t.Run("returns request errors", func(t *testing.T) {
+		j := New(testkit.NewReplay())
+		_, err := j.Score(ctx, "prompt", "response")
+		assert.ErrorIs(t, err, testkit.ErrReplayExhausted)
+	})
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/server"
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/tool"
+)
+
+// Transport describes how Connect reaches an MCP server. Use Stdio,
+// StreamableHTTP, SSE, or InProcess to create one.
+type Transport interface {
+	newClient() (*client.Client, error)
+}
+
+// transportFunc adapts a client constructor to Transport.
+type transportFunc func() (*client.Client, error)
+
+func (f transportFunc) newClient() (*client.Client, error) { return f() }
+
+// Stdio starts command as a subprocess and talks to it over stdin and
+// stdout. env entries have the form "KEY=value".
+func Stdio(command string, env []string, args ...string) Transport {
+	return transportFunc(func() (*client.Client, error) {
+		return client.NewStdioMCPClient(command, env, args...)
+	})
+}
+
+// StreamableHTTP connects to an MCP server's streamable HTTP endpoint at
+// url. Options such as transport.WithHTTPHeaders configure the requests.
+func StreamableHTTP(url string, opts ...transport.StreamableHTTPCOption) Transport {
+	return transportFunc(func() (*client.Client, error) {
+		return client.NewStreamableHttpClient(url, opts...)
+	})
+}
+
+// SSE connects to an MCP server using the older HTTP with server-sent
+// events transport.
+func SSE(url string, opts ...transport.ClientOption) Transport {
+	return transportFunc(func() (*client.Client, error) {
+		return client.NewSSEMCPClient(url, opts...)
+	})
+}
+
+// InProcess connects to an MCP server running in the same process, such as
+// one created with NewServer.
+func InProcess(s *server.MCPServer) Transport {
+	return transportFunc(func() (*client.Client, error) {
+		return client.NewInProcessClient(s)
+	})
+}
+
+// Connect connects to the MCP server reached by t, initializes the session,
+// and discovers its tools. Close the returned registry when done.
+//
+// Example:
+//
+//	remote, err := mcp.Connect(ctx, mcp.StreamableHTTP("https://example.com/mcp"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer remote.Close()
+//
+//	registry := tool.NewRegistry()
+//	if err := remote.RegisterTo(registry); err != nil {
+//	    log.Fatal(err)
+//	}
+//	a := agent.New(client, registry)
+func Connect(ctx context.Context, t Transport) (*RemoteRegistry, error) {
+	c, err := t.newClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MCP client: %w", err)
+	}
+	return newRemoteRegistryFromClient(ctx, c)
+}
+
+// Handler returns a tool handler that calls the named tool on the remote
+// server. Errors reported by the remote tool are returned as errors, so
+// tool.Registry turns them into error results.
+func (r *RemoteRegistry) Handler(name string) tool.Handler {
+	return func(ctx context.Context, call ai.ToolCall) (string, error) {
+		call.Name = name
+		result, err := r.client.CallTool(ctx, ToMCPCallToolRequest(call))
+		if err != nil {
+			return "", err
+		}
+		converted := FromMCPCallToolResult(call.ID, result)
+		if converted.IsError {
+			return "", errors.New(converted.Content)
+		}
+		return converted.Content, nil
+	}
+}
+
+// ToolPairs returns the remote tools with proxy handlers, sorted by name,
+// for adding to a registry or an agent.Skill.
+func (r *RemoteRegistry) ToolPairs() []tool.ToolPair {
+	tools := r.Tools()
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+
+	pairs := make([]tool.ToolPair, len(tools))
+	for i, t := range tools {
+		pairs[i] = tool.ToolPair{Tool: t, Handler: r.Handler(t.Name)}
+	}
+	return pairs
+}
+
+// RegisterTo registers every remote tool into registry with a proxy
+// handler. It fails if registry already has a tool of the same name; tools
+// registered before the conflict remain registered. Tools added to the
+// server later are not registered; call Refresh and RegisterTo on a new
+// registry to pick them up.
+func (r *RemoteRegistry) RegisterTo(registry *tool.Registry) error {
+	for _, p := range r.ToolPairs() {
+		if err := registry.Register(p.Tool, p.Handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
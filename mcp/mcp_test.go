@@ -3,12 +3,16 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"net/http/httptest"
 	"testing"
 
 	ai "github.com/spetersoncode/gains"
 	"github.com/spetersoncode/gains/tool"
+	"github.com/spetersoncode/gains/workflow"
 	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -436,3 +440,137 @@ func TestRemoteRegistryIntegration(t *testing.T) {
 		assert.Equal(t, 1, remoteRegistry.Len())
 	})
 }
+
+func TestWorkflowServerIntegration(t *testing.T) {
+	type researchState struct {
+		Topic   string `json:"topic" desc:"Topic to research" required:"true"`
+		Summary string `json:"summary"`
+	}
+
+	var gotTopic string
+	step := workflow.NewFuncStep("research", func(ctx context.Context, state *researchState) error {
+		gotTopic = state.Topic
+		return nil
+	})
+
+	workflows := workflow.NewRegistry()
+	workflows.Register(workflow.NewRunnerJSON("research", step))
+
+	server := NewWorkflowServer(workflows, WithName("test-workflows"))
+	c, err := client.NewInProcessClient(server)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, c.Start(ctx))
+	defer c.Close()
+
+	_, err = c.Initialize(ctx, mcp.InitializeRequest{
+		Params: mcp.InitializeParams{
+			ProtocolVersion: mcp.LATEST_PROTOCOL_VERSION,
+			Capabilities:    mcp.ClientCapabilities{},
+			ClientInfo: mcp.Implementation{
+				Name:    "test-client",
+				Version: "1.0.0",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("derives input schema from state type", func(t *testing.T) {
+		result, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+		require.NoError(t, err)
+		require.Len(t, result.Tools, 1)
+
+		gainsTool := FromMCPTool(result.Tools[0])
+		assert.Equal(t, "research", gainsTool.Name)
+		assert.Contains(t, string(gainsTool.Parameters), `"topic"`)
+		assert.Contains(t, string(gainsTool.Parameters), `"required":["topic"]`)
+	})
+
+	t.Run("runs workflow with arguments as state", func(t *testing.T) {
+		result, err := c.CallTool(ctx, mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      "research",
+				Arguments: map[string]any{"topic": "tides"},
+			},
+		})
+		require.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Equal(t, "tides", gotTopic)
+	})
+}
+
+func TestConnect(t *testing.T) {
+	newSource := func() *tool.Registry {
+		return tool.NewRegistry().Add(
+			tool.Func("echo", "Echo text", func(ctx context.Context, args struct {
+				Text string `json:"text"`
+			}) (string, error) {
+				return args.Text, nil
+			}),
+			tool.Func("fail", "Always fails", func(ctx context.Context, args struct{}) (string, error) {
+				return "", errors.New("boom")
+			}),
+		)
+	}
+
+	t.Run("registers remote tools with proxy handlers", func(t *testing.T) {
+		ctx := context.Background()
+		remote, err := Connect(ctx, InProcess(NewServer(newSource())))
+		require.NoError(t, err)
+		defer remote.Close()
+
+		registry := tool.NewRegistry()
+		require.NoError(t, remote.RegisterTo(registry))
+		assert.ElementsMatch(t, []string{"echo", "fail"}, registry.Names())
+
+		result, err := registry.Execute(ctx, ai.ToolCall{ID: "1", Name: "echo", Arguments: `{"text":"hi"}`})
+		require.NoError(t, err)
+		assert.Equal(t, "hi", result.Content)
+		assert.False(t, result.IsError)
+
+		result, err = registry.Execute(ctx, ai.ToolCall{ID: "2", Name: "fail", Arguments: `{}`})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content, "boom")
+	})
+
+	t.Run("fails on name conflicts", func(t *testing.T) {
+		ctx := context.Background()
+		remote, err := Connect(ctx, InProcess(NewServer(newSource())))
+		require.NoError(t, err)
+		defer remote.Close()
+
+		registry := tool.NewRegistry().Add(
+			tool.Func("echo", "Local echo", func(ctx context.Context, args struct{}) (string, error) {
+				return "local", nil
+			}),
+		)
+		assert.Error(t, remote.RegisterTo(registry))
+	})
+
+	t.Run("returns tool pairs sorted by name", func(t *testing.T) {
+		remote, err := Connect(context.Background(), InProcess(NewServer(newSource())))
+		require.NoError(t, err)
+		defer remote.Close()
+
+		pairs := remote.ToolPairs()
+		require.Len(t, pairs, 2)
+		assert.Equal(t, "echo", pairs[0].Tool.Name)
+		assert.Equal(t, "fail", pairs[1].Tool.Name)
+	})
+
+	t.Run("connects over streamable HTTP", func(t *testing.T) {
+		srv := httptest.NewServer(server.NewStreamableHTTPServer(NewServer(newSource())))
+		defer srv.Close()
+
+		ctx := context.Background()
+		remote, err := Connect(ctx, StreamableHTTP(srv.URL))
+		require.NoError(t, err)
+		defer remote.Close()
+
+		result, err := remote.Handler("echo")(ctx, ai.ToolCall{ID: "1", Name: "echo", Arguments: `{"text":"over http"}`})
+		require.NoError(t, err)
+		assert.Equal(t, "over http", result)
+	})
+}
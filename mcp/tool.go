@@ -5,8 +5,8 @@
 //
 //   - Server: Expose a gains [tool.Registry] as an MCP server, allowing MCP clients
 //     like Claude Desktop to discover and use your tools.
-//   - Client: Connect to MCP servers and use their tools through [RemoteRegistry],
-//     which can be used with gains agents.
+//   - Client: Connect to MCP servers over stdio, streamable HTTP, or SSE and
+//     register their tools into a [tool.Registry] for gains agents.
 //
 // # Exposing Tools as an MCP Server
 //
@@ -24,20 +24,25 @@
 //
 // # Consuming MCP Servers
 //
-// To use tools from an MCP server with a gains agent:
+// Connect discovers the tools of an MCP server, reached through a
+// [Transport] such as [Stdio] or [StreamableHTTP]. RegisterTo adds them to
+// a registry with handlers that proxy each call to the server:
 //
-//	// Connect to an MCP server
-//	remote, err := mcp.NewRemoteRegistry(ctx, "./my-mcp-server", nil)
+//	remote, err := mcp.Connect(ctx, mcp.Stdio("npx", nil, "-y", "@modelcontextprotocol/server-filesystem", "/tmp"))
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
 //	defer remote.Close()
 //
-//	// Use the remote tools with an agent
-//	agent := agent.New(client)
-//	for _, t := range remote.Tools() {
-//	    agent.RegisterTool(t, remote.Execute)
+//	registry := tool.NewRegistry()
+//	if err := remote.RegisterTo(registry); err != nil {
+//	    log.Fatal(err)
 //	}
+//	a := agent.New(client, registry)
+//
+// ToolPairs returns the same proxies for bundling into an agent.Skill.
+// Errors reported by a remote tool become error results the model can
+// recover from.
 package mcp
 
 import (
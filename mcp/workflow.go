@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/spetersoncode/gains/tool"
+	"github.com/spetersoncode/gains/workflow"
+)
+
+// NewWorkflowServer creates an MCP server that exposes each workflow in a
+// workflow.Registry as an MCP tool. Calling the tool runs the workflow to
+// completion with the tool arguments as its initial state.
+//
+// For runners created with workflow.NewRunner or workflow.NewRunnerJSON, the
+// tool's input schema is derived from the workflow's state type. Other
+// runners accept any JSON object.
+//
+// Example:
+//
+//	type ResearchState struct {
+//	    Topic   string `json:"topic" desc:"Topic to research" required:"true"`
+//	    Summary string `json:"summary"`
+//	}
+//
+//	workflows := workflow.NewRegistry()
+//	workflows.Register(workflow.NewRunnerJSON[ResearchState]("research", researchWorkflow))
+//
+//	mcpServer := mcp.NewWorkflowServer(workflows, mcp.WithName("my-workflows"))
+func NewWorkflowServer(registry *workflow.Registry, opts ...ServerOption) *server.MCPServer {
+	cfg := &serverConfig{
+		name:    "gains-mcp-server",
+		version: "1.0.0",
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	s := server.NewMCPServer(
+		cfg.name,
+		cfg.version,
+		server.WithToolCapabilities(true),
+	)
+	AddWorkflows(s, registry)
+	return s
+}
+
+// AddWorkflows registers each workflow in a workflow.Registry as a tool on an
+// existing MCP server. Use it to serve workflows alongside tools from
+// NewServer.
+func AddWorkflows(s *server.MCPServer, registry *workflow.Registry) {
+	for _, name := range registry.Names() {
+		runner := registry.Get(name)
+		if runner == nil {
+			continue
+		}
+		reg := workflowRegistration(runner)
+		s.AddTool(ToMCPTool(reg.Tool), createMCPHandler(reg.Tool.Name, reg.Handler))
+	}
+}
+
+// ServeWorkflows starts an MCP server over stdin/stdout that exposes the
+// workflows in a workflow.Registry as tools.
+//
+// Example:
+//
+//	if err := mcp.ServeWorkflows(workflows); err != nil {
+//	    log.Fatal(err)
+//	}
+func ServeWorkflows(registry *workflow.Registry, opts ...ServerOption) error {
+	s := NewWorkflowServer(registry, opts...)
+	return server.ServeStdio(s)
+}
+
+// workflowRegistration wraps a runner as a tool registration, using the
+// runner's state schema when it provides one.
+func workflowRegistration(runner workflow.Runner) tool.Registration {
+	reg := workflow.NewTool(runner)
+	if sr, ok := runner.(workflow.SchemaRunner); ok {
+		if schema, err := sr.InputSchema(); err == nil {
+			reg.Tool.Parameters = schema
+		}
+	}
+	return reg
+}
@@ -0,0 +1,36 @@
+// Package memory manages what an agent remembers of a conversation.
+//
+// A [Memory] rewrites the conversation history before each agent step. The
+// agent keeps the full history; only the messages sent to the model change.
+// Attach one with agent.WithMemory.
+//
+// # Short-Term Memory
+//
+// [Window] keeps the system messages and the most recent messages:
+//
+//	a.Run(ctx, messages, agent.WithMemory(memory.NewWindow(20)))
+//
+// # Summarization
+//
+// [Summarizer] leaves the conversation alone until its token count exceeds
+// a threshold, then replaces the older messages with a summary written by a
+// model, keeping the most recent ones verbatim:
+//
+//	mem := memory.NewSummarizer(client,
+//	    memory.WithMaxTokens(16000),
+//	    memory.WithKeepRecent(8),
+//	    memory.WithChatOptions(gains.WithModel(model.ClaudeHaiku45)),
+//	)
+//
+// # Long-Term Memory
+//
+// [LongTerm] stores texts with their embeddings and recalls those most
+// similar to the latest user message into the conversation:
+//
+//	facts := memory.NewLongTerm(embedder, memory.WithTopK(5))
+//	facts.Remember(ctx, "The user prefers metric units")
+//
+// Combine memories with [Chain]; each receives the output of the previous:
+//
+//	mem := memory.Chain(facts, memory.NewSummarizer(client))
+package memory
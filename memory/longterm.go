@@ -0,0 +1,204 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// ErrEmptyQuery is returned by Recall for an empty query.
+var ErrEmptyQuery = errors.New("memory: empty query")
+
+// Match is a remembered text and its similarity to a query.
+type Match struct {
+	Text  string
+	Score float64
+}
+
+// LongTermOption configures a LongTerm memory.
+type LongTermOption func(*LongTerm)
+
+// WithTopK sets how many memories Compact recalls for each step.
+// Default is 3.
+func WithTopK(k int) LongTermOption {
+	return func(l *LongTerm) {
+		l.topK = k
+	}
+}
+
+// WithMinScore sets the cosine similarity a memory needs to be recalled by
+// Compact. Default is 0.
+func WithMinScore(score float64) LongTermOption {
+	return func(l *LongTerm) {
+		l.minScore = score
+	}
+}
+
+// WithEmbeddingOptions sets options for the embedding calls, such as the
+// embedding model.
+func WithEmbeddingOptions(opts ...ai.EmbeddingOption) LongTermOption {
+	return func(l *LongTerm) {
+		l.embedOpts = append(l.embedOpts, opts...)
+	}
+}
+
+// LongTerm is embedding-backed memory. Texts stored with Remember are
+// recalled by semantic similarity, and Compact adds the memories most
+// relevant to the latest user message to the conversation.
+//
+// Memories are kept in process; copy them elsewhere with Entries if they
+// must outlive it.
+type LongTerm struct {
+	embedder  ai.EmbeddingProvider
+	topK      int
+	minScore  float64
+	embedOpts []ai.EmbeddingOption
+
+	mu      sync.RWMutex
+	entries []entry
+}
+
+// entry is a remembered text and its embedding.
+type entry struct {
+	text   string
+	vector []float64
+}
+
+// NewLongTerm returns an empty LongTerm memory that embeds texts with e.
+func NewLongTerm(e ai.EmbeddingProvider, opts ...LongTermOption) *LongTerm {
+	l := &LongTerm{embedder: e, topK: 3}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Remember embeds and stores texts.
+func (l *LongTerm) Remember(ctx context.Context, texts ...string) error {
+	if len(texts) == 0 {
+		return nil
+	}
+	opts := append([]ai.EmbeddingOption{ai.WithEmbeddingTaskType(ai.EmbeddingTaskTypeRetrievalDocument)}, l.embedOpts...)
+	resp, err := l.embedder.Embed(ctx, texts, opts...)
+	if err != nil {
+		return fmt.Errorf("memory: embed texts: %w", err)
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return fmt.Errorf("memory: got %d embeddings for %d texts", len(resp.Embeddings), len(texts))
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, text := range texts {
+		l.entries = append(l.entries, entry{text: text, vector: resp.Embeddings[i]})
+	}
+	return nil
+}
+
+// Recall returns up to k remembered texts most similar to query, best
+// match first.
+func (l *LongTerm) Recall(ctx context.Context, query string, k int) ([]Match, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, ErrEmptyQuery
+	}
+	if l.Len() == 0 || k <= 0 {
+		return nil, nil
+	}
+
+	opts := append([]ai.EmbeddingOption{ai.WithEmbeddingTaskType(ai.EmbeddingTaskTypeRetrievalQuery)}, l.embedOpts...)
+	resp, err := l.embedder.Embed(ctx, []string{query}, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("memory: embed query: %w", err)
+	}
+	if len(resp.Embeddings) == 0 {
+		return nil, fmt.Errorf("memory: got no embedding for query")
+	}
+	q := resp.Embeddings[0]
+
+	l.mu.RLock()
+	matches := make([]Match, len(l.entries))
+	for i, e := range l.entries {
+		matches[i] = Match{Text: e.text, Score: cosine(q, e.vector)}
+	}
+	l.mu.RUnlock()
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches, nil
+}
+
+// Compact adds a system message with the memories relevant to the latest
+// user message, after the leading system messages. Messages are returned
+// unchanged when nothing relevant is remembered.
+func (l *LongTerm) Compact(ctx context.Context, messages []ai.Message) ([]ai.Message, error) {
+	var query string
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == ai.RoleUser && messages[i].Content != "" {
+			query = messages[i].Content
+			break
+		}
+	}
+	if query == "" || l.Len() == 0 {
+		return messages, nil
+	}
+
+	matches, err := l.Recall(ctx, query, l.topK)
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	for _, m := range matches {
+		if m.Score >= l.minScore {
+			fmt.Fprintf(&b, "\n- %s", m.Text)
+		}
+	}
+	if b.Len() == 0 {
+		return messages, nil
+	}
+	return withContext(messages, "Relevant memories:"+b.String()), nil
+}
+
+// Entries returns the remembered texts in the order they were stored.
+func (l *LongTerm) Entries() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	texts := make([]string, len(l.entries))
+	for i, e := range l.entries {
+		texts[i] = e.text
+	}
+	return texts
+}
+
+// Len returns the number of remembered texts.
+func (l *LongTerm) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return len(l.entries)
+}
+
+// cosine returns the cosine similarity of a and b, or 0 if either is zero
+// or their lengths differ.
+func cosine(a, b []float64) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
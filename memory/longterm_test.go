@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wordEmbedder embeds texts as counts of a fixed vocabulary.
+type wordEmbedder struct{}
+
+var vocabulary = []string{"metric", "units", "coffee", "tea", "morning"}
+
+func (wordEmbedder) Embed(ctx context.Context, texts []string, opts ...ai.EmbeddingOption) (*ai.EmbeddingResponse, error) {
+	resp := &ai.EmbeddingResponse{}
+	for _, text := range texts {
+		vector := make([]float64, len(vocabulary))
+		for i, word := range vocabulary {
+			vector[i] = float64(strings.Count(strings.ToLower(text), word))
+		}
+		resp.Embeddings = append(resp.Embeddings, vector)
+	}
+	return resp, nil
+}
+
+func TestLongTerm_Recall(t *testing.T) {
+	l := NewLongTerm(wordEmbedder{})
+	require.NoError(t, l.Remember(context.Background(),
+		"The user prefers metric units",
+		"The user drinks tea in the morning",
+	))
+	assert.Equal(t, 2, l.Len())
+	assert.Equal(t, []string{"The user prefers metric units", "The user drinks tea in the morning"}, l.Entries())
+
+	matches, err := l.Recall(context.Background(), "tea or coffee?", 1)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "The user drinks tea in the morning", matches[0].Text)
+
+	_, err = l.Recall(context.Background(), " ", 1)
+	assert.ErrorIs(t, err, ErrEmptyQuery)
+}
+
+func TestLongTerm_Compact(t *testing.T) {
+	l := NewLongTerm(wordEmbedder{}, WithTopK(2), WithMinScore(0.1))
+	require.NoError(t, l.Remember(context.Background(), "The user prefers metric units", "The user drinks tea"))
+
+	messages := []ai.Message{
+		{Role: ai.RoleSystem, Content: "Be brief"},
+		{Role: ai.RoleUser, Content: "Which units should the report use?"},
+	}
+	got, err := l.Compact(context.Background(), messages)
+	require.NoError(t, err)
+	require.Len(t, got, 3)
+	assert.Equal(t, messages[0], got[0])
+	assert.Equal(t, ai.Message{Role: ai.RoleSystem, Content: "Relevant memories:\n- The user prefers metric units"}, got[1])
+	assert.Equal(t, messages[1], got[2])
+
+	t.Run("nothing relevant leaves messages unchanged", func(t *testing.T) {
+		unrelated := []ai.Message{{Role: ai.RoleUser, Content: "Hello"}}
+		got, err := l.Compact(context.Background(), unrelated)
+		require.NoError(t, err)
+		assert.Equal(t, unrelated, got)
+	})
+}
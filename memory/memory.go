@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// Memory prepares the conversation history sent with each agent step. It
+// returns the messages to send in place of messages, which it must not
+// modify. Implementations must be safe for concurrent use.
+type Memory interface {
+	Compact(ctx context.Context, messages []ai.Message) ([]ai.Message, error)
+}
+
+// Func adapts a function to the Memory interface.
+type Func func(ctx context.Context, messages []ai.Message) ([]ai.Message, error)
+
+// Compact calls f.
+func (f Func) Compact(ctx context.Context, messages []ai.Message) ([]ai.Message, error) {
+	return f(ctx, messages)
+}
+
+// Chain returns a Memory that applies each memory in turn, passing the
+// messages returned by one to the next.
+//
+//	mem := memory.Chain(longTerm, memory.NewSummarizer(client))
+func Chain(memories ...Memory) Memory {
+	return Func(func(ctx context.Context, messages []ai.Message) ([]ai.Message, error) {
+		var err error
+		for _, m := range memories {
+			if messages, err = m.Compact(ctx, messages); err != nil {
+				return nil, err
+			}
+		}
+		return messages, nil
+	})
+}
+
+// TokenCounter returns the number of tokens messages occupy in a prompt.
+type TokenCounter func(messages []ai.Message) int
+
+// EstimateTokens approximates the token count of messages at four
+// characters per token plus a small per-message overhead. It is the default
+// TokenCounter; use a provider tokenizer where precision matters.
+func EstimateTokens(messages []ai.Message) int {
+	return ai.EstimateTokens(nil, messages)
+}
+
+// splitSystem returns the leading system messages of messages and the rest.
+func splitSystem(messages []ai.Message) (system, rest []ai.Message) {
+	i := 0
+	for i < len(messages) && messages[i].Role == ai.RoleSystem {
+		i++
+	}
+	return messages[:i], messages[i:]
+}
+
+// recentStart returns the index in messages at which the last n messages
+// begin, moved back past any leading tool results so they stay with the
+// assistant message that requested them.
+func recentStart(messages []ai.Message, n int) int {
+	if n >= len(messages) {
+		return 0
+	}
+	start := len(messages) - max(n, 0)
+	for start > 0 && start < len(messages) && messages[start].Role == ai.RoleTool {
+		start--
+	}
+	return start
+}
+
+// withContext returns messages with a system message carrying content
+// inserted after the leading system messages. The input slice is not
+// modified.
+func withContext(messages []ai.Message, content string) []ai.Message {
+	system, rest := splitSystem(messages)
+	result := make([]ai.Message, 0, len(messages)+1)
+	result = append(result, system...)
+	result = append(result, ai.Message{Role: ai.RoleSystem, Content: content})
+	return append(result, rest...)
+}
+
+// transcript renders messages as plain text for a summarization prompt.
+func transcript(messages []ai.Message) string {
+	var b strings.Builder
+	for _, m := range messages {
+		if m.Content != "" {
+			fmt.Fprintf(&b, "%s: %s\n", m.Role, m.Content)
+		}
+		for _, tc := range m.ToolCalls {
+			fmt.Fprintf(&b, "assistant called %s with %s\n", tc.Name, tc.Arguments)
+		}
+		for _, tr := range m.ToolResults {
+			fmt.Fprintf(&b, "tool result: %s\n", tr.Content)
+		}
+	}
+	return b.String()
+}
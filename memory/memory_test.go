@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindow_Compact(t *testing.T) {
+	messages := []ai.Message{
+		{Role: ai.RoleSystem, Content: "Be brief"},
+		{Role: ai.RoleUser, Content: "One"},
+		{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{ID: "c1", Name: "lookup"}}},
+		{Role: ai.RoleTool, ToolResults: []ai.ToolResult{{ToolCallID: "c1", Content: "found"}}},
+		{Role: ai.RoleAssistant, Content: "Two"},
+	}
+
+	t.Run("keeps system and recent messages", func(t *testing.T) {
+		got, err := NewWindow(1).Compact(context.Background(), messages)
+		require.NoError(t, err)
+		assert.Equal(t, []ai.Message{messages[0], messages[4]}, got)
+	})
+
+	t.Run("keeps tool results with their call", func(t *testing.T) {
+		got, err := NewWindow(2).Compact(context.Background(), messages)
+		require.NoError(t, err)
+		assert.Equal(t, []ai.Message{messages[0], messages[2], messages[3], messages[4]}, got)
+	})
+
+	t.Run("small conversations are unchanged", func(t *testing.T) {
+		got, err := NewWindow(10).Compact(context.Background(), messages)
+		require.NoError(t, err)
+		assert.Equal(t, messages, got)
+	})
+}
+
+func TestChain(t *testing.T) {
+	tag := func(s string) Memory {
+		return Func(func(ctx context.Context, messages []ai.Message) ([]ai.Message, error) {
+			return append(messages, ai.Message{Role: ai.RoleUser, Content: s}), nil
+		})
+	}
+
+	got, err := Chain(tag("a"), tag("b")).Compact(context.Background(), nil)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "a", got[0].Content)
+	assert.Equal(t, "b", got[1].Content)
+}
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, EstimateTokens(nil))
+	assert.Equal(t, 2+4, EstimateTokens([]ai.Message{{Role: ai.RoleUser, Content: "12345678"}}))
+}
@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/chat"
+)
+
+// DefaultSummaryPrompt instructs the model that summarizes a conversation.
+const DefaultSummaryPrompt = "Summarize the conversation below for an assistant that will continue it. " +
+	"Keep facts, decisions, open questions, and results of tool calls that later turns may rely on. " +
+	"Reply with the summary only."
+
+// SummarizerOption configures a Summarizer.
+type SummarizerOption func(*Summarizer)
+
+// WithMaxTokens sets the token count above which the conversation is
+// summarized. Default is 8000.
+func WithMaxTokens(n int) SummarizerOption {
+	return func(s *Summarizer) {
+		s.maxTokens = n
+	}
+}
+
+// WithKeepRecent sets how many recent messages are kept verbatim when the
+// conversation is summarized. Default is 10.
+func WithKeepRecent(n int) SummarizerOption {
+	return func(s *Summarizer) {
+		s.keepRecent = n
+	}
+}
+
+// WithSummaryPrompt replaces DefaultSummaryPrompt.
+func WithSummaryPrompt(prompt string) SummarizerOption {
+	return func(s *Summarizer) {
+		s.prompt = prompt
+	}
+}
+
+// WithTokenCounter replaces EstimateTokens for measuring the conversation.
+func WithTokenCounter(fn TokenCounter) SummarizerOption {
+	return func(s *Summarizer) {
+		s.count = fn
+	}
+}
+
+// WithChatOptions sets options for the summarization chat calls, such as a
+// cheaper model.
+func WithChatOptions(opts ...ai.Option) SummarizerOption {
+	return func(s *Summarizer) {
+		s.chatOpts = append(s.chatOpts, opts...)
+	}
+}
+
+// Summarizer compacts a conversation whose token count exceeds a threshold
+// by replacing its older messages with a model-written summary. The system
+// messages and the most recent messages are kept verbatim.
+//
+// The summary is cached, so later steps of the same conversation only
+// summarize the messages that have aged out since, together with the
+// previous summary.
+type Summarizer struct {
+	client     chat.Client
+	maxTokens  int
+	keepRecent int
+	prompt     string
+	count      TokenCounter
+	chatOpts   []ai.Option
+
+	mu      sync.Mutex
+	covered []ai.Message // messages the cached summary covers
+	summary string
+}
+
+// NewSummarizer returns a Summarizer that writes summaries with c.
+func NewSummarizer(c chat.Client, opts ...SummarizerOption) *Summarizer {
+	s := &Summarizer{
+		client:     c,
+		maxTokens:  8000,
+		keepRecent: 10,
+		prompt:     DefaultSummaryPrompt,
+		count:      EstimateTokens,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Compact returns messages unchanged while they fit within the token
+// threshold. Above it, the messages before the most recent ones are
+// replaced by a system message holding their summary.
+func (s *Summarizer) Compact(ctx context.Context, messages []ai.Message) ([]ai.Message, error) {
+	if s.count(messages) <= s.maxTokens {
+		return messages, nil
+	}
+
+	system, rest := splitSystem(messages)
+	start := recentStart(rest, s.keepRecent)
+	if start == 0 {
+		return messages, nil
+	}
+	older, recent := rest[:start], rest[start:]
+
+	summary, err := s.summarize(ctx, older)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ai.Message, 0, len(system)+1+len(recent))
+	result = append(result, system...)
+	result = append(result, ai.Message{Role: ai.RoleSystem, Content: "Summary of the earlier conversation:\n" + summary})
+	return append(result, recent...), nil
+}
+
+// summarize returns a summary of older, extending the cached summary when
+// it covers a prefix of older.
+func (s *Summarizer) summarize(ctx context.Context, older []ai.Message) (string, error) {
+	s.mu.Lock()
+	previous, covered := s.summary, s.covered
+	s.mu.Unlock()
+
+	if len(covered) > len(older) || !reflect.DeepEqual(covered, older[:len(covered)]) {
+		previous, covered = "", nil
+	}
+	if previous != "" && len(covered) == len(older) {
+		return previous, nil
+	}
+
+	content := transcript(older[len(covered):])
+	if previous != "" {
+		content = "Summary so far:\n" + previous + "\n\nLater messages:\n" + content
+	}
+
+	resp, err := s.client.Chat(ctx, []ai.Message{
+		{Role: ai.RoleSystem, Content: s.prompt},
+		{Role: ai.RoleUser, Content: content},
+	}, s.chatOpts...)
+	if err != nil {
+		return "", fmt.Errorf("memory: summarize conversation: %w", err)
+	}
+
+	s.mu.Lock()
+	s.summary = resp.Content
+	s.covered = append([]ai.Message{}, older...)
+	s.mu.Unlock()
+	return resp.Content, nil
+}
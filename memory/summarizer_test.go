@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// summaryClient returns a fixed summary and records each prompt.
+type summaryClient struct {
+	prompts []string
+	err     error
+}
+
+func (c *summaryClient) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	c.prompts = append(c.prompts, messages[len(messages)-1].Content)
+	return &ai.Response{Content: "summary"}, nil
+}
+
+func (c *summaryClient) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+	return nil, errors.New("not implemented")
+}
+
+func conversation(n int) []ai.Message {
+	messages := []ai.Message{{Role: ai.RoleSystem, Content: "Be brief"}}
+	for i := range n {
+		role := ai.RoleUser
+		if i%2 == 1 {
+			role = ai.RoleAssistant
+		}
+		messages = append(messages, ai.Message{Role: role, Content: strings.Repeat("x", 40)})
+	}
+	return messages
+}
+
+func TestSummarizer_Compact(t *testing.T) {
+	client := &summaryClient{}
+	s := NewSummarizer(client, WithMaxTokens(50), WithKeepRecent(2))
+
+	t.Run("below threshold is unchanged", func(t *testing.T) {
+		messages := conversation(2)
+		got, err := s.Compact(context.Background(), messages)
+		require.NoError(t, err)
+		assert.Equal(t, messages, got)
+		assert.Empty(t, client.prompts)
+	})
+
+	messages := conversation(6)
+	got, err := s.Compact(context.Background(), messages)
+	require.NoError(t, err)
+	require.Len(t, got, 4)
+	assert.Equal(t, messages[0], got[0])
+	assert.Equal(t, ai.RoleSystem, got[1].Role)
+	assert.Contains(t, got[1].Content, "summary")
+	assert.Equal(t, messages[5:], got[2:])
+	require.Len(t, client.prompts, 1)
+
+	t.Run("reuses the cached summary", func(t *testing.T) {
+		_, err := s.Compact(context.Background(), messages)
+		require.NoError(t, err)
+		assert.Len(t, client.prompts, 1)
+	})
+
+	t.Run("extends the summary with later messages", func(t *testing.T) {
+		longer := append(append([]ai.Message{}, messages...), conversation(2)[1:]...)
+		_, err := s.Compact(context.Background(), longer)
+		require.NoError(t, err)
+		require.Len(t, client.prompts, 2)
+		assert.True(t, strings.HasPrefix(client.prompts[1], "Summary so far:\nsummary"))
+	})
+}
+
+func TestSummarizer_Error(t *testing.T) {
+	s := NewSummarizer(&summaryClient{err: errors.New("unavailable")}, WithMaxTokens(10), WithKeepRecent(1))
+	_, err := s.Compact(context.Background(), conversation(4))
+	assert.ErrorContains(t, err, "unavailable")
+}
@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"context"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// Window is short-term memory that keeps the system messages and the most
+// recent messages of a conversation, dropping older ones. Tool results stay
+// with the assistant message that requested them, so the window may hold a
+// few more messages than its size.
+type Window struct {
+	size int
+}
+
+// NewWindow returns a Window keeping the last size non-system messages.
+// A size of 0 or less keeps every message.
+func NewWindow(size int) *Window {
+	return &Window{size: size}
+}
+
+// Compact returns the system messages and the window of recent messages.
+func (w *Window) Compact(ctx context.Context, messages []ai.Message) ([]ai.Message, error) {
+	if w.size <= 0 {
+		return messages, nil
+	}
+	system, rest := splitSystem(messages)
+	start := recentStart(rest, w.size)
+	if start == 0 {
+		return messages, nil
+	}
+	return append(append([]ai.Message{}, system...), rest[start:]...), nil
+}
@@ -1,6 +1,10 @@
 package gains
 
-import "github.com/google/uuid"
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+)
 
 // Role represents the role of a message sender in a conversation.
 type Role string
@@ -16,26 +20,34 @@ const (
 type ContentPartType string
 
 const (
-	ContentPartTypeText  ContentPartType = "text"
-	ContentPartTypeImage ContentPartType = "image"
+	ContentPartTypeText     ContentPartType = "text"
+	ContentPartTypeImage    ContentPartType = "image"
+	ContentPartTypeDocument ContentPartType = "document"
+	ContentPartTypeAudio    ContentPartType = "audio"
 )
 
 // ContentPart represents a single part of multimodal content.
-// Use either Text (for text parts) or ImageURL/Base64 (for image parts).
+// Use either Text (for text parts), ImageURL/Base64 (for image parts), or
+// Base64/FileURL/Text (for document parts).
 type ContentPart struct {
-	// Type indicates the content type: "text" or "image".
+	// Type indicates the content type: "text", "image", "document", or "audio".
 	Type ContentPartType `json:"type"`
-	// Text contains the text content. Only used when Type is "text".
+	// Text contains the text content. Used when Type is "text", or for
+	// plain text documents.
 	Text string `json:"text,omitempty"`
 	// ImageURL contains a URL to an image. Only used when Type is "image".
 	// Mutually exclusive with Base64.
 	ImageURL string `json:"imageUrl,omitempty"`
-	// Base64 contains base64-encoded image data. Only used when Type is "image".
-	// Mutually exclusive with ImageURL.
+	// Base64 contains base64-encoded image, document, or audio data.
+	// Mutually exclusive with ImageURL and FileURL.
 	Base64 string `json:"base64,omitempty"`
-	// MimeType specifies the image format (e.g., "image/jpeg", "image/png").
-	// Required when using Base64, optional for ImageURL (may be inferred).
+	// MimeType specifies the format (e.g., "image/png", "application/pdf").
+	// Required when using Base64, optional for URLs (may be inferred).
 	MimeType string `json:"mimeType,omitempty"`
+	// FileURL contains a URL to a document. Only used when Type is "document".
+	FileURL string `json:"fileUrl,omitempty"`
+	// Filename is an optional display name for a document.
+	Filename string `json:"filename,omitempty"`
 }
 
 // NewTextPart creates a text content part.
@@ -63,6 +75,66 @@ func NewImageBase64Part(base64Data, mimeType string) ContentPart {
 	}
 }
 
+// NewAudioBase64Part creates an audio content part from base64 data.
+func NewAudioBase64Part(base64Data, mimeType string) ContentPart {
+	return ContentPart{
+		Type:     ContentPartTypeAudio,
+		Base64:   base64Data,
+		MimeType: mimeType,
+	}
+}
+
+// NewDocumentBase64Part creates a document content part from base64 data,
+// such as a PDF ("application/pdf") or plain text ("text/plain") file.
+func NewDocumentBase64Part(base64Data, mimeType, filename string) ContentPart {
+	return ContentPart{
+		Type:     ContentPartTypeDocument,
+		Base64:   base64Data,
+		MimeType: mimeType,
+		Filename: filename,
+	}
+}
+
+// NewDocumentURLPart creates a document content part from a URL.
+func NewDocumentURLPart(url, mimeType string) ContentPart {
+	return ContentPart{
+		Type:     ContentPartTypeDocument,
+		FileURL:  url,
+		MimeType: mimeType,
+	}
+}
+
+// NewTextDocumentPart creates a plain text document content part.
+// Unlike a text part, it is presented to the model as an attached file.
+func NewTextDocumentPart(text, filename string) ContentPart {
+	return ContentPart{
+		Type:     ContentPartTypeDocument,
+		Text:     text,
+		MimeType: "text/plain",
+		Filename: filename,
+	}
+}
+
+// DocumentText returns the contents of a plain text document part, decoding
+// Base64 data when the MIME type is text/*. Returns false for other parts,
+// such as PDFs, and for undecodable data.
+func (p ContentPart) DocumentText() (string, bool) {
+	if p.Type != ContentPartTypeDocument {
+		return "", false
+	}
+	if p.Text != "" {
+		return p.Text, true
+	}
+	if p.Base64 == "" || !strings.HasPrefix(p.MimeType, "text/") {
+		return "", false
+	}
+	data, err := base64.StdEncoding.DecodeString(p.Base64)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
 // Message represents a single message in a conversation.
 type Message struct {
 	// ID is an optional unique identifier for the message.
@@ -79,11 +151,20 @@ type Message struct {
 	// ToolResults contains results from tool executions.
 	// Only populated when Role is RoleTool.
 	ToolResults []ToolResult `json:"toolResults,omitempty"`
+	// Name optionally identifies the participant that authored the message,
+	// such as a user handle or a sub-agent name.
+	Name string `json:"name,omitempty"`
+	// CreatedAt records when the message was created. Zero if unknown.
+	CreatedAt time.Time `json:"createdAt,omitzero"`
+	// Metadata holds application-defined key/value pairs such as user IDs,
+	// channel IDs, or provenance. It is persisted with the message but never
+	// sent to providers.
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
-// GenerateMessageID creates a unique message identifier.
+// GenerateMessageID creates a unique message identifier using NewID.
 func GenerateMessageID() string {
-	return "msg-" + uuid.New().String()
+	return "msg-" + NewID()
 }
 
 // HasParts returns true if the message has multimodal content parts.
@@ -91,6 +172,22 @@ func (m Message) HasParts() bool {
 	return len(m.Parts) > 0
 }
 
+// LastTurnStart returns the index of the first message of the last
+// assistant turn: everything after the last user message, including tool
+// calls and their results. It returns -1 if there is no user message or no
+// message follows it.
+func LastTurnStart(messages []Message) int {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == RoleUser {
+			if i == len(messages)-1 {
+				return -1
+			}
+			return i + 1
+		}
+	}
+	return -1
+}
+
 // Response represents a complete response from a chat provider.
 type Response struct {
 	Content      string `json:"content,omitempty"`
@@ -99,10 +196,44 @@ type Response struct {
 	// ToolCalls contains any tool invocation requests from the model.
 	// Check if len(ToolCalls) > 0 to determine if tools should be executed.
 	ToolCalls []ToolCall `json:"toolCalls,omitempty"`
-	// Parts contains multimodal output content (text, images).
-	// Populated when the model generates non-text content (e.g., images).
-	// For text-only responses, this may be empty and Content is used instead.
+	// Parts contains multimodal output content (text, images, audio) in the
+	// order the model produced it. Populated when the model generates
+	// non-text content, such as Gemini image output or OpenAI audio output.
+	// For text-only responses, this is empty and Content is used instead.
 	Parts []ContentPart `json:"parts,omitempty"`
+	// Timing contains latency and throughput for streamed responses, measured
+	// from the start of the request. Set by client.Client.ChatStream; nil for
+	// non-streaming calls.
+	Timing *StreamStats `json:"timing,omitempty"`
+	// Candidates contains every completion when more than one was requested
+	// with WithCandidateCount, the first of which is also reflected in
+	// Content, FinishReason, ToolCalls, and Parts. Nil for single completions.
+	Candidates []Candidate `json:"candidates,omitempty"`
+	// ProviderMetadata holds the request ID, model version, and rate-limit
+	// state the provider reported. Nil for dry runs and responses that did
+	// not come from a provider.
+	ProviderMetadata *ProviderMetadata `json:"providerMetadata,omitempty"`
+}
+
+// Candidate is one of several alternative completions for a request.
+type Candidate struct {
+	Content      string        `json:"content,omitempty"`
+	FinishReason string        `json:"finishReason,omitempty"`
+	ToolCalls    []ToolCall    `json:"toolCalls,omitempty"`
+	Parts        []ContentPart `json:"parts,omitempty"`
+}
+
+// CandidateContents returns the content of each candidate, or of the
+// response itself when it has no candidates.
+func (r Response) CandidateContents() []string {
+	if len(r.Candidates) == 0 {
+		return []string{r.Content}
+	}
+	contents := make([]string, len(r.Candidates))
+	for i, c := range r.Candidates {
+		contents[i] = c.Content
+	}
+	return contents
 }
 
 // HasParts returns true if the response has multimodal content parts.
@@ -110,10 +241,29 @@ func (r Response) HasParts() bool {
 	return len(r.Parts) > 0
 }
 
+// PartsOfType returns the response parts of the given type, such as
+// ContentPartTypeImage or ContentPartTypeAudio.
+func (r Response) PartsOfType(t ContentPartType) []ContentPart {
+	var parts []ContentPart
+	for _, p := range r.Parts {
+		if p.Type == t {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
 // Usage contains token usage information for a request.
 type Usage struct {
 	InputTokens  int `json:"inputTokens"`
 	OutputTokens int `json:"outputTokens"`
+	// CachedInputTokens is the portion of InputTokens served from the
+	// provider's prompt cache, when reported.
+	CachedInputTokens int `json:"cachedInputTokens,omitempty"`
+	// CacheWriteInputTokens is the portion of InputTokens written to the
+	// provider's prompt cache, when reported. Anthropic bills these above
+	// the standard input rate.
+	CacheWriteInputTokens int `json:"cacheWriteInputTokens,omitempty"`
 }
 
 // StreamEvent represents a single event in a streaming response.
@@ -1,9 +1,12 @@
 package gains
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRoleConstants(t *testing.T) {
@@ -16,6 +19,8 @@ func TestRoleConstants(t *testing.T) {
 func TestContentPartTypeConstants(t *testing.T) {
 	assert.Equal(t, ContentPartType("text"), ContentPartTypeText)
 	assert.Equal(t, ContentPartType("image"), ContentPartTypeImage)
+	assert.Equal(t, ContentPartType("document"), ContentPartTypeDocument)
+	assert.Equal(t, ContentPartType("audio"), ContentPartTypeAudio)
 }
 
 func TestNewTextPart(t *testing.T) {
@@ -145,6 +150,61 @@ func TestNewImageBase64Part(t *testing.T) {
 	}
 }
 
+func TestNewAudioBase64Part(t *testing.T) {
+	part := NewAudioBase64Part("UklGRg==", "audio/wav")
+	assert.Equal(t, ContentPartTypeAudio, part.Type)
+	assert.Equal(t, "UklGRg==", part.Base64)
+	assert.Equal(t, "audio/wav", part.MimeType)
+}
+
+func TestNewDocumentParts(t *testing.T) {
+	t.Run("base64 document", func(t *testing.T) {
+		part := NewDocumentBase64Part("JVBERi0=", "application/pdf", "report.pdf")
+		assert.Equal(t, ContentPart{
+			Type:     ContentPartTypeDocument,
+			Base64:   "JVBERi0=",
+			MimeType: "application/pdf",
+			Filename: "report.pdf",
+		}, part)
+	})
+
+	t.Run("URL document", func(t *testing.T) {
+		part := NewDocumentURLPart("https://example.com/report.pdf", "application/pdf")
+		assert.Equal(t, ContentPartTypeDocument, part.Type)
+		assert.Equal(t, "https://example.com/report.pdf", part.FileURL)
+		assert.Empty(t, part.ImageURL)
+	})
+
+	t.Run("text document", func(t *testing.T) {
+		part := NewTextDocumentPart("hello", "notes.txt")
+		assert.Equal(t, "text/plain", part.MimeType)
+		assert.Equal(t, "notes.txt", part.Filename)
+	})
+}
+
+func TestContentPartDocumentText(t *testing.T) {
+	tests := []struct {
+		name   string
+		part   ContentPart
+		text   string
+		wantOK bool
+	}{
+		{"text document", NewTextDocumentPart("hello", ""), "hello", true},
+		{"base64 text document", NewDocumentBase64Part("aGVsbG8=", "text/markdown", "a.md"), "hello", true},
+		{"pdf document", NewDocumentBase64Part("JVBERi0=", "application/pdf", ""), "", false},
+		{"invalid base64", NewDocumentBase64Part("!!", "text/plain", ""), "", false},
+		{"text part", NewTextPart("hello"), "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, ok := tt.part.DocumentText()
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.text, text)
+		})
+	}
+}
+
 func TestMessageHasParts(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -191,6 +251,31 @@ func TestMessageHasParts(t *testing.T) {
 	}
 }
 
+func TestLastTurnStart(t *testing.T) {
+	user := Message{Role: RoleUser, Content: "hi"}
+	assistant := Message{Role: RoleAssistant, Content: "hello"}
+	call := Message{Role: RoleAssistant, ToolCalls: []ToolCall{{ID: "c1", Name: "t"}}}
+	result := Message{Role: RoleTool, ToolResults: []ToolResult{{ToolCallID: "c1"}}}
+
+	tests := []struct {
+		name     string
+		messages []Message
+		expected int
+	}{
+		{"empty", nil, -1},
+		{"no user message", []Message{{Role: RoleSystem}, assistant}, -1},
+		{"user message last", []Message{user, assistant, user}, -1},
+		{"single reply", []Message{user, assistant}, 1},
+		{"tool turn", []Message{user, assistant, user, call, result, assistant}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, LastTurnStart(tt.messages))
+		})
+	}
+}
+
 func TestMessageStruct(t *testing.T) {
 	t.Run("creates user message with content", func(t *testing.T) {
 		msg := Message{
@@ -227,6 +312,36 @@ func TestMessageStruct(t *testing.T) {
 	})
 }
 
+func TestMessageMetadataJSON(t *testing.T) {
+	t.Run("round-trips name, timestamp, and metadata", func(t *testing.T) {
+		created := time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC)
+		msg := Message{
+			Role:      RoleUser,
+			Content:   "Hello",
+			Name:      "alice",
+			CreatedAt: created,
+			Metadata:  map[string]string{"user_id": "u-123", "channel": "support"},
+		}
+
+		data, err := json.Marshal(msg)
+		require.NoError(t, err)
+
+		var decoded Message
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, "alice", decoded.Name)
+		assert.True(t, created.Equal(decoded.CreatedAt))
+		assert.Equal(t, msg.Metadata, decoded.Metadata)
+	})
+
+	t.Run("omits empty fields", func(t *testing.T) {
+		data, err := json.Marshal(Message{Role: RoleUser, Content: "Hi"})
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "name")
+		assert.NotContains(t, string(data), "createdAt")
+		assert.NotContains(t, string(data), "metadata")
+	})
+}
+
 func TestResponseStruct(t *testing.T) {
 	t.Run("creates response with content", func(t *testing.T) {
 		resp := Response{
@@ -254,6 +369,35 @@ func TestResponseStruct(t *testing.T) {
 	})
 }
 
+func TestResponsePartsOfType(t *testing.T) {
+	resp := Response{
+		Parts: []ContentPart{
+			NewTextPart("Here you go"),
+			NewImageBase64Part("img1", "image/png"),
+			NewAudioBase64Part("aud", "audio/wav"),
+			NewImageBase64Part("img2", "image/png"),
+		},
+	}
+
+	images := resp.PartsOfType(ContentPartTypeImage)
+	require.Len(t, images, 2)
+	assert.Equal(t, "img1", images[0].Base64)
+	assert.Equal(t, "img2", images[1].Base64)
+	assert.Len(t, resp.PartsOfType(ContentPartTypeAudio), 1)
+	assert.Empty(t, resp.PartsOfType(ContentPartTypeDocument))
+}
+
+func TestResponseCandidateContents(t *testing.T) {
+	single := Response{Content: "only"}
+	assert.Equal(t, []string{"only"}, single.CandidateContents())
+
+	multi := Response{
+		Content:    "a",
+		Candidates: []Candidate{{Content: "a"}, {Content: "b"}},
+	}
+	assert.Equal(t, []string{"a", "b"}, multi.CandidateContents())
+}
+
 func TestStreamEventStruct(t *testing.T) {
 	t.Run("creates delta event", func(t *testing.T) {
 		event := StreamEvent{
@@ -0,0 +1,57 @@
+package gains
+
+import (
+	"net/http"
+	"time"
+)
+
+// ProviderMetadata is what a provider reports about a response beyond its
+// content: identifiers for support requests, the model version that served
+// it, and rate-limit state for alerting before limits are reached. Fields a
+// provider does not report are empty.
+type ProviderMetadata struct {
+	// RequestID identifies the request to the provider. Include it when
+	// reporting problems to the provider's support.
+	RequestID string `json:"requestId,omitempty"`
+	// Model is the model version that served the request, such as the
+	// dated snapshot behind an alias.
+	Model string `json:"model,omitempty"`
+	// RateLimit is the rate-limit state after the request, or nil if the
+	// provider does not report one. Anthropic and OpenAI report it; Google
+	// and Vertex AI do not.
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+	// Header holds the HTTP response headers.
+	Header http.Header `json:"header,omitempty"`
+}
+
+// RateLimit is a provider's rate-limit state. Limits and remaining counts
+// apply to the current window, which ends at the corresponding reset time.
+// A zero limit means the provider did not report that limit.
+type RateLimit struct {
+	RequestsLimit     int       `json:"requestsLimit,omitempty"`
+	RequestsRemaining int       `json:"requestsRemaining"`
+	RequestsReset     time.Time `json:"requestsReset,omitzero"`
+	TokensLimit       int       `json:"tokensLimit,omitempty"`
+	TokensRemaining   int       `json:"tokensRemaining"`
+	TokensReset       time.Time `json:"tokensReset,omitzero"`
+}
+
+// Remaining returns the smaller of the fractions of requests and tokens
+// left in the current window, from 0 to 1, so callers can alert when it
+// falls below a threshold:
+//
+//	if rl := resp.ProviderMetadata.RateLimit; rl != nil && rl.Remaining() < 0.1 {
+//	    alert("rate limit nearly exhausted")
+//	}
+//
+// It returns 1 if no limits were reported.
+func (r *RateLimit) Remaining() float64 {
+	fraction := 1.0
+	if r.RequestsLimit > 0 {
+		fraction = min(fraction, float64(r.RequestsRemaining)/float64(r.RequestsLimit))
+	}
+	if r.TokensLimit > 0 {
+		fraction = min(fraction, float64(r.TokensRemaining)/float64(r.TokensLimit))
+	}
+	return max(fraction, 0)
+}
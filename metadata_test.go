@@ -0,0 +1,25 @@
+package gains
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit_Remaining(t *testing.T) {
+	tests := []struct {
+		name string
+		rl   RateLimit
+		want float64
+	}{
+		{"no limits", RateLimit{}, 1},
+		{"requests only", RateLimit{RequestsLimit: 100, RequestsRemaining: 40}, 0.4},
+		{"lower of requests and tokens", RateLimit{RequestsLimit: 100, RequestsRemaining: 40, TokensLimit: 1000, TokensRemaining: 100}, 0.1},
+		{"exhausted", RateLimit{TokensLimit: 1000}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, tt.rl.Remaining(), 1e-9)
+		})
+	}
+}
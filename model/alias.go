@@ -0,0 +1,33 @@
+package model
+
+import ai "github.com/spetersoncode/gains"
+
+// Alias names a model indirectly, such as "claude-latest" or "cheap-chat",
+// so code can select models by role while configuration decides which
+// model fills it. The client resolves aliases with client.Config.Aliases,
+// falling back to DefaultAliases:
+//
+//	resp, err := c.Chat(ctx, messages, ai.WithModel(model.Alias("cheap-chat")))
+//
+// An Alias has no provider and cannot be sent to a provider unresolved.
+type Alias string
+
+// String returns the alias name.
+func (a Alias) String() string { return string(a) }
+
+// Provider returns the empty provider; the provider is that of the model
+// the alias resolves to.
+func (a Alias) Provider() ai.Provider { return "" }
+
+// DefaultAliases returns the aliases every client resolves unless its
+// configuration overrides them: "claude-latest", "gpt-latest",
+// "gemini-latest", and "vertex-latest", naming each provider's recommended
+// default model.
+func DefaultAliases() map[string]ai.Model {
+	return map[string]ai.Model{
+		"claude-latest": DefaultClaudeModel,
+		"gpt-latest":    DefaultGPTModel,
+		"gemini-latest": DefaultGeminiModel,
+		"vertex-latest": DefaultVertexModel,
+	}
+}
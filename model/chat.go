@@ -7,7 +7,13 @@ type ChatModel struct {
 	id                  string
 	provider            ai.Provider
 	pricing             ChatPricing
+	contextWindow       int
 	supportsImageOutput bool
+
+	// Request features the model rejects
+	noTools          bool
+	noVision         bool
+	noResponseSchema bool
 }
 
 // String returns the API identifier for this model.
@@ -20,10 +26,27 @@ func (m ChatModel) Provider() ai.Provider { return m.provider }
 func (m ChatModel) Pricing() ChatPricing { return m.pricing }
 
 // Cost calculates the cost in USD for the given token usage.
-// Uses standard per-million token rates; does not account for cached
-// input tokens or long context tiers which require extended usage tracking.
+// Cached input tokens and long context tiers are priced when the model
+// defines them; see CalculateTieredCost.
 func (m ChatModel) Cost(usage ai.Usage) float64 {
-	return CalculateCost(usage, m.pricing)
+	return CalculateTieredCost(usage, m.pricing)
+}
+
+// ContextWindow returns the maximum number of input tokens the model
+// accepts, or 0 if unknown.
+func (m ChatModel) ContextWindow() int { return m.contextWindow }
+
+// SupportsCapability reports whether the model accepts requests using c.
+func (m ChatModel) SupportsCapability(c ai.Capability) bool {
+	switch c {
+	case ai.CapabilityTools:
+		return !m.noTools
+	case ai.CapabilityVision:
+		return !m.noVision
+	case ai.CapabilityResponseSchema:
+		return !m.noResponseSchema
+	}
+	return true
 }
 
 // SupportsImageOutput returns true if this model can generate images
@@ -36,14 +59,14 @@ func (m ChatModel) SupportsImageOutput() bool {
 // Model pricing last verified: December 14, 2025
 var (
 	// Claude 4.5 Family (Current) - auto-updating aliases
-	ClaudeOpus45   = ChatModel{id: "claude-opus-4-5", provider: ai.ProviderAnthropic, pricing: ChatPricing{InputPerMillion: 5.00, OutputPerMillion: 25.00}}
-	ClaudeSonnet45 = ChatModel{id: "claude-sonnet-4-5", provider: ai.ProviderAnthropic, pricing: ChatPricing{InputPerMillion: 3.00, OutputPerMillion: 15.00}}
-	ClaudeHaiku45  = ChatModel{id: "claude-haiku-4-5", provider: ai.ProviderAnthropic, pricing: ChatPricing{InputPerMillion: 1.00, OutputPerMillion: 5.00}}
+	ClaudeOpus45   = ChatModel{id: "claude-opus-4-5", provider: ai.ProviderAnthropic, pricing: ChatPricing{InputPerMillion: 5.00, OutputPerMillion: 25.00, CachedInputPerMillion: 0.50, CacheWriteInputPerMillion: 6.25}, contextWindow: 200_000}
+	ClaudeSonnet45 = ChatModel{id: "claude-sonnet-4-5", provider: ai.ProviderAnthropic, pricing: ChatPricing{InputPerMillion: 3.00, OutputPerMillion: 15.00, CachedInputPerMillion: 0.30, CacheWriteInputPerMillion: 3.75}, contextWindow: 200_000}
+	ClaudeHaiku45  = ChatModel{id: "claude-haiku-4-5", provider: ai.ProviderAnthropic, pricing: ChatPricing{InputPerMillion: 1.00, OutputPerMillion: 5.00, CachedInputPerMillion: 0.10, CacheWriteInputPerMillion: 1.25}, contextWindow: 200_000}
 
 	// Pinned versions (use for production stability)
-	ClaudeOpus45_20251101   = ChatModel{id: "claude-opus-4-5-20251101", provider: ai.ProviderAnthropic, pricing: ChatPricing{InputPerMillion: 5.00, OutputPerMillion: 25.00}}
-	ClaudeSonnet45_20250929 = ChatModel{id: "claude-sonnet-4-5-20250929", provider: ai.ProviderAnthropic, pricing: ChatPricing{InputPerMillion: 3.00, OutputPerMillion: 15.00}}
-	ClaudeHaiku45_20251001  = ChatModel{id: "claude-haiku-4-5-20251001", provider: ai.ProviderAnthropic, pricing: ChatPricing{InputPerMillion: 1.00, OutputPerMillion: 5.00}}
+	ClaudeOpus45_20251101   = ChatModel{id: "claude-opus-4-5-20251101", provider: ai.ProviderAnthropic, pricing: ChatPricing{InputPerMillion: 5.00, OutputPerMillion: 25.00, CachedInputPerMillion: 0.50, CacheWriteInputPerMillion: 6.25}, contextWindow: 200_000}
+	ClaudeSonnet45_20250929 = ChatModel{id: "claude-sonnet-4-5-20250929", provider: ai.ProviderAnthropic, pricing: ChatPricing{InputPerMillion: 3.00, OutputPerMillion: 15.00, CachedInputPerMillion: 0.30, CacheWriteInputPerMillion: 3.75}, contextWindow: 200_000}
+	ClaudeHaiku45_20251001  = ChatModel{id: "claude-haiku-4-5-20251001", provider: ai.ProviderAnthropic, pricing: ChatPricing{InputPerMillion: 1.00, OutputPerMillion: 5.00, CachedInputPerMillion: 0.10, CacheWriteInputPerMillion: 1.25}, contextWindow: 200_000}
 
 	// DefaultClaudeModel is the recommended default Anthropic model.
 	DefaultClaudeModel = ClaudeSonnet45
@@ -53,24 +76,24 @@ var (
 // Model pricing last verified: December 14, 2025
 var (
 	// GPT-5.2 Series (Latest - December 2025)
-	GPT52    = ChatModel{id: "gpt-5.2", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 1.75, OutputPerMillion: 14.00, CachedInputPerMillion: 0.175}}
-	GPT52Pro = ChatModel{id: "gpt-5.2-pro", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 3.50, OutputPerMillion: 28.00, CachedInputPerMillion: 0.35}}
+	GPT52    = ChatModel{id: "gpt-5.2", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 1.75, OutputPerMillion: 14.00, CachedInputPerMillion: 0.175}, contextWindow: 400_000}
+	GPT52Pro = ChatModel{id: "gpt-5.2-pro", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 3.50, OutputPerMillion: 28.00, CachedInputPerMillion: 0.35}, contextWindow: 400_000}
 
 	// GPT-5.1 Series
-	GPT51      = ChatModel{id: "gpt-5.1", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 1.25, OutputPerMillion: 10.00, CachedInputPerMillion: 0.125}}
-	GPT51Mini  = ChatModel{id: "gpt-5.1-mini", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 0.30, OutputPerMillion: 1.25, CachedInputPerMillion: 0.03}}
-	GPT51Codex = ChatModel{id: "gpt-5.1-codex", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 1.25, OutputPerMillion: 10.00, CachedInputPerMillion: 0.125}}
+	GPT51      = ChatModel{id: "gpt-5.1", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 1.25, OutputPerMillion: 10.00, CachedInputPerMillion: 0.125}, contextWindow: 400_000}
+	GPT51Mini  = ChatModel{id: "gpt-5.1-mini", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 0.30, OutputPerMillion: 1.25, CachedInputPerMillion: 0.03}, contextWindow: 400_000}
+	GPT51Codex = ChatModel{id: "gpt-5.1-codex", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 1.25, OutputPerMillion: 10.00, CachedInputPerMillion: 0.125}, contextWindow: 400_000}
 
 	// GPT-5 Series
-	GPT5     = ChatModel{id: "gpt-5", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 1.25, OutputPerMillion: 10.00, CachedInputPerMillion: 0.125}}
-	GPT5Mini = ChatModel{id: "gpt-5-mini", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 0.25, OutputPerMillion: 1.00, CachedInputPerMillion: 0.025}}
-	GPT5Nano = ChatModel{id: "gpt-5-nano", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 0.10, OutputPerMillion: 0.40, CachedInputPerMillion: 0.01}}
-	GPT5Pro  = ChatModel{id: "gpt-5-pro", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 2.50, OutputPerMillion: 20.00, CachedInputPerMillion: 0.25}}
+	GPT5     = ChatModel{id: "gpt-5", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 1.25, OutputPerMillion: 10.00, CachedInputPerMillion: 0.125}, contextWindow: 400_000}
+	GPT5Mini = ChatModel{id: "gpt-5-mini", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 0.25, OutputPerMillion: 1.00, CachedInputPerMillion: 0.025}, contextWindow: 400_000}
+	GPT5Nano = ChatModel{id: "gpt-5-nano", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 0.10, OutputPerMillion: 0.40, CachedInputPerMillion: 0.01}, contextWindow: 400_000}
+	GPT5Pro  = ChatModel{id: "gpt-5-pro", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 2.50, OutputPerMillion: 20.00, CachedInputPerMillion: 0.25}, contextWindow: 400_000}
 
 	// O-Series Reasoning Models
-	O3     = ChatModel{id: "o3", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 2.00, OutputPerMillion: 16.00, CachedInputPerMillion: 0.20}}
-	O3Mini = ChatModel{id: "o3-mini", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 0.50, OutputPerMillion: 2.00, CachedInputPerMillion: 0.05}}
-	O4Mini = ChatModel{id: "o4-mini", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 0.50, OutputPerMillion: 2.00, CachedInputPerMillion: 0.05}}
+	O3     = ChatModel{id: "o3", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 2.00, OutputPerMillion: 16.00, CachedInputPerMillion: 0.20}, contextWindow: 200_000}
+	O3Mini = ChatModel{id: "o3-mini", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 0.50, OutputPerMillion: 2.00, CachedInputPerMillion: 0.05}, contextWindow: 200_000, noVision: true}
+	O4Mini = ChatModel{id: "o4-mini", provider: ai.ProviderOpenAI, pricing: ChatPricing{InputPerMillion: 0.50, OutputPerMillion: 2.00, CachedInputPerMillion: 0.05}, contextWindow: 200_000}
 
 	// DefaultGPTModel is the recommended default OpenAI model.
 	DefaultGPTModel = GPT52
@@ -80,22 +103,22 @@ var (
 // Model pricing last verified: December 19, 2025
 var (
 	// Gemini 3.0 (Latest - November 2025)
-	Gemini3Pro          = ChatModel{id: "gemini-3.0-pro", provider: ai.ProviderGoogle, pricing: ChatPricing{InputPerMillion: 2.00, OutputPerMillion: 12.00, InputPerMillionLong: 4.00, OutputPerMillionLong: 18.00}}
-	Gemini3FlashPreview = ChatModel{id: "gemini-3-flash-preview", provider: ai.ProviderGoogle, pricing: ChatPricing{InputPerMillion: 0.15, OutputPerMillion: 0.60}}
-	Gemini3DeepThink    = ChatModel{id: "gemini-3.0-deep-think", provider: ai.ProviderGoogle, pricing: ChatPricing{InputPerMillion: 4.00, OutputPerMillion: 24.00, InputPerMillionLong: 8.00, OutputPerMillionLong: 36.00}}
+	Gemini3Pro          = ChatModel{id: "gemini-3.0-pro", provider: ai.ProviderGoogle, pricing: ChatPricing{InputPerMillion: 2.00, OutputPerMillion: 12.00, InputPerMillionLong: 4.00, OutputPerMillionLong: 18.00}, contextWindow: 1_048_576}
+	Gemini3FlashPreview = ChatModel{id: "gemini-3-flash-preview", provider: ai.ProviderGoogle, pricing: ChatPricing{InputPerMillion: 0.15, OutputPerMillion: 0.60}, contextWindow: 1_048_576}
+	Gemini3DeepThink    = ChatModel{id: "gemini-3.0-deep-think", provider: ai.ProviderGoogle, pricing: ChatPricing{InputPerMillion: 4.00, OutputPerMillion: 24.00, InputPerMillionLong: 8.00, OutputPerMillionLong: 36.00}, contextWindow: 1_048_576}
 
 	// Gemini 2.5 Series
-	Gemini25Pro       = ChatModel{id: "gemini-2.5-pro", provider: ai.ProviderGoogle, pricing: ChatPricing{InputPerMillion: 1.25, OutputPerMillion: 10.00, InputPerMillionLong: 2.50, OutputPerMillionLong: 15.00}}
-	Gemini25Flash     = ChatModel{id: "gemini-2.5-flash", provider: ai.ProviderGoogle, pricing: ChatPricing{InputPerMillion: 0.15, OutputPerMillion: 0.60, InputPerMillionLong: 0.15, OutputPerMillionLong: 0.60}}
-	Gemini25FlashLite = ChatModel{id: "gemini-2.5-flash-lite", provider: ai.ProviderGoogle, pricing: ChatPricing{InputPerMillion: 0.075, OutputPerMillion: 0.30, InputPerMillionLong: 0.075, OutputPerMillionLong: 0.30}}
+	Gemini25Pro       = ChatModel{id: "gemini-2.5-pro", provider: ai.ProviderGoogle, pricing: ChatPricing{InputPerMillion: 1.25, OutputPerMillion: 10.00, InputPerMillionLong: 2.50, OutputPerMillionLong: 15.00}, contextWindow: 1_048_576}
+	Gemini25Flash     = ChatModel{id: "gemini-2.5-flash", provider: ai.ProviderGoogle, pricing: ChatPricing{InputPerMillion: 0.15, OutputPerMillion: 0.60, InputPerMillionLong: 0.15, OutputPerMillionLong: 0.60}, contextWindow: 1_048_576}
+	Gemini25FlashLite = ChatModel{id: "gemini-2.5-flash-lite", provider: ai.ProviderGoogle, pricing: ChatPricing{InputPerMillion: 0.075, OutputPerMillion: 0.30, InputPerMillionLong: 0.075, OutputPerMillionLong: 0.30}, contextWindow: 1_048_576}
 
 	// DefaultGeminiModel is the recommended default Google model.
 	DefaultGeminiModel = Gemini25Flash
 
 	// Gemini Image Models (chat models that support image output via ResponseModalities)
 	// Use these with WithImageOutput() to generate images in chat responses.
-	Gemini25FlashImage        = ChatModel{id: "gemini-2.5-flash-preview-image-generation", provider: ai.ProviderGoogle, pricing: ChatPricing{InputPerMillion: 0.15, OutputPerMillion: 0.60}, supportsImageOutput: true}
-	Gemini3ProImagePreview    = ChatModel{id: "gemini-3-pro-image-preview", provider: ai.ProviderGoogle, pricing: ChatPricing{InputPerMillion: 2.00, OutputPerMillion: 12.00}, supportsImageOutput: true}
+	Gemini25FlashImage        = ChatModel{id: "gemini-2.5-flash-preview-image-generation", provider: ai.ProviderGoogle, pricing: ChatPricing{InputPerMillion: 0.15, OutputPerMillion: 0.60}, contextWindow: 32_768, supportsImageOutput: true, noTools: true, noResponseSchema: true}
+	Gemini3ProImagePreview    = ChatModel{id: "gemini-3-pro-image-preview", provider: ai.ProviderGoogle, pricing: ChatPricing{InputPerMillion: 2.00, OutputPerMillion: 12.00}, contextWindow: 65_536, supportsImageOutput: true, noTools: true}
 )
 
 // Google Vertex AI Models (same models as Gemini, but via Vertex AI backend)
@@ -103,20 +126,38 @@ var (
 // Model pricing last verified: December 19, 2025
 var (
 	// Vertex Gemini 3.0 (Latest - November 2025)
-	VertexGemini3Pro          = ChatModel{id: "gemini-3.0-pro", provider: ai.ProviderVertex, pricing: ChatPricing{InputPerMillion: 2.00, OutputPerMillion: 12.00, InputPerMillionLong: 4.00, OutputPerMillionLong: 18.00}}
-	VertexGemini3FlashPreview = ChatModel{id: "gemini-3-flash-preview", provider: ai.ProviderVertex, pricing: ChatPricing{InputPerMillion: 0.15, OutputPerMillion: 0.60}}
-	VertexGemini3DeepThink    = ChatModel{id: "gemini-3.0-deep-think", provider: ai.ProviderVertex, pricing: ChatPricing{InputPerMillion: 4.00, OutputPerMillion: 24.00, InputPerMillionLong: 8.00, OutputPerMillionLong: 36.00}}
+	VertexGemini3Pro          = ChatModel{id: "gemini-3.0-pro", provider: ai.ProviderVertex, pricing: ChatPricing{InputPerMillion: 2.00, OutputPerMillion: 12.00, InputPerMillionLong: 4.00, OutputPerMillionLong: 18.00}, contextWindow: 1_048_576}
+	VertexGemini3FlashPreview = ChatModel{id: "gemini-3-flash-preview", provider: ai.ProviderVertex, pricing: ChatPricing{InputPerMillion: 0.15, OutputPerMillion: 0.60}, contextWindow: 1_048_576}
+	VertexGemini3DeepThink    = ChatModel{id: "gemini-3.0-deep-think", provider: ai.ProviderVertex, pricing: ChatPricing{InputPerMillion: 4.00, OutputPerMillion: 24.00, InputPerMillionLong: 8.00, OutputPerMillionLong: 36.00}, contextWindow: 1_048_576}
 
 	// Vertex Gemini 2.5 Series
-	VertexGemini25Pro       = ChatModel{id: "gemini-2.5-pro", provider: ai.ProviderVertex, pricing: ChatPricing{InputPerMillion: 1.25, OutputPerMillion: 10.00, InputPerMillionLong: 2.50, OutputPerMillionLong: 15.00}}
-	VertexGemini25Flash     = ChatModel{id: "gemini-2.5-flash", provider: ai.ProviderVertex, pricing: ChatPricing{InputPerMillion: 0.15, OutputPerMillion: 0.60, InputPerMillionLong: 0.15, OutputPerMillionLong: 0.60}}
-	VertexGemini25FlashLite = ChatModel{id: "gemini-2.5-flash-lite", provider: ai.ProviderVertex, pricing: ChatPricing{InputPerMillion: 0.075, OutputPerMillion: 0.30, InputPerMillionLong: 0.075, OutputPerMillionLong: 0.30}}
+	VertexGemini25Pro       = ChatModel{id: "gemini-2.5-pro", provider: ai.ProviderVertex, pricing: ChatPricing{InputPerMillion: 1.25, OutputPerMillion: 10.00, InputPerMillionLong: 2.50, OutputPerMillionLong: 15.00}, contextWindow: 1_048_576}
+	VertexGemini25Flash     = ChatModel{id: "gemini-2.5-flash", provider: ai.ProviderVertex, pricing: ChatPricing{InputPerMillion: 0.15, OutputPerMillion: 0.60, InputPerMillionLong: 0.15, OutputPerMillionLong: 0.60}, contextWindow: 1_048_576}
+	VertexGemini25FlashLite = ChatModel{id: "gemini-2.5-flash-lite", provider: ai.ProviderVertex, pricing: ChatPricing{InputPerMillion: 0.075, OutputPerMillion: 0.30, InputPerMillionLong: 0.075, OutputPerMillionLong: 0.30}, contextWindow: 1_048_576}
 
 	// DefaultVertexModel is the recommended default Vertex AI model.
 	DefaultVertexModel = VertexGemini25Flash
 
 	// Vertex Gemini Image Models (chat models that support image output via ResponseModalities)
 	// Use these with WithImageOutput() to generate images in chat responses.
-	VertexGemini25FlashImage     = ChatModel{id: "gemini-2.5-flash-preview-image-generation", provider: ai.ProviderVertex, pricing: ChatPricing{InputPerMillion: 0.15, OutputPerMillion: 0.60}, supportsImageOutput: true}
-	VertexGemini3ProImagePreview = ChatModel{id: "gemini-3-pro-image-preview", provider: ai.ProviderVertex, pricing: ChatPricing{InputPerMillion: 2.00, OutputPerMillion: 12.00}, supportsImageOutput: true}
+	VertexGemini25FlashImage     = ChatModel{id: "gemini-2.5-flash-preview-image-generation", provider: ai.ProviderVertex, pricing: ChatPricing{InputPerMillion: 0.15, OutputPerMillion: 0.60}, contextWindow: 32_768, supportsImageOutput: true, noTools: true, noResponseSchema: true}
+	VertexGemini3ProImagePreview = ChatModel{id: "gemini-3-pro-image-preview", provider: ai.ProviderVertex, pricing: ChatPricing{InputPerMillion: 2.00, OutputPerMillion: 12.00}, contextWindow: 65_536, supportsImageOutput: true, noTools: true}
 )
+
+// ChatModels returns every chat model declared by this package, including
+// pinned versions and the Vertex AI variants of Gemini models.
+func ChatModels() []ChatModel {
+	return []ChatModel{
+		ClaudeOpus45, ClaudeSonnet45, ClaudeHaiku45,
+		ClaudeOpus45_20251101, ClaudeSonnet45_20250929, ClaudeHaiku45_20251001,
+		GPT52, GPT52Pro, GPT51, GPT51Mini, GPT51Codex,
+		GPT5, GPT5Mini, GPT5Nano, GPT5Pro,
+		O3, O3Mini, O4Mini,
+		Gemini3Pro, Gemini3FlashPreview, Gemini3DeepThink,
+		Gemini25Pro, Gemini25Flash, Gemini25FlashLite,
+		Gemini25FlashImage, Gemini3ProImagePreview,
+		VertexGemini3Pro, VertexGemini3FlashPreview, VertexGemini3DeepThink,
+		VertexGemini25Pro, VertexGemini25Flash, VertexGemini25FlashLite,
+		VertexGemini25FlashImage, VertexGemini3ProImagePreview,
+	}
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChatModel_ContextWindow(t *testing.T) {
+	assert.Equal(t, 200_000, ClaudeSonnet45.ContextWindow())
+	assert.Equal(t, 1_048_576, ai.ModelContextWindow(Gemini25Flash))
+}
+
+func TestChatModel_SupportsCapability(t *testing.T) {
+	assert.True(t, ai.ModelSupports(ClaudeSonnet45, ai.CapabilityVision))
+	assert.False(t, ai.ModelSupports(O3Mini, ai.CapabilityVision))
+	assert.True(t, ai.ModelSupports(O3Mini, ai.CapabilityTools))
+	assert.False(t, ai.ModelSupports(Gemini25FlashImage, ai.CapabilityTools))
+	assert.False(t, ai.ModelSupports(Gemini25FlashImage, ai.CapabilityResponseSchema))
+}
+
+func TestChatModels(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, m := range ChatModels() {
+		key := m.Provider().String() + "/" + m.String()
+		assert.False(t, seen[key], "duplicate model %s", key)
+		seen[key] = true
+	}
+	assert.True(t, seen["anthropic/claude-sonnet-4-5"])
+	assert.True(t, seen["vertex/gemini-2.5-flash"])
+}
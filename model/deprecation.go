@@ -0,0 +1,96 @@
+package model
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// Deprecation describes a provider's announced retirement of a model
+// version.
+type Deprecation struct {
+	// Provider is the provider retiring the model.
+	Provider ai.Provider `json:"provider"`
+	// Model is the API identifier of the deprecated model.
+	Model string `json:"model"`
+	// Retires is when the provider stops serving the model, or zero if no
+	// date has been announced.
+	Retires time.Time `json:"retires,omitzero"`
+	// Replacement is the identifier of the recommended successor, if any.
+	Replacement string `json:"replacement,omitempty"`
+	// Note adds context, such as why the model was deprecated.
+	Note string `json:"note,omitempty"`
+}
+
+// Retired reports whether the model's retirement date has passed at t.
+func (d Deprecation) Retired(t time.Time) bool {
+	return !d.Retires.IsZero() && !t.Before(d.Retires)
+}
+
+// String describes the deprecation, as in "claude-3-opus-20240229 is
+// deprecated and retires on 2026-01-05; use claude-opus-4-5".
+func (d Deprecation) String() string {
+	s := d.Model + " is deprecated"
+	if !d.Retires.IsZero() {
+		s += fmt.Sprintf(" and retires on %s", d.Retires.Format(time.DateOnly))
+	}
+	if d.Replacement != "" {
+		s += "; use " + d.Replacement
+	}
+	if d.Note != "" {
+		s += " (" + d.Note + ")"
+	}
+	return s
+}
+
+//go:embed deprecations.json
+var deprecationData []byte
+
+// deprecationList is the embedded deprecation metadata, and deprecations
+// indexes it by provider and model ID.
+var (
+	deprecationList = mustParseDeprecations(deprecationData)
+	deprecations    = indexDeprecations(deprecationList)
+)
+
+func mustParseDeprecations(data []byte) []Deprecation {
+	var list []Deprecation
+	if err := json.Unmarshal(data, &list); err != nil {
+		panic("model: invalid deprecations.json: " + err.Error())
+	}
+	return list
+}
+
+func indexDeprecations(list []Deprecation) map[deprecationKey]Deprecation {
+	index := make(map[deprecationKey]Deprecation, len(list))
+	for _, d := range list {
+		index[deprecationKey{d.Provider, d.Model}] = d
+	}
+	return index
+}
+
+type deprecationKey struct {
+	provider ai.Provider
+	model    string
+}
+
+// Deprecated reports whether m is known to be deprecated by its provider,
+// per the deprecation metadata embedded in this package, and returns the
+// details. It works for any model, including models declared outside this
+// package, by provider and API identifier.
+func Deprecated(m ai.Model) (Deprecation, bool) {
+	if m == nil {
+		return Deprecation{}, false
+	}
+	d, ok := deprecations[deprecationKey{m.Provider(), m.String()}]
+	return d, ok
+}
+
+// Deprecations returns every deprecation in the embedded metadata.
+func Deprecations() []Deprecation {
+	return slices.Clone(deprecationList)
+}
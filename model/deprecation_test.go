@@ -0,0 +1,63 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// idModel is a model declared outside the catalog.
+type idModel struct {
+	id       string
+	provider ai.Provider
+}
+
+func (m idModel) String() string        { return m.id }
+func (m idModel) Provider() ai.Provider { return m.provider }
+
+func TestDeprecated(t *testing.T) {
+	d, ok := Deprecated(idModel{"claude-3-opus-20240229", ai.ProviderAnthropic})
+	require.True(t, ok)
+	assert.Equal(t, "claude-opus-4-5", d.Replacement)
+	assert.Equal(t, "claude-3-opus-20240229 is deprecated and retires on 2026-01-05; use claude-opus-4-5", d.String())
+
+	_, ok = Deprecated(Gemini25FlashImage)
+	assert.True(t, ok, "catalog models are covered")
+
+	_, ok = Deprecated(idModel{"claude-3-opus-20240229", ai.ProviderOpenAI})
+	assert.False(t, ok, "matched by provider")
+
+	_, ok = Deprecated(ClaudeSonnet45)
+	assert.False(t, ok)
+
+	_, ok = Deprecated(nil)
+	assert.False(t, ok)
+}
+
+func TestDeprecation_Retired(t *testing.T) {
+	d := Deprecation{Retires: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)}
+	assert.False(t, d.Retired(time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, d.Retired(time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, Deprecation{}.Retired(time.Now()), "no announced date")
+}
+
+func TestDeprecations(t *testing.T) {
+	list := Deprecations()
+	require.NotEmpty(t, list)
+	for _, d := range list {
+		assert.NotEmpty(t, d.Model)
+		assert.NotEmpty(t, d.Provider)
+	}
+}
+
+func TestDefaultAliases(t *testing.T) {
+	aliases := DefaultAliases()
+	assert.Equal(t, DefaultClaudeModel, aliases["claude-latest"])
+	assert.Equal(t, DefaultGPTModel, aliases["gpt-latest"])
+
+	assert.Equal(t, "cheap-chat", Alias("cheap-chat").String())
+	assert.Empty(t, Alias("cheap-chat").Provider())
+}
@@ -48,6 +48,17 @@
 //	    },
 //	})
 //
+// # Transcription Models
+//
+// Use transcription models for speech-to-text:
+//
+//	c := client.New(client.Config{
+//	    APIKeys: client.APIKeys{OpenAI: os.Getenv("OPENAI_API_KEY")},
+//	    Defaults: client.Defaults{
+//	        Transcription: model.GPT4oMiniTranscribe,
+//	    },
+//	})
+//
 // # Pricing Information
 //
 // All models include pricing methods for cost estimation:
@@ -62,7 +73,7 @@
 //
 //	pricing := model.GPT52.Pricing()
 //	if pricing.HasCachedPricing() {
-//	    // OpenAI models support cached input pricing
+//	    // OpenAI and Anthropic models support cached input pricing
 //	    cachedCost := float64(cachedTokens) / 1_000_000 * pricing.CachedInputPerMillion
 //	}
 //
@@ -72,11 +83,29 @@
 //	    longInputCost := float64(tokens) / 1_000_000 * pricing.InputPerMillionLong
 //	}
 //
+// ChatModel.Cost applies both tiers automatically using [CalculateTieredCost],
+// based on Usage.CachedInputTokens, Usage.CacheWriteInputTokens, and the
+// prompt size.
+//
+// # Aliases
+//
+// An [Alias] names a model by role instead of ID. The client resolves it
+// through client.Config Aliases, falling back to [DefaultAliases]
+// (claude-latest, gpt-latest, gemini-latest, vertex-latest):
+//
+//	ai.WithModel(model.Alias("cheap-chat"))
+//
+// # Deprecations
+//
+// [Deprecated] reports whether a provider has announced the retirement of
+// a model, with its retirement date and replacement. The client emits
+// EventModelDeprecated the first time it uses such a model.
+//
 // # Available Providers
 //
 // Models are available for three providers:
 //
 //   - Anthropic: Claude models (chat only)
-//   - OpenAI: GPT and O-series models (chat, image, embedding)
-//   - Google: Gemini and Imagen models (chat, image, embedding)
+//   - OpenAI: GPT and O-series models (chat, image, embedding, transcription)
+//   - Google: Gemini and Imagen models (chat, image, embedding, transcription)
 package model
@@ -9,9 +9,13 @@ type ChatPricing struct {
 	InputPerMillion float64
 	// OutputPerMillion is the standard output token pricing (all providers).
 	OutputPerMillion float64
-	// CachedInputPerMillion is for cached/prompt-cached input tokens (OpenAI only).
+	// CachedInputPerMillion is for cached/prompt-cached input tokens (OpenAI, Anthropic).
 	// Check HasCachedPricing() before using.
 	CachedInputPerMillion float64
+	// CacheWriteInputPerMillion is for input tokens written to the prompt
+	// cache (Anthropic, 1.25x the input rate for the default 5-minute cache).
+	// Cache writes are billed at the standard input rate when it is zero.
+	CacheWriteInputPerMillion float64
 	// InputPerMillionLong is for long context >200K tokens (Google only).
 	// Check HasLongContextPricing() before using.
 	InputPerMillionLong float64
@@ -53,12 +57,35 @@ func (p ImagePricing) HasFlatPricing() bool {
 	return p.PerImage > 0
 }
 
+// Cost returns the price in USD for generating count images at the given quality.
+// Quality tiers take precedence over flat pricing when both are set. Standard
+// (or unspecified) quality uses the medium tier and HD uses the high tier.
+func (p ImagePricing) Cost(quality ai.ImageQuality, count int) float64 {
+	if !p.HasQualityTiers() {
+		return p.PerImage * float64(count)
+	}
+	price := p.MediumQuality
+	if quality == ai.ImageQualityHD {
+		price = p.HighQuality
+	}
+	return price * float64(count)
+}
+
 // EmbeddingPricing contains embedding pricing per million tokens (USD).
 type EmbeddingPricing struct {
 	// PerMillion is the price per million tokens.
 	PerMillion float64
 }
 
+// Cost returns the price in USD for the given embedding token usage.
+func (p EmbeddingPricing) Cost(usage ai.Usage) float64 {
+	return float64(usage.InputTokens) * p.PerMillion / 1_000_000
+}
+
+// LongContextThreshold is the prompt size in tokens above which long context
+// pricing applies for models that have it.
+const LongContextThreshold = 200_000
+
 // CalculateCost computes the cost in USD for the given token usage and pricing.
 // Uses standard per-million token rates; does not account for cached
 // input tokens or long context tiers.
@@ -67,3 +94,64 @@ func CalculateCost(usage ai.Usage, pricing ChatPricing) float64 {
 	outputCost := float64(usage.OutputTokens) * pricing.OutputPerMillion / 1_000_000
 	return inputCost + outputCost
 }
+
+// CalculateTieredCost computes the cost in USD for the given token usage,
+// applying the cached input rate to usage.CachedInputTokens, the cache write
+// rate to usage.CacheWriteInputTokens, and long context rates when the prompt
+// exceeds LongContextThreshold. Tiers the pricing does not define fall back
+// to the standard rates.
+func CalculateTieredCost(usage ai.Usage, pricing ChatPricing) float64 {
+	inputRate, outputRate := pricing.InputPerMillion, pricing.OutputPerMillion
+	if usage.InputTokens > LongContextThreshold {
+		if pricing.InputPerMillionLong > 0 {
+			inputRate = pricing.InputPerMillionLong
+		}
+		if pricing.OutputPerMillionLong > 0 {
+			outputRate = pricing.OutputPerMillionLong
+		}
+	}
+
+	uncached := usage.InputTokens
+	var cachedCost float64
+	if pricing.HasCachedPricing() && usage.CachedInputTokens > 0 {
+		cached := min(usage.CachedInputTokens, usage.InputTokens)
+		uncached -= cached
+		cachedCost = float64(cached) * pricing.CachedInputPerMillion / 1_000_000
+	}
+	var writeCost float64
+	if pricing.CacheWriteInputPerMillion > 0 && usage.CacheWriteInputTokens > 0 {
+		written := min(usage.CacheWriteInputTokens, uncached)
+		uncached -= written
+		writeCost = float64(written) * pricing.CacheWriteInputPerMillion / 1_000_000
+	}
+
+	inputCost := float64(uncached) * inputRate / 1_000_000
+	outputCost := float64(usage.OutputTokens) * outputRate / 1_000_000
+	return inputCost + cachedCost + writeCost + outputCost
+}
+
+// TranscriptionPricing contains speech-to-text pricing (USD).
+// Token-billed models report usage and are priced per token; others are
+// priced by audio duration.
+type TranscriptionPricing struct {
+	// PerMinute is the price per minute of audio.
+	PerMinute float64
+	// InputPerMillion is the price per million audio input tokens.
+	InputPerMillion float64
+	// OutputPerMillion is the price per million text output tokens.
+	OutputPerMillion float64
+}
+
+// Cost returns the price in USD for a transcription. Token pricing is used
+// when the response reports token usage; otherwise the audio duration is
+// priced per minute.
+func (p TranscriptionPricing) Cost(resp *ai.TranscriptionResponse) float64 {
+	if resp == nil {
+		return 0
+	}
+	if resp.Usage.InputTokens > 0 || resp.Usage.OutputTokens > 0 {
+		return float64(resp.Usage.InputTokens)*p.InputPerMillion/1_000_000 +
+			float64(resp.Usage.OutputTokens)*p.OutputPerMillion/1_000_000
+	}
+	return resp.Duration.Minutes() * p.PerMinute
+}
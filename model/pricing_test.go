@@ -2,6 +2,7 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	ai "github.com/spetersoncode/gains"
 	"github.com/stretchr/testify/assert"
@@ -61,8 +62,13 @@ func TestChatPricing_HasCachedPricing(t *testing.T) {
 		assert.True(t, pricing.HasCachedPricing())
 	})
 
-	t.Run("returns false when no cached pricing", func(t *testing.T) {
+	t.Run("returns true for Anthropic models", func(t *testing.T) {
 		pricing := ClaudeSonnet45.Pricing()
+		assert.True(t, pricing.HasCachedPricing())
+	})
+
+	t.Run("returns false when no cached pricing", func(t *testing.T) {
+		pricing := Gemini25Pro.Pricing()
 		assert.False(t, pricing.HasCachedPricing())
 	})
 }
@@ -78,3 +84,83 @@ func TestChatPricing_HasLongContextPricing(t *testing.T) {
 		assert.False(t, pricing.HasLongContextPricing())
 	})
 }
+
+func TestCalculateTieredCost(t *testing.T) {
+	t.Run("matches standard cost without tiers", func(t *testing.T) {
+		usage := ai.Usage{InputTokens: 1000, OutputTokens: 500}
+		pricing := ChatPricing{InputPerMillion: 1.00, OutputPerMillion: 2.00}
+		assert.InDelta(t, CalculateCost(usage, pricing), CalculateTieredCost(usage, pricing), 0.000001)
+	})
+
+	t.Run("prices cached input tokens", func(t *testing.T) {
+		pricing := ChatPricing{InputPerMillion: 1.00, OutputPerMillion: 2.00, CachedInputPerMillion: 0.10}
+		usage := ai.Usage{InputTokens: 1_000_000, CachedInputTokens: 400_000}
+		// 600K * $1/M + 400K * $0.10/M = $0.60 + $0.04
+		assert.InDelta(t, 0.64, CalculateTieredCost(usage, pricing), 0.0001)
+	})
+
+	t.Run("prices cache writes", func(t *testing.T) {
+		pricing := ClaudeSonnet45.Pricing()
+		usage := ai.Usage{InputTokens: 1_000_000, CachedInputTokens: 200_000, CacheWriteInputTokens: 300_000}
+		// 500K * $3/M + 200K * $0.30/M + 300K * $3.75/M = $1.50 + $0.06 + $1.125
+		assert.InDelta(t, 2.685, CalculateTieredCost(usage, pricing), 0.0001)
+	})
+
+	t.Run("prices cache writes at the input rate without write pricing", func(t *testing.T) {
+		pricing := ChatPricing{InputPerMillion: 1.00, OutputPerMillion: 2.00}
+		usage := ai.Usage{InputTokens: 1_000_000, CacheWriteInputTokens: 400_000}
+		assert.InDelta(t, 1.0, CalculateTieredCost(usage, pricing), 0.0001)
+	})
+
+	t.Run("ignores cached tokens without cached pricing", func(t *testing.T) {
+		pricing := ChatPricing{InputPerMillion: 1.00, OutputPerMillion: 2.00}
+		usage := ai.Usage{InputTokens: 1_000_000, CachedInputTokens: 400_000}
+		assert.InDelta(t, 1.0, CalculateTieredCost(usage, pricing), 0.0001)
+	})
+
+	t.Run("applies long context rates above threshold", func(t *testing.T) {
+		pricing := Gemini25Pro.Pricing()
+		short := ai.Usage{InputTokens: LongContextThreshold, OutputTokens: 1_000_000}
+		long := ai.Usage{InputTokens: LongContextThreshold + 1, OutputTokens: 1_000_000}
+
+		// 200K * $1.25/M + 1M * $10/M
+		assert.InDelta(t, 10.25, CalculateTieredCost(short, pricing), 0.0001)
+		// 200,001 * $2.50/M + 1M * $15/M
+		assert.InDelta(t, 15.5, CalculateTieredCost(long, pricing), 0.0001)
+	})
+}
+
+func TestEmbeddingPricing_Cost(t *testing.T) {
+	cost := TextEmbedding3Small.Pricing().Cost(ai.Usage{InputTokens: 1_000_000})
+	assert.InDelta(t, 0.02, cost, 0.0001)
+}
+
+func TestImagePricing_Cost(t *testing.T) {
+	t.Run("flat pricing", func(t *testing.T) {
+		pricing := ImagePricing{PerImage: 0.04}
+		assert.InDelta(t, 0.08, pricing.Cost(ai.ImageQualityHD, 2), 0.0001)
+	})
+
+	t.Run("quality tiers", func(t *testing.T) {
+		pricing := ImagePricing{LowQuality: 0.01, MediumQuality: 0.04, HighQuality: 0.17}
+		assert.InDelta(t, 0.04, pricing.Cost("", 1), 0.0001)
+		assert.InDelta(t, 0.04, pricing.Cost(ai.ImageQualityStandard, 1), 0.0001)
+		assert.InDelta(t, 0.34, pricing.Cost(ai.ImageQualityHD, 2), 0.0001)
+	})
+}
+
+func TestTranscriptionPricing_Cost(t *testing.T) {
+	t.Run("duration pricing", func(t *testing.T) {
+		resp := &ai.TranscriptionResponse{Duration: 90 * time.Second}
+		assert.InDelta(t, 0.009, Whisper1.Pricing().Cost(resp), 0.0001)
+	})
+
+	t.Run("token pricing", func(t *testing.T) {
+		resp := &ai.TranscriptionResponse{Usage: ai.Usage{InputTokens: 1_000_000, OutputTokens: 100_000}}
+		assert.InDelta(t, 3.5, GPT4oMiniTranscribe.Pricing().Cost(resp), 0.0001)
+	})
+
+	t.Run("nil response", func(t *testing.T) {
+		assert.Zero(t, Whisper1.Pricing().Cost(nil))
+	})
+}
@@ -0,0 +1,55 @@
+package model
+
+import ai "github.com/spetersoncode/gains"
+
+// TranscriptionModel represents a speech-to-text model from any provider.
+type TranscriptionModel struct {
+	id       string
+	provider ai.Provider
+	pricing  TranscriptionPricing
+}
+
+// String returns the API identifier for this model.
+func (m TranscriptionModel) String() string { return m.id }
+
+// Provider returns which provider this model belongs to.
+func (m TranscriptionModel) Provider() ai.Provider { return m.provider }
+
+// Pricing returns the pricing for this model.
+func (m TranscriptionModel) Pricing() TranscriptionPricing { return m.pricing }
+
+// OpenAI Transcription Models
+// Model pricing last verified: December 14, 2025
+var (
+	// GPT-4o Transcribe Series (token-billed)
+	GPT4oTranscribe     = TranscriptionModel{id: "gpt-4o-transcribe", provider: ai.ProviderOpenAI, pricing: TranscriptionPricing{PerMinute: 0.006, InputPerMillion: 6.00, OutputPerMillion: 10.00}}
+	GPT4oMiniTranscribe = TranscriptionModel{id: "gpt-4o-mini-transcribe", provider: ai.ProviderOpenAI, pricing: TranscriptionPricing{PerMinute: 0.003, InputPerMillion: 3.00, OutputPerMillion: 5.00}}
+
+	// Whisper (duration-billed)
+	Whisper1 = TranscriptionModel{id: "whisper-1", provider: ai.ProviderOpenAI, pricing: TranscriptionPricing{PerMinute: 0.006}}
+
+	// DefaultOpenAITranscriptionModel is the recommended default OpenAI transcription model.
+	DefaultOpenAITranscriptionModel = GPT4oMiniTranscribe
+)
+
+// Google Transcription Models
+// Gemini transcribes audio through multimodal generation; pricing is per audio input token.
+// Model pricing last verified: December 14, 2025
+var (
+	// Gemini 2.5 Flash
+	Gemini25FlashTranscribe = TranscriptionModel{id: "gemini-2.5-flash", provider: ai.ProviderGoogle, pricing: TranscriptionPricing{InputPerMillion: 1.00, OutputPerMillion: 2.50}}
+
+	// DefaultGoogleTranscriptionModel is the recommended default Google transcription model.
+	DefaultGoogleTranscriptionModel = Gemini25FlashTranscribe
+)
+
+// Google Vertex AI Transcription Models (via Vertex AI backend)
+// Vertex AI uses Application Default Credentials instead of API keys.
+// Model pricing last verified: December 14, 2025
+var (
+	// Vertex Gemini 2.5 Flash
+	VertexGemini25FlashTranscribe = TranscriptionModel{id: "gemini-2.5-flash", provider: ai.ProviderVertex, pricing: TranscriptionPricing{InputPerMillion: 1.00, OutputPerMillion: 2.50}}
+
+	// DefaultVertexTranscriptionModel is the recommended default Vertex AI transcription model.
+	DefaultVertexTranscriptionModel = VertexGemini25FlashTranscribe
+)
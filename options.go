@@ -25,6 +25,59 @@ func ModelSupportsImageOutput(m Model) bool {
 	return false
 }
 
+// Capability is a request feature that some chat models do not support.
+type Capability string
+
+const (
+	CapabilityTools          Capability = "tools"
+	CapabilityVision         Capability = "vision"
+	CapabilityResponseSchema Capability = "response schema"
+)
+
+// CapabilityReporter is an optional interface that models can implement
+// to report which request features they support.
+type CapabilityReporter interface {
+	SupportsCapability(c Capability) bool
+}
+
+// ModelSupports checks if a model supports a request feature. Models that
+// do not implement CapabilityReporter are assumed to support every feature.
+func ModelSupports(m Model, c Capability) bool {
+	if cr, ok := m.(CapabilityReporter); ok {
+		return cr.SupportsCapability(c)
+	}
+	return true
+}
+
+// RequiredCapabilities returns the features a chat request needs: tools
+// when tools are offered, vision when a message contains an image, and
+// response schema when a schema is set.
+func RequiredCapabilities(messages []Message, o *Options) []Capability {
+	var caps []Capability
+	if len(o.Tools) > 0 {
+		caps = append(caps, CapabilityTools)
+	}
+	if hasImages(messages) {
+		caps = append(caps, CapabilityVision)
+	}
+	if o.ResponseSchema != nil {
+		caps = append(caps, CapabilityResponseSchema)
+	}
+	return caps
+}
+
+// hasImages reports whether any message contains an image part.
+func hasImages(messages []Message) bool {
+	for _, m := range messages {
+		for _, p := range m.Parts {
+			if p.Type == ContentPartTypeImage {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // ResponseFormat specifies how the model should format its response.
 type ResponseFormat string
 
@@ -33,14 +86,14 @@ type ResponseFormat string
 type ImageAspectRatio string
 
 const (
-	ImageAspectRatio1x1   ImageAspectRatio = "1:1"
-	ImageAspectRatio2x3   ImageAspectRatio = "2:3"
-	ImageAspectRatio3x2   ImageAspectRatio = "3:2"
-	ImageAspectRatio3x4   ImageAspectRatio = "3:4"
-	ImageAspectRatio4x3   ImageAspectRatio = "4:3"
-	ImageAspectRatio9x16  ImageAspectRatio = "9:16"
-	ImageAspectRatio16x9  ImageAspectRatio = "16:9"
-	ImageAspectRatio21x9  ImageAspectRatio = "21:9"
+	ImageAspectRatio1x1  ImageAspectRatio = "1:1"
+	ImageAspectRatio2x3  ImageAspectRatio = "2:3"
+	ImageAspectRatio3x2  ImageAspectRatio = "3:2"
+	ImageAspectRatio3x4  ImageAspectRatio = "3:4"
+	ImageAspectRatio4x3  ImageAspectRatio = "4:3"
+	ImageAspectRatio9x16 ImageAspectRatio = "9:16"
+	ImageAspectRatio16x9 ImageAspectRatio = "16:9"
+	ImageAspectRatio21x9 ImageAspectRatio = "21:9"
 )
 
 // ImageOutputSize specifies the resolution for generated images in chat responses.
@@ -68,10 +121,19 @@ type ResponseSchema struct {
 	Description string
 	// Schema is the JSON Schema definition.
 	Schema json.RawMessage
-	// Strict enables strict schema enforcement (OpenAI only, defaults to true).
+	// Strict is ignored; strict enforcement is on unless disabled with
+	// WithStrictSchema(false).
+	//
+	// Deprecated: Use WithStrictSchema.
 	Strict bool
 }
 
+// SchemaTransformFunc rewrites a response schema just before it is sent to
+// provider. The schema has already been adapted for the provider, for
+// example with OpenAI's strict-mode requirements, and may be modified in
+// place or replaced.
+type SchemaTransformFunc func(provider Provider, schema map[string]any) map[string]any
+
 // Options contains configuration for a chat request.
 type Options struct {
 	Model            Model
@@ -81,10 +143,29 @@ type Options struct {
 	ToolChoice       ToolChoice
 	ResponseFormat   ResponseFormat
 	ResponseSchema   *ResponseSchema
-	RetryConfig      *RetryConfig     // Per-call retry config override (nil = use client default)
-	ImageOutput      bool             // Enable image output for models that support it
-	ImageAspectRatio ImageAspectRatio // Aspect ratio for generated images (Google/Vertex only)
-	ImageOutputSize  ImageOutputSize  // Resolution for generated images (Google/Vertex only)
+	StrictSchema     *bool               // Strict response schema enforcement (OpenAI only, nil = strict)
+	SchemaTransform  SchemaTransformFunc // Provider-specific response schema rewrite
+	SchemaWarning    SchemaWarningFunc   // Receives constraints dropped from schemas (nil = log)
+	SchemaRetries    int                 // Re-requests after a response fails schema validation
+	RetryConfig      *RetryConfig        // Per-call retry config override (nil = use client default)
+	ImageOutput      bool                // Enable image output for models that support it
+	ImageAspectRatio ImageAspectRatio    // Aspect ratio for generated images (Google/Vertex only)
+	ImageOutputSize  ImageOutputSize     // Resolution for generated images (Google/Vertex only)
+	CacheControl     bool                // Mark prompt cache breakpoints (Anthropic only)
+	AudioOutput      *AudioOutput        // Request spoken audio output (OpenAI only)
+	Metadata         map[string]string   // Application key/value pairs reported in client events
+	CandidateCount   int                 // Number of alternative completions to return (0 or 1 = one)
+	Constraint       *OutputConstraint   // Regex or grammar the response must match
+	Region           string              // Location to serve the request (Vertex only, "" = client's choice)
+}
+
+// AudioOutput configures spoken audio in chat responses.
+type AudioOutput struct {
+	// Voice is the provider voice name (e.g., "alloy", "coral").
+	Voice string
+	// Format is the audio encoding (e.g., "wav", "mp3", "pcm16").
+	// Streaming responses only support "pcm16".
+	Format string
 }
 
 // Option is a functional option for configuring chat requests.
@@ -144,6 +225,54 @@ func WithResponseSchema(schema ResponseSchema) Option {
 	}
 }
 
+// WithStrictSchema controls strict enforcement of the response schema.
+// Strict mode, the default, guarantees output matching the schema but only
+// accepts a subset of JSON Schema: every object is closed to additional
+// properties and every property is required, with optional properties made
+// nullable instead. Disable it for schemas outside that subset.
+// Note: Only affects OpenAI.
+func WithStrictSchema(strict bool) Option {
+	return func(o *Options) {
+		o.StrictSchema = &strict
+	}
+}
+
+// WithSchemaTransform sets a hook that rewrites the response schema for
+// each provider, for edge cases such as keywords one provider rejects:
+//
+//	ai.WithSchemaTransform(func(p ai.Provider, schema map[string]any) map[string]any {
+//	    if p == ai.ProviderGoogle {
+//	        delete(schema, "$schema")
+//	    }
+//	    return schema
+//	})
+func WithSchemaTransform(fn SchemaTransformFunc) Option {
+	return func(o *Options) {
+		o.SchemaTransform = fn
+	}
+}
+
+// TransformSchema returns schema rewritten by the SchemaTransform hook for
+// provider, or schema unchanged if no hook is set.
+func (o *Options) TransformSchema(provider Provider, schema map[string]any) map[string]any {
+	if o.SchemaTransform == nil {
+		return schema
+	}
+	return o.SchemaTransform(provider, schema)
+}
+
+// WithSchemaRetry repairs and validates responses to a WithResponseSchema
+// request. JSON is extracted from surrounding text or code fences with
+// RepairJSON and checked with ValidateJSON; a response that still fails is
+// sent back to the model with the validation errors, up to n more times,
+// before client.Client.Chat fails with *ErrSchemaViolation. Streaming
+// responses are not retried.
+func WithSchemaRetry(n int) Option {
+	return func(o *Options) {
+		o.SchemaRetries = n
+	}
+}
+
 // WithRetry overrides the client's default retry configuration for this request.
 // Use DefaultRetryConfig(), DisabledRetryConfig(), or NewRetryConfig() to create configs.
 func WithRetry(cfg RetryConfig) Option {
@@ -188,6 +317,58 @@ func WithImageOutputSize(size ImageOutputSize) Option {
 	}
 }
 
+// WithAudioOutput requests spoken audio alongside text. The audio is returned
+// as an audio part in Response.Parts and its transcript as Response.Content.
+// Note: Only supported by OpenAI audio models (e.g., gpt-4o-audio-preview).
+func WithAudioOutput(voice, format string) Option {
+	return func(o *Options) {
+		o.AudioOutput = &AudioOutput{Voice: voice, Format: format}
+	}
+}
+
+// WithCacheControl enables prompt caching for providers that need explicit
+// cache breakpoints. The system prompt, tool definitions, and conversation up
+// to the latest message are marked cacheable, so a repeated prefix is billed
+// at the cached input rate on later turns and reported in Usage.CachedInputTokens.
+// Note: Only affects Anthropic; OpenAI and Google cache prompts automatically.
+func WithCacheControl() Option {
+	return func(o *Options) {
+		o.CacheControl = true
+	}
+}
+
+// WithMetadata attaches an application-defined key/value pair, such as a
+// tenant or user ID, to the request. Metadata is not sent to the provider;
+// the client reports it on the events it emits for the request.
+func WithMetadata(key, value string) Option {
+	return func(o *Options) {
+		if o.Metadata == nil {
+			o.Metadata = make(map[string]string)
+		}
+		o.Metadata[key] = value
+	}
+}
+
+// WithRegion sends the request to the given provider location, such as
+// "europe-west4", for data residency or latency. Only Vertex AI requests
+// are routed by region; the client keeps one connection per location.
+func WithRegion(region string) Option {
+	return func(o *Options) {
+		o.Region = region
+	}
+}
+
+// WithCandidateCount requests n alternative completions for the same
+// request, returned in Response.Candidates. The response's own fields hold
+// the first candidate. OpenAI and Google sample candidates in one request;
+// for other providers the client sends n requests concurrently and sums
+// their usage. Only Chat returns candidates; ChatStream streams one.
+func WithCandidateCount(n int) Option {
+	return func(o *Options) {
+		o.CandidateCount = n
+	}
+}
+
 // ApplyOptions applies functional options to an Options struct.
 func ApplyOptions(opts ...Option) *Options {
 	o := &Options{}
@@ -153,6 +153,30 @@ func TestWithJSONMode(t *testing.T) {
 	})
 }
 
+func TestWithCacheControl(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		assert.False(t, ApplyOptions().CacheControl)
+	})
+
+	t.Run("enables cache control", func(t *testing.T) {
+		opts := ApplyOptions(WithCacheControl())
+		assert.True(t, opts.CacheControl)
+	})
+}
+
+func TestWithAudioOutput(t *testing.T) {
+	t.Run("disabled by default", func(t *testing.T) {
+		assert.Nil(t, ApplyOptions().AudioOutput)
+	})
+
+	t.Run("sets voice and format", func(t *testing.T) {
+		opts := ApplyOptions(WithAudioOutput("alloy", "wav"))
+		require.NotNil(t, opts.AudioOutput)
+		assert.Equal(t, "alloy", opts.AudioOutput.Voice)
+		assert.Equal(t, "wav", opts.AudioOutput.Format)
+	})
+}
+
 func TestWithResponseSchema(t *testing.T) {
 	t.Run("sets schema and enables JSON mode", func(t *testing.T) {
 		schema := ResponseSchema{
@@ -222,3 +246,61 @@ func TestDisabledRetryConfig(t *testing.T) {
 
 	assert.Equal(t, 1, cfg.MaxAttempts)
 }
+
+func TestWithStrictSchema(t *testing.T) {
+	assert.Nil(t, ApplyOptions().StrictSchema)
+
+	opts := ApplyOptions(WithStrictSchema(false))
+	require.NotNil(t, opts.StrictSchema)
+	assert.False(t, *opts.StrictSchema)
+}
+
+func TestOptions_TransformSchema(t *testing.T) {
+	schema := map[string]any{"type": "object", "$schema": "https://json-schema.org/draft/2020-12/schema"}
+
+	t.Run("returns schema unchanged without a hook", func(t *testing.T) {
+		assert.Equal(t, schema, ApplyOptions().TransformSchema(ProviderGoogle, schema))
+	})
+
+	t.Run("applies the hook per provider", func(t *testing.T) {
+		opts := ApplyOptions(WithSchemaTransform(func(p Provider, s map[string]any) map[string]any {
+			if p != ProviderGoogle {
+				return s
+			}
+			out := map[string]any{}
+			for k, v := range s {
+				if k != "$schema" {
+					out[k] = v
+				}
+			}
+			return out
+		}))
+		assert.Equal(t, map[string]any{"type": "object"}, opts.TransformSchema(ProviderGoogle, schema))
+		assert.Equal(t, schema, opts.TransformSchema(ProviderOpenAI, schema))
+	})
+}
+
+// textOnlyModel is a Model without tool or vision support.
+type textOnlyModel struct{ testModel }
+
+func (textOnlyModel) SupportsCapability(c Capability) bool {
+	return c == CapabilityResponseSchema
+}
+
+func TestModelSupports(t *testing.T) {
+	assert.True(t, ModelSupports(testModel("m"), CapabilityTools), "models without metadata support everything")
+	assert.False(t, ModelSupports(textOnlyModel{"m"}, CapabilityTools))
+	assert.True(t, ModelSupports(textOnlyModel{"m"}, CapabilityResponseSchema))
+}
+
+func TestRequiredCapabilities(t *testing.T) {
+	text := []Message{{Role: RoleUser, Content: "Hi"}}
+	assert.Empty(t, RequiredCapabilities(text, ApplyOptions()))
+
+	image := []Message{{Role: RoleUser, Parts: []ContentPart{NewTextPart("What is this?"), NewImageURLPart("https://example.com/a.png")}}}
+	o := ApplyOptions(
+		WithTools([]Tool{{Name: "search"}}),
+		WithResponseSchema(ResponseSchema{Name: "answer"}),
+	)
+	assert.Equal(t, []Capability{CapabilityTools, CapabilityVision, CapabilityResponseSchema}, RequiredCapabilities(image, o))
+}
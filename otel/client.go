@@ -0,0 +1,220 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/client"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/model"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instruments holds the metrics recorded for client requests.
+type instruments struct {
+	tokens   metric.Int64Histogram
+	duration metric.Float64Histogram
+	cost     metric.Float64Counter
+}
+
+func newInstruments(mp metric.MeterProvider) *instruments {
+	meter := mp.Meter(instrumentationName)
+	var ins instruments
+	var err error
+	ins.tokens, err = meter.Int64Histogram("gen_ai.client.token.usage",
+		metric.WithUnit("{token}"),
+		metric.WithDescription("Number of input and output tokens used"))
+	handle(err)
+	ins.duration, err = meter.Float64Histogram("gen_ai.client.operation.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of generative AI client operations"))
+	handle(err)
+	ins.cost, err = meter.Float64Counter("gains.client.cost",
+		metric.WithUnit("USD"),
+		metric.WithDescription("Estimated cost of generative AI client operations"))
+	handle(err)
+	return &ins
+}
+
+// handle reports an instrument creation error to the global error handler.
+// The instrument returned alongside such an error is still usable.
+func handle(err error) {
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// request tracks the span and metrics of one client request.
+type request struct {
+	ins   *instruments
+	span  trace.Span
+	start time.Time
+	model ai.Model
+	attrs []attribute.KeyValue // metric attributes
+}
+
+// startRequest starts the span of a client request. m may be nil if the
+// model is unknown.
+func startRequest(ctx context.Context, cfg *config, ins *instruments, operation string, m ai.Model, spanAttrs ...attribute.KeyValue) (context.Context, *request) {
+	attrs := []attribute.KeyValue{attrOperationName.String(operation)}
+	name := operation
+	if m != nil {
+		attrs = append(attrs,
+			attrProviderName.String(m.Provider().String()),
+			attrRequestModel.String(m.String()),
+		)
+		name = operation + " " + m.String()
+	}
+	ctx, span := cfg.tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, spanAttrs...)...))
+	// Clip attrs so appending per-record attributes never shares storage
+	return ctx, &request{ins: ins, span: span, start: time.Now(), model: m, attrs: slices.Clip(attrs)}
+}
+
+// end records the request's outcome and usage and ends its span.
+func (r *request) end(ctx context.Context, usage *ai.Usage, finishReason string, err error) {
+	attrs := r.attrs
+	if err != nil {
+		attrs = append(attrs, attrErrorType.String(fmt.Sprintf("%T", err)))
+		r.span.RecordError(err)
+		r.span.SetStatus(codes.Error, err.Error())
+	}
+	r.ins.duration.Record(ctx, time.Since(r.start).Seconds(), metric.WithAttributes(attrs...))
+
+	if usage != nil {
+		r.span.SetAttributes(
+			attrInputTokens.Int(usage.InputTokens),
+			attrOutputTokens.Int(usage.OutputTokens),
+		)
+		r.ins.tokens.Record(ctx, int64(usage.InputTokens),
+			metric.WithAttributes(append(r.attrs, attrTokenType.String("input"))...))
+		if usage.OutputTokens > 0 {
+			r.ins.tokens.Record(ctx, int64(usage.OutputTokens),
+				metric.WithAttributes(append(r.attrs, attrTokenType.String("output"))...))
+		}
+		if cost, ok := r.cost(*usage); ok {
+			r.ins.cost.Add(ctx, cost, metric.WithAttributes(r.attrs...))
+		}
+	}
+	if finishReason != "" {
+		r.span.SetAttributes(attrFinishReasons.StringSlice([]string{finishReason}))
+	}
+	r.span.End()
+}
+
+// cost prices usage with the request model's pricing, if it has any.
+func (r *request) cost(usage ai.Usage) (float64, bool) {
+	switch priced := r.model.(type) {
+	case interface{ Pricing() model.ChatPricing }:
+		return model.CalculateTieredCost(usage, priced.Pricing()), true
+	case interface{ Pricing() model.EmbeddingPricing }:
+		return priced.Pricing().Cost(usage), true
+	default:
+		return 0, false
+	}
+}
+
+// chatModel returns the model of a chat request, or the default model if
+// the request does not set one.
+func (c *config) chatModel(options *ai.Options) ai.Model {
+	if options.Model != nil {
+		return options.Model
+	}
+	return c.defaultModel
+}
+
+// chatAttributes returns the span attributes of a chat request's options.
+func chatAttributes(options *ai.Options) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if options.MaxTokens > 0 {
+		attrs = append(attrs, attrMaxTokens.Int(options.MaxTokens))
+	}
+	if options.Temperature != nil {
+		attrs = append(attrs, attrTemperature.Float64(*options.Temperature))
+	}
+	return attrs
+}
+
+// ClientMiddleware returns client middleware that traces each request and
+// records its token usage, duration, and cost. Streams are traced until
+// their channel closes.
+func ClientMiddleware(opts ...Option) client.Middleware {
+	cfg := newConfig(opts)
+	ins := newInstruments(cfg.meterProvider)
+
+	return client.Middleware{
+		Chat: func(next client.ChatFunc) client.ChatFunc {
+			return func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+				options := ai.ApplyOptions(append(ai.ContextOptions(ctx), opts...)...)
+				ctx, req := startRequest(ctx, cfg, ins, "chat", cfg.chatModel(options), chatAttributes(options)...)
+				resp, err := next(ctx, messages, opts...)
+				if resp != nil {
+					req.end(ctx, &resp.Usage, resp.FinishReason, err)
+				} else {
+					req.end(ctx, nil, "", err)
+				}
+				return resp, err
+			}
+		},
+		ChatStream: func(next client.ChatStreamFunc) client.ChatStreamFunc {
+			return func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+				options := ai.ApplyOptions(append(ai.ContextOptions(ctx), opts...)...)
+				ctx, req := startRequest(ctx, cfg, ins, "chat", cfg.chatModel(options), chatAttributes(options)...)
+				ch, err := next(ctx, messages, opts...)
+				if err != nil {
+					req.end(ctx, nil, "", err)
+					return nil, err
+				}
+				out := make(chan event.Event, cap(ch))
+				go func() {
+					defer close(out)
+					var resp *ai.Response
+					var streamErr error
+					for e := range ch {
+						switch e.Type {
+						case event.MessageEnd:
+							resp = e.Response
+						case event.RunError:
+							streamErr = e.Error
+						}
+						out <- e
+					}
+					if resp != nil {
+						req.end(ctx, &resp.Usage, resp.FinishReason, streamErr)
+					} else {
+						req.end(ctx, nil, "", streamErr)
+					}
+				}()
+				return out, nil
+			}
+		},
+		Embed: func(next client.EmbedFunc) client.EmbedFunc {
+			return func(ctx context.Context, texts []string, opts ...ai.EmbeddingOption) (*ai.EmbeddingResponse, error) {
+				ctx, req := startRequest(ctx, cfg, ins, "embeddings", ai.ApplyEmbeddingOptions(opts...).Model)
+				resp, err := next(ctx, texts, opts...)
+				if resp != nil {
+					req.end(ctx, &resp.Usage, "", err)
+				} else {
+					req.end(ctx, nil, "", err)
+				}
+				return resp, err
+			}
+		},
+		Image: func(next client.ImageFunc) client.ImageFunc {
+			return func(ctx context.Context, prompt string, opts ...ai.ImageOption) (*ai.ImageResponse, error) {
+				ctx, req := startRequest(ctx, cfg, ins, "image_generation", ai.ApplyImageOptions(opts...).Model)
+				resp, err := next(ctx, prompt, opts...)
+				req.end(ctx, nil, "", err)
+				return resp, err
+			}
+		},
+	}
+}
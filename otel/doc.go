@@ -0,0 +1,38 @@
+// Package otel instruments gains with OpenTelemetry tracing and metrics,
+// following the OpenTelemetry semantic conventions for generative AI.
+//
+// # Client Requests
+//
+// [ClientMiddleware] creates a span for each chat, embedding, and image
+// request and records token usage, request duration, and cost metrics:
+//
+//	c := client.New(cfg, client.WithMiddleware(otel.ClientMiddleware()))
+//
+// Spans carry gen_ai.operation.name, gen_ai.provider.name,
+// gen_ai.request.model, gen_ai.usage.input_tokens, and
+// gen_ai.usage.output_tokens. The metrics are gen_ai.client.token.usage,
+// gen_ai.client.operation.duration, and gains.client.cost (USD, for models
+// with pricing). Requests that rely on the client's default model are
+// recorded under the model set with [WithDefaultModel].
+//
+// # Agent and Workflow Runs
+//
+// [TraceAgent] and [TraceWorkflow] wrap a streaming run. They start a span
+// for the run, pass its context to the run so client spans nest beneath it,
+// and turn the run's events into child spans for each step and tool
+// execution:
+//
+//	events := otel.TraceAgent(ctx, "researcher", func(ctx context.Context) <-chan event.Event {
+//	    return a.RunStream(ctx, messages)
+//	})
+//	for e := range events {
+//	    // e.TraceParent identifies the span active when e occurred
+//	}
+//
+// Every forwarded event has its TraceParent set to the W3C traceparent of
+// the innermost span open when it occurred, so event consumers such as
+// AG-UI servers or log sinks can correlate events with traces.
+//
+// By default the global tracer and meter providers are used; set others
+// with [WithTracerProvider] and [WithMeterProvider].
+package otel
@@ -0,0 +1,97 @@
+package otel
+
+import (
+	"context"
+
+	ai "github.com/spetersoncode/gains"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies this package to tracer and meter providers.
+const instrumentationName = "github.com/spetersoncode/gains/otel"
+
+// Attribute keys from the OpenTelemetry semantic conventions for generative
+// AI, plus gains-specific keys for workflow structure.
+const (
+	attrOperationName  = attribute.Key("gen_ai.operation.name")
+	attrProviderName   = attribute.Key("gen_ai.provider.name")
+	attrRequestModel   = attribute.Key("gen_ai.request.model")
+	attrMaxTokens      = attribute.Key("gen_ai.request.max_tokens")
+	attrTemperature    = attribute.Key("gen_ai.request.temperature")
+	attrInputTokens    = attribute.Key("gen_ai.usage.input_tokens")
+	attrOutputTokens   = attribute.Key("gen_ai.usage.output_tokens")
+	attrFinishReasons  = attribute.Key("gen_ai.response.finish_reasons")
+	attrTokenType      = attribute.Key("gen_ai.token.type")
+	attrAgentName      = attribute.Key("gen_ai.agent.name")
+	attrToolName       = attribute.Key("gen_ai.tool.name")
+	attrToolCallID     = attribute.Key("gen_ai.tool.call.id")
+	attrErrorType      = attribute.Key("error.type")
+	attrWorkflowName   = attribute.Key("gains.workflow.name")
+	attrStep           = attribute.Key("gains.step")
+	attrStepName       = attribute.Key("gains.step.name")
+	attrTerminationMsg = attribute.Key("gains.run.message")
+)
+
+// Option configures instrumentation.
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	defaultModel   ai.Model
+}
+
+// WithTracerProvider sets the tracer provider used to create spans.
+// Defaults to the global provider.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the meter provider used to record metrics.
+// Defaults to the global provider.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// WithDefaultModel names the model used by requests that do not set one
+// with ai.WithModel, normally the client's default chat model, so their
+// spans and metrics carry the model and their cost can be computed.
+func WithDefaultModel(m ai.Model) Option {
+	return func(c *config) {
+		c.defaultModel = m
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.tracerProvider == nil {
+		c.tracerProvider = otel.GetTracerProvider()
+	}
+	if c.meterProvider == nil {
+		c.meterProvider = otel.GetMeterProvider()
+	}
+	return c
+}
+
+func (c *config) tracer() trace.Tracer {
+	return c.tracerProvider.Tracer(instrumentationName)
+}
+
+// traceParent returns the W3C traceparent of the span in ctx, or "" if
+// there is none.
+func traceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
@@ -0,0 +1,186 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/client"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracerProvider() (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), recorder
+}
+
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+// finalChat wraps a ChatFunc that returns resp and err.
+func finalChat(resp *ai.Response, err error) client.ChatFunc {
+	return func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+		return resp, err
+	}
+}
+
+func TestClientMiddleware_Chat(t *testing.T) {
+	tp, recorder := newTracerProvider()
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	mw := ClientMiddleware(WithTracerProvider(tp), WithMeterProvider(mp))
+
+	chat := mw.Chat(finalChat(&ai.Response{
+		Content:      "Hi",
+		FinishReason: "stop",
+		Usage:        ai.Usage{InputTokens: 1000, OutputTokens: 200},
+	}, nil))
+	_, err := chat(context.Background(), nil, ai.WithModel(model.GPT5), ai.WithMaxTokens(50))
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "chat gpt-5", spans[0].Name())
+	v, _ := spanAttr(spans[0], attrProviderName)
+	assert.Equal(t, "openai", v.AsString())
+	v, _ = spanAttr(spans[0], attrInputTokens)
+	assert.Equal(t, int64(1000), v.AsInt64())
+	v, _ = spanAttr(spans[0], attrMaxTokens)
+	assert.Equal(t, int64(50), v.AsInt64())
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	names := map[string]bool{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			names[m.Name] = true
+			if m.Name == "gains.client.cost" {
+				sum := m.Data.(metricdata.Sum[float64])
+				assert.Greater(t, sum.DataPoints[0].Value, 0.0)
+			}
+		}
+	}
+	assert.True(t, names["gen_ai.client.token.usage"])
+	assert.True(t, names["gen_ai.client.operation.duration"])
+	assert.True(t, names["gains.client.cost"])
+}
+
+func TestClientMiddleware_Errors(t *testing.T) {
+	tp, recorder := newTracerProvider()
+	mw := ClientMiddleware(WithTracerProvider(tp), WithDefaultModel(model.ClaudeSonnet45))
+
+	_, err := mw.Chat(finalChat(nil, errors.New("rate limited")))(context.Background(), nil)
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "chat "+model.ClaudeSonnet45.String(), spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestClientMiddleware_ChatStream(t *testing.T) {
+	tp, recorder := newTracerProvider()
+	mw := ClientMiddleware(WithTracerProvider(tp))
+
+	stream := mw.ChatStream(func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+		ch := make(chan event.Event, 2)
+		ch <- event.Event{Type: event.MessageDelta, Delta: "Hi"}
+		ch <- event.Event{Type: event.MessageEnd, Response: &ai.Response{Usage: ai.Usage{InputTokens: 3, OutputTokens: 1}}}
+		close(ch)
+		return ch, nil
+	})
+	ch, err := stream(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, recorder.Ended(), "span ends with the stream")
+	for range ch {
+	}
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	v, _ := spanAttr(spans[0], attrOutputTokens)
+	assert.Equal(t, int64(1), v.AsInt64())
+}
+
+func TestTraceAgent(t *testing.T) {
+	tp, recorder := newTracerProvider()
+	call := ai.ToolCall{ID: "call-1", Name: "search"}
+
+	var runCtx context.Context
+	events := TraceAgent(context.Background(), "researcher", func(ctx context.Context) <-chan event.Event {
+		runCtx = ctx
+		ch := make(chan event.Event, 6)
+		ch <- event.Event{Type: event.RunStart}
+		ch <- event.Event{Type: event.StepStart, Step: 1}
+		ch <- event.Event{Type: event.ToolCallExecuting, Step: 1, ToolCall: &call}
+		ch <- event.Event{Type: event.ToolCallResult, Step: 1, ToolCall: &call, ToolResult: &ai.ToolResult{ToolCallID: "call-1", Content: "failed", IsError: true}}
+		ch <- event.Event{Type: event.StepEnd, Step: 1}
+		ch <- event.Event{Type: event.RunEnd}
+		close(ch)
+		return ch
+	}, WithTracerProvider(tp))
+
+	var parents []string
+	for e := range events {
+		require.NotEmpty(t, e.TraceParent)
+		parents = append(parents, e.TraceParent)
+	}
+	assert.NotNil(t, runCtx)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range spans {
+		byName[s.Name()] = s
+	}
+	run := byName["invoke_agent researcher"]
+	step := byName["step 1"]
+	tool := byName["execute_tool search"]
+	require.NotNil(t, run)
+	require.NotNil(t, step)
+	require.NotNil(t, tool)
+
+	assert.Equal(t, run.SpanContext().SpanID(), step.Parent().SpanID())
+	assert.Equal(t, step.SpanContext().SpanID(), tool.Parent().SpanID())
+	assert.Equal(t, codes.Error, tool.Status().Code)
+
+	// Events within the tool execution carry the tool span's context
+	assert.Contains(t, parents[2], tool.SpanContext().SpanID().String())
+	assert.Contains(t, parents[0], run.SpanContext().SpanID().String())
+}
+
+func TestTraceWorkflow_Error(t *testing.T) {
+	tp, recorder := newTracerProvider()
+	events := TraceWorkflow(context.Background(), "pipeline", func(ctx context.Context) <-chan event.Event {
+		ch := make(chan event.Event, 3)
+		ch <- event.Event{Type: event.StepStart, StepName: "fetch"}
+		ch <- event.Event{Type: event.RunError, Error: errors.New("boom")}
+		close(ch)
+		return ch
+	}, WithTracerProvider(tp))
+	for range events {
+	}
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2, "open step span is ended")
+	for _, s := range spans {
+		if s.Name() == "workflow pipeline" {
+			assert.Equal(t, codes.Error, s.Status().Code)
+		}
+	}
+}
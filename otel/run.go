@@ -0,0 +1,173 @@
+package otel
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/spetersoncode/gains/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RunFunc starts a streaming agent or workflow run with ctx.
+type RunFunc func(ctx context.Context) <-chan event.Event
+
+// TraceAgent traces an agent run started by run. The run's span is named
+// "invoke_agent {name}"; each agent step and tool execution gets a child
+// span. The returned channel forwards the run's events with TraceParent set
+// and must be drained.
+func TraceAgent(ctx context.Context, name string, run RunFunc, opts ...Option) <-chan event.Event {
+	return traceRun(ctx, newConfig(opts), "invoke_agent "+name, run,
+		attrOperationName.String("invoke_agent"),
+		attrAgentName.String(name),
+	)
+}
+
+// TraceWorkflow traces a workflow run started by run. The run's span is
+// named "workflow {name}"; each workflow step, and each tool execution of
+// agent steps, gets a child span. The returned channel forwards the run's
+// events with TraceParent set and must be drained.
+func TraceWorkflow(ctx context.Context, name string, run RunFunc, opts ...Option) <-chan event.Event {
+	return traceRun(ctx, newConfig(opts), "workflow "+name, run,
+		attrWorkflowName.String(name),
+	)
+}
+
+// traceRun starts the run span, starts the run beneath it, and converts its
+// events to child spans while forwarding them.
+func traceRun(ctx context.Context, cfg *config, spanName string, run RunFunc, attrs ...attribute.KeyValue) <-chan event.Event {
+	ctx, span := cfg.tracer().Start(ctx, spanName, trace.WithAttributes(attrs...))
+	ch := run(ctx)
+	out := make(chan event.Event, cap(ch))
+	go func() {
+		defer close(out)
+		t := &runTracer{
+			tracer: cfg.tracer(),
+			runCtx: ctx,
+			run:    span,
+			steps:  make(map[string]openSpan),
+			tools:  make(map[string]openSpan),
+		}
+		for e := range ch {
+			e.TraceParent = traceParent(t.observe(e))
+			out <- e
+		}
+		t.finish()
+	}()
+	return out
+}
+
+// openSpan is a span that has been started but not ended, with the context
+// holding it.
+type openSpan struct {
+	ctx  context.Context
+	span trace.Span
+}
+
+// runTracer turns the events of one run into spans.
+type runTracer struct {
+	tracer trace.Tracer
+	runCtx context.Context
+	run    trace.Span
+	steps  map[string]openSpan
+	tools  map[string]openSpan
+	err    error
+}
+
+// stepKey identifies the step an event belongs to: its workflow step name,
+// or its agent step number.
+func stepKey(e event.Event) string {
+	if e.StepName != "" {
+		return e.StepName
+	}
+	if e.Step > 0 {
+		return strconv.Itoa(e.Step)
+	}
+	return ""
+}
+
+// observe updates the spans for e and returns the context of the innermost
+// span open when e occurred.
+func (t *runTracer) observe(e event.Event) context.Context {
+	switch e.Type {
+	case event.StepStart:
+		key := stepKey(e)
+		ctx, span := t.tracer.Start(t.runCtx, "step "+key)
+		if e.StepName != "" {
+			span.SetAttributes(attrStepName.String(e.StepName))
+		} else {
+			span.SetAttributes(attrStep.Int(e.Step))
+		}
+		t.steps[key] = openSpan{ctx, span}
+		return ctx
+
+	case event.StepEnd:
+		key := stepKey(e)
+		if s, ok := t.steps[key]; ok {
+			delete(t.steps, key)
+			s.span.End()
+			return s.ctx
+		}
+
+	case event.ToolCallExecuting:
+		if e.ToolCall == nil {
+			break
+		}
+		parent := t.runCtx
+		if s, ok := t.steps[stepKey(e)]; ok {
+			parent = s.ctx
+		}
+		ctx, span := t.tracer.Start(parent, "execute_tool "+e.ToolCall.Name,
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithAttributes(
+				attrOperationName.String("execute_tool"),
+				attrToolName.String(e.ToolCall.Name),
+				attrToolCallID.String(e.ToolCall.ID),
+			))
+		t.tools[e.ToolCall.ID] = openSpan{ctx, span}
+		return ctx
+
+	case event.ToolCallResult:
+		if e.ToolResult == nil {
+			break
+		}
+		if s, ok := t.tools[e.ToolResult.ToolCallID]; ok {
+			delete(t.tools, e.ToolResult.ToolCallID)
+			if e.ToolResult.IsError {
+				s.span.SetStatus(codes.Error, e.ToolResult.Content)
+			}
+			s.span.End()
+			return s.ctx
+		}
+
+	case event.RunError:
+		t.err = e.Error
+
+	case event.RunEnd:
+		if e.Message != "" {
+			t.run.SetAttributes(attrTerminationMsg.String(e.Message))
+		}
+	}
+
+	if s, ok := t.steps[stepKey(e)]; ok {
+		return s.ctx
+	}
+	return t.runCtx
+}
+
+// finish ends any spans left open by a run that stopped early, then the
+// run span, marking it failed if the run ended with an error.
+func (t *runTracer) finish() {
+	for _, s := range t.tools {
+		s.span.End()
+	}
+	for _, s := range t.steps {
+		s.span.End()
+	}
+	if t.err != nil {
+		t.run.RecordError(t.err)
+		t.run.SetStatus(codes.Error, t.err.Error())
+	}
+	t.run.End()
+}
@@ -0,0 +1,91 @@
+package redact
+
+import (
+	"cmp"
+	"encoding/json"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Target names the kind of data a Policy redacted.
+type Target string
+
+// Targets of audit entries.
+const (
+	TargetText       Target = "text"
+	TargetJSON       Target = "json"
+	TargetMessages   Target = "messages"
+	TargetTranscript Target = "transcript"
+	TargetEvent      Target = "event"
+	TargetStore      Target = "store"
+)
+
+// Rule names the kind of policy rule that redacted a value.
+type Rule string
+
+// Policy rules.
+const (
+	RuleField   Rule = "field"
+	RuleRole    Rule = "role"
+	RuleTool    Rule = "tool"
+	RulePattern Rule = "pattern"
+)
+
+// AuditEntry records the redactions one rule made in one value. It never
+// holds the redacted data.
+type AuditEntry struct {
+	Time   time.Time `json:"time"`
+	Target Target    `json:"target"`
+	// Key identifies the value: the store key for TargetStore and the
+	// event type for TargetEvent.
+	Key  string `json:"key,omitempty"`
+	Rule Rule   `json:"rule"`
+	// Match is what the rule matched: the field pattern, role, tool name,
+	// or pattern kind.
+	Match string `json:"match"`
+	// Count is the number of values the rule redacted.
+	Count int `json:"count"`
+}
+
+// AuditFunc receives audit entries. It may be called concurrently.
+type AuditFunc func(AuditEntry)
+
+// JSONLinesAudit returns an AuditFunc that writes each entry to w as one
+// JSON object per line. Writes are serialized; write errors are ignored.
+func JSONLinesAudit(w io.Writer) AuditFunc {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	return func(e AuditEntry) {
+		mu.Lock()
+		defer mu.Unlock()
+		enc.Encode(e)
+	}
+}
+
+// end reports the redactions of the pass to the audit log, ordered by rule
+// and match.
+func (ps *pass) end() {
+	if ps.audit == nil || len(ps.counts) == 0 {
+		return
+	}
+	matches := make([]ruleMatch, 0, len(ps.counts))
+	for m := range ps.counts {
+		matches = append(matches, m)
+	}
+	slices.SortFunc(matches, func(a, b ruleMatch) int {
+		return cmp.Or(cmp.Compare(a.rule, b.rule), cmp.Compare(a.match, b.match))
+	})
+	now := time.Now()
+	for _, m := range matches {
+		ps.audit(AuditEntry{
+			Time:   now,
+			Target: ps.target,
+			Key:    ps.key,
+			Rule:   m.rule,
+			Match:  m.match,
+			Count:  ps.counts[m],
+		})
+	}
+}
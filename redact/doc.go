@@ -0,0 +1,83 @@
+// Package redact keeps personal data and secrets out of model requests by
+// replacing them with reversible placeholders.
+//
+// A [Redactor] detects email addresses, phone numbers, US Social Security
+// numbers, and API keys, and replaces each distinct value with a stable
+// placeholder such as "[EMAIL_1]". It remembers the originals, so
+// placeholders the model repeats back can be restored:
+//
+//	r := redact.New()
+//	masked := r.Redact("Mail jane@example.com") // "Mail [EMAIL_1]"
+//	r.Restore(masked)                           // "Mail jane@example.com"
+//
+// # Client Middleware
+//
+// [Middleware] redacts every outgoing request, including earlier turns and
+// tool results, and restores the placeholders in responses, so callers see
+// the original values while providers never receive them. A client is
+// usually shared by many conversations, so give each conversation its own
+// Redactor on the context of its runs with [WithRedactor]:
+//
+//	c := client.New(cfg, client.WithMiddleware(redact.Middleware(nil)))
+//	// per conversation
+//	ctx = redact.WithRedactor(ctx, redact.New())
+//	result, err := a.Run(ctx, messages)
+//
+// A Redactor passed to Middleware is used by requests whose context has
+// none. Either way, a response only has the placeholders of its own
+// request restored, so a model tricked into repeating another
+// conversation's placeholder cannot reveal its value.
+//
+// # Tools
+//
+// [WrapRegistry] wraps tool handlers with the context's Redactor, or the
+// one it is given: arguments are restored before a handler runs, so it
+// works with real values, and its result is redacted before it reaches the
+// agent's history, events, and the model:
+//
+//	agent.New(c, redact.WrapRegistry(registry, nil))
+//
+// Use one Redactor for the requests and tools of a conversation so
+// placeholders stay consistent across turns. A Redactor holds every value
+// it has replaced in memory; create one per conversation rather than per
+// process.
+//
+// # Custom Patterns
+//
+// Add detectors for other data with WithPattern, or restrict detection to
+// some kinds with WithKinds:
+//
+//	r := redact.New(
+//	    redact.WithKinds(redact.KindEmail, redact.KindAPIKey),
+//	    redact.WithPattern("ACCOUNT", regexp.MustCompile(`\bACC-\d{8}\b`)),
+//	)
+//
+// Detection is pattern based and errs toward catching common formats; it
+// is a safeguard, not a guarantee that no personal data is sent.
+//
+// # Retention Policies
+//
+// A [Policy] removes data for good before it is retained, to meet
+// data-retention and compliance requirements. Configure it once with
+// field names, message roles, tool names, and text patterns:
+//
+//	policy := redact.NewPolicy(
+//	    redact.WithFields("password", "*_token", "ssn"),
+//	    redact.WithRoles(gains.RoleSystem),
+//	    redact.WithTools("lookup_customer"),
+//	    redact.WithTextPatterns(redact.DefaultPatterns()...),
+//	    redact.WithAudit(redact.JSONLinesAudit(auditFile)),
+//	)
+//
+// Apply it to persisted stores, event recorders, and exported
+// transcripts:
+//
+//	sessions := session.NewManager(policy.WrapAdapter(adapter))
+//	sink := policy.Sink(event.JSONLinesSink(eventFile))
+//	transcript.HTML(w, messages, transcript.WithMessageFilter(policy.RedactTranscript))
+//
+// Removed values are replaced with [Mask], and text pattern matches with
+// their kind, as in "[EMAIL]". Each rule that redacts something is
+// reported to the audit log as an [AuditEntry] naming the target, the
+// rule, and how many values it removed, never the values themselves.
+package redact
@@ -0,0 +1,128 @@
+package redact
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+)
+
+// Sink returns an event.Sink that applies p to each event before passing
+// it to next, so event recorders such as event.JSONLinesSink keep only
+// what the policy allows:
+//
+//	sink := policy.Sink(event.JSONLinesSink(f))
+func (p *Policy) Sink(next event.Sink) event.Sink {
+	return func(e event.Event) {
+		next(p.RedactEvent(e))
+	}
+}
+
+// RedactEvent returns a copy of e with the policy applied. Message deltas
+// and responses are treated as assistant messages, and tool arguments and
+// results follow the tool rules by the event's tool call. State, patches,
+// and activity content are redacted as JSON, becoming json.RawMessage
+// values when they change.
+func (p *Policy) RedactEvent(e event.Event) event.Event {
+	ps := p.begin(TargetEvent, string(e.Type))
+	defer ps.end()
+
+	var toolRedacted bool
+	if e.ToolCall != nil {
+		call := ps.toolCalls([]ai.ToolCall{*e.ToolCall})[0]
+		e.ToolCall = &call
+		_, toolRedacted = ps.calls[call.ID]
+	}
+	switch {
+	case e.Delta == "":
+	case toolRedacted:
+		e.Delta = Mask
+	case e.Type == event.MessageDelta && ps.role(ai.RoleAssistant):
+		e.Delta = Mask
+	default:
+		e.Delta = ps.text(e.Delta)
+	}
+	if e.ToolResult != nil {
+		result := *e.ToolResult
+		result.Content = ps.toolResult(result.ToolCallID, result.Content)
+		e.ToolResult = &result
+	}
+	if e.Response != nil {
+		e.Response = ps.response(e.Response)
+	}
+	e.Message = ps.text(e.Message)
+	if e.Error != nil {
+		if msg := ps.text(e.Error.Error()); msg != e.Error.Error() {
+			e.Error = &redactedError{msg: msg, err: e.Error}
+		}
+	}
+	e.PendingToolCalls = ps.toolCalls(e.PendingToolCalls)
+	e.Messages = ps.messages(e.Messages)
+	e.State = ps.any(e.State)
+	e.ActivityContent = ps.any(e.ActivityContent)
+	e.StatePatches = ps.patches(e.StatePatches)
+	e.ActivityPatches = ps.patches(e.ActivityPatches)
+	return e
+}
+
+// response returns a copy of resp with the policy applied, as an assistant
+// message.
+func (ps *pass) response(resp *ai.Response) *ai.Response {
+	out := *resp
+	msg := ps.messages([]ai.Message{{Role: ai.RoleAssistant, Content: resp.Content, ToolCalls: resp.ToolCalls}})[0]
+	out.Content, out.ToolCalls = msg.Content, msg.ToolCalls
+	if len(resp.Candidates) > 0 {
+		out.Candidates = slices.Clone(resp.Candidates)
+		for i, c := range out.Candidates {
+			msg := ps.messages([]ai.Message{{Role: ai.RoleAssistant, Content: c.Content, ToolCalls: c.ToolCalls}})[0]
+			out.Candidates[i].Content, out.Candidates[i].ToolCalls = msg.Content, msg.ToolCalls
+		}
+	}
+	return &out
+}
+
+// any returns v with the policy applied through its JSON encoding, or v
+// unchanged if nothing was redacted.
+func (ps *pass) any(v any) any {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	redacted := ps.json(data)
+	if string(redacted) == string(data) {
+		return v
+	}
+	return json.RawMessage(redacted)
+}
+
+// patches returns a copy of patches with the policy applied to their
+// values. A patch whose path ends in a redacted field has its value
+// removed.
+func (ps *pass) patches(patches []event.JSONPatch) []event.JSONPatch {
+	if len(patches) == 0 {
+		return patches
+	}
+	out := slices.Clone(patches)
+	for i, patch := range out {
+		if patch.Value == nil {
+			continue
+		}
+		if pattern, ok := ps.field(lastSegment(patch.Path)); ok {
+			ps.count(RuleField, pattern)
+			out[i].Value = Mask
+			continue
+		}
+		out[i].Value = ps.any(patch.Value)
+	}
+	return out
+}
+
+// lastSegment returns the last segment of a JSON Pointer.
+func lastSegment(pointer string) string {
+	return pointer[strings.LastIndex(pointer, "/")+1:]
+}
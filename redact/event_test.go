@@ -0,0 +1,87 @@
+package redact
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_RedactEvent(t *testing.T) {
+	p := NewPolicy(
+		WithTools("lookup_customer"),
+		WithFields("ssn"),
+		WithTextPatterns(DefaultPatterns()...),
+	)
+
+	t.Run("tool result", func(t *testing.T) {
+		e := p.RedactEvent(event.Event{
+			Type:       event.ToolCallResult,
+			ToolCall:   &ai.ToolCall{ID: "1", Name: "lookup_customer", Arguments: `{"id":7}`},
+			ToolResult: &ai.ToolResult{ToolCallID: "1", Content: "Jane Doe"},
+		})
+		assert.Equal(t, Mask, e.ToolCall.Arguments)
+		assert.Equal(t, Mask, e.ToolResult.Content)
+	})
+
+	t.Run("delta and response", func(t *testing.T) {
+		e := p.RedactEvent(event.Event{Type: event.MessageDelta, Delta: "mail jane@example.com"})
+		assert.Equal(t, "mail [EMAIL]", e.Delta)
+
+		resp := &ai.Response{Content: "mail jane@example.com"}
+		e = p.RedactEvent(event.Event{Type: event.MessageEnd, Response: resp})
+		assert.Equal(t, "mail [EMAIL]", e.Response.Content)
+		assert.Equal(t, "mail jane@example.com", resp.Content, "input unchanged")
+	})
+
+	t.Run("state", func(t *testing.T) {
+		e := p.RedactEvent(event.Event{
+			Type:  event.StateSnapshot,
+			State: map[string]any{"name": "Jane", "ssn": "123-45-6789"},
+		})
+		raw, ok := e.State.(json.RawMessage)
+		require.True(t, ok)
+		assert.JSONEq(t, `{"name":"Jane","ssn":"[REDACTED]"}`, string(raw))
+
+		e = p.RedactEvent(event.Event{
+			Type:         event.StateDelta,
+			StatePatches: []event.JSONPatch{{Op: event.PatchReplace, Path: "/customer/ssn", Value: "123-45-6789"}},
+		})
+		assert.Equal(t, Mask, e.StatePatches[0].Value)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cause := errors.New("no account for jane@example.com")
+		e := p.RedactEvent(event.Event{Type: event.RunError, Error: cause})
+		assert.Equal(t, "no account for [EMAIL]", e.Error.Error())
+		assert.ErrorIs(t, e.Error, cause)
+	})
+}
+
+func TestPolicy_RedactEventAssistantRole(t *testing.T) {
+	p := NewPolicy(WithRoles(ai.RoleAssistant))
+	e := p.RedactEvent(event.Event{Type: event.MessageDelta, Delta: "Hello"})
+	assert.Equal(t, Mask, e.Delta)
+}
+
+func TestPolicy_Sink(t *testing.T) {
+	var got []event.Event
+	var entries []AuditEntry
+	p := NewPolicy(
+		WithTextPatterns(DefaultPatterns()...),
+		WithAudit(func(e AuditEntry) { entries = append(entries, e) }),
+	)
+	sink := p.Sink(func(e event.Event) { got = append(got, e) })
+
+	sink(event.Event{Type: event.MessageDelta, Delta: "call 555-123-4567"})
+
+	require.Len(t, got, 1)
+	assert.Equal(t, "call [PHONE]", got[0].Delta)
+	require.Len(t, entries, 1)
+	assert.Equal(t, TargetEvent, entries[0].Target)
+	assert.Equal(t, string(event.MessageDelta), entries[0].Key)
+}
@@ -0,0 +1,177 @@
+package redact
+
+import (
+	"context"
+	"strings"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/client"
+	"github.com/spetersoncode/gains/event"
+)
+
+// redactorKeyType is the context key for the Redactor of a request.
+type redactorKeyType struct{}
+
+// WithRedactor returns a context whose model requests and tool calls are
+// redacted with r in place of the Redactor given to Middleware or
+// WrapRegistry. Pass it to each conversation's runs, so one client serves
+// many conversations without their values mixing.
+func WithRedactor(ctx context.Context, r *Redactor) context.Context {
+	return context.WithValue(ctx, redactorKeyType{}, r)
+}
+
+// FromContext returns the Redactor set with WithRedactor, or nil.
+func FromContext(ctx context.Context) *Redactor {
+	r, _ := ctx.Value(redactorKeyType{}).(*Redactor)
+	return r
+}
+
+// resolve returns the Redactor of ctx, or fallback if it has none.
+func resolve(ctx context.Context, fallback *Redactor) *Redactor {
+	if r := FromContext(ctx); r != nil {
+		return r
+	}
+	return fallback
+}
+
+// Middleware returns client middleware that redacts the messages of every
+// chat request, the texts of embedding requests, and image prompts, and
+// restores placeholders in chat responses. Streamed message deltas are
+// restored as they arrive; text that may be the start of a placeholder is
+// held back until the placeholder completes.
+//
+// Each request uses the Redactor set on its context with WithRedactor, or
+// r if it has none; with neither, requests pass through unchanged. Only
+// the placeholders of values found in the request itself are restored in
+// its response, so a response echoing a placeholder of another
+// conversation sharing the Redactor does not reveal that conversation's
+// value.
+func Middleware(r *Redactor) client.Middleware {
+	return client.Middleware{
+		Chat: func(next client.ChatFunc) client.ChatFunc {
+			return func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+				q := newRequest(resolve(ctx, r))
+				if q == nil {
+					return next(ctx, messages, opts...)
+				}
+				resp, err := next(ctx, redactMessages(messages, q.redact), opts...)
+				return restoreResponse(resp, q.restore), err
+			}
+		},
+		ChatStream: func(next client.ChatStreamFunc) client.ChatStreamFunc {
+			return func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+				q := newRequest(resolve(ctx, r))
+				if q == nil {
+					return next(ctx, messages, opts...)
+				}
+				ch, err := next(ctx, redactMessages(messages, q.redact), opts...)
+				if err != nil {
+					return nil, err
+				}
+				return restoreStream(ch, q.restore), nil
+			}
+		},
+		Embed: func(next client.EmbedFunc) client.EmbedFunc {
+			return func(ctx context.Context, texts []string, opts ...ai.EmbeddingOption) (*ai.EmbeddingResponse, error) {
+				r := resolve(ctx, r)
+				if r == nil {
+					return next(ctx, texts, opts...)
+				}
+				redacted := make([]string, len(texts))
+				for i, text := range texts {
+					redacted[i] = r.Redact(text)
+				}
+				return next(ctx, redacted, opts...)
+			}
+		},
+		Image: func(next client.ImageFunc) client.ImageFunc {
+			return func(ctx context.Context, prompt string, opts ...ai.ImageOption) (*ai.ImageResponse, error) {
+				if r := resolve(ctx, r); r != nil {
+					prompt = r.Redact(prompt)
+				}
+				return next(ctx, prompt, opts...)
+			}
+		},
+	}
+}
+
+// request redacts one model request and restores its response, knowing
+// only the placeholders that stand for values of the request.
+type request struct {
+	r    *Redactor
+	seen map[string]string // placeholder to value
+}
+
+// newRequest returns a request redacted by r, or nil if r is nil.
+func newRequest(r *Redactor) *request {
+	if r == nil {
+		return nil
+	}
+	return &request{r: r, seen: make(map[string]string)}
+}
+
+func (q *request) redact(s string) string {
+	return q.r.redact(s, q.seen)
+}
+
+func (q *request) restore(s string) string {
+	return restore(s, func(holder string) (string, bool) {
+		value, ok := q.seen[holder]
+		return value, ok
+	})
+}
+
+// restoreStream forwards the events of ch with placeholders restored by
+// restore in message deltas and final responses.
+func restoreStream(ch <-chan event.Event, restore func(string) string) <-chan event.Event {
+	out := make(chan event.Event, cap(ch))
+	go func() {
+		defer close(out)
+		var pending strings.Builder
+		var last event.Event // most recent delta, a template for flushing
+		flush := func() {
+			if pending.Len() == 0 {
+				return
+			}
+			e := last
+			e.Delta = restore(pending.String())
+			pending.Reset()
+			out <- e
+		}
+
+		for e := range ch {
+			switch e.Type {
+			case event.MessageDelta:
+				pending.WriteString(e.Delta)
+				last = e
+				text := pending.String()
+				ready, held := splitPlaceholder(text)
+				if ready == "" {
+					continue
+				}
+				pending.Reset()
+				pending.WriteString(held)
+				e.Delta = restore(ready)
+			case event.MessageEnd:
+				flush()
+				e.Response = restoreResponse(e.Response, restore)
+			case event.RunError:
+				flush()
+			}
+			out <- e
+		}
+		flush()
+	}()
+	return out
+}
+
+// splitPlaceholder splits text before a trailing "[" that may begin a
+// placeholder still being streamed, returning the text that is safe to
+// emit and the text to hold back.
+func splitPlaceholder(text string) (ready, held string) {
+	i := strings.LastIndexByte(text, '[')
+	if i < 0 || strings.IndexByte(text[i:], ']') >= 0 || len(text)-i > maxPlaceholderLen {
+		return text, ""
+	}
+	return text[:i], text[i:]
+}
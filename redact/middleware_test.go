@@ -0,0 +1,128 @@
+package redact
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware_Chat(t *testing.T) {
+	r := New()
+	var sent []ai.Message
+	chat := Middleware(r).Chat(func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+		sent = messages
+		return &ai.Response{Content: "I emailed [EMAIL_1]."}, nil
+	})
+
+	resp, err := chat(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Email jane@example.com"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Email [EMAIL_1]", sent[0].Content)
+	assert.Equal(t, "I emailed jane@example.com.", resp.Content)
+}
+
+func TestMiddleware_ChatStream(t *testing.T) {
+	r := New()
+
+	deltas := []string{"Sent to [EM", "AIL", "_1] and [", "EMAIL_1]", " ok ["}
+	stream := Middleware(r).ChatStream(func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+		ch := make(chan event.Event, 10)
+		for _, d := range deltas {
+			ch <- event.Event{Type: event.MessageDelta, MessageID: "m", Delta: d}
+		}
+		ch <- event.Event{Type: event.MessageEnd, MessageID: "m", Response: &ai.Response{Content: strings.Join(deltas, "")}}
+		close(ch)
+		return ch, nil
+	})
+
+	ch, err := stream(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Email jane@example.com"}})
+	require.NoError(t, err)
+	var text strings.Builder
+	var final *ai.Response
+	for e := range ch {
+		switch e.Type {
+		case event.MessageDelta:
+			assert.Equal(t, "m", e.MessageID)
+			text.WriteString(e.Delta)
+		case event.MessageEnd:
+			final = e.Response
+		}
+	}
+	want := "Sent to jane@example.com and jane@example.com ok ["
+	assert.Equal(t, want, text.String())
+	require.NotNil(t, final)
+	assert.Equal(t, want, final.Content)
+}
+
+func TestMiddleware_OtherRequestsPlaceholders(t *testing.T) {
+	r := New()
+	chat := Middleware(r).Chat(func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+		return &ai.Response{Content: "Contact [EMAIL_1] or [PHONE_1]."}, nil
+	})
+
+	// A's request redacts A's email, which B's response then echoes
+	_, err := chat(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "I am jane@example.com"}})
+	require.NoError(t, err)
+	resp, err := chat(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Call 555-123-4567"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Contact [EMAIL_1] or 555-123-4567.", resp.Content, "only the request's own placeholders are restored")
+}
+
+func TestMiddleware_WithRedactor(t *testing.T) {
+	shared := New()
+	var sent []ai.Message
+	chat := Middleware(shared).Chat(func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+		sent = messages
+		return &ai.Response{Content: messages[0].Content}, nil
+	})
+
+	conversation := New()
+	ctx := WithRedactor(context.Background(), conversation)
+	resp, err := chat(ctx, []ai.Message{{Role: ai.RoleUser, Content: "jane@example.com"}})
+	require.NoError(t, err)
+	assert.Equal(t, "[EMAIL_1]", sent[0].Content)
+	assert.Equal(t, "jane@example.com", resp.Content)
+	assert.Equal(t, 1, conversation.Len())
+	assert.Zero(t, shared.Len(), "the context's Redactor replaces the middleware's")
+
+	// Without either, requests pass through
+	chat = Middleware(nil).Chat(func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+		sent = messages
+		return &ai.Response{}, nil
+	})
+	_, err = chat(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "jane@example.com"}})
+	require.NoError(t, err)
+	assert.Equal(t, "jane@example.com", sent[0].Content)
+}
+
+func TestWrapRegistry(t *testing.T) {
+	r := New()
+	var received string
+	registry := tool.NewRegistry().Add(
+		tool.WithHandler("lookup", "Look up a customer", nil, func(ctx context.Context, call ai.ToolCall) (string, error) {
+			received = call.Arguments
+			if strings.Contains(call.Arguments, "missing") {
+				return "", errors.New("no customer missing@example.com")
+			}
+			return "Phone: 555-123-4567", nil
+		}),
+	)
+	wrapped := WrapRegistry(registry, r)
+	email := r.Redact("jane@example.com")
+
+	result, err := wrapped.Execute(context.Background(), ai.ToolCall{ID: "1", Name: "lookup", Arguments: `{"email":"` + email + `"}`})
+	require.NoError(t, err)
+	assert.Equal(t, `{"email":"jane@example.com"}`, received)
+	assert.Equal(t, "Phone: [PHONE_1]", result.Content)
+
+	result, err = wrapped.Execute(context.Background(), ai.ToolCall{ID: "2", Name: "lookup", Arguments: `{"email":"missing"}`})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "no customer [EMAIL_2]", result.Content)
+}
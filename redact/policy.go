@@ -0,0 +1,392 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"slices"
+	"strings"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// Mask replaces values removed by a Policy: fields, messages of redacted
+// roles, and the arguments and results of redacted tools. Values matched
+// by a text pattern are replaced with their kind in brackets, as in
+// "[EMAIL]".
+const Mask = "[REDACTED]"
+
+// PolicyOption configures a Policy.
+type PolicyOption func(*Policy)
+
+// WithFields removes the values of JSON object fields whose names match
+// one of the patterns, wherever they appear: in stored values, tool
+// arguments and results, and event state. Patterns use path.Match syntax
+// and match case-insensitively, as in "password" or "*_token".
+func WithFields(patterns ...string) PolicyOption {
+	return func(p *Policy) {
+		for _, pattern := range patterns {
+			p.fields = append(p.fields, strings.ToLower(pattern))
+		}
+	}
+}
+
+// WithRoles removes the content of messages with the given roles, such as
+// ai.RoleSystem to keep prompts out of retained logs.
+func WithRoles(roles ...ai.Role) PolicyOption {
+	return func(p *Policy) {
+		p.roles = append(p.roles, roles...)
+	}
+}
+
+// WithTools removes the arguments and results of calls to the named tools.
+func WithTools(names ...string) PolicyOption {
+	return func(p *Policy) {
+		p.tools = append(p.tools, names...)
+	}
+}
+
+// WithTextPatterns replaces the values matching the patterns in all text
+// the policy keeps, such as DefaultPatterns for email addresses, phone
+// numbers, and keys. Unlike a Redactor, a Policy's replacements are not
+// reversible.
+func WithTextPatterns(patterns ...Pattern) PolicyOption {
+	return func(p *Policy) {
+		p.patterns = append(p.patterns, patterns...)
+	}
+}
+
+// WithAudit calls fn with an entry for each rule that redacted something.
+// See JSONLinesAudit.
+func WithAudit(fn AuditFunc) PolicyOption {
+	return func(p *Policy) {
+		p.audit = fn
+	}
+}
+
+// Policy is a central set of redaction rules applied to data before it is
+// retained: persisted stores (WrapAdapter), event recorders (Sink), and
+// exported transcripts (RedactTranscript). Where a Redactor hides data
+// from providers for the length of a conversation, a Policy removes it for
+// good, to meet data-retention and compliance requirements, and reports
+// what it removed to an audit log. It is safe for concurrent use.
+type Policy struct {
+	fields   []string
+	roles    []ai.Role
+	tools    []string
+	patterns []Pattern
+	audit    AuditFunc
+}
+
+// NewPolicy returns a Policy with the given rules. A Policy without rules
+// changes nothing.
+func NewPolicy(opts ...PolicyOption) *Policy {
+	p := &Policy{}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// RedactText returns s with the values matching the policy's text
+// patterns replaced.
+func (p *Policy) RedactText(s string) string {
+	ps := p.begin(TargetText, "")
+	defer ps.end()
+	return ps.text(s)
+}
+
+// RedactJSON returns a copy of the JSON document data with the policy
+// applied: fields are removed, objects shaped like messages, tool calls,
+// and tool results follow the role and tool rules, and string values are
+// matched against the text patterns, including JSON encoded in strings.
+// Tool results are matched to calls by ID within the same document. Data
+// that is not JSON is treated as text.
+//
+// RedactJSON has the signature of client.Redactor, so a policy can also
+// redact recorded provider transcripts:
+//
+//	client.WithTranscript(sink, client.WithRedactor(policy.RedactJSON))
+func (p *Policy) RedactJSON(data []byte) []byte {
+	ps := p.begin(TargetJSON, "")
+	defer ps.end()
+	return ps.json(data)
+}
+
+// RedactMessages returns a copy of messages with the policy applied.
+func (p *Policy) RedactMessages(messages []ai.Message) []ai.Message {
+	ps := p.begin(TargetMessages, "")
+	defer ps.end()
+	return ps.messages(messages)
+}
+
+// RedactTranscript returns a copy of messages with the policy applied,
+// audited as an exported transcript. Pass it to
+// transcript.WithMessageFilter.
+func (p *Policy) RedactTranscript(messages []ai.Message) []ai.Message {
+	ps := p.begin(TargetTranscript, "")
+	defer ps.end()
+	return ps.messages(messages)
+}
+
+// field reports the pattern matching the field name, if any.
+func (p *Policy) field(name string) (string, bool) {
+	name = strings.ToLower(name)
+	for _, pattern := range p.fields {
+		if ok, err := path.Match(pattern, name); ok || (err != nil && pattern == name) {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// pass applies a policy to one value and tallies the redactions for the
+// audit log.
+type pass struct {
+	*Policy
+	target Target
+	key    string
+	counts map[ruleMatch]int
+	calls  map[string]string // redacted tool call IDs to tool names
+}
+
+// ruleMatch identifies what a rule matched.
+type ruleMatch struct {
+	rule  Rule
+	match string
+}
+
+func (p *Policy) begin(target Target, key string) *pass {
+	return &pass{Policy: p, target: target, key: key}
+}
+
+// count records a redaction by rule.
+func (ps *pass) count(rule Rule, match string) {
+	if ps.audit == nil {
+		return
+	}
+	if ps.counts == nil {
+		ps.counts = make(map[ruleMatch]int)
+	}
+	ps.counts[ruleMatch{rule, match}]++
+}
+
+// text replaces the values matching the text patterns in s.
+func (ps *pass) text(s string) string {
+	if s == "" {
+		return s
+	}
+	for _, pattern := range ps.patterns {
+		s = pattern.Regexp.ReplaceAllStringFunc(s, func(string) string {
+			ps.count(RulePattern, string(pattern.Kind))
+			return "[" + string(pattern.Kind) + "]"
+		})
+	}
+	return s
+}
+
+// role reports whether the content of messages of role is removed.
+func (ps *pass) role(role ai.Role) bool {
+	if slices.Contains(ps.roles, role) {
+		ps.count(RuleRole, string(role))
+		return true
+	}
+	return false
+}
+
+// tool reports whether the arguments of a call to name with id are
+// removed, remembering id so its result is removed too.
+func (ps *pass) tool(id, name string) bool {
+	if !slices.Contains(ps.tools, name) {
+		return false
+	}
+	if ps.calls == nil {
+		ps.calls = make(map[string]string)
+	}
+	ps.calls[id] = name
+	ps.count(RuleTool, name)
+	return true
+}
+
+// result reports whether the result of tool call id is removed.
+func (ps *pass) result(id string) bool {
+	name, ok := ps.calls[id]
+	if ok {
+		ps.count(RuleTool, name)
+	}
+	return ok
+}
+
+// messages returns a copy of messages with the policy applied.
+func (ps *pass) messages(messages []ai.Message) []ai.Message {
+	if messages == nil {
+		return nil
+	}
+	out := make([]ai.Message, len(messages))
+	for i, m := range messages {
+		if ps.role(m.Role) {
+			m.Content = Mask
+			m.Parts = nil
+		} else {
+			m.Content = ps.text(m.Content)
+			if len(m.Parts) > 0 {
+				m.Parts = slices.Clone(m.Parts)
+				for j := range m.Parts {
+					m.Parts[j].Text = ps.text(m.Parts[j].Text)
+				}
+			}
+		}
+		m.ToolCalls = ps.toolCalls(m.ToolCalls)
+		if len(m.ToolResults) > 0 {
+			m.ToolResults = slices.Clone(m.ToolResults)
+			for j, r := range m.ToolResults {
+				m.ToolResults[j].Content = ps.toolResult(r.ToolCallID, r.Content)
+			}
+		}
+		out[i] = m
+	}
+	return out
+}
+
+// toolCalls returns a copy of calls with the policy applied to their
+// arguments.
+func (ps *pass) toolCalls(calls []ai.ToolCall) []ai.ToolCall {
+	if len(calls) == 0 {
+		return calls
+	}
+	out := slices.Clone(calls)
+	for i, call := range out {
+		if ps.tool(call.ID, call.Name) {
+			out[i].Arguments = Mask
+		} else {
+			out[i].Arguments = string(ps.json([]byte(call.Arguments)))
+		}
+	}
+	return out
+}
+
+// toolResult returns the content of the result of tool call id with the
+// policy applied.
+func (ps *pass) toolResult(id, content string) string {
+	if ps.result(id) {
+		return Mask
+	}
+	return string(ps.json([]byte(content)))
+}
+
+// json returns a copy of the JSON document data with the policy applied,
+// or data unchanged if nothing was redacted.
+func (ps *pass) json(data []byte) []byte {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[' && trimmed[0] != '"') {
+		return []byte(ps.text(string(data)))
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return []byte(ps.text(string(data)))
+	}
+	ps.collect(v)
+	redacted, changed := ps.value(v)
+	if !changed {
+		return data
+	}
+	out, err := json.Marshal(redacted)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+// collect remembers the IDs of calls to redacted tools in v, so their
+// results are redacted wherever they appear in the document.
+func (ps *pass) collect(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		id, _ := v["id"].(string)
+		name, _ := v["name"].(string)
+		if _, ok := v["arguments"]; ok && slices.Contains(ps.tools, name) {
+			if ps.calls == nil {
+				ps.calls = make(map[string]string)
+			}
+			ps.calls[id] = name
+		}
+		for _, child := range v {
+			ps.collect(child)
+		}
+	case []any:
+		for _, child := range v {
+			ps.collect(child)
+		}
+	}
+}
+
+// value returns v with the policy applied and whether it changed.
+func (ps *pass) value(v any) (any, bool) {
+	switch v := v.(type) {
+	case string:
+		s := v
+		if t := strings.TrimSpace(s); strings.HasPrefix(t, "{") || strings.HasPrefix(t, "[") {
+			s = string(ps.json([]byte(s)))
+		} else {
+			s = ps.text(s)
+		}
+		return s, s != v
+	case []any:
+		changed := false
+		for i, child := range v {
+			redacted, ok := ps.value(child)
+			v[i] = redacted
+			changed = changed || ok
+		}
+		return v, changed
+	case map[string]any:
+		return v, ps.object(v)
+	}
+	return v, false
+}
+
+// object applies the policy to the object v in place and reports whether
+// it changed.
+func (ps *pass) object(v map[string]any) bool {
+	changed := false
+	if role, ok := v["role"].(string); ok && ps.role(ai.Role(role)) {
+		v["content"] = Mask
+		delete(v, "parts")
+		changed = true
+	}
+	if name, ok := v["name"].(string); ok {
+		if _, args := v["arguments"]; args && slices.Contains(ps.tools, name) {
+			ps.count(RuleTool, name)
+			v["arguments"] = Mask
+			changed = true
+		}
+	}
+	if id, ok := v["toolCallId"].(string); ok {
+		if _, content := v["content"]; content && ps.result(id) {
+			v["content"] = Mask
+			changed = true
+		}
+	}
+	keys := make([]string, 0, len(v))
+	for key := range v {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if v[key] == Mask {
+			continue
+		}
+		if pattern, ok := ps.field(key); ok {
+			ps.count(RuleField, pattern)
+			v[key] = Mask
+			changed = true
+			continue
+		}
+		redacted, ok := ps.value(v[key])
+		v[key] = redacted
+		changed = changed || ok
+	}
+	return changed
+}
@@ -0,0 +1,147 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_RedactText(t *testing.T) {
+	p := NewPolicy(WithTextPatterns(DefaultPatterns()...))
+	assert.Equal(t, "Mail [EMAIL] or [EMAIL]", p.RedactText("Mail jane@example.com or bob@example.com"))
+
+	assert.Equal(t, "jane@example.com", NewPolicy().RedactText("jane@example.com"))
+}
+
+func TestPolicy_RedactJSON(t *testing.T) {
+	p := NewPolicy(
+		WithFields("password", "*_token"),
+		WithTextPatterns(DefaultPatterns()...),
+	)
+
+	t.Run("fields and patterns", func(t *testing.T) {
+		got := p.RedactJSON([]byte(`{"user":{"Password":"hunter2","email":"jane@example.com","age":42},"refresh_token":"abc"}`))
+		assert.JSONEq(t, `{"user":{"Password":"[REDACTED]","email":"[EMAIL]","age":42},"refresh_token":"[REDACTED]"}`, string(got))
+	})
+
+	t.Run("json in strings", func(t *testing.T) {
+		got := p.RedactJSON([]byte(`{"arguments":"{\"password\":\"hunter2\"}"}`))
+		var v struct{ Arguments string }
+		require.NoError(t, json.Unmarshal(got, &v))
+		assert.JSONEq(t, `{"password":"[REDACTED]"}`, v.Arguments)
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		data := []byte(`{"b": 1, "a": "plain"}`)
+		assert.Equal(t, data, p.RedactJSON(data))
+	})
+
+	t.Run("not json", func(t *testing.T) {
+		assert.Equal(t, "hi [EMAIL]", string(p.RedactJSON([]byte("hi jane@example.com"))))
+	})
+}
+
+func TestPolicy_RedactMessages(t *testing.T) {
+	p := NewPolicy(
+		WithRoles(ai.RoleSystem),
+		WithTools("lookup_customer"),
+		WithFields("card"),
+		WithTextPatterns(DefaultPatterns()...),
+	)
+	messages := []ai.Message{
+		{Role: ai.RoleSystem, Content: "Internal instructions"},
+		{Role: ai.RoleUser, Content: "I'm jane@example.com"},
+		{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{
+			{ID: "1", Name: "lookup_customer", Arguments: `{"email":"jane@example.com"}`},
+			{ID: "2", Name: "charge", Arguments: `{"card":"4111","amount":5}`},
+		}},
+		{Role: ai.RoleTool, ToolResults: []ai.ToolResult{
+			{ToolCallID: "1", Content: "Jane Doe, 1 Main St"},
+			{ToolCallID: "2", Content: "charged"},
+		}},
+	}
+
+	got := p.RedactMessages(messages)
+
+	assert.Equal(t, Mask, got[0].Content)
+	assert.Equal(t, "I'm [EMAIL]", got[1].Content)
+	assert.Equal(t, Mask, got[2].ToolCalls[0].Arguments)
+	assert.JSONEq(t, `{"card":"[REDACTED]","amount":5}`, got[2].ToolCalls[1].Arguments)
+	assert.Equal(t, Mask, got[3].ToolResults[0].Content)
+	assert.Equal(t, "charged", got[3].ToolResults[1].Content)
+
+	assert.Equal(t, "Internal instructions", messages[0].Content, "input unchanged")
+	assert.Equal(t, `{"email":"jane@example.com"}`, messages[2].ToolCalls[0].Arguments)
+}
+
+func TestPolicy_RedactJSONMessages(t *testing.T) {
+	p := NewPolicy(WithRoles(ai.RoleSystem), WithTools("lookup_customer"))
+	data, err := json.Marshal([]ai.Message{
+		{Role: ai.RoleSystem, Content: "Internal instructions"},
+		{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{ID: "1", Name: "lookup_customer", Arguments: `{}`}}},
+		{Role: ai.RoleTool, ToolResults: []ai.ToolResult{{ToolCallID: "1", Content: "Jane Doe"}}},
+	})
+	require.NoError(t, err)
+
+	var got []ai.Message
+	require.NoError(t, json.Unmarshal(p.RedactJSON(data), &got))
+
+	assert.Equal(t, Mask, got[0].Content)
+	assert.Equal(t, Mask, got[1].ToolCalls[0].Arguments)
+	assert.Equal(t, Mask, got[2].ToolResults[0].Content)
+}
+
+func TestPolicy_Audit(t *testing.T) {
+	var entries []AuditEntry
+	p := NewPolicy(
+		WithFields("password"),
+		WithRoles(ai.RoleSystem),
+		WithTextPatterns(DefaultPatterns()...),
+		WithAudit(func(e AuditEntry) { entries = append(entries, e) }),
+	)
+
+	p.RedactTranscript([]ai.Message{
+		{Role: ai.RoleSystem, Content: "secret"},
+		{Role: ai.RoleUser, Content: "a@example.com and b@example.com"},
+		{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{ID: "1", Name: "login", Arguments: `{"password":"x"}`}}},
+	})
+
+	require.Len(t, entries, 3)
+	assert.Equal(t, RuleField, entries[0].Rule)
+	assert.Equal(t, "password", entries[0].Match)
+	assert.Equal(t, RulePattern, entries[1].Rule)
+	assert.Equal(t, "EMAIL", entries[1].Match)
+	assert.Equal(t, 2, entries[1].Count)
+	assert.Equal(t, RuleRole, entries[2].Rule)
+	assert.Equal(t, "system", entries[2].Match)
+	for _, e := range entries {
+		assert.Equal(t, TargetTranscript, e.Target)
+		assert.False(t, e.Time.IsZero())
+	}
+
+	entries = nil
+	p.RedactText("nothing to see")
+	assert.Empty(t, entries)
+}
+
+func TestJSONLinesAudit(t *testing.T) {
+	var b bytes.Buffer
+	p := NewPolicy(WithFields("password"), WithAudit(JSONLinesAudit(&b)))
+
+	p.RedactJSON([]byte(`{"password":"x"}`))
+	p.RedactJSON([]byte(`{"password":"y"}`))
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	require.Len(t, lines, 2)
+	var e AuditEntry
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &e))
+	assert.Equal(t, TargetJSON, e.Target)
+	assert.Equal(t, RuleField, e.Rule)
+	assert.Equal(t, 1, e.Count)
+	assert.NotContains(t, b.String(), `"x"`)
+}
@@ -0,0 +1,245 @@
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// Kind names a category of redacted data. It forms the placeholder prefix,
+// as in "[EMAIL_1]", so custom kinds should be upper case letters, digits,
+// and underscores.
+type Kind string
+
+// Built-in kinds.
+const (
+	KindEmail  Kind = "EMAIL"
+	KindPhone  Kind = "PHONE"
+	KindSSN    Kind = "SSN"
+	KindAPIKey Kind = "API_KEY"
+)
+
+// Pattern detects one kind of data.
+type Pattern struct {
+	Kind   Kind
+	Regexp *regexp.Regexp
+}
+
+// DefaultPatterns returns the built-in detectors, in the order they run.
+// API keys run first so digits inside keys are not mistaken for phone
+// numbers.
+func DefaultPatterns() []Pattern {
+	return []Pattern{
+		{KindAPIKey, apiKeyPattern},
+		{KindEmail, emailPattern},
+		{KindSSN, ssnPattern},
+		{KindPhone, phonePattern},
+	}
+}
+
+var (
+	// apiKeyPattern matches the key formats of common AI and cloud
+	// providers: OpenAI and Anthropic, Google, AWS access keys, GitHub
+	// tokens, and Slack tokens.
+	apiKeyPattern = regexp.MustCompile(`\b(?:sk-(?:ant-|proj-)?[A-Za-z0-9_-]{20,}|AIza[0-9A-Za-z_-]{35}|AKIA[0-9A-Z]{16}|gh[pousr]_[A-Za-z0-9]{36,}|xox[abprs]-[A-Za-z0-9-]{10,})`)
+	emailPattern  = regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)
+	ssnPattern    = regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+	// phonePattern matches North American numbers with optional country
+	// code and international numbers starting with "+".
+	phonePattern = regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{3}\)\s?|\b\d{3}[\s.-])\d{3}[\s.-]\d{4}\b|\+\d{1,3}(?:[\s.-]?\d{2,4}){3,5}\b`)
+
+	// placeholderPattern matches any placeholder a Redactor produces.
+	placeholderPattern = regexp.MustCompile(`\[[A-Z][A-Z0-9_]*_\d+\]`)
+)
+
+// maxPlaceholderLen bounds how much streamed text is held back while a
+// placeholder may be incomplete.
+const maxPlaceholderLen = 48
+
+// Option configures a Redactor.
+type Option func(*Redactor)
+
+// WithKinds limits detection to the given kinds. Patterns added with
+// WithPattern are unaffected.
+func WithKinds(kinds ...Kind) Option {
+	return func(r *Redactor) {
+		r.patterns = slices.DeleteFunc(r.patterns, func(p Pattern) bool {
+			return !slices.Contains(kinds, p.Kind)
+		})
+	}
+}
+
+// WithPattern adds a detector for kind. Added patterns run after the
+// built-in ones, in the order they are added.
+func WithPattern(kind Kind, re *regexp.Regexp) Option {
+	return func(r *Redactor) {
+		r.patterns = append(r.patterns, Pattern{Kind: kind, Regexp: re})
+	}
+}
+
+// Redactor replaces sensitive values with placeholders and restores them.
+// Each distinct value gets one placeholder for the Redactor's lifetime, so
+// the model can tell values apart and refer to them consistently. It is
+// safe for concurrent use.
+type Redactor struct {
+	patterns []Pattern
+
+	mu       sync.Mutex
+	byValue  map[string]string
+	byHolder map[string]string
+	counts   map[Kind]int
+}
+
+// New returns a Redactor using DefaultPatterns.
+func New(opts ...Option) *Redactor {
+	r := &Redactor{
+		patterns: DefaultPatterns(),
+		byValue:  make(map[string]string),
+		byHolder: make(map[string]string),
+		counts:   make(map[Kind]int),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Redact replaces the sensitive values in s with placeholders.
+func (r *Redactor) Redact(s string) string {
+	return r.redact(s, nil)
+}
+
+// redact replaces the sensitive values in s with placeholders, recording
+// each placeholder used and its value in seen if it is non-nil.
+func (r *Redactor) redact(s string, seen map[string]string) string {
+	if s == "" {
+		return s
+	}
+	for _, p := range r.patterns {
+		s = p.Regexp.ReplaceAllStringFunc(s, func(value string) string {
+			holder := r.placeholder(p.Kind, value)
+			if seen != nil {
+				seen[holder] = value
+			}
+			return holder
+		})
+	}
+	return s
+}
+
+// placeholder returns the placeholder of value, assigning the next one of
+// kind if value has none.
+func (r *Redactor) placeholder(kind Kind, value string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if holder, ok := r.byValue[value]; ok {
+		return holder
+	}
+	r.counts[kind]++
+	holder := fmt.Sprintf("[%s_%d]", kind, r.counts[kind])
+	r.byValue[value] = holder
+	r.byHolder[holder] = value
+	return holder
+}
+
+// Restore replaces the placeholders in s with the values they stand for.
+// Placeholders this Redactor did not produce are left unchanged.
+func (r *Redactor) Restore(s string) string {
+	return restore(s, func(holder string) (string, bool) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		value, ok := r.byHolder[holder]
+		return value, ok
+	})
+}
+
+// restore replaces the placeholders in s that lookup knows with their
+// values.
+func restore(s string, lookup func(holder string) (string, bool)) string {
+	if !strings.Contains(s, "[") {
+		return s
+	}
+	return placeholderPattern.ReplaceAllStringFunc(s, func(holder string) string {
+		if value, ok := lookup(holder); ok {
+			return value
+		}
+		return holder
+	})
+}
+
+// Len returns the number of distinct values redacted so far.
+func (r *Redactor) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.byValue)
+}
+
+// RedactMessages returns a copy of messages with the text content, tool
+// call arguments, and tool results of each redacted. Images and other
+// binary parts are unchanged.
+func (r *Redactor) RedactMessages(messages []ai.Message) []ai.Message {
+	return redactMessages(messages, r.Redact)
+}
+
+// redactMessages is RedactMessages with redact applied to each text.
+func redactMessages(messages []ai.Message, redact func(string) string) []ai.Message {
+	out := make([]ai.Message, len(messages))
+	for i, m := range messages {
+		m.Content = redact(m.Content)
+		if len(m.Parts) > 0 {
+			m.Parts = slices.Clone(m.Parts)
+			for j := range m.Parts {
+				m.Parts[j].Text = redact(m.Parts[j].Text)
+			}
+		}
+		m.ToolCalls = mapToolCalls(m.ToolCalls, redact)
+		if len(m.ToolResults) > 0 {
+			m.ToolResults = slices.Clone(m.ToolResults)
+			for j := range m.ToolResults {
+				m.ToolResults[j].Content = redact(m.ToolResults[j].Content)
+			}
+		}
+		out[i] = m
+	}
+	return out
+}
+
+// RestoreResponse returns a copy of resp with the placeholders in the
+// content and tool call arguments of it and its candidates restored.
+func (r *Redactor) RestoreResponse(resp *ai.Response) *ai.Response {
+	return restoreResponse(resp, r.Restore)
+}
+
+// restoreResponse is RestoreResponse with restore applied to each text.
+func restoreResponse(resp *ai.Response, restore func(string) string) *ai.Response {
+	if resp == nil {
+		return nil
+	}
+	restored := *resp
+	restored.Content = restore(resp.Content)
+	restored.ToolCalls = mapToolCalls(resp.ToolCalls, restore)
+	if len(resp.Candidates) > 0 {
+		restored.Candidates = slices.Clone(resp.Candidates)
+		for i, c := range restored.Candidates {
+			restored.Candidates[i].Content = restore(c.Content)
+			restored.Candidates[i].ToolCalls = mapToolCalls(c.ToolCalls, restore)
+		}
+	}
+	return &restored
+}
+
+// mapToolCalls returns a copy of calls with fn applied to their arguments.
+func mapToolCalls(calls []ai.ToolCall, fn func(string) string) []ai.ToolCall {
+	if len(calls) == 0 {
+		return calls
+	}
+	out := slices.Clone(calls)
+	for i := range out {
+		out[i].Arguments = fn(out[i].Arguments)
+	}
+	return out
+}
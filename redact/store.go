@@ -0,0 +1,85 @@
+package redact
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/spetersoncode/gains/internal/store"
+)
+
+// WrapAdapter returns an adapter that applies p to every value before it
+// is written to a, as with RedactJSON, so sessions, agent event logs, and
+// workflow checkpoints, artifacts, and run history are persisted without
+// the data the policy removes. Reads return what was stored. The adapter
+// keeps a's revision checks and locks, if it has them.
+//
+// Redacting checkpoints or event logs changes the state a resumed run
+// sees; redact only fields a resumed run can do without.
+func (p *Policy) WrapAdapter(a store.Adapter) store.Adapter {
+	w := &adapter{Adapter: a, policy: p}
+	rs, revisions := a.(store.RevisionSaver)
+	locker, locks := a.(store.Locker)
+	switch {
+	case revisions && locks:
+		return struct {
+			*adapter
+			revisionSaver
+			store.Locker
+		}{w, revisionSaver{w, rs}, locker}
+	case revisions:
+		return struct {
+			*adapter
+			revisionSaver
+		}{w, revisionSaver{w, rs}}
+	case locks:
+		return struct {
+			*adapter
+			store.Locker
+		}{w, locker}
+	}
+	return w
+}
+
+// adapter is a store.Adapter that redacts values before writing them.
+type adapter struct {
+	store.Adapter
+	policy *Policy
+}
+
+// Set stores value under key with the policy applied.
+func (a *adapter) Set(ctx context.Context, key string, value json.RawMessage) error {
+	return a.Adapter.Set(ctx, key, a.redact(key, value))
+}
+
+// Save stores data with the policy applied to each value.
+func (a *adapter) Save(ctx context.Context, data map[string]json.RawMessage) error {
+	return a.Adapter.Save(ctx, a.redactAll(data))
+}
+
+// redact returns value, stored under key, with the policy applied.
+func (a *adapter) redact(key string, value json.RawMessage) json.RawMessage {
+	ps := a.policy.begin(TargetStore, key)
+	defer ps.end()
+	return ps.json(value)
+}
+
+// redactAll returns a copy of data with the policy applied to each value.
+func (a *adapter) redactAll(data map[string]json.RawMessage) map[string]json.RawMessage {
+	out := make(map[string]json.RawMessage, len(data))
+	for key, value := range data {
+		out[key] = a.redact(key, value)
+	}
+	return out
+}
+
+// revisionSaver redacts values saved through a store.RevisionSaver.
+type revisionSaver struct {
+	adapter *adapter
+	saver   store.RevisionSaver
+}
+
+// SaveRevision saves data with the policy applied to each value, if the
+// stored revision is expected.
+func (r revisionSaver) SaveRevision(ctx context.Context, data map[string]json.RawMessage, expected int64) error {
+	return r.saver.SaveRevision(ctx, r.adapter.redactAll(data), expected)
+}
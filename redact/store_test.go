@@ -0,0 +1,60 @@
+package redact
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/internal/store"
+	"github.com/spetersoncode/gains/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_WrapAdapter(t *testing.T) {
+	ctx := context.Background()
+	var entries []AuditEntry
+	p := NewPolicy(
+		WithFields("password"),
+		WithAudit(func(e AuditEntry) { entries = append(entries, e) }),
+	)
+	base := store.NewMemoryAdapter()
+	a := p.WrapAdapter(base)
+
+	require.NoError(t, a.Set(ctx, "user", json.RawMessage(`{"name":"jane","password":"x"}`)))
+	got, ok, err := base.Get(ctx, "user")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.JSONEq(t, `{"name":"jane","password":"[REDACTED]"}`, string(got))
+
+	require.NoError(t, a.Save(ctx, map[string]json.RawMessage{"a": json.RawMessage(`{"password":"y"}`)}))
+	got, _, _ = base.Get(ctx, "a")
+	assert.JSONEq(t, `{"password":"[REDACTED]"}`, string(got))
+
+	require.Len(t, entries, 2)
+	assert.Equal(t, TargetStore, entries[0].Target)
+	assert.Equal(t, "user", entries[0].Key)
+
+	_, revisions := a.(store.RevisionSaver)
+	_, locks := a.(store.Locker)
+	assert.True(t, revisions)
+	assert.True(t, locks)
+}
+
+func TestPolicy_WrapAdapterSession(t *testing.T) {
+	ctx := context.Background()
+	p := NewPolicy(WithTextPatterns(DefaultPatterns()...))
+	base := session.NewMemoryAdapter()
+	sessions := session.NewManager(p.WrapAdapter(base))
+
+	sess, err := sessions.Create(ctx)
+	require.NoError(t, err)
+	require.NoError(t, sess.Append(ctx, ai.Message{Role: ai.RoleUser, Content: "I'm jane@example.com"}))
+
+	resumed, err := session.NewManager(base).Resume(ctx, sess.ID())
+	require.NoError(t, err)
+	messages := resumed.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "I'm [EMAIL]", messages[0].Content)
+}
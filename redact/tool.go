@@ -0,0 +1,40 @@
+package redact
+
+import (
+	"context"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/tool"
+)
+
+// WrapRegistry returns a copy of registry whose handlers run with their
+// arguments restored and have their results, including error messages,
+// redacted, by the Redactor set on the call's context with WithRedactor,
+// or r if it has none. With neither, handlers run unchanged. See
+// tool.Registry.Wrap.
+func WrapRegistry(registry *tool.Registry, r *Redactor) *tool.Registry {
+	return registry.Wrap(func(_ ai.Tool, next tool.Handler) tool.Handler {
+		return func(ctx context.Context, call ai.ToolCall) (string, error) {
+			r := resolve(ctx, r)
+			if r == nil {
+				return next(ctx, call)
+			}
+			call.Arguments = r.Restore(call.Arguments)
+			content, err := next(ctx, call)
+			if err != nil {
+				return "", &redactedError{msg: r.Redact(err.Error()), err: err}
+			}
+			return r.Redact(content), nil
+		}
+	})
+}
+
+// redactedError is a tool error whose message is redacted. The original
+// error remains available to errors.Is and errors.As.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
@@ -0,0 +1,44 @@
+// Package runtime coordinates the shutdown of processes that serve agent
+// and workflow runs.
+//
+// Servers and workers register each run as it starts, and on shutdown
+// call [Drain], which stops new runs from starting, waits for the
+// in-flight ones to finish, and then flushes event recorders:
+//
+//	ctx, done, err := runtime.Begin(r.Context())
+//	if err != nil {
+//	    return err // runtime.ErrDraining: shutting down
+//	}
+//	defer done()
+//	result, err := a.Run(ctx, msgs)
+//
+// HTTP servers can wrap their run endpoints with [Handler], which answers
+// 503 Service Unavailable while draining.
+//
+// # Deadlines
+//
+// Drain waits until its context is done. Runs still going then have their
+// contexts cancelled with the cause [ErrDrainTimeout], and Drain waits a
+// short grace period for them to return. A workflow with a checkpointer
+// has saved its completed steps by then, so it can be resumed by run ID
+// after a restart instead of starting over:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	if err := runtime.Drain(ctx); err != nil {
+//	    slog.Warn("drain incomplete", "error", err)
+//	}
+//	server.Shutdown(ctx)
+//
+// # Flushing
+//
+// Functions registered with [OnDrain] run once the runs have stopped, so
+// buffered event recorders, transcripts, and traces are written before the
+// process exits:
+//
+//	trace := bufio.NewWriter(f)
+//	runtime.OnDrain(func(ctx context.Context) error { return trace.Flush() })
+//
+// The package-level functions use a process-wide [Coordinator]; create one
+// with [New] to drain a part of a process on its own.
+package runtime
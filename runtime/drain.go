@@ -0,0 +1,230 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrDraining is returned by Begin once draining has started.
+var ErrDraining = errors.New("runtime: draining")
+
+// ErrDrainTimeout is the cancellation cause of runs still in flight when
+// the context passed to Drain is done.
+var ErrDrainTimeout = errors.New("runtime: drain deadline exceeded")
+
+// DefaultGracePeriod is how long Drain waits for cancelled runs to return,
+// and for flush functions to finish, after its context is done.
+const DefaultGracePeriod = 5 * time.Second
+
+// Coordinator tracks in-flight runs so they can be drained on shutdown.
+// It is safe for concurrent use.
+type Coordinator struct {
+	grace time.Duration
+
+	mu       sync.Mutex
+	draining bool
+	runs     map[*inflight]struct{}
+	idle     chan struct{} // closed when draining and no runs are left
+	flushers []func(context.Context) error
+}
+
+// inflight is one run registered with Begin.
+type inflight struct {
+	cancel context.CancelCauseFunc
+}
+
+// Option configures a Coordinator.
+type Option func(*Coordinator)
+
+// WithGracePeriod sets how long Drain waits for cancelled runs and flush
+// functions after its context is done. The default is DefaultGracePeriod.
+func WithGracePeriod(d time.Duration) Option {
+	return func(c *Coordinator) {
+		c.grace = d
+	}
+}
+
+// New creates a Coordinator.
+func New(opts ...Option) *Coordinator {
+	c := &Coordinator{
+		grace: DefaultGracePeriod,
+		runs:  make(map[*inflight]struct{}),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Begin registers a run. The run should use the returned context, which
+// Drain cancels if its deadline passes, and call done when it returns.
+// Once draining has started Begin returns ErrDraining.
+func (c *Coordinator) Begin(ctx context.Context) (runCtx context.Context, done func(), err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.draining {
+		return ctx, func() {}, ErrDraining
+	}
+	runCtx, cancel := context.WithCancelCause(ctx)
+	run := &inflight{cancel: cancel}
+	c.runs[run] = struct{}{}
+
+	var once sync.Once
+	done = func() {
+		once.Do(func() {
+			c.end(run)
+			cancel(nil)
+		})
+	}
+	return runCtx, done, nil
+}
+
+// end unregisters a run.
+func (c *Coordinator) end(run *inflight) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.runs, run)
+	if c.draining && len(c.runs) == 0 {
+		c.closeIdle()
+	}
+}
+
+// closeIdle closes the idle channel if it is still open. c.mu must be held.
+func (c *Coordinator) closeIdle() {
+	select {
+	case <-c.idle:
+	default:
+		close(c.idle)
+	}
+}
+
+// OnDrain registers fn to be called by Drain after the runs have stopped,
+// in registration order.
+func (c *Coordinator) OnDrain(fn func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.flushers = append(c.flushers, fn)
+}
+
+// Draining reports whether Drain has been called.
+func (c *Coordinator) Draining() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.draining
+}
+
+// Active returns the number of runs in flight.
+func (c *Coordinator) Active() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.runs)
+}
+
+// Drain stops new runs from starting and waits for the in-flight ones to
+// return. If ctx is done first, the remaining runs are cancelled with
+// ErrDrainTimeout and given the grace period to return. Drain then calls
+// the OnDrain functions and returns their errors joined with any error
+// from waiting. Drain may be called more than once; each call flushes.
+func (c *Coordinator) Drain(ctx context.Context) error {
+	c.mu.Lock()
+	if !c.draining {
+		c.draining = true
+		c.idle = make(chan struct{})
+		if len(c.runs) == 0 {
+			c.closeIdle()
+		}
+	}
+	idle := c.idle
+	c.mu.Unlock()
+
+	var errs []error
+	select {
+	case <-idle:
+	case <-ctx.Done():
+		c.cancelRuns()
+		select {
+		case <-idle:
+			errs = append(errs, fmt.Errorf("runtime: drain: runs cancelled: %w", ctx.Err()))
+		case <-time.After(c.grace):
+			errs = append(errs, fmt.Errorf("runtime: drain: %d runs did not stop: %w", c.Active(), ctx.Err()))
+		}
+	}
+
+	flushCtx := ctx
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		flushCtx, cancel = context.WithTimeout(context.WithoutCancel(ctx), c.grace)
+		defer cancel()
+	}
+	c.mu.Lock()
+	flushers := append([]func(context.Context) error(nil), c.flushers...)
+	c.mu.Unlock()
+	for _, fn := range flushers {
+		if err := fn(flushCtx); err != nil {
+			errs = append(errs, fmt.Errorf("runtime: flush: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// cancelRuns cancels every in-flight run with ErrDrainTimeout.
+func (c *Coordinator) cancelRuns() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for run := range c.runs {
+		run.cancel(ErrDrainTimeout)
+	}
+}
+
+// Handler wraps next so that each request is a run: its context is
+// cancelled if the drain deadline passes, and while draining new requests
+// are refused with 503 Service Unavailable.
+func (c *Coordinator) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, done, err := c.Begin(r.Context())
+		if err != nil {
+			w.Header().Set("Connection", "close")
+			http.Error(w, "Server is shutting down", http.StatusServiceUnavailable)
+			return
+		}
+		defer done()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// std is the process-wide Coordinator used by the package-level functions.
+var std = New()
+
+// Begin registers a run with the process-wide Coordinator.
+// See Coordinator.Begin.
+func Begin(ctx context.Context) (context.Context, func(), error) {
+	return std.Begin(ctx)
+}
+
+// OnDrain registers a flush function with the process-wide Coordinator.
+// See Coordinator.OnDrain.
+func OnDrain(fn func(ctx context.Context) error) {
+	std.OnDrain(fn)
+}
+
+// Draining reports whether the process is draining.
+func Draining() bool {
+	return std.Draining()
+}
+
+// Drain drains the process-wide Coordinator. See Coordinator.Drain.
+func Drain(ctx context.Context) error {
+	return std.Drain(ctx)
+}
+
+// Handler wraps next with the process-wide Coordinator.
+// See Coordinator.Handler.
+func Handler(next http.Handler) http.Handler {
+	return std.Handler(next)
+}
@@ -0,0 +1,106 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrainWaitsForRuns(t *testing.T) {
+	c := New()
+	_, done, err := c.Begin(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, c.Active())
+
+	var flushed []string
+	c.OnDrain(func(ctx context.Context) error {
+		flushed = append(flushed, "events")
+		return nil
+	})
+
+	drained := make(chan error, 1)
+	go func() { drained <- c.Drain(context.Background()) }()
+
+	require.Eventually(t, c.Draining, time.Second, time.Millisecond)
+	_, _, err = c.Begin(context.Background())
+	assert.ErrorIs(t, err, ErrDraining)
+
+	select {
+	case <-drained:
+		t.Fatal("Drain returned with a run in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+	assert.Empty(t, flushed)
+
+	done()
+	done() // idempotent
+	require.NoError(t, <-drained)
+	assert.Equal(t, []string{"events"}, flushed)
+	assert.Zero(t, c.Active())
+}
+
+func TestDrainDeadlineCancelsRuns(t *testing.T) {
+	c := New(WithGracePeriod(time.Second))
+	runCtx, done, err := c.Begin(context.Background())
+	require.NoError(t, err)
+	go func() {
+		<-runCtx.Done() // a run that checkpoints and stops when cancelled
+		done()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = c.Drain(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, context.Cause(runCtx), ErrDrainTimeout)
+}
+
+func TestDrainGivesUpOnStuckRuns(t *testing.T) {
+	c := New(WithGracePeriod(10 * time.Millisecond))
+	_, _, err := c.Begin(context.Background())
+	require.NoError(t, err)
+
+	var flushCtxErr error
+	c.OnDrain(func(ctx context.Context) error {
+		flushCtxErr = ctx.Err()
+		return errors.New("disk full")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = c.Drain(ctx)
+	assert.ErrorContains(t, err, "1 runs did not stop")
+	assert.ErrorContains(t, err, "disk full")
+	assert.NoError(t, flushCtxErr, "flush gets its own grace period")
+}
+
+func TestHandler(t *testing.T) {
+	c := New()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	h := c.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+
+	inflight := httptest.NewRecorder()
+	go h.ServeHTTP(inflight, httptest.NewRequest(http.MethodPost, "/", nil))
+	<-started
+
+	drained := make(chan error, 1)
+	go func() { drained <- c.Drain(context.Background()) }()
+	require.Eventually(t, c.Draining, time.Second, time.Millisecond)
+
+	refused := httptest.NewRecorder()
+	h.ServeHTTP(refused, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, refused.Code)
+
+	close(release)
+	require.NoError(t, <-drained)
+}
@@ -6,6 +6,8 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // SchemaFor generates a JSON schema from a struct type T.
@@ -24,6 +26,21 @@ import (
 //   - default:"value"  - Default value
 //   - minItems:"1"     - Minimum array items
 //   - maxItems:"10"    - Maximum array items
+//   - oneof:"a,b"      - Variants registered with RegisterVariant
+//
+// Field types map to schemas as follows:
+//   - Structs become nested objects. Embedded structs without a json tag
+//     have their fields promoted, as with encoding/json.
+//   - Slices and arrays become arrays of their element's schema.
+//   - map[string]T becomes an object whose additionalProperties is T's schema.
+//   - Pointers are optional: they are never required, whatever their tags.
+//   - time.Time becomes a string with the date-time format.
+//   - Fields tagged oneof, including slices of an interface type, accept any
+//     of the named variants, expressed with anyOf.
+//   - A struct type nested within itself, such as a tree node, is described
+//     once under $defs and referenced with $ref. The top-level type refers
+//     to itself with "#". Gemini, which lacks $ref, receives the schema
+//     flattened to a few levels of nesting.
 //
 // Example:
 //
@@ -46,7 +63,11 @@ func SchemaFor[T any]() (json.RawMessage, error) {
 		return nil, fmt.Errorf("SchemaFor: type %T is not a struct", zero)
 	}
 
-	schema := buildObjectSchema(t)
+	b := newSchemaBuilder(t)
+	schema := b.buildObjectSchema(t)
+	if len(b.defs) > 0 {
+		schema["$defs"] = b.defs
+	}
 	return json.Marshal(schema)
 }
 
@@ -59,16 +80,145 @@ func MustSchemaFor[T any]() json.RawMessage {
 	return schema
 }
 
+// variants maps the names used in oneof tags to their types.
+var variants sync.Map
+
+// RegisterVariant registers the type of v under name, so fields tagged
+// oneof:"name" accept it. Register the concrete types an interface field can
+// hold, typically from an init function next to the types:
+//
+//	type Shape interface{ Area() float64 }
+//
+//	type Circle struct {
+//	    Kind   string  `json:"kind" enum:"circle" required:"true"`
+//	    Radius float64 `json:"radius" required:"true"`
+//	}
+//
+//	func init() {
+//	    gains.RegisterVariant("circle", Circle{})
+//	    gains.RegisterVariant("square", Square{})
+//	}
+//
+//	type Args struct {
+//	    Shapes []Shape `json:"shapes" oneof:"circle,square"`
+//	}
+//
+// A discriminating field such as Kind above lets the model and the decoder
+// tell variants apart. Registering a name again replaces its type.
+func RegisterVariant(name string, v any) {
+	variants.Store(name, reflect.TypeOf(v))
+}
+
 // schemaMap represents a JSON schema as a map for easy construction.
 type schemaMap map[string]any
 
-// buildObjectSchema creates a JSON schema for a struct type.
-func buildObjectSchema(t reflect.Type) schemaMap {
+// timeType is the type of time.Time, described as a date-time string.
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaBuilder builds the schema of a type, tracking the struct types being
+// built to turn recursive references into $refs.
+type schemaBuilder struct {
+	root     reflect.Type
+	building map[reflect.Type]bool
+	defs     map[string]schemaMap    // definitions of recursive types
+	names    map[reflect.Type]string // definition names of recursive types
+	taken    map[string]reflect.Type // types by definition name
+}
+
+// newSchemaBuilder creates a schema builder for the top-level type root.
+func newSchemaBuilder(root reflect.Type) *schemaBuilder {
+	return &schemaBuilder{
+		root:     root,
+		building: make(map[reflect.Type]bool),
+		defs:     make(map[string]schemaMap),
+		names:    make(map[reflect.Type]string),
+		taken:    make(map[string]reflect.Type),
+	}
+}
+
+// buildObjectSchema creates a JSON schema for a struct type. A type that
+// occurs within itself is moved to the definitions and replaced with a
+// reference.
+func (b *schemaBuilder) buildObjectSchema(t reflect.Type) schemaMap {
+	if b.building[t] {
+		return b.ref(t)
+	}
+	if name, ok := b.names[t]; ok && b.defs[name] != nil {
+		return b.ref(t)
+	}
+
+	b.building[t] = true
+	schema := b.buildStructSchema(t)
+	delete(b.building, t)
+
+	if _, recursive := b.names[t]; recursive && t != b.root {
+		b.defs[b.names[t]] = schema
+		return b.ref(t)
+	}
+	return schema
+}
+
+// ref returns a reference to the definition of t, naming it on first use.
+// The top-level type is the whole schema, referenced as "#".
+func (b *schemaBuilder) ref(t reflect.Type) schemaMap {
+	if t == b.root {
+		return schemaMap{"$ref": "#"}
+	}
+	name, ok := b.names[t]
+	if !ok {
+		base := t.Name()
+		if base == "" {
+			base = "Object"
+		}
+		name = base
+		for i := 2; b.taken[name] != nil; i++ {
+			name = base + strconv.Itoa(i)
+		}
+		b.names[t] = name
+		b.taken[name] = t
+	}
+	return schemaMap{"$ref": "#/$defs/" + name}
+}
+
+// buildStructSchema creates the object schema of a struct type's fields.
+func (b *schemaBuilder) buildStructSchema(t reflect.Type) schemaMap {
 	properties := make(map[string]schemaMap)
 	var required []string
+	b.addFields(t, properties, &required)
+
+	schema := schemaMap{
+		"type":       "object",
+		"properties": properties,
+	}
+
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+// addFields adds the properties of struct type t to properties, and the
+// names of its required properties to required. Fields of embedded structs
+// are added after t's own fields and never replace them.
+func (b *schemaBuilder) addFields(t reflect.Type, properties map[string]schemaMap, required *[]string) {
+	var embedded []reflect.Type
 
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
+		jsonTag := field.Tag.Get("json")
+
+		// Promote the fields of untagged embedded structs
+		if field.Anonymous && jsonTag == "" {
+			embeddedType := field.Type
+			if embeddedType.Kind() == reflect.Ptr {
+				embeddedType = embeddedType.Elem()
+			}
+			if embeddedType.Kind() == reflect.Struct && embeddedType != timeType {
+				embedded = append(embedded, embeddedType)
+				continue
+			}
+		}
 
 		// Skip unexported fields
 		if !field.IsExported() {
@@ -76,7 +226,6 @@ func buildObjectSchema(t reflect.Type) schemaMap {
 		}
 
 		// Get JSON name
-		jsonTag := field.Tag.Get("json")
 		if jsonTag == "" || jsonTag == "-" {
 			continue
 		}
@@ -86,38 +235,31 @@ func buildObjectSchema(t reflect.Type) schemaMap {
 		if name == "" {
 			continue
 		}
+		if _, exists := properties[name]; exists {
+			continue
+		}
 
 		// Build field schema
-		fieldSchema := buildFieldSchema(field)
-		properties[name] = fieldSchema
+		properties[name] = b.buildFieldSchema(field)
 
-		// Check if required
-		if field.Tag.Get("required") == "true" {
-			required = append(required, name)
+		// Check if required; pointers are always optional
+		if field.Tag.Get("required") == "true" && field.Type.Kind() != reflect.Ptr {
+			*required = append(*required, name)
 		}
 	}
 
-	schema := schemaMap{
-		"type":       "object",
-		"properties": properties,
-	}
-
-	if len(required) > 0 {
-		schema["required"] = required
+	for _, et := range embedded {
+		if b.building[et] {
+			continue
+		}
+		b.building[et] = true
+		b.addFields(et, properties, required)
+		delete(b.building, et)
 	}
-
-	return schema
 }
 
 // buildFieldSchema creates a JSON schema for a struct field.
-func buildFieldSchema(field reflect.StructField) schemaMap {
-	schema := schemaMap{}
-
-	// Add description if present
-	if desc := field.Tag.Get("desc"); desc != "" {
-		schema["description"] = desc
-	}
-
+func (b *schemaBuilder) buildFieldSchema(field reflect.StructField) schemaMap {
 	// Determine type and add type-specific properties
 	fieldType := field.Type
 
@@ -126,51 +268,31 @@ func buildFieldSchema(field reflect.StructField) schemaMap {
 		fieldType = fieldType.Elem()
 	}
 
+	var schema schemaMap
+	oneOf := field.Tag.Get("oneof")
+	switch {
+	case oneOf != "" && (fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array):
+		schema = schemaMap{"type": "array", "items": b.buildUnionSchema(oneOf)}
+	case oneOf != "":
+		schema = b.buildUnionSchema(oneOf)
+	default:
+		schema = b.buildTypeSchema(fieldType)
+	}
+
+	// Add description if present
+	if desc := field.Tag.Get("desc"); desc != "" {
+		schema["description"] = desc
+	}
+
 	switch fieldType.Kind() {
 	case reflect.String:
-		schema["type"] = "string"
 		addStringConstraints(schema, field.Tag)
-
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		schema["type"] = "integer"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
 		addNumericConstraints(schema, field.Tag)
-
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		schema["type"] = "integer"
-		schema["minimum"] = 0
-		addNumericConstraints(schema, field.Tag)
-
-	case reflect.Float32, reflect.Float64:
-		schema["type"] = "number"
-		addNumericConstraints(schema, field.Tag)
-
-	case reflect.Bool:
-		schema["type"] = "boolean"
-
 	case reflect.Slice, reflect.Array:
-		schema["type"] = "array"
-		itemSchema := buildTypeSchema(fieldType.Elem())
-		schema["items"] = itemSchema
 		addArrayConstraints(schema, field.Tag)
-
-	case reflect.Map:
-		if fieldType.Key().Kind() == reflect.String {
-			schema["type"] = "object"
-			valueSchema := buildTypeSchema(fieldType.Elem())
-			schema["additionalProperties"] = valueSchema
-		} else {
-			schema["type"] = "object"
-		}
-
-	case reflect.Struct:
-		// Nested struct
-		nested := buildObjectSchema(fieldType)
-		for k, v := range nested {
-			schema[k] = v
-		}
-
-	default:
-		schema["type"] = "string" // Fallback
 	}
 
 	// Add default value if present
@@ -181,12 +303,29 @@ func buildFieldSchema(field reflect.StructField) schemaMap {
 	return schema
 }
 
+// buildUnionSchema creates a JSON schema accepting any of the variants
+// named in a oneof tag. Unregistered names are skipped.
+func (b *schemaBuilder) buildUnionSchema(names string) schemaMap {
+	var alternatives []any
+	for _, name := range strings.Split(names, ",") {
+		t, ok := variants.Load(strings.TrimSpace(name))
+		if !ok {
+			continue
+		}
+		alternatives = append(alternatives, b.buildTypeSchema(t.(reflect.Type)))
+	}
+	return schemaMap{"anyOf": alternatives}
+}
+
 // buildTypeSchema creates a JSON schema for a Go type (used for array items, map values).
-func buildTypeSchema(t reflect.Type) schemaMap {
+func (b *schemaBuilder) buildTypeSchema(t reflect.Type) schemaMap {
 	// Handle pointer types
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
+	if t == timeType {
+		return schemaMap{"type": "string", "format": "date-time"}
+	}
 
 	switch t.Kind() {
 	case reflect.String:
@@ -202,18 +341,18 @@ func buildTypeSchema(t reflect.Type) schemaMap {
 	case reflect.Slice, reflect.Array:
 		return schemaMap{
 			"type":  "array",
-			"items": buildTypeSchema(t.Elem()),
+			"items": b.buildTypeSchema(t.Elem()),
 		}
 	case reflect.Map:
 		if t.Key().Kind() == reflect.String {
 			return schemaMap{
 				"type":                 "object",
-				"additionalProperties": buildTypeSchema(t.Elem()),
+				"additionalProperties": b.buildTypeSchema(t.Elem()),
 			}
 		}
 		return schemaMap{"type": "object"}
 	case reflect.Struct:
-		return buildObjectSchema(t)
+		return b.buildObjectSchema(t)
 	default:
 		return schemaMap{"type": "string"}
 	}
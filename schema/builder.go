@@ -0,0 +1,111 @@
+package schema
+
+import "encoding/json"
+
+// Schema is a JSON Schema built with the functions of this package. It is
+// a plain map, so any keyword without a builder method can be set directly:
+//
+//	s := schema.String()
+//	s["format"] = "uri"
+//
+// Builder methods modify the schema in place and return it for chaining.
+type Schema map[string]any
+
+// Property is a named property of an object schema.
+type Property struct {
+	Name   string
+	Schema Schema
+}
+
+// Prop returns the property name with schema s.
+func Prop(name string, s Schema) Property {
+	return Property{Name: name, Schema: s}
+}
+
+// Object returns an object schema with the given properties.
+func Object(props ...Property) Schema {
+	properties := make(map[string]any, len(props))
+	for _, p := range props {
+		properties[p.Name] = p.Schema
+	}
+	return Schema{"type": "object", "properties": properties}
+}
+
+// String returns a string schema.
+func String() Schema { return Schema{"type": "string"} }
+
+// Integer returns an integer schema.
+func Integer() Schema { return Schema{"type": "integer"} }
+
+// Number returns a number schema.
+func Number() Schema { return Schema{"type": "number"} }
+
+// Boolean returns a boolean schema.
+func Boolean() Schema { return Schema{"type": "boolean"} }
+
+// Array returns an array schema whose items match items.
+func Array(items Schema) Schema {
+	return Schema{"type": "array", "items": items}
+}
+
+// AnyOf returns a schema matching any of alternatives.
+func AnyOf(alternatives ...Schema) Schema {
+	alts := make([]any, len(alternatives))
+	for i, a := range alternatives {
+		alts[i] = a
+	}
+	return Schema{"anyOf": alts}
+}
+
+// Ref returns a reference to the definition name, added to an enclosing
+// schema with Define. References may be recursive, so a definition can
+// refer to itself to describe trees and nested threads.
+func Ref(name string) Schema {
+	return Schema{"$ref": DefsPrefix + name}
+}
+
+// DefsPrefix is the prefix of references created by Ref.
+const DefsPrefix = "#/$defs/"
+
+// Describe sets the schema's description.
+func (s Schema) Describe(description string) Schema {
+	s["description"] = description
+	return s
+}
+
+// Require marks properties of an object schema as required.
+func (s Schema) Require(names ...string) Schema {
+	required, _ := s["required"].([]string)
+	s["required"] = append(required, names...)
+	return s
+}
+
+// Enum restricts the schema to the given values.
+func (s Schema) Enum(values ...any) Schema {
+	s["enum"] = values
+	return s
+}
+
+// Define adds the definition name, which Ref(name) refers to anywhere in
+// s. Define definitions on the outermost schema, the one sent as tool
+// parameters or response schema.
+func (s Schema) Define(name string, def Schema) Schema {
+	defs, _ := s["$defs"].(map[string]any)
+	if defs == nil {
+		defs = make(map[string]any)
+		s["$defs"] = defs
+	}
+	defs[name] = def
+	return s
+}
+
+// JSON returns the schema's JSON encoding, for use as tool parameters or a
+// response schema. It panics if the schema holds a value that cannot be
+// encoded.
+func (s Schema) JSON() json.RawMessage {
+	data, err := json.Marshal(s)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
@@ -0,0 +1,88 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	comment := Object(
+		Prop("author", String().Describe("Who wrote the comment")),
+		Prop("votes", Integer()),
+		Prop("replies", Array(Ref("comment"))),
+	).Require("author")
+
+	thread := Object(
+		Prop("title", String()),
+		Prop("status", String().Enum("open", "closed")),
+		Prop("comments", Array(Ref("comment"))),
+	).Require("title").Define("comment", comment)
+
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"title": {"type": "string"},
+			"status": {"type": "string", "enum": ["open", "closed"]},
+			"comments": {"type": "array", "items": {"$ref": "#/$defs/comment"}}
+		},
+		"required": ["title"],
+		"$defs": {
+			"comment": {
+				"type": "object",
+				"properties": {
+					"author": {"type": "string", "description": "Who wrote the comment"},
+					"votes": {"type": "integer"},
+					"replies": {"type": "array", "items": {"$ref": "#/$defs/comment"}}
+				},
+				"required": ["author"]
+			}
+		}
+	}`, string(thread.JSON()))
+
+	t.Run("validates recursively", func(t *testing.T) {
+		s := thread.JSON()
+		assert.NoError(t, Validate(s, []byte(`{"title":"t","comments":[{"author":"a","replies":[{"author":"b"}]}]}`)))
+
+		err := Validate(s, []byte(`{"title":"t","comments":[{"author":"a","replies":[{"votes":"x"}]}]}`))
+		assert.EqualError(t, err, `$.comments[0].replies[0]: missing required property "author"`+"\n"+
+			`$.comments[0].replies[0].votes: expected integer, got string`)
+	})
+}
+
+func TestValidate_Refs(t *testing.T) {
+	assert.ErrorContains(t, Validate([]byte(`{"$ref":"#/$defs/missing"}`), []byte(`1`)),
+		`cannot resolve schema reference "#/$defs/missing"`)
+	assert.ErrorContains(t, Validate([]byte(`{"$ref":"#"}`), []byte(`1`)), "nests too deeply")
+	assert.NoError(t, Validate([]byte(`{"definitions":{"a/b":{"type":"string"}},"$ref":"#/definitions/a~1b"}`), []byte(`"x"`)))
+}
+
+func TestFlatten(t *testing.T) {
+	var s map[string]any
+	require.NoError(t, json.Unmarshal(Object(
+		Prop("root", Ref("node").Describe("The root node")),
+	).Define("node", Object(
+		Prop("name", String()),
+		Prop("children", Array(Ref("node"))),
+	).Describe("A node")).JSON(), &s))
+
+	flat := Flatten(s, 2)
+	assert.NotContains(t, flat, "$defs")
+
+	root := flat["properties"].(map[string]any)["root"].(map[string]any)
+	assert.Equal(t, "The root node", root["description"], "keywords beside $ref win")
+
+	level1 := root["properties"].(map[string]any)["children"].(map[string]any)["items"].(map[string]any)
+	assert.Equal(t, "A node", level1["description"])
+	assert.Contains(t, level1["properties"], "children")
+
+	level2 := level1["properties"].(map[string]any)["children"].(map[string]any)["items"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "object", "description": "A node"}, level2)
+
+	// The input is unchanged
+	assert.Contains(t, s, "$defs")
+
+	assert.Equal(t, map[string]any{"x": map[string]any{}}, Flatten(map[string]any{"x": map[string]any{"$ref": "#/nowhere"}}, 1))
+}
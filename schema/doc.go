@@ -0,0 +1,68 @@
+// Package schema validates JSON documents against JSON Schema without
+// third-party dependencies.
+//
+// Validate checks an instance against a schema and reports every violation
+// with the JSON path of the offending value:
+//
+//	err := schema.Validate(tool.Parameters, []byte(call.Arguments))
+//	var invalid *schema.ErrValidation
+//	if errors.As(err, &invalid) {
+//	    for _, v := range invalid.Violations {
+//	        fmt.Println(v.Path, v.Message) // $.limit value 500 is greater than the maximum 100
+//	    }
+//	}
+//
+// # Building Schemas
+//
+// Object, String, Array, and the other builders describe schemas in code,
+// where struct tags and gains.SchemaFor do not fit. Define adds named
+// definitions that Ref refers to, so recursive structures such as trees or
+// nested comment threads can be expressed:
+//
+//	comment := schema.Object(
+//	    schema.Prop("author", schema.String()),
+//	    schema.Prop("replies", schema.Array(schema.Ref("comment"))),
+//	).Require("author")
+//
+//	thread := schema.Object(
+//	    schema.Prop("comments", schema.Array(schema.Ref("comment"))),
+//	).Define("comment", comment)
+//
+//	tool := gains.Tool{Name: "post_thread", Parameters: thread.JSON()}
+//
+// Providers supporting $ref receive the schema as is. Gemini does not, so
+// its schemas are flattened with Flatten, which inlines definitions and
+// expands recursive references a few levels deep.
+//
+// # Provider Compatibility
+//
+// Downgrade rewrites a schema for a provider supporting only part of JSON
+// Schema, described by a Profile such as Gemini. Unsupported keywords are
+// expressed in supported ones where possible, such as const as a
+// single-value enum; the rest are removed, noted in the description so the
+// model still sees them, and returned as Dropped constraints. The Gemini
+// provider downgrades every tool and response schema this way, reporting
+// dropped constraints to gains.WithSchemaWarnings.
+//
+// # Supported Keywords
+//
+// Validate implements the practical subset of JSON Schema used for tool
+// parameters and structured output:
+//
+//   - Any type: type, enum, const, allOf, anyOf, oneOf, not, and local $ref
+//     references into $defs or definitions
+//   - Numbers: minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf
+//   - Strings: minLength, maxLength, pattern, format (date-time, date, time,
+//     email, uri, uuid)
+//   - Arrays: items, minItems, maxItems, uniqueItems
+//   - Objects: properties, required, additionalProperties, minProperties,
+//     maxProperties
+//
+// Other keywords are ignored, so schemas using them still validate the
+// keywords above. Patterns use Go's RE2 syntax, which lacks lookarounds and
+// backreferences.
+//
+// The client validates structured output with this package when
+// gains.WithSchemaRetry is set, and tool.WithArgumentValidation validates
+// tool call arguments before handlers run.
+package schema
@@ -0,0 +1,210 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Profile describes the JSON Schema a provider accepts, for Downgrade.
+type Profile struct {
+	// Keywords are the keywords the provider supports. Others are removed.
+	Keywords []string
+
+	// TypeArrays reports whether type may be an array of types. Without
+	// it, a null member becomes nullable: true if supported, and several
+	// other members become anyOf alternatives.
+	TypeArrays bool
+}
+
+// Gemini is the schema subset of Gemini function declarations and
+// response schemas, based on OpenAPI 3.0. Schemas must also be flattened
+// with Flatten, as $ref is not supported.
+var Gemini = Profile{
+	Keywords: []string{
+		"type", "format", "title", "description", "nullable", "enum",
+		"default", "example", "anyOf", "properties", "required",
+		"propertyOrdering", "minProperties", "maxProperties", "items",
+		"minItems", "maxItems", "minLength", "maxLength", "pattern",
+		"minimum", "maximum",
+	},
+}
+
+// annotations are keywords removed without a report, as they do not
+// constrain values.
+var annotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "examples": true,
+	"deprecated": true, "readOnly": true, "writeOnly": true,
+}
+
+// Dropped is a keyword Downgrade removed from a schema.
+type Dropped struct {
+	// Path is the JSON pointer of the schema the keyword was removed from,
+	// empty for the root.
+	Path    string `json:"path"`
+	Keyword string `json:"keyword"`
+	Value   any    `json:"value"`
+}
+
+// String describes the dropped keyword, as in
+// "/properties/code: pattern \"^[A-Z]{3}$\"".
+func (d Dropped) String() string {
+	path := d.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s: %s %s", path, d.Keyword, compact(d.Value))
+}
+
+// Downgrade returns a copy of s rewritten for a provider supporting only
+// profile, and the constraints it could not keep. Constraints are
+// rewritten in supported terms where possible:
+//
+//   - const becomes a single-value enum
+//   - oneOf becomes anyOf, which the model satisfies the same way
+//   - a type array becomes a single type with nullable: true, or anyOf
+//
+// Other unsupported keywords are removed and reported, except annotations
+// such as $schema and examples, and noted in the schema's description, so
+// the model still sees constraints the provider cannot enforce. The input
+// is not modified.
+func Downgrade(s map[string]any, profile Profile) (map[string]any, []Dropped) {
+	d := downgrader{profile: profile, supported: make(map[string]bool, len(profile.Keywords))}
+	for _, kw := range profile.Keywords {
+		d.supported[kw] = true
+	}
+	return d.schema(s, ""), d.dropped
+}
+
+type downgrader struct {
+	profile   Profile
+	supported map[string]bool
+	dropped   []Dropped
+}
+
+func (d *downgrader) schema(s map[string]any, path string) map[string]any {
+	out := make(map[string]any, len(s))
+	for k, v := range s {
+		out[k] = v
+	}
+	d.rewrite(out)
+
+	var notes []string
+	keys := make([]string, 0, len(out))
+	for k := range out {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if d.supported[k] {
+			out[k] = d.children(k, out[k], path+"/"+k)
+			continue
+		}
+		v := out[k]
+		delete(out, k)
+		if annotations[k] {
+			continue
+		}
+		d.dropped = append(d.dropped, Dropped{Path: path, Keyword: k, Value: v})
+		notes = append(notes, k+": "+compact(v))
+	}
+
+	if len(notes) > 0 && d.supported["description"] {
+		desc, _ := out["description"].(string)
+		note := "(" + strings.Join(notes, "; ") + ")"
+		if desc != "" {
+			note = desc + " " + note
+		}
+		out["description"] = note
+	}
+	return out
+}
+
+// rewrite expresses unsupported keywords of s in supported ones.
+func (d *downgrader) rewrite(s map[string]any) {
+	if c, ok := s["const"]; ok && !d.supported["const"] && d.supported["enum"] {
+		if _, exists := s["enum"]; !exists {
+			s["enum"] = []any{c}
+			delete(s, "const")
+		}
+	}
+	if alts, ok := s["oneOf"]; ok && !d.supported["oneOf"] && d.supported["anyOf"] {
+		if _, exists := s["anyOf"]; !exists {
+			s["anyOf"] = alts
+			delete(s, "oneOf")
+		}
+	}
+	types, ok := s["type"].([]any)
+	if !ok || d.profile.TypeArrays {
+		return
+	}
+	var kept []any
+	for _, t := range types {
+		if t == "null" && d.supported["nullable"] {
+			s["nullable"] = true
+			continue
+		}
+		kept = append(kept, t)
+	}
+	switch {
+	case len(kept) == 1:
+		s["type"] = kept[0]
+	case len(kept) > 1 && d.supported["anyOf"]:
+		if _, exists := s["anyOf"]; !exists {
+			alts := make([]any, len(kept))
+			for i, t := range kept {
+				alts[i] = map[string]any{"type": t}
+			}
+			s["anyOf"] = alts
+			delete(s, "type")
+		}
+	}
+}
+
+// children downgrades the subschemas held by keyword k.
+func (d *downgrader) children(k string, v any, path string) any {
+	switch k {
+	case "items", "additionalProperties", "not":
+		if m, ok := v.(map[string]any); ok {
+			return d.schema(m, path)
+		}
+	case "anyOf", "oneOf", "allOf":
+		if list, ok := v.([]any); ok {
+			out := make([]any, len(list))
+			for i, item := range list {
+				out[i] = item
+				if m, ok := item.(map[string]any); ok {
+					out[i] = d.schema(m, fmt.Sprintf("%s/%d", path, i))
+				}
+			}
+			return out
+		}
+	case "properties", "patternProperties", "$defs", "definitions":
+		if props, ok := v.(map[string]any); ok {
+			out := make(map[string]any, len(props))
+			for name, prop := range props {
+				out[name] = prop
+				if m, ok := prop.(map[string]any); ok {
+					out[name] = d.schema(m, path+"/"+escapePointer(name))
+				}
+			}
+			return out
+		}
+	}
+	return v
+}
+
+// escapePointer escapes a JSON pointer reference token.
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// compact returns v as compact JSON.
+func compact(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,74 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDowngrade(t *testing.T) {
+	var in map[string]any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"additionalProperties": false,
+		"properties": {
+			"code": {"type": "string", "description": "Airport code", "pattern": "^[A-Z]{3}$"},
+			"kind": {"const": "flight"},
+			"seats": {"type": ["integer", "null"], "exclusiveMinimum": 0, "multipleOf": 2},
+			"id": {"type": ["string", "integer"]},
+			"legs": {"type": "array", "uniqueItems": true, "items": {"oneOf": [{"type": "string"}, {"type": "object", "additionalProperties": {"type": "number"}}]}}
+		},
+		"required": ["code"]
+	}`), &in))
+	original, err := json.Marshal(in)
+	require.NoError(t, err)
+
+	out, dropped := Downgrade(in, Gemini)
+
+	got, err := json.Marshal(out)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"description": "(additionalProperties: false)",
+		"properties": {
+			"code": {"type": "string", "description": "Airport code", "pattern": "^[A-Z]{3}$"},
+			"kind": {"enum": ["flight"]},
+			"seats": {"type": "integer", "nullable": true, "description": "(exclusiveMinimum: 0; multipleOf: 2)"},
+			"id": {"anyOf": [{"type": "string"}, {"type": "integer"}]},
+			"legs": {"type": "array", "description": "(uniqueItems: true)", "items": {"anyOf": [
+				{"type": "string"},
+				{"type": "object", "description": "(additionalProperties: {\"type\":\"number\"})"}
+			]}}
+		},
+		"required": ["code"]
+	}`, string(got))
+
+	var descriptions []string
+	for _, d := range dropped {
+		descriptions = append(descriptions, d.String())
+	}
+	assert.ElementsMatch(t, []string{
+		`/: additionalProperties false`,
+		`/properties/seats: exclusiveMinimum 0`,
+		`/properties/seats: multipleOf 2`,
+		`/properties/legs: uniqueItems true`,
+		`/properties/legs/items/anyOf/1: additionalProperties {"type":"number"}`,
+	}, descriptions)
+
+	after, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(original), string(after), "input must not be modified")
+}
+
+func TestDowngrade_Supported(t *testing.T) {
+	in := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"n": map[string]any{"type": "integer", "minimum": 1.0}},
+	}
+	out, dropped := Downgrade(in, Gemini)
+	assert.Empty(t, dropped)
+	assert.Equal(t, in, out)
+}
@@ -0,0 +1,100 @@
+package schema
+
+import "strings"
+
+// Flatten returns a copy of schema with every $ref replaced by the schema it
+// refers to, and the $defs and definitions keywords removed, for providers
+// such as Gemini that do not support references. A recursive reference is
+// expanded at most maxDepth times along any path; past that, it becomes an
+// object schema carrying only the definition's description. References
+// that cannot be resolved are replaced with an empty schema. The input is
+// not modified.
+func Flatten(schema map[string]any, maxDepth int) map[string]any {
+	f := flattener{root: schema, maxDepth: maxDepth, depth: make(map[string]int)}
+	out, _ := f.flatten(schema).(map[string]any)
+	return out
+}
+
+// flattener inlines references, counting how many times each is being
+// expanded on the current path.
+type flattener struct {
+	root     map[string]any
+	maxDepth int
+	depth    map[string]int
+}
+
+func (f *flattener) flatten(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok {
+			return f.inline(ref, v)
+		}
+		out := make(map[string]any, len(v))
+		for key, child := range v {
+			if key == "$defs" || key == "definitions" {
+				continue
+			}
+			out[key] = f.flatten(child)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, child := range v {
+			out[i] = f.flatten(child)
+		}
+		return out
+	}
+	return value
+}
+
+// inline returns the flattened target of ref. Keywords beside the $ref,
+// such as a description, override the target's.
+func (f *flattener) inline(ref string, site map[string]any) any {
+	target, ok := resolveRef(f.root, ref).(map[string]any)
+	if !ok {
+		return map[string]any{}
+	}
+
+	var out map[string]any
+	if f.depth[ref] >= f.maxDepth {
+		out = map[string]any{"type": "object"}
+		if desc, ok := target["description"]; ok {
+			out["description"] = desc
+		}
+	} else {
+		f.depth[ref]++
+		out, _ = f.flatten(target).(map[string]any)
+		f.depth[ref]--
+	}
+
+	for key, v := range site {
+		if key != "$ref" {
+			out[key] = f.flatten(v)
+		}
+	}
+	return out
+}
+
+// resolveRef returns the schema a local reference such as "#/$defs/node"
+// or "#" points to within root, or nil if it points nowhere.
+func resolveRef(root map[string]any, ref string) any {
+	pointer, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil
+	}
+	var current any = root
+	if pointer == "" {
+		return current
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		if current, ok = obj[token]; !ok {
+			return nil
+		}
+	}
+	return current
+}
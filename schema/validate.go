@@ -0,0 +1,451 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Violation is one way an instance fails to match a schema.
+type Violation struct {
+	// Path is the JSON path of the offending value, such as "$.items[2]".
+	Path string
+	// Message describes the violation.
+	Message string
+}
+
+// String returns the violation as "path: message".
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// ErrValidation is returned by Validate when an instance does not match its
+// schema. It lists every violation found.
+type ErrValidation struct {
+	Violations []Violation
+}
+
+// Error returns the violations, one per line.
+func (e *ErrValidation) Error() string {
+	lines := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		lines[i] = v.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Validate checks the JSON document instanceJSON against the JSON Schema
+// schemaJSON. It returns *ErrValidation listing every violation, or an error
+// if either document is not valid JSON. Keywords outside the supported
+// subset are ignored.
+func Validate(schemaJSON, instanceJSON []byte) error {
+	var s any
+	if err := json.Unmarshal(schemaJSON, &s); err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+	switch s.(type) {
+	case map[string]any, bool:
+	default:
+		return errors.New("invalid schema: must be an object or a boolean")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(instanceJSON))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	if dec.More() {
+		return errors.New("invalid JSON: unexpected content after value")
+	}
+
+	v := validator{root: s}
+	v.validate("$", s, value)
+	if len(v.violations) > 0 {
+		return &ErrValidation{Violations: v.violations}
+	}
+	return nil
+}
+
+// maxRefDepth bounds nested $ref resolution, stopping references that loop
+// without consuming any of the instance.
+const maxRefDepth = 512
+
+// validator collects the violations found while walking an instance.
+type validator struct {
+	root       any // the schema document, against which $refs resolve
+	refDepth   int
+	violations []Violation
+}
+
+func (v *validator) addf(path, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// matches reports whether value matches schema without recording violations.
+func (v *validator) matches(schema, value any) bool {
+	sub := validator{root: v.root, refDepth: v.refDepth}
+	sub.validate("$", schema, value)
+	return len(sub.violations) == 0
+}
+
+// validate records the violations of value against schema, which is a
+// schema object or a boolean schema.
+func (v *validator) validate(path string, schema, value any) {
+	s, ok := schema.(map[string]any)
+	if !ok {
+		if allowed, isBool := schema.(bool); isBool && !allowed {
+			v.addf(path, "value is not allowed")
+		}
+		return
+	}
+
+	if ref, ok := s["$ref"].(string); ok {
+		v.validateRef(path, ref, value)
+	}
+
+	if types := schemaTypes(s["type"]); len(types) > 0 && !slices.ContainsFunc(types, func(t string) bool {
+		return matchesType(t, value)
+	}) {
+		v.addf(path, "expected %s, got %s", strings.Join(types, " or "), jsonType(value))
+		return
+	}
+
+	if enum, ok := s["enum"].([]any); ok && !slices.ContainsFunc(enum, func(e any) bool {
+		return jsonEqual(e, value)
+	}) {
+		v.addf(path, "value %s is not one of the allowed values", encodeValue(value))
+	}
+	if c, ok := s["const"]; ok && !jsonEqual(c, value) {
+		v.addf(path, "value must be %s", encodeValue(c))
+	}
+
+	v.validateComposition(path, s, value)
+
+	switch value := value.(type) {
+	case json.Number:
+		v.validateNumber(path, s, value)
+	case string:
+		v.validateString(path, s, value)
+	case []any:
+		v.validateArray(path, s, value)
+	case map[string]any:
+		v.validateObject(path, s, value)
+	}
+}
+
+// validateRef validates value against the schema ref points to. Only local
+// references, such as "#/$defs/node" or "#", are supported.
+func (v *validator) validateRef(path, ref string, value any) {
+	root, _ := v.root.(map[string]any)
+	target := resolveRef(root, ref)
+	if target == nil {
+		v.addf(path, "cannot resolve schema reference %q", ref)
+		return
+	}
+	if v.refDepth >= maxRefDepth {
+		v.addf(path, "schema reference %q nests too deeply", ref)
+		return
+	}
+	v.refDepth++
+	v.validate(path, target, value)
+	v.refDepth--
+}
+
+// validateComposition checks the allOf, anyOf, oneOf, and not keywords.
+func (v *validator) validateComposition(path string, s map[string]any, value any) {
+	if all, ok := s["allOf"].([]any); ok {
+		for _, sub := range all {
+			v.validate(path, sub, value)
+		}
+	}
+	if anyOf, ok := s["anyOf"].([]any); ok && !slices.ContainsFunc(anyOf, func(sub any) bool {
+		return v.matches(sub, value)
+	}) {
+		v.addf(path, "value does not match any of the allowed schemas")
+	}
+	if oneOf, ok := s["oneOf"].([]any); ok {
+		n := 0
+		for _, sub := range oneOf {
+			if v.matches(sub, value) {
+				n++
+			}
+		}
+		if n != 1 {
+			v.addf(path, "value matches %d schemas, want exactly one", n)
+		}
+	}
+	if not, ok := s["not"]; ok && v.matches(not, value) {
+		v.addf(path, "value matches a disallowed schema")
+	}
+}
+
+// validateNumber checks the numeric keywords.
+func (v *validator) validateNumber(path string, s map[string]any, n json.Number) {
+	f, err := n.Float64()
+	if err != nil {
+		v.addf(path, "invalid number %s", n)
+		return
+	}
+	if min, ok := number(s["minimum"]); ok && f < min {
+		v.addf(path, "value %s is less than the minimum %v", n, min)
+	}
+	if max, ok := number(s["maximum"]); ok && f > max {
+		v.addf(path, "value %s is greater than the maximum %v", n, max)
+	}
+	if min, ok := number(s["exclusiveMinimum"]); ok && f <= min {
+		v.addf(path, "value %s must be greater than %v", n, min)
+	}
+	if max, ok := number(s["exclusiveMaximum"]); ok && f >= max {
+		v.addf(path, "value %s must be less than %v", n, max)
+	}
+	if m, ok := number(s["multipleOf"]); ok && m > 0 {
+		if q := f / m; math.Abs(q-math.Round(q)) > 1e-9 {
+			v.addf(path, "value %s is not a multiple of %v", n, m)
+		}
+	}
+}
+
+// validateString checks the string keywords. Lengths count characters, not
+// bytes.
+func (v *validator) validateString(path string, s map[string]any, str string) {
+	length := utf8.RuneCountInString(str)
+	if min, ok := number(s["minLength"]); ok && float64(length) < min {
+		v.addf(path, "length %d is less than the minimum %v", length, min)
+	}
+	if max, ok := number(s["maxLength"]); ok && float64(length) > max {
+		v.addf(path, "length %d is greater than the maximum %v", length, max)
+	}
+	if pattern, ok := s["pattern"].(string); ok {
+		re, err := compilePattern(pattern)
+		if err != nil {
+			v.addf(path, "invalid pattern %q in schema: %v", pattern, err)
+		} else if !re.MatchString(str) {
+			v.addf(path, "value %q does not match pattern %q", str, pattern)
+		}
+	}
+	if format, ok := s["format"].(string); ok && !validFormat(format, str) {
+		v.addf(path, "value %q is not a valid %s", str, format)
+	}
+}
+
+// validateArray checks the array keywords and validates each item.
+func (v *validator) validateArray(path string, s map[string]any, arr []any) {
+	if min, ok := number(s["minItems"]); ok && float64(len(arr)) < min {
+		v.addf(path, "array has %d items, fewer than the minimum %v", len(arr), min)
+	}
+	if max, ok := number(s["maxItems"]); ok && float64(len(arr)) > max {
+		v.addf(path, "array has %d items, more than the maximum %v", len(arr), max)
+	}
+	if unique, ok := s["uniqueItems"].(bool); ok && unique {
+		seen := make(map[string]int, len(arr))
+		for i, item := range arr {
+			key := encodeValue(item)
+			if first, dup := seen[key]; dup {
+				v.addf(fmt.Sprintf("%s[%d]", path, i), "duplicate of item %d", first)
+				continue
+			}
+			seen[key] = i
+		}
+	}
+	if items, ok := s["items"]; ok {
+		for i, item := range arr {
+			v.validate(fmt.Sprintf("%s[%d]", path, i), items, item)
+		}
+	}
+}
+
+// validateObject checks the object keywords and validates each property.
+func (v *validator) validateObject(path string, s map[string]any, obj map[string]any) {
+	properties, _ := s["properties"].(map[string]any)
+
+	if required, ok := s["required"].([]any); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, present := obj[name]; !present {
+				v.addf(path, "missing required property %q", name)
+			}
+		}
+	}
+	if min, ok := number(s["minProperties"]); ok && float64(len(obj)) < min {
+		v.addf(path, "object has %d properties, fewer than the minimum %v", len(obj), min)
+	}
+	if max, ok := number(s["maxProperties"]); ok && float64(len(obj)) > max {
+		v.addf(path, "object has %d properties, more than the maximum %v", len(obj), max)
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propPath := path + "." + name
+		if propSchema, known := properties[name]; known {
+			v.validate(propPath, propSchema, obj[name])
+			continue
+		}
+		switch additional := s["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				v.addf(path, "unexpected property %q", name)
+			}
+		case map[string]any:
+			v.validate(propPath, additional, obj[name])
+		}
+	}
+}
+
+// schemaTypes returns the types allowed by a schema's type keyword, which
+// may be a single type or a list of types.
+func schemaTypes(t any) []string {
+	switch t := t.(type) {
+	case string:
+		return []string{t}
+	case []any:
+		types := make([]string, 0, len(t))
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				types = append(types, s)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+// matchesType reports whether value is an instance of the JSON Schema type t.
+func matchesType(t string, value any) bool {
+	switch t {
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	default:
+		return jsonType(value) == t
+	}
+}
+
+// jsonType returns the JSON type name of a decoded value.
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// number returns a numeric schema keyword's value.
+func number(v any) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+// jsonEqual reports whether two decoded JSON values are equal, comparing
+// numbers by value.
+func jsonEqual(a, b any) bool {
+	return encodeValue(a) == encodeValue(b)
+}
+
+// encodeValue returns the canonical JSON encoding of a decoded value, with
+// numbers normalized so 1 and 1.0 encode the same.
+func encodeValue(value any) string {
+	data, _ := json.Marshal(normalize(value))
+	return string(data)
+}
+
+// normalize converts the json.Numbers in a decoded value to float64.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = normalize(item)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			out[k] = normalize(item)
+		}
+		return out
+	}
+	return value
+}
+
+// patterns caches compiled pattern keywords, since the same schemas are
+// validated repeatedly.
+var patterns sync.Map
+
+// compilePattern returns the compiled regular expression of a pattern
+// keyword. Go's RE2 syntax covers the ECMA-262 features schemas commonly
+// use; lookarounds and backreferences fail to compile.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// validFormat reports whether s is valid for a format keyword. Unknown
+// formats accept any string.
+func validFormat(format, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, s)
+		return err == nil
+	case "time":
+		_, err := time.Parse("15:04:05Z07:00", s)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   string
+		instance string
+		want     []string
+	}{
+		{"type", `{"type":"string"}`, `1`, []string{"$: expected string, got number"}},
+		{"type list", `{"type":["string","null"]}`, `null`, nil},
+		{"integer", `{"type":"integer"}`, `2.0`, nil},
+		{"not integer", `{"type":"integer"}`, `2.5`, []string{"$: expected integer, got number"}},
+		{"enum", `{"enum":["a","b"]}`, `"c"`, []string{`$: value "c" is not one of the allowed values`}},
+		{"enum numbers", `{"enum":[1,2]}`, `1.0`, nil},
+		{"const", `{"const":{"a":[1]}}`, `{"a":[1.0]}`, nil},
+		{"minimum", `{"minimum":1}`, `0`, []string{"$: value 0 is less than the minimum 1"}},
+		{"maximum", `{"maximum":10}`, `11`, []string{"$: value 11 is greater than the maximum 10"}},
+		{"exclusive bounds", `{"exclusiveMinimum":0,"exclusiveMaximum":1}`, `1`, []string{"$: value 1 must be less than 1"}},
+		{"multipleOf", `{"multipleOf":0.5}`, `1.25`, []string{"$: value 1.25 is not a multiple of 0.5"}},
+		{"multipleOf ok", `{"multipleOf":0.1}`, `0.3`, nil},
+		{"minLength counts characters", `{"minLength":3}`, `"héé"`, nil},
+		{"maxLength", `{"maxLength":2}`, `"abc"`, []string{"$: length 3 is greater than the maximum 2"}},
+		{"pattern", `{"pattern":"^[a-z]+$"}`, `"abc1"`, []string{`$: value "abc1" does not match pattern "^[a-z]+$"`}},
+		{"format date-time", `{"format":"date-time"}`, `"2025-01-02T03:04:05Z"`, nil},
+		{"format email", `{"format":"email"}`, `"not an email"`, []string{`$: value "not an email" is not a valid email`}},
+		{"unknown format", `{"format":"hostname"}`, `"anything"`, nil},
+		{"items", `{"items":{"type":"integer"}}`, `[1,"x"]`, []string{"$[1]: expected integer, got string"}},
+		{"array bounds", `{"minItems":2,"maxItems":3}`, `[1]`, []string{"$: array has 1 items, fewer than the minimum 2"}},
+		{"uniqueItems", `{"uniqueItems":true}`, `[1,2,1]`, []string{"$[2]: duplicate of item 0"}},
+		{
+			"object",
+			`{"type":"object","properties":{"a":{"type":"string"}},"required":["a","b"],"additionalProperties":false}`,
+			`{"a":1,"c":true}`,
+			[]string{`$: missing required property "b"`, "$.a: expected string, got number", `$: unexpected property "c"`},
+		},
+		{"additionalProperties schema", `{"additionalProperties":{"type":"number"}}`, `{"x":"y"}`, []string{"$.x: expected number, got string"}},
+		{"property bounds", `{"maxProperties":1}`, `{"a":1,"b":2}`, []string{"$: object has 2 properties, more than the maximum 1"}},
+		{"anyOf", `{"anyOf":[{"type":"string"},{"type":"integer"}]}`, `true`, []string{"$: value does not match any of the allowed schemas"}},
+		{"oneOf", `{"oneOf":[{"type":"integer"},{"type":"number"}]}`, `1`, []string{"$: value matches 2 schemas, want exactly one"}},
+		{"allOf", `{"allOf":[{"minimum":1},{"maximum":2}]}`, `3`, []string{"$: value 3 is greater than the maximum 2"}},
+		{"not", `{"not":{"type":"null"}}`, `null`, []string{"$: value matches a disallowed schema"}},
+		{"false schema", `{"properties":{"a":false}}`, `{"a":1}`, []string{"$.a: value is not allowed"}},
+		{"true schema", `true`, `{"anything":[1]}`, nil},
+		{"unknown keywords ignored", `{"type":"string","x-custom":1}`, `"ok"`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate([]byte(tt.schema), []byte(tt.instance))
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var invalid *ErrValidation
+			require.True(t, errors.As(err, &invalid), "got %v", err)
+			got := make([]string, len(invalid.Violations))
+			for i, v := range invalid.Violations {
+				got[i] = v.String()
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidate_Errors(t *testing.T) {
+	assert.ErrorContains(t, Validate([]byte(`nope`), []byte(`{}`)), "invalid schema")
+	assert.ErrorContains(t, Validate([]byte(`[]`), []byte(`{}`)), "invalid schema")
+	assert.ErrorContains(t, Validate([]byte(`{}`), []byte(`{"a":`)), "invalid JSON")
+	assert.ErrorContains(t, Validate([]byte(`{}`), []byte(`{} {}`)), "invalid JSON")
+
+	err := Validate([]byte(`{"pattern":"(?<=a)b"}`), []byte(`"b"`))
+	assert.ErrorContains(t, err, "invalid pattern")
+}
+
+func TestErrValidation(t *testing.T) {
+	err := &ErrValidation{Violations: []Violation{
+		{Path: "$", Message: "first"},
+		{Path: "$.a", Message: "second"},
+	}}
+	assert.Equal(t, "$: first\n$.a: second", err.Error())
+}
@@ -0,0 +1,69 @@
+package gains
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/spetersoncode/gains/schema"
+)
+
+// SchemaWarning reports the constraints dropped from a schema when it was
+// downgraded for a provider that cannot express them.
+type SchemaWarning struct {
+	Provider Provider
+	// Tool is the tool whose parameters were downgraded; empty for the
+	// response schema.
+	Tool    string
+	Dropped []schema.Dropped
+}
+
+// String lists the dropped constraints.
+func (w SchemaWarning) String() string {
+	target := "response schema"
+	if w.Tool != "" {
+		target = fmt.Sprintf("tool %q", w.Tool)
+	}
+	return fmt.Sprintf("%s does not support the %s constraints %s", w.Provider, target, strings.Join(w.constraints(), ", "))
+}
+
+func (w SchemaWarning) constraints() []string {
+	dropped := make([]string, len(w.Dropped))
+	for i, d := range w.Dropped {
+		dropped[i] = d.String()
+	}
+	return dropped
+}
+
+// SchemaWarningFunc receives the constraints dropped from a schema.
+type SchemaWarningFunc func(SchemaWarning)
+
+// WithSchemaWarnings sets the function receiving the constraints dropped
+// from tool parameter and response schemas for providers that support only
+// part of JSON Schema, such as Gemini. Dropped constraints are also noted
+// in the schema's description for the model. Without it, they are logged
+// with slog.Default at warn level.
+func WithSchemaWarnings(fn SchemaWarningFunc) Option {
+	return func(o *Options) {
+		o.SchemaWarning = fn
+	}
+}
+
+// DowngradeSchema returns s downgraded with schema.Downgrade for a provider
+// supporting profile, reporting any dropped constraints to the
+// SchemaWarning hook. tool names the tool the schema describes; empty for
+// the response schema.
+func (o *Options) DowngradeSchema(provider Provider, tool string, s map[string]any, profile schema.Profile) map[string]any {
+	s, dropped := schema.Downgrade(s, profile)
+	if len(dropped) == 0 {
+		return s
+	}
+	w := SchemaWarning{Provider: provider, Tool: tool, Dropped: dropped}
+	if o.SchemaWarning != nil {
+		o.SchemaWarning(w)
+	} else {
+		slog.Default().Warn("gains: schema constraints dropped",
+			"provider", string(provider), "tool", tool, "constraints", w.constraints())
+	}
+	return s
+}
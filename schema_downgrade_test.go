@@ -0,0 +1,28 @@
+package gains
+
+import (
+	"testing"
+
+	"github.com/spetersoncode/gains/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_DowngradeSchema(t *testing.T) {
+	var warnings []SchemaWarning
+	opts := ApplyOptions(WithSchemaWarnings(func(w SchemaWarning) {
+		warnings = append(warnings, w)
+	}))
+
+	s := map[string]any{"type": "string", "pattern": "^a", "const": "a", "not": map[string]any{"type": "null"}}
+	out := opts.DowngradeSchema(ProviderGoogle, "lookup", s, schema.Profile{Keywords: []string{"type", "pattern", "enum"}})
+	assert.Equal(t, map[string]any{"type": "string", "pattern": "^a", "enum": []any{"a"}}, out)
+
+	require.Len(t, warnings, 1)
+	assert.Equal(t, ProviderGoogle, warnings[0].Provider)
+	assert.Equal(t, "lookup", warnings[0].Tool)
+	assert.Equal(t, `google does not support the tool "lookup" constraints /: not {"type":"null"}`, warnings[0].String())
+
+	opts.DowngradeSchema(ProviderGoogle, "", map[string]any{"type": "string"}, schema.Gemini)
+	assert.Len(t, warnings, 1, "nothing dropped, nothing reported")
+}
@@ -0,0 +1,129 @@
+package gains
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaAddress struct {
+	City string `json:"city" required:"true"`
+}
+
+type schemaAudit struct {
+	CreatedAt time.Time `json:"created_at" desc:"Creation time"`
+	Name      string    `json:"name" desc:"Shadowed by the outer field"`
+}
+
+type schemaCircle struct {
+	Kind   string  `json:"kind" enum:"circle" required:"true"`
+	Radius float64 `json:"radius" required:"true"`
+}
+
+type schemaSquare struct {
+	Kind string  `json:"kind" enum:"square" required:"true"`
+	Side float64 `json:"side" required:"true"`
+}
+
+type schemaShape interface{}
+
+type schemaNode struct {
+	Name     string       `json:"name"`
+	Children []schemaNode `json:"children"`
+}
+
+type schemaArgs struct {
+	schemaAudit
+	Name     string                   `json:"name" desc:"Full name" required:"true"`
+	Nickname *string                  `json:"nickname" required:"true"`
+	Address  schemaAddress            `json:"address"`
+	Previous []*schemaAddress         `json:"previous" maxItems:"3"`
+	Scores   map[string]int           `json:"scores"`
+	Shape    schemaShape              `json:"shape" oneof:"circle,square"`
+	Shapes   []schemaShape            `json:"shapes" oneof:"circle,missing"`
+	Tree     schemaNode               `json:"tree"`
+	Labels   map[string]schemaAddress `json:"labels"`
+}
+
+func TestSchemaFor(t *testing.T) {
+	RegisterVariant("circle", schemaCircle{})
+	RegisterVariant("square", schemaSquare{})
+
+	data, err := SchemaFor[schemaArgs]()
+	require.NoError(t, err)
+
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(data, &schema))
+	props := schema["properties"].(map[string]any)
+	prop := func(name string) map[string]any { return props[name].(map[string]any) }
+
+	t.Run("pointers are optional", func(t *testing.T) {
+		assert.Equal(t, []any{"name"}, schema["required"])
+		assert.Equal(t, "string", prop("nickname")["type"])
+	})
+
+	t.Run("nested structs", func(t *testing.T) {
+		assert.Equal(t, "object", prop("address")["type"])
+		assert.Equal(t, []any{"city"}, prop("address")["required"])
+
+		previous := prop("previous")
+		assert.Equal(t, "array", previous["type"])
+		assert.Equal(t, float64(3), previous["maxItems"])
+		assert.Equal(t, "object", previous["items"].(map[string]any)["type"])
+	})
+
+	t.Run("maps", func(t *testing.T) {
+		assert.Equal(t, map[string]any{"type": "integer"}, prop("scores")["additionalProperties"])
+		labels := prop("labels")["additionalProperties"].(map[string]any)
+		assert.Equal(t, []any{"city"}, labels["required"])
+	})
+
+	t.Run("embedded structs are promoted", func(t *testing.T) {
+		assert.Equal(t, map[string]any{"type": "string", "format": "date-time", "description": "Creation time"}, prop("created_at"))
+		assert.Equal(t, "Full name", prop("name")["description"], "outer fields win over promoted ones")
+		assert.NotContains(t, props, "schemaAudit")
+	})
+
+	t.Run("oneof unions", func(t *testing.T) {
+		anyOf := prop("shape")["anyOf"].([]any)
+		require.Len(t, anyOf, 2)
+		circle := anyOf[0].(map[string]any)
+		assert.Contains(t, circle["properties"], "radius")
+		square := anyOf[1].(map[string]any)
+		assert.Contains(t, square["properties"], "side")
+
+		shapes := prop("shapes")
+		assert.Equal(t, "array", shapes["type"])
+		assert.Len(t, shapes["items"].(map[string]any)["anyOf"], 1, "unregistered variants are skipped")
+	})
+
+	t.Run("recursive types", func(t *testing.T) {
+		assert.Equal(t, map[string]any{"$ref": "#/$defs/schemaNode"}, prop("tree"))
+		node := schema["$defs"].(map[string]any)["schemaNode"].(map[string]any)
+		children := node["properties"].(map[string]any)["children"].(map[string]any)
+		assert.Equal(t, map[string]any{"$ref": "#/$defs/schemaNode"}, children["items"])
+	})
+}
+
+func TestSchemaFor_RecursiveRoot(t *testing.T) {
+	data, err := SchemaFor[schemaNode]()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"children": {"type": "array", "items": {"$ref": "#"}}
+		}
+	}`, string(data))
+
+	assert.NoError(t, ValidateJSON(data, []byte(`{"name":"a","children":[{"name":"b","children":[]}]}`)))
+	assert.ErrorContains(t, ValidateJSON(data, []byte(`{"children":[{"name":1}]}`)), "$.children[0].name: expected string")
+}
+
+func TestSchemaFor_NotStruct(t *testing.T) {
+	_, err := SchemaFor[string]()
+	assert.Error(t, err)
+}
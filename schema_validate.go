@@ -0,0 +1,39 @@
+package gains
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/spetersoncode/gains/schema"
+)
+
+// RepairJSON extracts the JSON value from a model response that wraps it in
+// other text, such as a Markdown code fence or a sentence before or after
+// the JSON. It returns content trimmed of surrounding whitespace if it is
+// already valid JSON or contains no JSON object or array.
+func RepairJSON(content string) string {
+	trimmed := strings.TrimSpace(content)
+	if json.Valid([]byte(trimmed)) {
+		return trimmed
+	}
+
+	for i, r := range trimmed {
+		if r != '{' && r != '[' {
+			continue
+		}
+		dec := json.NewDecoder(strings.NewReader(trimmed[i:]))
+		var value json.RawMessage
+		if err := dec.Decode(&value); err == nil {
+			return string(value)
+		}
+	}
+	return trimmed
+}
+
+// ValidateJSON checks data against a JSON schema using schema.Validate,
+// which supports the keywords used for structured output and tool
+// parameters. All violations are reported, each prefixed with the JSON path
+// of the offending value.
+func ValidateJSON(s json.RawMessage, data []byte) error {
+	return schema.Validate(s, data)
+}
@@ -0,0 +1,74 @@
+package gains
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepairJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"valid JSON", ` {"a":1} `, `{"a":1}`},
+		{"code fence", "```json\n{\"a\": 1}\n```", `{"a": 1}`},
+		{"surrounding text", `Here you go: {"a": {"b": "}"}} Hope that helps!`, `{"a": {"b": "}"}}`},
+		{"array", `Result: [1, 2]`, `[1, 2]`},
+		{"skips invalid candidates", `{oops} then {"a": true}`, `{"a": true}`},
+		{"no JSON", `no json here`, `no json here`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, RepairJSON(tt.content))
+		})
+	}
+}
+
+func TestValidateJSON(t *testing.T) {
+	schema := json.RawMessage(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"age": {"type": "integer"},
+			"role": {"type": "string", "enum": ["admin", "user"]},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"nickname": {"type": ["string", "null"]}
+		},
+		"required": ["name", "age"],
+		"additionalProperties": false
+	}`)
+
+	t.Run("valid", func(t *testing.T) {
+		err := ValidateJSON(schema, []byte(`{"name":"Ada","age":36,"role":"admin","tags":["x"],"nickname":null}`))
+		assert.NoError(t, err)
+	})
+
+	t.Run("reports every violation with its path", func(t *testing.T) {
+		err := ValidateJSON(schema, []byte(`{"age":36.5,"role":"owner","tags":["x",2],"extra":1}`))
+		require.Error(t, err)
+		msg := err.Error()
+		assert.Contains(t, msg, `$: missing required property "name"`)
+		assert.Contains(t, msg, `$.age: expected integer, got number`)
+		assert.Contains(t, msg, `$.role: value "owner" is not one of the allowed values`)
+		assert.Contains(t, msg, `$.tags[1]: expected string, got number`)
+		assert.Contains(t, msg, `$: unexpected property "extra"`)
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		assert.ErrorContains(t, ValidateJSON(schema, []byte(`{"name":`)), "invalid JSON")
+		assert.ErrorContains(t, ValidateJSON(schema, []byte(`{} trailing`)), "invalid JSON")
+	})
+
+	t.Run("invalid schema", func(t *testing.T) {
+		assert.ErrorContains(t, ValidateJSON(json.RawMessage(`nope`), []byte(`{}`)), "invalid schema")
+	})
+}
+
+func TestWithSchemaRetry(t *testing.T) {
+	assert.Zero(t, ApplyOptions().SchemaRetries)
+	assert.Equal(t, 2, ApplyOptions(WithSchemaRetry(2)).SchemaRetries)
+}
@@ -0,0 +1,49 @@
+// Package serve exposes gains agents and chat clients as an OpenAI-compatible
+// chat completions API, so tooling built for OpenAI clients, such as chat
+// frontends and observability proxies, can talk to them unchanged.
+//
+// A [Backend] answers a conversation with a stream of gains events. [Agent]
+// runs an agent, [Client] a chat client such as client.Client, and
+// [Provider] a bare ai.ChatProvider:
+//
+//	h := serve.NewHandler(serve.Agent(a, agent.WithMaxSteps(10)),
+//	    serve.WithModels(map[string]ai.Model{"assistant": model.ClaudeSonnet45}),
+//	    serve.WithAPIKeys(os.Getenv("SERVE_API_KEY")),
+//	)
+//	http.Handle("/v1/", h)
+//
+// Point any OpenAI client at the server's /v1 base URL:
+//
+//	curl localhost:8080/v1/chat/completions -H "Authorization: Bearer $SERVE_API_KEY" \
+//	    -d '{"model": "assistant", "messages": [{"role": "user", "content": "Hi"}]}'
+//
+// # Requests
+//
+// Messages of every role are converted with [ToGainsMessages], including
+// image parts and the tool calls and results of earlier turns. The model,
+// temperature, and max_tokens (or max_completion_tokens) become chat
+// options; other request fields are ignored. Tools offered in the request
+// are not supported: an agent runs the tools of its own registry.
+//
+// # Streaming
+//
+// With "stream": true, the response is an SSE stream of chat.completion.chunk
+// objects ending with data: [DONE]. Message deltas are sent as they arrive.
+// Tool calls of the final response, the finish reason, and, with
+// stream_options.include_usage, the usage follow in the last chunks. A
+// stream that fails ends with an error object instead of [DONE].
+//
+// # Agents
+//
+// An agent's completion holds the text of every step, such as "Let me look
+// that up" before a tool call, with a blank line between steps. Streamed and
+// non-streamed requests return the same content. The agent runs its own
+// tools, so completions never carry tool calls, even when the run stops at
+// its step limit or at a client tool call.
+//
+// # Errors
+//
+// Errors use the OpenAI error body. Backend errors carrying an HTTP status,
+// such as an ai.Error from a provider, keep it; others are 500 Internal
+// Server Error.
+package serve
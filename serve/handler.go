@@ -0,0 +1,444 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/agent"
+	"github.com/spetersoncode/gains/chat"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/internal/apikey"
+	"github.com/spetersoncode/gains/sse"
+)
+
+// DefaultModelID is the model ID listed by a Handler without WithModels.
+const DefaultModelID = "gains"
+
+// Backend answers a conversation with a stream of gains events, such as a
+// chat client's or an agent's stream. Deltas are streamed to the caller as
+// content, and the response of the final RunEnd event is the completion.
+type Backend interface {
+	Stream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error)
+}
+
+// BackendFunc adapts a function to a Backend.
+type BackendFunc func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error)
+
+// Stream calls f.
+func (f BackendFunc) Stream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+	return f(ctx, messages, opts...)
+}
+
+// AgentRunner is the interface required from gains agents.
+type AgentRunner interface {
+	RunStream(ctx context.Context, messages []ai.Message, opts ...agent.Option) <-chan event.Event
+}
+
+// Agent returns a Backend running a with opts. The request's chat options
+// are applied to every step with agent.WithChatOptions. The agent runs its
+// own tools; only its messages reach the caller. The completion's content
+// is the text of every step, separated by blank lines, both when streamed
+// and not, and it never carries tool calls.
+func Agent(a AgentRunner, opts ...agent.Option) Backend {
+	return BackendFunc(func(ctx context.Context, messages []ai.Message, chatOpts ...ai.Option) (<-chan event.Event, error) {
+		runOpts := append(slices.Clip(opts), agent.WithChatOptions(chatOpts...))
+		return agentMessages(ctx, a.RunStream(ctx, messages, runOpts...)), nil
+	})
+}
+
+// agentMessages reduces an agent's stream to the deltas of its messages,
+// separating messages with a blank line, and the final RunEnd or RunError.
+// The RunEnd response holds the content of every message instead of the
+// last step's, and drops the tool calls the agent ran or stopped at.
+func agentMessages(ctx context.Context, stream <-chan event.Event) <-chan event.Event {
+	ch := make(chan event.Event)
+	go func() {
+		defer close(ch)
+		defer func() {
+			for range stream {
+			}
+		}()
+		send := func(e event.Event) bool {
+			select {
+			case ch <- e:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		var content strings.Builder
+		var messageID string
+		for ev := range stream {
+			switch ev.Type {
+			case event.MessageDelta:
+				if ev.Delta == "" {
+					continue
+				}
+				delta := ev.Delta
+				if ev.MessageID != messageID {
+					messageID = ev.MessageID
+					if content.Len() > 0 {
+						delta = "\n\n" + delta
+					}
+				}
+				content.WriteString(delta)
+				if !send(event.Event{Type: event.MessageDelta, Delta: delta}) {
+					return
+				}
+
+			case event.RunEnd:
+				resp := &ai.Response{}
+				if ev.Response != nil {
+					*resp = *ev.Response
+				}
+				resp.Content = content.String()
+				if len(resp.ToolCalls) > 0 || finishReason(resp) == "tool_calls" {
+					resp.ToolCalls, resp.FinishReason = nil, ""
+				}
+				send(event.Event{Type: event.RunEnd, Response: resp})
+				return
+
+			case event.RunError:
+				send(ev)
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// Client returns a Backend streaming from c, such as a client.Client.
+func Client(c chat.Client) Backend {
+	return BackendFunc(c.ChatStream)
+}
+
+// Provider returns a Backend streaming from a bare chat provider.
+func Provider(p ai.ChatProvider) Backend {
+	return BackendFunc(func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+		stream, err := p.ChatStream(ctx, messages, opts...)
+		if err != nil {
+			return nil, err
+		}
+		ch := make(chan event.Event)
+		go func() {
+			defer close(ch)
+			defer func() {
+				for range stream {
+				}
+			}()
+			send := func(e event.Event) bool {
+				select {
+				case ch <- e:
+					return true
+				case <-ctx.Done():
+					return false
+				}
+			}
+			for ev := range stream {
+				if ev.Err != nil {
+					send(event.Event{Type: event.RunError, Error: ev.Err})
+					return
+				}
+				if ev.Delta != "" && !send(event.Event{Type: event.MessageDelta, Delta: ev.Delta}) {
+					return
+				}
+				if ev.Done {
+					send(event.Event{Type: event.RunEnd, Response: ev.Response})
+					return
+				}
+			}
+		}()
+		return ch, nil
+	})
+}
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithModels sets the models callers can choose by ID. A request for one
+// of them runs with ai.WithModel, and a request for another model fails
+// with 404 Not Found. The IDs are listed by GET /v1/models. Without it, the
+// backend's default model answers every request, whatever model it names.
+func WithModels(models map[string]ai.Model) Option {
+	return func(h *Handler) {
+		h.models = models
+	}
+}
+
+// WithAPIKeys requires requests to carry one of keys as a bearer token, as
+// OpenAI clients send their API key. Empty keys are ignored. Without a
+// non-empty key, requests are not authenticated.
+func WithAPIKeys(keys ...string) Option {
+	return func(h *Handler) {
+		h.apiKeys.Add(keys...)
+	}
+}
+
+// WithChatOptions sets chat options applied to every request, before those
+// derived from the request itself.
+func WithChatOptions(opts ...ai.Option) Option {
+	return func(h *Handler) {
+		h.chatOpts = append(h.chatOpts, opts...)
+	}
+}
+
+// Handler serves a Backend as an OpenAI-compatible chat completions API:
+//
+//	POST /v1/chat/completions  - a completion, streamed as SSE when "stream" is set
+//	GET  /v1/models            - the models callers can choose
+//
+// Mount it at "/v1/". It is safe for concurrent use.
+type Handler struct {
+	backend  Backend
+	models   map[string]ai.Model
+	apiKeys  apikey.Keys
+	chatOpts []ai.Option
+	mux      *http.ServeMux
+}
+
+// NewHandler creates a Handler serving backend.
+func NewHandler(backend Backend, opts ...Option) *Handler {
+	h := &Handler{backend: backend, mux: http.NewServeMux()}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.mux.HandleFunc("POST /v1/chat/completions", h.serveChatCompletions)
+	h.mux.HandleFunc("GET /v1/models", h.serveModels)
+	return h
+}
+
+// ServeHTTP authenticates the request and routes it.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.apiKeys.Authorized(r) {
+		writeError(w, http.StatusUnauthorized, APIError{Message: "invalid API key", Type: "invalid_request_error", Code: "invalid_api_key"})
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// serveModels lists the models callers can choose.
+func (h *Handler) serveModels(w http.ResponseWriter, r *http.Request) {
+	ids := []string{DefaultModelID}
+	if len(h.models) > 0 {
+		ids = ids[:0]
+		for id := range h.models {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+	}
+	models := make([]Model, len(ids))
+	for i, id := range ids {
+		models[i] = Model{ID: id, Object: "model", OwnedBy: "gains"}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"object": "list", "data": models})
+}
+
+// serveChatCompletions answers a chat completions request.
+func (h *Handler) serveChatCompletions(w http.ResponseWriter, r *http.Request) {
+	var req ChatCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, invalidRequest("invalid request body: "+err.Error()))
+		return
+	}
+	messages, err := ToGainsMessages(req.Messages)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, invalidRequest(err.Error()))
+		return
+	}
+	if len(messages) == 0 {
+		writeError(w, http.StatusBadRequest, invalidRequest("messages are required"))
+		return
+	}
+	opts, apiErr := h.options(req)
+	if apiErr != nil {
+		writeError(w, http.StatusNotFound, *apiErr)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	stream, err := h.backend.Stream(ctx, messages, opts...)
+	if err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	defer func() {
+		// Stop the backend before draining a stream left early
+		cancel()
+		for range stream {
+		}
+	}()
+
+	c := completion{id: "chatcmpl-" + ai.NewID(), created: time.Now().Unix(), model: req.Model}
+	if c.model == "" {
+		c.model = DefaultModelID
+	}
+	if req.Stream {
+		includeUsage := req.StreamOptions != nil && req.StreamOptions.IncludeUsage
+		c.stream(ctx, w, stream, includeUsage)
+		return
+	}
+
+	resp, _, err := event.Accumulate(stream)
+	if err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, c.response(resp))
+}
+
+// options returns the chat options of req, or an error if it names an
+// unknown model.
+func (h *Handler) options(req ChatCompletionRequest) ([]ai.Option, *APIError) {
+	opts := slices.Clip(h.chatOpts)
+	if len(h.models) > 0 {
+		m, ok := h.models[req.Model]
+		if !ok {
+			return nil, &APIError{
+				Message: "the model `" + req.Model + "` does not exist",
+				Type:    "invalid_request_error",
+				Code:    "model_not_found",
+			}
+		}
+		opts = append(opts, ai.WithModel(m))
+	}
+	if req.Temperature != nil {
+		opts = append(opts, ai.WithTemperature(*req.Temperature))
+	}
+	if req.MaxCompletionTokens > 0 {
+		opts = append(opts, ai.WithMaxTokens(req.MaxCompletionTokens))
+	} else if req.MaxTokens > 0 {
+		opts = append(opts, ai.WithMaxTokens(req.MaxTokens))
+	}
+	return opts, nil
+}
+
+// completion holds the identity shared by a response's chunks.
+type completion struct {
+	id      string
+	created int64
+	model   string
+}
+
+// response returns resp as a non-streaming completion.
+func (c completion) response(resp *ai.Response) ChatCompletion {
+	msg := ChatMessage{Role: "assistant", Content: MessageContent{Text: resp.Content}}
+	if len(resp.ToolCalls) > 0 {
+		msg.ToolCalls = fromGainsToolCalls(resp.ToolCalls, false)
+	}
+	return ChatCompletion{
+		ID:      c.id,
+		Object:  "chat.completion",
+		Created: c.created,
+		Model:   c.model,
+		Choices: []ChatChoice{{Message: msg, FinishReason: finishReason(resp)}},
+		Usage:   fromGainsUsage(resp.Usage),
+	}
+}
+
+// chunk returns a streamed chunk changing the choice by delta.
+func (c completion) chunk(delta ChunkDelta, finish *string) ChatCompletionChunk {
+	return ChatCompletionChunk{
+		ID:      c.id,
+		Object:  "chat.completion.chunk",
+		Created: c.created,
+		Model:   c.model,
+		Choices: []ChunkChoice{{Delta: delta, FinishReason: finish}},
+	}
+}
+
+// stream writes the events of a backend stream as completion chunks,
+// ending with the finish reason, the usage if asked for, and [DONE]. A
+// stream that fails ends with an error object instead.
+func (c completion) stream(ctx context.Context, w http.ResponseWriter, stream <-chan event.Event, includeUsage bool) {
+	sw, err := sse.NewWriter(ctx, w)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, APIError{Message: err.Error(), Type: "server_error"})
+		return
+	}
+	defer sw.Close()
+
+	if sw.WriteJSON("", c.chunk(ChunkDelta{Role: "assistant"}, nil)) != nil {
+		return
+	}
+	acc := ai.NewStreamAccumulator()
+	for e := range stream {
+		if e.Type == event.MessageDelta && e.Delta != "" {
+			if sw.WriteJSON("", c.chunk(ChunkDelta{Content: e.Delta}, nil)) != nil {
+				return
+			}
+		}
+		if event.AddTo(acc, e) {
+			break
+		}
+	}
+
+	if err := acc.Err(); err != nil {
+		apiErr, _ := backendError(err)
+		sw.WriteJSON("", map[string]APIError{"error": apiErr})
+		return
+	}
+	resp := acc.Response()
+	if len(resp.ToolCalls) > 0 {
+		if sw.WriteJSON("", c.chunk(ChunkDelta{ToolCalls: fromGainsToolCalls(resp.ToolCalls, true)}, nil)) != nil {
+			return
+		}
+	}
+	finish := finishReason(resp)
+	if sw.WriteJSON("", c.chunk(ChunkDelta{}, &finish)) != nil {
+		return
+	}
+	if includeUsage {
+		usage := c.chunk(ChunkDelta{}, nil)
+		usage.Choices = []ChunkChoice{}
+		usage.Usage = fromGainsUsage(resp.Usage)
+		if sw.WriteJSON("", usage) != nil {
+			return
+		}
+	}
+	sw.WriteEvent("", []byte("[DONE]"))
+}
+
+// invalidRequest returns the error of a malformed request.
+func invalidRequest(msg string) APIError {
+	return APIError{Message: msg, Type: "invalid_request_error"}
+}
+
+// backendError returns the API error and HTTP status for a backend error.
+// Errors carrying a client or server HTTP status keep it.
+func backendError(err error) (APIError, int) {
+	status := http.StatusInternalServerError
+	var statusErr interface{ StatusCode() int }
+	if errors.As(err, &statusErr) && statusErr.StatusCode() >= 400 {
+		status = statusErr.StatusCode()
+	}
+	errType := "server_error"
+	if status < 500 {
+		errType = "invalid_request_error"
+	}
+	return APIError{Message: err.Error(), Type: errType}, status
+}
+
+// writeBackendError writes a backend error response.
+func writeBackendError(w http.ResponseWriter, err error) {
+	apiErr, status := backendError(err)
+	writeError(w, status, apiErr)
+}
+
+// writeError writes an error response.
+func writeError(w http.ResponseWriter, status int, apiErr APIError) {
+	writeJSON(w, status, map[string]APIError{"error": apiErr})
+}
+
+// writeJSON writes v as a JSON response with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,290 @@
+package serve
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/agent"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testModel is a model with an ID.
+type testModel string
+
+func (m testModel) String() string        { return string(m) }
+func (m testModel) Provider() ai.Provider { return ai.ProviderOpenAI }
+
+// streamProvider streams deltas followed by resp, or fails with err.
+type streamProvider struct {
+	deltas []string
+	resp   *ai.Response
+	err    error
+	opts   *ai.Options
+}
+
+func (p *streamProvider) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (p *streamProvider) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan ai.StreamEvent, error) {
+	p.opts = ai.ApplyOptions(opts...)
+	ch := make(chan ai.StreamEvent, len(p.deltas)+1)
+	for _, d := range p.deltas {
+		ch <- ai.StreamEvent{Delta: d}
+	}
+	if p.err != nil {
+		ch <- ai.StreamEvent{Err: p.err}
+	} else {
+		ch <- ai.StreamEvent{Done: true, Response: p.resp}
+	}
+	close(ch)
+	return ch, nil
+}
+
+// stepsChat answers an agent's steps with responses in turn, streaming each
+// one's content in two deltas.
+type stepsChat struct {
+	mu        sync.Mutex
+	responses []*ai.Response
+	calls     int
+}
+
+func (c *stepsChat) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	resp := c.responses[c.calls%len(c.responses)]
+	c.calls++
+	return resp, nil
+}
+
+func (c *stepsChat) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+	resp, _ := c.Chat(ctx, messages, opts...)
+	half := len(resp.Content) / 2
+	ch := make(chan event.Event, 4)
+	event.Emit(ch, event.Event{Type: event.MessageStart})
+	event.Emit(ch, event.Event{Type: event.MessageDelta, Delta: resp.Content[:half]})
+	event.Emit(ch, event.Event{Type: event.MessageDelta, Delta: resp.Content[half:]})
+	event.Emit(ch, event.Event{Type: event.MessageEnd, Response: resp})
+	close(ch)
+	return ch, nil
+}
+
+// newLookupAgent returns an agent that looks up the weather with a tool
+// before answering.
+func newLookupAgent() *agent.Agent {
+	registry := tool.NewRegistry()
+	registry.MustRegister(ai.Tool{Name: "lookup"}, func(ctx context.Context, call ai.ToolCall) (string, error) {
+		return "sunny", nil
+	})
+	return agent.New(&stepsChat{responses: []*ai.Response{
+		{
+			Content:      "Let me look that up.",
+			ToolCalls:    []ai.ToolCall{{ID: "call_1", Name: "lookup", Arguments: "{}"}},
+			FinishReason: "tool_use",
+		},
+		{Content: "It is sunny.", FinishReason: "end_turn", Usage: ai.Usage{InputTokens: 9, OutputTokens: 3}},
+	}}, registry)
+}
+
+// post sends a chat completions request with body to h.
+func post(h http.Handler, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(body)))
+	return rec
+}
+
+// chunks returns the data lines of an SSE response.
+func chunks(t *testing.T, body string) []string {
+	t.Helper()
+	var data []string
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		if line, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			data = append(data, line)
+		}
+	}
+	return data
+}
+
+func TestHandler_ChatCompletion(t *testing.T) {
+	p := &streamProvider{
+		deltas: []string{"Hel", "lo"},
+		resp:   &ai.Response{Content: "Hello", FinishReason: "end_turn", Usage: ai.Usage{InputTokens: 5, OutputTokens: 2}},
+	}
+	h := NewHandler(Provider(p))
+
+	rec := post(h, `{"model": "gpt-4o", "temperature": 0.2, "max_tokens": 50, "messages": [{"role": "user", "content": "Hi"}]}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp ChatCompletion
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "chat.completion", resp.Object)
+	assert.Equal(t, "gpt-4o", resp.Model)
+	assert.True(t, strings.HasPrefix(resp.ID, "chatcmpl-"))
+	require.Len(t, resp.Choices, 1)
+	assert.Equal(t, "assistant", resp.Choices[0].Message.Role)
+	assert.Equal(t, "Hello", resp.Choices[0].Message.Content.Text)
+	assert.Equal(t, "stop", resp.Choices[0].FinishReason)
+	assert.Equal(t, &ChatUsage{PromptTokens: 5, CompletionTokens: 2, TotalTokens: 7}, resp.Usage)
+
+	require.NotNil(t, p.opts.Temperature)
+	assert.Equal(t, 0.2, *p.opts.Temperature)
+	assert.Equal(t, 50, p.opts.MaxTokens)
+	assert.Nil(t, p.opts.Model, "without WithModels the backend's model answers")
+}
+
+func TestHandler_ChatCompletionStream(t *testing.T) {
+	p := &streamProvider{
+		deltas: []string{"Hel", "lo"},
+		resp: &ai.Response{
+			Content:   "Hello",
+			ToolCalls: []ai.ToolCall{{ID: "call_1", Name: "get_weather", Arguments: `{"location":"Paris"}`}},
+			Usage:     ai.Usage{InputTokens: 5, OutputTokens: 2},
+		},
+	}
+	h := NewHandler(Provider(p))
+
+	rec := post(h, `{"model": "m", "stream": true, "stream_options": {"include_usage": true}, "messages": [{"role": "user", "content": "Hi"}]}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+
+	data := chunks(t, rec.Body.String())
+	require.Len(t, data, 7)
+	assert.Equal(t, "[DONE]", data[6])
+
+	var parsed []ChatCompletionChunk
+	for _, d := range data[:6] {
+		var chunk ChatCompletionChunk
+		require.NoError(t, json.Unmarshal([]byte(d), &chunk), d)
+		assert.Equal(t, "chat.completion.chunk", chunk.Object)
+		parsed = append(parsed, chunk)
+	}
+	assert.Equal(t, "assistant", parsed[0].Choices[0].Delta.Role)
+	assert.Equal(t, "Hel", parsed[1].Choices[0].Delta.Content)
+	assert.Equal(t, "lo", parsed[2].Choices[0].Delta.Content)
+	require.Len(t, parsed[3].Choices[0].Delta.ToolCalls, 1)
+	call := parsed[3].Choices[0].Delta.ToolCalls[0]
+	assert.Equal(t, 0, *call.Index)
+	assert.Equal(t, "get_weather", call.Function.Name)
+	assert.Equal(t, "tool_calls", *parsed[4].Choices[0].FinishReason)
+	assert.Empty(t, parsed[5].Choices)
+	assert.Equal(t, 7, parsed[5].Usage.TotalTokens)
+	assert.Equal(t, parsed[0].ID, parsed[5].ID)
+}
+
+func TestHandler_StreamError(t *testing.T) {
+	h := NewHandler(Provider(&streamProvider{deltas: []string{"Hel"}, err: errors.New("overloaded")}))
+
+	rec := post(h, `{"stream": true, "messages": [{"role": "user", "content": "Hi"}]}`)
+	data := chunks(t, rec.Body.String())
+	require.NotEmpty(t, data)
+	last := data[len(data)-1]
+	assert.NotEqual(t, "[DONE]", last)
+	assert.Contains(t, last, `"error"`)
+	assert.Contains(t, last, "overloaded")
+}
+
+func TestHandler_Errors(t *testing.T) {
+	provider := &streamProvider{resp: &ai.Response{Content: "ok"}}
+
+	t.Run("unknown model", func(t *testing.T) {
+		h := NewHandler(Provider(provider), WithModels(map[string]ai.Model{"assistant": testModel("gpt-4o")}))
+		rec := post(h, `{"model": "other", "messages": [{"role": "user", "content": "Hi"}]}`)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "model_not_found")
+
+		rec = post(h, `{"model": "assistant", "messages": [{"role": "user", "content": "Hi"}]}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, testModel("gpt-4o"), provider.opts.Model)
+	})
+
+	t.Run("api keys", func(t *testing.T) {
+		h := NewHandler(Provider(provider), WithAPIKeys("secret"))
+		rec := post(h, `{"messages": [{"role": "user", "content": "Hi"}]}`)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(`{"messages": [{"role": "user", "content": "Hi"}]}`))
+		req.Header.Set("Authorization", "Bearer secret")
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		h := NewHandler(Provider(provider))
+		assert.Equal(t, http.StatusBadRequest, post(h, `not json`).Code)
+		assert.Equal(t, http.StatusBadRequest, post(h, `{"messages": []}`).Code)
+		assert.Equal(t, http.StatusBadRequest, post(h, `{"messages": [{"role": "narrator", "content": "Hi"}]}`).Code)
+	})
+
+	t.Run("backend status", func(t *testing.T) {
+		h := NewHandler(BackendFunc(func(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+			return nil, ai.NewTransientError("rate limited", http.StatusTooManyRequests, nil)
+		}))
+		rec := post(h, `{"messages": [{"role": "user", "content": "Hi"}]}`)
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Contains(t, rec.Body.String(), "rate limited")
+	})
+}
+
+// completionText returns the content and finish reason of a completion,
+// streamed or not, failing the test if it carries tool calls.
+func completionText(t *testing.T, rec *httptest.ResponseRecorder, stream bool) (content, finish string) {
+	t.Helper()
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	if !stream {
+		var resp ChatCompletion
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Len(t, resp.Choices, 1)
+		assert.Empty(t, resp.Choices[0].Message.ToolCalls, "the agent's tool calls stay internal")
+		return resp.Choices[0].Message.Content.Text, resp.Choices[0].FinishReason
+	}
+
+	data := chunks(t, rec.Body.String())
+	require.NotEmpty(t, data)
+	require.Equal(t, "[DONE]", data[len(data)-1])
+	var text strings.Builder
+	for _, d := range data[:len(data)-1] {
+		var chunk ChatCompletionChunk
+		require.NoError(t, json.Unmarshal([]byte(d), &chunk), d)
+		require.Len(t, chunk.Choices, 1)
+		assert.Empty(t, chunk.Choices[0].Delta.ToolCalls, "the agent's tool calls stay internal")
+		text.WriteString(chunk.Choices[0].Delta.Content)
+		if chunk.Choices[0].FinishReason != nil {
+			finish = *chunk.Choices[0].FinishReason
+		}
+	}
+	return text.String(), finish
+}
+
+func TestHandler_Agent(t *testing.T) {
+	for _, stream := range []bool{false, true} {
+		body := fmt.Sprintf(`{"stream": %t, "messages": [{"role": "user", "content": "Weather?"}]}`, stream)
+
+		t.Run(fmt.Sprintf("stream=%t", stream), func(t *testing.T) {
+			h := NewHandler(Agent(newLookupAgent()))
+			content, finish := completionText(t, post(h, body), stream)
+			assert.Equal(t, "Let me look that up.\n\nIt is sunny.", content, "every step's text in both modes")
+			assert.Equal(t, "stop", finish)
+		})
+
+		t.Run(fmt.Sprintf("stopped at a tool call stream=%t", stream), func(t *testing.T) {
+			h := NewHandler(Agent(newLookupAgent(), agent.WithMaxSteps(1)))
+			content, finish := completionText(t, post(h, body), stream)
+			assert.Equal(t, "Let me look that up.", content)
+			assert.Equal(t, "stop", finish)
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package serve
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// ChatCompletionRequest is the body of a chat completions request. Fields
+// of the OpenAI API not listed here are ignored.
+type ChatCompletionRequest struct {
+	Model               string         `json:"model"`
+	Messages            []ChatMessage  `json:"messages"`
+	Stream              bool           `json:"stream,omitempty"`
+	StreamOptions       *StreamOptions `json:"stream_options,omitempty"`
+	Temperature         *float64       `json:"temperature,omitempty"`
+	MaxTokens           int            `json:"max_tokens,omitempty"`
+	MaxCompletionTokens int            `json:"max_completion_tokens,omitempty"`
+	User                string         `json:"user,omitempty"`
+}
+
+// StreamOptions configures a streamed chat completion.
+type StreamOptions struct {
+	// IncludeUsage adds a final chunk with the usage of the request.
+	IncludeUsage bool `json:"include_usage,omitempty"`
+}
+
+// ChatMessage is a message of a chat completions request or response.
+type ChatMessage struct {
+	Role string `json:"role"`
+	// Content is a string, or in requests an array of content parts.
+	Content    MessageContent `json:"content"`
+	Name       string         `json:"name,omitempty"`
+	ToolCalls  []ChatToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+}
+
+// MessageContent is the content of a message: plain text, or the text and
+// image parts of a multimodal user message.
+type MessageContent struct {
+	Text  string
+	Parts []ContentPart
+}
+
+// ContentPart is a part of multimodal message content.
+type ContentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+// ImageURL is the image of an image_url content part: an http(s) URL or a
+// base64 data URL.
+type ImageURL struct {
+	URL string `json:"url"`
+}
+
+// UnmarshalJSON accepts a string, an array of content parts, or null.
+func (c *MessageContent) UnmarshalJSON(data []byte) error {
+	*c = MessageContent{}
+	switch {
+	case string(data) == "null":
+		return nil
+	case len(data) > 0 && data[0] == '"':
+		return json.Unmarshal(data, &c.Text)
+	default:
+		return json.Unmarshal(data, &c.Parts)
+	}
+}
+
+// MarshalJSON writes the content as its parts if it has any, and as a
+// string otherwise.
+func (c MessageContent) MarshalJSON() ([]byte, error) {
+	if c.Parts != nil {
+		return json.Marshal(c.Parts)
+	}
+	return json.Marshal(c.Text)
+}
+
+// ChatToolCall is a function call made by the model.
+type ChatToolCall struct {
+	// Index orders the calls of a streamed chunk.
+	Index    *int         `json:"index,omitempty"`
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function FunctionCall `json:"function"`
+}
+
+// FunctionCall names the function of a tool call and its JSON arguments.
+type FunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// ChatCompletion is the response to a non-streaming request.
+type ChatCompletion struct {
+	ID      string       `json:"id"`
+	Object  string       `json:"object"`
+	Created int64        `json:"created"`
+	Model   string       `json:"model"`
+	Choices []ChatChoice `json:"choices"`
+	Usage   *ChatUsage   `json:"usage,omitempty"`
+}
+
+// ChatChoice is a completion choice.
+type ChatChoice struct {
+	Index        int         `json:"index"`
+	Message      ChatMessage `json:"message"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+// ChatCompletionChunk is one event of a streamed response.
+type ChatCompletionChunk struct {
+	ID      string        `json:"id"`
+	Object  string        `json:"object"`
+	Created int64         `json:"created"`
+	Model   string        `json:"model"`
+	Choices []ChunkChoice `json:"choices"`
+	Usage   *ChatUsage    `json:"usage,omitempty"`
+}
+
+// ChunkChoice is the change to a choice carried by a chunk.
+type ChunkChoice struct {
+	Index        int        `json:"index"`
+	Delta        ChunkDelta `json:"delta"`
+	FinishReason *string    `json:"finish_reason"`
+}
+
+// ChunkDelta is the content added to a choice by a chunk.
+type ChunkDelta struct {
+	Role      string         `json:"role,omitempty"`
+	Content   string         `json:"content,omitempty"`
+	ToolCalls []ChatToolCall `json:"tool_calls,omitempty"`
+}
+
+// ChatUsage is the token usage of a request.
+type ChatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Model is an entry of the model list.
+type Model struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+}
+
+// APIError is the error body of a failed request.
+type APIError struct {
+	Message string `json:"message"`
+	Type    string `json:"type"`
+	Code    string `json:"code,omitempty"`
+}
+
+// ToGainsMessages converts the messages of a request to gains messages.
+// Consecutive tool messages become one tool message with a result for each.
+func ToGainsMessages(messages []ChatMessage) ([]ai.Message, error) {
+	var out []ai.Message
+	for i, m := range messages {
+		switch m.Role {
+		case "system", "developer":
+			out = append(out, ai.Message{Role: ai.RoleSystem, Content: m.Content.String()})
+		case "user":
+			msg := ai.Message{Role: ai.RoleUser, Name: m.Name}
+			if m.Content.Parts == nil {
+				msg.Content = m.Content.Text
+			} else {
+				parts, err := toGainsParts(m.Content.Parts)
+				if err != nil {
+					return nil, fmt.Errorf("message %d: %w", i, err)
+				}
+				msg.Parts = parts
+			}
+			out = append(out, msg)
+		case "assistant":
+			msg := ai.Message{Role: ai.RoleAssistant, Content: m.Content.String(), Name: m.Name}
+			for _, tc := range m.ToolCalls {
+				msg.ToolCalls = append(msg.ToolCalls, ai.ToolCall{ID: tc.ID, Name: tc.Function.Name, Arguments: tc.Function.Arguments})
+			}
+			out = append(out, msg)
+		case "tool":
+			result := ai.ToolResult{ToolCallID: m.ToolCallID, Content: m.Content.String()}
+			if n := len(out); n > 0 && out[n-1].Role == ai.RoleTool {
+				out[n-1].ToolResults = append(out[n-1].ToolResults, result)
+				continue
+			}
+			out = append(out, ai.Message{Role: ai.RoleTool, ToolResults: []ai.ToolResult{result}})
+		default:
+			return nil, fmt.Errorf("message %d: unsupported role %q", i, m.Role)
+		}
+	}
+	return out, nil
+}
+
+// String returns the text of the content, joining the text of its parts.
+func (c MessageContent) String() string {
+	if c.Parts == nil {
+		return c.Text
+	}
+	var b strings.Builder
+	for _, p := range c.Parts {
+		if p.Type == "text" {
+			b.WriteString(p.Text)
+		}
+	}
+	return b.String()
+}
+
+// toGainsParts converts request content parts to gains content parts.
+func toGainsParts(parts []ContentPart) ([]ai.ContentPart, error) {
+	out := make([]ai.ContentPart, 0, len(parts))
+	for _, p := range parts {
+		switch p.Type {
+		case "text":
+			out = append(out, ai.ContentPart{Type: ai.ContentPartTypeText, Text: p.Text})
+		case "image_url":
+			if p.ImageURL == nil || p.ImageURL.URL == "" {
+				return nil, fmt.Errorf("image_url part without a url")
+			}
+			out = append(out, imagePart(p.ImageURL.URL))
+		default:
+			return nil, fmt.Errorf("unsupported content part type %q", p.Type)
+		}
+	}
+	return out, nil
+}
+
+// imagePart converts an image URL, which may be a base64 data URL, to an
+// image content part.
+func imagePart(url string) ai.ContentPart {
+	if rest, ok := strings.CutPrefix(url, "data:"); ok {
+		if mimeType, data, ok := strings.Cut(rest, ";base64,"); ok {
+			return ai.ContentPart{Type: ai.ContentPartTypeImage, Base64: data, MimeType: mimeType}
+		}
+	}
+	return ai.ContentPart{Type: ai.ContentPartTypeImage, ImageURL: url}
+}
+
+// fromGainsToolCalls converts tool calls to their wire form. Streamed calls
+// are indexed.
+func fromGainsToolCalls(calls []ai.ToolCall, indexed bool) []ChatToolCall {
+	out := make([]ChatToolCall, len(calls))
+	for i, tc := range calls {
+		out[i] = ChatToolCall{
+			ID:       tc.ID,
+			Type:     "function",
+			Function: FunctionCall{Name: tc.Name, Arguments: tc.Arguments},
+		}
+		if indexed {
+			out[i].Index = &i
+		}
+	}
+	return out
+}
+
+// fromGainsUsage converts token usage to its wire form.
+func fromGainsUsage(u ai.Usage) *ChatUsage {
+	return &ChatUsage{
+		PromptTokens:     u.InputTokens,
+		CompletionTokens: u.OutputTokens,
+		TotalTokens:      u.InputTokens + u.OutputTokens,
+	}
+}
+
+// finishReason maps a provider's finish reason to the OpenAI one.
+func finishReason(resp *ai.Response) string {
+	switch strings.ToLower(resp.FinishReason) {
+	case "length", "max_tokens":
+		return "length"
+	case "content_filter", "safety", "refusal", "recitation":
+		return "content_filter"
+	case "tool_calls", "tool_use", "function_call":
+		return "tool_calls"
+	}
+	if len(resp.ToolCalls) > 0 {
+		return "tool_calls"
+	}
+	return "stop"
+}
@@ -0,0 +1,62 @@
+package serve
+
+import (
+	"encoding/json"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToGainsMessages(t *testing.T) {
+	var messages []ChatMessage
+	require.NoError(t, json.Unmarshal([]byte(`[
+		{"role": "system", "content": "Be brief."},
+		{"role": "user", "content": [
+			{"type": "text", "text": "What is this?"},
+			{"type": "image_url", "image_url": {"url": "data:image/png;base64,iVBOR"}},
+			{"type": "image_url", "image_url": {"url": "https://example.com/cat.jpg"}}
+		]},
+		{"role": "assistant", "content": null, "tool_calls": [
+			{"id": "call_1", "type": "function", "function": {"name": "lookup", "arguments": "{}"}},
+			{"id": "call_2", "type": "function", "function": {"name": "lookup", "arguments": "{}"}}
+		]},
+		{"role": "tool", "tool_call_id": "call_1", "content": "a cat"},
+		{"role": "tool", "tool_call_id": "call_2", "content": "a hat"}
+	]`), &messages))
+
+	got, err := ToGainsMessages(messages)
+	require.NoError(t, err)
+	require.Len(t, got, 4)
+
+	assert.Equal(t, ai.Message{Role: ai.RoleSystem, Content: "Be brief."}, got[0])
+	assert.Equal(t, []ai.ContentPart{
+		{Type: ai.ContentPartTypeText, Text: "What is this?"},
+		{Type: ai.ContentPartTypeImage, Base64: "iVBOR", MimeType: "image/png"},
+		{Type: ai.ContentPartTypeImage, ImageURL: "https://example.com/cat.jpg"},
+	}, got[1].Parts)
+	assert.Len(t, got[2].ToolCalls, 2)
+	assert.Equal(t, []ai.ToolResult{
+		{ToolCallID: "call_1", Content: "a cat"},
+		{ToolCallID: "call_2", Content: "a hat"},
+	}, got[3].ToolResults)
+
+	_, err = ToGainsMessages([]ChatMessage{{Role: "user", Content: MessageContent{Parts: []ContentPart{{Type: "input_audio"}}}}})
+	assert.ErrorContains(t, err, "input_audio")
+}
+
+func TestFinishReason(t *testing.T) {
+	for reason, want := range map[string]string{
+		"stop":       "stop",
+		"end_turn":   "stop",
+		"STOP":       "stop",
+		"max_tokens": "length",
+		"MAX_TOKENS": "length",
+		"tool_use":   "tool_calls",
+		"SAFETY":     "content_filter",
+	} {
+		assert.Equal(t, want, finishReason(&ai.Response{FinishReason: reason}), reason)
+	}
+	assert.Equal(t, "tool_calls", finishReason(&ai.Response{ToolCalls: []ai.ToolCall{{ID: "1"}}}))
+}
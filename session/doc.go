@@ -0,0 +1,77 @@
+// Package session provides persistent multi-turn conversations for gains.
+//
+// A [Session] combines a conversation history with an ID and metadata, and
+// writes itself to an [Adapter] after every change, so a conversation
+// survives process restarts. A [Manager] creates, lists, resumes, and deletes
+// sessions stored in an adapter.
+//
+// # Basic Usage
+//
+// Create a manager over an adapter, then start a session:
+//
+//	sessions := session.NewManager(adapter)
+//
+//	sess, err := sessions.Create(ctx, session.WithModel(model.ClaudeSonnet45))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	err = sess.Append(ctx, gains.Message{Role: gains.RoleUser, Content: "Hello"})
+//
+// # Resuming Sessions
+//
+// Look up a session by ID, or list the stored sessions, most recently
+// updated first:
+//
+//	sess, err := sessions.Resume(ctx, id)
+//	if errors.Is(err, session.ErrNotFound) {
+//	    // start a new session
+//	}
+//
+//	infos, err := sessions.List(ctx)
+//	for _, info := range infos {
+//	    fmt.Println(info.ID, info.UpdatedAt)
+//	}
+//
+// # Agents
+//
+// Run an agent against a session with agent.RunSession. Append the user's
+// message first; every message the agent produces is appended after it:
+//
+//	sess.Append(ctx, gains.Message{Role: gains.RoleUser, Content: input})
+//	result, err := a.RunSession(ctx, sess, agent.WithMaxSteps(5))
+//
+// # Concurrent Runs
+//
+// Two requests running an agent on the same session at once would
+// interleave their messages. Take the session's lock around each run:
+// TryLock fails with ErrBusy while another run holds it, suitable for
+// answering 409 Conflict, and Lock waits its turn instead. Resume the
+// session after locking, so the run starts from the latest history:
+//
+//	unlock, err := sessions.TryLock(ctx, id)
+//	if errors.Is(err, session.ErrBusy) {
+//	    http.Error(w, "conversation busy", http.StatusConflict)
+//	    return
+//	}
+//	defer unlock()
+//	sess, err := sessions.Resume(ctx, id)
+//
+// Locks expire after DefaultLockTTL, or the duration set with WithLockTTL,
+// so a crashed process cannot block a session forever. The store/redis
+// adapter shares locks between every instance using the same Redis; with
+// other adapters a lock is held by its Manager only.
+//
+// Without a lock, a session stored in an adapter with per-key versions,
+// such as store/redis, rejects a write if the session was changed since
+// this Session value read or wrote it, including by another Session value
+// in the same process. The error matches ErrConflict. Resume the session
+// and retry.
+//
+// # Storage
+//
+// Sessions are stored under keys prefixed with "session/", so one adapter
+// can hold sessions alongside other data. [NewMemoryAdapter] keeps sessions
+// in memory; the store/redis package provides a Redis adapter, and any
+// other backend can implement [Adapter].
+package session
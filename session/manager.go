@@ -0,0 +1,244 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/internal/store"
+)
+
+// ErrNotFound indicates no session exists with the requested ID.
+var ErrNotFound = errors.New("session: not found")
+
+// ErrNoTurn indicates a session has no assistant turn to drop.
+var ErrNoTurn = errors.New("session: no assistant turn")
+
+// ErrBusy indicates another run holds a session's lock.
+var ErrBusy = errors.New("session: busy")
+
+// DefaultLockTTL is how long a session lock is held if it is not released.
+const DefaultLockTTL = 10 * time.Minute
+
+// lockPollInterval is how often Lock retries a held lock.
+const lockPollInterval = 100 * time.Millisecond
+
+// Manager creates and resumes sessions stored in an adapter.
+type Manager struct {
+	adapter Adapter
+	locker  store.Locker
+	lockTTL time.Duration
+}
+
+// ManagerOption configures a Manager.
+type ManagerOption func(*Manager)
+
+// WithLockTTL sets how long a session lock is held if it is not released,
+// bounding how long a crashed run blocks its session. Default is
+// DefaultLockTTL.
+func WithLockTTL(ttl time.Duration) ManagerOption {
+	return func(m *Manager) {
+		m.lockTTL = ttl
+	}
+}
+
+// NewManager creates a Manager over the given adapter.
+// If adapter is nil, an in-memory adapter is used.
+func NewManager(adapter Adapter, opts ...ManagerOption) *Manager {
+	if adapter == nil {
+		adapter = NewMemoryAdapter()
+	}
+	m := &Manager{adapter: adapter, lockTTL: DefaultLockTTL}
+	if locker, ok := adapter.(store.Locker); ok {
+		m.locker = locker
+	} else {
+		m.locker = &store.LocalLocker{}
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// TryLock acquires the run lock of session id, so that only one run at a
+// time appends to its history. It returns a function releasing the lock,
+// or an error wrapping ErrBusy if another run holds it.
+//
+// Adapters that implement locks, such as the store/redis adapter, share
+// them with every process using the same backend. For other adapters the
+// lock is held by this Manager only.
+func (m *Manager) TryLock(ctx context.Context, id string) (unlock func(), err error) {
+	token, ok, err := m.locker.TryLock(ctx, lockKey(id), m.lockTTL)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrBusy, id)
+	}
+	return func() {
+		// Release even if the run's context was canceled; the lock would
+		// otherwise block the session until it expires.
+		m.locker.Unlock(context.WithoutCancel(ctx), lockKey(id), token)
+	}, nil
+}
+
+// Lock is like TryLock, but waits for the lock to be released, queuing the
+// caller behind the run holding it. It returns ctx's error if ctx is done
+// first.
+func (m *Manager) Lock(ctx context.Context, id string) (unlock func(), err error) {
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+	for {
+		unlock, err := m.TryLock(ctx, id)
+		if !errors.Is(err, ErrBusy) {
+			return unlock, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Option configures a new session.
+type Option func(*Metadata)
+
+// WithID sets the session ID instead of generating one.
+func WithID(id string) Option {
+	return func(m *Metadata) {
+		m.ID = id
+	}
+}
+
+// WithModel records the model the session starts with.
+func WithModel(model ai.Model) Option {
+	return func(m *Metadata) {
+		m.Model = model.String()
+	}
+}
+
+// WithLabel sets an application-defined label on the session.
+func WithLabel(key, value string) Option {
+	return func(m *Metadata) {
+		if m.Labels == nil {
+			m.Labels = make(map[string]string)
+		}
+		m.Labels[key] = value
+	}
+}
+
+// Create starts a new, empty session and persists it.
+func (m *Manager) Create(ctx context.Context, opts ...Option) (*Session, error) {
+	now := time.Now()
+	meta := Metadata{CreatedAt: now, UpdatedAt: now}
+	for _, opt := range opts {
+		opt(&meta)
+	}
+	if meta.ID == "" {
+		meta.ID = ai.NewID()
+	}
+
+	s := &Session{
+		meta:     meta,
+		messages: store.NewMessageStore(m.adapter),
+		adapter:  m.adapter,
+	}
+	if err := s.Sync(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Resume loads a stored session by ID. It returns an error wrapping
+// ErrNotFound if no such session exists.
+func (m *Manager) Resume(ctx context.Context, id string) (*Session, error) {
+	meta, version, err := m.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := store.NewMessageStore(m.adapter)
+	if err := messages.Reload(ctx, messagesKey(id)); err != nil && !errors.Is(err, store.ErrKeyNotFound) {
+		return nil, err
+	}
+	return &Session{meta: *meta, metaVersion: version, messages: messages, adapter: m.adapter}, nil
+}
+
+// Get returns the metadata of a stored session without loading its
+// messages. It returns an error wrapping ErrNotFound if no such session
+// exists.
+func (m *Manager) Get(ctx context.Context, id string) (*Metadata, error) {
+	meta, _, err := m.get(ctx, id)
+	return meta, err
+}
+
+// get returns the metadata of a stored session with its version, for
+// adapters that are store.Versioners.
+func (m *Manager) get(ctx context.Context, id string) (*Metadata, int64, error) {
+	raw, version, ok, err := store.GetVersion(ctx, m.adapter, metadataKey(id))
+	if err != nil {
+		return nil, 0, err
+	}
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: %q", ErrNotFound, id)
+	}
+	var meta Metadata
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return nil, 0, &store.SerializationError{Key: metadataKey(id), Err: err}
+	}
+	return &meta, version, nil
+}
+
+// List returns the metadata of all stored sessions, most recently updated
+// first.
+func (m *Manager) List(ctx context.Context) ([]Metadata, error) {
+	keys, err := m.adapter.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []Metadata
+	for _, key := range keys {
+		id, ok := sessionIDFromMetadataKey(key)
+		if !ok {
+			continue
+		}
+		meta, err := m.Get(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			// Deleted since Keys was called.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *meta)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].UpdatedAt.After(sessions[j].UpdatedAt)
+	})
+	return sessions, nil
+}
+
+// Delete removes a stored session. Deleting a missing session is not an
+// error.
+func (m *Manager) Delete(ctx context.Context, id string) error {
+	if err := m.adapter.Delete(ctx, metadataKey(id)); err != nil {
+		return err
+	}
+	return m.adapter.Delete(ctx, messagesKey(id))
+}
+
+func sessionIDFromMetadataKey(key string) (string, bool) {
+	if !strings.HasPrefix(key, keyPrefix) || !strings.HasSuffix(key, "/meta") {
+		return "", false
+	}
+	id := strings.TrimSuffix(strings.TrimPrefix(key, keyPrefix), "/meta")
+	return id, id != ""
+}
@@ -0,0 +1,165 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_Create(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(nil)
+
+	sess, err := m.Create(ctx, WithModel(model.ClaudeSonnet45), WithLabel("user", "u1"))
+	require.NoError(t, err)
+	assert.NotEmpty(t, sess.ID())
+	assert.Equal(t, 0, sess.Len())
+
+	meta := sess.Metadata()
+	assert.Equal(t, "claude-sonnet-4-5", meta.Model)
+	assert.Equal(t, "u1", meta.Labels["user"])
+	assert.False(t, meta.CreatedAt.IsZero())
+	assert.Equal(t, meta.CreatedAt, meta.UpdatedAt)
+
+	other, err := m.Create(ctx)
+	require.NoError(t, err)
+	assert.NotEqual(t, sess.ID(), other.ID())
+}
+
+func TestManager_CreateWithID(t *testing.T) {
+	sess, err := NewManager(nil).Create(context.Background(), WithID("chat-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "chat-1", sess.ID())
+}
+
+func TestManager_Resume(t *testing.T) {
+	ctx := context.Background()
+	adapter := NewMemoryAdapter()
+
+	sess, err := NewManager(adapter).Create(ctx, WithModel(model.ClaudeSonnet45))
+	require.NoError(t, err)
+	require.NoError(t, sess.Append(ctx, ai.Message{Role: ai.RoleUser, Content: "Remember me"}))
+
+	// A new manager over the same adapter, as after a restart.
+	resumed, err := NewManager(adapter).Resume(ctx, sess.ID())
+	require.NoError(t, err)
+	assert.Equal(t, sess.Messages(), resumed.Messages())
+	assert.Equal(t, sess.Metadata().Model, resumed.Metadata().Model)
+	assert.True(t, sess.Metadata().CreatedAt.Equal(resumed.Metadata().CreatedAt))
+
+	require.NoError(t, resumed.Append(ctx, ai.Message{Role: ai.RoleAssistant, Content: "I do"}))
+	again, err := NewManager(adapter).Resume(ctx, sess.ID())
+	require.NoError(t, err)
+	assert.Equal(t, 2, again.Len())
+}
+
+func TestManager_ResumeNotFound(t *testing.T) {
+	_, err := NewManager(nil).Resume(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestManager_List(t *testing.T) {
+	ctx := context.Background()
+	adapter := NewMemoryAdapter()
+	require.NoError(t, adapter.Set(ctx, "unrelated", []byte(`"value"`)))
+	m := NewManager(adapter)
+
+	first, err := m.Create(ctx, WithID("first"))
+	require.NoError(t, err)
+	_, err = m.Create(ctx, WithID("second"))
+	require.NoError(t, err)
+
+	time.Sleep(time.Millisecond)
+	require.NoError(t, first.Append(ctx, ai.Message{Role: ai.RoleUser, Content: "Hi"}))
+
+	sessions, err := m.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "first", sessions[0].ID)
+	assert.Equal(t, 1, sessions[0].MessageCount)
+	assert.Equal(t, "second", sessions[1].ID)
+}
+
+func TestManager_Delete(t *testing.T) {
+	ctx := context.Background()
+	adapter := NewMemoryAdapter()
+	m := NewManager(adapter)
+
+	sess, err := m.Create(ctx)
+	require.NoError(t, err)
+	require.NoError(t, sess.Append(ctx, ai.Message{Role: ai.RoleUser, Content: "Hi"}))
+
+	require.NoError(t, m.Delete(ctx, sess.ID()))
+	_, err = m.Resume(ctx, sess.ID())
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	n, err := adapter.Len(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	assert.NoError(t, m.Delete(ctx, "missing"))
+}
+
+func TestManager_TryLock(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(nil)
+
+	unlock, err := m.TryLock(ctx, "chat-1")
+	require.NoError(t, err)
+
+	_, err = m.TryLock(ctx, "chat-1")
+	assert.ErrorIs(t, err, ErrBusy)
+
+	other, err := m.TryLock(ctx, "chat-2")
+	require.NoError(t, err, "locks are per session")
+	other()
+
+	unlock()
+	again, err := m.TryLock(ctx, "chat-1")
+	require.NoError(t, err)
+	again()
+}
+
+func TestManager_LockExpires(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(nil, WithLockTTL(time.Millisecond))
+
+	_, err := m.TryLock(ctx, "chat-1")
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+
+	unlock, err := m.TryLock(ctx, "chat-1")
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestManager_Lock(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(nil)
+
+	unlock, err := m.TryLock(ctx, "chat-1")
+	require.NoError(t, err)
+
+	t.Run("waits for release", func(t *testing.T) {
+		time.AfterFunc(20*time.Millisecond, unlock)
+		next, err := m.Lock(ctx, "chat-1")
+		require.NoError(t, err)
+		next()
+	})
+
+	t.Run("gives up when ctx is done", func(t *testing.T) {
+		held, err := m.TryLock(ctx, "chat-1")
+		require.NoError(t, err)
+		defer held()
+
+		waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		_, err = m.Lock(waitCtx, "chat-1")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,176 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/internal/store"
+)
+
+// Adapter is the persistence backend sessions are stored in.
+// Implementations must be thread-safe.
+type Adapter = store.Adapter
+
+// ErrConflict indicates a write was rejected because another process
+// changed the same data since this one read it. Adapters with optimistic
+// concurrency, such as the store/redis adapter, return errors matching it;
+// resume the session and retry. It is the same error as store.ErrConflict.
+var ErrConflict = store.ErrConflict
+
+// NewMemoryAdapter creates an in-memory adapter. Sessions stored in it do
+// not survive restarts; use it for tests and short-lived processes.
+func NewMemoryAdapter() Adapter {
+	return store.NewMemoryAdapter()
+}
+
+// Metadata describes a stored session.
+type Metadata struct {
+	// ID uniquely identifies the session.
+	ID string `json:"id"`
+	// Model is the model most recently used in the session, if known.
+	Model string `json:"model,omitempty"`
+	// CreatedAt is when the session was created.
+	CreatedAt time.Time `json:"createdAt"`
+	// UpdatedAt is when the session was last changed.
+	UpdatedAt time.Time `json:"updatedAt"`
+	// MessageCount is the number of messages in the session.
+	MessageCount int `json:"messageCount"`
+	// Labels holds application-defined values, such as a title or user ID.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// Session is a conversation that persists itself to an adapter after every
+// change. Sessions are safe for concurrent use.
+type Session struct {
+	mu          sync.Mutex
+	meta        Metadata
+	metaVersion int64 // for adapters that are store.Versioners
+	messages    *store.MessageStore
+	adapter     Adapter
+}
+
+// ID returns the session ID.
+func (s *Session) ID() string {
+	return s.meta.ID
+}
+
+// Metadata returns a copy of the session metadata.
+func (s *Session) Metadata() Metadata {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.metadataLocked()
+}
+
+func (s *Session) metadataLocked() Metadata {
+	meta := s.meta
+	meta.MessageCount = s.messages.Len()
+	if s.meta.Labels != nil {
+		meta.Labels = make(map[string]string, len(s.meta.Labels))
+		for k, v := range s.meta.Labels {
+			meta.Labels[k] = v
+		}
+	}
+	return meta
+}
+
+// Messages returns a copy of the conversation history.
+func (s *Session) Messages() []ai.Message {
+	return s.messages.Messages()
+}
+
+// Len returns the number of messages in the session.
+func (s *Session) Len() int {
+	return s.messages.Len()
+}
+
+// Append adds messages to the session and persists it.
+func (s *Session) Append(ctx context.Context, msgs ...ai.Message) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages.Append(msgs...)
+	return s.touchLocked(ctx)
+}
+
+// DropLastTurn removes the last assistant turn, every message after the
+// last user message, and persists the session. It returns the removed
+// messages, or ErrNoTurn if no assistant turn follows a user message.
+func (s *Session) DropLastTurn(ctx context.Context) ([]ai.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	messages := s.messages.Messages()
+	start := ai.LastTurnStart(messages)
+	if start < 0 {
+		return nil, ErrNoTurn
+	}
+	s.messages.Truncate(start)
+	return messages[start:], s.touchLocked(ctx)
+}
+
+// SetModel records the model used in the session and persists it.
+func (s *Session) SetModel(ctx context.Context, model string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.meta.Model == model {
+		return nil
+	}
+	s.meta.Model = model
+	return s.touchLocked(ctx)
+}
+
+// SetLabel sets an application-defined label and persists the session.
+func (s *Session) SetLabel(ctx context.Context, key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.meta.Labels == nil {
+		s.meta.Labels = make(map[string]string)
+	}
+	s.meta.Labels[key] = value
+	return s.touchLocked(ctx)
+}
+
+// Sync persists the session to its adapter. Append, SetModel, and SetLabel
+// sync automatically; call Sync to retry after one of them fails.
+func (s *Session) Sync(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.syncLocked(ctx)
+}
+
+func (s *Session) touchLocked(ctx context.Context) error {
+	s.meta.UpdatedAt = time.Now()
+	return s.syncLocked(ctx)
+}
+
+// syncLocked writes messages before metadata, so a listed session always
+// has its messages stored.
+func (s *Session) syncLocked(ctx context.Context) error {
+	if err := s.messages.Sync(ctx, messagesKey(s.meta.ID)); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(s.metadataLocked())
+	if err != nil {
+		return &store.SerializationError{Key: metadataKey(s.meta.ID), Err: err}
+	}
+	v, ok := s.adapter.(store.Versioner)
+	if !ok {
+		return s.adapter.Set(ctx, metadataKey(s.meta.ID), raw)
+	}
+	version, err := v.SetVersion(ctx, metadataKey(s.meta.ID), raw, s.metaVersion)
+	if err != nil {
+		return err
+	}
+	s.metaVersion = version
+	return nil
+}
+
+const keyPrefix = "session/"
+
+func metadataKey(id string) string { return keyPrefix + id + "/meta" }
+func messagesKey(id string) string { return keyPrefix + id + "/messages" }
+func lockKey(id string) string     { return keyPrefix + id + "/lock" }
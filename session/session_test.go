@@ -0,0 +1,101 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession_AppendSyncs(t *testing.T) {
+	ctx := context.Background()
+	adapter := NewMemoryAdapter()
+	sess, err := NewManager(adapter).Create(ctx)
+	require.NoError(t, err)
+	created := sess.Metadata().UpdatedAt
+
+	require.NoError(t, sess.Append(ctx,
+		ai.Message{Role: ai.RoleUser, Content: "Hello"},
+		ai.Message{Role: ai.RoleAssistant, Content: "Hi"},
+	))
+	assert.Equal(t, 2, sess.Len())
+
+	raw, ok, err := adapter.Get(ctx, messagesKey(sess.ID()))
+	require.NoError(t, err)
+	require.True(t, ok)
+	var stored []ai.Message
+	require.NoError(t, json.Unmarshal(raw, &stored))
+	assert.Equal(t, sess.Messages(), stored)
+
+	meta := sess.Metadata()
+	assert.Equal(t, 2, meta.MessageCount)
+	assert.False(t, meta.UpdatedAt.Before(created))
+}
+
+func TestSession_DropLastTurn(t *testing.T) {
+	ctx := context.Background()
+	adapter := NewMemoryAdapter()
+	sess, err := NewManager(adapter).Create(ctx)
+	require.NoError(t, err)
+
+	_, err = sess.DropLastTurn(ctx)
+	assert.ErrorIs(t, err, ErrNoTurn)
+
+	require.NoError(t, sess.Append(ctx,
+		ai.Message{Role: ai.RoleUser, Content: "Weather?"},
+		ai.Message{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{ID: "c1", Name: "weather"}}},
+		ai.Message{Role: ai.RoleTool, ToolResults: []ai.ToolResult{{ToolCallID: "c1", Content: "sunny"}}},
+		ai.Message{Role: ai.RoleAssistant, Content: "Sunny."},
+	))
+
+	dropped, err := sess.DropLastTurn(ctx)
+	require.NoError(t, err)
+	assert.Len(t, dropped, 3)
+	assert.Equal(t, "Sunny.", dropped[2].Content)
+	assert.Equal(t, []ai.Message{{Role: ai.RoleUser, Content: "Weather?"}}, sess.Messages())
+
+	resumed, err := NewManager(adapter).Resume(ctx, sess.ID())
+	require.NoError(t, err)
+	assert.Equal(t, 1, resumed.Len())
+
+	_, err = sess.DropLastTurn(ctx)
+	assert.ErrorIs(t, err, ErrNoTurn)
+}
+
+func TestSession_AppendNothing(t *testing.T) {
+	ctx := context.Background()
+	sess, err := NewManager(nil).Create(ctx)
+	require.NoError(t, err)
+	updated := sess.Metadata().UpdatedAt
+
+	require.NoError(t, sess.Append(ctx))
+	assert.Equal(t, updated, sess.Metadata().UpdatedAt)
+}
+
+func TestSession_SetModelAndLabel(t *testing.T) {
+	ctx := context.Background()
+	m := NewManager(nil)
+	sess, err := m.Create(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, sess.SetModel(ctx, "gpt-5"))
+	require.NoError(t, sess.SetLabel(ctx, "title", "Trip planning"))
+
+	meta, err := m.Get(ctx, sess.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-5", meta.Model)
+	assert.Equal(t, "Trip planning", meta.Labels["title"])
+}
+
+func TestSession_MetadataIsCopy(t *testing.T) {
+	ctx := context.Background()
+	sess, err := NewManager(nil).Create(ctx, WithLabel("title", "original"))
+	require.NoError(t, err)
+
+	meta := sess.Metadata()
+	meta.Labels["title"] = "modified"
+	assert.Equal(t, "original", sess.Metadata().Labels["title"])
+}
@@ -0,0 +1,31 @@
+// Package sse writes Server-Sent Events responses, for the AG-UI, A2A, and
+// other streaming endpoints served with gains.
+//
+// [NewWriter] sets the streaming headers and sends them, and each write is
+// flushed so events reach the client as they happen:
+//
+//	sw, err := sse.NewWriter(r.Context(), w, sse.WithHeartbeat(15*time.Second))
+//	if err != nil {
+//	    http.Error(w, err.Error(), http.StatusInternalServerError)
+//	    return
+//	}
+//	defer sw.Close()
+//	for ev := range events {
+//	    if err := sw.WriteJSON(string(ev.Type), ev); err != nil {
+//	        return // client gone
+//	    }
+//	}
+//
+// # Keepalive
+//
+// Proxies close connections that stay silent too long. [WithHeartbeat]
+// writes a comment line at an interval, which clients ignore, keeping an
+// idle stream open while a model thinks or a tool runs. [WithRetry] tells
+// clients how long to wait before reconnecting a dropped stream.
+//
+// # Cancellation
+//
+// Writes fail with the context's error once the request context is done,
+// so a producer stops as soon as the client disconnects. A Writer is safe
+// for concurrent use.
+package sse
@@ -0,0 +1,193 @@
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrStreamingUnsupported is returned by NewWriter for a ResponseWriter
+// that cannot flush.
+var ErrStreamingUnsupported = errors.New("sse: streaming not supported")
+
+// ErrClosed is returned by writes after Close.
+var ErrClosed = errors.New("sse: writer closed")
+
+// Event is a single Server-Sent Event. Empty fields are omitted.
+type Event struct {
+	// ID sets the client's last event ID, sent back when it reconnects.
+	ID string
+	// Event is the event name; clients treat an unnamed event as "message".
+	Event string
+	// Data is the payload. Multi-line data is split into one data line
+	// per line.
+	Data []byte
+	// Retry tells the client how long to wait before reconnecting.
+	Retry time.Duration
+}
+
+// Option configures a Writer.
+type Option func(*Writer)
+
+// WithHeartbeat writes a comment line every interval, so proxies keep an
+// idle stream open. Zero or less disables it, which is the default.
+func WithHeartbeat(interval time.Duration) Option {
+	return func(sw *Writer) {
+		sw.heartbeat = interval
+	}
+}
+
+// WithRetry sends the client a reconnection delay when the stream opens.
+func WithRetry(d time.Duration) Option {
+	return func(sw *Writer) {
+		sw.retry = d
+	}
+}
+
+// Writer writes Server-Sent Events to an HTTP response, flushing each.
+type Writer struct {
+	ctx       context.Context
+	w         io.Writer
+	flusher   http.Flusher
+	heartbeat time.Duration
+	retry     time.Duration
+
+	mu     sync.Mutex
+	closed bool
+	stop   chan struct{}
+}
+
+// NewWriter prepares w for streaming events until ctx is done, usually the
+// request context. It sets the event stream headers and sends them with
+// the retry hint, if any, so no other status can be written afterwards.
+func NewWriter(ctx context.Context, w http.ResponseWriter, opts ...Option) (*Writer, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, ErrStreamingUnsupported
+	}
+	sw := &Writer{ctx: ctx, w: w, flusher: flusher, stop: make(chan struct{})}
+	for _, opt := range opts {
+		opt(sw)
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	h.Set("X-Accel-Buffering", "no") // disable nginx response buffering
+	w.WriteHeader(http.StatusOK)
+
+	if sw.retry > 0 {
+		if err := sw.Write(Event{Retry: sw.retry}); err != nil {
+			return nil, err
+		}
+	} else {
+		flusher.Flush()
+	}
+
+	if sw.heartbeat > 0 {
+		go sw.beat()
+	}
+	return sw, nil
+}
+
+// beat writes heartbeat comments until the writer is closed or its
+// context is done.
+func (sw *Writer) beat() {
+	ticker := time.NewTicker(sw.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if sw.Comment("heartbeat") != nil {
+				return
+			}
+		case <-sw.stop:
+			return
+		case <-sw.ctx.Done():
+			return
+		}
+	}
+}
+
+// Write writes ev and flushes it.
+func (sw *Writer) Write(ev Event) error {
+	var b strings.Builder
+	if ev.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", singleLine(ev.ID))
+	}
+	if ev.Event != "" {
+		fmt.Fprintf(&b, "event: %s\n", singleLine(ev.Event))
+	}
+	if ev.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", ev.Retry.Milliseconds())
+	}
+	if ev.Data != nil {
+		for _, line := range strings.Split(string(ev.Data), "\n") {
+			fmt.Fprintf(&b, "data: %s\n", strings.TrimSuffix(line, "\r"))
+		}
+	}
+	b.WriteString("\n")
+	return sw.send(b.String())
+}
+
+// WriteEvent writes an event named event with data as its payload.
+func (sw *Writer) WriteEvent(event string, data []byte) error {
+	return sw.Write(Event{Event: event, Data: data})
+}
+
+// WriteJSON writes an event named event with v encoded as JSON. An empty
+// event name sends an unnamed event.
+func (sw *Writer) WriteJSON(event string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("sse: encode event: %w", err)
+	}
+	return sw.WriteEvent(event, data)
+}
+
+// Comment writes a comment line, which clients ignore.
+func (sw *Writer) Comment(text string) error {
+	return sw.send(": " + singleLine(text) + "\n\n")
+}
+
+// Close stops the heartbeat. Later writes return ErrClosed. It does not
+// end the response, which ends when the handler returns.
+func (sw *Writer) Close() error {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	if !sw.closed {
+		sw.closed = true
+		close(sw.stop)
+	}
+	return nil
+}
+
+// send writes raw event stream text and flushes it.
+func (sw *Writer) send(text string) error {
+	if err := sw.ctx.Err(); err != nil {
+		return err
+	}
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	if sw.closed {
+		return ErrClosed
+	}
+	if _, err := io.WriteString(sw.w, text); err != nil {
+		return fmt.Errorf("sse: write event: %w", err)
+	}
+	sw.flusher.Flush()
+	return nil
+}
+
+// singleLine replaces line breaks in a field value, which would otherwise
+// end the field.
+func singleLine(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+}
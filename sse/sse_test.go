@@ -0,0 +1,92 @@
+package sse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	w := httptest.NewRecorder()
+	sw, err := NewWriter(context.Background(), w, WithRetry(3*time.Second))
+	require.NoError(t, err)
+
+	require.NoError(t, sw.WriteEvent("RUN_STARTED", []byte(`{"type":"RUN_STARTED"}`)))
+	require.NoError(t, sw.WriteJSON("", map[string]int{"n": 1}))
+	require.NoError(t, sw.Write(Event{ID: "7", Event: "multi\nline", Data: []byte("a\nb")}))
+	require.NoError(t, sw.Comment("ping"))
+
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+	assert.True(t, w.Flushed)
+	assert.Equal(t, "retry: 3000\n\n"+
+		"event: RUN_STARTED\ndata: {\"type\":\"RUN_STARTED\"}\n\n"+
+		"data: {\"n\":1}\n\n"+
+		"id: 7\nevent: multi line\ndata: a\ndata: b\n\n"+
+		": ping\n\n", w.Body.String())
+
+	require.NoError(t, sw.Close())
+	assert.ErrorIs(t, sw.WriteEvent("late", nil), ErrClosed)
+}
+
+func TestWriter_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sw, err := NewWriter(ctx, httptest.NewRecorder())
+	require.NoError(t, err)
+
+	cancel()
+	assert.ErrorIs(t, sw.WriteEvent("message", []byte("x")), context.Canceled)
+}
+
+func TestWriter_Unsupported(t *testing.T) {
+	_, err := NewWriter(context.Background(), struct{ http.ResponseWriter }{httptest.NewRecorder()})
+	assert.ErrorIs(t, err, ErrStreamingUnsupported)
+}
+
+// lockedRecorder guards a ResponseRecorder written by the heartbeat.
+type lockedRecorder struct {
+	mu sync.Mutex
+	*httptest.ResponseRecorder
+}
+
+func (r *lockedRecorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ResponseRecorder.Write(p)
+}
+
+func (r *lockedRecorder) WriteString(s string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ResponseRecorder.WriteString(s)
+}
+
+func (r *lockedRecorder) Flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ResponseRecorder.Flush()
+}
+
+func (r *lockedRecorder) body() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.Body.String()
+}
+
+func TestWriter_Heartbeat(t *testing.T) {
+	w := &lockedRecorder{ResponseRecorder: httptest.NewRecorder()}
+	sw, err := NewWriter(context.Background(), w, WithHeartbeat(5*time.Millisecond))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(w.body(), ": heartbeat\n\n")
+	}, time.Second, 5*time.Millisecond)
+	sw.Close()
+}
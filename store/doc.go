@@ -0,0 +1,38 @@
+// Package store provides key-value state shared by several processes
+// through a persistence adapter.
+//
+// A [Store] caches state in memory. Sync writes it to the adapter, and
+// Reload reads it back. Every write records a revision number under
+// [RevisionKey]. When several servers share one adapter, for example
+// horizontally scaled AG-UI servers behind a load balancer, write with
+// UpdateCAS instead of Sync. UpdateCAS only writes if the revision is
+// unchanged since the last Reload. Otherwise it returns an error matching
+// [ErrConflict], and the caller reloads and retries:
+//
+//	s := store.New(redis.New(client))
+//	if err := s.Reload(ctx); err != nil {
+//	    return err
+//	}
+//	for {
+//	    err := s.UpdateCAS(ctx, func(data map[string]any) error {
+//	        data["status"] = "approved"
+//	        return nil
+//	    })
+//	    if !errors.Is(err, store.ErrConflict) {
+//	        return err
+//	    }
+//	    if err := s.Reload(ctx); err != nil {
+//	        return err
+//	    }
+//	}
+//
+// # Adapters
+//
+// [NewMemoryAdapter] keeps state in process memory. Use
+// github.com/spetersoncode/gains/store/redis to share state between
+// processes. Custom adapters implement [Adapter]. An adapter shared by
+// several processes should also implement [RevisionSaver], as the Redis
+// adapter does, so that the revision check and the write happen in one
+// atomic step; otherwise UpdateCAS reads the revision before saving, and a
+// concurrent writer can slip in between.
+package store
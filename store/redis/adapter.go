@@ -0,0 +1,414 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spetersoncode/gains/internal/store"
+)
+
+// Client executes a Redis command and returns its reply. Replies use the
+// conventional Go types: string or []byte for bulk strings, int64 for
+// integers, and []any for arrays. A nil reply must be returned as
+// (nil, nil), not as an error.
+type Client interface {
+	Do(ctx context.Context, args ...any) (any, error)
+}
+
+// ClientFunc adapts a function to the Client interface.
+type ClientFunc func(ctx context.Context, args ...any) (any, error)
+
+// Do calls f.
+func (f ClientFunc) Do(ctx context.Context, args ...any) (any, error) {
+	return f(ctx, args...)
+}
+
+// Pipeliner is optionally implemented by clients that can send several
+// commands in one round trip. Pipeline returns one reply per command, in
+// order; a per-command error may be returned as an error value in the
+// replies.
+type Pipeliner interface {
+	Pipeline(ctx context.Context, cmds [][]any) ([]any, error)
+}
+
+// DefaultPrefix is the key prefix used when none is configured.
+const DefaultPrefix = "gains:"
+
+// scanCount is the COUNT hint for SCAN when listing keys.
+const scanCount = 100
+
+// Adapter stores data in Redis. It implements the store adapter interface
+// used by session.Manager and is safe for concurrent use.
+type Adapter struct {
+	client Client
+	prefix string
+	ttl    time.Duration
+}
+
+// Option configures an Adapter.
+type Option func(*Adapter)
+
+// WithPrefix sets the prefix prepended to every key. Default is
+// DefaultPrefix.
+func WithPrefix(prefix string) Option {
+	return func(a *Adapter) {
+		a.prefix = prefix
+	}
+}
+
+// WithTTL expires keys ttl after their last write. Default is no expiry.
+func WithTTL(ttl time.Duration) Option {
+	return func(a *Adapter) {
+		a.ttl = ttl
+	}
+}
+
+// New creates an Adapter that stores data through client.
+func New(client Client, opts ...Option) *Adapter {
+	a := &Adapter{
+		client: client,
+		prefix: DefaultPrefix,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// Get retrieves a value by key.
+func (a *Adapter) Get(ctx context.Context, key string) (json.RawMessage, bool, error) {
+	value, _, ok, err := a.GetVersion(ctx, key)
+	return value, ok, err
+}
+
+// GetVersion retrieves a value by key with its version, which is 0 for a
+// missing key. Pass the version to SetVersion to write the key only if no
+// one else has since.
+func (a *Adapter) GetVersion(ctx context.Context, key string) (json.RawMessage, int64, bool, error) {
+	reply, err := a.client.Do(ctx, getCommand(a.prefix+key)...)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	return parseGet(reply)
+}
+
+// Set stores a value by key, replacing any value written concurrently. Use
+// SetVersion to detect concurrent writes.
+func (a *Adapter) Set(ctx context.Context, key string, value json.RawMessage) error {
+	_, err := a.set(ctx, key, value, -1)
+	return err
+}
+
+// SetVersion stores a value by key if its version still equals expected,
+// as returned by GetVersion or the previous SetVersion, and returns the new
+// version. Otherwise it writes nothing and fails with *ConflictError.
+func (a *Adapter) SetVersion(ctx context.Context, key string, value json.RawMessage, expected int64) (int64, error) {
+	return a.set(ctx, key, value, max(expected, 0))
+}
+
+// set writes key if its version equals expected, or unconditionally if
+// expected is -1.
+func (a *Adapter) set(ctx context.Context, key string, value json.RawMessage, expected int64) (int64, error) {
+	reply, err := a.client.Do(ctx, "EVAL", setScript, 1, a.prefix+key,
+		string(value), expected, a.ttl.Milliseconds())
+	if err != nil {
+		return 0, err
+	}
+	values, ok := reply.([]any)
+	if !ok || len(values) != 2 {
+		return 0, &ReplyError{Command: "EVAL", Reply: reply}
+	}
+	status, err1 := toInt64(values[0])
+	version, err2 := toInt64(values[1])
+	if err1 != nil || err2 != nil {
+		return 0, &ReplyError{Command: "EVAL", Reply: reply}
+	}
+	if status == 0 {
+		return 0, &ConflictError{Key: key, Expected: expected, Actual: version}
+	}
+	return version, nil
+}
+
+// Delete removes a key. No error if the key doesn't exist.
+func (a *Adapter) Delete(ctx context.Context, key string) error {
+	_, err := a.client.Do(ctx, "DEL", a.prefix+key)
+	return err
+}
+
+// Has returns true if the key exists.
+func (a *Adapter) Has(ctx context.Context, key string) (bool, error) {
+	reply, err := a.client.Do(ctx, "EXISTS", a.prefix+key)
+	if err != nil {
+		return false, err
+	}
+	n, err := toInt64(reply)
+	if err != nil {
+		return false, &ReplyError{Command: "EXISTS", Reply: reply}
+	}
+	return n > 0, nil
+}
+
+// Keys returns all keys under the adapter's prefix, without the prefix.
+func (a *Adapter) Keys(ctx context.Context) ([]string, error) {
+	var keys []string
+	cursor := "0"
+	for {
+		reply, err := a.client.Do(ctx, "SCAN", cursor, "MATCH", escapeGlob(a.prefix)+"*", "COUNT", scanCount)
+		if err != nil {
+			return nil, err
+		}
+		values, ok := reply.([]any)
+		if !ok || len(values) != 2 {
+			return nil, &ReplyError{Command: "SCAN", Reply: reply}
+		}
+		next, ok := toString(values[0])
+		batch, ok2 := values[1].([]any)
+		if !ok || !ok2 {
+			return nil, &ReplyError{Command: "SCAN", Reply: reply}
+		}
+		for _, v := range batch {
+			// With a prefix such as "", the pattern also matches lock keys.
+			if k, ok := toString(v); ok && !strings.HasPrefix(k, a.lockKey("")) {
+				keys = append(keys, strings.TrimPrefix(k, a.prefix))
+			}
+		}
+		if next == "0" {
+			return dedupe(keys), nil
+		}
+		cursor = next
+	}
+}
+
+// Len returns the number of stored keys.
+func (a *Adapter) Len(ctx context.Context) (int, error) {
+	keys, err := a.Keys(ctx)
+	return len(keys), err
+}
+
+// Clear removes all keys under the adapter's prefix.
+func (a *Adapter) Clear(ctx context.Context) error {
+	keys, err := a.Keys(ctx)
+	if err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		args := make([]any, 0, len(keys)+1)
+		args = append(args, "DEL")
+		for _, k := range keys {
+			args = append(args, a.prefix+k)
+		}
+		if _, err := a.client.Do(ctx, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load retrieves all data as a map.
+func (a *Adapter) Load(ctx context.Context) (map[string]json.RawMessage, error) {
+	keys, err := a.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	replies := make([]any, len(keys))
+	if p, ok := a.client.(Pipeliner); ok && len(keys) > 0 {
+		cmds := make([][]any, len(keys))
+		for i, k := range keys {
+			cmds[i] = getCommand(a.prefix + k)
+		}
+		if replies, err = p.Pipeline(ctx, cmds); err != nil {
+			return nil, err
+		}
+		if len(replies) != len(keys) {
+			return nil, &ReplyError{Command: "HMGET", Reply: replies}
+		}
+	} else {
+		for i, k := range keys {
+			if replies[i], err = a.client.Do(ctx, getCommand(a.prefix+k)...); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	data := make(map[string]json.RawMessage, len(keys))
+	for i, k := range keys {
+		if err, ok := replies[i].(error); ok {
+			return nil, err
+		}
+		value, _, found, err := parseGet(replies[i])
+		if err != nil {
+			return nil, err
+		}
+		// Keys that expired or were deleted since SCAN are skipped.
+		if found {
+			data[k] = value
+		}
+	}
+	return data, nil
+}
+
+// Save stores all data from a map, replacing existing data, in a single
+// atomic script. Like Set, it replaces data written concurrently.
+func (a *Adapter) Save(ctx context.Context, data map[string]json.RawMessage) error {
+	return a.save(ctx, data, "", 0)
+}
+
+// SaveRevision replaces all data like Save, in the same script that checks
+// the revision a store.Store keeps under store.RevisionKey, so
+// Store.UpdateCAS is atomic across every process sharing the Redis. It
+// fails with *store.ConflictError, writing nothing, if the stored revision
+// is not expected.
+func (a *Adapter) SaveRevision(ctx context.Context, data map[string]json.RawMessage, expected int64) error {
+	return a.save(ctx, data, store.RevisionKey, expected)
+}
+
+// save writes data and deletes every other key in one script. If check is
+// non-empty, nothing is written unless the JSON data of key check is the
+// revision expected, or the key is missing and expected is 0.
+func (a *Adapter) save(ctx context.Context, data map[string]json.RawMessage, check string, expected int64) error {
+	existing, err := a.Keys(ctx)
+	if err != nil {
+		return err
+	}
+
+	writes := make([]string, 0, len(data))
+	for k := range data {
+		writes = append(writes, k)
+	}
+	var deletes []string
+	for _, k := range existing {
+		if _, ok := data[k]; !ok {
+			deletes = append(deletes, k)
+		}
+	}
+	checkIndex := 0
+	if check != "" {
+		// A check key that is not written is deleted, as Save replaces all
+		// data.
+		checkIndex = slices.Index(slices.Concat(writes, deletes), check) + 1
+		if checkIndex == 0 {
+			deletes = append(deletes, check)
+			checkIndex = len(writes) + len(deletes)
+		}
+	}
+
+	args := make([]any, 0, 7+len(writes)*2+len(deletes))
+	args = append(args, "EVAL", saveScript, len(writes)+len(deletes))
+	for _, k := range writes {
+		args = append(args, a.prefix+k)
+	}
+	for _, k := range deletes {
+		args = append(args, a.prefix+k)
+	}
+	args = append(args, len(writes), a.ttl.Milliseconds(), checkIndex, expected)
+	for _, k := range writes {
+		args = append(args, string(data[k]))
+	}
+
+	reply, err := a.client.Do(ctx, args...)
+	if err != nil {
+		return err
+	}
+	if values, ok := reply.([]any); ok && len(values) == 2 {
+		status, err1 := toInt64(values[0])
+		actual, err2 := toInt64(values[1])
+		if err1 == nil && err2 == nil && status == 0 {
+			return &store.ConflictError{Expected: expected, Actual: actual}
+		}
+	} else if status, err := toInt64(reply); err == nil && status == 1 {
+		return nil
+	}
+	return &ReplyError{Command: "EVAL", Reply: reply}
+}
+
+// parseGet parses an HMGET data ver reply.
+func parseGet(reply any) (json.RawMessage, int64, bool, error) {
+	values, ok := reply.([]any)
+	if !ok || len(values) != 2 {
+		return nil, 0, false, &ReplyError{Command: "HMGET", Reply: reply}
+	}
+	if values[0] == nil {
+		return nil, 0, false, nil
+	}
+	data, ok := toString(values[0])
+	if !ok {
+		return nil, 0, false, &ReplyError{Command: "HMGET", Reply: reply}
+	}
+	var version int64
+	if values[1] != nil {
+		v, err := toInt64(values[1])
+		if err != nil {
+			return nil, 0, false, &ReplyError{Command: "HMGET", Reply: reply}
+		}
+		version = v
+	}
+	return json.RawMessage(data), version, true, nil
+}
+
+func getCommand(key string) []any {
+	return []any{"HMGET", key, "data", "ver"}
+}
+
+// toInt64 converts an integer or numeric string reply.
+func toInt64(v any) (int64, error) {
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case int:
+		return int64(n), nil
+	case string:
+		return strconv.ParseInt(n, 10, 64)
+	case []byte:
+		return strconv.ParseInt(string(n), 10, 64)
+	}
+	return 0, &ReplyError{Command: "integer", Reply: v}
+}
+
+// toString converts a bulk string reply.
+func toString(v any) (string, bool) {
+	switch s := v.(type) {
+	case string:
+		return s, true
+	case []byte:
+		return string(s), true
+	}
+	return "", false
+}
+
+// escapeGlob escapes glob metacharacters so a prefix matches literally in
+// SCAN MATCH patterns.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// dedupe removes duplicate keys, which SCAN may return.
+func dedupe(keys []string) []string {
+	seen := make(map[string]bool, len(keys))
+	result := keys[:0]
+	for _, k := range keys {
+		if !seen[k] {
+			seen[k] = true
+			result = append(result, k)
+		}
+	}
+	return result
+}
+
+var (
+	_ store.Adapter       = (*Adapter)(nil)
+	_ store.Versioner     = (*Adapter)(nil)
+	_ store.RevisionSaver = (*Adapter)(nil)
+)
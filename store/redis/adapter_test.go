@@ -0,0 +1,531 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/session"
+	"github.com/spetersoncode/gains/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis is an in-memory Redis supporting the commands and scripts the
+// adapter uses. Replies use go-redis conventions.
+type fakeRedis struct {
+	mu       sync.Mutex
+	hashes   map[string]*fakeHash
+	strings  map[string]string
+	commands []string
+}
+
+type fakeHash struct {
+	data  string
+	ver   int64
+	ttlMS int64
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{hashes: make(map[string]*fakeHash), strings: make(map[string]string)}
+}
+
+func (f *fakeRedis) Do(_ context.Context, args ...any) (any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s := make([]string, len(args))
+	for i, a := range args {
+		s[i] = fmt.Sprint(a)
+	}
+	f.commands = append(f.commands, s[0])
+
+	switch s[0] {
+	case "HMGET":
+		h, ok := f.hashes[s[1]]
+		if !ok {
+			return []any{nil, nil}, nil
+		}
+		return []any{h.data, strconv.FormatInt(h.ver, 10)}, nil
+	case "EXISTS":
+		_, ok := f.hashes[s[1]]
+		if ok {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case "DEL":
+		var n int64
+		for _, k := range s[1:] {
+			if _, ok := f.hashes[k]; ok {
+				delete(f.hashes, k)
+				n++
+			}
+		}
+		return n, nil
+	case "SCAN":
+		var keys []any
+		for k := range f.hashes {
+			if ok, _ := path.Match(s[3], k); ok {
+				keys = append(keys, k)
+			}
+		}
+		for k := range f.strings {
+			if ok, _ := path.Match(s[3], k); ok {
+				keys = append(keys, k)
+			}
+		}
+		return []any{"0", keys}, nil
+	case "SET":
+		// SET key value NX PX ms
+		if _, ok := f.strings[s[1]]; ok {
+			return nil, nil
+		}
+		f.strings[s[1]] = s[2]
+		return "OK", nil
+	case "EVAL":
+		return f.eval(s[1], s[2:])
+	}
+	return nil, fmt.Errorf("fake redis: unsupported command %s", s[0])
+}
+
+func (f *fakeRedis) eval(script string, rest []string) (any, error) {
+	numKeys, _ := strconv.Atoi(rest[0])
+	keys, argv := rest[1:1+numKeys], rest[1+numKeys:]
+	atoi := func(s string) int64 { n, _ := strconv.ParseInt(s, 10, 64); return n }
+	version := func(k string) int64 {
+		if h, ok := f.hashes[k]; ok {
+			return h.ver
+		}
+		return 0
+	}
+
+	switch script {
+	case setScript:
+		cur := version(keys[0])
+		if expected := atoi(argv[1]); expected >= 0 && cur != expected {
+			return []any{int64(0), cur}, nil
+		}
+		f.hashes[keys[0]] = &fakeHash{data: argv[0], ver: cur + 1, ttlMS: atoi(argv[2])}
+		return []any{int64(1), cur + 1}, nil
+	case saveScript:
+		n := int(atoi(argv[0]))
+		ttl := atoi(argv[1])
+		if check := atoi(argv[2]); check > 0 {
+			var cur int64
+			if h, ok := f.hashes[keys[check-1]]; ok {
+				cur = atoi(h.data)
+			}
+			if cur != atoi(argv[3]) {
+				return []any{int64(0), cur}, nil
+			}
+		}
+		for _, k := range keys[n:] {
+			delete(f.hashes, k)
+		}
+		for i := 0; i < n; i++ {
+			f.hashes[keys[i]] = &fakeHash{data: argv[4+i], ver: version(keys[i]) + 1, ttlMS: ttl}
+		}
+		return int64(1), nil
+	case refreshScript:
+		if f.strings[keys[0]] != argv[0] {
+			return int64(0), nil
+		}
+		return int64(1), nil
+	case unlockScript:
+		if f.strings[keys[0]] != argv[0] {
+			return int64(0), nil
+		}
+		delete(f.strings, keys[0])
+		return int64(1), nil
+	}
+	return nil, fmt.Errorf("fake redis: unknown script")
+}
+
+// pipelineRedis adds Pipeliner support to fakeRedis.
+type pipelineRedis struct {
+	*fakeRedis
+	pipelines int
+}
+
+func (p *pipelineRedis) Pipeline(ctx context.Context, cmds [][]any) ([]any, error) {
+	p.pipelines++
+	replies := make([]any, len(cmds))
+	for i, cmd := range cmds {
+		reply, err := p.Do(ctx, cmd...)
+		if err != nil {
+			replies[i] = err
+		} else {
+			replies[i] = reply
+		}
+	}
+	return replies, nil
+}
+
+func TestAdapter_SetGet(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeRedis()
+	a := New(fake, WithPrefix("app:"))
+
+	_, ok, err := a.Get(ctx, "missing")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, a.Set(ctx, "k", json.RawMessage(`{"n":1}`)))
+	value, ok, err := a.Get(ctx, "k")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.JSONEq(t, `{"n":1}`, string(value))
+
+	require.Contains(t, fake.hashes, "app:k")
+	assert.Equal(t, int64(1), fake.hashes["app:k"].ver)
+	assert.Zero(t, fake.hashes["app:k"].ttlMS)
+}
+
+func TestAdapter_TTL(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeRedis()
+	a := New(fake, WithTTL(time.Hour))
+
+	require.NoError(t, a.Set(ctx, "k", json.RawMessage(`1`)))
+	require.NoError(t, a.Save(ctx, map[string]json.RawMessage{"k2": json.RawMessage(`2`)}))
+
+	assert.Equal(t, time.Hour.Milliseconds(), fake.hashes[DefaultPrefix+"k2"].ttlMS)
+}
+
+func TestAdapter_KeysAreScopedToPrefix(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeRedis()
+	a := New(fake, WithPrefix("a:"))
+	b := New(fake, WithPrefix("b:"))
+
+	require.NoError(t, a.Set(ctx, "x", json.RawMessage(`1`)))
+	require.NoError(t, a.Set(ctx, "y", json.RawMessage(`2`)))
+	require.NoError(t, b.Set(ctx, "z", json.RawMessage(`3`)))
+
+	keys, err := a.Keys(ctx)
+	require.NoError(t, err)
+	sort.Strings(keys)
+	assert.Equal(t, []string{"x", "y"}, keys)
+
+	n, err := a.Len(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	has, err := a.Has(ctx, "z")
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	require.NoError(t, a.Delete(ctx, "x"))
+	has, err = a.Has(ctx, "x")
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	require.NoError(t, a.Clear(ctx))
+	n, err = a.Len(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	n, err = b.Len(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestAdapter_OptimisticConcurrency(t *testing.T) {
+	ctx := context.Background()
+	a := New(newFakeRedis())
+
+	// Two readers sharing one adapter read the same version.
+	_, first, _, err := a.GetVersion(ctx, "k")
+	require.NoError(t, err)
+	_, second, _, err := a.GetVersion(ctx, "k")
+	require.NoError(t, err)
+	assert.Zero(t, first, "missing keys are at version 0")
+
+	v, err := a.SetVersion(ctx, "k", json.RawMessage(`1`), first)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), v)
+
+	_, err = a.SetVersion(ctx, "k", json.RawMessage(`2`), second)
+	var conflict *ConflictError
+	require.ErrorAs(t, err, &conflict, "the second writer does not overwrite the first")
+	assert.Equal(t, "k", conflict.Key)
+	assert.Equal(t, int64(0), conflict.Expected)
+	assert.Equal(t, int64(1), conflict.Actual)
+	assert.Equal(t, `1`, mustGet(t, a, "k"))
+
+	// Rereading resolves the conflict.
+	_, second, _, err = a.GetVersion(ctx, "k")
+	require.NoError(t, err)
+	v, err = a.SetVersion(ctx, "k", json.RawMessage(`2`), second)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), v)
+
+	// Plain Set replaces whatever is stored.
+	require.NoError(t, a.Set(ctx, "k", json.RawMessage(`3`)))
+	assert.Equal(t, `3`, mustGet(t, a, "k"))
+}
+
+// mustGet returns the value a stores under key.
+func mustGet(t *testing.T, a *Adapter, key string) string {
+	t.Helper()
+	value, ok, err := a.Get(context.Background(), key)
+	require.NoError(t, err)
+	require.True(t, ok)
+	return string(value)
+}
+
+func TestAdapter_Save(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeRedis()
+	a := New(fake)
+
+	require.NoError(t, a.Set(ctx, "stale", json.RawMessage(`0`)))
+	require.NoError(t, a.Save(ctx, map[string]json.RawMessage{
+		"a": json.RawMessage(`1`),
+		"b": json.RawMessage(`2`),
+	}))
+
+	data, err := a.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]json.RawMessage{"a": json.RawMessage(`1`), "b": json.RawMessage(`2`)}, data)
+}
+
+func TestAdapter_LoadPipelined(t *testing.T) {
+	ctx := context.Background()
+	client := &pipelineRedis{fakeRedis: newFakeRedis()}
+	a := New(client)
+
+	require.NoError(t, a.Save(ctx, map[string]json.RawMessage{
+		"a": json.RawMessage(`1`),
+		"b": json.RawMessage(`2`),
+	}))
+	client.commands = nil
+
+	data, err := New(client).Load(ctx)
+	require.NoError(t, err)
+	assert.Len(t, data, 2)
+	assert.Equal(t, 1, client.pipelines)
+}
+
+func TestAdapter_Session(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeRedis()
+
+	sess, err := session.NewManager(New(fake)).Create(ctx)
+	require.NoError(t, err)
+	require.NoError(t, sess.Append(ctx, ai.Message{Role: ai.RoleUser, Content: "Hello"}))
+
+	// A new adapter, as after a restart.
+	resumed, err := session.NewManager(New(fake)).Resume(ctx, sess.ID())
+	require.NoError(t, err)
+	assert.Equal(t, sess.Messages(), resumed.Messages())
+	require.NoError(t, resumed.Append(ctx, ai.Message{Role: ai.RoleAssistant, Content: "Hi"}))
+
+	// The original session is now stale.
+	var conflict *ConflictError
+	assert.ErrorAs(t, sess.Append(ctx, ai.Message{Role: ai.RoleUser, Content: "Again"}), &conflict)
+}
+
+func TestAdapter_SessionSharedAdapter(t *testing.T) {
+	ctx := context.Background()
+	sessions := session.NewManager(New(newFakeRedis()))
+
+	sess, err := sessions.Create(ctx)
+	require.NoError(t, err)
+
+	// Two requests in one process resume the same session.
+	first, err := sessions.Resume(ctx, sess.ID())
+	require.NoError(t, err)
+	second, err := sessions.Resume(ctx, sess.ID())
+	require.NoError(t, err)
+
+	require.NoError(t, first.Append(ctx, ai.Message{Role: ai.RoleUser, Content: "first"}))
+	err = second.Append(ctx, ai.Message{Role: ai.RoleUser, Content: "second"})
+	assert.ErrorIs(t, err, session.ErrConflict)
+
+	stored, err := sessions.Resume(ctx, sess.ID())
+	require.NoError(t, err)
+	require.Len(t, stored.Messages(), 1)
+	assert.Equal(t, "first", stored.Messages()[0].Content)
+}
+
+func TestAdapter_Lock(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeRedis()
+	a, b := New(fake), New(fake)
+
+	token, ok, err := a.TryLock(ctx, "session/s1/lock", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Contains(t, fake.strings, "lock:gains:session/s1/lock")
+
+	_, ok, err = b.TryLock(ctx, "session/s1/lock", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "locks are shared between adapters")
+
+	require.NoError(t, b.Unlock(ctx, "session/s1/lock", "other-token"))
+	assert.Contains(t, fake.strings, "lock:gains:session/s1/lock", "only the holder can unlock")
+
+	held, err := b.Refresh(ctx, "session/s1/lock", "other-token", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, held, "only the holder can refresh")
+	held, err = a.Refresh(ctx, "session/s1/lock", token, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, held)
+
+	require.NoError(t, a.Unlock(ctx, "session/s1/lock", token))
+	_, ok, err = b.TryLock(ctx, "session/s1/lock", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	keys, err := a.Keys(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, keys, "locks are not data keys")
+}
+
+func TestAdapter_LocksWithoutPrefix(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeRedis()
+	a := New(fake, WithPrefix(""))
+
+	require.NoError(t, a.Set(ctx, "k", json.RawMessage(`1`)))
+	_, ok, err := a.TryLock(ctx, "k/lock", time.Minute)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	keys, err := a.Keys(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"k"}, keys, "locks are not data keys")
+
+	data, err := a.Load(ctx)
+	require.NoError(t, err)
+	assert.Len(t, data, 1)
+
+	require.NoError(t, a.Save(ctx, map[string]json.RawMessage{"k": json.RawMessage(`2`)}))
+	assert.Contains(t, fake.strings, "lock:k/lock", "Save keeps locks")
+}
+
+func TestAdapter_SessionLock(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeRedis()
+
+	unlock, err := session.NewManager(New(fake)).TryLock(ctx, "chat-1")
+	require.NoError(t, err)
+	defer unlock()
+
+	// Another instance sharing the same Redis.
+	_, err = session.NewManager(New(fake)).TryLock(ctx, "chat-1")
+	assert.ErrorIs(t, err, session.ErrBusy)
+}
+
+func TestAdapter_ConflictIsStoreConflict(t *testing.T) {
+	assert.ErrorIs(t, &ConflictError{Key: "k"}, store.ErrConflict)
+	assert.ErrorIs(t, &ConflictError{Key: "k"}, session.ErrConflict)
+}
+
+func TestAdapter_StoreUpdateCAS(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeRedis()
+
+	// Two servers sharing one Redis.
+	first := store.New(New(fake))
+	second := store.New(New(fake))
+	require.NoError(t, first.Reload(ctx))
+	require.NoError(t, second.Reload(ctx))
+
+	require.NoError(t, first.UpdateCAS(ctx, func(data map[string]any) error {
+		data["owner"] = "first"
+		return nil
+	}))
+
+	err := second.UpdateCAS(ctx, func(data map[string]any) error {
+		data["owner"] = "second"
+		return nil
+	})
+	assert.ErrorIs(t, err, store.ErrConflict)
+
+	require.NoError(t, second.Reload(ctx))
+	assert.Equal(t, "first", second.GetString("owner"))
+	require.NoError(t, second.UpdateCAS(ctx, func(data map[string]any) error {
+		data["owner"] = "second"
+		return nil
+	}))
+	assert.Equal(t, int64(2), second.Revision())
+}
+
+func TestAdapter_SaveRevision(t *testing.T) {
+	ctx := context.Background()
+	fake := newFakeRedis()
+	a := New(fake)
+
+	require.NoError(t, a.SaveRevision(ctx, map[string]json.RawMessage{
+		"a":               json.RawMessage(`1`),
+		store.RevisionKey: json.RawMessage(`1`),
+	}, 0))
+
+	err := a.SaveRevision(ctx, map[string]json.RawMessage{
+		"b":               json.RawMessage(`2`),
+		store.RevisionKey: json.RawMessage(`1`),
+	}, 0)
+	var conflict *store.ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, int64(0), conflict.Expected)
+	assert.Equal(t, int64(1), conflict.Actual)
+	assert.ErrorIs(t, err, store.ErrConflict)
+
+	data, err := a.Load(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]json.RawMessage{
+		"a":               json.RawMessage(`1`),
+		store.RevisionKey: json.RawMessage(`1`),
+	}, data, "a rejected save writes nothing")
+}
+
+func TestAdapter_StoreUpdateCASRace(t *testing.T) {
+	ctx := context.Background()
+	adapter := New(newFakeRedis())
+
+	// Two stores sharing one adapter, as in one server handling two requests.
+	stores := []*store.Store{store.New(adapter), store.New(adapter)}
+	for _, s := range stores {
+		require.NoError(t, s.Reload(ctx))
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(stores))
+	for i, s := range stores {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.UpdateCAS(ctx, func(data map[string]any) error {
+				data["owner"] = strconv.Itoa(i)
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+
+	var succeeded int
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+		} else {
+			assert.ErrorIs(t, err, store.ErrConflict)
+		}
+	}
+	assert.Equal(t, 1, succeeded, "exactly one UpdateCAS wins")
+
+	fresh := store.New(adapter)
+	require.NoError(t, fresh.Reload(ctx))
+	assert.Equal(t, int64(1), fresh.Revision())
+}
+
+func TestEscapeGlob(t *testing.T) {
+	assert.Equal(t, `app\*\[1\]:`, escapeGlob("app*[1]:"))
+}
@@ -0,0 +1,72 @@
+// Package redis provides a Redis-backed persistence adapter for gains
+// stores and sessions.
+//
+// The adapter talks to Redis through a minimal [Client] interface, so it
+// works with any Redis library without adding a dependency to gains. Wrap
+// your client's generic command method with [ClientFunc]; with go-redis:
+//
+//	rdb := goredis.NewClient(&goredis.Options{Addr: "localhost:6379"})
+//
+//	client := redis.ClientFunc(func(ctx context.Context, args ...any) (any, error) {
+//	    v, err := rdb.Do(ctx, args...).Result()
+//	    if errors.Is(err, goredis.Nil) {
+//	        return nil, nil
+//	    }
+//	    return v, err
+//	})
+//
+//	adapter := redis.New(client,
+//	    redis.WithPrefix("myapp:"),
+//	    redis.WithTTL(24*time.Hour),
+//	)
+//	sessions := session.NewManager(adapter)
+//
+// # Storage Layout
+//
+// Each key is stored as a Redis hash under the configured prefix (default
+// "gains:") with two fields: "data" holds the JSON value and "ver" a version
+// number incremented on every write. With a TTL, keys expire that long
+// after their last write.
+//
+// # Optimistic Concurrency
+//
+// Get, Set, and Save replace data without checking for concurrent writers.
+// To detect them, read with GetVersion and write with SetVersion, passing
+// back the version that was read: the write succeeds only if the version
+// in Redis is unchanged, and otherwise fails with a [*ConflictError] and
+// writes nothing. Reread and retry to resolve the conflict. Versions belong
+// to the caller, so two readers sharing one Adapter detect each other's
+// writes. The adapter implements store.Versioner, which sessions use to
+// reject writes of a stale session. The error matches store.ErrConflict
+// and session.ErrConflict.
+//
+// For state shared by several servers, wrap the adapter in a store.Store and
+// write with UpdateCAS. The adapter implements store.RevisionSaver, so the
+// revision check and the write run in one script, and of two stores that
+// read the same revision, whether in one process or several, only one
+// write succeeds:
+//
+//	state := store.New(adapter)
+//	err := state.UpdateCAS(ctx, func(data map[string]any) error { ... })
+//
+// # Round Trips
+//
+// Set, SetVersion, Save, and SaveRevision run as Lua scripts, so each is atomic and takes
+// one round trip regardless of how many keys Save writes. Load fetches keys in one
+// round trip when the client also implements [Pipeliner].
+//
+// # Locks
+//
+// The adapter implements locks shared by every process using the same
+// Redis and prefix, which session.Manager uses to let one run at a time
+// write to a session. Each lock is a string key "lock:" followed by the
+// prefix and lock name, set with SET NX and an expiry, and extended or
+// released only by the holder's token. Lock keys are never listed as data
+// keys, even with an empty prefix.
+//
+// # Redis Cluster
+//
+// Save touches many keys in one script, which Redis Cluster only allows
+// when they share a hash slot. Use a prefix with a hash tag, such as
+// "{myapp}:", to keep an adapter's keys in one slot.
+package redis
@@ -0,0 +1,35 @@
+package redis
+
+import (
+	"fmt"
+
+	"github.com/spetersoncode/gains/internal/store"
+)
+
+// ConflictError indicates a SetVersion write was rejected because the key
+// was changed by another writer since the caller read or wrote it. It
+// matches store.ErrConflict with errors.Is.
+type ConflictError struct {
+	Key      string
+	Expected int64 // Version the caller last saw
+	Actual   int64 // Version currently stored
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("redis: version conflict for key %q: expected version %d, found %d", e.Key, e.Expected, e.Actual)
+}
+
+// Is reports whether target is store.ErrConflict.
+func (e *ConflictError) Is(target error) bool {
+	return target == store.ErrConflict
+}
+
+// ReplyError indicates Redis returned a reply of an unexpected type.
+type ReplyError struct {
+	Command string
+	Reply   any
+}
+
+func (e *ReplyError) Error() string {
+	return fmt.Sprintf("redis: unexpected reply to %s: %T", e.Command, e.Reply)
+}
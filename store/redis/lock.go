@@ -0,0 +1,62 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/internal/store"
+)
+
+// Ensure Adapter implements store.Locker and store.Refresher
+var (
+	_ store.Locker    = (*Adapter)(nil)
+	_ store.Refresher = (*Adapter)(nil)
+)
+
+// lockPrefix is prepended to lock keys, keeping them out of the adapter's
+// data keys.
+const lockPrefix = "lock:"
+
+// TryLock acquires the named lock for ttl with SET NX, returning the token
+// that releases it, or false if another holder has it. Locks are shared by
+// every adapter using the same Redis and prefix.
+func (a *Adapter) TryLock(ctx context.Context, name string, ttl time.Duration) (string, bool, error) {
+	token := ai.NewID()
+	reply, err := a.client.Do(ctx, "SET", a.lockKey(name), token, "NX", "PX", ttl.Milliseconds())
+	if err != nil {
+		return "", false, err
+	}
+	if reply == nil {
+		return "", false, nil
+	}
+	if s, ok := toString(reply); !ok || s != "OK" {
+		return "", false, &ReplyError{Command: "SET", Reply: reply}
+	}
+	return token, true, nil
+}
+
+// Refresh extends the named lock to expire ttl from now if it is still
+// held under token, and reports whether it is.
+func (a *Adapter) Refresh(ctx context.Context, name, token string, ttl time.Duration) (bool, error) {
+	reply, err := a.client.Do(ctx, "EVAL", refreshScript, 1, a.lockKey(name), token, ttl.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+	n, err := toInt64(reply)
+	if err != nil {
+		return false, &ReplyError{Command: "EVAL", Reply: reply}
+	}
+	return n == 1, nil
+}
+
+// Unlock releases the named lock if it is still held under token.
+func (a *Adapter) Unlock(ctx context.Context, name, token string) error {
+	_, err := a.client.Do(ctx, "EVAL", unlockScript, 1, a.lockKey(name), token)
+	return err
+}
+
+// lockKey returns the Redis key of the named lock.
+func (a *Adapter) lockKey(name string) string {
+	return lockPrefix + a.prefix + name
+}
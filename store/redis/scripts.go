@@ -0,0 +1,78 @@
+package redis
+
+// setScript writes one key if its version matches.
+//
+// KEYS[1] is the key. ARGV is the JSON data, the expected version (-1 to
+// skip the check, 0 for a missing key), and the TTL in milliseconds (0 for
+// none). Returns {1, newVersion} on success or {0, currentVersion} on
+// conflict.
+const setScript = `
+local cur = tonumber(redis.call('HGET', KEYS[1], 'ver') or 0)
+local expected = tonumber(ARGV[2])
+if expected >= 0 and cur ~= expected then
+  return {0, cur}
+end
+local ver = cur + 1
+redis.call('HSET', KEYS[1], 'data', ARGV[1], 'ver', ver)
+local ttl = tonumber(ARGV[3])
+if ttl > 0 then
+  redis.call('PEXPIRE', KEYS[1], ttl)
+end
+return {1, ver}
+`
+
+// saveScript replaces a set of keys atomically, optionally only if a
+// revision key holds the expected revision.
+//
+// KEYS holds the n keys to write followed by the keys to delete. ARGV[1] is
+// n, ARGV[2] the TTL in milliseconds, ARGV[3] the index in KEYS of the key
+// whose JSON data is the revision to check (0 for no check), and ARGV[4]
+// the expected revision (0 for a missing key), followed by the JSON data of
+// each key to write. Returns 1 on success or {0, currentRevision} if the
+// revision differs, having written nothing.
+const saveScript = `
+local n = tonumber(ARGV[1])
+local ttl = tonumber(ARGV[2])
+local check = tonumber(ARGV[3])
+if check > 0 then
+  local cur = tonumber(redis.call('HGET', KEYS[check], 'data') or 0)
+  if cur ~= tonumber(ARGV[4]) then
+    return {0, cur}
+  end
+end
+for i = n + 1, #KEYS do
+  redis.call('DEL', KEYS[i])
+end
+for i = 1, n do
+  local ver = tonumber(redis.call('HGET', KEYS[i], 'ver') or 0) + 1
+  redis.call('HSET', KEYS[i], 'data', ARGV[4 + i], 'ver', ver)
+  if ttl > 0 then
+    redis.call('PEXPIRE', KEYS[i], ttl)
+  end
+end
+return 1
+`
+
+// unlockScript deletes a lock key if it still holds the caller's token.
+//
+// KEYS[1] is the lock key and ARGV[1] the token. Returns 1 if the lock was
+// released or 0 if another holder has it.
+const unlockScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`
+
+// refreshScript extends a lock key's expiry if it still holds the caller's
+// token.
+//
+// KEYS[1] is the lock key, ARGV[1] the token, and ARGV[2] the new TTL in
+// milliseconds. Returns 1 if the lock was extended or 0 if another holder
+// has it.
+const refreshScript = `
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`
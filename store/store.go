@@ -0,0 +1,45 @@
+package store
+
+import (
+	istore "github.com/spetersoncode/gains/internal/store"
+)
+
+// Store is a thread-safe key-value store persisted to an Adapter with
+// revision-checked writes.
+type Store = istore.Store
+
+// Adapter is a persistence backend. Implementations must be thread-safe.
+type Adapter = istore.Adapter
+
+// RevisionSaver is implemented by adapters that check the stored revision
+// and save in one atomic step.
+type RevisionSaver = istore.RevisionSaver
+
+// Versioner is implemented by adapters whose callers read a key's version
+// and pass it back to write the key only if it is unchanged.
+type Versioner = istore.Versioner
+
+// ConflictError reports a write that Store.UpdateCAS rejected. It matches
+// ErrConflict with errors.Is.
+type ConflictError = istore.ConflictError
+
+// RevisionKey is the reserved adapter key that holds a store's revision.
+const RevisionKey = istore.RevisionKey
+
+// ErrConflict indicates that the stored state changed after it was last
+// read, so a conditional write was rejected. Errors from Store.UpdateCAS
+// and from adapters with their own optimistic concurrency, such as the
+// Redis adapter, match it with errors.Is.
+var ErrConflict = istore.ErrConflict
+
+// New creates a Store persisted to adapter, or to a new in-memory adapter
+// if adapter is nil.
+func New(adapter Adapter) *Store {
+	return istore.New(adapter)
+}
+
+// NewMemoryAdapter creates an in-memory adapter. State stored in it is
+// lost on restart and is not shared with other processes.
+func NewMemoryAdapter() Adapter {
+	return istore.NewMemoryAdapter()
+}
@@ -0,0 +1,67 @@
+package store_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spetersoncode/gains/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_UpdateCAS(t *testing.T) {
+	ctx := context.Background()
+	adapter := store.NewMemoryAdapter()
+
+	// Two servers sharing one adapter.
+	first := store.New(adapter)
+	second := store.New(adapter)
+	require.NoError(t, first.Reload(ctx))
+	require.NoError(t, second.Reload(ctx))
+
+	require.NoError(t, first.UpdateCAS(ctx, func(data map[string]any) error {
+		data["owner"] = "first"
+		return nil
+	}))
+
+	err := second.UpdateCAS(ctx, func(data map[string]any) error {
+		data["owner"] = "second"
+		return nil
+	})
+	require.ErrorIs(t, err, store.ErrConflict)
+	var conflict *store.ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, int64(0), conflict.Expected)
+	assert.Equal(t, int64(1), conflict.Actual)
+	assert.False(t, second.Has("owner"), "cache untouched on conflict")
+
+	require.NoError(t, second.Reload(ctx))
+	assert.Equal(t, "first", second.GetString("owner"))
+	require.NoError(t, second.UpdateCAS(ctx, func(data map[string]any) error {
+		data["owner"] = "second"
+		return nil
+	}))
+	assert.Equal(t, int64(2), second.Revision())
+
+	_, ok, err := adapter.Get(ctx, store.RevisionKey)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	_, atomic := adapter.(store.RevisionSaver)
+	assert.True(t, atomic)
+}
+
+func TestStore_UpdateCASAbort(t *testing.T) {
+	ctx := context.Background()
+	s := store.New(nil)
+	s.Set("status", "pending")
+
+	abort := errors.New("not allowed")
+	err := s.UpdateCAS(ctx, func(data map[string]any) error {
+		data["status"] = "approved"
+		return abort
+	})
+	assert.ErrorIs(t, err, abort)
+	assert.Equal(t, "pending", s.GetString("status"))
+	assert.Equal(t, int64(0), s.Revision())
+}
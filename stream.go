@@ -0,0 +1,200 @@
+package gains
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// StreamStats summarizes the timing of a streamed response.
+type StreamStats struct {
+	// Start is when accumulation began, normally just before the request.
+	Start time.Time `json:"start"`
+	// TimeToFirstToken is the time from Start to the first non-empty delta.
+	// Zero if no content was streamed.
+	TimeToFirstToken time.Duration `json:"timeToFirstToken"`
+	// Duration is the time from Start to the end of the stream, or to the
+	// latest event if the stream has not finished.
+	Duration time.Duration `json:"duration"`
+	// Deltas is the number of non-empty deltas received.
+	Deltas int `json:"deltas"`
+	// OutputTokens is the output token count reported in the final response.
+	OutputTokens int `json:"outputTokens"`
+	// TokensPerSecond is OutputTokens divided by the generation time, measured
+	// from the first token to the end of the stream. Zero until the final
+	// response reports usage.
+	TokensPerSecond float64 `json:"tokensPerSecond"`
+}
+
+// StreamAccumulator collects streaming events into a final Response and
+// timing statistics. Feed it events with Add, or drain a whole channel with
+// AccumulateStream. For client and agent event channels, use
+// event.Accumulate. A StreamAccumulator is not safe for concurrent use.
+type StreamAccumulator struct {
+	start      time.Time
+	firstToken time.Time
+	last       time.Time
+	content    strings.Builder
+	deltas     int
+	response   *Response
+	err        error
+	done       bool
+}
+
+// NewStreamAccumulator creates an accumulator whose timing starts now.
+// Create it just before starting the request so time-to-first-token
+// includes request latency.
+func NewStreamAccumulator() *StreamAccumulator {
+	now := time.Now()
+	return &StreamAccumulator{start: now, last: now}
+}
+
+// Add records a provider stream event. It reports whether the stream has
+// finished, either with a final response or an error.
+func (a *StreamAccumulator) Add(ev StreamEvent) bool {
+	if ev.Err != nil {
+		a.Fail(ev.Err)
+		return true
+	}
+	a.AddDelta(ev.Delta)
+	if ev.Done {
+		a.Complete(ev.Response)
+		return true
+	}
+	return false
+}
+
+// AddDelta records an incremental piece of content. Empty deltas are ignored.
+func (a *StreamAccumulator) AddDelta(delta string) {
+	if delta == "" {
+		return
+	}
+	now := time.Now()
+	if a.deltas == 0 {
+		a.firstToken = now
+	}
+	a.deltas++
+	a.last = now
+	a.content.WriteString(delta)
+}
+
+// Complete records the final response and marks the stream finished.
+// A nil response leaves Response built from the accumulated deltas.
+func (a *StreamAccumulator) Complete(resp *Response) {
+	if resp != nil {
+		a.response = resp
+	}
+	a.finish()
+}
+
+// Fail records a stream error and marks the stream finished.
+func (a *StreamAccumulator) Fail(err error) {
+	a.err = err
+	a.finish()
+}
+
+func (a *StreamAccumulator) finish() {
+	a.done = true
+	a.last = time.Now()
+}
+
+// Done reports whether the stream has finished.
+func (a *StreamAccumulator) Done() bool { return a.done }
+
+// Err returns the stream error, if any.
+func (a *StreamAccumulator) Err() error { return a.err }
+
+// Content returns the content accumulated from deltas so far.
+func (a *StreamAccumulator) Content() string { return a.content.String() }
+
+// Response returns the final response. If the stream ended without one, or
+// has not ended yet, it returns a response holding the accumulated content.
+func (a *StreamAccumulator) Response() *Response {
+	if a.response != nil {
+		return a.response
+	}
+	return &Response{Content: a.content.String()}
+}
+
+// Stats returns timing statistics for the stream so far.
+func (a *StreamAccumulator) Stats() StreamStats {
+	stats := StreamStats{
+		Start:    a.start,
+		Duration: a.last.Sub(a.start),
+		Deltas:   a.deltas,
+	}
+	if a.deltas > 0 {
+		stats.TimeToFirstToken = a.firstToken.Sub(a.start)
+	}
+	if a.response != nil {
+		stats.OutputTokens = a.response.Usage.OutputTokens
+	}
+
+	// Measure generation from the first token; fall back to the whole
+	// stream when content arrived in a single delta.
+	generation := stats.Duration - stats.TimeToFirstToken
+	if generation <= 0 {
+		generation = stats.Duration
+	}
+	if stats.OutputTokens > 0 && generation > 0 {
+		stats.TokensPerSecond = float64(stats.OutputTokens) / generation.Seconds()
+	}
+	return stats
+}
+
+// AccumulateStream drains a provider stream and returns the final response
+// and its timing statistics. On a stream error the response is nil. Timing
+// starts when AccumulateStream is called.
+func AccumulateStream(ch <-chan StreamEvent) (*Response, StreamStats, error) {
+	acc := NewStreamAccumulator()
+	for ev := range ch {
+		if !acc.Done() {
+			acc.Add(ev)
+		}
+	}
+	if acc.Err() != nil {
+		return nil, acc.Stats(), acc.Err()
+	}
+	return acc.Response(), acc.Stats(), nil
+}
+
+// StreamToWriter writes the content of a provider stream to w as it arrives
+// and returns the final response. It returns early with an error if the
+// stream fails, ctx is cancelled, or a write to w fails; the rest of the
+// stream is then drained in the background. For client and agent event
+// channels, use event.StreamToWriter.
+func StreamToWriter(ctx context.Context, ch <-chan StreamEvent, w io.Writer) (*Response, error) {
+	acc := NewStreamAccumulator()
+	for {
+		select {
+		case <-ctx.Done():
+			go drainStream(ch)
+			return nil, ctx.Err()
+		case ev, ok := <-ch:
+			if !ok {
+				return acc.Response(), nil
+			}
+			if ev.Delta != "" {
+				if _, err := io.WriteString(w, ev.Delta); err != nil {
+					go drainStream(ch)
+					return nil, fmt.Errorf("write stream: %w", err)
+				}
+			}
+			if acc.Add(ev) {
+				go drainStream(ch)
+				if acc.Err() != nil {
+					return nil, acc.Err()
+				}
+				return acc.Response(), nil
+			}
+		}
+	}
+}
+
+// drainStream discards the remaining events of ch so its sender can finish.
+func drainStream(ch <-chan StreamEvent) {
+	for range ch {
+	}
+}
@@ -0,0 +1,151 @@
+package gains
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamAccumulator(t *testing.T) {
+	t.Run("accumulates deltas and final response", func(t *testing.T) {
+		acc := NewStreamAccumulator()
+		assert.False(t, acc.Add(StreamEvent{Delta: "Hello, "}))
+		assert.False(t, acc.Add(StreamEvent{Delta: "world"}))
+		assert.Equal(t, "Hello, world", acc.Content())
+
+		final := &Response{Content: "Hello, world", Usage: Usage{OutputTokens: 4}}
+		assert.True(t, acc.Add(StreamEvent{Done: true, Response: final}))
+		assert.True(t, acc.Done())
+		assert.Same(t, final, acc.Response())
+		assert.NoError(t, acc.Err())
+
+		stats := acc.Stats()
+		assert.Equal(t, 2, stats.Deltas)
+		assert.Equal(t, 4, stats.OutputTokens)
+		assert.LessOrEqual(t, stats.TimeToFirstToken, stats.Duration)
+	})
+
+	t.Run("builds response from deltas when none is sent", func(t *testing.T) {
+		acc := NewStreamAccumulator()
+		acc.Add(StreamEvent{Delta: "partial"})
+		assert.Equal(t, "partial", acc.Response().Content)
+		assert.False(t, acc.Done())
+	})
+
+	t.Run("records errors", func(t *testing.T) {
+		acc := NewStreamAccumulator()
+		streamErr := errors.New("connection reset")
+		assert.True(t, acc.Add(StreamEvent{Err: streamErr}))
+		assert.ErrorIs(t, acc.Err(), streamErr)
+	})
+
+	t.Run("ignores empty deltas", func(t *testing.T) {
+		acc := NewStreamAccumulator()
+		acc.AddDelta("")
+		stats := acc.Stats()
+		assert.Zero(t, stats.Deltas)
+		assert.Zero(t, stats.TimeToFirstToken)
+	})
+}
+
+func TestStreamAccumulatorStats(t *testing.T) {
+	start := time.Now()
+	acc := &StreamAccumulator{
+		start:      start,
+		firstToken: start.Add(500 * time.Millisecond),
+		last:       start.Add(2500 * time.Millisecond),
+		deltas:     10,
+		response:   &Response{Usage: Usage{OutputTokens: 100}},
+		done:       true,
+	}
+
+	stats := acc.Stats()
+	assert.Equal(t, 500*time.Millisecond, stats.TimeToFirstToken)
+	assert.Equal(t, 2500*time.Millisecond, stats.Duration)
+	assert.InDelta(t, 50.0, stats.TokensPerSecond, 0.001)
+}
+
+func TestAccumulateStream(t *testing.T) {
+	t.Run("returns final response", func(t *testing.T) {
+		ch := make(chan StreamEvent, 3)
+		ch <- StreamEvent{Delta: "Hi"}
+		ch <- StreamEvent{Delta: " there"}
+		ch <- StreamEvent{Done: true, Response: &Response{Content: "Hi there"}}
+		close(ch)
+
+		resp, stats, err := AccumulateStream(ch)
+		require.NoError(t, err)
+		assert.Equal(t, "Hi there", resp.Content)
+		assert.Equal(t, 2, stats.Deltas)
+	})
+
+	t.Run("returns stream error", func(t *testing.T) {
+		ch := make(chan StreamEvent, 2)
+		ch <- StreamEvent{Delta: "Hi"}
+		ch <- StreamEvent{Err: errors.New("boom")}
+		close(ch)
+
+		resp, _, err := AccumulateStream(ch)
+		assert.Nil(t, resp)
+		assert.EqualError(t, err, "boom")
+	})
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, errors.New("broken pipe") }
+
+func TestStreamToWriter(t *testing.T) {
+	t.Run("writes deltas and returns final response", func(t *testing.T) {
+		final := &Response{Content: "Hello", Usage: Usage{OutputTokens: 2}}
+		ch := make(chan StreamEvent, 3)
+		ch <- StreamEvent{Delta: "Hel"}
+		ch <- StreamEvent{Delta: "lo"}
+		ch <- StreamEvent{Done: true, Response: final}
+		close(ch)
+
+		var sb strings.Builder
+		resp, err := StreamToWriter(context.Background(), ch, &sb)
+		require.NoError(t, err)
+		assert.Equal(t, "Hello", sb.String())
+		assert.Same(t, final, resp)
+	})
+
+	t.Run("returns stream error", func(t *testing.T) {
+		ch := make(chan StreamEvent, 2)
+		ch <- StreamEvent{Delta: "partial"}
+		ch <- StreamEvent{Err: errors.New("connection reset")}
+		close(ch)
+
+		var sb strings.Builder
+		resp, err := StreamToWriter(context.Background(), ch, &sb)
+		assert.Nil(t, resp)
+		assert.EqualError(t, err, "connection reset")
+		assert.Equal(t, "partial", sb.String())
+	})
+
+	t.Run("returns write error", func(t *testing.T) {
+		ch := make(chan StreamEvent, 2)
+		ch <- StreamEvent{Delta: "Hi"}
+		ch <- StreamEvent{Done: true}
+		close(ch)
+
+		_, err := StreamToWriter(context.Background(), ch, failingWriter{})
+		assert.ErrorContains(t, err, "broken pipe")
+	})
+
+	t.Run("stops when context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		ch := make(chan StreamEvent)
+		defer close(ch)
+
+		_, err := StreamToWriter(ctx, ch, &strings.Builder{})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
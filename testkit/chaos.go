@@ -0,0 +1,272 @@
+package testkit
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// Fault is a failure a Chaos provider injects into a request.
+type Fault int
+
+const (
+	// FaultNone passes the request through unchanged.
+	FaultNone Fault = iota
+	// FaultRateLimit fails the request with a transient HTTP 429 error
+	// without calling the wrapped provider.
+	FaultRateLimit
+	// FaultServerError fails the request with a transient HTTP 500 error
+	// without calling the wrapped provider.
+	FaultServerError
+	// FaultMalformedJSON cuts the response content and tool call arguments
+	// short, so they no longer parse as JSON.
+	FaultMalformedJSON
+	// FaultTruncatedStream ends a stream with a transient error after half
+	// of its deltas, before the final response. Chat requests fail with the
+	// same error.
+	FaultTruncatedStream
+)
+
+// String returns the fault's name.
+func (f Fault) String() string {
+	switch f {
+	case FaultNone:
+		return "none"
+	case FaultRateLimit:
+		return "rate_limit"
+	case FaultServerError:
+		return "server_error"
+	case FaultMalformedJSON:
+		return "malformed_json"
+	case FaultTruncatedStream:
+		return "truncated_stream"
+	default:
+		return "unknown"
+	}
+}
+
+// ChaosOption configures a Chaos provider.
+type ChaosOption func(*Chaos)
+
+// WithLatency delays every request by d before it is sent or failed. The
+// delay ends early if the request's context is canceled.
+func WithLatency(d time.Duration) ChaosOption {
+	return func(c *Chaos) {
+		c.latency = d
+	}
+}
+
+// WithFaults scripts the faults of successive requests: the first request
+// gets faults[0], the second faults[1], and so on. Requests past the end
+// of the script pass through, unless WithFaultRate is also set.
+func WithFaults(faults ...Fault) ChaosOption {
+	return func(c *Chaos) {
+		c.script = append(c.script, faults...)
+	}
+}
+
+// WithFaultRate injects one of kinds, chosen at random, into each unscripted
+// request with probability rate. The choices are drawn from a generator
+// seeded with seed, so a sequential run injects the same faults every time.
+func WithFaultRate(rate float64, seed int64, kinds ...Fault) ChaosOption {
+	return func(c *Chaos) {
+		c.rate = rate
+		c.kinds = kinds
+		c.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// WithRetryAfter sets the delay FaultRateLimit errors ask callers to wait
+// before retrying. The default is none.
+func WithRetryAfter(d time.Duration) ChaosOption {
+	return func(c *Chaos) {
+		c.retryAfter = d
+	}
+}
+
+// Chaos is an ai.ChatProvider that injects latency and faults into requests
+// to another provider, to exercise retry, fallback, and error handling
+// deterministically. Route a client's requests through it with
+// client.WithChatProvider. It is safe for concurrent use.
+type Chaos struct {
+	provider   ai.ChatProvider
+	latency    time.Duration
+	retryAfter time.Duration
+	rate       float64
+	kinds      []Fault
+
+	mu       sync.Mutex
+	script   []Fault
+	rng      *rand.Rand
+	requests int
+	injected []Fault
+}
+
+var _ ai.ChatProvider = (*Chaos)(nil)
+
+// NewChaos returns a Chaos provider around p. Without options it passes
+// every request through unchanged.
+func NewChaos(p ai.ChatProvider, opts ...ChaosOption) *Chaos {
+	c := &Chaos{provider: p}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// next returns the fault for the next request and records it.
+func (c *Chaos) next() Fault {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fault := FaultNone
+	switch {
+	case c.requests < len(c.script):
+		fault = c.script[c.requests]
+	case c.rng != nil && len(c.kinds) > 0 && c.rng.Float64() < c.rate:
+		fault = c.kinds[c.rng.Intn(len(c.kinds))]
+	}
+	c.requests++
+	c.injected = append(c.injected, fault)
+	return fault
+}
+
+// begin waits out the latency and returns the fault for the request, or
+// the error that fails it before the wrapped provider is called.
+func (c *Chaos) begin(ctx context.Context) (Fault, error) {
+	if c.latency > 0 {
+		timer := time.NewTimer(c.latency)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return FaultNone, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	fault := c.next()
+	switch fault {
+	case FaultRateLimit:
+		return fault, ai.NewTransientErrorWithRetry("chaos: rate limited", 429, c.retryAfter, nil)
+	case FaultServerError:
+		return fault, ai.NewTransientError("chaos: internal server error", 500, nil)
+	}
+	return fault, nil
+}
+
+// errTruncated is the error ending a truncated stream.
+func errTruncated() error {
+	return ai.NewTransientError("chaos: stream truncated", 0, io.ErrUnexpectedEOF)
+}
+
+// Chat sends the request to the wrapped provider, injecting the request's
+// fault.
+func (c *Chaos) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	fault, err := c.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if fault == FaultTruncatedStream {
+		return nil, errTruncated()
+	}
+
+	resp, err := c.provider.Chat(ctx, messages, opts...)
+	if err != nil || fault != FaultMalformedJSON {
+		return resp, err
+	}
+	return malform(resp), nil
+}
+
+// ChatStream streams the request from the wrapped provider, injecting the
+// request's fault.
+func (c *Chaos) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan ai.StreamEvent, error) {
+	fault, err := c.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ch, err := c.provider.ChatStream(ctx, messages, opts...)
+	if err != nil || fault == FaultNone {
+		return ch, err
+	}
+
+	out := make(chan ai.StreamEvent, cap(ch))
+	go func() {
+		defer close(out)
+		if fault == FaultTruncatedStream {
+			truncate(ch, out)
+			return
+		}
+		for ev := range ch {
+			if ev.Done && ev.Response != nil {
+				ev.Response = malform(ev.Response)
+			}
+			out <- ev
+		}
+	}()
+	return out, nil
+}
+
+// truncate forwards the first half of in's deltas to out and then ends the
+// stream with an error. The rest of in is drained so the wrapped provider
+// can finish.
+func truncate(in <-chan ai.StreamEvent, out chan<- ai.StreamEvent) {
+	var buffered []ai.StreamEvent
+	for ev := range in {
+		if ev.Err != nil || ev.Done {
+			break
+		}
+		buffered = append(buffered, ev)
+	}
+	for _, ev := range buffered[:len(buffered)/2] {
+		out <- ev
+	}
+	out <- ai.StreamEvent{Err: errTruncated()}
+	for range in {
+	}
+}
+
+// malform returns a copy of resp whose non-empty content and tool call
+// arguments are cut short and left with an unclosed brace, which no JSON
+// parser accepts.
+func malform(resp *ai.Response) *ai.Response {
+	if resp == nil {
+		return nil
+	}
+	bad := *resp
+	bad.Content = malformJSON(resp.Content)
+	if len(resp.ToolCalls) > 0 {
+		bad.ToolCalls = make([]ai.ToolCall, len(resp.ToolCalls))
+		for i, call := range resp.ToolCalls {
+			call.Arguments = malformJSON(call.Arguments)
+			bad.ToolCalls[i] = call
+		}
+	}
+	return &bad
+}
+
+func malformJSON(s string) string {
+	if s == "" {
+		return s
+	}
+	return s[:len(s)/2] + "{"
+}
+
+// Requests returns the number of requests the provider has received.
+func (c *Chaos) Requests() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.requests
+}
+
+// Faults returns the fault injected into each request so far, in order,
+// with FaultNone for requests passed through unchanged.
+func (c *Chaos) Faults() []Fault {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Fault(nil), c.injected...)
+}
@@ -0,0 +1,138 @@
+package testkit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/client"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubProvider answers every request with the same JSON content, streamed
+// in four deltas.
+type stubProvider struct{}
+
+func (stubProvider) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	return &ai.Response{
+		Content:   `{"ok":true}`,
+		ToolCalls: []ai.ToolCall{{ID: "call_1", Name: "search", Arguments: `{"q":"go"}`}},
+	}, nil
+}
+
+func (stubProvider) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan ai.StreamEvent, error) {
+	ch := make(chan ai.StreamEvent, 5)
+	for _, d := range []string{`{"o`, `k":`, `tru`, `e}`} {
+		ch <- ai.StreamEvent{Delta: d}
+	}
+	ch <- ai.StreamEvent{Done: true, Response: &ai.Response{Content: `{"ok":true}`}}
+	close(ch)
+	return ch, nil
+}
+
+func TestChaosScriptedFaults(t *testing.T) {
+	ctx := context.Background()
+	c := NewChaos(stubProvider{}, WithFaults(FaultRateLimit, FaultServerError, FaultMalformedJSON, FaultTruncatedStream))
+
+	_, err := c.Chat(ctx, nil)
+	require.Error(t, err)
+	assert.True(t, ai.IsTransient(err))
+	assert.Equal(t, 429, ai.StatusCodeOf(err))
+
+	_, err = c.Chat(ctx, nil)
+	assert.Equal(t, 500, ai.StatusCodeOf(err))
+
+	resp, err := c.Chat(ctx, nil)
+	require.NoError(t, err)
+	assert.False(t, json.Valid([]byte(resp.Content)))
+	assert.False(t, json.Valid([]byte(resp.ToolCalls[0].Arguments)))
+
+	_, err = c.Chat(ctx, nil)
+	assert.True(t, ai.IsTransient(err))
+
+	resp, err = c.Chat(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, resp.Content)
+
+	assert.Equal(t, 5, c.Requests())
+	assert.Equal(t, []Fault{FaultRateLimit, FaultServerError, FaultMalformedJSON, FaultTruncatedStream, FaultNone}, c.Faults())
+}
+
+func TestChaosTruncatedStream(t *testing.T) {
+	c := NewChaos(stubProvider{}, WithFaults(FaultTruncatedStream))
+
+	ch, err := c.ChatStream(context.Background(), nil)
+	require.NoError(t, err)
+
+	var deltas []string
+	var streamErr error
+	for ev := range ch {
+		assert.False(t, ev.Done)
+		if ev.Err != nil {
+			streamErr = ev.Err
+			continue
+		}
+		deltas = append(deltas, ev.Delta)
+	}
+	assert.Equal(t, []string{`{"o`, `k":`}, deltas)
+	assert.True(t, ai.IsTransient(streamErr))
+}
+
+func TestChaosMalformedStream(t *testing.T) {
+	c := NewChaos(stubProvider{}, WithFaults(FaultMalformedJSON))
+
+	ch, err := c.ChatStream(context.Background(), nil)
+	require.NoError(t, err)
+	resp, _, err := ai.AccumulateStream(ch)
+	require.NoError(t, err)
+	assert.False(t, json.Valid([]byte(resp.Content)))
+}
+
+func TestChaosFaultRateIsSeeded(t *testing.T) {
+	run := func() []Fault {
+		c := NewChaos(stubProvider{}, WithFaultRate(0.5, 42, FaultRateLimit, FaultServerError))
+		for range 20 {
+			c.Chat(context.Background(), nil)
+		}
+		return c.Faults()
+	}
+
+	first := run()
+	assert.Equal(t, first, run())
+	assert.Contains(t, first, FaultNone)
+	assert.Contains(t, first, FaultRateLimit)
+}
+
+func TestChaosLatencyRespectsContext(t *testing.T) {
+	c := NewChaos(stubProvider{}, WithLatency(time.Hour))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := c.Chat(ctx, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Zero(t, c.Requests())
+}
+
+func TestChaosWithClientRetry(t *testing.T) {
+	chaos := NewChaos(stubProvider{}, WithFaults(FaultRateLimit, FaultServerError))
+	c := client.New(client.Config{
+		Defaults: client.Defaults{Chat: model.ClaudeSonnet45},
+	}, client.WithChatProvider(ai.ProviderAnthropic, chaos))
+	retry := ai.WithRetry(ai.RetryConfig{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Multiplier: 1})
+
+	resp, err := c.Chat(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}, retry)
+	require.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, resp.Content)
+	assert.Equal(t, 3, chaos.Requests())
+
+	ch, err := c.ChatStream(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}, retry)
+	require.NoError(t, err)
+	resp, _, err = event.Accumulate(ch)
+	require.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, resp.Content)
+}
@@ -0,0 +1,53 @@
+// Package testkit supports golden-trace regression tests for agents and
+// workflows.
+//
+// A [Trace] is the deterministic outline of a run: its steps, routing,
+// messages, tool calls, and outcome, without IDs, timestamps, streaming
+// deltas, or token usage. Record a run against live models once, saving
+// the model responses with a [Recorder] and the trace as a golden file,
+// then replay the responses in tests and assert the run still produces an
+// equivalent trace:
+//
+//	func TestResearchAgent(t *testing.T) {
+//	    replay, err := testkit.LoadReplay("testdata/research.responses.json")
+//	    require.NoError(t, err)
+//
+//	    a := agent.New(replay, registry)
+//	    trace := testkit.Collect(a.RunStream(ctx, messages))
+//	    testkit.AssertGolden(t, "testdata/research.trace.jsonl", trace)
+//	}
+//
+// To record, run the same code with a Recorder around a real client and
+// save its responses:
+//
+//	rec := testkit.NewRecorder(c)
+//	trace := testkit.Collect(agent.New(rec, registry).RunStream(ctx, messages))
+//	require.NoError(t, rec.Save("testdata/research.responses.json"))
+//
+// AssertGolden writes the golden file when it does not exist, or when the
+// GAINS_UPDATE_GOLDEN environment variable is set, so an intended change
+// is accepted by rerunning the tests with GAINS_UPDATE_GOLDEN=1 and
+// reviewing the diff of the golden file.
+//
+// # Concurrency
+//
+// Tool calls executed in parallel and branches of parallel workflow steps
+// emit events in an order that varies between runs. Traces put such events
+// in a canonical order before comparing them. A [Replay] answers requests
+// in the order they arrive, so runs that send concurrent chat requests
+// should be recorded and replayed with parallelism disabled.
+//
+// # Fault Injection
+//
+// A [Chaos] provider wraps another ai.ChatProvider and injects latency,
+// rate limits, server errors, malformed JSON, or truncated streams, so
+// retry, fallback, and agent error handling can be tested without a flaky
+// network. Faults are scripted per request or drawn from a seeded
+// generator, so each run injects the same ones:
+//
+//	chaos := testkit.NewChaos(fake, testkit.WithFaults(testkit.FaultRateLimit, testkit.FaultTruncatedStream))
+//	c := client.New(cfg, client.WithChatProvider(ai.ProviderAnthropic, chaos))
+//
+// Client requests for Anthropic models now fail twice before reaching
+// fake, and chaos.Faults reports what each request received.
+package testkit
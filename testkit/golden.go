@@ -0,0 +1,49 @@
+package testkit
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// UpdateEnv is the environment variable that makes AssertGolden rewrite
+// golden files instead of comparing against them.
+const UpdateEnv = "GAINS_UPDATE_GOLDEN"
+
+// AssertGolden fails t if got is not equivalent to the trace in the golden
+// file at path, reporting where they differ. If the file does not exist,
+// or UpdateEnv is set, got is written to it instead.
+func AssertGolden(t testing.TB, path string, got Trace) {
+	t.Helper()
+
+	want, err := LoadTrace(path)
+	if os.Getenv(UpdateEnv) != "" || errors.Is(err, fs.ErrNotExist) {
+		if err := writeGolden(path, got); err != nil {
+			t.Fatalf("testkit: write golden trace: %v", err)
+		}
+		t.Logf("testkit: wrote golden trace %s", path)
+		return
+	}
+	if err != nil {
+		t.Fatalf("testkit: read golden trace: %v", err)
+	}
+	if diff := Diff(want, got); diff != "" {
+		t.Errorf("testkit: trace does not match %s; rerun with %s=1 to accept it\n%s", path, UpdateEnv, diff)
+	}
+}
+
+// writeGolden writes the canonical form of trace to path, creating its
+// directory if needed.
+func writeGolden(path string, trace Trace) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if _, err := trace.Canonical().WriteTo(&buf); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
@@ -0,0 +1,70 @@
+package testkit_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/agent"
+	"github.com/spetersoncode/gains/testkit"
+	"github.com/spetersoncode/gains/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failureRecorder captures the failures of an assertion.
+type failureRecorder struct {
+	*testing.T
+	failures []string
+}
+
+func (r *failureRecorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func runAgent(t *testing.T, responses ...ai.Response) testkit.Trace {
+	registry := tool.NewRegistry()
+	registry.MustRegister(
+		ai.Tool{Name: "lookup", Parameters: json.RawMessage(`{"type":"object"}`)},
+		func(ctx context.Context, call ai.ToolCall) (string, error) { return "42", nil },
+	)
+	a := agent.New(testkit.NewReplay(responses...), registry)
+	return testkit.Collect(a.RunStream(context.Background(), []ai.Message{{Role: ai.RoleUser, Content: "What is the answer?"}}))
+}
+
+func TestAssertGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.trace.jsonl")
+	lookup := ai.Response{ToolCalls: []ai.ToolCall{
+		{ID: "call_1", Name: "lookup", Arguments: `{"q":"a"}`},
+		{ID: "call_2", Name: "lookup", Arguments: `{"q":"b"}`},
+	}}
+
+	// The first run records the golden file
+	testkit.AssertGolden(t, path, runAgent(t, lookup, ai.Response{Content: "The answer is 42."}))
+	golden, err := testkit.LoadTrace(path)
+	require.NoError(t, err)
+	require.NotEmpty(t, golden)
+
+	t.Run("matches an equivalent run", func(t *testing.T) {
+		testkit.AssertGolden(t, path, runAgent(t, lookup, ai.Response{Content: "The answer is 42."}))
+	})
+
+	t.Run("reports a changed run", func(t *testing.T) {
+		rec := &failureRecorder{T: t}
+		testkit.AssertGolden(rec, path, runAgent(t, ai.Response{Content: "I don't know."}))
+		require.Len(t, rec.failures, 1)
+		assert.Contains(t, rec.failures[0], "trace does not match")
+		assert.Contains(t, rec.failures[0], testkit.UpdateEnv)
+	})
+
+	t.Run("updates the golden file", func(t *testing.T) {
+		t.Setenv(testkit.UpdateEnv, "1")
+		testkit.AssertGolden(t, path, runAgent(t, ai.Response{Content: "I don't know."}))
+		updated, err := testkit.LoadTrace(path)
+		require.NoError(t, err)
+		assert.NotEqual(t, golden, updated)
+	})
+}
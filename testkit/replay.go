@@ -0,0 +1,167 @@
+package testkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/chat"
+	"github.com/spetersoncode/gains/event"
+)
+
+// ErrReplayExhausted is returned by a Replay asked for more responses than
+// it holds.
+var ErrReplayExhausted = errors.New("testkit: no recorded responses left")
+
+// Replay is a chat.Client that answers each request with the next of a
+// fixed list of responses, such as those saved by a Recorder. It is safe
+// for concurrent use.
+type Replay struct {
+	mu        sync.Mutex
+	responses []ai.Response
+	requests  [][]ai.Message
+}
+
+var _ chat.Client = (*Replay)(nil)
+
+// NewReplay returns a Replay answering requests with responses, in order.
+func NewReplay(responses ...ai.Response) *Replay {
+	return &Replay{responses: responses}
+}
+
+// LoadReplay returns a Replay answering requests with the responses in the
+// file at path, written by Recorder.Save.
+func LoadReplay(path string) (*Replay, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var responses []ai.Response
+	if err := json.Unmarshal(raw, &responses); err != nil {
+		return nil, fmt.Errorf("testkit: %s: %w", path, err)
+	}
+	return NewReplay(responses...), nil
+}
+
+// next records a request and returns the response for it.
+func (r *Replay) next(messages []ai.Message) (*ai.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, messages)
+	if len(r.requests) > len(r.responses) {
+		return nil, ErrReplayExhausted
+	}
+	resp := r.responses[len(r.requests)-1]
+	return &resp, nil
+}
+
+// Chat returns the next response.
+func (r *Replay) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.next(messages)
+}
+
+// ChatStream streams the next response as a single delta.
+func (r *Replay) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	resp, err := r.next(messages)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan event.Event, 3)
+	ch <- event.Event{Type: event.MessageStart}
+	if resp.Content != "" {
+		ch <- event.Event{Type: event.MessageDelta, Delta: resp.Content}
+	}
+	ch <- event.Event{Type: event.MessageEnd, Response: resp}
+	close(ch)
+	return ch, nil
+}
+
+// Requests returns the conversations sent to the Replay so far.
+func (r *Replay) Requests() [][]ai.Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]ai.Message(nil), r.requests...)
+}
+
+// Remaining returns the number of responses not yet used.
+func (r *Replay) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return max(len(r.responses)-len(r.requests), 0)
+}
+
+// Recorder is a chat.Client that passes requests to another client and
+// records its responses for a Replay. It is safe for concurrent use.
+type Recorder struct {
+	client    chat.Client
+	mu        sync.Mutex
+	responses []ai.Response
+}
+
+var _ chat.Client = (*Recorder)(nil)
+
+// NewRecorder returns a Recorder around c.
+func NewRecorder(c chat.Client) *Recorder {
+	return &Recorder{client: c}
+}
+
+func (r *Recorder) record(resp *ai.Response) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responses = append(r.responses, *resp)
+}
+
+// Chat sends the request to the wrapped client and records the response.
+func (r *Recorder) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	resp, err := r.client.Chat(ctx, messages, opts...)
+	if err == nil && resp != nil {
+		r.record(resp)
+	}
+	return resp, err
+}
+
+// ChatStream sends the request to the wrapped client and records the
+// response of its MessageEnd event.
+func (r *Recorder) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+	ch, err := r.client.ChatStream(ctx, messages, opts...)
+	if err != nil {
+		return nil, err
+	}
+	out := make(chan event.Event, cap(ch))
+	go func() {
+		defer close(out)
+		for e := range ch {
+			if e.Type == event.MessageEnd && e.Response != nil {
+				r.record(e.Response)
+			}
+			out <- e
+		}
+	}()
+	return out, nil
+}
+
+// Responses returns the responses recorded so far.
+func (r *Recorder) Responses() []ai.Response {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ai.Response(nil), r.responses...)
+}
+
+// Save writes the recorded responses to the file at path for LoadReplay.
+func (r *Recorder) Save(path string) error {
+	raw, err := json.MarshalIndent(r.Responses(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(raw, '\n'), 0o644)
+}
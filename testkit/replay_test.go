@@ -0,0 +1,60 @@
+package testkit
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplay(t *testing.T) {
+	ctx := context.Background()
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}
+	r := NewReplay(ai.Response{Content: "first"}, ai.Response{Content: "second"})
+
+	resp, err := r.Chat(ctx, messages)
+	require.NoError(t, err)
+	assert.Equal(t, "first", resp.Content)
+
+	ch, err := r.ChatStream(ctx, messages)
+	require.NoError(t, err)
+	resp, _, err = event.Accumulate(ch)
+	require.NoError(t, err)
+	assert.Equal(t, "second", resp.Content)
+	assert.Zero(t, r.Remaining())
+	assert.Len(t, r.Requests(), 2)
+
+	_, err = r.Chat(ctx, messages)
+	assert.ErrorIs(t, err, ErrReplayExhausted)
+}
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	messages := []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}
+	rec := NewRecorder(NewReplay(
+		ai.Response{Content: "Hello", ToolCalls: []ai.ToolCall{{ID: "call_1", Name: "search", Arguments: `{}`}}},
+		ai.Response{Content: "Done"},
+	))
+
+	_, err := rec.Chat(ctx, messages)
+	require.NoError(t, err)
+	ch, err := rec.ChatStream(ctx, messages)
+	require.NoError(t, err)
+	for range ch {
+	}
+	require.Len(t, rec.Responses(), 2)
+
+	path := filepath.Join(t.TempDir(), "responses.json")
+	require.NoError(t, rec.Save(path))
+	replay, err := LoadReplay(path)
+	require.NoError(t, err)
+	resp, err := replay.Chat(ctx, messages)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", resp.Content)
+	assert.Equal(t, "search", resp.ToolCalls[0].Name)
+	assert.Equal(t, 1, replay.Remaining())
+}
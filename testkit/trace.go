@@ -0,0 +1,267 @@
+package testkit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spetersoncode/gains/event"
+)
+
+// Entry is one event of a Trace, reduced to the fields that describe what
+// the run did.
+type Entry struct {
+	Type      event.Type `json:"type"`
+	Step      int        `json:"step,omitempty"`
+	StepName  string     `json:"stepName,omitempty"`
+	Agent     string     `json:"agent,omitempty"`
+	Route     string     `json:"route,omitempty"`
+	Iteration int        `json:"iteration,omitempty"`
+	// Tool and Arguments identify the tool call of tool events. Arguments
+	// are compacted JSON.
+	Tool      string `json:"tool,omitempty"`
+	Arguments string `json:"arguments,omitempty"`
+	// ToolError is set on ToolCallResult entries for failed calls.
+	ToolError bool `json:"toolError,omitempty"`
+	// Content is the response content of MessageEnd entries.
+	Content string `json:"content,omitempty"`
+	// ToolCalls names the tools requested by the response of MessageEnd
+	// entries.
+	ToolCalls []string `json:"toolCalls,omitempty"`
+	Message   string   `json:"message,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// Trace is the outline of a run: the sequence of its run, step, message,
+// tool, and routing events.
+type Trace []Entry
+
+// traced reports whether events of type t appear in traces. Deltas and
+// argument fragments depend on how a response is chunked, and state and
+// activity events are UI concerns, so they are left out.
+func traced(t event.Type) bool {
+	switch t {
+	case event.RunStart, event.RunEnd, event.RunError,
+		event.StepStart, event.StepEnd, event.StepSkipped,
+		event.MessageEnd,
+		event.ToolCallApproved, event.ToolCallRejected, event.ToolCallExecuting, event.ToolCallResult,
+		event.ParallelStart, event.ParallelEnd, event.RouteSelected, event.LoopIteration,
+		event.BudgetExceeded:
+		return true
+	}
+	return false
+}
+
+// Collect drains ch and returns the trace of its events.
+func Collect(ch <-chan event.Event) Trace {
+	var events []event.Event
+	for e := range ch {
+		events = append(events, e)
+	}
+	return FromEvents(events)
+}
+
+// FromEvents returns the trace of events.
+func FromEvents(events []event.Event) Trace {
+	var trace Trace
+	for _, e := range events {
+		if !traced(e.Type) {
+			continue
+		}
+		entry := Entry{
+			Type:      e.Type,
+			Step:      e.Step,
+			StepName:  e.StepName,
+			Agent:     e.Agent,
+			Route:     e.RouteName,
+			Iteration: e.Iteration,
+			Message:   e.Message,
+		}
+		if e.ToolCall != nil {
+			entry.Tool = e.ToolCall.Name
+			entry.Arguments = compactJSON(e.ToolCall.Arguments)
+		}
+		if e.ToolResult != nil {
+			entry.ToolError = e.ToolResult.IsError
+		}
+		if e.Type == event.MessageEnd && e.Response != nil {
+			entry.Content = e.Response.Content
+			for _, tc := range e.Response.ToolCalls {
+				entry.ToolCalls = append(entry.ToolCalls, tc.Name)
+			}
+		}
+		if e.Error != nil {
+			entry.Error = e.Error.Error()
+		}
+		trace = append(trace, entry)
+	}
+	return trace
+}
+
+// compactJSON removes insignificant whitespace from s if it is valid JSON.
+func compactJSON(s string) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(s)); err != nil {
+		return s
+	}
+	return buf.String()
+}
+
+// Canonical returns a copy of the trace with events whose order varies
+// between runs put in a fixed order: consecutive tool events of the same
+// step are sorted by tool and arguments, and the events between a
+// ParallelStart and its ParallelEnd are grouped by step name.
+func (t Trace) Canonical() Trace {
+	out := slices.Clone(t)
+	canonicalize(out)
+	return out
+}
+
+// canonicalize orders t in place, as described by Canonical.
+func canonicalize(t Trace) {
+	for i := 0; i < len(t); i++ {
+		switch {
+		case t[i].Type == event.ParallelStart:
+			end := matchingParallelEnd(t, i)
+			inner := t[i+1 : end]
+			canonicalize(inner)
+			slices.SortStableFunc(inner, func(a, b Entry) int {
+				return strings.Compare(a.StepName, b.StepName)
+			})
+			i = end
+
+		case isToolEvent(t[i].Type):
+			j := i + 1
+			for j < len(t) && isToolEvent(t[j].Type) && t[j].Step == t[i].Step && t[j].Agent == t[i].Agent {
+				j++
+			}
+			slices.SortStableFunc(t[i:j], compareToolEntries)
+			i = j - 1
+		}
+	}
+}
+
+// matchingParallelEnd returns the index of the ParallelEnd closing the
+// ParallelStart at start, or len(t) if the run ended before it.
+func matchingParallelEnd(t Trace, start int) int {
+	depth := 0
+	for i := start; i < len(t); i++ {
+		switch t[i].Type {
+		case event.ParallelStart:
+			depth++
+		case event.ParallelEnd:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(t)
+}
+
+func isToolEvent(t event.Type) bool {
+	switch t {
+	case event.ToolCallApproved, event.ToolCallRejected, event.ToolCallExecuting, event.ToolCallResult:
+		return true
+	}
+	return false
+}
+
+// toolEventRank orders the events of one tool call.
+var toolEventRank = map[event.Type]int{
+	event.ToolCallApproved:  0,
+	event.ToolCallRejected:  0,
+	event.ToolCallExecuting: 1,
+	event.ToolCallResult:    2,
+}
+
+func compareToolEntries(a, b Entry) int {
+	if c := strings.Compare(a.Tool, b.Tool); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Arguments, b.Arguments); c != 0 {
+		return c
+	}
+	return toolEventRank[a.Type] - toolEventRank[b.Type]
+}
+
+// Diff compares the canonical forms of two traces and describes where they
+// differ, or returns an empty string if they are equivalent.
+func Diff(want, got Trace) string {
+	wantLines := want.Canonical().lines()
+	gotLines := got.Canonical().lines()
+	if slices.Equal(wantLines, gotLines) {
+		return ""
+	}
+
+	first := 0
+	for first < len(wantLines) && first < len(gotLines) && wantLines[first] == gotLines[first] {
+		first++
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "traces differ at entry %d (want %d entries, got %d):\n", first+1, len(wantLines), len(gotLines))
+	const context = 5
+	for i := first; i < len(wantLines) && i < first+context; i++ {
+		fmt.Fprintf(&b, "- %s\n", wantLines[i])
+	}
+	for i := first; i < len(gotLines) && i < first+context; i++ {
+		fmt.Fprintf(&b, "+ %s\n", gotLines[i])
+	}
+	return b.String()
+}
+
+// lines returns the entries of t as JSON lines.
+func (t Trace) lines() []string {
+	lines := make([]string, len(t))
+	for i, e := range t {
+		raw, _ := json.Marshal(e)
+		lines[i] = string(raw)
+	}
+	return lines
+}
+
+// WriteTo writes the trace to w as one JSON object per line.
+func (t Trace) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	for _, line := range t.lines() {
+		m, err := io.WriteString(w, line+"\n")
+		n += int64(m)
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// ReadTrace reads a trace written by Trace.WriteTo.
+func ReadTrace(r io.Reader) (Trace, error) {
+	var trace Trace
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("testkit: trace line %d: %w", line, err)
+		}
+		trace = append(trace, e)
+	}
+	return trace, scanner.Err()
+}
+
+// LoadTrace reads a trace from the file at path.
+func LoadTrace(path string) (Trace, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadTrace(f)
+}
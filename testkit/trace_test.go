@@ -0,0 +1,110 @@
+package testkit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromEvents(t *testing.T) {
+	call := &ai.ToolCall{ID: "call_1", Name: "search", Arguments: `{ "q": "go" }`}
+	trace := FromEvents([]event.Event{
+		{Type: event.RunStart, RunID: "run-1"},
+		{Type: event.StepStart, Step: 1},
+		{Type: event.MessageStart, Step: 1, MessageID: "msg_1"},
+		{Type: event.MessageDelta, Step: 1, Delta: "Searching"},
+		{Type: event.MessageEnd, Step: 1, Response: &ai.Response{Content: "Searching", ToolCalls: []ai.ToolCall{*call}}},
+		{Type: event.ToolCallExecuting, Step: 1, ToolCall: call},
+		{Type: event.ToolCallResult, Step: 1, ToolCall: call, ToolResult: &ai.ToolResult{IsError: true}},
+		{Type: event.RunError, Error: errors.New("boom")},
+	})
+
+	assert.Equal(t, Trace{
+		{Type: event.RunStart},
+		{Type: event.StepStart, Step: 1},
+		{Type: event.MessageEnd, Step: 1, Content: "Searching", ToolCalls: []string{"search"}},
+		{Type: event.ToolCallExecuting, Step: 1, Tool: "search", Arguments: `{"q":"go"}`},
+		{Type: event.ToolCallResult, Step: 1, Tool: "search", Arguments: `{"q":"go"}`, ToolError: true},
+		{Type: event.RunError, Error: "boom"},
+	}, trace)
+}
+
+func TestTrace_Canonical(t *testing.T) {
+	t.Run("orders parallel tool calls", func(t *testing.T) {
+		a := Trace{
+			{Type: event.ToolCallExecuting, Step: 1, Tool: "b"},
+			{Type: event.ToolCallExecuting, Step: 1, Tool: "a"},
+			{Type: event.ToolCallResult, Step: 1, Tool: "a"},
+			{Type: event.ToolCallResult, Step: 1, Tool: "b"},
+			{Type: event.StepEnd, Step: 1},
+		}
+		b := Trace{
+			{Type: event.ToolCallExecuting, Step: 1, Tool: "a"},
+			{Type: event.ToolCallResult, Step: 1, Tool: "a"},
+			{Type: event.ToolCallExecuting, Step: 1, Tool: "b"},
+			{Type: event.ToolCallResult, Step: 1, Tool: "b"},
+			{Type: event.StepEnd, Step: 1},
+		}
+		assert.Equal(t, b, a.Canonical())
+		assert.Empty(t, Diff(a, b))
+		assert.Equal(t, "b", a[0].Tool, "Canonical does not modify the trace")
+	})
+
+	t.Run("groups parallel branches", func(t *testing.T) {
+		a := Trace{
+			{Type: event.ParallelStart, StepName: "fanout"},
+			{Type: event.StepStart, StepName: "y"},
+			{Type: event.StepStart, StepName: "x"},
+			{Type: event.StepEnd, StepName: "x"},
+			{Type: event.StepEnd, StepName: "y"},
+			{Type: event.ParallelEnd, StepName: "fanout"},
+		}
+		b := Trace{
+			{Type: event.ParallelStart, StepName: "fanout"},
+			{Type: event.StepStart, StepName: "x"},
+			{Type: event.StepEnd, StepName: "x"},
+			{Type: event.StepStart, StepName: "y"},
+			{Type: event.StepEnd, StepName: "y"},
+			{Type: event.ParallelEnd, StepName: "fanout"},
+		}
+		assert.Empty(t, Diff(a, b))
+	})
+
+	t.Run("keeps sequential steps in order", func(t *testing.T) {
+		a := Trace{{Type: event.StepStart, StepName: "b"}, {Type: event.StepStart, StepName: "a"}}
+		b := Trace{{Type: event.StepStart, StepName: "a"}, {Type: event.StepStart, StepName: "b"}}
+		assert.NotEmpty(t, Diff(a, b))
+	})
+}
+
+func TestDiff(t *testing.T) {
+	want := Trace{{Type: event.RunStart}, {Type: event.StepStart, Step: 1}, {Type: event.RunEnd}}
+	got := Trace{{Type: event.RunStart}, {Type: event.RunError, Error: "boom"}}
+
+	diff := Diff(want, got)
+	assert.Contains(t, diff, "traces differ at entry 2 (want 3 entries, got 2)")
+	assert.Contains(t, diff, `- {"type":"step_start","step":1}`)
+	assert.Contains(t, diff, `+ {"type":"run_error","error":"boom"}`)
+}
+
+func TestReadTrace(t *testing.T) {
+	trace := Trace{
+		{Type: event.RunStart},
+		{Type: event.ToolCallExecuting, Step: 1, Tool: "search", Arguments: `{"q":"go"}`},
+	}
+	var sb strings.Builder
+	_, err := trace.WriteTo(&sb)
+	require.NoError(t, err)
+
+	read, err := ReadTrace(strings.NewReader(sb.String()))
+	require.NoError(t, err)
+	assert.Equal(t, trace, read)
+
+	_, err = ReadTrace(strings.NewReader("{\n"))
+	assert.ErrorContains(t, err, "trace line 1")
+}
@@ -0,0 +1,38 @@
+// Package tokenizer counts tokens offline for context budgets, truncation,
+// and cost estimates.
+//
+// EncodeCount returns the tokens text takes for a model, chosen by model ID
+// and computed without network calls or vocabulary files:
+//
+//	n := tokenizer.EncodeCount("gpt-4o", prompt)
+//
+// # Built-in Estimators
+//
+// OpenAI models use an estimator that splits text with tiktoken's
+// pre-tokenization rules and estimates the tokens of each piece. Claude
+// models are estimated at 3.5 characters per token and Gemini models at 4.
+// Models of other families use Default. Model IDs are matched by family
+// name, so Bedrock and Vertex AI IDs such as "anthropic.claude-3-haiku"
+// resolve too.
+//
+// gains.EstimateTokens, and with it ContextManager, memory summarization,
+// and client.Client.CountTokens for OpenAI models, counts with these
+// estimators. They are estimates, not encodings: the package ships no BPE
+// vocabularies, and CountTokens marks the counts it makes with them as
+// Estimated.
+//
+// # Custom Tokenizers
+//
+// Register a Tokenizer for a model ID prefix to replace an estimate with an
+// exact count, for example from a BPE library:
+//
+//	tokenizer.Register("gpt-4o", tokenizer.Func(func(text string) int {
+//	    return len(enc.Encode(text, nil, nil))
+//	}))
+//
+// Registered tokenizers take precedence over the built-in estimators, and
+// the longest matching prefix wins. Any Tokenizer's Count method also fits
+// ingest.NewTokenChunker:
+//
+//	chunker := ingest.NewTokenChunker(512, 64, tokenizer.For("gpt-4o").Count)
+package tokenizer
@@ -0,0 +1,61 @@
+package tokenizer
+
+import (
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+)
+
+// pieces splits text the way tiktoken's cl100k and o200k encodings do before
+// applying byte pair merges: contractions, words with one leading
+// non-letter, numbers of up to three digits, punctuation runs, and
+// whitespace. RE2 lacks tiktoken's "\s+(?!\S)" lookahead; dropping it moves
+// a piece boundary inside whitespace runs but not the number of pieces.
+var pieces = regexp.MustCompile(`(?i:'s|'t|'re|'ve|'m|'ll|'d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+`)
+
+// NewOpenAI returns the offline estimator for OpenAI models. It splits text
+// into the pieces tiktoken encodes separately and estimates the merges
+// within each piece: a word of up to eight ASCII letters is one token, as
+// common English words are, and longer words take one more token per eight
+// letters; non-ASCII letters count one token each; numbers, whitespace, and
+// short punctuation runs are one token. The result is an estimate; register
+// a BPE tokenizer with Register where exact counts matter.
+func NewOpenAI() Tokenizer {
+	return Func(func(text string) int {
+		n := 0
+		for _, piece := range pieces.FindAllString(text, -1) {
+			n += pieceTokens(piece)
+		}
+		return n
+	})
+}
+
+// pieceTokens estimates the tokens of one pre-tokenized piece. Whitespace
+// and digits add nothing to pieces containing letters or punctuation, since
+// tiktoken merges a leading space into the word after it.
+func pieceTokens(piece string) int {
+	var ascii, other, symbols int
+	for _, r := range piece {
+		switch {
+		case r < utf8.RuneSelf && unicode.IsLetter(r):
+			ascii++
+		case unicode.IsLetter(r):
+			other++
+		case unicode.IsSpace(r), unicode.IsDigit(r):
+		default:
+			symbols++
+		}
+	}
+
+	switch {
+	case ascii > 0 || other > 0:
+		n := other
+		if ascii > 0 {
+			n += 1 + (ascii-1)/8
+		}
+		return n
+	case symbols > 0:
+		return 1 + (symbols-1)/3
+	}
+	return 1
+}
@@ -0,0 +1,145 @@
+package tokenizer
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Tokenizer counts the tokens text encodes to for a family of models.
+// Implementations must be safe for concurrent use.
+type Tokenizer interface {
+	Count(text string) int
+}
+
+// Func adapts a function to the Tokenizer interface.
+type Func func(text string) int
+
+// Count returns f(text).
+func (f Func) Count(text string) int {
+	return f(text)
+}
+
+// Heuristic returns a Tokenizer estimating one token per charsPerToken bytes
+// of text, rounding down.
+func Heuristic(charsPerToken float64) Tokenizer {
+	return Func(func(text string) int {
+		return int(float64(len(text)) / charsPerToken)
+	})
+}
+
+// Built-in estimators. None needs network access or vocabulary files.
+var (
+	// OpenAI estimates counts of OpenAI's o200k and cl100k encodings by
+	// splitting text as tiktoken does and estimating the tokens of each
+	// piece. See NewOpenAI.
+	OpenAI = NewOpenAI()
+	// Claude estimates Anthropic models at 3.5 characters per token.
+	Claude = Heuristic(3.5)
+	// Gemini estimates Google models at 4 characters per token.
+	Gemini = Heuristic(4)
+	// Default estimates models of unknown families at 4 characters per
+	// token.
+	Default = Heuristic(4)
+)
+
+var (
+	mu         sync.RWMutex
+	registered = map[string]Tokenizer{}
+	prefixes   []string // registered prefixes, longest first
+)
+
+// Register makes t the tokenizer of every model whose ID starts with
+// prefix, taking precedence over the built-in estimators. The longest
+// matching prefix wins. Register a real BPE tokenizer here where exact
+// counts matter:
+//
+//	tokenizer.Register("gpt-4o", tokenizer.Func(func(text string) int {
+//	    return len(enc.Encode(text, nil, nil))
+//	}))
+func Register(prefix string, t Tokenizer) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, exists := registered[prefix]; !exists {
+		prefixes = append(prefixes, prefix)
+		sort.SliceStable(prefixes, func(i, j int) bool {
+			return len(prefixes[i]) > len(prefixes[j])
+		})
+	}
+	registered[prefix] = t
+}
+
+// For returns the tokenizer of a model ID: a registered tokenizer if one
+// matches, otherwise the built-in estimator of the model's family, or
+// Default if the family is unknown or model is empty.
+func For(model string) Tokenizer {
+	if t, ok := Lookup(model); ok {
+		return t
+	}
+	return Default
+}
+
+// Lookup returns the registered tokenizer or built-in estimator of a model
+// ID, and false if the ID matches no registered prefix and no known model
+// family.
+func Lookup(model string) (Tokenizer, bool) {
+	mu.RLock()
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(model, prefix) {
+			t := registered[prefix]
+			mu.RUnlock()
+			return t, true
+		}
+	}
+	mu.RUnlock()
+
+	switch family(model) {
+	case familyOpenAI:
+		return OpenAI, true
+	case familyClaude:
+		return Claude, true
+	case familyGemini:
+		return Gemini, true
+	}
+	return nil, false
+}
+
+// EncodeCount returns the number of tokens text encodes to for model,
+// using the tokenizer For returns.
+func EncodeCount(model, text string) int {
+	if text == "" {
+		return 0
+	}
+	return For(model).Count(text)
+}
+
+type modelFamily int
+
+const (
+	familyUnknown modelFamily = iota
+	familyOpenAI
+	familyClaude
+	familyGemini
+)
+
+// family identifies the model family of a model ID. IDs of models served
+// through other platforms, such as "anthropic.claude-3-haiku" on Bedrock or
+// "publishers/google/models/gemini-2.0-flash" on Vertex AI, are recognized
+// by the family name they contain.
+func family(model string) modelFamily {
+	id := strings.ToLower(model)
+	if i := strings.LastIndexByte(id, '/'); i >= 0 {
+		id = id[i+1:]
+	}
+	switch {
+	case strings.Contains(id, "claude"):
+		return familyClaude
+	case strings.Contains(id, "gemini"), strings.Contains(id, "gemma"):
+		return familyGemini
+	case strings.HasPrefix(id, "gpt-"), strings.HasPrefix(id, "chatgpt-"),
+		strings.HasPrefix(id, "o1"), strings.HasPrefix(id, "o3"), strings.HasPrefix(id, "o4"),
+		strings.HasPrefix(id, "text-embedding-"), strings.HasPrefix(id, "davinci"):
+		return familyOpenAI
+	}
+	return familyUnknown
+}
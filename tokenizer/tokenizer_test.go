@@ -0,0 +1,79 @@
+package tokenizer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeuristic(t *testing.T) {
+	assert.Equal(t, 20, Heuristic(3.5).Count(strings.Repeat("x", 70)))
+	assert.Equal(t, 17, Heuristic(4).Count(strings.Repeat("x", 70)))
+	assert.Equal(t, 0, Heuristic(4).Count(""))
+}
+
+func TestOpenAI(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"hello", 1},
+		{"Hello world", 2},
+		{"Hello, world!", 4},
+		{"I'm here", 3},
+		{"internationalization", 3},
+		{"12345", 2},
+		{"a\n\nb", 3},
+		{"func main() {}", 4},
+		{"日本語", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.want, OpenAI.Count(tt.text))
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		model string
+		want  modelFamily
+	}{
+		{"gpt-4o", familyOpenAI},
+		{"o3-mini", familyOpenAI},
+		{"text-embedding-3-small", familyOpenAI},
+		{"claude-sonnet-4-5", familyClaude},
+		{"anthropic.claude-3-haiku-20240307-v1:0", familyClaude},
+		{"gemini-2.5-flash", familyGemini},
+		{"publishers/google/models/gemini-2.0-flash", familyGemini},
+		{"llama-3", familyUnknown},
+		{"", familyUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			assert.Equal(t, tt.want, family(tt.model))
+			_, ok := Lookup(tt.model)
+			assert.Equal(t, tt.want != familyUnknown, ok)
+		})
+	}
+
+	text := strings.Repeat("x", 70)
+	assert.Equal(t, 20, EncodeCount("claude-haiku-4-5", text))
+	assert.Equal(t, 17, EncodeCount("gemini-2.5-pro", text))
+	assert.Equal(t, 17, EncodeCount("unknown", text))
+}
+
+func TestRegister(t *testing.T) {
+	Register("test-model", Func(func(string) int { return 1 }))
+	Register("test-model-large", Func(func(string) int { return 2 }))
+
+	assert.Equal(t, 1, EncodeCount("test-model-small", "anything"))
+	assert.Equal(t, 2, EncodeCount("test-model-large-v2", "anything"))
+	assert.Equal(t, 0, EncodeCount("test-model", ""))
+
+	// Re-registering a prefix replaces its tokenizer
+	Register("test-model", Func(func(string) int { return 3 }))
+	assert.Equal(t, 3, EncodeCount("test-model-small", "anything"))
+}
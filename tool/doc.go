@@ -41,6 +41,10 @@
 //	default:"value"  - Default value
 //	minItems:"1"     - Minimum array items
 //	maxItems:"10"    - Maximum array items
+//	oneof:"a,b"      - Variants registered with gains.RegisterVariant
+//
+// Nested and embedded structs, slices, maps, pointers, and time.Time fields
+// are described as documented on gains.SchemaFor.
 //
 // # Built-in Tools
 //
@@ -88,4 +92,32 @@
 //   - ClientTools(): image, embedding, chat (requires client)
 //   - StandardTools(): file, HTTP, search (no client required)
 //   - AllTools(): all tools including client tools
+//
+// # Large Results
+//
+// Use WithMaxResultSize to keep oversized tool output out of the context window.
+// Results over the limit are stored in the registry and the model receives the
+// first page with a reference it can pass to the built-in read_tool_result tool:
+//
+//	registry := tool.NewRegistry(tool.WithMaxResultSize(16 * 1024))
+//
+// # Argument Validation
+//
+// Models occasionally send arguments that break the tool's schema, such as a
+// missing required field or a number out of range. WithArgumentValidation
+// checks arguments with schema.Validate before each handler runs and
+// answers invalid calls with an error result listing the violations, so the
+// model can retry:
+//
+//	registry := tool.NewRegistry(tool.WithArgumentValidation())
+//
+// # System Prompt Summary
+//
+// SystemPrompt renders the registered tools as a short system prompt section.
+// Add guidance for tools the model tends to misuse with SetGuidance:
+//
+//	registry.SetGuidance("search_files", "Prefer this over read_file when the location is unknown.")
+//	system := basePrompt + "\n\n" + registry.SystemPrompt()
+//
+// Agents can inject the summary automatically with agent.WithToolSummary.
 package tool
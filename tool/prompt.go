@@ -0,0 +1,79 @@
+package tool
+
+import (
+	"sort"
+	"strings"
+)
+
+// SetGuidance attaches usage guidance to a registered tool, such as when to
+// prefer it over similar tools. Guidance appears in SystemPrompt and is
+// removed when the tool is unregistered.
+// Returns ErrToolNotFound if no tool with that name is registered.
+func (r *Registry) SetGuidance(name, guidance string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rt, ok := r.tools[name]
+	if !ok {
+		return &ErrToolNotFound{Name: name}
+	}
+	rt.guidance = guidance
+	r.tools[name] = rt
+	return nil
+}
+
+// SystemPrompt renders a concise summary of the registered tools for use in
+// a system prompt. Each tool is listed by name with the first line of its
+// description and any guidance set with SetGuidance. The summary reflects
+// the registry's contents at the time of the call, so calling it per request
+// keeps the prompt in sync as tools are added or removed.
+// Returns an empty string if the registry has no tools.
+//
+// Example output:
+//
+//	## Available Tools
+//
+//	- get_weather: Get current weather for a city.
+//	  Guidance: Use for forecasts up to 7 days out.
+//	- search_files: Search for patterns in files.
+func (r *Registry) SystemPrompt() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if len(r.tools) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(r.tools))
+	for name := range r.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("## Available Tools\n")
+	for _, name := range names {
+		rt := r.tools[name]
+		b.WriteString("\n- ")
+		b.WriteString(name)
+		if desc := firstLine(rt.tool.Description); desc != "" {
+			b.WriteString(": ")
+			b.WriteString(desc)
+		}
+		if guidance := strings.TrimSpace(rt.guidance); guidance != "" {
+			b.WriteString("\n  Guidance: ")
+			b.WriteString(strings.Join(strings.Fields(guidance), " "))
+		}
+	}
+	return b.String()
+}
+
+// firstLine returns the first non-empty line of s, trimmed.
+func firstLine(s string) string {
+	for line := range strings.Lines(s) {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
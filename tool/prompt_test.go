@@ -0,0 +1,55 @@
+package tool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistrySystemPrompt(t *testing.T) {
+	noop := func(ctx context.Context, args struct{}) (string, error) { return "", nil }
+
+	t.Run("empty registry renders nothing", func(t *testing.T) {
+		assert.Empty(t, NewRegistry().SystemPrompt())
+	})
+
+	t.Run("lists tools sorted with first description line", func(t *testing.T) {
+		registry := NewRegistry().Add(
+			Func("search", "Search the web.\nReturns up to ten results as JSON.", noop),
+			Func("calculate", "Evaluate a math expression.", noop),
+		)
+
+		expected := "## Available Tools\n" +
+			"\n- calculate: Evaluate a math expression." +
+			"\n- search: Search the web."
+		assert.Equal(t, expected, registry.SystemPrompt())
+	})
+
+	t.Run("includes guidance", func(t *testing.T) {
+		registry := NewRegistry().Add(Func("search", "Search the web.", noop))
+		require.NoError(t, registry.SetGuidance("search", "Use only for\n  current events."))
+
+		assert.Contains(t, registry.SystemPrompt(),
+			"- search: Search the web.\n  Guidance: Use only for current events.")
+	})
+
+	t.Run("guidance for unknown tool fails", func(t *testing.T) {
+		err := NewRegistry().SetGuidance("missing", "text")
+		var notFound *ErrToolNotFound
+		assert.ErrorAs(t, err, &notFound)
+	})
+
+	t.Run("tracks registry changes", func(t *testing.T) {
+		registry := NewRegistry().Add(Func("search", "Search the web.", noop))
+		require.NoError(t, registry.SetGuidance("search", "Prefer cached results."))
+		assert.Contains(t, registry.SystemPrompt(), "search")
+
+		registry.Unregister("search")
+		assert.Empty(t, registry.SystemPrompt())
+
+		registry.Add(Func("search", "Search the web.", noop))
+		assert.NotContains(t, registry.SystemPrompt(), "Guidance")
+	})
+}
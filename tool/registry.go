@@ -12,7 +12,8 @@ import (
 type registeredTool struct {
 	tool     ai.Tool
 	handler  Handler
-	isClient bool // true for client-side tools that have no local handler
+	isClient bool   // true for client-side tools that have no local handler
+	guidance string // optional usage guidance rendered by SystemPrompt
 }
 
 // Registry manages registered tools and their handlers.
@@ -20,13 +21,30 @@ type registeredTool struct {
 type Registry struct {
 	mu    sync.RWMutex
 	tools map[string]registeredTool
+
+	// maxResultSize caps inline result size; oversized results are paginated.
+	maxResultSize int
+	results       *resultStore
+
+	// validateArgs checks arguments against parameter schemas before execution.
+	validateArgs bool
 }
 
 // NewRegistry creates an empty tool registry.
-func NewRegistry() *Registry {
-	return &Registry{
-		tools: make(map[string]registeredTool),
+// If WithMaxResultSize is given, the built-in read_tool_result tool is
+// registered so the model can page through oversized results.
+func NewRegistry(opts ...RegistryOption) *Registry {
+	r := &Registry{
+		tools:   make(map[string]registeredTool),
+		results: newResultStore(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.maxResultSize > 0 {
+		r.registerReadToolResult()
 	}
+	return r
 }
 
 // Register adds a tool with its handler to the registry.
@@ -221,6 +239,11 @@ func MustRegisterFunc[T any](r *Registry, name, description string, fn TypedHand
 // If the tool is a client-side tool, returns ErrClientTool.
 // If the handler returns an error, the error is captured in ToolResult.IsError
 // and the error message is returned as the content (allowing the model to recover).
+// If the registry has a maximum result size, oversized content is stored and
+// replaced with its first page (see WithMaxResultSize).
+// With WithArgumentValidation, arguments that do not match the tool's
+// parameter schema are returned as an error result without calling the handler.
+// In a dry run (see gains.WithDryRun), the handler is not called.
 func (r *Registry) Execute(ctx context.Context, call ai.ToolCall) (ai.ToolResult, error) {
 	r.mu.RLock()
 	rt, ok := r.tools[call.Name]
@@ -234,6 +257,23 @@ func (r *Registry) Execute(ctx context.Context, call ai.ToolCall) (ai.ToolResult
 		return ai.ToolResult{}, &ErrClientTool{Name: call.Name}
 	}
 
+	if r.validateArgs {
+		if err := validateArguments(rt.tool, call); err != nil {
+			return ai.ToolResult{
+				ToolCallID: call.ID,
+				Content:    err.Error(),
+				IsError:    true,
+			}, nil
+		}
+	}
+
+	if ai.IsDryRun(ctx) {
+		return ai.ToolResult{
+			ToolCallID: call.ID,
+			Content:    "dry run: " + call.Name + " not executed",
+		}, nil
+	}
+
 	content, err := rt.handler(ctx, call)
 	if err != nil {
 		// Return error as tool result so model can potentially recover
@@ -244,6 +284,11 @@ func (r *Registry) Execute(ctx context.Context, call ai.ToolCall) (ai.ToolResult
 		}, nil
 	}
 
+	// Pages from read_tool_result are already sized to fit
+	if call.Name != ReadToolResultName {
+		content = r.paginateResult(content)
+	}
+
 	return ai.ToolResult{
 		ToolCallID: call.ID,
 		Content:    content,
@@ -326,3 +371,42 @@ func (r *Registry) Add(regs ...Registration) *Registry {
 	}
 	return r
 }
+
+// Clone returns an independent copy of the registry, so tools can be added
+// to it without affecting the original.
+func (r *Registry) Clone() *Registry {
+	return r.Wrap(func(_ ai.Tool, next Handler) Handler { return next })
+}
+
+// Wrap returns a copy of the registry whose handlers are wrapped by mw, for
+// cross-cutting behavior such as redaction, auditing, or rate limiting:
+//
+//	audited := registry.Wrap(func(t ai.Tool, next tool.Handler) tool.Handler {
+//	    return func(ctx context.Context, call ai.ToolCall) (string, error) {
+//	        log.Printf("calling %s", call.Name)
+//	        return next(ctx, call)
+//	    }
+//	})
+//
+// Client tools, guidance, the maximum result size, and argument validation
+// carry over unchanged.
+// The copy is independent: tools registered on either registry afterwards
+// are not added to the other.
+func (r *Registry) Wrap(mw func(t ai.Tool, next Handler) Handler) *Registry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wrapped := &Registry{
+		tools:         make(map[string]registeredTool, len(r.tools)),
+		maxResultSize: r.maxResultSize,
+		results:       r.results,
+		validateArgs:  r.validateArgs,
+	}
+	for name, rt := range r.tools {
+		if !rt.isClient {
+			rt.handler = mw(rt.tool, rt.handler)
+		}
+		wrapped.tools[name] = rt
+	}
+	return wrapped
+}
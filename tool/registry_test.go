@@ -185,3 +185,104 @@ func TestRegistryExecuteWithFluentRegistration(t *testing.T) {
 		assert.False(t, result.IsError)
 	})
 }
+
+func TestRegistryExecuteDryRun(t *testing.T) {
+	called := false
+	registry := NewRegistry().Add(
+		Func("delete_file", "Delete a file", func(ctx context.Context, args struct {
+			Path string `json:"path"`
+		}) (string, error) {
+			called = true
+			return "deleted", nil
+		}),
+	)
+
+	result, err := registry.Execute(ai.WithDryRun(context.Background()), ai.ToolCall{
+		ID:        "call_1",
+		Name:      "delete_file",
+		Arguments: `{"path": "a.txt"}`,
+	})
+
+	require.NoError(t, err)
+	assert.False(t, called)
+	assert.Equal(t, "call_1", result.ToolCallID)
+	assert.Equal(t, "dry run: delete_file not executed", result.Content)
+	assert.False(t, result.IsError)
+}
+
+func TestRegistryExecuteArgumentValidation(t *testing.T) {
+	called := false
+	handler := Func("search", "Search", func(ctx context.Context, args struct {
+		Query string `json:"query" required:"true"`
+		Limit int    `json:"limit" min:"1" max:"100"`
+	}) (string, error) {
+		called = true
+		return "results", nil
+	})
+
+	registry := NewRegistry(WithArgumentValidation()).Add(handler)
+
+	result, err := registry.Execute(context.Background(), ai.ToolCall{
+		ID:        "call_1",
+		Name:      "search",
+		Arguments: `{"limit": 500}`,
+	})
+	require.NoError(t, err)
+	assert.False(t, called)
+	assert.True(t, result.IsError)
+	assert.Contains(t, result.Content, "invalid arguments for search")
+	assert.Contains(t, result.Content, `$: missing required property "query"`)
+	assert.Contains(t, result.Content, "$.limit: value 500 is greater than the maximum 100")
+
+	result, err = registry.Execute(context.Background(), ai.ToolCall{
+		ID:        "call_2",
+		Name:      "search",
+		Arguments: `{"query": "go", "limit": 5}`,
+	})
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.Equal(t, "results", result.Content)
+
+	// Without the option, arguments reach the handler unchecked
+	called = false
+	result, err = NewRegistry().Add(handler).Execute(context.Background(), ai.ToolCall{
+		ID:        "call_3",
+		Name:      "search",
+		Arguments: `{"limit": 500}`,
+	})
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.False(t, result.IsError)
+}
+
+func TestRegistryWrap(t *testing.T) {
+	registry := NewRegistry().Add(
+		WithHandler("echo", "Echo arguments", nil, func(ctx context.Context, call ai.ToolCall) (string, error) {
+			return call.Arguments, nil
+		}),
+	)
+	require.NoError(t, registry.RegisterClientTool(ai.Tool{Name: "confirm"}))
+	require.NoError(t, registry.SetGuidance("echo", "Use sparingly."))
+
+	var seen []string
+	wrapped := registry.Wrap(func(tl ai.Tool, next Handler) Handler {
+		return func(ctx context.Context, call ai.ToolCall) (string, error) {
+			seen = append(seen, tl.Name)
+			content, err := next(ctx, call)
+			return "wrapped " + content, err
+		}
+	})
+
+	result, err := wrapped.Execute(context.Background(), ai.ToolCall{ID: "1", Name: "echo", Arguments: "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "wrapped hi", result.Content)
+	assert.Equal(t, []string{"echo"}, seen)
+	assert.True(t, wrapped.IsClientTool("confirm"))
+	assert.Contains(t, wrapped.SystemPrompt(), "Use sparingly.")
+
+	t.Run("original is unchanged", func(t *testing.T) {
+		result, err := registry.Execute(context.Background(), ai.ToolCall{ID: "1", Name: "echo", Arguments: "hi"})
+		require.NoError(t, err)
+		assert.Equal(t, "hi", result.Content)
+	})
+}
@@ -0,0 +1,142 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"unicode/utf8"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// ReadToolResultName is the name of the built-in tool registered when a
+// registry has a maximum result size. The model calls it to page through
+// results that were too large to return inline.
+const ReadToolResultName = "read_tool_result"
+
+// RegistryOption configures a Registry.
+type RegistryOption func(*Registry)
+
+// WithMaxResultSize limits the size in bytes of tool results returned to the model.
+// Results larger than the limit are stored in the registry and replaced with
+// their first page plus a reference the model can pass to the built-in
+// read_tool_result tool to read further pages. A value of 0 disables the limit.
+func WithMaxResultSize(bytes int) RegistryOption {
+	return func(r *Registry) {
+		r.maxResultSize = bytes
+	}
+}
+
+// ReadToolResultArgs are the arguments for the read_tool_result tool.
+type ReadToolResultArgs struct {
+	ResultID string `json:"result_id" desc:"Identifier of the stored result" required:"true"`
+	Offset   int    `json:"offset" desc:"Byte offset to start reading from" min:"0"`
+}
+
+// resultStore holds oversized tool results for paginated reads.
+type resultStore struct {
+	mu      sync.RWMutex
+	results map[string]string
+}
+
+func newResultStore() *resultStore {
+	return &resultStore{results: make(map[string]string)}
+}
+
+func (s *resultStore) put(content string) string {
+	id := "result-" + ai.NewID()
+	s.mu.Lock()
+	s.results[id] = content
+	s.mu.Unlock()
+	return id
+}
+
+func (s *resultStore) get(id string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	content, ok := s.results[id]
+	return content, ok
+}
+
+func (s *resultStore) clear() {
+	s.mu.Lock()
+	s.results = make(map[string]string)
+	s.mu.Unlock()
+}
+
+// StoredResult returns the full content of an oversized result by its ID.
+// Returns false if no result with that ID is stored.
+func (r *Registry) StoredResult(id string) (string, bool) {
+	return r.results.get(id)
+}
+
+// ClearStoredResults discards all oversized results held by the registry.
+// Call this between conversations to release memory; references to cleared
+// results can no longer be read by the model.
+func (r *Registry) ClearStoredResults() {
+	r.results.clear()
+}
+
+// paginateResult stores content if it exceeds the registry's maximum result
+// size and returns the first page with a reference to the stored result.
+// Content within the limit is returned unchanged.
+func (r *Registry) paginateResult(content string) string {
+	if r.maxResultSize <= 0 || len(content) <= r.maxResultSize {
+		return content
+	}
+	id := r.results.put(content)
+	return r.resultPage(id, content, 0)
+}
+
+// resultPage formats the page of content starting at offset.
+func (r *Registry) resultPage(id, content string, offset int) string {
+	end := pageEnd(content, offset, r.maxResultSize)
+	page := content[offset:end]
+	if end >= len(content) {
+		return fmt.Sprintf("%s\n\n[End of result %s: bytes %d-%d of %d.]",
+			page, id, offset, end, len(content))
+	}
+	return fmt.Sprintf("%s\n\n[Result truncated: bytes %d-%d of %d. Call %s with result_id=%q and offset=%d to read more.]",
+		page, offset, end, len(content), ReadToolResultName, id, end)
+}
+
+// pageEnd returns the end offset of a page, backing off to a rune boundary
+// so multi-byte characters are never split across pages.
+func pageEnd(content string, offset, size int) int {
+	end := offset + size
+	if end >= len(content) {
+		return len(content)
+	}
+	for end > offset && !utf8.RuneStart(content[end]) {
+		end--
+	}
+	if end == offset {
+		// Page smaller than a single rune; include the whole rune.
+		_, n := utf8.DecodeRuneInString(content[offset:])
+		end = offset + n
+	}
+	return end
+}
+
+// registerReadToolResult adds the built-in read_tool_result tool.
+func (r *Registry) registerReadToolResult() {
+	t, h := MustBind(ReadToolResultName,
+		"Read a page of a tool result that was too large to return in full. "+
+			"Use the result_id and offset given in the truncation notice.",
+		func(ctx context.Context, args ReadToolResultArgs) (string, error) {
+			content, ok := r.results.get(args.ResultID)
+			if !ok {
+				return "", fmt.Errorf("result not found: %s", args.ResultID)
+			}
+			if args.Offset < 0 || args.Offset >= len(content) {
+				return "", fmt.Errorf("offset %d out of range (result is %d bytes)", args.Offset, len(content))
+			}
+			// Align to a rune boundary in case the offset points mid-character
+			offset := args.Offset
+			for offset > 0 && !utf8.RuneStart(content[offset]) {
+				offset--
+			}
+			return r.resultPage(args.ResultID, content, offset), nil
+		})
+	r.tools[ReadToolResultName] = registeredTool{tool: t, handler: h}
+}
@@ -0,0 +1,120 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryMaxResultSize(t *testing.T) {
+	large := strings.Repeat("abcdefghij", 10) // 100 bytes
+
+	newRegistry := func() *Registry {
+		return NewRegistry(WithMaxResultSize(40)).Add(
+			Func("dump", "Dump data", func(ctx context.Context, args struct{}) (string, error) {
+				return large, nil
+			}),
+			Func("small", "Small data", func(ctx context.Context, args struct{}) (string, error) {
+				return "tiny", nil
+			}),
+		)
+	}
+
+	t.Run("registers read_tool_result", func(t *testing.T) {
+		registry := newRegistry()
+		_, ok := registry.GetTool(ReadToolResultName)
+		assert.True(t, ok)
+
+		_, ok = NewRegistry().GetTool(ReadToolResultName)
+		assert.False(t, ok, "not registered without a limit")
+	})
+
+	t.Run("leaves small results unchanged", func(t *testing.T) {
+		result, err := newRegistry().Execute(context.Background(), ai.ToolCall{ID: "c1", Name: "small", Arguments: "{}"})
+		require.NoError(t, err)
+		assert.Equal(t, "tiny", result.Content)
+	})
+
+	t.Run("paginates oversized results", func(t *testing.T) {
+		registry := newRegistry()
+
+		result, err := registry.Execute(context.Background(), ai.ToolCall{ID: "c1", Name: "dump", Arguments: "{}"})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(result.Content, large[:40]))
+		assert.Contains(t, result.Content, "bytes 0-40 of 100")
+
+		var id string
+		_, after, ok := strings.Cut(result.Content, `result_id="`)
+		require.True(t, ok)
+		id, _, _ = strings.Cut(after, `"`)
+
+		stored, ok := registry.StoredResult(id)
+		require.True(t, ok)
+		assert.Equal(t, large, stored)
+
+		page, err := registry.Execute(context.Background(), ai.ToolCall{
+			ID:        "c2",
+			Name:      ReadToolResultName,
+			Arguments: fmt.Sprintf(`{"result_id":%q,"offset":40}`, id),
+		})
+		require.NoError(t, err)
+		assert.False(t, page.IsError)
+		assert.True(t, strings.HasPrefix(page.Content, large[40:80]))
+		assert.Contains(t, page.Content, "offset=80")
+
+		last, err := registry.Execute(context.Background(), ai.ToolCall{
+			ID:        "c3",
+			Name:      ReadToolResultName,
+			Arguments: fmt.Sprintf(`{"result_id":%q,"offset":80}`, id),
+		})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(last.Content, large[80:]))
+		assert.Contains(t, last.Content, "End of result")
+	})
+
+	t.Run("reports unknown results and bad offsets", func(t *testing.T) {
+		registry := newRegistry()
+
+		result, err := registry.Execute(context.Background(), ai.ToolCall{
+			ID:        "c1",
+			Name:      ReadToolResultName,
+			Arguments: `{"result_id":"missing","offset":0}`,
+		})
+		require.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, result.Content, "not found")
+	})
+
+	t.Run("clears stored results", func(t *testing.T) {
+		registry := newRegistry()
+		result, err := registry.Execute(context.Background(), ai.ToolCall{ID: "c1", Name: "dump", Arguments: "{}"})
+		require.NoError(t, err)
+		_, after, _ := strings.Cut(result.Content, `result_id="`)
+		id, _, _ := strings.Cut(after, `"`)
+
+		registry.ClearStoredResults()
+		_, ok := registry.StoredResult(id)
+		assert.False(t, ok)
+	})
+}
+
+func TestPageEnd(t *testing.T) {
+	t.Run("does not split multi-byte runes", func(t *testing.T) {
+		content := "aé" // 'é' is two bytes
+		assert.Equal(t, 1, pageEnd(content, 0, 2))
+	})
+
+	t.Run("includes whole rune when page is smaller than a rune", func(t *testing.T) {
+		content := "日本"
+		assert.Equal(t, 3, pageEnd(content, 0, 1))
+	})
+
+	t.Run("clamps to content length", func(t *testing.T) {
+		assert.Equal(t, 5, pageEnd("hello", 2, 10))
+	})
+}
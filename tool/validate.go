@@ -0,0 +1,34 @@
+package tool
+
+import (
+	"fmt"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/schema"
+)
+
+// WithArgumentValidation validates tool call arguments against each tool's
+// parameter schema before its handler runs. Calls with invalid arguments
+// are not executed; they return an error result listing the violations so
+// the model can correct the call.
+func WithArgumentValidation() RegistryOption {
+	return func(r *Registry) {
+		r.validateArgs = true
+	}
+}
+
+// validateArguments checks call's arguments against t's parameter schema,
+// treating empty arguments as an empty object.
+func validateArguments(t ai.Tool, call ai.ToolCall) error {
+	if len(t.Parameters) == 0 {
+		return nil
+	}
+	args := call.Arguments
+	if args == "" {
+		args = "{}"
+	}
+	if err := schema.Validate(t.Parameters, []byte(args)); err != nil {
+		return fmt.Errorf("invalid arguments for %s:\n%w", call.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// Package transcript exports conversations as Markdown or HTML for sharing,
+// audits, and bug reports.
+//
+// Both renderers take a message history, such as a session's or an agent
+// result's, and write a readable transcript:
+//
+//	var buf bytes.Buffer
+//	err := transcript.Markdown(&buf, sess.Messages(), transcript.WithTitle("Support chat"))
+//
+//	f, _ := os.Create("chat.html")
+//	defer f.Close()
+//	err = transcript.HTML(f, result.Messages())
+//
+// The HTML renderer produces a standalone page with all text escaped.
+// Images, audio, and documents appear as short descriptions; their data is
+// never included.
+//
+// # Tool Calls
+//
+// Each tool result is shown with the call it answers. By default calls are
+// collapsed into expandable blocks (HTML <details>, which GitHub renders in
+// Markdown too); WithTools shows them expanded or hides them:
+//
+//	transcript.Markdown(w, messages, transcript.WithTools(transcript.ToolsExpanded))
+//
+// # Redaction
+//
+// WithRedaction filters all text before it is written, so transcripts can
+// leave the system without the personal data they contain. A
+// redact.Redactor works as a filter:
+//
+//	r := redact.New()
+//	transcript.HTML(w, messages, transcript.WithRedaction(r.Redact))
+//
+// Filters are plain functions, so custom rules are easy to add:
+//
+//	transcript.WithRedaction(func(s string) string {
+//	    return strings.ReplaceAll(s, customerName, "[CUSTOMER]")
+//	})
+//
+// WithMessageFilter rewrites the messages before they are rendered, for
+// rules that depend on roles or tools. A redact.Policy applies an
+// organization's retention rules and records each redaction in its audit
+// log:
+//
+//	transcript.HTML(w, messages, transcript.WithMessageFilter(policy.RedactTranscript))
+package transcript
@@ -0,0 +1,64 @@
+package transcript
+
+import (
+	"html/template"
+	"io"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// HTML writes messages to w as a standalone HTML page. All text is escaped,
+// so transcripts of untrusted conversations are safe to open in a browser.
+func HTML(w io.Writer, messages []ai.Message, opts ...Option) error {
+	c := newConfig(opts)
+	return htmlTemplate.Execute(w, htmlPage{
+		Title:    c.title,
+		Expanded: c.tools == ToolsExpanded,
+		Entries:  build(messages, c),
+	})
+}
+
+type htmlPage struct {
+	Title    string
+	Expanded bool
+	Entries  []entry
+}
+
+var htmlTemplate = template.Must(template.New("transcript").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{if .Title}}{{.Title}}{{else}}Transcript{{end}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+.message { border: 1px solid #ddd; border-radius: 6px; padding: 0.75rem 1rem; margin: 1rem 0; }
+.user { background: #f4f8ff; }
+.system { background: #f7f7f7; }
+.speaker { font-weight: 600; }
+.time { color: #777; font-size: 0.85em; margin-left: 0.5rem; }
+.text { white-space: pre-wrap; margin: 0.5rem 0 0; }
+.attachment { color: #555; font-style: italic; }
+.tool { margin: 0.5rem 0; }
+.tool.error summary, .tool.error .label { color: #b00020; }
+pre { background: #f0f0f0; padding: 0.5rem; overflow-x: auto; }
+</style>
+</head>
+<body>
+{{if .Title}}<h1>{{.Title}}</h1>
+{{end}}{{$expanded := .Expanded}}{{range .Entries}}<div class="message {{.Role}}">
+<div><span class="speaker">{{.Speaker}}</span>{{with .Timestamp}}<span class="time">{{.}}</span>{{end}}</div>
+{{if .Text}}<p class="text">{{.Text}}</p>
+{{end}}{{range .Attachments}}<p class="attachment">[{{.}}]</p>
+{{end}}{{range .Tools}}{{if $expanded}}<div class="tool{{if .IsError}} error{{end}}">
+<div class="label">{{.Label}}</div>
+{{else}}<details class="tool{{if .IsError}} error{{end}}">
+<summary>{{.Label}}</summary>
+{{end}}{{if .Name}}<div>Arguments:</div>
+<pre>{{.Arguments}}</pre>
+{{end}}{{if .HasResult}}<div>Result:</div>
+<pre>{{.Result}}</pre>
+{{end}}{{if $expanded}}</div>{{else}}</details>{{end}}
+{{end}}</div>
+{{end}}</body>
+</html>
+`))
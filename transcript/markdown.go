@@ -0,0 +1,85 @@
+package transcript
+
+import (
+	"io"
+	"strings"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// Markdown writes messages to w as a Markdown transcript. Collapsed tool
+// calls use HTML <details> blocks, which GitHub and most Markdown viewers
+// render as expandable sections.
+func Markdown(w io.Writer, messages []ai.Message, opts ...Option) error {
+	c := newConfig(opts)
+
+	var b strings.Builder
+	if c.title != "" {
+		b.WriteString("# " + c.title + "\n\n")
+	}
+
+	for i, e := range build(messages, c) {
+		if i > 0 {
+			b.WriteString("---\n\n")
+		}
+		b.WriteString("**" + e.Speaker() + "**")
+		if ts := e.Timestamp(); ts != "" {
+			b.WriteString(" · " + ts)
+		}
+		b.WriteString("\n\n")
+
+		if e.Text != "" {
+			b.WriteString(e.Text + "\n\n")
+		}
+		for _, a := range e.Attachments {
+			b.WriteString("_[" + a + "]_\n\n")
+		}
+		for _, t := range e.Tools {
+			writeMarkdownTool(&b, t, c.tools)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMarkdownTool writes one tool call and its result.
+func writeMarkdownTool(b *strings.Builder, t toolEntry, display ToolDisplay) {
+	if display == ToolsCollapsed {
+		b.WriteString("<details>\n<summary>" + escapeSummary(t.Label()) + "</summary>\n\n")
+	} else {
+		b.WriteString("**" + t.Label() + "**\n\n")
+	}
+
+	if t.Name != "" {
+		b.WriteString("Arguments:\n\n")
+		writeCodeBlock(b, "json", t.Arguments)
+	}
+	if t.HasResult {
+		b.WriteString("Result:\n\n")
+		writeCodeBlock(b, "", t.Result)
+	}
+
+	if display == ToolsCollapsed {
+		b.WriteString("</details>\n\n")
+	}
+}
+
+// writeCodeBlock writes s as a fenced code block, using a fence longer than
+// any run of backticks in s.
+func writeCodeBlock(b *strings.Builder, lang, s string) {
+	fence := "```"
+	for strings.Contains(s, fence) {
+		fence += "`"
+	}
+	b.WriteString(fence + lang + "\n" + s)
+	if !strings.HasSuffix(s, "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString(fence + "\n\n")
+}
+
+// escapeSummary escapes the characters that would end a <summary> element.
+func escapeSummary(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,288 @@
+package transcript
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+)
+
+// ToolDisplay controls how tool calls and their results are rendered.
+type ToolDisplay int
+
+const (
+	// ToolsCollapsed renders each tool call as a collapsible block whose
+	// arguments and result are hidden until expanded. This is the default.
+	ToolsCollapsed ToolDisplay = iota
+	// ToolsExpanded renders tool call arguments and results inline.
+	ToolsExpanded
+	// ToolsHidden omits tool calls and tool results entirely.
+	ToolsHidden
+)
+
+// Option configures a transcript.
+type Option func(*config)
+
+type config struct {
+	title    string
+	tools    ToolDisplay
+	system   bool
+	redactFn []func(string) string
+	filters  []func([]ai.Message) []ai.Message
+}
+
+// WithTitle sets the heading of the transcript and, for HTML, the page
+// title. Transcripts have no heading by default.
+func WithTitle(title string) Option {
+	return func(c *config) {
+		c.title = title
+	}
+}
+
+// WithTools sets how tool calls and results are rendered.
+func WithTools(display ToolDisplay) Option {
+	return func(c *config) {
+		c.tools = display
+	}
+}
+
+// WithoutSystem omits system messages from the transcript.
+func WithoutSystem() Option {
+	return func(c *config) {
+		c.system = false
+	}
+}
+
+// WithRedaction filters every piece of text in the transcript through fn
+// before it is rendered: message content, names, tool arguments, and tool
+// results. Filters added by repeated calls run in order. A
+// redact.Redactor's Redact method is a ready-made filter.
+func WithRedaction(fn func(string) string) Option {
+	return func(c *config) {
+		c.redactFn = append(c.redactFn, fn)
+	}
+}
+
+// WithMessageFilter passes the messages through fn before the transcript
+// is built, for rules that depend on a message's role or tool, such as a
+// redact.Policy's RedactTranscript method. Filters run in the order they
+// are added, before WithRedaction filters.
+func WithMessageFilter(fn func([]ai.Message) []ai.Message) Option {
+	return func(c *config) {
+		c.filters = append(c.filters, fn)
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{tools: ToolsCollapsed, system: true}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// redact applies the configured redaction filters to s.
+func (c *config) redact(s string) string {
+	if s == "" {
+		return s
+	}
+	for _, fn := range c.redactFn {
+		s = fn(s)
+	}
+	return s
+}
+
+// entry is a message prepared for rendering.
+type entry struct {
+	Role        ai.Role
+	Name        string
+	Time        time.Time
+	Text        string
+	Attachments []string
+	Tools       []toolEntry
+}
+
+// toolEntry is a tool call and, when found, its result.
+type toolEntry struct {
+	ID        string
+	Name      string
+	Arguments string
+	Result    string
+	HasResult bool
+	IsError   bool
+}
+
+// build converts messages into entries. Tool results are attached to the
+// call they answer; results without a matching call are rendered as their
+// own tool entries.
+func build(messages []ai.Message, c *config) []entry {
+	for _, fn := range c.filters {
+		messages = fn(messages)
+	}
+	calls := make(map[string]bool)
+	results := make(map[string]ai.ToolResult)
+	for _, msg := range messages {
+		for _, call := range msg.ToolCalls {
+			calls[call.ID] = true
+		}
+		for _, result := range msg.ToolResults {
+			results[result.ToolCallID] = result
+		}
+	}
+
+	entries := make([]entry, 0, len(messages))
+	for _, msg := range messages {
+		if msg.Role == ai.RoleSystem && !c.system {
+			continue
+		}
+
+		e := entry{
+			Role: msg.Role,
+			Name: c.redact(msg.Name),
+			Time: msg.CreatedAt,
+			Text: c.redact(messageText(msg)),
+		}
+		for _, part := range msg.Parts {
+			if a := attachment(part); a != "" {
+				e.Attachments = append(e.Attachments, c.redact(a))
+			}
+		}
+
+		if c.tools != ToolsHidden {
+			for _, call := range msg.ToolCalls {
+				t := toolEntry{
+					ID:        call.ID,
+					Name:      call.Name,
+					Arguments: c.redact(indentJSON(call.Arguments)),
+				}
+				if result, ok := results[call.ID]; ok {
+					t.Result = c.redact(result.Content)
+					t.HasResult = true
+					t.IsError = result.IsError
+				}
+				e.Tools = append(e.Tools, t)
+			}
+			for _, result := range msg.ToolResults {
+				if calls[result.ToolCallID] {
+					continue
+				}
+				e.Tools = append(e.Tools, toolEntry{
+					ID:        result.ToolCallID,
+					Result:    c.redact(result.Content),
+					HasResult: true,
+					IsError:   result.IsError,
+				})
+			}
+		}
+
+		if e.Text == "" && len(e.Attachments) == 0 && len(e.Tools) == 0 {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// messageText returns the text of a message: its text parts if it has
+// parts, otherwise its content.
+func messageText(msg ai.Message) string {
+	if !msg.HasParts() {
+		return msg.Content
+	}
+	var buf bytes.Buffer
+	for _, part := range msg.Parts {
+		if part.Type != ai.ContentPartTypeText || part.Text == "" {
+			continue
+		}
+		if buf.Len() > 0 {
+			buf.WriteString("\n\n")
+		}
+		buf.WriteString(part.Text)
+	}
+	return buf.String()
+}
+
+// attachment describes a non-text content part, or returns "" for text.
+// Attachment data is never included in a transcript.
+func attachment(part ai.ContentPart) string {
+	switch part.Type {
+	case ai.ContentPartTypeImage:
+		if part.ImageURL != "" {
+			return "image: " + part.ImageURL
+		}
+		return "image (" + part.MimeType + ")"
+	case ai.ContentPartTypeAudio:
+		return "audio (" + part.MimeType + ")"
+	case ai.ContentPartTypeDocument:
+		name := part.Filename
+		if name == "" {
+			name = part.FileURL
+		}
+		if name == "" {
+			return "document (" + part.MimeType + ")"
+		}
+		return "document: " + name
+	}
+	return ""
+}
+
+// indentJSON pretty-prints arguments that are valid JSON and returns
+// anything else unchanged.
+func indentJSON(s string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(s), "", "  "); err != nil {
+		return s
+	}
+	return buf.String()
+}
+
+// roleLabel returns the display name of a role.
+func roleLabel(role ai.Role) string {
+	switch role {
+	case ai.RoleUser:
+		return "User"
+	case ai.RoleAssistant:
+		return "Assistant"
+	case ai.RoleSystem:
+		return "System"
+	case ai.RoleTool:
+		return "Tool"
+	}
+	return string(role)
+}
+
+// Speaker returns the heading of an entry, such as "Assistant (researcher)".
+func (e entry) Speaker() string {
+	if e.Name == "" {
+		return roleLabel(e.Role)
+	}
+	return fmt.Sprintf("%s (%s)", roleLabel(e.Role), e.Name)
+}
+
+// Timestamp returns the entry's creation time in UTC, or "" if unknown.
+func (e entry) Timestamp() string {
+	if e.Time.IsZero() {
+		return ""
+	}
+	return e.Time.UTC().Format("2006-01-02 15:04:05 UTC")
+}
+
+// Label returns the summary line of a tool entry.
+func (t toolEntry) Label() string {
+	if t.Name == "" {
+		if t.IsError {
+			return "Tool result: " + t.ID + " (error)"
+		}
+		return "Tool result: " + t.ID
+	}
+	name := t.Name
+	switch {
+	case !t.HasResult:
+		return "Tool call: " + name + " (no result)"
+	case t.IsError:
+		return "Tool call: " + name + " (error)"
+	}
+	return "Tool call: " + name
+}
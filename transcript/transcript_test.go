@@ -0,0 +1,143 @@
+package transcript
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMessages() []ai.Message {
+	return []ai.Message{
+		{Role: ai.RoleSystem, Content: "You are helpful."},
+		{
+			Role:      ai.RoleUser,
+			Content:   "Weather for jane@example.com?",
+			CreatedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			Role:      ai.RoleAssistant,
+			ToolCalls: []ai.ToolCall{{ID: "call_1", Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+		},
+		{
+			Role:        ai.RoleTool,
+			ToolResults: []ai.ToolResult{{ToolCallID: "call_1", Content: "Sunny, 22C"}},
+		},
+		{Role: ai.RoleAssistant, Content: "It is sunny in Paris."},
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, Markdown(&b, testMessages(), WithTitle("Chat")))
+	out := b.String()
+
+	assert.True(t, strings.HasPrefix(out, "# Chat\n\n**System**"))
+	assert.Contains(t, out, "**User** · 2025-03-01 12:00:00 UTC\n\nWeather for jane@example.com?")
+	assert.Contains(t, out, "<details>\n<summary>Tool call: get_weather</summary>")
+	assert.Contains(t, out, "```json\n{\n  \"city\": \"Paris\"\n}\n```")
+	assert.Contains(t, out, "Result:\n\n```\nSunny, 22C\n```")
+	assert.Contains(t, out, "It is sunny in Paris.")
+	// The tool message is shown with its call, not on its own
+	assert.NotContains(t, out, "**Tool**")
+}
+
+func TestMarkdown_Tools(t *testing.T) {
+	var expanded strings.Builder
+	require.NoError(t, Markdown(&expanded, testMessages(), WithTools(ToolsExpanded)))
+	assert.NotContains(t, expanded.String(), "<details>")
+	assert.Contains(t, expanded.String(), "**Tool call: get_weather**")
+
+	var hidden strings.Builder
+	require.NoError(t, Markdown(&hidden, testMessages(), WithTools(ToolsHidden)))
+	assert.NotContains(t, hidden.String(), "get_weather")
+	assert.NotContains(t, hidden.String(), "Sunny")
+}
+
+func TestMarkdown_UnmatchedResult(t *testing.T) {
+	messages := []ai.Message{
+		{Role: ai.RoleTool, ToolResults: []ai.ToolResult{{ToolCallID: "call_9", Content: "boom", IsError: true}}},
+	}
+	var b strings.Builder
+	require.NoError(t, Markdown(&b, messages, WithTools(ToolsExpanded)))
+	assert.Contains(t, b.String(), "**Tool**\n\n**Tool result: call_9 (error)**")
+	assert.Contains(t, b.String(), "boom")
+}
+
+func TestMarkdown_CodeFence(t *testing.T) {
+	messages := []ai.Message{
+		{Role: ai.RoleAssistant, ToolCalls: []ai.ToolCall{{ID: "1", Name: "run", Arguments: "```go```"}}},
+	}
+	var b strings.Builder
+	require.NoError(t, Markdown(&b, messages))
+	assert.Contains(t, b.String(), "````json\n```go```\n````")
+}
+
+func TestRedaction(t *testing.T) {
+	hideEmail := func(s string) string { return strings.ReplaceAll(s, "jane@example.com", "[EMAIL]") }
+	hideCity := func(s string) string { return strings.ReplaceAll(s, "Paris", "[CITY]") }
+
+	var b strings.Builder
+	require.NoError(t, Markdown(&b, testMessages(), WithRedaction(hideEmail), WithRedaction(hideCity)))
+	out := b.String()
+
+	assert.NotContains(t, out, "jane@example.com")
+	assert.NotContains(t, out, "Paris")
+	assert.Contains(t, out, "Weather for [EMAIL]?")
+	assert.Contains(t, out, `"city": "[CITY]"`)
+}
+
+func TestMessageFilter(t *testing.T) {
+	dropTools := func(messages []ai.Message) []ai.Message {
+		var out []ai.Message
+		for _, m := range messages {
+			if len(m.ToolCalls) == 0 && len(m.ToolResults) == 0 {
+				out = append(out, m)
+			}
+		}
+		return out
+	}
+
+	var b strings.Builder
+	require.NoError(t, Markdown(&b, testMessages(), WithMessageFilter(dropTools)))
+	out := b.String()
+
+	assert.NotContains(t, out, "get_weather")
+	assert.Contains(t, out, "It is sunny in Paris.")
+}
+
+func TestWithoutSystem(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, Markdown(&b, testMessages(), WithoutSystem()))
+	assert.NotContains(t, b.String(), "You are helpful.")
+}
+
+func TestHTML(t *testing.T) {
+	messages := append(testMessages(), ai.Message{
+		Role:  ai.RoleUser,
+		Name:  "jane",
+		Parts: []ai.ContentPart{ai.NewTextPart("<script>alert(1)</script>"), ai.NewImageBase64Part("aGk=", "image/png")},
+	})
+
+	var b strings.Builder
+	require.NoError(t, HTML(&b, messages, WithTitle("Chat")))
+	out := b.String()
+
+	assert.Contains(t, out, "<title>Chat</title>")
+	assert.Contains(t, out, `<div class="message user">`)
+	assert.Contains(t, out, "<summary>Tool call: get_weather</summary>")
+	assert.Contains(t, out, "Sunny, 22C")
+	assert.Contains(t, out, "User (jane)")
+	assert.Contains(t, out, "[image (image/png)]")
+	assert.NotContains(t, out, "aGk=")
+	assert.NotContains(t, out, "<script>")
+	assert.Contains(t, out, "&lt;script&gt;")
+
+	var expanded strings.Builder
+	require.NoError(t, HTML(&expanded, testMessages(), WithTools(ToolsExpanded)))
+	assert.NotContains(t, expanded.String(), "<details")
+	assert.Contains(t, expanded.String(), `<div class="label">Tool call: get_weather</div>`)
+}
@@ -0,0 +1,53 @@
+package gains
+
+import (
+	"context"
+	"time"
+)
+
+// TranscriptionProvider defines the interface for speech-to-text providers.
+type TranscriptionProvider interface {
+	// Transcribe converts spoken audio to text.
+	// Returns an error if audio is empty.
+	Transcribe(ctx context.Context, audio []byte, opts ...TranscriptionOption) (*TranscriptionResponse, error)
+}
+
+// TranscriptionResponse represents a complete response from a transcription provider.
+type TranscriptionResponse struct {
+	// Text is the transcribed text.
+	Text string
+	// Duration is the length of the audio, when reported by the provider.
+	// OpenAI reports it for whisper-1; token-billed models report Usage instead.
+	Duration time.Duration
+	// Usage contains token usage information, when reported by the provider.
+	Usage Usage
+}
+
+// audioSignatures maps leading bytes of common audio containers to MIME types.
+var audioSignatures = []struct {
+	offset   int
+	magic    string
+	mimeType string
+}{
+	{0, "RIFF", "audio/wav"},
+	{0, "ID3", "audio/mpeg"},
+	{0, "fLaC", "audio/flac"},
+	{0, "OggS", "audio/ogg"},
+	{0, "\x1a\x45\xdf\xa3", "audio/webm"},
+	{4, "ftyp", "audio/mp4"},
+	{0, "\xff\xfb", "audio/mpeg"},
+	{0, "\xff\xf3", "audio/mpeg"},
+	{0, "\xff\xf2", "audio/mpeg"},
+}
+
+// DetectAudioMimeType returns the MIME type of audio data based on its
+// container signature. Unrecognized data is assumed to be "audio/mpeg".
+func DetectAudioMimeType(data []byte) string {
+	for _, sig := range audioSignatures {
+		end := sig.offset + len(sig.magic)
+		if len(data) >= end && string(data[sig.offset:end]) == sig.magic {
+			return sig.mimeType
+		}
+	}
+	return "audio/mpeg"
+}
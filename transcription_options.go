@@ -0,0 +1,68 @@
+package gains
+
+// TranscriptionOptions contains configuration for a transcription request.
+type TranscriptionOptions struct {
+	Model       Model
+	Language    string
+	Prompt      string
+	MimeType    string
+	RetryConfig *RetryConfig // Per-call retry config override (nil = use client default)
+}
+
+// TranscriptionOption is a functional option for configuring transcription requests.
+type TranscriptionOption func(*TranscriptionOptions)
+
+// WithTranscriptionModel sets the model to use for transcription.
+func WithTranscriptionModel(model Model) TranscriptionOption {
+	return func(o *TranscriptionOptions) {
+		o.Model = model
+	}
+}
+
+// WithTranscriptionLanguage sets the spoken language as an ISO-639-1 code
+// (e.g., "en", "de"). Supplying it improves accuracy and latency.
+func WithTranscriptionLanguage(language string) TranscriptionOption {
+	return func(o *TranscriptionOptions) {
+		o.Language = language
+	}
+}
+
+// WithTranscriptionPrompt supplies context such as vocabulary, names, or the
+// preceding transcript segment to guide the transcription.
+func WithTranscriptionPrompt(prompt string) TranscriptionOption {
+	return func(o *TranscriptionOptions) {
+		o.Prompt = prompt
+	}
+}
+
+// WithAudioMimeType sets the MIME type of the audio (e.g., "audio/wav",
+// "audio/mpeg"). If not set, it is detected from the audio data.
+func WithAudioMimeType(mimeType string) TranscriptionOption {
+	return func(o *TranscriptionOptions) {
+		o.MimeType = mimeType
+	}
+}
+
+// WithTranscriptionRetry overrides the client's default retry configuration for this request.
+func WithTranscriptionRetry(cfg RetryConfig) TranscriptionOption {
+	return func(o *TranscriptionOptions) {
+		o.RetryConfig = &cfg
+	}
+}
+
+// WithTranscriptionRetryDisabled disables retry for this request (single attempt).
+func WithTranscriptionRetryDisabled() TranscriptionOption {
+	return func(o *TranscriptionOptions) {
+		disabled := DisabledRetryConfig()
+		o.RetryConfig = &disabled
+	}
+}
+
+// ApplyTranscriptionOptions applies functional options to a TranscriptionOptions struct.
+func ApplyTranscriptionOptions(opts ...TranscriptionOption) *TranscriptionOptions {
+	o := &TranscriptionOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
@@ -0,0 +1,63 @@
+package gains
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyTranscriptionOptions(t *testing.T) {
+	t.Run("returns empty options when no options provided", func(t *testing.T) {
+		opts := ApplyTranscriptionOptions()
+		assert.NotNil(t, opts)
+		assert.Nil(t, opts.Model)
+		assert.Empty(t, opts.Language)
+		assert.Empty(t, opts.Prompt)
+		assert.Empty(t, opts.MimeType)
+		assert.Nil(t, opts.RetryConfig)
+	})
+
+	t.Run("applies multiple options", func(t *testing.T) {
+		opts := ApplyTranscriptionOptions(
+			WithTranscriptionModel(testEmbeddingModel("whisper-1")),
+			WithTranscriptionLanguage("en"),
+			WithTranscriptionPrompt("gains, Gemini"),
+			WithAudioMimeType("audio/wav"),
+		)
+
+		assert.Equal(t, "whisper-1", opts.Model.String())
+		assert.Equal(t, "en", opts.Language)
+		assert.Equal(t, "gains, Gemini", opts.Prompt)
+		assert.Equal(t, "audio/wav", opts.MimeType)
+	})
+
+	t.Run("disables retry", func(t *testing.T) {
+		opts := ApplyTranscriptionOptions(WithTranscriptionRetryDisabled())
+		assert.NotNil(t, opts.RetryConfig)
+		assert.Equal(t, 1, opts.RetryConfig.MaxAttempts)
+	})
+}
+
+func TestDetectAudioMimeType(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{"wav", []byte("RIFF\x24\x00\x00\x00WAVEfmt "), "audio/wav"},
+		{"mp3 with ID3 tag", []byte("ID3\x04\x00"), "audio/mpeg"},
+		{"mp3 frame", []byte{0xff, 0xfb, 0x90, 0x00}, "audio/mpeg"},
+		{"flac", []byte("fLaC\x00\x00"), "audio/flac"},
+		{"ogg", []byte("OggS\x00\x02"), "audio/ogg"},
+		{"webm", []byte{0x1a, 0x45, 0xdf, 0xa3, 0x01}, "audio/webm"},
+		{"m4a", []byte("\x00\x00\x00\x20ftypM4A "), "audio/mp4"},
+		{"unknown", []byte("hello"), "audio/mpeg"},
+		{"empty", nil, "audio/mpeg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectAudioMimeType(tt.data))
+		})
+	}
+}
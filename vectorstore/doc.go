@@ -0,0 +1,20 @@
+// Package vectorstore stores documents with their embeddings and finds the
+// ones most similar to a query, for retrieval-augmented generation.
+//
+// A [Store] upserts, queries, and deletes documents. [Memory] keeps them in
+// process; the pgvector subpackage stores them in PostgreSQL:
+//
+//	store := vectorstore.NewMemory()
+//	resp, err := client.Embed(ctx, texts)
+//	for i, text := range texts {
+//	    store.Upsert(ctx, vectorstore.Document{ID: ids[i], Content: text, Vector: resp.Embeddings[i]})
+//	}
+//
+// Query with the embedding of a question to get the closest documents:
+//
+//	matches, err := store.Query(ctx, queryVector, 5,
+//	    vectorstore.WithFilter(map[string]string{"lang": "en"}))
+//
+// In workflows, workflow.NewRetrievalStep embeds the query and writes the
+// matches into the state for later prompt steps.
+package vectorstore
@@ -0,0 +1,100 @@
+package vectorstore
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"sync"
+)
+
+// Memory is an in-process Store that compares the query with every
+// document. It suits tests and collections of up to some tens of thousands
+// of documents.
+type Memory struct {
+	mu   sync.RWMutex
+	docs map[string]Document
+}
+
+// NewMemory returns an empty in-memory store.
+func NewMemory() *Memory {
+	return &Memory{docs: make(map[string]Document)}
+}
+
+// Upsert adds documents, replacing stored documents with the same ID.
+func (m *Memory) Upsert(ctx context.Context, docs ...Document) error {
+	for _, d := range docs {
+		if d.ID == "" {
+			return ErrMissingID
+		}
+		if len(d.Vector) == 0 {
+			return ErrEmptyVector
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, d := range docs {
+		d.Metadata = maps.Clone(d.Metadata)
+		d.Vector = append([]float64(nil), d.Vector...)
+		m.docs[d.ID] = d
+	}
+	return nil
+}
+
+// Query returns up to k documents most similar to vector, best match
+// first. Ties are ordered by ID.
+func (m *Memory) Query(ctx context.Context, vector []float64, k int, opts ...QueryOption) ([]Match, error) {
+	if len(vector) == 0 {
+		return nil, ErrEmptyVector
+	}
+	if k <= 0 {
+		return nil, nil
+	}
+	o := ApplyQueryOptions(opts...)
+
+	m.mu.RLock()
+	matches := make([]Match, 0, len(m.docs))
+	for _, d := range m.docs {
+		if !o.Matches(d.Metadata) {
+			continue
+		}
+		score := Cosine(vector, d.Vector)
+		if o.MinScore != 0 && score < o.MinScore {
+			continue
+		}
+		matches = append(matches, Match{Document: d, Score: score})
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	if len(matches) > k {
+		matches = matches[:k]
+	}
+	for i := range matches {
+		matches[i].Metadata = maps.Clone(matches[i].Metadata)
+		matches[i].Vector = append([]float64(nil), matches[i].Vector...)
+	}
+	return matches, nil
+}
+
+// Delete removes the documents with the given IDs.
+func (m *Memory) Delete(ctx context.Context, ids ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		delete(m.docs, id)
+	}
+	return nil
+}
+
+// Len returns the number of stored documents.
+func (m *Memory) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.docs)
+}
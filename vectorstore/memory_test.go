@@ -0,0 +1,89 @@
+package vectorstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCosine(t *testing.T) {
+	assert.InDelta(t, 1.0, Cosine([]float64{1, 2}, []float64{2, 4}), 1e-9)
+	assert.InDelta(t, 0.0, Cosine([]float64{1, 0}, []float64{0, 1}), 1e-9)
+	assert.InDelta(t, -1.0, Cosine([]float64{1, 0}, []float64{-1, 0}), 1e-9)
+	assert.Equal(t, 0.0, Cosine([]float64{1}, []float64{1, 0}))
+	assert.Equal(t, 0.0, Cosine([]float64{0, 0}, []float64{1, 0}))
+}
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemory()
+	require.NoError(t, store.Upsert(ctx,
+		Document{ID: "a", Content: "alpha", Vector: []float64{1, 0}, Metadata: map[string]string{"lang": "en"}},
+		Document{ID: "b", Content: "beta", Vector: []float64{1, 1}, Metadata: map[string]string{"lang": "de"}},
+		Document{ID: "c", Content: "gamma", Vector: []float64{-1, 0}, Metadata: map[string]string{"lang": "en"}},
+	))
+	assert.Equal(t, 3, store.Len())
+
+	t.Run("best matches first", func(t *testing.T) {
+		matches, err := store.Query(ctx, []float64{1, 0}, 2)
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+		assert.Equal(t, "a", matches[0].ID)
+		assert.Equal(t, "alpha", matches[0].Content)
+		assert.InDelta(t, 1.0, matches[0].Score, 1e-9)
+		assert.Equal(t, "b", matches[1].ID)
+	})
+
+	t.Run("negative scores are kept without a minimum", func(t *testing.T) {
+		matches, err := store.Query(ctx, []float64{1, 0}, 10)
+		require.NoError(t, err)
+		require.Len(t, matches, 3)
+		assert.Equal(t, "c", matches[2].ID)
+	})
+
+	t.Run("filter and minimum score", func(t *testing.T) {
+		matches, err := store.Query(ctx, []float64{1, 0}, 10, WithFilter(map[string]string{"lang": "en"}))
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+		assert.Equal(t, "a", matches[0].ID)
+		assert.Equal(t, "c", matches[1].ID)
+
+		matches, err = store.Query(ctx, []float64{1, 0}, 10, WithMinScore(0.5))
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+	})
+
+	t.Run("results do not alias stored documents", func(t *testing.T) {
+		matches, err := store.Query(ctx, []float64{1, 0}, 1)
+		require.NoError(t, err)
+		matches[0].Metadata["lang"] = "fr"
+		matches[0].Vector[0] = 0
+
+		matches, err = store.Query(ctx, []float64{1, 0}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "en", matches[0].Metadata["lang"])
+		assert.Equal(t, []float64{1, 0}, matches[0].Vector)
+	})
+
+	t.Run("upsert replaces and delete removes", func(t *testing.T) {
+		require.NoError(t, store.Upsert(ctx, Document{ID: "a", Content: "alpha 2", Vector: []float64{0, 1}}))
+		matches, err := store.Query(ctx, []float64{0, 1}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "alpha 2", matches[0].Content)
+
+		require.NoError(t, store.Delete(ctx, "a", "missing"))
+		assert.Equal(t, 2, store.Len())
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		assert.ErrorIs(t, store.Upsert(ctx, Document{Vector: []float64{1}}), ErrMissingID)
+		assert.ErrorIs(t, store.Upsert(ctx, Document{ID: "x"}), ErrEmptyVector)
+		_, err := store.Query(ctx, nil, 1)
+		assert.ErrorIs(t, err, ErrEmptyVector)
+		matches, err := store.Query(ctx, []float64{1, 0}, 0)
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+}
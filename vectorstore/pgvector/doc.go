@@ -0,0 +1,19 @@
+// Package pgvector provides a vectorstore.Store backed by PostgreSQL with
+// the pgvector extension.
+//
+// The store works with any database/sql driver for PostgreSQL, so this
+// module does not depend on one:
+//
+//	db, err := sql.Open("pgx", os.Getenv("DATABASE_URL"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	docs, err := pgvector.New(db, "documents")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	err = docs.CreateTable(ctx, 1536)
+//
+// Create an HNSW or IVFFlat index with vector_cosine_ops on the embedding
+// column for large tables, since queries rank by cosine distance.
+package pgvector
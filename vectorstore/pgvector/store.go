@@ -0,0 +1,219 @@
+package pgvector
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spetersoncode/gains/vectorstore"
+)
+
+// ErrInvalidTable is returned by New for a table name that is not a plain
+// or schema-qualified SQL identifier.
+var ErrInvalidTable = errors.New("pgvector: invalid table name")
+
+// DB executes SQL statements. *sql.DB, *sql.Conn, and *sql.Tx implement it
+// with any PostgreSQL driver, such as pgx's stdlib package or lib/pq.
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// tablePattern matches a table name, optionally qualified by a schema.
+var tablePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Store is a vectorstore.Store backed by a PostgreSQL table with the
+// pgvector extension. The table has the columns id (text primary key),
+// content (text), metadata (jsonb), and embedding (vector); CreateTable
+// creates it. Matches are ranked by cosine distance and do not include the
+// stored vectors.
+type Store struct {
+	db    DB
+	table string
+}
+
+var _ vectorstore.Store = (*Store)(nil)
+
+// New returns a store using table in db.
+func New(db DB, table string) (*Store, error) {
+	if !tablePattern.MatchString(table) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTable, table)
+	}
+	return &Store{db: db, table: table}, nil
+}
+
+// CreateTable enables the pgvector extension and creates the table for
+// vectors of the given dimensions, unless they exist.
+func (s *Store) CreateTable(ctx context.Context, dimensions int) error {
+	if _, err := s.db.ExecContext(ctx, "CREATE EXTENSION IF NOT EXISTS vector"); err != nil {
+		return fmt.Errorf("pgvector: create extension: %w", err)
+	}
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id text PRIMARY KEY,
+	content text NOT NULL DEFAULT '',
+	metadata jsonb NOT NULL DEFAULT '{}',
+	embedding vector(%d) NOT NULL
+)`, s.table, dimensions)
+	if _, err := s.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("pgvector: create table: %w", err)
+	}
+	return nil
+}
+
+// maxParams is the most bind parameters PostgreSQL accepts in one
+// statement.
+const maxParams = 65535
+
+// upsertBatch is the most documents one upsert statement inserts, at four
+// parameters each.
+const upsertBatch = maxParams / 4
+
+// Upsert inserts documents, replacing rows with the same ID. Of documents
+// sharing an ID, the last wins, as with vectorstore.Memory. Documents are
+// sent in statements of up to 16383 rows to stay within PostgreSQL's
+// bind parameter limit; a Store on a *sql.Tx makes a multi-statement
+// upsert atomic.
+func (s *Store) Upsert(ctx context.Context, docs ...vectorstore.Document) error {
+	// One statement cannot touch a row twice, so keep the last of each ID
+	last := make(map[string]int, len(docs))
+	for i, d := range docs {
+		if d.ID == "" {
+			return vectorstore.ErrMissingID
+		}
+		if len(d.Vector) == 0 {
+			return vectorstore.ErrEmptyVector
+		}
+		last[d.ID] = i
+	}
+	unique := make([]vectorstore.Document, 0, len(last))
+	for i, d := range docs {
+		if last[d.ID] == i {
+			unique = append(unique, d)
+		}
+	}
+
+	for batch := range slices.Chunk(unique, upsertBatch) {
+		if err := s.upsert(ctx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upsert inserts docs, which have distinct IDs, in one statement.
+func (s *Store) upsert(ctx context.Context, docs []vectorstore.Document) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "INSERT INTO %s (id, content, metadata, embedding) VALUES ", s.table)
+	args := make([]any, 0, 4*len(docs))
+	for i, d := range docs {
+		metadata, err := json.Marshal(d.Metadata)
+		if err != nil {
+			return fmt.Errorf("pgvector: encode metadata of %s: %w", d.ID, err)
+		}
+		if d.Metadata == nil {
+			metadata = []byte("{}")
+		}
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		n := len(args)
+		fmt.Fprintf(&b, "($%d, $%d, $%d::jsonb, $%d::vector)", n+1, n+2, n+3, n+4)
+		args = append(args, d.ID, d.Content, string(metadata), formatVector(d.Vector))
+	}
+	b.WriteString(" ON CONFLICT (id) DO UPDATE SET content = EXCLUDED.content, metadata = EXCLUDED.metadata, embedding = EXCLUDED.embedding")
+
+	if _, err := s.db.ExecContext(ctx, b.String(), args...); err != nil {
+		return fmt.Errorf("pgvector: upsert: %w", err)
+	}
+	return nil
+}
+
+// Query returns up to k documents nearest to vector by cosine distance.
+// Metadata filters use jsonb containment, so an index on metadata speeds
+// them up.
+func (s *Store) Query(ctx context.Context, vector []float64, k int, opts ...vectorstore.QueryOption) ([]vectorstore.Match, error) {
+	if len(vector) == 0 {
+		return nil, vectorstore.ErrEmptyVector
+	}
+	if k <= 0 {
+		return nil, nil
+	}
+	o := vectorstore.ApplyQueryOptions(opts...)
+	filter, err := json.Marshal(o.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("pgvector: encode filter: %w", err)
+	}
+	if o.Filter == nil {
+		filter = []byte("{}")
+	}
+
+	query := fmt.Sprintf(`SELECT id, content, metadata, 1 - (embedding <=> $1::vector) AS score
+FROM %s
+WHERE metadata @> $2::jsonb AND 1 - (embedding <=> $1::vector) >= $3
+ORDER BY embedding <=> $1::vector
+LIMIT %d`, s.table, k)
+	minScore := o.MinScore
+	if minScore == 0 {
+		minScore = -1 // cosine similarity is never below -1
+	}
+	rows, err := s.db.QueryContext(ctx, query, formatVector(vector), string(filter), minScore)
+	if err != nil {
+		return nil, fmt.Errorf("pgvector: query: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []vectorstore.Match
+	for rows.Next() {
+		var m vectorstore.Match
+		var metadata []byte
+		if err := rows.Scan(&m.ID, &m.Content, &metadata, &m.Score); err != nil {
+			return nil, fmt.Errorf("pgvector: scan: %w", err)
+		}
+		if err := json.Unmarshal(metadata, &m.Metadata); err != nil {
+			return nil, fmt.Errorf("pgvector: decode metadata of %s: %w", m.ID, err)
+		}
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("pgvector: query: %w", err)
+	}
+	return matches, nil
+}
+
+// Delete removes the rows with the given IDs.
+func (s *Store) Delete(ctx context.Context, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	placeholders := make([]string, len(ids))
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "$" + strconv.Itoa(i+1)
+		args[i] = id
+	}
+	query := fmt.Sprintf("DELETE FROM %s WHERE id IN (%s)", s.table, strings.Join(placeholders, ", "))
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("pgvector: delete: %w", err)
+	}
+	return nil
+}
+
+// formatVector returns v in pgvector's text format, such as "[1,0.5,2]".
+func formatVector(v []float64) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, x := range v {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(x, 'g', -1, 64))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
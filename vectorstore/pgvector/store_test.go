@@ -0,0 +1,205 @@
+package pgvector
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/spetersoncode/gains/vectorstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a database/sql driver that records statements and answers
+// queries with fixed rows.
+type recorder struct {
+	mu         sync.Mutex
+	statements []string
+	args       [][]driver.Value
+	rows       [][]driver.Value
+}
+
+func (r *recorder) Open(name string) (driver.Conn, error) { return &conn{r: r}, nil }
+
+func (r *recorder) record(query string, args []driver.Value) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statements = append(r.statements, query)
+	r.args = append(r.args, args)
+}
+
+type conn struct{ r *recorder }
+
+func (c *conn) Prepare(query string) (driver.Stmt, error) { return &stmt{r: c.r, query: query}, nil }
+func (c *conn) Close() error                              { return nil }
+func (c *conn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+
+type stmt struct {
+	r     *recorder
+	query string
+}
+
+func (s *stmt) Close() error  { return nil }
+func (s *stmt) NumInput() int { return -1 }
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.r.record(s.query, args)
+	return driver.RowsAffected(0), nil
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.r.record(s.query, args)
+	return &rows{values: s.r.rows}, nil
+}
+
+type rows struct {
+	values [][]driver.Value
+	i      int
+}
+
+func (r *rows) Columns() []string { return []string{"id", "content", "metadata", "score"} }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.i >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.i])
+	r.i++
+	return nil
+}
+
+var driverID int
+
+// newStore returns a store on a fresh recorder.
+func newStore(t *testing.T) (*Store, *recorder) {
+	t.Helper()
+	r := &recorder{}
+	driverID++
+	name := "pgvector-recorder-" + strconv.Itoa(driverID)
+	sql.Register(name, r)
+	db, err := sql.Open(name, "")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	s, err := New(db, "docs")
+	require.NoError(t, err)
+	return s, r
+}
+
+func TestNew_InvalidTable(t *testing.T) {
+	for _, table := range []string{"", "docs; DROP TABLE x", "1docs", "a.b.c", `"docs"`} {
+		_, err := New(nil, table)
+		assert.ErrorIs(t, err, ErrInvalidTable)
+	}
+	_, err := New(nil, "public.docs")
+	assert.NoError(t, err)
+}
+
+func TestFormatVector(t *testing.T) {
+	assert.Equal(t, "[1,0.5,-2]", formatVector([]float64{1, 0.5, -2}))
+	assert.Equal(t, "[]", formatVector(nil))
+}
+
+func TestStore_CreateTable(t *testing.T) {
+	s, r := newStore(t)
+	require.NoError(t, s.CreateTable(context.Background(), 3))
+	require.Len(t, r.statements, 2)
+	assert.Equal(t, "CREATE EXTENSION IF NOT EXISTS vector", r.statements[0])
+	assert.Contains(t, r.statements[1], "CREATE TABLE IF NOT EXISTS docs")
+	assert.Contains(t, r.statements[1], "embedding vector(3) NOT NULL")
+}
+
+func TestStore_Upsert(t *testing.T) {
+	ctx := context.Background()
+	s, r := newStore(t)
+	require.NoError(t, s.Upsert(ctx,
+		vectorstore.Document{ID: "a", Content: "alpha", Vector: []float64{1, 0}, Metadata: map[string]string{"lang": "en"}},
+		vectorstore.Document{ID: "b", Content: "beta", Vector: []float64{0, 1}},
+	))
+	require.Len(t, r.statements, 1)
+	assert.Contains(t, r.statements[0], "VALUES ($1, $2, $3::jsonb, $4::vector), ($5, $6, $7::jsonb, $8::vector) ON CONFLICT (id) DO UPDATE")
+	assert.Equal(t, []driver.Value{"a", "alpha", `{"lang":"en"}`, "[1,0]", "b", "beta", "{}", "[0,1]"}, r.args[0])
+
+	assert.ErrorIs(t, s.Upsert(ctx, vectorstore.Document{Vector: []float64{1}}), vectorstore.ErrMissingID)
+	assert.ErrorIs(t, s.Upsert(ctx, vectorstore.Document{ID: "c"}), vectorstore.ErrEmptyVector)
+	require.NoError(t, s.Upsert(ctx))
+	assert.Len(t, r.statements, 1)
+}
+
+func TestStore_Upsert_DuplicateIDs(t *testing.T) {
+	s, r := newStore(t)
+	require.NoError(t, s.Upsert(context.Background(),
+		vectorstore.Document{ID: "a", Content: "first", Vector: []float64{1, 0}},
+		vectorstore.Document{ID: "b", Content: "beta", Vector: []float64{0, 1}},
+		vectorstore.Document{ID: "a", Content: "second", Vector: []float64{1, 1}},
+	))
+	require.Len(t, r.statements, 1)
+	assert.Contains(t, r.statements[0], "VALUES ($1, $2, $3::jsonb, $4::vector), ($5, $6, $7::jsonb, $8::vector) ON CONFLICT")
+	assert.Equal(t, []driver.Value{"b", "beta", "{}", "[0,1]", "a", "second", "{}", "[1,1]"}, r.args[0])
+}
+
+func TestStore_Upsert_Batches(t *testing.T) {
+	s, r := newStore(t)
+	docs := make([]vectorstore.Document, upsertBatch+1)
+	for i := range docs {
+		docs[i] = vectorstore.Document{ID: strconv.Itoa(i), Vector: []float64{1}}
+	}
+	require.NoError(t, s.Upsert(context.Background(), docs...))
+
+	require.Len(t, r.args, 2)
+	assert.Len(t, r.args[0], 4*upsertBatch)
+	assert.LessOrEqual(t, len(r.args[0]), maxParams)
+	assert.Equal(t, []driver.Value{strconv.Itoa(upsertBatch), "", "{}", "[1]"}, r.args[1])
+}
+
+func TestStore_Query(t *testing.T) {
+	ctx := context.Background()
+	s, r := newStore(t)
+	r.rows = [][]driver.Value{
+		{"a", "alpha", []byte(`{"lang":"en"}`), 0.9},
+		{"b", "beta", []byte(`{}`), 0.4},
+	}
+
+	matches, err := s.Query(ctx, []float64{1, 0}, 2,
+		vectorstore.WithFilter(map[string]string{"lang": "en"}), vectorstore.WithMinScore(0.3))
+	require.NoError(t, err)
+	require.Len(t, matches, 2)
+	assert.Equal(t, "a", matches[0].ID)
+	assert.Equal(t, "alpha", matches[0].Content)
+	assert.Equal(t, map[string]string{"lang": "en"}, matches[0].Metadata)
+	assert.Equal(t, 0.9, matches[0].Score)
+
+	require.Len(t, r.statements, 1)
+	assert.Contains(t, r.statements[0], "FROM docs")
+	assert.Contains(t, r.statements[0], "ORDER BY embedding <=> $1::vector")
+	assert.Contains(t, r.statements[0], "LIMIT 2")
+	assert.Equal(t, []driver.Value{"[1,0]", `{"lang":"en"}`, 0.3}, r.args[0])
+
+	t.Run("no filter or minimum", func(t *testing.T) {
+		_, err := s.Query(ctx, []float64{1, 0}, 1)
+		require.NoError(t, err)
+		assert.Equal(t, []driver.Value{"[1,0]", "{}", -1.0}, r.args[1])
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := s.Query(ctx, nil, 1)
+		assert.ErrorIs(t, err, vectorstore.ErrEmptyVector)
+		matches, err := s.Query(ctx, []float64{1}, 0)
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+}
+
+func TestStore_Delete(t *testing.T) {
+	ctx := context.Background()
+	s, r := newStore(t)
+	require.NoError(t, s.Delete(ctx, "a", "b"))
+	require.NoError(t, s.Delete(ctx))
+	require.Len(t, r.statements, 1)
+	assert.Equal(t, "DELETE FROM docs WHERE id IN ($1, $2)", r.statements[0])
+	assert.Equal(t, []driver.Value{"a", "b"}, r.args[0])
+}
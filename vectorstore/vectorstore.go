@@ -0,0 +1,112 @@
+package vectorstore
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+var (
+	// ErrEmptyVector is returned for a document or query without a vector.
+	ErrEmptyVector = errors.New("vectorstore: empty vector")
+
+	// ErrMissingID is returned by Upsert for a document without an ID.
+	ErrMissingID = errors.New("vectorstore: document has no ID")
+)
+
+// Document is a text stored with its embedding.
+type Document struct {
+	ID       string
+	Content  string
+	Metadata map[string]string
+	Vector   []float64
+}
+
+// Match is a document returned by a query, with its cosine similarity to
+// the query vector. Stores may leave Document.Vector unset in matches.
+type Match struct {
+	Document
+	Score float64
+}
+
+// Store holds documents and finds those nearest to a query vector.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Upsert adds documents, replacing stored documents with the same ID.
+	Upsert(ctx context.Context, docs ...Document) error
+
+	// Query returns up to k documents most similar to vector, best match
+	// first.
+	Query(ctx context.Context, vector []float64, k int, opts ...QueryOption) ([]Match, error)
+
+	// Delete removes the documents with the given IDs. Unknown IDs are
+	// ignored.
+	Delete(ctx context.Context, ids ...string) error
+}
+
+// QueryOptions configures a query.
+type QueryOptions struct {
+	// Filter restricts matches to documents whose metadata contains every
+	// key with the given value.
+	Filter map[string]string
+
+	// MinScore drops matches whose similarity is below it. Zero means no
+	// minimum.
+	MinScore float64
+}
+
+// QueryOption configures a query.
+type QueryOption func(*QueryOptions)
+
+// WithFilter restricts matches to documents whose metadata contains every
+// key of filter with the same value.
+func WithFilter(filter map[string]string) QueryOption {
+	return func(o *QueryOptions) {
+		o.Filter = filter
+	}
+}
+
+// WithMinScore drops matches whose cosine similarity is below score.
+func WithMinScore(score float64) QueryOption {
+	return func(o *QueryOptions) {
+		o.MinScore = score
+	}
+}
+
+// ApplyQueryOptions applies query options, for use by Store
+// implementations.
+func ApplyQueryOptions(opts ...QueryOption) *QueryOptions {
+	o := &QueryOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Matches reports whether metadata contains every entry of filter.
+func (o *QueryOptions) Matches(metadata map[string]string) bool {
+	for k, v := range o.Filter {
+		if got, ok := metadata[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Cosine returns the cosine similarity of a and b, or 0 if either is zero
+// or their lengths differ.
+func Cosine(a, b []float64) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -87,6 +87,9 @@ func NewAgentStep[S any](
 // Name returns the step name.
 func (a *AgentStep[S]) Name() string { return a.name }
 
+// DescribeStep describes the step for Workflow.Describe.
+func (a *AgentStep[S]) DescribeStep() StepInfo { return StepInfo{Kind: KindAgent} }
+
 // Run executes the agent to completion.
 func (a *AgentStep[S]) Run(ctx context.Context, state *S, opts ...Option) error {
 	options := ApplyOptions(opts...)
@@ -116,6 +119,9 @@ func (a *AgentStep[S]) Run(ctx context.Context, state *S, opts ...Option) error
 	// Create and run agent
 	ag := agent.New(a.chatClient, a.registry)
 	result, err := ag.Run(ctx, msgs, agentOpts...)
+	if result != nil {
+		recordUsage(ctx, result.TotalUsage)
+	}
 	if err != nil {
 		return &StepError{StepName: a.name, Err: err}
 	}
@@ -136,10 +142,11 @@ func (a *AgentStep[S]) Run(ctx context.Context, state *S, opts ...Option) error
 
 // RunStream executes the agent and emits mapped workflow events.
 func (a *AgentStep[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-chan Event {
-	ch := make(chan Event, 100)
+	ch := newEventChannel(opts)
 
 	go func() {
 		defer close(ch)
+		defer recoverStream(ch, a.name)
 
 		options := ApplyOptions(opts...)
 
@@ -159,7 +166,8 @@ func (a *AgentStep[S]) RunStream(ctx context.Context, state *S, opts ...Option)
 		chatOpts = append(chatOpts, options.ChatOptions...)
 
 		// Build agent options
-		agentOpts := make([]agent.Option, 0, len(a.agentOpts)+1)
+		agentOpts := make([]agent.Option, 0, len(a.agentOpts)+2)
+		agentOpts = append(agentOpts, agent.WithEventBuffer(options.EventBuffer))
 		agentOpts = append(agentOpts, a.agentOpts...)
 		if len(chatOpts) > 0 {
 			agentOpts = append(agentOpts, agent.WithChatOptions(chatOpts...))
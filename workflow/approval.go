@@ -0,0 +1,314 @@
+package workflow
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spetersoncode/gains/agent"
+	"github.com/spetersoncode/gains/event"
+)
+
+// Approval is a human decision on an approval step.
+type Approval struct {
+	Approved bool `json:"approved"`
+	// Comment is the approver's note, such as the reason for a rejection.
+	Comment string `json:"comment,omitempty"`
+}
+
+// ApprovalOption configures an ApprovalStep.
+type ApprovalOption func(*approvalConfig)
+
+type approvalConfig struct {
+	title  string
+	broker *agent.UserInputBroker
+}
+
+// WithApprovalBroker makes the step block until a decision arrives through
+// broker, such as from agui.HandleUserInput. The response's Confirmed field
+// approves, Value is the comment, and a cancelled request rejects.
+func WithApprovalBroker(broker *agent.UserInputBroker) ApprovalOption {
+	return func(c *approvalConfig) {
+		c.broker = broker
+	}
+}
+
+// WithApprovalTitle sets the title of the approval request shown to the
+// approver.
+func WithApprovalTitle(title string) ApprovalOption {
+	return func(c *approvalConfig) {
+		c.title = title
+	}
+}
+
+// ApprovalStep asks a human to approve the workflow's progress and runs
+// the approve or reject branch according to the decision.
+type ApprovalStep[S any] struct {
+	name    string
+	message func(*S) string
+	approve Step[S]
+	reject  Step[S]
+	config  approvalConfig
+}
+
+// NewApprovalStep creates a step that requests approval with the message
+// built from state, emitting an ActivityUserInput event of type "confirm",
+// and then runs approve or reject. A nil approve continues the workflow
+// with no step; a nil reject fails it with ErrApprovalRejected. Branches
+// read the decision with ApprovalFrom.
+//
+// With WithApprovalBroker, the step blocks until the decision arrives
+// through the broker. Otherwise the run must be checkpointed, and the step
+// suspends it: the run ends with ErrSuspended, TerminationSuspended, and
+// Result.ResumeToken, which the activity also carries. Continue it with
+// Workflow.ResumeWithApproval once the decision is made. An approval step
+// inside a Parallel branch, which is not checkpointed, needs a broker.
+//
+// A dry run asks for no decision and walks both branches.
+//
+// Example:
+//
+//	publish := NewApprovalStep("review",
+//	    func(s *PostState) string { return "Publish this post?\n\n" + s.Draft },
+//	    publishStep,
+//	    reviseStep,
+//	)
+func NewApprovalStep[S any](name string, message func(*S) string, approve, reject Step[S], opts ...ApprovalOption) *ApprovalStep[S] {
+	a := &ApprovalStep[S]{
+		name:    name,
+		message: message,
+		approve: approve,
+		reject:  reject,
+		config:  approvalConfig{title: "Approval required"},
+	}
+	for _, opt := range opts {
+		opt(&a.config)
+	}
+	return a
+}
+
+// Name returns the step name.
+func (a *ApprovalStep[S]) Name() string { return a.name }
+
+// DescribeStep describes the approval step and its branches for
+// Workflow.Describe.
+func (a *ApprovalStep[S]) DescribeStep() StepInfo {
+	info := StepInfo{Kind: KindApproval, Flow: FlowBranch}
+	if a.approve != nil {
+		info.Children = append(info.Children, ChildStep{Step: a.approve, Label: "approved"})
+	}
+	if a.reject != nil {
+		info.Children = append(info.Children, ChildStep{Step: a.reject, Label: "rejected"})
+	}
+	return info
+}
+
+// Run requests approval and executes the matching branch.
+func (a *ApprovalStep[S]) Run(ctx context.Context, state *S, opts ...Option) error {
+	if dryRun(ctx, ApplyOptions(opts...)) {
+		for _, d := range []Approval{{Approved: true}, {}} {
+			if step := a.branch(d); step != nil {
+				if err := step.Run(withApproval(ctx, d), state, opts...); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	d, err := a.decide(ctx, state, nil)
+	if err != nil {
+		return err
+	}
+	step := a.branch(d)
+	if step == nil {
+		return a.rejected(d)
+	}
+	return step.Run(withApproval(ctx, d), state, opts...)
+}
+
+// RunStream requests approval, emitting the request as an activity, and
+// executes the matching branch.
+func (a *ApprovalStep[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-chan Event {
+	ch := newEventChannel(opts)
+
+	go func() {
+		defer close(ch)
+		defer recoverStream(ch, a.name)
+		event.Emit(ch, Event{Type: event.StepStart, StepName: a.name})
+
+		var decisions []Approval
+		if dryRun(ctx, ApplyOptions(opts...)) {
+			decisions = []Approval{{Approved: true}, {}}
+		} else {
+			d, err := a.decide(ctx, state, ch)
+			if err != nil {
+				event.Emit(ch, Event{Type: event.RunError, StepName: a.name, Error: err})
+				return
+			}
+			decisions = []Approval{d}
+		}
+
+		for _, d := range decisions {
+			route := "rejected"
+			if d.Approved {
+				route = "approved"
+			}
+			event.Emit(ch, Event{Type: event.RouteSelected, StepName: a.name, RouteName: route})
+
+			step := a.branch(d)
+			if step == nil {
+				if err := a.rejected(d); err != nil && len(decisions) == 1 {
+					event.Emit(ch, Event{Type: event.RunError, StepName: a.name, Error: err})
+					return
+				}
+				continue
+			}
+			for ev := range step.RunStream(withApproval(ctx, d), state, opts...) {
+				ch <- ev
+				if ev.Type == event.RunError {
+					return
+				}
+			}
+		}
+		event.Emit(ch, Event{Type: event.StepEnd, StepName: a.name})
+	}()
+
+	return ch
+}
+
+// branch returns the step to run for decision d, or nil.
+func (a *ApprovalStep[S]) branch(d Approval) Step[S] {
+	if d.Approved {
+		return a.approve
+	}
+	return a.reject
+}
+
+// rejected returns the error of a decision with no branch to run: nil if
+// it approves, and ErrApprovalRejected otherwise.
+func (a *ApprovalStep[S]) rejected(d Approval) error {
+	switch {
+	case d.Approved:
+		return nil
+	case d.Comment != "":
+		return &StepError{StepName: a.name, Err: fmt.Errorf("%w: %s", ErrApprovalRejected, d.Comment)}
+	default:
+		return &StepError{StepName: a.name, Err: ErrApprovalRejected}
+	}
+}
+
+// decide returns the decision given to ResumeWithApproval, or requests one
+// through the broker, or suspends the run. Activity events are emitted to
+// ch unless it is nil.
+func (a *ApprovalStep[S]) decide(ctx context.Context, state *S, ch chan<- Event) (Approval, error) {
+	emit := func(e Event) {
+		if ch != nil {
+			event.Emit(ch, e)
+		}
+	}
+
+	cp := checkpointerFrom(ctx)
+	if d, ok := cp.decision(a.name); ok {
+		return d, nil
+	}
+
+	message := a.message(state)
+	if a.config.broker != nil {
+		var requestID string
+		resp, err := a.config.broker.RequestWith(ctx, agent.UserInputRequest{
+			Type:    agent.InputTypeConfirm,
+			Title:   a.config.title,
+			Message: message,
+		}, func(req agent.UserInputRequest) {
+			requestID = req.ID
+			emit(event.NewUserInputPending(req.ID, string(req.Type), req.Title, req.Message, nil, "", ""))
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				emit(event.NewUserInputCancelled(requestID))
+			} else {
+				emit(event.NewUserInputTimeout(requestID))
+			}
+			return Approval{}, &StepError{StepName: a.name, Err: err}
+		}
+		if resp.Cancelled {
+			emit(event.NewUserInputCancelled(requestID))
+			return Approval{Comment: resp.Value}, nil
+		}
+		emit(event.NewUserInputResponded(requestID, resp.Value, resp.Confirmed))
+		return Approval{Approved: resp.Confirmed, Comment: resp.Value}, nil
+	}
+
+	if cp == nil {
+		return Approval{}, &StepError{StepName: a.name, Err: ErrNoApprover}
+	}
+	token, err := cp.suspend(ctx, a.name, state)
+	if err != nil {
+		return Approval{}, err
+	}
+	emit(event.NewUserInputSuspended(token, string(agent.InputTypeConfirm), a.config.title, message, token))
+	return Approval{}, &suspendedError{step: a.name, token: token}
+}
+
+// suspendedError is returned by an approval step that suspended the run.
+type suspendedError struct {
+	step  string
+	token string
+}
+
+func (e *suspendedError) Error() string {
+	return fmt.Sprintf("workflow: run suspended for approval at step %q", e.step)
+}
+
+func (e *suspendedError) Is(target error) bool { return target == ErrSuspended }
+
+// approvalKeyType is the context key for the decision of the enclosing
+// approval step.
+type approvalKeyType struct{}
+
+func withApproval(ctx context.Context, d Approval) context.Context {
+	return context.WithValue(ctx, approvalKeyType{}, d)
+}
+
+// ApprovalFrom returns the decision of the approval step whose branch is
+// running under ctx.
+func ApprovalFrom(ctx context.Context) (Approval, bool) {
+	d, ok := ctx.Value(approvalKeyType{}).(Approval)
+	return d, ok
+}
+
+// resumeToken identifies an approval step awaiting a decision in a
+// checkpointed run. Nonce is random, so a token cannot be forged from the
+// run ID and step name; the checkpoint keeps the nonce it issued to compare
+// against.
+type resumeToken struct {
+	RunID string `json:"r"`
+	Step  string `json:"s"`
+	Nonce string `json:"n"`
+}
+
+// newResumeToken encodes a resume token for the approval step of runID,
+// returning it with its nonce.
+func newResumeToken(runID, step string) (token, nonce string) {
+	b := make([]byte, 16)
+	rand.Read(b)
+	nonce = base64.RawURLEncoding.EncodeToString(b)
+	raw, _ := json.Marshal(resumeToken{RunID: runID, Step: step, Nonce: nonce})
+	return base64.RawURLEncoding.EncodeToString(raw), nonce
+}
+
+// parseResumeToken decodes token.
+func parseResumeToken(token string) (resumeToken, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	var t resumeToken
+	if err == nil {
+		err = json.Unmarshal(raw, &t)
+	}
+	if err != nil || t.RunID == "" || t.Step == "" || t.Nonce == "" {
+		return resumeToken{}, ErrInvalidResumeToken
+	}
+	return t, nil
+}
@@ -0,0 +1,249 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spetersoncode/gains/agent"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type approvalState struct {
+	Draft     string
+	Published bool
+	Feedback  string
+	Drafts    int
+}
+
+// approvalChain drafts, asks for approval, and publishes or records the
+// reviewer's feedback.
+func approvalChain(opts ...ApprovalOption) *Chain[approvalState] {
+	return NewChain("post",
+		NewFuncStep("draft", func(ctx context.Context, s *approvalState) error {
+			s.Drafts++
+			s.Draft = "hello"
+			return nil
+		}),
+		NewApprovalStep("review",
+			func(s *approvalState) string { return "Publish " + s.Draft + "?" },
+			NewFuncStep("publish", func(ctx context.Context, s *approvalState) error {
+				s.Published = true
+				return nil
+			}),
+			NewFuncStep("revise", func(ctx context.Context, s *approvalState) error {
+				d, _ := ApprovalFrom(ctx)
+				s.Feedback = d.Comment
+				return nil
+			}),
+			opts...,
+		),
+	)
+}
+
+func TestApprovalStep_Suspend(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	wf := New("publish", approvalChain(), WithCheckpointer(adapter))
+
+	result, err := wf.Run(context.Background(), &approvalState{})
+	require.ErrorIs(t, err, ErrSuspended)
+	assert.Equal(t, TerminationSuspended, result.Termination)
+	require.NotEmpty(t, result.ResumeToken)
+	assert.False(t, result.State.Published)
+
+	resumed, err := wf.ResumeWithApproval(context.Background(), result.ResumeToken, Approval{Approved: true})
+	require.NoError(t, err)
+	assert.Equal(t, TerminationComplete, resumed.Termination)
+	assert.True(t, resumed.State.Published)
+	assert.Equal(t, 1, resumed.State.Drafts, "completed steps should not rerun")
+
+	_, err = wf.ResumeWithApproval(context.Background(), result.ResumeToken, Approval{Approved: true})
+	assert.ErrorIs(t, err, ErrInvalidResumeToken, "decided steps cannot be decided again")
+}
+
+func TestApprovalStep_SuspendReject(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	wf := New("publish", approvalChain(), WithCheckpointer(adapter))
+
+	result, err := wf.Run(context.Background(), &approvalState{})
+	require.ErrorIs(t, err, ErrSuspended)
+
+	resumed, err := wf.ResumeWithApproval(context.Background(), result.ResumeToken,
+		Approval{Comment: "too short"})
+	require.NoError(t, err)
+	assert.False(t, resumed.State.Published)
+	assert.Equal(t, "too short", resumed.State.Feedback)
+}
+
+func TestApprovalStep_SuspendStream(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	wf := New("publish", approvalChain(WithApprovalTitle("Review post")), WithCheckpointer(adapter))
+
+	var activity *event.UserInputActivity
+	var runErr error
+	for ev := range wf.RunStream(context.Background(), &approvalState{}) {
+		switch ev.Type {
+		case event.ActivitySnapshot:
+			a := ev.ActivityContent.(event.UserInputActivity)
+			activity = &a
+		case event.RunError:
+			runErr = ev.Error
+		}
+	}
+	require.ErrorIs(t, runErr, ErrSuspended)
+	require.NotNil(t, activity)
+	assert.Equal(t, "confirm", activity.Type)
+	assert.Equal(t, "Review post", activity.Title)
+	assert.Equal(t, "Publish hello?", activity.Message)
+	require.NotEmpty(t, activity.ResumeToken)
+
+	resumed, err := wf.ResumeWithApproval(context.Background(), activity.ResumeToken, Approval{Approved: true})
+	require.NoError(t, err)
+	assert.True(t, resumed.State.Published)
+}
+
+func TestApprovalStep_Broker(t *testing.T) {
+	broker := agent.NewUserInputBrokerWith(agent.WithInputTimeout(time.Second))
+	step := approvalChain(WithApprovalBroker(broker))
+
+	var events []Event
+	state := &approvalState{}
+	for ev := range step.RunStream(context.Background(), state) {
+		events = append(events, ev)
+		if ev.Type == event.ActivitySnapshot {
+			require.NoError(t, broker.Respond(agent.UserInputResponse{
+				RequestID: ev.ActivityID,
+				Confirmed: true,
+			}))
+		}
+	}
+
+	assert.True(t, state.Published)
+	var route string
+	for _, ev := range events {
+		assert.NotEqual(t, event.RunError, ev.Type, "unexpected error: %v", ev.Error)
+		if ev.Type == event.RouteSelected {
+			route = ev.RouteName
+		}
+	}
+	assert.Equal(t, "approved", route)
+}
+
+func TestApprovalStep_BrokerCancelled(t *testing.T) {
+	broker := agent.NewUserInputBrokerWith(agent.WithInputTimeout(time.Second))
+	step := NewApprovalStep[approvalState]("review",
+		func(s *approvalState) string { return "Publish?" }, nil, nil,
+		WithApprovalBroker(broker))
+
+	var runErr error
+	for ev := range step.RunStream(context.Background(), &approvalState{}) {
+		switch ev.Type {
+		case event.ActivitySnapshot:
+			require.NoError(t, broker.Respond(agent.UserInputResponse{
+				RequestID: ev.ActivityID,
+				Cancelled: true,
+			}))
+		case event.RunError:
+			runErr = ev.Error
+		}
+	}
+	assert.ErrorIs(t, runErr, ErrApprovalRejected)
+}
+
+func TestApprovalStep_NoApprover(t *testing.T) {
+	step := NewApprovalStep[approvalState]("review",
+		func(s *approvalState) string { return "Publish?" }, nil, nil)
+	assert.ErrorIs(t, step.Run(context.Background(), &approvalState{}), ErrNoApprover)
+}
+
+func TestApprovalStep_DryRun(t *testing.T) {
+	state := &approvalState{}
+	require.NoError(t, approvalChain().Run(context.Background(), state, WithDryRun()))
+	assert.True(t, state.Published, "dry runs walk both branches")
+}
+
+func TestWorkflow_ResumeWithApproval_InvalidToken(t *testing.T) {
+	wf := New("publish", approvalChain(), WithCheckpointer(store.NewMemoryAdapter()))
+	_, err := wf.ResumeWithApproval(context.Background(), "not-a-token", Approval{Approved: true})
+	assert.ErrorIs(t, err, ErrInvalidResumeToken)
+}
+
+func TestWorkflow_ResumeWithApproval_Concurrent(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	var published atomic.Int32
+	wf := New("publish", NewChain("post",
+		NewApprovalStep("review",
+			func(s *approvalState) string { return "Publish?" },
+			NewFuncStep("publish", func(ctx context.Context, s *approvalState) error {
+				published.Add(1)
+				return nil
+			}),
+			nil,
+		),
+	), WithCheckpointer(adapter))
+
+	result, err := wf.Run(context.Background(), &approvalState{})
+	require.ErrorIs(t, err, ErrSuspended)
+
+	const n = 8
+	var wg sync.WaitGroup
+	var succeeded, rejected atomic.Int32
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := wf.ResumeWithApproval(context.Background(), result.ResumeToken, Approval{Approved: true})
+			switch {
+			case err == nil:
+				succeeded.Add(1)
+			case errors.Is(err, ErrInvalidResumeToken):
+				rejected.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), succeeded.Load(), "only one decision continues the run")
+	assert.Equal(t, int32(n-1), rejected.Load())
+	assert.Equal(t, int32(1), published.Load(), "the approved branch runs once")
+}
+
+func TestWorkflow_ResumeWithApproval_ForgedToken(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	wf := New("publish", approvalChain(), WithCheckpointer(adapter))
+
+	result, err := wf.Run(context.Background(), &approvalState{})
+	require.ErrorIs(t, err, ErrSuspended)
+
+	forged, _ := newResumeToken(result.RunID, "review")
+	_, err = wf.ResumeWithApproval(context.Background(), forged, Approval{Approved: true})
+	assert.ErrorIs(t, err, ErrInvalidResumeToken)
+
+	resumed, err := wf.ResumeWithApproval(context.Background(), result.ResumeToken, Approval{Approved: true})
+	require.NoError(t, err, "the issued token still works")
+	assert.True(t, resumed.State.Published)
+}
+
+func TestWorkflow_Resume_RecordedDecision(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	wf := New("publish", approvalChain(), WithCheckpointer(adapter))
+
+	result, err := wf.Run(context.Background(), &approvalState{})
+	require.ErrorIs(t, err, ErrSuspended)
+
+	// The process stops after recording the decision, before continuing
+	tok, err := parseResumeToken(result.ResumeToken)
+	require.NoError(t, err)
+	_, err = decide(context.Background(), adapter, tok, Approval{Approved: true})
+	require.NoError(t, err)
+
+	resumed, err := wf.Resume(context.Background(), result.RunID)
+	require.NoError(t, err)
+	assert.True(t, resumed.State.Published, "Resume applies the recorded decision")
+}
@@ -0,0 +1,203 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spetersoncode/gains/internal/store"
+)
+
+// ErrArtifactNotFound indicates no artifact exists for a run ID or name.
+var ErrArtifactNotFound = errors.New("workflow: artifact not found")
+
+// Artifact is a named output of a workflow run, persisted by
+// WithArtifactStore from a state field tagged with `artifact:"name"`.
+// Each time a workflow stores an artifact of the same name, its version
+// increases by one.
+type Artifact struct {
+	Name      string          `json:"name"`
+	Version   int             `json:"version"`
+	Workflow  string          `json:"workflow"`
+	RunID     string          `json:"runId"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// Decode unmarshals the artifact's data into v.
+func (a *Artifact) Decode(v any) error {
+	if err := json.Unmarshal(a.Data, v); err != nil {
+		return &store.SerializationError{Key: artifactRunKey(a.RunID), Err: err}
+	}
+	return nil
+}
+
+// artifactRun is the persisted set of artifacts of one run.
+type artifactRun struct {
+	RunID     string              `json:"runId"`
+	Workflow  string              `json:"workflow"`
+	Artifacts map[string]Artifact `json:"artifacts"`
+}
+
+// artifactLatest points at the latest version of a named artifact.
+type artifactLatest struct {
+	Version int    `json:"version"`
+	RunID   string `json:"runId"`
+}
+
+// artifactRunKey returns the adapter key for a run's artifacts.
+func artifactRunKey(runID string) string {
+	return "artifact/run/" + runID
+}
+
+// artifactLatestKey returns the adapter key for the latest version of a
+// workflow's named artifact.
+func artifactLatestKey(workflow, name string) string {
+	return "artifact/latest/" + workflow + "/" + name
+}
+
+// artifactFields returns the values of the fields of state tagged with
+// `artifact:"name"`, keyed by name. Only top-level fields of a struct state
+// are considered; a tag of "-" or an empty name is ignored.
+func artifactFields(state any) map[string]any {
+	v := reflect.ValueOf(state)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := make(map[string]any)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("artifact"), ",")
+		if name == "" || name == "-" || !f.IsExported() {
+			continue
+		}
+		fields[name] = v.Field(i).Interface()
+	}
+	return fields
+}
+
+// saveArtifacts stores the tagged fields of state as the artifacts of
+// runID, each as the next version of its name. A run that is saved again,
+// such as after Resume, replaces its earlier artifacts with new versions.
+func saveArtifacts(ctx context.Context, adapter store.Adapter, workflow, runID string, state any) error {
+	fields := artifactFields(state)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	run := artifactRun{RunID: runID, Workflow: workflow, Artifacts: make(map[string]Artifact, len(fields))}
+	now := time.Now()
+	for _, name := range names {
+		data, err := json.Marshal(fields[name])
+		if err != nil {
+			return &store.SerializationError{Key: artifactRunKey(runID), Err: err}
+		}
+		latest, err := loadLatest(ctx, adapter, workflow, name)
+		if err != nil {
+			return err
+		}
+		latest.Version++
+		latest.RunID = runID
+		run.Artifacts[name] = Artifact{
+			Name:      name,
+			Version:   latest.Version,
+			Workflow:  workflow,
+			RunID:     runID,
+			Data:      data,
+			CreatedAt: now,
+		}
+		raw, err := json.Marshal(latest)
+		if err != nil {
+			return &store.SerializationError{Key: artifactLatestKey(workflow, name), Err: err}
+		}
+		if err := adapter.Set(ctx, artifactLatestKey(workflow, name), raw); err != nil {
+			return err
+		}
+	}
+
+	raw, err := json.Marshal(run)
+	if err != nil {
+		return &store.SerializationError{Key: artifactRunKey(runID), Err: err}
+	}
+	return adapter.Set(ctx, artifactRunKey(runID), raw)
+}
+
+// loadLatest reads the latest version pointer of a named artifact. A name
+// that was never stored has version 0.
+func loadLatest(ctx context.Context, adapter store.Adapter, workflow, name string) (artifactLatest, error) {
+	var latest artifactLatest
+	raw, ok, err := adapter.Get(ctx, artifactLatestKey(workflow, name))
+	if err != nil || !ok {
+		return latest, err
+	}
+	if err := json.Unmarshal(raw, &latest); err != nil {
+		return latest, &store.SerializationError{Key: artifactLatestKey(workflow, name), Err: err}
+	}
+	return latest, nil
+}
+
+// LoadArtifacts returns the artifacts stored for runID, sorted by name. It
+// returns an error matching ErrArtifactNotFound if the run stored none.
+func LoadArtifacts(ctx context.Context, adapter store.Adapter, runID string) ([]Artifact, error) {
+	raw, ok, err := adapter.Get(ctx, artifactRunKey(runID))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: run %s", ErrArtifactNotFound, runID)
+	}
+	var run artifactRun
+	if err := json.Unmarshal(raw, &run); err != nil {
+		return nil, &store.SerializationError{Key: artifactRunKey(runID), Err: err}
+	}
+	artifacts := make([]Artifact, 0, len(run.Artifacts))
+	for _, a := range run.Artifacts {
+		artifacts = append(artifacts, a)
+	}
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].Name < artifacts[j].Name })
+	return artifacts, nil
+}
+
+// LoadArtifact returns the artifact name stored for runID. It returns an
+// error matching ErrArtifactNotFound if the run did not store it.
+func LoadArtifact(ctx context.Context, adapter store.Adapter, runID, name string) (*Artifact, error) {
+	artifacts, err := LoadArtifacts(ctx, adapter, runID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range artifacts {
+		if artifacts[i].Name == name {
+			return &artifacts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s in run %s", ErrArtifactNotFound, name, runID)
+}
+
+// LatestArtifact returns the most recent version of the artifact name
+// stored by the named workflow. It returns an error matching
+// ErrArtifactNotFound if the workflow never stored it.
+func LatestArtifact(ctx context.Context, adapter store.Adapter, workflow, name string) (*Artifact, error) {
+	latest, err := loadLatest(ctx, adapter, workflow, name)
+	if err != nil {
+		return nil, err
+	}
+	if latest.Version == 0 {
+		return nil, fmt.Errorf("%w: %s of workflow %q", ErrArtifactNotFound, name, workflow)
+	}
+	return LoadArtifact(ctx, adapter, latest.RunID, name)
+}
@@ -0,0 +1,121 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reportState has fields stored as artifacts.
+type reportState struct {
+	Topic  string
+	Report string `artifact:"report"`
+	Score  int    `artifact:"score"`
+	Draft  string `artifact:"-"`
+}
+
+func reportWorkflow(opts ...Option) *Workflow[reportState] {
+	return New("report", NewFuncStep("write", func(ctx context.Context, s *reportState) error {
+		s.Report = "all about " + s.Topic
+		s.Score = len(s.Topic)
+		s.Draft = "scratch"
+		return nil
+	}), opts...)
+}
+
+func TestWorkflow_Artifacts(t *testing.T) {
+	ctx := context.Background()
+	adapter := store.NewMemoryAdapter()
+	wf := reportWorkflow(WithArtifactStore(adapter))
+
+	result, err := wf.Run(ctx, &reportState{Topic: "go"})
+	require.NoError(t, err)
+	require.NotEmpty(t, result.RunID)
+
+	artifacts, err := LoadArtifacts(ctx, adapter, result.RunID)
+	require.NoError(t, err)
+	require.Len(t, artifacts, 2)
+	assert.Equal(t, "report", artifacts[0].Name)
+	assert.Equal(t, "score", artifacts[1].Name)
+	assert.Equal(t, 1, artifacts[0].Version)
+	assert.Equal(t, "report", artifacts[0].Workflow)
+	assert.Equal(t, result.RunID, artifacts[0].RunID)
+
+	var report string
+	require.NoError(t, artifacts[0].Decode(&report))
+	assert.Equal(t, "all about go", report)
+
+	t.Run("versions increase across runs", func(t *testing.T) {
+		second, err := wf.Run(ctx, &reportState{Topic: "rust"}, WithRunID("run-2"))
+		require.NoError(t, err)
+		assert.Equal(t, "run-2", second.RunID)
+
+		a, err := LoadArtifact(ctx, adapter, "run-2", "score")
+		require.NoError(t, err)
+		assert.Equal(t, 2, a.Version)
+
+		latest, err := LatestArtifact(ctx, adapter, "report", "report")
+		require.NoError(t, err)
+		assert.Equal(t, "run-2", latest.RunID)
+		require.NoError(t, latest.Decode(&report))
+		assert.Equal(t, "all about rust", report)
+	})
+
+	t.Run("missing artifacts", func(t *testing.T) {
+		_, err := LoadArtifacts(ctx, adapter, "unknown")
+		assert.True(t, errors.Is(err, ErrArtifactNotFound))
+
+		_, err = LoadArtifact(ctx, adapter, result.RunID, "draft")
+		assert.True(t, errors.Is(err, ErrArtifactNotFound))
+
+		_, err = LatestArtifact(ctx, adapter, "other", "report")
+		assert.True(t, errors.Is(err, ErrArtifactNotFound))
+	})
+}
+
+func TestWorkflow_ArtifactsNotStored(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("failed run", func(t *testing.T) {
+		adapter := store.NewMemoryAdapter()
+		wf := New("report", NewFuncStep("write", func(ctx context.Context, s *reportState) error {
+			return errors.New("boom")
+		}), WithArtifactStore(adapter))
+
+		result, err := wf.Run(ctx, &reportState{})
+		require.Error(t, err)
+		_, err = LoadArtifacts(ctx, adapter, result.RunID)
+		assert.True(t, errors.Is(err, ErrArtifactNotFound))
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		adapter := store.NewMemoryAdapter()
+		result, err := reportWorkflow(WithArtifactStore(adapter)).Run(ctx, &reportState{}, WithDryRun())
+		require.NoError(t, err)
+		assert.Empty(t, result.RunID)
+		n, err := adapter.Len(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, n)
+	})
+}
+
+func TestWorkflow_RunStream_Artifacts(t *testing.T) {
+	ctx := context.Background()
+	adapter := store.NewMemoryAdapter()
+	wf := reportWorkflow(WithArtifactStore(adapter))
+
+	for ev := range wf.RunStream(ctx, &reportState{Topic: "go"}, WithRunID("stream-1")) {
+		require.NotEqual(t, event.RunError, ev.Type)
+	}
+
+	a, err := LoadArtifact(ctx, adapter, "stream-1", "report")
+	require.NoError(t, err)
+	var report string
+	require.NoError(t, a.Decode(&report))
+	assert.Equal(t, "all about go", report)
+}
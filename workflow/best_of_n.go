@@ -0,0 +1,337 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/chat"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/internal/group"
+	"github.com/spetersoncode/gains/judge"
+)
+
+// ErrNoCandidates indicates that no completion of a best-of-N step could
+// be sampled and scored.
+var ErrNoCandidates = errors.New("workflow: no candidate completions")
+
+// DefaultSamples is the number of completions a BestOfNStep samples
+// without WithSamples or WithSampleModels.
+const DefaultSamples = 3
+
+// Scorer rates a candidate response to the prompt messages. Higher scores
+// are better.
+type Scorer func(ctx context.Context, prompt []ai.Message, response string) (float64, error)
+
+// Candidate is one completion sampled by a BestOfNStep.
+type Candidate struct {
+	// Index is the candidate's position among the samples, from 0.
+	Index int `json:"index"`
+	// Model is the model that answered, if set with WithSampleModels.
+	Model   string  `json:"model,omitempty"`
+	Content string  `json:"content"`
+	Score   float64 `json:"score"`
+	// Reasoning is the judge's explanation of the score.
+	Reasoning string `json:"reasoning,omitempty"`
+	// Error is the message of the error the candidate failed with, when it
+	// could not be sampled or scored.
+	Error string `json:"error,omitempty"`
+}
+
+// BestOfN is the outcome of a BestOfNStep.
+type BestOfN struct {
+	// Winner is the highest-scoring candidate; ties go to the lower index.
+	Winner Candidate `json:"winner"`
+	// Candidates are all the samples, in index order, including failures.
+	Candidates []Candidate `json:"candidates"`
+}
+
+// BestOfNOption configures a BestOfNStep.
+type BestOfNOption func(*bestOfNConfig)
+
+type bestOfNConfig struct {
+	samples  int
+	models   []ai.Model
+	chatOpts []ai.Option
+	scorer   Scorer
+	judge    *judge.Judge
+	criteria string
+}
+
+// WithSamples sets the number of completions to sample. Default is
+// DefaultSamples, or the number of models given to WithSampleModels.
+func WithSamples(n int) BestOfNOption {
+	return func(c *bestOfNConfig) {
+		c.samples = n
+	}
+}
+
+// WithSampleModels spreads the samples across models, in turn, so the
+// step can pick the best answer of several models.
+func WithSampleModels(models ...ai.Model) BestOfNOption {
+	return func(c *bestOfNConfig) {
+		c.models = append(c.models, models...)
+	}
+}
+
+// WithSampleOptions passes chat options to every sampled completion, such
+// as a temperature high enough for the samples to differ.
+func WithSampleOptions(opts ...ai.Option) BestOfNOption {
+	return func(c *bestOfNConfig) {
+		c.chatOpts = append(c.chatOpts, opts...)
+	}
+}
+
+// WithScorer scores candidates with fn instead of an LLM judge.
+func WithScorer(fn Scorer) BestOfNOption {
+	return func(c *bestOfNConfig) {
+		c.scorer = fn
+	}
+}
+
+// WithJudge scores candidates with j, from judge.MinScore to
+// judge.MaxScore, recording its reasoning.
+func WithJudge(j *judge.Judge) BestOfNOption {
+	return func(c *bestOfNConfig) {
+		c.judge = j
+	}
+}
+
+// WithJudgeCriteria sets what the default judge rates candidates on. It
+// has no effect with WithJudge or WithScorer.
+func WithJudgeCriteria(criteria string) BestOfNOption {
+	return func(c *bestOfNConfig) {
+		c.criteria = criteria
+	}
+}
+
+// BestOfNStep samples several completions of the same prompt, scores
+// them, and stores the best with every score in a state field.
+type BestOfNStep[S any] struct {
+	name   string
+	client chat.Client
+	prompt PromptFunc[S]
+	field  func(*S) *BestOfN
+	config bestOfNConfig
+}
+
+// NewBestOfNStep creates a step that samples completions of the prompt
+// concurrently, optionally across models, and scores each with a judge or
+// a Scorer. The result, naming the winner, is stored in the field.
+// Without WithScorer or WithJudge, candidates are rated by a judge.Judge
+// on c, with the criteria of WithJudgeCriteria.
+//
+// Candidates that fail to be sampled or scored are kept in the result with
+// their error, and the step fails with ErrNoCandidates only if every one
+// does. Concurrency is bounded by WithMaxConcurrency and
+// WithRunConcurrency, like parallel branches.
+//
+// Example:
+//
+//	step := NewBestOfNStep("headline", client,
+//	    func(s *PostState) []ai.Message {
+//	        return []ai.Message{{Role: ai.RoleUser, Content: "Write a headline for:\n" + s.Draft}}
+//	    },
+//	    func(s *PostState) *BestOfN { return &s.Headline },
+//	    WithSampleModels(model.ClaudeSonnet45, model.GPT52),
+//	    WithJudgeCriteria("Accurate, specific, and under ten words."),
+//	)
+func NewBestOfNStep[S any](name string, c chat.Client, prompt PromptFunc[S], field func(*S) *BestOfN, opts ...BestOfNOption) *BestOfNStep[S] {
+	b := &BestOfNStep[S]{
+		name:   name,
+		client: c,
+		prompt: prompt,
+		field:  field,
+	}
+	for _, opt := range opts {
+		opt(&b.config)
+	}
+	if b.config.samples <= 0 {
+		b.config.samples = DefaultSamples
+		if len(b.config.models) > 0 {
+			b.config.samples = len(b.config.models)
+		}
+	}
+	if b.config.scorer == nil && b.config.judge == nil {
+		var judgeOpts []judge.Option
+		if b.config.criteria != "" {
+			judgeOpts = append(judgeOpts, judge.WithCriteria(b.config.criteria))
+		}
+		b.config.judge = judge.New(c, judgeOpts...)
+	}
+	return b
+}
+
+// Name returns the step name.
+func (b *BestOfNStep[S]) Name() string { return b.name }
+
+// DescribeStep describes the step for Workflow.Describe.
+func (b *BestOfNStep[S]) DescribeStep() StepInfo { return StepInfo{Kind: KindBestOfN} }
+
+// Run samples and scores the completions and stores the result.
+func (b *BestOfNStep[S]) Run(ctx context.Context, state *S, opts ...Option) error {
+	options := ApplyOptions(opts...)
+	msgs := b.prompt(state)
+	if dryRun(ctx, options) {
+		return nil
+	}
+	result, err := b.sample(ctx, msgs, options, nil)
+	if err != nil {
+		return err
+	}
+	*b.field(state) = *result
+	return nil
+}
+
+// RunStream samples and scores the completions, emitting progress as each
+// candidate is scored if enabled, and stores the result. The StepEnd event
+// reports the winner.
+func (b *BestOfNStep[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-chan Event {
+	ch := newEventChannel(opts)
+
+	go func() {
+		defer close(ch)
+		defer recoverStream(ch, b.name)
+		event.Emit(ch, Event{Type: event.StepStart, StepName: b.name})
+
+		options := ApplyOptions(opts...)
+		msgs := b.prompt(state)
+		if dryRun(ctx, options) {
+			for i := range b.config.samples {
+				event.Emit(ch, Event{Type: event.ChatPlanned, StepName: b.name, Messages: msgs, Tools: ai.ApplyOptions(b.chatOptions(ctx, options, i)...).Tools})
+			}
+			event.Emit(ch, Event{Type: event.StepEnd, StepName: b.name})
+			return
+		}
+
+		emitProgress(ch, options, b.name, 0, b.config.samples, "samples")
+		result, err := b.sample(ctx, msgs, options, func(completed int) {
+			emitProgress(ch, options, b.name, completed, b.config.samples, "samples")
+		})
+		if err != nil {
+			event.Emit(ch, Event{Type: event.RunError, StepName: b.name, Error: err})
+			return
+		}
+		*b.field(state) = *result
+		event.Emit(ch, Event{
+			Type:     event.StepEnd,
+			StepName: b.name,
+			Response: &ai.Response{Content: result.Winner.Content},
+			Message:  fmt.Sprintf("candidate %d won with score %g", result.Winner.Index, result.Winner.Score),
+		})
+	}()
+
+	return ch
+}
+
+// sample runs every candidate and picks the winner. done, if not nil, is
+// called with the number of candidates finished after each finishes.
+func (b *BestOfNStep[S]) sample(ctx context.Context, msgs []ai.Message, options *Options, done func(completed int)) (*BestOfN, error) {
+	candidates := make([]Candidate, b.config.samples)
+	var mu sync.Mutex
+	var completed int
+
+	var g group.Group
+	g.SetLimit(options.MaxConcurrency)
+	g.SetLimiter(ai.ContextConcurrencyLimiter(ctx))
+	for i := range candidates {
+		g.Go(func() error {
+			c := b.candidate(ctx, msgs, options, i)
+			mu.Lock()
+			defer mu.Unlock()
+			candidates[i] = c
+			completed++
+			if done != nil {
+				done(completed)
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, &StepError{StepName: b.name, Err: err}
+	}
+
+	winner := -1
+	var errs []string
+	for i, c := range candidates {
+		if c.Error != "" {
+			errs = append(errs, fmt.Sprintf("candidate %d: %s", i, c.Error))
+			continue
+		}
+		if winner < 0 || c.Score > candidates[winner].Score {
+			winner = i
+		}
+	}
+	if winner < 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, &StepError{StepName: b.name, Err: fmt.Errorf("%w: %s", ErrNoCandidates, strings.Join(errs, "; "))}
+	}
+	return &BestOfN{Winner: candidates[winner], Candidates: candidates}, nil
+}
+
+// candidate samples and scores candidate i. Failures are recorded in the
+// candidate's Error.
+func (b *BestOfNStep[S]) candidate(ctx context.Context, msgs []ai.Message, options *Options, i int) Candidate {
+	c := Candidate{Index: i}
+	if len(b.config.models) > 0 {
+		c.Model = b.config.models[i%len(b.config.models)].String()
+	}
+
+	resp, err := b.client.Chat(ctx, msgs, b.chatOptions(ctx, options, i)...)
+	if err != nil {
+		c.Error = err.Error()
+		return c
+	}
+	recordUsage(ctx, resp.Usage)
+	c.Content = resp.Content
+
+	if b.config.scorer != nil {
+		c.Score, err = b.config.scorer(ctx, msgs, resp.Content)
+	} else {
+		var verdict *judge.Verdict
+		if verdict, err = b.config.judge.Score(ctx, promptText(msgs), resp.Content); err == nil {
+			recordUsage(ctx, verdict.Usage)
+			c.Score = float64(verdict.Score)
+			c.Reasoning = verdict.Reasoning
+		}
+	}
+	if err != nil {
+		c.Error = err.Error()
+	}
+	return c
+}
+
+// chatOptions returns the chat options of candidate i: the context's, the
+// step's, the run's, and the candidate's model, in that order.
+func (b *BestOfNStep[S]) chatOptions(ctx context.Context, options *Options, i int) []ai.Option {
+	chatOpts := append([]ai.Option{}, ai.ContextOptions(ctx)...)
+	chatOpts = append(chatOpts, b.config.chatOpts...)
+	chatOpts = append(chatOpts, options.ChatOptions...)
+	if len(b.config.models) > 0 {
+		chatOpts = append(chatOpts, ai.WithModel(b.config.models[i%len(b.config.models)]))
+	}
+	return chatOpts
+}
+
+// promptText renders msgs as the prompt shown to a judge: the content of
+// a single message, or each message prefixed with its role.
+func promptText(msgs []ai.Message) string {
+	if len(msgs) == 1 {
+		return msgs[0].Content
+	}
+	var b strings.Builder
+	for i, m := range msgs {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString(string(m.Role))
+		b.WriteString(": ")
+		b.WriteString(m.Content)
+	}
+	return b.String()
+}
@@ -0,0 +1,204 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/judge"
+	"github.com/spetersoncode/gains/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleClient answers each chat with answer, called concurrently.
+type sampleClient struct {
+	answer func(msgs []ai.Message, opts *ai.Options) (string, error)
+}
+
+func (c sampleClient) Chat(ctx context.Context, msgs []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	content, err := c.answer(msgs, ai.ApplyOptions(opts...))
+	if err != nil {
+		return nil, err
+	}
+	return &ai.Response{Content: content, Usage: ai.Usage{InputTokens: 10, OutputTokens: 20}}, nil
+}
+
+func (c sampleClient) ChatStream(ctx context.Context, msgs []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+	return nil, errors.New("not implemented")
+}
+
+type bestOfNState struct {
+	Topic    string
+	Headline BestOfN
+}
+
+func headlinePrompt(s *bestOfNState) []ai.Message {
+	return []ai.Message{{Role: ai.RoleUser, Content: "Write a headline about " + s.Topic}}
+}
+
+func headlineField(s *bestOfNState) *BestOfN { return &s.Headline }
+
+// modelAnswer answers with the name of the requested model, if any.
+func modelAnswer(msgs []ai.Message, opts *ai.Options) (string, error) {
+	if opts.Model == nil {
+		return "sample", nil
+	}
+	return "from " + opts.Model.String(), nil
+}
+
+// lengthScorer scores a response by its length.
+func lengthScorer(ctx context.Context, prompt []ai.Message, response string) (float64, error) {
+	return float64(len(response)), nil
+}
+
+func TestBestOfN_ScorerPicksWinner(t *testing.T) {
+	step := NewBestOfNStep("headline", sampleClient{answer: modelAnswer}, headlinePrompt, headlineField,
+		WithSampleModels(model.GPT52, model.ClaudeSonnet45),
+		WithScorer(lengthScorer),
+	)
+
+	state := &bestOfNState{Topic: "tides"}
+	require.NoError(t, step.Run(context.Background(), state))
+
+	result := state.Headline
+	require.Len(t, result.Candidates, 2)
+	assert.Equal(t, "gpt-5.2", result.Candidates[0].Model)
+	assert.Equal(t, "from gpt-5.2", result.Candidates[0].Content)
+	assert.Equal(t, "claude-sonnet-4-5", result.Candidates[1].Model)
+	assert.Equal(t, 1, result.Winner.Index)
+	assert.Equal(t, "from claude-sonnet-4-5", result.Winner.Content)
+	assert.Equal(t, float64(len("from claude-sonnet-4-5")), result.Winner.Score)
+}
+
+func TestBestOfN_ModelsRotate(t *testing.T) {
+	step := NewBestOfNStep("headline", sampleClient{answer: modelAnswer}, headlinePrompt, headlineField,
+		WithSamples(3),
+		WithSampleModels(model.GPT52, model.ClaudeSonnet45),
+		WithScorer(lengthScorer),
+	)
+
+	state := &bestOfNState{}
+	require.NoError(t, step.Run(context.Background(), state))
+
+	require.Len(t, state.Headline.Candidates, 3)
+	assert.Equal(t, "gpt-5.2", state.Headline.Candidates[2].Model)
+}
+
+func TestBestOfN_TieGoesToLowestIndex(t *testing.T) {
+	step := NewBestOfNStep("headline", sampleClient{answer: modelAnswer}, headlinePrompt, headlineField,
+		WithScorer(func(ctx context.Context, prompt []ai.Message, response string) (float64, error) {
+			return 5, nil
+		}),
+	)
+
+	state := &bestOfNState{}
+	require.NoError(t, step.Run(context.Background(), state))
+
+	assert.Len(t, state.Headline.Candidates, DefaultSamples)
+	assert.Equal(t, 0, state.Headline.Winner.Index)
+}
+
+func TestBestOfN_Judge(t *testing.T) {
+	samples := sampleClient{answer: modelAnswer}
+	judgeClient := sampleClient{answer: func(msgs []ai.Message, opts *ai.Options) (string, error) {
+		if strings.Contains(msgs[len(msgs)-1].Content, "from claude-sonnet-4-5") {
+			return `{"reasoning": "Vivid.", "score": 9}`, nil
+		}
+		return `{"reasoning": "Bland.", "score": 4}`, nil
+	}}
+	step := NewBestOfNStep("headline", samples, headlinePrompt, headlineField,
+		WithSampleModels(model.GPT52, model.ClaudeSonnet45),
+		WithJudge(judge.New(judgeClient)),
+	)
+
+	state := &bestOfNState{Topic: "tides"}
+	require.NoError(t, step.Run(context.Background(), state))
+
+	winner := state.Headline.Winner
+	assert.Equal(t, 1, winner.Index)
+	assert.Equal(t, float64(9), winner.Score)
+	assert.Equal(t, "Vivid.", winner.Reasoning)
+	assert.Equal(t, "Bland.", state.Headline.Candidates[0].Reasoning)
+}
+
+func TestBestOfN_FailedCandidatesExcluded(t *testing.T) {
+	client := sampleClient{answer: func(msgs []ai.Message, opts *ai.Options) (string, error) {
+		if opts.Model == model.ClaudeSonnet45 {
+			return "", errors.New("overloaded")
+		}
+		return modelAnswer(msgs, opts)
+	}}
+	step := NewBestOfNStep("headline", client, headlinePrompt, headlineField,
+		WithSampleModels(model.ClaudeSonnet45, model.GPT52),
+		WithScorer(lengthScorer),
+	)
+
+	state := &bestOfNState{}
+	require.NoError(t, step.Run(context.Background(), state))
+
+	assert.Equal(t, 1, state.Headline.Winner.Index)
+	assert.Equal(t, "overloaded", state.Headline.Candidates[0].Error)
+}
+
+func TestBestOfN_NoCandidates(t *testing.T) {
+	step := NewBestOfNStep("headline", sampleClient{answer: modelAnswer}, headlinePrompt, headlineField,
+		WithScorer(func(ctx context.Context, prompt []ai.Message, response string) (float64, error) {
+			return 0, errors.New("unscorable")
+		}),
+	)
+
+	err := step.Run(context.Background(), &bestOfNState{})
+	require.ErrorIs(t, err, ErrNoCandidates)
+	assert.Contains(t, err.Error(), "unscorable")
+}
+
+func TestBestOfN_RunStream(t *testing.T) {
+	step := NewBestOfNStep("headline", sampleClient{answer: modelAnswer}, headlinePrompt, headlineField,
+		WithSampleModels(model.GPT52, model.ClaudeSonnet45),
+		WithScorer(lengthScorer),
+	)
+
+	state := &bestOfNState{}
+	var progress []int
+	var end *Event
+	for e := range step.RunStream(context.Background(), state, WithProgress()) {
+		switch e.Type {
+		case event.Progress:
+			progress = append(progress, e.Progress.Completed)
+		case event.StepEnd:
+			end = &e
+		case event.RunError:
+			t.Fatalf("unexpected error: %v", e.Error)
+		}
+	}
+
+	assert.Equal(t, []int{0, 1, 2}, progress)
+	require.NotNil(t, end)
+	assert.Equal(t, "from claude-sonnet-4-5", end.Response.Content)
+	assert.Equal(t, "candidate 1 won with score 22", end.Message)
+	assert.Equal(t, 1, state.Headline.Winner.Index)
+}
+
+func TestBestOfN_DryRun(t *testing.T) {
+	step := NewBestOfNStep("headline", sampleClient{answer: func(msgs []ai.Message, opts *ai.Options) (string, error) {
+		t.Fatal("dry run called the model")
+		return "", nil
+	}}, headlinePrompt, headlineField, WithSamples(2), WithScorer(lengthScorer))
+
+	var planned int
+	for e := range step.RunStream(context.Background(), &bestOfNState{}, WithDryRun()) {
+		if e.Type == event.ChatPlanned {
+			planned++
+		}
+	}
+	assert.Equal(t, 2, planned)
+}
+
+func TestBestOfN_Describe(t *testing.T) {
+	step := NewBestOfNStep("headline", sampleClient{answer: modelAnswer}, headlinePrompt, headlineField)
+	assert.Equal(t, KindBestOfN, step.DescribeStep().Kind)
+}
@@ -20,7 +20,18 @@ func NewChain[S any](name string, steps ...Step[S]) *Chain[S] {
 // Name returns the chain name.
 func (c *Chain[S]) Name() string { return c.name }
 
-// Run executes steps sequentially.
+// DescribeStep describes the chain and its steps for Workflow.Describe.
+func (c *Chain[S]) DescribeStep() StepInfo {
+	info := StepInfo{Kind: KindChain, Flow: FlowSequence}
+	for _, step := range c.steps {
+		info.Children = append(info.Children, ChildStep{Step: step})
+	}
+	return info
+}
+
+// Run executes steps sequentially. Under a workflow checkpointer, steps that
+// completed in an earlier attempt of the run are skipped, and progress is
+// saved after each step.
 func (c *Chain[S]) Run(ctx context.Context, state *S, opts ...Option) error {
 	options := ApplyOptions(opts...)
 
@@ -30,7 +41,13 @@ func (c *Chain[S]) Run(ctx context.Context, state *S, opts ...Option) error {
 		defer cancel()
 	}
 
-	for _, step := range c.steps {
+	cp := checkpointerFrom(ctx)
+	start := cp.startChain(c.name)
+
+	for i, step := range c.steps {
+		if i < start {
+			continue
+		}
 		if err := ctx.Err(); err != nil {
 			return &StepError{StepName: step.Name(), Err: err}
 		}
@@ -51,26 +68,33 @@ func (c *Chain[S]) Run(ctx context.Context, state *S, opts ...Option) error {
 					return &StepError{StepName: step.Name(), Err: handlerErr}
 				}
 				// Handler suppressed the error (returned nil)
-				if options.ContinueOnError {
-					continue
+				if !options.ContinueOnError {
+					// Error suppressed, stop successfully
+					cp.endChain(c.name)
+					return nil
 				}
-				// Error suppressed, stop successfully
-				return nil
+			} else {
+				// No handler - propagate original error
+				return &StepError{StepName: step.Name(), Err: err}
 			}
-			// No handler - propagate original error
-			return &StepError{StepName: step.Name(), Err: err}
+		}
+
+		if err := cp.stepDone(ctx, c.name, i+1, state); err != nil {
+			return err
 		}
 	}
 
+	cp.endChain(c.name)
 	return nil
 }
 
 // RunStream executes steps sequentially and emits events.
 func (c *Chain[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-chan Event {
-	ch := make(chan Event, 100)
+	ch := newEventChannel(opts)
 
 	go func() {
 		defer close(ch)
+		defer recoverStream(ch, c.name)
 		options := ApplyOptions(opts...)
 
 		if options.Timeout > 0 {
@@ -81,7 +105,17 @@ func (c *Chain[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-ch
 
 		event.Emit(ch, Event{Type: event.RunStart, StepName: c.name})
 
-		for _, step := range c.steps {
+		cp := checkpointerFrom(ctx)
+		start := cp.startChain(c.name)
+
+		for i, step := range c.steps {
+			if i < start {
+				event.Emit(ch, Event{Type: event.StepSkipped, StepName: step.Name(), Message: "completed in an earlier attempt"})
+				continue
+			}
+			if i == start {
+				emitProgress(ch, options, c.name, i, len(c.steps), "steps")
+			}
 			if err := ctx.Err(); err != nil {
 				event.Emit(ch, Event{Type: event.RunError, StepName: step.Name(), Error: err})
 				return
@@ -114,18 +148,26 @@ func (c *Chain[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-ch
 						return
 					}
 					// Handler suppressed the error
-					if options.ContinueOnError {
-						continue
+					if !options.ContinueOnError {
+						// Error suppressed, stop successfully
+						cp.endChain(c.name)
+						event.Emit(ch, Event{Type: event.RunEnd, StepName: c.name})
+						return
 					}
-					// Error suppressed, stop successfully
-					event.Emit(ch, Event{Type: event.RunEnd, StepName: c.name})
+				} else {
+					// No handler - error was already emitted by step, just stop
 					return
 				}
-				// No handler - error was already emitted by step, just stop
+			}
+
+			if err := cp.stepDone(ctx, c.name, i+1, state); err != nil {
+				event.Emit(ch, Event{Type: event.RunError, StepName: c.name, Error: err})
 				return
 			}
+			emitProgress(ch, options, c.name, i+1, len(c.steps), "steps")
 		}
 
+		cp.endChain(c.name)
 		event.Emit(ch, Event{
 			Type:     event.RunEnd,
 			StepName: c.name,
@@ -0,0 +1,247 @@
+package workflow
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spetersoncode/gains/internal/store"
+)
+
+// ErrCheckpointNotFound indicates no checkpoint exists for a run ID.
+var ErrCheckpointNotFound = errors.New("workflow: checkpoint not found")
+
+// checkpointRecord is the persisted form of a checkpoint.
+type checkpointRecord struct {
+	RunID     string          `json:"runId"`
+	Workflow  string          `json:"workflow"`
+	State     json.RawMessage `json:"state"`
+	Positions map[string]int  `json:"positions,omitempty"`
+	Done      bool            `json:"done,omitempty"`
+	Suspended string          `json:"suspended,omitempty"` // approval step awaited
+	Nonce     string          `json:"nonce,omitempty"`     // of the suspended step's resume token
+	// Decisions are the approval decisions recorded by ResumeWithApproval
+	// that the run has not yet applied.
+	Decisions map[string]Approval `json:"decisions,omitempty"`
+	UpdatedAt time.Time           `json:"updatedAt"`
+}
+
+// checkpointKey returns the adapter key for a run's checkpoint.
+func checkpointKey(runID string) string {
+	return "workflow/" + runID
+}
+
+// checkpointer records the progress of one workflow run. Chains report each
+// completed step to it, and it persists the state together with the number
+// of completed steps of every chain currently in progress.
+type checkpointer struct {
+	adapter  store.Adapter
+	runID    string
+	workflow string
+
+	mu        sync.Mutex
+	positions map[string]int      // completed steps per in-progress chain
+	resume    map[string]int      // positions to skip to, consumed on first use
+	suspended string              // approval step the run is waiting on
+	nonce     string              // of the suspended step's resume token
+	decisions map[string]Approval // approval decisions, consumed on first use
+}
+
+func newCheckpointer(adapter store.Adapter, workflow, runID string) *checkpointer {
+	return &checkpointer{
+		adapter:   adapter,
+		runID:     runID,
+		workflow:  workflow,
+		positions: make(map[string]int),
+		resume:    make(map[string]int),
+		decisions: make(map[string]Approval),
+	}
+}
+
+// loadCheckpoint reads the checkpoint for runID.
+func loadCheckpoint(ctx context.Context, adapter store.Adapter, runID string) (*checkpointRecord, error) {
+	raw, ok, err := adapter.Get(ctx, checkpointKey(runID))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrCheckpointNotFound, runID)
+	}
+	var rec checkpointRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return nil, &store.SerializationError{Key: checkpointKey(runID), Err: err}
+	}
+	return &rec, nil
+}
+
+// startChain returns how many leading steps of the named chain completed
+// in an earlier attempt of this run. Each position is only used once, so a
+// chain that runs again, such as inside a loop, starts from the beginning.
+// A nil checkpointer always returns 0.
+func (c *checkpointer) startChain(name string) int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := c.resume[name]
+	delete(c.resume, name)
+	if n > 0 {
+		c.positions[name] = n
+	}
+	return n
+}
+
+// stepDone records that the named chain completed n steps and persists state.
+func (c *checkpointer) stepDone(ctx context.Context, name string, n int, state any) error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	c.positions[name] = n
+	c.mu.Unlock()
+	if err := c.save(ctx, state, false); err != nil {
+		return fmt.Errorf("workflow: checkpoint %s: %w", c.runID, err)
+	}
+	return nil
+}
+
+// endChain forgets the position of a chain that finished all its steps.
+func (c *checkpointer) endChain(name string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.positions, name)
+}
+
+// save persists state and the current chain positions.
+func (c *checkpointer) save(ctx context.Context, state any, done bool) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return &store.SerializationError{Key: checkpointKey(c.runID), Err: err}
+	}
+
+	c.mu.Lock()
+	rec := checkpointRecord{
+		RunID:     c.runID,
+		Workflow:  c.workflow,
+		State:     data,
+		Positions: make(map[string]int, len(c.positions)),
+		Done:      done,
+		Suspended: c.suspended,
+		Nonce:     c.nonce,
+		UpdatedAt: time.Now(),
+	}
+	for k, v := range c.positions {
+		rec.Positions[k] = v
+	}
+	if len(c.decisions) > 0 {
+		rec.Decisions = make(map[string]Approval, len(c.decisions))
+		for k, v := range c.decisions {
+			rec.Decisions[k] = v
+		}
+	}
+	c.mu.Unlock()
+
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return &store.SerializationError{Key: checkpointKey(c.runID), Err: err}
+	}
+	return c.adapter.Set(ctx, checkpointKey(c.runID), raw)
+}
+
+// suspend records that the run waits on the named approval step and
+// persists state, returning the token to resume the run with.
+func (c *checkpointer) suspend(ctx context.Context, name string, state any) (string, error) {
+	token, nonce := newResumeToken(c.runID, name)
+	c.mu.Lock()
+	c.suspended, c.nonce = name, nonce
+	c.mu.Unlock()
+	if err := c.save(ctx, state, false); err != nil {
+		return "", fmt.Errorf("workflow: checkpoint %s: %w", c.runID, err)
+	}
+	return token, nil
+}
+
+// decision returns the decision for the named approval step given to
+// ResumeWithApproval, if any. Each decision is only used once, so a step
+// that runs again, such as inside a loop, asks again. A nil checkpointer
+// has no decisions.
+func (c *checkpointer) decision(name string) (Approval, bool) {
+	if c == nil {
+		return Approval{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	d, ok := c.decisions[name]
+	if ok {
+		delete(c.decisions, name)
+		c.suspended, c.nonce = "", ""
+	}
+	return d, ok
+}
+
+// checkpointLockTTL bounds how long a crashed process can hold a run's
+// checkpoint lock.
+const checkpointLockTTL = time.Minute
+
+// decide records decision for the approval step of the run that token was
+// issued for. It holds the run's lock, through adapter if it implements
+// store.Locker, from loading the checkpoint until the decision is saved, so
+// a concurrent decision for the same step sees it and fails with
+// ErrInvalidResumeToken. It returns the updated checkpoint.
+func decide(ctx context.Context, adapter store.Adapter, t resumeToken, decision Approval) (*checkpointRecord, error) {
+	unlock, err := store.Lock(ctx, adapter, checkpointKey(t.RunID)+"/lock", checkpointLockTTL)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	rec, err := loadCheckpoint(ctx, adapter, t.RunID)
+	if err != nil {
+		return nil, err
+	}
+	if rec.Done || rec.Suspended != t.Step {
+		return nil, fmt.Errorf("%w: step %q of run %s is not awaiting approval", ErrInvalidResumeToken, t.Step, t.RunID)
+	}
+	if subtle.ConstantTimeCompare([]byte(rec.Nonce), []byte(t.Nonce)) != 1 {
+		return nil, fmt.Errorf("%w: not issued for step %q of run %s", ErrInvalidResumeToken, t.Step, t.RunID)
+	}
+
+	rec.Suspended, rec.Nonce = "", ""
+	if rec.Decisions == nil {
+		rec.Decisions = make(map[string]Approval)
+	}
+	rec.Decisions[t.Step] = decision
+	rec.UpdatedAt = time.Now()
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return nil, &store.SerializationError{Key: checkpointKey(t.RunID), Err: err}
+	}
+	if err := adapter.Set(ctx, checkpointKey(t.RunID), raw); err != nil {
+		return nil, fmt.Errorf("workflow: checkpoint %s: %w", t.RunID, err)
+	}
+	return rec, nil
+}
+
+// checkpointKeyType is the context key for the active checkpointer.
+type checkpointKeyType struct{}
+
+// withCheckpointer returns a context carrying cp. A nil cp removes any
+// checkpointer, which Parallel uses so branches running on copies of the
+// state never overwrite the checkpoint.
+func withCheckpointer(ctx context.Context, cp *checkpointer) context.Context {
+	return context.WithValue(ctx, checkpointKeyType{}, cp)
+}
+
+// checkpointerFrom returns the checkpointer in ctx, or nil.
+func checkpointerFrom(ctx context.Context) *checkpointer {
+	cp, _ := ctx.Value(checkpointKeyType{}).(*checkpointer)
+	return cp
+}
@@ -0,0 +1,175 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkpointChain builds a three-step chain that fails at step2 while fail is set.
+func checkpointChain(calls *[3]atomic.Int32, fail *atomic.Bool) *Chain[testState] {
+	return NewChain("pipeline",
+		NewFuncStep("step1", func(ctx context.Context, s *testState) error {
+			calls[0].Add(1)
+			s.Step1 = "one"
+			return nil
+		}),
+		NewFuncStep("step2", func(ctx context.Context, s *testState) error {
+			calls[1].Add(1)
+			if fail.Load() {
+				return errors.New("transient failure")
+			}
+			s.Step2 = s.Step1 + "-two"
+			return nil
+		}),
+		NewFuncStep("step3", func(ctx context.Context, s *testState) error {
+			calls[2].Add(1)
+			s.Step3 = s.Step2 + "-three"
+			return nil
+		}),
+	)
+}
+
+func TestWorkflow_Resume(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	var calls [3]atomic.Int32
+	var fail atomic.Bool
+	fail.Store(true)
+
+	wf := New("test", checkpointChain(&calls, &fail), WithCheckpointer(adapter))
+
+	result, err := wf.Run(context.Background(), &testState{}, WithRunID("run-1"))
+	require.Error(t, err)
+	assert.Equal(t, "run-1", result.RunID)
+
+	fail.Store(false)
+	result, err = wf.Resume(context.Background(), "run-1")
+	require.NoError(t, err)
+	assert.Equal(t, TerminationComplete, result.Termination)
+	assert.Equal(t, "one-two-three", result.State.Step3)
+
+	assert.Equal(t, int32(1), calls[0].Load(), "completed step should not rerun")
+	assert.Equal(t, int32(2), calls[1].Load())
+	assert.Equal(t, int32(1), calls[2].Load())
+
+	t.Run("finished run is not rerun", func(t *testing.T) {
+		result, err := wf.Resume(context.Background(), "run-1")
+		require.NoError(t, err)
+		assert.Equal(t, "one-two-three", result.State.Step3)
+		assert.Equal(t, int32(1), calls[2].Load())
+	})
+}
+
+func TestWorkflow_Run_GeneratesRunID(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	var calls [3]atomic.Int32
+	var fail atomic.Bool
+
+	wf := New("test", checkpointChain(&calls, &fail))
+	result, err := wf.Run(context.Background(), &testState{}, WithCheckpointer(adapter))
+	require.NoError(t, err)
+	require.NotEmpty(t, result.RunID)
+
+	_, ok, err := adapter.Get(context.Background(), checkpointKey(result.RunID))
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestWorkflow_Resume_Errors(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	var calls [3]atomic.Int32
+	var fail atomic.Bool
+
+	t.Run("requires a checkpointer", func(t *testing.T) {
+		wf := New("test", checkpointChain(&calls, &fail))
+		_, err := wf.Resume(context.Background(), "run-1")
+		assert.ErrorIs(t, err, ErrNoCheckpointer)
+	})
+
+	t.Run("unknown run", func(t *testing.T) {
+		wf := New("test", checkpointChain(&calls, &fail), WithCheckpointer(adapter))
+		_, err := wf.Resume(context.Background(), "missing")
+		assert.ErrorIs(t, err, ErrCheckpointNotFound)
+	})
+
+	t.Run("run of another workflow", func(t *testing.T) {
+		wf := New("first", checkpointChain(&calls, &fail), WithCheckpointer(adapter))
+		_, err := wf.Run(context.Background(), &testState{}, WithRunID("run-2"))
+		require.NoError(t, err)
+
+		other := New("second", checkpointChain(&calls, &fail), WithCheckpointer(adapter))
+		_, err = other.Resume(context.Background(), "run-2")
+		assert.Error(t, err)
+	})
+}
+
+func TestWorkflow_ResumeStream(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	var calls [3]atomic.Int32
+	var fail atomic.Bool
+	fail.Store(true)
+
+	wf := New("test", checkpointChain(&calls, &fail), WithCheckpointer(adapter), WithRunID("run-1"))
+
+	var sawError bool
+	for ev := range wf.RunStream(context.Background(), &testState{}) {
+		if ev.Type == event.RunError {
+			sawError = true
+		}
+	}
+	require.True(t, sawError)
+
+	fail.Store(false)
+	result, err := wf.Resume(context.Background(), "run-1")
+	require.NoError(t, err)
+	assert.Equal(t, "one-two-three", result.State.Step3)
+	assert.Equal(t, int32(1), calls[0].Load())
+}
+
+func TestChain_RunStream_SkipsCompletedSteps(t *testing.T) {
+	var calls [3]atomic.Int32
+	var fail atomic.Bool
+	cp := newCheckpointer(store.NewMemoryAdapter(), "test", "run-1")
+	cp.resume["pipeline"] = 2
+
+	ctx := withCheckpointer(context.Background(), cp)
+	state := &testState{Step2: "restored"}
+	var skipped []string
+	for ev := range checkpointChain(&calls, &fail).RunStream(ctx, state) {
+		if ev.Type == event.StepSkipped {
+			skipped = append(skipped, ev.StepName)
+		}
+	}
+
+	assert.Equal(t, []string{"step1", "step2"}, skipped)
+	assert.Equal(t, "restored-three", state.Step3)
+	assert.Equal(t, int32(0), calls[0].Load())
+}
+
+func TestParallel_BranchesDoNotCheckpoint(t *testing.T) {
+	adapter := store.NewMemoryAdapter()
+	var calls [3]atomic.Int32
+	var fail atomic.Bool
+
+	branch := checkpointChain(&calls, &fail)
+	parallel := NewParallel("fanout", []Step[testState]{branch}, func(state *testState, branches map[string]*testState, errs map[string]error) error {
+		state.Result = branches["pipeline"].Step3
+		return nil
+	})
+
+	wf := New("test", parallel, WithCheckpointer(adapter))
+	result, err := wf.Run(context.Background(), &testState{})
+	require.NoError(t, err)
+
+	rec, err := loadCheckpoint(context.Background(), adapter, result.RunID)
+	require.NoError(t, err)
+	assert.True(t, rec.Done)
+	assert.Empty(t, rec.Positions)
+	assert.Equal(t, "one-two-three", result.State.Result)
+}
@@ -5,7 +5,7 @@
 //   - Parallel: Concurrent execution with branch state isolation and aggregation
 //   - Router: Conditional branching based on predicates or LLM classification
 //   - Loop: Iterative execution until a condition is met
-//   - Merge: Conditional joining of multiple steps with fan-in aggregation
+//   - Merge: Joining parallel branches when all, the first, or a quorum succeed
 //
 // All workflow types implement the Step[S] interface, enabling arbitrary nesting
 // and composition. The generic type parameter S is your user-defined state struct.
@@ -104,6 +104,29 @@
 //	    },
 //	)
 //
+// When a branch fails, its siblings are cancelled through their context and
+// only the failure is reported; pass WithCancelOnError(false) to let every
+// branch finish, or WithContinueOnError(true) to hand failures to the
+// aggregator. WithMaxConcurrency bounds how many branches of each parallel
+// step run at once; WithRunConcurrency bounds the branches, agent tool
+// calls, and sub-agents of the whole run together. A panicking branch fails
+// with a *StepError wrapping a *PanicError rather than crashing the process.
+//
+// By default a parallel step waits for every branch. Pass a join policy to
+// NewParallel to finish early: WithFirstSuccess takes the first branch to
+// succeed, such as racing several models for the fastest good answer, and
+// WithQuorum(n) takes the first n. The branches still running are then
+// cancelled, failures are tolerated while the quorum can be met, and only
+// the contributing branches reach the aggregator; in a stream, a
+// RouteSelected event names each of them. A quorum that can no longer be
+// met fails the step with ErrQuorumNotMet:
+//
+//	race := workflow.NewParallel("answer",
+//	    []workflow.Step[QAState]{largeModelStep, fastModelStep},
+//	    takeAnswer,
+//	    workflow.WithFirstSuccess(),
+//	)
+//
 // # Conditional Routing
 //
 // Route based on state conditions:
@@ -188,6 +211,68 @@
 //	    workflow.WithMaxIterations(5),
 //	)
 //
+// # Retries and Fallbacks
+//
+// NewRetryStep retries a failing step with exponential backoff. Transient
+// errors are retried by default; WithRetryIf chooses which errors to retry
+// and WithRetryConfig sets the attempts and delays. NewFallbackStep tries
+// alternate steps in turn when one fails, such as a cheaper model:
+//
+//	summarize := workflow.NewFallbackStep("summarize",
+//	    workflow.NewRetryStep("retry-large", largeStep,
+//	        workflow.WithRetryConfig(gains.NewRetryConfig(3, time.Second, 10*time.Second, 2, 0.1)),
+//	    ),
+//	    smallStep,
+//	)
+//
+// # Best-of-N Sampling
+//
+// NewBestOfNStep samples several completions of one prompt concurrently,
+// optionally across models, scores each with a judge.Judge or a Scorer,
+// and stores the winner with every candidate and score in a BestOfN field:
+//
+//	headline := workflow.NewBestOfNStep("headline", client, headlinePrompt,
+//	    func(s *PostState) *workflow.BestOfN { return &s.Headline },
+//	    workflow.WithSampleModels(model.ClaudeSonnet45, model.GPT52),
+//	    workflow.WithJudgeCriteria("Accurate, specific, and under ten words."),
+//	)
+//
+// # Prompt Templates
+//
+// NewPromptTemplate renders prompt messages from state with text/template,
+// delimiting untrusted values against prompt injection. Fields tagged
+// prompt:"untrusted" render inside <untrusted> blocks they cannot break
+// out of, and the system message tells the model to treat them as data:
+//
+//	type SupportState struct {
+//	    Message string `prompt:"untrusted"`
+//	    Reply   string
+//	}
+//
+//	tmpl, err := workflow.NewPromptTemplate[SupportState](
+//	    "You are a support agent.",
+//	    "Reply to the customer:\n{{.Message}}",
+//	    workflow.WithStripControlTokens(),
+//	)
+//	step := workflow.NewTemplatePromptStep("reply", client, tmpl, nil,
+//	    func(s *SupportState) *string { return &s.Reply },
+//	)
+//
+// # Retrieval
+//
+// NewRetrievalStep embeds a query from the state, retrieves the most
+// similar documents from a vectorstore.Store, and stores them for a later
+// prompt step:
+//
+//	retrieve := workflow.NewRetrievalStep("retrieve", c, store,
+//	    func(s *QAState) string { return s.Question },
+//	    func(s *QAState, matches []vectorstore.Match) { s.Sources = matches },
+//	    workflow.WithTopK(5),
+//	)
+//	answer := workflow.NewPromptStep("answer", c, answerPrompt, nil,
+//	    func(s *QAState) *string { return &s.Answer })
+//	wf := workflow.New("qa", workflow.NewChain("rag", retrieve, answer))
+//
 // # Streaming Events
 //
 // Monitor workflow progress in real-time:
@@ -211,6 +296,114 @@
 //	// Access final results from state
 //	fmt.Println(state.Summary)
 //
+// WithProgress adds Progress events estimating how far chains and loops
+// have come, for progress bars:
+//
+//	case event.Progress:
+//	    fmt.Printf("%s: %.0f%%\n", e.StepName, e.Progress.Fraction()*100)
+//
+// To log runs instead of consuming events, pass WithLogger with a
+// *slog.Logger; WithLogLevels sets the level of each kind of record.
+//
+// # Checkpointing
+//
+// With WithCheckpointer, a workflow saves its state (which must be JSON
+// serializable) after every completed chain step, keyed by run ID. An
+// interrupted or failed run continues from where it stopped with Resume:
+//
+//	wf := workflow.New("pipeline", chain, workflow.WithCheckpointer(adapter))
+//	result, err := wf.Run(ctx, state)
+//	if err != nil {
+//	    // Fix the cause, then skip the steps that already completed
+//	    result, err = wf.Resume(ctx, result.RunID)
+//	}
+//
+// Branches of a Parallel step run on copies of the state and are not
+// checkpointed individually; a resumed run reruns the whole Parallel step.
+//
+// # Human Approval
+//
+// NewApprovalStep pauses a workflow for a human decision and runs an
+// approve or reject branch. It emits an ActivityUserInput event for the
+// frontend, then either blocks until the decision arrives through an
+// agent.UserInputBroker, or suspends a checkpointed run until it is
+// continued with ResumeWithApproval:
+//
+//	review := workflow.NewApprovalStep("review",
+//	    func(s *PostState) string { return "Publish this post?\n\n" + s.Draft },
+//	    publishStep, reviseStep,
+//	)
+//	wf := workflow.New("post", workflow.NewChain("post", draftStep, review),
+//	    workflow.WithCheckpointer(adapter))
+//	result, err := wf.Run(ctx, state)
+//	if errors.Is(err, workflow.ErrSuspended) {
+//	    // ... later, once the reviewer decides
+//	    result, err = wf.ResumeWithApproval(ctx, result.ResumeToken,
+//	        workflow.Approval{Approved: true})
+//	}
+//
+// Resume tokens carry a random nonce recorded in the checkpoint, so they
+// cannot be built from a run ID, and each is accepted once: the decision
+// is saved under a lock on the run before the run continues, and a second
+// decision fails with ErrInvalidResumeToken. Runs resumed by several
+// processes need a checkpointer implementing store.Locker, such as
+// store/redis.
+//
+// # Artifacts
+//
+// WithArtifactStore persists the outputs of a run so other services can
+// fetch them by run ID. State fields tagged with `artifact:"name"` are
+// stored when the run completes, each as a new version of its name:
+//
+//	wf := workflow.New("report", chain, workflow.WithArtifactStore(adapter))
+//	result, err := wf.Run(ctx, state)
+//	// ... elsewhere
+//	a, err := workflow.LoadArtifact(ctx, adapter, result.RunID, "report")
+//	var report string
+//	err = a.Decode(&report)
+//
+// # Run History
+//
+// WithRunHistory records every run for dashboards and audits: its status,
+// a hash of the input state, duration, token usage, and error. Records are
+// written when a run starts and when it ends, so runs still in progress,
+// or whose process died, show as running. Query them with GetRun and
+// ListRuns:
+//
+//	wf := workflow.New("ingest", chain, workflow.WithRunHistory(adapter))
+//	// ... elsewhere
+//	failed, err := workflow.ListRuns(ctx, adapter, workflow.RunFilter{
+//	    Workflow: "ingest",
+//	    Status:   []workflow.RunStatus{workflow.RunStatusError},
+//	    Since:    time.Now().Add(-24 * time.Hour),
+//	})
+//
+// # Dry Runs
+//
+// WithDryRun walks a workflow without calling models or executing tools,
+// to review what it would do. Prompt steps emit ChatPlanned events with the
+// rendered messages and tool definitions instead of calling the model, and
+// classifier routers walk every route:
+//
+//	for e := range wf.RunStream(ctx, state, workflow.WithDryRun()) {
+//	    if e.Type == event.ChatPlanned {
+//	        fmt.Printf("%s would send %d messages\n", e.StepName, len(e.Messages))
+//	    }
+//	}
+//
+// # Graph Export
+//
+// Describe walks a workflow's step tree and returns its graph: a node per
+// step with its kind, and edges between the steps that do the work. Render
+// it for documentation or review with Mermaid or DOT:
+//
+//	fmt.Println(wf.Describe().Mermaid())
+//
+// Chains, parallels, loops, and retries render as subgraphs around their
+// steps; routers and fallbacks render as decision nodes with an edge per
+// route or alternate. Custom composite steps implement StepDescriber to
+// appear with their children.
+//
 // # Composability
 //
 // Workflows can be nested since all patterns implement Step[S]:
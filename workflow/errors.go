@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/internal/group"
 )
 
 var (
@@ -21,6 +24,22 @@ var (
 
 	// ErrMaxIterationsExceeded indicates a loop reached its iteration limit.
 	ErrMaxIterationsExceeded = errors.New("workflow: maximum loop iterations exceeded")
+
+	// ErrSuspended indicates an approval step suspended the run to wait for
+	// a decision. Continue it with Workflow.ResumeWithApproval.
+	ErrSuspended = errors.New("workflow: run suspended for approval")
+
+	// ErrApprovalRejected indicates an approval step without a reject
+	// branch was rejected.
+	ErrApprovalRejected = errors.New("workflow: approval rejected")
+
+	// ErrNoApprover indicates an approval step ran with neither an
+	// approval broker nor a checkpointer to suspend the run with.
+	ErrNoApprover = errors.New("workflow: approval step has no approver or checkpointer")
+
+	// ErrInvalidResumeToken indicates a resume token is malformed or its
+	// step is not awaiting a decision.
+	ErrInvalidResumeToken = errors.New("workflow: invalid resume token")
 )
 
 // StepError wraps errors from step execution.
@@ -39,6 +58,31 @@ func (e *StepError) Unwrap() error {
 	return e.Err
 }
 
+// PanicError reports a panic recovered from a step running in its own
+// goroutine. It is returned or emitted wrapped in a *StepError; use
+// errors.As to inspect the panic value and stack trace.
+type PanicError = group.PanicError
+
+// callStep runs fn, converting a panic into a *StepError that wraps a
+// *PanicError.
+func callStep(name string, fn func() error) error {
+	err := group.Call(fn)
+	if pe, ok := err.(*PanicError); ok {
+		return &StepError{StepName: name, Err: pe}
+	}
+	return err
+}
+
+// recoverStream reports a panic in a RunStream goroutine as a RunError event
+// carrying a *StepError that wraps a *PanicError. Defer it after closing ch
+// so the event is sent before the channel closes.
+func recoverStream(ch chan<- Event, stepName string) {
+	if r := recover(); r != nil {
+		err := &StepError{StepName: stepName, Err: group.Recovered(r)}
+		event.Emit(ch, Event{Type: event.RunError, StepName: stepName, Error: err})
+	}
+}
+
 // ParallelError wraps errors from parallel execution.
 type ParallelError struct {
 	Errors map[string]error
@@ -11,10 +11,23 @@ import (
 //   - event.MessageStart, event.MessageDelta, event.MessageEnd
 //   - event.ToolCallStart, event.ToolCallArgs, event.ToolCallEnd, event.ToolCallResult
 //   - event.ParallelStart, event.ParallelEnd
-//   - event.RouteSelected, event.LoopIteration
+//   - event.RouteSelected, event.LoopIteration, event.Progress
 //   - event.StateSnapshot, event.StateDelta
 type Event = event.Event
 
+// emitProgress emits a Progress event for the named step if options enable
+// them.
+func emitProgress(ch chan<- Event, options *Options, name string, completed, total int, unit string) {
+	if !options.Progress {
+		return
+	}
+	event.Emit(ch, Event{
+		Type:     event.Progress,
+		StepName: name,
+		Progress: &event.ProgressInfo{Completed: completed, Total: total, Unit: unit},
+	})
+}
+
 // StateEmitter allows workflow steps to emit state change notifications
 // for AG-UI shared state synchronization. Steps can send full snapshots
 // or incremental patches to keep the frontend in sync.
@@ -88,6 +101,11 @@ const (
 
 	// TerminationError indicates an error occurred.
 	TerminationError TerminationReason = "error"
+
+	// TerminationSuspended indicates an approval step is waiting for a
+	// decision. Continue the run with Workflow.ResumeWithApproval and
+	// Result.ResumeToken.
+	TerminationSuspended TerminationReason = "suspended"
 )
 
 // Result represents the final outcome of workflow execution.
@@ -96,6 +114,10 @@ type Result[S any] struct {
 	// WorkflowName identifies the workflow.
 	WorkflowName string
 
+	// RunID identifies a checkpointed run or the run's artifacts; empty when
+	// neither checkpointing nor artifacts are enabled.
+	RunID string
+
 	// State contains the final state after execution.
 	// All step outputs are stored in state fields via setters.
 	State *S
@@ -105,4 +127,8 @@ type Result[S any] struct {
 
 	// Error contains any error that caused termination.
 	Error error
+
+	// ResumeToken identifies the approval step awaiting a decision when
+	// Termination is TerminationSuspended.
+	ResumeToken string
 }
@@ -0,0 +1,122 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spetersoncode/gains/event"
+)
+
+// FallbackStep runs a primary step and, when it fails, each alternate in
+// turn until one succeeds, such as a cheaper or more available model.
+type FallbackStep[S any] struct {
+	name  string
+	steps []Step[S]
+}
+
+// NewFallbackStep creates a step that tries primary, then each fallback in
+// order, stopping at the first that succeeds. If every step fails, the
+// last error is returned. A cancelled context stops the fallbacks.
+//
+// Steps run on the same state, so a failed step's partial changes are seen
+// by the next. Wrap a step in NewRetryStep to retry it before falling back.
+//
+// Example:
+//
+//	step := NewFallbackStep("summarize",
+//	    NewPromptStep("large", client, prompt, nil, output, ai.WithModel(largeModel)),
+//	    NewPromptStep("small", client, prompt, nil, output, ai.WithModel(smallModel)),
+//	)
+func NewFallbackStep[S any](name string, primary Step[S], fallbacks ...Step[S]) *FallbackStep[S] {
+	return &FallbackStep[S]{
+		name:  name,
+		steps: append([]Step[S]{primary}, fallbacks...),
+	}
+}
+
+// Name returns the step name.
+func (f *FallbackStep[S]) Name() string { return f.name }
+
+// DescribeStep describes the fallback step and its alternates for
+// Workflow.Describe.
+func (f *FallbackStep[S]) DescribeStep() StepInfo {
+	info := StepInfo{Kind: KindFallback, Flow: FlowBranch}
+	for i, step := range f.steps {
+		label := "primary"
+		if i > 0 {
+			label = fmt.Sprintf("fallback %d", i)
+		}
+		info.Children = append(info.Children, ChildStep{Step: step, Label: label})
+	}
+	return info
+}
+
+// Run executes the steps in order until one succeeds.
+func (f *FallbackStep[S]) Run(ctx context.Context, state *S, opts ...Option) error {
+	var err error
+	for _, step := range f.steps {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			if err != nil {
+				return err
+			}
+			return ctxErr
+		}
+		err = callStep(step.Name(), func() error { return step.Run(ctx, state, opts...) })
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// RunStream executes the steps in order until one succeeds and emits
+// events. The events of each step are forwarded, except the error of a
+// step that is fallen back from, which is reported as a RetryFailed event.
+func (f *FallbackStep[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-chan Event {
+	ch := newEventChannel(opts)
+
+	go func() {
+		defer close(ch)
+		defer recoverStream(ch, f.name)
+		event.Emit(ch, Event{Type: event.StepStart, StepName: f.name})
+
+		var err error
+		for i, step := range f.steps {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				if err == nil {
+					err = ctxErr
+				}
+				break
+			}
+
+			err = nil
+			last := i == len(f.steps)-1
+			for ev := range step.RunStream(ctx, state, opts...) {
+				if ev.Type == event.RunError {
+					err = ev.Error
+					if !last {
+						continue
+					}
+				}
+				ch <- ev
+			}
+			if err == nil {
+				event.Emit(ch, Event{Type: event.StepEnd, StepName: f.name})
+				return
+			}
+			if last {
+				return
+			}
+			event.Emit(ch, Event{
+				Type:     event.RetryFailed,
+				StepName: f.name,
+				Error:    err,
+				Attempt:  i + 1,
+				Message:  "falling back to " + f.steps[i+1].Name(),
+			})
+		}
+		event.Emit(ch, Event{Type: event.RunError, StepName: f.name, Error: err})
+	}()
+
+	return ch
+}
@@ -0,0 +1,144 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spetersoncode/gains/event"
+)
+
+type fallbackState struct {
+	Tried  []string
+	Result string
+}
+
+func fallbackFunc(name string, err error) Step[fallbackState] {
+	return NewFuncStep[fallbackState](name, func(ctx context.Context, s *fallbackState) error {
+		s.Tried = append(s.Tried, name)
+		if err != nil {
+			return err
+		}
+		s.Result = name
+		return nil
+	})
+}
+
+func TestFallbackStep_Run_PrimarySucceeds(t *testing.T) {
+	step := NewFallbackStep("fallback", fallbackFunc("primary", nil), fallbackFunc("backup", nil))
+	state := &fallbackState{}
+
+	if err := step.Run(context.Background(), state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(state.Tried) != 1 || state.Result != "primary" {
+		t.Errorf("expected only primary to run, got %v", state.Tried)
+	}
+}
+
+func TestFallbackStep_Run_FallsBack(t *testing.T) {
+	step := NewFallbackStep("fallback",
+		fallbackFunc("primary", errors.New("down")),
+		fallbackFunc("second", errors.New("also down")),
+		fallbackFunc("third", nil),
+	)
+	state := &fallbackState{}
+
+	if err := step.Run(context.Background(), state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(state.Tried) != 3 || state.Result != "third" {
+		t.Errorf("expected all three to run, got %v", state.Tried)
+	}
+}
+
+func TestFallbackStep_Run_AllFail(t *testing.T) {
+	errLast := errors.New("last")
+	step := NewFallbackStep("fallback",
+		fallbackFunc("primary", errors.New("first")),
+		fallbackFunc("backup", errLast),
+	)
+
+	err := step.Run(context.Background(), &fallbackState{})
+	if !errors.Is(err, errLast) {
+		t.Errorf("expected last error, got %v", err)
+	}
+}
+
+func TestFallbackStep_Run_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	primary := NewFuncStep[fallbackState]("primary", func(ctx context.Context, s *fallbackState) error {
+		cancel()
+		return ctx.Err()
+	})
+	step := NewFallbackStep("fallback", primary, fallbackFunc("backup", nil))
+	state := &fallbackState{}
+
+	if err := step.Run(ctx, state); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if len(state.Tried) != 0 {
+		t.Errorf("expected no fallback after cancellation, got %v", state.Tried)
+	}
+}
+
+func TestFallbackStep_RunStream(t *testing.T) {
+	step := NewFallbackStep("fallback",
+		fallbackFunc("primary", errors.New("down")),
+		fallbackFunc("backup", nil),
+	)
+
+	var fallbacks, runErrors int
+	var last Event
+	for ev := range step.RunStream(context.Background(), &fallbackState{}) {
+		switch ev.Type {
+		case event.RetryFailed:
+			fallbacks++
+			if ev.Message != "falling back to backup" || ev.Attempt != 1 {
+				t.Errorf("unexpected fallback event: %+v", ev)
+			}
+		case event.RunError:
+			runErrors++
+		}
+		last = ev
+	}
+
+	if fallbacks != 1 {
+		t.Errorf("expected 1 fallback event, got %d", fallbacks)
+	}
+	if runErrors != 0 {
+		t.Errorf("expected the primary's error not forwarded, got %d", runErrors)
+	}
+	if last.Type != event.StepEnd || last.StepName != "fallback" {
+		t.Errorf("expected StepEnd for fallback last, got %s %s", last.Type, last.StepName)
+	}
+}
+
+func TestFallbackStep_RunStream_AllFail(t *testing.T) {
+	step := NewFallbackStep("fallback",
+		fallbackFunc("primary", errors.New("down")),
+		fallbackFunc("backup", errors.New("also down")),
+	)
+
+	var runErrors []Event
+	for ev := range step.RunStream(context.Background(), &fallbackState{}) {
+		if ev.Type == event.RunError {
+			runErrors = append(runErrors, ev)
+		}
+	}
+	if len(runErrors) != 1 || runErrors[0].Error == nil {
+		t.Errorf("expected the last step's error, got %v", runErrors)
+	}
+}
+
+func TestFallbackStep_Describe(t *testing.T) {
+	step := NewFallbackStep("fallback", fallbackFunc("primary", nil), fallbackFunc("backup", nil))
+	g := New("wf", step).Describe()
+
+	if len(g.Nodes) != 3 || g.Nodes[0].Kind != KindFallback {
+		t.Fatalf("unexpected nodes: %+v", g.Nodes)
+	}
+	if len(g.Edges) != 2 || g.Edges[0].Label != "primary" || g.Edges[1].Label != "fallback 1" {
+		t.Errorf("unexpected edges: %+v", g.Edges)
+	}
+}
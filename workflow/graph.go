@@ -0,0 +1,286 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StepKind classifies a step in a workflow graph.
+type StepKind string
+
+// Step kinds of the steps in this package. Steps that do not implement
+// StepDescriber are KindStep.
+const (
+	KindStep             StepKind = "step"
+	KindFunc             StepKind = "func"
+	KindPrompt           StepKind = "prompt"
+	KindAgent            StepKind = "agent"
+	KindTool             StepKind = "tool"
+	KindRetrieval        StepKind = "retrieval"
+	KindChain            StepKind = "chain"
+	KindParallel         StepKind = "parallel"
+	KindRouter           StepKind = "router"
+	KindClassifierRouter StepKind = "classifier_router"
+	KindLoop             StepKind = "loop"
+	KindRetry            StepKind = "retry"
+	KindFallback         StepKind = "fallback"
+	KindApproval         StepKind = "approval"
+	KindBestOfN          StepKind = "best_of_n"
+)
+
+// Flow is how a step runs the steps it contains.
+type Flow string
+
+const (
+	// FlowSequence runs the children one after another. It is the flow of
+	// steps that wrap a single child.
+	FlowSequence Flow = "sequence"
+	// FlowParallel runs the children concurrently.
+	FlowParallel Flow = "parallel"
+	// FlowBranch runs one of the children, chosen by the step.
+	FlowBranch Flow = "branch"
+	// FlowLoop runs the children in sequence, repeatedly.
+	FlowLoop Flow = "loop"
+)
+
+// NamedStep is any step, whatever its state type.
+type NamedStep interface {
+	Name() string
+}
+
+// ChildStep is a step contained in another.
+type ChildStep struct {
+	// Step is the contained step.
+	Step NamedStep
+	// Label describes when the step runs, such as a route name.
+	Label string
+}
+
+// StepInfo is how a step describes itself in a workflow graph.
+type StepInfo struct {
+	Kind StepKind
+	// Detail is shown next to the step's name, such as a loop's limit.
+	Detail string
+	// Flow is how Children run; empty means FlowSequence.
+	Flow     Flow
+	Children []ChildStep
+}
+
+// StepDescriber is implemented by steps that describe themselves to
+// Workflow.Describe. Composite steps must implement it for their children
+// to appear in the graph.
+type StepDescriber interface {
+	DescribeStep() StepInfo
+}
+
+// Node is a step in a workflow graph.
+type Node struct {
+	// ID identifies the node within the graph.
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Kind   StepKind `json:"kind"`
+	Detail string   `json:"detail,omitempty"`
+	// Parent is the ID of the step containing this one, if any.
+	Parent string `json:"parent,omitempty"`
+	// Group reports whether the node contains steps that run inside it, as
+	// chains, parallels, loops, and retries do. Groups render as subgraphs
+	// around their children; branching steps render as decision nodes.
+	Group bool `json:"group,omitempty"`
+}
+
+// Edge is a transition from one step to the next.
+type Edge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Label string `json:"label,omitempty"`
+}
+
+// Graph is the structure of a workflow: its steps, nested as they are
+// composed, and the transitions between the steps that do the work.
+// Nodes are ordered depth first, as the steps are composed.
+type Graph struct {
+	Name  string `json:"name"`
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Describe walks the workflow's step tree and returns its graph, for
+// documenting and reviewing a pipeline. Render it with Graph.Mermaid or
+// Graph.DOT, or encode it as JSON.
+func (w *Workflow[S]) Describe() *Graph {
+	g := &Graph{Name: w.name, Nodes: []Node{}, Edges: []Edge{}}
+	g.walk(w.root, "")
+	return g
+}
+
+// walk adds step and its children to the graph and returns the IDs of the
+// nodes the step starts and ends at.
+func (g *Graph) walk(step NamedStep, parent string) (entries, exits []string) {
+	info := StepInfo{Kind: KindStep}
+	if d, ok := step.(StepDescriber); ok {
+		info = d.DescribeStep()
+	}
+	id := fmt.Sprintf("n%d", len(g.Nodes)+1)
+	index := len(g.Nodes)
+	g.Nodes = append(g.Nodes, Node{ID: id, Name: step.Name(), Kind: info.Kind, Detail: info.Detail, Parent: parent})
+
+	var children []ChildStep
+	for _, c := range info.Children {
+		if c.Step != nil {
+			children = append(children, c)
+		}
+	}
+	if len(children) == 0 {
+		return []string{id}, []string{id}
+	}
+
+	if info.Flow == FlowBranch {
+		for _, c := range children {
+			in, out := g.walk(c.Step, id)
+			for _, to := range in {
+				g.Edges = append(g.Edges, Edge{From: id, To: to, Label: c.Label})
+			}
+			exits = append(exits, out...)
+		}
+		return []string{id}, exits
+	}
+
+	g.Nodes[index].Group = true
+	for i, c := range children {
+		in, out := g.walk(c.Step, id)
+		switch {
+		case i == 0 || info.Flow == FlowParallel:
+			entries = append(entries, in...)
+		default:
+			g.connect(exits, in, c.Label)
+			exits = nil
+		}
+		exits = append(exits, out...)
+	}
+	if info.Flow == FlowLoop {
+		g.connect(exits, entries, "repeat")
+	}
+	return entries, exits
+}
+
+// connect adds an edge from each of from to each of to.
+func (g *Graph) connect(from, to []string, label string) {
+	for _, f := range from {
+		for _, t := range to {
+			g.Edges = append(g.Edges, Edge{From: f, To: t, Label: label})
+		}
+	}
+}
+
+// title is how a node is labelled: its name, with its kind and detail.
+func (n Node) title() string {
+	var b strings.Builder
+	b.WriteString(n.Name)
+	if n.Kind != KindStep && n.Kind != KindFunc {
+		fmt.Fprintf(&b, " (%s)", n.Kind)
+	}
+	if n.Detail != "" {
+		fmt.Fprintf(&b, " [%s]", n.Detail)
+	}
+	return b.String()
+}
+
+// layout returns the nodes of each group, with the top-level nodes under
+// "", and the IDs of the branching steps. Nodes inside a branching step
+// belong to the step's group.
+func (g *Graph) layout() (groups map[string][]Node, branches map[string]bool) {
+	byID := make(map[string]Node, len(g.Nodes))
+	for _, n := range g.Nodes {
+		byID[n.ID] = n
+	}
+	groups = make(map[string][]Node)
+	branches = make(map[string]bool)
+	for _, n := range g.Nodes {
+		parent := n.Parent
+		if parent != "" && !byID[parent].Group {
+			branches[parent] = true
+		}
+		for parent != "" && !byID[parent].Group {
+			parent = byID[parent].Parent
+		}
+		groups[parent] = append(groups[parent], n)
+	}
+	return groups, branches
+}
+
+// Mermaid renders the graph as a Mermaid flowchart. Groups become
+// subgraphs and branching steps decision nodes.
+func (g *Graph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	groups, branches := g.layout()
+	var write func(parent string, indent string)
+	write = func(parent, indent string) {
+		for _, n := range groups[parent] {
+			switch {
+			case n.Group:
+				fmt.Fprintf(&b, "%ssubgraph %s[\"%s\"]\n", indent, n.ID, mermaidText(n.title()))
+				write(n.ID, indent+"    ")
+				fmt.Fprintf(&b, "%send\n", indent)
+			case branches[n.ID]:
+				fmt.Fprintf(&b, "%s%s{\"%s\"}\n", indent, n.ID, mermaidText(n.title()))
+			default:
+				fmt.Fprintf(&b, "%s%s[\"%s\"]\n", indent, n.ID, mermaidText(n.title()))
+			}
+		}
+	}
+	write("", "    ")
+	for _, e := range g.Edges {
+		if e.Label != "" {
+			fmt.Fprintf(&b, "    %s -->|\"%s\"| %s\n", e.From, mermaidText(e.Label), e.To)
+		} else {
+			fmt.Fprintf(&b, "    %s --> %s\n", e.From, e.To)
+		}
+	}
+	return b.String()
+}
+
+// DOT renders the graph in the Graphviz DOT language. Groups become
+// clusters and branching steps diamonds.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %s {\n", dotText(g.Name))
+	b.WriteString("    rankdir=TB;\n    node [shape=box];\n")
+	groups, branches := g.layout()
+	var write func(parent string, indent string)
+	write = func(parent, indent string) {
+		for _, n := range groups[parent] {
+			switch {
+			case n.Group:
+				fmt.Fprintf(&b, "%ssubgraph cluster_%s {\n", indent, n.ID)
+				fmt.Fprintf(&b, "%s    label=%s;\n", indent, dotText(n.title()))
+				write(n.ID, indent+"    ")
+				fmt.Fprintf(&b, "%s}\n", indent)
+			case branches[n.ID]:
+				fmt.Fprintf(&b, "%s%s [label=%s, shape=diamond];\n", indent, n.ID, dotText(n.title()))
+			default:
+				fmt.Fprintf(&b, "%s%s [label=%s];\n", indent, n.ID, dotText(n.title()))
+			}
+		}
+	}
+	write("", "    ")
+	for _, e := range g.Edges {
+		if e.Label != "" {
+			fmt.Fprintf(&b, "    %s -> %s [label=%s];\n", e.From, e.To, dotText(e.Label))
+		} else {
+			fmt.Fprintf(&b, "    %s -> %s;\n", e.From, e.To)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// mermaidText escapes text for a quoted Mermaid label.
+func mermaidText(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "\n", " ").Replace(s)
+}
+
+// dotText quotes text as a DOT string.
+func dotText(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,130 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type graphTestState struct {
+	Urgent bool
+}
+
+// opaqueStep is a step that does not describe itself.
+type opaqueStep struct{ name string }
+
+func (s opaqueStep) Name() string { return s.name }
+func (s opaqueStep) Run(ctx context.Context, state *graphTestState, opts ...Option) error {
+	return nil
+}
+func (s opaqueStep) RunStream(ctx context.Context, state *graphTestState, opts ...Option) <-chan Event {
+	ch := make(chan Event)
+	close(ch)
+	return ch
+}
+
+// newGraphTestWorkflow returns a workflow composing every flow:
+// fetch, then a router choosing between escalate and a parallel of two
+// summaries, then a loop around a retried review.
+func newGraphTestWorkflow() *Workflow[graphTestState] {
+	step := func(name string) Step[graphTestState] {
+		return NewFuncStep(name, func(ctx context.Context, s *graphTestState) error { return nil })
+	}
+	return New("triage", NewChain("pipeline",
+		step("fetch"),
+		NewRouter("route", []Route[graphTestState]{
+			{Name: "urgent", Condition: func(ctx context.Context, s *graphTestState) bool { return s.Urgent }, Step: step("escalate")},
+		}, NewParallel("summarize", []Step[graphTestState]{step("short"), opaqueStep{"long"}}, nil)),
+		NewLoopN("refine", NewRetryStep("retry", step("review")), 3),
+	))
+}
+
+func TestWorkflow_Describe(t *testing.T) {
+	g := newGraphTestWorkflow().Describe()
+	assert.Equal(t, "triage", g.Name)
+
+	nodes := make(map[string]Node)
+	for _, n := range g.Nodes {
+		nodes[n.Name] = n
+	}
+	require.Len(t, nodes, 10)
+	assert.Equal(t, Node{ID: "n1", Name: "pipeline", Kind: KindChain, Group: true}, nodes["pipeline"])
+	assert.Equal(t, Node{ID: "n3", Name: "route", Kind: KindRouter, Parent: "n1"}, nodes["route"])
+	assert.Equal(t, KindStep, nodes["long"].Kind)
+	assert.Equal(t, nodes["summarize"].ID, nodes["long"].Parent)
+	assert.Equal(t, "max 3 iterations", nodes["refine"].Detail)
+	assert.Equal(t, KindRetry, nodes["retry"].Kind)
+	assert.True(t, nodes["retry"].Group)
+
+	// Edges connect the steps that do the work, by name
+	type edge struct{ from, to, label string }
+	var edges []edge
+	for _, e := range g.Edges {
+		var from, to string
+		for _, n := range g.Nodes {
+			if n.ID == e.From {
+				from = n.Name
+			}
+			if n.ID == e.To {
+				to = n.Name
+			}
+		}
+		edges = append(edges, edge{from, to, e.Label})
+	}
+	assert.ElementsMatch(t, []edge{
+		{"fetch", "route", ""},
+		{"route", "escalate", "urgent"},
+		{"route", "short", "default"},
+		{"route", "long", "default"},
+		{"escalate", "review", ""},
+		{"short", "review", ""},
+		{"long", "review", ""},
+		{"review", "review", "repeat"},
+	}, edges)
+}
+
+func TestGraph_Render(t *testing.T) {
+	g := newGraphTestWorkflow().Describe()
+
+	mermaid := g.Mermaid()
+	assert.Equal(t, `flowchart TD
+    subgraph n1["pipeline (chain)"]
+        n2["fetch"]
+        n3{"route (router)"}
+        n4["escalate"]
+        subgraph n5["summarize (parallel)"]
+            n6["short"]
+            n7["long"]
+        end
+        subgraph n8["refine (loop) [max 3 iterations]"]
+            subgraph n9["retry (retry) [max 10 attempts]"]
+                n10["review"]
+            end
+        end
+    end
+    n3 -->|"urgent"| n4
+    n3 -->|"default"| n6
+    n3 -->|"default"| n7
+    n2 --> n3
+    n10 -->|"repeat"| n10
+    n4 --> n10
+    n6 --> n10
+    n7 --> n10
+`, mermaid)
+
+	dot := g.DOT()
+	assert.Contains(t, dot, `digraph "triage" {`)
+	assert.Contains(t, dot, "subgraph cluster_n5 {")
+	assert.Contains(t, dot, `n3 [label="route (router)", shape=diamond];`)
+	assert.Contains(t, dot, `n3 -> n4 [label="urgent"];`)
+	assert.Contains(t, dot, "n2 -> n3;")
+}
+
+func TestGraph_Escaping(t *testing.T) {
+	step := NewFuncStep(`say "hi"`, func(ctx context.Context, s *graphTestState) error { return nil })
+	g := New("w", step).Describe()
+	assert.Contains(t, g.Mermaid(), `n1["say #quot;hi#quot;"]`)
+	assert.Contains(t, g.DOT(), `n1 [label="say \"hi\""];`)
+}
@@ -0,0 +1,310 @@
+package workflow
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/internal/store"
+)
+
+// ErrRunNotFound indicates no history record exists for a run ID.
+var ErrRunNotFound = errors.New("workflow: run not found")
+
+// RunStatus is the state of a recorded workflow run.
+type RunStatus string
+
+const (
+	// RunStatusRunning indicates the run has started and not yet ended, or
+	// that its process stopped before recording the outcome.
+	RunStatusRunning RunStatus = "running"
+
+	// RunStatusComplete indicates the run completed.
+	RunStatusComplete RunStatus = "complete"
+
+	// RunStatusError indicates the run failed.
+	RunStatusError RunStatus = "error"
+
+	// RunStatusCancelled indicates the run's context was cancelled.
+	RunStatusCancelled RunStatus = "cancelled"
+
+	// RunStatusTimeout indicates the run's deadline was exceeded.
+	RunStatusTimeout RunStatus = "timeout"
+
+	// RunStatusSuspended indicates the run is waiting for an approval
+	// decision.
+	RunStatusSuspended RunStatus = "suspended"
+)
+
+// RunRecord summarizes one workflow run, persisted by WithRunHistory when
+// the run starts and again when it ends.
+type RunRecord struct {
+	RunID    string `json:"runId"`
+	Workflow string `json:"workflow"`
+
+	// InputHash is the hex SHA-256 of the JSON encoding of the state the
+	// run started with, so runs on identical inputs can be grouped.
+	InputHash string `json:"inputHash,omitempty"`
+
+	Status    RunStatus     `json:"status"`
+	StartedAt time.Time     `json:"startedAt"`
+	EndedAt   time.Time     `json:"endedAt,omitzero"`
+	Duration  time.Duration `json:"duration,omitempty"`
+
+	// Usage is the token usage of the run's prompt, classifier, and agent
+	// steps, summed over all its attempts.
+	Usage ai.Usage `json:"usage"`
+
+	// Error is the text of the error that ended the run, if any.
+	Error string `json:"error,omitempty"`
+
+	// Attempts is the number of times the run was started: 1, plus one for
+	// each Resume.
+	Attempts int `json:"attempts"`
+}
+
+// RunFilter selects records for ListRuns. Zero fields match every run.
+type RunFilter struct {
+	// Workflow matches runs of the named workflow.
+	Workflow string
+
+	// Status matches runs with any of the given statuses.
+	Status []RunStatus
+
+	// Since and Until match runs started at or after Since and before Until.
+	Since time.Time
+	Until time.Time
+
+	// Limit caps the number of records returned.
+	Limit int
+}
+
+// matches reports whether rec is selected by f.
+func (f RunFilter) matches(rec *RunRecord) bool {
+	if f.Workflow != "" && rec.Workflow != f.Workflow {
+		return false
+	}
+	if len(f.Status) > 0 && !containsStatus(f.Status, rec.Status) {
+		return false
+	}
+	if !f.Since.IsZero() && rec.StartedAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !rec.StartedAt.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+func containsStatus(statuses []RunStatus, s RunStatus) bool {
+	for _, status := range statuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// historyPrefix is the adapter key prefix of run history records.
+const historyPrefix = "history/run/"
+
+// historyKey returns the adapter key for a run's history record.
+func historyKey(runID string) string {
+	return historyPrefix + runID
+}
+
+// GetRun returns the history record of runID. It returns an error matching
+// ErrRunNotFound if the run was not recorded.
+func GetRun(ctx context.Context, adapter store.Adapter, runID string) (*RunRecord, error) {
+	raw, ok, err := adapter.Get(ctx, historyKey(runID))
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRunNotFound, runID)
+	}
+	var rec RunRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return nil, &store.SerializationError{Key: historyKey(runID), Err: err}
+	}
+	return &rec, nil
+}
+
+// ListRuns returns the history records selected by filter, most recently
+// started first. It reads every record in the adapter, so keep histories
+// in an adapter of their own or prune them with DeleteRun.
+func ListRuns(ctx context.Context, adapter store.Adapter, filter RunFilter) ([]RunRecord, error) {
+	keys, err := adapter.Keys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var records []RunRecord
+	for _, key := range keys {
+		runID, ok := strings.CutPrefix(key, historyPrefix)
+		if !ok {
+			continue
+		}
+		rec, err := GetRun(ctx, adapter, runID)
+		if errors.Is(err, ErrRunNotFound) {
+			continue // deleted since Keys
+		}
+		if err != nil {
+			return nil, err
+		}
+		if filter.matches(rec) {
+			records = append(records, *rec)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].StartedAt.Equal(records[j].StartedAt) {
+			return records[i].StartedAt.After(records[j].StartedAt)
+		}
+		return records[i].RunID < records[j].RunID
+	})
+	if filter.Limit > 0 && len(records) > filter.Limit {
+		records = records[:filter.Limit]
+	}
+	return records, nil
+}
+
+// DeleteRun removes the history record of runID. Deleting a run that was
+// not recorded is not an error.
+func DeleteRun(ctx context.Context, adapter store.Adapter, runID string) error {
+	return adapter.Delete(ctx, historyKey(runID))
+}
+
+// historian records one run attempt in the run history.
+type historian struct {
+	adapter store.Adapter
+	rec     RunRecord
+	start   time.Time
+	usage   usageCounter
+}
+
+// startHistory records the start of an attempt of runID, or returns nil if
+// run history is not configured. A resumed run keeps the record of its
+// first attempt, adding to its usage and attempts.
+func startHistory[S any](ctx context.Context, options *Options, workflow, runID string, state *S, resumed bool) (*historian, error) {
+	if options.RunHistory == nil || runID == "" {
+		return nil, nil
+	}
+	h := &historian{adapter: options.RunHistory, start: time.Now()}
+
+	prev, err := GetRun(ctx, h.adapter, runID)
+	switch {
+	case resumed && err == nil:
+		h.rec = *prev
+	case err != nil && !errors.Is(err, ErrRunNotFound):
+		return nil, err
+	default:
+		h.rec = RunRecord{RunID: runID, Workflow: workflow, StartedAt: h.start}
+		if data, err := json.Marshal(state); err == nil {
+			sum := sha256.Sum256(data)
+			h.rec.InputHash = hex.EncodeToString(sum[:])
+		}
+	}
+	h.rec.Status = RunStatusRunning
+	h.rec.EndedAt = time.Time{}
+	h.rec.Error = ""
+	h.rec.Attempts++
+
+	if err := h.save(ctx); err != nil {
+		return nil, fmt.Errorf("workflow: history %s: %w", runID, err)
+	}
+	return h, nil
+}
+
+// finish records the outcome of the attempt. The record is written even
+// if ctx is cancelled, since that is often how the run ended.
+func (h *historian) finish(ctx context.Context, termination TerminationReason, runErr error) error {
+	if h == nil {
+		return nil
+	}
+	now := time.Now()
+	h.rec.Status = RunStatus(termination)
+	h.rec.EndedAt = now
+	h.rec.Duration += now.Sub(h.start)
+	usage := h.usage.total()
+	h.rec.Usage.InputTokens += usage.InputTokens
+	h.rec.Usage.OutputTokens += usage.OutputTokens
+	h.rec.Usage.CachedInputTokens += usage.CachedInputTokens
+	h.rec.Usage.CacheWriteInputTokens += usage.CacheWriteInputTokens
+	if runErr != nil {
+		h.rec.Error = runErr.Error()
+	}
+	if err := h.save(context.WithoutCancel(ctx)); err != nil {
+		return fmt.Errorf("workflow: history %s: %w", h.rec.RunID, err)
+	}
+	return nil
+}
+
+func (h *historian) save(ctx context.Context) error {
+	raw, err := json.Marshal(h.rec)
+	if err != nil {
+		return &store.SerializationError{Key: historyKey(h.rec.RunID), Err: err}
+	}
+	return h.adapter.Set(ctx, historyKey(h.rec.RunID), raw)
+}
+
+// usageCounter sums the token usage of the model calls made during a run.
+type usageCounter struct {
+	mu    sync.Mutex
+	usage ai.Usage
+}
+
+func (c *usageCounter) add(u ai.Usage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.usage.InputTokens += u.InputTokens
+	c.usage.OutputTokens += u.OutputTokens
+	c.usage.CachedInputTokens += u.CachedInputTokens
+	c.usage.CacheWriteInputTokens += u.CacheWriteInputTokens
+}
+
+func (c *usageCounter) total() ai.Usage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.usage
+}
+
+// usageKeyType is the context key for the active usage counter.
+type usageKeyType struct{}
+
+// withUsageCounter returns a context whose steps add the usage of their
+// model calls to c when run with Run. Streaming runs count usage from
+// their MessageEnd events instead.
+func withUsageCounter(ctx context.Context, c *usageCounter) context.Context {
+	return context.WithValue(ctx, usageKeyType{}, c)
+}
+
+// recordUsage adds u to the usage counter in ctx, if there is one.
+func recordUsage(ctx context.Context, u ai.Usage) {
+	if c, ok := ctx.Value(usageKeyType{}).(*usageCounter); ok {
+		c.add(u)
+	}
+}
+
+// termination returns why a run under ctx stopped, given the error it
+// failed with, if any.
+func termination(ctx context.Context, err error) TerminationReason {
+	switch {
+	case err == nil:
+		return TerminationComplete
+	case errors.Is(err, ErrSuspended):
+		return TerminationSuspended
+	case ctx.Err() == context.Canceled:
+		return TerminationCancelled
+	case ctx.Err() == context.DeadlineExceeded:
+		return TerminationTimeout
+	default:
+		return TerminationError
+	}
+}
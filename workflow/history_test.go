@@ -0,0 +1,166 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func historyPromptStep(provider *mockProvider) *PromptStep[testState, string] {
+	return NewPromptStep("prompt", provider,
+		func(s *testState) []ai.Message {
+			return []ai.Message{{Role: ai.RoleUser, Content: s.Input}}
+		},
+		nil,
+		func(s *testState) *string { return &s.Output },
+	)
+}
+
+func TestWorkflow_RunHistory(t *testing.T) {
+	ctx := context.Background()
+	adapter := store.NewMemoryAdapter()
+	provider := &mockProvider{responses: []mockResponse{{content: "one"}, {content: "two"}}}
+	wf := New("summarize", NewChain("chain", historyPromptStep(provider), historyPromptStep(provider)), WithRunHistory(adapter))
+
+	result, err := wf.Run(ctx, &testState{Input: "hello"}, WithRunID("run-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "run-1", result.RunID)
+
+	rec, err := GetRun(ctx, adapter, "run-1")
+	require.NoError(t, err)
+	assert.Equal(t, "summarize", rec.Workflow)
+	assert.Equal(t, RunStatusComplete, rec.Status)
+	assert.Equal(t, ai.Usage{InputTokens: 20, OutputTokens: 40}, rec.Usage)
+	assert.Len(t, rec.InputHash, 64)
+	assert.Equal(t, 1, rec.Attempts)
+	assert.False(t, rec.EndedAt.Before(rec.StartedAt))
+	assert.Empty(t, rec.Error)
+
+	t.Run("same input has same hash", func(t *testing.T) {
+		_, err := wf.Run(ctx, &testState{Input: "hello"}, WithRunID("run-2"))
+		require.NoError(t, err)
+		other, err := GetRun(ctx, adapter, "run-2")
+		require.NoError(t, err)
+		assert.Equal(t, rec.InputHash, other.InputHash)
+	})
+
+	t.Run("unknown run", func(t *testing.T) {
+		_, err := GetRun(ctx, adapter, "missing")
+		assert.ErrorIs(t, err, ErrRunNotFound)
+	})
+}
+
+func TestWorkflow_RunHistoryRecordsFailures(t *testing.T) {
+	ctx := context.Background()
+	adapter := store.NewMemoryAdapter()
+	wf := New("failing", NewFuncStep("fail", func(ctx context.Context, s *testState) error {
+		return errors.New("boom")
+	}), WithRunHistory(adapter))
+
+	result, err := wf.Run(ctx, &testState{})
+	require.Error(t, err)
+
+	rec, err := GetRun(ctx, adapter, result.RunID)
+	require.NoError(t, err)
+	assert.Equal(t, RunStatusError, rec.Status)
+	assert.Contains(t, rec.Error, "boom")
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		wf := New("cancelled", NewFuncStep("wait", func(ctx context.Context, s *testState) error {
+			cancel()
+			return ctx.Err()
+		}), WithRunHistory(adapter))
+
+		result, err := wf.Run(ctx, &testState{})
+		require.Error(t, err)
+		rec, err := GetRun(context.Background(), adapter, result.RunID)
+		require.NoError(t, err)
+		assert.Equal(t, RunStatusCancelled, rec.Status)
+	})
+}
+
+func TestWorkflow_RunHistoryStream(t *testing.T) {
+	ctx := context.Background()
+	adapter := store.NewMemoryAdapter()
+	provider := &mockProvider{responses: []mockResponse{{content: "hi"}}}
+	wf := New("stream", historyPromptStep(provider), WithRunHistory(adapter))
+
+	for range wf.RunStream(ctx, &testState{Input: "hello"}, WithRunID("stream-1")) {
+	}
+
+	rec, err := GetRun(ctx, adapter, "stream-1")
+	require.NoError(t, err)
+	assert.Equal(t, RunStatusComplete, rec.Status)
+	assert.Equal(t, ai.Usage{InputTokens: 10, OutputTokens: 20}, rec.Usage)
+}
+
+func TestWorkflow_RunHistoryResume(t *testing.T) {
+	ctx := context.Background()
+	adapter := store.NewMemoryAdapter()
+	var calls [3]atomic.Int32
+	var fail atomic.Bool
+	fail.Store(true)
+
+	wf := New("test", checkpointChain(&calls, &fail), WithCheckpointer(adapter), WithRunHistory(adapter))
+	_, err := wf.Run(ctx, &testState{}, WithRunID("run-1"))
+	require.Error(t, err)
+	first, err := GetRun(ctx, adapter, "run-1")
+	require.NoError(t, err)
+
+	fail.Store(false)
+	_, err = wf.Resume(ctx, "run-1")
+	require.NoError(t, err)
+
+	rec, err := GetRun(ctx, adapter, "run-1")
+	require.NoError(t, err)
+	assert.Equal(t, RunStatusComplete, rec.Status)
+	assert.Equal(t, 2, rec.Attempts)
+	assert.Empty(t, rec.Error)
+	assert.Equal(t, first.InputHash, rec.InputHash)
+	assert.True(t, rec.StartedAt.Equal(first.StartedAt))
+}
+
+func TestListRuns(t *testing.T) {
+	ctx := context.Background()
+	adapter := store.NewMemoryAdapter()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []RunRecord{
+		{RunID: "a", Workflow: "ingest", Status: RunStatusComplete, StartedAt: base},
+		{RunID: "b", Workflow: "ingest", Status: RunStatusError, StartedAt: base.Add(time.Hour)},
+		{RunID: "c", Workflow: "report", Status: RunStatusComplete, StartedAt: base.Add(2 * time.Hour)},
+		{RunID: "d", Workflow: "ingest", Status: RunStatusRunning, StartedAt: base.Add(3 * time.Hour)},
+	}
+	for _, rec := range records {
+		h := &historian{adapter: adapter, rec: rec}
+		require.NoError(t, h.save(ctx))
+	}
+	// Unrelated keys in the same adapter are ignored.
+	require.NoError(t, adapter.Set(ctx, checkpointKey("a"), []byte(`{}`)))
+
+	ids := func(filter RunFilter) []string {
+		runs, err := ListRuns(ctx, adapter, filter)
+		require.NoError(t, err)
+		var ids []string
+		for _, r := range runs {
+			ids = append(ids, r.RunID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"d", "c", "b", "a"}, ids(RunFilter{}))
+	assert.Equal(t, []string{"d", "b", "a"}, ids(RunFilter{Workflow: "ingest"}))
+	assert.Equal(t, []string{"c", "a"}, ids(RunFilter{Status: []RunStatus{RunStatusComplete}}))
+	assert.Equal(t, []string{"c", "b"}, ids(RunFilter{Since: base.Add(time.Hour), Until: base.Add(3 * time.Hour)}))
+	assert.Equal(t, []string{"d"}, ids(RunFilter{Limit: 1}))
+
+	require.NoError(t, DeleteRun(ctx, adapter, "d"))
+	assert.Equal(t, []string{"b", "a"}, ids(RunFilter{Workflow: "ingest"}))
+}
@@ -2,6 +2,7 @@ package workflow
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/spetersoncode/gains/event"
 )
@@ -119,6 +120,15 @@ func NewLoopN[S any](name string, step Step[S], n int) *Loop[S] {
 // Name returns the loop name.
 func (l *Loop[S]) Name() string { return l.name }
 
+// DescribeStep describes the loop and its body for Workflow.Describe.
+func (l *Loop[S]) DescribeStep() StepInfo {
+	info := StepInfo{Kind: KindLoop, Flow: FlowLoop, Children: []ChildStep{{Step: l.step}}}
+	if l.maxIters > 0 {
+		info.Detail = fmt.Sprintf("max %d iterations", l.maxIters)
+	}
+	return info
+}
+
 // Run executes the step repeatedly until the exit condition returns true.
 func (l *Loop[S]) Run(ctx context.Context, state *S, opts ...Option) error {
 	options := ApplyOptions(opts...)
@@ -171,10 +181,11 @@ func (l *Loop[S]) Run(ctx context.Context, state *S, opts ...Option) error {
 
 // RunStream executes the step repeatedly and emits events.
 func (l *Loop[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-chan Event {
-	ch := make(chan Event, 100)
+	ch := newEventChannel(opts)
 
 	go func() {
 		defer close(ch)
+		defer recoverStream(ch, l.name)
 		options := ApplyOptions(opts...)
 
 		if options.Timeout > 0 {
@@ -221,9 +232,11 @@ func (l *Loop[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-cha
 					}
 					// Handler suppressed the error
 					if options.ContinueOnError {
+						emitProgress(ch, options, l.name, i, l.maxIters, "iterations")
 						continue
 					}
 					// Error suppressed, stop successfully
+					emitProgress(ch, options, l.name, i, i, "iterations")
 					event.Emit(ch, Event{Type: event.RunEnd, StepName: l.name})
 					return
 				}
@@ -233,12 +246,15 @@ func (l *Loop[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-cha
 
 			// Check exit condition after step execution
 			if l.exitCondition(ctx, state, i) {
+				// Exiting early completes the estimate
+				emitProgress(ch, options, l.name, i, i, "iterations")
 				event.Emit(ch, Event{
 					Type:     event.RunEnd,
 					StepName: l.name,
 				})
 				return
 			}
+			emitProgress(ch, options, l.name, i, l.maxIters, "iterations")
 		}
 
 		// Max iterations exceeded
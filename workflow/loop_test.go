@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/spetersoncode/gains/event"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -216,3 +217,27 @@ func TestNewLoopWithExitCondition(t *testing.T) {
 		assert.Error(t, err) // Should error due to context cancellation
 	})
 }
+
+func TestLoop_RunStreamProgress(t *testing.T) {
+	step := NewFuncStep("count", func(ctx context.Context, s *loopTestState) error {
+		s.Count++
+		return nil
+	})
+	loop := NewLoopUntil("loop", step,
+		func(s *loopTestState) bool { return s.Count == 2 },
+		WithMaxIterations(5),
+	)
+
+	var progress []event.ProgressInfo
+	for ev := range loop.RunStream(context.Background(), &loopTestState{}, WithProgress()) {
+		if ev.Type == event.Progress {
+			progress = append(progress, *ev.Progress)
+		}
+	}
+
+	// Exiting before the limit completes the estimate
+	assert.Equal(t, []event.ProgressInfo{
+		{Completed: 1, Total: 5, Unit: "iterations"},
+		{Completed: 2, Total: 2, Unit: "iterations"},
+	}, progress)
+}
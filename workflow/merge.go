@@ -1,15 +1,153 @@
 package workflow
 
-// This file previously contained aggregator helpers for the old dynamic State system.
-// With struct-based state, aggregators are custom functions that know the state schema:
-//
-// Example aggregator for struct-based state:
-//
-//	func collectResults(state *MyState, results map[string]*BranchResult[MyState], errors map[string]error) error {
-//	    for name, br := range results {
-//	        state.Results[name] = br.State.Result
-//	    }
-//	    return nil
-//	}
-//
-// The Aggregator[S] type is defined in parallel.go.
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrQuorumNotMet indicates too few branches of a parallel step with a
+// quorum succeeded.
+var ErrQuorumNotMet = errors.New("workflow: parallel quorum not met")
+
+// errQuorumReached cancels the branches still running once a parallel
+// step's quorum is met.
+var errQuorumReached = errors.New("workflow: parallel quorum reached")
+
+// ParallelOption configures how a Parallel step joins its branches.
+type ParallelOption func(*parallelConfig)
+
+type parallelConfig struct {
+	quorum int
+}
+
+// WithJoinAll waits for every branch, failing the step on any branch
+// error unless WithContinueOnError is set. This is the default.
+func WithJoinAll() ParallelOption {
+	return func(c *parallelConfig) {
+		c.quorum = 0
+	}
+}
+
+// WithFirstSuccess ends the step as soon as one branch succeeds,
+// cancelling the others, such as to race several models and keep the
+// fastest answer. It is WithQuorum(1).
+func WithFirstSuccess() ParallelOption {
+	return WithQuorum(1)
+}
+
+// WithQuorum ends the step as soon as n branches succeed, cancelling the
+// others. Branch failures are tolerated while the quorum can still be met;
+// once it cannot, the remaining branches are cancelled and the step fails
+// with ErrQuorumNotMet. Only the n contributing branches are passed to the
+// aggregator, with the failures seen so far. Zero or less waits for every
+// branch, as WithJoinAll.
+func WithQuorum(n int) ParallelOption {
+	return func(c *parallelConfig) {
+		c.quorum = max(n, 0)
+	}
+}
+
+// join tracks the branches of a parallel step against its join policy.
+type join[S any] struct {
+	quorum int // branches needed; zero waits for all
+	total  int
+
+	mu       sync.Mutex
+	branches map[string]*S
+	errs     map[string]error
+	order    []string // contributing branches, in completion order
+}
+
+func newJoin[S any](quorum, total int) *join[S] {
+	return &join[S]{
+		quorum:   quorum,
+		total:    total,
+		branches: make(map[string]*S),
+		errs:     make(map[string]error),
+	}
+}
+
+// set records the state of branch name without counting it towards the
+// quorum.
+func (j *join[S]) set(name string, state *S) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.branches[name] = state
+}
+
+// succeed records that branch name succeeded with state. It returns
+// errQuorumReached when the branch completes the quorum, to cancel the
+// others. Branches succeeding after the quorum is met do not contribute.
+func (j *join[S]) succeed(name string, state *S) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.quorum > 0 && len(j.order) >= j.quorum {
+		return nil
+	}
+	j.branches[name] = state
+	j.order = append(j.order, name)
+	if j.quorum > 0 && len(j.order) == j.quorum {
+		return errQuorumReached
+	}
+	return nil
+}
+
+// fail records that branch name failed with err. It returns err if the
+// siblings should be cancelled: with a quorum, once it can no longer be
+// met, and otherwise if cancelOnError is set.
+func (j *join[S]) fail(name string, err error, cancelOnError bool) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.errs[name] = err
+	if j.quorum == 0 {
+		if cancelOnError {
+			return err
+		}
+		return nil
+	}
+	if len(j.order) < j.quorum && len(j.errs) > j.total-j.quorum {
+		return err
+	}
+	return nil
+}
+
+// tolerates reports whether branch failures are reported as skipped
+// branches rather than errors.
+func (j *join[S]) tolerates(continueOnError bool) bool {
+	return continueOnError || j.quorum > 0
+}
+
+// contributors returns the branches that met the quorum, in completion
+// order, or nil without a quorum.
+func (j *join[S]) contributors() []string {
+	if j.quorum == 0 {
+		return nil
+	}
+	return j.order
+}
+
+// err returns the error of the joined step once every branch has
+// returned, or nil if it succeeded.
+func (j *join[S]) err(continueOnError bool) error {
+	if j.quorum == 0 {
+		if len(j.errs) > 0 && !continueOnError {
+			return &ParallelError{Errors: j.errs}
+		}
+		return nil
+	}
+	if len(j.order) < j.quorum {
+		return fmt.Errorf("%w: %d of %d branches succeeded, %d required: %w",
+			ErrQuorumNotMet, len(j.order), j.total, j.quorum, &ParallelError{Errors: j.errs})
+	}
+	return nil
+}
+
+// siblingReason describes why a branch was cancelled through branchCtx.
+func siblingReason(branchCtx context.Context) string {
+	if errors.Is(context.Cause(branchCtx), errQuorumReached) {
+		return "quorum reached"
+	}
+	return "sibling step failed"
+}
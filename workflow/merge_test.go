@@ -2,8 +2,10 @@ package workflow
 
 import (
 	"context"
+	"errors"
 	"testing"
 
+	"github.com/spetersoncode/gains/event"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -192,3 +194,113 @@ func TestParallelWithAggregator(t *testing.T) {
 		assert.ElementsMatch(t, []string{"value1", "value3"}, state.Results)
 	})
 }
+
+// quorumSteps returns branches named after their outcome: "ok" branches
+// set A to their name, "fail" branches fail, and "slow" branches block
+// until cancelled, so a step joining them returns only if they are.
+func quorumSteps(names ...string) []Step[parallelTestState] {
+	var steps []Step[parallelTestState]
+	for _, name := range names {
+		steps = append(steps, NewFuncStep(name, func(ctx context.Context, s *parallelTestState) error {
+			switch {
+			case len(name) >= 4 && name[:4] == "fail":
+				return errors.New(name + " failed")
+			case len(name) >= 4 && name[:4] == "slow":
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			s.A = name
+			return nil
+		}))
+	}
+	return steps
+}
+
+// collectA records the A of every branch passed to the aggregator.
+func collectA(state *parallelTestState, branches map[string]*parallelTestState, errs map[string]error) error {
+	for _, br := range branches {
+		state.Results = append(state.Results, br.A)
+	}
+	return nil
+}
+
+func TestParallel_FirstSuccess(t *testing.T) {
+	parallel := NewParallel("race", quorumSteps("slow", "ok", "slow2"), collectA, WithFirstSuccess())
+
+	state := &parallelTestState{}
+	require.NoError(t, parallel.Run(context.Background(), state))
+	assert.Equal(t, []string{"ok"}, state.Results)
+}
+
+func TestParallel_Quorum(t *testing.T) {
+	parallel := NewParallel("vote", quorumSteps("fail", "ok1", "ok2"), collectA, WithQuorum(2))
+
+	state := &parallelTestState{}
+	require.NoError(t, parallel.Run(context.Background(), state))
+	assert.ElementsMatch(t, []string{"ok1", "ok2"}, state.Results)
+}
+
+func TestParallel_QuorumNotMet(t *testing.T) {
+	parallel := NewParallel("vote", quorumSteps("fail1", "fail2", "slow"), collectA, WithQuorum(2))
+
+	err := parallel.Run(context.Background(), &parallelTestState{})
+	require.ErrorIs(t, err, ErrQuorumNotMet)
+	var pe *ParallelError
+	require.ErrorAs(t, err, &pe)
+	assert.Len(t, pe.Errors, 2)
+}
+
+func TestParallel_JoinAllIsDefault(t *testing.T) {
+	parallel := NewParallel("all", quorumSteps("ok1", "ok2"), collectA, WithQuorum(1), WithJoinAll())
+
+	state := &parallelTestState{}
+	require.NoError(t, parallel.Run(context.Background(), state))
+	assert.ElementsMatch(t, []string{"ok1", "ok2"}, state.Results)
+}
+
+func TestParallel_RunStreamFirstSuccess(t *testing.T) {
+	failed := make(chan struct{})
+	steps := []Step[parallelTestState]{
+		NewFuncStep("fail", func(ctx context.Context, s *parallelTestState) error {
+			close(failed)
+			return errors.New("fail failed")
+		}),
+		NewFuncStep("ok", func(ctx context.Context, s *parallelTestState) error {
+			<-failed
+			s.A = "ok"
+			return nil
+		}),
+	}
+	steps = append(steps, quorumSteps("slow")...)
+	parallel := NewParallel("race", steps, collectA, WithFirstSuccess())
+
+	state := &parallelTestState{}
+	var selected []string
+	skipped := map[string]string{}
+	for ev := range parallel.RunStream(context.Background(), state) {
+		switch ev.Type {
+		case event.RunError:
+			t.Fatalf("unexpected error: %v", ev.Error)
+		case event.RouteSelected:
+			selected = append(selected, ev.RouteName)
+		case event.StepSkipped:
+			skipped[ev.StepName] = ev.Message
+		}
+	}
+	assert.Equal(t, []string{"ok"}, selected)
+	assert.Equal(t, []string{"ok"}, state.Results)
+	assert.Equal(t, "step failed, continuing", skipped["fail"])
+	assert.Equal(t, "quorum reached", skipped["slow"])
+}
+
+func TestParallel_RunStreamQuorumNotMet(t *testing.T) {
+	parallel := NewParallel("vote", quorumSteps("fail1", "fail2", "ok"), collectA, WithQuorum(2))
+
+	var runErr error
+	for ev := range parallel.RunStream(context.Background(), &parallelTestState{}) {
+		if ev.Type == event.RunError {
+			runErr = ev.Error
+		}
+	}
+	assert.ErrorIs(t, runErr, ErrQuorumNotMet)
+}
@@ -2,9 +2,12 @@ package workflow
 
 import (
 	"context"
+	"log/slog"
 	"time"
 
 	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/internal/store"
 )
 
 // ErrorHandler is called when a step encounters an error.
@@ -29,6 +32,10 @@ type Options struct {
 	// MaxConcurrency limits parallel step execution (0 = unlimited).
 	MaxConcurrency int
 
+	// RunConcurrency limits the parallel branches and agent tool calls
+	// running at once across the whole run (0 = unlimited).
+	RunConcurrency int
+
 	// ErrorHandler is called on step errors. See ErrorHandler type for semantics.
 	ErrorHandler ErrorHandler
 
@@ -37,8 +44,54 @@ type Options struct {
 	// Has no effect when ErrorHandler returns an error (workflow always stops with error).
 	ContinueOnError bool
 
+	// CancelOnError cancels the remaining branches of a parallel step as soon
+	// as one fails. It has no effect when ContinueOnError is true. Default is true.
+	CancelOnError bool
+
 	// ChatOptions are passed to LLM calls within steps.
 	ChatOptions []ai.Option
+
+	// ContextManager trims the messages of prompt steps to fit the model's
+	// context window. Nil sends them as built.
+	ContextManager *ai.ContextManager
+
+	// Checkpointer persists the state and chain positions of a Workflow run
+	// after each completed step. Nil disables checkpointing.
+	Checkpointer store.Adapter
+
+	// RunID identifies a checkpointed run. If empty, Workflow.Run generates one.
+	RunID string
+
+	// ArtifactStore persists the state fields tagged with `artifact:"name"`
+	// when a Workflow run completes. Nil disables artifacts.
+	ArtifactStore store.Adapter
+
+	// RunHistory records the outcome, duration, and token usage of each
+	// Workflow run. Nil disables run history.
+	RunHistory store.Adapter
+
+	// DryRun walks the steps without calling models or executing tools.
+	// Default is false.
+	DryRun bool
+
+	// Logger receives a structured log record for each run, step, tool
+	// call, and retry event of a Workflow or Runner. If nil, nothing is
+	// logged.
+	Logger *slog.Logger
+
+	// LogLevels sets the level of each kind of record sent to Logger.
+	// Default is event.DefaultLogLevels.
+	LogLevels event.LogLevels
+
+	// Progress emits Progress events as chains complete steps and loops
+	// complete iterations. Default is false.
+	Progress bool
+
+	// EventBuffer is the capacity of each step's event channel, and of the
+	// channel of each parallel branch. Events are dropped when a slow
+	// consumer lets one fill; see event.Stats. Default is
+	// event.DefaultBufferSize.
+	EventBuffer int
 }
 
 // Option is a functional option for workflow configuration.
@@ -66,6 +119,18 @@ func WithMaxConcurrency(n int) Option {
 	}
 }
 
+// WithRunConcurrency limits the total parallelism of a run to n: parallel
+// branches, the tool calls of agent steps, and the sub-agents those tools
+// invoke all share one budget, where WithMaxConcurrency bounds each
+// parallel step separately. Work that finds the budget spent runs
+// sequentially rather than waiting. A value of 0 means no limit. See
+// ai.WithConcurrencyLimit.
+func WithRunConcurrency(n int) Option {
+	return func(o *Options) {
+		o.RunConcurrency = n
+	}
+}
+
 // WithErrorHandler sets a custom error handler.
 func WithErrorHandler(fn ErrorHandler) Option {
 	return func(o *Options) {
@@ -80,6 +145,77 @@ func WithContinueOnError(enabled bool) Option {
 	}
 }
 
+// WithCancelOnError controls whether a failing parallel branch cancels its
+// siblings. Disable it to let every branch finish so the ParallelError
+// reports all failures. Default is true.
+func WithCancelOnError(enabled bool) Option {
+	return func(o *Options) {
+		o.CancelOnError = enabled
+	}
+}
+
+// WithCheckpointer persists Workflow runs to adapter after every completed
+// chain step so an interrupted run can continue with Workflow.Resume. Any
+// store adapter works, such as the Redis adapter in
+// github.com/spetersoncode/gains/store/redis.
+func WithCheckpointer(adapter store.Adapter) Option {
+	return func(o *Options) {
+		o.Checkpointer = adapter
+	}
+}
+
+// WithArtifactStore persists the outputs of Workflow runs to adapter. When a
+// run completes, every state field tagged with `artifact:"name"` is stored
+// as the next version of the named artifact under the run ID, which
+// downstream services pass to LoadArtifacts to fetch the results without
+// running the workflow again:
+//
+//	type State struct {
+//	    Topic  string
+//	    Report string `artifact:"report"`
+//	}
+func WithArtifactStore(adapter store.Adapter) Option {
+	return func(o *Options) {
+		o.ArtifactStore = adapter
+	}
+}
+
+// WithRunHistory records each Workflow run to adapter: a record marked
+// running when the run starts, updated with its outcome, duration, token
+// usage, and error when it ends. Query the records with GetRun and
+// ListRuns, under the run ID reported in Result.RunID.
+func WithRunHistory(adapter store.Adapter) Option {
+	return func(o *Options) {
+		o.RunHistory = adapter
+	}
+}
+
+// WithRunID sets the ID under which a checkpointed run is stored. Choose a
+// stable ID, such as a job or request ID, so the run can be resumed after a
+// crash. Without it a random ID is generated and reported in Result.RunID.
+func WithRunID(id string) Option {
+	return func(o *Options) {
+		o.RunID = id
+	}
+}
+
+// WithDryRun walks the workflow without calling models or executing tools.
+// Prompt steps emit a ChatPlanned event with the messages and tools they
+// would send and leave their state field unset, classifier routers walk
+// every route, and checkpoints are not written. Agent and tool steps see the
+// dry run through the context (see gains.WithDryRun).
+func WithDryRun() Option {
+	return func(o *Options) {
+		o.DryRun = true
+	}
+}
+
+// dryRun reports whether a step runs dry, either by option or because ctx
+// was marked by gains.WithDryRun.
+func dryRun(ctx context.Context, options *Options) bool {
+	return options.DryRun || ai.IsDryRun(ctx)
+}
+
 // WithChatOptions passes options to LLM calls.
 func WithChatOptions(opts ...ai.Option) Option {
 	return func(o *Options) {
@@ -87,6 +223,49 @@ func WithChatOptions(opts ...ai.Option) Option {
 	}
 }
 
+// WithContextManager trims the messages built by prompt steps with m so
+// they fit the context window of the model they are sent to.
+func WithContextManager(m *ai.ContextManager) Option {
+	return func(o *Options) {
+		o.ContextManager = m
+	}
+}
+
+// WithLogger logs runs of a Workflow or Runner to logger: step starts and
+// ends, routing, tool calls, retries, errors, and token usage from
+// RunStream, and the start and outcome of Run, which emits no events.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *Options) {
+		o.Logger = logger
+	}
+}
+
+// WithLogLevels sets the levels used by WithLogger.
+func WithLogLevels(levels event.LogLevels) Option {
+	return func(o *Options) {
+		o.LogLevels = levels
+	}
+}
+
+// WithProgress makes chains and loops emit Progress events estimating how
+// far they have come: completed steps out of the chain's steps, and
+// completed iterations out of the loop's limit. Frontends can show them as
+// progress bars; the outermost chain's events track the whole run.
+func WithProgress() Option {
+	return func(o *Options) {
+		o.Progress = true
+	}
+}
+
+// WithEventBuffer sets the capacity of the event channels of the workflow's
+// steps. Raise it for parallel workflows whose branches stream faster than
+// the consumer reads, which otherwise drops deltas.
+func WithEventBuffer(n int) Option {
+	return func(o *Options) {
+		o.EventBuffer = n
+	}
+}
+
 // WithModel is a convenience option to set the model for chat calls.
 func WithModel(model ai.Model) Option {
 	return func(o *Options) {
@@ -113,9 +292,24 @@ func ApplyOptions(opts ...Option) *Options {
 	o := &Options{
 		StepTimeout:     2 * time.Minute,
 		ContinueOnError: false,
+		CancelOnError:   true,
+		LogLevels:       event.DefaultLogLevels(),
 	}
 	for _, opt := range opts {
 		opt(o)
 	}
 	return o
 }
+
+// eventBuffer returns the capacity of step event channels.
+func (o *Options) eventBuffer() int {
+	if o.EventBuffer > 0 {
+		return o.EventBuffer
+	}
+	return event.DefaultBufferSize
+}
+
+// newEventChannel creates the event channel of a step run with opts.
+func newEventChannel(opts []Option) chan Event {
+	return make(chan Event, ApplyOptions(opts...).eventBuffer())
+}
@@ -3,9 +3,12 @@ package workflow
 import (
 	"context"
 	"encoding/json"
-	"sync"
+	"errors"
+	"time"
 
+	ai "github.com/spetersoncode/gains"
 	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/internal/group"
 )
 
 // Aggregator combines results from parallel steps into the shared state.
@@ -18,17 +21,24 @@ type Parallel[S any] struct {
 	name       string
 	steps      []Step[S]
 	aggregator Aggregator[S]
+	config     parallelConfig
 }
 
 // NewParallel creates a parallel workflow.
 // The aggregator is called with all results after all steps complete.
 // If aggregator is nil, no automatic merging occurs (user handles via aggregator).
-func NewParallel[S any](name string, steps []Step[S], aggregator Aggregator[S]) *Parallel[S] {
-	return &Parallel[S]{
+// Options set the join policy: WithJoinAll (the default), WithFirstSuccess,
+// or WithQuorum.
+func NewParallel[S any](name string, steps []Step[S], aggregator Aggregator[S], opts ...ParallelOption) *Parallel[S] {
+	p := &Parallel[S]{
 		name:       name,
 		steps:      steps,
 		aggregator: aggregator,
 	}
+	for _, opt := range opts {
+		opt(&p.config)
+	}
+	return p
 }
 
 // DeepClone creates a deep copy of a struct using JSON serialization.
@@ -49,7 +59,22 @@ func DeepClone[S any](src *S) (*S, error) {
 // Name returns the parallel workflow name.
 func (p *Parallel[S]) Name() string { return p.name }
 
+// DescribeStep describes the parallel step and its branches for
+// Workflow.Describe.
+func (p *Parallel[S]) DescribeStep() StepInfo {
+	info := StepInfo{Kind: KindParallel, Flow: FlowParallel}
+	for _, step := range p.steps {
+		info.Children = append(info.Children, ChildStep{Step: step})
+	}
+	return info
+}
+
 // Run executes steps concurrently.
+// Each branch runs on a deep copy of state. When a branch fails and neither
+// ContinueOnError nor WithCancelOnError(false) is set, the remaining branches
+// are cancelled. With a quorum, the remaining branches are cancelled once it
+// is met instead. A panicking branch fails with a *StepError wrapping a
+// *PanicError instead of crashing the process.
 func (p *Parallel[S]) Run(ctx context.Context, state *S, opts ...Option) error {
 	options := ApplyOptions(opts...)
 
@@ -59,65 +84,42 @@ func (p *Parallel[S]) Run(ctx context.Context, state *S, opts ...Option) error {
 		defer cancel()
 	}
 
-	branches := make(map[string]*S)
-	errors := make(map[string]error)
-	var mu sync.Mutex
-	var wg sync.WaitGroup
+	cancelOnError := options.CancelOnError && !options.ContinueOnError
+	g, branchCtx := group.WithContext(ctx)
+	branchCtx = withCheckpointer(branchCtx, nil)
+	g.SetLimit(options.MaxConcurrency)
+	g.SetLimiter(ai.ContextConcurrencyLimiter(ctx))
 
-	// Semaphore for concurrency limiting
-	var sem chan struct{}
-	if options.MaxConcurrency > 0 {
-		sem = make(chan struct{}, options.MaxConcurrency)
-	}
+	j := newJoin[S](p.config.quorum, len(p.steps))
 
 	for _, step := range p.steps {
-		wg.Add(1)
-		go func(s Step[S]) {
-			defer wg.Done()
-
-			if sem != nil {
-				sem <- struct{}{}
-				defer func() { <-sem }()
-			}
-
-			// Each parallel branch gets a deep-cloned state
-			branchState, err := DeepClone(state)
-			if err != nil {
-				mu.Lock()
-				errors[s.Name()] = &StepError{StepName: s.Name(), Err: err}
-				mu.Unlock()
-				return
+		g.Go(func() error {
+			// A sibling already failed or the quorum is met; don't start this branch
+			if branchCtx.Err() != nil && ctx.Err() == nil {
+				return nil
 			}
 
-			stepCtx := ctx
-			if options.StepTimeout > 0 {
-				var cancel context.CancelFunc
-				stepCtx, cancel = context.WithTimeout(ctx, options.StepTimeout)
-				defer cancel()
+			branchState, err := runBranch(branchCtx, step, state, options.StepTimeout, opts)
+			if err != nil && cancelledBySibling(ctx, branchCtx, err) {
+				return nil
 			}
-
-			err = s.Run(stepCtx, branchState, opts...)
-
-			mu.Lock()
-			defer mu.Unlock()
 			if err != nil {
-				errors[s.Name()] = err
-			} else {
-				branches[s.Name()] = branchState
+				return j.fail(step.Name(), err, cancelOnError)
 			}
-		}(step)
+			return j.succeed(step.Name(), branchState)
+		})
 	}
 
-	wg.Wait()
+	_ = g.Wait() // Branch errors are collected by j
 
 	// Handle errors
-	if len(errors) > 0 && !options.ContinueOnError {
-		return &ParallelError{Errors: errors}
+	if err := j.err(options.ContinueOnError); err != nil {
+		return err
 	}
 
 	// Aggregate results
 	if p.aggregator != nil {
-		if err := p.aggregator(state, branches, errors); err != nil {
+		if err := p.aggregator(state, j.branches, j.errs); err != nil {
 			return err
 		}
 	}
@@ -125,12 +127,44 @@ func (p *Parallel[S]) Run(ctx context.Context, state *S, opts ...Option) error {
 	return nil
 }
 
+// runBranch runs step on a deep copy of state and returns the copy. A panic
+// in the step is returned as a *StepError wrapping a *PanicError.
+func runBranch[S any](ctx context.Context, step Step[S], state *S, timeout time.Duration, opts []Option) (*S, error) {
+	branchState, err := DeepClone(state)
+	if err != nil {
+		return nil, &StepError{StepName: step.Name(), Err: err}
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err = callStep(step.Name(), func() error {
+		return step.Run(ctx, branchState, opts...)
+	})
+	return branchState, err
+}
+
+// cancelledBySibling reports whether err results from branchCtx being
+// cancelled because another branch failed, rather than from the branch
+// itself or the caller's context.
+func cancelledBySibling(ctx, branchCtx context.Context, err error) bool {
+	return ctx.Err() == nil && branchCtx.Err() != nil && errors.Is(err, context.Canceled)
+}
+
 // RunStream executes steps concurrently and emits events.
+// Cancellation and panic handling follow Run. Branches cancelled because a
+// sibling failed or the quorum was met emit StepSkipped rather than
+// RunError. With a quorum, failed branches emit StepSkipped too, and a
+// RouteSelected event names each contributing branch before ParallelEnd.
 func (p *Parallel[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-chan Event {
-	ch := make(chan Event, 100)
+	ch := newEventChannel(opts)
 
 	go func() {
 		defer close(ch)
+		defer recoverStream(ch, p.name)
 		options := ApplyOptions(opts...)
 
 		if options.Timeout > 0 {
@@ -141,70 +175,86 @@ func (p *Parallel[S]) RunStream(ctx context.Context, state *S, opts ...Option) <
 
 		event.Emit(ch, Event{Type: event.ParallelStart, StepName: p.name})
 
-		branches := make(map[string]*S)
-		errors := make(map[string]error)
-		var mu sync.Mutex
-		var wg sync.WaitGroup
+		cancelOnError := options.CancelOnError && !options.ContinueOnError
+		g, branchCtx := group.WithContext(ctx)
+		branchCtx = withCheckpointer(branchCtx, nil)
+		g.SetLimit(options.MaxConcurrency)
+		g.SetLimiter(ai.ContextConcurrencyLimiter(ctx))
+
+		j := newJoin[S](p.config.quorum, len(p.steps))
+		tolerated := j.tolerates(options.ContinueOnError)
 
 		// Create a merged event channel
-		eventCh := make(chan Event, len(p.steps)*100)
+		eventCh := make(chan Event, len(p.steps)*options.eventBuffer())
 
-		// Semaphore for concurrency limiting
-		var sem chan struct{}
-		if options.MaxConcurrency > 0 {
-			sem = make(chan struct{}, options.MaxConcurrency)
+		skip := func(name string, err error, msg string) {
+			eventCh <- Event{Type: event.StepSkipped, StepName: name, Error: err, Message: msg}
 		}
 
-		for _, step := range p.steps {
-			wg.Add(1)
-			go func(s Step[S]) {
-				defer wg.Done()
-
-				if sem != nil {
-					sem <- struct{}{}
-					defer func() { <-sem }()
-				}
+		// fail reports a branch failure that did not come from its stream
+		fail := func(name string, err error) error {
+			if tolerated {
+				skip(name, err, "step failed, continuing")
+			} else {
+				eventCh <- Event{Type: event.RunError, StepName: name, Error: err}
+			}
+			return j.fail(name, err, cancelOnError)
+		}
 
-				// Deep clone state for this branch
-				branchState, err := DeepClone(state)
-				if err != nil {
-					mu.Lock()
-					errors[s.Name()] = &StepError{StepName: s.Name(), Err: err}
-					mu.Unlock()
-					eventCh <- Event{Type: event.RunError, StepName: s.Name(), Error: err}
-					return
-				}
+		runStreamBranch := func(s Step[S]) error {
+			if branchCtx.Err() != nil && ctx.Err() == nil {
+				skip(s.Name(), context.Cause(branchCtx), siblingReason(branchCtx))
+				return nil
+			}
 
-				stepEvents := s.RunStream(ctx, branchState, opts...)
+			// Deep clone state for this branch
+			branchState, err := DeepClone(state)
+			if err != nil {
+				return fail(s.Name(), &StepError{StepName: s.Name(), Err: err})
+			}
 
-				for ev := range stepEvents {
-					mu.Lock()
-					if ev.Type == event.StepEnd {
-						branches[s.Name()] = branchState
-					}
-					if ev.Type == event.RunError {
-						errors[s.Name()] = ev.Error
-						// In ContinueOnError mode, emit StepSkipped instead of RunError
-						if options.ContinueOnError {
-							eventCh <- Event{
-								Type:     event.StepSkipped,
-								StepName: s.Name(),
-								Error:    ev.Error,
-								Message:  "step failed, continuing",
-							}
-							mu.Unlock()
-							continue
-						}
+			var failed error
+			for ev := range s.RunStream(branchCtx, branchState, opts...) {
+				if ev.Type == event.RunError && cancelledBySibling(ctx, branchCtx, ev.Error) {
+					skip(s.Name(), ev.Error, siblingReason(branchCtx))
+					failed = nil
+					continue
+				}
+				if ev.Type == event.StepEnd && j.quorum == 0 {
+					j.set(s.Name(), branchState)
+				}
+				if ev.Type == event.RunError {
+					failed = ev.Error
+					// When failures are tolerated, emit StepSkipped instead of RunError
+					if tolerated {
+						skip(s.Name(), ev.Error, "step failed, continuing")
+						continue
 					}
-					mu.Unlock()
-					eventCh <- ev
 				}
-			}(step)
+				eventCh <- ev
+			}
+			if failed != nil {
+				return j.fail(s.Name(), failed, cancelOnError)
+			}
+			if branchCtx.Err() != nil && ctx.Err() == nil {
+				return nil
+			}
+			return j.succeed(s.Name(), branchState)
 		}
 
-		// Wait for all steps and close event channel
+		// Start branches in the background so a concurrency limit never
+		// blocks event forwarding
 		go func() {
-			wg.Wait()
+			for _, step := range p.steps {
+				g.Go(func() error {
+					err := group.Call(func() error { return runStreamBranch(step) })
+					if pe, ok := err.(*PanicError); ok {
+						return fail(step.Name(), &StepError{StepName: step.Name(), Err: pe})
+					}
+					return err
+				})
+			}
+			_ = g.Wait()
 			close(eventCh)
 		}()
 
@@ -214,14 +264,17 @@ func (p *Parallel[S]) RunStream(ctx context.Context, state *S, opts ...Option) <
 		}
 
 		// Handle errors
-		if len(errors) > 0 && !options.ContinueOnError {
-			event.Emit(ch, Event{Type: event.RunError, StepName: p.name, Error: &ParallelError{Errors: errors}})
+		if err := j.err(options.ContinueOnError); err != nil {
+			event.Emit(ch, Event{Type: event.RunError, StepName: p.name, Error: err})
 			return
 		}
+		for _, name := range j.contributors() {
+			event.Emit(ch, Event{Type: event.RouteSelected, StepName: p.name, RouteName: name})
+		}
 
 		// Aggregate
 		if p.aggregator != nil {
-			if err := p.aggregator(state, branches, errors); err != nil {
+			if err := p.aggregator(state, j.branches, j.errs); err != nil {
 				event.Emit(ch, Event{Type: event.RunError, StepName: p.name, Error: err})
 				return
 			}
@@ -0,0 +1,126 @@
+package patterns
+
+import (
+	"context"
+	"fmt"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/chat"
+	"github.com/spetersoncode/gains/workflow"
+)
+
+// DefaultWriterPrompt is the system prompt for drafting and revising.
+const DefaultWriterPrompt = "You are a skilled writer. Complete the task. When " +
+	"given a previous draft and reviewer feedback, revise the draft to address " +
+	"the feedback. Reply with the draft only."
+
+// DefaultCriticPrompt is the system prompt for reviewing a draft.
+const DefaultCriticPrompt = "You are a demanding reviewer. Decide whether the " +
+	"draft fully and correctly completes the task. If it does not, give " +
+	"specific, actionable feedback."
+
+// Critique is a critic's verdict on a draft.
+type Critique struct {
+	// Approved reports whether the draft is acceptable as is.
+	Approved bool `json:"approved"`
+	// Feedback explains what to improve. Empty when approved.
+	Feedback string `json:"feedback"`
+}
+
+// CriticConfig configures NewCriticLoop.
+type CriticConfig[S any] struct {
+	// Task returns the task to complete. Required.
+	Task func(*S) string
+
+	// Draft returns where the current draft is stored. Required.
+	Draft func(*S) *string
+
+	// Critique returns where the latest critique is stored. Required.
+	Critique func(*S) *Critique
+
+	// MaxRounds limits draft-and-critique rounds. Default is 3.
+	MaxRounds int
+
+	// WriterPrompt overrides DefaultWriterPrompt.
+	WriterPrompt string
+
+	// CriticPrompt overrides DefaultCriticPrompt.
+	CriticPrompt string
+
+	// WriterOptions are applied to drafting calls.
+	WriterOptions []ai.Option
+
+	// CriticOptions are applied to critique calls, e.g. to use a different
+	// model for review.
+	CriticOptions []ai.Option
+}
+
+// NewCriticLoop creates a loop that drafts a response to Task, asks a
+// critic to review it, and revises with the critic's feedback until the
+// critic approves or MaxRounds is reached. Reaching MaxRounds is not an
+// error: the last draft is kept, and Critique.Approved reports whether it
+// was accepted.
+func NewCriticLoop[S any](name string, c chat.Client, cfg CriticConfig[S]) *workflow.Loop[S] {
+	writerPrompt := cfg.WriterPrompt
+	if writerPrompt == "" {
+		writerPrompt = DefaultWriterPrompt
+	}
+	criticPrompt := cfg.CriticPrompt
+	if criticPrompt == "" {
+		criticPrompt = DefaultCriticPrompt
+	}
+	maxRounds := cfg.MaxRounds
+	if maxRounds <= 0 {
+		maxRounds = 3
+	}
+
+	draft := workflow.NewFuncStep(name+"/draft", func(ctx context.Context, s *S) error {
+		if cfg.Task == nil || cfg.Draft == nil || cfg.Critique == nil {
+			return fmt.Errorf("patterns: critic loop %q requires Task, Draft, and Critique accessors", name)
+		}
+		prompt := "Task:\n" + cfg.Task(s)
+		if prev := *cfg.Draft(s); prev != "" {
+			prompt += "\n\nPrevious draft:\n" + prev
+			if feedback := cfg.Critique(s).Feedback; feedback != "" {
+				prompt += "\n\nReviewer feedback:\n" + feedback
+			}
+		}
+		resp, err := c.Chat(ctx, []ai.Message{
+			{Role: ai.RoleSystem, Content: writerPrompt},
+			{Role: ai.RoleUser, Content: prompt},
+		}, cfg.WriterOptions...)
+		if err != nil {
+			return err
+		}
+		*cfg.Draft(s) = resp.Content
+		return nil
+	})
+
+	critique := workflow.NewPromptStep(name+"/critique", c,
+		func(s *S) []ai.Message {
+			return []ai.Message{
+				{Role: ai.RoleSystem, Content: criticPrompt},
+				{Role: ai.RoleUser, Content: "Task:\n" + cfg.Task(s) + "\n\nDraft:\n" + *cfg.Draft(s)},
+			}
+		},
+		critiqueSchema(),
+		func(s *S) *Critique { return cfg.Critique(s) },
+		cfg.CriticOptions...,
+	)
+
+	round := workflow.NewChain[S](name+"/round", draft, critique)
+	return workflow.NewLoopWithExitCondition[S](name, round,
+		func(_ context.Context, s *S, iteration int) bool {
+			return cfg.Critique(s).Approved || iteration >= maxRounds
+		},
+		workflow.WithMaxIterations(maxRounds),
+	)
+}
+
+func critiqueSchema() *ai.ResponseSchema {
+	return &ai.ResponseSchema{
+		Name:        "critique",
+		Description: "Review verdict for the draft",
+		Schema:      ai.MustSchemaFor[Critique](),
+	}
+}
@@ -0,0 +1,63 @@
+package patterns
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type essayState struct {
+	Task     string
+	Draft    string
+	Critique Critique
+}
+
+func criticConfig() CriticConfig[essayState] {
+	return CriticConfig[essayState]{
+		Task:     func(s *essayState) string { return s.Task },
+		Draft:    func(s *essayState) *string { return &s.Draft },
+		Critique: func(s *essayState) *Critique { return &s.Critique },
+	}
+}
+
+func TestCriticLoop(t *testing.T) {
+	c := &mockClient{rules: []mockRule{
+		// Critic rules match the draft at the end of the critique prompt.
+		{contains: "Draft:\nfirst draft", reply: `{"approved":false,"feedback":"add an example"}`},
+		{contains: "Draft:\nsecond draft", reply: `{"approved":true,"feedback":""}`},
+		// Writer rules.
+		{contains: "add an example", reply: "second draft"},
+		{contains: "Task:", reply: "first draft"},
+	}}
+
+	state := &essayState{Task: "Explain generics"}
+	err := NewCriticLoop("essay", c, criticConfig()).Run(context.Background(), state)
+
+	require.NoError(t, err)
+	assert.Equal(t, "second draft", state.Draft)
+	assert.True(t, state.Critique.Approved)
+	assert.Equal(t, 4, c.callCount())
+
+	revision := c.calls[2][1].Content
+	assert.Contains(t, revision, "Previous draft:\nfirst draft")
+	assert.Contains(t, revision, "Reviewer feedback:\nadd an example")
+}
+
+func TestCriticLoop_MaxRounds(t *testing.T) {
+	c := &mockClient{rules: []mockRule{
+		{contains: "Draft:", reply: `{"approved":false,"feedback":"try again"}`},
+		{contains: "Task:", reply: "a draft"},
+	}}
+	cfg := criticConfig()
+	cfg.MaxRounds = 2
+
+	state := &essayState{Task: "Explain generics"}
+	err := NewCriticLoop("essay", c, cfg).Run(context.Background(), state)
+
+	require.NoError(t, err)
+	assert.Equal(t, "a draft", state.Draft)
+	assert.False(t, state.Critique.Approved)
+	assert.Equal(t, 4, c.callCount())
+}
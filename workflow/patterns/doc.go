@@ -0,0 +1,38 @@
+// Package patterns provides ready-made workflow templates built from the
+// workflow package's steps.
+//
+// Each template is configured with state accessors, functions that read
+// inputs from and return pointers to fields of your state struct, so it
+// plugs into any state type. Templates return ordinary workflow steps that
+// can be run directly or nested in larger workflows.
+//
+// # Templates
+//
+//   - [NewResearchAndSummarize]: Research a topic from several angles concurrently, then summarize
+//   - [NewExtractTransformLoad]: Extract structured records from documents, transform, and load them
+//   - [NewTriageRouter]: Classify input into a category and run that category's step
+//   - [NewCriticLoop]: Draft, critique, and revise until a critic approves
+//
+// # Basic Usage
+//
+//	type ReportState struct {
+//	    Topic    string
+//	    Findings []string
+//	    Summary  string
+//	}
+//
+//	step := patterns.NewResearchAndSummarize("report", client, patterns.ResearchConfig[ReportState]{
+//	    Topic:    func(s *ReportState) string { return s.Topic },
+//	    Angles:   []string{"technical", "market", "regulatory"},
+//	    Findings: func(s *ReportState) *[]string { return &s.Findings },
+//	    Summary:  func(s *ReportState) *string { return &s.Summary },
+//	})
+//
+//	state := &ReportState{Topic: "solid-state batteries"}
+//	_, err := workflow.New("report", step).Run(ctx, state)
+//
+// # Prompts
+//
+// Every template has default system prompts that can be overridden in its
+// config, and accepts chat options applied to all of its model calls.
+package patterns
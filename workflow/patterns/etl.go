@@ -0,0 +1,117 @@
+package patterns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/chat"
+	"github.com/spetersoncode/gains/workflow"
+)
+
+// DefaultExtractPrompt is the system prompt for extracting a record from a
+// document.
+const DefaultExtractPrompt = "You extract structured data from documents. " +
+	"Return only the fields requested by the schema, using values stated in " +
+	"the document. Leave a field empty if the document does not provide it."
+
+// ETLConfig configures NewExtractTransformLoad. S is the workflow state and
+// T the record type extracted from each document; T must be a struct that
+// the schema generator supports.
+type ETLConfig[S, T any] struct {
+	// Documents returns the documents to extract from. Required.
+	Documents func(*S) []string
+
+	// Records returns where the extracted, transformed records are stored,
+	// one per document in document order. Required.
+	Records func(*S) *[]T
+
+	// Instructions describe what to extract, e.g. "the invoice number,
+	// vendor, and total". Sent with every document.
+	Instructions string
+
+	// Transform optionally validates or normalizes each extracted record.
+	// An error fails the extraction.
+	Transform func(ctx context.Context, record T) (T, error)
+
+	// Load optionally delivers the records, e.g. writing them to a database.
+	// It runs after all documents are extracted.
+	Load func(ctx context.Context, state *S, records []T) error
+
+	// Concurrency limits concurrent extraction calls. 0 means no limit.
+	Concurrency int
+
+	// ExtractPrompt overrides DefaultExtractPrompt.
+	ExtractPrompt string
+
+	// ChatOptions are applied to every model call.
+	ChatOptions []ai.Option
+}
+
+// NewExtractTransformLoad creates a two-step chain. The first step,
+// name+"/extract", extracts a T from every document with structured output,
+// applies Transform, and stores the results in Records. The second,
+// name+"/load", passes the records to Load.
+func NewExtractTransformLoad[S, T any](name string, c chat.Client, cfg ETLConfig[S, T]) *workflow.Chain[S] {
+	extractPrompt := cfg.ExtractPrompt
+	if extractPrompt == "" {
+		extractPrompt = DefaultExtractPrompt
+	}
+
+	extract := workflow.NewFuncStep(name+"/extract", func(ctx context.Context, s *S) error {
+		if cfg.Documents == nil || cfg.Records == nil {
+			return fmt.Errorf("patterns: etl %q requires Documents and Records accessors", name)
+		}
+		schema, err := ai.SchemaFor[T]()
+		if err != nil {
+			return err
+		}
+		opts := append(append([]ai.Option{}, cfg.ChatOptions...), ai.WithResponseSchema(ai.ResponseSchema{
+			Name:        "record",
+			Description: "Data extracted from the document",
+			Schema:      schema,
+		}))
+
+		records, err := forEach(ctx, cfg.Documents(s), cfg.Concurrency, func(ctx context.Context, doc string) (T, error) {
+			var record T
+			prompt := doc
+			if cfg.Instructions != "" {
+				prompt = "Extract " + cfg.Instructions + "\n\nDocument:\n" + doc
+			}
+			resp, err := c.Chat(ctx, []ai.Message{
+				{Role: ai.RoleSystem, Content: extractPrompt},
+				{Role: ai.RoleUser, Content: prompt},
+			}, opts...)
+			if err != nil {
+				return record, err
+			}
+			if err := json.Unmarshal([]byte(resp.Content), &record); err != nil {
+				return record, &ai.UnmarshalError{
+					Context:    fmt.Sprintf("etl %q", name),
+					Content:    resp.Content,
+					TargetType: fmt.Sprintf("%T", record),
+					Err:        err,
+				}
+			}
+			if cfg.Transform != nil {
+				return cfg.Transform(ctx, record)
+			}
+			return record, nil
+		})
+		if err != nil {
+			return err
+		}
+		*cfg.Records(s) = records
+		return nil
+	})
+
+	load := workflow.NewFuncStep(name+"/load", func(ctx context.Context, s *S) error {
+		if cfg.Load == nil {
+			return nil
+		}
+		return cfg.Load(ctx, s, *cfg.Records(s))
+	})
+
+	return workflow.NewChain[S](name, extract, load)
+}
@@ -0,0 +1,87 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type invoice struct {
+	Vendor string  `json:"vendor"`
+	Total  float64 `json:"total"`
+}
+
+type etlState struct {
+	Docs     []string
+	Invoices []invoice
+}
+
+func etlConfig() ETLConfig[etlState, invoice] {
+	return ETLConfig[etlState, invoice]{
+		Documents:    func(s *etlState) []string { return s.Docs },
+		Records:      func(s *etlState) *[]invoice { return &s.Invoices },
+		Instructions: "the vendor and total",
+	}
+}
+
+func TestExtractTransformLoad(t *testing.T) {
+	c := &mockClient{rules: []mockRule{
+		{contains: "ACME", reply: `{"vendor":"acme","total":10}`},
+		{contains: "Globex", reply: `{"vendor":"globex","total":20}`},
+	}}
+
+	var loaded []invoice
+	cfg := etlConfig()
+	cfg.Transform = func(_ context.Context, r invoice) (invoice, error) {
+		r.Vendor = strings.ToUpper(r.Vendor)
+		return r, nil
+	}
+	cfg.Load = func(_ context.Context, _ *etlState, records []invoice) error {
+		loaded = records
+		return nil
+	}
+
+	state := &etlState{Docs: []string{"Invoice from ACME", "Invoice from Globex"}}
+	err := NewExtractTransformLoad("invoices", c, cfg).Run(context.Background(), state)
+
+	require.NoError(t, err)
+	want := []invoice{{Vendor: "ACME", Total: 10}, {Vendor: "GLOBEX", Total: 20}}
+	assert.Equal(t, want, state.Invoices)
+	assert.Equal(t, want, loaded)
+	assert.Contains(t, c.calls[0][1].Content, "Extract the vendor and total")
+}
+
+func TestExtractTransformLoad_InvalidJSON(t *testing.T) {
+	c := &mockClient{rules: []mockRule{{contains: "ACME", reply: "not json"}}}
+
+	state := &etlState{Docs: []string{"Invoice from ACME"}}
+	err := NewExtractTransformLoad("invoices", c, etlConfig()).Run(context.Background(), state)
+
+	var unmarshalErr *ai.UnmarshalError
+	assert.ErrorAs(t, err, &unmarshalErr)
+	assert.Empty(t, state.Invoices)
+}
+
+func TestExtractTransformLoad_TransformError(t *testing.T) {
+	c := &mockClient{rules: []mockRule{{contains: "ACME", reply: `{"vendor":"acme","total":-1}`}}}
+	invalid := errors.New("negative total")
+	cfg := etlConfig()
+	cfg.Transform = func(_ context.Context, r invoice) (invoice, error) {
+		if r.Total < 0 {
+			return r, invalid
+		}
+		return r, nil
+	}
+	cfg.Load = func(context.Context, *etlState, []invoice) error {
+		t.Fatal("load should not run")
+		return nil
+	}
+
+	err := NewExtractTransformLoad("invoices", c, cfg).Run(context.Background(), &etlState{Docs: []string{"ACME"}})
+	assert.ErrorIs(t, err, invalid)
+}
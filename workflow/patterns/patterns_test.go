@@ -0,0 +1,116 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockClient answers each call with the reply of the first rule whose key
+// appears in the last message. It is safe for concurrent use.
+type mockClient struct {
+	mu    sync.Mutex
+	rules []mockRule
+	calls [][]ai.Message
+}
+
+type mockRule struct {
+	contains string
+	reply    string
+	err      error
+}
+
+func (m *mockClient) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, messages)
+	m.mu.Unlock()
+
+	last := messages[len(messages)-1].Content
+	for _, r := range m.rules {
+		if strings.Contains(last, r.contains) {
+			if r.err != nil {
+				return nil, r.err
+			}
+			return &ai.Response{Content: r.reply}, nil
+		}
+	}
+	return &ai.Response{Content: "unexpected prompt"}, nil
+}
+
+func (m *mockClient) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+	resp, err := m.Chat(ctx, messages, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ch := make(chan event.Event, 3)
+	ch <- event.Event{Type: event.MessageStart}
+	ch <- event.Event{Type: event.MessageDelta, Delta: resp.Content}
+	ch <- event.Event{Type: event.MessageEnd, Response: resp}
+	close(ch)
+	return ch, nil
+}
+
+func (m *mockClient) callCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.calls)
+}
+
+func TestForEach(t *testing.T) {
+	t.Run("preserves order", func(t *testing.T) {
+		got, err := forEach(context.Background(), []int{1, 2, 3, 4}, 2, func(_ context.Context, n int) (int, error) {
+			return n * n, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 4, 9, 16}, got)
+	})
+
+	t.Run("returns first error", func(t *testing.T) {
+		boom := errors.New("boom")
+		_, err := forEach(context.Background(), []int{1, 2, 3}, 0, func(_ context.Context, n int) (int, error) {
+			if n == 2 {
+				return 0, boom
+			}
+			return n, nil
+		})
+		assert.ErrorIs(t, err, boom)
+	})
+
+	t.Run("respects limit", func(t *testing.T) {
+		var mu sync.Mutex
+		active, peak := 0, 0
+		_, err := forEach(context.Background(), make([]int, 10), 3, func(_ context.Context, _ int) (int, error) {
+			mu.Lock()
+			active++
+			peak = max(peak, active)
+			mu.Unlock()
+			defer func() {
+				mu.Lock()
+				active--
+				mu.Unlock()
+			}()
+			return 0, nil
+		})
+		require.NoError(t, err)
+		assert.LessOrEqual(t, peak, 3)
+	})
+
+	t.Run("recovers panics", func(t *testing.T) {
+		_, err := forEach(context.Background(), []int{1, 2}, 0, func(_ context.Context, n int) (int, error) {
+			if n == 2 {
+				panic("boom")
+			}
+			return n, nil
+		})
+		var panicErr *ai.PanicError
+		require.ErrorAs(t, err, &panicErr)
+		assert.Equal(t, "boom", panicErr.Value)
+	})
+}
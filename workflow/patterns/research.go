@@ -0,0 +1,137 @@
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/chat"
+	"github.com/spetersoncode/gains/internal/group"
+	"github.com/spetersoncode/gains/workflow"
+)
+
+// DefaultResearchPrompt is the system prompt for each research pass.
+const DefaultResearchPrompt = "You are a meticulous researcher. Report the key facts, " +
+	"open questions, and notable sources on the topic from the requested angle. " +
+	"Be concise and specific."
+
+// DefaultSummaryPrompt is the system prompt for the summary step.
+const DefaultSummaryPrompt = "You are an expert editor. Combine the research notes " +
+	"into a single clear, well-organized summary. Resolve overlaps and note " +
+	"any disagreements between the notes."
+
+// ResearchConfig configures NewResearchAndSummarize.
+type ResearchConfig[S any] struct {
+	// Topic returns the topic to research. Required.
+	Topic func(*S) string
+
+	// Angles are perspectives researched concurrently, one model call each.
+	// If empty, the topic is researched in a single general pass.
+	Angles []string
+
+	// Findings returns where the notes from each angle are stored, in the
+	// order of Angles. Required.
+	Findings func(*S) *[]string
+
+	// Summary returns where the final summary is stored. Required.
+	Summary func(*S) *string
+
+	// ResearchPrompt overrides DefaultResearchPrompt.
+	ResearchPrompt string
+
+	// SummaryPrompt overrides DefaultSummaryPrompt.
+	SummaryPrompt string
+
+	// ChatOptions are applied to every model call.
+	ChatOptions []ai.Option
+}
+
+// NewResearchAndSummarize creates a two-step chain that researches a topic
+// from each configured angle concurrently, stores the notes in Findings,
+// then summarizes them into Summary. The steps are named name+"/research"
+// and name+"/summarize".
+func NewResearchAndSummarize[S any](name string, c chat.Client, cfg ResearchConfig[S]) *workflow.Chain[S] {
+	researchPrompt := cfg.ResearchPrompt
+	if researchPrompt == "" {
+		researchPrompt = DefaultResearchPrompt
+	}
+	summaryPrompt := cfg.SummaryPrompt
+	if summaryPrompt == "" {
+		summaryPrompt = DefaultSummaryPrompt
+	}
+	angles := cfg.Angles
+	if len(angles) == 0 {
+		angles = []string{"general overview"}
+	}
+
+	research := workflow.NewFuncStep(name+"/research", func(ctx context.Context, s *S) error {
+		if cfg.Topic == nil || cfg.Findings == nil || cfg.Summary == nil {
+			return fmt.Errorf("patterns: research %q requires Topic, Findings, and Summary accessors", name)
+		}
+		topic := cfg.Topic(s)
+		notes, err := forEach(ctx, angles, 0, func(ctx context.Context, angle string) (string, error) {
+			resp, err := c.Chat(ctx, []ai.Message{
+				{Role: ai.RoleSystem, Content: researchPrompt},
+				{Role: ai.RoleUser, Content: fmt.Sprintf("Topic: %s\nAngle: %s", topic, angle)},
+			}, cfg.ChatOptions...)
+			if err != nil {
+				return "", fmt.Errorf("angle %q: %w", angle, err)
+			}
+			return resp.Content, nil
+		})
+		if err != nil {
+			return err
+		}
+		*cfg.Findings(s) = notes
+		return nil
+	})
+
+	summarize := workflow.NewPromptStep(name+"/summarize", c,
+		func(s *S) []ai.Message {
+			var b strings.Builder
+			fmt.Fprintf(&b, "Topic: %s\n", cfg.Topic(s))
+			for i, note := range *cfg.Findings(s) {
+				fmt.Fprintf(&b, "\n## Notes: %s\n%s\n", angles[i], note)
+			}
+			return []ai.Message{
+				{Role: ai.RoleSystem, Content: summaryPrompt},
+				{Role: ai.RoleUser, Content: b.String()},
+			}
+		},
+		nil,
+		func(s *S) *string { return cfg.Summary(s) },
+		cfg.ChatOptions...,
+	)
+
+	return workflow.NewChain[S](name, research, summarize)
+}
+
+// forEach calls fn for every item concurrently, running at most limit calls
+// at once when limit > 0, and returns the results in input order. The first
+// error, including a recovered panic, cancels the remaining calls and is
+// returned.
+func forEach[T, R any](ctx context.Context, items []T, limit int, fn func(context.Context, T) (R, error)) ([]R, error) {
+	g, gctx := group.WithContext(ctx)
+	g.SetLimit(limit)
+	g.SetLimiter(ai.ContextConcurrencyLimiter(ctx))
+
+	results := make([]R, len(items))
+	for i, item := range items {
+		g.Go(func() error {
+			if err := gctx.Err(); err != nil {
+				return err
+			}
+			r, err := fn(gctx, item)
+			if err != nil {
+				return err
+			}
+			results[i] = r
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
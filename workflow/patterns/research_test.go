@@ -0,0 +1,83 @@
+package patterns
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spetersoncode/gains/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type researchState struct {
+	Topic    string
+	Findings []string
+	Summary  string
+}
+
+func researchConfig() ResearchConfig[researchState] {
+	return ResearchConfig[researchState]{
+		Topic:    func(s *researchState) string { return s.Topic },
+		Angles:   []string{"technical", "market"},
+		Findings: func(s *researchState) *[]string { return &s.Findings },
+		Summary:  func(s *researchState) *string { return &s.Summary },
+	}
+}
+
+func TestResearchAndSummarize(t *testing.T) {
+	c := &mockClient{rules: []mockRule{
+		{contains: "Angle: technical", reply: "tech notes"},
+		{contains: "Angle: market", reply: "market notes"},
+		{contains: "## Notes", reply: "the summary"},
+	}}
+
+	step := NewResearchAndSummarize("report", c, researchConfig())
+	state := &researchState{Topic: "batteries"}
+	_, err := workflow.New("wf", step).Run(context.Background(), state)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tech notes", "market notes"}, state.Findings)
+	assert.Equal(t, "the summary", state.Summary)
+	assert.Equal(t, 3, c.callCount())
+
+	summaryPrompt := c.calls[2][1].Content
+	assert.Contains(t, summaryPrompt, "## Notes: technical\ntech notes")
+	assert.Contains(t, summaryPrompt, "## Notes: market\nmarket notes")
+}
+
+func TestResearchAndSummarize_DefaultAngle(t *testing.T) {
+	c := &mockClient{rules: []mockRule{
+		{contains: "Angle: general overview", reply: "notes"},
+		{contains: "## Notes", reply: "summary"},
+	}}
+	cfg := researchConfig()
+	cfg.Angles = nil
+
+	state := &researchState{Topic: "batteries"}
+	err := NewResearchAndSummarize("report", c, cfg).Run(context.Background(), state)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"notes"}, state.Findings)
+	assert.Equal(t, "summary", state.Summary)
+}
+
+func TestResearchAndSummarize_Error(t *testing.T) {
+	boom := errors.New("boom")
+	c := &mockClient{rules: []mockRule{
+		{contains: "Angle: market", err: boom},
+		{contains: "Angle:", reply: "notes"},
+	}}
+
+	state := &researchState{Topic: "batteries"}
+	err := NewResearchAndSummarize("report", c, researchConfig()).Run(context.Background(), state)
+
+	assert.ErrorIs(t, err, boom)
+	assert.Empty(t, state.Summary)
+}
+
+func TestResearchAndSummarize_MissingAccessors(t *testing.T) {
+	err := NewResearchAndSummarize("report", &mockClient{}, ResearchConfig[researchState]{}).
+		Run(context.Background(), &researchState{})
+	assert.ErrorContains(t, err, "requires Topic, Findings, and Summary")
+}
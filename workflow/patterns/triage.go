@@ -0,0 +1,129 @@
+package patterns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/chat"
+	"github.com/spetersoncode/gains/workflow"
+)
+
+// DefaultTriagePrompt is the system prompt for classifying input. The
+// category list is appended to it.
+const DefaultTriagePrompt = "You triage incoming requests. Choose the single " +
+	"category that best fits the request."
+
+// TriageCategory is a category the triage router can choose.
+type TriageCategory[S any] struct {
+	// Name identifies the category and is what the model returns.
+	Name string
+
+	// Description tells the model what belongs in the category.
+	Description string
+
+	// Step handles input in this category.
+	Step workflow.Step[S]
+}
+
+// TriageConfig configures NewTriageRouter.
+type TriageConfig[S any] struct {
+	// Input returns the text to classify. Required.
+	Input func(*S) string
+
+	// Category returns where the chosen category name is stored. Required.
+	Category func(*S) *string
+
+	// Categories are the categories to choose from. Required.
+	Categories []TriageCategory[S]
+
+	// Fallback handles input whose classification matches no category.
+	// If nil, an unmatched classification fails with
+	// workflow.ErrNoRouteMatched.
+	Fallback workflow.Step[S]
+
+	// TriagePrompt overrides DefaultTriagePrompt.
+	TriagePrompt string
+
+	// ChatOptions are applied to the classification call.
+	ChatOptions []ai.Option
+}
+
+// triageResult is the structured classification output.
+type triageResult struct {
+	Category string `json:"category"`
+}
+
+// NewTriageRouter creates a two-step chain. The first step,
+// name+"/classify", asks the model to pick a category using structured
+// output and stores its name in Category. The second, name+"/route", runs
+// the chosen category's step, or Fallback.
+func NewTriageRouter[S any](name string, c chat.Client, cfg TriageConfig[S]) *workflow.Chain[S] {
+	triagePrompt := cfg.TriagePrompt
+	if triagePrompt == "" {
+		triagePrompt = DefaultTriagePrompt
+	}
+
+	names := make([]any, len(cfg.Categories))
+	var b strings.Builder
+	b.WriteString(triagePrompt)
+	b.WriteString("\n\nCategories:\n")
+	for i, cat := range cfg.Categories {
+		names[i] = cat.Name
+		fmt.Fprintf(&b, "- %s: %s\n", cat.Name, cat.Description)
+	}
+	system := b.String()
+	schema, _ := json.Marshal(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"category": map[string]any{"type": "string", "enum": names},
+		},
+		"required":             []string{"category"},
+		"additionalProperties": false,
+	})
+	opts := append(append([]ai.Option{}, cfg.ChatOptions...), ai.WithResponseSchema(ai.ResponseSchema{
+		Name:        "triage",
+		Description: "The chosen category",
+		Schema:      schema,
+	}))
+
+	classify := workflow.NewFuncStep(name+"/classify", func(ctx context.Context, s *S) error {
+		if cfg.Input == nil || cfg.Category == nil {
+			return fmt.Errorf("patterns: triage %q requires Input and Category accessors", name)
+		}
+		resp, err := c.Chat(ctx, []ai.Message{
+			{Role: ai.RoleSystem, Content: system},
+			{Role: ai.RoleUser, Content: cfg.Input(s)},
+		}, opts...)
+		if err != nil {
+			return err
+		}
+		var result triageResult
+		if err := json.Unmarshal([]byte(resp.Content), &result); err != nil {
+			return &ai.UnmarshalError{
+				Context:    fmt.Sprintf("triage %q", name),
+				Content:    resp.Content,
+				TargetType: "category",
+				Err:        err,
+			}
+		}
+		*cfg.Category(s) = strings.TrimSpace(result.Category)
+		return nil
+	})
+
+	routes := make([]workflow.Route[S], len(cfg.Categories))
+	for i, cat := range cfg.Categories {
+		routes[i] = workflow.Route[S]{
+			Name: cat.Name,
+			Condition: func(_ context.Context, s *S) bool {
+				return strings.EqualFold(*cfg.Category(s), cat.Name)
+			},
+			Step: cat.Step,
+		}
+	}
+	route := workflow.NewRouter(name+"/route", routes, cfg.Fallback)
+
+	return workflow.NewChain[S](name, classify, route)
+}
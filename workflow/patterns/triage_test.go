@@ -0,0 +1,68 @@
+package patterns
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spetersoncode/gains/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ticketState struct {
+	Ticket    string
+	Category  string
+	HandledBy string
+}
+
+func handler(name string) workflow.Step[ticketState] {
+	return workflow.NewFuncStep(name, func(_ context.Context, s *ticketState) error {
+		s.HandledBy = name
+		return nil
+	})
+}
+
+func triageConfig() TriageConfig[ticketState] {
+	return TriageConfig[ticketState]{
+		Input:    func(s *ticketState) string { return s.Ticket },
+		Category: func(s *ticketState) *string { return &s.Category },
+		Categories: []TriageCategory[ticketState]{
+			{Name: "billing", Description: "Payments and invoices", Step: handler("billing-team")},
+			{Name: "bug", Description: "Something is broken", Step: handler("engineering")},
+		},
+	}
+}
+
+func TestTriageRouter(t *testing.T) {
+	c := &mockClient{rules: []mockRule{
+		{contains: "charged twice", reply: `{"category":"billing"}`},
+		{contains: "crashes", reply: `{"category":"BUG"}`},
+	}}
+	router := NewTriageRouter("triage", c, triageConfig())
+
+	state := &ticketState{Ticket: "I was charged twice"}
+	require.NoError(t, router.Run(context.Background(), state))
+	assert.Equal(t, "billing", state.Category)
+	assert.Equal(t, "billing-team", state.HandledBy)
+
+	state = &ticketState{Ticket: "The app crashes on launch"}
+	require.NoError(t, router.Run(context.Background(), state))
+	assert.Equal(t, "engineering", state.HandledBy)
+
+	system := c.calls[0][0].Content
+	assert.Contains(t, system, "- billing: Payments and invoices")
+	assert.Contains(t, system, "- bug: Something is broken")
+}
+
+func TestTriageRouter_Fallback(t *testing.T) {
+	c := &mockClient{rules: []mockRule{{contains: "hello", reply: `{"category":"other"}`}}}
+
+	cfg := triageConfig()
+	err := NewTriageRouter("triage", c, cfg).Run(context.Background(), &ticketState{Ticket: "hello"})
+	assert.ErrorIs(t, err, workflow.ErrNoRouteMatched)
+
+	cfg.Fallback = handler("front-desk")
+	state := &ticketState{Ticket: "hello"}
+	require.NoError(t, NewTriageRouter("triage", c, cfg).Run(context.Background(), state))
+	assert.Equal(t, "front-desk", state.HandledBy)
+}
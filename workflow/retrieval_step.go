@@ -0,0 +1,162 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/vectorstore"
+)
+
+// RetrievalOption configures a RetrievalStep.
+type RetrievalOption func(*retrievalConfig)
+
+type retrievalConfig struct {
+	topK          int
+	queryOpts     []vectorstore.QueryOption
+	embeddingOpts []ai.EmbeddingOption
+}
+
+// WithTopK sets the maximum number of documents retrieved.
+// Default is 4.
+func WithTopK(k int) RetrievalOption {
+	return func(c *retrievalConfig) {
+		c.topK = k
+	}
+}
+
+// WithRetrievalQueryOptions sets options for the vector store query, such
+// as vectorstore.WithFilter and vectorstore.WithMinScore.
+func WithRetrievalQueryOptions(opts ...vectorstore.QueryOption) RetrievalOption {
+	return func(c *retrievalConfig) {
+		c.queryOpts = append(c.queryOpts, opts...)
+	}
+}
+
+// WithRetrievalEmbeddingOptions sets options for embedding the query, such
+// as the embedding model. The task type defaults to
+// ai.EmbeddingTaskTypeRetrievalQuery.
+func WithRetrievalEmbeddingOptions(opts ...ai.EmbeddingOption) RetrievalOption {
+	return func(c *retrievalConfig) {
+		c.embeddingOpts = append(c.embeddingOpts, opts...)
+	}
+}
+
+// RetrievalStep embeds a query built from state, retrieves the most similar
+// documents from a vector store, and stores them in state for later steps,
+// typically a PromptStep that includes them in its prompt.
+type RetrievalStep[S any] struct {
+	name     string
+	embedder ai.EmbeddingProvider
+	store    vectorstore.Store
+	query    func(*S) string
+	setter   func(*S, []vectorstore.Match)
+	config   retrievalConfig
+}
+
+// NewRetrievalStep creates a step that retrieves documents for a query.
+// The setter receives the matches ordered by descending score. An empty
+// query retrieves nothing and passes no matches to the setter.
+//
+// Parameters:
+//   - name: Unique identifier for the step
+//   - embedder: Provider that embeds the query, such as a client.Client
+//   - store: Vector store to search
+//   - query: Function that builds the query text from state
+//   - setter: Function that stores the matches in state
+//
+// Example:
+//
+//	retrieve := NewRetrievalStep("retrieve", c, store,
+//	    func(s *QAState) string { return s.Question },
+//	    func(s *QAState, matches []vectorstore.Match) { s.Context = matches },
+//	    WithTopK(5),
+//	)
+func NewRetrievalStep[S any](
+	name string,
+	embedder ai.EmbeddingProvider,
+	store vectorstore.Store,
+	query func(*S) string,
+	setter func(*S, []vectorstore.Match),
+	opts ...RetrievalOption,
+) *RetrievalStep[S] {
+	config := retrievalConfig{topK: 4}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return &RetrievalStep[S]{
+		name:     name,
+		embedder: embedder,
+		store:    store,
+		query:    query,
+		setter:   setter,
+		config:   config,
+	}
+}
+
+// Name returns the step name.
+func (r *RetrievalStep[S]) Name() string { return r.name }
+
+// DescribeStep describes the step for Workflow.Describe.
+func (r *RetrievalStep[S]) DescribeStep() StepInfo { return StepInfo{Kind: KindRetrieval} }
+
+// Run embeds the query and retrieves the matching documents. In a dry run
+// nothing is embedded or retrieved.
+func (r *RetrievalStep[S]) Run(ctx context.Context, state *S, opts ...Option) error {
+	options := ApplyOptions(opts...)
+
+	if options.StepTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.StepTimeout)
+		defer cancel()
+	}
+
+	query := r.query(state)
+	if query == "" || dryRun(ctx, options) {
+		if r.setter != nil {
+			r.setter(state, nil)
+		}
+		return nil
+	}
+
+	embeddingOpts := append([]ai.EmbeddingOption{ai.WithEmbeddingTaskType(ai.EmbeddingTaskTypeRetrievalQuery)}, r.config.embeddingOpts...)
+	resp, err := r.embedder.Embed(ctx, []string{query}, embeddingOpts...)
+	if err != nil {
+		return &StepError{StepName: r.name, Err: fmt.Errorf("embedding query: %w", err)}
+	}
+	if len(resp.Embeddings) == 0 {
+		return &StepError{StepName: r.name, Err: errors.New("embedding query: no embedding returned")}
+	}
+
+	matches, err := r.store.Query(ctx, resp.Embeddings[0], r.config.topK, r.config.queryOpts...)
+	if err != nil {
+		return &StepError{StepName: r.name, Err: fmt.Errorf("querying store: %w", err)}
+	}
+
+	if r.setter != nil {
+		r.setter(state, matches)
+	}
+	return nil
+}
+
+// RunStream retrieves the documents and emits events.
+func (r *RetrievalStep[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-chan Event {
+	ch := make(chan Event, 10)
+
+	go func() {
+		defer close(ch)
+		defer recoverStream(ch, r.name)
+		event.Emit(ch, Event{Type: event.StepStart, StepName: r.name})
+
+		if err := r.Run(ctx, state, opts...); err != nil {
+			event.Emit(ch, Event{Type: event.RunError, StepName: r.name, Error: err})
+			return
+		}
+
+		event.Emit(ch, Event{Type: event.StepEnd, StepName: r.name})
+	}()
+
+	return ch
+}
@@ -0,0 +1,128 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/vectorstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEmbedder embeds texts with fixed vectors and records its calls.
+type fakeEmbedder struct {
+	vectors map[string][]float64
+	err     error
+	texts   []string
+	opts    *ai.EmbeddingOptions
+}
+
+func (f *fakeEmbedder) Embed(ctx context.Context, texts []string, opts ...ai.EmbeddingOption) (*ai.EmbeddingResponse, error) {
+	f.texts = append(f.texts, texts...)
+	f.opts = ai.ApplyEmbeddingOptions(opts...)
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := &ai.EmbeddingResponse{}
+	for _, text := range texts {
+		resp.Embeddings = append(resp.Embeddings, f.vectors[text])
+	}
+	return resp, nil
+}
+
+type ragState struct {
+	Question string
+	Sources  []vectorstore.Match
+}
+
+func ragStore(t *testing.T) *vectorstore.Memory {
+	t.Helper()
+	store := vectorstore.NewMemory()
+	require.NoError(t, store.Upsert(context.Background(),
+		vectorstore.Document{ID: "go", Content: "Go has goroutines", Vector: []float64{1, 0}, Metadata: map[string]string{"lang": "go"}},
+		vectorstore.Document{ID: "rust", Content: "Rust has ownership", Vector: []float64{0, 1}, Metadata: map[string]string{"lang": "rust"}},
+		vectorstore.Document{ID: "both", Content: "Both compile", Vector: []float64{1, 1}, Metadata: map[string]string{"lang": "go"}},
+	))
+	return store
+}
+
+func newRAGStep(embedder ai.EmbeddingProvider, store vectorstore.Store, opts ...RetrievalOption) *RetrievalStep[ragState] {
+	return NewRetrievalStep("retrieve", embedder, store,
+		func(s *ragState) string { return s.Question },
+		func(s *ragState, matches []vectorstore.Match) { s.Sources = matches },
+		opts...,
+	)
+}
+
+func TestRetrievalStep(t *testing.T) {
+	ctx := context.Background()
+	store := ragStore(t)
+	embedder := &fakeEmbedder{vectors: map[string][]float64{"concurrency?": {1, 0.1}}}
+
+	t.Run("retrieves top k", func(t *testing.T) {
+		state := &ragState{Question: "concurrency?"}
+		require.NoError(t, newRAGStep(embedder, store, WithTopK(2)).Run(ctx, state))
+		require.Len(t, state.Sources, 2)
+		assert.Equal(t, "go", state.Sources[0].ID)
+		assert.Equal(t, "both", state.Sources[1].ID)
+		assert.Equal(t, []string{"concurrency?"}, embedder.texts)
+		assert.Equal(t, ai.EmbeddingTaskTypeRetrievalQuery, embedder.opts.TaskType)
+	})
+
+	t.Run("options are passed on", func(t *testing.T) {
+		state := &ragState{Question: "concurrency?"}
+		step := newRAGStep(embedder, store,
+			WithRetrievalQueryOptions(vectorstore.WithFilter(map[string]string{"lang": "go"})),
+			WithRetrievalEmbeddingOptions(ai.WithEmbeddingTaskType(ai.EmbeddingTaskTypeSemanticSimilarity)),
+		)
+		require.NoError(t, step.Run(ctx, state))
+		require.Len(t, state.Sources, 2)
+		assert.Equal(t, ai.EmbeddingTaskTypeSemanticSimilarity, embedder.opts.TaskType)
+	})
+
+	t.Run("empty query retrieves nothing", func(t *testing.T) {
+		calls := len(embedder.texts)
+		state := &ragState{Sources: []vectorstore.Match{{}}}
+		require.NoError(t, newRAGStep(embedder, store).Run(ctx, state))
+		assert.Empty(t, state.Sources)
+		assert.Len(t, embedder.texts, calls)
+	})
+
+	t.Run("dry run retrieves nothing", func(t *testing.T) {
+		calls := len(embedder.texts)
+		state := &ragState{Question: "concurrency?"}
+		require.NoError(t, newRAGStep(embedder, store).Run(ctx, state, WithDryRun()))
+		assert.Empty(t, state.Sources)
+		assert.Len(t, embedder.texts, calls)
+	})
+
+	t.Run("embedding errors", func(t *testing.T) {
+		failing := &fakeEmbedder{err: errors.New("quota exceeded")}
+		err := newRAGStep(failing, store).Run(ctx, &ragState{Question: "q"})
+		var stepErr *StepError
+		require.ErrorAs(t, err, &stepErr)
+		assert.Equal(t, "retrieve", stepErr.StepName)
+		assert.ErrorContains(t, err, "quota exceeded")
+	})
+
+	t.Run("store errors", func(t *testing.T) {
+		err := newRAGStep(&fakeEmbedder{vectors: map[string][]float64{}}, store).Run(ctx, &ragState{Question: "q"})
+		assert.ErrorIs(t, err, vectorstore.ErrEmptyVector)
+	})
+}
+
+func TestRetrievalStep_RunStream(t *testing.T) {
+	embedder := &fakeEmbedder{vectors: map[string][]float64{"q": {0, 1}}}
+	state := &ragState{Question: "q"}
+
+	var types []event.Type
+	for ev := range newRAGStep(embedder, ragStore(t), WithTopK(1)).RunStream(context.Background(), state) {
+		types = append(types, ev.Type)
+	}
+	assert.Equal(t, []event.Type{event.StepStart, event.StepEnd}, types)
+	require.Len(t, state.Sources, 1)
+	assert.Equal(t, "rust", state.Sources[0].ID)
+}
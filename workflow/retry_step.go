@@ -2,7 +2,9 @@ package workflow
 
 import (
 	"context"
+	"fmt"
 
+	ai "github.com/spetersoncode/gains"
 	"github.com/spetersoncode/gains/event"
 	"github.com/spetersoncode/gains/internal/retry"
 )
@@ -15,17 +17,52 @@ type RetryStep[S any] struct {
 	config retry.Config
 }
 
+// RetryOption configures a RetryStep.
+type RetryOption func(*retry.Config)
+
+// WithRetryConfig sets the attempt limit and backoff schedule, replacing
+// the default of 10 attempts with exponential backoff from 1s.
+func WithRetryConfig(cfg ai.RetryConfig) RetryOption {
+	return func(c *retry.Config) {
+		c.MaxAttempts = cfg.MaxAttempts
+		c.InitialDelay = cfg.InitialDelay
+		c.MaxDelay = cfg.MaxDelay
+		c.Multiplier = cfg.Multiplier
+		c.Jitter = cfg.Jitter
+	}
+}
+
+// WithRetryIf sets the predicate deciding whether a failed attempt is
+// retried, replacing the default of retrying transient errors such as rate
+// limits, server errors, and timeouts (see ai.IsTransient). Errors a step
+// returns are wrapped, so match them with errors.Is or errors.As.
+func WithRetryIf(fn func(error) bool) RetryOption {
+	return func(c *retry.Config) {
+		c.Retryable = fn
+	}
+}
+
 // NewRetryStep creates a step that retries on transient errors.
-// Uses the default retry configuration (10 attempts, exponential backoff).
+// Uses the default retry configuration (10 attempts, exponential backoff)
+// unless options override it.
 //
 // Example:
 //
 //	step := NewRetryStep("fetch-with-retry", fetchStep)
-func NewRetryStep[S any](name string, step Step[S]) *RetryStep[S] {
+//
+//	step := NewRetryStep("validate", validateStep,
+//	    WithRetryConfig(ai.NewRetryConfig(3, 100*time.Millisecond, time.Second, 2, 0)),
+//	    WithRetryIf(func(err error) bool { return errors.Is(err, ErrInvalidOutput) }),
+//	)
+func NewRetryStep[S any](name string, step Step[S], opts ...RetryOption) *RetryStep[S] {
+	config := retry.DefaultConfig()
+	for _, opt := range opts {
+		opt(&config)
+	}
 	return &RetryStep[S]{
 		name:   name,
 		step:   step,
-		config: retry.DefaultConfig(),
+		config: config,
 	}
 }
 
@@ -52,6 +89,16 @@ func NewRetryStepWithConfig[S any](name string, step Step[S], config retry.Confi
 // Name returns the step name.
 func (r *RetryStep[S]) Name() string { return r.name }
 
+// DescribeStep describes the retry step and the step it retries for
+// Workflow.Describe.
+func (r *RetryStep[S]) DescribeStep() StepInfo {
+	info := StepInfo{Kind: KindRetry, Children: []ChildStep{{Step: r.step}}}
+	if r.config.MaxAttempts > 0 {
+		info.Detail = fmt.Sprintf("max %d attempts", r.config.MaxAttempts)
+	}
+	return info
+}
+
 // Run executes the wrapped step with retry logic.
 func (r *RetryStep[S]) Run(ctx context.Context, state *S, opts ...Option) error {
 	_, err := retry.Do(ctx, r.config, func() (struct{}, error) {
@@ -64,10 +111,11 @@ func (r *RetryStep[S]) Run(ctx context.Context, state *S, opts ...Option) error
 // RunStream executes the wrapped step with retry logic and emits events.
 // Retry events are emitted to provide observability into retry attempts.
 func (r *RetryStep[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-chan Event {
-	ch := make(chan Event, 100)
+	ch := newEventChannel(opts)
 
 	go func() {
 		defer close(ch)
+		defer recoverStream(ch, r.name)
 		event.Emit(ch, Event{Type: event.StepStart, StepName: r.name})
 
 		// Create event channel for retry observability
@@ -78,7 +126,7 @@ func (r *RetryStep[S]) RunStream(ctx context.Context, state *S, opts ...Option)
 		go func() {
 			defer close(retryEvents)
 			_, runErr = retry.DoWithEvents(ctx, r.config, retryEvents, func() (struct{}, error) {
-				err := r.step.Run(ctx, state, opts...)
+				err := callStep(r.step.Name(), func() error { return r.step.Run(ctx, state, opts...) })
 				return struct{}{}, err
 			})
 		}()
@@ -218,3 +218,62 @@ func TestRetryStep_ContextCancellation(t *testing.T) {
 		t.Errorf("expected context.Canceled, got %v", err)
 	}
 }
+
+func TestRetryStep_WithRetryIf(t *testing.T) {
+	errInvalid := errors.New("invalid output")
+	attempts := 0
+	step := NewFuncStep[retryState]("inner", func(ctx context.Context, s *retryState) error {
+		attempts++
+		if attempts < 3 {
+			return errInvalid
+		}
+		return nil
+	})
+
+	retryStep := NewRetryStep("retry", step,
+		WithRetryConfig(ai.NewRetryConfig(5, time.Millisecond, 10*time.Millisecond, 2, 0)),
+		WithRetryIf(func(err error) bool { return errors.Is(err, errInvalid) }),
+	)
+	if err := retryStep.Run(context.Background(), &retryState{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryStep_WithRetryIf_SkipsTransient(t *testing.T) {
+	attempts := 0
+	step := NewFuncStep[retryState]("inner", func(ctx context.Context, s *retryState) error {
+		attempts++
+		return ai.NewTransientError("temporary failure", 503, nil)
+	})
+
+	retryStep := NewRetryStep("retry", step,
+		WithRetryConfig(ai.NewRetryConfig(5, time.Millisecond, 10*time.Millisecond, 2, 0)),
+		WithRetryIf(func(err error) bool { return false }),
+	)
+	if err := retryStep.Run(context.Background(), &retryState{}); err == nil {
+		t.Fatal("expected error")
+	}
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestRetryStep_WithRetryConfig_MaxAttempts(t *testing.T) {
+	attempts := 0
+	step := NewFuncStep[retryState]("inner", func(ctx context.Context, s *retryState) error {
+		attempts++
+		return ai.NewTransientError("temporary failure", 500, nil)
+	})
+
+	retryStep := NewRetryStep("retry", step,
+		WithRetryConfig(ai.NewRetryConfig(2, time.Millisecond, time.Millisecond, 1, 0)))
+	if err := retryStep.Run(context.Background(), &retryState{}); err == nil {
+		t.Fatal("expected error")
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 attempts, got %d", attempts)
+	}
+}
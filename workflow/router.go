@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
+	"sort"
 	"strings"
 
 	ai "github.com/spetersoncode/gains"
@@ -49,6 +52,18 @@ func NewRouter[S any](name string, routes []Route[S], defaultRoute Step[S]) *Rou
 // Name returns the router name.
 func (r *Router[S]) Name() string { return r.name }
 
+// DescribeStep describes the router and its routes for Workflow.Describe.
+func (r *Router[S]) DescribeStep() StepInfo {
+	info := StepInfo{Kind: KindRouter, Flow: FlowBranch}
+	for _, route := range r.routes {
+		info.Children = append(info.Children, ChildStep{Step: route.Step, Label: route.Name})
+	}
+	if r.defaultRoute != nil {
+		info.Children = append(info.Children, ChildStep{Step: r.defaultRoute, Label: "default"})
+	}
+	return info
+}
+
 // Run evaluates conditions and executes the matching step.
 func (r *Router[S]) Run(ctx context.Context, state *S, opts ...Option) error {
 	options := ApplyOptions(opts...)
@@ -82,10 +97,11 @@ func (r *Router[S]) Run(ctx context.Context, state *S, opts ...Option) error {
 
 // RunStream evaluates conditions and streams the matching step's events.
 func (r *Router[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-chan Event {
-	ch := make(chan Event, 100)
+	ch := newEventChannel(opts)
 
 	go func() {
 		defer close(ch)
+		defer recoverStream(ch, r.name)
 		options := ApplyOptions(opts...)
 
 		if options.Timeout > 0 {
@@ -193,6 +209,16 @@ func ClassifierSchema[S any](routes map[string]Step[S]) ai.Option {
 // Name returns the router name.
 func (c *ClassifierRouter[S]) Name() string { return c.name }
 
+// DescribeStep describes the router and its routes, ordered by name, for
+// Workflow.Describe.
+func (c *ClassifierRouter[S]) DescribeStep() StepInfo {
+	info := StepInfo{Kind: KindClassifierRouter, Flow: FlowBranch}
+	for _, name := range slices.Sorted(maps.Keys(c.routes)) {
+		info.Children = append(info.Children, ChildStep{Step: c.routes[name], Label: name})
+	}
+	return info
+}
+
 // Run classifies input and executes the matching route.
 func (c *ClassifierRouter[S]) Run(ctx context.Context, state *S, opts ...Option) error {
 	options := ApplyOptions(opts...)
@@ -204,16 +230,27 @@ func (c *ClassifierRouter[S]) Run(ctx context.Context, state *S, opts ...Option)
 	}
 
 	// Merge chat options
-	chatOpts := make([]ai.Option, 0, len(c.chatOpts)+len(options.ChatOptions))
+	ctxOpts := ai.ContextOptions(ctx)
+	chatOpts := make([]ai.Option, 0, len(ctxOpts)+len(c.chatOpts)+len(options.ChatOptions))
+	chatOpts = append(chatOpts, ctxOpts...)
 	chatOpts = append(chatOpts, c.chatOpts...)
 	chatOpts = append(chatOpts, options.ChatOptions...)
 
 	// Get classification from LLM
 	msgs := c.prompt(state)
+	if dryRun(ctx, options) {
+		for _, name := range c.routeNames() {
+			if err := c.routes[name].Run(ctx, state, opts...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 	resp, err := c.chatClient.Chat(ctx, msgs, chatOpts...)
 	if err != nil {
 		return &StepError{StepName: c.name, Err: err}
 	}
+	recordUsage(ctx, resp.Usage)
 
 	classification, err := extractClassification(resp.Content)
 	if err != nil {
@@ -240,10 +277,11 @@ func (c *ClassifierRouter[S]) Run(ctx context.Context, state *S, opts ...Option)
 
 // RunStream classifies input with streaming and executes the matching route.
 func (c *ClassifierRouter[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-chan Event {
-	ch := make(chan Event, 100)
+	ch := newEventChannel(opts)
 
 	go func() {
 		defer close(ch)
+		defer recoverStream(ch, c.name)
 		options := ApplyOptions(opts...)
 
 		if options.Timeout > 0 {
@@ -255,12 +293,25 @@ func (c *ClassifierRouter[S]) RunStream(ctx context.Context, state *S, opts ...O
 		event.Emit(ch, Event{Type: event.StepStart, StepName: c.name})
 
 		// Merge chat options
-		chatOpts := make([]ai.Option, 0, len(c.chatOpts)+len(options.ChatOptions))
+		ctxOpts := ai.ContextOptions(ctx)
+		chatOpts := make([]ai.Option, 0, len(ctxOpts)+len(c.chatOpts)+len(options.ChatOptions))
+		chatOpts = append(chatOpts, ctxOpts...)
 		chatOpts = append(chatOpts, c.chatOpts...)
 		chatOpts = append(chatOpts, options.ChatOptions...)
 
 		// Get classification with streaming
 		msgs := c.prompt(state)
+		if dryRun(ctx, options) {
+			// Any route could be selected, so walk them all
+			event.Emit(ch, Event{Type: event.ChatPlanned, StepName: c.name, Messages: msgs, Tools: ai.ApplyOptions(chatOpts...).Tools})
+			for _, name := range c.routeNames() {
+				event.Emit(ch, Event{Type: event.RouteSelected, StepName: c.name, RouteName: name})
+				for ev := range c.routes[name].RunStream(ctx, state, opts...) {
+					ch <- ev
+				}
+			}
+			return
+		}
 		streamCh, err := c.chatClient.ChatStream(ctx, msgs, chatOpts...)
 		if err != nil {
 			event.Emit(ch, Event{Type: event.RunError, StepName: c.name, Error: err})
@@ -322,6 +373,16 @@ func (c *ClassifierRouter[S]) RunStream(ctx context.Context, state *S, opts ...O
 	return ch
 }
 
+// routeNames returns the route keys in sorted order.
+func (c *ClassifierRouter[S]) routeNames() []string {
+	names := make([]string, 0, len(c.routes))
+	for name := range c.routes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // extractClassification parses the LLM response to get the classification.
 // Handles both JSON structured output and plain text responses.
 func extractClassification(content string) (string, error) {
@@ -4,8 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"sync"
 
+	ai "github.com/spetersoncode/gains"
 	"github.com/spetersoncode/gains/event"
 )
 
@@ -25,6 +27,15 @@ type Runner interface {
 	RunStream(ctx context.Context, state any, opts ...Option) <-chan Event
 }
 
+// SchemaRunner is a Runner that can describe its input state as a JSON schema.
+// Runners created with NewRunner and NewRunnerJSON implement it.
+type SchemaRunner interface {
+	Runner
+
+	// InputSchema returns a JSON schema for the workflow's input state.
+	InputSchema() (json.RawMessage, error)
+}
+
 // RunnerFunc wraps a Step[S] as a Runner using a state factory function.
 // The factory creates a new state instance and optionally initializes it from input.
 type RunnerFunc[S any] struct {
@@ -103,12 +114,27 @@ func (r *RunnerFunc[S]) Name() string {
 	return r.name
 }
 
-// RunStream executes the workflow and returns an event stream.
+// InputSchema returns a JSON schema describing the workflow's state type S.
+// Returns an error if S is not a struct type.
+func (r *RunnerFunc[S]) InputSchema() (json.RawMessage, error) {
+	return ai.SchemaFor[S]()
+}
+
+// StateType returns the workflow's state type S.
+func (r *RunnerFunc[S]) StateType() reflect.Type {
+	return reflect.TypeFor[S]()
+}
+
+// RunStream executes the workflow and returns an event stream. The final
+// RunEnd event carries the state.
 func (r *RunnerFunc[S]) RunStream(ctx context.Context, input any, opts ...Option) <-chan Event {
-	ch := make(chan event.Event, 100)
+	options := ApplyOptions(opts...)
+	ctx = ai.WithConcurrencyLimit(ctx, options.RunConcurrency)
+	ch := make(chan event.Event, options.eventBuffer())
 
 	go func() {
 		defer close(ch)
+		defer recoverStream(ch, r.name)
 
 		// Create state from input
 		state, err := r.factory(input)
@@ -125,11 +151,11 @@ func (r *RunnerFunc[S]) RunStream(ctx context.Context, input any, opts ...Option
 			event.Emit(ch, ev)
 		}
 
-		// Emit run end
-		event.Emit(ch, Event{Type: event.RunEnd})
+		// Emit run end with the final state
+		event.Emit(ch, Event{Type: event.RunEnd, State: state})
 	}()
 
-	return ch
+	return logRun(ctx, options, ch)
 }
 
 // Registry stores and retrieves Runners by name.
@@ -196,6 +222,26 @@ func (r *Registry) Len() int {
 	return len(r.runners)
 }
 
+// InputSchemas returns the input state JSON schema of each registered
+// workflow, keyed by name. Workflows whose runner is not a SchemaRunner, or
+// whose schema cannot be generated, are omitted.
+func (r *Registry) InputSchemas() map[string]json.RawMessage {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schemas := make(map[string]json.RawMessage, len(r.runners))
+	for name, runner := range r.runners {
+		sr, ok := runner.(SchemaRunner)
+		if !ok {
+			continue
+		}
+		if schema, err := sr.InputSchema(); err == nil {
+			schemas[name] = schema
+		}
+	}
+	return schemas
+}
+
 // RunStream executes the named workflow and returns an event stream.
 // Returns an error channel if the workflow is not found.
 func (r *Registry) RunStream(ctx context.Context, name string, input any, opts ...Option) <-chan Event {
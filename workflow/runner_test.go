@@ -2,6 +2,7 @@ package workflow
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/spetersoncode/gains/event"
@@ -44,6 +45,10 @@ func TestNewRunnerJSON(t *testing.T) {
 			}
 			if ev.Type == event.RunEnd {
 				hasEnd = true
+				state, ok := ev.State.(*testRunnerState)
+				if !ok || state.Result != "processed: hello world" {
+					t.Errorf("expected final state on RunEnd, got %#v", ev.State)
+				}
 			}
 		}
 
@@ -176,6 +181,31 @@ func TestRegistry(t *testing.T) {
 		}
 	})
 
+	t.Run("InputSchemas", func(t *testing.T) {
+		reg := NewRegistry()
+		step := NewFuncStep("noop", func(ctx context.Context, state *testRunnerState) error {
+			return nil
+		})
+		reg.Register(NewRunnerJSON[testRunnerState]("wf-schema", step))
+		reg.Register(NewRunnerJSON[string]("wf-scalar", NewFuncStep("noop", func(ctx context.Context, state *string) error {
+			return nil
+		})))
+
+		schemas := reg.InputSchemas()
+		if len(schemas) != 1 {
+			t.Fatalf("expected 1 schema, got %d", len(schemas))
+		}
+
+		var schema map[string]any
+		if err := json.Unmarshal(schemas["wf-schema"], &schema); err != nil {
+			t.Fatalf("invalid schema: %v", err)
+		}
+		props, _ := schema["properties"].(map[string]any)
+		if _, ok := props["query"]; !ok {
+			t.Errorf("expected query property, got %v", schema)
+		}
+	})
+
 	t.Run("RunStream with registered workflow", func(t *testing.T) {
 		reg := NewRegistry()
 		step := NewFuncStep("process", func(ctx context.Context, state *testRunnerState) error {
@@ -0,0 +1,324 @@
+package spec
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/chat"
+	"github.com/spetersoncode/gains/internal/retry"
+	"github.com/spetersoncode/gains/workflow"
+)
+
+// Compile builds a workflow from doc. Its steps run as a chain named after
+// the workflow. Names in doc are resolved against reg, and templates are
+// parsed, so a document that compiles only fails at run time on template
+// execution. Prompt steps call c, which may be nil if there are none.
+// opts are passed to workflow.New.
+func Compile[S any](doc *Document, c chat.Client, reg *Registry[S], opts ...workflow.Option) (*workflow.Workflow[S], error) {
+	if doc.Name == "" {
+		return nil, fmt.Errorf("%w: workflow without a name", ErrInvalidSpec)
+	}
+	if len(doc.Steps) == 0 {
+		return nil, fmt.Errorf("%w: workflow %q has no steps", ErrInvalidSpec, doc.Name)
+	}
+	if reg == nil {
+		reg = NewRegistry[S]()
+	}
+	cp := &compiler[S]{client: c, reg: reg, names: map[string]bool{doc.Name: true}}
+	steps, err := cp.steps(doc.Steps)
+	if err != nil {
+		return nil, err
+	}
+	return workflow.New(doc.Name, workflow.NewChain(doc.Name, steps...), opts...), nil
+}
+
+// compiler compiles the steps of one document.
+type compiler[S any] struct {
+	client chat.Client
+	reg    *Registry[S]
+	// names are the step names seen, which must be unique.
+	names map[string]bool
+}
+
+// invalid returns an ErrInvalidSpec error for the step named name.
+func invalid(name, format string, args ...any) error {
+	return fmt.Errorf("%w: step %q: %s", ErrInvalidSpec, name, fmt.Sprintf(format, args...))
+}
+
+func (c *compiler[S]) steps(specs []StepSpec) ([]workflow.Step[S], error) {
+	steps := make([]workflow.Step[S], 0, len(specs))
+	for i := range specs {
+		step, err := c.step(&specs[i])
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+func (c *compiler[S]) step(s *StepSpec) (workflow.Step[S], error) {
+	if s.Name == "" {
+		return nil, fmt.Errorf("%w: step without a name", ErrInvalidSpec)
+	}
+	if c.names[s.Name] {
+		return nil, invalid(s.Name, "duplicate name")
+	}
+	c.names[s.Name] = true
+
+	switch s.Type {
+	case TypeFunc:
+		fn, ok := c.reg.funcs[s.Ref]
+		if !ok {
+			return nil, invalid(s.Name, "unknown function %q", s.Ref)
+		}
+		return workflow.NewFuncStep(s.Name, fn), nil
+	case TypeStep:
+		step, ok := c.reg.steps[s.Ref]
+		if !ok {
+			return nil, invalid(s.Name, "unknown step %q", s.Ref)
+		}
+		return step, nil
+	case TypePrompt:
+		return c.prompt(s)
+	case TypeChain:
+		steps, err := c.children(s)
+		if err != nil {
+			return nil, err
+		}
+		return workflow.NewChain(s.Name, steps...), nil
+	case TypeParallel:
+		steps, err := c.children(s)
+		if err != nil {
+			return nil, err
+		}
+		var agg workflow.Aggregator[S]
+		if s.Aggregator != "" {
+			var ok bool
+			if agg, ok = c.reg.aggregators[s.Aggregator]; !ok {
+				return nil, invalid(s.Name, "unknown aggregator %q", s.Aggregator)
+			}
+		}
+		return workflow.NewParallel(s.Name, steps, agg, workflow.WithQuorum(s.Quorum)), nil
+	case TypeRouter:
+		return c.router(s)
+	case TypeLoop:
+		return c.loop(s)
+	case TypeRetry:
+		return c.retry(s)
+	case "":
+		return nil, invalid(s.Name, "missing type")
+	default:
+		return nil, invalid(s.Name, "unknown type %q", s.Type)
+	}
+}
+
+// children compiles the steps of a chain or parallel.
+func (c *compiler[S]) children(s *StepSpec) ([]workflow.Step[S], error) {
+	if len(s.Steps) == 0 {
+		return nil, invalid(s.Name, "%s without steps", s.Type)
+	}
+	return c.steps(s.Steps)
+}
+
+// child compiles the step wrapped by a loop or retry.
+func (c *compiler[S]) child(s *StepSpec) (workflow.Step[S], error) {
+	if s.Step == nil {
+		return nil, invalid(s.Name, "%s without a step", s.Type)
+	}
+	return c.step(s.Step)
+}
+
+func (c *compiler[S]) router(s *StepSpec) (workflow.Step[S], error) {
+	if len(s.Routes) == 0 {
+		return nil, invalid(s.Name, "router without routes")
+	}
+	routes := make([]workflow.Route[S], 0, len(s.Routes))
+	for _, r := range s.Routes {
+		if r.Name == "" {
+			return nil, invalid(s.Name, "route without a name")
+		}
+		cond, err := c.condition(s.Name+"/"+r.Name, r.When)
+		if err != nil {
+			return nil, err
+		}
+		step, err := c.step(&r.Step)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, workflow.Route[S]{
+			Name:      r.Name,
+			Condition: func(_ context.Context, state *S) bool { return cond(state) },
+			Step:      step,
+		})
+	}
+	var def workflow.Step[S]
+	if s.Default != nil {
+		var err error
+		if def, err = c.step(s.Default); err != nil {
+			return nil, err
+		}
+	}
+	return workflow.NewRouter(s.Name, routes, def), nil
+}
+
+func (c *compiler[S]) loop(s *StepSpec) (workflow.Step[S], error) {
+	set := 0
+	for _, ok := range []bool{s.Until != "", s.While != "", s.Iterations > 0} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, invalid(s.Name, "loop needs exactly one of until, while, and iterations")
+	}
+	step, err := c.child(s)
+	if err != nil {
+		return nil, err
+	}
+	if s.Iterations > 0 {
+		return workflow.NewLoopN(s.Name, step, s.Iterations), nil
+	}
+	var opts []workflow.LoopOption
+	if s.MaxIterations > 0 {
+		opts = append(opts, workflow.WithMaxIterations(s.MaxIterations))
+	}
+	if s.Until != "" {
+		cond, err := c.condition(s.Name+"/until", s.Until)
+		if err != nil {
+			return nil, err
+		}
+		return workflow.NewLoopUntil(s.Name, step, cond, opts...), nil
+	}
+	cond, err := c.condition(s.Name+"/while", s.While)
+	if err != nil {
+		return nil, err
+	}
+	return workflow.NewLoopWhile(s.Name, step, cond, opts...), nil
+}
+
+func (c *compiler[S]) retry(s *StepSpec) (workflow.Step[S], error) {
+	step, err := c.child(s)
+	if err != nil {
+		return nil, err
+	}
+	cfg := retry.DefaultConfig()
+	if s.MaxAttempts > 0 {
+		cfg.MaxAttempts = s.MaxAttempts
+	}
+	if s.InitialDelay != "" {
+		if cfg.InitialDelay, err = time.ParseDuration(s.InitialDelay); err != nil {
+			return nil, invalid(s.Name, "initial_delay: %v", err)
+		}
+	}
+	return workflow.NewRetryStepWithConfig(s.Name, step, cfg), nil
+}
+
+// template parses text with the registry's template functions. A missing
+// map key is an error rather than "<no value>".
+func (c *compiler[S]) template(name, text string) (*template.Template, error) {
+	t, err := template.New(name).Option("missingkey=error").Funcs(c.reg.templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSpec, err)
+	}
+	return t, nil
+}
+
+// condition compiles a condition template. Text without actions is an
+// expression, so "eq .Status \"done\"" is short for
+// "{{eq .Status \"done\"}}". The condition holds when the output is true
+// as parsed by strconv.ParseBool; output that is not a boolean, or a
+// template that fails to execute, does not hold.
+func (c *compiler[S]) condition(name, text string) (func(*S) bool, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, invalid(name, "empty condition")
+	}
+	if !strings.Contains(text, "{{") {
+		text = "{{" + text + "}}"
+	}
+	t, err := c.template(name, text)
+	if err != nil {
+		return nil, err
+	}
+	return func(state *S) bool {
+		var b strings.Builder
+		if err := t.Execute(&b, state); err != nil {
+			return false
+		}
+		v, err := strconv.ParseBool(strings.TrimSpace(b.String()))
+		return err == nil && v
+	}, nil
+}
+
+// prompt compiles a prompt step. Its templates are a
+// workflow.PromptTemplate, so state fields tagged prompt:"untrusted", and
+// those named by the step's untrusted list, are delimited as in
+// workflow.NewTemplatePromptStep.
+func (c *compiler[S]) prompt(s *StepSpec) (workflow.Step[S], error) {
+	if c.client == nil {
+		return nil, invalid(s.Name, "prompt steps need a chat client")
+	}
+	if s.Prompt == "" {
+		return nil, invalid(s.Name, "prompt without a prompt template")
+	}
+	tmpl, err := workflow.NewPromptTemplate[S](s.System, s.Prompt,
+		workflow.WithTemplateFuncs(c.reg.templateFuncs),
+		workflow.WithUntrusted(s.Untrusted...),
+	)
+	if err != nil {
+		return nil, invalid(s.Name, "%v", err)
+	}
+	var field func(*S) *string
+	if s.Output != "" {
+		if field, err = outputField[S](s.Output); err != nil {
+			return nil, invalid(s.Name, "%v", err)
+		}
+	}
+	var chatOpts []ai.Option
+	if s.Model != "" {
+		model, ok := c.reg.models[s.Model]
+		if !ok {
+			return nil, invalid(s.Name, "unknown model %q", s.Model)
+		}
+		chatOpts = append(chatOpts, ai.WithModel(model))
+	}
+	if s.Temperature != nil {
+		chatOpts = append(chatOpts, ai.WithTemperature(*s.Temperature))
+	}
+	if s.MaxTokens > 0 {
+		chatOpts = append(chatOpts, ai.WithMaxTokens(s.MaxTokens))
+	}
+	return workflow.NewTemplatePromptStep(s.Name, c.client, tmpl, nil, field, chatOpts...), nil
+}
+
+// outputField returns an accessor for the string field of S named name,
+// matching either the Go field name or its JSON name.
+func outputField[S any](name string) (func(*S) *string, error) {
+	t := reflect.TypeFor[S]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("output %q needs a struct state, got %s", name, t)
+	}
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		jsonName, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Name != name && jsonName != name {
+			continue
+		}
+		if f.Type.Kind() != reflect.String {
+			return nil, fmt.Errorf("output %q is a %s, not a string", name, f.Type)
+		}
+		index := f.Index
+		return func(state *S) *string {
+			return reflect.ValueOf(state).Elem().FieldByIndex(index).Addr().Interface().(*string)
+		}, nil
+	}
+	return nil, fmt.Errorf("state %s has no field %q", t, name)
+}
@@ -0,0 +1,68 @@
+// Package spec loads workflows from declarative YAML or JSON documents, so
+// pipelines can be authored and changed without writing Go.
+//
+// A document names the workflow and lists its steps. Steps nest as the
+// workflow package's patterns do: chains, parallels, routers, loops, and
+// retries around prompt steps and Go functions. Anything a document cannot
+// express, such as a step function, aggregator, agent step, or model, is
+// registered in Go under a name the document refers to.
+//
+// # Documents
+//
+//	name: support
+//	steps:
+//	  - name: classify
+//	    type: prompt
+//	    system: You classify support tickets as billing or other.
+//	    prompt: "Classify: {{.Ticket}}"
+//	    output: Category
+//	  - name: route
+//	    type: router
+//	    routes:
+//	      - name: billing
+//	        when: eq .Category "billing"
+//	        step: {name: refund, type: func, ref: refund}
+//	    default: {name: escalate, type: step, ref: support-agent}
+//	  - name: polish
+//	    type: loop
+//	    until: ge .Score 8
+//	    max_iterations: 3
+//	    step: {name: revise, type: func, ref: revise}
+//
+// JSON documents have the same fields. Unknown fields are rejected.
+//
+// # Templates
+//
+// Prompts and conditions are text/template templates executed with the
+// state as data, so {{.Ticket}} reads the state's Ticket field. Functions
+// registered with Registry.TemplateFunc are available to them. A condition
+// without {{ }} actions is a single expression, and holds when it renders
+// "true". A prompt step's output names a string field of the state, by Go
+// or JSON name, that receives the response.
+//
+// Prompt steps render as a workflow.PromptTemplate, which sees the state's
+// exported fields but not its methods. State fields tagged
+// prompt:"untrusted", and fields or map keys listed under the step's
+// untrusted key, are delimited against prompt injection, and
+// {{untrusted .Value}} delimits any value.
+//
+// # Compiling
+//
+//	reg := spec.NewRegistry[SupportState]().
+//	    Func("refund", issueRefund).
+//	    Func("revise", revise).
+//	    Step("support-agent", agentStep)
+//
+//	doc, err := spec.Load("support.yaml")
+//	if err != nil {
+//	    return err
+//	}
+//	wf, err := spec.Compile(doc, client, reg)
+//	if err != nil {
+//	    return err // ErrInvalidSpec: unknown names, bad templates, ...
+//	}
+//	result, err := wf.Run(ctx, &SupportState{Ticket: ticket})
+//
+// Compile checks every name and parses every template, so a document that
+// compiles fails at run time only when a template fails to execute.
+package spec
@@ -0,0 +1,63 @@
+package spec
+
+import (
+	"text/template"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/workflow"
+)
+
+// Registry holds the Go values a document refers to by name: step
+// functions, prebuilt steps, parallel aggregators, models, and template
+// functions. Register everything before calling Compile.
+type Registry[S any] struct {
+	funcs         map[string]workflow.StepFunc[S]
+	steps         map[string]workflow.Step[S]
+	aggregators   map[string]workflow.Aggregator[S]
+	models        map[string]ai.Model
+	templateFuncs template.FuncMap
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry[S any]() *Registry[S] {
+	return &Registry[S]{
+		funcs:         make(map[string]workflow.StepFunc[S]),
+		steps:         make(map[string]workflow.Step[S]),
+		aggregators:   make(map[string]workflow.Aggregator[S]),
+		models:        make(map[string]ai.Model),
+		templateFuncs: make(template.FuncMap),
+	}
+}
+
+// Func registers a function for steps of type func. It returns the
+// registry for chaining.
+func (r *Registry[S]) Func(name string, fn workflow.StepFunc[S]) *Registry[S] {
+	r.funcs[name] = fn
+	return r
+}
+
+// Step registers a prebuilt step, such as an agent or tool step, for steps
+// of type step.
+func (r *Registry[S]) Step(name string, step workflow.Step[S]) *Registry[S] {
+	r.steps[name] = step
+	return r
+}
+
+// Aggregator registers an aggregator for parallel steps.
+func (r *Registry[S]) Aggregator(name string, agg workflow.Aggregator[S]) *Registry[S] {
+	r.aggregators[name] = agg
+	return r
+}
+
+// Model registers a model for prompt steps.
+func (r *Registry[S]) Model(name string, model ai.Model) *Registry[S] {
+	r.models[name] = model
+	return r
+}
+
+// TemplateFunc registers a function callable from prompt and condition
+// templates. See text/template for the signatures allowed.
+func (r *Registry[S]) TemplateFunc(name string, fn any) *Registry[S] {
+	r.templateFuncs[name] = fn
+	return r
+}
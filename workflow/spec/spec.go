@@ -0,0 +1,120 @@
+package spec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Step types of a StepSpec.
+const (
+	TypeFunc     = "func"
+	TypeStep     = "step"
+	TypePrompt   = "prompt"
+	TypeChain    = "chain"
+	TypeParallel = "parallel"
+	TypeRouter   = "router"
+	TypeLoop     = "loop"
+	TypeRetry    = "retry"
+)
+
+// ErrInvalidSpec indicates a workflow document that cannot be compiled.
+var ErrInvalidSpec = errors.New("spec: invalid workflow")
+
+// Document is a declarative workflow definition.
+type Document struct {
+	// Name is the workflow name.
+	Name string `json:"name" yaml:"name"`
+
+	// Steps run in sequence as the workflow's root chain.
+	Steps []StepSpec `json:"steps" yaml:"steps"`
+}
+
+// StepSpec defines a step. Type selects which of the other fields apply:
+//
+//   - func: Ref names a function in the Registry
+//   - step: Ref names a step in the Registry
+//   - prompt: System and Prompt are message templates; the response is
+//     stored in the state field named by Output. Untrusted names state
+//     fields or map keys delimited as untrusted input, in addition to
+//     fields tagged prompt:"untrusted"
+//   - chain: Steps run in sequence
+//   - parallel: Steps run concurrently; Aggregator names an aggregator in
+//     the Registry, and Quorum, if set, ends the step once that many
+//     steps succeed
+//   - router: the first of Routes whose When holds runs, else Default
+//   - loop: Step runs until Until holds, while While holds, or Iterations
+//     times, at most MaxIterations times
+//   - retry: Step is retried up to MaxAttempts times
+type StepSpec struct {
+	Name string `json:"name" yaml:"name"`
+	Type string `json:"type" yaml:"type"`
+
+	Ref string `json:"ref,omitempty" yaml:"ref,omitempty"`
+
+	System      string   `json:"system,omitempty" yaml:"system,omitempty"`
+	Prompt      string   `json:"prompt,omitempty" yaml:"prompt,omitempty"`
+	Output      string   `json:"output,omitempty" yaml:"output,omitempty"`
+	Model       string   `json:"model,omitempty" yaml:"model,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty" yaml:"temperature,omitempty"`
+	MaxTokens   int      `json:"max_tokens,omitempty" yaml:"max_tokens,omitempty"`
+	Untrusted   []string `json:"untrusted,omitempty" yaml:"untrusted,omitempty"`
+
+	Steps      []StepSpec `json:"steps,omitempty" yaml:"steps,omitempty"`
+	Aggregator string     `json:"aggregator,omitempty" yaml:"aggregator,omitempty"`
+	Quorum     int        `json:"quorum,omitempty" yaml:"quorum,omitempty"`
+
+	Routes  []RouteSpec `json:"routes,omitempty" yaml:"routes,omitempty"`
+	Default *StepSpec   `json:"default,omitempty" yaml:"default,omitempty"`
+
+	Step          *StepSpec `json:"step,omitempty" yaml:"step,omitempty"`
+	Until         string    `json:"until,omitempty" yaml:"until,omitempty"`
+	While         string    `json:"while,omitempty" yaml:"while,omitempty"`
+	Iterations    int       `json:"iterations,omitempty" yaml:"iterations,omitempty"`
+	MaxIterations int       `json:"max_iterations,omitempty" yaml:"max_iterations,omitempty"`
+
+	MaxAttempts  int    `json:"max_attempts,omitempty" yaml:"max_attempts,omitempty"`
+	InitialDelay string `json:"initial_delay,omitempty" yaml:"initial_delay,omitempty"`
+}
+
+// RouteSpec is a router branch.
+type RouteSpec struct {
+	Name string `json:"name" yaml:"name"`
+	// When is the condition template selecting the route.
+	When string   `json:"when" yaml:"when"`
+	Step StepSpec `json:"step" yaml:"step"`
+}
+
+// Parse decodes a workflow document from YAML or JSON. Unknown fields are
+// rejected so typos surface as errors.
+func Parse(data []byte) (*Document, error) {
+	return Decode(bytes.NewReader(data))
+}
+
+// Decode reads a workflow document in YAML or JSON from r.
+func Decode(r io.Reader) (*Document, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	var doc Document
+	if err := dec.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: empty document", ErrInvalidSpec)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSpec, err)
+	}
+	return &doc, nil
+}
+
+// Load reads a workflow document from a YAML or JSON file.
+func Load(path string) (*Document, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Decode(f)
+}
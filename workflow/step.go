@@ -42,19 +42,23 @@ func NewFuncStep[S any](name string, fn StepFunc[S]) *FuncStep[S] {
 // Name returns the step name.
 func (f *FuncStep[S]) Name() string { return f.name }
 
+// DescribeStep describes the step for Workflow.Describe.
+func (f *FuncStep[S]) DescribeStep() StepInfo { return StepInfo{Kind: KindFunc} }
+
 // Run executes the function.
 func (f *FuncStep[S]) Run(ctx context.Context, state *S, opts ...Option) error {
 	return f.fn(ctx, state)
 }
 
 // RunStream executes the function and emits events.
+// A panic in the function is reported as a RunError wrapping a *PanicError.
 func (f *FuncStep[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-chan Event {
 	ch := make(chan Event, 10)
 	go func() {
 		defer close(ch)
 		event.Emit(ch, Event{Type: event.StepStart, StepName: f.name})
 
-		err := f.fn(ctx, state)
+		err := callStep(f.name, func() error { return f.fn(ctx, state) })
 		if err != nil {
 			event.Emit(ch, Event{Type: event.RunError, StepName: f.name, Error: err})
 			return
@@ -98,6 +102,9 @@ func NewStatefulFuncStep[S any](name string, fn StatefulStepFunc[S]) *StatefulFu
 // Name returns the step name.
 func (f *StatefulFuncStep[S]) Name() string { return f.name }
 
+// DescribeStep describes the step for Workflow.Describe.
+func (f *StatefulFuncStep[S]) DescribeStep() StepInfo { return StepInfo{Kind: KindFunc} }
+
 // Run executes the function with a no-op emitter (state events are discarded).
 func (f *StatefulFuncStep[S]) Run(ctx context.Context, state *S, opts ...Option) error {
 	return f.fn(ctx, state, NewNoOpEmitter())
@@ -105,7 +112,7 @@ func (f *StatefulFuncStep[S]) Run(ctx context.Context, state *S, opts ...Option)
 
 // RunStream executes the function and emits events including state changes.
 func (f *StatefulFuncStep[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-chan Event {
-	ch := make(chan Event, 100) // Larger buffer for state events
+	ch := newEventChannel(opts)
 	go func() {
 		defer close(ch)
 		event.Emit(ch, Event{Type: event.StepStart, StepName: f.name})
@@ -113,7 +120,7 @@ func (f *StatefulFuncStep[S]) RunStream(ctx context.Context, state *S, opts ...O
 		// Create emitter that sends to our channel
 		emitter := NewChannelEmitter(ch, f.name)
 
-		err := f.fn(ctx, state, emitter)
+		err := callStep(f.name, func() error { return f.fn(ctx, state, emitter) })
 		if err != nil {
 			event.Emit(ch, Event{Type: event.RunError, StepName: f.name, Error: err})
 			return
@@ -139,7 +146,7 @@ type PromptFunc[S any] func(state *S) []ai.Message
 type PromptStep[S, T any] struct {
 	name       string
 	chatClient chat.Client
-	prompt     PromptFunc[S]
+	render     func(*S) ([]ai.Message, error)
 	schema     *ai.ResponseSchema
 	field      func(*S) *T
 	chatOpts   []ai.Option
@@ -149,6 +156,10 @@ type PromptStep[S, T any] struct {
 // The field getter returns a pointer to where the result should be stored.
 // Type parameters are inferred from the function arguments.
 //
+// The messages from prompt are sent as they are: prompt:"untrusted" tags
+// have no effect here. Delimit untrusted values with Untrusted, or render
+// the messages with NewTemplatePromptStep.
+//
 // For plain text (schema = nil):
 //
 //	step := NewPromptStep("summarize", client, promptFn, nil,
@@ -171,7 +182,36 @@ func NewPromptStep[S, T any](
 	return &PromptStep[S, T]{
 		name:       name,
 		chatClient: c,
-		prompt:     prompt,
+		render:     func(state *S) ([]ai.Message, error) { return prompt(state), nil },
+		schema:     schema,
+		field:      field,
+		chatOpts:   opts,
+	}
+}
+
+// NewTemplatePromptStep creates a step for a single LLM call whose messages
+// are rendered from tmpl, delimiting the state's untrusted values. A
+// template that fails to render fails the step.
+//
+//	tmpl, err := NewPromptTemplate[MyState]("You are a support agent.",
+//	    "Answer the customer:\n{{.Message}}",
+//	    WithUntrusted("Message"),
+//	)
+//	step := NewTemplatePromptStep("answer", client, tmpl, nil,
+//	    func(s *MyState) *string { return &s.Answer },
+//	)
+func NewTemplatePromptStep[S, T any](
+	name string,
+	c chat.Client,
+	tmpl *PromptTemplate[S],
+	schema *ai.ResponseSchema,
+	field func(*S) *T,
+	opts ...ai.Option,
+) *PromptStep[S, T] {
+	return &PromptStep[S, T]{
+		name:       name,
+		chatClient: c,
+		render:     tmpl.Render,
 		schema:     schema,
 		field:      field,
 		chatOpts:   opts,
@@ -181,12 +221,17 @@ func NewPromptStep[S, T any](
 // Name returns the step name.
 func (p *PromptStep[S, T]) Name() string { return p.name }
 
+// DescribeStep describes the step for Workflow.Describe.
+func (p *PromptStep[S, T]) DescribeStep() StepInfo { return StepInfo{Kind: KindPrompt} }
+
 // Run executes the LLM call.
 func (p *PromptStep[S, T]) Run(ctx context.Context, state *S, opts ...Option) error {
 	options := ApplyOptions(opts...)
 
-	// Merge chat options: constructor opts first, then runtime opts
-	chatOpts := make([]ai.Option, 0, len(p.chatOpts)+len(options.ChatOptions)+1)
+	// Merge chat options: context opts first, then constructor opts, then runtime opts
+	ctxOpts := ai.ContextOptions(ctx)
+	chatOpts := make([]ai.Option, 0, len(ctxOpts)+len(p.chatOpts)+len(options.ChatOptions)+1)
+	chatOpts = append(chatOpts, ctxOpts...)
 	chatOpts = append(chatOpts, p.chatOpts...)
 	chatOpts = append(chatOpts, options.ChatOptions...)
 
@@ -195,11 +240,22 @@ func (p *PromptStep[S, T]) Run(ctx context.Context, state *S, opts ...Option) er
 		chatOpts = append(chatOpts, ai.WithResponseSchema(*p.schema))
 	}
 
-	msgs := p.prompt(state)
+	msgs, err := p.render(state)
+	if err != nil {
+		return err
+	}
+	msgs, err = fitContext(ctx, options, msgs, chatOpts)
+	if err != nil {
+		return err
+	}
+	if dryRun(ctx, options) {
+		return nil
+	}
 	resp, err := p.chatClient.Chat(ctx, msgs, chatOpts...)
 	if err != nil {
 		return err
 	}
+	recordUsage(ctx, resp.Usage)
 
 	if p.field != nil {
 		if err := p.storeResult(state, resp.Content); err != nil {
@@ -210,6 +266,14 @@ func (p *PromptStep[S, T]) Run(ctx context.Context, state *S, opts ...Option) er
 	return nil
 }
 
+// fitContext trims msgs with the context manager in options, if any.
+func fitContext(ctx context.Context, options *Options, msgs []ai.Message, chatOpts []ai.Option) ([]ai.Message, error) {
+	if options.ContextManager == nil {
+		return msgs, nil
+	}
+	return options.ContextManager.Fit(ctx, msgs, chatOpts...)
+}
+
 // storeResult stores the response content into the field.
 func (p *PromptStep[S, T]) storeResult(state *S, content string) error {
 	fieldPtr := p.field(state)
@@ -236,16 +300,19 @@ func (p *PromptStep[S, T]) storeResult(state *S, content string) error {
 
 // RunStream executes the LLM call with streaming.
 func (p *PromptStep[S, T]) RunStream(ctx context.Context, state *S, opts ...Option) <-chan Event {
-	ch := make(chan Event, 100)
+	ch := newEventChannel(opts)
 
 	go func() {
 		defer close(ch)
+		defer recoverStream(ch, p.name)
 		event.Emit(ch, Event{Type: event.StepStart, StepName: p.name})
 
 		options := ApplyOptions(opts...)
 
-		// Merge chat options: constructor opts first, then runtime opts
-		chatOpts := make([]ai.Option, 0, len(p.chatOpts)+len(options.ChatOptions)+1)
+		// Merge chat options: context opts first, then constructor opts, then runtime opts
+		ctxOpts := ai.ContextOptions(ctx)
+		chatOpts := make([]ai.Option, 0, len(ctxOpts)+len(p.chatOpts)+len(options.ChatOptions)+1)
+		chatOpts = append(chatOpts, ctxOpts...)
 		chatOpts = append(chatOpts, p.chatOpts...)
 		chatOpts = append(chatOpts, options.ChatOptions...)
 
@@ -254,7 +321,19 @@ func (p *PromptStep[S, T]) RunStream(ctx context.Context, state *S, opts ...Opti
 			chatOpts = append(chatOpts, ai.WithResponseSchema(*p.schema))
 		}
 
-		msgs := p.prompt(state)
+		msgs, err := p.render(state)
+		if err == nil {
+			msgs, err = fitContext(ctx, options, msgs, chatOpts)
+		}
+		if err != nil {
+			event.Emit(ch, Event{Type: event.RunError, StepName: p.name, Error: err})
+			return
+		}
+		if dryRun(ctx, options) {
+			event.Emit(ch, Event{Type: event.ChatPlanned, StepName: p.name, Messages: msgs, Tools: ai.ApplyOptions(chatOpts...).Tools})
+			event.Emit(ch, Event{Type: event.StepEnd, StepName: p.name})
+			return
+		}
 		streamCh, err := p.chatClient.ChatStream(ctx, msgs, chatOpts...)
 		if err != nil {
 			event.Emit(ch, Event{Type: event.RunError, StepName: p.name, Error: err})
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"time"
 
 	ai "github.com/spetersoncode/gains"
 	"github.com/spetersoncode/gains/event"
@@ -74,6 +73,11 @@ func NewToolStep[S, T any](
 // Name returns the step name.
 func (t *ToolStep[S, T]) Name() string { return t.name }
 
+// DescribeStep describes the step and the tool it runs for Workflow.Describe.
+func (t *ToolStep[S, T]) DescribeStep() StepInfo {
+	return StepInfo{Kind: KindTool, Detail: t.toolName}
+}
+
 // Run executes the tool.
 func (t *ToolStep[S, T]) Run(ctx context.Context, state *S, opts ...Option) error {
 	options := ApplyOptions(opts...)
@@ -99,7 +103,7 @@ func (t *ToolStep[S, T]) Run(ctx context.Context, state *S, opts ...Option) erro
 
 	// Create synthetic tool call
 	call := ai.ToolCall{
-		ID:        fmt.Sprintf("%s-%d", t.name, time.Now().UnixNano()),
+		ID:        t.name + "-" + ai.NewID(),
 		Name:      t.toolName,
 		Arguments: string(argsJSON),
 	}
@@ -135,6 +139,7 @@ func (t *ToolStep[S, T]) RunStream(ctx context.Context, state *S, opts ...Option
 
 	go func() {
 		defer close(ch)
+		defer recoverStream(ch, t.name)
 		event.Emit(ch, Event{Type: event.StepStart, StepName: t.name})
 
 		err := t.Run(ctx, state, opts...)
@@ -2,18 +2,32 @@ package workflow
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	ai "github.com/spetersoncode/gains"
+	"github.com/spetersoncode/gains/event"
+	"github.com/spetersoncode/gains/internal/store"
 )
 
+// ErrNoCheckpointer indicates Resume was called without WithCheckpointer.
+var ErrNoCheckpointer = errors.New("workflow: no checkpointer configured")
+
 // Workflow is the top-level orchestrator that wraps a root step.
 // It provides the primary entry point for workflow execution.
 type Workflow[S any] struct {
 	name string
 	root Step[S]
+
+	// defaults are applied before the options passed to each run.
+	defaults []Option
 }
 
-// New creates a new workflow with a root step.
-func New[S any](name string, root Step[S]) *Workflow[S] {
-	return &Workflow[S]{name: name, root: root}
+// New creates a new workflow with a root step. The options become defaults
+// for every run, which options passed to Run, RunStream, or Resume override.
+func New[S any](name string, root Step[S], opts ...Option) *Workflow[S] {
+	return &Workflow[S]{name: name, root: root, defaults: opts}
 }
 
 // Name returns the workflow name.
@@ -22,33 +36,286 @@ func (w *Workflow[S]) Name() string { return w.name }
 // Run executes the workflow synchronously.
 // State is mutated in place - access results via state fields after completion.
 // The state parameter must not be nil.
+//
+// With WithCheckpointer, the state is persisted after each completed chain
+// step under the run ID from WithRunID (or a generated one reported in
+// Result.RunID), so an interrupted run can continue with Resume. With
+// WithArtifactStore, the tagged state fields are stored under the same run
+// ID once the run completes. With WithRunHistory, the run's outcome is
+// recorded under the same run ID.
 func (w *Workflow[S]) Run(ctx context.Context, state *S, opts ...Option) (*Result[S], error) {
-	err := w.root.Run(ctx, state, opts...)
+	opts = w.withDefaults(opts)
+	options := ApplyOptions(opts...)
+	if options.DryRun {
+		ctx = ai.WithDryRun(ctx)
+	}
+	ctx = ai.WithConcurrencyLimit(ctx, options.RunConcurrency)
+	runID := w.newRunID(options)
+	return w.logged(ctx, options, func() (*Result[S], error) {
+		return w.run(ctx, state, runID, w.newCheckpointer(options, runID), false, options, opts)
+	})
+}
+
+// Resume continues the checkpointed run runID from its last completed step.
+// The state is restored from the checkpoint, and chain steps that completed
+// before the interruption are skipped. Resuming a run that already finished
+// returns its final state without running any steps. Resume requires
+// WithCheckpointer, passed here or to New, and returns an error matching
+// ErrCheckpointNotFound if the run has no checkpoint.
+func (w *Workflow[S]) Resume(ctx context.Context, runID string, opts ...Option) (*Result[S], error) {
+	return w.resume(ctx, runID, nil, Approval{}, opts)
+}
+
+// ResumeWithApproval continues a run suspended by an approval step with
+// the decision for it. token is the resume token from Result.ResumeToken
+// or the step's user input activity. The approval step runs the branch for
+// the decision, and the run continues as with Resume.
+//
+// ResumeWithApproval returns an error matching ErrInvalidResumeToken if
+// token is malformed, was not issued by the run, or its step is no longer
+// awaiting a decision. Of several concurrent calls with the same token,
+// such as a double-submitted approval form, only the first continues the
+// run. The decision is recorded in the checkpoint under a lock on the run,
+// taken through the checkpointer if it implements store.Locker, as the
+// store/redis adapter does, and otherwise held within this process only.
+func (w *Workflow[S]) ResumeWithApproval(ctx context.Context, token string, decision Approval, opts ...Option) (*Result[S], error) {
+	t, err := parseResumeToken(token)
+	if err != nil {
+		return nil, err
+	}
+	return w.resume(ctx, t.RunID, &t, decision, opts)
+}
+
+// resume continues the checkpointed run runID. If t is non-nil, decision
+// for its approval step is first checked and recorded in the checkpoint.
+func (w *Workflow[S]) resume(ctx context.Context, runID string, t *resumeToken, decision Approval, opts []Option) (*Result[S], error) {
+	opts = w.withDefaults(opts)
+	options := ApplyOptions(opts...)
+	if options.Checkpointer == nil {
+		return nil, ErrNoCheckpointer
+	}
+
+	var rec *checkpointRecord
+	var err error
+	if t != nil {
+		rec, err = decide(ctx, options.Checkpointer, *t, decision)
+	} else {
+		rec, err = loadCheckpoint(ctx, options.Checkpointer, runID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rec.Workflow != w.name {
+		return nil, fmt.Errorf("workflow: run %s belongs to workflow %q, not %q", runID, rec.Workflow, w.name)
+	}
+	state := new(S)
+	if err := json.Unmarshal(rec.State, state); err != nil {
+		return nil, &store.SerializationError{Key: checkpointKey(runID), Err: err}
+	}
+	if rec.Done {
+		return &Result[S]{
+			WorkflowName: w.name,
+			RunID:        runID,
+			State:        state,
+			Termination:  TerminationComplete,
+		}, nil
+	}
+
+	cp := newCheckpointer(options.Checkpointer, w.name, runID)
+	for name, n := range rec.Positions {
+		cp.resume[name] = n
+	}
+	for name, d := range rec.Decisions {
+		cp.decisions[name] = d
+	}
+	ctx = ai.WithConcurrencyLimit(ctx, options.RunConcurrency)
+	return w.logged(ctx, options, func() (*Result[S], error) {
+		return w.run(ctx, state, runID, cp, true, options, opts)
+	})
+}
+
+// logged calls run, logging its start and outcome to the WithLogger logger,
+// if there is one.
+func (w *Workflow[S]) logged(ctx context.Context, options *Options, run func() (*Result[S], error)) (*Result[S], error) {
+	if options.Logger == nil {
+		return run()
+	}
+	event.Log(ctx, options.Logger, options.LogLevels, Event{Type: event.RunStart, StepName: w.name})
+	result, err := run()
+	if err != nil {
+		event.Log(ctx, options.Logger, options.LogLevels, Event{Type: event.RunError, StepName: w.name, RunID: result.RunID, Error: err})
+	} else {
+		event.Log(ctx, options.Logger, options.LogLevels, Event{Type: event.RunEnd, StepName: w.name, RunID: result.RunID, Message: string(result.Termination)})
+	}
+	return result, err
+}
+
+// logRun logs the events of ch to the WithLogger logger, if there is one.
+func logRun(ctx context.Context, options *Options, ch <-chan Event) <-chan Event {
+	if options.Logger == nil {
+		return ch
+	}
+	return event.LogStream(ctx, options.Logger, options.LogLevels, ch)
+}
+
+// run executes the root step, checkpointing through cp if it is non-nil,
+// and stores the run's artifacts once it completes. resumed reports
+// whether the run continues an earlier attempt.
+func (w *Workflow[S]) run(ctx context.Context, state *S, runID string, cp *checkpointer, resumed bool, options *Options, opts []Option) (*Result[S], error) {
+	hist, err := startHistory(ctx, options, w.name, runID, state, resumed)
+	if err != nil {
+		return &Result[S]{
+			WorkflowName: w.name,
+			RunID:        runID,
+			State:        state,
+			Error:        err,
+			Termination:  TerminationError,
+		}, err
+	}
+	if hist != nil {
+		ctx = withUsageCounter(ctx, &hist.usage)
+	}
+	if cp != nil {
+		ctx = withCheckpointer(ctx, cp)
+	}
+
+	err = w.root.Run(ctx, state, opts...)
+	if err == nil {
+		err = w.finish(ctx, state, runID, cp, options)
+	}
+	term := termination(ctx, err)
+	if herr := hist.finish(ctx, term, err); herr != nil && err == nil {
+		err, term = herr, TerminationError
+	}
 	if err != nil {
-		termination := TerminationError
-		if ctx.Err() == context.Canceled {
-			termination = TerminationCancelled
-		} else if ctx.Err() == context.DeadlineExceeded {
-			termination = TerminationTimeout
-		}
 		return &Result[S]{
 			WorkflowName: w.name,
+			RunID:        runID,
 			State:        state,
 			Error:        err,
-			Termination:  termination,
+			Termination:  term,
+			ResumeToken:  resumeTokenOf(err),
 		}, err
 	}
 
 	return &Result[S]{
 		WorkflowName: w.name,
+		RunID:        runID,
 		State:        state,
 		Termination:  TerminationComplete,
 	}, nil
 }
 
+// resumeTokenOf returns the resume token of a run suspended with err, or
+// empty.
+func resumeTokenOf(err error) string {
+	var se *suspendedError
+	if errors.As(err, &se) {
+		return se.token
+	}
+	return ""
+}
+
 // RunStream executes the workflow and returns an event channel.
 // State is mutated in place during streaming.
 // The state parameter must not be nil.
+// Checkpointing, artifacts, and run history work as in Run; the run is
+// marked finished and its artifacts are stored unless the stream ends with
+// a RunError.
 func (w *Workflow[S]) RunStream(ctx context.Context, state *S, opts ...Option) <-chan Event {
-	return w.root.RunStream(ctx, state, opts...)
+	opts = w.withDefaults(opts)
+	options := ApplyOptions(opts...)
+	if options.DryRun {
+		ctx = ai.WithDryRun(ctx)
+	}
+	ctx = ai.WithConcurrencyLimit(ctx, options.RunConcurrency)
+	runID := w.newRunID(options)
+	if runID == "" {
+		return logRun(ctx, options, w.root.RunStream(ctx, state, opts...))
+	}
+
+	cp := w.newCheckpointer(options, runID)
+	if cp != nil {
+		ctx = withCheckpointer(ctx, cp)
+	}
+	ch := make(chan Event, options.eventBuffer())
+	go func() {
+		defer close(ch)
+		hist, err := startHistory(ctx, options, w.name, runID, state, false)
+		if err != nil {
+			event.Emit(ch, Event{Type: event.RunError, StepName: w.name, Error: err})
+			return
+		}
+
+		failed := false
+		var runErr error
+		for ev := range w.root.RunStream(ctx, state, opts...) {
+			failed = ev.Type == event.RunError
+			if failed {
+				runErr = ev.Error
+			}
+			if hist != nil && ev.Type == event.MessageEnd && ev.Response != nil {
+				hist.usage.add(ev.Response.Usage)
+			}
+			ch <- ev
+		}
+		if !failed {
+			if runErr = w.finish(ctx, state, runID, cp, options); runErr != nil {
+				failed = true
+				event.Emit(ch, Event{Type: event.RunError, StepName: w.name, Error: runErr})
+			}
+		}
+
+		if err := hist.finish(ctx, termination(ctx, runErr), runErr); err != nil && !failed {
+			event.Emit(ch, Event{Type: event.RunError, StepName: w.name, Error: err})
+		}
+	}()
+	return logRun(ctx, options, ch)
+}
+
+// finish marks a completed run's checkpoint finished and stores its
+// artifacts.
+func (w *Workflow[S]) finish(ctx context.Context, state *S, runID string, cp *checkpointer, options *Options) error {
+	if cp != nil {
+		if err := cp.save(ctx, state, true); err != nil {
+			return fmt.Errorf("workflow: checkpoint %s: %w", runID, err)
+		}
+	}
+	if options.ArtifactStore != nil && !options.DryRun {
+		if err := saveArtifacts(ctx, options.ArtifactStore, w.name, runID, state); err != nil {
+			return fmt.Errorf("workflow: artifacts %s: %w", runID, err)
+		}
+	}
+	return nil
+}
+
+// newRunID returns the ID of a new run: the one from WithRunID or a
+// generated one when the run is checkpointed, stores artifacts, or is
+// recorded in run history, and empty otherwise. Dry runs persist nothing
+// and get no ID.
+func (w *Workflow[S]) newRunID(options *Options) string {
+	if options.DryRun || (options.Checkpointer == nil && options.ArtifactStore == nil && options.RunHistory == nil) {
+		return ""
+	}
+	if options.RunID != "" {
+		return options.RunID
+	}
+	return ai.NewID()
+}
+
+// newCheckpointer returns a checkpointer for the run runID, or nil if
+// checkpointing is not configured.
+func (w *Workflow[S]) newCheckpointer(options *Options, runID string) *checkpointer {
+	if options.Checkpointer == nil || runID == "" {
+		return nil
+	}
+	return newCheckpointer(options.Checkpointer, w.name, runID)
+}
+
+// withDefaults returns the workflow defaults followed by opts.
+func (w *Workflow[S]) withDefaults(opts []Option) []Option {
+	if len(w.defaults) == 0 {
+		return opts
+	}
+	return append(append([]Option{}, w.defaults...), opts...)
 }
@@ -1,8 +1,11 @@
 package workflow
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"log/slog"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -231,6 +234,117 @@ func TestPromptStep_RunStream(t *testing.T) {
 	assert.Equal(t, "Hello", state.Output)
 }
 
+func TestPromptStep_DryRun(t *testing.T) {
+	provider := &mockProvider{
+		responses: []mockResponse{{content: "Hello"}},
+	}
+	tools := []ai.Tool{{Name: "search", Description: "Search the web"}}
+
+	step := NewPromptStep("prompt", provider,
+		func(s *testState) []ai.Message {
+			return []ai.Message{{Role: ai.RoleUser, Content: s.Input}}
+		},
+		nil,
+		func(s *testState) *string { return &s.Output },
+		ai.WithTools(tools),
+	)
+
+	t.Run("run", func(t *testing.T) {
+		state := &testState{Input: "Hi"}
+		require.NoError(t, step.Run(context.Background(), state, WithDryRun()))
+		assert.Empty(t, state.Output)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		state := &testState{Input: "Hi"}
+		var planned []Event
+		var completed bool
+		for ev := range step.RunStream(ai.WithDryRun(context.Background()), state) {
+			switch ev.Type {
+			case event.ChatPlanned:
+				planned = append(planned, ev)
+			case event.StepEnd:
+				completed = true
+			}
+		}
+
+		require.Len(t, planned, 1)
+		assert.Equal(t, "prompt", planned[0].StepName)
+		assert.Equal(t, []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}, planned[0].Messages)
+		assert.Equal(t, tools, planned[0].Tools)
+		assert.True(t, completed)
+		assert.Empty(t, state.Output)
+	})
+
+	assert.Equal(t, 0, provider.callCount, "the model is not called")
+}
+
+func TestPromptStep_ContextManager(t *testing.T) {
+	step := NewPromptStep("prompt", &mockProvider{},
+		func(s *testState) []ai.Message {
+			return []ai.Message{
+				{Role: ai.RoleSystem, Content: "Be brief"},
+				{Role: ai.RoleUser, Content: strings.Repeat("x", 400)},
+				{Role: ai.RoleUser, Content: s.Input},
+			}
+		},
+		nil,
+		func(s *testState) *string { return &s.Output },
+	)
+
+	cm := ai.NewContextManager(ai.WithContextLimit(50), ai.WithOutputReserve(0))
+	var planned []ai.Message
+	for ev := range step.RunStream(context.Background(), &testState{Input: "Hi"}, WithContextManager(cm), WithDryRun()) {
+		if ev.Type == event.ChatPlanned {
+			planned = ev.Messages
+		}
+	}
+
+	assert.Equal(t, []ai.Message{
+		{Role: ai.RoleSystem, Content: "Be brief"},
+		{Role: ai.RoleUser, Content: "Hi"},
+	}, planned)
+}
+
+// optionsRecorder wraps mockProvider and records the options of each call.
+type optionsRecorder struct {
+	*mockProvider
+	calls []*ai.Options
+}
+
+func (r *optionsRecorder) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	r.calls = append(r.calls, ai.ApplyOptions(opts...))
+	return r.mockProvider.Chat(ctx, messages, opts...)
+}
+
+func TestPromptStep_ContextOptions(t *testing.T) {
+	provider := &optionsRecorder{mockProvider: &mockProvider{
+		responses: []mockResponse{{content: "Hello"}},
+	}}
+
+	step := NewPromptStep("prompt", provider,
+		func(s *testState) []ai.Message {
+			return []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}
+		},
+		nil,
+		func(s *testState) *string { return &s.Output },
+		ai.WithMaxTokens(100),
+	)
+
+	ctx := ai.WithContextOptions(context.Background(),
+		ai.WithMaxTokens(50),
+		ai.WithTemperature(0.4),
+		ai.WithMetadata("tenant", "acme"),
+	)
+	err := step.Run(ctx, &testState{})
+
+	require.NoError(t, err)
+	require.Len(t, provider.calls, 1)
+	assert.Equal(t, 100, provider.calls[0].MaxTokens, "step options override context options")
+	assert.Equal(t, 0.4, *provider.calls[0].Temperature)
+	assert.Equal(t, "acme", provider.calls[0].Metadata["tenant"])
+}
+
 // --- Chain Tests ---
 
 func TestChain_Run(t *testing.T) {
@@ -309,6 +423,28 @@ func TestChain_RunStream(t *testing.T) {
 	assert.Equal(t, expected, eventTypes)
 }
 
+func TestChain_RunStreamProgress(t *testing.T) {
+	noop := func(ctx context.Context, state *testState) error { return nil }
+	chain := NewChain("test-chain",
+		NewFuncStep("step1", noop),
+		NewFuncStep("step2", noop),
+	)
+
+	var progress []event.ProgressInfo
+	for ev := range chain.RunStream(context.Background(), &testState{}, WithProgress()) {
+		if ev.Type == event.Progress {
+			assert.Equal(t, "test-chain", ev.StepName)
+			progress = append(progress, *ev.Progress)
+		}
+	}
+
+	assert.Equal(t, []event.ProgressInfo{
+		{Completed: 0, Total: 2, Unit: "steps"},
+		{Completed: 1, Total: 2, Unit: "steps"},
+		{Completed: 2, Total: 2, Unit: "steps"},
+	}, progress)
+}
+
 func TestChain_Timeout(t *testing.T) {
 	slowStep := NewFuncStep[testState]("slow", func(ctx context.Context, state *testState) error {
 		select {
@@ -330,6 +466,21 @@ func TestChain_Timeout(t *testing.T) {
 
 // --- Parallel Tests ---
 
+func TestWithEventBuffer(t *testing.T) {
+	step := NewFuncStep[testState]("step", func(ctx context.Context, state *testState) error { return nil })
+	chain := NewChain("chain", step)
+
+	ch := chain.RunStream(context.Background(), &testState{}, WithEventBuffer(500))
+	assert.Equal(t, 500, cap(ch))
+	for range ch {
+	}
+
+	ch = chain.RunStream(context.Background(), &testState{})
+	assert.Equal(t, event.DefaultBufferSize, cap(ch))
+	for range ch {
+	}
+}
+
 func TestParallel_Run(t *testing.T) {
 	var count atomic.Int32
 
@@ -503,6 +654,189 @@ func TestParallel_MaxConcurrency(t *testing.T) {
 	assert.LessOrEqual(t, maxConcurrent.Load(), int32(2))
 }
 
+func TestWorkflow_RunConcurrency(t *testing.T) {
+	var concurrent, maxConcurrent atomic.Int32
+	leaf := func() Step[testState] {
+		return NewFuncStep[testState]("leaf", func(ctx context.Context, state *testState) error {
+			current := concurrent.Add(1)
+			for {
+				max := maxConcurrent.Load()
+				if current <= max || maxConcurrent.CompareAndSwap(max, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			concurrent.Add(-1)
+			return nil
+		})
+	}
+	inner := func(name string) Step[testState] {
+		return NewParallel[testState](name, []Step[testState]{leaf(), leaf(), leaf()}, nil)
+	}
+	outer := NewParallel[testState]("outer", []Step[testState]{inner("a"), inner("b"), inner("c")}, nil)
+
+	for _, stream := range []bool{false, true} {
+		maxConcurrent.Store(0)
+		wf := New("nested", outer)
+		if stream {
+			for ev := range wf.RunStream(context.Background(), &testState{}, WithRunConcurrency(2)) {
+				require.NotEqual(t, event.RunError, ev.Type, "%v", ev.Error)
+			}
+		} else {
+			_, err := wf.Run(context.Background(), &testState{}, WithRunConcurrency(2))
+			require.NoError(t, err)
+		}
+		assert.LessOrEqual(t, maxConcurrent.Load(), int32(2), "stream=%v", stream)
+	}
+}
+
+func TestParallel_CancelOnError(t *testing.T) {
+	newSteps := func(slowErr *atomic.Value) []Step[testState] {
+		started := make(chan struct{})
+		return []Step[testState]{
+			NewFuncStep[testState]("fail", func(ctx context.Context, state *testState) error {
+				<-started
+				return errors.New("fail error")
+			}),
+			NewFuncStep[testState]("slow", func(ctx context.Context, state *testState) error {
+				close(started)
+				select {
+				case <-ctx.Done():
+					slowErr.Store(ctx.Err())
+					return ctx.Err()
+				case <-time.After(200 * time.Millisecond):
+					return errors.New("slow error")
+				}
+			}),
+		}
+	}
+
+	t.Run("failure cancels siblings by default", func(t *testing.T) {
+		var slowErr atomic.Value
+		parallel := NewParallel("test-parallel", newSteps(&slowErr), nil)
+
+		err := parallel.Run(context.Background(), &testState{})
+
+		var parallelErr *ParallelError
+		require.ErrorAs(t, err, &parallelErr)
+		assert.Len(t, parallelErr.Errors, 1, "cancelled siblings are not reported")
+		assert.Contains(t, parallelErr.Errors, "fail")
+		assert.Equal(t, context.Canceled, slowErr.Load())
+	})
+
+	t.Run("disabled lets siblings finish", func(t *testing.T) {
+		var slowErr atomic.Value
+		parallel := NewParallel("test-parallel", newSteps(&slowErr), nil)
+
+		err := parallel.Run(context.Background(), &testState{}, WithCancelOnError(false))
+
+		var parallelErr *ParallelError
+		require.ErrorAs(t, err, &parallelErr)
+		assert.Len(t, parallelErr.Errors, 2)
+		assert.Nil(t, slowErr.Load())
+	})
+
+	t.Run("streaming skips cancelled siblings", func(t *testing.T) {
+		var slowErr atomic.Value
+		parallel := NewParallel("test-parallel", newSteps(&slowErr), nil)
+
+		var skipped, failed []string
+		for ev := range parallel.RunStream(context.Background(), &testState{}) {
+			switch ev.Type {
+			case event.StepSkipped:
+				skipped = append(skipped, ev.StepName)
+			case event.RunError:
+				failed = append(failed, ev.StepName)
+			}
+		}
+
+		assert.Equal(t, []string{"slow"}, skipped)
+		assert.Equal(t, []string{"fail", "test-parallel"}, failed)
+	})
+}
+
+func TestParallel_RecoversPanics(t *testing.T) {
+	steps := []Step[testState]{
+		NewFuncStep[testState]("ok", func(ctx context.Context, state *testState) error {
+			return nil
+		}),
+		NewFuncStep[testState]("boom", func(ctx context.Context, state *testState) error {
+			panic("boom")
+		}),
+	}
+	parallel := NewParallel[testState]("test-parallel", steps, nil)
+
+	t.Run("run", func(t *testing.T) {
+		err := parallel.Run(context.Background(), &testState{})
+
+		var stepErr *StepError
+		require.ErrorAs(t, err, &stepErr)
+		assert.Equal(t, "boom", stepErr.StepName)
+		var panicErr *PanicError
+		require.ErrorAs(t, err, &panicErr)
+		assert.Equal(t, "boom", panicErr.Value)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		var runErr error
+		for ev := range parallel.RunStream(context.Background(), &testState{}) {
+			if ev.Type == event.RunError && ev.StepName == "boom" {
+				runErr = ev.Error
+			}
+		}
+
+		var panicErr *PanicError
+		require.ErrorAs(t, runErr, &panicErr)
+		assert.Equal(t, "boom", panicErr.Value)
+	})
+}
+
+// panicProvider panics on every call.
+type panicProvider struct{}
+
+func (panicProvider) Chat(ctx context.Context, messages []ai.Message, opts ...ai.Option) (*ai.Response, error) {
+	panic("provider bug")
+}
+
+func (panicProvider) ChatStream(ctx context.Context, messages []ai.Message, opts ...ai.Option) (<-chan event.Event, error) {
+	panic("provider bug")
+}
+
+func TestRunStream_RecoversPanics(t *testing.T) {
+	prompt := NewPromptStep("prompt", panicProvider{},
+		func(s *testState) []ai.Message {
+			return []ai.Message{{Role: ai.RoleUser, Content: "Hi"}}
+		},
+		nil,
+		func(s *testState) *string { return &s.Output },
+	)
+	boom := NewFuncStep[testState]("boom", func(ctx context.Context, state *testState) error {
+		panic("boom")
+	})
+
+	steps := map[string]Step[testState]{
+		"prompt": prompt,
+		"chain":  NewChain("chain", boom),
+		"retry":  NewRetryStep("retry", boom),
+	}
+	for name, step := range steps {
+		t.Run(name, func(t *testing.T) {
+			var runErr error
+			for ev := range step.RunStream(context.Background(), &testState{}) {
+				if ev.Type == event.RunError && runErr == nil {
+					runErr = ev.Error
+				}
+			}
+
+			var stepErr *StepError
+			require.ErrorAs(t, runErr, &stepErr)
+			var panicErr *PanicError
+			require.ErrorAs(t, runErr, &panicErr)
+			assert.NotEmpty(t, panicErr.Stack)
+		})
+	}
+}
+
 func TestParallel_RunStream(t *testing.T) {
 	steps := []Step[testState]{
 		NewFuncStep[testState]("step1", func(ctx context.Context, state *testState) error {
@@ -699,6 +1033,46 @@ func TestClassifierRouter_Run(t *testing.T) {
 	assert.Equal(t, "billing", state.HandledBy)
 }
 
+func TestClassifierRouter_DryRun(t *testing.T) {
+	provider := &mockProvider{
+		responses: []mockResponse{{content: "billing"}},
+	}
+
+	var visited []string
+	visit := func(name string) Step[testState] {
+		return NewFuncStep[testState](name, func(ctx context.Context, state *testState) error {
+			visited = append(visited, name)
+			return nil
+		})
+	}
+
+	router := NewClassifierRouter("classifier", provider,
+		func(s *testState) []ai.Message {
+			return []ai.Message{{Role: ai.RoleUser, Content: s.Ticket}}
+		},
+		map[string]Step[testState]{
+			"technical": visit("technical"),
+			"billing":   visit("billing"),
+		},
+	)
+
+	var routes []string
+	var planned bool
+	for ev := range router.RunStream(context.Background(), &testState{Ticket: "Help"}, WithDryRun()) {
+		switch ev.Type {
+		case event.ChatPlanned:
+			planned = true
+		case event.RouteSelected:
+			routes = append(routes, ev.RouteName)
+		}
+	}
+
+	assert.True(t, planned)
+	assert.Equal(t, []string{"billing", "technical"}, routes)
+	assert.Equal(t, []string{"billing", "technical"}, visited)
+	assert.Equal(t, 0, provider.callCount, "the model is not called")
+}
+
 func TestClassifierRouter_UnknownClassification(t *testing.T) {
 	provider := &mockProvider{
 		responses: []mockResponse{{content: "unknown"}},
@@ -777,6 +1151,42 @@ func TestWorkflow_RunStream(t *testing.T) {
 	assert.Contains(t, eventTypes, event.RunEnd)
 }
 
+func TestWorkflow_Logger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	step := NewFuncStep[testState]("step1", func(ctx context.Context, state *testState) error {
+		return nil
+	})
+	wf := New("test-workflow", NewChain("inner", step), WithLogger(logger))
+
+	t.Run("run stream logs events", func(t *testing.T) {
+		buf.Reset()
+		for range wf.RunStream(context.Background(), &testState{}) {
+		}
+		assert.Contains(t, buf.String(), `msg="step started" event=step_start step_name=step1`)
+		assert.Contains(t, buf.String(), `msg="run finished"`)
+	})
+
+	t.Run("run logs start and outcome", func(t *testing.T) {
+		buf.Reset()
+		_, err := wf.Run(context.Background(), &testState{})
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), `msg="run started" event=run_start step_name=test-workflow`)
+		assert.Contains(t, buf.String(), "message=complete")
+	})
+
+	t.Run("run logs failures", func(t *testing.T) {
+		buf.Reset()
+		failing := New("failing", NewFuncStep[testState]("fail", func(ctx context.Context, state *testState) error {
+			return errors.New("intentional error")
+		}))
+		_, err := failing.Run(context.Background(), &testState{}, WithLogger(logger))
+		require.Error(t, err)
+		assert.Contains(t, buf.String(), "level=ERROR")
+		assert.Contains(t, buf.String(), "intentional error")
+	})
+}
+
 // --- Nested Workflow Tests ---
 
 func TestNestedWorkflows(t *testing.T) {